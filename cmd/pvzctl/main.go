@@ -0,0 +1,64 @@
+// pvzctl - консольная утилита администратора ПВЗ. Работает напрямую с базой данных
+// через тот же слой запросов, что и сервер, и нужна, когда HTTP API недоступен
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"pvz-service/internal/config"
+	"pvz-service/internal/db"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := newRootCommand().ExecuteContext(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// newRootCommand создает корневую команду со всеми подкомандами
+func newRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:           "pvzctl",
+		Short:         "Администрирование сервиса ПВЗ напрямую через базу данных",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	root.AddCommand(
+		newUserCommand(),
+		newPVZCommand(),
+		newReceptionCommand(),
+		newMigrateCommand(),
+		newSeedCommand(),
+	)
+
+	return root
+}
+
+// connect подключается к базе данных по настройкам из переменных окружения, как сервер
+func connect() (*db.Database, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return connectWith(cfg)
+}
+
+// connectWith подключается к базе данных с уже загруженной конфигурацией
+func connectWith(cfg *config.Config) (*db.Database, error) {
+	database, err := db.NewDatabase(&cfg.Database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return database, nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// executeCommand запускает pvzctl с аргументами и возвращает ошибку выполнения
+func executeCommand(args ...string) error {
+	root := newRootCommand()
+	root.SetArgs(args)
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	return root.ExecuteContext(context.Background())
+}
+
+// TestArgumentValidation проверяет, что неверные аргументы отклоняются до подключения к базе данных
+func TestArgumentValidation(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "Неизвестная роль",
+			args:     []string{"user", "create", "--email", "admin@example.com", "--password", "secret", "--role", "admin"},
+			expected: "role must be employee or moderator",
+		},
+		{
+			name:     "Пользователь без email и телефона",
+			args:     []string{"user", "create", "--password", "secret"},
+			expected: "email or phone is required",
+		},
+		{
+			name:     "Неверный телефон",
+			args:     []string{"user", "create", "--phone", "123", "--password", "secret"},
+			expected: "invalid phone",
+		},
+		{
+			name:     "Закрытие приёмки без ID",
+			args:     []string{"reception", "close"},
+			expected: "exactly one of --id or --pvz-id is required",
+		},
+		{
+			name:     "Закрытие приёмки с обоими ID",
+			args:     []string{"reception", "close", "--id", "a", "--pvz-id", "b"},
+			expected: "exactly one of --id or --pvz-id is required",
+		},
+		{
+			name:     "Назначение городов без ID модератора",
+			args:     []string{"user", "cities", "--city", "Москва"},
+			expected: "--id must be a user UUID",
+		},
+		{
+			name:     "Назначение ПВЗ с неверным ID ПВЗ",
+			args:     []string{"user", "pvz", "--id", "123e4567-e89b-12d3-a456-426614174000", "--pvz", "pvz-1"},
+			expected: "--pvz must be a pvz UUID",
+		},
+		{
+			name:     "Откат миграций на неверное число шагов",
+			args:     []string{"migrate", "down", "0"},
+			expected: "N must be a positive number",
+		},
+		{
+			name:     "Тестовые данные без ПВЗ",
+			args:     []string{"seed", "--pvz", "0"},
+			expected: "--pvz, --receptions and --products must be positive",
+		},
+		{
+			name:     "Доля штрихкодов больше единицы",
+			args:     []string{"seed", "--barcode-share", "1.5"},
+			expected: "--barcode-share and --open-share must be between 0 and 1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := executeCommand(tc.args...)
+
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
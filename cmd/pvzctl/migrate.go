@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/spf13/cobra"
+)
+
+// newMigrateCommand создает команды применения миграций из каталога migrations
+func newMigrateCommand() *cobra.Command {
+	var path string
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Применение миграций схемы базы данных",
+	}
+	cmd.PersistentFlags().StringVar(&path, "path", "migrations", "каталог с файлами миграций")
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "up",
+			Short: "Применить все новые миграции",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return runMigrations(cmd, path, func(m *migrate.Migrate) error {
+					return m.Up()
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "down N",
+			Short: "Откатить N последних миграций",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				steps, err := strconv.Atoi(args[0])
+				if err != nil || steps <= 0 {
+					return fmt.Errorf("N must be a positive number, got %q", args[0])
+				}
+				return runMigrations(cmd, path, func(m *migrate.Migrate) error {
+					return m.Steps(-steps)
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "version",
+			Short: "Показать текущую версию схемы",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return runMigrations(cmd, path, func(m *migrate.Migrate) error {
+					return nil
+				})
+			},
+		},
+	)
+
+	return cmd
+}
+
+// runMigrations выполняет action над миграциями и выводит итоговую версию схемы
+func runMigrations(cmd *cobra.Command, path string, action func(m *migrate.Migrate) error) error {
+	database, err := connect()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	driver, err := postgres.WithInstance(database.DB.DB, &postgres.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to create migration driver: %w", err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance("file://"+path, "postgres", driver)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	if err := action(m); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		fmt.Fprintln(cmd.OutOrStdout(), "No migrations applied")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get schema version: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Schema version: %d (dirty: %t)\n", version, dirty)
+	return nil
+}
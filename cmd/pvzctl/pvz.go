@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+
+	"github.com/spf13/cobra"
+)
+
+// newPVZCommand создает команды работы с ПВЗ
+func newPVZCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pvz",
+		Short: "Работа с ПВЗ",
+	}
+	cmd.AddCommand(newPVZListCommand())
+	return cmd
+}
+
+// newPVZListCommand создает команду вывода списка ПВЗ
+func newPVZListCommand() *cobra.Command {
+	var params models.PVZListQuery
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Показать список ПВЗ",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			database, err := connect()
+			if err != nil {
+				return err
+			}
+			defer database.Close()
+
+			pvzList, total, err := queries.NewPVZQueries(database).GetPVZList(cmd.Context(), params)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tCITY\tREGISTERED")
+			for _, pvz := range pvzList {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", pvz.ID, pvz.City, pvz.RegistrationDate.Format(time.RFC3339))
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Total: %d\n", total)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&params.StartDate, "start-date", "", "начало периода регистрации в формате RFC3339")
+	cmd.Flags().StringVar(&params.EndDate, "end-date", "", "конец периода регистрации в формате RFC3339")
+	cmd.Flags().IntVar(&params.Page, "page", 1, "номер страницы")
+	cmd.Flags().IntVar(&params.Limit, "limit", 30, "количество ПВЗ на странице")
+
+	return cmd
+}
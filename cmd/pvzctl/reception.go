@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+
+	"github.com/spf13/cobra"
+)
+
+// newReceptionCommand создает команды работы с приёмками
+func newReceptionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reception",
+		Short: "Работа с приёмками",
+	}
+	cmd.AddCommand(newReceptionCloseCommand())
+	return cmd
+}
+
+// newReceptionCloseCommand создает команду принудительного закрытия приёмки
+// без проверок роли и сверки с накладной
+func newReceptionCloseCommand() *cobra.Command {
+	var receptionID, pvzID string
+
+	cmd := &cobra.Command{
+		Use:   "close",
+		Short: "Принудительно закрыть приёмку по ID или последнюю открытую приёмку ПВЗ",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (receptionID == "") == (pvzID == "") {
+				return errors.New("exactly one of --id or --pvz-id is required")
+			}
+
+			database, err := connect()
+			if err != nil {
+				return err
+			}
+			defer database.Close()
+
+			receptionQueries := queries.NewReceptionQueries(database)
+
+			var reception *models.Reception
+			if receptionID != "" {
+				reception, err = receptionQueries.GetReceptionByID(cmd.Context(), receptionID)
+			} else {
+				reception, err = receptionQueries.GetLastOpenReception(cmd.Context(), pvzID)
+			}
+			if err != nil {
+				return err
+			}
+
+			// Приостановленную приёмку из консоли можно закрыть без возобновления
+			if reception.Status == "close" {
+				return fmt.Errorf("reception %s is already closed", reception.ID)
+			}
+
+			closed, err := receptionQueries.CloseReception(cmd.Context(), reception.ID, "")
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Closed reception %s in pvz %s\n", closed.ID, closed.PvzID)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&receptionID, "id", "", "ID приёмки")
+	cmd.Flags().StringVar(&pvzID, "pvz-id", "", "ID ПВЗ, в котором закрывается последняя открытая приёмка")
+
+	return cmd
+}
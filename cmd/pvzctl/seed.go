@@ -0,0 +1,223 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"pvz-service/internal/config"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+
+	"github.com/spf13/cobra"
+)
+
+// seedBatchSize - сколько ПВЗ создается одним запросом
+const seedBatchSize = 500
+
+// weighted - значение с относительной частотой
+type weighted struct {
+	value  string
+	weight float64
+}
+
+// Распределения тестовых данных: большая часть ПВЗ в Москве, одежды принимают больше, чем обуви
+var (
+	seedCities = []weighted{
+		{"Москва", 0.6},
+		{"Санкт-Петербург", 0.25},
+		{"Казань", 0.15},
+	}
+	seedProductTypes = []weighted{
+		{"одежда", 0.5},
+		{"электроника", 0.3},
+		{"обувь", 0.2},
+	}
+)
+
+// seedOptions - параметры генерации тестовых данных
+type seedOptions struct {
+	PVZ                  int
+	ReceptionsPerPVZ     int
+	ProductsPerReception int
+	BarcodeShare         float64
+	OpenShare            float64
+	Seed                 int64
+}
+
+// seedReception - план приёмки: типы и штрихкоды товаров, пустой штрихкод - товар без штрихкода
+type seedReception struct {
+	Types    []string
+	Barcodes []string
+	Open     bool
+}
+
+// seedPVZ - план ПВЗ с его приёмками
+type seedPVZ struct {
+	City       string
+	Address    string
+	Receptions []seedReception
+}
+
+// newSeedCommand создает команду генерации тестовых данных для нагрузочного тестирования
+func newSeedCommand() *cobra.Command {
+	var opts seedOptions
+
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Создать тестовые ПВЗ, приёмки и товары для нагрузочного тестирования",
+		Long: "Создает ПВЗ, приёмки и товары через слой запросов сервера. Количество приёмок и товаров\n" +
+			"распределено вокруг заданных средних, последняя приёмка ПВЗ может остаться открытой.\n" +
+			"В промышленном окружении (APP_ENV=production) команда не запускается",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.validate(); err != nil {
+				return err
+			}
+
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				return err
+			}
+			if cfg.App.IsProduction() {
+				return errors.New("seed is disabled in production environment")
+			}
+
+			database, err := connectWith(cfg)
+			if err != nil {
+				return err
+			}
+			defer database.Close()
+
+			pvzQueries := queries.NewPVZQueries(database)
+			receptionQueries := queries.NewReceptionQueries(database)
+			productQueries := queries.NewProductQueries(database)
+
+			started := time.Now()
+			plan := planSeed(rand.New(rand.NewSource(opts.Seed)), opts)
+			var receptions, products int
+
+			for start := 0; start < len(plan); start += seedBatchSize {
+				batch := plan[start:min(start+seedBatchSize, len(plan))]
+
+				pvzs := make([]models.PVZ, 0, len(batch))
+				for _, p := range batch {
+					pvzs = append(pvzs, models.PVZ{City: p.City, Address: p.Address})
+				}
+				created, err := pvzQueries.CreatePVZBatch(cmd.Context(), pvzs)
+				if err != nil {
+					return err
+				}
+
+				for i, pvz := range created {
+					for _, planned := range batch[i].Receptions {
+						reception, err := receptionQueries.CreateReception(cmd.Context(), pvz.ID, "")
+						if err != nil {
+							return err
+						}
+						for j, productType := range planned.Types {
+							if _, err := productQueries.AddProduct(cmd.Context(), reception.ID, "", productType, planned.Barcodes[j]); err != nil {
+								return err
+							}
+						}
+						if !planned.Open {
+							if _, err := receptionQueries.CloseReception(cmd.Context(), reception.ID, ""); err != nil {
+								return err
+							}
+						}
+						receptions++
+						products += len(planned.Types)
+					}
+				}
+
+				fmt.Fprintf(cmd.OutOrStdout(), "Created %d of %d pvz\n", start+len(batch), len(plan))
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Seeded %d pvz, %d receptions, %d products in %s (seed %d)\n",
+				len(plan), receptions, products, time.Since(started).Round(time.Millisecond), opts.Seed)
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&opts.PVZ, "pvz", 10, "количество ПВЗ")
+	cmd.Flags().IntVar(&opts.ReceptionsPerPVZ, "receptions", 5, "среднее количество приёмок в ПВЗ")
+	cmd.Flags().IntVar(&opts.ProductsPerReception, "products", 20, "среднее количество товаров в приёмке")
+	cmd.Flags().Float64Var(&opts.BarcodeShare, "barcode-share", 0.7, "доля товаров со штрихкодом от 0 до 1")
+	cmd.Flags().Float64Var(&opts.OpenShare, "open-share", 0.3, "доля ПВЗ с открытой последней приёмкой от 0 до 1")
+	cmd.Flags().Int64Var(&opts.Seed, "seed", time.Now().UnixNano(), "начальное значение генератора, одинаковое значение дает одинаковые данные")
+
+	return cmd
+}
+
+// validate проверяет параметры до подключения к базе данных
+func (o seedOptions) validate() error {
+	if o.PVZ < 1 || o.ReceptionsPerPVZ < 1 || o.ProductsPerReception < 1 {
+		return errors.New("--pvz, --receptions and --products must be positive")
+	}
+	if o.BarcodeShare < 0 || o.BarcodeShare > 1 || o.OpenShare < 0 || o.OpenShare > 1 {
+		return errors.New("--barcode-share and --open-share must be between 0 and 1")
+	}
+	return nil
+}
+
+// planSeed генерирует план тестовых данных. Количество приёмок и товаров распределено
+// нормально вокруг среднего со стандартным отклонением в треть среднего, но не меньше одного
+func planSeed(rng *rand.Rand, opts seedOptions) []seedPVZ {
+	// Адреса уникальны между запусками, чтобы повторный запуск не конфликтовал с прежними ПВЗ
+	run := rng.Int63n(1 << 32)
+
+	plan := make([]seedPVZ, 0, opts.PVZ)
+	for i := 0; i < opts.PVZ; i++ {
+		pvz := seedPVZ{
+			City:    pick(rng, seedCities),
+			Address: fmt.Sprintf("ул. Нагрузочная, д. %d, корп. %x", i+1, run),
+		}
+
+		count := around(rng, opts.ReceptionsPerPVZ)
+		for r := 0; r < count; r++ {
+			products := around(rng, opts.ProductsPerReception)
+			reception := seedReception{
+				Types:    make([]string, 0, products),
+				Barcodes: make([]string, 0, products),
+				Open:     r == count-1 && rng.Float64() < opts.OpenShare,
+			}
+			for p := 0; p < products; p++ {
+				reception.Types = append(reception.Types, pick(rng, seedProductTypes))
+				barcode := ""
+				if rng.Float64() < opts.BarcodeShare {
+					barcode = fmt.Sprintf("46%011d", rng.Int63n(1e11))
+				}
+				reception.Barcodes = append(reception.Barcodes, barcode)
+			}
+			pvz.Receptions = append(pvz.Receptions, reception)
+		}
+
+		plan = append(plan, pvz)
+	}
+
+	return plan
+}
+
+// around возвращает случайное целое около mean, не меньше 1 и не больше 3*mean
+func around(rng *rand.Rand, mean int) int {
+	value := int(math.Round(float64(mean) + rng.NormFloat64()*float64(mean)/3))
+	return max(1, min(value, 3*mean))
+}
+
+// pick выбирает значение с учетом весов
+func pick(rng *rand.Rand, values []weighted) string {
+	var total float64
+	for _, v := range values {
+		total += v.weight
+	}
+
+	x := rng.Float64() * total
+	for _, v := range values {
+		if x < v.weight {
+			return v.value
+		}
+		x -= v.weight
+	}
+	return values[len(values)-1].value
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPlanSeed проверяет, что план тестовых данных воспроизводим и соответствует параметрам
+func TestPlanSeed(t *testing.T) {
+	opts := seedOptions{PVZ: 50, ReceptionsPerPVZ: 4, ProductsPerReception: 10, BarcodeShare: 0.5, OpenShare: 1, Seed: 42}
+
+	plan := planSeed(rand.New(rand.NewSource(opts.Seed)), opts)
+	assert.Equal(t, plan, planSeed(rand.New(rand.NewSource(opts.Seed)), opts))
+	assert.Len(t, plan, opts.PVZ)
+
+	addresses := make(map[string]bool)
+	for _, pvz := range plan {
+		assert.False(t, addresses[pvz.Address], "адрес повторяется: %s", pvz.Address)
+		addresses[pvz.Address] = true
+
+		assert.NotEmpty(t, pvz.Receptions)
+		assert.LessOrEqual(t, len(pvz.Receptions), 3*opts.ReceptionsPerPVZ)
+		for i, reception := range pvz.Receptions {
+			// Открытой может остаться только последняя приёмка
+			assert.Equal(t, i == len(pvz.Receptions)-1, reception.Open)
+			assert.NotEmpty(t, reception.Types)
+			assert.Len(t, reception.Barcodes, len(reception.Types))
+			for _, barcode := range reception.Barcodes {
+				if barcode != "" {
+					assert.Len(t, barcode, 13)
+				}
+			}
+		}
+	}
+}
+
+// TestPick проверяет выбор значения с учетом весов
+func TestPick(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	values := []weighted{{"часто", 0.9}, {"редко", 0.1}, {"никогда", 0}}
+
+	counts := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		counts[pick(rng, values)]++
+	}
+
+	assert.Greater(t, counts["часто"], counts["редко"])
+	assert.Zero(t, counts["никогда"])
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"pvz-service/internal/authz"
+	"pvz-service/internal/city"
+	"pvz-service/internal/config"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/otp"
+	"pvz-service/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+// newUserCommand создает команды управления пользователями
+func newUserCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Управление пользователями",
+	}
+	cmd.AddCommand(newUserCreateCommand(), newUserCitiesCommand(), newUserPVZCommand())
+	return cmd
+}
+
+// newUserCreateCommand создает команду создания пользователя с ролью
+func newUserCreateCommand() *cobra.Command {
+	var email, phone, password, role string
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Создать пользователя с ролью из матрицы прав",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !authz.IsRole(role) {
+				return fmt.Errorf("role must be %s, got %q", strings.Join(authz.Roles(), " or "), role)
+			}
+			if email == "" && phone == "" {
+				return errors.New("email or phone is required")
+			}
+			if password == "" {
+				return errors.New("password is required")
+			}
+
+			if phone != "" {
+				normalized, err := otp.NormalizePhone(phone)
+				if err != nil {
+					return err
+				}
+				phone = normalized
+			}
+
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				return err
+			}
+
+			passwordHasher, err := utils.NewPasswordHasher(&cfg.Password)
+			if err != nil {
+				return err
+			}
+
+			database, err := connectWith(cfg)
+			if err != nil {
+				return err
+			}
+			defer database.Close()
+
+			authQueries := queries.NewAuthQueries(database)
+
+			if email != "" {
+				exists, err := authQueries.GetUserByEmail(cmd.Context(), email)
+				if err != nil {
+					return err
+				}
+				if exists {
+					return fmt.Errorf("user with email %s already exists", email)
+				}
+			}
+
+			passwordHash, err := passwordHasher.HashPassword(password)
+			if err != nil {
+				return fmt.Errorf("failed to hash password: %w", err)
+			}
+
+			id, err := authQueries.CreateUser(cmd.Context(), email, phone, passwordHash, role)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Created %s %s\n", role, id)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&email, "email", "", "email пользователя")
+	cmd.Flags().StringVar(&phone, "phone", "", "телефон пользователя")
+	cmd.Flags().StringVar(&password, "password", "", "пароль пользователя")
+	cmd.Flags().StringVar(&role, "role", "employee", "роль: "+strings.Join(authz.Roles(), " или "))
+
+	return cmd
+}
+
+// newUserCitiesCommand создает команду назначения модератору городов, в которых он управляет ПВЗ
+func newUserCitiesCommand() *cobra.Command {
+	var id string
+	var cities []string
+
+	cmd := &cobra.Command{
+		Use:   "cities",
+		Short: "Назначить модератору города; без --city ограничение по городам снимается",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := uuid.Parse(id); err != nil {
+				return fmt.Errorf("--id must be a user UUID: %w", err)
+			}
+
+			database, err := connect()
+			if err != nil {
+				return err
+			}
+			defer database.Close()
+
+			// Приводим города к названиям из справочника, как при создании ПВЗ
+			resolver := city.NewResolver(queries.NewCityQueries(database), city.DefaultCacheTTL)
+			names := make([]string, 0, len(cities))
+			for _, input := range cities {
+				name, err := resolver.Resolve(cmd.Context(), input)
+				if err != nil {
+					return fmt.Errorf("city %q: %w", input, err)
+				}
+				names = append(names, name)
+			}
+
+			if err := queries.NewModeratorQueries(database).SetModeratorCities(cmd.Context(), id, names); err != nil {
+				return err
+			}
+
+			if len(names) == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "Moderator %s is not restricted by city\n", id)
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Moderator %s manages PVZ in: %s\n", id, strings.Join(names, ", "))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&id, "id", "", "ID модератора")
+	cmd.Flags().StringArrayVar(&cities, "city", nil, "город модератора, флаг можно повторять")
+
+	return cmd
+}
+
+// newUserPVZCommand создает команду назначения сотруднику ПВЗ, в которых он работает
+func newUserPVZCommand() *cobra.Command {
+	var id string
+	var pvzIDs []string
+
+	cmd := &cobra.Command{
+		Use:   "pvz",
+		Short: "Назначить сотруднику ПВЗ; без --pvz ограничение по ПВЗ снимается",
+		Long: "Назначает сотруднику ПВЗ, с которыми он работает. Список записывается в токены,\n" +
+			"выданные после назначения, уже выданные токены действуют до истечения срока",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := uuid.Parse(id); err != nil {
+				return fmt.Errorf("--id must be a user UUID: %w", err)
+			}
+			for _, pvzID := range pvzIDs {
+				if _, err := uuid.Parse(pvzID); err != nil {
+					return fmt.Errorf("--pvz must be a pvz UUID, got %q", pvzID)
+				}
+			}
+
+			database, err := connect()
+			if err != nil {
+				return err
+			}
+			defer database.Close()
+
+			if err := queries.NewEmployeeQueries(database).SetEmployeePVZ(cmd.Context(), id, pvzIDs); err != nil {
+				return err
+			}
+
+			if len(pvzIDs) == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "Employee %s is not restricted by pvz\n", id)
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Employee %s works in pvz: %s\n", id, strings.Join(pvzIDs, ", "))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&id, "id", "", "ID сотрудника")
+	cmd.Flags().StringArrayVar(&pvzIDs, "pvz", nil, "ID ПВЗ сотрудника, флаг можно повторять")
+
+	return cmd
+}
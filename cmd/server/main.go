@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,13 +12,53 @@ import (
 	"time"
 
 	"pvz-service/internal/api"
+	"pvz-service/internal/app"
 	"pvz-service/internal/config"
 	"pvz-service/internal/db"
+	"pvz-service/internal/errreport"
+	"pvz-service/internal/grpcapi"
+	"pvz-service/internal/metrics"
+	"pvz-service/internal/tracing"
+
+	"google.golang.org/grpc"
 )
 
 func main() {
+	// С флагом --validate-config сервер только проверяет конфигурацию и завершается,
+	// код возврата отличен от нуля, если конфигурация с ошибками
+	validateOnly := flag.Bool("validate-config", false, "validate configuration and exit without starting the server")
+	flag.Parse()
+
 	// Загружаем конфигурацию
-	cfg := config.LoadConfig()
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+	if *validateOnly {
+		log.Println("Config is valid")
+		return
+	}
+	log.Printf("Loaded config: %+v", cfg.Redacted())
+
+	// Настраиваем трассировку до создания остальных компонентов
+	shutdownTracing, err := tracing.Setup(context.Background(), &cfg.Tracing)
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+
+	shutdownMetrics, err := metrics.Setup(context.Background(), &cfg.Metrics, cfg.Tracing.ServiceName)
+	if err != nil {
+		log.Fatalf("Failed to initialize metrics: %v", err)
+	}
+
+	// Паники обработчиков отправляются в Sentry, если задан SENTRY_DSN
+	flushErrors, err := errreport.Setup(&cfg.Sentry, cfg.App.Environment)
+	if err != nil {
+		log.Fatalf("Failed to initialize error reporting: %v", err)
+	}
 
 	// Устанавливаем соединение с базой данных
 	database, err := db.NewDatabase(&cfg.Database)
@@ -25,8 +67,22 @@ func main() {
 	}
 	defer database.Close()
 
+	// Зависимости общие для HTTP и gRPC API и фоновых заданий: хаб событий ленты ПВЗ,
+	// счетчики запросов к API, кэши флагов функций и статусов пользователей
+	container, err := app.New(cfg, database)
+	if err != nil {
+		log.Fatalf("Failed to initialize dependencies: %v", err)
+	}
+
 	// Настраиваем маршруты
-	router := api.SetupRouter(cfg, database)
+	router := api.SetupRouter(container)
+
+	// Запускаем фоновые задания, они останавливаются при завершении работы
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	backgroundJobs := app.NewJobs(container)
+	backgroundJobs.Start(jobsCtx)
 
 	// Настраиваем HTTP сервер
 	server := &http.Server{
@@ -44,12 +100,29 @@ func main() {
 		}
 	}()
 
+	// gRPC сервер для сканеров работает рядом с HTTP на отдельном порту
+	var grpcServer *grpc.Server
+	if cfg.Server.GRPCPort != "" {
+		listener, err := net.Listen("tcp", ":"+cfg.Server.GRPCPort)
+		if err != nil {
+			log.Fatalf("Failed to listen gRPC port: %v", err)
+		}
+		grpcServer = grpcapi.SetupServer(container)
+		go func() {
+			log.Printf("gRPC server is starting on port %s", cfg.Server.GRPCPort)
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatalf("Failed to start gRPC server: %v", err)
+			}
+		}()
+	}
+
 	// Настраиваем корректное завершение работы (gracefull shutdown)
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopJobs()
 
 	// Даем 10 секунд на завершение текущих запросов
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -59,5 +132,35 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	// Записываем статистику запросов, обработанных после последнего запуска задания
+	if err := backgroundJobs.UsageFlush.RunOnce(ctx); err != nil {
+		log.Printf("Failed to flush API usage: %v", err)
+	}
+
+	// Открытым потокам сканеров даем то же время, затем закрываем их принудительно
+	if grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			grpcServer.Stop()
+		}
+	}
+
+	// Отправляем накопленные спаны, метрики и отчёты об ошибках
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("Failed to shutdown tracing: %v", err)
+	}
+	if err := shutdownMetrics(ctx); err != nil {
+		log.Printf("Failed to shutdown metrics: %v", err)
+	}
+	if err := flushErrors(ctx); err != nil {
+		log.Printf("Failed to flush error reports: %v", err)
+	}
+
 	log.Println("Server exited properly")
 }
@@ -1,10 +1,19 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	"net/http"
+	"slices"
+	"strings"
 
+	"pvz-service/internal/api/response"
 	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
 	"pvz-service/internal/models"
+	"pvz-service/internal/otp"
+	"pvz-service/internal/service"
+	"pvz-service/internal/tracing"
 	"pvz-service/internal/utils"
 
 	"github.com/gin-gonic/gin"
@@ -15,14 +24,29 @@ type AuthHandler struct {
 	jwtManager      utils.JWTManagerInterface
 	authQueries     queries.AuthQueriesInterface
 	passwordChecker utils.PasswordCheckerInterface
+	sessions        service.SessionIssuer
+	twoFactor       service.TwoFactorChallenger
+	dummyRoles      []string
+	loginAudit      service.LoginRecorder
+	countryHeader   string
 }
 
-// NewAuthHandler создает новый экземпляр AuthHandler
-func NewAuthHandler(jwtManager utils.JWTManagerInterface, authQueries queries.AuthQueriesInterface, passwordChecker utils.PasswordCheckerInterface) *AuthHandler {
+// NewAuthHandler создает новый экземпляр AuthHandler.
+// Токены входа выдаются в сессиях sessions, тестовые токены /dummyLogin к сессиям не привязаны.
+// Пользователям с включенной двухфакторной аутентификацией токен выдается после второго фактора.
+// dummyRoles - роли, для которых /dummyLogin выдает тестовый токен.
+// Попытки входа записываются в журнал loginAudit; страна клиента берется из заголовка countryHeader,
+// который выставляет балансировщик, пустое имя заголовка - страна не записывается
+func NewAuthHandler(jwtManager utils.JWTManagerInterface, authQueries queries.AuthQueriesInterface, passwordChecker utils.PasswordCheckerInterface, sessions service.SessionIssuer, twoFactor service.TwoFactorChallenger, dummyRoles []string, loginAudit service.LoginRecorder, countryHeader string) *AuthHandler {
 	return &AuthHandler{
 		jwtManager:      jwtManager,
 		authQueries:     authQueries,
 		passwordChecker: passwordChecker,
+		sessions:        sessions,
+		twoFactor:       twoFactor,
+		dummyRoles:      dummyRoles,
+		loginAudit:      loginAudit,
+		countryHeader:   countryHeader,
 	}
 }
 
@@ -32,23 +56,25 @@ func (h *AuthHandler) DummyLogin(c *gin.Context) {
 
 	// Проверяем запрос
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Message: "Неверный запрос: " + err.Error(),
-		})
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	// Проверяем, что для роли разрешена выдача тестового токена
+	if !slices.Contains(h.dummyRoles, req.Role) {
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgDummyRoleForbidden))
 		return
 	}
 
 	// Генерируем JWT токен
 	token, err := h.jwtManager.GenerateDummyToken(req.Role)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Message: "Ошибка генерации токена: " + err.Error(),
-		})
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgTokenGenerateFailed, err))
 		return
 	}
 
 	// Возвращаем токен
-	c.JSON(http.StatusOK, models.DummyLoginResponse{
+	response.JSON(c, http.StatusOK, models.DummyLoginResponse{
 		Token: token,
 	})
 }
@@ -59,50 +85,66 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	// Проверяем данные запроса
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Message: "Неверный запрос: " + err.Error(),
-		})
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
 		return
 	}
 
 	// Проверяем, существует ли пользователь с таким email
-	exists, err := h.authQueries.GetUserByEmail(c.Request.Context(), req.Email)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Message: "Ошибка при проверке email: " + err.Error(),
-		})
-		return
+	if req.Email != "" {
+		exists, err := h.authQueries.GetUserByEmail(c.Request.Context(), req.Email)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCheckEmailFailed, err))
+			return
+		}
+
+		if exists {
+			response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgEmailTaken))
+			return
+		}
 	}
 
-	if exists {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Message: "Пользователь с таким email уже существует",
-		})
-		return
+	// Приводим телефон к единому формату и проверяем, что он свободен
+	var phone string
+	if req.Phone != "" {
+		normalized, err := otp.NormalizePhone(req.Phone)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgInvalidPhone))
+			return
+		}
+		phone = normalized
+
+		_, err = h.authQueries.GetUserByPhone(c.Request.Context(), phone)
+		if err == nil {
+			response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgPhoneTaken))
+			return
+		}
+		if !errors.Is(err, queries.ErrNotFound) {
+			response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCheckPhoneFailed, err))
+			return
+		}
 	}
 
 	// Хешируем пароль
-	passwordHash, err := utils.HashPassword(req.Password)
+	_, span := tracing.Start(c.Request.Context(), "password.Hash")
+	passwordHash, err := h.passwordChecker.HashPassword(req.Password)
+	tracing.End(span, err)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Message: "Ошибка при хешировании пароля: " + err.Error(),
-		})
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgPasswordHashFailed, err))
 		return
 	}
 
 	// Создаем пользователя
-	id, err := h.authQueries.CreateUser(c.Request.Context(), req.Email, passwordHash, req.Role)
+	id, err := h.authQueries.CreateUser(c.Request.Context(), req.Email, phone, passwordHash, req.Role)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Message: "Ошибка при создании пользователя: " + err.Error(),
-		})
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCreateUserFailed, err))
 		return
 	}
 
 	// Возвращаем данные созданного пользователя
-	c.JSON(http.StatusCreated, models.RegisterResponse{
+	response.JSON(c, http.StatusCreated, models.RegisterResponse{
 		ID:    id,
 		Email: req.Email,
+		Phone: phone,
 		Role:  req.Role,
 	})
 }
@@ -113,41 +155,115 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	// Проверяем запрос
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Message: "Неверный запрос: " + err.Error(),
-		})
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
 		return
 	}
 
 	// Получаем пользователя из базы данных
 	user, err := h.authQueries.GetUserWithCredentials(c.Request.Context(), req.Email)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Message: "Неверные учетные данные",
-		})
+		if errors.Is(err, queries.ErrNotFound) {
+			h.recordLogin(c, req.Email, nil, models.LoginFailureUnknownUser)
+		}
+		response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgInvalidCredentials))
 		return
 	}
 
 	// Проверяем пароль - используем PasswordHash
+	_, span := tracing.Start(c.Request.Context(), "password.Check")
 	err = h.passwordChecker.CheckPassword(req.Password, user.PasswordHash)
+	span.End()
+	if err != nil {
+		h.recordLogin(c, req.Email, user, models.LoginFailureInvalidPassword)
+		response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgInvalidCredentials))
+		return
+	}
+
+	// Деактивированный пользователь не может войти даже с верным паролем
+	if !user.IsActive {
+		h.recordLogin(c, req.Email, user, models.LoginFailureDeactivated)
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgUserDeactivated))
+		return
+	}
+
+	// Вход с верным паролем считается успешным и при включенной двухфакторной аутентификации
+	h.recordLogin(c, req.Email, user, "")
+
+	// Пересчитываем хеш, если изменились алгоритм или параметры хеширования.
+	// Ошибка не мешает входу: хеш будет пересчитан при следующем входе
+	if h.passwordChecker.NeedsRehash(user.PasswordHash) {
+		h.rehashPassword(c, user.ID, req.Password)
+	}
+
+	// Выдаем токен сессии или, при включенной двухфакторной аутентификации, токен второго этапа входа
+	issueLoginToken(c, h.sessions, h.twoFactor, user, req.RememberMe)
+}
+
+// RefreshToken обрабатывает запрос на новый токен сессии «запомнить меня» по refresh-токену.
+// В ответе новый refresh-токен, предыдущий больше не действует
+func (h *AuthHandler) RefreshToken(c *gin.Context) {
+	var req models.RefreshTokenRequest
+
+	// Проверяем запрос
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	tokens, err := h.sessions.RefreshToken(c.Request.Context(), req.RefreshToken)
+	if errors.Is(err, service.ErrRefreshTokenInvalid) {
+		response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgRefreshTokenInvalid))
+		return
+	}
+	if errors.Is(err, service.ErrUserDeactivated) {
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgUserDeactivated))
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Message: "Неверные учетные данные",
-		})
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgTokenCreateFailed, err))
 		return
 	}
 
-	// Генерируем JWT-токен
-	token, err := h.jwtManager.GenerateToken(user.ID, user.Role)
+	response.JSON(c, http.StatusOK, tokens)
+}
+
+// recordLogin записывает попытку входа в журнал, failureReason пустая - вход успешный.
+// Ошибка записи не мешает входу и только пишется в лог
+func (h *AuthHandler) recordLogin(c *gin.Context, email string, user *models.User, failureReason string) {
+	attempt := models.LoginAttempt{
+		Email:     email,
+		Success:   failureReason == "",
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	if user != nil {
+		attempt.UserID = &user.ID
+	}
+	if failureReason != "" {
+		attempt.FailureReason = &failureReason
+	}
+	if h.countryHeader != "" {
+		if country := strings.ToUpper(strings.TrimSpace(c.GetHeader(h.countryHeader))); country != "" {
+			attempt.Country = &country
+		}
+	}
+
+	if err := h.loginAudit.RecordLogin(c.Request.Context(), attempt); err != nil {
+		log.Printf("Failed to record login attempt for %s: %v", email, err)
+	}
+}
+
+// rehashPassword сохраняет хеш пароля, пересчитанный под текущие настройки
+func (h *AuthHandler) rehashPassword(c *gin.Context, userID, password string) {
+	_, span := tracing.Start(c.Request.Context(), "password.Rehash")
+	passwordHash, err := h.passwordChecker.HashPassword(password)
+	tracing.End(span, err)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Message: "Ошибка при создании токена: " + err.Error(),
-		})
+		log.Printf("Failed to rehash password for user %s: %v", userID, err)
 		return
 	}
 
-	// Возвращаем токен
-	c.JSON(http.StatusOK, models.LoginResponse{
-		Token: token,
-	})
+	if err := h.authQueries.UpdatePasswordHash(c.Request.Context(), userID, passwordHash); err != nil {
+		log.Printf("Failed to update password hash for user %s: %v", userID, err)
+	}
 }
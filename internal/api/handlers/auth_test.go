@@ -8,12 +8,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"pvz-service/internal/db/queries"
 	"pvz-service/internal/models"
+	"pvz-service/internal/service"
 	"pvz-service/internal/utils"
 )
 
@@ -27,8 +30,12 @@ func (m *MockJWTManager) GenerateDummyToken(role string) (string, error) {
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockJWTManager) GenerateToken(userID, role string) (string, error) {
-	args := m.Called(userID, role)
+func (m *MockJWTManager) TokenTTL(role string) time.Duration {
+	return time.Hour
+}
+
+func (m *MockJWTManager) GenerateToken(userID, role, sessionID string, pvzIDs []string) (string, error) {
+	args := m.Called(userID, role, sessionID, pvzIDs)
 	return args.String(0), args.Error(1)
 }
 
@@ -47,11 +54,24 @@ func (m *MockAuthQueries) GetUserByEmail(ctx context.Context, email string) (boo
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockAuthQueries) CreateUser(ctx context.Context, email, passwordHash, role string) (string, error) {
-	args := m.Called(ctx, email, passwordHash, role)
+func (m *MockAuthQueries) CreateUser(ctx context.Context, email, phone, passwordHash, role string) (string, error) {
+	args := m.Called(ctx, email, phone, passwordHash, role)
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockAuthQueries) GetUserByPhone(ctx context.Context, phone string) (*models.User, error) {
+	args := m.Called(ctx, phone)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockAuthQueries) UpdatePasswordHash(ctx context.Context, userID, passwordHash string) error {
+	args := m.Called(ctx, userID, passwordHash)
+	return args.Error(0)
+}
+
 func (m *MockAuthQueries) GetUserWithCredentials(ctx context.Context, email string) (*models.User, error) {
 	args := m.Called(ctx, email)
 	if args.Get(0) == nil {
@@ -60,36 +80,120 @@ func (m *MockAuthQueries) GetUserWithCredentials(ctx context.Context, email stri
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
+func (m *MockAuthQueries) GetUserCredentialsByID(ctx context.Context, userID string) (*models.User, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockAuthQueries) GetProfile(ctx context.Context, userID string) (*models.UserProfile, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserProfile), args.Error(1)
+}
+
+func (m *MockAuthQueries) UpdateProfile(ctx context.Context, userID string, update models.ProfileUpdate) (*models.UserProfile, error) {
+	args := m.Called(ctx, userID, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserProfile), args.Error(1)
+}
+
+func (m *MockAuthQueries) IsUserActive(ctx context.Context, userID string) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAuthQueries) SetUserActive(ctx context.Context, userID string, active bool) error {
+	args := m.Called(ctx, userID, active)
+	return args.Error(0)
+}
+
+// MockSessionIssuer мокирует выдачу токенов с сессией
+type MockSessionIssuer struct {
+	mock.Mock
+}
+
+func (m *MockSessionIssuer) IssueToken(ctx context.Context, userID, role, userAgent, ip string, rememberMe bool) (*models.LoginResponse, error) {
+	args := m.Called(ctx, userID, role, userAgent, ip, rememberMe)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginResponse), args.Error(1)
+}
+
+func (m *MockSessionIssuer) RefreshToken(ctx context.Context, refreshToken string) (*models.LoginResponse, error) {
+	args := m.Called(ctx, refreshToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginResponse), args.Error(1)
+}
+
+// stubChallenger начинает второй этап входа, если задан challenge. Пустой stubChallenger -
+// пользователи без двухфакторной аутентификации
+type stubChallenger struct {
+	challenge *models.TwoFactorChallengeResponse
+}
+
+func (s stubChallenger) Challenge(ctx context.Context, userID string, rememberMe bool) (*models.TwoFactorChallengeResponse, error) {
+	return s.challenge, nil
+}
+
+// discardLoginAudit не записывает попытки входа
+type discardLoginAudit struct{}
+
+func (discardLoginAudit) RecordLogin(ctx context.Context, attempt models.LoginAttempt) error {
+	return nil
+}
+
 type MockPasswordChecker struct {
 	mock.Mock
 }
 
+func (m *MockPasswordChecker) HashPassword(password string) (string, error) {
+	args := m.Called(password)
+	return args.String(0), args.Error(1)
+}
+
 func (m *MockPasswordChecker) CheckPassword(password, hashedPassword string) error {
 	args := m.Called(password, hashedPassword)
 	return args.Error(0)
 }
 
+func (m *MockPasswordChecker) NeedsRehash(hashedPassword string) bool {
+	args := m.Called(hashedPassword)
+	return args.Bool(0)
+}
+
 // Настройка тестового окружения
-func setupAuthTest() (*gin.Engine, *MockJWTManager, *MockAuthQueries, *MockPasswordChecker) {
+func setupAuthTest() (*gin.Engine, *MockJWTManager, *MockAuthQueries, *MockPasswordChecker, *MockSessionIssuer) {
 	gin.SetMode(gin.TestMode)
 	r := gin.Default()
 
 	jwtManager := new(MockJWTManager)
 	authQueries := new(MockAuthQueries)
 	passwordChecker := new(MockPasswordChecker)
+	sessions := new(MockSessionIssuer)
 
-	authHandler := NewAuthHandler(jwtManager, authQueries, passwordChecker)
+	authHandler := NewAuthHandler(jwtManager, authQueries, passwordChecker, sessions, stubChallenger{}, []string{"employee", "moderator"}, discardLoginAudit{}, "")
 
 	r.POST("/dummyLogin", authHandler.DummyLogin)
 	r.POST("/register", authHandler.Register)
 	r.POST("/login", authHandler.Login)
+	r.POST("/auth/refresh", authHandler.RefreshToken)
 
-	return r, jwtManager, authQueries, passwordChecker
+	return r, jwtManager, authQueries, passwordChecker, sessions
 }
 
 // TestDummyLoginSuccess проверяет успешный сценарий для dummyLogin
 func TestDummyLoginSuccess(t *testing.T) {
-	r, jwtManager, _, _ := setupAuthTest()
+	r, jwtManager, _, _, _ := setupAuthTest()
 
 	// Настраиваем мок JWTManager для возврата токена
 	jwtManager.On("GenerateDummyToken", "employee").Return("test-dummy-token", nil)
@@ -120,7 +224,7 @@ func TestDummyLoginSuccess(t *testing.T) {
 
 // TestDummyLoginInvalidRole проверяет сценарий с некорректной ролью
 func TestDummyLoginInvalidRole(t *testing.T) {
-	r, _, _, _ := setupAuthTest()
+	r, _, _, _, _ := setupAuthTest()
 
 	// Создаем запрос с некорректной ролью
 	loginReq := map[string]string{
@@ -145,7 +249,7 @@ func TestDummyLoginInvalidRole(t *testing.T) {
 
 // TestDummyLoginJWTError проверяет сценарий с ошибкой генерации JWT
 func TestDummyLoginJWTError(t *testing.T) {
-	r, jwtManager, _, _ := setupAuthTest()
+	r, jwtManager, _, _, _ := setupAuthTest()
 
 	// Настраиваем мок JWTManager для возврата ошибки
 	jwtManager.On("GenerateDummyToken", "moderator").Return("", errors.New("jwt generation error"))
@@ -175,15 +279,36 @@ func TestDummyLoginJWTError(t *testing.T) {
 	jwtManager.AssertExpectations(t)
 }
 
+// TestDummyLoginRoleNotAllowed проверяет отказ для роли вне списка разрешенных
+func TestDummyLoginRoleNotAllowed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	jwtManager := new(MockJWTManager)
+	authHandler := NewAuthHandler(jwtManager, new(MockAuthQueries), new(MockPasswordChecker), new(MockSessionIssuer), stubChallenger{}, []string{"employee"}, discardLoginAudit{}, "")
+	r.POST("/dummyLogin", authHandler.DummyLogin)
+
+	jsonData, _ := json.Marshal(models.DummyLoginRequest{Role: "moderator"})
+	req, _ := http.NewRequest("POST", "/dummyLogin", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	jwtManager.AssertNotCalled(t, "GenerateDummyToken", mock.Anything)
+}
+
 // Продолжение файла internal/api/handlers/auth_test.go
 
 // TestRegisterSuccess проверяет успешный сценарий регистрации
 func TestRegisterSuccess(t *testing.T) {
-	r, _, authQueries, _ := setupAuthTest()
+	r, _, authQueries, passwordChecker, _ := setupAuthTest()
 
 	// Настраиваем моки
 	authQueries.On("GetUserByEmail", mock.Anything, "new@example.com").Return(false, nil)
-	authQueries.On("CreateUser", mock.Anything, "new@example.com", mock.AnythingOfType("string"), "employee").Return("test-uuid", nil)
+	passwordChecker.On("HashPassword", "secure_password").Return("password-hash", nil)
+	authQueries.On("CreateUser", mock.Anything, "new@example.com", "", "password-hash", "employee").Return("test-uuid", nil)
 
 	// Создаем запрос
 	registerReq := models.RegisterRequest{
@@ -213,9 +338,58 @@ func TestRegisterSuccess(t *testing.T) {
 	authQueries.AssertExpectations(t)
 }
 
+// TestRegisterWithPhone проверяет регистрацию сотрудника только по телефону
+func TestRegisterWithPhone(t *testing.T) {
+	r, _, authQueries, passwordChecker, _ := setupAuthTest()
+
+	authQueries.On("GetUserByPhone", mock.Anything, "+79990001122").Return(nil, queries.ErrNotFound)
+	passwordChecker.On("HashPassword", "secure_password").Return("password-hash", nil)
+	authQueries.On("CreateUser", mock.Anything, "", "+79990001122", "password-hash", "employee").Return("test-uuid", nil)
+
+	jsonData, _ := json.Marshal(models.RegisterRequest{
+		Phone:    "8 999 000-11-22",
+		Password: "secure_password",
+		Role:     "employee",
+	})
+	req, _ := http.NewRequest("POST", "/register", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response models.RegisterResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "+79990001122", response.Phone)
+	authQueries.AssertNotCalled(t, "GetUserByEmail", mock.Anything, mock.Anything)
+	authQueries.AssertExpectations(t)
+}
+
+// TestRegisterPhoneTaken проверяет отказ при занятом телефоне
+func TestRegisterPhoneTaken(t *testing.T) {
+	r, _, authQueries, _, _ := setupAuthTest()
+
+	authQueries.On("GetUserByPhone", mock.Anything, "+79990001122").Return(&models.User{ID: "other"}, nil)
+
+	jsonData, _ := json.Marshal(models.RegisterRequest{
+		Phone:    "+79990001122",
+		Password: "secure_password",
+		Role:     "employee",
+	})
+	req, _ := http.NewRequest("POST", "/register", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	authQueries.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 // TestRegisterUserAlreadyExists проверяет сценарий с уже существующим пользователем
 func TestRegisterUserAlreadyExists(t *testing.T) {
-	r, _, authQueries, _ := setupAuthTest()
+	r, _, authQueries, _, _ := setupAuthTest()
 
 	// Настраиваем моки - пользователь уже существует
 	authQueries.On("GetUserByEmail", mock.Anything, "existing@example.com").Return(true, nil)
@@ -248,7 +422,7 @@ func TestRegisterUserAlreadyExists(t *testing.T) {
 
 // TestRegisterInvalidData проверяет сценарий с некорректными данными
 func TestRegisterInvalidData(t *testing.T) {
-	r, _, _, _ := setupAuthTest()
+	r, _, _, _, _ := setupAuthTest()
 
 	// Создаем запрос с некорректными данными
 	registerReq := map[string]string{
@@ -275,7 +449,7 @@ func TestRegisterInvalidData(t *testing.T) {
 
 // TestRegisterDatabaseError проверяет сценарий с ошибкой базы данных
 func TestRegisterDatabaseError(t *testing.T) {
-	r, _, authQueries, _ := setupAuthTest()
+	r, _, authQueries, _, _ := setupAuthTest()
 
 	// Настраиваем моки - ошибка при проверке пользователя
 	authQueries.On("GetUserByEmail", mock.Anything, "error@example.com").Return(false, errors.New("database error"))
@@ -308,7 +482,7 @@ func TestRegisterDatabaseError(t *testing.T) {
 
 // TestLoginSuccess проверяет успешный вход в систему
 func TestLoginSuccess(t *testing.T) {
-	r, jwtManager, authQueries, passworcChecker := setupAuthTest()
+	r, _, authQueries, passworcChecker, sessions := setupAuthTest()
 
 	// Создаем тестового пользователя
 	testUser := &models.User{
@@ -316,12 +490,14 @@ func TestLoginSuccess(t *testing.T) {
 		Email:        "user@example.com",
 		Role:         "employee",
 		PasswordHash: "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy", // хеш для пароля "password123"
+		IsActive:     true,
 	}
 
 	// Настраиваем моки
 	authQueries.On("GetUserWithCredentials", mock.Anything, "user@example.com").Return(testUser, nil)
-	jwtManager.On("GenerateToken", "test-uuid", "employee").Return("test-token", nil)
+	sessions.On("IssueToken", mock.Anything, "test-uuid", "employee", mock.Anything, mock.Anything, false).Return(&models.LoginResponse{Token: "test-token"}, nil)
 	passworcChecker.On("CheckPassword", "password123", mock.Anything).Return(nil)
+	passworcChecker.On("NeedsRehash", testUser.PasswordHash).Return(false)
 
 	// Создаем запрос
 	loginReq := models.LoginRequest{
@@ -346,12 +522,98 @@ func TestLoginSuccess(t *testing.T) {
 
 	// Проверяем, что моки были вызваны с правильными аргументами
 	authQueries.AssertExpectations(t)
-	jwtManager.AssertExpectations(t)
+	sessions.AssertExpectations(t)
+}
+
+// TestLoginTwoFactorRequired проверяет, что с включенной двухфакторной аутентификацией
+// вместо токена выдается токен второго этапа входа
+func TestLoginTwoFactorRequired(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	authQueries := new(MockAuthQueries)
+	passwordChecker := new(MockPasswordChecker)
+	sessions := new(MockSessionIssuer)
+	challenge := &models.TwoFactorChallengeResponse{TwoFactorRequired: true, ChallengeToken: "challenge-token", ExpiresAt: time.Date(2026, 3, 2, 10, 5, 0, 0, time.UTC)}
+	authHandler := NewAuthHandler(new(MockJWTManager), authQueries, passwordChecker, sessions, stubChallenger{challenge: challenge}, nil, discardLoginAudit{}, "")
+	r.POST("/login", authHandler.Login)
+
+	testUser := &models.User{ID: "test-uuid", Email: "moderator@example.com", Role: "moderator", PasswordHash: "hash", IsActive: true}
+	authQueries.On("GetUserWithCredentials", mock.Anything, "moderator@example.com").Return(testUser, nil)
+	passwordChecker.On("CheckPassword", "password123", "hash").Return(nil)
+	passwordChecker.On("NeedsRehash", "hash").Return(false)
+
+	w := postJSON(r, "/login", models.LoginRequest{Email: "moderator@example.com", Password: "password123"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"twoFactorRequired":true,"challengeToken":"challenge-token","expiresAt":"2026-03-02T10:05:00Z"}`, w.Body.String())
+	sessions.AssertNotCalled(t, "IssueToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestLoginDeactivatedUser проверяет, что деактивированный пользователь не получает токен
+func TestLoginDeactivatedUser(t *testing.T) {
+	r, _, authQueries, passwordChecker, sessions := setupAuthTest()
+
+	testUser := &models.User{
+		ID:           "test-uuid",
+		Email:        "user@example.com",
+		Role:         "employee",
+		PasswordHash: "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy",
+	}
+	authQueries.On("GetUserWithCredentials", mock.Anything, "user@example.com").Return(testUser, nil)
+	passwordChecker.On("CheckPassword", "password123", testUser.PasswordHash).Return(nil)
+
+	jsonData, _ := json.Marshal(models.LoginRequest{Email: "user@example.com", Password: "password123"})
+	req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	var response models.ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Пользователь деактивирован", response.Message)
+	sessions.AssertNotCalled(t, "IssueToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestLoginRehashesPassword проверяет пересчет хеша, созданного с устаревшими параметрами
+func TestLoginRehashesPassword(t *testing.T) {
+	r, _, authQueries, passwordChecker, sessions := setupAuthTest()
+
+	testUser := &models.User{
+		ID:           "test-uuid",
+		Email:        "user@example.com",
+		Role:         "employee",
+		PasswordHash: "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy",
+		IsActive:     true,
+	}
+
+	authQueries.On("GetUserWithCredentials", mock.Anything, "user@example.com").Return(testUser, nil)
+	passwordChecker.On("CheckPassword", "password123", testUser.PasswordHash).Return(nil)
+	passwordChecker.On("NeedsRehash", testUser.PasswordHash).Return(true)
+	passwordChecker.On("HashPassword", "password123").Return("$argon2id$new-hash", nil)
+	// Ошибка сохранения нового хеша не мешает входу
+	authQueries.On("UpdatePasswordHash", mock.Anything, "test-uuid", "$argon2id$new-hash").Return(errors.New("db error"))
+	sessions.On("IssueToken", mock.Anything, "test-uuid", "employee", mock.Anything, mock.Anything, false).Return(&models.LoginResponse{Token: "test-token"}, nil)
+
+	jsonData, _ := json.Marshal(models.LoginRequest{
+		Email:    "user@example.com",
+		Password: "password123",
+	})
+	req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	authQueries.AssertExpectations(t)
+	passwordChecker.AssertExpectations(t)
 }
 
 // TestLoginUserNotFound проверяет сценарий с несуществующим пользователем
 func TestLoginUserNotFound(t *testing.T) {
-	r, _, authQueries, _ := setupAuthTest()
+	r, _, authQueries, _, _ := setupAuthTest()
 
 	// Настраиваем моки - пользователь не найден
 	authQueries.On("GetUserWithCredentials", mock.Anything, "nonexistent@example.com").Return(nil, errors.New("user not found"))
@@ -383,7 +645,7 @@ func TestLoginUserNotFound(t *testing.T) {
 
 // TestLoginInvalidPassword проверяет сценарий с неверным паролем
 func TestLoginInvalidPassword(t *testing.T) {
-	r, _, authQueries, passworcChecker := setupAuthTest()
+	r, _, authQueries, passworcChecker, _ := setupAuthTest()
 
 	// Создаем тестового пользователя с хешем для пароля "password123"
 	testUser := &models.User{
@@ -391,6 +653,7 @@ func TestLoginInvalidPassword(t *testing.T) {
 		Email:        "user@example.com",
 		Role:         "employee",
 		PasswordHash: "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy",
+		IsActive:     true,
 	}
 
 	// Настраиваем моки
@@ -424,7 +687,7 @@ func TestLoginInvalidPassword(t *testing.T) {
 
 // TestLoginTokenError проверяет сценарий с ошибкой генерации токена
 func TestLoginTokenError(t *testing.T) {
-	r, jwtManager, authQueries, passwordChecker := setupAuthTest()
+	r, _, authQueries, passwordChecker, sessions := setupAuthTest()
 
 	// Создаем тестового пользователя
 	testUser := &models.User{
@@ -432,12 +695,14 @@ func TestLoginTokenError(t *testing.T) {
 		Email:        "user@example.com",
 		Role:         "employee",
 		PasswordHash: "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy", // хеш для пароля "password123"
+		IsActive:     true,
 	}
 
 	// Настраиваем моки
 	authQueries.On("GetUserWithCredentials", mock.Anything, "user@example.com").Return(testUser, nil)
-	jwtManager.On("GenerateToken", "test-uuid", "employee").Return("", errors.New("token generation error"))
+	sessions.On("IssueToken", mock.Anything, "test-uuid", "employee", mock.Anything, mock.Anything, false).Return(nil, errors.New("token generation error"))
 	passwordChecker.On("CheckPassword", "password123", testUser.PasswordHash).Return(nil)
+	passwordChecker.On("NeedsRehash", testUser.PasswordHash).Return(false)
 
 	// Создаем запрос
 	loginReq := models.LoginRequest{
@@ -462,12 +727,12 @@ func TestLoginTokenError(t *testing.T) {
 
 	// Проверяем, что моки были вызваны с правильными аргументами
 	authQueries.AssertExpectations(t)
-	jwtManager.AssertExpectations(t)
+	sessions.AssertExpectations(t)
 }
 
 // TestLoginInvalidRequest проверяет сценарий с некорректными данными запроса
 func TestLoginInvalidRequest(t *testing.T) {
-	r, _, _, _ := setupAuthTest()
+	r, _, _, _, _ := setupAuthTest()
 
 	// Создаем запрос с некорректными данными
 	loginReq := map[string]string{
@@ -490,3 +755,31 @@ func TestLoginInvalidRequest(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Contains(t, response.Message, "Неверный запрос")
 }
+
+// TestRefreshToken проверяет обмен refresh-токена и отказ для использованного токена
+func TestRefreshToken(t *testing.T) {
+	r, _, _, _, sessions := setupAuthTest()
+
+	sessions.On("RefreshToken", mock.Anything, "valid").
+		Return(&models.LoginResponse{Token: "new-token", RefreshToken: "next"}, nil)
+	sessions.On("RefreshToken", mock.Anything, "used").Return(nil, service.ErrRefreshTokenInvalid)
+
+	for _, tt := range []struct {
+		body string
+		code int
+	}{
+		{`{"refreshToken": "valid"}`, http.StatusOK},
+		{`{"refreshToken": "used"}`, http.StatusUnauthorized},
+		{`{}`, http.StatusBadRequest},
+	} {
+		req, _ := http.NewRequest("POST", "/auth/refresh", bytes.NewBufferString(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.body)
+		if tt.code == http.StatusOK {
+			assert.JSONEq(t, `{"token": "new-token", "refreshToken": "next"}`, w.Body.String())
+		}
+	}
+}
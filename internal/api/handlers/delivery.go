@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeliveryHandler содержит обработчики разбора неудачных исходящих доставок
+type DeliveryHandler struct {
+	deliveryQueries queries.DeliveryQueriesInterface
+}
+
+// NewDeliveryHandler создает новый экземпляр DeliveryHandler
+func NewDeliveryHandler(deliveryQueries queries.DeliveryQueriesInterface) *DeliveryHandler {
+	return &DeliveryHandler{
+		deliveryQueries: deliveryQueries,
+	}
+}
+
+// GetDeliveries обрабатывает запрос на получение неудачных доставок: ожидающих повтора или в dead-letter queue
+func (h *DeliveryHandler) GetDeliveries(c *gin.Context) {
+	var query models.DeliveryListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+
+	deliveries, err := h.deliveryQueries.GetDeliveries(c.Request.Context(), query)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetDeliveriesFailed, err))
+		return
+	}
+
+	if deliveries == nil {
+		deliveries = []models.FailedDelivery{}
+	}
+
+	response.JSON(c, http.StatusOK, deliveries)
+}
+
+// RequeueDelivery обрабатывает запрос на возврат доставки в очередь повторов с немедленной попыткой
+func (h *DeliveryHandler) RequeueDelivery(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("deliveryId"), 10, 64)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgDeliveryNotFound))
+		return
+	}
+
+	err = h.deliveryQueries.RequeueDelivery(c.Request.Context(), id)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgDeliveryNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgRequeueDeliveryFailed, err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
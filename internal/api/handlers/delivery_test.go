@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+)
+
+// MockDeliveryQueries мокирует запросы для работы с неудачными доставками
+type MockDeliveryQueries struct {
+	queries.DeliveryQueriesInterface
+	mock.Mock
+}
+
+func (m *MockDeliveryQueries) GetDeliveries(ctx context.Context, params models.DeliveryListQuery) ([]models.FailedDelivery, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.FailedDelivery), args.Error(1)
+}
+
+func (m *MockDeliveryQueries) RequeueDelivery(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// Настройка тестового окружения
+func setupDeliveryHandlerTest() (*gin.Engine, *MockDeliveryQueries) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	deliveryQueries := new(MockDeliveryQueries)
+	deliveryHandler := NewDeliveryHandler(deliveryQueries)
+
+	r.GET("/admin/deliveries", deliveryHandler.GetDeliveries)
+	r.POST("/admin/deliveries/:deliveryId/requeue", deliveryHandler.RequeueDelivery)
+
+	return r, deliveryQueries
+}
+
+// TestGetDeliveries проверяет список доставок из dead-letter queue
+func TestGetDeliveries(t *testing.T) {
+	r, deliveryQueries := setupDeliveryHandlerTest()
+
+	deliveryQueries.On("GetDeliveries", mock.Anything, models.DeliveryListQuery{Status: models.DeliveryStatusDead}).
+		Return([]models.FailedDelivery{{
+			ID:            3,
+			Target:        models.DeliveryTargetAlerts,
+			Event:         json.RawMessage(`{"id":1}`),
+			Status:        models.DeliveryStatusDead,
+			Attempts:      10,
+			NextAttemptAt: time.Now(),
+		}}, nil)
+
+	req, _ := http.NewRequest("GET", "/admin/deliveries?status=dead", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result []models.FailedDelivery
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Len(t, result, 1)
+	assert.Equal(t, int64(3), result[0].ID)
+}
+
+// TestGetDeliveriesInvalidStatus проверяет отклонение неизвестного статуса
+func TestGetDeliveriesInvalidStatus(t *testing.T) {
+	r, deliveryQueries := setupDeliveryHandlerTest()
+
+	req, _ := http.NewRequest("GET", "/admin/deliveries?status=sent", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	deliveryQueries.AssertNotCalled(t, "GetDeliveries", mock.Anything, mock.Anything)
+}
+
+// TestRequeueDelivery проверяет возврат доставки в очередь и ответ для неизвестной доставки
+func TestRequeueDelivery(t *testing.T) {
+	r, deliveryQueries := setupDeliveryHandlerTest()
+
+	deliveryQueries.On("RequeueDelivery", mock.Anything, int64(3)).Return(nil)
+	deliveryQueries.On("RequeueDelivery", mock.Anything, int64(4)).
+		Return(fmt.Errorf("delivery 4: %w", queries.ErrNotFound))
+
+	for path, code := range map[string]int{
+		"/admin/deliveries/3/requeue":   http.StatusNoContent,
+		"/admin/deliveries/4/requeue":   http.StatusNotFound,
+		"/admin/deliveries/abc/requeue": http.StatusNotFound,
+	} {
+		req, _ := http.NewRequest("POST", path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, code, w.Code, path)
+	}
+}
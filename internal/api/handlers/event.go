@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/events"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EventHandler содержит обработчики ленты событий ПВЗ
+type EventHandler struct {
+	poller      events.Poller
+	pollTimeout time.Duration
+}
+
+// NewEventHandler создает новый экземпляр EventHandler
+func NewEventHandler(poller events.Poller, pollTimeout time.Duration) *EventHandler {
+	return &EventHandler{
+		poller:      poller,
+		pollTimeout: pollTimeout,
+	}
+}
+
+// Poll обрабатывает запрос long-polling: возвращает события ПВЗ после курсора
+// или пустой список, если за время ожидания событий не появилось
+func (h *EventHandler) Poll(c *gin.Context) {
+	pvzID := c.Param("pvzId")
+
+	// Проверяем, что pvzId указан
+	if pvzID == "" {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgPVZIDRequired))
+		return
+	}
+
+	var query models.EventPollQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+
+	eventList, cursor := h.poller.Poll(c.Request.Context(), pvzID, query.Cursor, h.pollTimeout)
+	if eventList == nil {
+		eventList = []models.Event{}
+	}
+
+	response.JSON(c, http.StatusOK, models.EventPollResponse{
+		Events: eventList,
+		Cursor: cursor,
+	})
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportPageSize - количество событий, которое выгрузка читает из БД за один запрос
+const exportPageSize = 1000
+
+// exportMaxRows ограничивает количество событий в одном ответе выгрузки
+const exportMaxRows = 1000000
+
+// EventExportHandler содержит обработчик выгрузки истории событий
+type EventExportHandler struct {
+	outboxQueries queries.OutboxQueriesInterface
+	pageSize      int
+	maxRows       int
+}
+
+// NewEventExportHandler создает новый экземпляр EventExportHandler
+func NewEventExportHandler(outboxQueries queries.OutboxQueriesInterface) *EventExportHandler {
+	return &EventExportHandler{
+		outboxQueries: outboxQueries,
+		pageSize:      exportPageSize,
+		maxRows:       exportMaxRows,
+	}
+}
+
+// Export обрабатывает запрос на выгрузку событий за период в формате NDJSON: по одному событию в строке.
+// События читаются из БД страницами и отправляются клиенту по мере записи, поэтому медленный клиент
+// задерживает чтение следующей страницы, а не накапливает выгрузку в памяти сервера.
+// Если выгрузка прервалась или достигла ограничения maxRows (передается в заголовке X-Row-Limit),
+// ее можно продолжить с параметром after, равным ID последнего полученного события.
+// С параметром download=true выгрузка отдается файлом
+func (h *EventExportHandler) Export(c *gin.Context) {
+	var query models.EventExportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// Первая страница читается до отправки заголовков, чтобы ошибка БД вернулась статусом 500
+	page, err := h.outboxQueries.GetEventsPage(ctx, query.From, query.To, query.After, h.pageSize)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgExportEventsFailed, err))
+		return
+	}
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("X-Row-Limit", strconv.Itoa(h.maxRows))
+	if query.Download {
+		response.Attachment(c, fmt.Sprintf("events-%s-%s.ndjson", query.From.UTC().Format("20060102T150405Z"), query.To.UTC().Format("20060102T150405Z")))
+	}
+	c.Status(http.StatusOK)
+
+	encoder := json.NewEncoder(c.Writer)
+	written := 0
+	for {
+		if len(page) > h.maxRows-written {
+			page = page[:h.maxRows-written]
+		}
+		for _, outboxEvent := range page {
+			err := encoder.Encode(models.Event{
+				ID:        outboxEvent.ID,
+				DedupID:   outboxEvent.EventID,
+				PvzID:     outboxEvent.PvzID,
+				Type:      outboxEvent.Type,
+				Payload:   json.RawMessage(outboxEvent.Payload),
+				CreatedAt: outboxEvent.CreatedAt,
+			})
+			if err != nil {
+				// Клиент закрыл соединение
+				return
+			}
+		}
+		c.Writer.Flush()
+		written += len(page)
+
+		if len(page) < h.pageSize || written >= h.maxRows || ctx.Err() != nil {
+			return
+		}
+
+		page, err = h.outboxQueries.GetEventsPage(ctx, query.From, query.To, page[len(page)-1].ID, h.pageSize)
+		if err != nil {
+			// Статус уже отправлен: клиент увидит оборванную выгрузку и продолжит ее с параметром after
+			log.Printf("Event export interrupted: %v", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+)
+
+// pagedOutbox отдает сохраненные события страницами, как GetEventsPage
+type pagedOutbox struct {
+	queries.OutboxQueriesInterface
+	events []models.OutboxEvent
+	pages  int
+	err    error
+}
+
+func (o *pagedOutbox) GetEventsPage(ctx context.Context, from, to time.Time, afterID int64, limit int) ([]models.OutboxEvent, error) {
+	o.pages++
+	if o.err != nil {
+		return nil, o.err
+	}
+
+	var page []models.OutboxEvent
+	for _, event := range o.events {
+		if event.ID > afterID && len(page) < limit {
+			page = append(page, event)
+		}
+	}
+	return page, nil
+}
+
+func setupEventExportTest(outbox *pagedOutbox) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	eventExportHandler := NewEventExportHandler(outbox)
+	eventExportHandler.pageSize = 2
+	r.GET("/admin/events/export", eventExportHandler.Export)
+
+	return r
+}
+
+// TestExportEvents проверяет выгрузку всех страниц событий построчно в NDJSON
+func TestExportEvents(t *testing.T) {
+	outbox := &pagedOutbox{}
+	for id := int64(1); id <= 5; id++ {
+		outbox.events = append(outbox.events, models.OutboxEvent{
+			ID:      id,
+			EventID: fmt.Sprintf("event-%d", id),
+			PvzID:   "pvz-1",
+			Type:    models.EventProductAdded,
+			Payload: []byte(`{"id":"product-1"}`),
+		})
+	}
+	r := setupEventExportTest(outbox)
+
+	req, _ := http.NewRequest("GET", "/admin/events/export?from=2025-01-01T00:00:00Z&to=2025-02-01T00:00:00Z&after=1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+	var ids []int64
+	scanner := bufio.NewScanner(strings.NewReader(w.Body.String()))
+	for scanner.Scan() {
+		var event models.Event
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		assert.Equal(t, map[string]interface{}{"id": "product-1"}, event.Payload)
+		ids = append(ids, event.ID)
+	}
+	assert.Equal(t, []int64{2, 3, 4, 5}, ids)
+	assert.Equal(t, 3, outbox.pages)
+}
+
+// TestExportEventsRowLimit проверяет остановку выгрузки на ограничении и выгрузку файлом
+func TestExportEventsRowLimit(t *testing.T) {
+	outbox := &pagedOutbox{}
+	for id := int64(1); id <= 5; id++ {
+		outbox.events = append(outbox.events, models.OutboxEvent{ID: id, Payload: []byte(`{}`)})
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	eventExportHandler := NewEventExportHandler(outbox)
+	eventExportHandler.pageSize = 2
+	eventExportHandler.maxRows = 3
+	r.GET("/admin/events/export", eventExportHandler.Export)
+
+	req, _ := http.NewRequest("GET", "/admin/events/export?from=2025-01-01T00:00:00Z&to=2025-02-01T00:00:00Z&download=true", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "3", w.Header().Get("X-Row-Limit"))
+	assert.Equal(t, `attachment; filename="events-20250101T000000Z-20250201T000000Z.ndjson"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, 3, strings.Count(w.Body.String(), "\n"))
+	assert.Equal(t, 2, outbox.pages)
+}
+
+// TestExportEventsInvalidPeriod проверяет отклонение периода без границ или с обратным порядком
+func TestExportEventsInvalidPeriod(t *testing.T) {
+	r := setupEventExportTest(&pagedOutbox{})
+
+	for _, path := range []string{
+		"/admin/events/export?from=2025-01-01T00:00:00Z",
+		"/admin/events/export?from=2025-02-01T00:00:00Z&to=2025-01-01T00:00:00Z",
+	} {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+	}
+}
+
+// TestExportEventsError проверяет ответ 500, если первая страница не прочитана
+func TestExportEventsError(t *testing.T) {
+	r := setupEventExportTest(&pagedOutbox{err: errors.New("database error")})
+
+	req, _ := http.NewRequest("GET", "/admin/events/export?from=2025-01-01T00:00:00Z&to=2025-02-01T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/events"
+	"pvz-service/internal/models"
+	"pvz-service/internal/storage"
+)
+
+func setupEventTest(pollTimeout time.Duration) (*gin.Engine, *events.Hub) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	hub := events.NewHub(10)
+	eventHandler := NewEventHandler(hub, pollTimeout)
+	r.GET("/pvz/:pvzId/events/poll", eventHandler.Poll)
+
+	return r, hub
+}
+
+// TestPollEventsReturnsPublished проверяет выдачу событий после курсора
+func TestPollEventsReturnsPublished(t *testing.T) {
+	r, hub := setupEventTest(time.Second)
+	first := hub.Publish("pvz1", models.EventReceptionCreated, nil)
+	hub.Publish("pvz1", models.EventProductAdded, nil)
+
+	req, _ := http.NewRequest("GET", "/pvz/pvz1/events/poll?cursor=1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.EventPollResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Events, 1)
+	assert.Equal(t, models.EventProductAdded, response.Events[0].Type)
+	assert.Equal(t, first.ID+1, response.Cursor)
+}
+
+// TestPollEventsTimeout проверяет пустой ответ по истечении ожидания
+func TestPollEventsTimeout(t *testing.T) {
+	r, _ := setupEventTest(10 * time.Millisecond)
+
+	req, _ := http.NewRequest("GET", "/pvz/pvz1/events/poll", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"events":[],"cursor":0}`, w.Body.String())
+}
+
+// TestPollEventsInvalidCursor проверяет валидацию курсора
+func TestPollEventsInvalidCursor(t *testing.T) {
+	r, _ := setupEventTest(time.Second)
+
+	req, _ := http.NewRequest("GET", "/pvz/pvz1/events/poll?cursor=abc", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// passthroughTx выполняет функцию без транзакции
+type passthroughTx struct{}
+
+func (passthroughTx) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// alwaysOpen - ПВЗ без ограничения часов работы
+type alwaysOpen struct{}
+
+func (alwaysOpen) CheckOpen(ctx context.Context, pvzID string) error {
+	return nil
+}
+
+// noQuotas - операции без квот
+type noQuotas struct{}
+
+func (noQuotas) Consume(ctx context.Context, userID, role, operation string, count int) error {
+	return nil
+}
+
+// noLocks - блокировки всегда свободны
+type noLocks struct{}
+
+func (noLocks) Lock(ctx context.Context, key string) error {
+	return nil
+}
+
+// recordingOutbox запоминает события, записанные обработчиками в outbox
+type recordingOutbox struct {
+	queries.OutboxQueriesInterface
+	events []models.Event
+	err    error
+}
+
+func (o *recordingOutbox) AddEvent(ctx context.Context, pvzID, eventType string, payload interface{}) error {
+	o.events = append(o.events, models.Event{PvzID: pvzID, Type: eventType, Payload: payload})
+	return o.err
+}
+
+// TestAddProductRecordsEvent проверяет запись события в outbox при добавлении товара
+func TestAddProductRecordsEvent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	productQueries := new(MockProductQueries)
+	receptionQueries := new(MockReceptionQueries)
+	outbox := &recordingOutbox{}
+	productHandler := newTestProductHandler(productQueries, receptionQueries, outbox, storage.Disabled{}, productLimits{}, alwaysOpen{}, noQuotas{}, noLocks{})
+	r.POST("/products", func(c *gin.Context) {
+		c.Set("userRole", "employee")
+		productHandler.AddProduct(c)
+	})
+
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	receptionQueries.On("GetLastOpenReception", mock.Anything, pvzID).
+		Return(&models.Reception{ID: "reception-uuid", PvzID: pvzID, Status: "in_progress"}, nil)
+	receptionQueries.On("LockReceptions", mock.Anything, mock.Anything).Return([]models.Reception{{Status: "in_progress"}}, nil)
+	productQueries.On("AddProduct", mock.Anything, "reception-uuid", mock.Anything, "обувь", "").
+		Return(&models.Product{ID: "product-uuid", Type: "обувь", ReceptionID: "reception-uuid"}, nil)
+
+	body, _ := json.Marshal(models.CreateProductRequest{Type: "обувь", PvzID: pvzID})
+	req, _ := http.NewRequest("POST", "/products", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	assert.Len(t, outbox.events, 1)
+	assert.Equal(t, pvzID, outbox.events[0].PvzID)
+	assert.Equal(t, models.EventProductAdded, outbox.events[0].Type)
+}
+
+// TestAddProductOutboxError проверяет, что товар не считается добавленным без записи события
+func TestAddProductOutboxError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	productQueries := new(MockProductQueries)
+	receptionQueries := new(MockReceptionQueries)
+	outbox := &recordingOutbox{err: errors.New("outbox unavailable")}
+	productHandler := newTestProductHandler(productQueries, receptionQueries, outbox, storage.Disabled{}, productLimits{}, alwaysOpen{}, noQuotas{}, noLocks{})
+	r.POST("/products", func(c *gin.Context) {
+		c.Set("userRole", "employee")
+		productHandler.AddProduct(c)
+	})
+
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	receptionQueries.On("GetLastOpenReception", mock.Anything, pvzID).
+		Return(&models.Reception{ID: "reception-uuid", PvzID: pvzID, Status: "in_progress"}, nil)
+	receptionQueries.On("LockReceptions", mock.Anything, mock.Anything).Return([]models.Reception{{Status: "in_progress"}}, nil)
+	productQueries.On("AddProduct", mock.Anything, "reception-uuid", mock.Anything, "обувь", "").
+		Return(&models.Product{ID: "product-uuid", Type: "обувь", ReceptionID: "reception-uuid"}, nil)
+
+	body, _ := json.Marshal(models.CreateProductRequest{Type: "обувь", PvzID: pvzID})
+	req, _ := http.NewRequest("POST", "/products", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
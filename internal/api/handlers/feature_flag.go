@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/db"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/featureflags"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// featureFlagName - допустимое имя флага, например returns_workflow
+var featureFlagName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// FeatureFlagHandler содержит обработчики управления флагами функций
+type FeatureFlagHandler struct {
+	flagQueries queries.FeatureFlagQueriesInterface
+	pvzQueries  queries.PVZQueriesInterface
+	tx          db.Transactor
+	flags       featureflags.FlagsInterface
+}
+
+// NewFeatureFlagHandler создает новый экземпляр FeatureFlagHandler
+func NewFeatureFlagHandler(flagQueries queries.FeatureFlagQueriesInterface, pvzQueries queries.PVZQueriesInterface, tx db.Transactor, flags featureflags.FlagsInterface) *FeatureFlagHandler {
+	return &FeatureFlagHandler{
+		flagQueries: flagQueries,
+		pvzQueries:  pvzQueries,
+		tx:          tx,
+		flags:       flags,
+	}
+}
+
+// GetFlags обрабатывает запрос на получение всех флагов с их значениями для ПВЗ
+func (h *FeatureFlagHandler) GetFlags(c *gin.Context) {
+	flags, err := h.flagQueries.GetFlags(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetFeatureFlagsFailed, err))
+		return
+	}
+
+	if flags == nil {
+		flags = []models.FeatureFlag{}
+	}
+	for i := range flags {
+		if flags[i].PVZ == nil {
+			flags[i].PVZ = []models.FeatureFlagPVZ{}
+		}
+	}
+
+	response.JSON(c, http.StatusOK, flags)
+}
+
+// UpdateFlag обрабатывает запрос на изменение флага: общего значения, описания и значений для ПВЗ.
+// Флаг создается при первом изменении
+func (h *FeatureFlagHandler) UpdateFlag(c *gin.Context) {
+	name := c.Param("name")
+	if !featureFlagName.MatchString(name) {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgInvalidFeatureFlagName))
+		return
+	}
+
+	var req models.UpdateFeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	// Значения для несуществующих ПВЗ не сохраняем
+	for _, pvzFlag := range req.PVZ {
+		_, err := h.pvzQueries.GetPVZ(c.Request.Context(), pvzFlag.PvzID)
+		if errors.Is(err, queries.ErrNotFound) {
+			response.Error(c, http.StatusNotFound, i18n.Wrap(c, i18n.MsgPVZNotFound, err))
+			return
+		}
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgUpdateFeatureFlagFailed, err))
+			return
+		}
+	}
+
+	var flag *models.FeatureFlag
+	err := h.tx.InTx(c.Request.Context(), func(ctx context.Context) error {
+		current, err := h.flagQueries.GetFlag(ctx, name)
+		if errors.Is(err, queries.ErrNotFound) {
+			current = &models.FeatureFlag{Name: name}
+		} else if err != nil {
+			return err
+		}
+
+		if req.Enabled != nil {
+			current.Enabled = *req.Enabled
+		}
+		if req.Description != nil {
+			current.Description = *req.Description
+		}
+		if _, err := h.flagQueries.UpsertFlag(ctx, *current); err != nil {
+			return err
+		}
+
+		for _, pvzFlag := range req.PVZ {
+			if pvzFlag.Enabled == nil {
+				err = h.flagQueries.DeletePVZFlag(ctx, name, pvzFlag.PvzID)
+			} else {
+				err = h.flagQueries.SetPVZFlag(ctx, name, pvzFlag.PvzID, *pvzFlag.Enabled)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		flag, err = h.flagQueries.GetFlag(ctx, name)
+		return err
+	})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgUpdateFeatureFlagFailed, err))
+		return
+	}
+
+	h.flags.Invalidate()
+
+	if flag.PVZ == nil {
+		flag.PVZ = []models.FeatureFlagPVZ{}
+	}
+	response.JSON(c, http.StatusOK, flag)
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+)
+
+// MockFeatureFlagQueries мокирует запросы для работы с флагами функций
+type MockFeatureFlagQueries struct {
+	mock.Mock
+}
+
+func (m *MockFeatureFlagQueries) GetFlags(ctx context.Context) ([]models.FeatureFlag, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.FeatureFlag), args.Error(1)
+}
+
+func (m *MockFeatureFlagQueries) GetFlag(ctx context.Context, name string) (*models.FeatureFlag, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FeatureFlag), args.Error(1)
+}
+
+func (m *MockFeatureFlagQueries) UpsertFlag(ctx context.Context, flag models.FeatureFlag) (*models.FeatureFlag, error) {
+	args := m.Called(ctx, flag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FeatureFlag), args.Error(1)
+}
+
+func (m *MockFeatureFlagQueries) SetPVZFlag(ctx context.Context, name, pvzID string, enabled bool) error {
+	args := m.Called(ctx, name, pvzID, enabled)
+	return args.Error(0)
+}
+
+func (m *MockFeatureFlagQueries) DeletePVZFlag(ctx context.Context, name, pvzID string) error {
+	args := m.Called(ctx, name, pvzID)
+	return args.Error(0)
+}
+
+// MockFeatureFlags мокирует кэш флагов функций
+type MockFeatureFlags struct {
+	mock.Mock
+}
+
+func (m *MockFeatureFlags) Enabled(ctx context.Context, name, pvzID string) bool {
+	args := m.Called(ctx, name, pvzID)
+	return args.Bool(0)
+}
+
+func (m *MockFeatureFlags) Invalidate() {
+	m.Called()
+}
+
+const testFlagPVZID = "123e4567-e89b-12d3-a456-426614174040"
+
+// Настройка тестового окружения
+func setupFeatureFlagTest() (*gin.Engine, *MockFeatureFlagQueries, *MockPVZQueries, *MockFeatureFlags) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	flagQueries := new(MockFeatureFlagQueries)
+	pvzQueries := new(MockPVZQueries)
+	flags := new(MockFeatureFlags)
+	featureFlagHandler := NewFeatureFlagHandler(flagQueries, pvzQueries, passthroughTx{}, flags)
+
+	r.GET("/admin/flags", featureFlagHandler.GetFlags)
+	r.PATCH("/admin/flags/:name", featureFlagHandler.UpdateFlag)
+
+	return r, flagQueries, pvzQueries, flags
+}
+
+// TestGetFeatureFlags проверяет список флагов
+func TestGetFeatureFlags(t *testing.T) {
+	r, flagQueries, _, _ := setupFeatureFlagTest()
+
+	flagQueries.On("GetFlags", mock.Anything).Return([]models.FeatureFlag{
+		{Name: "returns_workflow", Enabled: true},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/admin/flags", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"name":"returns_workflow","enabled":true,"description":"","updatedAt":"0001-01-01T00:00:00Z","pvz":[]}]`, w.Body.String())
+}
+
+// TestUpdateFeatureFlagCreates проверяет создание флага со значением для ПВЗ и сброс кэша
+func TestUpdateFeatureFlagCreates(t *testing.T) {
+	r, flagQueries, pvzQueries, flags := setupFeatureFlagTest()
+
+	pvzQueries.On("GetPVZ", mock.Anything, testFlagPVZID).Return(&models.PVZ{ID: testFlagPVZID}, nil)
+	flagQueries.On("GetFlag", mock.Anything, "returns_workflow").
+		Return(nil, fmt.Errorf("feature flag returns_workflow: %w", queries.ErrNotFound)).Once()
+	flagQueries.On("UpsertFlag", mock.Anything, models.FeatureFlag{Name: "returns_workflow", Description: "Новый процесс возвратов"}).
+		Return(&models.FeatureFlag{Name: "returns_workflow"}, nil)
+	flagQueries.On("SetPVZFlag", mock.Anything, "returns_workflow", testFlagPVZID, true).Return(nil)
+	flagQueries.On("GetFlag", mock.Anything, "returns_workflow").Return(&models.FeatureFlag{
+		Name:        "returns_workflow",
+		Description: "Новый процесс возвратов",
+		PVZ:         []models.FeatureFlagPVZ{{PvzID: testFlagPVZID, Enabled: true}},
+	}, nil)
+	flags.On("Invalidate").Return()
+
+	w := patchJSON(r, "/admin/flags/returns_workflow", `{"description": "Новый процесс возвратов", "pvz": [{"pvzId": "`+testFlagPVZID+`", "enabled": true}]}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var flag models.FeatureFlag
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &flag))
+	assert.False(t, flag.Enabled)
+	assert.Equal(t, []models.FeatureFlagPVZ{{PvzID: testFlagPVZID, Enabled: true}}, flag.PVZ)
+	flagQueries.AssertExpectations(t)
+	flags.AssertExpectations(t)
+}
+
+// TestUpdateFeatureFlagRemovesPVZValue проверяет, что enabled: null убирает значение для ПВЗ
+func TestUpdateFeatureFlagRemovesPVZValue(t *testing.T) {
+	r, flagQueries, pvzQueries, flags := setupFeatureFlagTest()
+
+	existing := &models.FeatureFlag{Name: "returns_workflow", Description: "Возвраты"}
+	pvzQueries.On("GetPVZ", mock.Anything, testFlagPVZID).Return(&models.PVZ{ID: testFlagPVZID}, nil)
+	flagQueries.On("GetFlag", mock.Anything, "returns_workflow").Return(existing, nil)
+	flagQueries.On("UpsertFlag", mock.Anything, models.FeatureFlag{Name: "returns_workflow", Enabled: true, Description: "Возвраты"}).
+		Return(existing, nil)
+	flagQueries.On("DeletePVZFlag", mock.Anything, "returns_workflow", testFlagPVZID).Return(nil)
+	flags.On("Invalidate").Return()
+
+	w := patchJSON(r, "/admin/flags/returns_workflow", `{"enabled": true, "pvz": [{"pvzId": "`+testFlagPVZID+`", "enabled": null}]}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	flagQueries.AssertExpectations(t)
+	flagQueries.AssertNotCalled(t, "SetPVZFlag", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestUpdateFeatureFlagUnknownPVZ проверяет, что значения для несуществующего ПВЗ не сохраняются
+func TestUpdateFeatureFlagUnknownPVZ(t *testing.T) {
+	r, flagQueries, pvzQueries, flags := setupFeatureFlagTest()
+
+	pvzQueries.On("GetPVZ", mock.Anything, testFlagPVZID).
+		Return(nil, fmt.Errorf("pvz %s: %w", testFlagPVZID, queries.ErrNotFound))
+
+	w := patchJSON(r, "/admin/flags/returns_workflow", `{"pvz": [{"pvzId": "`+testFlagPVZID+`", "enabled": true}]}`)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	flagQueries.AssertNotCalled(t, "UpsertFlag", mock.Anything, mock.Anything)
+	flags.AssertNotCalled(t, "Invalidate")
+}
+
+// TestUpdateFeatureFlagInvalidName проверяет проверку имени флага
+func TestUpdateFeatureFlagInvalidName(t *testing.T) {
+	r, flagQueries, _, _ := setupFeatureFlagTest()
+
+	w := patchJSON(r, "/admin/flags/Returns-Workflow", `{"enabled": true}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	flagQueries.AssertNotCalled(t, "GetFlag", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FileHandler отдает вложения локального хранилища по подписанным ссылкам
+type FileHandler struct {
+	downloader storage.Downloader
+}
+
+// NewFileHandler создает новый экземпляр FileHandler
+func NewFileHandler(downloader storage.Downloader) *FileHandler {
+	return &FileHandler{
+		downloader: downloader,
+	}
+}
+
+// Download отдает файл по ссылке, выданной хранилищем. Авторизацию заменяет подпись ссылки,
+// поэтому ссылку можно открыть в браузере или передать в <img>, как подписанную ссылку S3
+func (h *FileHandler) Download(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	file, err := h.downloader.Open(c.Request.Context(), key, c.Query("expires"), c.Query("signature"))
+	switch {
+	case errors.Is(err, storage.ErrInvalidLink), errors.Is(err, storage.ErrInvalidKey):
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgFileLinkInvalid))
+		return
+	case errors.Is(err, storage.ErrObjectNotFound):
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgFileNotFound))
+		return
+	case err != nil:
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgFileDownloadFailed, err))
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgFileDownloadFailed, err))
+		return
+	}
+
+	// Тип содержимого определяется по расширению ключа, которое задается при загрузке по содержимому файла
+	c.Header("X-Content-Type-Options", "nosniff")
+	http.ServeContent(c.Writer, c.Request, path.Base(key), info.ModTime(), file)
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"pvz-service/internal/config"
+	"pvz-service/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileDownload(t *testing.T) {
+	store, err := storage.NewLocalStorage(&config.StorageConfig{
+		LocalDir:    t.TempDir(),
+		LocalSecret: "secret",
+		URLTTL:      time.Minute,
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.Put(context.Background(), "products/photo.png", strings.NewReader("png"), 3, "image/png"))
+
+	link, err := store.URL(context.Background(), "products/photo.png")
+	require.NoError(t, err)
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/files/*key", NewFileHandler(store).Download)
+
+	t.Run("Подписанная ссылка", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, u.RequestURI(), nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+		assert.Equal(t, "png", w.Body.String())
+	})
+
+	t.Run("Подпись не совпадает", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/files/products/photo.png?"+
+			url.Values{"expires": {u.Query().Get("expires")}, "signature": {"forged"}}.Encode(), nil))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
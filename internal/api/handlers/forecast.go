@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+	"pvz-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ForecastHandler содержит обработчик прогноза поступления товаров в ПВЗ
+type ForecastHandler struct {
+	pvzQueries queries.PVZQueriesInterface
+	forecaster *service.IntakeForecaster
+}
+
+// NewForecastHandler создает новый экземпляр ForecastHandler
+func NewForecastHandler(pvzQueries queries.PVZQueriesInterface, reportQueries queries.ReportQueriesInterface) *ForecastHandler {
+	return &ForecastHandler{
+		pvzQueries: pvzQueries,
+		forecaster: service.NewIntakeForecaster(reportQueries),
+	}
+}
+
+// GetForecast обрабатывает запрос планировщика смен на прогноз числа товаров в ПВЗ по дням.
+// По умолчанию прогноз строится на 7 дней по истории за 4 недели
+func (h *ForecastHandler) GetForecast(c *gin.Context) {
+	pvzID := c.Param("pvzId")
+	if _, err := uuid.Parse(pvzID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgPVZNotFound))
+		return
+	}
+
+	query := models.ForecastQuery{Days: 7, Weeks: 4}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+
+	pvz, err := h.pvzQueries.GetPVZ(c.Request.Context(), pvzID)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgPVZNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgForecastFailed, err))
+		return
+	}
+
+	forecast, err := h.forecaster.Forecast(c.Request.Context(), *pvz, query.Days, query.Weeks)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgForecastFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, forecast)
+}
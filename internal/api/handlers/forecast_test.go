@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupForecastTest создает маршрут прогноза поступления товаров
+func setupForecastTest() (*gin.Engine, *MockPVZQueries, *MockReportQueries) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	pvzQueries := new(MockPVZQueries)
+	reportQueries := new(MockReportQueries)
+	handler := NewForecastHandler(pvzQueries, reportQueries)
+	r.GET("/pvz/:pvzId/forecast", handler.GetForecast)
+
+	return r, pvzQueries, reportQueries
+}
+
+// TestGetForecast проверяет прогноз по истории в часовом поясе ПВЗ
+func TestGetForecast(t *testing.T) {
+	r, pvzQueries, reportQueries := setupForecastTest()
+
+	timezone := "Europe/Moscow"
+	pvzQueries.On("GetPVZ", mock.Anything, testPvzID).Return(&models.PVZ{ID: testPvzID, Timezone: &timezone}, nil)
+	// История за 2 недели заканчивается местной полуночью: 21:00 UTC для Москвы
+	reportQueries.On("GetDailyIntake", mock.Anything, testPvzID, timezone, mock.MatchedBy(func(from time.Time) bool {
+		return from.Hour() == 21
+	}), mock.MatchedBy(func(to time.Time) bool {
+		return to.Hour() == 21 && time.Since(to) < 24*time.Hour
+	})).Return([]models.DailyIntake{}, nil)
+
+	req, _ := http.NewRequest("GET", "/pvz/"+testPvzID+"/forecast?days=3&weeks=2", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result models.IntakeForecast
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 2, result.Weeks)
+	assert.Len(t, result.Days, 3)
+	assert.Equal(t, 0.0, result.DailyAverage)
+	reportQueries.AssertExpectations(t)
+}
+
+// TestGetForecastInvalidQuery проверяет ограничения горизонта прогноза и глубины истории
+func TestGetForecastInvalidQuery(t *testing.T) {
+	r, pvzQueries, _ := setupForecastTest()
+
+	for _, query := range []string{"days=0", "days=15", "weeks=13", "weeks=x"} {
+		req, _ := http.NewRequest("GET", "/pvz/"+testPvzID+"/forecast?"+query, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	pvzQueries.AssertNotCalled(t, "GetPVZ", mock.Anything, mock.Anything)
+}
+
+// TestGetForecastNotFound проверяет ответ для неизвестного ПВЗ
+func TestGetForecastNotFound(t *testing.T) {
+	r, pvzQueries, reportQueries := setupForecastTest()
+
+	pvzQueries.On("GetPVZ", mock.Anything, testPvzID).Return(nil, queries.ErrNotFound)
+
+	for _, pvzID := range []string{testPvzID, "not-a-uuid"} {
+		req, _ := http.NewRequest("GET", "/pvz/"+pvzID+"/forecast", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	}
+	reportQueries.AssertNotCalled(t, "GetDailyIntake", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
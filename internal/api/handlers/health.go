@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadinessChecker проверяет зависимости сервиса
+type ReadinessChecker interface {
+	Run(ctx context.Context) health.Report
+}
+
+// HealthHandler содержит обработчики проверок живости и готовности для оркестратора
+type HealthHandler struct {
+	checker ReadinessChecker
+}
+
+// NewHealthHandler создает новый экземпляр HealthHandler
+func NewHealthHandler(checker ReadinessChecker) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+	}
+}
+
+// Live отвечает, что процесс работает. Зависимости не проверяются,
+// чтобы их недоступность не приводила к перезапуску сервиса
+func (h *HealthHandler) Live(c *gin.Context) {
+	response.JSON(c, http.StatusOK, gin.H{"status": health.StatusOK})
+}
+
+// Ready проверяет зависимости и возвращает отчет по каждой из них. Сервис готов
+// принимать запросы, пока доступны критичные зависимости: недоступность некритичных
+// дает статус degraded с кодом 200, чтобы частичный сбой не снимал сервис с балансировки
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.checker.Run(c.Request.Context())
+
+	status := http.StatusOK
+	if report.Status == health.StatusFail {
+		status = http.StatusServiceUnavailable
+	}
+	response.JSON(c, status, report)
+}
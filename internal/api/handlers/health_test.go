@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pvz-service/internal/health"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fixedReport возвращает заданный отчет о готовности
+type fixedReport health.Report
+
+func (r fixedReport) Run(ctx context.Context) health.Report {
+	return health.Report(r)
+}
+
+func TestHealthReady(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		wantStatus int
+	}{
+		{"Все зависимости доступны", health.StatusOK, http.StatusOK},
+		{"Недоступна некритичная зависимость", health.StatusDegraded, http.StatusOK},
+		{"Недоступна критичная зависимость", health.StatusFail, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			handler := NewHealthHandler(fixedReport{Status: tt.status, Checks: []health.Result{
+				{Name: "postgres", Status: health.StatusOK, Critical: true},
+			}})
+			r.GET("/readyz", handler.Ready)
+
+			req, _ := http.NewRequest("GET", "/readyz", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var report health.Report
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+			assert.Equal(t, tt.status, report.Status)
+			assert.Len(t, report.Checks, 1)
+		})
+	}
+}
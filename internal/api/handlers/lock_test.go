@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pvz-service/internal/models"
+	"pvz-service/internal/service"
+	"pvz-service/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// busyLocks - блокировки заняты другим экземпляром сервиса дольше времени ожидания, ключи запоминаются
+type busyLocks struct {
+	keys []string
+}
+
+func (b *busyLocks) Lock(ctx context.Context, key string) error {
+	b.keys = append(b.keys, key)
+	return fmt.Errorf("%w: %s", service.ErrLockTimeout, key)
+}
+
+// TestPVZOperationsLocked проверяет, что открытие приёмки и удаление товаров ПВЗ
+// не выполняются, пока блокировка ПВЗ занята
+func TestPVZOperationsLocked(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Создание приёмки", func(t *testing.T) {
+		locks := &busyLocks{}
+		receptionQueries := new(MockReceptionQueries)
+		handler := NewReceptionHandler(receptionQueries, new(MockProductQueries), new(MockManifestQueries), nil, nil, passthroughTx{}, &recordingOutbox{}, alwaysOpen{}, nil, noQuotas{}, locks)
+		r := gin.New()
+		r.POST("/receptions", func(c *gin.Context) {
+			c.Set("userRole", models.RoleEmployee)
+			handler.CreateReception(c)
+		})
+
+		jsonData, _ := json.Marshal(models.CreateReceptionRequest{PvzID: testPvzID})
+		req, _ := http.NewRequest("POST", "/receptions", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, []string{service.PVZLockKey(testPvzID)}, locks.keys)
+		receptionQueries.AssertNotCalled(t, "CreateReception", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Удаление последнего товара", func(t *testing.T) {
+		locks := &busyLocks{}
+		productQueries := new(MockProductQueries)
+		receptionQueries := new(MockReceptionQueries)
+		handler := newTestProductHandler(productQueries, receptionQueries, &recordingOutbox{}, storage.Disabled{}, productLimits{}, alwaysOpen{}, noQuotas{}, locks)
+		r := gin.New()
+		r.POST("/pvz/:pvzId/delete_last_product", func(c *gin.Context) {
+			c.Set("userRole", models.RoleEmployee)
+			handler.DeleteLastProduct(c)
+		})
+
+		req, _ := http.NewRequest("POST", "/pvz/"+testPvzID+"/delete_last_product", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, []string{service.PVZLockKey(testPvzID)}, locks.keys)
+		receptionQueries.AssertNotCalled(t, "GetLastOpenReception", mock.Anything, mock.Anything)
+		productQueries.AssertNotCalled(t, "DeleteProduct", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Удаление товара по ID", func(t *testing.T) {
+		locks := &busyLocks{}
+		productQueries := new(MockProductQueries)
+		receptionQueries := new(MockReceptionQueries)
+		handler := newTestProductHandler(productQueries, receptionQueries, &recordingOutbox{}, storage.Disabled{}, productLimits{}, alwaysOpen{}, noQuotas{}, locks)
+		r := gin.New()
+		r.DELETE("/products/:productId", func(c *gin.Context) {
+			c.Set("userRole", models.RoleEmployee)
+			handler.DeleteProduct(c)
+		})
+
+		productQueries.On("GetProduct", mock.Anything, "product-1").
+			Return(&models.Product{ID: "product-1", ReceptionID: testReceptionID}, nil)
+		receptionQueries.On("GetReceptionByID", mock.Anything, testReceptionID).
+			Return(&models.Reception{ID: testReceptionID, PvzID: testPvzID, Status: "in_progress"}, nil)
+
+		req, _ := http.NewRequest("DELETE", "/products/product-1", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, []string{service.PVZLockKey(testPvzID)}, locks.keys)
+		productQueries.AssertNotCalled(t, "GetLastProductFromReception", mock.Anything, mock.Anything)
+		productQueries.AssertNotCalled(t, "DeleteProduct", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoginAuditHandler содержит обработчики журнала входов
+type LoginAuditHandler struct {
+	loginAuditQueries queries.LoginAuditQueriesInterface
+}
+
+// NewLoginAuditHandler создает новый экземпляр LoginAuditHandler
+func NewLoginAuditHandler(loginAuditQueries queries.LoginAuditQueriesInterface) *LoginAuditHandler {
+	return &LoginAuditHandler{
+		loginAuditQueries: loginAuditQueries,
+	}
+}
+
+// GetLoginAudit обрабатывает запрос на получение страницы журнала входов от новых попыток к старым
+func (h *LoginAuditHandler) GetLoginAudit(c *gin.Context) {
+	query := models.LoginAuditQuery{Page: 1, Limit: 50}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+
+	attempts, total, err := h.loginAuditQueries.ListAttempts(c.Request.Context(), query)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetLoginAuditFailed, err))
+		return
+	}
+
+	if attempts == nil {
+		attempts = []models.LoginAttempt{}
+	}
+
+	response.Paginated(c, http.StatusOK, attempts, response.NewMeta(query.Page, query.Limit, total))
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+)
+
+// recordingLoginAudit запоминает записанные попытки входа
+type recordingLoginAudit struct {
+	attempts []models.LoginAttempt
+}
+
+func (r *recordingLoginAudit) RecordLogin(ctx context.Context, attempt models.LoginAttempt) error {
+	r.attempts = append(r.attempts, attempt)
+	return nil
+}
+
+// TestLoginRecordsAttempts проверяет запись удачных и неудачных попыток входа в журнал
+func TestLoginRecordsAttempts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	authQueries := new(MockAuthQueries)
+	passwordChecker := new(MockPasswordChecker)
+	sessions := new(MockSessionIssuer)
+	audit := &recordingLoginAudit{}
+	authHandler := NewAuthHandler(new(MockJWTManager), authQueries, passwordChecker, sessions, stubChallenger{}, nil, audit, "CF-IPCountry")
+	r.POST("/login", authHandler.Login)
+
+	user := &models.User{ID: "user-uuid", Email: "user@example.com", Role: models.RoleEmployee, PasswordHash: "hash", IsActive: true}
+	authQueries.On("GetUserWithCredentials", mock.Anything, "user@example.com").Return(user, nil)
+	authQueries.On("GetUserWithCredentials", mock.Anything, "ghost@example.com").Return(nil, fmt.Errorf("user: %w", queries.ErrNotFound))
+	passwordChecker.On("CheckPassword", "wrong", "hash").Return(fmt.Errorf("mismatch"))
+	passwordChecker.On("CheckPassword", "password123", "hash").Return(nil)
+	passwordChecker.On("NeedsRehash", "hash").Return(false)
+	sessions.On("IssueToken", mock.Anything, "user-uuid", models.RoleEmployee, "terminal/1.0", "192.0.2.10", false).
+		Return(&models.LoginResponse{Token: "token"}, nil)
+
+	login := func(email, password string) int {
+		body, _ := json.Marshal(models.LoginRequest{Email: email, Password: password})
+		req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "192.0.2.10:5555"
+		req.Header.Set("User-Agent", "terminal/1.0")
+		req.Header.Set("CF-IPCountry", "kz")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, login("ghost@example.com", "password123"))
+	assert.Equal(t, http.StatusUnauthorized, login("user@example.com", "wrong"))
+	assert.Equal(t, http.StatusOK, login("user@example.com", "password123"))
+
+	require.Len(t, audit.attempts, 3)
+	assert.Nil(t, audit.attempts[0].UserID)
+	assert.Equal(t, models.LoginFailureUnknownUser, *audit.attempts[0].FailureReason)
+	assert.Equal(t, "user-uuid", *audit.attempts[1].UserID)
+	assert.Equal(t, models.LoginFailureInvalidPassword, *audit.attempts[1].FailureReason)
+
+	success := audit.attempts[2]
+	assert.True(t, success.Success)
+	assert.Nil(t, success.FailureReason)
+	assert.Equal(t, "192.0.2.10", success.IP)
+	assert.Equal(t, "terminal/1.0", success.UserAgent)
+	assert.Equal(t, "KZ", *success.Country)
+}
+
+// loginAuditStore отдает журнал входов и запоминает параметры запроса
+type loginAuditStore struct {
+	queries.LoginAuditQueriesInterface
+	params models.LoginAuditQuery
+}
+
+func (s *loginAuditStore) ListAttempts(ctx context.Context, params models.LoginAuditQuery) ([]models.LoginAttempt, int, error) {
+	s.params = params
+	return nil, 0, nil
+}
+
+// TestGetLoginAudit проверяет фильтры журнала входов и их проверку
+func TestGetLoginAudit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	store := &loginAuditStore{}
+	r.GET("/admin/login-audit", NewLoginAuditHandler(store).GetLoginAudit)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/admin/login-audit?success=false&ip=192.0.2.10&from=2026-03-01T00:00:00Z")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[]`, w.Body.String())
+	require.NotNil(t, store.params.Success)
+	assert.False(t, *store.params.Success)
+	assert.Equal(t, "192.0.2.10", store.params.IP)
+	assert.NotNil(t, store.params.From)
+
+	assert.Equal(t, http.StatusBadRequest, get("/admin/login-audit?ip=terminal").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/admin/login-audit?userId=user-1").Code)
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/models"
+)
+
+// setupManifestTest настраивает окружение для тестов импорта накладных
+func setupManifestTest() (*gin.Engine, *MockReceptionQueries, *MockProductQueries, *MockManifestQueries) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	receptionQueries := new(MockReceptionQueries)
+	productQueries := new(MockProductQueries)
+	manifestQueries := new(MockManifestQueries)
+
+	receptionHandler := NewReceptionHandler(receptionQueries, productQueries, manifestQueries, nil, nil, passthroughTx{}, &recordingOutbox{}, alwaysOpen{}, nil, noQuotas{}, noLocks{})
+
+	r.POST("/receptions/:receptionId/import", receptionHandler.ImportManifest)
+	r.POST("/pvz/:pvzId/close_last_reception", receptionHandler.CloseLastReception)
+
+	return r, receptionQueries, productQueries, manifestQueries
+}
+
+// newManifestRequest формирует multipart-запрос с файлом накладной
+func newManifestRequest(t *testing.T, receptionID, filename, content string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", filename)
+	assert.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	req, _ := http.NewRequest("POST", "/receptions/"+receptionID+"/import", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+// TestImportManifestSuccess проверяет импорт CSV-накладной
+func TestImportManifestSuccess(t *testing.T) {
+	r, receptionQueries, _, manifestQueries := setupManifestTest()
+
+	receptionID := "223e4567-e89b-12d3-a456-426614174000"
+	receptionQueries.On("GetReceptionByID", mock.Anything, receptionID).Return(&models.Reception{
+		ID:     receptionID,
+		Status: "in_progress",
+	}, nil)
+	manifestQueries.On("ReplaceExpectedProducts", mock.Anything, receptionID, mock.MatchedBy(func(lines []models.ExpectedProduct) bool {
+		return len(lines) == 2 && lines[0].Type == "электроника" && *lines[0].Barcode == "4600000000011" &&
+			lines[1].Type == "обувь" && lines[1].Barcode == nil && lines[1].Quantity == 3
+	})).Return(nil)
+
+	csv := "type,barcode,quantity\nэлектроника,4600000000011,1\nобувь,,3\n"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newManifestRequest(t, receptionID, "manifest.csv", csv))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response models.ManifestImportResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, response.Lines)
+	assert.Equal(t, 4, response.Items)
+
+	receptionQueries.AssertExpectations(t)
+	manifestQueries.AssertExpectations(t)
+}
+
+// TestImportManifestErrors проверяет отказ в импорте
+func TestImportManifestErrors(t *testing.T) {
+	receptionID := "223e4567-e89b-12d3-a456-426614174000"
+
+	tests := []struct {
+		name       string
+		status     string
+		filename   string
+		content    string
+		wantStatus int
+	}{
+		{name: "Закрытая приёмка", status: "close", filename: "manifest.csv", content: "type\nобувь\n", wantStatus: http.StatusBadRequest},
+		{name: "Неподдерживаемый формат", status: "in_progress", filename: "manifest.pdf", content: "%PDF", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "Неизвестный тип товара", status: "in_progress", filename: "manifest.csv", content: "type\nмебель\n", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, receptionQueries, _, manifestQueries := setupManifestTest()
+			receptionQueries.On("GetReceptionByID", mock.Anything, receptionID).Return(&models.Reception{
+				ID:     receptionID,
+				Status: tt.status,
+			}, nil)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newManifestRequest(t, receptionID, tt.filename, tt.content))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			manifestQueries.AssertNotCalled(t, "ReplaceExpectedProducts")
+		})
+	}
+}
+
+// TestCloseLastReceptionWithDiscrepancies проверяет, что приёмка с расхождениями закрывается
+// только с force=true и причиной, а причина записывается в журнал
+func TestCloseLastReceptionWithDiscrepancies(t *testing.T) {
+	r, receptionQueries, productQueries, manifestQueries := setupManifestTest()
+
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	receptionID := "223e4567-e89b-12d3-a456-426614174000"
+	openReception := &models.Reception{ID: receptionID, DateTime: time.Now(), PvzID: pvzID, Status: "in_progress"}
+	closedReception := &models.Reception{ID: receptionID, DateTime: openReception.DateTime, PvzID: pvzID, Status: "close"}
+	wantDiscrepancies := &models.ManifestDiscrepancies{
+		ByType:             []models.TypeDiscrepancy{{Type: "электроника", Expected: 2, Actual: 1}},
+		MissingBarcodes:    []string{},
+		UnexpectedBarcodes: []string{},
+	}
+
+	receptionQueries.On("GetLastOpenReception", mock.Anything, pvzID).Return(openReception, nil)
+	receptionQueries.On("LockReceptions", mock.Anything, []string{receptionID}).Return([]models.Reception{*openReception}, nil)
+	manifestQueries.On("GetExpectedProducts", mock.Anything, receptionID).Return([]models.ExpectedProduct{
+		{ReceptionID: receptionID, Type: "электроника", Quantity: 2},
+	}, nil)
+	productQueries.On("GetProductsByReception", mock.Anything, receptionID).Return([]models.Product{
+		{ID: "p1", Type: "электроника", ReceptionID: receptionID},
+	}, nil)
+
+	t.Run("Без подтверждения возвращается 409 с расхождениями", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/pvz/"+pvzID+"/close_last_reception", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		var body struct {
+			Details models.ManifestDiscrepancies `json:"details"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, wantDiscrepancies.ByType, body.Details.ByType)
+		receptionQueries.AssertNotCalled(t, "CloseReception", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Подтверждение без причины отклоняется", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/pvz/"+pvzID+"/close_last_reception?force=true", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		receptionQueries.AssertNotCalled(t, "CloseReception", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Причина из пробелов отклоняется", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/pvz/"+pvzID+"/close_last_reception?force=true&reason=%20%20", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		receptionQueries.AssertNotCalled(t, "CloseReception", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Закрытие с подтверждением и причиной", func(t *testing.T) {
+		receptionQueries.On("CloseReception", mock.Anything, receptionID, mock.Anything).Return(closedReception, nil)
+		productQueries.On("GetReceptionDamages", mock.Anything, receptionID).Return([]models.ProductDamage{
+			{ProductID: "p1", ReceptionID: receptionID, Type: "электроника", Description: "вмятина на корпусе"},
+		}, nil)
+		manifestQueries.On("RecordForcedClose", mock.Anything, receptionID, mock.Anything, "недовоз", wantDiscrepancies).Return(nil)
+
+		req, _ := http.NewRequest("POST", "/pvz/"+pvzID+"/close_last_reception?force=true&reason=недовоз", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response models.CloseReceptionResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "close", response.Status)
+		assert.Equal(t, "недовоз", response.ForceReason)
+		assert.NotNil(t, response.Discrepancies)
+		assert.Equal(t, wantDiscrepancies.ByType, response.Discrepancies.ByType)
+		assert.Len(t, response.Damaged, 1)
+		assert.Equal(t, "вмятина на корпусе", response.Damaged[0].Description)
+	})
+
+	receptionQueries.AssertExpectations(t)
+	productQueries.AssertExpectations(t)
+	manifestQueries.AssertExpectations(t)
+}
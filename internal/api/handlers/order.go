@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/authz"
+	"pvz-service/internal/db"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/mapper"
+	"pvz-service/internal/models"
+	"pvz-service/internal/otp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrderHandler содержит обработчики для выдачи заказов покупателям
+type OrderHandler struct {
+	orderQueries  queries.OrderQueriesInterface
+	tx            db.Transactor
+	outboxQueries queries.OutboxQueriesInterface
+}
+
+// NewOrderHandler создает новый экземпляр OrderHandler.
+// Событие о выдаче заказа записывается в outbox в одной транзакции с выдачей
+func NewOrderHandler(orderQueries queries.OrderQueriesInterface, tx db.Transactor, outboxQueries queries.OutboxQueriesInterface) *OrderHandler {
+	return &OrderHandler{
+		orderQueries:  orderQueries,
+		tx:            tx,
+		outboxQueries: outboxQueries,
+	}
+}
+
+// CreateOrder обрабатывает запрос на создание заказа из товаров ПВЗ
+func (h *OrderHandler) CreateOrder(c *gin.Context) {
+	// Проверяем право роли пользователя на действие
+	if !authz.Can(c.GetString("userRole"), authz.OrderWrite) {
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbiddenCreateOrder))
+		return
+	}
+
+	var req models.CreateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+	if denyPVZAccess(c, req.PvzID) {
+		return
+	}
+
+	phone, err := otp.NormalizePhone(req.RecipientPhone)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgInvalidPhone))
+		return
+	}
+
+	// Проверяем, что номер заказа не занят
+	_, err = h.orderQueries.GetOrderByNumber(c.Request.Context(), req.OrderNumber)
+	if err == nil {
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgOrderNumberTaken))
+		return
+	}
+	if !errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCreateOrderFailed, err))
+		return
+	}
+
+	order, err := h.orderQueries.CreateOrder(c.Request.Context(), models.Order{
+		OrderNumber:    req.OrderNumber,
+		RecipientPhone: phone,
+		PvzID:          req.PvzID,
+	}, req.ProductIDs)
+	if errors.Is(err, queries.ErrOrderProductsUnavailable) {
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgOrderProductsUnavailable))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCreateOrderFailed, err))
+		return
+	}
+
+	h.respondWithOrder(c, http.StatusCreated, order)
+}
+
+// GetOrder обрабатывает запрос на получение заказа с его товарами
+func (h *OrderHandler) GetOrder(c *gin.Context) {
+	order, err := h.orderQueries.GetOrder(c.Request.Context(), c.Param("orderId"))
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgOrderNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetOrderFailed, err))
+		return
+	}
+	if denyPVZAccess(c, order.PvzID) {
+		return
+	}
+
+	h.respondWithOrder(c, http.StatusOK, order)
+}
+
+// IssueOrder обрабатывает запрос на выдачу заказа получателю.
+// Все товары заказа отмечаются выданными с сотрудником и временем выдачи
+func (h *OrderHandler) IssueOrder(c *gin.Context) {
+	// Проверяем право роли пользователя на действие
+	if !authz.Can(c.GetString("userRole"), authz.OrderWrite) {
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbiddenIssueOrder))
+		return
+	}
+
+	employeeID, ok := c.Get("userID")
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgUserContextMissing))
+		return
+	}
+
+	// Заказ из ПВЗ вне области токена отклоняется до выдачи, несуществующий - при выдаче
+	if pvzScoped(c) {
+		order, err := h.orderQueries.GetOrder(c.Request.Context(), c.Param("orderId"))
+		if err != nil && !errors.Is(err, queries.ErrNotFound) {
+			response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgIssueOrderFailed, err))
+			return
+		}
+		if err == nil && denyPVZAccess(c, order.PvzID) {
+			return
+		}
+	}
+
+	// Выдаем заказ и записываем событие о выдаче в одной транзакции
+	var order *models.Order
+	err := h.tx.InTx(c.Request.Context(), func(ctx context.Context) error {
+		var err error
+		order, err = h.orderQueries.IssueOrder(ctx, c.Param("orderId"), employeeID.(string))
+		if err != nil {
+			return err
+		}
+		return h.outboxQueries.AddEvent(ctx, order.PvzID, models.EventOrderIssued, order)
+	})
+	switch {
+	case errors.Is(err, queries.ErrNotFound):
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgOrderNotFound))
+		return
+	case errors.Is(err, queries.ErrOrderIssued):
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgOrderAlreadyIssued))
+		return
+	case errors.Is(err, queries.ErrOrderNotReady):
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgOrderNotReady))
+		return
+	case err != nil:
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgIssueOrderFailed, err))
+		return
+	}
+
+	h.respondWithOrder(c, http.StatusOK, order)
+}
+
+// respondWithOrder отправляет заказ вместе с его товарами
+func (h *OrderHandler) respondWithOrder(c *gin.Context, status int, order *models.Order) {
+	products, err := h.orderQueries.GetOrderProducts(c.Request.Context(), order.ID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetOrderFailed, err))
+		return
+	}
+
+	result := models.OrderResponse{
+		Order:    *order,
+		Products: mapper.Products(products),
+	}
+
+	response.JSON(c, status, result)
+}
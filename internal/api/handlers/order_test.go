@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+)
+
+// MockOrderQueries мокирует запросы для работы с заказами покупателей
+type MockOrderQueries struct {
+	mock.Mock
+}
+
+func (m *MockOrderQueries) CreateOrder(ctx context.Context, order models.Order, productIDs []string) (*models.Order, error) {
+	args := m.Called(ctx, order, productIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Order), args.Error(1)
+}
+
+func (m *MockOrderQueries) GetOrder(ctx context.Context, orderID string) (*models.Order, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Order), args.Error(1)
+}
+
+func (m *MockOrderQueries) GetOrderByNumber(ctx context.Context, orderNumber string) (*models.Order, error) {
+	args := m.Called(ctx, orderNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Order), args.Error(1)
+}
+
+func (m *MockOrderQueries) GetOrderProducts(ctx context.Context, orderID string) ([]models.Product, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Product), args.Error(1)
+}
+
+func (m *MockOrderQueries) IssueOrder(ctx context.Context, orderID, employeeID string) (*models.Order, error) {
+	args := m.Called(ctx, orderID, employeeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Order), args.Error(1)
+}
+
+const (
+	testOrderID    = "123e4567-e89b-12d3-a456-426614174010"
+	testEmployeeID = "123e4567-e89b-12d3-a456-426614174020"
+	testPvzID      = "123e4567-e89b-12d3-a456-426614174000"
+)
+
+// setupOrderTest создает роутер заказов с заданной ролью пользователя
+func setupOrderTest(role string) (*gin.Engine, *MockOrderQueries, *recordingOutbox) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	orderQueries := new(MockOrderQueries)
+	outbox := &recordingOutbox{}
+	orderHandler := NewOrderHandler(orderQueries, passthroughTx{}, outbox)
+
+	authorized := r.Group("/")
+	authorized.Use(func(c *gin.Context) {
+		c.Set("userID", testEmployeeID)
+		c.Set("userRole", role)
+		c.Next()
+	})
+	authorized.POST("/orders", orderHandler.CreateOrder)
+	authorized.GET("/orders/:orderId", orderHandler.GetOrder)
+	authorized.POST("/orders/:orderId/issue", orderHandler.IssueOrder)
+
+	return r, orderQueries, outbox
+}
+
+// TestCreateOrderSuccess проверяет создание заказа с нормализацией телефона получателя
+func TestCreateOrderSuccess(t *testing.T) {
+	r, orderQueries, _ := setupOrderTest("employee")
+
+	productIDs := []string{"123e4567-e89b-12d3-a456-426614174001"}
+	order := models.Order{OrderNumber: "A-100", RecipientPhone: "+79991234567", PvzID: testPvzID}
+	orderQueries.On("GetOrderByNumber", mock.Anything, "A-100").Return(nil, fmt.Errorf("order: %w", queries.ErrNotFound))
+	orderQueries.On("CreateOrder", mock.Anything, order, productIDs).
+		Return(&models.Order{ID: testOrderID, OrderNumber: "A-100", RecipientPhone: "+79991234567", PvzID: testPvzID}, nil)
+	orderQueries.On("GetOrderProducts", mock.Anything, testOrderID).
+		Return([]models.Product{{ID: productIDs[0], Type: "обувь", ReceptionID: "r1"}}, nil)
+
+	body, _ := json.Marshal(models.CreateOrderRequest{
+		OrderNumber:    "A-100",
+		RecipientPhone: "8 (999) 123-45-67",
+		PvzID:          testPvzID,
+		ProductIDs:     productIDs,
+	})
+	req, _ := http.NewRequest("POST", "/orders", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response models.OrderResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, testOrderID, response.ID)
+	assert.Len(t, response.Products, 1)
+
+	orderQueries.AssertExpectations(t)
+}
+
+// TestCreateOrderNumberTaken проверяет отказ при повторном номере заказа
+func TestCreateOrderNumberTaken(t *testing.T) {
+	r, orderQueries, _ := setupOrderTest("employee")
+
+	orderQueries.On("GetOrderByNumber", mock.Anything, "A-100").Return(&models.Order{ID: testOrderID}, nil)
+
+	body, _ := json.Marshal(models.CreateOrderRequest{
+		OrderNumber:    "A-100",
+		RecipientPhone: "+79991234567",
+		PvzID:          testPvzID,
+		ProductIDs:     []string{"123e4567-e89b-12d3-a456-426614174001"},
+	})
+	req, _ := http.NewRequest("POST", "/orders", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	orderQueries.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestCreateOrderProductsUnavailable проверяет отказ, если товары нельзя включить в заказ
+func TestCreateOrderProductsUnavailable(t *testing.T) {
+	r, orderQueries, _ := setupOrderTest("employee")
+
+	orderQueries.On("GetOrderByNumber", mock.Anything, "A-100").Return(nil, queries.ErrNotFound)
+	orderQueries.On("CreateOrder", mock.Anything, mock.Anything, mock.Anything).Return(nil, queries.ErrOrderProductsUnavailable)
+
+	body, _ := json.Marshal(models.CreateOrderRequest{
+		OrderNumber:    "A-100",
+		RecipientPhone: "+79991234567",
+		PvzID:          testPvzID,
+		ProductIDs:     []string{"123e4567-e89b-12d3-a456-426614174001"},
+	})
+	req, _ := http.NewRequest("POST", "/orders", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+// TestIssueOrderSuccess проверяет выдачу заказа сотрудником и запись события
+func TestIssueOrderSuccess(t *testing.T) {
+	r, orderQueries, outbox := setupOrderTest("employee")
+
+	issuedAt := time.Now()
+	employeeID := testEmployeeID
+	orderQueries.On("IssueOrder", mock.Anything, testOrderID, testEmployeeID).
+		Return(&models.Order{ID: testOrderID, PvzID: testPvzID, IssuedAt: &issuedAt, IssuedBy: &employeeID}, nil)
+	orderQueries.On("GetOrderProducts", mock.Anything, testOrderID).Return([]models.Product{}, nil)
+
+	req, _ := http.NewRequest("POST", "/orders/"+testOrderID+"/issue", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, outbox.events, 1)
+	assert.Equal(t, models.EventOrderIssued, outbox.events[0].Type)
+	assert.Equal(t, testPvzID, outbox.events[0].PvzID)
+
+	var response models.OrderResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, testEmployeeID, *response.IssuedBy)
+}
+
+// TestIssueOrderErrors проверяет ответы при невозможности выдать заказ
+func TestIssueOrderErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"Заказ не найден", fmt.Errorf("order: %w", queries.ErrNotFound), http.StatusNotFound},
+		{"Заказ уже выдан", queries.ErrOrderIssued, http.StatusConflict},
+		{"Товары не на хранении", queries.ErrOrderNotReady, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, orderQueries, outbox := setupOrderTest("employee")
+			orderQueries.On("IssueOrder", mock.Anything, testOrderID, testEmployeeID).Return(nil, tt.err)
+
+			req, _ := http.NewRequest("POST", "/orders/"+testOrderID+"/issue", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Empty(t, outbox.events)
+		})
+	}
+}
+
+// TestIssueOrderForbidden проверяет, что модератор не может выдавать заказы
+func TestIssueOrderForbidden(t *testing.T) {
+	r, orderQueries, _ := setupOrderTest("moderator")
+
+	req, _ := http.NewRequest("POST", "/orders/"+testOrderID+"/issue", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	orderQueries.AssertNotCalled(t, "IssueOrder", mock.Anything, mock.Anything, mock.Anything)
+}
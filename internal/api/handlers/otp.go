@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/clock"
+	"pvz-service/internal/config"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+	"pvz-service/internal/otp"
+	"pvz-service/internal/service"
+	"pvz-service/internal/sms"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OTPHandler содержит обработчики входа по телефону и одноразовому коду
+type OTPHandler struct {
+	sessions    service.SessionIssuer
+	twoFactor   service.TwoFactorChallenger
+	authQueries queries.AuthQueriesInterface
+	otpQueries  queries.OTPQueriesInterface
+	smsSender   sms.SenderInterface
+	config      config.OTPConfig
+	clock       clock.Clock
+}
+
+// NewOTPHandler создает новый экземпляр OTPHandler.
+// Токен после проверки кода выдается в новой сессии пользователя, а при включенной
+// двухфакторной аутентификации - после второго фактора
+func NewOTPHandler(sessions service.SessionIssuer, twoFactor service.TwoFactorChallenger, authQueries queries.AuthQueriesInterface, otpQueries queries.OTPQueriesInterface, smsSender sms.SenderInterface, config config.OTPConfig) *OTPHandler {
+	return &OTPHandler{
+		sessions:    sessions,
+		twoFactor:   twoFactor,
+		authQueries: authQueries,
+		otpQueries:  otpQueries,
+		smsSender:   smsSender,
+		config:      config,
+		clock:       clock.System{},
+	}
+}
+
+// RequestCode обрабатывает запрос на отправку кода входа по SMS.
+// Ответ не зависит от того, зарегистрирован ли телефон
+func (h *OTPHandler) RequestCode(c *gin.Context) {
+	var req models.OTPRequest
+
+	// Проверяем запрос
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	phone, err := otp.NormalizePhone(req.Phone)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgInvalidPhone))
+		return
+	}
+
+	// Ограничиваем частоту запросов кода на один телефон
+	now := h.clock.Now()
+	count, err := h.otpQueries.CountCodesSince(c.Request.Context(), phone, now.Add(-h.config.RequestWindow))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgOTPCreateFailed, err))
+		return
+	}
+	if count >= h.config.RequestLimit {
+		response.Error(c, http.StatusTooManyRequests, i18n.T(c, i18n.MsgOTPTooManyRequests))
+		return
+	}
+
+	expiresAt := now.Add(h.config.CodeTTL)
+
+	// Незарегистрированному телефону код не отправляем
+	_, err = h.authQueries.GetUserByPhone(c.Request.Context(), phone)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.JSON(c, http.StatusOK, models.OTPRequestResponse{ExpiresAt: expiresAt})
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCheckPhoneFailed, err))
+		return
+	}
+
+	code, err := otp.GenerateCode()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgOTPCreateFailed, err))
+		return
+	}
+
+	err = h.otpQueries.CreateCode(c.Request.Context(), phone, otp.HashCode(phone, code), expiresAt)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgOTPCreateFailed, err))
+		return
+	}
+
+	err = h.smsSender.Send(c.Request.Context(), phone, i18n.T(c, i18n.MsgOTPMessage)+": "+code)
+	if err != nil {
+		response.Error(c, http.StatusBadGateway, i18n.Wrap(c, i18n.MsgOTPSendFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, models.OTPRequestResponse{ExpiresAt: expiresAt})
+}
+
+// VerifyCode обрабатывает запрос на обмен кода входа на JWT-токен
+func (h *OTPHandler) VerifyCode(c *gin.Context) {
+	var req models.OTPVerifyRequest
+
+	// Проверяем запрос
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	phone, err := otp.NormalizePhone(req.Phone)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgInvalidPhone))
+		return
+	}
+
+	code, err := h.otpQueries.GetActiveCode(c.Request.Context(), phone)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgOTPInvalid))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgOTPVerifyFailed, err))
+		return
+	}
+
+	// Попытка списывается до проверки кода условным обновлением счетчика: параллельные запросы
+	// не получат больше MaxAttempts проверок, сколько бы их ни пришло одновременно
+	err = h.otpQueries.IncrementAttempts(c.Request.Context(), code.ID, h.config.MaxAttempts)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusTooManyRequests, i18n.T(c, i18n.MsgOTPAttemptsExceeded))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgOTPVerifyFailed, err))
+		return
+	}
+
+	if !otp.CheckCode(phone, req.Code, code.CodeHash) {
+		response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgOTPInvalid))
+		return
+	}
+
+	// Код одноразовый: повторный запрос с тем же кодом получит ErrNotFound
+	err = h.otpQueries.ConsumeCode(c.Request.Context(), code.ID)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgOTPInvalid))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgOTPVerifyFailed, err))
+		return
+	}
+
+	user, err := h.authQueries.GetUserByPhone(c.Request.Context(), phone)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgInvalidCredentials))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgOTPVerifyFailed, err))
+		return
+	}
+	if !user.IsActive {
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgUserDeactivated))
+		return
+	}
+
+	// Выдаем токен сессии или, при включенной двухфакторной аутентификации, токен второго этапа входа
+	issueLoginToken(c, h.sessions, h.twoFactor, user, req.RememberMe)
+}
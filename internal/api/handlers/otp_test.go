@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/config"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/otp"
+)
+
+// MockOTPQueries мокирует запросы для работы с кодами входа
+type MockOTPQueries struct {
+	mock.Mock
+}
+
+func (m *MockOTPQueries) CountCodesSince(ctx context.Context, phone string, since time.Time) (int, error) {
+	args := m.Called(ctx, phone, since)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockOTPQueries) CreateCode(ctx context.Context, phone, codeHash string, expiresAt time.Time) error {
+	args := m.Called(ctx, phone, codeHash, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockOTPQueries) GetActiveCode(ctx context.Context, phone string) (*models.OTPCode, error) {
+	args := m.Called(ctx, phone)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OTPCode), args.Error(1)
+}
+
+func (m *MockOTPQueries) IncrementAttempts(ctx context.Context, codeID string, maxAttempts int) error {
+	args := m.Called(ctx, codeID, maxAttempts)
+	return args.Error(0)
+}
+
+func (m *MockOTPQueries) ConsumeCode(ctx context.Context, codeID string) error {
+	args := m.Called(ctx, codeID)
+	return args.Error(0)
+}
+
+// MockSMSSender мокирует провайдера SMS
+type MockSMSSender struct {
+	mock.Mock
+}
+
+func (m *MockSMSSender) Send(ctx context.Context, phone, message string) error {
+	args := m.Called(ctx, phone, message)
+	return args.Error(0)
+}
+
+const testPhone = "+79990001122"
+
+// Настройка тестового окружения
+func setupOTPTest() (*gin.Engine, *MockSessionIssuer, *MockAuthQueries, *MockOTPQueries, *MockSMSSender) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	sessions := new(MockSessionIssuer)
+	authQueries := new(MockAuthQueries)
+	otpQueries := new(MockOTPQueries)
+	smsSender := new(MockSMSSender)
+
+	otpHandler := NewOTPHandler(sessions, stubChallenger{}, authQueries, otpQueries, smsSender, config.OTPConfig{
+		CodeTTL:       5 * time.Minute,
+		RequestLimit:  3,
+		RequestWindow: 15 * time.Minute,
+		MaxAttempts:   5,
+	})
+
+	r.POST("/auth/otp/request", otpHandler.RequestCode)
+	r.POST("/auth/otp/verify", otpHandler.VerifyCode)
+
+	return r, sessions, authQueries, otpQueries, smsSender
+}
+
+func postJSON(r *gin.Engine, path string, body interface{}) *httptest.ResponseRecorder {
+	jsonData, _ := json.Marshal(body)
+	req, _ := http.NewRequest("POST", path, bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestRequestOTPSuccess проверяет отправку кода зарегистрированному телефону
+func TestRequestOTPSuccess(t *testing.T) {
+	r, _, authQueries, otpQueries, smsSender := setupOTPTest()
+
+	var sentCode string
+	otpQueries.On("CountCodesSince", mock.Anything, testPhone, mock.Anything).Return(0, nil)
+	authQueries.On("GetUserByPhone", mock.Anything, testPhone).Return(&models.User{ID: "user-id", Role: "employee", IsActive: true}, nil)
+	otpQueries.On("CreateCode", mock.Anything, testPhone, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	smsSender.On("Send", mock.Anything, testPhone, mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) {
+			message := args.String(2)
+			sentCode = message[strings.LastIndex(message, " ")+1:]
+		}).
+		Return(nil)
+
+	w := postJSON(r, "/auth/otp/request", models.OTPRequest{Phone: "8 (999) 000-11-22"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, sentCode, otp.CodeLength)
+
+	// В базу сохраняется хеш отправленного кода, а не сам код
+	codeHash := otpQueries.Calls[1].Arguments.String(2)
+	assert.True(t, otp.CheckCode(testPhone, sentCode, codeHash))
+	smsSender.AssertExpectations(t)
+}
+
+// TestRequestOTPUnknownPhone проверяет, что незарегистрированному телефону код не отправляется
+func TestRequestOTPUnknownPhone(t *testing.T) {
+	r, _, authQueries, otpQueries, smsSender := setupOTPTest()
+
+	otpQueries.On("CountCodesSince", mock.Anything, testPhone, mock.Anything).Return(0, nil)
+	authQueries.On("GetUserByPhone", mock.Anything, testPhone).Return(nil, queries.ErrNotFound)
+
+	w := postJSON(r, "/auth/otp/request", models.OTPRequest{Phone: testPhone})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	otpQueries.AssertNotCalled(t, "CreateCode", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	smsSender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestRequestOTPRateLimited проверяет ограничение частоты запросов кода
+func TestRequestOTPRateLimited(t *testing.T) {
+	r, _, _, otpQueries, smsSender := setupOTPTest()
+
+	otpQueries.On("CountCodesSince", mock.Anything, testPhone, mock.Anything).Return(3, nil)
+
+	w := postJSON(r, "/auth/otp/request", models.OTPRequest{Phone: testPhone})
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	smsSender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestRequestOTPInvalidPhone проверяет валидацию номера телефона
+func TestRequestOTPInvalidPhone(t *testing.T) {
+	r, _, _, _, _ := setupOTPTest()
+
+	w := postJSON(r, "/auth/otp/request", models.OTPRequest{Phone: "12-34"})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestVerifyOTPSuccess проверяет обмен кода на токен
+func TestVerifyOTPSuccess(t *testing.T) {
+	r, sessions, authQueries, otpQueries, _ := setupOTPTest()
+
+	otpQueries.On("GetActiveCode", mock.Anything, testPhone).
+		Return(&models.OTPCode{ID: "code-id", CodeHash: otp.HashCode(testPhone, "123456")}, nil)
+	otpQueries.On("IncrementAttempts", mock.Anything, "code-id", 5).Return(nil)
+	otpQueries.On("ConsumeCode", mock.Anything, "code-id").Return(nil)
+	authQueries.On("GetUserByPhone", mock.Anything, testPhone).Return(&models.User{ID: "user-id", Role: "employee", IsActive: true}, nil)
+	sessions.On("IssueToken", mock.Anything, "user-id", "employee", mock.Anything, mock.Anything, false).Return(&models.LoginResponse{Token: "otp-token"}, nil)
+
+	w := postJSON(r, "/auth/otp/verify", models.OTPVerifyRequest{Phone: testPhone, Code: "123456"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.LoginResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "otp-token", response.Token)
+}
+
+// TestVerifyOTPWrongCode проверяет учет неудачной попытки
+func TestVerifyOTPWrongCode(t *testing.T) {
+	r, sessions, _, otpQueries, _ := setupOTPTest()
+
+	otpQueries.On("GetActiveCode", mock.Anything, testPhone).
+		Return(&models.OTPCode{ID: "code-id", CodeHash: otp.HashCode(testPhone, "123456")}, nil)
+	otpQueries.On("IncrementAttempts", mock.Anything, "code-id", 5).Return(nil)
+
+	w := postJSON(r, "/auth/otp/verify", models.OTPVerifyRequest{Phone: testPhone, Code: "000000"})
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	otpQueries.AssertExpectations(t)
+	sessions.AssertNotCalled(t, "IssueToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestVerifyOTPAttemptsExceeded проверяет блокировку кода после превышения попыток
+func TestVerifyOTPAttemptsExceeded(t *testing.T) {
+	r, _, _, otpQueries, _ := setupOTPTest()
+
+	otpQueries.On("GetActiveCode", mock.Anything, testPhone).
+		Return(&models.OTPCode{ID: "code-id", CodeHash: otp.HashCode(testPhone, "123456"), Attempts: 4}, nil)
+	// Параллельный запрос успел списать последнюю попытку между чтением кода и проверкой
+	otpQueries.On("IncrementAttempts", mock.Anything, "code-id", 5).Return(queries.ErrNotFound)
+
+	w := postJSON(r, "/auth/otp/verify", models.OTPVerifyRequest{Phone: testPhone, Code: "123456"})
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	otpQueries.AssertNotCalled(t, "ConsumeCode", mock.Anything, mock.Anything)
+}
+
+// TestVerifyOTPNoActiveCode проверяет ответ при отсутствии активного кода
+func TestVerifyOTPNoActiveCode(t *testing.T) {
+	r, _, _, otpQueries, _ := setupOTPTest()
+
+	otpQueries.On("GetActiveCode", mock.Anything, testPhone).Return(nil, queries.ErrNotFound)
+
+	w := postJSON(r, "/auth/otp/verify", models.OTPVerifyRequest{Phone: testPhone, Code: "123456"})
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/clock"
+	"pvz-service/internal/config"
+	"pvz-service/internal/db"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/mail"
+	"pvz-service/internal/models"
+	"pvz-service/internal/otp"
+	"pvz-service/internal/tracing"
+	"pvz-service/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PasswordHandler содержит обработчики смены и сброса пароля
+type PasswordHandler struct {
+	authQueries     queries.AuthQueriesInterface
+	resetQueries    queries.PasswordResetQueriesInterface
+	sessionQueries  queries.SessionQueriesInterface
+	tx              db.Transactor
+	passwordChecker utils.PasswordCheckerInterface
+	mailSender      mail.SenderInterface
+	config          config.PasswordResetConfig
+	clock           clock.Clock
+}
+
+// NewPasswordHandler создает новый экземпляр PasswordHandler.
+// После смены или сброса пароля остальные сессии пользователя завершаются
+func NewPasswordHandler(authQueries queries.AuthQueriesInterface, resetQueries queries.PasswordResetQueriesInterface, sessionQueries queries.SessionQueriesInterface, tx db.Transactor, passwordChecker utils.PasswordCheckerInterface, mailSender mail.SenderInterface, config config.PasswordResetConfig) *PasswordHandler {
+	return &PasswordHandler{
+		authQueries:     authQueries,
+		resetQueries:    resetQueries,
+		sessionQueries:  sessionQueries,
+		tx:              tx,
+		passwordChecker: passwordChecker,
+		mailSender:      mailSender,
+		config:          config,
+		clock:           clock.System{},
+	}
+}
+
+// ChangePassword обрабатывает запрос на смену пароля текущего пользователя по старому паролю.
+// Текущая сессия сохраняется, остальные завершаются
+func (h *PasswordHandler) ChangePassword(c *gin.Context) {
+	var req models.ChangePasswordRequest
+
+	// Проверяем запрос
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	userID := c.GetString("userID")
+	user, err := h.authQueries.GetUserCredentialsByID(c.Request.Context(), userID)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgUserContextMissing))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgChangePasswordFailed, err))
+		return
+	}
+
+	_, span := tracing.Start(c.Request.Context(), "password.Check")
+	err = h.passwordChecker.CheckPassword(req.OldPassword, user.PasswordHash)
+	span.End()
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgOldPasswordInvalid))
+		return
+	}
+
+	passwordHash, err := h.hashPassword(c.Request.Context(), req.NewPassword)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgPasswordHashFailed, err))
+		return
+	}
+
+	err = h.tx.InTx(c.Request.Context(), func(ctx context.Context) error {
+		if err := h.authQueries.UpdatePasswordHash(ctx, user.ID, passwordHash); err != nil {
+			return err
+		}
+		_, err := h.sessionQueries.RevokeOtherSessions(ctx, user.ID, c.GetString("sessionID"))
+		return err
+	})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgChangePasswordFailed, err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RequestReset обрабатывает запрос на отправку токена сброса пароля на email.
+// Ответ не зависит от того, зарегистрирован ли email и не превышен ли лимит писем
+func (h *PasswordHandler) RequestReset(c *gin.Context) {
+	var req models.PasswordResetRequest
+
+	// Проверяем запрос
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	user, err := h.authQueries.GetUserWithCredentials(c.Request.Context(), req.Email)
+	if errors.Is(err, queries.ErrNotFound) {
+		c.Status(http.StatusAccepted)
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgPasswordResetRequestFailed, err))
+		return
+	}
+
+	// Ограничиваем число писем одному пользователю
+	now := h.clock.Now()
+	count, err := h.resetQueries.CountTokensSince(c.Request.Context(), user.ID, now.Add(-h.config.RequestWindow))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgPasswordResetRequestFailed, err))
+		return
+	}
+	if count >= h.config.RequestLimit {
+		log.Printf("Password reset limit reached for user %s", user.ID)
+		c.Status(http.StatusAccepted)
+		return
+	}
+
+	token, err := otp.GenerateToken()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgPasswordResetRequestFailed, err))
+		return
+	}
+
+	err = h.resetQueries.CreateToken(c.Request.Context(), user.ID, otp.HashToken(token), now.Add(h.config.TokenTTL))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgPasswordResetRequestFailed, err))
+		return
+	}
+
+	err = h.mailSender.Send(c.Request.Context(), req.Email, i18n.T(c, i18n.MsgPasswordResetSubject), i18n.T(c, i18n.MsgPasswordResetMessage)+": "+token)
+	if err != nil {
+		response.Error(c, http.StatusBadGateway, i18n.Wrap(c, i18n.MsgPasswordResetRequestFailed, err))
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// ConfirmReset обрабатывает запрос на установку нового пароля по токену сброса.
+// Токен одноразовый, все сессии пользователя завершаются
+func (h *PasswordHandler) ConfirmReset(c *gin.Context) {
+	var req models.PasswordResetConfirmRequest
+
+	// Проверяем запрос
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	// Хешируем пароль до использования токена, чтобы ошибка хеширования не сожгла токен
+	passwordHash, err := h.hashPassword(c.Request.Context(), req.NewPassword)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgPasswordHashFailed, err))
+		return
+	}
+
+	err = h.tx.InTx(c.Request.Context(), func(ctx context.Context) error {
+		userID, err := h.resetQueries.ConsumeToken(ctx, otp.HashToken(req.Token))
+		if err != nil {
+			return err
+		}
+		if err := h.authQueries.UpdatePasswordHash(ctx, userID, passwordHash); err != nil {
+			return err
+		}
+		_, err = h.sessionQueries.RevokeOtherSessions(ctx, userID, "")
+		return err
+	})
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgResetTokenInvalid))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgPasswordResetFailed, err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// hashPassword хеширует новый пароль с текущими настройками
+func (h *PasswordHandler) hashPassword(ctx context.Context, password string) (string, error) {
+	_, span := tracing.Start(ctx, "password.Hash")
+	passwordHash, err := h.passwordChecker.HashPassword(password)
+	tracing.End(span, err)
+	return passwordHash, err
+}
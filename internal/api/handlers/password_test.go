@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/config"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/otp"
+)
+
+// MockPasswordResetQueries мокирует запросы к токенам сброса пароля
+type MockPasswordResetQueries struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetQueries) CountTokensSince(ctx context.Context, userID string, since time.Time) (int, error) {
+	args := m.Called(ctx, userID, since)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPasswordResetQueries) CreateToken(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error {
+	args := m.Called(ctx, userID, tokenHash, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetQueries) ConsumeToken(ctx context.Context, tokenHash string) (string, error) {
+	args := m.Called(ctx, tokenHash)
+	return args.String(0), args.Error(1)
+}
+
+// MockMailSender мокирует отправку писем
+type MockMailSender struct {
+	mock.Mock
+}
+
+func (m *MockMailSender) Send(ctx context.Context, to, subject, body string) error {
+	args := m.Called(ctx, to, subject, body)
+	return args.Error(0)
+}
+
+const testResetEmail = "employee@example.com"
+
+type passwordTestDeps struct {
+	authQueries     *MockAuthQueries
+	resetQueries    *MockPasswordResetQueries
+	sessionQueries  *MockSessionQueries
+	passwordChecker *MockPasswordChecker
+	mailSender      *MockMailSender
+}
+
+// setupPasswordTest создает роутер смены и сброса пароля для пользователя с текущей сессией testSessionID
+func setupPasswordTest() (*gin.Engine, passwordTestDeps) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	deps := passwordTestDeps{
+		authQueries:     new(MockAuthQueries),
+		resetQueries:    new(MockPasswordResetQueries),
+		sessionQueries:  new(MockSessionQueries),
+		passwordChecker: new(MockPasswordChecker),
+		mailSender:      new(MockMailSender),
+	}
+
+	passwordHandler := NewPasswordHandler(deps.authQueries, deps.resetQueries, deps.sessionQueries, passthroughTx{},
+		deps.passwordChecker, deps.mailSender, config.PasswordResetConfig{
+			TokenTTL:      time.Hour,
+			RequestLimit:  3,
+			RequestWindow: time.Hour,
+		})
+
+	r.POST("/auth/reset/request", passwordHandler.RequestReset)
+	r.POST("/auth/reset/confirm", passwordHandler.ConfirmReset)
+	r.POST("/me/change_password", func(c *gin.Context) {
+		c.Set("userID", testEmployeeID)
+		c.Set("sessionID", testSessionID)
+		c.Next()
+	}, passwordHandler.ChangePassword)
+
+	return r, deps
+}
+
+// TestChangePasswordSuccess проверяет смену пароля с завершением остальных сессий
+func TestChangePasswordSuccess(t *testing.T) {
+	r, deps := setupPasswordTest()
+
+	deps.authQueries.On("GetUserCredentialsByID", mock.Anything, testEmployeeID).
+		Return(&models.User{ID: testEmployeeID, PasswordHash: "old-hash"}, nil)
+	deps.passwordChecker.On("CheckPassword", "old-secret", "old-hash").Return(nil)
+	deps.passwordChecker.On("HashPassword", "new-secret").Return("new-hash", nil)
+	deps.authQueries.On("UpdatePasswordHash", mock.Anything, testEmployeeID, "new-hash").Return(nil)
+	deps.sessionQueries.On("RevokeOtherSessions", mock.Anything, testEmployeeID, testSessionID).Return(int64(2), nil)
+
+	w := postJSON(r, "/me/change_password", models.ChangePasswordRequest{OldPassword: "old-secret", NewPassword: "new-secret"})
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	deps.authQueries.AssertExpectations(t)
+	deps.sessionQueries.AssertExpectations(t)
+}
+
+// TestChangePasswordWrongOldPassword проверяет, что без верного текущего пароля пароль не меняется
+func TestChangePasswordWrongOldPassword(t *testing.T) {
+	r, deps := setupPasswordTest()
+
+	deps.authQueries.On("GetUserCredentialsByID", mock.Anything, testEmployeeID).
+		Return(&models.User{ID: testEmployeeID, PasswordHash: "old-hash"}, nil)
+	deps.passwordChecker.On("CheckPassword", "wrong", "old-hash").Return(assert.AnError)
+
+	w := postJSON(r, "/me/change_password", models.ChangePasswordRequest{OldPassword: "wrong", NewPassword: "new-secret"})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	deps.authQueries.AssertNotCalled(t, "UpdatePasswordHash", mock.Anything, mock.Anything, mock.Anything)
+	deps.sessionQueries.AssertNotCalled(t, "RevokeOtherSessions", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestRequestResetSendsToken проверяет отправку токена и сохранение только его хеша
+func TestRequestResetSendsToken(t *testing.T) {
+	r, deps := setupPasswordTest()
+
+	var sentToken string
+	deps.authQueries.On("GetUserWithCredentials", mock.Anything, testResetEmail).
+		Return(&models.User{ID: testEmployeeID, Email: testResetEmail}, nil)
+	deps.resetQueries.On("CountTokensSince", mock.Anything, testEmployeeID, mock.Anything).Return(0, nil)
+	deps.resetQueries.On("CreateToken", mock.Anything, testEmployeeID, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	deps.mailSender.On("Send", mock.Anything, testResetEmail, mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) {
+			body := args.String(3)
+			sentToken = body[strings.LastIndex(body, " ")+1:]
+		}).
+		Return(nil)
+
+	w := postJSON(r, "/auth/reset/request", models.PasswordResetRequest{Email: testResetEmail})
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.NotEmpty(t, sentToken)
+	tokenHash := deps.resetQueries.Calls[1].Arguments.String(2)
+	assert.Equal(t, otp.HashToken(sentToken), tokenHash)
+}
+
+// TestRequestResetUnknownEmail проверяет, что ответ не раскрывает наличие email
+func TestRequestResetUnknownEmail(t *testing.T) {
+	r, deps := setupPasswordTest()
+
+	deps.authQueries.On("GetUserWithCredentials", mock.Anything, testResetEmail).Return(nil, queries.ErrNotFound)
+
+	w := postJSON(r, "/auth/reset/request", models.PasswordResetRequest{Email: testResetEmail})
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	deps.mailSender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestRequestResetLimit проверяет, что сверх лимита письма не отправляются, а ответ не меняется
+func TestRequestResetLimit(t *testing.T) {
+	r, deps := setupPasswordTest()
+
+	deps.authQueries.On("GetUserWithCredentials", mock.Anything, testResetEmail).
+		Return(&models.User{ID: testEmployeeID, Email: testResetEmail}, nil)
+	deps.resetQueries.On("CountTokensSince", mock.Anything, testEmployeeID, mock.Anything).Return(3, nil)
+
+	w := postJSON(r, "/auth/reset/request", models.PasswordResetRequest{Email: testResetEmail})
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	deps.resetQueries.AssertNotCalled(t, "CreateToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	deps.mailSender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestConfirmResetSuccess проверяет установку пароля по токену и завершение всех сессий
+func TestConfirmResetSuccess(t *testing.T) {
+	r, deps := setupPasswordTest()
+
+	deps.passwordChecker.On("HashPassword", "new-secret").Return("new-hash", nil)
+	deps.resetQueries.On("ConsumeToken", mock.Anything, otp.HashToken("reset-token")).Return(testEmployeeID, nil)
+	deps.authQueries.On("UpdatePasswordHash", mock.Anything, testEmployeeID, "new-hash").Return(nil)
+	deps.sessionQueries.On("RevokeOtherSessions", mock.Anything, testEmployeeID, "").Return(int64(1), nil)
+
+	w := postJSON(r, "/auth/reset/confirm", models.PasswordResetConfirmRequest{Token: "reset-token", NewPassword: "new-secret"})
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	deps.authQueries.AssertExpectations(t)
+	deps.sessionQueries.AssertExpectations(t)
+}
+
+// TestConfirmResetInvalidToken проверяет ответ для истекшего или уже использованного токена
+func TestConfirmResetInvalidToken(t *testing.T) {
+	r, deps := setupPasswordTest()
+
+	deps.passwordChecker.On("HashPassword", "new-secret").Return("new-hash", nil)
+	deps.resetQueries.On("ConsumeToken", mock.Anything, otp.HashToken("reset-token")).Return("", queries.ErrNotFound)
+
+	w := postJSON(r, "/auth/reset/confirm", models.PasswordResetConfirmRequest{Token: "reset-token", NewPassword: "new-secret"})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	deps.authQueries.AssertNotCalled(t, "UpdatePasswordHash", mock.Anything, mock.Anything, mock.Anything)
+}
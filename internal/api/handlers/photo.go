@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+	"pvz-service/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// errInvalidPhoto возвращается для файла неподдерживаемого формата или слишком большого размера
+var errInvalidPhoto = errors.New("invalid photo")
+
+// bodyTooLarge сообщает, что загружаемый файл не уместился в ограничение http.MaxBytesReader
+func bodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
+// photoFiles читает файлы фотографий из поля photos multipart-запроса, ограничивая размер тела.
+// В запросах другого типа файлов нет
+func photoFiles(c *gin.Context) ([]*multipart.FileHeader, error) {
+	if c.ContentType() != "multipart/form-data" {
+		return nil, nil
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, models.MaxProductPhotos*models.MaxPhotoSize+1<<20)
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, err
+	}
+	return form.File["photos"], nil
+}
+
+// respondPhotoUploadError преобразует ошибку загрузки фотографий в ответ
+func respondPhotoUploadError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, errInvalidPhoto):
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidPhoto, err))
+	case errors.Is(err, storage.ErrNotConfigured):
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgPhotoStorageDisabled))
+	default:
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgPhotoUploadFailed, err))
+	}
+}
+
+// uploadPhotos проверяет файлы фотографий и загружает их в хранилище под ключами приёмки
+func uploadPhotos(ctx context.Context, store storage.Storage, receptionID string, files []*multipart.FileHeader) ([]models.ProductPhoto, error) {
+	photos := make([]models.ProductPhoto, 0, len(files))
+
+	for _, fileHeader := range files {
+		if fileHeader.Size > models.MaxPhotoSize {
+			return nil, fmt.Errorf("%w: %s is larger than %d bytes", errInvalidPhoto, fileHeader.Filename, models.MaxPhotoSize)
+		}
+
+		key, err := uploadImage(ctx, store, "products/"+receptionID+"/", fileHeader)
+		if err != nil {
+			return nil, err
+		}
+		photos = append(photos, models.ProductPhoto{StorageKey: &key})
+	}
+
+	return photos, nil
+}
+
+// uploadImage определяет формат изображения по содержимому и загружает его в хранилище
+// под случайным ключом с префиксом prefix
+func uploadImage(ctx context.Context, store storage.Storage, prefix string, fileHeader *multipart.FileHeader) (string, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", fileHeader.Filename, err)
+	}
+	defer file.Close()
+
+	// Формат определяется по первым байтам, расширению и заголовку клиента не доверяем
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", fmt.Errorf("failed to read %s: %w", fileHeader.Filename, err)
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	ext, ok := models.PhotoContentTypes[contentType]
+	if !ok {
+		return "", fmt.Errorf("%w: %s has unsupported type %s", errInvalidPhoto, fileHeader.Filename, contentType)
+	}
+
+	key := prefix + uuid.New().String() + ext
+	body := io.MultiReader(bytes.NewReader(head), file)
+	if err := store.Put(ctx, key, body, fileHeader.Size, contentType); err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/models"
+	"pvz-service/internal/storage"
+)
+
+// pngHeader - сигнатура PNG, по которой определяется формат файла
+var pngHeader = []byte("\x89PNG\r\n\x1a\n0000")
+
+// memoryStorage хранит загруженные объекты в памяти
+type memoryStorage struct {
+	objects map[string]string
+}
+
+func (s *memoryStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	s.objects[key] = contentType + ":" + string(data)
+	return nil
+}
+
+func (s *memoryStorage) URL(ctx context.Context, key string) (string, error) {
+	return "https://storage.example.com/" + key, nil
+}
+
+func setupPhotoTest(store storage.Storage) (*gin.Engine, *MockProductQueries) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	productQueries := new(MockProductQueries)
+	receptionQueries := new(MockReceptionQueries)
+	receptionQueries.On("GetLastOpenReception", mock.Anything, "123e4567-e89b-12d3-a456-426614174000").Return(&models.Reception{
+		ID:     "reception-uuid",
+		PvzID:  "123e4567-e89b-12d3-a456-426614174000",
+		Status: "in_progress",
+	}, nil)
+	receptionQueries.On("LockReceptions", mock.Anything, mock.Anything).Return([]models.Reception{{Status: "in_progress"}}, nil)
+
+	productHandler := newTestProductHandler(productQueries, receptionQueries, &recordingOutbox{}, store, productLimits{}, alwaysOpen{}, noQuotas{}, noLocks{})
+	r.POST("/products", func(c *gin.Context) {
+		c.Set("userRole", "employee")
+		productHandler.AddProduct(c)
+	})
+
+	return r, productQueries
+}
+
+// newPhotoRequest собирает multipart-запрос добавления товара с файлами фотографий
+func newPhotoRequest(files ...[]byte) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	writer.WriteField("type", "электроника")
+	writer.WriteField("pvzId", "123e4567-e89b-12d3-a456-426614174000")
+	for i, data := range files {
+		part, _ := writer.CreateFormFile("photos", fmt.Sprintf("photo%d.bin", i))
+		part.Write(data)
+	}
+	writer.Close()
+
+	req, _ := http.NewRequest("POST", "/products", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+// TestAddProductWithUploadedPhoto проверяет загрузку фотографии в хранилище при добавлении товара
+func TestAddProductWithUploadedPhoto(t *testing.T) {
+	store := &memoryStorage{objects: map[string]string{}}
+	r, productQueries := setupPhotoTest(store)
+
+	productQueries.On("AddProductWithPhotos", mock.Anything, "reception-uuid", mock.Anything, "электроника", "", mock.MatchedBy(func(photos []models.ProductPhoto) bool {
+		return len(photos) == 1 && photos[0].StorageKey != nil && strings.HasPrefix(*photos[0].StorageKey, "products/reception-uuid/") && strings.HasSuffix(*photos[0].StorageKey, ".png")
+	})).Return(&models.Product{ID: "product-uuid", Datetime: time.Now(), Type: "электроника", ReceptionID: "reception-uuid"}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newPhotoRequest(pngHeader))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response models.ProductResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Photos, 1)
+	assert.True(t, strings.HasPrefix(response.Photos[0], "https://storage.example.com/products/reception-uuid/"))
+
+	assert.Len(t, store.objects, 1)
+	for _, object := range store.objects {
+		assert.Equal(t, "image/png:"+string(pngHeader), object)
+	}
+	productQueries.AssertExpectations(t)
+}
+
+// TestAddProductWithPhotoURL проверяет сохранение внешней ссылки на фотографию
+func TestAddProductWithPhotoURL(t *testing.T) {
+	r, productQueries := setupPhotoTest(storage.Disabled{})
+
+	photoURL := "https://cdn.example.com/photo.jpg"
+	productQueries.On("AddProductWithPhotos", mock.Anything, "reception-uuid", mock.Anything, "электроника", "", []models.ProductPhoto{{URL: &photoURL}}).
+		Return(&models.Product{ID: "product-uuid", Type: "электроника", ReceptionID: "reception-uuid"}, nil)
+
+	jsonData, _ := json.Marshal(models.CreateProductRequest{
+		Type:      "электроника",
+		PvzID:     "123e4567-e89b-12d3-a456-426614174000",
+		PhotoURLs: []string{photoURL},
+	})
+	req, _ := http.NewRequest("POST", "/products", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response models.ProductResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, []string{photoURL}, response.Photos)
+}
+
+// TestAddProductInvalidPhoto проверяет отказ для файлов, не являющихся изображениями
+func TestAddProductInvalidPhoto(t *testing.T) {
+	r, productQueries := setupPhotoTest(&memoryStorage{objects: map[string]string{}})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newPhotoRequest([]byte("%PDF-1.4 not an image")))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	productQueries.AssertNotCalled(t, "AddProductWithPhotos", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestAddProductPhotoStorageDisabled проверяет отказ в загрузке без настроенного хранилища
+func TestAddProductPhotoStorageDisabled(t *testing.T) {
+	r, _ := setupPhotoTest(storage.Disabled{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newPhotoRequest(pngHeader))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestAddProductTooManyPhotos проверяет ограничение количества фотографий
+func TestAddProductTooManyPhotos(t *testing.T) {
+	r, _ := setupPhotoTest(&memoryStorage{objects: map[string]string{}})
+
+	files := make([][]byte, models.MaxProductPhotos+1)
+	for i := range files {
+		files[i] = pngHeader
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newPhotoRequest(files...))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -1,92 +1,157 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"log"
 	"net/http"
+	"strconv"
 
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/authz"
+	"pvz-service/internal/db"
 	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/mapper"
 	"pvz-service/internal/models"
+	"pvz-service/internal/service"
+	"pvz-service/internal/storage"
 
 	"github.com/gin-gonic/gin"
 )
 
+// maxProductListRows ограничивает количество товаров в одном ответе списка товаров приёмки
+const maxProductListRows = 100000
+
 // ProductHandler содержит обработчики для работы с товарами
 type ProductHandler struct {
 	productQueries   queries.ProductQueriesInterface
 	receptionQueries queries.ReceptionQueriesInterface
+	tx               db.Transactor
+	outboxQueries    queries.OutboxQueriesInterface
+	storage          storage.Storage
+	productService   *service.ProductService
+	maxListRows      int
 }
 
-// NewProductHandler создает новый экземпляр ProductHandler
-func NewProductHandler(productQueries queries.ProductQueriesInterface, receptionQueries queries.ReceptionQueriesInterface) *ProductHandler {
+// NewProductHandler создает новый экземпляр ProductHandler.
+// События о товарах записываются в outbox в одной транзакции с изменением,
+// правила добавления и удаления товаров применяет productService, общий с gRPC потоком сканирований
+func NewProductHandler(productQueries queries.ProductQueriesInterface, receptionQueries queries.ReceptionQueriesInterface, tx db.Transactor, outboxQueries queries.OutboxQueriesInterface, storage storage.Storage, productService *service.ProductService) *ProductHandler {
 	return &ProductHandler{
 		productQueries:   productQueries,
 		receptionQueries: receptionQueries,
+		tx:               tx,
+		outboxQueries:    outboxQueries,
+		storage:          storage,
+		productService:   productService,
+		maxListRows:      maxProductListRows,
 	}
 }
 
 // AddProduct обрабатывает запрос на добавление товара в приёмку
 func (h *ProductHandler) AddProduct(c *gin.Context) {
-	// Проверяем, что пользователь - сотрудник
-	userRole, _ := c.Get("userRole")
-	if userRole != "employee" {
-		c.JSON(http.StatusForbidden, models.ErrorResponse{
-			Message: "Доступ запрещен: только сотрудники могут добавлять товары",
-		})
+	// Проверяем право роли пользователя на действие
+	if !authz.Can(c.GetString("userRole"), authz.ProductAdd) {
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbiddenAddProduct))
 		return
 	}
 
 	var req models.CreateProductRequest
 
+	// Фотографии загружаются вместе с товаром в multipart-запросе
+	files, err := photoFiles(c)
+	if bodyTooLarge(err) {
+		response.Error(c, http.StatusRequestEntityTooLarge, i18n.T(c, i18n.MsgRequestTooLarge))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
 	// Проверяем запрос
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Message: "Неверный запрос: " + err.Error(),
-		})
+	if err := c.ShouldBind(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
 		return
 	}
 
-	// Получаем последнюю открытую приёмку для ПВЗ
-	reception, err := h.receptionQueries.GetLastOpenReception(c.Request.Context(), req.PvzID)
+	if len(req.PhotoURLs)+len(files) > models.MaxProductPhotos {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgTooManyPhotos))
+		return
+	}
+	if denyPVZAccess(c, req.PvzID) {
+		return
+	}
+
+	// Получаем открытую приёмку для ПВЗ
+	reception, err := h.productService.OpenReception(c.Request.Context(), req.PvzID)
+	if errors.Is(err, service.ErrReceptionClosed) {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgReceptionClosed))
+		return
+	}
+	if errors.Is(err, service.ErrReceptionPaused) {
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgReceptionPaused))
+		return
+	}
+	if errors.Is(err, service.ErrOutsideWorkingHours) {
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgOutsideWorkingHours))
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Message: "Нет активной приёмки для данного ПВЗ: " + err.Error(),
-		})
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgNoOpenReception, err))
 		return
 	}
 
-	// Проверяем, что статус приёмки - "in_progress"
-	if reception.Status != "in_progress" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Message: "Приёмка уже закрыта",
-		})
+	// Загружаем фотографии в хранилище до записи товара
+	photos, err := uploadPhotos(c.Request.Context(), h.storage, reception.ID, files)
+	if err != nil {
+		respondPhotoUploadError(c, err)
 		return
 	}
+	for _, url := range req.PhotoURLs {
+		photos = append(photos, models.ProductPhoto{URL: &url})
+	}
 
-	// Добавляем товар
-	product, err := h.productQueries.AddProduct(c.Request.Context(), reception.ID, req.Type)
+	// Добавляем товар и событие о нем в одной транзакции
+	result, err := h.productService.AddProduct(c.Request.Context(), c.GetString("userID"), reception, req.Type, req.Barcode, photos, req.OverrideCapacity)
+	// Приёмку могли закрыть или приостановить параллельным запросом
+	if errors.Is(err, service.ErrReceptionClosed) {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgReceptionClosed))
+		return
+	}
+	if errors.Is(err, service.ErrReceptionPaused) {
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgReceptionPaused))
+		return
+	}
+	var limitErr *service.ProductLimitError
+	if errors.As(err, &limitErr) {
+		response.ErrorWithDetails(c, http.StatusConflict, i18n.T(c, i18n.MsgProductTypeLimitExceeded), limitErr.Exceeded)
+		return
+	}
+	var fullErr *service.PVZFullError
+	if errors.As(err, &fullErr) {
+		response.ErrorWithDetails(c, http.StatusConflict, i18n.T(c, i18n.MsgPVZFull), fullErr.Occupancy)
+		return
+	}
+	if errors.Is(err, service.ErrReceptionFull) {
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgReceptionFull))
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Message: "Ошибка при добавлении товара: " + err.Error(),
-		})
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgAddProductFailed, err))
 		return
 	}
 
 	// Возвращаем данные добавленного товара
-	c.JSON(http.StatusCreated, models.ProductResponse{
-		ID:          product.ID,
-		DateTime:    product.Datetime,
-		Type:        product.Type,
-		ReceptionID: product.ReceptionID,
-	})
+	response.JSON(c, http.StatusCreated, result)
 }
 
 // DeleteLastProduct обрабатывает запрос на удаление последнего добавленного товара
 func (h *ProductHandler) DeleteLastProduct(c *gin.Context) {
-	// Проверяем, что пользователь - сотрудник
-	userRole, _ := c.Get("userRole")
-	if userRole != "employee" {
-		c.JSON(http.StatusForbidden, models.ErrorResponse{
-			Message: "Доступ запрещен: только сотрудники могут удалять товары",
-		})
+	// Проверяем право роли пользователя на действие
+	if !authz.Can(c.GetString("userRole"), authz.ProductDeleteLast) {
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbiddenDeleteProduct))
 		return
 	}
 
@@ -94,47 +159,384 @@ func (h *ProductHandler) DeleteLastProduct(c *gin.Context) {
 
 	// Проверяем, что pvzId указан
 	if pvzID == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Message: "Не указан ID ПВЗ",
-		})
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgPVZIDRequired))
 		return
 	}
 
-	// Получаем последнюю открытую приёмку для ПВЗ
-	reception, err := h.receptionQueries.GetLastOpenReception(c.Request.Context(), pvzID)
+	// Удаляем последний товар открытой приёмки по общему правилу удаления
+	err := h.productService.DeleteLastProduct(c.Request.Context(), c.GetString("userRole"), c.GetString("userID"), pvzID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Message: "Нет активной приёмки для данного ПВЗ: " + err.Error(),
-		})
+		respondDeleteProductError(c, err)
 		return
 	}
 
-	// Проверяем, что статус приёмки - "in_progress"
-	if reception.Status != "in_progress" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Message: "Приёмка уже закрыта",
-		})
+	// Возвращаем успешный ответ
+	c.Status(http.StatusOK)
+}
+
+// DeleteLastProducts обрабатывает запрос сотрудника на удаление count последних добавленных товаров
+// открытой приёмки ПВЗ одной транзакцией. В ответе - ID удаленных товаров от последнего к первому
+func (h *ProductHandler) DeleteLastProducts(c *gin.Context) {
+	var query models.DeleteLastProductsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
 		return
 	}
 
-	// Получаем последний добавленный товар
-	product, err := h.productQueries.GetLastProductFromReception(c.Request.Context(), reception.ID)
+	deleted, err := h.productService.DeleteLastProducts(c.Request.Context(), c.GetString("userRole"), c.GetString("userID"), c.Param("pvzId"), query.Count)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Message: "Нет товаров для удаления в данной приёмке: " + err.Error(),
-		})
+		respondDeleteProductError(c, err)
 		return
 	}
 
-	// Удаляем товар
-	err = h.productQueries.DeleteProduct(c.Request.Context(), product.ID)
+	response.JSON(c, http.StatusOK, models.DeleteLastProductsResponse{ProductIDs: deleted})
+}
+
+// DeleteProduct обрабатывает запрос на удаление товара открытой приёмки по ID.
+// Модератор может удалить любой товар, сотрудник - только последний добавленный
+func (h *ProductHandler) DeleteProduct(c *gin.Context) {
+	if h.denyProductPVZAccess(c, c.Param("productId")) {
+		return
+	}
+
+	err := h.productService.DeleteProduct(c.Request.Context(), c.GetString("userRole"), c.GetString("userID"), c.Param("productId"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Message: "Ошибка при удалении товара: " + err.Error(),
+		respondDeleteProductError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// UpdateProduct обрабатывает запрос на исправление типа товара. Сотрудник исправляет товары открытой
+// приёмки, модератор - закрытой; исправление записывается в историю, порядок товаров не меняется
+func (h *ProductHandler) UpdateProduct(c *gin.Context) {
+	var req models.UpdateProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+	if h.denyProductPVZAccess(c, c.Param("productId")) {
+		return
+	}
+
+	result, err := h.productService.UpdateProductType(c.Request.Context(), c.GetString("userRole"), c.GetString("userID"), c.Param("productId"), req.Type)
+	var limitErr *service.ProductLimitError
+	switch {
+	case errors.Is(err, service.ErrRetypeForbidden):
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbidden))
+	case errors.Is(err, queries.ErrNotFound):
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgProductNotFound))
+	case errors.As(err, &limitErr):
+		response.ErrorWithDetails(c, http.StatusConflict, i18n.T(c, i18n.MsgProductTypeLimitExceeded), limitErr.Exceeded)
+	case err != nil:
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgUpdateProductTypeFailed, err))
+	default:
+		response.JSON(c, http.StatusOK, result)
+	}
+}
+
+// MarkDamaged обрабатывает запрос сотрудника на отметку товара приёмки поврежденным с описанием
+// и фотографиями повреждения: ссылками в photoUrls или файлами в multipart-запросе
+func (h *ProductHandler) MarkDamaged(c *gin.Context) {
+	if !authz.Can(c.GetString("userRole"), authz.ProductMarkDamaged) {
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbidden))
+		return
+	}
+
+	files, err := photoFiles(c)
+	if bodyTooLarge(err) {
+		response.Error(c, http.StatusRequestEntityTooLarge, i18n.T(c, i18n.MsgRequestTooLarge))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	var req models.MarkDamagedRequest
+	if err := c.ShouldBind(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+	if len(req.PhotoURLs)+len(files) > models.MaxProductPhotos {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgTooManyPhotos))
+		return
+	}
+	if h.denyProductPVZAccess(c, c.Param("productId")) {
+		return
+	}
+
+	product, err := h.productQueries.GetProduct(c.Request.Context(), c.Param("productId"))
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgProductNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgMarkDamagedFailed, err))
+		return
+	}
+
+	photos, err := uploadPhotos(c.Request.Context(), h.storage, product.ReceptionID, files)
+	if err != nil {
+		respondPhotoUploadError(c, err)
+		return
+	}
+	for _, url := range req.PhotoURLs {
+		photos = append(photos, models.ProductPhoto{URL: &url})
+	}
+
+	result, err := h.productService.MarkDamaged(c.Request.Context(), c.GetString("userID"), product, req.Description, photos)
+	switch {
+	case errors.Is(err, service.ErrProductAlreadyDamaged):
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgProductAlreadyDamaged))
+	case errors.Is(err, service.ErrReceptionClosed):
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgReceptionClosed))
+	case err != nil:
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgMarkDamagedFailed, err))
+	default:
+		response.JSON(c, http.StatusOK, result)
+	}
+}
+
+// denyProductPVZAccess отвечает 403, если товар лежит в ПВЗ вне области токена, и сообщает,
+// отклонен ли запрос. ПВЗ товара запрашивается только для токена со списком ПВЗ, а несуществующий
+// товар пропускается, чтобы обработчик ответил на него как обычно
+func (h *ProductHandler) denyProductPVZAccess(c *gin.Context, productID string) bool {
+	if !pvzScoped(c) {
+		return false
+	}
+
+	states, err := h.productQueries.GetProductStates(c.Request.Context(), []string{productID})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetProductStatusFailed, err))
+		return true
+	}
+	if len(states) == 0 {
+		return false
+	}
+	return denyPVZAccess(c, states[0].PvzID)
+}
+
+// respondDeleteProductError преобразует ошибку удаления товара в ответ
+func respondDeleteProductError(c *gin.Context, err error) {
+	var quotaErr *service.QuotaExceededError
+	switch {
+	case errors.As(err, &quotaErr):
+		respondQuotaExceeded(c, quotaErr)
+	case errors.Is(err, service.ErrLockTimeout):
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgPVZBusy))
+	case errors.Is(err, service.ErrDeleteForbidden):
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbidden))
+	case errors.Is(err, queries.ErrNotFound):
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgProductNotFound))
+	case errors.Is(err, service.ErrNoOpenReception):
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgNoOpenReception, err))
+	case errors.Is(err, service.ErrReceptionClosed):
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgReceptionClosed))
+	case errors.Is(err, service.ErrReceptionPaused):
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgReceptionPaused))
+	case errors.Is(err, service.ErrNoProductsToDelete):
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgNoProductsToDelete, err))
+	case errors.Is(err, service.ErrNotLastProduct):
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgDeleteNotLastProduct))
+	case errors.Is(err, service.ErrNotEnoughProducts):
+		response.Error(c, http.StatusConflict, i18n.Wrap(c, i18n.MsgNotEnoughProducts, err))
+	default:
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgDeleteProductFailed, err))
+	}
+}
+
+// GetStatusBatch обрабатывает запрос статусов набора товаров для сверки офлайн-очереди
+func (h *ProductHandler) GetStatusBatch(c *gin.Context) {
+	var req models.ProductStatusBatchRequest
+
+	// Проверяем запрос
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	states, err := h.productQueries.GetProductStates(c.Request.Context(), req.ProductIDs)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetProductStatusFailed, err))
+		return
+	}
+
+	// Товары ПВЗ вне области токена отдаются как отсутствующие, чтобы не раскрывать их приёмку и ПВЗ
+	statesByID := make(map[string]models.ProductState, len(states))
+	for _, state := range states {
+		if !canAccessPVZ(c, state.PvzID) {
+			continue
+		}
+		statesByID[state.ID] = state
+	}
+
+	// Возвращаем статусы в порядке запроса, отсутствующие товары помечаем not_found
+	result := make([]models.ProductStatusResponse, 0, len(req.ProductIDs))
+	for _, id := range req.ProductIDs {
+		state, ok := statesByID[id]
+		if !ok {
+			result = append(result, models.ProductStatusResponse{
+				ID:     id,
+				Status: models.ProductStatusNotFound,
+			})
+			continue
+		}
+
+		status := models.ProductStatusReceived
+		switch {
+		case state.Status == models.ProductLifecycleIssued:
+			status = models.ProductStatusIssued
+		case state.ReceptionStatus == "in_progress":
+			status = models.ProductStatusInReception
+		}
+
+		result = append(result, models.ProductStatusResponse{
+			ID:          id,
+			Status:      status,
+			Version:     state.Version,
+			ReceptionID: state.ReceptionID,
+			PvzID:       state.PvzID,
 		})
+	}
+
+	response.JSON(c, http.StatusOK, result)
+}
+
+// ListProducts обрабатывает запрос на получение страницы товаров приёмки в порядке добавления.
+// Товары читаются из БД курсором и отправляются клиенту по мере чтения, поэтому большая приёмка
+// не загружается в память целиком. На странице не больше maxListRows товаров, ограничение передается
+// в заголовке X-Row-Limit, пагинация - как у остальных списков. С параметром download=true
+// список отдается файлом
+func (h *ProductHandler) ListProducts(c *gin.Context) {
+	query := models.ProductListQuery{Page: 1}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
 		return
 	}
 
-	// Возвращаем успешный ответ
-	c.Status(http.StatusOK)
+	reception, err := h.receptionQueries.GetReceptionByID(c.Request.Context(), query.ReceptionID)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReceptionNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetReceptionFailed, err))
+		return
+	}
+	if denyPVZAccess(c, reception.PvzID) {
+		return
+	}
+
+	limit := h.maxListRows
+	if query.Limit > 0 && query.Limit < limit {
+		limit = query.Limit
+	}
+	meta := response.NewMeta(query.Page, limit, reception.ProductCount)
+
+	// Ответ начинается с первым прочитанным товаром, чтобы ошибка запроса вернулась статусом 500
+	var stream *response.ArrayWriter
+	err = h.productQueries.StreamProductsByReception(c.Request.Context(), reception.ID, limit, (query.Page-1)*limit, func(product models.Product) error {
+		if stream == nil {
+			stream = h.startProductList(c, reception, meta, query.Download)
+		}
+		return stream.Add(mapper.Product(product))
+	})
+	if err != nil && stream == nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetProductsFailed, err))
+		return
+	}
+	if err != nil {
+		// Статус уже отправлен: клиент увидит оборванный ответ
+		log.Printf("Product list for reception %s interrupted after %d products: %v", reception.ID, stream.Count(), err)
+		return
+	}
+
+	if stream == nil {
+		stream = h.startProductList(c, reception, meta, query.Download)
+	}
+	if err := stream.Close(); err != nil {
+		log.Printf("Failed to finish product list for reception %s: %v", reception.ID, err)
+	}
+}
+
+// startProductList отправляет заголовки страницы товаров приёмки и открывает массив
+func (h *ProductHandler) startProductList(c *gin.Context, reception *models.Reception, meta response.Meta, download bool) *response.ArrayWriter {
+	c.Header("X-Row-Limit", strconv.Itoa(h.maxListRows))
+	if download {
+		response.Attachment(c, "products-"+reception.ID+".json")
+	}
+	return response.StartPaginatedArray(c, http.StatusOK, meta)
+}
+
+// GetInventory обрабатывает запрос товаров, находящихся на хранении в ПВЗ
+func (h *ProductHandler) GetInventory(c *gin.Context) {
+	pvzID := c.Param("pvzId")
+
+	products, err := h.productQueries.GetInventory(c.Request.Context(), pvzID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetInventoryFailed, err))
+		return
+	}
+
+	result := models.InventoryResponse{
+		PvzID:    pvzID,
+		Total:    len(products),
+		Products: mapper.Products(products),
+	}
+
+	response.JSON(c, http.StatusOK, result)
+}
+
+// IssueProduct обрабатывает запрос на выдачу товара, находящегося на хранении
+func (h *ProductHandler) IssueProduct(c *gin.Context) {
+	// Проверяем право роли пользователя на действие
+	if !authz.Can(c.GetString("userRole"), authz.ProductIssue) {
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbiddenIssueProduct))
+		return
+	}
+
+	productID := c.Param("productId")
+
+	// Проверяем, что товар существует и находится на хранении
+	states, err := h.productQueries.GetProductStates(c.Request.Context(), []string{productID})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgIssueProductFailed, err))
+		return
+	}
+	if len(states) == 0 {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgProductNotFound))
+		return
+	}
+	if denyPVZAccess(c, states[0].PvzID) {
+		return
+	}
+	if states[0].Status != models.ProductLifecycleStored {
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgProductNotStored))
+		return
+	}
+
+	// Выдаем товар и записываем событие о выдаче в одной транзакции
+	var result models.ProductResponse
+	err = h.tx.InTx(c.Request.Context(), func(ctx context.Context) error {
+		product, err := h.productQueries.IssueProduct(ctx, productID)
+		if err != nil {
+			return err
+		}
+
+		result = mapper.Product(*product)
+		return h.outboxQueries.AddEvent(ctx, states[0].PvzID, models.EventProductIssued, result)
+	})
+	// Товар могли выдать параллельным запросом после проверки
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgProductNotStored))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgIssueProductFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, result)
 }
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupProductDamageTest создает маршрут отметки товара поврежденным с ролью role
+func setupProductDamageTest(role string) (*gin.Engine, *MockProductQueries, *MockReceptionQueries, *recordingOutbox) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	productQueries := new(MockProductQueries)
+	receptionQueries := new(MockReceptionQueries)
+	outbox := &recordingOutbox{}
+	productHandler := newTestProductHandler(productQueries, receptionQueries, outbox, storage.Disabled{}, productLimits{}, alwaysOpen{}, noQuotas{}, noLocks{})
+	r.POST("/products/:productId/mark_damaged", func(c *gin.Context) {
+		c.Set("userRole", role)
+		c.Set("userID", testEmployeeID)
+		productHandler.MarkDamaged(c)
+	})
+
+	return r, productQueries, receptionQueries, outbox
+}
+
+// postMarkDamaged отправляет запрос отметки товара поврежденным
+func postMarkDamaged(r *gin.Engine, req models.MarkDamagedRequest) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(req)
+	httpReq, _ := http.NewRequest("POST", "/products/"+testProductID+"/mark_damaged", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httpReq)
+	return w
+}
+
+// TestMarkDamaged проверяет отметку товара открытой приёмки поврежденным с фотографией и событием
+func TestMarkDamaged(t *testing.T) {
+	r, productQueries, receptionQueries, outbox := setupProductDamageTest(models.RoleEmployee)
+	photoURL := "https://cdn.example.com/damage.jpg"
+
+	productQueries.On("GetProduct", mock.Anything, testProductID).
+		Return(&models.Product{ID: testProductID, Type: models.ProductTypeShoes, ReceptionID: testReceptionID}, nil)
+	receptionQueries.On("LockReceptions", mock.Anything, []string{testReceptionID}).
+		Return([]models.Reception{{ID: testReceptionID, PvzID: testPvzID, Status: "in_progress"}}, nil)
+	productQueries.On("MarkProductDamaged", mock.Anything, testProductID, testEmployeeID, "порвана коробка", []models.ProductPhoto{{URL: &photoURL}}).
+		Return(&models.ProductDamage{
+			ProductID:   testProductID,
+			ReceptionID: testReceptionID,
+			Type:        models.ProductTypeShoes,
+			Description: "порвана коробка",
+			DamagedAt:   time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		}, nil)
+
+	w := postMarkDamaged(r, models.MarkDamagedRequest{Description: "порвана коробка", PhotoURLs: []string{photoURL}})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result models.ProductDamage
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, "порвана коробка", result.Description)
+	assert.Equal(t, []string{photoURL}, result.Photos)
+	assert.Len(t, outbox.events, 1)
+	assert.Equal(t, models.EventProductDamaged, outbox.events[0].Type)
+	productQueries.AssertExpectations(t)
+}
+
+// TestMarkDamagedRejected проверяет отказ в отметке без изменения товара
+func TestMarkDamagedRejected(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       string
+		product    *models.Product
+		status     string
+		wantStatus int
+	}{
+		{"Модератору отметка недоступна", models.RoleModerator, &models.Product{ID: testProductID, ReceptionID: testReceptionID}, "in_progress", http.StatusForbidden},
+		{"Приёмка закрыта", models.RoleEmployee, &models.Product{ID: testProductID, ReceptionID: testReceptionID}, "close", http.StatusConflict},
+		{"Товар уже отмечен", models.RoleEmployee, &models.Product{ID: testProductID, ReceptionID: testReceptionID, Damaged: true}, "in_progress", http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, productQueries, receptionQueries, outbox := setupProductDamageTest(tt.role)
+
+			productQueries.On("GetProduct", mock.Anything, testProductID).Return(tt.product, nil)
+			receptionQueries.On("LockReceptions", mock.Anything, []string{testReceptionID}).
+				Return([]models.Reception{{ID: testReceptionID, PvzID: testPvzID, Status: tt.status}}, nil)
+
+			w := postMarkDamaged(r, models.MarkDamagedRequest{Description: "вмятина"})
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Empty(t, outbox.events)
+			productQueries.AssertNotCalled(t, "MarkProductDamaged", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestMarkDamagedValidation проверяет отказ без описания и для отсутствующего товара
+func TestMarkDamagedValidation(t *testing.T) {
+	r, productQueries, _, _ := setupProductDamageTest(models.RoleEmployee)
+
+	w := postMarkDamaged(r, models.MarkDamagedRequest{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	productQueries.On("GetProduct", mock.Anything, testProductID).Return(nil, fmt.Errorf("product %s: %w", testProductID, queries.ErrNotFound))
+	w = postMarkDamaged(r, models.MarkDamagedRequest{Description: "вмятина"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"pvz-service/internal/models"
+	"pvz-service/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupDeleteLastProductsTest создает маршрут удаления последних товаров от имени пользователя с ролью role
+func setupDeleteLastProductsTest(role string) (*gin.Engine, *MockProductQueries, *MockReceptionQueries, *recordingOutbox) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	productQueries := new(MockProductQueries)
+	receptionQueries := new(MockReceptionQueries)
+	outbox := &recordingOutbox{}
+	handler := newTestProductHandler(productQueries, receptionQueries, outbox, storage.Disabled{}, productLimits{}, alwaysOpen{}, noQuotas{}, noLocks{})
+
+	r.POST("/pvz/:pvzId/delete_last_products", func(c *gin.Context) {
+		c.Set("userRole", role)
+		c.Set("userID", "user-1")
+		handler.DeleteLastProducts(c)
+	})
+
+	return r, productQueries, receptionQueries, outbox
+}
+
+// TestDeleteLastProducts проверяет удаление нескольких последних товаров с событием на каждый товар
+func TestDeleteLastProducts(t *testing.T) {
+	r, productQueries, receptionQueries, outbox := setupDeleteLastProductsTest(models.RoleEmployee)
+
+	reception := models.Reception{ID: testReceptionID, PvzID: testPvzID, Status: "in_progress"}
+	receptionQueries.On("GetLastOpenReception", mock.Anything, testPvzID).Return(&reception, nil)
+	receptionQueries.On("LockReceptions", mock.Anything, []string{testReceptionID}).Return([]models.Reception{reception}, nil)
+	productQueries.On("GetLastProductsFromReception", mock.Anything, testReceptionID, 3).Return([]models.Product{
+		{ID: "p3", Type: "обувь", ReceptionID: testReceptionID},
+		{ID: "p2", Type: "обувь", ReceptionID: testReceptionID},
+		{ID: "p1", Type: "одежда", ReceptionID: testReceptionID},
+	}, nil)
+	productQueries.On("DeleteProduct", mock.Anything, mock.Anything, "user-1").Return(nil)
+
+	req, _ := http.NewRequest("POST", "/pvz/"+testPvzID+"/delete_last_products?count=3", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result models.DeleteLastProductsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, []string{"p3", "p2", "p1"}, result.ProductIDs)
+	productQueries.AssertNumberOfCalls(t, "DeleteProduct", 3)
+	assert.Len(t, outbox.events, 3)
+	assert.Equal(t, models.EventProductDeleted, outbox.events[0].Type)
+}
+
+// TestDeleteLastProductsNotEnough проверяет, что при нехватке товаров ничего не удаляется
+func TestDeleteLastProductsNotEnough(t *testing.T) {
+	r, productQueries, receptionQueries, _ := setupDeleteLastProductsTest(models.RoleEmployee)
+
+	reception := models.Reception{ID: testReceptionID, PvzID: testPvzID, Status: "in_progress"}
+	receptionQueries.On("GetLastOpenReception", mock.Anything, testPvzID).Return(&reception, nil)
+	receptionQueries.On("LockReceptions", mock.Anything, []string{testReceptionID}).Return([]models.Reception{reception}, nil)
+	productQueries.On("GetLastProductsFromReception", mock.Anything, testReceptionID, 5).Return([]models.Product{
+		{ID: "p1", ReceptionID: testReceptionID},
+	}, nil)
+
+	req, _ := http.NewRequest("POST", "/pvz/"+testPvzID+"/delete_last_products?count=5", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	productQueries.AssertNotCalled(t, "DeleteProduct", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestDeleteLastProductsRejected проверяет проверку параметра count и права роли до обращения к БД
+func TestDeleteLastProductsRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		role   string
+		query  string
+		status int
+	}{
+		{"Без count", models.RoleEmployee, "", http.StatusBadRequest},
+		{"Нулевой count", models.RoleEmployee, "count=0", http.StatusBadRequest},
+		{"Слишком большой count", models.RoleEmployee, "count=" + strconv.Itoa(models.MaxDeleteLastProducts+1), http.StatusBadRequest},
+		{"Модератор", models.RoleModerator, "count=2", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _, receptionQueries, _ := setupDeleteLastProductsTest(tt.role)
+
+			req, _ := http.NewRequest("POST", "/pvz/"+testPvzID+"/delete_last_products?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			receptionQueries.AssertNotCalled(t, "GetLastOpenReception", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProductLimitHandler содержит обработчики управления ограничениями количества товаров по типам
+type ProductLimitHandler struct {
+	productLimitQueries queries.ProductLimitQueriesInterface
+}
+
+// NewProductLimitHandler создает новый экземпляр ProductLimitHandler
+func NewProductLimitHandler(productLimitQueries queries.ProductLimitQueriesInterface) *ProductLimitHandler {
+	return &ProductLimitHandler{
+		productLimitQueries: productLimitQueries,
+	}
+}
+
+// GetLimits обрабатывает запрос на получение всех ограничений
+func (h *ProductLimitHandler) GetLimits(c *gin.Context) {
+	limits, err := h.productLimitQueries.GetLimits(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetProductLimitsFailed, err))
+		return
+	}
+
+	if limits == nil {
+		limits = []models.ProductTypeLimit{}
+	}
+
+	response.JSON(c, http.StatusOK, limits)
+}
+
+// SetLimit обрабатывает запрос на установку ограничения для типа товара
+func (h *ProductLimitHandler) SetLimit(c *gin.Context) {
+	productType := c.Param("type")
+	if !slices.Contains(models.ProductTypes, productType) {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgInvalidRequest))
+		return
+	}
+
+	var req models.SetProductTypeLimitRequest
+
+	// Проверяем запрос
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	limit, err := h.productLimitQueries.UpsertLimit(c.Request.Context(), models.ProductTypeLimit{
+		Type:            productType,
+		MaxPerReception: req.MaxPerReception,
+		Comment:         req.Comment,
+	})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgSetProductLimitFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, limit)
+}
+
+// DeleteLimit обрабатывает запрос на снятие ограничения для типа товара
+func (h *ProductLimitHandler) DeleteLimit(c *gin.Context) {
+	err := h.productLimitQueries.DeleteLimit(c.Request.Context(), c.Param("type"))
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgProductLimitNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgDeleteProductLimitFailed, err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/storage"
+)
+
+// productLimits - ограничения по типам товаров, текущее количество товаров каждого типа в приёмке
+// и заполненность ПВЗ. Пустое значение означает, что ограничений нет
+type productLimits struct {
+	queries.ProductLimitQueriesInterface
+	limits    map[string]int
+	counts    map[string]int
+	occupancy *models.PVZOccupancy
+}
+
+func (p productLimits) GetLimit(ctx context.Context, productType string) (*models.ProductTypeLimit, error) {
+	limit, ok := p.limits[productType]
+	if !ok {
+		return nil, fmt.Errorf("product type limit %s: %w", productType, queries.ErrNotFound)
+	}
+	return &models.ProductTypeLimit{Type: productType, MaxPerReception: limit}, nil
+}
+
+func (p productLimits) CountReceptionProducts(ctx context.Context, receptionID, productType string) (int, error) {
+	return p.counts[productType], nil
+}
+
+func (p productLimits) GetPVZOccupancy(ctx context.Context, pvzID string) (*models.PVZOccupancy, error) {
+	if p.occupancy == nil {
+		return &models.PVZOccupancy{PvzID: pvzID}, nil
+	}
+	return p.occupancy, nil
+}
+
+// TestAddProductTypeLimit проверяет отказ в добавлении товара сверх ограничения для его типа
+func TestAddProductTypeLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	productQueries := new(MockProductQueries)
+	receptionQueries := new(MockReceptionQueries)
+	limits := productLimits{
+		limits: map[string]int{models.ProductTypeElectronics: 10},
+		counts: map[string]int{models.ProductTypeElectronics: 10, models.ProductTypeClothes: 50},
+	}
+	productHandler := newTestProductHandler(productQueries, receptionQueries, &recordingOutbox{}, storage.Disabled{}, limits, alwaysOpen{}, noQuotas{}, noLocks{})
+	r.POST("/products", func(c *gin.Context) {
+		c.Set("userRole", "employee")
+		productHandler.AddProduct(c)
+	})
+
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	receptionQueries.On("GetLastOpenReception", mock.Anything, pvzID).
+		Return(&models.Reception{ID: "reception-uuid", DateTime: time.Now(), PvzID: pvzID, Status: "in_progress"}, nil)
+	receptionQueries.On("LockReceptions", mock.Anything, mock.Anything).Return([]models.Reception{{Status: "in_progress"}}, nil)
+	productQueries.On("AddProduct", mock.Anything, "reception-uuid", mock.Anything, models.ProductTypeClothes, "").
+		Return(&models.Product{ID: "product-uuid", Type: models.ProductTypeClothes, ReceptionID: "reception-uuid"}, nil)
+
+	send := func(productType string) *httptest.ResponseRecorder {
+		jsonData, _ := json.Marshal(models.CreateProductRequest{Type: productType, PvzID: pvzID})
+		req, _ := http.NewRequest("POST", "/products", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Тип с исчерпанным ограничением", func(t *testing.T) {
+		w := send(models.ProductTypeElectronics)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{
+			"message": "Превышено ограничение на количество товаров этого типа в приёмке",
+			"details": {"type": "электроника", "limit": 10, "current": 10}
+		}`, w.Body.String())
+		productQueries.AssertNotCalled(t, "AddProduct", mock.Anything, mock.Anything, mock.Anything, models.ProductTypeElectronics, mock.Anything)
+	})
+
+	t.Run("Тип без ограничения", func(t *testing.T) {
+		w := send(models.ProductTypeClothes)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+}
+
+// MockProductLimitQueries мокирует запросы для управления ограничениями количества товаров
+type MockProductLimitQueries struct {
+	productLimits
+	mock.Mock
+}
+
+func (m *MockProductLimitQueries) UpsertLimit(ctx context.Context, limit models.ProductTypeLimit) (*models.ProductTypeLimit, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProductTypeLimit), args.Error(1)
+}
+
+func (m *MockProductLimitQueries) DeleteLimit(ctx context.Context, productType string) error {
+	args := m.Called(ctx, productType)
+	return args.Error(0)
+}
+
+// TestSetProductLimit проверяет установку ограничения и отклонение неизвестного типа товара
+func TestSetProductLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	productLimitQueries := new(MockProductLimitQueries)
+	productLimitHandler := NewProductLimitHandler(productLimitQueries)
+	r.PUT("/admin/product-limits/:type", productLimitHandler.SetLimit)
+	r.DELETE("/admin/product-limits/:type", productLimitHandler.DeleteLimit)
+
+	productLimitQueries.On("UpsertLimit", mock.Anything, models.ProductTypeLimit{Type: models.ProductTypeElectronics, MaxPerReception: 10, Comment: "страховка"}).
+		Return(&models.ProductTypeLimit{Type: models.ProductTypeElectronics, MaxPerReception: 10, Comment: "страховка"}, nil)
+	productLimitQueries.On("DeleteLimit", mock.Anything, models.ProductTypeShoes).
+		Return(fmt.Errorf("product type limit %s: %w", models.ProductTypeShoes, queries.ErrNotFound))
+
+	body := []byte(`{"maxPerReception": 10, "comment": "страховка"}`)
+
+	req, _ := http.NewRequest("PUT", "/admin/product-limits/электроника", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest("PUT", "/admin/product-limits/мебель", bytes.NewBuffer(body))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	req, _ = http.NewRequest("DELETE", "/admin/product-limits/обувь", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	productLimitQueries.AssertExpectations(t)
+}
+
+// TestAddProductPVZCapacity проверяет отказ в приёмке сверх вместимости ПВЗ и приём с подтверждением
+func TestAddProductPVZCapacity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	productQueries := new(MockProductQueries)
+	receptionQueries := new(MockReceptionQueries)
+	capacity := 100
+	limits := productLimits{occupancy: &models.PVZOccupancy{PvzID: testPvzID, Capacity: &capacity, Occupancy: 101}}
+	productHandler := newTestProductHandler(productQueries, receptionQueries, &recordingOutbox{}, storage.Disabled{}, limits, alwaysOpen{}, noQuotas{}, noLocks{})
+	r.POST("/products", func(c *gin.Context) {
+		c.Set("userRole", "employee")
+		productHandler.AddProduct(c)
+	})
+
+	receptionQueries.On("GetLastOpenReception", mock.Anything, testPvzID).
+		Return(&models.Reception{ID: testReceptionID, DateTime: time.Now(), PvzID: testPvzID, Status: "in_progress"}, nil)
+	receptionQueries.On("LockReceptions", mock.Anything, mock.Anything).Return([]models.Reception{{Status: "in_progress"}}, nil)
+	productQueries.On("AddProduct", mock.Anything, testReceptionID, mock.Anything, models.ProductTypeClothes, "").
+		Return(&models.Product{ID: testProductID, Type: models.ProductTypeClothes, ReceptionID: testReceptionID}, nil)
+
+	send := func(override bool) *httptest.ResponseRecorder {
+		jsonData, _ := json.Marshal(models.CreateProductRequest{Type: models.ProductTypeClothes, PvzID: testPvzID, OverrideCapacity: override})
+		req, _ := http.NewRequest("POST", "/products", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("ПВЗ заполнен", func(t *testing.T) {
+		w := send(false)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		var body struct {
+			Details models.PVZOccupancy `json:"details"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, 101, body.Details.Occupancy)
+	})
+
+	t.Run("Приём сверх вместимости с подтверждением", func(t *testing.T) {
+		w := send(true)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response models.ProductResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.True(t, response.OverCapacity)
+	})
+}
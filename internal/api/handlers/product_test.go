@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -14,7 +15,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"pvz-service/internal/db/queries"
 	"pvz-service/internal/models"
+	"pvz-service/internal/service"
+	"pvz-service/internal/storage"
 )
 
 // MockProductQueries мокирует запросы для работы с товарами
@@ -22,8 +26,8 @@ type MockProductQueries struct {
 	mock.Mock
 }
 
-func (m *MockProductQueries) AddProduct(ctx context.Context, receptionID, productType string) (*models.Product, error) {
-	args := m.Called(ctx, receptionID, productType)
+func (m *MockProductQueries) AddProduct(ctx context.Context, receptionID, userID, productType, barcode string) (*models.Product, error) {
+	args := m.Called(ctx, receptionID, userID, productType, barcode)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -38,6 +42,14 @@ func (m *MockProductQueries) GetLastProductFromReception(ctx context.Context, re
 	return args.Get(0).(*models.Product), args.Error(1)
 }
 
+func (m *MockProductQueries) GetLastProductsFromReception(ctx context.Context, receptionID string, limit int) ([]models.Product, error) {
+	args := m.Called(ctx, receptionID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Product), args.Error(1)
+}
+
 func (m *MockProductQueries) GetProductsByReception(ctx context.Context, receptionID string) ([]models.Product, error) {
 	args := m.Called(ctx, receptionID)
 	if args.Get(0) == nil {
@@ -46,11 +58,104 @@ func (m *MockProductQueries) GetProductsByReception(ctx context.Context, recepti
 	return args.Get(0).([]models.Product), args.Error(1)
 }
 
-func (m *MockProductQueries) DeleteProduct(ctx context.Context, productID string) error {
-	args := m.Called(ctx, productID)
+func (m *MockProductQueries) DeleteProduct(ctx context.Context, productID, userID string) error {
+	args := m.Called(ctx, productID, userID)
 	return args.Error(0)
 }
 
+func (m *MockProductQueries) MoveProducts(ctx context.Context, fromReceptionID, toReceptionID string) (int, error) {
+	args := m.Called(ctx, fromReceptionID, toReceptionID)
+	return args.Int(0), args.Error(1)
+}
+
+// StreamProductsByReception передает в fn товары, заданные в моке, не больше limit
+func (m *MockProductQueries) StreamProductsByReception(ctx context.Context, receptionID string, limit, offset int, fn func(models.Product) error) error {
+	args := m.Called(ctx, receptionID, limit, offset)
+	if products, ok := args.Get(0).([]models.Product); ok {
+		for i, product := range products {
+			if i == limit {
+				break
+			}
+			if err := fn(product); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockProductQueries) GetProductStates(ctx context.Context, productIDs []string) ([]models.ProductState, error) {
+	args := m.Called(ctx, productIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ProductState), args.Error(1)
+}
+
+func (m *MockProductQueries) AddProductWithPhotos(ctx context.Context, receptionID, userID, productType, barcode string, photos []models.ProductPhoto) (*models.Product, error) {
+	args := m.Called(ctx, receptionID, userID, productType, barcode, photos)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockProductQueries) GetPhotosByReception(ctx context.Context, receptionID string) ([]models.ProductPhoto, error) {
+	args := m.Called(ctx, receptionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ProductPhoto), args.Error(1)
+}
+
+func (m *MockProductQueries) GetProduct(ctx context.Context, productID string) (*models.Product, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockProductQueries) GetInventory(ctx context.Context, pvzID string) ([]models.Product, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Product), args.Error(1)
+}
+
+func (m *MockProductQueries) IssueProduct(ctx context.Context, productID string) (*models.Product, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockProductQueries) UpdateProductType(ctx context.Context, productID, authorID, productType string) (*models.ProductTypeChange, error) {
+	args := m.Called(ctx, productID, authorID, productType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProductTypeChange), args.Error(1)
+}
+
+func (m *MockProductQueries) MarkProductDamaged(ctx context.Context, productID, userID, description string, photos []models.ProductPhoto) (*models.ProductDamage, error) {
+	args := m.Called(ctx, productID, userID, description, photos)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProductDamage), args.Error(1)
+}
+
+func (m *MockProductQueries) GetReceptionDamages(ctx context.Context, receptionID string) ([]models.ProductDamage, error) {
+	args := m.Called(ctx, receptionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ProductDamage), args.Error(1)
+}
+
 // MockReceptionQueries мокирует запросы для работы с приёмками
 type MockReceptionQueries struct {
 	mock.Mock
@@ -64,6 +169,36 @@ func (m *MockReceptionQueries) GetLastOpenReception(ctx context.Context, pvzID s
 	return args.Get(0).(*models.Reception), args.Error(1)
 }
 
+func (m *MockReceptionQueries) GetLastPausedReception(ctx context.Context, pvzID string) (*models.Reception, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Reception), args.Error(1)
+}
+
+func (m *MockReceptionQueries) PauseReception(ctx context.Context, pvzID string) (*models.Reception, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Reception), args.Error(1)
+}
+
+func (m *MockReceptionQueries) ResumeReception(ctx context.Context, pvzID string) (*models.Reception, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Reception), args.Error(1)
+}
+
+// newTestProductHandler создает обработчик товаров с сервисом товаров на тех же зависимостях, как контейнер приложения
+func newTestProductHandler(productQueries queries.ProductQueriesInterface, receptionQueries queries.ReceptionQueriesInterface, outbox queries.OutboxQueriesInterface, store storage.Storage, limits queries.ProductLimitQueriesInterface, hours service.WorkingHoursChecker, quotas service.QuotaChecker, locks service.Locker) *ProductHandler {
+	productService := service.NewProductService(productQueries, receptionQueries, passthroughTx{}, outbox, store, limits, hours, quotas, locks)
+	return NewProductHandler(productQueries, receptionQueries, passthroughTx{}, outbox, store, productService)
+}
+
 // Настройка тестового окружения
 func setupProductTest() (*gin.Engine, *MockProductQueries, *MockReceptionQueries) {
 	gin.SetMode(gin.TestMode)
@@ -72,7 +207,7 @@ func setupProductTest() (*gin.Engine, *MockProductQueries, *MockReceptionQueries
 	productQueries := new(MockProductQueries)
 	receptionQueries := new(MockReceptionQueries)
 
-	productHandler := NewProductHandler(productQueries, receptionQueries)
+	productHandler := newTestProductHandler(productQueries, receptionQueries, &recordingOutbox{}, storage.Disabled{}, productLimits{}, alwaysOpen{}, noQuotas{}, noLocks{})
 
 	// Создаем группу маршрутов с middleware для установки роли пользователя
 	authorized := r.Group("/")
@@ -83,6 +218,8 @@ func setupProductTest() (*gin.Engine, *MockProductQueries, *MockReceptionQueries
 
 	authorized.POST("/products", productHandler.AddProduct)
 	authorized.POST("/pvz/:pvzId/delete_last_product", productHandler.DeleteLastProduct)
+	authorized.POST("/products/:productId/issue", productHandler.IssueProduct)
+	authorized.GET("/pvz/:pvzId/inventory", productHandler.GetInventory)
 
 	return r, productQueries, receptionQueries
 }
@@ -108,7 +245,8 @@ func TestAddProductSuccess(t *testing.T) {
 
 	// Настраиваем моки
 	receptionQueries.On("GetLastOpenReception", mock.Anything, "123e4567-e89b-12d3-a456-426614174000").Return(testReception, nil)
-	productQueries.On("AddProduct", mock.Anything, "reception-uuid", "электроника").Return(testProduct, nil)
+	receptionQueries.On("LockReceptions", mock.Anything, mock.Anything).Return([]models.Reception{{Status: "in_progress"}}, nil)
+	productQueries.On("AddProduct", mock.Anything, "reception-uuid", mock.Anything, "электроника", "").Return(testProduct, nil)
 
 	// Создаем запрос
 	reqBody := models.CreateProductRequest{
@@ -175,7 +313,7 @@ func TestAddProductForbidden(t *testing.T) {
 	})
 
 	// Регистрируем обработчик
-	productHandler := NewProductHandler(new(MockProductQueries), new(MockReceptionQueries))
+	productHandler := newTestProductHandler(new(MockProductQueries), new(MockReceptionQueries), &recordingOutbox{}, storage.Disabled{}, productLimits{}, alwaysOpen{}, noQuotas{}, noLocks{})
 	moderatorRouter.POST("/products", productHandler.AddProduct)
 
 	// Создаем запрос
@@ -207,6 +345,8 @@ func TestAddProductNoOpenReception(t *testing.T) {
 	// Настраиваем моки - нет открытой приёмки
 	receptionQueries.On("GetLastOpenReception", mock.Anything, "123e4567-e89b-12d3-a456-426614174000").
 		Return(nil, errors.New("no open reception found"))
+	receptionQueries.On("GetLastPausedReception", mock.Anything, "123e4567-e89b-12d3-a456-426614174000").
+		Return(nil, queries.ErrNotFound)
 
 	// Создаем запрос
 	reqBody := models.CreateProductRequest{
@@ -233,6 +373,26 @@ func TestAddProductNoOpenReception(t *testing.T) {
 	receptionQueries.AssertExpectations(t)
 }
 
+// TestAddProductPausedReception проверяет отказ в добавлении товара, пока приёмка ПВЗ на паузе
+func TestAddProductPausedReception(t *testing.T) {
+	r, productQueries, receptionQueries := setupProductTest()
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+
+	receptionQueries.On("GetLastOpenReception", mock.Anything, pvzID).Return(nil, errors.New("no open reception found"))
+	receptionQueries.On("GetLastPausedReception", mock.Anything, pvzID).
+		Return(&models.Reception{ID: "reception-uuid", PvzID: pvzID, Status: "paused"}, nil)
+
+	jsonData, _ := json.Marshal(models.CreateProductRequest{Type: "электроника", PvzID: pvzID})
+	req, _ := http.NewRequest("POST", "/products", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "Приёмка приостановлена")
+	productQueries.AssertNotCalled(t, "AddProduct", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 // TestAddProductClosedReception проверяет случай с закрытой приёмкой
 func TestAddProductClosedReception(t *testing.T) {
 	r, _, receptionQueries := setupProductTest()
@@ -287,7 +447,8 @@ func TestAddProductError(t *testing.T) {
 
 	// Настраиваем моки
 	receptionQueries.On("GetLastOpenReception", mock.Anything, "123e4567-e89b-12d3-a456-426614174000").Return(testReception, nil)
-	productQueries.On("AddProduct", mock.Anything, "reception-uuid", "электроника").
+	receptionQueries.On("LockReceptions", mock.Anything, mock.Anything).Return([]models.Reception{{Status: "in_progress"}}, nil)
+	productQueries.On("AddProduct", mock.Anything, "reception-uuid", mock.Anything, "электроника", "").
 		Return(nil, errors.New("database error"))
 
 	// Создаем запрос
@@ -343,7 +504,7 @@ func TestDeleteLastProductSuccess(t *testing.T) {
 	// Настраиваем моки
 	receptionQueries.On("GetLastOpenReception", mock.Anything, pvzID).Return(testReception, nil)
 	productQueries.On("GetLastProductFromReception", mock.Anything, receptionID).Return(testProduct, nil)
-	productQueries.On("DeleteProduct", mock.Anything, productID).Return(nil)
+	productQueries.On("DeleteProduct", mock.Anything, productID, mock.Anything).Return(nil)
 
 	// Создаем запрос
 	req, _ := http.NewRequest("POST", "/pvz/"+pvzID+"/delete_last_product", nil)
@@ -373,7 +534,7 @@ func TestDeleteLastProductForbidden(t *testing.T) {
 	productQueries := new(MockProductQueries)
 	receptionQueries := new(MockReceptionQueries)
 
-	productHandler := NewProductHandler(productQueries, receptionQueries)
+	productHandler := newTestProductHandler(productQueries, receptionQueries, &recordingOutbox{}, storage.Disabled{}, productLimits{}, alwaysOpen{}, noQuotas{}, noLocks{})
 
 	// Настраиваем middleware для установки роли модератора
 	r.POST("/pvz/:pvzId/delete_last_product", func(c *gin.Context) {
@@ -410,6 +571,7 @@ func TestDeleteLastProductNoOpenReception(t *testing.T) {
 	// Настраиваем моки - нет открытой приёмки
 	receptionQueries.On("GetLastOpenReception", mock.Anything, pvzID).
 		Return(nil, errors.New("no open reception found"))
+	receptionQueries.On("GetLastPausedReception", mock.Anything, pvzID).Return(nil, queries.ErrNotFound)
 
 	// Создаем запрос
 	req, _ := http.NewRequest("POST", "/pvz/"+pvzID+"/delete_last_product", nil)
@@ -536,7 +698,7 @@ func TestDeleteLastProductError(t *testing.T) {
 	// Настраиваем моки
 	receptionQueries.On("GetLastOpenReception", mock.Anything, pvzID).Return(testReception, nil)
 	productQueries.On("GetLastProductFromReception", mock.Anything, receptionID).Return(testProduct, nil)
-	productQueries.On("DeleteProduct", mock.Anything, productID).Return(errors.New("database error"))
+	productQueries.On("DeleteProduct", mock.Anything, productID, mock.Anything).Return(errors.New("database error"))
 
 	// Создаем запрос
 	req, _ := http.NewRequest("POST", "/pvz/"+pvzID+"/delete_last_product", nil)
@@ -568,7 +730,7 @@ func TestDeleteLastProductEmptyPvzID(t *testing.T) {
 	productQueries := new(MockProductQueries)
 	receptionQueries := new(MockReceptionQueries)
 
-	productHandler := NewProductHandler(productQueries, receptionQueries)
+	productHandler := newTestProductHandler(productQueries, receptionQueries, &recordingOutbox{}, storage.Disabled{}, productLimits{}, alwaysOpen{}, noQuotas{}, noLocks{})
 
 	// Настраиваем маршрут с пустым параметром pvzId
 	r.POST("/pvz//delete_last_product", func(c *gin.Context) {
@@ -591,3 +753,402 @@ func TestDeleteLastProductEmptyPvzID(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "Не указан ID ПВЗ", response.Message)
 }
+
+// TestGetStatusBatchSuccess проверяет пакетное получение статусов товаров
+func TestGetStatusBatchSuccess(t *testing.T) {
+	r, productQueries, _ := setupProductTest()
+	r.POST("/products/status_batch", newTestProductHandler(productQueries, new(MockReceptionQueries), &recordingOutbox{}, storage.Disabled{}, productLimits{}, alwaysOpen{}, noQuotas{}, noLocks{}).GetStatusBatch)
+
+	ids := []string{
+		"123e4567-e89b-12d3-a456-426614174001",
+		"123e4567-e89b-12d3-a456-426614174002",
+		"123e4567-e89b-12d3-a456-426614174003",
+	}
+	productQueries.On("GetProductStates", mock.Anything, ids).Return([]models.ProductState{
+		{ID: ids[2], Version: 1, ReceptionID: "r1", ReceptionStatus: "in_progress", PvzID: "pvz1"},
+		{ID: ids[0], Version: 3, ReceptionID: "r0", ReceptionStatus: "close", PvzID: "pvz1"},
+	}, nil)
+
+	jsonData, _ := json.Marshal(models.ProductStatusBatchRequest{ProductIDs: ids})
+	req, _ := http.NewRequest("POST", "/products/status_batch", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []models.ProductStatusResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ProductStatusResponse{
+		{ID: ids[0], Status: models.ProductStatusReceived, Version: 3, ReceptionID: "r0", PvzID: "pvz1"},
+		{ID: ids[1], Status: models.ProductStatusNotFound},
+		{ID: ids[2], Status: models.ProductStatusInReception, Version: 1, ReceptionID: "r1", PvzID: "pvz1"},
+	}, response)
+
+	productQueries.AssertExpectations(t)
+}
+
+// TestGetStatusBatchTooMany проверяет ограничение размера пакета
+func TestGetStatusBatchTooMany(t *testing.T) {
+	r, productQueries, _ := setupProductTest()
+	r.POST("/products/status_batch", newTestProductHandler(productQueries, new(MockReceptionQueries), &recordingOutbox{}, storage.Disabled{}, productLimits{}, alwaysOpen{}, noQuotas{}, noLocks{}).GetStatusBatch)
+
+	ids := make([]string, models.MaxStatusBatchSize+1)
+	for i := range ids {
+		ids[i] = "123e4567-e89b-12d3-a456-426614174001"
+	}
+
+	jsonData, _ := json.Marshal(models.ProductStatusBatchRequest{ProductIDs: ids})
+	req, _ := http.NewRequest("POST", "/products/status_batch", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	productQueries.AssertNotCalled(t, "GetProductStates")
+}
+
+// TestGetInventorySuccess проверяет получение товаров, находящихся на хранении в ПВЗ
+func TestGetInventorySuccess(t *testing.T) {
+	r, productQueries, _ := setupProductTest()
+
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	barcode := "4600000000001"
+	productQueries.On("GetInventory", mock.Anything, pvzID).Return([]models.Product{
+		{ID: "p1", Type: "обувь", ReceptionID: "r1", Barcode: &barcode},
+		{ID: "p2", Type: "одежда", ReceptionID: "r2"},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/pvz/"+pvzID+"/inventory", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.InventoryResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, pvzID, response.PvzID)
+	assert.Equal(t, 2, response.Total)
+	assert.Equal(t, "p1", response.Products[0].ID)
+	assert.Equal(t, &barcode, response.Products[0].Barcode)
+
+	productQueries.AssertExpectations(t)
+}
+
+// setupProductListTest создает маршрут списка товаров приёмки с ограничением maxRows
+func setupProductListTest(maxRows int) (*gin.Engine, *MockProductQueries, *MockReceptionQueries) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	productQueries := new(MockProductQueries)
+	receptionQueries := new(MockReceptionQueries)
+	productHandler := newTestProductHandler(productQueries, receptionQueries, &recordingOutbox{}, storage.Disabled{}, productLimits{}, alwaysOpen{}, noQuotas{}, noLocks{})
+	productHandler.maxListRows = maxRows
+	r.GET("/products", productHandler.ListProducts)
+	return r, productQueries, receptionQueries
+}
+
+// TestListProducts проверяет потоковую выдачу товаров приёмки с ограничением и выгрузкой файлом
+func TestListProducts(t *testing.T) {
+	r, productQueries, receptionQueries := setupProductListTest(2)
+
+	receptionQueries.On("GetReceptionByID", mock.Anything, testReceptionID).
+		Return(&models.Reception{ID: testReceptionID, ProductCount: 3}, nil)
+	productQueries.On("StreamProductsByReception", mock.Anything, testReceptionID, 2, 0).Return([]models.Product{
+		{ID: "p1", Type: "обувь", ReceptionID: testReceptionID},
+		{ID: "p2", Type: "одежда", ReceptionID: testReceptionID},
+		{ID: "p3", Type: "электроника", ReceptionID: testReceptionID},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/products?receptionId="+testReceptionID+"&download=true", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "3", w.Header().Get("X-Total-Count"))
+	assert.Equal(t, "2", w.Header().Get("X-Row-Limit"))
+	assert.Equal(t, []string{`</products?download=true&limit=2&page=2&receptionId=` + testReceptionID + `>; rel="next"`}, w.Header().Values("Link"))
+	assert.Equal(t, `attachment; filename="products-`+testReceptionID+`.json"`, w.Header().Get("Content-Disposition"))
+
+	var products []models.ProductResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &products))
+	assert.Len(t, products, 2)
+	assert.Equal(t, "p2", products[1].ID)
+}
+
+// TestListProductsPage проверяет выдачу второй страницы товаров с ограничением из запроса
+func TestListProductsPage(t *testing.T) {
+	r, productQueries, receptionQueries := setupProductListTest(maxProductListRows)
+
+	receptionQueries.On("GetReceptionByID", mock.Anything, testReceptionID).
+		Return(&models.Reception{ID: testReceptionID, ProductCount: 3}, nil)
+	productQueries.On("StreamProductsByReception", mock.Anything, testReceptionID, 2, 2).Return([]models.Product{
+		{ID: "p3", Type: "электроника", ReceptionID: testReceptionID},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/products?receptionId="+testReceptionID+"&page=2&limit=2", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "3", w.Header().Get("X-Total-Count"))
+	assert.Equal(t, []string{`</products?limit=2&page=1&receptionId=` + testReceptionID + `>; rel="prev"`}, w.Header().Values("Link"))
+
+	var products []models.ProductResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &products))
+	assert.Len(t, products, 1)
+	assert.Equal(t, "p3", products[0].ID)
+}
+
+// TestListProductsEmpty проверяет пустой список товаров без заголовка выгрузки файлом
+func TestListProductsEmpty(t *testing.T) {
+	r, productQueries, receptionQueries := setupProductListTest(maxProductListRows)
+
+	receptionQueries.On("GetReceptionByID", mock.Anything, testReceptionID).
+		Return(&models.Reception{ID: testReceptionID}, nil)
+	productQueries.On("StreamProductsByReception", mock.Anything, testReceptionID, maxProductListRows, 0).Return(nil, nil)
+
+	req, _ := http.NewRequest("GET", "/products?receptionId="+testReceptionID, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[]`, w.Body.String())
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+}
+
+// TestListProductsErrors проверяет ошибки до начала ответа
+func TestListProductsErrors(t *testing.T) {
+	otherReceptionID := "223e4567-e89b-12d3-a456-426614174999"
+	r, productQueries, receptionQueries := setupProductListTest(maxProductListRows)
+
+	receptionQueries.On("GetReceptionByID", mock.Anything, otherReceptionID).
+		Return(nil, fmt.Errorf("reception %s: %w", otherReceptionID, queries.ErrNotFound))
+	receptionQueries.On("GetReceptionByID", mock.Anything, testReceptionID).
+		Return(&models.Reception{ID: testReceptionID}, nil)
+	productQueries.On("StreamProductsByReception", mock.Anything, testReceptionID, maxProductListRows, 0).
+		Return(nil, errors.New("database error"))
+
+	for _, tt := range []struct {
+		query string
+		code  int
+	}{
+		{"", http.StatusBadRequest},
+		{"?receptionId=abc", http.StatusBadRequest},
+		{"?receptionId=" + testReceptionID + "&page=0", http.StatusBadRequest},
+		{"?receptionId=" + otherReceptionID, http.StatusNotFound},
+		{"?receptionId=" + testReceptionID, http.StatusInternalServerError},
+	} {
+		req, _ := http.NewRequest("GET", "/products"+tt.query, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, tt.code, w.Code, tt.query)
+	}
+}
+
+// TestIssueProductSuccess проверяет выдачу товара и запись события о выдаче
+func TestIssueProductSuccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	productQueries := new(MockProductQueries)
+	outbox := &recordingOutbox{}
+	productHandler := newTestProductHandler(productQueries, new(MockReceptionQueries), outbox, storage.Disabled{}, productLimits{}, alwaysOpen{}, noQuotas{}, noLocks{})
+	r.POST("/products/:productId/issue", func(c *gin.Context) {
+		c.Set("userRole", "employee")
+		productHandler.IssueProduct(c)
+	})
+
+	productID := "123e4567-e89b-12d3-a456-426614174001"
+	productQueries.On("GetProductStates", mock.Anything, []string{productID}).Return([]models.ProductState{
+		{ID: productID, Version: 2, ReceptionID: "r1", ReceptionStatus: "close", PvzID: "pvz1", Status: models.ProductLifecycleStored},
+	}, nil)
+	productQueries.On("IssueProduct", mock.Anything, productID).
+		Return(&models.Product{ID: productID, Type: "обувь", ReceptionID: "r1"}, nil)
+
+	req, _ := http.NewRequest("POST", "/products/"+productID+"/issue", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, outbox.events, 1)
+	assert.Equal(t, models.EventProductIssued, outbox.events[0].Type)
+	assert.Equal(t, "pvz1", outbox.events[0].PvzID)
+
+	productQueries.AssertExpectations(t)
+}
+
+// TestIssueProductNotStored проверяет, что нельзя выдать товар из открытой приёмки или уже выданный
+func TestIssueProductNotStored(t *testing.T) {
+	r, productQueries, _ := setupProductTest()
+
+	productID := "123e4567-e89b-12d3-a456-426614174001"
+	productQueries.On("GetProductStates", mock.Anything, []string{productID}).Return([]models.ProductState{
+		{ID: productID, ReceptionID: "r1", ReceptionStatus: "in_progress", PvzID: "pvz1", Status: models.ProductLifecycleReceived},
+	}, nil)
+
+	req, _ := http.NewRequest("POST", "/products/"+productID+"/issue", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	productQueries.AssertNotCalled(t, "IssueProduct", mock.Anything, mock.Anything)
+}
+
+// TestIssueProductNotFound проверяет выдачу несуществующего товара
+func TestIssueProductNotFound(t *testing.T) {
+	r, productQueries, _ := setupProductTest()
+
+	productID := "123e4567-e89b-12d3-a456-426614174001"
+	productQueries.On("GetProductStates", mock.Anything, []string{productID}).Return([]models.ProductState{}, nil)
+
+	req, _ := http.NewRequest("POST", "/products/"+productID+"/issue", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// TestIssueProductForbidden проверяет, что модератор не может выдавать товары
+func TestIssueProductForbidden(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	productQueries := new(MockProductQueries)
+	productHandler := newTestProductHandler(productQueries, new(MockReceptionQueries), &recordingOutbox{}, storage.Disabled{}, productLimits{}, alwaysOpen{}, noQuotas{}, noLocks{})
+	r.POST("/products/:productId/issue", func(c *gin.Context) {
+		c.Set("userRole", "moderator")
+		productHandler.IssueProduct(c)
+	})
+
+	req, _ := http.NewRequest("POST", "/products/some-id/issue", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	productQueries.AssertNotCalled(t, "GetProductStates", mock.Anything, mock.Anything)
+}
+
+// setupDeleteProductTest создает роутер удаления товара по ID с заданной ролью
+func setupDeleteProductTest(role string) (*gin.Engine, *MockProductQueries, *MockReceptionQueries, *recordingOutbox) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	productQueries := new(MockProductQueries)
+	receptionQueries := new(MockReceptionQueries)
+	outbox := &recordingOutbox{}
+	productHandler := newTestProductHandler(productQueries, receptionQueries, outbox, storage.Disabled{}, productLimits{}, alwaysOpen{}, noQuotas{}, noLocks{})
+
+	r.DELETE("/products/:productId", func(c *gin.Context) {
+		c.Set("userRole", role)
+		productHandler.DeleteProduct(c)
+	})
+
+	return r, productQueries, receptionQueries, outbox
+}
+
+// TestDeleteProductByModerator проверяет удаление модератором товара из середины приёмки
+func TestDeleteProductByModerator(t *testing.T) {
+	r, productQueries, receptionQueries, outbox := setupDeleteProductTest("moderator")
+
+	productID := "123e4567-e89b-12d3-a456-426614174002"
+	productQueries.On("GetProduct", mock.Anything, productID).
+		Return(&models.Product{ID: productID, Type: "обувь", ReceptionID: "r1"}, nil)
+	receptionQueries.On("GetReceptionByID", mock.Anything, "r1").
+		Return(&models.Reception{ID: "r1", PvzID: "pvz1", Status: "in_progress"}, nil)
+	receptionQueries.On("LockReceptions", mock.Anything, []string{"r1"}).
+		Return([]models.Reception{{ID: "r1", PvzID: "pvz1", Status: "in_progress"}}, nil)
+	productQueries.On("DeleteProduct", mock.Anything, productID, mock.Anything).Return(nil)
+
+	req, _ := http.NewRequest("DELETE", "/products/"+productID, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Len(t, outbox.events, 1)
+	assert.Equal(t, models.EventProductDeleted, outbox.events[0].Type)
+	assert.Equal(t, "pvz1", outbox.events[0].PvzID)
+	productQueries.AssertNotCalled(t, "GetLastProductFromReception", mock.Anything, mock.Anything)
+	productQueries.AssertExpectations(t)
+}
+
+// TestDeleteProductEmployeeNotLast проверяет, что сотрудник не может нарушить порядок LIFO
+func TestDeleteProductEmployeeNotLast(t *testing.T) {
+	r, productQueries, receptionQueries, outbox := setupDeleteProductTest("employee")
+
+	productID := "123e4567-e89b-12d3-a456-426614174002"
+	productQueries.On("GetProduct", mock.Anything, productID).
+		Return(&models.Product{ID: productID, ReceptionID: "r1"}, nil)
+	receptionQueries.On("GetReceptionByID", mock.Anything, "r1").
+		Return(&models.Reception{ID: "r1", PvzID: "pvz1", Status: "in_progress"}, nil)
+	receptionQueries.On("LockReceptions", mock.Anything, []string{"r1"}).
+		Return([]models.Reception{{ID: "r1", PvzID: "pvz1", Status: "in_progress"}}, nil)
+	productQueries.On("GetLastProductFromReception", mock.Anything, "r1").
+		Return(&models.Product{ID: "another-product", ReceptionID: "r1"}, nil)
+
+	req, _ := http.NewRequest("DELETE", "/products/"+productID, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Empty(t, outbox.events)
+	productQueries.AssertNotCalled(t, "DeleteProduct", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestDeleteProductEmployeeLast проверяет удаление сотрудником последнего товара по ID
+func TestDeleteProductEmployeeLast(t *testing.T) {
+	r, productQueries, receptionQueries, _ := setupDeleteProductTest("employee")
+
+	productID := "123e4567-e89b-12d3-a456-426614174002"
+	product := &models.Product{ID: productID, ReceptionID: "r1"}
+	productQueries.On("GetProduct", mock.Anything, productID).Return(product, nil)
+	receptionQueries.On("GetReceptionByID", mock.Anything, "r1").
+		Return(&models.Reception{ID: "r1", PvzID: "pvz1", Status: "in_progress"}, nil)
+	receptionQueries.On("LockReceptions", mock.Anything, []string{"r1"}).
+		Return([]models.Reception{{ID: "r1", PvzID: "pvz1", Status: "in_progress"}}, nil)
+	productQueries.On("GetLastProductFromReception", mock.Anything, "r1").Return(product, nil)
+	productQueries.On("DeleteProduct", mock.Anything, productID, mock.Anything).Return(nil)
+
+	req, _ := http.NewRequest("DELETE", "/products/"+productID, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	productQueries.AssertExpectations(t)
+}
+
+// TestDeleteProductClosedReception проверяет запрет удаления товара из закрытой приёмки
+func TestDeleteProductClosedReception(t *testing.T) {
+	r, productQueries, receptionQueries, _ := setupDeleteProductTest("moderator")
+
+	productID := "123e4567-e89b-12d3-a456-426614174002"
+	productQueries.On("GetProduct", mock.Anything, productID).
+		Return(&models.Product{ID: productID, ReceptionID: "r1"}, nil)
+	receptionQueries.On("GetReceptionByID", mock.Anything, "r1").
+		Return(&models.Reception{ID: "r1", PvzID: "pvz1", Status: "close"}, nil)
+	receptionQueries.On("LockReceptions", mock.Anything, []string{"r1"}).
+		Return([]models.Reception{{ID: "r1", PvzID: "pvz1", Status: "close"}}, nil)
+
+	req, _ := http.NewRequest("DELETE", "/products/"+productID, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	productQueries.AssertNotCalled(t, "DeleteProduct", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestDeleteProductNotFound проверяет удаление несуществующего товара
+func TestDeleteProductNotFound(t *testing.T) {
+	r, productQueries, _, _ := setupDeleteProductTest("moderator")
+
+	productID := "123e4567-e89b-12d3-a456-426614174002"
+	productQueries.On("GetProduct", mock.Anything, productID).
+		Return(nil, fmt.Errorf("product %s: %w", productID, queries.ErrNotFound))
+
+	req, _ := http.NewRequest("DELETE", "/products/"+productID, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testProductID = "423e4567-e89b-12d3-a456-426614174000"
+
+// setupProductUpdateTest создает маршрут исправления типа товара с ролью role и ограничениями limits
+func setupProductUpdateTest(role string, limits productLimits) (*gin.Engine, *MockProductQueries, *MockReceptionQueries, *recordingOutbox) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	productQueries := new(MockProductQueries)
+	receptionQueries := new(MockReceptionQueries)
+	outbox := &recordingOutbox{}
+	productHandler := newTestProductHandler(productQueries, receptionQueries, outbox, storage.Disabled{}, limits, alwaysOpen{}, noQuotas{}, noLocks{})
+	r.PATCH("/products/:productId", func(c *gin.Context) {
+		c.Set("userRole", role)
+		c.Set("userID", testEmployeeID)
+		productHandler.UpdateProduct(c)
+	})
+
+	return r, productQueries, receptionQueries, outbox
+}
+
+// patchProductType отправляет запрос исправления типа товара
+func patchProductType(r *gin.Engine, productType string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(models.UpdateProductRequest{Type: productType})
+	req, _ := http.NewRequest("PATCH", "/products/"+testProductID, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestUpdateProductType проверяет исправление типа товара с записью в историю и событием
+func TestUpdateProductType(t *testing.T) {
+	tests := []struct {
+		name   string
+		role   string
+		status string
+	}{
+		{"Сотрудник в открытой приёмке", models.RoleEmployee, "in_progress"},
+		{"Модератор в закрытой приёмке", models.RoleModerator, "close"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, productQueries, receptionQueries, outbox := setupProductUpdateTest(tt.role, productLimits{})
+
+			productQueries.On("GetProduct", mock.Anything, testProductID).
+				Return(&models.Product{ID: testProductID, Type: models.ProductTypeShoes, ReceptionID: testReceptionID}, nil)
+			receptionQueries.On("LockReceptions", mock.Anything, []string{testReceptionID}).
+				Return([]models.Reception{{ID: testReceptionID, PvzID: testPvzID, Status: tt.status}}, nil)
+			productQueries.On("UpdateProductType", mock.Anything, testProductID, testEmployeeID, models.ProductTypeClothes).
+				Return(&models.ProductTypeChange{ProductID: testProductID, OldType: models.ProductTypeShoes, NewType: models.ProductTypeClothes}, nil)
+
+			w := patchProductType(r, models.ProductTypeClothes)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			var result models.ProductTypeChangeResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+			assert.Equal(t, models.ProductTypeClothes, result.Type)
+			assert.Equal(t, models.ProductTypeShoes, result.PreviousType)
+			assert.Len(t, outbox.events, 1)
+			assert.Equal(t, models.EventProductRetyped, outbox.events[0].Type)
+			productQueries.AssertExpectations(t)
+		})
+	}
+}
+
+// TestUpdateProductTypeRejected проверяет отказ в исправлении типа без изменения товара
+func TestUpdateProductTypeRejected(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       string
+		status     string
+		limits     productLimits
+		wantStatus int
+	}{
+		{"Сотрудник в закрытой приёмке", models.RoleEmployee, "close", productLimits{}, http.StatusForbidden},
+		{"Модератор в открытой приёмке", models.RoleModerator, "in_progress", productLimits{}, http.StatusForbidden},
+		{
+			name:   "Превышено ограничение для нового типа",
+			role:   models.RoleEmployee,
+			status: "in_progress",
+			limits: productLimits{
+				limits: map[string]int{models.ProductTypeClothes: 1},
+				counts: map[string]int{models.ProductTypeClothes: 1},
+			},
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, productQueries, receptionQueries, outbox := setupProductUpdateTest(tt.role, tt.limits)
+
+			productQueries.On("GetProduct", mock.Anything, testProductID).
+				Return(&models.Product{ID: testProductID, Type: models.ProductTypeShoes, ReceptionID: testReceptionID}, nil)
+			receptionQueries.On("LockReceptions", mock.Anything, []string{testReceptionID}).
+				Return([]models.Reception{{ID: testReceptionID, PvzID: testPvzID, Status: tt.status}}, nil)
+
+			w := patchProductType(r, models.ProductTypeClothes)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Empty(t, outbox.events)
+			productQueries.AssertNotCalled(t, "UpdateProductType", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestUpdateProductTypeValidation проверяет отказ при неизвестном типе и отсутствующем товаре
+func TestUpdateProductTypeValidation(t *testing.T) {
+	r, productQueries, _, _ := setupProductUpdateTest(models.RoleEmployee, productLimits{})
+
+	w := patchProductType(r, "мебель")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	productQueries.On("GetProduct", mock.Anything, testProductID).Return(nil, fmt.Errorf("product %s: %w", testProductID, queries.ErrNotFound))
+	w = patchProductType(r, models.ProductTypeClothes)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
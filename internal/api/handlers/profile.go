@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+	"pvz-service/internal/otp"
+	"pvz-service/internal/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProfileHandler содержит обработчики профиля текущего пользователя
+type ProfileHandler struct {
+	authQueries queries.AuthQueriesInterface
+	storage     storage.Storage
+}
+
+// NewProfileHandler создает новый экземпляр ProfileHandler.
+// Аватары загружаются в хранилище вложений store
+func NewProfileHandler(authQueries queries.AuthQueriesInterface, store storage.Storage) *ProfileHandler {
+	return &ProfileHandler{
+		authQueries: authQueries,
+		storage:     store,
+	}
+}
+
+// GetProfile обрабатывает запрос на получение профиля текущего пользователя
+func (h *ProfileHandler) GetProfile(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgUserContextMissing))
+		return
+	}
+
+	profile, err := h.authQueries.GetProfile(c.Request.Context(), userID.(string))
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgUserNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetProfileFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, newProfileResponse(c.Request.Context(), h.storage, profile))
+}
+
+// UpdateProfile обрабатывает запрос на изменение профиля текущего пользователя.
+// Аватар загружается файлом avatar в multipart-запросе или задается ссылкой avatarUrl,
+// пустая avatarUrl удаляет аватар
+func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgUserContextMissing))
+		return
+	}
+
+	var req models.UpdateProfileRequest
+
+	var avatarFile *multipart.FileHeader
+	if c.ContentType() == "multipart/form-data" {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, models.MaxAvatarSize+1<<20)
+		form, err := c.MultipartForm()
+		if bodyTooLarge(err) {
+			response.Error(c, http.StatusRequestEntityTooLarge, i18n.T(c, i18n.MsgRequestTooLarge))
+			return
+		}
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+			return
+		}
+		if files := form.File["avatar"]; len(files) > 0 {
+			avatarFile = files[0]
+		}
+	}
+
+	// Проверяем запрос
+	if err := c.ShouldBind(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+	if avatarFile != nil && req.AvatarURL != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, errors.New("avatar and avatarUrl are mutually exclusive")))
+		return
+	}
+
+	if req.FirstName == nil && req.LastName == nil && req.Phone == nil && req.AvatarURL == nil && avatarFile == nil {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgProfileUpdateEmpty))
+		return
+	}
+
+	update := models.ProfileUpdate{}
+	if req.FirstName != nil {
+		firstName := strings.TrimSpace(*req.FirstName)
+		update.FirstName = &firstName
+	}
+	if req.LastName != nil {
+		lastName := strings.TrimSpace(*req.LastName)
+		update.LastName = &lastName
+	}
+	if req.AvatarURL != nil {
+		update.Avatar = &models.ProfileAvatar{}
+		if *req.AvatarURL != "" {
+			update.Avatar.URL = req.AvatarURL
+		}
+	}
+
+	// Телефон приводится к единому формату и не должен принадлежать другому пользователю
+	if req.Phone != nil {
+		phone, err := otp.NormalizePhone(*req.Phone)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgInvalidPhone))
+			return
+		}
+
+		owner, err := h.authQueries.GetUserByPhone(c.Request.Context(), phone)
+		if err == nil && owner.ID != userID.(string) {
+			response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgPhoneTaken))
+			return
+		}
+		if err != nil && !errors.Is(err, queries.ErrNotFound) {
+			response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCheckPhoneFailed, err))
+			return
+		}
+		update.Phone = &phone
+	}
+
+	// Загружаем аватар в хранилище до изменения профиля
+	if avatarFile != nil {
+		key, err := h.uploadAvatar(c.Request.Context(), userID.(string), avatarFile)
+		if errors.Is(err, errInvalidPhoto) {
+			response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidAvatar, err))
+			return
+		}
+		if errors.Is(err, storage.ErrNotConfigured) {
+			response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgAvatarStorageDisabled))
+			return
+		}
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgAvatarUploadFailed, err))
+			return
+		}
+		update.Avatar = &models.ProfileAvatar{StorageKey: &key}
+	}
+
+	profile, err := h.authQueries.UpdateProfile(c.Request.Context(), userID.(string), update)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgUserNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgUpdateProfileFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, newProfileResponse(c.Request.Context(), h.storage, profile))
+}
+
+// uploadAvatar проверяет размер и формат аватара и загружает его в хранилище под ключом пользователя
+func (h *ProfileHandler) uploadAvatar(ctx context.Context, userID string, fileHeader *multipart.FileHeader) (string, error) {
+	if fileHeader.Size > models.MaxAvatarSize {
+		return "", errInvalidPhoto
+	}
+	return uploadImage(ctx, h.storage, "avatars/"+userID+"/", fileHeader)
+}
+
+// newProfileResponse преобразует профиль в ответ API, подписывая ссылку на аватар из хранилища
+func newProfileResponse(ctx context.Context, store storage.Storage, profile *models.UserProfile) models.ProfileResponse {
+	result := models.ProfileResponse{
+		ID:        profile.ID,
+		Email:     profile.Email,
+		Role:      profile.Role,
+		FirstName: profile.FirstName,
+		LastName:  profile.LastName,
+	}
+	if profile.Phone != nil {
+		result.Phone = *profile.Phone
+	}
+
+	switch {
+	case profile.AvatarURL != nil:
+		result.AvatarURL = *profile.AvatarURL
+	case profile.AvatarKey != nil:
+		// Недоступная ссылка на аватар не должна ломать ответ
+		url, err := store.URL(ctx, *profile.AvatarKey)
+		if err != nil {
+			log.Printf("Failed to get avatar url for %s: %v", *profile.AvatarKey, err)
+			break
+		}
+		result.AvatarURL = url
+	}
+
+	return result
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/storage"
+)
+
+// setupProfileTest создает маршруты профиля для сотрудника testEmployeeID
+func setupProfileTest(store storage.Storage) (*gin.Engine, *MockAuthQueries) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	authQueries := new(MockAuthQueries)
+	profileHandler := NewProfileHandler(authQueries, store)
+	setUser := func(c *gin.Context) {
+		c.Set("userID", testEmployeeID)
+		c.Set("userRole", "employee")
+	}
+	r.GET("/me", setUser, profileHandler.GetProfile)
+	r.PATCH("/me", setUser, profileHandler.UpdateProfile)
+
+	return r, authQueries
+}
+
+// newAvatarRequest собирает multipart-запрос изменения профиля с файлом аватара
+func newAvatarRequest(data []byte) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	writer.WriteField("firstName", "Иван")
+	part, _ := writer.CreateFormFile("avatar", "avatar.bin")
+	part.Write(data)
+	writer.Close()
+
+	req, _ := http.NewRequest("PATCH", "/me", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+// TestGetProfile проверяет ответ с подписанной ссылкой на аватар из хранилища
+func TestGetProfile(t *testing.T) {
+	r, authQueries := setupProfileTest(&memoryStorage{objects: map[string]string{}})
+
+	key := "avatars/" + testEmployeeID + "/a.png"
+	phone := "+79990001122"
+	authQueries.On("GetProfile", mock.Anything, testEmployeeID).Return(&models.UserProfile{
+		ID: testEmployeeID, Role: "employee", Phone: &phone, FirstName: "Иван", LastName: "Петров", AvatarKey: &key,
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/me", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var profile models.ProfileResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &profile))
+	assert.Equal(t, "Петров", profile.LastName)
+	assert.Equal(t, phone, profile.Phone)
+	assert.Equal(t, "https://storage.example.com/"+key, profile.AvatarURL)
+}
+
+// TestGetProfileNotFound проверяет ответ для пользователя без записи в базе, например с тестовым токеном
+func TestGetProfileNotFound(t *testing.T) {
+	r, authQueries := setupProfileTest(storage.Disabled{})
+	authQueries.On("GetProfile", mock.Anything, testEmployeeID).Return(nil, queries.ErrNotFound)
+
+	req, _ := http.NewRequest("GET", "/me", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// TestUpdateProfile проверяет изменение имени и телефона в JSON-запросе
+func TestUpdateProfile(t *testing.T) {
+	r, authQueries := setupProfileTest(storage.Disabled{})
+
+	firstName, phone := "Иван", "+79990001122"
+	authQueries.On("GetUserByPhone", mock.Anything, phone).Return(nil, queries.ErrNotFound)
+	authQueries.On("UpdateProfile", mock.Anything, testEmployeeID, models.ProfileUpdate{FirstName: &firstName, Phone: &phone}).
+		Return(&models.UserProfile{ID: testEmployeeID, Role: "employee", FirstName: firstName, Phone: &phone}, nil)
+
+	w := patchJSON(r, "/me", `{"firstName": " Иван ", "phone": "8 (999) 000-11-22"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var profile models.ProfileResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &profile))
+	assert.Equal(t, firstName, profile.FirstName)
+	authQueries.AssertExpectations(t)
+}
+
+// TestUpdateProfileAvatarUpload проверяет загрузку аватара в хранилище
+func TestUpdateProfileAvatarUpload(t *testing.T) {
+	store := &memoryStorage{objects: map[string]string{}}
+	r, authQueries := setupProfileTest(store)
+
+	authQueries.On("UpdateProfile", mock.Anything, testEmployeeID, mock.MatchedBy(func(update models.ProfileUpdate) bool {
+		return update.Avatar != nil && update.Avatar.StorageKey != nil && update.Avatar.URL == nil &&
+			strings.HasPrefix(*update.Avatar.StorageKey, "avatars/"+testEmployeeID+"/") &&
+			strings.HasSuffix(*update.Avatar.StorageKey, ".png")
+	})).Return(&models.UserProfile{ID: testEmployeeID, Role: "employee", FirstName: "Иван"}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAvatarRequest(pngHeader))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, store.objects, 1)
+	authQueries.AssertExpectations(t)
+}
+
+// TestUpdateProfileErrors проверяет отказы без изменения профиля
+func TestUpdateProfileErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		store storage.Storage
+		req   func() *http.Request
+		code  int
+	}{
+		{"Пустой запрос", storage.Disabled{}, func() *http.Request { return newPatchRequest(`{}`) }, http.StatusBadRequest},
+		{"Неверный телефон", storage.Disabled{}, func() *http.Request { return newPatchRequest(`{"phone": "abc"}`) }, http.StatusBadRequest},
+		{"Телефон занят", storage.Disabled{}, func() *http.Request { return newPatchRequest(`{"phone": "+79990003344"}`) }, http.StatusBadRequest},
+		{"Неверная ссылка на аватар", storage.Disabled{}, func() *http.Request { return newPatchRequest(`{"avatarUrl": "not a url"}`) }, http.StatusBadRequest},
+		{"Неверный формат аватара", &memoryStorage{objects: map[string]string{}}, func() *http.Request { return newAvatarRequest([]byte("GIF89a")) }, http.StatusBadRequest},
+		{"Хранилище не настроено", storage.Disabled{}, func() *http.Request { return newAvatarRequest(pngHeader) }, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, authQueries := setupProfileTest(tt.store)
+			authQueries.On("GetUserByPhone", mock.Anything, "+79990003344").Return(&models.User{ID: "other-user"}, nil)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, tt.req())
+
+			assert.Equal(t, tt.code, w.Code)
+			authQueries.AssertNotCalled(t, "UpdateProfile", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func newPatchRequest(body string) *http.Request {
+	req, _ := http.NewRequest("PATCH", "/me", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+	"pvz-service/internal/publiclink"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PublicReceptionHandler отдает статус приёмки по публичной ссылке без авторизации
+type PublicReceptionHandler struct {
+	receptionQueries queries.ReceptionQueriesInterface
+	publicLinks      *publiclink.Signer
+}
+
+// NewPublicReceptionHandler создает новый экземпляр PublicReceptionHandler
+func NewPublicReceptionHandler(receptionQueries queries.ReceptionQueriesInterface, publicLinks *publiclink.Signer) *PublicReceptionHandler {
+	return &PublicReceptionHandler{
+		receptionQueries: receptionQueries,
+		publicLinks:      publicLinks,
+	}
+}
+
+// GetReceptionStatus обрабатывает запрос поставщика на статус приёмки по токену ссылки,
+// выданному при создании приёмки. Поддельный токен и неизвестная приёмка неотличимы для клиента
+func (h *PublicReceptionHandler) GetReceptionStatus(c *gin.Context) {
+	receptionID, err := h.publicLinks.Verify(c.Param("token"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgPublicLinkNotFound))
+		return
+	}
+
+	reception, err := h.receptionQueries.GetReceptionByID(c.Request.Context(), receptionID.String())
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgPublicLinkNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetReceptionFailed, err))
+		return
+	}
+
+	status := models.PublicReceptionOpen
+	if reception.Status == "close" {
+		status = models.PublicReceptionClosed
+	}
+
+	response.JSON(c, http.StatusOK, models.PublicReceptionStatus{
+		Status:       status,
+		DateTime:     reception.DateTime,
+		ProductCount: reception.ProductCount,
+	})
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pvz-service/internal/models"
+	"pvz-service/internal/publiclink"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPublicReceptionStatus проверяет статус приёмки по ссылке, выданной при ее создании
+func TestPublicReceptionStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	signer := publiclink.NewSigner("secret")
+	receptionQueries := new(MockReceptionQueries)
+	receptionHandler := NewReceptionHandler(receptionQueries, new(MockProductQueries), new(MockManifestQueries), nil, nil, passthroughTx{}, &recordingOutbox{}, alwaysOpen{}, signer, noQuotas{}, noLocks{})
+	r.POST("/receptions", func(c *gin.Context) {
+		c.Set("userRole", models.RoleEmployee)
+		receptionHandler.CreateReception(c)
+	})
+	r.GET("/public/receptions/:token", NewPublicReceptionHandler(receptionQueries, signer).GetReceptionStatus)
+
+	reception := &models.Reception{
+		ID:           "223e4567-e89b-12d3-a456-426614174000",
+		DateTime:     time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+		PvzID:        testPvzID,
+		Status:       "paused",
+		ProductCount: 4,
+	}
+	receptionQueries.On("CreateReception", mock.Anything, testPvzID, mock.Anything).Return(reception, nil)
+	receptionQueries.On("GetReceptionByID", mock.Anything, reception.ID).Return(reception, nil)
+
+	body, _ := json.Marshal(models.CreateReceptionRequest{PvzID: testPvzID})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/receptions", bytes.NewReader(body)))
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created models.CreateReceptionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.NotEmpty(t, created.PublicToken)
+
+	t.Run("Статус по ссылке", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/public/receptions/"+created.PublicToken, nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var status models.PublicReceptionStatus
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+		assert.Equal(t, models.PublicReceptionOpen, status.Status)
+		assert.Equal(t, 4, status.ProductCount)
+		assert.NotContains(t, w.Body.String(), testPvzID)
+	})
+
+	t.Run("Чужая подпись", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		token := publiclink.NewSigner("other").Sign(uuid.MustParse(reception.ID))
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/public/receptions/"+token, nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -2,28 +2,47 @@
 package handlers
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
+	"slices"
+	"strings"
 
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/authz"
+	"pvz-service/internal/city"
 	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/mapper"
 	"pvz-service/internal/models"
+	"pvz-service/internal/service"
+	"pvz-service/internal/storage"
 
 	"github.com/gin-gonic/gin"
 )
 
+// defaultPVZListReceptions - сколько последних приёмок ПВЗ возвращается в списке ПВЗ по умолчанию
+const defaultPVZListReceptions = 5
+
 // PVZHandler содержит обработчики для работы с ПВЗ
 type PVZHandler struct {
 	pvzQueries       queries.PVZQueriesInterface
 	receptionQueries queries.ReceptionQueriesInterface
 	productQueries   queries.ProductQueriesInterface
+	cityResolver     city.ResolverInterface
+	storage          storage.Storage
+	cityAccess       *service.CityAccess
 }
 
-// NewPVZHandler создает новый экземпляр PVZHandler
-func NewPVZHandler(pvzQueries queries.PVZQueriesInterface, receptionQueries queries.ReceptionQueriesInterface, productQueries queries.ProductQueriesInterface) *PVZHandler {
+// NewPVZHandler создает новый экземпляр PVZHandler.
+// Модератор создает и деактивирует ПВЗ только в назначенных ему городах (moderatorQueries)
+func NewPVZHandler(pvzQueries queries.PVZQueriesInterface, receptionQueries queries.ReceptionQueriesInterface, productQueries queries.ProductQueriesInterface, moderatorQueries queries.ModeratorQueriesInterface, cityResolver city.ResolverInterface, storage storage.Storage) *PVZHandler {
 	return &PVZHandler{
 		pvzQueries:       pvzQueries,
 		receptionQueries: receptionQueries,
 		productQueries:   productQueries,
+		cityResolver:     cityResolver,
+		storage:          storage,
+		cityAccess:       service.NewCityAccess(moderatorQueries, pvzQueries),
 	}
 }
 
@@ -33,35 +52,46 @@ func (h *PVZHandler) CreatePVZ(c *gin.Context) {
 
 	// Проверяем запрос
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Message: "Неверный запрос: " + err.Error(),
-		})
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
 		return
 	}
 
-	userRole, _ := c.Get("userRole")
-	if userRole != "moderator" {
-		c.JSON(http.StatusForbidden, models.ErrorResponse{
-			Message: "Доступ запрещен: только модераторы могут создавать ПВЗ",
-		})
+	if !authz.Can(c.GetString("userRole"), authz.PVZCreate) {
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbiddenCreatePVZ))
+		return
+	}
+
+	// Приводим город к названию из справочника
+	cityName, err := h.cityResolver.Resolve(c.Request.Context(), req.City)
+	if errors.Is(err, city.ErrUnknownCity) {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgUnknownCity)+": "+req.City)
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCreatePVZFailed, err))
+		return
+	}
+
+	// Модератор создает ПВЗ только в своих городах
+	err = h.cityAccess.CheckCity(c.Request.Context(), c.GetString("userID"), cityName)
+	if errors.Is(err, service.ErrCityForbidden) {
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbiddenPVZCity))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCheckCityAccessFailed, err))
 		return
 	}
 
 	// Создаем ПВЗ
-	pvz, err := h.pvzQueries.CreatePVZ(c.Request.Context(), req.City)
+	pvz, err := h.pvzQueries.CreatePVZ(c.Request.Context(), cityName, strings.TrimSpace(req.Address), req.Timezone)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Message: "Ошибка при создании ПВЗ: " + err.Error(),
-		})
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCreatePVZFailed, err))
 		return
 	}
 
 	// Возвращаем данные созданного ПВЗ
-	c.JSON(http.StatusCreated, models.PVZResponse{
-		ID:               pvz.ID,
-		RegistrationDate: pvz.RegistrationDate,
-		City:             pvz.City,
-	})
+	response.JSON(c, http.StatusCreated, mapper.PVZ(*pvz))
 }
 
 // GetPVZList обрабатывает запрос на получение списка ПВЗ с фильтрацией и пагинацией
@@ -71,86 +101,223 @@ func (h *PVZHandler) GetPVZList(c *gin.Context) {
 	// Устанавливаем значения по умолчанию
 	query.Page = 1
 	query.Limit = 10
+	query.ReceptionsLimit = defaultPVZListReceptions
 
 	// Извлекаем параметры запроса
 	if err := c.ShouldBindQuery(&query); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Message: "Неверные параметры запроса: " + err.Error(),
-		})
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
 		return
 	}
 
+	// Теги приёмок хранятся в нижнем регистре
+	query.Tag = strings.ToLower(strings.TrimSpace(query.Tag))
+
+	// Дополнительные данные ПВЗ запрашиваются списком через запятую
+	if query.Include != "" {
+		for _, part := range strings.Split(query.Include, ",") {
+			if !slices.Contains(models.PVZIncludes, strings.TrimSpace(part)) {
+				response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgInvalidQueryParams)+": include="+part)
+				return
+			}
+		}
+	}
+
+	// Приводим город фильтра к названию из справочника
+	if query.City != "" {
+		cityName, err := h.cityResolver.Resolve(c.Request.Context(), query.City)
+		if errors.Is(err, city.ErrUnknownCity) {
+			response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgUnknownCity)+": "+query.City)
+			return
+		}
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetPVZListFailed, err))
+			return
+		}
+		query.City = cityName
+	}
+
 	// Получаем список ПВЗ
 	pvzList, total, err := h.pvzQueries.GetPVZList(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Message: "Ошибка при получении списка ПВЗ: " + err.Error(),
-		})
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetPVZListFailed, err))
 		return
 	}
 
-	// Формируем ответ с приёмками и товарами
-	var response []models.PVZWithReceptionsResponse
-
+	// Получаем последние приёмки всех ПВЗ страницы одним запросом. Лишняя приёмка
+	// сверх ReceptionsLimit запрашивается, чтобы узнать, есть ли у ПВЗ более старые
+	pvzIDs := make([]string, 0, len(pvzList))
 	for _, pvz := range pvzList {
-		// Получаем все приёмки для ПВЗ
-		receptions, err := h.receptionQueries.GetReceptionsByPVZ(c.Request.Context(), pvz.ID)
+		pvzIDs = append(pvzIDs, pvz.ID)
+	}
+	receptionsByPVZ := make(map[string][]models.Reception, len(pvzList))
+	if len(pvzIDs) > 0 {
+		latest, err := h.receptionQueries.GetLatestReceptionsByPVZ(c.Request.Context(), pvzIDs, query.ReceptionsLimit+1)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Message: "Ошибка при получении приёмок: " + err.Error(),
-			})
+			response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetReceptionsFailed, err))
 			return
 		}
+		for _, reception := range latest {
+			receptionsByPVZ[reception.PvzID] = append(receptionsByPVZ[reception.PvzID], reception)
+		}
+	}
+
+	// Формируем ответ с приёмками и товарами
+	var result []models.PVZWithReceptionsResponse
+
+	for _, pvz := range pvzList {
+		receptions := receptionsByPVZ[pvz.ID]
+		hasMore := len(receptions) > query.ReceptionsLimit
+		if hasMore {
+			receptions = receptions[:query.ReceptionsLimit]
+		}
 
 		// Собираем информацию о приёмках и товарах
 		receptionDetails := make([]models.ReceptionDetails, 0, len(receptions))
 
 		for _, reception := range receptions {
-			// Получаем товары для приёмки
-			products, err := h.productQueries.GetProductsByReception(c.Request.Context(), reception.ID)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-					Message: "Ошибка при получении товаров: " + err.Error(),
-				})
-				return
+			// Получаем товары для приёмки; пустые приёмки определяются по счетчику без обращения к товарам
+			var products []models.Product
+			if reception.ProductCount > 0 {
+				products, err = h.productQueries.GetProductsByReception(c.Request.Context(), reception.ID)
+				if err != nil {
+					response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetProductsFailed, err))
+					return
+				}
+			}
+
+			// Получаем фотографии товаров приёмки
+			photos := make(map[string][]models.ProductPhoto)
+			if len(products) > 0 {
+				receptionPhotos, err := h.productQueries.GetPhotosByReception(c.Request.Context(), reception.ID)
+				if err != nil {
+					response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetPhotosFailed, err))
+					return
+				}
+				for _, photo := range receptionPhotos {
+					photos[photo.ProductID] = append(photos[photo.ProductID], photo)
+				}
 			}
 
 			// Преобразуем товары в ответ
-			productResponses := make([]models.ProductResponse, 0, len(products))
-			for _, product := range products {
-				productResponses = append(productResponses, models.ProductResponse{
-					ID:          product.ID,
-					DateTime:    product.Datetime,
-					Type:        product.Type,
-					ReceptionID: product.ReceptionID,
-				})
+			productResponses := mapper.Products(products)
+			for i := range productResponses {
+				productResponses[i].Photos = service.PhotoURLs(c.Request.Context(), h.storage, photos[productResponses[i].ID])
 			}
 
 			// Добавляем информацию о приёмке и товарах
 			receptionDetails = append(receptionDetails, models.ReceptionDetails{
-				Reception: models.ReceptionResponse{
-					ID:       reception.ID,
-					DateTime: reception.DateTime,
-					PvzID:    reception.PvzID,
-					Status:   reception.Status,
-				},
-				Products: productResponses,
+				Reception: mapper.Reception(reception),
+				Products:  productResponses,
 			})
 		}
 
 		// Добавляем ПВЗ с приёмками в ответ
-		response = append(response, models.PVZWithReceptionsResponse{
-			PVZ: models.PVZResponse{
-				ID:               pvz.ID,
-				RegistrationDate: pvz.RegistrationDate,
-				City:             pvz.City,
-			},
-			Receptions: receptionDetails,
+		result = append(result, models.PVZWithReceptionsResponse{
+			PVZ:               mapper.PVZ(pvz),
+			Receptions:        receptionDetails,
+			ReceptionsHasMore: hasMore,
 		})
 	}
 
-	// Добавляем заголовок X-Total-Count для пагинации
-	c.Header("X-Total-Count", fmt.Sprintf("%d", total))
+	// Общее количество и ссылки на соседние страницы передаются в заголовках, а в API v1 - также в meta
+	response.Paginated(c, http.StatusOK, result, response.NewMeta(query.Page, query.Limit, total))
+}
+
+// GetInactivePVZ обрабатывает запрос на получение ПВЗ, предложенных к деактивации
+func (h *PVZHandler) GetInactivePVZ(c *gin.Context) {
+	inactive, err := h.pvzQueries.GetInactivePVZ(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetInactivePVZFailed, err))
+		return
+	}
+
+	result := make([]models.InactivePVZResponse, 0, len(inactive))
+	for _, pvz := range inactive {
+		item := mapper.InactivePVZ(pvz)
+		item.Actions = []models.PVZAction{
+			{
+				Name:   "deactivate",
+				Method: http.MethodPost,
+				Href:   "/admin/pvz/" + pvz.ID + "/deactivate",
+			},
+		}
+		result = append(result, item)
+	}
+
+	response.JSON(c, http.StatusOK, result)
+}
+
+// DeactivatePVZ обрабатывает запрос на деактивацию ПВЗ
+func (h *PVZHandler) DeactivatePVZ(c *gin.Context) {
+	pvzID := c.Param("pvzId")
+
+	// Проверяем, что pvzId указан
+	if pvzID == "" {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgPVZIDRequired))
+		return
+	}
+
+	// Модератор деактивирует ПВЗ только в своих городах
+	err := h.cityAccess.CheckPVZ(c.Request.Context(), c.GetString("userID"), pvzID)
+	switch {
+	case errors.Is(err, queries.ErrNotFound):
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgActivePVZNotFound))
+		return
+	case errors.Is(err, service.ErrCityForbidden):
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbiddenPVZCity))
+		return
+	case err != nil:
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCheckCityAccessFailed, err))
+		return
+	}
+
+	err = h.pvzQueries.DeactivatePVZ(c.Request.Context(), pvzID)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgActivePVZNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgDeactivatePVZFailed, err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetCapacity обрабатывает запрос модератора на изменение вместимости ПВЗ своего города.
+// Сверх вместимости товары принимаются только с подтверждением, null снимает ограничение
+func (h *PVZHandler) SetCapacity(c *gin.Context) {
+	var req models.UpdatePVZCapacityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	pvzID := c.Param("pvzId")
+
+	// Модератор меняет вместимость только ПВЗ в своих городах
+	err := h.cityAccess.CheckPVZ(c.Request.Context(), c.GetString("userID"), pvzID)
+	switch {
+	case errors.Is(err, queries.ErrNotFound):
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgPVZNotFound))
+		return
+	case errors.Is(err, service.ErrCityForbidden):
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbiddenPVZCity))
+		return
+	case err != nil:
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCheckCityAccessFailed, err))
+		return
+	}
+
+	pvz, err := h.pvzQueries.SetCapacity(c.Request.Context(), pvzID, req.Capacity)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgPVZNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgUpdatePVZCapacityFailed, err))
+		return
+	}
 
-	c.JSON(http.StatusOK, response)
+	response.JSON(c, http.StatusOK, mapper.PVZ(*pvz))
 }
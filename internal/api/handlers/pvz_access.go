@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/authz"
+	"pvz-service/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errPVZAccessDenied прерывает транзакцию, если объект запроса оказался в ПВЗ вне области токена
+var errPVZAccessDenied = errors.New("pvz access denied")
+
+// pvzScoped сообщает, ограничен ли токен списком ПВЗ. Только тогда обработчику нужно
+// узнавать ПВЗ товара отдельным запросом
+func pvzScoped(c *gin.Context) bool {
+	return len(c.GetStringSlice("pvzIDs")) > 0
+}
+
+// canAccessPVZ сообщает, входит ли ПВЗ в область токена из claim pvz_ids
+func canAccessPVZ(c *gin.Context, pvzID string) bool {
+	return authz.CanAccessPVZ(c.GetStringSlice("pvzIDs"), pvzID)
+}
+
+// denyPVZAccess отвечает 403, если ПВЗ не входит в область токена, и сообщает, отклонен ли запрос.
+// Маршруты /pvz/:pvzId проверяет RequirePVZAccess, остальные обработчики узнают ПВЗ из тела запроса,
+// приёмки, товара или заказа и проверяют его сами
+func denyPVZAccess(c *gin.Context, pvzID string) bool {
+	if canAccessPVZ(c, pvzID) {
+		return false
+	}
+	response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgPVZAccessDenied))
+	return true
+}
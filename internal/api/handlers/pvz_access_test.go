@@ -0,0 +1,370 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testForeignPvzID        = "923e4567-e89b-12d3-a456-426614174000"
+	testScopeReceptionID    = "923e4567-e89b-12d3-a456-426614174001"
+	testScopeTargetID       = "923e4567-e89b-12d3-a456-426614174002"
+	testScopeProductID      = "923e4567-e89b-12d3-a456-426614174003"
+	testScopeOrderID        = "923e4567-e89b-12d3-a456-426614174004"
+	testScopeRecipientPhone = "+79990000000"
+)
+
+// pvzAccessMocks - зависимости обработчиков в тесте области токена
+type pvzAccessMocks struct {
+	receptions *MockReceptionQueries
+	products   *MockProductQueries
+	orders     *MockOrderQueries
+	outbox     *recordingOutbox
+}
+
+// setupPVZAccessTest создает маршруты, которые узнают ПВЗ из тела запроса, приёмки, товара или заказа,
+// для пользователя с ролью role и токеном, ограниченным ПВЗ testPvzID
+func setupPVZAccessTest(role string) (*gin.Engine, pvzAccessMocks) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	m := pvzAccessMocks{
+		receptions: new(MockReceptionQueries),
+		products:   new(MockProductQueries),
+		orders:     new(MockOrderQueries),
+		outbox:     &recordingOutbox{},
+	}
+	pvzQueries := new(MockPVZQueries)
+
+	receptionHandler := NewReceptionHandler(m.receptions, m.products, new(MockManifestQueries), nil, nil, passthroughTx{}, m.outbox, alwaysOpen{}, nil, noQuotas{}, noLocks{})
+	productHandler := newTestProductHandler(m.products, m.receptions, m.outbox, nil, productLimits{}, alwaysOpen{}, noQuotas{}, noLocks{})
+	actHandler := NewReceptionActHandler(m.receptions, m.products, pvzQueries)
+	reassignHandler := NewReceptionReassignHandler(pvzQueries, m.receptions, moderatorCities{}, passthroughTx{}, m.outbox)
+	orderHandler := NewOrderHandler(m.orders, passthroughTx{}, m.outbox)
+
+	scoped := r.Group("/", func(c *gin.Context) {
+		c.Set("userID", testEmployeeID)
+		c.Set("userRole", role)
+		c.Set("pvzIDs", []string{testPvzID})
+		c.Next()
+	})
+	scoped.POST("/receptions", receptionHandler.CreateReception)
+	scoped.POST("/receptions/:receptionId/import", receptionHandler.ImportManifest)
+	scoped.PATCH("/receptions/:receptionId", receptionHandler.UpdateReception)
+	scoped.GET("/receptions/overdue", receptionHandler.GetOverdueReceptions)
+	scoped.GET("/receptions/:receptionId/notes", receptionHandler.GetReceptionNotes)
+	scoped.GET("/receptions/:receptionId/discrepancies", receptionHandler.GetDiscrepancyReport)
+	scoped.GET("/receptions/:receptionId/act.pdf", actHandler.GetReceptionAct)
+	scoped.GET("/receptions/:receptionId/custody", actHandler.GetReceptionCustody)
+	scoped.POST("/receptions/:receptionId/merge_into/:targetId", receptionHandler.MergeReceptions)
+	scoped.POST("/receptions/:receptionId/reassign", reassignHandler.ReassignReception)
+	scoped.POST("/products", productHandler.AddProduct)
+	scoped.GET("/products", productHandler.ListProducts)
+	scoped.POST("/products/status_batch", productHandler.GetStatusBatch)
+	scoped.DELETE("/products/:productId", productHandler.DeleteProduct)
+	scoped.PATCH("/products/:productId", productHandler.UpdateProduct)
+	scoped.POST("/products/:productId/issue", productHandler.IssueProduct)
+	scoped.POST("/products/:productId/mark_damaged", productHandler.MarkDamaged)
+	scoped.POST("/orders", orderHandler.CreateOrder)
+	scoped.GET("/orders/:orderId", orderHandler.GetOrder)
+	scoped.POST("/orders/:orderId/issue", orderHandler.IssueOrder)
+
+	return r, m
+}
+
+// foreignReception возвращает приёмку testScopeReceptionID из ПВЗ вне области токена
+func foreignReception(m pvzAccessMocks) {
+	m.receptions.On("GetReceptionByID", mock.Anything, testScopeReceptionID).Return(&models.Reception{
+		ID:     testScopeReceptionID,
+		PvzID:  testForeignPvzID,
+		Status: "in_progress",
+	}, nil)
+}
+
+// foreignProduct возвращает товар testScopeProductID из ПВЗ вне области токена
+func foreignProduct(m pvzAccessMocks) {
+	m.products.On("GetProductStates", mock.Anything, []string{testScopeProductID}).Return([]models.ProductState{
+		{ID: testScopeProductID, PvzID: testForeignPvzID, ReceptionID: testScopeReceptionID, Status: models.ProductLifecycleStored},
+	}, nil)
+}
+
+// foreignOrder возвращает заказ testScopeOrderID из ПВЗ вне области токена
+func foreignOrder(m pvzAccessMocks) {
+	m.orders.On("GetOrder", mock.Anything, testScopeOrderID).Return(&models.Order{ID: testScopeOrderID, PvzID: testForeignPvzID}, nil)
+}
+
+// TestScopedTokenForeignPVZ проверяет, что токен с claim pvz_ids не дает работать с приёмками,
+// товарами и заказами других ПВЗ на маршрутах без :pvzId, и что отказ происходит до изменений
+func TestScopedTokenForeignPVZ(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    string
+		request func(t *testing.T) *http.Request
+		setup   func(m pvzAccessMocks)
+		// notCalled - изменяющие методы, которые не должны вызываться
+		notCalled func(t *testing.T, m pvzAccessMocks)
+	}{
+		{
+			name:    "Создание приёмки",
+			role:    models.RoleEmployee,
+			request: jsonRequest("POST", "/receptions", `{"pvzId":"`+testForeignPvzID+`"}`),
+			notCalled: func(t *testing.T, m pvzAccessMocks) {
+				m.receptions.AssertNotCalled(t, "CreateReception", mock.Anything, mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name: "Импорт накладной",
+			role: models.RoleEmployee,
+			request: func(t *testing.T) *http.Request {
+				return newManifestRequest(t, testScopeReceptionID, "manifest.csv", "type,barcode,quantity\nобувь,,1\n")
+			},
+			setup: foreignReception,
+		},
+		{
+			name:    "Изменение заметки приёмки",
+			role:    models.RoleEmployee,
+			request: jsonRequest("PATCH", "/receptions/"+testScopeReceptionID, `{"note":"чужая"}`),
+			setup: func(m pvzAccessMocks) {
+				m.receptions.On("UpdateReceptionNotes", mock.Anything, testScopeReceptionID, testEmployeeID, mock.Anything, mock.Anything).
+					Return(&models.Reception{ID: testScopeReceptionID, PvzID: testForeignPvzID}, nil)
+			},
+		},
+		{
+			name:    "Просроченные приёмки чужого ПВЗ",
+			role:    models.RoleEmployee,
+			request: jsonRequest("GET", "/receptions/overdue?pvzId="+testForeignPvzID, ""),
+			notCalled: func(t *testing.T, m pvzAccessMocks) {
+				m.receptions.AssertNotCalled(t, "GetOverdueReceptions", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:    "История заметок приёмки",
+			role:    models.RoleEmployee,
+			request: jsonRequest("GET", "/receptions/"+testScopeReceptionID+"/notes", ""),
+			setup:   foreignReception,
+		},
+		{
+			name:    "Отчет о расхождениях",
+			role:    models.RoleEmployee,
+			request: jsonRequest("GET", "/receptions/"+testScopeReceptionID+"/discrepancies", ""),
+			setup:   foreignReception,
+		},
+		{
+			name:    "Акт приёмки",
+			role:    models.RoleEmployee,
+			request: jsonRequest("GET", "/receptions/"+testScopeReceptionID+"/act.pdf", ""),
+			setup:   foreignReception,
+		},
+		{
+			name:    "Цепочка хранения",
+			role:    models.RoleEmployee,
+			request: jsonRequest("GET", "/receptions/"+testScopeReceptionID+"/custody", ""),
+			setup:   foreignReception,
+		},
+		{
+			name:    "Объединение приёмок",
+			role:    models.RoleModerator,
+			request: jsonRequest("POST", "/receptions/"+testScopeReceptionID+"/merge_into/"+testScopeTargetID, ""),
+			setup: func(m pvzAccessMocks) {
+				m.receptions.On("LockReceptions", mock.Anything, []string{testScopeReceptionID, testScopeTargetID}).Return([]models.Reception{
+					{ID: testScopeReceptionID, PvzID: testForeignPvzID, Status: "in_progress"},
+					{ID: testScopeTargetID, PvzID: testForeignPvzID, Status: "in_progress"},
+				}, nil)
+			},
+			notCalled: func(t *testing.T, m pvzAccessMocks) {
+				m.products.AssertNotCalled(t, "MoveProducts", mock.Anything, mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:    "Перенос чужой приёмки",
+			role:    models.RoleModerator,
+			request: jsonRequest("POST", "/receptions/"+testScopeReceptionID+"/reassign?pvzId="+testPvzID, ""),
+			setup:   foreignReception,
+		},
+		{
+			name:    "Перенос приёмки в чужой ПВЗ",
+			role:    models.RoleModerator,
+			request: jsonRequest("POST", "/receptions/"+testScopeReceptionID+"/reassign?pvzId="+testForeignPvzID, ""),
+			setup: func(m pvzAccessMocks) {
+				m.receptions.On("GetReceptionByID", mock.Anything, testScopeReceptionID).Return(&models.Reception{
+					ID:     testScopeReceptionID,
+					PvzID:  testPvzID,
+					Status: "close",
+				}, nil)
+			},
+		},
+		{
+			name:    "Добавление товара",
+			role:    models.RoleEmployee,
+			request: jsonRequest("POST", "/products", `{"type":"обувь","pvzId":"`+testForeignPvzID+`"}`),
+			notCalled: func(t *testing.T, m pvzAccessMocks) {
+				m.receptions.AssertNotCalled(t, "GetLastOpenReception", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:    "Список товаров приёмки",
+			role:    models.RoleEmployee,
+			request: jsonRequest("GET", "/products?receptionId="+testScopeReceptionID, ""),
+			setup:   foreignReception,
+		},
+		{
+			name:    "Удаление товара по ID",
+			role:    models.RoleModerator,
+			request: jsonRequest("DELETE", "/products/"+testScopeProductID, ""),
+			setup:   foreignProduct,
+			notCalled: func(t *testing.T, m pvzAccessMocks) {
+				m.products.AssertNotCalled(t, "GetProduct", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:    "Исправление типа товара",
+			role:    models.RoleModerator,
+			request: jsonRequest("PATCH", "/products/"+testScopeProductID, `{"type":"обувь"}`),
+			setup:   foreignProduct,
+			notCalled: func(t *testing.T, m pvzAccessMocks) {
+				m.products.AssertNotCalled(t, "GetProduct", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:    "Выдача товара",
+			role:    models.RoleEmployee,
+			request: jsonRequest("POST", "/products/"+testScopeProductID+"/issue", ""),
+			setup:   foreignProduct,
+			notCalled: func(t *testing.T, m pvzAccessMocks) {
+				m.products.AssertNotCalled(t, "IssueProduct", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:    "Отметка о повреждении",
+			role:    models.RoleEmployee,
+			request: jsonRequest("POST", "/products/"+testScopeProductID+"/mark_damaged", `{"description":"вмятина"}`),
+			setup:   foreignProduct,
+			notCalled: func(t *testing.T, m pvzAccessMocks) {
+				m.products.AssertNotCalled(t, "GetProduct", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name: "Создание заказа",
+			role: models.RoleEmployee,
+			request: jsonRequest("POST", "/orders", `{"orderNumber":"A-1","recipientPhone":"`+testScopeRecipientPhone+
+				`","pvzId":"`+testForeignPvzID+`","productIds":["`+testScopeProductID+`"]}`),
+			notCalled: func(t *testing.T, m pvzAccessMocks) {
+				m.orders.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:    "Просмотр заказа",
+			role:    models.RoleEmployee,
+			request: jsonRequest("GET", "/orders/"+testScopeOrderID, ""),
+			setup:   foreignOrder,
+			notCalled: func(t *testing.T, m pvzAccessMocks) {
+				m.orders.AssertNotCalled(t, "GetOrderProducts", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:    "Выдача заказа",
+			role:    models.RoleEmployee,
+			request: jsonRequest("POST", "/orders/"+testScopeOrderID+"/issue", ""),
+			setup:   foreignOrder,
+			notCalled: func(t *testing.T, m pvzAccessMocks) {
+				m.orders.AssertNotCalled(t, "IssueOrder", mock.Anything, mock.Anything, mock.Anything)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := setupPVZAccessTest(tt.role)
+			if tt.setup != nil {
+				tt.setup(m)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, tt.request(t))
+
+			assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+			assert.Empty(t, m.outbox.events)
+			if tt.notCalled != nil {
+				tt.notCalled(t, m)
+			}
+		})
+	}
+}
+
+// TestScopedTokenOwnPVZ проверяет, что токен с claim pvz_ids работает с приёмками своего ПВЗ
+func TestScopedTokenOwnPVZ(t *testing.T) {
+	r, m := setupPVZAccessTest(models.RoleEmployee)
+	m.receptions.On("GetReceptionByID", mock.Anything, testScopeReceptionID).Return(&models.Reception{
+		ID:     testScopeReceptionID,
+		PvzID:  testPvzID,
+		Status: "in_progress",
+	}, nil)
+	m.receptions.On("GetReceptionNotes", mock.Anything, testScopeReceptionID).Return([]models.ReceptionNote{}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, jsonRequest("GET", "/receptions/"+testScopeReceptionID+"/notes", "")(t))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	m.receptions.AssertExpectations(t)
+}
+
+// TestScopedTokenFiltersLists проверяет, что общие списки не раскрывают токену с claim pvz_ids
+// приёмки и товары других ПВЗ
+func TestScopedTokenFiltersLists(t *testing.T) {
+	t.Run("Просроченные приёмки без pvzId", func(t *testing.T) {
+		r, m := setupPVZAccessTest(models.RoleEmployee)
+		m.receptions.On("GetOverdueReceptions", mock.Anything, "").Return([]models.OverdueReception{
+			{ID: "own-reception", PvzID: testPvzID, Status: "in_progress"},
+			{ID: "foreign-reception", PvzID: testForeignPvzID, Status: "in_progress"},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, jsonRequest("GET", "/receptions/overdue", "")(t))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []models.OverdueReceptionResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response, 1)
+		assert.Equal(t, "own-reception", response[0].ID)
+	})
+
+	t.Run("Статусы товаров", func(t *testing.T) {
+		r, m := setupPVZAccessTest(models.RoleEmployee)
+		ownProductID := "923e4567-e89b-12d3-a456-426614174005"
+		ids := []string{ownProductID, testScopeProductID}
+		m.products.On("GetProductStates", mock.Anything, ids).Return([]models.ProductState{
+			{ID: ownProductID, Version: 1, ReceptionID: "own-reception", ReceptionStatus: "in_progress", PvzID: testPvzID},
+			{ID: testScopeProductID, Version: 2, ReceptionID: testScopeReceptionID, ReceptionStatus: "in_progress", PvzID: testForeignPvzID},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, jsonRequest("POST", "/products/status_batch", `{"productIds":["`+ownProductID+`","`+testScopeProductID+`"]}`)(t))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []models.ProductStatusResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, []models.ProductStatusResponse{
+			{ID: ownProductID, Status: models.ProductStatusInReception, Version: 1, ReceptionID: "own-reception", PvzID: testPvzID},
+			{ID: testScopeProductID, Status: models.ProductStatusNotFound},
+		}, response)
+	})
+}
+
+// jsonRequest возвращает построитель запроса с JSON-телом body
+func jsonRequest(method, path, body string) func(t *testing.T) *http.Request {
+	return func(t *testing.T) *http.Request {
+		req, err := http.NewRequest(method, path, strings.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/authz"
+	"pvz-service/internal/db"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+	"pvz-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PVZArchiveHandler содержит обработчик удаления ПВЗ в архив
+type PVZArchiveHandler struct {
+	pvzService *service.PVZService
+	cityAccess *service.CityAccess
+}
+
+// NewPVZArchiveHandler создает новый экземпляр PVZArchiveHandler.
+// Модератор удаляет ПВЗ только в назначенных ему городах (moderatorQueries)
+func NewPVZArchiveHandler(pvzQueries queries.PVZQueriesInterface, receptionQueries queries.ReceptionQueriesInterface, moderatorQueries queries.ModeratorQueriesInterface, tx db.Transactor, outboxQueries queries.OutboxQueriesInterface) *PVZArchiveHandler {
+	return &PVZArchiveHandler{
+		pvzService: service.NewPVZService(pvzQueries, receptionQueries, tx, outboxQueries),
+		cityAccess: service.NewCityAccess(moderatorQueries, pvzQueries),
+	}
+}
+
+// DeletePVZ обрабатывает запрос модератора на удаление ПВЗ. ПВЗ переносится в архив
+// с сохранением истории приёмок; ПВЗ с открытой приёмкой удаляется только с force=true,
+// открытые приёмки при этом закрываются
+func (h *PVZArchiveHandler) DeletePVZ(c *gin.Context) {
+	if !authz.Can(c.GetString("userRole"), authz.PVZDelete) {
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbidden))
+		return
+	}
+
+	var query models.ArchivePVZQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+
+	pvzID := c.Param("pvzId")
+	err := h.cityAccess.CheckPVZ(c.Request.Context(), c.GetString("userID"), pvzID)
+	switch {
+	case errors.Is(err, queries.ErrNotFound):
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgPVZNotFound))
+		return
+	case errors.Is(err, service.ErrCityForbidden):
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbiddenPVZCity))
+		return
+	case err != nil:
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCheckCityAccessFailed, err))
+		return
+	}
+
+	result, err := h.pvzService.ArchivePVZ(c.Request.Context(), pvzID, c.GetString("userID"), query.Force)
+	switch {
+	case errors.Is(err, queries.ErrNotFound):
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgPVZNotFound))
+	case errors.Is(err, service.ErrPVZHasOpenReception):
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgPVZHasOpenReception))
+	case err != nil:
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgArchivePVZFailed, err))
+	default:
+		response.JSON(c, http.StatusOK, result)
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupPVZArchiveTest создает маршрут удаления ПВЗ с ролью role
+func setupPVZArchiveTest(role string) (*gin.Engine, *MockPVZQueries, *MockReceptionQueries, *recordingOutbox) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	pvzQueries := new(MockPVZQueries)
+	receptionQueries := new(MockReceptionQueries)
+	outbox := &recordingOutbox{}
+	handler := NewPVZArchiveHandler(pvzQueries, receptionQueries, moderatorCities{}, passthroughTx{}, outbox)
+	r.DELETE("/pvz/:pvzId", func(c *gin.Context) {
+		c.Set("userRole", role)
+		handler.DeletePVZ(c)
+	})
+
+	return r, pvzQueries, receptionQueries, outbox
+}
+
+// TestDeletePVZ проверяет удаление ПВЗ без открытых приёмок
+func TestDeletePVZ(t *testing.T) {
+	r, pvzQueries, receptionQueries, outbox := setupPVZArchiveTest(models.RoleModerator)
+	archivedAt := time.Date(2026, 3, 1, 21, 30, 0, 0, time.UTC)
+
+	pvzQueries.On("LockPVZ", mock.Anything, testPvzID).Return(&models.PVZ{ID: testPvzID}, nil)
+	pvzQueries.On("ArchivePVZ", mock.Anything, testPvzID).Return(archivedAt, nil)
+
+	req, _ := http.NewRequest("DELETE", "/pvz/"+testPvzID, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result models.ArchivePVZResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, archivedAt, result.ArchivedAt)
+	assert.Empty(t, result.ClosedReceptions)
+
+	assert.Len(t, outbox.events, 1)
+	assert.Equal(t, models.EventPVZArchived, outbox.events[0].Type)
+	receptionQueries.AssertNotCalled(t, "CloseReception", mock.Anything, mock.Anything, mock.Anything)
+	pvzQueries.AssertExpectations(t)
+}
+
+// TestDeletePVZOpenReception проверяет отказ в удалении ПВЗ с открытой приёмкой без force
+func TestDeletePVZOpenReception(t *testing.T) {
+	r, pvzQueries, _, outbox := setupPVZArchiveTest(models.RoleModerator)
+
+	pvzQueries.On("LockPVZ", mock.Anything, testPvzID).Return(&models.PVZ{ID: testPvzID, OpenReception: true}, nil)
+
+	req, _ := http.NewRequest("DELETE", "/pvz/"+testPvzID, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Empty(t, outbox.events)
+	pvzQueries.AssertNotCalled(t, "ArchivePVZ", mock.Anything, mock.Anything)
+}
+
+// TestDeletePVZForce проверяет закрытие открытой приёмки при удалении с force=true
+func TestDeletePVZForce(t *testing.T) {
+	r, pvzQueries, receptionQueries, outbox := setupPVZArchiveTest(models.RoleModerator)
+
+	pvzQueries.On("LockPVZ", mock.Anything, testPvzID).Return(&models.PVZ{ID: testPvzID, OpenReception: true}, nil)
+	receptionQueries.On("GetReceptionsByPVZ", mock.Anything, testPvzID).Return([]models.Reception{
+		{ID: "closed-reception", PvzID: testPvzID, Status: "close"},
+		{ID: testReceptionID, PvzID: testPvzID, Status: "in_progress"},
+	}, nil)
+	receptionQueries.On("CloseReception", mock.Anything, testReceptionID, mock.Anything).
+		Return(&models.Reception{ID: testReceptionID, PvzID: testPvzID, Status: "close"}, nil)
+	pvzQueries.On("ArchivePVZ", mock.Anything, testPvzID).Return(time.Now(), nil)
+
+	req, _ := http.NewRequest("DELETE", "/pvz/"+testPvzID+"?force=true", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result models.ArchivePVZResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Len(t, result.ClosedReceptions, 1)
+	assert.Equal(t, testReceptionID, result.ClosedReceptions[0].ID)
+
+	assert.Len(t, outbox.events, 2)
+	assert.Equal(t, models.EventReceptionClosed, outbox.events[0].Type)
+	assert.Equal(t, models.EventPVZArchived, outbox.events[1].Type)
+	receptionQueries.AssertNumberOfCalls(t, "CloseReception", 1)
+}
+
+// TestDeletePVZErrors проверяет ответы для отсутствующего ПВЗ и роли без права удаления
+func TestDeletePVZErrors(t *testing.T) {
+	r, pvzQueries, _, _ := setupPVZArchiveTest(models.RoleModerator)
+	pvzQueries.On("LockPVZ", mock.Anything, testPvzID).Return(nil, queries.ErrNotFound)
+
+	req, _ := http.NewRequest("DELETE", "/pvz/"+testPvzID, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	r, pvzQueries, _, _ = setupPVZArchiveTest(models.RoleEmployee)
+	req, _ = http.NewRequest("DELETE", "/pvz/"+testPvzID, nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	pvzQueries.AssertNotCalled(t, "LockPVZ", mock.Anything, mock.Anything)
+}
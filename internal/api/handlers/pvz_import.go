@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"unicode/utf8"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/authz"
+	"pvz-service/internal/city"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+	"pvz-service/internal/pvzimport"
+	"pvz-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxPVZImportSize ограничивает размер загружаемого файла импорта ПВЗ
+const maxPVZImportSize = 2 << 20
+
+// maxPVZAddressLength ограничивает длину адреса ПВЗ в символах
+const maxPVZAddressLength = 255
+
+// ImportPVZ обрабатывает загрузку CSV-файла с городами и адресами ПВЗ (только для модераторов).
+// Каждая строка проверяется отдельно; если хотя бы одна строка неверна, ни один ПВЗ не создается.
+// С параметром dryRun=true файл только проверяется
+func (h *PVZHandler) ImportPVZ(c *gin.Context) {
+	if !authz.Can(c.GetString("userRole"), authz.PVZCreate) {
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbiddenCreatePVZ))
+		return
+	}
+
+	var query models.PVZImportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+
+	// Получаем и разбираем файл импорта
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxPVZImportSize)
+	fileHeader, err := c.FormFile("file")
+	if bodyTooLarge(err) {
+		response.Error(c, http.StatusRequestEntityTooLarge, i18n.T(c, i18n.MsgRequestTooLarge))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgImportFileMissing, err))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgImportFileInvalid, err))
+		return
+	}
+	defer file.Close()
+
+	rows, err := pvzimport.ParseCSV(file)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgImportFileInvalid, err))
+		return
+	}
+
+	results, err := h.validateImportRows(c, rows)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgImportPVZFailed, err))
+		return
+	}
+
+	report := models.PVZImportResponse{
+		DryRun: query.DryRun,
+		Total:  len(results),
+		Rows:   results,
+	}
+	for _, result := range results {
+		if result.Status == models.PVZImportRowError {
+			report.Failed++
+		}
+	}
+
+	if report.Failed > 0 {
+		response.JSON(c, http.StatusUnprocessableEntity, report)
+		return
+	}
+	if query.DryRun {
+		response.JSON(c, http.StatusOK, report)
+		return
+	}
+
+	// Создаем все ПВЗ одним запросом
+	pvzs := make([]models.PVZ, 0, len(results))
+	for _, result := range results {
+		pvzs = append(pvzs, models.PVZ{City: result.City, Address: result.Address})
+	}
+	created, err := h.pvzQueries.CreatePVZBatch(c.Request.Context(), pvzs)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgImportPVZFailed, err))
+		return
+	}
+
+	for i := range report.Rows {
+		report.Rows[i].Status = models.PVZImportRowCreated
+		report.Rows[i].PvzID = created[i].ID
+	}
+	report.Created = len(created)
+
+	response.JSON(c, http.StatusCreated, report)
+}
+
+// validateImportRows проверяет строки импорта: город из справочника и доступный модератору,
+// непустой адрес, не повторяющийся в файле и не занятый существующим ПВЗ.
+// Ошибка возвращается только при сбое проверки, а не для неверных строк
+func (h *PVZHandler) validateImportRows(c *gin.Context, rows []pvzimport.Row) ([]models.PVZImportRowResult, error) {
+	ctx := c.Request.Context()
+	userID := c.GetString("userID")
+
+	// Город и права модератора проверяются один раз для каждого написания города
+	resolved := map[string]string{}
+	rowErrors := map[string]i18n.Key{}
+
+	results := make([]models.PVZImportRowResult, 0, len(rows))
+	seen := map[string]bool{}
+	for _, row := range rows {
+		result := models.PVZImportRowResult{
+			Line:    row.Line,
+			City:    row.City,
+			Address: row.Address,
+			Status:  models.PVZImportRowValid,
+		}
+
+		var rowError i18n.Key
+		switch {
+		case row.City == "":
+			rowError = i18n.MsgPVZCityRequired
+		case row.Address == "":
+			rowError = i18n.MsgPVZAddressRequired
+		case utf8.RuneCountInString(row.Address) > maxPVZAddressLength:
+			rowError = i18n.MsgPVZAddressTooLong
+		}
+
+		if rowError == "" {
+			if _, ok := resolved[row.City]; !ok {
+				cityName, key, err := h.resolveImportCity(c, userID, row.City)
+				if err != nil {
+					return nil, err
+				}
+				resolved[row.City] = cityName
+				rowErrors[row.City] = key
+			}
+			result.City = resolved[row.City]
+			rowError = rowErrors[row.City]
+		}
+
+		if rowError == "" {
+			key := result.City + "\x00" + result.Address
+			if seen[key] {
+				rowError = i18n.MsgPVZAddressDuplicate
+			}
+			seen[key] = true
+		}
+
+		if rowError != "" {
+			result.Status = models.PVZImportRowError
+			result.Error = i18n.T(c, rowError)
+		}
+		results = append(results, result)
+	}
+
+	// Проверяем, что адреса не заняты существующими ПВЗ
+	var addresses []string
+	for _, result := range results {
+		if result.Status == models.PVZImportRowValid {
+			addresses = append(addresses, result.Address)
+		}
+	}
+	if len(addresses) == 0 {
+		return results, nil
+	}
+
+	existing, err := h.pvzQueries.GetPVZByAddresses(ctx, addresses)
+	if err != nil {
+		return nil, err
+	}
+	taken := make(map[string]bool, len(existing))
+	for _, pvz := range existing {
+		taken[pvz.City+"\x00"+pvz.Address] = true
+	}
+	for i, result := range results {
+		if result.Status == models.PVZImportRowValid && taken[result.City+"\x00"+result.Address] {
+			results[i].Status = models.PVZImportRowError
+			results[i].Error = i18n.T(c, i18n.MsgPVZAddressExists)
+		}
+	}
+
+	return results, nil
+}
+
+// resolveImportCity приводит город строки к названию из справочника и проверяет, что он назначен модератору.
+// Для неверного города возвращается ключ сообщения об ошибке строки
+func (h *PVZHandler) resolveImportCity(c *gin.Context, userID, cityInput string) (string, i18n.Key, error) {
+	cityName, err := h.cityResolver.Resolve(c.Request.Context(), cityInput)
+	if errors.Is(err, city.ErrUnknownCity) {
+		return cityInput, i18n.MsgUnknownCity, nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	err = h.cityAccess.CheckCity(c.Request.Context(), userID, cityName)
+	if errors.Is(err, service.ErrCityForbidden) {
+		return cityName, i18n.MsgForbiddenPVZCity, nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	return cityName, "", nil
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/models"
+	"pvz-service/internal/storage"
+)
+
+// setupPVZImportTest создает маршрут импорта ПВЗ для модератора с назначенными городами
+func setupPVZImportTest(cities moderatorCities) (*gin.Engine, *MockPVZQueries) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	pvzQueries := new(MockPVZQueries)
+	pvzHandler := NewPVZHandler(pvzQueries, new(MockReceptionQueries), new(MockProductQueries), cities, newTestCityResolver(), storage.Disabled{})
+
+	r.POST("/pvz/import", func(c *gin.Context) {
+		c.Set("userRole", "moderator")
+		c.Set("userID", testModeratorID)
+		pvzHandler.ImportPVZ(c)
+	})
+
+	return r, pvzQueries
+}
+
+const testModeratorID = "123e4567-e89b-12d3-a456-426614174050"
+
+// postPVZImport отправляет CSV-файл импорта ПВЗ
+func postPVZImport(t *testing.T, r *gin.Engine, query, content string) (*httptest.ResponseRecorder, models.PVZImportResponse) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", "pvz.csv")
+	assert.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	req, _ := http.NewRequest("POST", "/pvz/import"+query, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var report models.PVZImportResponse
+	json.Unmarshal(w.Body.Bytes(), &report)
+	return w, report
+}
+
+// TestImportPVZSuccess проверяет создание ПВЗ с приведением городов к справочнику
+func TestImportPVZSuccess(t *testing.T) {
+	r, pvzQueries := setupPVZImportTest(moderatorCities{})
+
+	pvzQueries.On("GetPVZByAddresses", mock.Anything, []string{"ул. Ленина, 1", "Невский пр., 10"}).Return([]models.PVZ{}, nil)
+	pvzQueries.On("CreatePVZBatch", mock.Anything, []models.PVZ{
+		{City: "Москва", Address: "ул. Ленина, 1"},
+		{City: "Санкт-Петербург", Address: "Невский пр., 10"},
+	}).Return([]models.PVZ{
+		{ID: "pvz-1", City: "Москва", Address: "ул. Ленина, 1"},
+		{ID: "pvz-2", City: "Санкт-Петербург", Address: "Невский пр., 10"},
+	}, nil)
+
+	w, report := postPVZImport(t, r, "", "city,address\nмск,\"ул. Ленина, 1\"\nспб,\"Невский пр., 10\"\n")
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, models.PVZImportRowCreated, report.Rows[1].Status)
+	assert.Equal(t, "pvz-2", report.Rows[1].PvzID)
+	assert.Equal(t, "Санкт-Петербург", report.Rows[1].City)
+}
+
+// TestImportPVZRowErrors проверяет отчёт по строкам: при ошибках ПВЗ не создаются
+func TestImportPVZRowErrors(t *testing.T) {
+	r, pvzQueries := setupPVZImportTest(moderatorCities{testModeratorID: {"Москва", "Казань"}})
+
+	pvzQueries.On("GetPVZByAddresses", mock.Anything, []string{"ул. Ленина, 1", "ул. Баумана, 5"}).
+		Return([]models.PVZ{{ID: "existing", City: "Казань", Address: "ул. Баумана, 5"}}, nil)
+
+	w, report := postPVZImport(t, r, "",
+		"city,address\n"+
+			"Москва,\"ул. Ленина, 1\"\n"+
+			"Москва,\"ул. Ленина, 1\"\n"+
+			"Атлантида,ул. Морская\n"+
+			"Санкт-Петербург,Невский пр.\n"+
+			"Москва,\n"+
+			"Казань,\"ул. Баумана, 5\"\n")
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, 6, report.Total)
+	assert.Equal(t, 5, report.Failed)
+	assert.Equal(t, models.PVZImportRowValid, report.Rows[0].Status)
+	for _, row := range report.Rows[1:] {
+		assert.Equal(t, models.PVZImportRowError, row.Status, "строка %d", row.Line)
+		assert.NotEmpty(t, row.Error)
+	}
+	pvzQueries.AssertNotCalled(t, "CreatePVZBatch", mock.Anything, mock.Anything)
+}
+
+// TestImportPVZDryRun проверяет, что в режиме проверки ПВЗ не создаются
+func TestImportPVZDryRun(t *testing.T) {
+	r, pvzQueries := setupPVZImportTest(moderatorCities{})
+
+	pvzQueries.On("GetPVZByAddresses", mock.Anything, []string{"ул. Ленина, 1"}).Return([]models.PVZ{}, nil)
+
+	w, report := postPVZImport(t, r, "?dryRun=true", "city,address\nМосква,\"ул. Ленина, 1\"\n")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, models.PVZImportRowValid, report.Rows[0].Status)
+	pvzQueries.AssertNotCalled(t, "CreatePVZBatch", mock.Anything, mock.Anything)
+}
+
+// TestImportPVZInvalidFile проверяет отказ для файла без нужных колонок
+func TestImportPVZInvalidFile(t *testing.T) {
+	r, _ := setupPVZImportTest(moderatorCities{})
+
+	w, _ := postPVZImport(t, r, "", "name\nПВЗ 1\n")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestImportPVZTooLarge проверяет отказ с 413 для файла больше ограничения импорта
+func TestImportPVZTooLarge(t *testing.T) {
+	r, pvzQueries := setupPVZImportTest(moderatorCities{})
+
+	w, _ := postPVZImport(t, r, "", strings.Repeat("Москва,ул. Ленина, 1\n", maxPVZImportSize/20))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	pvzQueries.AssertNotCalled(t, "CreatePVZBatch", mock.Anything, mock.Anything)
+}
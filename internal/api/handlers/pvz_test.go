@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -14,7 +15,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"pvz-service/internal/city"
+	"pvz-service/internal/db/queries"
 	"pvz-service/internal/models"
+	"pvz-service/internal/storage"
 )
 
 // MockPVZQueries мокирует запросы для работы с ПВЗ
@@ -22,25 +26,28 @@ type MockPVZQueries struct {
 	mock.Mock
 }
 
-func (m *MockReceptionQueries) CheckOpenReception(ctx context.Context, pvzID string) (bool, error) {
-	args := m.Called(ctx, pvzID)
-	return args.Bool(0), args.Error(1)
+func (m *MockReceptionQueries) CreateReception(ctx context.Context, pvzID, userID string) (*models.Reception, error) {
+	args := m.Called(ctx, pvzID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Reception), args.Error(1)
 }
 
-func (m *MockReceptionQueries) CreateReception(ctx context.Context, pvzID string) (*models.Reception, error) {
-	args := m.Called(ctx, pvzID)
+func (m *MockReceptionQueries) CloseReception(ctx context.Context, receptionID, userID string) (*models.Reception, error) {
+	args := m.Called(ctx, receptionID, userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Reception), args.Error(1)
 }
 
-func (m *MockReceptionQueries) CloseReception(ctx context.Context, receptionID string) (*models.Reception, error) {
-	args := m.Called(ctx, receptionID)
+func (m *MockReceptionQueries) CloseStaleReceptions(ctx context.Context, openedBefore time.Time) ([]models.Reception, error) {
+	args := m.Called(ctx, openedBefore)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.Reception), args.Error(1)
+	return args.Get(0).([]models.Reception), args.Error(1)
 }
 
 func (m *MockReceptionQueries) GetReceptionsByPVZ(ctx context.Context, pvzID string) ([]models.Reception, error) {
@@ -51,14 +58,107 @@ func (m *MockReceptionQueries) GetReceptionsByPVZ(ctx context.Context, pvzID str
 	return args.Get(0).([]models.Reception), args.Error(1)
 }
 
-func (m *MockPVZQueries) CreatePVZ(ctx context.Context, city string) (*models.PVZ, error) {
-	args := m.Called(ctx, city)
+func (m *MockReceptionQueries) ListReceptions(ctx context.Context, pvzID string, params models.ReceptionListQuery) ([]models.Reception, int, error) {
+	args := m.Called(ctx, pvzID, params)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]models.Reception), args.Int(1), args.Error(2)
+}
+
+func (m *MockReceptionQueries) GetLatestReceptionsByPVZ(ctx context.Context, pvzIDs []string, perPVZ int) ([]models.Reception, error) {
+	args := m.Called(ctx, pvzIDs, perPVZ)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Reception), args.Error(1)
+}
+
+func (m *MockReceptionQueries) GetReceptionByID(ctx context.Context, receptionID string) (*models.Reception, error) {
+	args := m.Called(ctx, receptionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Reception), args.Error(1)
+}
+
+func (m *MockReceptionQueries) LockReceptions(ctx context.Context, receptionIDs []string) ([]models.Reception, error) {
+	args := m.Called(ctx, receptionIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Reception), args.Error(1)
+}
+
+func (m *MockReceptionQueries) ReassignReception(ctx context.Context, receptionID, fromPvzID, toPvzID, userID string, reason *string) (*models.Reception, error) {
+	args := m.Called(ctx, receptionID, fromPvzID, toPvzID, userID, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Reception), args.Error(1)
+}
+
+func (m *MockReceptionQueries) FlagOverdueReceptions(ctx context.Context, openedBefore time.Time) ([]models.OverdueReception, error) {
+	args := m.Called(ctx, openedBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.OverdueReception), args.Error(1)
+}
+
+func (m *MockReceptionQueries) GetOverdueReceptions(ctx context.Context, pvzID string) ([]models.OverdueReception, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.OverdueReception), args.Error(1)
+}
+
+func (m *MockReceptionQueries) UpdateReceptionNotes(ctx context.Context, receptionID, authorID string, note *string, tags []string) (*models.Reception, error) {
+	args := m.Called(ctx, receptionID, authorID, note, tags)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Reception), args.Error(1)
+}
+
+func (m *MockReceptionQueries) GetReceptionNotes(ctx context.Context, receptionID string) ([]models.ReceptionNote, error) {
+	args := m.Called(ctx, receptionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ReceptionNote), args.Error(1)
+}
+
+func (m *MockReceptionQueries) GetCustodyEntries(ctx context.Context, receptionID string) ([]models.CustodyEntry, error) {
+	args := m.Called(ctx, receptionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CustodyEntry), args.Error(1)
+}
+
+func (m *MockPVZQueries) CreatePVZ(ctx context.Context, city, address, timezone string) (*models.PVZ, error) {
+	args := m.Called(ctx, city, address, timezone)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.PVZ), args.Error(1)
 }
 
+func (m *MockPVZQueries) LockPVZ(ctx context.Context, pvzID string) (*models.PVZ, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PVZ), args.Error(1)
+}
+
+func (m *MockPVZQueries) ArchivePVZ(ctx context.Context, pvzID string) (time.Time, error) {
+	args := m.Called(ctx, pvzID)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
 func (m *MockPVZQueries) GetPVZList(ctx context.Context, params models.PVZListQuery) ([]models.PVZ, int, error) {
 	args := m.Called(ctx, params)
 
@@ -70,6 +170,84 @@ func (m *MockPVZQueries) GetPVZList(ctx context.Context, params models.PVZListQu
 	return pvzList, args.Int(1), args.Error(2)
 }
 
+func (m *MockPVZQueries) GetPVZ(ctx context.Context, pvzID string) (*models.PVZ, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PVZ), args.Error(1)
+}
+
+func (m *MockPVZQueries) FlagInactivePVZ(ctx context.Context, inactiveSince time.Time) (int64, error) {
+	args := m.Called(ctx, inactiveSince)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPVZQueries) GetInactivePVZ(ctx context.Context) ([]models.InactivePVZ, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.InactivePVZ), args.Error(1)
+}
+
+func (m *MockPVZQueries) DeactivatePVZ(ctx context.Context, pvzID string) error {
+	args := m.Called(ctx, pvzID)
+	return args.Error(0)
+}
+
+func (m *MockPVZQueries) CreatePVZBatch(ctx context.Context, pvzs []models.PVZ) ([]models.PVZ, error) {
+	args := m.Called(ctx, pvzs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PVZ), args.Error(1)
+}
+
+func (m *MockPVZQueries) GetPVZByAddresses(ctx context.Context, addresses []string) ([]models.PVZ, error) {
+	args := m.Called(ctx, addresses)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PVZ), args.Error(1)
+}
+
+func (m *MockPVZQueries) SetCapacity(ctx context.Context, pvzID string, capacity *int) (*models.PVZ, error) {
+	args := m.Called(ctx, pvzID, capacity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PVZ), args.Error(1)
+}
+
+// staticCityQueries отдает фиксированный справочник городов
+type staticCityQueries []models.City
+
+func (q staticCityQueries) GetCities(ctx context.Context) ([]models.City, error) {
+	return q, nil
+}
+
+// moderatorCities отдает фиксированные назначения модераторов по городам
+type moderatorCities map[string][]string
+
+func (m moderatorCities) GetModeratorCities(ctx context.Context, userID string) ([]string, error) {
+	return m[userID], nil
+}
+
+func (m moderatorCities) SetModeratorCities(ctx context.Context, userID string, cities []string) error {
+	m[userID] = cities
+	return nil
+}
+
+// newTestCityResolver создает резолвер городов со справочником из миграций
+func newTestCityResolver() *city.Resolver {
+	return city.NewResolver(staticCityQueries{
+		{Name: "Москва", Aliases: []string{"мск", "moscow"}},
+		{Name: "Санкт-Петербург", Aliases: []string{"спб", "питер", "saint petersburg"}},
+		{Name: "Казань", Aliases: []string{"kazan"}},
+	}, city.DefaultCacheTTL)
+}
+
 // Настройка тестового окружения
 func setupPVZTest() (*gin.Engine, *MockPVZQueries, *MockReceptionQueries, *MockProductQueries) {
 	gin.SetMode(gin.TestMode)
@@ -79,7 +257,7 @@ func setupPVZTest() (*gin.Engine, *MockPVZQueries, *MockReceptionQueries, *MockP
 	receptionQueries := new(MockReceptionQueries)
 	productQueries := new(MockProductQueries)
 
-	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries)
+	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries, moderatorCities{}, newTestCityResolver(), storage.Disabled{})
 
 	// Настраиваем маршрут для создания ПВЗ
 	// В реальном приложении здесь должна быть проверка роли "moderator"
@@ -103,7 +281,7 @@ func TestCreatePVZSuccess(t *testing.T) {
 	}
 
 	// Настраиваем моки
-	pvzQueries.On("CreatePVZ", mock.Anything, "Москва").Return(testPVZ, nil)
+	pvzQueries.On("CreatePVZ", mock.Anything, "Москва", "", "").Return(testPVZ, nil)
 
 	// Создаем запрос
 	reqBody := models.CreatePVZRequest{
@@ -134,6 +312,55 @@ func TestCreatePVZSuccess(t *testing.T) {
 	pvzQueries.AssertExpectations(t)
 }
 
+// TestCreatePVZTimezone проверяет сохранение часового пояса отображения и отказ для неизвестного пояса
+func TestCreatePVZTimezone(t *testing.T) {
+	r, pvzQueries, _, _ := setupPVZTest()
+
+	timezone := "Asia/Almaty"
+	pvzQueries.On("CreatePVZ", mock.Anything, "Москва", "", timezone).
+		Return(&models.PVZ{ID: "pvz-uuid", City: "Москва", Timezone: &timezone}, nil)
+
+	req, _ := http.NewRequest("POST", "/pvz", bytes.NewBufferString(`{"city":"Москва","timezone":"Asia/Almaty"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var result models.PVZResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, timezone, result.Timezone)
+
+	req, _ = http.NewRequest("POST", "/pvz", bytes.NewBufferString(`{"city":"Москва","timezone":"Mars/Olympus"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	pvzQueries.AssertNumberOfCalls(t, "CreatePVZ", 1)
+}
+
+// TestCreatePVZCityAlias проверяет приведение варианта написания к названию из справочника
+func TestCreatePVZCityAlias(t *testing.T) {
+	for _, input := range []string{"санкт-петербург", "СПб", " Saint  Petersburg "} {
+		t.Run(input, func(t *testing.T) {
+			r, pvzQueries, _, _ := setupPVZTest()
+
+			pvzQueries.On("CreatePVZ", mock.Anything, "Санкт-Петербург", "", "").
+				Return(&models.PVZ{ID: "pvz-uuid", City: "Санкт-Петербург"}, nil)
+
+			jsonData, _ := json.Marshal(models.CreatePVZRequest{City: input})
+			req, _ := http.NewRequest("POST", "/pvz", bytes.NewBuffer(jsonData))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusCreated, w.Code)
+			pvzQueries.AssertExpectations(t)
+		})
+	}
+}
+
 // TestCreatePVZInvalidRequest проверяет случай с некорректным запросом
 func TestCreatePVZInvalidRequest(t *testing.T) {
 	r, _, _, _ := setupPVZTest()
@@ -189,7 +416,7 @@ func TestCreatePVZDatabaseError(t *testing.T) {
 	r, pvzQueries, _, _ := setupPVZTest()
 
 	// Настраиваем моки - ошибка при создании ПВЗ
-	pvzQueries.On("CreatePVZ", mock.Anything, "Москва").Return(nil, errors.New("database error"))
+	pvzQueries.On("CreatePVZ", mock.Anything, "Москва", "", "").Return(nil, errors.New("database error"))
 
 	// Создаем запрос
 	reqBody := models.CreatePVZRequest{
@@ -270,7 +497,7 @@ func TestCreatePVZForbidden(t *testing.T) {
 	receptionQueries := new(MockReceptionQueries)
 	productQueries := new(MockProductQueries)
 
-	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries)
+	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries, moderatorCities{}, newTestCityResolver(), storage.Disabled{})
 
 	// Настраиваем маршрут с ролью employee
 	r.POST("/pvz", func(c *gin.Context) {
@@ -302,7 +529,7 @@ func TestCreatePVZForbidden(t *testing.T) {
 // TestGetPVZListSuccess проверяет успешное получение списка ПВЗ
 func TestGetPVZListSuccess(t *testing.T) {
 	r, pvzQueries, receptionQueries, productQueries := setupPVZTest()
-	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries)
+	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries, moderatorCities{}, newTestCityResolver(), storage.Disabled{})
 	// Создаем тестовые данные
 	testPVZList := []models.PVZ{
 		{
@@ -320,10 +547,11 @@ func TestGetPVZListSuccess(t *testing.T) {
 	// Создаем тестовые приёмки для первого ПВЗ
 	testReceptions1 := []models.Reception{
 		{
-			ID:       "323e4567-e89b-12d3-a456-426614174000",
-			DateTime: time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC),
-			PvzID:    "123e4567-e89b-12d3-a456-426614174000",
-			Status:   "inprogress",
+			ID:           "323e4567-e89b-12d3-a456-426614174000",
+			DateTime:     time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC),
+			PvzID:        "123e4567-e89b-12d3-a456-426614174000",
+			Status:       "inprogress",
+			ProductCount: 1,
 		},
 	}
 
@@ -340,10 +568,11 @@ func TestGetPVZListSuccess(t *testing.T) {
 	// Создаем тестовые приёмки для второго ПВЗ
 	testReceptions2 := []models.Reception{
 		{
-			ID:       "523e4567-e89b-12d3-a456-426614174000",
-			DateTime: time.Date(2025, 3, 20, 10, 0, 0, 0, time.UTC),
-			PvzID:    "223e4567-e89b-12d3-a456-426614174000",
-			Status:   "close",
+			ID:           "523e4567-e89b-12d3-a456-426614174000",
+			DateTime:     time.Date(2025, 3, 20, 10, 0, 0, 0, time.UTC),
+			PvzID:        "223e4567-e89b-12d3-a456-426614174000",
+			Status:       "close",
+			ProductCount: 1,
 		},
 	}
 
@@ -359,18 +588,25 @@ func TestGetPVZListSuccess(t *testing.T) {
 
 	// Параметры запроса
 	params := models.PVZListQuery{
-		StartDate: "2025-03-01T00:00:00Z",
-		EndDate:   "2025-04-15T00:00:00Z",
-		Page:      1,
-		Limit:     10,
+		StartDate:       "2025-03-01T00:00:00Z",
+		EndDate:         "2025-04-15T00:00:00Z",
+		Page:            1,
+		Limit:           10,
+		ReceptionsLimit: 5,
 	}
 
 	// Настраиваем моки
 	pvzQueries.On("GetPVZList", mock.Anything, params).Return(testPVZList, 2, nil)
-	receptionQueries.On("GetReceptionsByPVZ", mock.Anything, "123e4567-e89b-12d3-a456-426614174000").Return(testReceptions1, nil)
-	receptionQueries.On("GetReceptionsByPVZ", mock.Anything, "223e4567-e89b-12d3-a456-426614174000").Return(testReceptions2, nil)
+	receptionQueries.On("GetLatestReceptionsByPVZ", mock.Anything,
+		[]string{"123e4567-e89b-12d3-a456-426614174000", "223e4567-e89b-12d3-a456-426614174000"}, 6).
+		Return(append(testReceptions1, testReceptions2...), nil)
 	productQueries.On("GetProductsByReception", mock.Anything, "323e4567-e89b-12d3-a456-426614174000").Return(testProducts1, nil)
 	productQueries.On("GetProductsByReception", mock.Anything, "523e4567-e89b-12d3-a456-426614174000").Return(testProducts2, nil)
+	photoURL := "https://cdn.example.com/photo.jpg"
+	productQueries.On("GetPhotosByReception", mock.Anything, "323e4567-e89b-12d3-a456-426614174000").Return([]models.ProductPhoto{
+		{ProductID: "423e4567-e89b-12d3-a456-426614174000", URL: &photoURL},
+	}, nil)
+	productQueries.On("GetPhotosByReception", mock.Anything, "523e4567-e89b-12d3-a456-426614174000").Return([]models.ProductPhoto{}, nil)
 
 	// Настраиваем маршрут для получения списка ПВЗ
 	r.GET("/pvz", func(c *gin.Context) {
@@ -416,6 +652,7 @@ func TestGetPVZListSuccess(t *testing.T) {
 	assert.Equal(t, 1, len(response[0].Receptions[0].Products))
 	assert.Equal(t, "423e4567-e89b-12d3-a456-426614174000", response[0].Receptions[0].Products[0].ID)
 	assert.Equal(t, "электроника", response[0].Receptions[0].Products[0].Type)
+	assert.Equal(t, []string{photoURL}, response[0].Receptions[0].Products[0].Photos)
 
 	// Проверяем, что моки были вызваны с правильными аргументами
 	pvzQueries.AssertExpectations(t)
@@ -426,13 +663,14 @@ func TestGetPVZListSuccess(t *testing.T) {
 // TestGetPVZListEmptyResult проверяет получение пустого списка ПВЗ
 func TestGetPVZListEmptyResult(t *testing.T) {
 	r, pvzQueries, receptionQueries, productQueries := setupPVZTest()
-	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries)
+	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries, moderatorCities{}, newTestCityResolver(), storage.Disabled{})
 	// Параметры запроса
 	params := models.PVZListQuery{
-		StartDate: "2026-01-01T00:00:00Z", // Будущая дата, когда нет ПВЗ
-		EndDate:   "2026-12-31T23:59:59Z",
-		Page:      1,
-		Limit:     10,
+		StartDate:       "2026-01-01T00:00:00Z", // Будущая дата, когда нет ПВЗ
+		EndDate:         "2026-12-31T23:59:59Z",
+		Page:            1,
+		Limit:           10,
+		ReceptionsLimit: 5,
 	}
 
 	// Настраиваем моки - пустой список
@@ -470,7 +708,7 @@ func TestGetPVZListEmptyResult(t *testing.T) {
 // TestGetPVZListPagination проверяет работу пагинации
 func TestGetPVZListPagination(t *testing.T) {
 	r, pvzQueries, receptionQueries, productQueries := setupPVZTest()
-	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries)
+	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries, moderatorCities{}, newTestCityResolver(), storage.Disabled{})
 
 	// Создаем тестовые данные - только один ПВЗ на второй странице
 	testPVZList := []models.PVZ{
@@ -486,13 +724,14 @@ func TestGetPVZListPagination(t *testing.T) {
 
 	// Параметры запроса - вторая страница
 	params := models.PVZListQuery{
-		Page:  2,
-		Limit: 1, // Один элемент на странице
+		Page:            2,
+		Limit:           1, // Один элемент на странице
+		ReceptionsLimit: 5,
 	}
 
 	// Настраиваем моки
 	pvzQueries.On("GetPVZList", mock.Anything, params).Return(testPVZList, 3, nil) // Всего 3 элемента
-	receptionQueries.On("GetReceptionsByPVZ", mock.Anything, "323e4567-e89b-12d3-a456-426614174000").Return(testReceptions, nil)
+	receptionQueries.On("GetLatestReceptionsByPVZ", mock.Anything, []string{"323e4567-e89b-12d3-a456-426614174000"}, 6).Return(testReceptions, nil)
 
 	// Настраиваем маршрут для получения списка ПВЗ
 	r.GET("/pvz", func(c *gin.Context) {
@@ -526,10 +765,191 @@ func TestGetPVZListPagination(t *testing.T) {
 	receptionQueries.AssertExpectations(t)
 }
 
+// TestGetPVZListCounters проверяет, что счетчики попадают в ответ,
+// а товары пустых приёмок не запрашиваются
+func TestGetPVZListCounters(t *testing.T) {
+	r, pvzQueries, receptionQueries, productQueries := setupPVZTest()
+	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries, moderatorCities{}, newTestCityResolver(), storage.Disabled{})
+
+	testPVZList := []models.PVZ{
+		{
+			ID:               "323e4567-e89b-12d3-a456-426614174000",
+			RegistrationDate: time.Date(2025, 2, 15, 10, 0, 0, 0, time.UTC),
+			City:             "Казань",
+			OpenReception:    true,
+		},
+	}
+	testReceptions := []models.Reception{
+		{
+			ID:       "423e4567-e89b-12d3-a456-426614174000",
+			DateTime: time.Date(2025, 2, 16, 10, 0, 0, 0, time.UTC),
+			PvzID:    "323e4567-e89b-12d3-a456-426614174000",
+			Status:   "in_progress",
+		},
+	}
+
+	params := models.PVZListQuery{Page: 1, Limit: 10, ReceptionsLimit: 5}
+	pvzQueries.On("GetPVZList", mock.Anything, params).Return(testPVZList, 1, nil)
+	receptionQueries.On("GetLatestReceptionsByPVZ", mock.Anything, []string{"323e4567-e89b-12d3-a456-426614174000"}, 6).Return(testReceptions, nil)
+
+	r.GET("/pvz", func(c *gin.Context) {
+		c.Set("userRole", "employee")
+		pvzHandler.GetPVZList(c)
+	})
+
+	req, _ := http.NewRequest("GET", "/pvz", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []models.PVZWithReceptionsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response, 1)
+	assert.True(t, response[0].PVZ.OpenReception)
+	assert.Len(t, response[0].Receptions, 1)
+	assert.Equal(t, 0, response[0].Receptions[0].Reception.ProductCount)
+	assert.Empty(t, response[0].Receptions[0].Products)
+
+	productQueries.AssertNotCalled(t, "GetProductsByReception", mock.Anything, mock.Anything)
+	productQueries.AssertNotCalled(t, "GetPhotosByReception", mock.Anything, mock.Anything)
+}
+
+// TestGetPVZListIncludeCounts проверяет счетчики приёмок ПВЗ по параметру include и отказ для неизвестного значения
+func TestGetPVZListIncludeCounts(t *testing.T) {
+	r, pvzQueries, receptionQueries, productQueries := setupPVZTest()
+	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries, moderatorCities{}, newTestCityResolver(), storage.Disabled{})
+	r.GET("/pvz", pvzHandler.GetPVZList)
+
+	receptionsCount, productsCount := 3, 42
+	lastReceptionAt := time.Date(2025, 2, 16, 10, 0, 0, 0, time.UTC)
+	testPVZList := []models.PVZ{
+		{
+			ID:              "323e4567-e89b-12d3-a456-426614174000",
+			City:            "Казань",
+			OpenReception:   true,
+			ReceptionsCount: &receptionsCount,
+			ProductsCount:   &productsCount,
+			LastReceptionAt: &lastReceptionAt,
+		},
+	}
+
+	params := models.PVZListQuery{Page: 1, Limit: 10, Include: "counts"}
+	pvzQueries.On("GetPVZList", mock.Anything, params).Return(testPVZList, 1, nil)
+	receptionQueries.On("GetLatestReceptionsByPVZ", mock.Anything, []string{"323e4567-e89b-12d3-a456-426614174000"}, 1).Return([]models.Reception{}, nil)
+
+	req, _ := http.NewRequest("GET", "/pvz?include=counts&receptionsLimit=0", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"openReception":true,"occupancy":0,"receptionsCount":3,"productsCount":42,"lastReceptionAt":"2025-02-16T10:00:00Z"`)
+
+	req, _ = http.NewRequest("GET", "/pvz?include=counts,products", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestGetPVZListReceptionsLimit проверяет ограничение вложенных приёмок и признак receptionsHasMore
+func TestGetPVZListReceptionsLimit(t *testing.T) {
+	r, pvzQueries, receptionQueries, productQueries := setupPVZTest()
+	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries, moderatorCities{}, newTestCityResolver(), storage.Disabled{})
+
+	testPVZList := []models.PVZ{
+		{ID: "123e4567-e89b-12d3-a456-426614174000", City: "Москва"},
+		{ID: "223e4567-e89b-12d3-a456-426614174000", City: "Казань"},
+	}
+	// Для лимита 2 запрашиваются 3 приёмки: у первого ПВЗ их больше лимита, у второго - одна
+	testReceptions := []models.Reception{
+		{ID: "323e4567-e89b-12d3-a456-426614174003", PvzID: testPVZList[0].ID, Status: "in_progress"},
+		{ID: "323e4567-e89b-12d3-a456-426614174002", PvzID: testPVZList[0].ID, Status: "close"},
+		{ID: "323e4567-e89b-12d3-a456-426614174001", PvzID: testPVZList[0].ID, Status: "close"},
+		{ID: "423e4567-e89b-12d3-a456-426614174000", PvzID: testPVZList[1].ID, Status: "close"},
+	}
+
+	params := models.PVZListQuery{Page: 1, Limit: 10, ReceptionsLimit: 2}
+	pvzQueries.On("GetPVZList", mock.Anything, params).Return(testPVZList, 2, nil)
+	receptionQueries.On("GetLatestReceptionsByPVZ", mock.Anything,
+		[]string{testPVZList[0].ID, testPVZList[1].ID}, 3).Return(testReceptions, nil)
+
+	r.GET("/pvz", pvzHandler.GetPVZList)
+
+	req, _ := http.NewRequest("GET", "/pvz?receptionsLimit=2", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []models.PVZWithReceptionsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response, 2)
+	assert.True(t, response[0].ReceptionsHasMore)
+	assert.Len(t, response[0].Receptions, 2)
+	assert.Equal(t, "323e4567-e89b-12d3-a456-426614174003", response[0].Receptions[0].Reception.ID)
+	assert.False(t, response[1].ReceptionsHasMore)
+	assert.Len(t, response[1].Receptions, 1)
+
+	req, _ = http.NewRequest("GET", "/pvz?receptionsLimit=51", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestGetPVZListFilters проверяет передачу фильтров по городу и статусу приёмки в запрос
+func TestGetPVZListFilters(t *testing.T) {
+	r, pvzQueries, receptionQueries, productQueries := setupPVZTest()
+	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries, moderatorCities{}, newTestCityResolver(), storage.Disabled{})
+
+	// Город из запроса приводится к названию из справочника
+	params := models.PVZListQuery{
+		City:            "Казань",
+		ReceptionStatus: "in_progress",
+		Page:            1,
+		Limit:           10,
+		ReceptionsLimit: 5,
+	}
+	pvzQueries.On("GetPVZList", mock.Anything, params).Return([]models.PVZ{}, 0, nil)
+
+	r.GET("/pvz", func(c *gin.Context) {
+		c.Set("userRole", "moderator")
+		pvzHandler.GetPVZList(c)
+	})
+
+	req, _ := http.NewRequest("GET", "/pvz?city=kazan&receptionStatus=in_progress", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	pvzQueries.AssertExpectations(t)
+}
+
+// TestGetPVZListInvalidFilters проверяет отказ при неизвестном городе или статусе приёмки
+func TestGetPVZListInvalidFilters(t *testing.T) {
+	r, pvzQueries, receptionQueries, productQueries := setupPVZTest()
+	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries, moderatorCities{}, newTestCityResolver(), storage.Disabled{})
+
+	r.GET("/pvz", func(c *gin.Context) {
+		c.Set("userRole", "moderator")
+		pvzHandler.GetPVZList(c)
+	})
+
+	for _, target := range []string{"/pvz?city=Атлантида", "/pvz?receptionStatus=on_hold"} {
+		req, _ := http.NewRequest("GET", target, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, target)
+	}
+	pvzQueries.AssertNotCalled(t, "GetPVZList", mock.Anything, mock.Anything)
+}
+
 // TestGetPVZListInvalidParams проверяет обработку некорректных параметров
 func TestGetPVZListInvalidParams(t *testing.T) {
 	r, pvzQueries, receptionQueries, productQueries := setupPVZTest()
-	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries)
+	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries, moderatorCities{}, newTestCityResolver(), storage.Disabled{})
 
 	// Параметры запроса с некорректными значениями
 	params := models.PVZListQuery{
@@ -570,12 +990,13 @@ func TestGetPVZListInvalidParams(t *testing.T) {
 // TestGetPVZListDatabaseError проверяет обработку ошибки базы данных
 func TestGetPVZListDatabaseError(t *testing.T) {
 	r, pvzQueries, receptionQueries, productQueries := setupPVZTest()
-	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries)
+	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries, moderatorCities{}, newTestCityResolver(), storage.Disabled{})
 
 	// Параметры запроса
 	params := models.PVZListQuery{
-		Page:  1,
-		Limit: 10,
+		Page:            1,
+		Limit:           10,
+		ReceptionsLimit: 5,
 	}
 
 	// Настраиваем моки - ошибка базы данных
@@ -609,7 +1030,7 @@ func TestGetPVZListDatabaseError(t *testing.T) {
 // TestGetPVZListDateFilter проверяет фильтрацию по датам
 func TestGetPVZListDateFilter(t *testing.T) {
 	r, pvzQueries, receptionQueries, productQueries := setupPVZTest()
-	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries)
+	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries, moderatorCities{}, newTestCityResolver(), storage.Disabled{})
 
 	// Создаем тестовые данные - ПВЗ в заданном диапазоне дат
 	testPVZList := []models.PVZ{
@@ -625,15 +1046,16 @@ func TestGetPVZListDateFilter(t *testing.T) {
 
 	// Параметры запроса с фильтрацией по датам
 	params := models.PVZListQuery{
-		StartDate: "2025-03-01T00:00:00Z",
-		EndDate:   "2025-03-31T23:59:59Z",
-		Page:      1,
-		Limit:     10,
+		StartDate:       "2025-03-01T00:00:00Z",
+		EndDate:         "2025-03-31T23:59:59Z",
+		Page:            1,
+		Limit:           10,
+		ReceptionsLimit: 5,
 	}
 
 	// Настраиваем моки
 	pvzQueries.On("GetPVZList", mock.Anything, params).Return(testPVZList, 1, nil)
-	receptionQueries.On("GetReceptionsByPVZ", mock.Anything, "123e4567-e89b-12d3-a456-426614174000").Return(testReceptions, nil)
+	receptionQueries.On("GetLatestReceptionsByPVZ", mock.Anything, []string{"123e4567-e89b-12d3-a456-426614174000"}, 6).Return(testReceptions, nil)
 
 	// Настраиваем маршрут для получения списка ПВЗ
 	r.GET("/pvz", func(c *gin.Context) {
@@ -666,3 +1088,163 @@ func TestGetPVZListDateFilter(t *testing.T) {
 	pvzQueries.AssertExpectations(t)
 	receptionQueries.AssertExpectations(t)
 }
+
+// TestGetInactivePVZSuccess проверяет отчёт по неактивным ПВЗ с действиями
+func TestGetInactivePVZSuccess(t *testing.T) {
+	r, pvzQueries, receptionQueries, productQueries := setupPVZTest()
+	pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries, moderatorCities{}, newTestCityResolver(), storage.Disabled{})
+
+	inactive := []models.InactivePVZ{
+		{
+			ID:               "123e4567-e89b-12d3-a456-426614174000",
+			City:             "Казань",
+			RegistrationDate: time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC),
+			LastActivityAt:   time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC),
+			FlaggedAt:        time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	pvzQueries.On("GetInactivePVZ", mock.Anything).Return(inactive, nil)
+
+	r.GET("/admin/pvz/inactive", pvzHandler.GetInactivePVZ)
+
+	req, _ := http.NewRequest("GET", "/admin/pvz/inactive", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []models.InactivePVZResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response, 1)
+	assert.Equal(t, "Казань", response[0].PVZ.City)
+	assert.Equal(t, inactive[0].LastActivityAt, response[0].LastActivityAt)
+	assert.Equal(t, "/admin/pvz/123e4567-e89b-12d3-a456-426614174000/deactivate", response[0].Actions[0].Href)
+	assert.Equal(t, http.MethodPost, response[0].Actions[0].Method)
+
+	pvzQueries.AssertExpectations(t)
+}
+
+// TestDeactivatePVZ проверяет деактивацию ПВЗ
+func TestDeactivatePVZ(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+
+	tests := []struct {
+		name       string
+		queryErr   error
+		wantStatus int
+	}{
+		{name: "Успешная деактивация", queryErr: nil, wantStatus: http.StatusNoContent},
+		{name: "ПВЗ не найден", queryErr: fmt.Errorf("active pvz: %w", queries.ErrNotFound), wantStatus: http.StatusNotFound},
+		{name: "Ошибка базы данных", queryErr: errors.New("database error"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, pvzQueries, receptionQueries, productQueries := setupPVZTest()
+			pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries, moderatorCities{}, newTestCityResolver(), storage.Disabled{})
+			pvzQueries.On("DeactivatePVZ", mock.Anything, pvzID).Return(tt.queryErr)
+
+			r.POST("/admin/pvz/:pvzId/deactivate", pvzHandler.DeactivatePVZ)
+
+			req, _ := http.NewRequest("POST", "/admin/pvz/"+pvzID+"/deactivate", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			pvzQueries.AssertExpectations(t)
+		})
+	}
+}
+
+// TestPVZModeratorCities проверяет, что модератор управляет ПВЗ только в назначенных городах
+func TestPVZModeratorCities(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	moderatorID := "123e4567-e89b-12d3-a456-426614174040"
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	pvzQueries := new(MockPVZQueries)
+	pvzHandler := NewPVZHandler(pvzQueries, new(MockReceptionQueries), new(MockProductQueries),
+		moderatorCities{moderatorID: {"Москва"}}, newTestCityResolver(), storage.Disabled{})
+
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", moderatorID)
+		c.Set("userRole", "moderator")
+		c.Next()
+	})
+	r.POST("/pvz", pvzHandler.CreatePVZ)
+	r.POST("/admin/pvz/:pvzId/deactivate", pvzHandler.DeactivatePVZ)
+
+	pvzQueries.On("CreatePVZ", mock.Anything, "Москва", "", "").Return(&models.PVZ{ID: pvzID, City: "Москва"}, nil)
+	pvzQueries.On("GetPVZ", mock.Anything, pvzID).Return(&models.PVZ{ID: pvzID, City: "Казань"}, nil)
+
+	createPVZ := func(city string) int {
+		jsonData, _ := json.Marshal(models.CreatePVZRequest{City: city})
+		req, _ := http.NewRequest("POST", "/pvz", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusCreated, createPVZ("мск"))
+	assert.Equal(t, http.StatusForbidden, createPVZ("Казань"))
+
+	req, _ := http.NewRequest("POST", "/admin/pvz/"+pvzID+"/deactivate", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	pvzQueries.AssertNotCalled(t, "DeactivatePVZ", mock.Anything, mock.Anything)
+}
+
+// TestSetPVZCapacity проверяет изменение и снятие вместимости ПВЗ
+func TestSetPVZCapacity(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	capacity := 200
+
+	tests := []struct {
+		name       string
+		body       string
+		capacity   *int
+		queryErr   error
+		wantStatus int
+	}{
+		{name: "Установка вместимости", body: `{"capacity": 200}`, capacity: &capacity, wantStatus: http.StatusOK},
+		{name: "Снятие ограничения", body: `{"capacity": null}`, capacity: nil, wantStatus: http.StatusOK},
+		{name: "ПВЗ не найден", body: `{"capacity": 200}`, capacity: &capacity, queryErr: fmt.Errorf("pvz: %w", queries.ErrNotFound), wantStatus: http.StatusNotFound},
+		{name: "Нулевая вместимость", body: `{"capacity": 0}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, pvzQueries, receptionQueries, productQueries := setupPVZTest()
+			pvzHandler := NewPVZHandler(pvzQueries, receptionQueries, productQueries, moderatorCities{}, newTestCityResolver(), storage.Disabled{})
+			if tt.wantStatus != http.StatusBadRequest {
+				var pvz *models.PVZ
+				if tt.queryErr == nil {
+					pvz = &models.PVZ{ID: pvzID, City: "Москва", Capacity: tt.capacity, Occupancy: 150}
+				}
+				pvzQueries.On("SetCapacity", mock.Anything, pvzID, tt.capacity).Return(pvz, tt.queryErr)
+			}
+
+			r.PUT("/pvz/:pvzId/capacity", pvzHandler.SetCapacity)
+
+			req, _ := http.NewRequest("PUT", "/pvz/"+pvzID+"/capacity", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				var response models.PVZResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.capacity, response.Capacity)
+				assert.Equal(t, 150, response.Occupancy)
+			}
+			pvzQueries.AssertExpectations(t)
+		})
+	}
+}
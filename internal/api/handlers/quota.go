@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"time"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/authz"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+	"pvz-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// QuotaHandler содержит обработчики управления квотами операций по ролям и исключениями из них
+type QuotaHandler struct {
+	quotaQueries queries.QuotaQueriesInterface
+}
+
+// NewQuotaHandler создает новый экземпляр QuotaHandler
+func NewQuotaHandler(quotaQueries queries.QuotaQueriesInterface) *QuotaHandler {
+	return &QuotaHandler{
+		quotaQueries: quotaQueries,
+	}
+}
+
+// GetQuotas обрабатывает запрос на получение квот всех ролей
+func (h *QuotaHandler) GetQuotas(c *gin.Context) {
+	quotas, err := h.quotaQueries.GetQuotas(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetQuotasFailed, err))
+		return
+	}
+
+	if quotas == nil {
+		quotas = []models.OperationQuota{}
+	}
+
+	response.JSON(c, http.StatusOK, quotas)
+}
+
+// SetQuota обрабатывает запрос на установку квоты операции для роли
+func (h *QuotaHandler) SetQuota(c *gin.Context) {
+	role, operation := c.Param("role"), c.Param("operation")
+	if !slices.Contains(authz.Roles(), role) || !slices.Contains(models.QuotaOperations, operation) {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgInvalidRequest))
+		return
+	}
+
+	var req models.SetOperationQuotaRequest
+
+	// Проверяем запрос
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	quota, err := h.quotaQueries.UpsertQuota(c.Request.Context(), models.OperationQuota{
+		Role:       role,
+		Operation:  operation,
+		MaxPerHour: req.MaxPerHour,
+		Comment:    req.Comment,
+	})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgSetQuotaFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, quota)
+}
+
+// DeleteQuota обрабатывает запрос на снятие квоты операции для роли
+func (h *QuotaHandler) DeleteQuota(c *gin.Context) {
+	err := h.quotaQueries.DeleteQuota(c.Request.Context(), c.Param("role"), c.Param("operation"))
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgQuotaNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgDeleteQuotaFailed, err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetOverrides обрабатывает запрос на получение действующих исключений из квот
+func (h *QuotaHandler) GetOverrides(c *gin.Context) {
+	overrides, err := h.quotaQueries.GetOverrides(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetQuotasFailed, err))
+		return
+	}
+
+	if overrides == nil {
+		overrides = []models.QuotaOverride{}
+	}
+
+	response.JSON(c, http.StatusOK, overrides)
+}
+
+// SetOverride обрабатывает запрос администратора на временное исключение из квоты для пользователя,
+// например на разбор ошибочной приёмки. Исключение заменяет квоту роли до expiresAt
+func (h *QuotaHandler) SetOverride(c *gin.Context) {
+	userID, operation := c.Param("userId"), c.Param("operation")
+	if _, err := uuid.Parse(userID); err != nil || !slices.Contains(models.QuotaOperations, operation) {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgInvalidRequest))
+		return
+	}
+
+	var req models.SetQuotaOverrideRequest
+
+	// Проверяем запрос
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+	if !req.ExpiresAt.After(time.Now()) {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, errors.New("expiresAt must be in the future")))
+		return
+	}
+
+	var createdBy *string
+	if adminID := c.GetString("userID"); adminID != "" {
+		createdBy = &adminID
+	}
+
+	override, err := h.quotaQueries.UpsertOverride(c.Request.Context(), models.QuotaOverride{
+		UserID:     userID,
+		Operation:  operation,
+		MaxPerHour: req.MaxPerHour,
+		ExpiresAt:  req.ExpiresAt,
+		Comment:    req.Comment,
+		CreatedBy:  createdBy,
+	})
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgUserNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgSetQuotaFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, override)
+}
+
+// DeleteOverride обрабатывает запрос на отмену исключения из квоты до истечения его срока
+func (h *QuotaHandler) DeleteOverride(c *gin.Context) {
+	if _, err := uuid.Parse(c.Param("userId")); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgInvalidRequest))
+		return
+	}
+
+	err := h.quotaQueries.DeleteOverride(c.Request.Context(), c.Param("userId"), c.Param("operation"))
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgQuotaOverrideNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgDeleteQuotaFailed, err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondQuotaExceeded отвечает 429 на операцию сверх квоты. Retry-After - время до сброса счетчика
+func respondQuotaExceeded(c *gin.Context, err *service.QuotaExceededError) {
+	retryAfter := math.Max(1, math.Ceil(err.RetryAfter(time.Now()).Seconds()))
+	c.Header("Retry-After", fmt.Sprintf("%.0f", retryAfter))
+	response.ErrorWithDetails(c, http.StatusTooManyRequests, i18n.T(c, i18n.MsgQuotaExceeded), err.Exceeded)
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/service"
+	"pvz-service/internal/storage"
+)
+
+// exhaustedQuotas - квота всех операций исчерпана до конца часа
+type exhaustedQuotas struct{}
+
+func (exhaustedQuotas) Consume(ctx context.Context, userID, role, operation string, count int) error {
+	return &service.QuotaExceededError{Exceeded: models.QuotaExceeded{
+		Operation:    operation,
+		Limit:        200,
+		Requested:    count,
+		WindowResets: time.Now().Add(10 * time.Minute),
+	}}
+}
+
+// TestDeleteLastProductsQuotaExceeded проверяет отказ 429 без удаления товаров при исчерпанной квоте
+func TestDeleteLastProductsQuotaExceeded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	productQueries := new(MockProductQueries)
+	receptionQueries := new(MockReceptionQueries)
+	outbox := &recordingOutbox{}
+	handler := newTestProductHandler(productQueries, receptionQueries, outbox, storage.Disabled{}, productLimits{}, alwaysOpen{}, exhaustedQuotas{}, noLocks{})
+	r.POST("/pvz/:pvzId/delete_last_products", func(c *gin.Context) {
+		c.Set("userRole", models.RoleEmployee)
+		c.Set("userID", "user-1")
+		handler.DeleteLastProducts(c)
+	})
+
+	reception := models.Reception{ID: testReceptionID, PvzID: testPvzID, Status: "in_progress"}
+	receptionQueries.On("GetLastOpenReception", mock.Anything, testPvzID).Return(&reception, nil)
+	receptionQueries.On("LockReceptions", mock.Anything, []string{testReceptionID}).Return([]models.Reception{reception}, nil)
+	productQueries.On("GetLastProductsFromReception", mock.Anything, testReceptionID, 2).Return([]models.Product{
+		{ID: "p2", ReceptionID: testReceptionID},
+		{ID: "p1", ReceptionID: testReceptionID},
+	}, nil)
+
+	req, _ := http.NewRequest("POST", "/pvz/"+testPvzID+"/delete_last_products?count=2", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.InDelta(t, 600, retryAfter, 5)
+
+	var body struct {
+		Details models.QuotaExceeded `json:"details"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, models.QuotaOperationProductDelete, body.Details.Operation)
+	assert.Equal(t, 2, body.Details.Requested)
+
+	productQueries.AssertNotCalled(t, "DeleteProduct", mock.Anything, mock.Anything, mock.Anything)
+	assert.Empty(t, outbox.events)
+}
+
+// TestCreateReceptionQuotaExceeded проверяет, что приёмка сверх квоты не создается
+func TestCreateReceptionQuotaExceeded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	receptionQueries := new(MockReceptionQueries)
+	handler := NewReceptionHandler(receptionQueries, new(MockProductQueries), new(MockManifestQueries), nil, nil, passthroughTx{}, &recordingOutbox{}, alwaysOpen{}, nil, exhaustedQuotas{}, noLocks{})
+	r.POST("/receptions", func(c *gin.Context) {
+		c.Set("userRole", models.RoleEmployee)
+		c.Set("userID", "user-1")
+		handler.CreateReception(c)
+	})
+
+	w := postJSON(r, "/receptions", models.CreateReceptionRequest{PvzID: testPvzID})
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	receptionQueries.AssertNotCalled(t, "CreateReception", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// overrideStore запоминает сохраненные исключения из квот
+type overrideStore struct {
+	queries.QuotaQueriesInterface
+	saved []models.QuotaOverride
+}
+
+func (s *overrideStore) UpsertOverride(ctx context.Context, override models.QuotaOverride) (*models.QuotaOverride, error) {
+	s.saved = append(s.saved, override)
+	return &override, nil
+}
+
+// TestSetQuotaOverride проверяет выдачу исключения из квоты и проверку запроса
+func TestSetQuotaOverride(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	store := &overrideStore{}
+	handler := NewQuotaHandler(store)
+	r.PUT("/admin/quota-overrides/:userId/:operation", func(c *gin.Context) {
+		c.Set("userID", "admin-1")
+		handler.SetOverride(c)
+	})
+
+	userID := "523e4567-e89b-12d3-a456-426614174000"
+	put := func(path string, body interface{}) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest("PUT", path, bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	future := models.SetQuotaOverrideRequest{ExpiresAt: time.Now().Add(2 * time.Hour), Comment: "Разбор ошибочной приёмки"}
+
+	w := put("/admin/quota-overrides/"+userID+"/product.delete", future)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, store.saved, 1)
+	assert.Nil(t, store.saved[0].MaxPerHour)
+	assert.Equal(t, "admin-1", *store.saved[0].CreatedBy)
+
+	// Неизвестная операция, неверный ID пользователя и истекший срок отклоняются
+	assert.Equal(t, http.StatusBadRequest, put("/admin/quota-overrides/"+userID+"/product.wipe", future).Code)
+	assert.Equal(t, http.StatusBadRequest, put("/admin/quota-overrides/user-1/product.delete", future).Code)
+	past := models.SetQuotaOverrideRequest{ExpiresAt: time.Now().Add(-time.Minute)}
+	assert.Equal(t, http.StatusBadRequest, put("/admin/quota-overrides/"+userID+"/product.delete", past).Code)
+	assert.Len(t, store.saved, 1)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+	"pvz-service/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitHandler содержит обработчики управления индивидуальными лимитами запросов
+type RateLimitHandler struct {
+	rateLimitQueries queries.RateLimitQueriesInterface
+	overrides        ratelimit.OverridesInterface
+}
+
+// NewRateLimitHandler создает новый экземпляр RateLimitHandler
+func NewRateLimitHandler(rateLimitQueries queries.RateLimitQueriesInterface, overrides ratelimit.OverridesInterface) *RateLimitHandler {
+	return &RateLimitHandler{
+		rateLimitQueries: rateLimitQueries,
+		overrides:        overrides,
+	}
+}
+
+// GetOverrides обрабатывает запрос на получение всех индивидуальных лимитов
+func (h *RateLimitHandler) GetOverrides(c *gin.Context) {
+	overrides, err := h.rateLimitQueries.GetOverrides(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetRateLimitsFailed, err))
+		return
+	}
+
+	if overrides == nil {
+		overrides = []models.RateLimitOverride{}
+	}
+
+	response.JSON(c, http.StatusOK, overrides)
+}
+
+// SetOverride обрабатывает запрос на установку индивидуального лимита для ключа
+func (h *RateLimitHandler) SetOverride(c *gin.Context) {
+	var req models.SetRateLimitOverrideRequest
+
+	// Проверяем запрос
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	override, err := h.rateLimitQueries.UpsertOverride(c.Request.Context(), models.RateLimitOverride{
+		Key:               c.Param("key"),
+		RequestsPerMinute: req.RequestsPerMinute,
+		Burst:             req.Burst,
+		Comment:           req.Comment,
+	})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgSetRateLimitFailed, err))
+		return
+	}
+
+	h.overrides.Invalidate()
+
+	response.JSON(c, http.StatusOK, override)
+}
+
+// DeleteOverride обрабатывает запрос на удаление индивидуального лимита
+func (h *RateLimitHandler) DeleteOverride(c *gin.Context) {
+	err := h.rateLimitQueries.DeleteOverride(c.Request.Context(), c.Param("key"))
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgRateLimitNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgDeleteRateLimitFailed, err))
+		return
+	}
+
+	h.overrides.Invalidate()
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/ratelimit"
+)
+
+// MockRateLimitQueries мокирует запросы для работы с индивидуальными лимитами
+type MockRateLimitQueries struct {
+	mock.Mock
+}
+
+func (m *MockRateLimitQueries) GetOverrides(ctx context.Context) ([]models.RateLimitOverride, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RateLimitOverride), args.Error(1)
+}
+
+func (m *MockRateLimitQueries) UpsertOverride(ctx context.Context, override models.RateLimitOverride) (*models.RateLimitOverride, error) {
+	args := m.Called(ctx, override)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RateLimitOverride), args.Error(1)
+}
+
+func (m *MockRateLimitQueries) DeleteOverride(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+// MockRateLimitOverrides мокирует кэш индивидуальных лимитов
+type MockRateLimitOverrides struct {
+	mock.Mock
+}
+
+func (m *MockRateLimitOverrides) Lookup(ctx context.Context, key string) (ratelimit.Limit, bool) {
+	args := m.Called(ctx, key)
+	return args.Get(0).(ratelimit.Limit), args.Bool(1)
+}
+
+func (m *MockRateLimitOverrides) Invalidate() {
+	m.Called()
+}
+
+// Настройка тестового окружения
+func setupRateLimitHandlerTest() (*gin.Engine, *MockRateLimitQueries, *MockRateLimitOverrides) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	rateLimitQueries := new(MockRateLimitQueries)
+	overrides := new(MockRateLimitOverrides)
+	rateLimitHandler := NewRateLimitHandler(rateLimitQueries, overrides)
+
+	r.GET("/admin/rate-limits", rateLimitHandler.GetOverrides)
+	r.PUT("/admin/rate-limits/:key", rateLimitHandler.SetOverride)
+	r.DELETE("/admin/rate-limits/:key", rateLimitHandler.DeleteOverride)
+
+	return r, rateLimitQueries, overrides
+}
+
+// TestSetRateLimitOverride проверяет установку лимита и сброс кэша
+func TestSetRateLimitOverride(t *testing.T) {
+	r, rateLimitQueries, overrides := setupRateLimitHandlerTest()
+
+	expected := models.RateLimitOverride{Key: "user:tablet", RequestsPerMinute: 1200, Burst: 200, Comment: "планшет ПВЗ"}
+	rateLimitQueries.On("UpsertOverride", mock.Anything, expected).Return(&expected, nil)
+	overrides.On("Invalidate").Return()
+
+	jsonData, _ := json.Marshal(models.SetRateLimitOverrideRequest{RequestsPerMinute: 1200, Burst: 200, Comment: "планшет ПВЗ"})
+	req, _ := http.NewRequest("PUT", "/admin/rate-limits/user:tablet", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	rateLimitQueries.AssertExpectations(t)
+	overrides.AssertExpectations(t)
+}
+
+// TestSetRateLimitOverrideInvalid проверяет валидацию лимита
+func TestSetRateLimitOverrideInvalid(t *testing.T) {
+	r, rateLimitQueries, _ := setupRateLimitHandlerTest()
+
+	req, _ := http.NewRequest("PUT", "/admin/rate-limits/user:tablet", bytes.NewBufferString(`{"requestsPerMinute": 0, "burst": 10}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	rateLimitQueries.AssertNotCalled(t, "UpsertOverride", mock.Anything, mock.Anything)
+}
+
+// TestDeleteRateLimitOverride проверяет удаление лимита
+func TestDeleteRateLimitOverride(t *testing.T) {
+	r, rateLimitQueries, overrides := setupRateLimitHandlerTest()
+
+	rateLimitQueries.On("DeleteOverride", mock.Anything, "user:tablet").Return(nil)
+	rateLimitQueries.On("DeleteOverride", mock.Anything, "user:missing").Return(queries.ErrNotFound)
+	overrides.On("Invalidate").Return()
+
+	req, _ := http.NewRequest("DELETE", "/admin/rate-limits/user:tablet", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	req, _ = http.NewRequest("DELETE", "/admin/rate-limits/user:missing", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	overrides.AssertNumberOfCalls(t, "Invalidate", 1)
+}
+
+// TestGetRateLimitOverridesError проверяет ошибку базы данных
+func TestGetRateLimitOverridesError(t *testing.T) {
+	r, rateLimitQueries, _ := setupRateLimitHandlerTest()
+
+	rateLimitQueries.On("GetOverrides", mock.Anything).Return(nil, errors.New("database error"))
+
+	req, _ := http.NewRequest("GET", "/admin/rate-limits", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -1,116 +1,794 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"net/http"
+	"strings"
+	"time"
 
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/authz"
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db"
 	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/manifest"
+	"pvz-service/internal/mapper"
 	"pvz-service/internal/models"
+	"pvz-service/internal/publiclink"
+	"pvz-service/internal/service"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
+// maxManifestSize ограничивает размер загружаемой накладной
+const maxManifestSize = 10 << 20
+
+// errCloseConfirmationRequired прерывает транзакцию закрытия приёмки с расхождениями без force
+var errCloseConfirmationRequired = errors.New("close confirmation required")
+
+// defaultStaleReceptionAge - возраст, после которого открытая приёмка считается зависшей, по умолчанию
+const defaultStaleReceptionAge = 24 * time.Hour
+
 // ReceptionHandler содержит обработчики для работы с приёмками товаров
 type ReceptionHandler struct {
 	receptionQueries queries.ReceptionQueriesInterface
+	productQueries   queries.ProductQueriesInterface
+	manifestQueries  queries.ManifestQueriesInterface
+	templates        queries.ReceptionTemplateQueriesInterface
+	suppliers        queries.SupplierQueriesInterface
+	tx               db.Transactor
+	outboxQueries    queries.OutboxQueriesInterface
+	hours            service.WorkingHoursChecker
+	publicLinks      *publiclink.Signer
+	quotas           service.QuotaChecker
+	locks            service.Locker
+	clock            clock.Clock
 }
 
-// NewReceptionHandler создает новый экземпляр ReceptionHandler
-func NewReceptionHandler(receptionQueries queries.ReceptionQueriesInterface) *ReceptionHandler {
+// NewReceptionHandler создает новый экземпляр ReceptionHandler.
+// События о приёмках записываются в outbox в одной транзакции с изменением.
+// Приёмка создается только в часы работы ПВЗ, которые проверяет hours.
+// С publicLinks в ответ на создание приёмки добавляется токен публичной ссылки на ее статус.
+// Из шаблонов templates приёмка создается с накладной, вместимостью и заметкой шаблона,
+// поставщик приёмки проверяется по справочнику suppliers. Создание приёмки расходует квоту quotas
+// и выполняется под блокировкой ПВЗ locks, общей для всех экземпляров сервиса
+func NewReceptionHandler(receptionQueries queries.ReceptionQueriesInterface, productQueries queries.ProductQueriesInterface, manifestQueries queries.ManifestQueriesInterface, templates queries.ReceptionTemplateQueriesInterface, suppliers queries.SupplierQueriesInterface, tx db.Transactor, outboxQueries queries.OutboxQueriesInterface, hours service.WorkingHoursChecker, publicLinks *publiclink.Signer, quotas service.QuotaChecker, locks service.Locker) *ReceptionHandler {
 	return &ReceptionHandler{
 		receptionQueries: receptionQueries,
+		productQueries:   productQueries,
+		manifestQueries:  manifestQueries,
+		templates:        templates,
+		suppliers:        suppliers,
+		tx:               tx,
+		outboxQueries:    outboxQueries,
+		hours:            hours,
+		publicLinks:      publicLinks,
+		quotas:           quotas,
+		locks:            locks,
+		clock:            clock.System{},
 	}
 }
 
-// CreateReception обрабатывает запрос на создание приёмки товаров
+// errTemplateDeleted возвращается, если шаблон удалили, пока по нему создавалась приёмка
+var errTemplateDeleted = errors.New("reception template deleted")
+
+// errSupplierDeleted возвращается, если поставщика удалили, пока создавалась его приёмка
+var errSupplierDeleted = errors.New("supplier deleted")
+
+// CreateReception обрабатывает запрос на создание приёмки товаров. С templateId в приёмку
+// копируются строки шаблона как накладная, вместимость и заметка шаблона, supplierId указывает поставщика
 func (h *ReceptionHandler) CreateReception(c *gin.Context) {
-	// Проверяем, что пользователь - сотрудник
-	userRole, _ := c.Get("userRole")
-	if userRole != "employee" {
-		c.JSON(http.StatusForbidden, models.ErrorResponse{
-			Message: "Доступ запрещен: только сотрудники могут создавать приёмки",
-		})
+	// Проверяем право роли пользователя на действие
+	if !authz.Can(c.GetString("userRole"), authz.ReceptionWrite) {
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbiddenCreateReception))
 		return
 	}
 
 	var req models.CreateReceptionRequest
+	var query models.CreateReceptionQuery
 
 	// Проверяем запрос
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Message: "Неверный запрос: " + err.Error(),
-		})
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+	if denyPVZAccess(c, req.PvzID) {
 		return
 	}
 
-	// Проверяем, есть ли уже открытая приёмка для этого ПВЗ
-	hasOpen, err := h.receptionQueries.CheckOpenReception(c.Request.Context(), req.PvzID)
+	// Шаблон проверяется до создания, чтобы по неизвестному шаблону не открыть пустую приёмку
+	var template *models.ReceptionTemplate
+	if query.TemplateID != "" {
+		var err error
+		template, err = h.templates.GetTemplate(c.Request.Context(), query.TemplateID)
+		if errors.Is(err, queries.ErrNotFound) {
+			response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgTemplateNotFound))
+			return
+		}
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetTemplatesFailed, err))
+			return
+		}
+	}
+
+	// Поставщик проверяется по справочнику до создания приёмки
+	if req.SupplierID != "" {
+		_, err := h.suppliers.GetSupplier(c.Request.Context(), req.SupplierID)
+		if errors.Is(err, queries.ErrNotFound) {
+			response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgSupplierNotFound))
+			return
+		}
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetSuppliersFailed, err))
+			return
+		}
+	}
+
+	// Приёмка вне часов работы ПВЗ запрещена, если модератор не снял ограничение
+	err := h.hours.CheckOpen(c.Request.Context(), req.PvzID)
+	if errors.Is(err, service.ErrOutsideWorkingHours) {
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgOutsideWorkingHours))
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Message: "Ошибка при проверке открытых приёмок: " + err.Error(),
-		})
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCheckWorkingHoursFailed, err))
 		return
 	}
 
-	if hasOpen {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Message: "Для данного ПВЗ уже есть незакрытая приёмка",
-		})
+	// Создаем приёмку и событие о ней в одной транзакции. Вторую открытую приёмку ПВЗ
+	// не дает создать уникальный индекс, поэтому отдельная проверка перед созданием не нужна.
+	// Блокировка ПВЗ ставит в очередь параллельные создания с разных экземпляров сервиса:
+	// второй запрос получает 409 с уже открытой приёмкой, а не ошибку посреди применения шаблона
+	var result models.CreateReceptionResponse
+	err = h.tx.InTx(c.Request.Context(), func(ctx context.Context) error {
+		if err := h.locks.Lock(ctx, service.PVZLockKey(req.PvzID)); err != nil {
+			return err
+		}
+		if err := h.quotas.Consume(ctx, c.GetString("userID"), c.GetString("userRole"), models.QuotaOperationReceptionCreate, 1); err != nil {
+			return err
+		}
+
+		reception, err := h.receptionQueries.CreateReception(ctx, req.PvzID, c.GetString("userID"))
+		if err != nil {
+			return err
+		}
+
+		if template != nil {
+			reception, err = h.templates.ApplyTemplate(ctx, reception.ID, template.ID)
+			if errors.Is(err, queries.ErrNotFound) {
+				return errTemplateDeleted
+			}
+			if err != nil {
+				return err
+			}
+
+			// Заметка шаблона записывается в историю заметок от имени создавшего приёмку
+			if template.Note != "" {
+				reception, err = h.receptionQueries.UpdateReceptionNotes(ctx, reception.ID, c.GetString("userID"), &template.Note, nil)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		if req.SupplierID != "" {
+			reception, err = h.suppliers.SetReceptionSupplier(ctx, reception.ID, req.SupplierID)
+			if errors.Is(err, queries.ErrNotFound) {
+				return errSupplierDeleted
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		result.ReceptionResponse = mapper.Reception(*reception)
+		return h.outboxQueries.AddEvent(ctx, reception.PvzID, models.EventReceptionCreated, result.ReceptionResponse)
+	})
+	var openErr *queries.ReceptionOpenError
+	if errors.As(err, &openErr) {
+		response.ErrorWithDetails(c, http.StatusConflict, i18n.T(c, i18n.MsgReceptionAlreadyOpen), mapper.Reception(*openErr.Reception))
+		return
+	}
+	var quotaErr *service.QuotaExceededError
+	if errors.As(err, &quotaErr) {
+		respondQuotaExceeded(c, quotaErr)
+		return
+	}
+	if errors.Is(err, service.ErrLockTimeout) {
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgPVZBusy))
+		return
+	}
+	if errors.Is(err, errTemplateDeleted) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgTemplateNotFound))
+		return
+	}
+	if errors.Is(err, errSupplierDeleted) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgSupplierNotFound))
+		return
+	}
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgPVZNotFound))
 		return
 	}
-
-	// Создаем приёмку
-	reception, err := h.receptionQueries.CreateReception(c.Request.Context(), req.PvzID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Message: "Ошибка при создании приёмки: " + err.Error(),
-		})
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCreateReceptionFailed, err))
 		return
 	}
 
+	// Ссылку на статус приёмки сотрудник передает поставщику, сдавшему груз
+	if h.publicLinks != nil {
+		receptionID, err := uuid.Parse(result.ID)
+		if err == nil {
+			result.PublicToken = h.publicLinks.Sign(receptionID)
+		}
+	}
+
 	// Возвращаем данные созданной приёмки
-	c.JSON(http.StatusCreated, models.ReceptionResponse{
-		ID:       reception.ID,
-		DateTime: reception.DateTime,
-		PvzID:    reception.PvzID,
-		Status:   reception.Status,
-	})
+	response.JSON(c, http.StatusCreated, result)
 }
 
-// CloseLastReception обрабатывает запрос на закрытие последней открытой приёмки товаров
+// CloseLastReception обрабатывает запрос на закрытие последней открытой приёмки товаров.
+// Приёмка с расхождениями по накладной не закрывается молча: без force=true возвращается 409
+// с расхождениями, а закрытие с force=true требует причину, которая записывается в журнал
 func (h *ReceptionHandler) CloseLastReception(c *gin.Context) {
 	pvzID := c.Param("pvzId")
 
 	// Проверяем, что pvzId указан
 	if pvzID == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Message: "Не указан ID ПВЗ",
-		})
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgPVZIDRequired))
+		return
+	}
+
+	var query models.CloseReceptionQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+	// Причина из одних пробелов не объясняет расхождения
+	query.Reason = strings.TrimSpace(query.Reason)
+	if query.Force && query.Reason == "" {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgForceReasonRequired))
 		return
 	}
 
 	// Получаем последнюю открытую приёмку
 	reception, err := h.receptionQueries.GetLastOpenReception(c.Request.Context(), pvzID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Message: "Ошибка при получении приёмки: " + err.Error(),
-		})
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgGetReceptionFailed, err))
+		return
+	}
+
+	// Сверяем приёмку с накладной, закрываем ее и записываем событие о закрытии в одной транзакции.
+	// Сверка идет под блокировкой приёмки, чтобы параллельно добавленные или удаленные товары
+	// не сделали расхождения в ответе и журнале устаревшими
+	var (
+		result        models.CloseReceptionResponse
+		discrepancies *models.ManifestDiscrepancies
+		compareErr    error
+	)
+	err = h.tx.InTx(c.Request.Context(), func(ctx context.Context) error {
+		// Приёмку могли закрыть или приостановить параллельным запросом
+		locked, err := h.receptionQueries.LockReceptions(ctx, []string{reception.ID})
+		if err != nil {
+			return err
+		}
+		if len(locked) == 0 || locked[0].Status != "in_progress" {
+			return queries.ErrNotFound
+		}
+
+		discrepancies, compareErr = h.compareWithManifest(ctx, reception.ID)
+		if compareErr != nil {
+			return compareErr
+		}
+		forced := discrepancies != nil && discrepancies.HasAny()
+		if forced && !query.Force {
+			return errCloseConfirmationRequired
+		}
+
+		closedReception, err := h.receptionQueries.CloseReception(ctx, reception.ID, c.GetString("userID"))
+		if err != nil {
+			return err
+		}
+
+		// Поврежденные товары читаются после закрытия: отметить товар закрытой приёмки уже нельзя
+		damaged, err := h.productQueries.GetReceptionDamages(ctx, reception.ID)
+		if err != nil {
+			return err
+		}
+
+		result = models.CloseReceptionResponse{
+			ReceptionResponse: mapper.Reception(*closedReception),
+			Discrepancies:     discrepancies,
+			Damaged:           damaged,
+		}
+		if forced {
+			if err := h.manifestQueries.RecordForcedClose(ctx, reception.ID, c.GetString("userID"), query.Reason, discrepancies); err != nil {
+				return err
+			}
+			result.ForceReason = query.Reason
+		}
+		return h.outboxQueries.AddEvent(ctx, pvzID, models.EventReceptionClosed, result)
+	})
+	switch {
+	case errors.Is(err, errCloseConfirmationRequired):
+		response.ErrorWithDetails(c, http.StatusConflict, i18n.T(c, i18n.MsgCloseConfirmationRequired), discrepancies)
+	case errors.Is(err, queries.ErrNotFound):
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgNoOpenReception))
+	case compareErr != nil:
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgManifestCompareFailed, err))
+	case err != nil:
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCloseReceptionFailed, err))
+	default:
+		// Возвращаем данные закрытой приёмки
+		response.JSON(c, http.StatusOK, result)
+	}
+}
+
+// PauseReception обрабатывает запрос на приостановку открытой приёмки ПВЗ, например на обед или пересменку.
+// Пока приёмка на паузе, товары в нее не принимаются, а в ПВЗ можно открыть новую приёмку
+func (h *ReceptionHandler) PauseReception(c *gin.Context) {
+	if !authz.Can(c.GetString("userRole"), authz.ReceptionWrite) {
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbiddenPauseReception))
+		return
+	}
+
+	pvzID := c.Param("pvzId")
+
+	var result models.ReceptionResponse
+	err := h.tx.InTx(c.Request.Context(), func(ctx context.Context) error {
+		reception, err := h.receptionQueries.PauseReception(ctx, pvzID)
+		if err != nil {
+			return err
+		}
+
+		result = mapper.Reception(*reception)
+		return h.outboxQueries.AddEvent(ctx, pvzID, models.EventReceptionPaused, result)
+	})
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgNoOpenReception))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgPauseReceptionFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, result)
+}
+
+// ResumeReception обрабатывает запрос на возобновление последней приостановленной приёмки ПВЗ.
+// Если за время паузы в ПВЗ открыли другую приёмку, возвращается 409 с ней в details
+func (h *ReceptionHandler) ResumeReception(c *gin.Context) {
+	if !authz.Can(c.GetString("userRole"), authz.ReceptionWrite) {
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbiddenPauseReception))
+		return
+	}
+
+	pvzID := c.Param("pvzId")
+
+	// Возобновить приёмку, как и открыть новую, можно только в часы работы ПВЗ
+	err := h.hours.CheckOpen(c.Request.Context(), pvzID)
+	if errors.Is(err, service.ErrOutsideWorkingHours) {
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgOutsideWorkingHours))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCheckWorkingHoursFailed, err))
+		return
+	}
+
+	var result models.ReceptionResponse
+	err = h.tx.InTx(c.Request.Context(), func(ctx context.Context) error {
+		reception, err := h.receptionQueries.ResumeReception(ctx, pvzID)
+		if err != nil {
+			return err
+		}
+
+		result = mapper.Reception(*reception)
+		return h.outboxQueries.AddEvent(ctx, pvzID, models.EventReceptionResumed, result)
+	})
+	var openErr *queries.ReceptionOpenError
+	if errors.As(err, &openErr) {
+		response.ErrorWithDetails(c, http.StatusConflict, i18n.T(c, i18n.MsgReceptionAlreadyOpen), mapper.Reception(*openErr.Reception))
+		return
+	}
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgNoPausedReception))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgResumeReceptionFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, result)
+}
+
+// CloseStaleReceptions обрабатывает запрос модератора на закрытие всех открытых приёмок старше olderThan,
+// например оставленных тестовыми сценариями. О каждой закрытой приёмке записывается событие
+func (h *ReceptionHandler) CloseStaleReceptions(c *gin.Context) {
+	var query models.CloseStaleReceptionsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
 		return
 	}
 
-	// Закрываем приёмку
-	closedReception, err := h.receptionQueries.CloseReception(c.Request.Context(), reception.ID)
+	olderThan := defaultStaleReceptionAge
+	if query.OlderThan != "" {
+		parsed, err := time.ParseDuration(query.OlderThan)
+		if err != nil || parsed <= 0 {
+			response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgInvalidStaleThreshold))
+			return
+		}
+		olderThan = parsed
+	}
+
+	// Закрываем приёмки и записываем события о закрытии в одной транзакции
+	result := models.CloseStaleReceptionsResponse{ReceptionIDs: []string{}}
+	err := h.tx.InTx(c.Request.Context(), func(ctx context.Context) error {
+		closed, err := h.receptionQueries.CloseStaleReceptions(ctx, h.clock.Now().Add(-olderThan))
+		if err != nil {
+			return err
+		}
+
+		for _, reception := range closed {
+			event := models.CloseReceptionResponse{ReceptionResponse: mapper.Reception(reception)}
+			if err := h.outboxQueries.AddEvent(ctx, reception.PvzID, models.EventReceptionClosed, event); err != nil {
+				return err
+			}
+			result.ReceptionIDs = append(result.ReceptionIDs, reception.ID)
+		}
+		return nil
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Message: "Ошибка при закрытии приёмки: " + err.Error(),
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCloseStaleReceptionsFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, result)
+}
+
+// GetOverdueReceptions обрабатывает запрос на получение приёмок, не закрытых в срок SLA
+func (h *ReceptionHandler) GetOverdueReceptions(c *gin.Context) {
+	var query models.OverdueReceptionsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+
+	if query.PvzID != "" && denyPVZAccess(c, query.PvzID) {
+		return
+	}
+
+	overdue, err := h.receptionQueries.GetOverdueReceptions(c.Request.Context(), query.PvzID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetOverdueReceptionsFailed, err))
+		return
+	}
+
+	result := make([]models.OverdueReceptionResponse, 0, len(overdue))
+	for _, reception := range overdue {
+		// Без pvzId список общий: токену со списком ПВЗ отдаются только приёмки его ПВЗ
+		if !canAccessPVZ(c, reception.PvzID) {
+			continue
+		}
+		result = append(result, models.OverdueReceptionResponse{
+			ID:        reception.ID,
+			Number:    reception.Number,
+			DateTime:  reception.DateTime,
+			PvzID:     reception.PvzID,
+			FlaggedAt: reception.FlaggedAt,
 		})
+	}
+
+	response.JSON(c, http.StatusOK, result)
+}
+
+// ListReceptions обрабатывает запрос на получение страницы приёмок ПВЗ от новых к старым
+func (h *ReceptionHandler) ListReceptions(c *gin.Context) {
+	pvzID := c.Param("pvzId")
+	if _, err := uuid.Parse(pvzID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgPVZNotFound))
+		return
+	}
+
+	query := models.ReceptionListQuery{Page: 1, Limit: 10}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+
+	receptions, total, err := h.receptionQueries.ListReceptions(c.Request.Context(), pvzID, query)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetReceptionsFailed, err))
+		return
+	}
+
+	result := make([]models.ReceptionResponse, 0, len(receptions))
+	for _, reception := range receptions {
+		result = append(result, mapper.Reception(reception))
+	}
+
+	response.Paginated(c, http.StatusOK, result, response.NewMeta(query.Page, query.Limit, total))
+}
+
+// UpdateReception обрабатывает запрос на изменение заметки и тегов приёмки (только для сотрудников).
+// Каждое изменение сохраняется в истории заметок
+func (h *ReceptionHandler) UpdateReception(c *gin.Context) {
+	if !authz.Can(c.GetString("userRole"), authz.ReceptionWrite) {
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbiddenUpdateReception))
+		return
+	}
+
+	var req models.UpdateReceptionRequest
+
+	// Проверяем запрос
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+	if req.Note == nil && req.Tags == nil {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgReceptionUpdateEmpty))
+		return
+	}
+
+	receptionID := c.Param("receptionId")
+	if _, err := uuid.Parse(receptionID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReceptionNotFound))
+		return
+	}
+
+	var note *string
+	if req.Note != nil {
+		trimmed := strings.TrimSpace(*req.Note)
+		note = &trimmed
+	}
+	var tags []string
+	if req.Tags != nil {
+		tags = normalizeTags(*req.Tags)
+	}
+
+	var reception *models.Reception
+	err := h.tx.InTx(c.Request.Context(), func(ctx context.Context) error {
+		var err error
+		reception, err = h.receptionQueries.UpdateReceptionNotes(ctx, receptionID, c.GetString("userID"), note, tags)
+		if err != nil {
+			return err
+		}
+		// ПВЗ приёмки известен только после изменения: чужая приёмка откатывается вместе с историей заметок
+		if !canAccessPVZ(c, reception.PvzID) {
+			return errPVZAccessDenied
+		}
+		return nil
+	})
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReceptionNotFound))
+		return
+	}
+	if errors.Is(err, errPVZAccessDenied) {
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgPVZAccessDenied))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgUpdateReceptionFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, mapper.Reception(*reception))
+}
+
+// GetReceptionNotes обрабатывает запрос на получение истории заметок и тегов приёмки
+func (h *ReceptionHandler) GetReceptionNotes(c *gin.Context) {
+	receptionID := c.Param("receptionId")
+	if _, err := uuid.Parse(receptionID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReceptionNotFound))
+		return
+	}
+
+	// Проверяем, что приёмка существует, чтобы отличить ее от приёмки без истории
+	reception, err := h.receptionQueries.GetReceptionByID(c.Request.Context(), receptionID)
+	if err != nil {
+		if errors.Is(err, queries.ErrNotFound) {
+			response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReceptionNotFound))
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetReceptionFailed, err))
+		return
+	}
+	if denyPVZAccess(c, reception.PvzID) {
+		return
+	}
+
+	notes, err := h.receptionQueries.GetReceptionNotes(c.Request.Context(), receptionID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetReceptionNotesFailed, err))
+		return
+	}
+
+	result := make([]models.ReceptionNoteResponse, 0, len(notes))
+	for _, note := range notes {
+		result = append(result, mapper.ReceptionNote(note))
+	}
+
+	response.JSON(c, http.StatusOK, result)
+}
+
+// normalizeTags приводит теги к нижнему регистру без пробелов по краям,
+// убирает пустые и повторяющиеся теги с сохранением порядка
+func normalizeTags(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
+}
+
+// compareWithManifest возвращает расхождения с накладной или nil, если накладной нет
+func (h *ReceptionHandler) compareWithManifest(ctx context.Context, receptionID string) (*models.ManifestDiscrepancies, error) {
+	expected, err := h.manifestQueries.GetExpectedProducts(ctx, receptionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(expected) == 0 {
+		return nil, nil
+	}
+
+	products, err := h.productQueries.GetProductsByReception(ctx, receptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	discrepancies := manifest.Compare(expected, products)
+	return &discrepancies, nil
+}
+
+// GetDiscrepancyReport обрабатывает запрос отчета сверки приёмки с загруженной накладной:
+// недостающие и лишние товары и товары с типом, отличным от накладной.
+// С параметром format=csv отчет отдается файлом CSV
+func (h *ReceptionHandler) GetDiscrepancyReport(c *gin.Context) {
+	var query models.DiscrepancyReportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+
+	reception, err := h.receptionQueries.GetReceptionByID(c.Request.Context(), c.Param("receptionId"))
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReceptionNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetReceptionFailed, err))
+		return
+	}
+	if denyPVZAccess(c, reception.PvzID) {
+		return
+	}
+
+	expected, err := h.manifestQueries.GetExpectedProducts(c.Request.Context(), reception.ID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgManifestCompareFailed, err))
+		return
+	}
+	if len(expected) == 0 {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgManifestNotLoaded))
+		return
+	}
+
+	products, err := h.productQueries.GetProductsByReception(c.Request.Context(), reception.ID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetProductsFailed, err))
+		return
+	}
+
+	report := manifest.Report(reception.ID, expected, products)
+
+	if query.Format == "csv" {
+		var buf bytes.Buffer
+		if err := manifest.WriteReportCSV(&buf, report); err != nil {
+			response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgManifestCompareFailed, err))
+			return
+		}
+		response.Attachment(c, "discrepancies-"+reception.ID+".csv")
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+		return
+	}
+
+	response.JSON(c, http.StatusOK, report)
+}
+
+// ImportManifest обрабатывает загрузку накладной (CSV или XLSX) с ожидаемыми товарами приёмки
+func (h *ReceptionHandler) ImportManifest(c *gin.Context) {
+	receptionID := c.Param("receptionId")
+
+	// Получаем файл накладной
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxManifestSize)
+	fileHeader, err := c.FormFile("file")
+	if bodyTooLarge(err) {
+		response.Error(c, http.StatusRequestEntityTooLarge, i18n.T(c, i18n.MsgRequestTooLarge))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgManifestFileMissing, err))
+		return
+	}
+
+	// Проверяем, что приёмка существует и ещё открыта
+	reception, err := h.receptionQueries.GetReceptionByID(c.Request.Context(), receptionID)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReceptionNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetReceptionFailed, err))
+		return
+	}
+	if denyPVZAccess(c, reception.PvzID) {
+		return
+	}
+
+	if reception.Status != "in_progress" {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgReceptionClosed))
+		return
+	}
+
+	// Разбираем накладную
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgManifestReadFailed, err))
+		return
+	}
+	defer file.Close()
+
+	lines, err := manifest.Parse(fileHeader.Filename, file)
+	if errors.Is(err, manifest.ErrUnsupportedFormat) {
+		response.Error(c, http.StatusUnsupportedMediaType, i18n.T(c, i18n.MsgManifestUnsupportedFormat))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgManifestInvalid, err))
+		return
+	}
+
+	expected := make([]models.ExpectedProduct, 0, len(lines))
+	items := 0
+	for _, line := range lines {
+		product := models.ExpectedProduct{
+			ReceptionID: receptionID,
+			Type:        line.Type,
+			Quantity:    line.Quantity,
+		}
+		if line.Barcode != "" {
+			barcode := line.Barcode
+			product.Barcode = &barcode
+		}
+		expected = append(expected, product)
+		items += line.Quantity
+	}
+
+	// Сохраняем накладную, заменяя ранее загруженную
+	if err := h.manifestQueries.ReplaceExpectedProducts(c.Request.Context(), receptionID, expected); err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgManifestSaveFailed, err))
 		return
 	}
 
-	// Возвращаем данные закрытой приёмки
-	c.JSON(http.StatusOK, models.ReceptionResponse{
-		ID:       closedReception.ID,
-		DateTime: closedReception.DateTime,
-		PvzID:    closedReception.PvzID,
-		Status:   closedReception.Status,
+	response.JSON(c, http.StatusCreated, models.ManifestImportResponse{
+		ReceptionID: receptionID,
+		Lines:       len(expected),
+		Items:       items,
 	})
 }
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+	"pvz-service/internal/pdf"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReceptionActHandler содержит обработчики печатных документов приёмки
+type ReceptionActHandler struct {
+	receptionQueries queries.ReceptionQueriesInterface
+	productQueries   queries.ProductQueriesInterface
+	pvzQueries       queries.PVZQueriesInterface
+	clock            clock.Clock
+}
+
+// NewReceptionActHandler создает новый экземпляр ReceptionActHandler
+func NewReceptionActHandler(receptionQueries queries.ReceptionQueriesInterface, productQueries queries.ProductQueriesInterface, pvzQueries queries.PVZQueriesInterface) *ReceptionActHandler {
+	return &ReceptionActHandler{
+		receptionQueries: receptionQueries,
+		productQueries:   productQueries,
+		pvzQueries:       pvzQueries,
+		clock:            clock.System{},
+	}
+}
+
+// GetReceptionAct обрабатывает запрос на получение акта приёмки товаров в PDF.
+// Сотрудник печатает акт при передаче товаров курьеру
+func (h *ReceptionActHandler) GetReceptionAct(c *gin.Context) {
+	receptionID := c.Param("receptionId")
+	if _, err := uuid.Parse(receptionID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReceptionNotFound))
+		return
+	}
+
+	reception, err := h.receptionQueries.GetReceptionByID(c.Request.Context(), receptionID)
+	if err != nil {
+		if errors.Is(err, queries.ErrNotFound) {
+			response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReceptionNotFound))
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetReceptionFailed, err))
+		return
+	}
+	if denyPVZAccess(c, reception.PvzID) {
+		return
+	}
+
+	pvz, err := h.pvzQueries.GetPVZ(c.Request.Context(), reception.PvzID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgReceptionActFailed, err))
+		return
+	}
+
+	products, err := h.productQueries.GetProductsByReception(c.Request.Context(), receptionID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetProductsFailed, err))
+		return
+	}
+
+	// Документ формируется целиком до ответа, чтобы при ошибке вернуть JSON, а не обрезанный PDF
+	var buf bytes.Buffer
+	err = pdf.RenderReceptionAct(&buf, pdf.ReceptionAct{
+		Reception:   *reception,
+		PVZ:         *pvz,
+		Products:    products,
+		GeneratedAt: h.clock.Now(),
+	})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgReceptionActFailed, err))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=\"act-%s.pdf\"", receptionID))
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
+
+// GetReceptionCustody обрабатывает запрос документа о цепочке хранения приёмки для страховых случаев:
+// кто открыл приёмку, кто и когда добавлял, исправлял и удалял товары, кто закрыл приёмку.
+// По умолчанию документ возвращается в JSON, с format=pdf - в PDF
+func (h *ReceptionActHandler) GetReceptionCustody(c *gin.Context) {
+	receptionID := c.Param("receptionId")
+	if _, err := uuid.Parse(receptionID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReceptionNotFound))
+		return
+	}
+
+	var query models.CustodyQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+
+	reception, err := h.receptionQueries.GetReceptionByID(c.Request.Context(), receptionID)
+	if err != nil {
+		if errors.Is(err, queries.ErrNotFound) {
+			response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReceptionNotFound))
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetReceptionFailed, err))
+		return
+	}
+	if denyPVZAccess(c, reception.PvzID) {
+		return
+	}
+
+	entries, err := h.receptionQueries.GetCustodyEntries(c.Request.Context(), receptionID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCustodyFailed, err))
+		return
+	}
+
+	document := models.CustodyDocument{
+		ReceptionID: reception.ID,
+		Number:      reception.Number,
+		PvzID:       reception.PvzID,
+		Status:      reception.Status,
+		Entries:     entries,
+		GeneratedAt: h.clock.Now(),
+	}
+	if document.Entries == nil {
+		document.Entries = []models.CustodyEntry{}
+	}
+
+	if query.Format != "pdf" {
+		response.JSON(c, http.StatusOK, document)
+		return
+	}
+
+	pvz, err := h.pvzQueries.GetPVZ(c.Request.Context(), reception.PvzID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCustodyFailed, err))
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.RenderCustody(&buf, pdf.Custody{Document: document, PVZ: *pvz}); err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCustodyFailed, err))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"custody-%s.pdf\"", receptionID))
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
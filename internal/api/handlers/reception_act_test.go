@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupReceptionActTest создает маршрут получения акта приёмки
+func setupReceptionActTest() (*gin.Engine, *MockReceptionQueries, *MockProductQueries, *MockPVZQueries) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	receptionQueries := new(MockReceptionQueries)
+	productQueries := new(MockProductQueries)
+	pvzQueries := new(MockPVZQueries)
+	handler := NewReceptionActHandler(receptionQueries, productQueries, pvzQueries)
+	r.GET("/receptions/:receptionId/act.pdf", handler.GetReceptionAct)
+	r.GET("/receptions/:receptionId/custody", handler.GetReceptionCustody)
+
+	return r, receptionQueries, productQueries, pvzQueries
+}
+
+func TestGetReceptionAct(t *testing.T) {
+	r, receptionQueries, productQueries, pvzQueries := setupReceptionActTest()
+
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	barcode := "4601234567890"
+	receptionQueries.On("GetReceptionByID", mock.Anything, testReceptionID).
+		Return(&models.Reception{ID: testReceptionID, DateTime: time.Now(), PvzID: pvzID, Status: "close"}, nil)
+	pvzQueries.On("GetPVZ", mock.Anything, pvzID).
+		Return(&models.PVZ{ID: pvzID, City: "Москва", Address: "ул. Тверская, 1"}, nil)
+	productQueries.On("GetProductsByReception", mock.Anything, testReceptionID).Return([]models.Product{
+		{ID: "product-1", Type: "электроника", Datetime: time.Now(), ReceptionID: testReceptionID, Barcode: &barcode},
+		{ID: "product-2", Type: "обувь", Datetime: time.Now(), ReceptionID: testReceptionID},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/receptions/"+testReceptionID+"/act.pdf", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Equal(t, `inline; filename="act-`+testReceptionID+`.pdf"`, w.Header().Get("Content-Disposition"))
+	assert.True(t, bytes.HasPrefix(w.Body.Bytes(), []byte("%PDF-")))
+}
+
+func TestGetReceptionActErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		receptionErr error
+		productsErr  error
+		code         int
+	}{
+		{"Неверный ID приёмки", "/receptions/not-a-uuid/act.pdf", nil, nil, http.StatusNotFound},
+		{"Приёмка не найдена", "/receptions/" + testReceptionID + "/act.pdf", queries.ErrNotFound, nil, http.StatusNotFound},
+		{"Ошибка получения приёмки", "/receptions/" + testReceptionID + "/act.pdf", errors.New("database error"), nil, http.StatusInternalServerError},
+		{"Ошибка получения товаров", "/receptions/" + testReceptionID + "/act.pdf", nil, errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, receptionQueries, productQueries, pvzQueries := setupReceptionActTest()
+
+			if tt.receptionErr != nil {
+				receptionQueries.On("GetReceptionByID", mock.Anything, testReceptionID).Return(nil, tt.receptionErr)
+			} else {
+				receptionQueries.On("GetReceptionByID", mock.Anything, testReceptionID).
+					Return(&models.Reception{ID: testReceptionID, PvzID: "pvz-uuid", Status: "close"}, nil)
+			}
+			pvzQueries.On("GetPVZ", mock.Anything, "pvz-uuid").Return(&models.PVZ{ID: "pvz-uuid", City: "Москва"}, nil)
+			productQueries.On("GetProductsByReception", mock.Anything, testReceptionID).Return(nil, tt.productsErr)
+
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+		})
+	}
+}
+
+func TestGetReceptionCustody(t *testing.T) {
+	r, receptionQueries, _, pvzQueries := setupReceptionActTest()
+
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	opened := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	actor := "123e4567-e89b-12d3-a456-426614174099"
+	productID := "product-1"
+	receptionQueries.On("GetReceptionByID", mock.Anything, testReceptionID).
+		Return(&models.Reception{ID: testReceptionID, Number: "MSK001-2026-000001", PvzID: pvzID, Status: "close"}, nil)
+	receptionQueries.On("GetCustodyEntries", mock.Anything, testReceptionID).Return([]models.CustodyEntry{
+		{Action: models.CustodyReceptionOpened, At: &opened, ActorID: &actor},
+		{Action: models.CustodyProductAdded, At: &opened, ActorID: &actor, ProductID: &productID},
+		{Action: models.CustodyReceptionClosed},
+	}, nil)
+	pvzQueries.On("GetPVZ", mock.Anything, pvzID).Return(&models.PVZ{ID: pvzID, City: "Москва"}, nil)
+
+	t.Run("JSON", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/receptions/"+testReceptionID+"/custody", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var document models.CustodyDocument
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &document))
+		assert.Equal(t, "MSK001-2026-000001", document.Number)
+		assert.Len(t, document.Entries, 3)
+		assert.Nil(t, document.Entries[2].At)
+		pvzQueries.AssertNotCalled(t, "GetPVZ", mock.Anything, mock.Anything)
+	})
+
+	t.Run("PDF", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/receptions/"+testReceptionID+"/custody?format=pdf", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+		assert.True(t, bytes.HasPrefix(w.Body.Bytes(), []byte("%PDF-")))
+	})
+}
+
+func TestGetReceptionCustodyErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		receptionErr error
+		code         int
+	}{
+		{"Неверный ID приёмки", "/receptions/not-a-uuid/custody", nil, http.StatusNotFound},
+		{"Неизвестный формат", "/receptions/" + testReceptionID + "/custody?format=xml", nil, http.StatusBadRequest},
+		{"Приёмка не найдена", "/receptions/" + testReceptionID + "/custody", queries.ErrNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, receptionQueries, _, _ := setupReceptionActTest()
+			receptionQueries.On("GetReceptionByID", mock.Anything, testReceptionID).Return(nil, tt.receptionErr)
+
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+			receptionQueries.AssertNotCalled(t, "GetCustodyEntries", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReceptionArchiveHandler содержит обработчики архива старых приёмок
+type ReceptionArchiveHandler struct {
+	archiveQueries queries.ArchiveQueriesInterface
+}
+
+// NewReceptionArchiveHandler создает новый экземпляр ReceptionArchiveHandler
+func NewReceptionArchiveHandler(archiveQueries queries.ArchiveQueriesInterface) *ReceptionArchiveHandler {
+	return &ReceptionArchiveHandler{
+		archiveQueries: archiveQueries,
+	}
+}
+
+// RestoreReception обрабатывает запрос на возврат приёмки из архива вместе с товарами и историей
+func (h *ReceptionArchiveHandler) RestoreReception(c *gin.Context) {
+	receptionID := c.Param("receptionId")
+	if _, err := uuid.Parse(receptionID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgArchivedReceptionNotFound))
+		return
+	}
+
+	reception, err := h.archiveQueries.RestoreReception(c.Request.Context(), receptionID)
+	switch {
+	case errors.Is(err, queries.ErrNotFound):
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgArchivedReceptionNotFound))
+		return
+	case errors.Is(err, queries.ErrArchiveRestoreConflict):
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgArchiveRestoreConflict))
+		return
+	case err != nil:
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgRestoreReceptionFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, reception)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// archiveStore возвращает из архива единственную приёмку или заданную ошибку
+type archiveStore struct {
+	queries.ArchiveQueriesInterface
+	err error
+}
+
+func (s archiveStore) RestoreReception(ctx context.Context, receptionID string) (*models.Reception, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if receptionID != testReceptionID {
+		return nil, queries.ErrNotFound
+	}
+	return &models.Reception{ID: receptionID, PvzID: testPvzID, Status: "close"}, nil
+}
+
+// TestRestoreReception проверяет восстановление приёмки из архива и ответы на ошибки
+func TestRestoreReception(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	restore := func(store archiveStore, receptionID string) *httptest.ResponseRecorder {
+		r := gin.New()
+		r.POST("/admin/receptions/:receptionId/restore", NewReceptionArchiveHandler(store).RestoreReception)
+		req, _ := http.NewRequest("POST", "/admin/receptions/"+receptionID+"/restore", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := restore(archiveStore{}, testReceptionID)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var reception models.Reception
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &reception))
+	assert.Equal(t, testReceptionID, reception.ID)
+
+	assert.Equal(t, http.StatusNotFound, restore(archiveStore{}, "523e4567-e89b-12d3-a456-426614174000").Code)
+	assert.Equal(t, http.StatusNotFound, restore(archiveStore{}, "not-a-uuid").Code)
+	assert.Equal(t, http.StatusConflict, restore(archiveStore{err: queries.ErrArchiveRestoreConflict}, testReceptionID).Code)
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/authz"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/mapper"
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Ошибки проверки объединения приёмок
+var (
+	errMergeReceptionNotFound = errors.New("reception not found")
+	errMergeReceptionsNotOpen = errors.New("reception is not open")
+	errMergePVZMismatch       = errors.New("receptions belong to different pvz")
+)
+
+// MergeReceptions обрабатывает запрос модератора на объединение приёмки, открытой по ошибке,
+// с другой открытой приёмкой того же ПВЗ: все товары переносятся в целевую приёмку,
+// а исходная закрывается. Обе приёмки блокируются на время переноса
+func (h *ReceptionHandler) MergeReceptions(c *gin.Context) {
+	if !authz.Can(c.GetString("userRole"), authz.ReceptionMerge) {
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbidden))
+		return
+	}
+
+	sourceID := c.Param("receptionId")
+	targetID := c.Param("targetId")
+	if sourceID == targetID {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgMergeSameReception))
+		return
+	}
+
+	var result models.MergeReceptionsResponse
+	err := h.tx.InTx(c.Request.Context(), func(ctx context.Context) error {
+		receptions, err := h.receptionQueries.LockReceptions(ctx, []string{sourceID, targetID})
+		if err != nil {
+			return err
+		}
+
+		var source, target *models.Reception
+		for i := range receptions {
+			switch receptions[i].ID {
+			case sourceID:
+				source = &receptions[i]
+			case targetID:
+				target = &receptions[i]
+			}
+		}
+		if source == nil || target == nil {
+			return errMergeReceptionNotFound
+		}
+		if !canAccessPVZ(c, source.PvzID) || !canAccessPVZ(c, target.PvzID) {
+			return errPVZAccessDenied
+		}
+		if source.Status != "in_progress" || target.Status != "in_progress" {
+			return errMergeReceptionsNotOpen
+		}
+		if source.PvzID != target.PvzID {
+			return errMergePVZMismatch
+		}
+
+		moved, err := h.productQueries.MoveProducts(ctx, source.ID, target.ID)
+		if err != nil {
+			return err
+		}
+
+		closed, err := h.receptionQueries.CloseReception(ctx, source.ID, c.GetString("userID"))
+		if err != nil {
+			return err
+		}
+
+		// Счетчик товаров целевой приёмки обновлен триггером, заблокированная строка его не отражает
+		target.ProductCount += moved
+		result = models.MergeReceptionsResponse{
+			Source:        mapper.Reception(*closed),
+			Target:        mapper.Reception(*target),
+			MovedProducts: moved,
+		}
+
+		event := models.CloseReceptionResponse{ReceptionResponse: result.Source}
+		if err := h.outboxQueries.AddEvent(ctx, source.PvzID, models.EventReceptionClosed, event); err != nil {
+			return err
+		}
+		return h.outboxQueries.AddEvent(ctx, source.PvzID, models.EventReceptionMerged, result)
+	})
+	switch {
+	case errors.Is(err, errMergeReceptionNotFound):
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReceptionNotFound))
+	case errors.Is(err, errPVZAccessDenied):
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgPVZAccessDenied))
+	case errors.Is(err, errMergeReceptionsNotOpen):
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgMergeReceptionsNotOpen))
+	case errors.Is(err, errMergePVZMismatch):
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgMergeReceptionsPVZMismatch))
+	case err != nil:
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgMergeReceptionsFailed, err))
+	default:
+		response.JSON(c, http.StatusOK, result)
+	}
+}
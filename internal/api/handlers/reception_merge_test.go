@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testMergeSourceID = "323e4567-e89b-12d3-a456-426614174001"
+	testMergeTargetID = "323e4567-e89b-12d3-a456-426614174002"
+)
+
+// setupMergeTest создает маршрут объединения приёмок с ролью модератора
+func setupMergeTest() (*gin.Engine, *MockReceptionQueries, *MockProductQueries, *recordingOutbox) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	receptionQueries := new(MockReceptionQueries)
+	productQueries := new(MockProductQueries)
+	outbox := &recordingOutbox{}
+	receptionHandler := NewReceptionHandler(receptionQueries, productQueries, new(MockManifestQueries), nil, nil, passthroughTx{}, outbox, alwaysOpen{}, nil, noQuotas{}, noLocks{})
+	r.POST("/receptions/:receptionId/merge_into/:targetId", func(c *gin.Context) {
+		c.Set("userRole", models.RoleModerator)
+		receptionHandler.MergeReceptions(c)
+	})
+
+	return r, receptionQueries, productQueries, outbox
+}
+
+// TestMergeReceptions проверяет перенос товаров и закрытие исходной приёмки
+func TestMergeReceptions(t *testing.T) {
+	r, receptionQueries, productQueries, outbox := setupMergeTest()
+
+	receptionQueries.On("LockReceptions", mock.Anything, []string{testMergeSourceID, testMergeTargetID}).Return([]models.Reception{
+		{ID: testMergeSourceID, PvzID: testPvzID, Status: "in_progress", ProductCount: 2},
+		{ID: testMergeTargetID, PvzID: testPvzID, Status: "in_progress", ProductCount: 3},
+	}, nil)
+	productQueries.On("MoveProducts", mock.Anything, testMergeSourceID, testMergeTargetID).Return(2, nil)
+	receptionQueries.On("CloseReception", mock.Anything, testMergeSourceID, mock.Anything).
+		Return(&models.Reception{ID: testMergeSourceID, PvzID: testPvzID, Status: "close"}, nil)
+
+	req, _ := http.NewRequest("POST", "/receptions/"+testMergeSourceID+"/merge_into/"+testMergeTargetID, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result models.MergeReceptionsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 2, result.MovedProducts)
+	assert.Equal(t, "close", result.Source.Status)
+	assert.Equal(t, 5, result.Target.ProductCount)
+
+	assert.Len(t, outbox.events, 2)
+	assert.Equal(t, models.EventReceptionClosed, outbox.events[0].Type)
+	assert.Equal(t, models.EventReceptionMerged, outbox.events[1].Type)
+	receptionQueries.AssertExpectations(t)
+	productQueries.AssertExpectations(t)
+}
+
+// TestMergeReceptionsValidation проверяет отказ в объединении без переноса товаров
+func TestMergeReceptionsValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		receptions []models.Reception
+		lockErr    error
+		wantStatus int
+	}{
+		{
+			name:       "Та же приёмка",
+			target:     testMergeSourceID,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Приёмка не найдена",
+			target: testMergeTargetID,
+			receptions: []models.Reception{
+				{ID: testMergeSourceID, PvzID: testPvzID, Status: "in_progress"},
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "Целевая приёмка закрыта",
+			target: testMergeTargetID,
+			receptions: []models.Reception{
+				{ID: testMergeSourceID, PvzID: testPvzID, Status: "in_progress"},
+				{ID: testMergeTargetID, PvzID: testPvzID, Status: "close"},
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:   "Разные ПВЗ",
+			target: testMergeTargetID,
+			receptions: []models.Reception{
+				{ID: testMergeSourceID, PvzID: testPvzID, Status: "in_progress"},
+				{ID: testMergeTargetID, PvzID: "223e4567-e89b-12d3-a456-426614174000", Status: "in_progress"},
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Ошибка базы данных",
+			target:     testMergeTargetID,
+			lockErr:    errors.New("database error"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, receptionQueries, productQueries, outbox := setupMergeTest()
+			receptionQueries.On("LockReceptions", mock.Anything, mock.Anything).Return(tt.receptions, tt.lockErr).Maybe()
+
+			req, _ := http.NewRequest("POST", "/receptions/"+testMergeSourceID+"/merge_into/"+tt.target, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Empty(t, outbox.events)
+			productQueries.AssertNotCalled(t, "MoveProducts", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestMergeReceptionsForbidden проверяет, что сотрудник не может объединять приёмки
+func TestMergeReceptionsForbidden(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	receptionQueries := new(MockReceptionQueries)
+	r.POST("/receptions/:receptionId/merge_into/:targetId", func(c *gin.Context) {
+		c.Set("userRole", models.RoleEmployee)
+		newReceptionHandlerWithoutManifest(receptionQueries).MergeReceptions(c)
+	})
+
+	req, _ := http.NewRequest("POST", "/receptions/"+testMergeSourceID+"/merge_into/"+testMergeTargetID, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	receptionQueries.AssertNotCalled(t, "LockReceptions", mock.Anything, mock.Anything)
+}
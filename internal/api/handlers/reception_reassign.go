@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/authz"
+	"pvz-service/internal/db"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+	"pvz-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReceptionReassignHandler содержит обработчик переноса приёмки в другой ПВЗ
+type ReceptionReassignHandler struct {
+	receptionQueries queries.ReceptionQueriesInterface
+	pvzService       *service.PVZService
+	cityAccess       *service.CityAccess
+}
+
+// NewReceptionReassignHandler создает новый экземпляр ReceptionReassignHandler.
+// Модератор переносит приёмки только между ПВЗ назначенных ему городов (moderatorQueries)
+func NewReceptionReassignHandler(pvzQueries queries.PVZQueriesInterface, receptionQueries queries.ReceptionQueriesInterface, moderatorQueries queries.ModeratorQueriesInterface, tx db.Transactor, outboxQueries queries.OutboxQueriesInterface) *ReceptionReassignHandler {
+	return &ReceptionReassignHandler{
+		receptionQueries: receptionQueries,
+		pvzService:       service.NewPVZService(pvzQueries, receptionQueries, tx, outboxQueries),
+		cityAccess:       service.NewCityAccess(moderatorQueries, pvzQueries),
+	}
+}
+
+// ReassignReception обрабатывает запрос модератора на перенос приёмки вместе с товарами
+// в ПВЗ pvzId, например если приёмку открыли не в том ПВЗ. Модератор должен управлять
+// и текущим ПВЗ приёмки, и ПВЗ назначения
+func (h *ReceptionReassignHandler) ReassignReception(c *gin.Context) {
+	if !authz.Can(c.GetString("userRole"), authz.ReceptionReassign) {
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbidden))
+		return
+	}
+
+	var query models.ReassignReceptionQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+
+	receptionID := c.Param("receptionId")
+	if _, err := uuid.Parse(receptionID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReceptionNotFound))
+		return
+	}
+
+	ctx := c.Request.Context()
+	userID := c.GetString("userID")
+
+	reception, err := h.receptionQueries.GetReceptionByID(ctx, receptionID)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReceptionNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetReceptionFailed, err))
+		return
+	}
+
+	for _, pvzID := range []string{reception.PvzID, query.PvzID} {
+		if denyPVZAccess(c, pvzID) {
+			return
+		}
+
+		err := h.cityAccess.CheckPVZ(ctx, userID, pvzID)
+		switch {
+		case errors.Is(err, queries.ErrNotFound):
+			response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgPVZNotFound))
+			return
+		case errors.Is(err, service.ErrCityForbidden):
+			response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbiddenPVZCity))
+			return
+		case err != nil:
+			response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgCheckCityAccessFailed, err))
+			return
+		}
+	}
+
+	result, err := h.pvzService.ReassignReception(ctx, reception.ID, query.PvzID, userID, query.Reason)
+	switch {
+	case errors.Is(err, service.ErrTargetPVZNotFound):
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReassignTargetNotFound))
+	case errors.Is(err, queries.ErrNotFound):
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReceptionNotFound))
+	case errors.Is(err, service.ErrReassignSamePVZ):
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgReassignSamePVZ))
+	case errors.Is(err, service.ErrTargetPVZHasOpenReception):
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgReassignTargetOpen))
+	case errors.Is(err, queries.ErrReceptionHasOrders):
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgReassignReceptionHasOrders))
+	case err != nil:
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgReassignReceptionFailed, err))
+	default:
+		response.JSON(c, http.StatusOK, result)
+	}
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testReassignReceptionID = "423e4567-e89b-12d3-a456-426614174001"
+	testReassignTargetPvzID = "423e4567-e89b-12d3-a456-426614174002"
+)
+
+// setupReassignTest создает маршрут переноса приёмки с ролью role и городами модератора cities
+func setupReassignTest(role string, cities moderatorCities) (*gin.Engine, *MockPVZQueries, *MockReceptionQueries, *recordingOutbox) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	pvzQueries := new(MockPVZQueries)
+	receptionQueries := new(MockReceptionQueries)
+	outbox := &recordingOutbox{}
+	handler := NewReceptionReassignHandler(pvzQueries, receptionQueries, cities, passthroughTx{}, outbox)
+	r.POST("/receptions/:receptionId/reassign", func(c *gin.Context) {
+		c.Set("userRole", role)
+		c.Set("userID", "moderator-1")
+		handler.ReassignReception(c)
+	})
+
+	return r, pvzQueries, receptionQueries, outbox
+}
+
+func reassignRequest(r *gin.Engine, receptionID, query string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/receptions/"+receptionID+"/reassign?"+query, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestReassignReception проверяет перенос приёмки и событие в лентах обоих ПВЗ
+func TestReassignReception(t *testing.T) {
+	r, pvzQueries, receptionQueries, outbox := setupReassignTest(models.RoleModerator, moderatorCities{})
+	reception := models.Reception{ID: testReassignReceptionID, PvzID: testPvzID, Status: "close", ProductCount: 3}
+	moved := reception
+	moved.PvzID = testReassignTargetPvzID
+	reason := "ошибка ПВЗ"
+
+	receptionQueries.On("GetReceptionByID", mock.Anything, testReassignReceptionID).Return(&reception, nil)
+	pvzQueries.On("LockPVZ", mock.Anything, testReassignTargetPvzID).Return(&models.PVZ{ID: testReassignTargetPvzID, OpenReception: true}, nil)
+	receptionQueries.On("LockReceptions", mock.Anything, []string{testReassignReceptionID}).Return([]models.Reception{reception}, nil)
+	receptionQueries.On("ReassignReception", mock.Anything, testReassignReceptionID, testPvzID, testReassignTargetPvzID, "moderator-1", &reason).Return(&moved, nil)
+
+	w := reassignRequest(r, testReassignReceptionID, "pvzId="+testReassignTargetPvzID+"&reason=%D0%BE%D1%88%D0%B8%D0%B1%D0%BA%D0%B0%20%D0%9F%D0%92%D0%97")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result models.ReassignReceptionResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, testPvzID, result.FromPvzID)
+	assert.Equal(t, testReassignTargetPvzID, result.Reception.PvzID)
+	assert.Equal(t, 3, result.Reception.ProductCount)
+
+	if assert.Len(t, outbox.events, 2) {
+		assert.Equal(t, testPvzID, outbox.events[0].PvzID)
+		assert.Equal(t, testReassignTargetPvzID, outbox.events[1].PvzID)
+		assert.Equal(t, models.EventReceptionReassigned, outbox.events[1].Type)
+	}
+	receptionQueries.AssertExpectations(t)
+}
+
+// TestReassignReceptionConflicts проверяет отказы в переносе
+func TestReassignReceptionConflicts(t *testing.T) {
+	tests := []struct {
+		name       string
+		reception  models.Reception
+		target     *models.PVZ
+		targetErr  error
+		reassign   error
+		wantStatus int
+	}{
+		{
+			name:       "Открытая приёмка в ПВЗ с открытой приёмкой",
+			reception:  models.Reception{ID: testReassignReceptionID, PvzID: testPvzID, Status: "in_progress"},
+			target:     &models.PVZ{ID: testReassignTargetPvzID, OpenReception: true},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "Товары в заказах",
+			reception:  models.Reception{ID: testReassignReceptionID, PvzID: testPvzID, Status: "close"},
+			target:     &models.PVZ{ID: testReassignTargetPvzID},
+			reassign:   fmt.Errorf("reception: %w", queries.ErrReceptionHasOrders),
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "ПВЗ назначения в архиве",
+			reception:  models.Reception{ID: testReassignReceptionID, PvzID: testPvzID, Status: "close"},
+			targetErr:  fmt.Errorf("pvz: %w", queries.ErrNotFound),
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Тот же ПВЗ",
+			reception:  models.Reception{ID: testReassignReceptionID, PvzID: testReassignTargetPvzID, Status: "close"},
+			target:     &models.PVZ{ID: testReassignTargetPvzID},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, pvzQueries, receptionQueries, outbox := setupReassignTest(models.RoleModerator, moderatorCities{})
+
+			receptionQueries.On("GetReceptionByID", mock.Anything, testReassignReceptionID).Return(&tt.reception, nil)
+			pvzQueries.On("LockPVZ", mock.Anything, testReassignTargetPvzID).Return(tt.target, tt.targetErr)
+			receptionQueries.On("LockReceptions", mock.Anything, []string{testReassignReceptionID}).Return([]models.Reception{tt.reception}, nil)
+			receptionQueries.On("ReassignReception", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.reassign)
+
+			w := reassignRequest(r, testReassignReceptionID, "pvzId="+testReassignTargetPvzID)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Empty(t, outbox.events)
+		})
+	}
+}
+
+// TestReassignReceptionAccess проверяет права и ограничение модератора по городам
+func TestReassignReceptionAccess(t *testing.T) {
+	t.Run("Сотрудник", func(t *testing.T) {
+		r, _, receptionQueries, _ := setupReassignTest(models.RoleEmployee, moderatorCities{})
+
+		w := reassignRequest(r, testReassignReceptionID, "pvzId="+testReassignTargetPvzID)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		receptionQueries.AssertNotCalled(t, "GetReceptionByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ПВЗ назначения в чужом городе", func(t *testing.T) {
+		r, pvzQueries, receptionQueries, _ := setupReassignTest(models.RoleModerator, moderatorCities{"moderator-1": {"Москва"}})
+
+		receptionQueries.On("GetReceptionByID", mock.Anything, testReassignReceptionID).Return(&models.Reception{ID: testReassignReceptionID, PvzID: testPvzID}, nil)
+		pvzQueries.On("GetPVZ", mock.Anything, testPvzID).Return(&models.PVZ{ID: testPvzID, City: "Москва"}, nil)
+		pvzQueries.On("GetPVZ", mock.Anything, testReassignTargetPvzID).Return(&models.PVZ{ID: testReassignTargetPvzID, City: "Казань"}, nil)
+
+		w := reassignRequest(r, testReassignReceptionID, "pvzId="+testReassignTargetPvzID)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		pvzQueries.AssertNotCalled(t, "LockPVZ", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Без ПВЗ назначения", func(t *testing.T) {
+		r, _, _, _ := setupReassignTest(models.RoleModerator, moderatorCities{})
+
+		w := reassignRequest(r, testReassignReceptionID, "")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
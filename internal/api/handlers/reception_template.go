@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReceptionTemplateHandler содержит обработчики шаблонов приёмок для повторяющихся поставок
+type ReceptionTemplateHandler struct {
+	templates queries.ReceptionTemplateQueriesInterface
+}
+
+// NewReceptionTemplateHandler создает новый экземпляр ReceptionTemplateHandler
+func NewReceptionTemplateHandler(templates queries.ReceptionTemplateQueriesInterface) *ReceptionTemplateHandler {
+	return &ReceptionTemplateHandler{
+		templates: templates,
+	}
+}
+
+// ListTemplates обрабатывает запрос списка шаблонов приёмок со строками
+func (h *ReceptionTemplateHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.templates.ListTemplates(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetTemplatesFailed, err))
+		return
+	}
+
+	if templates == nil {
+		templates = []models.ReceptionTemplate{}
+	}
+	for i := range templates {
+		if templates[i].Lines == nil {
+			templates[i].Lines = []models.ReceptionTemplateLine{}
+		}
+	}
+
+	response.JSON(c, http.StatusOK, templates)
+}
+
+// GetTemplate обрабатывает запрос шаблона приёмки
+func (h *ReceptionTemplateHandler) GetTemplate(c *gin.Context) {
+	templateID := c.Param("templateId")
+	if _, err := uuid.Parse(templateID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgTemplateNotFound))
+		return
+	}
+
+	template, err := h.templates.GetTemplate(c.Request.Context(), templateID)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgTemplateNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetTemplatesFailed, err))
+		return
+	}
+
+	respondTemplate(c, http.StatusOK, template)
+}
+
+// CreateTemplate обрабатывает запрос на создание шаблона приёмки
+func (h *ReceptionTemplateHandler) CreateTemplate(c *gin.Context) {
+	var req models.ReceptionTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	template, err := h.templates.CreateTemplate(c.Request.Context(), c.GetString("userID"), req)
+	if errors.Is(err, queries.ErrTemplateNameTaken) {
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgTemplateNameTaken))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgSaveTemplateFailed, err))
+		return
+	}
+
+	respondTemplate(c, http.StatusCreated, template)
+}
+
+// UpdateTemplate обрабатывает запрос на изменение шаблона приёмки. Строки шаблона заменяются целиком,
+// уже созданные по шаблону приёмки не меняются
+func (h *ReceptionTemplateHandler) UpdateTemplate(c *gin.Context) {
+	templateID := c.Param("templateId")
+	if _, err := uuid.Parse(templateID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgTemplateNotFound))
+		return
+	}
+
+	var req models.ReceptionTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	template, err := h.templates.UpdateTemplate(c.Request.Context(), templateID, req)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgTemplateNotFound))
+		return
+	}
+	if errors.Is(err, queries.ErrTemplateNameTaken) {
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgTemplateNameTaken))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgSaveTemplateFailed, err))
+		return
+	}
+
+	respondTemplate(c, http.StatusOK, template)
+}
+
+// DeleteTemplate обрабатывает запрос на удаление шаблона приёмки
+func (h *ReceptionTemplateHandler) DeleteTemplate(c *gin.Context) {
+	templateID := c.Param("templateId")
+	if _, err := uuid.Parse(templateID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgTemplateNotFound))
+		return
+	}
+
+	err := h.templates.DeleteTemplate(c.Request.Context(), templateID)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgTemplateNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgDeleteTemplateFailed, err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondTemplate возвращает шаблон приёмки, шаблон без строк - с пустым списком lines
+func respondTemplate(c *gin.Context, code int, template *models.ReceptionTemplate) {
+	if template.Lines == nil {
+		template.Lines = []models.ReceptionTemplateLine{}
+	}
+	response.JSON(c, code, template)
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/storage"
+)
+
+const testTemplateID = "323e4567-e89b-12d3-a456-426614174000"
+
+// templateStore - шаблоны приёмок по ID и приёмки, к которым применялся шаблон
+type templateStore struct {
+	queries.ReceptionTemplateQueriesInterface
+	templates map[string]*models.ReceptionTemplate
+	applied   []string
+}
+
+func (s *templateStore) GetTemplate(ctx context.Context, templateID string) (*models.ReceptionTemplate, error) {
+	template, ok := s.templates[templateID]
+	if !ok {
+		return nil, queries.ErrNotFound
+	}
+	return template, nil
+}
+
+func (s *templateStore) CreateTemplate(ctx context.Context, authorID string, req models.ReceptionTemplateRequest) (*models.ReceptionTemplate, error) {
+	for _, template := range s.templates {
+		if template.Name == req.Name {
+			return nil, queries.ErrTemplateNameTaken
+		}
+	}
+	return &models.ReceptionTemplate{ID: "new-template", Name: req.Name, Note: req.Note, Capacity: req.Capacity, Lines: req.Lines}, nil
+}
+
+func (s *templateStore) ApplyTemplate(ctx context.Context, receptionID, templateID string) (*models.Reception, error) {
+	s.applied = append(s.applied, receptionID)
+	return &models.Reception{ID: receptionID, PvzID: "pvz-1", Status: "in_progress", Capacity: s.templates[templateID].Capacity, TemplateID: &templateID}, nil
+}
+
+// setupReceptionTemplateTest настраивает создание приёмок и шаблонов с шаблоном testTemplateID на 40 товаров
+func setupReceptionTemplateTest() (*gin.Engine, *templateStore, *MockReceptionQueries, *recordingOutbox) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	capacity := 40
+	store := &templateStore{templates: map[string]*models.ReceptionTemplate{
+		testTemplateID: {ID: testTemplateID, Name: "Обувь по пятницам", Note: "Коробки по 10 пар", Capacity: &capacity},
+	}}
+	receptionQueries := new(MockReceptionQueries)
+	outbox := &recordingOutbox{}
+
+	receptionHandler := NewReceptionHandler(receptionQueries, new(MockProductQueries), new(MockManifestQueries), store, nil, passthroughTx{}, outbox, alwaysOpen{}, nil, noQuotas{}, noLocks{})
+	templateHandler := NewReceptionTemplateHandler(store)
+
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", "user-1")
+		c.Set("userRole", models.RoleEmployee)
+		c.Next()
+	})
+	r.POST("/receptions", receptionHandler.CreateReception)
+	r.POST("/reception-templates", templateHandler.CreateTemplate)
+
+	return r, store, receptionQueries, outbox
+}
+
+// TestCreateReceptionFromTemplate проверяет копирование шаблона в новую приёмку
+func TestCreateReceptionFromTemplate(t *testing.T) {
+	r, store, receptionQueries, outbox := setupReceptionTemplateTest()
+
+	receptionQueries.On("CreateReception", mock.Anything, "123e4567-e89b-12d3-a456-426614174000", "user-1").
+		Return(&models.Reception{ID: "reception-1", PvzID: "pvz-1", Status: "in_progress", DateTime: time.Now()}, nil)
+	receptionQueries.On("UpdateReceptionNotes", mock.Anything, "reception-1", "user-1", mock.MatchedBy(func(note *string) bool {
+		return note != nil && *note == "Коробки по 10 пар"
+	}), []string(nil)).
+		Return(&models.Reception{ID: "reception-1", PvzID: "pvz-1", Status: "in_progress", Note: "Коробки по 10 пар", Capacity: store.templates[testTemplateID].Capacity, TemplateID: stringPtr(testTemplateID)}, nil)
+
+	w := postJSON(r, "/receptions?templateId="+testTemplateID, models.CreateReceptionRequest{PvzID: "123e4567-e89b-12d3-a456-426614174000"})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var reception models.ReceptionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &reception))
+	assert.Equal(t, "Коробки по 10 пар", reception.Note)
+	assert.Equal(t, 40, *reception.Capacity)
+	assert.Equal(t, testTemplateID, *reception.TemplateID)
+	assert.Equal(t, []string{"reception-1"}, store.applied)
+
+	// Событие о создании приёмки содержит данные шаблона
+	require.Len(t, outbox.events, 1)
+	assert.Equal(t, 40, *outbox.events[0].Payload.(models.ReceptionResponse).Capacity)
+
+	receptionQueries.AssertExpectations(t)
+}
+
+// TestCreateReceptionUnknownTemplate проверяет, что по неизвестному шаблону приёмка не создается
+func TestCreateReceptionUnknownTemplate(t *testing.T) {
+	r, store, receptionQueries, _ := setupReceptionTemplateTest()
+
+	w := postJSON(r, "/receptions?templateId=423e4567-e89b-12d3-a456-426614174000", models.CreateReceptionRequest{PvzID: "123e4567-e89b-12d3-a456-426614174000"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = postJSON(r, "/receptions?templateId=weekly", models.CreateReceptionRequest{PvzID: "123e4567-e89b-12d3-a456-426614174000"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	assert.Empty(t, store.applied)
+	receptionQueries.AssertNotCalled(t, "CreateReception", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestCreateReceptionTemplate проверяет создание шаблона и отказ при занятом названии
+func TestCreateReceptionTemplate(t *testing.T) {
+	r, _, _, _ := setupReceptionTemplateTest()
+
+	req := models.ReceptionTemplateRequest{
+		Name:  "Электроника по вторникам",
+		Lines: []models.ReceptionTemplateLine{{Type: models.ProductTypeElectronics, Quantity: 12}},
+	}
+	w := postJSON(r, "/reception-templates", req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var template models.ReceptionTemplate
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &template))
+	assert.Equal(t, req.Lines, template.Lines)
+
+	req.Name = "Обувь по пятницам"
+	w = postJSON(r, "/reception-templates", req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// Строка с неизвестным типом товара не проходит проверку
+	req.Name = "Посуда"
+	req.Lines = []models.ReceptionTemplateLine{{Type: "посуда", Quantity: 1}}
+	w = postJSON(r, "/reception-templates", req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestAddProductReceptionFull проверяет отказ в добавлении товара в заполненную по вместимости приёмку
+func TestAddProductReceptionFull(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	productQueries := new(MockProductQueries)
+	receptionQueries := new(MockReceptionQueries)
+	productHandler := newTestProductHandler(productQueries, receptionQueries, &recordingOutbox{}, storage.Disabled{}, productLimits{}, alwaysOpen{}, noQuotas{}, noLocks{})
+	r.POST("/products", func(c *gin.Context) {
+		c.Set("userRole", models.RoleEmployee)
+		productHandler.AddProduct(c)
+	})
+
+	capacity := 40
+	reception := models.Reception{ID: testReceptionID, PvzID: testPvzID, Status: "in_progress", Capacity: &capacity, ProductCount: 40}
+	receptionQueries.On("GetLastOpenReception", mock.Anything, testPvzID).Return(&reception, nil)
+	receptionQueries.On("LockReceptions", mock.Anything, []string{testReceptionID}).Return([]models.Reception{reception}, nil)
+
+	w := postJSON(r, "/products", models.CreateProductRequest{Type: models.ProductTypeShoes, PvzID: testPvzID})
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	productQueries.AssertNotCalled(t, "AddProduct", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
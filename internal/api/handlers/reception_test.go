@@ -2,10 +2,13 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"pvz-service/internal/db/queries"
 	"pvz-service/internal/models"
 	"testing"
 	"time"
@@ -18,6 +21,40 @@ import (
 // MockReceptionQueries уже должен быть определен в других тестах
 // Если нет, используем определение из предыдущих тестов
 
+// MockManifestQueries мокирует запросы для работы с накладными
+type MockManifestQueries struct {
+	mock.Mock
+}
+
+func (m *MockManifestQueries) ReplaceExpectedProducts(ctx context.Context, receptionID string, lines []models.ExpectedProduct) error {
+	args := m.Called(ctx, receptionID, lines)
+	return args.Error(0)
+}
+
+func (m *MockManifestQueries) GetExpectedProducts(ctx context.Context, receptionID string) ([]models.ExpectedProduct, error) {
+	args := m.Called(ctx, receptionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ExpectedProduct), args.Error(1)
+}
+
+func (m *MockManifestQueries) RecordForcedClose(ctx context.Context, receptionID, userID, reason string, discrepancies *models.ManifestDiscrepancies) error {
+	args := m.Called(ctx, receptionID, userID, reason, discrepancies)
+	return args.Error(0)
+}
+
+// newReceptionHandlerWithoutManifest создает обработчик, для которого накладные не загружены
+// и поврежденных товаров нет
+func newReceptionHandlerWithoutManifest(receptionQueries *MockReceptionQueries) *ReceptionHandler {
+	manifestQueries := new(MockManifestQueries)
+	manifestQueries.On("GetExpectedProducts", mock.Anything, mock.Anything).Return([]models.ExpectedProduct{}, nil).Maybe()
+	productQueries := new(MockProductQueries)
+	productQueries.On("GetReceptionDamages", mock.Anything, mock.Anything).Return([]models.ProductDamage{}, nil).Maybe()
+
+	return NewReceptionHandler(receptionQueries, productQueries, manifestQueries, nil, nil, passthroughTx{}, &recordingOutbox{}, alwaysOpen{}, nil, noQuotas{}, noLocks{})
+}
+
 // Настройка тестового окружения
 func setupReceptionTest() (*gin.Engine, *MockReceptionQueries) {
 	gin.SetMode(gin.TestMode)
@@ -25,7 +62,7 @@ func setupReceptionTest() (*gin.Engine, *MockReceptionQueries) {
 
 	receptionQueries := new(MockReceptionQueries)
 
-	receptionHandler := NewReceptionHandler(receptionQueries)
+	receptionHandler := newReceptionHandlerWithoutManifest(receptionQueries)
 
 	// Настраиваем маршруты
 	r.POST("/receptions", func(c *gin.Context) {
@@ -48,14 +85,14 @@ func TestCreateReceptionSuccess(t *testing.T) {
 	pvzID := "123e4567-e89b-12d3-a456-426614174000"
 	testReception := &models.Reception{
 		ID:       "223e4567-e89b-12d3-a456-426614174000",
+		Number:   "MSK001-2025-000123",
 		DateTime: time.Date(2025, 4, 16, 4, 16, 0, 0, time.UTC),
 		PvzID:    pvzID,
 		Status:   "inprogress",
 	}
 
 	// Настраиваем моки
-	receptionQueries.On("CheckOpenReception", mock.Anything, pvzID).Return(false, nil)
-	receptionQueries.On("CreateReception", mock.Anything, pvzID).Return(testReception, nil)
+	receptionQueries.On("CreateReception", mock.Anything, pvzID, mock.Anything).Return(testReception, nil)
 
 	// Создаем запрос
 	reqBody := models.CreateReceptionRequest{
@@ -80,6 +117,7 @@ func TestCreateReceptionSuccess(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.Equal(t, testReception.ID, response.ID)
+	assert.Equal(t, testReception.Number, response.Number)
 	assert.Equal(t, testReception.PvzID, response.PvzID)
 	assert.Equal(t, testReception.Status, response.Status)
 
@@ -94,7 +132,7 @@ func TestCreateReceptionForbidden(t *testing.T) {
 	r := gin.Default()
 
 	receptionQueries := new(MockReceptionQueries)
-	receptionHandler := NewReceptionHandler(receptionQueries)
+	receptionHandler := newReceptionHandlerWithoutManifest(receptionQueries)
 
 	// Настраиваем маршрут с ролью модератора
 	r.POST("/receptions", func(c *gin.Context) {
@@ -124,19 +162,26 @@ func TestCreateReceptionForbidden(t *testing.T) {
 	assert.Contains(t, response.Message, "Доступ запрещен")
 
 	// Проверяем, что моки НЕ были вызваны
-	receptionQueries.AssertNotCalled(t, "CheckOpenReception")
 	receptionQueries.AssertNotCalled(t, "CreateReception")
 }
 
-// TestCreateReceptionAlreadyExists проверяет случай с уже существующей открытой приёмкой
+// TestCreateReceptionAlreadyExists проверяет конфликт с уже существующей открытой приёмкой:
+// в ответе возвращается открытая приёмка
 func TestCreateReceptionAlreadyExists(t *testing.T) {
 	r, receptionQueries := setupReceptionTest()
 
 	// Создаем тестовые данные
 	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	openReception := &models.Reception{
+		ID:       "223e4567-e89b-12d3-a456-426614174000",
+		Number:   "MSK001-2025-000122",
+		DateTime: time.Date(2025, 4, 16, 4, 16, 0, 0, time.UTC),
+		PvzID:    pvzID,
+		Status:   "in_progress",
+	}
 
 	// Настраиваем моки - уже есть открытая приёмка
-	receptionQueries.On("CheckOpenReception", mock.Anything, pvzID).Return(true, nil)
+	receptionQueries.On("CreateReception", mock.Anything, pvzID, mock.Anything).Return(nil, &queries.ReceptionOpenError{Reception: openReception})
 
 	// Создаем запрос
 	reqBody := models.CreateReceptionRequest{
@@ -150,17 +195,21 @@ func TestCreateReceptionAlreadyExists(t *testing.T) {
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
-	// Проверяем ответ - должен быть статус 400 Bad Request
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	// Проверяем ответ - должен быть статус 409 Conflict
+	assert.Equal(t, http.StatusConflict, w.Code)
 
-	var response models.ErrorResponse
+	var response struct {
+		Message string                   `json:"message"`
+		Details models.ReceptionResponse `json:"details"`
+	}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.Contains(t, response.Message, "уже есть незакрытая приёмка")
+	assert.Equal(t, openReception.ID, response.Details.ID)
+	assert.Equal(t, openReception.Number, response.Details.Number)
 
 	// Проверяем, что моки были вызваны с правильными аргументами
 	receptionQueries.AssertExpectations(t)
-	receptionQueries.AssertNotCalled(t, "CreateReception")
 }
 
 // TestCreateReceptionInvalidRequest проверяет случай с некорректным запросом
@@ -188,19 +237,18 @@ func TestCreateReceptionInvalidRequest(t *testing.T) {
 	assert.Contains(t, response.Message, "Неверный запрос")
 
 	// Проверяем, что моки НЕ были вызваны
-	receptionQueries.AssertNotCalled(t, "CheckOpenReception")
 	receptionQueries.AssertNotCalled(t, "CreateReception")
 }
 
-// TestCreateReceptionCheckError проверяет ошибку при проверке открытых приёмок
-func TestCreateReceptionCheckError(t *testing.T) {
+// TestCreateReceptionPVZNotFound проверяет создание приёмки в несуществующем ПВЗ
+func TestCreateReceptionPVZNotFound(t *testing.T) {
 	r, receptionQueries := setupReceptionTest()
 
 	// Создаем тестовые данные
 	pvzID := "123e4567-e89b-12d3-a456-426614174000"
 
-	// Настраиваем моки - ошибка при проверке
-	receptionQueries.On("CheckOpenReception", mock.Anything, pvzID).Return(false, errors.New("database error"))
+	// Настраиваем моки - ПВЗ не найден
+	receptionQueries.On("CreateReception", mock.Anything, pvzID, mock.Anything).Return(nil, queries.ErrNotFound)
 
 	// Создаем запрос
 	reqBody := models.CreateReceptionRequest{
@@ -214,17 +262,11 @@ func TestCreateReceptionCheckError(t *testing.T) {
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
-	// Проверяем ответ - должен быть статус 500 Internal Server Error
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
-
-	var response models.ErrorResponse
-	err := json.Unmarshal(w.Body.Bytes(), &response)
-	assert.NoError(t, err)
-	assert.Contains(t, response.Message, "Ошибка при проверке открытых приёмок")
+	// Проверяем ответ - должен быть статус 404 Not Found
+	assert.Equal(t, http.StatusNotFound, w.Code)
 
 	// Проверяем, что моки были вызваны с правильными аргументами
 	receptionQueries.AssertExpectations(t)
-	receptionQueries.AssertNotCalled(t, "CreateReception")
 }
 
 // TestCreateReceptionCreateError проверяет ошибку при создании приёмки
@@ -235,8 +277,7 @@ func TestCreateReceptionCreateError(t *testing.T) {
 	pvzID := "123e4567-e89b-12d3-a456-426614174000"
 
 	// Настраиваем моки - ошибка при создании
-	receptionQueries.On("CheckOpenReception", mock.Anything, pvzID).Return(false, nil)
-	receptionQueries.On("CreateReception", mock.Anything, pvzID).Return(nil, errors.New("database error"))
+	receptionQueries.On("CreateReception", mock.Anything, pvzID, mock.Anything).Return(nil, errors.New("database error"))
 
 	// Создаем запрос
 	reqBody := models.CreateReceptionRequest{
@@ -287,7 +328,8 @@ func TestCloseLastReceptionSuccess(t *testing.T) {
 
 	// Настраиваем моки
 	receptionQueries.On("GetLastOpenReception", mock.Anything, pvzID).Return(openReception, nil)
-	receptionQueries.On("CloseReception", mock.Anything, receptionID).Return(closedReception, nil)
+	receptionQueries.On("LockReceptions", mock.Anything, []string{receptionID}).Return([]models.Reception{{ID: receptionID, PvzID: pvzID, Status: "in_progress"}}, nil)
+	receptionQueries.On("CloseReception", mock.Anything, receptionID, mock.Anything).Return(closedReception, nil)
 
 	// Создаем запрос
 	req, _ := http.NewRequest("POST", "/pvz/"+pvzID+"/close_last_reception", nil)
@@ -322,7 +364,7 @@ func TestCloseLastReceptionEmptyPvzID(t *testing.T) {
 	r.RemoveExtraSlash = true
 
 	receptionQueries := new(MockReceptionQueries)
-	receptionHandler := NewReceptionHandler(receptionQueries)
+	receptionHandler := newReceptionHandlerWithoutManifest(receptionQueries)
 
 	// Настраиваем маршрут с пустым параметром pvzId
 	r.POST("/pvz//close_last_reception", receptionHandler.CloseLastReception)
@@ -395,7 +437,8 @@ func TestCloseLastReceptionCloseError(t *testing.T) {
 
 	// Настраиваем моки - ошибка при закрытии
 	receptionQueries.On("GetLastOpenReception", mock.Anything, pvzID).Return(openReception, nil)
-	receptionQueries.On("CloseReception", mock.Anything, receptionID).Return(nil, errors.New("database error"))
+	receptionQueries.On("LockReceptions", mock.Anything, []string{receptionID}).Return([]models.Reception{{ID: receptionID, PvzID: pvzID, Status: "in_progress"}}, nil)
+	receptionQueries.On("CloseReception", mock.Anything, receptionID, mock.Anything).Return(nil, errors.New("database error"))
 
 	// Создаем запрос
 	req, _ := http.NewRequest("POST", "/pvz/"+pvzID+"/close_last_reception", nil)
@@ -415,3 +458,412 @@ func TestCloseLastReceptionCloseError(t *testing.T) {
 	// Проверяем, что моки были вызваны с правильными аргументами
 	receptionQueries.AssertExpectations(t)
 }
+
+// TestCloseLastReceptionAlreadyClosed проверяет, что приёмка, закрытая параллельным запросом
+// после того, как ее нашел обработчик, не закрывается второй раз
+func TestCloseLastReceptionAlreadyClosed(t *testing.T) {
+	r, receptionQueries := setupReceptionTest()
+
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	receptionID := "223e4567-e89b-12d3-a456-426614174000"
+
+	receptionQueries.On("GetLastOpenReception", mock.Anything, pvzID).Return(&models.Reception{ID: receptionID, PvzID: pvzID, Status: "in_progress"}, nil)
+	receptionQueries.On("LockReceptions", mock.Anything, []string{receptionID}).Return([]models.Reception{{ID: receptionID, PvzID: pvzID, Status: "close"}}, nil)
+
+	req, _ := http.NewRequest("POST", "/pvz/"+pvzID+"/close_last_reception", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	receptionQueries.AssertNotCalled(t, "CloseReception", mock.Anything, mock.Anything, mock.Anything)
+	receptionQueries.AssertExpectations(t)
+}
+
+// TestGetOverdueReceptions проверяет список приёмок, не закрытых в срок SLA
+func TestGetOverdueReceptions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	receptionQueries := new(MockReceptionQueries)
+	r.GET("/receptions/overdue", newReceptionHandlerWithoutManifest(receptionQueries).GetOverdueReceptions)
+
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	receptionQueries.On("GetOverdueReceptions", mock.Anything, pvzID).Return([]models.OverdueReception{
+		{ID: "reception-uuid", DateTime: time.Now().Add(-13 * time.Hour), PvzID: pvzID, Status: "in_progress", FlaggedAt: time.Now()},
+	}, nil)
+	receptionQueries.On("GetOverdueReceptions", mock.Anything, "").Return(nil, errors.New("database error"))
+
+	req, _ := http.NewRequest("GET", "/receptions/overdue?pvzId="+pvzID, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []models.OverdueReceptionResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response, 1)
+	assert.Equal(t, "reception-uuid", response[0].ID)
+
+	req, _ = http.NewRequest("GET", "/receptions/overdue", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	req, _ = http.NewRequest("GET", "/receptions/overdue?pvzId=invalid", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestListReceptions проверяет страницу приёмок ПВЗ с фильтром по статусу и ошибки параметров
+func TestListReceptions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	receptionQueries := new(MockReceptionQueries)
+	r.GET("/pvz/:pvzId/receptions", newReceptionHandlerWithoutManifest(receptionQueries).ListReceptions)
+
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	receptionQueries.On("ListReceptions", mock.Anything, pvzID, models.ReceptionListQuery{Status: "close", Page: 2, Limit: 1}).
+		Return([]models.Reception{{ID: "reception-2", PvzID: pvzID, Status: "close"}}, 3, nil)
+
+	req, _ := http.NewRequest("GET", "/pvz/"+pvzID+"/receptions?status=close&page=2&limit=1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "3", w.Header().Get("X-Total-Count"))
+	assert.Len(t, w.Header().Values("Link"), 2)
+	var receptions []models.ReceptionResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &receptions))
+	assert.Len(t, receptions, 1)
+	assert.Equal(t, "reception-2", receptions[0].ID)
+
+	for _, tt := range []struct {
+		path string
+		code int
+	}{
+		{"/pvz/invalid/receptions", http.StatusNotFound},
+		{"/pvz/" + pvzID + "/receptions?status=unknown", http.StatusBadRequest},
+		{"/pvz/" + pvzID + "/receptions?limit=101", http.StatusBadRequest},
+		{"/pvz/" + pvzID + "/receptions?page=0", http.StatusBadRequest},
+	} {
+		req, _ := http.NewRequest("GET", tt.path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, tt.code, w.Code, tt.path)
+	}
+	receptionQueries.AssertNumberOfCalls(t, "ListReceptions", 1)
+}
+
+// setupPauseReceptionTest создает маршруты паузы и возобновления приёмки для пользователя с ролью role
+func setupPauseReceptionTest(role string) (*gin.Engine, *MockReceptionQueries, *recordingOutbox) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	receptionQueries := new(MockReceptionQueries)
+	outbox := &recordingOutbox{}
+	handler := NewReceptionHandler(receptionQueries, new(MockProductQueries), new(MockManifestQueries), nil, nil, passthroughTx{}, outbox, alwaysOpen{}, nil, noQuotas{}, noLocks{})
+	setRole := func(c *gin.Context) { c.Set("userRole", role) }
+	r.POST("/pvz/:pvzId/pause_reception", setRole, handler.PauseReception)
+	r.POST("/pvz/:pvzId/resume_reception", setRole, handler.ResumeReception)
+	return r, receptionQueries, outbox
+}
+
+// TestPauseReception проверяет приостановку открытой приёмки с событием в ленте ПВЗ
+func TestPauseReception(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+
+	t.Run("Открытая приёмка приостанавливается", func(t *testing.T) {
+		r, receptionQueries, outbox := setupPauseReceptionTest(models.RoleEmployee)
+		receptionQueries.On("PauseReception", mock.Anything, pvzID).
+			Return(&models.Reception{ID: "reception-uuid", PvzID: pvzID, Status: "paused"}, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/pvz/"+pvzID+"/pause_reception", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var reception models.ReceptionResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &reception))
+		assert.Equal(t, "paused", reception.Status)
+		assert.Len(t, outbox.events, 1)
+		assert.Equal(t, models.EventReceptionPaused, outbox.events[0].Type)
+	})
+
+	t.Run("Нет открытой приёмки", func(t *testing.T) {
+		r, receptionQueries, outbox := setupPauseReceptionTest(models.RoleEmployee)
+		receptionQueries.On("PauseReception", mock.Anything, pvzID).
+			Return(nil, fmt.Errorf("open reception for pvz %s: %w", pvzID, queries.ErrNotFound))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/pvz/"+pvzID+"/pause_reception", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, outbox.events)
+	})
+
+	t.Run("Модератор не приостанавливает приёмки", func(t *testing.T) {
+		r, receptionQueries, _ := setupPauseReceptionTest(models.RoleModerator)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/pvz/"+pvzID+"/pause_reception", nil))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		receptionQueries.AssertNotCalled(t, "PauseReception", mock.Anything, mock.Anything)
+	})
+}
+
+// TestResumeReception проверяет возобновление приостановленной приёмки и конфликт с открытой за время паузы
+func TestResumeReception(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+
+	t.Run("Приостановленная приёмка возобновляется", func(t *testing.T) {
+		r, receptionQueries, outbox := setupPauseReceptionTest(models.RoleEmployee)
+		receptionQueries.On("ResumeReception", mock.Anything, pvzID).
+			Return(&models.Reception{ID: "reception-uuid", PvzID: pvzID, Status: "in_progress"}, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/pvz/"+pvzID+"/resume_reception", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, outbox.events, 1)
+		assert.Equal(t, models.EventReceptionResumed, outbox.events[0].Type)
+	})
+
+	t.Run("За время паузы открыта другая приёмка", func(t *testing.T) {
+		r, receptionQueries, _ := setupPauseReceptionTest(models.RoleEmployee)
+		open := &models.Reception{ID: "other-reception", PvzID: pvzID, Status: "in_progress"}
+		receptionQueries.On("ResumeReception", mock.Anything, pvzID).Return(nil, &queries.ReceptionOpenError{Reception: open})
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/pvz/"+pvzID+"/resume_reception", nil))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "other-reception")
+	})
+
+	t.Run("Нет приостановленной приёмки", func(t *testing.T) {
+		r, receptionQueries, _ := setupPauseReceptionTest(models.RoleEmployee)
+		receptionQueries.On("ResumeReception", mock.Anything, pvzID).
+			Return(nil, fmt.Errorf("paused reception for pvz %s: %w", pvzID, queries.ErrNotFound))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/pvz/"+pvzID+"/resume_reception", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+const testReceptionID = "123e4567-e89b-12d3-a456-426614174040"
+
+// setupUpdateReceptionTest создает маршрут изменения заметок приёмки для пользователя с ролью role
+func setupUpdateReceptionTest(role string) (*gin.Engine, *MockReceptionQueries) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	receptionQueries := new(MockReceptionQueries)
+	receptionHandler := newReceptionHandlerWithoutManifest(receptionQueries)
+
+	r.PATCH("/receptions/:receptionId", func(c *gin.Context) {
+		c.Set("userRole", role)
+		c.Set("userID", testEmployeeID)
+		receptionHandler.UpdateReception(c)
+	})
+
+	return r, receptionQueries
+}
+
+func patchJSON(r *gin.Engine, path, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("PATCH", path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestUpdateReceptionNormalizesTags проверяет сохранение заметки и нормализацию тегов
+func TestUpdateReceptionNormalizesTags(t *testing.T) {
+	r, receptionQueries := setupUpdateReceptionTest("employee")
+
+	note := "Коробка помята"
+	receptionQueries.On("UpdateReceptionNotes", mock.Anything, testReceptionID, testEmployeeID, &note, []string{"повреждена упаковка", "срочно"}).
+		Return(&models.Reception{
+			ID:     testReceptionID,
+			PvzID:  "pvz-uuid",
+			Status: "in_progress",
+			Note:   note,
+			Tags:   []string{"повреждена упаковка", "срочно"},
+		}, nil)
+
+	w := patchJSON(r, "/receptions/"+testReceptionID,
+		`{"note": " Коробка помята ", "tags": ["Повреждена упаковка", "срочно", " повреждена упаковка ", ""]}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result models.ReceptionResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, note, result.Note)
+	assert.Equal(t, []string{"повреждена упаковка", "срочно"}, result.Tags)
+	receptionQueries.AssertExpectations(t)
+}
+
+// TestUpdateReceptionValidation проверяет отказы без обращения к базе данных
+func TestUpdateReceptionValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		role string
+		path string
+		body string
+		code int
+	}{
+		{"Модератор не меняет заметки", "moderator", "/receptions/" + testReceptionID, `{"note": "x"}`, http.StatusForbidden},
+		{"Пустой запрос", "employee", "/receptions/" + testReceptionID, `{}`, http.StatusBadRequest},
+		{"Слишком много тегов", "employee", "/receptions/" + testReceptionID, `{"tags": ["1","2","3","4","5","6","7","8","9","10","11"]}`, http.StatusBadRequest},
+		{"Неверный ID приёмки", "employee", "/receptions/not-a-uuid", `{"note": "x"}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, receptionQueries := setupUpdateReceptionTest(tt.role)
+
+			w := patchJSON(r, tt.path, tt.body)
+
+			assert.Equal(t, tt.code, w.Code)
+			receptionQueries.AssertNotCalled(t, "UpdateReceptionNotes", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestUpdateReceptionNotFound проверяет ответ для несуществующей приёмки
+func TestUpdateReceptionNotFound(t *testing.T) {
+	r, receptionQueries := setupUpdateReceptionTest("employee")
+
+	receptionQueries.On("UpdateReceptionNotes", mock.Anything, testReceptionID, testEmployeeID, (*string)(nil), []string{}).
+		Return(nil, queries.ErrNotFound)
+
+	w := patchJSON(r, "/receptions/"+testReceptionID, `{"tags": []}`)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// TestCloseStaleReceptions проверяет закрытие зависших приёмок и запись событий о закрытии
+func TestCloseStaleReceptions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	receptionQueries := new(MockReceptionQueries)
+	outbox := &recordingOutbox{}
+	manifestQueries := new(MockManifestQueries)
+	receptionHandler := NewReceptionHandler(receptionQueries, new(MockProductQueries), manifestQueries, nil, nil, passthroughTx{}, outbox, alwaysOpen{}, nil, noQuotas{}, noLocks{})
+	r.POST("/admin/receptions/close_stale", receptionHandler.CloseStaleReceptions)
+
+	receptionQueries.On("CloseStaleReceptions", mock.Anything, mock.MatchedBy(func(openedBefore time.Time) bool {
+		return time.Since(openedBefore) > 47*time.Hour && time.Since(openedBefore) < 49*time.Hour
+	})).Return([]models.Reception{
+		{ID: "reception-1", PvzID: "pvz-1", Status: "close"},
+		{ID: "reception-2", PvzID: "pvz-2", Status: "close"},
+	}, nil)
+
+	req, _ := http.NewRequest("POST", "/admin/receptions/close_stale?olderThan=48h", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result models.CloseStaleReceptionsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, []string{"reception-1", "reception-2"}, result.ReceptionIDs)
+	assert.Len(t, outbox.events, 2)
+	assert.Equal(t, models.EventReceptionClosed, outbox.events[1].Type)
+	assert.Equal(t, "pvz-2", outbox.events[1].PvzID)
+	receptionQueries.AssertExpectations(t)
+}
+
+// TestCloseStaleReceptionsErrors проверяет неверный порог и ошибку базы данных
+func TestCloseStaleReceptionsErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	receptionQueries := new(MockReceptionQueries)
+	r.POST("/admin/receptions/close_stale", newReceptionHandlerWithoutManifest(receptionQueries).CloseStaleReceptions)
+
+	receptionQueries.On("CloseStaleReceptions", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+	for _, tt := range []struct {
+		query string
+		code  int
+	}{
+		{"?olderThan=-1h", http.StatusBadRequest},
+		{"?olderThan=day", http.StatusBadRequest},
+		{"", http.StatusInternalServerError},
+	} {
+		req, _ := http.NewRequest("POST", "/admin/receptions/close_stale"+tt.query, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, tt.code, w.Code, tt.query)
+	}
+	receptionQueries.AssertNumberOfCalls(t, "CloseStaleReceptions", 1)
+}
+
+// TestGetDiscrepancyReport проверяет отчет сверки в JSON и CSV
+func TestGetDiscrepancyReport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	receptionQueries := new(MockReceptionQueries)
+	productQueries := new(MockProductQueries)
+	manifestQueries := new(MockManifestQueries)
+	receptionHandler := NewReceptionHandler(receptionQueries, productQueries, manifestQueries, nil, nil, passthroughTx{}, &recordingOutbox{}, alwaysOpen{}, nil, noQuotas{}, noLocks{})
+	r.GET("/receptions/:receptionId/discrepancies", receptionHandler.GetDiscrepancyReport)
+
+	receptionQueries.On("GetReceptionByID", mock.Anything, testReceptionID).
+		Return(&models.Reception{ID: testReceptionID, Status: "close"}, nil)
+	manifestQueries.On("GetExpectedProducts", mock.Anything, testReceptionID).Return([]models.ExpectedProduct{
+		{Type: models.ProductTypeShoes, Quantity: 2},
+	}, nil)
+	productQueries.On("GetProductsByReception", mock.Anything, testReceptionID).Return([]models.Product{
+		{ID: "p1", Type: models.ProductTypeShoes},
+		{ID: "p2", Type: models.ProductTypeClothes},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/receptions/"+testReceptionID+"/discrepancies", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var report models.DiscrepancyReport
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, 2, report.ExpectedItems)
+	assert.Len(t, report.Missing, 1)
+	assert.Equal(t, 1, report.Missing[0].Quantity)
+	assert.Len(t, report.Extra, 1)
+	assert.Equal(t, "p2", report.Extra[0].ProductID)
+
+	req, _ = http.NewRequest("GET", "/receptions/"+testReceptionID+"/discrepancies?format=csv", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="discrepancies-`+testReceptionID+`.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Contains(t, w.Body.String(), "extra,,,одежда,p2,1\n")
+}
+
+// TestGetDiscrepancyReportWithoutManifest проверяет ответ 404, если накладная не загружена
+func TestGetDiscrepancyReportWithoutManifest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	receptionQueries := new(MockReceptionQueries)
+	r.GET("/receptions/:receptionId/discrepancies", newReceptionHandlerWithoutManifest(receptionQueries).GetDiscrepancyReport)
+
+	receptionQueries.On("GetReceptionByID", mock.Anything, testReceptionID).
+		Return(&models.Reception{ID: testReceptionID, Status: "in_progress"}, nil)
+
+	req, _ := http.NewRequest("GET", "/receptions/"+testReceptionID+"/discrepancies", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	var response models.ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Накладная для приёмки не загружена", response.Message)
+}
@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/city"
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+	"pvz-service/internal/service"
+	"pvz-service/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// defaultDuplicateWindow - окно поиска дублей штрихкодов по умолчанию
+const defaultDuplicateWindow = 24 * time.Hour
+
+// ReportHandler содержит обработчики для отчётов
+type ReportHandler struct {
+	reportQueries    queries.ReportQueriesInterface
+	reportJobQueries queries.ReportJobQueriesInterface
+	cityResolver     city.ResolverInterface
+	storage          storage.Storage
+	consistency      *service.ConsistencyChecker
+	exporter         *service.ReceptionExporter
+	clock            clock.Clock
+}
+
+// NewReportHandler создает новый экземпляр ReportHandler.
+// Отчёты, сформированные в фоне, сохраняются в хранилище вложений
+func NewReportHandler(reportQueries queries.ReportQueriesInterface, reportJobQueries queries.ReportJobQueriesInterface, cityResolver city.ResolverInterface, storage storage.Storage) *ReportHandler {
+	return &ReportHandler{
+		reportQueries:    reportQueries,
+		reportJobQueries: reportJobQueries,
+		cityResolver:     cityResolver,
+		storage:          storage,
+		consistency:      service.NewConsistencyChecker(reportQueries),
+		exporter:         service.NewReceptionExporter(reportQueries, reportJobQueries, storage),
+		clock:            clock.System{},
+	}
+}
+
+// GetDuplicateBarcodes обрабатывает запрос на получение отчёта по дублям штрихкодов
+func (h *ReportHandler) GetDuplicateBarcodes(c *gin.Context) {
+	var query models.DuplicateBarcodesQuery
+
+	// Извлекаем параметры запроса
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+
+	// Определяем окно поиска
+	window := defaultDuplicateWindow
+	if query.Window != "" {
+		parsed, err := time.ParseDuration(query.Window)
+		if err != nil || parsed <= 0 {
+			response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgInvalidReportWindow))
+			return
+		}
+		window = parsed
+	}
+
+	occurrences, err := h.reportQueries.GetDuplicateBarcodes(c.Request.Context(), h.clock.Now().Add(-window))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgReportFailed, err))
+		return
+	}
+
+	// Группируем сканирования по штрихкоду, сохраняя порядок из запроса
+	result := make([]models.DuplicateBarcodeResponse, 0)
+	for _, occurrence := range occurrences {
+		last := len(result) - 1
+		if last < 0 || result[last].Barcode != occurrence.Barcode {
+			result = append(result, models.DuplicateBarcodeResponse{Barcode: occurrence.Barcode})
+			last++
+		}
+		result[last].Occurrences = append(result[last].Occurrences, occurrence)
+	}
+
+	response.JSON(c, http.StatusOK, result)
+}
+
+// GetConsistencyReport обрабатывает запрос на проверку инвариантов данных:
+// несколько открытых приёмок одного ПВЗ, товары, добавленные после закрытия приёмки,
+// и товары в наличии в удаленных ПВЗ. Проверка выполняется при каждом запросе
+func (h *ReportHandler) GetConsistencyReport(c *gin.Context) {
+	report, err := h.consistency.Check(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgConsistencyCheckFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, report)
+}
+
+// GetUserActivity обрабатывает запрос модератора на отчёт о работе сотрудника за период:
+// сколько приёмок он открыл и закрыл, сколько товаров принял и удалил
+func (h *ReportHandler) GetUserActivity(c *gin.Context) {
+	userID := c.Param("userId")
+	if _, err := uuid.Parse(userID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgUserNotFound))
+		return
+	}
+
+	var query models.UserActivityQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+
+	activity, err := h.reportQueries.GetUserActivity(c.Request.Context(), userID, query.From.UTC(), query.To.UTC())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgReportFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, activity)
+}
+
+// GetSupplierReport обрабатывает запрос отчёта о поставках по поставщикам за период:
+// число приёмок и принятых товаров для сверки с бумажными накладными
+func (h *ReportHandler) GetSupplierReport(c *gin.Context) {
+	var query models.SupplierReportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+
+	intake, err := h.reportQueries.GetSupplierIntake(c.Request.Context(), query.From.UTC(), query.To.UTC())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgReportFailed, err))
+		return
+	}
+
+	if intake == nil {
+		intake = []models.SupplierIntake{}
+	}
+
+	response.JSON(c, http.StatusOK, intake)
+}
+
+// GetDamagedProducts обрабатывает запрос отчёта о товарах, отмеченных поврежденными при приёмке за период,
+// вместо бумажного журнала поврежденных товаров
+func (h *ReportHandler) GetDamagedProducts(c *gin.Context) {
+	var query models.DamagedProductsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+
+	damages, err := h.reportQueries.GetDamagedProducts(c.Request.Context(), query.From.UTC(), query.To.UTC())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgReportFailed, err))
+		return
+	}
+
+	if damages == nil {
+		damages = []models.ProductDamage{}
+	}
+
+	response.JSON(c, http.StatusOK, damages)
+}
+
+// ExportReceptions обрабатывает запрос на выгрузку приёмок всех ПВЗ города за период в CSV.
+// Выгрузка отправляется клиенту по мере чтения из БД. С параметром async=true выгрузка
+// формируется в фоне: в ответ возвращается задание отчёта, по которому позже выдается ссылка на файл
+func (h *ReportHandler) ExportReceptions(c *gin.Context) {
+	var query models.ReceptionExportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+
+	// Приводим город к названию из справочника
+	cityName, err := h.cityResolver.Resolve(c.Request.Context(), query.City)
+	if errors.Is(err, city.ErrUnknownCity) {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgUnknownCity)+": "+query.City)
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgExportReceptionsFailed, err))
+		return
+	}
+
+	params := models.ReceptionExportParams{City: cityName, From: query.From.UTC(), To: query.To.UTC()}
+
+	if query.Async {
+		job, err := h.exporter.StartExport(c.Request.Context(), params, c.GetString("userID"))
+		if errors.Is(err, storage.ErrNotConfigured) {
+			response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgReportStorageDisabled))
+			return
+		}
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgExportReceptionsFailed, err))
+			return
+		}
+
+		response.JSON(c, http.StatusAccepted, job)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	response.Attachment(c, fmt.Sprintf("receptions-%s-%s.csv", params.From.Format("20060102"), params.To.Format("20060102")))
+
+	if err := h.exporter.WriteCSV(c.Request.Context(), c.Writer, params); err != nil {
+		// Пока клиенту ничего не отправлено, ошибку можно вернуть статусом 500
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Disposition")
+			response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgExportReceptionsFailed, err))
+			return
+		}
+		log.Printf("Receptions export interrupted: %v", err)
+	}
+}
+
+// GetProductAging обрабатывает запрос отчёта о товарах, которые дольше заданного числа дней
+// лежат на хранении в закрытых приёмках и не выданы. Товары группируются по ПВЗ,
+// с format=csv отчёт выгружается файлом по строке на товар
+func (h *ReportHandler) GetProductAging(c *gin.Context) {
+	query := models.ProductAgingQuery{Days: models.DefaultProductAgingDays}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+
+	// Приводим город к названию из справочника
+	var cityName string
+	if query.City != "" {
+		var err error
+		cityName, err = h.cityResolver.Resolve(c.Request.Context(), query.City)
+		if errors.Is(err, city.ErrUnknownCity) {
+			response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgUnknownCity)+": "+query.City)
+			return
+		}
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgProductAgingFailed, err))
+			return
+		}
+	}
+
+	now := h.clock.Now().UTC()
+	products, err := h.reportQueries.GetAgingProducts(c.Request.Context(), now.AddDate(0, 0, -query.Days), cityName)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgProductAgingFailed, err))
+		return
+	}
+
+	report := service.BuildProductAging(products, query.Days, now)
+
+	if query.Format == "csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		response.Attachment(c, fmt.Sprintf("product-aging-%s.csv", now.Format("20060102")))
+		if err := service.WriteProductAgingCSV(c.Writer, report); err != nil {
+			log.Printf("Product aging export interrupted: %v", err)
+		}
+		return
+	}
+
+	response.JSON(c, http.StatusOK, report)
+}
+
+// GetReportJob обрабатывает запрос статуса задания отчёта. Для готового отчёта
+// в ответе есть ссылка на скачивание, которая выдается заново при каждом запросе
+func (h *ReportHandler) GetReportJob(c *gin.Context) {
+	jobID := c.Param("jobId")
+	if _, err := uuid.Parse(jobID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReportJobNotFound))
+		return
+	}
+
+	job, err := h.reportJobQueries.GetReportJob(c.Request.Context(), jobID)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReportJobNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetReportJobFailed, err))
+		return
+	}
+
+	if job.Status == models.ReportJobDone && job.ObjectKey != nil {
+		job.DownloadURL, err = h.storage.URL(c.Request.Context(), *job.ObjectKey)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetReportJobFailed, err))
+			return
+		}
+	}
+
+	response.JSON(c, http.StatusOK, job)
+}
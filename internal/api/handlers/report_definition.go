@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+	"pvz-service/internal/service"
+	"pvz-service/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReportDefinitionHandler содержит обработчики конструктора отчётов: сохраненные отчёты
+// и их запуск вручную. Отчёты с расписанием запускает фоновое задание
+type ReportDefinitionHandler struct {
+	definitionQueries queries.ReportDefinitionQueriesInterface
+	builder           *service.ReportBuilder
+}
+
+// NewReportDefinitionHandler создает новый экземпляр ReportDefinitionHandler
+func NewReportDefinitionHandler(definitionQueries queries.ReportDefinitionQueriesInterface, builder *service.ReportBuilder) *ReportDefinitionHandler {
+	return &ReportDefinitionHandler{
+		definitionQueries: definitionQueries,
+		builder:           builder,
+	}
+}
+
+// ListDefinitions обрабатывает запрос списка сохраненных отчётов
+func (h *ReportDefinitionHandler) ListDefinitions(c *gin.Context) {
+	definitions, err := h.definitionQueries.ListDefinitions(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetReportDefinitionsFailed, err))
+		return
+	}
+
+	if definitions == nil {
+		definitions = []models.ReportDefinition{}
+	}
+
+	response.JSON(c, http.StatusOK, definitions)
+}
+
+// GetDefinition обрабатывает запрос сохраненного отчёта
+func (h *ReportDefinitionHandler) GetDefinition(c *gin.Context) {
+	definition, ok := h.definition(c)
+	if !ok {
+		return
+	}
+
+	response.JSON(c, http.StatusOK, definition)
+}
+
+// CreateDefinition обрабатывает запрос на сохранение отчёта
+func (h *ReportDefinitionHandler) CreateDefinition(c *gin.Context) {
+	req, ok := bindReportDefinition(c)
+	if !ok {
+		return
+	}
+
+	definition, err := h.definitionQueries.CreateDefinition(c.Request.Context(), req, c.GetString("userID"), h.builder.NextRunAt(req.Schedule))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgSaveReportDefinitionFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusCreated, definition)
+}
+
+// UpdateDefinition обрабатывает запрос на изменение сохраненного отчёта.
+// Следующий запуск по расписанию отсчитывается от изменения
+func (h *ReportDefinitionHandler) UpdateDefinition(c *gin.Context) {
+	definitionID := c.Param("definitionId")
+	if _, err := uuid.Parse(definitionID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReportDefinitionNotFound))
+		return
+	}
+
+	req, ok := bindReportDefinition(c)
+	if !ok {
+		return
+	}
+
+	definition, err := h.definitionQueries.UpdateDefinition(c.Request.Context(), definitionID, req, h.builder.NextRunAt(req.Schedule))
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReportDefinitionNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgSaveReportDefinitionFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, definition)
+}
+
+// DeleteDefinition обрабатывает запрос на удаление сохраненного отчёта
+func (h *ReportDefinitionHandler) DeleteDefinition(c *gin.Context) {
+	definitionID := c.Param("definitionId")
+	if _, err := uuid.Parse(definitionID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReportDefinitionNotFound))
+		return
+	}
+
+	err := h.definitionQueries.DeleteDefinition(c.Request.Context(), definitionID)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReportDefinitionNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgDeleteReportDefinitionFailed, err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RunDefinition обрабатывает запрос на запуск сохраненного отчёта вручную. Отчёт формируется в фоне,
+// в ответе - задание отчёта, по которому готовый отчёт скачивается
+func (h *ReportDefinitionHandler) RunDefinition(c *gin.Context) {
+	definition, ok := h.definition(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.builder.Run(c.Request.Context(), *definition, c.GetString("userID"))
+	if errors.Is(err, storage.ErrNotConfigured) {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgReportStorageDisabled))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgRunReportFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusAccepted, job)
+}
+
+// definition получает сохраненный отчёт из параметра definitionId. Если отчёта нет
+// или его не удалось получить, отвечает ошибкой и возвращает false
+func (h *ReportDefinitionHandler) definition(c *gin.Context) (*models.ReportDefinition, bool) {
+	definitionID := c.Param("definitionId")
+	if _, err := uuid.Parse(definitionID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReportDefinitionNotFound))
+		return nil, false
+	}
+
+	definition, err := h.definitionQueries.GetDefinition(c.Request.Context(), definitionID)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgReportDefinitionNotFound))
+		return nil, false
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetReportDefinitionsFailed, err))
+		return nil, false
+	}
+
+	return definition, true
+}
+
+// bindReportDefinition разбирает запрос сохраненного отчёта и проверяет, что группировки и фильтры
+// доступны для его сущности. При ошибке отвечает 400 и возвращает false
+func bindReportDefinition(c *gin.Context) (models.ReportDefinitionRequest, bool) {
+	var req models.ReportDefinitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return req, false
+	}
+
+	var fieldErr *service.ReportDefinitionError
+	if err := service.ValidateReportDefinition(req); errors.As(err, &fieldErr) {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgUnsupportedReportField)+": "+fieldErr.Field+"="+fieldErr.Value)
+		return req, false
+	}
+
+	return req, true
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/models"
+	"pvz-service/internal/service"
+	"pvz-service/internal/storage"
+)
+
+// MockReportDefinitionQueries мокирует запросы к сохраненным отчётам конструктора
+type MockReportDefinitionQueries struct {
+	mock.Mock
+}
+
+func (m *MockReportDefinitionQueries) ListDefinitions(ctx context.Context) ([]models.ReportDefinition, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ReportDefinition), args.Error(1)
+}
+
+func (m *MockReportDefinitionQueries) GetDefinition(ctx context.Context, definitionID string) (*models.ReportDefinition, error) {
+	args := m.Called(ctx, definitionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReportDefinition), args.Error(1)
+}
+
+func (m *MockReportDefinitionQueries) CreateDefinition(ctx context.Context, req models.ReportDefinitionRequest, createdBy string, nextRunAt *time.Time) (*models.ReportDefinition, error) {
+	args := m.Called(ctx, req, createdBy, nextRunAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReportDefinition), args.Error(1)
+}
+
+func (m *MockReportDefinitionQueries) UpdateDefinition(ctx context.Context, definitionID string, req models.ReportDefinitionRequest, nextRunAt *time.Time) (*models.ReportDefinition, error) {
+	args := m.Called(ctx, definitionID, req, nextRunAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReportDefinition), args.Error(1)
+}
+
+func (m *MockReportDefinitionQueries) DeleteDefinition(ctx context.Context, definitionID string) error {
+	return m.Called(ctx, definitionID).Error(0)
+}
+
+func (m *MockReportDefinitionQueries) ClaimDueDefinitions(ctx context.Context, now time.Time, limit int) ([]models.ReportDefinition, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ReportDefinition), args.Error(1)
+}
+
+func (m *MockReportDefinitionQueries) BuildReport(ctx context.Context, definition models.ReportDefinition, since *time.Time) ([]models.ReportResultRow, error) {
+	args := m.Called(ctx, definition, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ReportResultRow), args.Error(1)
+}
+
+const testDefinitionID = "7d0f5d7a-4f4e-4c57-9a51-3c1f0c0b6a11"
+
+// Настройка тестового окружения
+func setupReportDefinitionTest(store storage.Storage) (*gin.Engine, *service.ReportBuilder, *MockReportDefinitionQueries, *MockReportJobQueries) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	definitionQueries := new(MockReportDefinitionQueries)
+	reportJobQueries := new(MockReportJobQueries)
+	builder := service.NewReportBuilder(definitionQueries, reportJobQueries, store, new(MockMailSender))
+	handler := NewReportDefinitionHandler(definitionQueries, builder)
+
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", "user-1")
+		c.Next()
+	})
+	r.POST("/reports/definitions", handler.CreateDefinition)
+	r.POST("/reports/definitions/:definitionId/run", handler.RunDefinition)
+
+	return r, builder, definitionQueries, reportJobQueries
+}
+
+// TestCreateReportDefinition проверяет сохранение отчёта с расписанием: первый запуск назначается через период
+func TestCreateReportDefinition(t *testing.T) {
+	r, _, definitionQueries, _ := setupReportDefinitionTest(storage.Disabled{})
+
+	definitionQueries.On("CreateDefinition", mock.Anything, mock.MatchedBy(func(req models.ReportDefinitionRequest) bool {
+		return req.Entity == models.ReportEntityProducts && len(req.GroupBy) == 2 && *req.ProductType == models.ProductTypeShoes
+	}), "user-1", mock.MatchedBy(func(nextRunAt *time.Time) bool {
+		return nextRunAt != nil && time.Until(*nextRunAt) > 6*24*time.Hour
+	})).Return(&models.ReportDefinition{ID: testDefinitionID, Entity: models.ReportEntityProducts}, nil)
+
+	body := `{"name":"Обувь по неделям","entity":"products","filters":{"periodDays":7,"productType":"обувь"},"groupBy":["type","day"],"format":"csv","schedule":"weekly","recipients":["boss@example.com"]}`
+	req, _ := http.NewRequest("POST", "/reports/definitions", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	definitionQueries.AssertExpectations(t)
+}
+
+// TestCreateReportDefinitionUnsupportedField проверяет отказ для группировки, недоступной сущности отчёта
+func TestCreateReportDefinitionUnsupportedField(t *testing.T) {
+	r, _, definitionQueries, _ := setupReportDefinitionTest(storage.Disabled{})
+
+	body := `{"name":"Приёмки","entity":"receptions","groupBy":["type"],"format":"csv"}`
+	req, _ := http.NewRequest("POST", "/reports/definitions", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "groupBy=type")
+	definitionQueries.AssertNotCalled(t, "CreateDefinition")
+}
+
+// TestRunReportDefinition проверяет запуск сохраненного отчёта вручную и сохранение файла в хранилище
+func TestRunReportDefinition(t *testing.T) {
+	store := &memoryStorage{objects: map[string]string{}}
+	r, builder, definitionQueries, reportJobQueries := setupReportDefinitionTest(store)
+
+	definition := &models.ReportDefinition{ID: testDefinitionID, Entity: models.ReportEntityReceptions, GroupBy: []string{"status"}, Format: "csv"}
+	definitionQueries.On("GetDefinition", mock.Anything, testDefinitionID).Return(definition, nil)
+	definitionQueries.On("BuildReport", mock.Anything, *definition, (*time.Time)(nil)).
+		Return([]models.ReportResultRow{{Groups: []string{"close"}, Count: 2, Products: 9}}, nil)
+	reportJobQueries.On("CreateReportJob", mock.Anything, models.ReportKindDefinition, mock.Anything, "user-1").
+		Return(&models.ReportJob{ID: "job-1", Kind: models.ReportKindDefinition, Status: models.ReportJobRunning}, nil)
+	reportJobQueries.On("CompleteReportJob", mock.Anything, "job-1", "reports/job-1.csv").Return(nil)
+
+	req, _ := http.NewRequest("POST", "/reports/definitions/"+testDefinitionID+"/run", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	builder.Wait()
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"job-1"`)
+	assert.Equal(t, "text/csv:status,count,products\nclose,2,9\n", store.objects["reports/job-1.csv"])
+	reportJobQueries.AssertExpectations(t)
+}
+
+// TestRunReportDefinitionStorageDisabled проверяет отказ запуска отчёта без хранилища вложений
+func TestRunReportDefinitionStorageDisabled(t *testing.T) {
+	r, _, definitionQueries, reportJobQueries := setupReportDefinitionTest(storage.Disabled{})
+
+	definitionQueries.On("GetDefinition", mock.Anything, testDefinitionID).
+		Return(&models.ReportDefinition{ID: testDefinitionID, Format: "csv"}, nil)
+
+	req, _ := http.NewRequest("POST", "/reports/definitions/"+testDefinitionID+"/run", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	reportJobQueries.AssertNotCalled(t, "CreateReportJob")
+}
@@ -0,0 +1,576 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/storage"
+)
+
+// MockReportQueries мокирует запросы для построения отчётов
+type MockReportQueries struct {
+	mock.Mock
+}
+
+func (m *MockReportQueries) GetDuplicateBarcodes(ctx context.Context, since time.Time) ([]models.BarcodeOccurrence, error) {
+	args := m.Called(ctx, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.BarcodeOccurrence), args.Error(1)
+}
+
+func (m *MockReportQueries) GetInvariantViolations(ctx context.Context) ([]models.InvariantViolation, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.InvariantViolation), args.Error(1)
+}
+
+func (m *MockReportQueries) GetUserActivity(ctx context.Context, userID string, from, to time.Time) (*models.UserActivity, error) {
+	args := m.Called(ctx, userID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserActivity), args.Error(1)
+}
+
+func (m *MockReportQueries) StreamCityReceptions(ctx context.Context, city string, from, to time.Time, fn func(models.ReceptionExportRow) error) error {
+	args := m.Called(ctx, city, from, to)
+	if rows, ok := args.Get(0).([]models.ReceptionExportRow); ok {
+		for _, row := range rows {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockReportQueries) GetDailyIntake(ctx context.Context, pvzID, timezone string, from, to time.Time) ([]models.DailyIntake, error) {
+	args := m.Called(ctx, pvzID, timezone, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DailyIntake), args.Error(1)
+}
+
+func (m *MockReportQueries) GetAgingProducts(ctx context.Context, storedBefore time.Time, city string) ([]models.AgingProduct, error) {
+	args := m.Called(ctx, storedBefore, city)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AgingProduct), args.Error(1)
+}
+
+func (m *MockReportQueries) GetSupplierIntake(ctx context.Context, from, to time.Time) ([]models.SupplierIntake, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SupplierIntake), args.Error(1)
+}
+
+func (m *MockReportQueries) GetDamagedProducts(ctx context.Context, from, to time.Time) ([]models.ProductDamage, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ProductDamage), args.Error(1)
+}
+
+// MockReportJobQueries мокирует запросы к заданиям фоновых отчётов
+type MockReportJobQueries struct {
+	mock.Mock
+}
+
+func (m *MockReportJobQueries) CreateReportJob(ctx context.Context, kind string, params []byte, createdBy string) (*models.ReportJob, error) {
+	args := m.Called(ctx, kind, params, createdBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReportJob), args.Error(1)
+}
+
+func (m *MockReportJobQueries) GetReportJob(ctx context.Context, jobID string) (*models.ReportJob, error) {
+	args := m.Called(ctx, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReportJob), args.Error(1)
+}
+
+func (m *MockReportJobQueries) CompleteReportJob(ctx context.Context, jobID, objectKey string) error {
+	return m.Called(ctx, jobID, objectKey).Error(0)
+}
+
+func (m *MockReportJobQueries) FailReportJob(ctx context.Context, jobID, reason string) error {
+	return m.Called(ctx, jobID, reason).Error(0)
+}
+
+// Настройка тестового окружения
+func setupReportTest() (*gin.Engine, *MockReportQueries) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	reportQueries := new(MockReportQueries)
+	reportHandler := NewReportHandler(reportQueries, new(MockReportJobQueries), newTestCityResolver(), storage.Disabled{})
+
+	r.GET("/reports/duplicate-barcodes", reportHandler.GetDuplicateBarcodes)
+	r.GET("/admin/consistency", reportHandler.GetConsistencyReport)
+	r.GET("/users/:userId/activity", reportHandler.GetUserActivity)
+	r.GET("/reports/damaged-products", reportHandler.GetDamagedProducts)
+
+	return r, reportQueries
+}
+
+// TestGetDuplicateBarcodesSuccess проверяет группировку сканирований по штрихкоду
+func TestGetDuplicateBarcodesSuccess(t *testing.T) {
+	r, reportQueries := setupReportTest()
+
+	occurrences := []models.BarcodeOccurrence{
+		{Barcode: "4600000000011", ProductID: "p1", ReceptionID: "r1", PvzID: "pvz1", ReceptionStatus: "in_progress"},
+		{Barcode: "4600000000011", ProductID: "p2", ReceptionID: "r2", PvzID: "pvz2", ReceptionStatus: "in_progress"},
+		{Barcode: "4600000000028", ProductID: "p3", ReceptionID: "r1", PvzID: "pvz1", ReceptionStatus: "in_progress"},
+		{Barcode: "4600000000028", ProductID: "p4", ReceptionID: "r3", PvzID: "pvz3", ReceptionStatus: "close"},
+	}
+
+	// Окно в 2 часа должно превратиться в нижнюю границу около now-2h
+	reportQueries.On("GetDuplicateBarcodes", mock.Anything, mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since) >= 2*time.Hour && time.Since(since) < 2*time.Hour+time.Minute
+	})).Return(occurrences, nil)
+
+	req, _ := http.NewRequest("GET", "/reports/duplicate-barcodes?window=2h", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []models.DuplicateBarcodeResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response, 2)
+	assert.Equal(t, "4600000000011", response[0].Barcode)
+	assert.Len(t, response[0].Occurrences, 2)
+	assert.Equal(t, "pvz2", response[0].Occurrences[1].PvzID)
+	assert.Equal(t, "4600000000028", response[1].Barcode)
+	assert.Len(t, response[1].Occurrences, 2)
+
+	reportQueries.AssertExpectations(t)
+}
+
+// TestGetDuplicateBarcodesInvalidWindow проверяет обработку неверного окна
+func TestGetDuplicateBarcodesInvalidWindow(t *testing.T) {
+	r, reportQueries := setupReportTest()
+
+	req, _ := http.NewRequest("GET", "/reports/duplicate-barcodes?window=вчера", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response models.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Contains(t, response.Message, "Неверный параметр window")
+
+	reportQueries.AssertNotCalled(t, "GetDuplicateBarcodes")
+}
+
+// TestGetDuplicateBarcodesDatabaseError проверяет ошибку базы данных
+func TestGetDuplicateBarcodesDatabaseError(t *testing.T) {
+	r, reportQueries := setupReportTest()
+
+	reportQueries.On("GetDuplicateBarcodes", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+	req, _ := http.NewRequest("GET", "/reports/duplicate-barcodes", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var response models.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Contains(t, response.Message, "Ошибка при построении отчёта")
+
+	reportQueries.AssertExpectations(t)
+}
+
+// TestGetConsistencyReport проверяет подсчет нарушений инвариантов по видам
+func TestGetConsistencyReport(t *testing.T) {
+	r, reportQueries := setupReportTest()
+
+	productID := "p1"
+	reportQueries.On("GetInvariantViolations", mock.Anything).Return([]models.InvariantViolation{
+		{Kind: models.InvariantMultipleOpenReceptions, PvzID: "pvz1", ReceptionID: "r1"},
+		{Kind: models.InvariantMultipleOpenReceptions, PvzID: "pvz1", ReceptionID: "r2"},
+		{Kind: models.InvariantProductAfterClose, PvzID: "pvz2", ReceptionID: "r3", ProductID: &productID},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/admin/consistency", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.ConsistencyReport
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, map[string]int{
+		models.InvariantMultipleOpenReceptions: 2,
+		models.InvariantProductAfterClose:      1,
+		models.InvariantOrphanedProduct:        0,
+	}, response.Counts)
+	assert.Len(t, response.Violations, 3)
+	reportQueries.AssertExpectations(t)
+}
+
+// TestGetConsistencyReportDatabaseError проверяет ошибку базы данных при проверке инвариантов
+func TestGetConsistencyReportDatabaseError(t *testing.T) {
+	r, reportQueries := setupReportTest()
+
+	reportQueries.On("GetInvariantViolations", mock.Anything).Return(nil, errors.New("database error"))
+
+	req, _ := http.NewRequest("GET", "/admin/consistency", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+// TestGetUserActivity проверяет отчёт о работе сотрудника за период
+func TestGetUserActivity(t *testing.T) {
+	r, reportQueries := setupReportTest()
+
+	userID := "123e4567-e89b-12d3-a456-426614174000"
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	reportQueries.On("GetUserActivity", mock.Anything, userID, from, to).Return(&models.UserActivity{
+		UserID:            userID,
+		From:              from,
+		To:                to,
+		ReceptionsCreated: 12,
+		ReceptionsClosed:  11,
+		ProductsAdded:     340,
+		ProductsDeleted:   4,
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/users/"+userID+"/activity?from=2026-03-01T03:00:00%2B03:00&to=2026-04-01T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.UserActivity
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 340, response.ProductsAdded)
+	assert.Equal(t, 4, response.ProductsDeleted)
+
+	reportQueries.AssertExpectations(t)
+}
+
+// TestGetUserActivityInvalidPeriod проверяет отказ без периода и с периодом, который заканчивается раньше начала
+func TestGetUserActivityInvalidPeriod(t *testing.T) {
+	r, reportQueries := setupReportTest()
+
+	for _, query := range []string{"", "?from=2026-04-01T00:00:00Z&to=2026-03-01T00:00:00Z"} {
+		req, _ := http.NewRequest("GET", "/users/123e4567-e89b-12d3-a456-426614174000/activity"+query, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	}
+
+	reportQueries.AssertNotCalled(t, "GetUserActivity")
+}
+
+// TestGetDamagedProducts проверяет отчёт о поврежденных товарах за период
+func TestGetDamagedProducts(t *testing.T) {
+	r, reportQueries := setupReportTest()
+
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	reportQueries.On("GetDamagedProducts", mock.Anything, from, to).Return([]models.ProductDamage{
+		{ProductID: "p1", ReceptionID: "r1", PvzID: "pvz1", Type: "обувь", Description: "порвана коробка"},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/reports/damaged-products?from=2026-03-01T00:00:00Z&to=2026-04-01T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []models.ProductDamage
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response, 1)
+	assert.Equal(t, "pvz1", response[0].PvzID)
+
+	req, _ = http.NewRequest("GET", "/reports/damaged-products?from=2026-04-01T00:00:00Z&to=2026-03-01T00:00:00Z", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	reportQueries.AssertNumberOfCalls(t, "GetDamagedProducts", 1)
+}
+
+// setupReportExportTest настраивает выгрузку приёмок города с хранилищем для фоновых отчётов
+func setupReportExportTest(store storage.Storage) (*gin.Engine, *ReportHandler, *MockReportQueries, *MockReportJobQueries) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	reportQueries := new(MockReportQueries)
+	reportJobQueries := new(MockReportJobQueries)
+	reportHandler := NewReportHandler(reportQueries, reportJobQueries, newTestCityResolver(), store)
+
+	r.GET("/reports/receptions", reportHandler.ExportReceptions)
+	r.GET("/reports/jobs/:jobId", reportHandler.GetReportJob)
+
+	return r, reportHandler, reportQueries, reportJobQueries
+}
+
+// stringPtr возвращает указатель на строку для необязательных полей
+func stringPtr(value string) *string {
+	return &value
+}
+
+// exportRows - приёмки города для проверки выгрузки
+var exportRows = []models.ReceptionExportRow{
+	{PvzID: "pvz1", City: "Москва", ReceptionID: "r1", Number: "MSK001-2026-000001", DateTime: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), Status: "close", ProductCount: 12, CreatedBy: stringPtr("u1"), ClosedBy: stringPtr("u2")},
+	{PvzID: "pvz2", City: "Москва", ReceptionID: "r2", Number: "MSK002-2026-000001", DateTime: time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC), Status: "in_progress", ProductCount: 3},
+}
+
+// TestExportReceptionsCSV проверяет выгрузку приёмок города, указанного синонимом, в CSV
+func TestExportReceptionsCSV(t *testing.T) {
+	r, _, reportQueries, _ := setupReportExportTest(storage.Disabled{})
+
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	reportQueries.On("StreamCityReceptions", mock.Anything, "Москва", from, to).Return(exportRows, nil)
+
+	req, _ := http.NewRequest("GET", "/reports/receptions?city=мск&from=2026-03-01T00:00:00Z&to=2026-04-01T00:00:00Z&format=csv", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="receptions-20260301-20260401.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "pvz_id,city,reception_id,number,datetime,status,product_count,created_by,closed_by\n"+
+		"pvz1,Москва,r1,MSK001-2026-000001,2026-03-02T09:00:00Z,close,12,u1,u2\n"+
+		"pvz2,Москва,r2,MSK002-2026-000001,2026-03-03T09:00:00Z,in_progress,3,,\n", w.Body.String())
+
+	reportQueries.AssertExpectations(t)
+}
+
+// TestExportReceptionsInvalidQuery проверяет отказ для неизвестного города, формата и периода
+func TestExportReceptionsInvalidQuery(t *testing.T) {
+	r, _, reportQueries, _ := setupReportExportTest(storage.Disabled{})
+
+	for _, query := range []string{
+		"city=Атлантида&from=2026-03-01T00:00:00Z&to=2026-04-01T00:00:00Z",
+		"city=Москва&from=2026-03-01T00:00:00Z&to=2026-04-01T00:00:00Z&format=xlsx",
+		"city=Москва&from=2026-04-01T00:00:00Z&to=2026-03-01T00:00:00Z",
+		"from=2026-03-01T00:00:00Z&to=2026-04-01T00:00:00Z",
+	} {
+		req, _ := http.NewRequest("GET", "/reports/receptions?"+query, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+
+	reportQueries.AssertNotCalled(t, "StreamCityReceptions")
+}
+
+// TestExportReceptionsDatabaseError проверяет, что ошибка БД до первой строки возвращается статусом 500
+func TestExportReceptionsDatabaseError(t *testing.T) {
+	r, _, reportQueries, _ := setupReportExportTest(storage.Disabled{})
+
+	reportQueries.On("StreamCityReceptions", mock.Anything, "Казань", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+	req, _ := http.NewRequest("GET", "/reports/receptions?city=kazan&from=2026-03-01T00:00:00Z&to=2026-04-01T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+}
+
+// TestExportReceptionsAsync проверяет формирование выгрузки в фоне и сохранение файла в хранилище
+func TestExportReceptionsAsync(t *testing.T) {
+	store := &memoryStorage{objects: map[string]string{}}
+	r, handler, reportQueries, reportJobQueries := setupReportExportTest(store)
+
+	jobID := "4b6e1a52-2d0e-4f3c-9a57-0d5b7a9c1e11"
+	reportQueries.On("StreamCityReceptions", mock.Anything, "Москва", mock.Anything, mock.Anything).Return(exportRows, nil)
+	reportJobQueries.On("CreateReportJob", mock.Anything, models.ReportKindCityReceptions, mock.MatchedBy(func(params []byte) bool {
+		return strings.Contains(string(params), `"city":"Москва"`)
+	}), "").Return(&models.ReportJob{ID: jobID, Kind: models.ReportKindCityReceptions, Status: models.ReportJobRunning}, nil)
+	reportJobQueries.On("CompleteReportJob", mock.Anything, jobID, "reports/"+jobID+".csv").Return(nil)
+
+	req, _ := http.NewRequest("GET", "/reports/receptions?city=Москва&from=2026-01-01T00:00:00Z&to=2026-04-01T00:00:00Z&async=true", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	handler.exporter.Wait()
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	var job models.ReportJob
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(t, jobID, job.ID)
+	assert.Equal(t, models.ReportJobRunning, job.Status)
+
+	assert.True(t, strings.HasPrefix(store.objects["reports/"+jobID+".csv"], "text/csv:pvz_id,city,"))
+	reportJobQueries.AssertExpectations(t)
+}
+
+// TestExportReceptionsAsyncWithoutStorage проверяет отказ в фоновой выгрузке без хранилища
+func TestExportReceptionsAsyncWithoutStorage(t *testing.T) {
+	r, _, _, reportJobQueries := setupReportExportTest(storage.Disabled{})
+
+	req, _ := http.NewRequest("GET", "/reports/receptions?city=Москва&from=2026-01-01T00:00:00Z&to=2026-04-01T00:00:00Z&async=true", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	reportJobQueries.AssertNotCalled(t, "CreateReportJob")
+}
+
+// TestGetReportJob проверяет ссылку на готовый отчёт и ответ для неизвестного задания
+func TestGetReportJob(t *testing.T) {
+	r, _, _, reportJobQueries := setupReportExportTest(&memoryStorage{objects: map[string]string{}})
+
+	jobID := "4b6e1a52-2d0e-4f3c-9a57-0d5b7a9c1e11"
+	missingID := "9f1c2d3e-4b5a-4c6d-8e7f-001122334455"
+	reportJobQueries.On("GetReportJob", mock.Anything, jobID).Return(&models.ReportJob{
+		ID:        jobID,
+		Status:    models.ReportJobDone,
+		ObjectKey: stringPtr("reports/" + jobID + ".csv"),
+	}, nil)
+	reportJobQueries.On("GetReportJob", mock.Anything, missingID).Return(nil, queries.ErrNotFound)
+
+	req, _ := http.NewRequest("GET", "/reports/jobs/"+jobID, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"downloadUrl":"https://storage.example.com/reports/`+jobID+`.csv"`)
+	assert.NotContains(t, w.Body.String(), "objectKey")
+
+	for _, id := range []string{missingID, "not-a-uuid"} {
+		req, _ = http.NewRequest("GET", "/reports/jobs/"+id, nil)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code, id)
+	}
+}
+
+// setupProductAgingTest настраивает отчёт о залежавшихся товарах с зафиксированным временем
+func setupProductAgingTest(now time.Time) (*gin.Engine, *MockReportQueries) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	reportQueries := new(MockReportQueries)
+	reportHandler := NewReportHandler(reportQueries, new(MockReportJobQueries), newTestCityResolver(), storage.Disabled{})
+	reportHandler.clock = clock.Fixed(now)
+
+	r.GET("/reports/product-aging", reportHandler.GetProductAging)
+
+	return r, reportQueries
+}
+
+// agingProducts - товары на хранении в двух ПВЗ Москвы
+var agingProducts = []models.AgingProduct{
+	{ProductID: "p1", Type: "электроника", Barcode: stringPtr("4600000000017"), ReceptionID: "r1", ReceptionNumber: "MSK001-2026-000001", PvzID: "pvz1", City: "Москва", Address: "Тверская, 1", StoredSince: time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)},
+	{ProductID: "p2", Type: "одежда", ReceptionID: "r1", ReceptionNumber: "MSK001-2026-000001", PvzID: "pvz1", City: "Москва", Address: "Тверская, 1", StoredSince: time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)},
+	{ProductID: "p3", Type: "обувь", ReceptionID: "r2", ReceptionNumber: "MSK002-2026-000004", PvzID: "pvz2", City: "Москва", Address: "Арбат, 10", StoredSince: time.Date(2026, 2, 20, 9, 0, 0, 0, time.UTC)},
+}
+
+// TestGetProductAging проверяет группировку залежавшихся товаров по ПВЗ и расчет срока хранения
+func TestGetProductAging(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	r, reportQueries := setupProductAgingTest(now)
+
+	reportQueries.On("GetAgingProducts", mock.Anything, now.AddDate(0, 0, -10), "Москва").Return(agingProducts, nil)
+
+	req, _ := http.NewRequest("GET", "/reports/product-aging?days=10&city=мск", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var report models.ProductAgingReport
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, 10, report.Days)
+	assert.Equal(t, 3, report.Total)
+	assert.Len(t, report.PVZ, 2)
+	assert.Equal(t, "pvz1", report.PVZ[0].PvzID)
+	assert.Equal(t, 2, report.PVZ[0].Count)
+	assert.Equal(t, 28, report.PVZ[0].OldestDays)
+	assert.Equal(t, "Арбат, 10", report.PVZ[1].Address)
+	assert.Equal(t, 10, report.PVZ[1].Products[0].AgeDays)
+
+	reportQueries.AssertExpectations(t)
+}
+
+// TestGetProductAgingCSV проверяет выгрузку отчёта в CSV со сроком по умолчанию
+func TestGetProductAgingCSV(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	r, reportQueries := setupProductAgingTest(now)
+
+	reportQueries.On("GetAgingProducts", mock.Anything, now.AddDate(0, 0, -models.DefaultProductAgingDays), "").Return(agingProducts[2:], nil)
+
+	req, _ := http.NewRequest("GET", "/reports/product-aging?format=csv", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="product-aging-20260302.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "pvz_id,city,address,product_id,type,barcode,reception_id,reception_number,stored_since,age_days\n"+
+		"pvz2,Москва,\"Арбат, 10\",p3,обувь,,r2,MSK002-2026-000004,2026-02-20T09:00:00Z,10\n", w.Body.String())
+
+	reportQueries.AssertExpectations(t)
+}
+
+// TestGetProductAgingInvalidQuery проверяет отказ для неверного срока, формата и неизвестного города
+func TestGetProductAgingInvalidQuery(t *testing.T) {
+	r, reportQueries := setupProductAgingTest(time.Now())
+
+	for _, query := range []string{"days=0", "days=1000", "format=xlsx", "city=Атлантида"} {
+		req, _ := http.NewRequest("GET", "/reports/product-aging?"+query, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+
+	reportQueries.AssertNotCalled(t, "GetAgingProducts")
+}
+
+// TestGetProductAgingDatabaseError проверяет ошибку базы данных
+func TestGetProductAgingDatabaseError(t *testing.T) {
+	r, reportQueries := setupProductAgingTest(time.Now())
+
+	reportQueries.On("GetAgingProducts", mock.Anything, mock.Anything, "").Return(nil, errors.New("database error"))
+
+	req, _ := http.NewRequest("GET", "/reports/product-aging", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
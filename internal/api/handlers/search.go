@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchHandler содержит обработчик поиска по ПВЗ, приёмкам и товарам
+type SearchHandler struct {
+	searchQueries queries.SearchQueriesInterface
+}
+
+// NewSearchHandler создает новый экземпляр SearchHandler
+func NewSearchHandler(searchQueries queries.SearchQueriesInterface) *SearchHandler {
+	return &SearchHandler{
+		searchQueries: searchQueries,
+	}
+}
+
+// Search обрабатывает запрос поиска по подстроке q: ПВЗ ищутся по городу и адресу,
+// приёмки - по номеру и заметке, товары - по штрихкоду и типу. Сотрудник с ПВЗ в токене
+// находит только записи своих ПВЗ
+func (h *SearchHandler) Search(c *gin.Context) {
+	query := models.SearchQuery{Limit: 10}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+
+	result, err := h.searchQueries.Search(c.Request.Context(), query.Q, query.Limit, c.GetStringSlice("pvzIDs"))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgSearchFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, result)
+}
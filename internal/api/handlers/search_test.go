@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSearchQueries struct {
+	mock.Mock
+}
+
+func (m *MockSearchQueries) Search(ctx context.Context, text string, limit int, pvzIDs []string) (*models.SearchResponse, error) {
+	args := m.Called(ctx, text, limit, pvzIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SearchResponse), args.Error(1)
+}
+
+// setupSearchTest создает маршрут поиска для пользователя с ПВЗ pvzIDs из токена
+func setupSearchTest(pvzIDs []string) (*gin.Engine, *MockSearchQueries) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	searchQueries := new(MockSearchQueries)
+	handler := NewSearchHandler(searchQueries)
+	r.GET("/search", func(c *gin.Context) {
+		c.Set("pvzIDs", pvzIDs)
+		handler.Search(c)
+	})
+
+	return r, searchQueries
+}
+
+// TestSearch проверяет группы результатов поиска и ограничение сотрудника его ПВЗ
+func TestSearch(t *testing.T) {
+	r, searchQueries := setupSearchTest([]string{testPvzID})
+
+	searchQueries.On("Search", mock.Anything, "ленина", 10, []string{testPvzID}).Return(&models.SearchResponse{
+		PVZ:        []models.PVZSearchHit{{ID: testPvzID, City: "Москва", Address: "ул. Ленина, 1"}},
+		Receptions: []models.ReceptionSearchHit{},
+		Products:   []models.ProductSearchHit{},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?q=%D0%BB%D0%B5%D0%BD%D0%B8%D0%BD%D0%B0", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result models.SearchResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Len(t, result.PVZ, 1)
+	assert.Empty(t, result.Products)
+	searchQueries.AssertExpectations(t)
+}
+
+// TestSearchValidation проверяет отказ для слишком короткой строки поиска
+func TestSearchValidation(t *testing.T) {
+	r, searchQueries := setupSearchTest(nil)
+
+	for _, query := range []string{"", "q=a", "q=abc&limit=100"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?"+query, nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	searchQueries.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
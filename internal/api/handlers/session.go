@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SessionHandler содержит обработчики просмотра и завершения сессий текущего пользователя
+type SessionHandler struct {
+	sessionQueries queries.SessionQueriesInterface
+}
+
+// NewSessionHandler создает новый экземпляр SessionHandler
+func NewSessionHandler(sessionQueries queries.SessionQueriesInterface) *SessionHandler {
+	return &SessionHandler{
+		sessionQueries: sessionQueries,
+	}
+}
+
+// GetSessions обрабатывает запрос на получение активных сессий текущего пользователя
+func (h *SessionHandler) GetSessions(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgUserContextMissing))
+		return
+	}
+
+	sessions, err := h.sessionQueries.GetActiveSessions(c.Request.Context(), userID.(string))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetSessionsFailed, err))
+		return
+	}
+
+	currentID := c.GetString("sessionID")
+	result := make([]models.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, models.SessionResponse{
+			ID:        session.ID,
+			UserAgent: session.UserAgent,
+			IP:        session.IP,
+			IssuedAt:  session.IssuedAt,
+			ExpiresAt: session.ExpiresAt,
+			Current:   session.ID == currentID,
+		})
+	}
+
+	response.JSON(c, http.StatusOK, result)
+}
+
+// RevokeSession обрабатывает запрос на завершение сессии текущего пользователя.
+// Токен завершенной сессии перестает приниматься сразу, не дожидаясь истечения срока
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgUserContextMissing))
+		return
+	}
+
+	sessionID := c.Param("sessionId")
+	if _, err := uuid.Parse(sessionID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgSessionNotFound))
+		return
+	}
+
+	err := h.sessionQueries.RevokeSession(c.Request.Context(), userID.(string), sessionID)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgSessionNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgRevokeSessionFailed, err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
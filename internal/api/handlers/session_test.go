@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+)
+
+// MockSessionQueries мокирует запросы для работы с сессиями пользователей
+type MockSessionQueries struct {
+	mock.Mock
+}
+
+func (m *MockSessionQueries) CreateSession(ctx context.Context, session models.Session) (*models.Session, error) {
+	args := m.Called(ctx, session)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Session), args.Error(1)
+}
+
+func (m *MockSessionQueries) GetActiveSessions(ctx context.Context, userID string) ([]models.Session, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Session), args.Error(1)
+}
+
+func (m *MockSessionQueries) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	args := m.Called(ctx, userID, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockSessionQueries) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSessionQueries) RevokeOtherSessions(ctx context.Context, userID, keepSessionID string) (int64, error) {
+	args := m.Called(ctx, userID, keepSessionID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockSessionQueries) RotateRefreshToken(ctx context.Context, oldHash, newHash string) (*models.Session, error) {
+	args := m.Called(ctx, oldHash, newHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Session), args.Error(1)
+}
+
+const (
+	testSessionID  = "123e4567-e89b-12d3-a456-426614174030"
+	testSessionID2 = "123e4567-e89b-12d3-a456-426614174031"
+)
+
+// setupSessionTest создает роутер сессий для пользователя с текущей сессией testSessionID
+func setupSessionTest() (*gin.Engine, *MockSessionQueries) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	sessionQueries := new(MockSessionQueries)
+	sessionHandler := NewSessionHandler(sessionQueries)
+
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", testEmployeeID)
+		c.Set("sessionID", testSessionID)
+		c.Next()
+	})
+	r.GET("/me/sessions", sessionHandler.GetSessions)
+	r.DELETE("/me/sessions/:sessionId", sessionHandler.RevokeSession)
+
+	return r, sessionQueries
+}
+
+// TestGetSessionsMarksCurrent проверяет список сессий с отметкой текущей
+func TestGetSessionsMarksCurrent(t *testing.T) {
+	r, sessionQueries := setupSessionTest()
+
+	sessionQueries.On("GetActiveSessions", mock.Anything, testEmployeeID).Return([]models.Session{
+		{ID: testSessionID2, UserID: testEmployeeID, UserAgent: "Android", IP: "10.0.0.2", ExpiresAt: time.Now().Add(time.Hour)},
+		{ID: testSessionID, UserID: testEmployeeID, UserAgent: "curl/8.0", IP: "10.0.0.1", ExpiresAt: time.Now().Add(time.Hour)},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/me/sessions", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result []models.SessionResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Len(t, result, 2)
+	assert.False(t, result[0].Current)
+	assert.True(t, result[1].Current)
+	assert.Equal(t, "curl/8.0", result[1].UserAgent)
+}
+
+// TestRevokeSessionSuccess проверяет завершение сессии
+func TestRevokeSessionSuccess(t *testing.T) {
+	r, sessionQueries := setupSessionTest()
+
+	sessionQueries.On("RevokeSession", mock.Anything, testEmployeeID, testSessionID2).Return(nil)
+
+	req, _ := http.NewRequest("DELETE", "/me/sessions/"+testSessionID2, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	sessionQueries.AssertExpectations(t)
+}
+
+// TestRevokeSessionNotFound проверяет ответ для чужой или уже завершенной сессии
+func TestRevokeSessionNotFound(t *testing.T) {
+	r, sessionQueries := setupSessionTest()
+
+	sessionQueries.On("RevokeSession", mock.Anything, testEmployeeID, testSessionID2).
+		Return(fmt.Errorf("session %s: %w", testSessionID2, queries.ErrNotFound))
+
+	req, _ := http.NewRequest("DELETE", "/me/sessions/"+testSessionID2, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// TestRevokeSessionInvalidID проверяет, что неверный ID не доходит до базы данных
+func TestRevokeSessionInvalidID(t *testing.T) {
+	r, sessionQueries := setupSessionTest()
+
+	req, _ := http.NewRequest("DELETE", "/me/sessions/not-a-uuid", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	sessionQueries.AssertNotCalled(t, "RevokeSession", mock.Anything, mock.Anything, mock.Anything)
+}
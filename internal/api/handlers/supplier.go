@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SupplierHandler содержит обработчики справочника поставщиков
+type SupplierHandler struct {
+	supplierQueries queries.SupplierQueriesInterface
+}
+
+// NewSupplierHandler создает новый экземпляр SupplierHandler
+func NewSupplierHandler(supplierQueries queries.SupplierQueriesInterface) *SupplierHandler {
+	return &SupplierHandler{
+		supplierQueries: supplierQueries,
+	}
+}
+
+// ListSuppliers обрабатывает запрос списка поставщиков
+func (h *SupplierHandler) ListSuppliers(c *gin.Context) {
+	suppliers, err := h.supplierQueries.ListSuppliers(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetSuppliersFailed, err))
+		return
+	}
+
+	if suppliers == nil {
+		suppliers = []models.Supplier{}
+	}
+
+	response.JSON(c, http.StatusOK, suppliers)
+}
+
+// GetSupplier обрабатывает запрос поставщика
+func (h *SupplierHandler) GetSupplier(c *gin.Context) {
+	supplierID := c.Param("supplierId")
+	if _, err := uuid.Parse(supplierID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgSupplierNotFound))
+		return
+	}
+
+	supplier, err := h.supplierQueries.GetSupplier(c.Request.Context(), supplierID)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgSupplierNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetSuppliersFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, supplier)
+}
+
+// CreateSupplier обрабатывает запрос на создание поставщика
+func (h *SupplierHandler) CreateSupplier(c *gin.Context) {
+	var req models.SupplierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	supplier, err := h.supplierQueries.CreateSupplier(c.Request.Context(), req)
+	if errors.Is(err, queries.ErrSupplierTaken) {
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgSupplierTaken))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgSaveSupplierFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusCreated, supplier)
+}
+
+// UpdateSupplier обрабатывает запрос на изменение поставщика
+func (h *SupplierHandler) UpdateSupplier(c *gin.Context) {
+	supplierID := c.Param("supplierId")
+	if _, err := uuid.Parse(supplierID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgSupplierNotFound))
+		return
+	}
+
+	var req models.SupplierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	supplier, err := h.supplierQueries.UpdateSupplier(c.Request.Context(), supplierID, req)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgSupplierNotFound))
+		return
+	}
+	if errors.Is(err, queries.ErrSupplierTaken) {
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgSupplierTaken))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgSaveSupplierFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, supplier)
+}
+
+// DeleteSupplier обрабатывает запрос на удаление поставщика без приёмок
+func (h *SupplierHandler) DeleteSupplier(c *gin.Context) {
+	supplierID := c.Param("supplierId")
+	if _, err := uuid.Parse(supplierID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgSupplierNotFound))
+		return
+	}
+
+	err := h.supplierQueries.DeleteSupplier(c.Request.Context(), supplierID)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgSupplierNotFound))
+		return
+	}
+	if errors.Is(err, queries.ErrSupplierInUse) {
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgSupplierInUse))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgDeleteSupplierFailed, err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
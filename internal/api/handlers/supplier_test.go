@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+)
+
+const testSupplierID = "523e4567-e89b-12d3-a456-426614174000"
+
+// supplierStore - поставщики по ID и число их приёмок
+type supplierStore struct {
+	queries.SupplierQueriesInterface
+	suppliers  map[string]*models.Supplier
+	receptions map[string]int
+}
+
+func (s *supplierStore) GetSupplier(ctx context.Context, supplierID string) (*models.Supplier, error) {
+	supplier, ok := s.suppliers[supplierID]
+	if !ok {
+		return nil, queries.ErrNotFound
+	}
+	return supplier, nil
+}
+
+func (s *supplierStore) DeleteSupplier(ctx context.Context, supplierID string) error {
+	if _, ok := s.suppliers[supplierID]; !ok {
+		return queries.ErrNotFound
+	}
+	if s.receptions[supplierID] > 0 {
+		return queries.ErrSupplierInUse
+	}
+	delete(s.suppliers, supplierID)
+	return nil
+}
+
+func (s *supplierStore) SetReceptionSupplier(ctx context.Context, receptionID, supplierID string) (*models.Reception, error) {
+	s.receptions[supplierID]++
+	return &models.Reception{ID: receptionID, PvzID: "pvz-1", Status: "in_progress", SupplierID: &supplierID}, nil
+}
+
+// setupSupplierTest настраивает создание приёмок и удаление поставщиков с поставщиком testSupplierID
+func setupSupplierTest() (*gin.Engine, *supplierStore, *MockReceptionQueries) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	store := &supplierStore{
+		suppliers:  map[string]*models.Supplier{testSupplierID: {ID: testSupplierID, Name: "ООО Ромашка"}},
+		receptions: map[string]int{},
+	}
+	receptionQueries := new(MockReceptionQueries)
+
+	receptionHandler := NewReceptionHandler(receptionQueries, new(MockProductQueries), new(MockManifestQueries), nil, store, passthroughTx{}, &recordingOutbox{}, alwaysOpen{}, nil, noQuotas{}, noLocks{})
+	supplierHandler := NewSupplierHandler(store)
+
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", "user-1")
+		c.Set("userRole", models.RoleEmployee)
+		c.Next()
+	})
+	r.POST("/receptions", receptionHandler.CreateReception)
+	r.DELETE("/suppliers/:supplierId", supplierHandler.DeleteSupplier)
+
+	return r, store, receptionQueries
+}
+
+// TestCreateReceptionWithSupplier проверяет создание приёмки с поставщиком и отказ по неизвестному поставщику
+func TestCreateReceptionWithSupplier(t *testing.T) {
+	r, store, receptionQueries := setupSupplierTest()
+
+	receptionQueries.On("CreateReception", mock.Anything, "123e4567-e89b-12d3-a456-426614174000", "user-1").
+		Return(&models.Reception{ID: "reception-1", PvzID: "pvz-1", Status: "in_progress", DateTime: time.Now()}, nil).Once()
+
+	w := postJSON(r, "/receptions", models.CreateReceptionRequest{PvzID: "123e4567-e89b-12d3-a456-426614174000", SupplierID: testSupplierID})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var reception models.ReceptionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &reception))
+	assert.Equal(t, testSupplierID, *reception.SupplierID)
+	assert.Equal(t, 1, store.receptions[testSupplierID])
+
+	w = postJSON(r, "/receptions", models.CreateReceptionRequest{PvzID: "123e4567-e89b-12d3-a456-426614174000", SupplierID: "623e4567-e89b-12d3-a456-426614174000"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	receptionQueries.AssertNumberOfCalls(t, "CreateReception", 1)
+}
+
+// TestDeleteSupplierInUse проверяет, что поставщика с приёмками удалить нельзя
+func TestDeleteSupplierInUse(t *testing.T) {
+	r, store, _ := setupSupplierTest()
+	store.receptions[testSupplierID] = 3
+
+	deleteSupplier := func(id string) int {
+		req, _ := http.NewRequest(http.MethodDelete, "/suppliers/"+id, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusConflict, deleteSupplier(testSupplierID))
+
+	store.receptions[testSupplierID] = 0
+	assert.Equal(t, http.StatusNoContent, deleteSupplier(testSupplierID))
+	assert.Equal(t, http.StatusNotFound, deleteSupplier(testSupplierID))
+	assert.Equal(t, http.StatusNotFound, deleteSupplier("romashka"))
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/mapper"
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultSyncLimit - число строк каждого вида в ответе синхронизации по умолчанию
+	defaultSyncLimit = 500
+
+	// syncLag - изменения последних секунд не передаются: транзакция, начатая раньше, может
+	// зафиксировать строку с более ранним временем изменения уже после ответа, и курсор ее пропустит
+	syncLag = 5 * time.Second
+)
+
+// SyncHandler содержит обработчик инкрементальной синхронизации терминалов ПВЗ
+type SyncHandler struct {
+	syncQueries queries.SyncQueriesInterface
+	clock       clock.Clock
+}
+
+// NewSyncHandler создает новый экземпляр SyncHandler
+func NewSyncHandler(syncQueries queries.SyncQueriesInterface) *SyncHandler {
+	return &SyncHandler{
+		syncQueries: syncQueries,
+		clock:       clock.System{},
+	}
+}
+
+// Sync обрабатывает запрос изменений ПВЗ, приёмок и товаров после курсора since: терминал,
+// работающий без сети, забирает только изменения вместо полной выгрузки. Сотрудник получает
+// изменения только своих ПВЗ. Пока hasMore true, следующую часть нужно запросить сразу с новым курсором
+func (h *SyncHandler) Sync(c *gin.Context) {
+	var query models.SyncQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+
+	cursor, err := models.ParseSyncSince(query.Since)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgInvalidSyncCursor))
+		return
+	}
+
+	pvzIDs := c.GetStringSlice("pvzIDs")
+	if query.PvzID != "" {
+		if len(pvzIDs) > 0 && !slices.Contains(pvzIDs, query.PvzID) {
+			response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgPVZAccessDenied))
+			return
+		}
+		pvzIDs = []string{query.PvzID}
+	}
+
+	if query.Limit == 0 {
+		query.Limit = defaultSyncLimit
+	}
+
+	until := h.clock.Now().Add(-syncLag).UTC().Truncate(time.Microsecond)
+	changes, err := h.syncQueries.GetChanges(c.Request.Context(), models.SyncParams{
+		Cursor: cursor,
+		Until:  until,
+		PvzIDs: pvzIDs,
+		Limit:  query.Limit,
+	})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgSyncFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, mapper.Sync(*changes, until))
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/models"
+)
+
+// syncStore запоминает параметры последней выборки изменений
+type syncStore struct {
+	params  *models.SyncParams
+	changes models.SyncChanges
+}
+
+func (s *syncStore) GetChanges(ctx context.Context, params models.SyncParams) (*models.SyncChanges, error) {
+	s.params = &params
+	changes := s.changes
+	changes.Cursor = models.SyncCursor{PVZ: models.SyncPosition{UpdatedAt: params.Until}}
+	return &changes, nil
+}
+
+// setupSyncTest настраивает синхронизацию для сотрудника ПВЗ testPvzID
+func setupSyncTest(now time.Time) (*gin.Engine, *syncStore) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	store := &syncStore{}
+	handler := NewSyncHandler(store)
+	handler.clock = clock.Fixed(now)
+
+	r.GET("/sync", func(c *gin.Context) {
+		c.Set("userRole", models.RoleEmployee)
+		c.Set("pvzIDs", []string{testPvzID})
+		handler.Sync(c)
+	})
+
+	return r, store
+}
+
+func getSync(r *gin.Engine, query string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodGet, "/sync"+query, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestSync проверяет выдачу изменений и курсор, который принимается следующим запросом
+func TestSync(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	r, store := setupSyncTest(now)
+	store.changes = models.SyncChanges{
+		Products: []models.SyncProduct{{Product: models.Product{ID: "product-1", ReceptionID: testReceptionID}, Status: models.ProductLifecycleStored}},
+	}
+
+	w := getSync(r, "?since=2025-03-01T10:00:00Z")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body models.SyncResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Empty(t, body.PVZ)
+	assert.NotNil(t, body.DeletedProducts)
+	require.Len(t, body.Products, 1)
+	assert.Equal(t, models.ProductLifecycleStored, body.Products[0].Status)
+	assert.Equal(t, now.Add(-syncLag), body.SyncedUntil)
+
+	// Сотрудник получает изменения своих ПВЗ, последние секунды не передаются
+	assert.Equal(t, []string{testPvzID}, store.params.PvzIDs)
+	assert.Equal(t, time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC), store.params.Cursor.Products.UpdatedAt)
+	assert.Equal(t, defaultSyncLimit, store.params.Limit)
+
+	w = getSync(r, "?since="+body.Cursor+"&limit=50")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, now.Add(-syncLag), store.params.Cursor.PVZ.UpdatedAt)
+	assert.Equal(t, 50, store.params.Limit)
+}
+
+// TestSyncValidation проверяет отказ по неверному курсору и чужому ПВЗ
+func TestSyncValidation(t *testing.T) {
+	r, store := setupSyncTest(time.Now())
+
+	assert.Equal(t, http.StatusBadRequest, getSync(r, "?since=yesterday").Code)
+	assert.Equal(t, http.StatusForbidden, getSync(r, "?pvzId=923e4567-e89b-12d3-a456-426614174000").Code)
+	assert.Equal(t, http.StatusBadRequest, getSync(r, "?limit=5000").Code)
+	assert.Nil(t, store.params)
+}
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+	"pvz-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorHandler содержит обработчики подключения двухфакторной аутентификации
+// и завершения входа вторым фактором
+type TwoFactorHandler struct {
+	twoFactor   *service.TwoFactorService
+	sessions    service.SessionIssuer
+	authQueries queries.AuthQueriesInterface
+}
+
+// NewTwoFactorHandler создает новый экземпляр TwoFactorHandler.
+// Токен после проверки второго фактора выдается в новой сессии пользователя
+func NewTwoFactorHandler(twoFactor *service.TwoFactorService, sessions service.SessionIssuer, authQueries queries.AuthQueriesInterface) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactor:   twoFactor,
+		sessions:    sessions,
+		authQueries: authQueries,
+	}
+}
+
+// GetStatus обрабатывает запрос состояния двухфакторной аутентификации текущего пользователя
+func (h *TwoFactorHandler) GetStatus(c *gin.Context) {
+	status, err := h.twoFactor.Status(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgTwoFactorFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, status)
+}
+
+// Setup обрабатывает запрос на новый секрет TOTP. Секрет начинает действовать
+// после подтверждения кодом из приложения в Enable
+func (h *TwoFactorHandler) Setup(c *gin.Context) {
+	user, err := h.authQueries.GetUserCredentialsByID(c.Request.Context(), c.GetString("userID"))
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgUserContextMissing))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgTwoFactorFailed, err))
+		return
+	}
+
+	// В приложении учетная запись подписывается email, а без него - телефоном
+	account := user.Email
+	if account == "" && user.Phone != nil {
+		account = *user.Phone
+	}
+	if account == "" {
+		account = user.ID
+	}
+
+	setup, err := h.twoFactor.Setup(c.Request.Context(), user.ID, account)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, setup)
+}
+
+// Enable обрабатывает подтверждение нового секрета кодом из приложения.
+// В ответе резервные коды, они показываются один раз
+func (h *TwoFactorHandler) Enable(c *gin.Context) {
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	codes, err := h.twoFactor.Enable(c.Request.Context(), c.GetString("userID"), req.Code)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, codes)
+}
+
+// Disable обрабатывает отключение двухфакторной аутентификации по коду из приложения или резервному коду
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	if err := h.twoFactor.Disable(c.Request.Context(), c.GetString("userID"), req.Code); err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RegenerateBackupCodes обрабатывает запрос новых резервных кодов по коду из приложения.
+// Прежние резервные коды перестают действовать
+func (h *TwoFactorHandler) RegenerateBackupCodes(c *gin.Context) {
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	codes, err := h.twoFactor.RegenerateBackupCodes(c.Request.Context(), c.GetString("userID"), req.Code)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, codes)
+}
+
+// Verify обрабатывает завершение входа: обменивает токен второго этапа и код
+// из приложения или резервный код на JWT-токен
+func (h *TwoFactorHandler) Verify(c *gin.Context) {
+	var req models.TwoFactorVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+
+	challenge, err := h.twoFactor.Verify(c.Request.Context(), req.ChallengeToken, req.Code)
+	if errors.Is(err, service.ErrChallengeInvalid) {
+		response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgTwoFactorChallengeInvalid))
+		return
+	}
+	if errors.Is(err, service.ErrChallengeAttemptsExceeded) {
+		response.Error(c, http.StatusTooManyRequests, i18n.T(c, i18n.MsgTwoFactorAttemptsExceeded))
+		return
+	}
+	if errors.Is(err, service.ErrTwoFactorCodeInvalid) {
+		response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgTwoFactorCodeInvalid))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgTwoFactorFailed, err))
+		return
+	}
+
+	// Роль и активность читаются заново: за время входа пользователя могли деактивировать
+	user, err := h.authQueries.GetUserCredentialsByID(c.Request.Context(), challenge.UserID)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgInvalidCredentials))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgTwoFactorFailed, err))
+		return
+	}
+	if !user.IsActive {
+		response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgUserDeactivated))
+		return
+	}
+
+	tokens, err := h.sessions.IssueToken(c.Request.Context(), user.ID, user.Role, c.Request.UserAgent(), c.ClientIP(), challenge.RememberMe)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgTokenCreateFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, tokens)
+}
+
+// respondTwoFactorError преобразует ошибку подключения или отключения двухфакторной аутентификации в ответ
+func respondTwoFactorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrTwoFactorEnabled):
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgTwoFactorEnabled))
+	case errors.Is(err, service.ErrTwoFactorNotEnabled):
+		response.Error(c, http.StatusConflict, i18n.T(c, i18n.MsgTwoFactorNotEnabled))
+	case errors.Is(err, service.ErrTwoFactorCodeInvalid):
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgTwoFactorCodeInvalid))
+	default:
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgTwoFactorFailed, err))
+	}
+}
+
+// issueLoginToken выдает токен новой сессии после проверки пароля или кода SMS.
+// Пользователю с включенной двухфакторной аутентификацией вместо токена
+// возвращается токен второго этапа входа для /auth/2fa/verify
+func issueLoginToken(c *gin.Context, sessions service.SessionIssuer, twoFactor service.TwoFactorChallenger, user *models.User, rememberMe bool) {
+	challenge, err := twoFactor.Challenge(c.Request.Context(), user.ID, rememberMe)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgTwoFactorFailed, err))
+		return
+	}
+	if challenge != nil {
+		response.JSON(c, http.StatusOK, challenge)
+		return
+	}
+
+	// Создаем сессию и выдаем привязанный к ней JWT-токен
+	tokens, err := sessions.IssueToken(c.Request.Context(), user.ID, user.Role, c.Request.UserAgent(), c.ClientIP(), rememberMe)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgTokenCreateFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, tokens)
+}
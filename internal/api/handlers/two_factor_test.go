@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/config"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/otp"
+	"pvz-service/internal/service"
+)
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+
+// twoFactorStore - подключенная двухфакторная аутентификация пользователя user-id
+// и незавершенные входы по хешу токена
+type twoFactorStore struct {
+	queries.TwoFactorQueriesInterface
+	enabled    bool
+	lastStep   int64
+	challenges map[string]*models.LoginChallenge
+}
+
+func (s *twoFactorStore) GetTOTP(ctx context.Context, userID string) (*models.UserTOTP, error) {
+	if !s.enabled {
+		return nil, queries.ErrNotFound
+	}
+	enabledAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	return &models.UserTOTP{UserID: userID, Secret: testTOTPSecret, LastUsedStep: s.lastStep, EnabledAt: &enabledAt}, nil
+}
+
+func (s *twoFactorStore) SaveTOTPSecret(ctx context.Context, userID, secret string) error {
+	if s.enabled {
+		return queries.ErrNotFound
+	}
+	return nil
+}
+
+func (s *twoFactorStore) UseTOTPStep(ctx context.Context, userID string, step int64) error {
+	if step <= s.lastStep {
+		return queries.ErrNotFound
+	}
+	s.lastStep = step
+	return nil
+}
+
+func (s *twoFactorStore) UseBackupCode(ctx context.Context, userID, codeHash string) error {
+	return queries.ErrNotFound
+}
+
+func (s *twoFactorStore) GetActiveChallenge(ctx context.Context, tokenHash string) (*models.LoginChallenge, error) {
+	challenge, ok := s.challenges[tokenHash]
+	if !ok {
+		return nil, queries.ErrNotFound
+	}
+	return challenge, nil
+}
+
+func (s *twoFactorStore) IncrementChallengeAttempts(ctx context.Context, challengeID string, maxAttempts int) error {
+	challenge := s.challenges[otp.HashToken(challengeID)]
+	if challenge.Attempts >= maxAttempts {
+		return queries.ErrNotFound
+	}
+	challenge.Attempts++
+	return nil
+}
+
+func (s *twoFactorStore) ConsumeChallenge(ctx context.Context, challengeID string) error {
+	delete(s.challenges, otp.HashToken(challengeID))
+	return nil
+}
+
+// setupTwoFactorTest настраивает обработчики двухфакторной аутентификации для пользователя user-id.
+// Незавершенный вход с токеном challenge-token ведет в сессию «запомнить меня»
+func setupTwoFactorTest() (*gin.Engine, *twoFactorStore, *MockSessionIssuer, *MockAuthQueries) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	store := &twoFactorStore{
+		enabled: true,
+		challenges: map[string]*models.LoginChallenge{
+			otp.HashToken("challenge-token"): {ID: "challenge-token", UserID: "user-id", RememberMe: true},
+		},
+	}
+	sessions := new(MockSessionIssuer)
+	authQueries := new(MockAuthQueries)
+
+	twoFactor := service.NewTwoFactorService(store, passthroughTx{}, config.TwoFactorConfig{Issuer: "PVZ", ChallengeTTL: 5 * time.Minute, MaxAttempts: 2, BackupCodes: 10})
+	handler := NewTwoFactorHandler(twoFactor, sessions, authQueries)
+
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", "user-id")
+		c.Next()
+	})
+	r.POST("/me/2fa/setup", handler.Setup)
+	r.POST("/auth/2fa/verify", handler.Verify)
+
+	return r, store, sessions, authQueries
+}
+
+// TestTwoFactorVerify проверяет выдачу токена после кода из приложения
+func TestTwoFactorVerify(t *testing.T) {
+	r, _, sessions, authQueries := setupTwoFactorTest()
+
+	authQueries.On("GetUserCredentialsByID", mock.Anything, "user-id").Return(&models.User{ID: "user-id", Role: models.RoleModerator, IsActive: true}, nil)
+	sessions.On("IssueToken", mock.Anything, "user-id", models.RoleModerator, mock.Anything, mock.Anything, true).Return(&models.LoginResponse{Token: "2fa-token", RefreshToken: "refresh"}, nil)
+
+	code, err := otp.TOTPCode(testTOTPSecret, otp.TOTPStep(time.Now()))
+	require.NoError(t, err)
+
+	w := postJSON(r, "/auth/2fa/verify", models.TwoFactorVerifyRequest{ChallengeToken: "challenge-token", Code: code})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var tokens models.LoginResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokens))
+	assert.Equal(t, "2fa-token", tokens.Token)
+
+	// Незавершенный вход одноразовый
+	w = postJSON(r, "/auth/2fa/verify", models.TwoFactorVerifyRequest{ChallengeToken: "challenge-token", Code: code})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	sessions.AssertNumberOfCalls(t, "IssueToken", 1)
+}
+
+// TestTwoFactorVerifyAttemptsExceeded проверяет ограничение числа попыток ввода кода
+func TestTwoFactorVerifyAttemptsExceeded(t *testing.T) {
+	r, _, sessions, _ := setupTwoFactorTest()
+
+	for i := 0; i < 2; i++ {
+		w := postJSON(r, "/auth/2fa/verify", models.TwoFactorVerifyRequest{ChallengeToken: "challenge-token", Code: "abcd-efgh"})
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+
+	code, err := otp.TOTPCode(testTOTPSecret, otp.TOTPStep(time.Now()))
+	require.NoError(t, err)
+	w := postJSON(r, "/auth/2fa/verify", models.TwoFactorVerifyRequest{ChallengeToken: "challenge-token", Code: code})
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	w = postJSON(r, "/auth/2fa/verify", models.TwoFactorVerifyRequest{ChallengeToken: "unknown", Code: code})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	sessions.AssertNotCalled(t, "IssueToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestTwoFactorSetup проверяет выдачу секрета и отказ, если двухфакторная аутентификация уже включена
+func TestTwoFactorSetup(t *testing.T) {
+	r, store, _, authQueries := setupTwoFactorTest()
+	store.enabled = false
+
+	authQueries.On("GetUserCredentialsByID", mock.Anything, "user-id").Return(&models.User{ID: "user-id", Email: "moderator@example.com", Role: models.RoleModerator, IsActive: true}, nil)
+
+	w := postJSON(r, "/me/2fa/setup", nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var setup models.TwoFactorSetupResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &setup))
+	assert.Len(t, setup.Secret, 32)
+	assert.Contains(t, setup.URI, "otpauth://totp/PVZ:moderator@example.com?")
+	assert.Contains(t, setup.URI, "secret="+setup.Secret)
+
+	store.enabled = true
+	w = postJSON(r, "/me/2fa/setup", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultUsagePeriod - период статистики использования API, если from не указан
+const defaultUsagePeriod = 24 * time.Hour
+
+// UsageHandler содержит обработчики статистики использования API
+type UsageHandler struct {
+	usageQueries queries.UsageQueriesInterface
+}
+
+// NewUsageHandler создает новый экземпляр UsageHandler
+func NewUsageHandler(usageQueries queries.UsageQueriesInterface) *UsageHandler {
+	return &UsageHandler{
+		usageQueries: usageQueries,
+	}
+}
+
+// GetUsage обрабатывает запрос статистики использования API по ПВЗ, пользователям или маршрутам:
+// так видны интеграции, злоупотребляющие API, и клиенты, повторяющие один запрос без остановки.
+// По умолчанию возвращается статистика по ПВЗ за последние сутки
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	var query models.UsageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidQueryParams, err))
+		return
+	}
+
+	if query.To.IsZero() {
+		query.To = time.Now().UTC()
+	}
+	if query.From.IsZero() {
+		query.From = query.To.Add(-defaultUsagePeriod)
+	}
+	if !query.From.Before(query.To) {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgInvalidQueryParams))
+		return
+	}
+
+	stats, err := h.usageQueries.GetUsage(c.Request.Context(), query)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetUsageFailed, err))
+		return
+	}
+
+	if stats == nil {
+		stats = []models.UsageStat{}
+	}
+
+	response.JSON(c, http.StatusOK, stats)
+}
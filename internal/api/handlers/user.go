@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/db"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UserStatusCache - кэш статуса пользователей middleware авторизации
+type UserStatusCache interface {
+	Forget(userID string)
+}
+
+// UserHandler содержит обработчики администрирования пользователей
+type UserHandler struct {
+	authQueries    queries.AuthQueriesInterface
+	sessionQueries queries.SessionQueriesInterface
+	tx             db.Transactor
+	statusCache    UserStatusCache
+}
+
+// NewUserHandler создает новый экземпляр UserHandler.
+// После изменения статуса пользователь удаляется из statusCache, чтобы изменение применилось сразу
+func NewUserHandler(authQueries queries.AuthQueriesInterface, sessionQueries queries.SessionQueriesInterface, tx db.Transactor, statusCache UserStatusCache) *UserHandler {
+	return &UserHandler{
+		authQueries:    authQueries,
+		sessionQueries: sessionQueries,
+		tx:             tx,
+		statusCache:    statusCache,
+	}
+}
+
+// DeactivateUser обрабатывает запрос модератора на деактивацию пользователя.
+// Все сессии пользователя завершаются, его токены перестают приниматься, а вход запрещается
+func (h *UserHandler) DeactivateUser(c *gin.Context) {
+	userID := c.Param("userId")
+	if _, err := uuid.Parse(userID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgUserNotFound))
+		return
+	}
+	if userID == c.GetString("userID") {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgDeactivateSelf))
+		return
+	}
+
+	err := h.tx.InTx(c.Request.Context(), func(ctx context.Context) error {
+		if err := h.authQueries.SetUserActive(ctx, userID, false); err != nil {
+			return err
+		}
+		_, err := h.sessionQueries.RevokeOtherSessions(ctx, userID, "")
+		return err
+	})
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgUserNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgDeactivateUserFailed, err))
+		return
+	}
+
+	h.statusCache.Forget(userID)
+	c.Status(http.StatusNoContent)
+}
+
+// ActivateUser обрабатывает запрос модератора на повторную активацию пользователя.
+// Завершенные при деактивации сессии не восстанавливаются, пользователь входит заново
+func (h *UserHandler) ActivateUser(c *gin.Context) {
+	userID := c.Param("userId")
+	if _, err := uuid.Parse(userID); err != nil {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgUserNotFound))
+		return
+	}
+
+	err := h.authQueries.SetUserActive(c.Request.Context(), userID, true)
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgUserNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgActivateUserFailed, err))
+		return
+	}
+
+	h.statusCache.Forget(userID)
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pvz-service/internal/db/queries"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testModeratorUserID = "523e4567-e89b-12d3-a456-426614174000"
+
+// recordingStatusCache запоминает пользователей, удаленных из кэша статусов
+type recordingStatusCache struct {
+	forgotten []string
+}
+
+func (c *recordingStatusCache) Forget(userID string) {
+	c.forgotten = append(c.forgotten, userID)
+}
+
+// setupUserTest создает маршруты администрирования пользователей от имени модератора
+func setupUserTest() (*gin.Engine, *MockAuthQueries, *MockSessionQueries, *recordingStatusCache) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	authQueries := new(MockAuthQueries)
+	sessionQueries := new(MockSessionQueries)
+	cache := &recordingStatusCache{}
+	handler := NewUserHandler(authQueries, sessionQueries, passthroughTx{}, cache)
+	setUser := func(c *gin.Context) {
+		c.Set("userID", testModeratorUserID)
+	}
+	r.POST("/admin/users/:userId/deactivate", setUser, handler.DeactivateUser)
+	r.POST("/admin/users/:userId/activate", setUser, handler.ActivateUser)
+
+	return r, authQueries, sessionQueries, cache
+}
+
+// TestDeactivateUser проверяет деактивацию пользователя с завершением всех его сессий
+func TestDeactivateUser(t *testing.T) {
+	r, authQueries, sessionQueries, cache := setupUserTest()
+
+	authQueries.On("SetUserActive", mock.Anything, testEmployeeID, false).Return(nil)
+	sessionQueries.On("RevokeOtherSessions", mock.Anything, testEmployeeID, "").Return(int64(2), nil)
+
+	req, _ := http.NewRequest("POST", "/admin/users/"+testEmployeeID+"/deactivate", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, []string{testEmployeeID}, cache.forgotten)
+	authQueries.AssertExpectations(t)
+	sessionQueries.AssertExpectations(t)
+}
+
+// TestDeactivateUserErrors проверяет ошибки деактивации, при которых кэш не сбрасывается
+func TestDeactivateUserErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		setErr     error
+		wantStatus int
+	}{
+		{
+			name:       "Некорректный ID",
+			userID:     "not-a-uuid",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Деактивация самого себя",
+			userID:     testModeratorUserID,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Пользователь не найден",
+			userID:     testEmployeeID,
+			setErr:     fmt.Errorf("user %s: %w", testEmployeeID, queries.ErrNotFound),
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Ошибка базы данных",
+			userID:     testEmployeeID,
+			setErr:     errors.New("database error"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, authQueries, sessionQueries, cache := setupUserTest()
+			authQueries.On("SetUserActive", mock.Anything, tt.userID, false).Return(tt.setErr).Maybe()
+
+			req, _ := http.NewRequest("POST", "/admin/users/"+tt.userID+"/deactivate", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Empty(t, cache.forgotten)
+			sessionQueries.AssertNotCalled(t, "RevokeOtherSessions", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestActivateUser проверяет повторную активацию пользователя
+func TestActivateUser(t *testing.T) {
+	r, authQueries, _, cache := setupUserTest()
+
+	authQueries.On("SetUserActive", mock.Anything, testEmployeeID, true).Return(nil)
+
+	req, _ := http.NewRequest("POST", "/admin/users/"+testEmployeeID+"/activate", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, []string{testEmployeeID}, cache.forgotten)
+	authQueries.AssertExpectations(t)
+}
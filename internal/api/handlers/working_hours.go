@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/db"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/mapper"
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WorkingHoursHandler содержит обработчики расписания работы ПВЗ
+type WorkingHoursHandler struct {
+	pvzQueries   queries.PVZQueriesInterface
+	hoursQueries queries.WorkingHoursQueriesInterface
+	tx           db.Transactor
+}
+
+// NewWorkingHoursHandler создает новый экземпляр WorkingHoursHandler
+func NewWorkingHoursHandler(pvzQueries queries.PVZQueriesInterface, hoursQueries queries.WorkingHoursQueriesInterface, tx db.Transactor) *WorkingHoursHandler {
+	return &WorkingHoursHandler{
+		pvzQueries:   pvzQueries,
+		hoursQueries: hoursQueries,
+		tx:           tx,
+	}
+}
+
+// GetWorkingHours обрабатывает запрос на получение часов работы ПВЗ
+func (h *WorkingHoursHandler) GetWorkingHours(c *gin.Context) {
+	pvz, err := h.pvzQueries.GetPVZ(c.Request.Context(), c.Param("pvzId"))
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgPVZNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetWorkingHoursFailed, err))
+		return
+	}
+
+	days, err := h.hoursQueries.GetWorkingHours(c.Request.Context(), pvz.ID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgGetWorkingHoursFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, mapper.WorkingHours(*pvz, days))
+}
+
+// SetWorkingHours обрабатывает запрос модератора на замену расписания ПВЗ.
+// Время указывается в часовом поясе ПВЗ, день недели без часов работы становится выходным
+func (h *WorkingHoursHandler) SetWorkingHours(c *gin.Context) {
+	var req models.SetWorkingHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, i18n.Wrap(c, i18n.MsgInvalidRequest, err))
+		return
+	}
+	if !validWorkingDays(req.Days) {
+		response.Error(c, http.StatusBadRequest, i18n.T(c, i18n.MsgInvalidWorkingHours))
+		return
+	}
+	sort.Slice(req.Days, func(i, j int) bool { return req.Days[i].Weekday < req.Days[j].Weekday })
+
+	var pvz *models.PVZ
+	err := h.tx.InTx(c.Request.Context(), func(ctx context.Context) error {
+		var err error
+		if pvz, err = h.pvzQueries.LockPVZ(ctx, c.Param("pvzId")); err != nil {
+			return err
+		}
+		return h.hoursQueries.SetWorkingHours(ctx, pvz.ID, req.Days)
+	})
+	if errors.Is(err, queries.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgPVZNotFound))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgSetWorkingHoursFailed, err))
+		return
+	}
+
+	response.JSON(c, http.StatusOK, mapper.WorkingHours(*pvz, req.Days))
+}
+
+// validWorkingDays проверяет, что каждый день недели указан не больше одного раза
+// и ПВЗ открывается раньше, чем закрывается. Работа через полночь не поддерживается
+func validWorkingDays(days []models.WorkingDay) bool {
+	seen := make(map[int]bool, len(days))
+	for _, day := range days {
+		if seen[day.Weekday] || day.OpensAt >= day.ClosesAt {
+			return false
+		}
+		seen[day.Weekday] = true
+	}
+	return true
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/service"
+	"pvz-service/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// closedHours - ПВЗ вне часов работы
+type closedHours struct{}
+
+func (closedHours) CheckOpen(ctx context.Context, pvzID string) error {
+	return service.ErrOutsideWorkingHours
+}
+
+// MockWorkingHoursQueries мокирует запросы к расписанию ПВЗ
+type MockWorkingHoursQueries struct {
+	mock.Mock
+}
+
+func (m *MockWorkingHoursQueries) GetWorkingHours(ctx context.Context, pvzID string) ([]models.WorkingDay, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WorkingDay), args.Error(1)
+}
+
+func (m *MockWorkingHoursQueries) SetWorkingHours(ctx context.Context, pvzID string, days []models.WorkingDay) error {
+	args := m.Called(ctx, pvzID, days)
+	return args.Error(0)
+}
+
+// setupWorkingHoursTest создает маршруты расписания ПВЗ
+func setupWorkingHoursTest() (*gin.Engine, *MockPVZQueries, *MockWorkingHoursQueries) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	pvzQueries := new(MockPVZQueries)
+	hoursQueries := new(MockWorkingHoursQueries)
+	handler := NewWorkingHoursHandler(pvzQueries, hoursQueries, passthroughTx{})
+	r.GET("/pvz/:pvzId/working-hours", handler.GetWorkingHours)
+	r.PUT("/pvz/:pvzId/working-hours", handler.SetWorkingHours)
+
+	return r, pvzQueries, hoursQueries
+}
+
+// TestGetWorkingHours проверяет получение расписания вместе с часовым поясом ПВЗ
+func TestGetWorkingHours(t *testing.T) {
+	r, pvzQueries, hoursQueries := setupWorkingHoursTest()
+
+	timezone := "Europe/Moscow"
+	pvzQueries.On("GetPVZ", mock.Anything, testPvzID).Return(&models.PVZ{ID: testPvzID, Timezone: &timezone}, nil)
+	hoursQueries.On("GetWorkingHours", mock.Anything, testPvzID).Return([]models.WorkingDay{
+		{Weekday: 1, OpensAt: "09:00", ClosesAt: "21:00"},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/pvz/"+testPvzID+"/working-hours", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result models.WorkingHoursResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, "Europe/Moscow", result.Timezone)
+	assert.Equal(t, []models.WorkingDay{{Weekday: 1, OpensAt: "09:00", ClosesAt: "21:00"}}, result.Days)
+}
+
+// TestGetWorkingHoursNotFound проверяет ответ для неизвестного ПВЗ
+func TestGetWorkingHoursNotFound(t *testing.T) {
+	r, pvzQueries, hoursQueries := setupWorkingHoursTest()
+
+	pvzQueries.On("GetPVZ", mock.Anything, testPvzID).Return(nil, queries.ErrNotFound)
+
+	req, _ := http.NewRequest("GET", "/pvz/"+testPvzID+"/working-hours", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	hoursQueries.AssertNotCalled(t, "GetWorkingHours", mock.Anything, mock.Anything)
+}
+
+// TestSetWorkingHours проверяет замену расписания с сортировкой дней недели
+func TestSetWorkingHours(t *testing.T) {
+	r, pvzQueries, hoursQueries := setupWorkingHoursTest()
+
+	sorted := []models.WorkingDay{
+		{Weekday: 1, OpensAt: "09:00", ClosesAt: "21:00"},
+		{Weekday: 6, OpensAt: "10:00", ClosesAt: "18:00"},
+	}
+	pvzQueries.On("LockPVZ", mock.Anything, testPvzID).Return(&models.PVZ{ID: testPvzID}, nil)
+	hoursQueries.On("SetWorkingHours", mock.Anything, testPvzID, sorted).Return(nil)
+
+	body, _ := json.Marshal(models.SetWorkingHoursRequest{Days: []models.WorkingDay{sorted[1], sorted[0]}})
+	req, _ := http.NewRequest("PUT", "/pvz/"+testPvzID+"/working-hours", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result models.WorkingHoursResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, sorted, result.Days)
+	hoursQueries.AssertExpectations(t)
+}
+
+// TestSetWorkingHoursValidation проверяет отказ в некорректном расписании
+func TestSetWorkingHoursValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"Неизвестный день недели", `{"days":[{"weekday":8,"opensAt":"09:00","closesAt":"21:00"}]}`},
+		{"Некорректное время", `{"days":[{"weekday":1,"opensAt":"9","closesAt":"21:00"}]}`},
+		{"Открытие позже закрытия", `{"days":[{"weekday":1,"opensAt":"21:00","closesAt":"09:00"}]}`},
+		{"Повтор дня недели", `{"days":[{"weekday":1,"opensAt":"09:00","closesAt":"12:00"},{"weekday":1,"opensAt":"13:00","closesAt":"21:00"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, pvzQueries, hoursQueries := setupWorkingHoursTest()
+
+			req, _ := http.NewRequest("PUT", "/pvz/"+testPvzID+"/working-hours", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			pvzQueries.AssertNotCalled(t, "LockPVZ", mock.Anything, mock.Anything)
+			hoursQueries.AssertNotCalled(t, "SetWorkingHours", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestCreateReceptionOutsideWorkingHours проверяет отказ в создании приёмки в закрытом ПВЗ
+func TestCreateReceptionOutsideWorkingHours(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	receptionQueries := new(MockReceptionQueries)
+	handler := NewReceptionHandler(receptionQueries, new(MockProductQueries), new(MockManifestQueries), nil, nil, passthroughTx{}, &recordingOutbox{}, closedHours{}, nil, noQuotas{}, noLocks{})
+	r.POST("/receptions", func(c *gin.Context) {
+		c.Set("userRole", models.RoleEmployee)
+		handler.CreateReception(c)
+	})
+
+	body, _ := json.Marshal(models.CreateReceptionRequest{PvzID: testPvzID})
+	req, _ := http.NewRequest("POST", "/receptions", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	receptionQueries.AssertNotCalled(t, "CreateReception", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestAddProductOutsideWorkingHours проверяет отказ в добавлении товара в закрытом ПВЗ
+func TestAddProductOutsideWorkingHours(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	productQueries := new(MockProductQueries)
+	receptionQueries := new(MockReceptionQueries)
+	handler := newTestProductHandler(productQueries, receptionQueries, &recordingOutbox{}, storage.Disabled{}, productLimits{}, closedHours{}, noQuotas{}, noLocks{})
+	r.POST("/products", func(c *gin.Context) {
+		c.Set("userRole", models.RoleEmployee)
+		handler.AddProduct(c)
+	})
+
+	receptionQueries.On("GetLastOpenReception", mock.Anything, testPvzID).
+		Return(&models.Reception{ID: testReceptionID, PvzID: testPvzID, Status: "in_progress"}, nil)
+
+	body, _ := json.Marshal(models.CreateProductRequest{Type: "электроника", PvzID: testPvzID})
+	req, _ := http.NewRequest("POST", "/products", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var response models.ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "ПВЗ сейчас закрыт: приёмка товаров вне часов работы запрещена", response.Message)
+	productQueries.AssertNotCalled(t, "AddProduct", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestDeleteLastProductOutsideWorkingHours проверяет, что ошибочно принятый товар можно удалить после закрытия ПВЗ:
+// часы работы ограничивают только открытие приёмок и добавление товаров
+func TestDeleteLastProductOutsideWorkingHours(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	productQueries := new(MockProductQueries)
+	receptionQueries := new(MockReceptionQueries)
+	handler := newTestProductHandler(productQueries, receptionQueries, &recordingOutbox{}, storage.Disabled{}, productLimits{}, closedHours{}, noQuotas{}, noLocks{})
+	r.POST("/pvz/:pvzId/delete_last_product", func(c *gin.Context) {
+		c.Set("userRole", models.RoleEmployee)
+		handler.DeleteLastProduct(c)
+	})
+
+	receptionQueries.On("GetLastOpenReception", mock.Anything, testPvzID).
+		Return(&models.Reception{ID: testReceptionID, PvzID: testPvzID, Status: "in_progress"}, nil)
+	productQueries.On("GetLastProductFromReception", mock.Anything, testReceptionID).
+		Return(&models.Product{ID: "product-1", ReceptionID: testReceptionID}, nil)
+	productQueries.On("DeleteProduct", mock.Anything, "product-1", mock.Anything).Return(nil)
+
+	req, _ := http.NewRequest("POST", "/pvz/"+testPvzID+"/delete_last_product", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	productQueries.AssertExpectations(t)
+}
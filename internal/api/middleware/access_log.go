@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"pvz-service/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// accessLogEntry - строка журнала запросов в формате JSON
+type accessLogEntry struct {
+	Time      string  `json:"time"`
+	RequestID string  `json:"requestId,omitempty"`
+	ClientIP  string  `json:"clientIp"`
+	UserID    string  `json:"userId,omitempty"`
+	Method    string  `json:"method"`
+	Path      string  `json:"path"`
+	Status    int     `json:"status"`
+	Size      int     `json:"size"`
+	LatencyMS float64 `json:"latencyMs"`
+	UserAgent string  `json:"userAgent,omitempty"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// AccessLog создает middleware журнала запросов в формате format: config.AccessLogJSON - по строке JSON
+// на запрос для сборщиков логов, иначе Common Log Format. IP клиента определяется с учетом доверенных прокси
+func AccessLog(format string) gin.HandlerFunc {
+	if format == config.AccessLogJSON {
+		return gin.LoggerWithFormatter(formatJSONAccessLog)
+	}
+	return gin.LoggerWithFormatter(formatCommonAccessLog)
+}
+
+// formatCommonAccessLog форматирует запрос в Common Log Format: host ident user [time] "request" status size
+func formatCommonAccessLog(param gin.LogFormatterParams) string {
+	user := "-"
+	if userID, ok := param.Keys["userID"].(string); ok && userID != "" {
+		user = userID
+	}
+	size := "-"
+	if param.BodySize > 0 {
+		size = fmt.Sprint(param.BodySize)
+	}
+
+	return fmt.Sprintf("%s - %s [%s] \"%s %s %s\" %d %s\n",
+		param.ClientIP,
+		user,
+		param.TimeStamp.Format("02/Jan/2006:15:04:05 -0700"),
+		param.Method,
+		param.Path,
+		param.Request.Proto,
+		param.StatusCode,
+		size,
+	)
+}
+
+// formatJSONAccessLog форматирует запрос строкой JSON с идентификатором запроса, пользователем и временем обработки
+func formatJSONAccessLog(param gin.LogFormatterParams) string {
+	entry := accessLogEntry{
+		Time:      param.TimeStamp.UTC().Format("2006-01-02T15:04:05.000Z07:00"),
+		ClientIP:  param.ClientIP,
+		Method:    param.Method,
+		Path:      param.Path,
+		Status:    param.StatusCode,
+		Size:      max(param.BodySize, 0),
+		LatencyMS: float64(param.Latency.Microseconds()) / 1000,
+		UserAgent: param.Request.UserAgent(),
+		Error:     param.ErrorMessage,
+	}
+	entry.RequestID, _ = param.Keys["requestID"].(string)
+	entry.UserID, _ = param.Keys["userID"].(string)
+
+	// Строка состоит только из строк и чисел, поэтому кодирование не завершается ошибкой
+	line, _ := json.Marshal(entry)
+	return string(line) + "\n"
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLogParams() gin.LogFormatterParams {
+	req, _ := http.NewRequest("GET", "/pvz?page=2", nil)
+	req.Header.Set("User-Agent", "scanner/1.0")
+	return gin.LogFormatterParams{
+		Request:    req,
+		TimeStamp:  time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		StatusCode: http.StatusOK,
+		Latency:    1500 * time.Microsecond,
+		ClientIP:   "203.0.113.7",
+		Method:     "GET",
+		Path:       "/pvz?page=2",
+		BodySize:   512,
+		Keys:       map[string]any{"requestID": "req-1", "userID": "user-1"},
+	}
+}
+
+func TestFormatCommonAccessLog(t *testing.T) {
+	line := formatCommonAccessLog(testLogParams())
+
+	assert.Equal(t, `203.0.113.7 - user-1 [01/Mar/2026:10:00:00 +0000] "GET /pvz?page=2 HTTP/1.1" 200 512`+"\n", line)
+
+	params := testLogParams()
+	params.Keys = nil
+	params.BodySize = 0
+	assert.Equal(t, `203.0.113.7 - - [01/Mar/2026:10:00:00 +0000] "GET /pvz?page=2 HTTP/1.1" 200 -`+"\n", formatCommonAccessLog(params))
+}
+
+func TestFormatJSONAccessLog(t *testing.T) {
+	var entry accessLogEntry
+	require.NoError(t, json.Unmarshal([]byte(formatJSONAccessLog(testLogParams())), &entry))
+
+	assert.Equal(t, accessLogEntry{
+		Time:      "2026-03-01T10:00:00.000Z",
+		RequestID: "req-1",
+		ClientIP:  "203.0.113.7",
+		UserID:    "user-1",
+		Method:    "GET",
+		Path:      "/pvz?page=2",
+		Status:    http.StatusOK,
+		Size:      512,
+		LatencyMS: 1.5,
+		UserAgent: "scanner/1.0",
+	}, entry)
+}
@@ -1,23 +1,36 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
-	"pvz-service/internal/models"
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/authz"
+	"pvz-service/internal/i18n"
 	"pvz-service/internal/utils"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware создает middleware для проверки JWT токена
-func AuthMiddleware(jwtManager utils.JWTManagerInterface) gin.HandlerFunc {
+// SessionChecker проверяет, что сессия токена не отозвана
+type SessionChecker interface {
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+}
+
+// UserChecker проверяет, что владелец токена существует и не деактивирован
+type UserChecker interface {
+	IsUserActive(ctx context.Context, userID string) (bool, error)
+}
+
+// AuthMiddleware создает middleware для проверки JWT токена.
+// Токены, привязанные к сессии (claim jti), отклоняются после завершения сессии,
+// а также после удаления или деактивации пользователя
+func AuthMiddleware(jwtManager utils.JWTManagerInterface, sessions SessionChecker, users UserChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Получаем токен из заголовка Authorization
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-				Message: "Отсутствует токен авторизации",
-			})
+			response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgTokenMissing))
 			c.Abort()
 			return
 		}
@@ -25,9 +38,7 @@ func AuthMiddleware(jwtManager utils.JWTManagerInterface) gin.HandlerFunc {
 		// Извлекаем токен из заголовка
 		tokenParts := strings.Split(authHeader, " ")
 		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-				Message: "Неверный формат токена",
-			})
+			response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgTokenMalformed))
 			c.Abort()
 			return
 		}
@@ -36,16 +47,62 @@ func AuthMiddleware(jwtManager utils.JWTManagerInterface) gin.HandlerFunc {
 		// Проверяем токен
 		claims, err := jwtManager.ValidateToken(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-				Message: "Неверный токен: " + err.Error(),
-			})
+			response.Error(c, http.StatusUnauthorized, i18n.Wrap(c, i18n.MsgTokenInvalid, err))
 			c.Abort()
 			return
 		}
 
+		// Проверяем, что сессия не завершена. Тестовые токены /dummyLogin не привязаны к сессии
+		if claims.ID != "" {
+			active, err := sessions.IsSessionActive(c.Request.Context(), claims.ID)
+			if err != nil {
+				response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgSessionCheckFailed, err))
+				c.Abort()
+				return
+			}
+			if !active {
+				response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgSessionRevoked))
+				c.Abort()
+				return
+			}
+
+			userActive, err := users.IsUserActive(c.Request.Context(), claims.UserID)
+			if err != nil {
+				response.Error(c, http.StatusInternalServerError, i18n.Wrap(c, i18n.MsgUserCheckFailed, err))
+				c.Abort()
+				return
+			}
+			if !userActive {
+				response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgUserDeactivated))
+				c.Abort()
+				return
+			}
+		}
+
 		// Сохраняем данные пользователя в контексте
 		c.Set("userID", claims.UserID)
 		c.Set("userRole", claims.Role)
+		c.Set("sessionID", claims.ID)
+		c.Set("pvzIDs", claims.PVZIDs)
+		c.Set("dummyToken", claims.IsDummy())
+
+		c.Next()
+	}
+}
+
+// ReadOnlyDummyTokens создает middleware, которое разрешает тестовым токенам /dummyLogin
+// только чтение: запросы GET, HEAD и OPTIONS. Ставится после AuthMiddleware
+func ReadOnlyDummyTokens() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetBool("dummyToken") {
+			switch c.Request.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+			default:
+				response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgDummyTokenReadOnly))
+				c.Abort()
+				return
+			}
+		}
 
 		c.Next()
 	}
@@ -57,18 +114,49 @@ func RequireRole(requiredRole string) gin.HandlerFunc {
 		// Получаем роль пользователя из контекста
 		userRole, exists := c.Get("userRole")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-				Message: "Нет данных о пользователе",
-			})
+			response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgUserContextMissing))
 			c.Abort()
 			return
 		}
 
 		// Проверяем соответствие роли
 		if userRole != requiredRole {
-			c.JSON(http.StatusForbidden, models.ErrorResponse{
-				Message: "Доступ запрещен: недостаточно прав",
-			})
+			response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbidden))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequirePermission создает middleware для проверки права роли пользователя по матрице authz
+func RequirePermission(permission authz.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userRole, exists := c.Get("userRole")
+		if !exists {
+			response.Error(c, http.StatusUnauthorized, i18n.T(c, i18n.MsgUserContextMissing))
+			c.Abort()
+			return
+		}
+
+		role, _ := userRole.(string)
+		if !authz.Can(role, permission) {
+			response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgForbidden))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequirePVZAccess создает middleware для маршрутов :pvzId, которое пропускает сотрудника
+// только к ПВЗ из claim pvz_ids его токена. Токен без списка ПВЗ не ограничивает доступ
+func RequirePVZAccess() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !authz.CanAccessPVZ(c.GetStringSlice("pvzIDs"), c.Param("pvzId")) {
+			response.Error(c, http.StatusForbidden, i18n.T(c, i18n.MsgPVZAccessDenied))
 			c.Abort()
 			return
 		}
@@ -1,13 +1,16 @@
 package middleware
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"pvz-service/internal/authz"
 	"pvz-service/internal/models"
 	"pvz-service/internal/utils"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -36,14 +39,38 @@ func (m *MockJWTManager) GenerateDummyToken(role string) (string, error) {
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockJWTManager) GenerateToken(userID, role string) (string, error) {
-	args := m.Called(userID, role)
+func (m *MockJWTManager) TokenTTL(role string) time.Duration {
+	return time.Hour
+}
+
+func (m *MockJWTManager) GenerateToken(userID, role, sessionID string, pvzIDs []string) (string, error) {
+	args := m.Called(userID, role, sessionID, pvzIDs)
 	if args.Get(0) == nil || args.Get(1) == nil {
 		return "", args.Error(1)
 	}
 	return args.String(0), args.Error(1)
 }
 
+// MockSessionChecker мокирует проверку отзыва сессий
+type MockSessionChecker struct {
+	mock.Mock
+}
+
+func (m *MockSessionChecker) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockUserChecker мокирует проверку статуса пользователя
+type MockUserChecker struct {
+	mock.Mock
+}
+
+func (m *MockUserChecker) IsUserActive(ctx context.Context, userID string) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
 // setupAuthTest настраивает тестовое окружение
 func setupAuthTest() (*gin.Engine, *MockJWTManager) {
 	gin.SetMode(gin.TestMode)
@@ -68,7 +95,7 @@ func TestAuthMiddlewareValidToken(t *testing.T) {
 	jwtManager.On("ValidateToken", validToken).Return(claims, nil)
 
 	// Настраиваем маршрут с middleware
-	r.GET("/protected", AuthMiddleware(jwtManager), func(c *gin.Context) {
+	r.GET("/protected", AuthMiddleware(jwtManager, new(MockSessionChecker), new(MockUserChecker)), func(c *gin.Context) {
 		// Проверяем, что данные пользователя сохранены в контексте
 		userID, exists := c.Get("userID")
 		assert.True(t, exists)
@@ -96,12 +123,116 @@ func TestAuthMiddlewareValidToken(t *testing.T) {
 	jwtManager.AssertExpectations(t)
 }
 
+// TestAuthMiddlewareActiveSession проверяет, что токен активной сессии принимается
+func TestAuthMiddlewareActiveSession(t *testing.T) {
+	r, jwtManager := setupAuthTest()
+	sessions := new(MockSessionChecker)
+
+	claims := &utils.CustomClaims{UserID: "user123", Role: "employee"}
+	claims.ID = "session-1"
+	jwtManager.On("ValidateToken", "session.jwt.token").Return(claims, nil)
+	sessions.On("IsSessionActive", mock.Anything, "session-1").Return(true, nil)
+	users := new(MockUserChecker)
+	users.On("IsUserActive", mock.Anything, "user123").Return(true, nil)
+
+	r.GET("/protected", AuthMiddleware(jwtManager, sessions, users), func(c *gin.Context) {
+		assert.Equal(t, "session-1", c.GetString("sessionID"))
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/protected", nil)
+	req.Header.Set("Authorization", "Bearer session.jwt.token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	sessions.AssertExpectations(t)
+	users.AssertExpectations(t)
+}
+
+// TestAuthMiddlewareRevokedSession проверяет отказ для токена завершенной сессии
+func TestAuthMiddlewareRevokedSession(t *testing.T) {
+	r, jwtManager := setupAuthTest()
+	sessions := new(MockSessionChecker)
+
+	claims := &utils.CustomClaims{UserID: "user123", Role: "employee"}
+	claims.ID = "session-1"
+	jwtManager.On("ValidateToken", "session.jwt.token").Return(claims, nil)
+	sessions.On("IsSessionActive", mock.Anything, "session-1").Return(false, nil)
+	users := new(MockUserChecker)
+
+	r.GET("/protected", AuthMiddleware(jwtManager, sessions, users), func(c *gin.Context) {
+		t.Fail()
+	})
+
+	req, _ := http.NewRequest("GET", "/protected", nil)
+	req.Header.Set("Authorization", "Bearer session.jwt.token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	var response models.ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Сессия завершена, войдите заново", response.Message)
+}
+
+// TestAuthMiddlewareDeactivatedUser проверяет отказ для токена деактивированного или удаленного пользователя
+func TestAuthMiddlewareDeactivatedUser(t *testing.T) {
+	tests := []struct {
+		name        string
+		checkErr    error
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:        "Пользователь деактивирован",
+			wantStatus:  http.StatusUnauthorized,
+			wantMessage: "Пользователь деактивирован",
+		},
+		{
+			name:        "Ошибка проверки",
+			checkErr:    errors.New("database error"),
+			wantStatus:  http.StatusInternalServerError,
+			wantMessage: "Ошибка при проверке пользователя: database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, jwtManager := setupAuthTest()
+			sessions := new(MockSessionChecker)
+			users := new(MockUserChecker)
+
+			claims := &utils.CustomClaims{UserID: "user123", Role: "employee"}
+			claims.ID = "session-1"
+			jwtManager.On("ValidateToken", "session.jwt.token").Return(claims, nil)
+			sessions.On("IsSessionActive", mock.Anything, "session-1").Return(true, nil)
+			users.On("IsUserActive", mock.Anything, "user123").Return(false, tt.checkErr)
+
+			r.GET("/protected", AuthMiddleware(jwtManager, sessions, users), func(c *gin.Context) {
+				t.Fail()
+			})
+
+			req, _ := http.NewRequest("GET", "/protected", nil)
+			req.Header.Set("Authorization", "Bearer session.jwt.token")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var response models.ErrorResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantMessage, response.Message)
+		})
+	}
+}
+
 // TestAuthMiddlewareMissingToken проверяет случай с отсутствующим токеном
 func TestAuthMiddlewareMissingToken(t *testing.T) {
 	r, jwtManager := setupAuthTest()
 
 	// Настраиваем маршрут с middleware
-	r.GET("/protected", AuthMiddleware(jwtManager), func(c *gin.Context) {
+	r.GET("/protected", AuthMiddleware(jwtManager, new(MockSessionChecker), new(MockUserChecker)), func(c *gin.Context) {
 		// Этот обработчик не должен быть вызван
 		t.Fail()
 	})
@@ -130,7 +261,7 @@ func TestAuthMiddlewareInvalidTokenFormat(t *testing.T) {
 	r, jwtManager := setupAuthTest()
 
 	// Настраиваем маршрут с middleware
-	r.GET("/protected", AuthMiddleware(jwtManager), func(c *gin.Context) {
+	r.GET("/protected", AuthMiddleware(jwtManager, new(MockSessionChecker), new(MockUserChecker)), func(c *gin.Context) {
 		// Этот обработчик не должен быть вызван
 		t.Fail()
 	})
@@ -176,7 +307,7 @@ func TestAuthMiddlewareInvalidToken(t *testing.T) {
 	jwtManager.On("ValidateToken", invalidToken).Return(nil, errors.New("token has expired"))
 
 	// Настраиваем маршрут с middleware
-	r.GET("/protected", AuthMiddleware(jwtManager), func(c *gin.Context) {
+	r.GET("/protected", AuthMiddleware(jwtManager, new(MockSessionChecker), new(MockUserChecker)), func(c *gin.Context) {
 		// Этот обработчик не должен быть вызван
 		t.Fail()
 	})
@@ -304,7 +435,7 @@ func TestAuthMiddlewareWithRequireRole(t *testing.T) {
 	jwtManager.On("ValidateToken", validToken).Return(claims, nil)
 
 	// Настраиваем маршрут с обоими middleware
-	r.GET("/admin", AuthMiddleware(jwtManager), RequireRole("admin"), func(c *gin.Context) {
+	r.GET("/admin", AuthMiddleware(jwtManager, new(MockSessionChecker), new(MockUserChecker)), RequireRole("admin"), func(c *gin.Context) {
 		c.Status(http.StatusOK)
 	})
 
@@ -342,3 +473,93 @@ func TestAuthMiddlewareWithRequireRole(t *testing.T) {
 	assert.NoError(t, json.Unmarshal(w2.Body.Bytes(), &response))
 	assert.Equal(t, "Доступ запрещен: недостаточно прав", response.Message)
 }
+
+// TestRequirePermission проверяет доступ по матрице прав ролей
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       interface{}
+		wantStatus int
+		aborted    bool
+	}{
+		{"Роль с правом", models.RoleModerator, http.StatusOK, false},
+		{"Роль без права", models.RoleEmployee, http.StatusForbidden, true},
+		{"Неизвестная роль", "auditor", http.StatusForbidden, true},
+		{"Нет данных о пользователе", nil, http.StatusUnauthorized, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("GET", "/admin", nil)
+			if tt.role != nil {
+				ctx.Set("userRole", tt.role)
+			}
+
+			RequirePermission(authz.Admin)(ctx)
+
+			assert.Equal(t, tt.aborted, ctx.IsAborted())
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+// TestRequirePVZAccess проверяет ограничение сотрудника ПВЗ из токена
+func TestRequirePVZAccess(t *testing.T) {
+	tests := []struct {
+		name       string
+		pvzIDs     []string
+		wantStatus int
+		aborted    bool
+	}{
+		{"ПВЗ из токена", []string{"pvz-1", "pvz-2"}, http.StatusOK, false},
+		{"Чужой ПВЗ", []string{"pvz-2"}, http.StatusForbidden, true},
+		{"Токен без списка ПВЗ", nil, http.StatusOK, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("GET", "/pvz/pvz-1/inventory", nil)
+			ctx.Params = gin.Params{{Key: "pvzId", Value: "pvz-1"}}
+			ctx.Set("pvzIDs", tt.pvzIDs)
+
+			RequirePVZAccess()(ctx)
+
+			assert.Equal(t, tt.aborted, ctx.IsAborted())
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+// TestReadOnlyDummyTokens проверяет, что тестовому токену доступно только чтение
+func TestReadOnlyDummyTokens(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		dummy      bool
+		wantStatus int
+		aborted    bool
+	}{
+		{"Чтение тестовым токеном", http.MethodGet, true, http.StatusOK, false},
+		{"Изменение тестовым токеном", http.MethodPost, true, http.StatusForbidden, true},
+		{"Удаление тестовым токеном", http.MethodDelete, true, http.StatusForbidden, true},
+		{"Изменение токеном сессии", http.MethodPost, false, http.StatusOK, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest(tt.method, "/pvz", nil)
+			ctx.Set("dummyToken", tt.dummy)
+
+			ReadOnlyDummyTokens()(ctx)
+
+			assert.Equal(t, tt.aborted, ctx.IsAborted())
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupBodyLimitTest() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(BodyLimit(BodyLimits{JSON: 16, Upload: 64}))
+
+	echo := func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.String(http.StatusOK, string(body))
+	}
+	r.POST("/receptions", echo)
+	r.POST("/pvz/:pvzId/close_last_reception", echo)
+	return r
+}
+
+// TestBodyLimit проверяет ограничение размера и типа тела запроса
+func TestBodyLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		body        string
+		chunked     bool
+		wantStatus  int
+	}{
+		{name: "JSON в пределах ограничения", path: "/receptions", contentType: "application/json; charset=utf-8", body: `{"pvzId":"1"}`, wantStatus: http.StatusOK},
+		{name: "JSON больше ограничения", path: "/receptions", contentType: "application/json", body: `{"pvzId":"` + strings.Repeat("1", 16) + `"}`, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "JSON без длины больше ограничения", path: "/receptions", contentType: "application/json", body: `{"pvzId":"` + strings.Repeat("1", 16) + `"}`, chunked: true, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "Загрузка файла", path: "/receptions", contentType: "multipart/form-data; boundary=x", body: strings.Repeat("1", 32), wantStatus: http.StatusOK},
+		{name: "Загрузка файла больше ограничения", path: "/receptions", contentType: "multipart/form-data; boundary=x", body: strings.Repeat("1", 65), wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "Неподдерживаемый тип", path: "/receptions", contentType: "text/plain", body: `{"pvzId":"1"}`, wantStatus: http.StatusUnsupportedMediaType},
+		{name: "Без тела", path: "/pvz/1/close_last_reception", wantStatus: http.StatusOK},
+	}
+
+	r := setupBodyLimitTest()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			if tt.body == "" {
+				req = httptest.NewRequest(http.MethodPost, tt.path, nil)
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, tt.body, w.Body.String())
+			}
+		})
+	}
+}
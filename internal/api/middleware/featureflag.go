@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/featureflags"
+	"pvz-service/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireFeature создает middleware, пропускающий запрос, только если флаг name включен.
+// Флаг проверяется для ПВЗ из параметра пути pvzId, а без него - общее значение.
+// Для выключенной функции маршрут отвечает 404, как если бы его не было
+func RequireFeature(flags featureflags.Checker, name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !flags.Enabled(c.Request.Context(), name, c.Param("pvzId")) {
+			response.Error(c, http.StatusNotFound, i18n.T(c, i18n.MsgFeatureDisabled))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// pvzFlags включает флаг только для перечисленных ПВЗ
+type pvzFlags map[string]bool
+
+func (f pvzFlags) Enabled(ctx context.Context, name, pvzID string) bool {
+	return f[pvzID]
+}
+
+func TestRequireFeature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/pvz/:pvzId/returns", RequireFeature(pvzFlags{"pilot": true}, "returns_workflow"), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	tests := []struct {
+		name  string
+		pvzID string
+		code  int
+	}{
+		{"Флаг включен для ПВЗ", "pilot", http.StatusCreated},
+		{"Флаг выключен для ПВЗ", "other", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/pvz/"+tt.pvzID+"/returns", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
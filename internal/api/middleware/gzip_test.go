@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupCompressionTest() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Gzip())
+
+	r.GET("/pvz", ETag(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"items": strings.Repeat("pvz", 100)})
+	})
+	r.GET("/empty", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	return r
+}
+
+func doCompressionRequest(r *gin.Engine, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", path, nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestGzipCompressesResponse проверяет сжатие ответа для клиента с Accept-Encoding: gzip
+func TestGzipCompressesResponse(t *testing.T) {
+	r := setupCompressionTest()
+
+	w := doCompressionRequest(r, "/pvz", map[string]string{"Accept-Encoding": "gzip, deflate"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+
+	reader, err := gzip.NewReader(w.Body)
+	assert.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), "pvzpvz")
+}
+
+// TestGzipSkipped проверяет ответы без сжатия: клиент без gzip и ответ без тела
+func TestGzipSkipped(t *testing.T) {
+	r := setupCompressionTest()
+
+	w := doCompressionRequest(r, "/pvz", map[string]string{"Accept-Encoding": "gzip;q=0, identity"})
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Contains(t, w.Body.String(), "pvzpvz")
+
+	w = doCompressionRequest(r, "/empty", map[string]string{"Accept-Encoding": "gzip"})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Zero(t, w.Body.Len())
+}
+
+// TestGzipFlush проверяет, что при Flush потоковый ответ уходит клиенту до завершения обработчика
+func TestGzipFlush(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Gzip())
+
+	var flushed int
+	r.GET("/stream", func(c *gin.Context) {
+		c.String(http.StatusOK, "line\n")
+		c.Writer.Flush()
+		flushed = c.Writer.(*gzipWriter).ResponseWriter.Size()
+	})
+
+	w := doCompressionRequest(r, "/stream", map[string]string{"Accept-Encoding": "gzip"})
+
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Positive(t, flushed)
+}
+
+// TestETagNotModified проверяет ответ 304 для неизменившегося списка, в том числе со сжатием
+func TestETagNotModified(t *testing.T) {
+	r := setupCompressionTest()
+
+	first := doCompressionRequest(r, "/pvz", nil)
+	etag := first.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	for _, encoding := range []string{"", "gzip"} {
+		w := doCompressionRequest(r, "/pvz", map[string]string{"If-None-Match": etag, "Accept-Encoding": encoding})
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Zero(t, w.Body.Len())
+	}
+
+	w := doCompressionRequest(r, "/pvz", map[string]string{"If-None-Match": `W/"stale"`})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "pvzpvz")
+}
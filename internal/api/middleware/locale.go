@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"log"
+	"pvz-service/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Locale создает middleware, определяющий язык ответа по заголовку Accept-Language
+func Locale(defaultLocale string) gin.HandlerFunc {
+	if !i18n.IsSupported(defaultLocale) {
+		log.Printf("Unsupported default locale %q, using %q", defaultLocale, i18n.DefaultLocale)
+		defaultLocale = i18n.DefaultLocale
+	}
+
+	return func(c *gin.Context) {
+		locale := i18n.Negotiate(c.GetHeader("Accept-Language"), defaultLocale)
+
+		c.Set(i18n.ContextKey, locale)
+		c.Header("Content-Language", locale)
+
+		c.Next()
+	}
+}
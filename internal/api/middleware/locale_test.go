@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLocaleMiddleware проверяет выбор языка сообщений об ошибках
+func TestLocaleMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		defaultLocale  string
+		acceptLanguage string
+		expectedLocale string
+		expectedMsg    string
+	}{
+		{"Казахский по заголовку", "ru", "kk-KZ,kk;q=0.9", "kk", "Авторизация токені жоқ"},
+		{"Английский по заголовку", "ru", "en-US", "en", "Authorization token is missing"},
+		{"Локаль по умолчанию из конфигурации", "en", "", "en", "Authorization token is missing"},
+		{"Неподдерживаемая локаль по умолчанию", "de", "", "ru", "Отсутствует токен авторизации"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, jwtManager := setupAuthTest()
+			r.Use(Locale(tt.defaultLocale))
+			r.GET("/protected", AuthMiddleware(jwtManager, new(MockSessionChecker), new(MockUserChecker)), func(c *gin.Context) {
+				t.Fail()
+			})
+
+			req, _ := http.NewRequest("GET", "/protected", nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Equal(t, tt.expectedLocale, w.Header().Get("Content-Language"))
+
+			var response models.ErrorResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedMsg, response.Message)
+		})
+	}
+}
+
+// TestLocaleMiddlewareSetsContext проверяет, что локаль доступна обработчикам
+func TestLocaleMiddlewareSetsContext(t *testing.T) {
+	r, _ := setupAuthTest()
+	r.Use(Locale("ru"))
+	r.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, i18n.FromContext(c))
+	})
+
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Language", "en")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, "en", w.Body.String())
+}
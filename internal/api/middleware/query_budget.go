@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// QueryBudgetHeader - заголовок ответа с числом SQL-запросов сверх бюджета в виде count/budget
+const QueryBudgetHeader = "X-Query-Budget-Exceeded"
+
+// requestQueries - гистограмма числа SQL-запросов на HTTP-запрос с разбивкой по маршруту
+var requestQueries, _ = metrics.Meter().Int64Histogram(
+	"http.server.request.db_queries",
+	metric.WithDescription("Число SQL-запросов на HTTP-запрос"),
+	metric.WithExplicitBucketBoundaries(1, 2, 5, 10, 20, 50, 100, 200),
+)
+
+// queryBudgetExceeded - счетчик HTTP-запросов, превысивших бюджет SQL-запросов
+var queryBudgetExceeded, _ = metrics.Meter().Int64Counter(
+	"http.server.request.db_query_budget_exceeded",
+	metric.WithDescription("HTTP-запросы, выполнившие больше SQL-запросов, чем разрешает бюджет"),
+)
+
+// queryBudgetWriter добавляет заголовок QueryBudgetHeader перед отправкой ответа,
+// если к этому моменту запрос уже превысил бюджет
+type queryBudgetWriter struct {
+	gin.ResponseWriter
+	counter *db.QueryCounter
+	budget  int
+}
+
+func (w *queryBudgetWriter) warn() {
+	if w.Written() {
+		return
+	}
+	if count := w.counter.Count(); count > w.budget {
+		w.Header().Set(QueryBudgetHeader, fmt.Sprintf("%d/%d", count, w.budget))
+	}
+}
+
+func (w *queryBudgetWriter) WriteHeaderNow() {
+	w.warn()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *queryBudgetWriter) Write(data []byte) (int, error) {
+	w.warn()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *queryBudgetWriter) WriteString(s string) (int, error) {
+	w.warn()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *queryBudgetWriter) Flush() {
+	w.warn()
+	w.ResponseWriter.Flush()
+}
+
+// QueryBudget создает middleware, который считает SQL-запросы каждого HTTP-запроса и пишет в лог
+// и метрики запросы, выполнившие больше budget SQL-запросов: так N+1 в обработчике заметен до выкладки.
+// С header ответ на такой запрос получает заголовок QueryBudgetHeader - включается вне production.
+// budget <= 0 отключает проверку, число запросов по маршрутам при этом все равно пишется в метрики
+func QueryBudget(budget int, header bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, counter := db.WithQueryCounter(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+
+		if budget > 0 && header {
+			writer := &queryBudgetWriter{ResponseWriter: c.Writer, counter: counter, budget: budget}
+			c.Writer = writer
+			defer func() { c.Writer = writer.ResponseWriter }()
+		}
+
+		c.Next()
+
+		count := counter.Count()
+		route := attribute.String("http.route", c.FullPath())
+		requestQueries.Record(ctx, int64(count), metric.WithAttributes(route))
+
+		if budget > 0 && count > budget {
+			queryBudgetExceeded.Add(ctx, 1, metric.WithAttributes(route))
+			log.Printf("Request %s %s (%s) made %d DB queries, budget is %d",
+				c.Request.Method, c.FullPath(), c.GetString("requestID"), count, budget)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pvz-service/internal/db"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupQueryBudgetTest создает маршрут, выполняющий queries SQL-запросов, с бюджетом 2
+func setupQueryBudgetTest(t *testing.T, queries int, header bool) *gin.Engine {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { mockDB.Close() })
+	database := &db.Database{DB: sqlx.NewDb(mockDB, "sqlmock")}
+	for i := 0; i < queries; i++ {
+		mock.ExpectExec(`UPDATE pvz`).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(QueryBudget(2, header))
+	r.GET("/pvz", func(c *gin.Context) {
+		for i := 0; i < queries; i++ {
+			_, err := database.ExecContext(c.Request.Context(), "UPDATE pvz SET city = city")
+			require.NoError(t, err)
+		}
+		c.JSON(http.StatusOK, gin.H{})
+	})
+	return r
+}
+
+// TestQueryBudget проверяет заголовок ответа на запрос, превысивший бюджет SQL-запросов
+func TestQueryBudget(t *testing.T) {
+	tests := []struct {
+		name    string
+		queries int
+		header  bool
+		want    string
+	}{
+		{name: "В пределах бюджета", queries: 2, header: true, want: ""},
+		{name: "Сверх бюджета", queries: 3, header: true, want: "3/2"},
+		{name: "Сверх бюджета в production", queries: 3, header: false, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := setupQueryBudgetTest(t, tt.queries, tt.header)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pvz", nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.want, w.Header().Get(QueryBudgetHeader))
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/ratelimit"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit создает middleware, ограничивающий частоту запросов по пользователю,
+// а для запросов без авторизации - по IP-адресу. Индивидуальные лимиты имеют приоритет
+func RateLimit(limiter *ratelimit.Limiter, overrides ratelimit.OverridesInterface, defaultLimit ratelimit.Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := RateLimitKey(c)
+
+		limit := defaultLimit
+		if override, ok := overrides.Lookup(c.Request.Context(), key); ok {
+			limit = override
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit.RequestsPerMinute))
+
+		allowed, retryAfter := limiter.Allow(key, limit)
+		if !allowed {
+			c.Header("Retry-After", fmt.Sprintf("%.0f", math.Ceil(retryAfter.Seconds())))
+			response.Error(c, http.StatusTooManyRequests, i18n.T(c, i18n.MsgRateLimited))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RateLimitKey возвращает ключ лимита запроса: user:<id> для авторизованных
+// пользователей и ip:<адрес> для остальных
+func RateLimitKey(c *gin.Context) string {
+	if userID, ok := c.Get("userID"); ok {
+		if id, ok := userID.(string); ok && id != "" {
+			return "user:" + id
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"pvz-service/internal/ratelimit"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// staticOverrides отдает фиксированные индивидуальные лимиты
+type staticOverrides map[string]ratelimit.Limit
+
+func (o staticOverrides) Lookup(ctx context.Context, key string) (ratelimit.Limit, bool) {
+	limit, ok := o[key]
+	return limit, ok
+}
+
+func (o staticOverrides) Invalidate() {}
+
+func setupRateLimitTest(overrides staticOverrides) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.Use(func(c *gin.Context) {
+		if userID := c.GetHeader("X-Test-User"); userID != "" {
+			c.Set("userID", userID)
+		}
+		c.Next()
+	})
+	r.Use(RateLimit(ratelimit.NewLimiter(), overrides, ratelimit.Limit{RequestsPerMinute: 60, Burst: 2}))
+	r.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	return r
+}
+
+func doRateLimitRequest(r *gin.Engine, userID string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/", nil)
+	if userID != "" {
+		req.Header.Set("X-Test-User", userID)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestRateLimitDefault проверяет отказ после исчерпания лимита по умолчанию
+func TestRateLimitDefault(t *testing.T) {
+	r := setupRateLimitTest(staticOverrides{})
+
+	assert.Equal(t, http.StatusOK, doRateLimitRequest(r, "user-1").Code)
+	assert.Equal(t, http.StatusOK, doRateLimitRequest(r, "user-1").Code)
+
+	w := doRateLimitRequest(r, "user-1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Equal(t, "60", w.Header().Get("X-RateLimit-Limit"))
+
+	// Лимит считается отдельно для каждого пользователя
+	assert.Equal(t, http.StatusOK, doRateLimitRequest(r, "user-2").Code)
+}
+
+// TestRateLimitOverride проверяет приоритет индивидуального лимита
+func TestRateLimitOverride(t *testing.T) {
+	r := setupRateLimitTest(staticOverrides{
+		"user:tablet": {RequestsPerMinute: 600, Burst: 5},
+	})
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, doRateLimitRequest(r, "tablet").Code)
+	}
+	w := doRateLimitRequest(r, "tablet")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "600", w.Header().Get("X-RateLimit-Limit"))
+}
+
+// TestRateLimitByIP проверяет ограничение по IP для запросов без авторизации
+func TestRateLimitByIP(t *testing.T) {
+	r := setupRateLimitTest(staticOverrides{})
+
+	doRateLimitRequest(r, "")
+	doRateLimitRequest(r, "")
+	assert.Equal(t, http.StatusTooManyRequests, doRateLimitRequest(r, "").Code)
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/errreport"
+	"pvz-service/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery создает middleware, перехватывающий паники обработчиков вместо gin.Recovery:
+// паника записывается в журнал со стеком и отправляется в Sentry с идентификатором запроса
+// и пользователем, а клиент получает ошибку в обычном формате ответа
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			// Клиент закрыл соединение - отвечать некому, и это не ошибка сервиса
+			if isBrokenPipe(recovered) {
+				log.Printf("Connection closed by client: %s %s: %v", c.Request.Method, c.Request.URL.Path, recovered)
+				c.Abort()
+				return
+			}
+
+			requestID := c.GetString("requestID")
+			log.Printf("Panic recovered: %s %s [request_id=%s]: %v\n%s", c.Request.Method, c.Request.URL.Path, requestID, recovered, debug.Stack())
+
+			errreport.ReportPanic(errreport.Panic{
+				Value:     recovered,
+				RequestID: requestID,
+				UserID:    c.GetString("userID"),
+				UserRole:  c.GetString("userRole"),
+				Request:   c.Request,
+			})
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			response.Error(c, http.StatusInternalServerError, i18n.T(c, i18n.MsgInternalError))
+			c.Abort()
+		}()
+
+		c.Next()
+	}
+}
+
+// isBrokenPipe сообщает, вызвана ли паника записью в соединение, закрытое клиентом
+func isBrokenPipe(recovered interface{}) bool {
+	err, ok := recovered.(error)
+	if !ok {
+		return false
+	}
+
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	var syscallErr *os.SyscallError
+	if !errors.As(opErr, &syscallErr) {
+		return false
+	}
+
+	message := strings.ToLower(syscallErr.Error())
+	return strings.Contains(message, "broken pipe") || strings.Contains(message, "connection reset by peer")
+}
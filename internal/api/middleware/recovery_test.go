@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRecoveryTest() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestID(), Recovery(), Locale("ru"))
+	return r
+}
+
+// TestRecoveryReturnsStructuredError проверяет ответ на панику в обычном формате ошибки
+func TestRecoveryReturnsStructuredError(t *testing.T) {
+	r := setupRecoveryTest()
+	r.GET("/panic", func(c *gin.Context) {
+		c.Set("userID", "user-1")
+		var m map[string]int
+		m["x"] = 1
+	})
+	r.GET("/ok", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	req, _ := http.NewRequest("GET", "/panic", nil)
+	req.Header.Set(RequestIDHeader, "req-42")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "req-42", w.Header().Get(RequestIDHeader))
+	var errResponse models.ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResponse))
+	assert.Equal(t, "Внутренняя ошибка сервера", errResponse.Message)
+
+	// Сервер продолжает обслуживать запросы
+	req, _ = http.NewRequest("GET", "/ok", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+// TestRecoveryEnveloped проверяет формат ошибки для маршрутов API v1
+func TestRecoveryEnveloped(t *testing.T) {
+	r := setupRecoveryTest()
+	r.GET("/api/v1/panic", response.Enveloped(), func(c *gin.Context) {
+		panic("boom")
+	})
+
+	req, _ := http.NewRequest("GET", "/api/v1/panic", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	var envelope response.Envelope
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	assert.NotNil(t, envelope.Error)
+}
+
+// TestRequestIDGenerated проверяет замену отсутствующего или недопустимого идентификатора запроса
+func TestRequestIDGenerated(t *testing.T) {
+	r := setupRecoveryTest()
+	r.GET("/ok", func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("requestID"))
+	})
+
+	for _, header := range []string{"", "bad id\nwith newline"} {
+		req, _ := http.NewRequest("GET", "/ok", nil)
+		if header != "" {
+			req.Header.Set(RequestIDHeader, header)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		requestID := w.Header().Get(RequestIDHeader)
+		assert.Len(t, requestID, 36)
+		assert.Equal(t, requestID, w.Body.String())
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+
+	"pvz-service/internal/usage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Usage создает middleware, который считает запросы к API по ПВЗ, пользователю и маршруту.
+// ПВЗ берется из пути запроса, а если его там нет - единственный ПВЗ, назначенный сотруднику.
+// Подключается после авторизации и до лимита запросов, чтобы отклоненные лимитом запросы тоже учитывались
+func Usage(counter *usage.Counter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			return
+		}
+		counter.Record(usagePVZ(c), c.GetString("userID"), c.Request.Method, route, c.Writer.Status() >= http.StatusBadRequest)
+	}
+}
+
+// usagePVZ возвращает ПВЗ запроса для статистики или пустую строку, если ПВЗ неизвестен
+func usagePVZ(c *gin.Context) string {
+	if pvzID := c.Param("pvzId"); pvzID != "" {
+		if _, err := uuid.Parse(pvzID); err == nil {
+			return pvzID
+		}
+		return ""
+	}
+	if pvzIDs := c.GetStringSlice("pvzIDs"); len(pvzIDs) == 1 {
+		return pvzIDs[0]
+	}
+	return ""
+}
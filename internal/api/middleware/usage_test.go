@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"pvz-service/internal/usage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUsage проверяет учет запросов по ПВЗ из пути, ПВЗ сотрудника и ответов с ошибкой
+func TestUsage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	counter := usage.NewCounter()
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", "user-1")
+		c.Set("pvzIDs", []string{"223e4567-e89b-12d3-a456-426614174000"})
+		c.Next()
+	}, Usage(counter))
+	r.GET("/pvz/:pvzId/inventory", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/products", func(c *gin.Context) { c.Status(http.StatusTooManyRequests) })
+
+	for _, target := range []string{"/pvz/123e4567-e89b-12d3-a456-426614174000/inventory", "/pvz/123e4567-e89b-12d3-a456-426614174000/inventory", "/pvz/unknown/inventory"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/products", nil))
+	// Несуществующий маршрут не учитывается
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	counts := counter.Drain()
+	require.Len(t, counts, 3)
+	sort.Slice(counts, func(i, j int) bool { return counts[i].Requests > counts[j].Requests })
+
+	assert.Equal(t, "123e4567-e89b-12d3-a456-426614174000", counts[0].Key.PvzID)
+	assert.Equal(t, "/pvz/:pvzId/inventory", counts[0].Key.Route)
+	assert.Equal(t, int64(2), counts[0].Requests)
+	assert.Zero(t, counts[0].Errors)
+
+	for _, count := range counts[1:] {
+		assert.Equal(t, "user-1", count.Key.UserID)
+		if count.Key.Route == "/products" {
+			// ПВЗ берется из назначения сотрудника, отклоненный запрос считается ошибкой
+			assert.Equal(t, "223e4567-e89b-12d3-a456-426614174000", count.Key.PvzID)
+			assert.Equal(t, int64(1), count.Errors)
+		} else {
+			assert.Empty(t, count.Key.PvzID)
+		}
+	}
+}
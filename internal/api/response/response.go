@@ -0,0 +1,129 @@
+package response
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"pvz-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// envelopeKey - ключ контекста, включающий единый формат ответа для маршрутов API v1
+const envelopeKey = "response.envelope"
+
+// Envelope представляет ответ API v1: данные, метаданные пагинации или ошибку
+type Envelope struct {
+	Data  interface{} `json:"data,omitempty"`
+	Meta  *Meta       `json:"meta,omitempty"`
+	Error *ErrorBody  `json:"error,omitempty"`
+}
+
+// Meta содержит параметры пагинации списка
+type Meta struct {
+	Page       int `json:"page"`
+	Limit      int `json:"limit"`
+	Total      int `json:"total"`
+	TotalPages int `json:"totalPages"`
+}
+
+// NewMeta создает параметры пагинации страницы page по limit элементов из total
+func NewMeta(page, limit, total int) Meta {
+	meta := Meta{Page: page, Limit: limit, Total: total}
+	if limit > 0 {
+		meta.TotalPages = (total + limit - 1) / limit
+	}
+	return meta
+}
+
+// ErrorBody представляет ошибку в ответе API v1
+type ErrorBody struct {
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// Enveloped создает middleware, включающий единый формат ответа для группы маршрутов
+func Enveloped() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(envelopeKey, true)
+		c.Next()
+	}
+}
+
+// Deprecated создает middleware для устаревших маршрутов без версии:
+// добавляет заголовки Deprecation и Link со ссылкой на маршрут-преемник с префиксом successorPrefix
+func Deprecated(successorPrefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Deprecation", "true")
+		c.Header("Link", fmt.Sprintf("<%s%s>; rel=\"successor-version\"", strings.TrimSuffix(successorPrefix, "/"), c.Request.URL.Path))
+		c.Next()
+	}
+}
+
+// isEnveloped сообщает, нужно ли отвечать в едином формате
+func isEnveloped(c *gin.Context) bool {
+	return c.GetBool(envelopeKey)
+}
+
+// JSON отправляет данные: в API v1 - в поле data, на старых маршрутах - как есть
+func JSON(c *gin.Context, status int, data interface{}) {
+	if isEnveloped(c) {
+		c.JSON(status, Envelope{Data: data})
+		return
+	}
+	c.JSON(status, data)
+}
+
+// Paginated отправляет страницу списка. Общее количество и ссылки на соседние страницы передаются
+// в заголовках (см. PaginationHeaders), в API v1 параметры пагинации также передаются в поле meta
+func Paginated(c *gin.Context, status int, data interface{}, meta Meta) {
+	PaginationHeaders(c, meta)
+	if isEnveloped(c) {
+		c.JSON(status, Envelope{Data: data, Meta: &meta})
+		return
+	}
+	c.JSON(status, data)
+}
+
+// PaginationHeaders отправляет общее количество элементов в заголовке X-Total-Count и ссылки
+// на предыдущую и следующую страницы в заголовке Link (rel="prev" и rel="next").
+// Ссылки строятся из адреса запроса, поэтому сохраняют фильтры списка
+func PaginationHeaders(c *gin.Context, meta Meta) {
+	c.Header("X-Total-Count", strconv.Itoa(meta.Total))
+	if meta.Page > 1 && meta.TotalPages > 0 {
+		c.Writer.Header().Add("Link", pageLink(c, min(meta.Page-1, meta.TotalPages), meta.Limit, "prev"))
+	}
+	if meta.Page < meta.TotalPages {
+		c.Writer.Header().Add("Link", pageLink(c, meta.Page+1, meta.Limit, "next"))
+	}
+}
+
+// pageLink возвращает ссылку на страницу page списка текущего запроса в формате заголовка Link
+func pageLink(c *gin.Context, page, limit int, rel string) string {
+	u := *c.Request.URL
+	query := u.Query()
+	query.Set("page", strconv.Itoa(page))
+	query.Set("limit", strconv.Itoa(limit))
+	u.RawQuery = query.Encode()
+	return fmt.Sprintf("<%s>; rel=%q", u.RequestURI(), rel)
+}
+
+// Error отправляет ошибку: в API v1 - в поле error, на старых маршрутах - в формате ErrorResponse
+func Error(c *gin.Context, status int, message string) {
+	if isEnveloped(c) {
+		c.JSON(status, Envelope{Error: &ErrorBody{Message: message}})
+		return
+	}
+	c.JSON(status, models.ErrorResponse{Message: message})
+}
+
+// ErrorWithDetails отправляет ошибку с дополнительными данными в поле details,
+// по которым клиент может показать причину без разбора текста сообщения
+func ErrorWithDetails(c *gin.Context, status int, message string, details interface{}) {
+	if isEnveloped(c) {
+		c.JSON(status, Envelope{Error: &ErrorBody{Message: message, Details: details}})
+		return
+	}
+	c.JSON(status, models.ErrorResponse{Message: message, Details: details})
+}
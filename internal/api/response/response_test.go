@@ -0,0 +1,148 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupResponseTest регистрирует обработчик в группе API v1 и на устаревшем маршруте
+func setupResponseTest(handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/v1/items", Enveloped(), handler)
+	r.GET("/items", Deprecated("/api/v1"), handler)
+	return r
+}
+
+func serve(r *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestJSON(t *testing.T) {
+	r := setupResponseTest(func(c *gin.Context) {
+		JSON(c, http.StatusOK, map[string]string{"id": "1"})
+	})
+
+	w := serve(r, "/api/v1/items")
+	assert.JSONEq(t, `{"data":{"id":"1"}}`, w.Body.String())
+
+	w = serve(r, "/items")
+	assert.JSONEq(t, `{"id":"1"}`, w.Body.String())
+	assert.Equal(t, "true", w.Header().Get("Deprecation"))
+	assert.Equal(t, `</api/v1/items>; rel="successor-version"`, w.Header().Get("Link"))
+}
+
+func TestPaginated(t *testing.T) {
+	r := setupResponseTest(func(c *gin.Context) {
+		Paginated(c, http.StatusOK, []int{1, 2}, NewMeta(2, 2, 5))
+	})
+
+	w := serve(r, "/api/v1/items?city=Казань&page=2&limit=2")
+	assert.JSONEq(t, `{"data":[1,2],"meta":{"page":2,"limit":2,"total":5,"totalPages":3}}`, w.Body.String())
+	assert.Equal(t, "5", w.Header().Get("X-Total-Count"))
+	assert.Equal(t, []string{
+		`</api/v1/items?city=%D0%9A%D0%B0%D0%B7%D0%B0%D0%BD%D1%8C&limit=2&page=1>; rel="prev"`,
+		`</api/v1/items?city=%D0%9A%D0%B0%D0%B7%D0%B0%D0%BD%D1%8C&limit=2&page=3>; rel="next"`,
+	}, w.Header().Values("Link"))
+
+	w = serve(r, "/items?page=2&limit=2")
+	assert.JSONEq(t, `[1,2]`, w.Body.String())
+	assert.Equal(t, "5", w.Header().Get("X-Total-Count"))
+	// Ссылки на страницы добавляются к ссылке на маршрут-преемник
+	assert.Equal(t, []string{
+		`</api/v1/items>; rel="successor-version"`,
+		`</items?limit=2&page=1>; rel="prev"`,
+		`</items?limit=2&page=3>; rel="next"`,
+	}, w.Header().Values("Link"))
+}
+
+func TestPaginationHeadersBoundaries(t *testing.T) {
+	tests := []struct {
+		name  string
+		meta  Meta
+		links []string
+	}{
+		{"Первая страница", NewMeta(1, 10, 25), []string{`</api/v1/items?limit=10&page=2>; rel="next"`}},
+		{"Последняя страница", NewMeta(3, 10, 25), []string{`</api/v1/items?limit=10&page=2>; rel="prev"`}},
+		{"Страница за концом списка", NewMeta(7, 10, 25), []string{`</api/v1/items?limit=10&page=3>; rel="prev"`}},
+		{"Пустой список", NewMeta(1, 10, 0), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := setupResponseTest(func(c *gin.Context) {
+				Paginated(c, http.StatusOK, []int{}, tt.meta)
+			})
+
+			w := serve(r, "/api/v1/items")
+			assert.Equal(t, tt.links, w.Header().Values("Link"))
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	r := setupResponseTest(func(c *gin.Context) {
+		Error(c, http.StatusBadRequest, "Неверный запрос")
+	})
+
+	w := serve(r, "/api/v1/items")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":{"message":"Неверный запрос"}}`, w.Body.String())
+
+	w = serve(r, "/items")
+	assert.JSONEq(t, `{"message":"Неверный запрос"}`, w.Body.String())
+}
+
+func TestErrorWithDetails(t *testing.T) {
+	r := setupResponseTest(func(c *gin.Context) {
+		ErrorWithDetails(c, http.StatusConflict, "Превышено ограничение", map[string]int{"limit": 10})
+	})
+
+	w := serve(r, "/api/v1/items")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"error":{"message":"Превышено ограничение","details":{"limit":10}}}`, w.Body.String())
+
+	w = serve(r, "/items")
+	assert.JSONEq(t, `{"message":"Превышено ограничение","details":{"limit":10}}`, w.Body.String())
+}
+
+func TestArrayWriter(t *testing.T) {
+	r := setupResponseTest(func(c *gin.Context) {
+		stream := StartArray(c, http.StatusOK)
+		for i := 1; i <= 3; i++ {
+			assert.NoError(t, stream.Add(map[string]int{"id": i}))
+		}
+		assert.Equal(t, 3, stream.Count())
+		assert.NoError(t, stream.Close())
+	})
+
+	w := serve(r, "/api/v1/items")
+	assert.JSONEq(t, `{"data":[{"id":1},{"id":2},{"id":3}]}`, w.Body.String())
+
+	w = serve(r, "/items")
+	assert.JSONEq(t, `[{"id":1},{"id":2},{"id":3}]`, w.Body.String())
+}
+
+func TestPaginatedArrayWriter(t *testing.T) {
+	r := setupResponseTest(func(c *gin.Context) {
+		stream := StartPaginatedArray(c, http.StatusOK, NewMeta(1, 2, 3))
+		assert.NoError(t, stream.Add(map[string]int{"id": 1}))
+		assert.NoError(t, stream.Add(map[string]int{"id": 2}))
+		assert.NoError(t, stream.Close())
+	})
+
+	w := serve(r, "/api/v1/items")
+	assert.JSONEq(t, `{"data":[{"id":1},{"id":2}],"meta":{"page":1,"limit":2,"total":3,"totalPages":2}}`, w.Body.String())
+	assert.Equal(t, "3", w.Header().Get("X-Total-Count"))
+	assert.Equal(t, `</api/v1/items?limit=2&page=2>; rel="next"`, w.Header().Get("Link"))
+
+	w = serve(r, "/items")
+	assert.JSONEq(t, `[{"id":1},{"id":2}]`, w.Body.String())
+}
@@ -0,0 +1,97 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+)
+
+// arrayFlushEvery - через сколько элементов записанная часть массива отправляется клиенту
+const arrayFlushEvery = 500
+
+// ArrayWriter записывает JSON-массив поэлементно, не накапливая ответ в памяти.
+// В API v1 массив передается в поле data
+type ArrayWriter struct {
+	c         *gin.Context
+	enveloped bool
+	count     int
+	meta      *Meta
+}
+
+// StartArray отправляет заголовки ответа со статусом status и открывает массив.
+// После вызова ответ уже начат: ошибку можно только записать в лог и оборвать ответ,
+// клиент увидит незакрытый JSON
+func StartArray(c *gin.Context, status int) *ArrayWriter {
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Status(status)
+
+	w := &ArrayWriter{c: c, enveloped: isEnveloped(c)}
+	if w.enveloped {
+		_, _ = c.Writer.WriteString(`{"data":[`)
+	} else {
+		_, _ = c.Writer.WriteString("[")
+	}
+	return w
+}
+
+// StartPaginatedArray отправляет заголовки страницы списка, как Paginated, и открывает массив.
+// В API v1 параметры пагинации передаются в поле meta после массива
+func StartPaginatedArray(c *gin.Context, status int, meta Meta) *ArrayWriter {
+	PaginationHeaders(c, meta)
+	w := StartArray(c, status)
+	w.meta = &meta
+	return w
+}
+
+// Add добавляет элемент в массив. Ошибка означает, что клиент закрыл соединение
+func (w *ArrayWriter) Add(item interface{}) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	if w.count > 0 {
+		if _, err := w.c.Writer.WriteString(","); err != nil {
+			return err
+		}
+	}
+	if _, err := w.c.Writer.Write(data); err != nil {
+		return err
+	}
+
+	w.count++
+	if w.count%arrayFlushEvery == 0 {
+		w.c.Writer.Flush()
+	}
+	return nil
+}
+
+// Count возвращает количество записанных элементов
+func (w *ArrayWriter) Count() int {
+	return w.count
+}
+
+// Close закрывает массив и отправляет остаток ответа
+func (w *ArrayWriter) Close() error {
+	end := "]"
+	if w.enveloped {
+		end = "]}"
+		if w.meta != nil {
+			meta, err := json.Marshal(w.meta)
+			if err != nil {
+				return err
+			}
+			end = `],"meta":` + string(meta) + "}"
+		}
+	}
+	if _, err := w.c.Writer.WriteString(end); err != nil {
+		return err
+	}
+	w.c.Writer.Flush()
+	return nil
+}
+
+// Attachment предлагает клиенту сохранить ответ файлом filename
+func Attachment(c *gin.Context, filename string) {
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+}
@@ -1,73 +1,376 @@
 package api
 
 import (
+	"log"
+
 	"pvz-service/internal/api/handlers"
 	"pvz-service/internal/api/middleware"
-	"pvz-service/internal/config"
-	"pvz-service/internal/db"
-	"pvz-service/internal/db/queries"
-	"pvz-service/internal/utils"
+	"pvz-service/internal/api/response"
+	"pvz-service/internal/app"
+	"pvz-service/internal/authz"
+	"pvz-service/internal/featureflags"
+	"pvz-service/internal/health"
+	"pvz-service/internal/ratelimit"
+	"pvz-service/internal/storage"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
-func SetupRouter(config *config.Config, db *db.Database) *gin.Engine {
-	// Создаем экземпляр Gin
-	router := gin.Default()
-	router.RemoveExtraSlash = true
+// SetupRouter создает HTTP API поверх зависимостей сервиса c
+func SetupRouter(c *app.Container) *gin.Engine {
+	config := c.Config
 
-	// Создаем менеджер JWT
-	jwtManager := utils.NewJWTManager(&config.JWT)
+	// Режим задается до создания экземпляра Gin, иначе отладочные сообщения успевают попасть в лог
+	gin.SetMode(config.Server.GinMode)
 
-	// Создаем запросы к базе данных
-	authQueries := queries.NewAuthQueries(db)
-	pvzQueries := queries.NewPVZQueries(db)
-	receptionQueries := queries.NewReceptionQueries(db)
-	productQueries := queries.NewProductQueries(db)
+	// Создаем экземпляр Gin. Вместо gin.Recovery паники перехватывает middleware.Recovery,
+	// отправляющий их в Sentry с идентификатором запроса
+	router := gin.New()
+	// IP клиента берется из X-Forwarded-For только от доверенных балансировщиков, иначе - адрес соединения
+	if err := router.SetTrustedProxies(config.Server.TrustedProxies); err != nil {
+		log.Fatalf("Invalid trusted proxies: %v", err)
+	}
+	router.Use(middleware.AccessLog(config.Server.AccessLogFormat), middleware.RequestID(), middleware.Recovery())
+	router.RemoveExtraSlash = true
+	// Спан на каждый запрос, дочерние спаны обработчиков и запросов к БД вкладываются в него
+	router.Use(otelgin.Middleware(config.Tracing.ServiceName))
+	router.Use(middleware.Locale(config.I18n.DefaultLocale))
+	// Тело запроса принимается только в JSON или multipart и ограничено по размеру до разбора обработчиком
+	router.Use(middleware.BodyLimit(middleware.BodyLimits{JSON: config.Server.MaxBodySize, Upload: config.Server.MaxUploadSize}))
+	// Число SQL-запросов на HTTP-запрос: превышение бюджета видно в логе, а вне production - в заголовке ответа
+	router.Use(middleware.QueryBudget(config.Database.QueryBudget, !config.App.IsProduction()))
+	if config.Server.Gzip {
+		router.Use(middleware.Gzip())
+	}
 
-	newPasswordChecker := &utils.DefaultPasswordChecker{}
+	q := c.Queries
 
 	// Создаем обработчики
-	authHandler := handlers.NewAuthHandler(jwtManager, authQueries, newPasswordChecker)
-	pvzHandler := handlers.NewPVZHandler(pvzQueries, receptionQueries, productQueries)
-	receptionHandler := handlers.NewReceptionHandler(receptionQueries)
-	productHandler := handlers.NewProductHandler(productQueries, receptionQueries)
+	authHandler := handlers.NewAuthHandler(c.JWT, q.Auth, c.PasswordHasher, c.Sessions, c.TwoFactor, config.DummyLogin.AllowedRoles, c.LoginAudit, config.LoginAudit.CountryHeader)
+	pvzHandler := handlers.NewPVZHandler(q.PVZ, q.Reception, q.Product, q.Moderator, c.CityResolver, c.Storage)
+	receptionHandler := handlers.NewReceptionHandler(q.Reception, q.Product, q.Manifest, q.ReceptionTemplate, q.Supplier, c.DB, q.Outbox, c.WorkingHours, c.PublicLinks, c.Quotas, c.Locks)
+	productHandler := handlers.NewProductHandler(q.Product, q.Reception, c.DB, q.Outbox, c.Storage, c.ProductService)
+	profileHandler := handlers.NewProfileHandler(q.Auth, c.Storage)
+	orderHandler := handlers.NewOrderHandler(q.Order, c.DB, q.Outbox)
+	reportHandler := handlers.NewReportHandler(q.Report, q.ReportJob, c.CityResolver, c.Storage)
+	forecastHandler := handlers.NewForecastHandler(q.PVZ, q.Report)
+	otpHandler := handlers.NewOTPHandler(c.Sessions, c.TwoFactor, q.Auth, q.OTP, c.SMS, config.OTP)
+	twoFactorHandler := handlers.NewTwoFactorHandler(c.TwoFactor, c.Sessions, q.Auth)
+	eventHandler := handlers.NewEventHandler(c.EventHub, config.Events.PollTimeout)
+	rateLimitHandler := handlers.NewRateLimitHandler(q.RateLimit, c.RateLimitOverrides)
+	productLimitHandler := handlers.NewProductLimitHandler(q.ProductLimit)
+	quotaHandler := handlers.NewQuotaHandler(q.Quota)
+	loginAuditHandler := handlers.NewLoginAuditHandler(q.LoginAudit)
+	receptionArchiveHandler := handlers.NewReceptionArchiveHandler(q.Archive)
+	receptionTemplateHandler := handlers.NewReceptionTemplateHandler(q.ReceptionTemplate)
+	supplierHandler := handlers.NewSupplierHandler(q.Supplier)
+	reportDefinitionHandler := handlers.NewReportDefinitionHandler(q.ReportDefinition, c.ReportBuilder)
+	eventExportHandler := handlers.NewEventExportHandler(q.Outbox)
+	sessionHandler := handlers.NewSessionHandler(q.Session)
+	deliveryHandler := handlers.NewDeliveryHandler(q.Delivery)
+	usageHandler := handlers.NewUsageHandler(q.Usage)
+	syncHandler := handlers.NewSyncHandler(q.Sync)
+	passwordHandler := handlers.NewPasswordHandler(q.Auth, q.PasswordReset, q.Session, c.DB, c.PasswordHasher, c.Mail, config.Reset)
+	featureFlagHandler := handlers.NewFeatureFlagHandler(q.FeatureFlag, q.PVZ, c.DB, c.FeatureFlags)
+	receptionActHandler := handlers.NewReceptionActHandler(q.Reception, q.Product, q.PVZ)
+	workingHoursHandler := handlers.NewWorkingHoursHandler(q.PVZ, q.WorkingHours, c.DB)
+	userHandler := handlers.NewUserHandler(q.Auth, q.Session, c.DB, c.ActiveUsers)
+	searchHandler := handlers.NewSearchHandler(q.Search)
+	publicReceptionHandler := handlers.NewPublicReceptionHandler(q.Reception, c.PublicLinks)
+	pvzArchiveHandler := handlers.NewPVZArchiveHandler(q.PVZ, q.Reception, q.Moderator, c.DB, q.Outbox)
+	receptionReassignHandler := handlers.NewReceptionReassignHandler(q.PVZ, q.Reception, q.Moderator, c.DB, q.Outbox)
+
+	// Проверки готовности: основная база критична всегда, остальные зависимости - если не указаны в READINESS_NON_CRITICAL
+	readinessChecks := []health.Check{{Name: "postgres", Ping: c.DB.PingContext}}
+	if c.DB.HasReplica() {
+		readinessChecks = append(readinessChecks, health.Check{Name: "replica", Ping: c.DB.PingReplica})
+	}
+	if pinger, ok := c.Storage.(storage.Pinger); ok {
+		readinessChecks = append(readinessChecks, health.Check{Name: "storage", Ping: pinger.Ping})
+	}
+	if config.Events.BrokerURL != "" {
+		readinessChecks = append(readinessChecks, health.Check{Name: "broker", Ping: health.DialURL(config.Events.BrokerURL)})
+	}
+	if config.Alerts.WebhookURL != "" {
+		readinessChecks = append(readinessChecks, health.Check{Name: "alerts", Ping: health.DialURL(config.Alerts.WebhookURL)})
+	}
+	healthHandler := handlers.NewHealthHandler(health.NewChecker(config.Health.CheckTimeout, config.Health.NonCritical, readinessChecks...))
 
 	// Создаем middleware для авторизации
-	authMiddleware := middleware.AuthMiddleware(jwtManager)
-	requireModerator := middleware.RequireRole("moderator")
+	authMiddleware := middleware.AuthMiddleware(c.JWT, q.Session, c.ActiveUsers)
+	// Права ролей проверяются по матрице authz, а не по названию роли
+	requireAdmin := middleware.RequirePermission(authz.Admin)
+	requirePVZCreate := middleware.RequirePermission(authz.PVZCreate)
+	requireReports := middleware.RequirePermission(authz.ReportsRead)
+
+	// Ограничение частоты запросов: по IP для публичных маршрутов и по пользователю для защищенных
+	rateLimit := func(c *gin.Context) { c.Next() }
+	if config.RateLimit.Enabled {
+		rateLimit = middleware.RateLimit(ratelimit.NewLimiter(), c.RateLimitOverrides, ratelimit.Limit{
+			RequestsPerMinute: config.RateLimit.RequestsPerMinute,
+			Burst:             config.RateLimit.Burst,
+		})
+	}
 
-	// Публичные маршруты (без авторизации)
-	publicRoutes := router.Group("")
-	{
-		// dummyLogin endpoint для получения тестового токена
-		publicRoutes.POST("/dummyLogin", authHandler.DummyLogin)
+	dummyLoginEnabled := config.DummyLogin.Enabled && !config.App.IsProduction()
+	if !dummyLoginEnabled {
+		log.Printf("dummyLogin is disabled (environment %s)", config.App.Environment)
+	}
+
+	// Тестовые токены /dummyLogin в режиме только для чтения не изменяют данные
+	dummyTokens := func(c *gin.Context) { c.Next() }
+	if config.DummyLogin.ReadOnly {
+		dummyTokens = middleware.ReadOnlyDummyTokens()
+	}
 
-		// Регистрация
-		publicRoutes.POST("/register", authHandler.Register)
+	// Проверки живости и готовности для оркестратора, без авторизации и версии API
+	router.GET("/livez", healthHandler.Live)
+	router.GET("/readyz", healthHandler.Ready)
 
-		// Вход
-		publicRoutes.POST("/login", authHandler.Login)
+	// Скачивание вложений локального хранилища: ссылки подписаны и действуют STORAGE_URL_TTL, авторизация не нужна
+	if downloader, ok := c.Storage.(storage.Downloader); ok {
+		router.GET("/files/*key", handlers.NewFileHandler(downloader).Download)
 	}
 
-	// Защищенные маршруты (с авторизацией)
-	protectedRoutes := router.Group("")
-	protectedRoutes.Use(authMiddleware)
+	// registerRoutes регистрирует все маршруты API в группе api
+	registerRoutes := func(api *gin.RouterGroup) {
+		// Публичные маршруты (без авторизации)
+		publicRoutes := api.Group("")
+		publicRoutes.Use(rateLimit)
+		{
+			// dummyLogin endpoint для получения тестового токена, в промышленном окружении недоступен
+			if dummyLoginEnabled {
+				publicRoutes.POST("/dummyLogin", authHandler.DummyLogin)
+			}
+
+			// Регистрация
+			publicRoutes.POST("/register", authHandler.Register)
+
+			// Вход
+			publicRoutes.POST("/login", authHandler.Login)
+			// Новый токен сессии «запомнить меня» по refresh-токену
+			publicRoutes.POST("/auth/refresh", authHandler.RefreshToken)
+
+			// Вход по телефону и одноразовому коду
+			publicRoutes.POST("/auth/otp/request", otpHandler.RequestCode)
+			publicRoutes.POST("/auth/otp/verify", otpHandler.VerifyCode)
+
+			// Завершение входа кодом из приложения-аутентификатора или резервным кодом
+			publicRoutes.POST("/auth/2fa/verify", twoFactorHandler.Verify)
+
+			// Сброс забытого пароля по токену из письма
+			publicRoutes.POST("/auth/reset/request", passwordHandler.RequestReset)
+			publicRoutes.POST("/auth/reset/confirm", passwordHandler.ConfirmReset)
+
+			// Статус приёмки по подписанной ссылке для поставщиков без учетной записи
+			if c.PublicLinks != nil {
+				publicRoutes.GET("/public/receptions/:token", publicReceptionHandler.GetReceptionStatus)
+			}
+		}
+
+		// Защищенные маршруты (с авторизацией)
+		protectedRoutes := api.Group("")
+		// Запросы считаются по ПВЗ и пользователям до лимита, чтобы в статистику попали и отклоненные
+		protectedRoutes.Use(authMiddleware, middleware.Usage(c.UsageCounter), dummyTokens, rateLimit)
+
+		protectedRoutes.POST("/receptions", receptionHandler.CreateReception)
+		protectedRoutes.POST("/receptions/:receptionId/import", receptionHandler.ImportManifest)
+		// Заметки и теги приёмки (например, «повреждена упаковка») и история их изменений
+		protectedRoutes.PATCH("/receptions/:receptionId", receptionHandler.UpdateReception)
+		protectedRoutes.GET("/receptions/:receptionId/notes", receptionHandler.GetReceptionNotes)
+		// Сверка приёмки с накладной: недостающие, лишние товары и расхождения типов, format=csv - файлом
+		protectedRoutes.GET("/receptions/:receptionId/discrepancies", receptionHandler.GetDiscrepancyReport)
+		// Акт приёмки товаров в PDF для передачи курьеру
+		protectedRoutes.GET("/receptions/:receptionId/act.pdf", receptionActHandler.GetReceptionAct)
+		// Цепочка хранения приёмки для страховых случаев: все действия с исполнителями, format=pdf - в PDF
+		protectedRoutes.GET("/receptions/:receptionId/custody", receptionActHandler.GetReceptionCustody)
+		// Перенос товаров ошибочно открытой приёмки в другую открытую приёмку того же ПВЗ с закрытием исходной
+		protectedRoutes.POST("/receptions/:receptionId/merge_into/:targetId", middleware.RequirePermission(authz.ReceptionMerge), receptionHandler.MergeReceptions)
+		// Перенос приёмки вместе с товарами в другой ПВЗ с записью в журнал переносов
+		protectedRoutes.POST("/receptions/:receptionId/reassign", middleware.RequirePermission(authz.ReceptionReassign), receptionReassignHandler.ReassignReception)
+		// Приёмки, не закрытые в срок SLA
+		protectedRoutes.GET("/receptions/overdue", receptionHandler.GetOverdueReceptions)
+
+		// Шаблоны приёмок для повторяющихся поставок: приёмка по шаблону создается через POST /receptions?templateId=
+		protectedRoutes.GET("/reception-templates", receptionTemplateHandler.ListTemplates)
+		protectedRoutes.GET("/reception-templates/:templateId", receptionTemplateHandler.GetTemplate)
+		protectedRoutes.POST("/reception-templates", middleware.RequirePermission(authz.ReceptionTemplates), receptionTemplateHandler.CreateTemplate)
+		protectedRoutes.PUT("/reception-templates/:templateId", middleware.RequirePermission(authz.ReceptionTemplates), receptionTemplateHandler.UpdateTemplate)
+		protectedRoutes.DELETE("/reception-templates/:templateId", middleware.RequirePermission(authz.ReceptionTemplates), receptionTemplateHandler.DeleteTemplate)
 
-	protectedRoutes.POST("/receptions", authMiddleware, receptionHandler.CreateReception)
+		// Справочник поставщиков: поставщик указывается при создании приёмки в supplierId
+		protectedRoutes.GET("/suppliers", supplierHandler.ListSuppliers)
+		protectedRoutes.GET("/suppliers/:supplierId", supplierHandler.GetSupplier)
+		protectedRoutes.POST("/suppliers", middleware.RequirePermission(authz.SupplierWrite), supplierHandler.CreateSupplier)
+		protectedRoutes.PUT("/suppliers/:supplierId", middleware.RequirePermission(authz.SupplierWrite), supplierHandler.UpdateSupplier)
+		protectedRoutes.DELETE("/suppliers/:supplierId", middleware.RequirePermission(authz.SupplierWrite), supplierHandler.DeleteSupplier)
 
-	protectedRoutes.POST("/products", productHandler.AddProduct)
+		// Поиск по ПВЗ, приёмкам и товарам одной строкой с результатами по группам
+		protectedRoutes.GET("/search", searchHandler.Search)
+
+		// Инкрементальная синхронизация терминалов ПВЗ: изменения ПВЗ, приёмок и товаров после курсора
+		protectedRoutes.GET("/sync", syncHandler.Sync)
+
+		protectedRoutes.POST("/products", productHandler.AddProduct)
+		// Товары приёмки потоком, download=true - файлом
+		protectedRoutes.GET("/products", productHandler.ListProducts)
+		protectedRoutes.POST("/products/status_batch", productHandler.GetStatusBatch)
+		// Удаление товара открытой приёмки по ID: модератор - любой товар, сотрудник - только последний
+		protectedRoutes.DELETE("/products/:productId", productHandler.DeleteProduct)
+		// Исправление типа товара: сотрудник - в открытой приёмке, модератор - в закрытой
+		protectedRoutes.PATCH("/products/:productId", productHandler.UpdateProduct)
+		// Выдача товара, находящегося на хранении (только для сотрудников)
+		protectedRoutes.POST("/products/:productId/issue", productHandler.IssueProduct)
+		// Отметка товара открытой приёмки поврежденным с описанием и фотографиями (только для сотрудников)
+		protectedRoutes.POST("/products/:productId/mark_damaged", productHandler.MarkDamaged)
+
+		// Профиль текущего пользователя: имя, телефон и аватар
+		protectedRoutes.GET("/me", profileHandler.GetProfile)
+		protectedRoutes.PATCH("/me", profileHandler.UpdateProfile)
+
+		// Сессии текущего пользователя
+		protectedRoutes.GET("/me/sessions", sessionHandler.GetSessions)
+		protectedRoutes.DELETE("/me/sessions/:sessionId", sessionHandler.RevokeSession)
+		// Смена пароля по текущему паролю, остальные сессии завершаются
+		protectedRoutes.POST("/me/change_password", passwordHandler.ChangePassword)
+		// Двухфакторная аутентификация: подключение приложения, отключение и резервные коды
+		protectedRoutes.GET("/me/2fa", twoFactorHandler.GetStatus)
+		protectedRoutes.POST("/me/2fa/setup", twoFactorHandler.Setup)
+		protectedRoutes.POST("/me/2fa/enable", twoFactorHandler.Enable)
+		protectedRoutes.POST("/me/2fa/disable", twoFactorHandler.Disable)
+		protectedRoutes.POST("/me/2fa/backup_codes", twoFactorHandler.RegenerateBackupCodes)
+
+		// Заказы покупателей: создание из товаров ПВЗ и выдача получателю
+		protectedRoutes.POST("/orders", orderHandler.CreateOrder)
+		protectedRoutes.GET("/orders/:orderId", orderHandler.GetOrder)
+		protectedRoutes.POST("/orders/:orderId/issue", orderHandler.IssueOrder)
+
+		// Маршруты для работы с ПВЗ
+		pvzRoutes := protectedRoutes.Group("/pvz")
+		{
+			// Создание ПВЗ (только для модераторов)
+			pvzRoutes.POST("", requirePVZCreate, pvzHandler.CreatePVZ)
+			// Массовое создание ПВЗ из CSV-файла с отчётом по строкам, dryRun=true - только проверка
+			pvzRoutes.POST("/import", requirePVZCreate, pvzHandler.ImportPVZ)
+			// Получение списка ПВЗ с фильтрацией и пагинацией, неизменившаяся страница отдается как 304 по ETag
+			pvzRoutes.GET("", middleware.ETag(), pvzHandler.GetPVZList)
+
+			// Маршруты конкретного ПВЗ: сотрудник с назначенными ПВЗ работает только с ними
+			pvzScoped := pvzRoutes.Group("/:pvzId", middleware.RequirePVZAccess())
+
+			// Приёмки ПВЗ от новых к старым с пагинацией, status - фильтр по статусу;
+			// неизменившаяся страница отдается как 304 по ETag
+			pvzScoped.GET("/receptions", middleware.ETag(), receptionHandler.ListReceptions)
+			pvzScoped.POST("/close_last_reception", receptionHandler.CloseLastReception)
+			// Пауза открытой приёмки на обед или пересменку: товары не принимаются, пока приёмка не возобновлена
+			pvzScoped.POST("/pause_reception", receptionHandler.PauseReception)
+			pvzScoped.POST("/resume_reception", receptionHandler.ResumeReception)
+			pvzScoped.POST("/delete_last_product", productHandler.DeleteLastProduct)
+			// Удаление нескольких последних товаров одной транзакцией, например ошибочно отсканированной коробки
+			pvzScoped.POST("/delete_last_products", productHandler.DeleteLastProducts)
+			// Лента событий ПВЗ в режиме long-polling
+			pvzScoped.GET("/events/poll", eventHandler.Poll)
+			// Товары на хранении в ПВЗ
+			pvzScoped.GET("/inventory", productHandler.GetInventory)
+			// Прогноз числа товаров по дням для планирования смен
+			pvzScoped.GET("/forecast", requireReports, forecastHandler.GetForecast)
+			// Часы работы ПВЗ: вне их приёмки и товары не принимаются
+			pvzScoped.GET("/working-hours", workingHoursHandler.GetWorkingHours)
+			pvzScoped.PUT("/working-hours", middleware.RequirePermission(authz.PVZSchedule), workingHoursHandler.SetWorkingHours)
+			// Вместимость ПВЗ: сверх нее товары принимаются только с overrideCapacity
+			pvzScoped.PUT("/capacity", middleware.RequirePermission(authz.PVZCapacity), pvzHandler.SetCapacity)
+			// Удаление ПВЗ в архив, force=true закрывает открытые приёмки. Включается флагом pvz_delete
+			pvzScoped.DELETE("", middleware.RequirePermission(authz.PVZDelete), middleware.RequireFeature(c.FeatureFlags, featureflags.PVZDelete), pvzArchiveHandler.DeletePVZ)
+		}
+
+		// Администрирование (только для модераторов)
+		adminRoutes := protectedRoutes.Group("/admin", requireAdmin)
+		{
+			adminRoutes.GET("/pvz/inactive", pvzHandler.GetInactivePVZ)
+			adminRoutes.POST("/pvz/:pvzId/deactivate", pvzHandler.DeactivatePVZ)
+
+			// Закрытие всех открытых приёмок старше olderThan (по умолчанию 24h)
+			adminRoutes.POST("/receptions/close_stale", receptionHandler.CloseStaleReceptions)
+			// Возврат приёмки, перенесенной в архив фоновым заданием
+			adminRoutes.POST("/receptions/:receptionId/restore", receptionArchiveHandler.RestoreReception)
+
+			// Проверка инвариантов данных, та же проверка периодически выполняется фоновым заданием
+			adminRoutes.GET("/consistency", reportHandler.GetConsistencyReport)
+
+			// Деактивация пользователей: токены деактивированного пользователя перестают приниматься
+			adminRoutes.POST("/users/:userId/deactivate", userHandler.DeactivateUser)
+			adminRoutes.POST("/users/:userId/activate", userHandler.ActivateUser)
+
+			// Индивидуальные лимиты запросов, ключ - user:<id> или ip:<адрес>
+			adminRoutes.GET("/rate-limits", rateLimitHandler.GetOverrides)
+			adminRoutes.PUT("/rate-limits/:key", rateLimitHandler.SetOverride)
+			adminRoutes.DELETE("/rate-limits/:key", rateLimitHandler.DeleteOverride)
+
+			// Неудачные доставки webhook и брокеру: очередь повторов и dead-letter queue
+			adminRoutes.GET("/deliveries", deliveryHandler.GetDeliveries)
+			adminRoutes.POST("/deliveries/:deliveryId/requeue", deliveryHandler.RequeueDelivery)
+
+			// Статистика запросов к API по ПВЗ, пользователям и маршрутам
+			adminRoutes.GET("/usage", usageHandler.GetUsage)
+
+			// Ограничения количества товаров одного типа в приёмке
+			adminRoutes.GET("/product-limits", productLimitHandler.GetLimits)
+			adminRoutes.PUT("/product-limits/:type", productLimitHandler.SetLimit)
+			adminRoutes.DELETE("/product-limits/:type", productLimitHandler.DeleteLimit)
+
+			// Квоты операций по ролям и временные исключения из них для пользователей
+			adminRoutes.GET("/quotas", quotaHandler.GetQuotas)
+			adminRoutes.PUT("/quotas/:role/:operation", quotaHandler.SetQuota)
+			adminRoutes.DELETE("/quotas/:role/:operation", quotaHandler.DeleteQuota)
+			adminRoutes.GET("/quota-overrides", quotaHandler.GetOverrides)
+			adminRoutes.PUT("/quota-overrides/:userId/:operation", quotaHandler.SetOverride)
+			adminRoutes.DELETE("/quota-overrides/:userId/:operation", quotaHandler.DeleteOverride)
+
+			// Журнал попыток входа
+			adminRoutes.GET("/login-audit", loginAuditHandler.GetLoginAudit)
+
+			// Выгрузка истории событий в формате NDJSON
+			adminRoutes.GET("/events/export", eventExportHandler.Export)
+
+			// Флаги функций: общее значение и значения для отдельных ПВЗ
+			adminRoutes.GET("/flags", featureFlagHandler.GetFlags)
+			adminRoutes.PATCH("/flags/:name", featureFlagHandler.UpdateFlag)
+		}
+
+		// Отчёты (только для модераторов)
+		reportRoutes := protectedRoutes.Group("/reports", requireReports)
+		{
+			reportRoutes.GET("/duplicate-barcodes", reportHandler.GetDuplicateBarcodes)
+			// Выгрузка приёмок всех ПВЗ города в CSV, за большой период - в фоне
+			reportRoutes.GET("/receptions", reportHandler.ExportReceptions)
+			reportRoutes.GET("/jobs/:jobId", reportHandler.GetReportJob)
+			// Товары, которые дольше N дней лежат на хранении и не выданы, по ПВЗ
+			reportRoutes.GET("/product-aging", reportHandler.GetProductAging)
+			// Приёмки и принятые товары по поставщикам за период
+			reportRoutes.GET("/suppliers", reportHandler.GetSupplierReport)
+			// Товары, отмеченные поврежденными при приёмке, за период
+			reportRoutes.GET("/damaged-products", reportHandler.GetDamagedProducts)
+			// Конструктор отчётов: сохраненные отчёты запускаются вручную или по расписанию,
+			// результат скачивается по заданию отчёта /reports/jobs/:jobId
+			reportRoutes.GET("/definitions", reportDefinitionHandler.ListDefinitions)
+			reportRoutes.GET("/definitions/:definitionId", reportDefinitionHandler.GetDefinition)
+			reportRoutes.POST("/definitions", reportDefinitionHandler.CreateDefinition)
+			reportRoutes.PUT("/definitions/:definitionId", reportDefinitionHandler.UpdateDefinition)
+			reportRoutes.DELETE("/definitions/:definitionId", reportDefinitionHandler.DeleteDefinition)
+			reportRoutes.POST("/definitions/:definitionId/run", reportDefinitionHandler.RunDefinition)
+		}
+
+		// Объем работы сотрудника за период: открытые и закрытые приёмки, принятые и удаленные товары
+		protectedRoutes.GET("/users/:userId/activity", requireReports, reportHandler.GetUserActivity)
+	}
 
-	// Маршруты для работы с ПВЗ
-	pvzRoutes := protectedRoutes.Group("/pvz")
-	{
-		// Создание ПВЗ (только для модераторов)
-		pvzRoutes.POST("", requireModerator, pvzHandler.CreatePVZ)
-		// Получение списка ПВЗ с фильтрацией и пагинацией
-		pvzRoutes.GET("", pvzHandler.GetPVZList)
+	// Маршруты API v1 с единым форматом ответа
+	registerRoutes(router.Group("/api/v1", response.Enveloped()))
 
-		pvzRoutes.POST("/:pvzId/close_last_reception", authMiddleware, receptionHandler.CloseLastReception)
-		pvzRoutes.POST("/:pvzId/delete_last_product", productHandler.DeleteLastProduct)
+	// Устаревшие маршруты без версии отвечают в прежнем формате и помечаются заголовком Deprecation
+	if config.API.LegacyRoutes {
+		registerRoutes(router.Group("", response.Deprecated("/api/v1")))
 	}
 
 	return router
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/app"
+	"pvz-service/internal/config"
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+)
+
+// setupTestRouter собирает роутер из контейнера зависимостей поверх sqlmock вместо базы данных
+func setupTestRouter(t *testing.T) (*gin.Engine, *app.Container, sqlmock.Sqlmock) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { mockDB.Close() })
+
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+	cfg.Server.GinMode = gin.TestMode
+
+	c, err := app.New(cfg, &db.Database{DB: sqlx.NewDb(mockDB, "sqlmock")})
+	require.NoError(t, err)
+	return SetupRouter(c), c, mock
+}
+
+// TestSetupRouter проверяет сборку маршрутов из контейнера зависимостей без соединения с базой данных
+func TestSetupRouter(t *testing.T) {
+	router, _, _ := setupTestRouter(t)
+
+	routes := map[string]bool{}
+	for _, route := range router.Routes() {
+		routes[route.Method+" "+route.Path] = true
+	}
+	assert.True(t, routes["POST /api/v1/login"])
+	assert.True(t, routes["GET /api/v1/admin/login-audit"])
+	assert.True(t, routes["POST /login"], "устаревшие маршруты включены по умолчанию")
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/livez", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+// TestPVZReceptionsETag проверяет, что неизменившаяся страница приёмок ПВЗ отдается как 304 по ETag
+func TestPVZReceptionsETag(t *testing.T) {
+	router, c, mock := setupTestRouter(t)
+
+	token, err := c.JWT.GenerateToken("user-1", models.RoleEmployee, "", nil)
+	require.NoError(t, err)
+
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM reception WHERE pvz_id = \$1`).
+			WithArgs(pvzID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(`SELECT .* FROM reception WHERE pvz_id = \$1 ORDER BY datetime DESC, id DESC`).
+			WithArgs(pvzID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "pvz_id", "status"}).AddRow("reception-1", pvzID, "close"))
+
+		req, _ := http.NewRequest("GET", "/api/v1/pvz/"+pvzID+"/receptions", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get("")
+	assert.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	second := get(etag)
+	assert.Equal(t, http.StatusNotModified, second.Code)
+	assert.Empty(t, second.Body.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
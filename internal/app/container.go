@@ -0,0 +1,204 @@
+// Package app собирает граф зависимостей сервиса: запросы к базе данных, сервисы и внешние
+// подключения создаются один раз и общие для HTTP API, gRPC API и фоновых заданий.
+// Запросы хранятся под типами интерфейсов: обработчики зависят только от интерфейсов,
+// и тест может подменить отдельные запросы заглушками до сборки маршрутов
+package app
+
+import (
+	"fmt"
+
+	"pvz-service/internal/city"
+	"pvz-service/internal/config"
+	"pvz-service/internal/db"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/events"
+	"pvz-service/internal/featureflags"
+	"pvz-service/internal/mail"
+	"pvz-service/internal/models"
+	"pvz-service/internal/notify"
+	"pvz-service/internal/publiclink"
+	"pvz-service/internal/ratelimit"
+	"pvz-service/internal/service"
+	"pvz-service/internal/sms"
+	"pvz-service/internal/storage"
+	"pvz-service/internal/usage"
+	"pvz-service/internal/utils"
+)
+
+// Queries содержит запросы к базе данных
+type Queries struct {
+	Auth              queries.AuthQueriesInterface
+	PVZ               queries.PVZQueriesInterface
+	Reception         queries.ReceptionQueriesInterface
+	Product           queries.ProductQueriesInterface
+	Report            queries.ReportQueriesInterface
+	Search            queries.SearchQueriesInterface
+	Manifest          queries.ManifestQueriesInterface
+	ReceptionTemplate queries.ReceptionTemplateQueriesInterface
+	Supplier          queries.SupplierQueriesInterface
+	City              queries.CityQueriesInterface
+	OTP               queries.OTPQueriesInterface
+	RateLimit         queries.RateLimitQueriesInterface
+	ProductLimit      queries.ProductLimitQueriesInterface
+	Outbox            queries.OutboxQueriesInterface
+	Session           queries.SessionQueriesInterface
+	Order             queries.OrderQueriesInterface
+	Moderator         queries.ModeratorQueriesInterface
+	Employee          queries.EmployeeQueriesInterface
+	Delivery          queries.DeliveryQueriesInterface
+	PasswordReset     queries.PasswordResetQueriesInterface
+	FeatureFlag       queries.FeatureFlagQueriesInterface
+	WorkingHours      queries.WorkingHoursQueriesInterface
+	ReportJob         queries.ReportJobQueriesInterface
+	TwoFactor         queries.TwoFactorQueriesInterface
+	Usage             queries.UsageQueriesInterface
+	Sync              queries.SyncQueriesInterface
+	Quota             queries.QuotaQueriesInterface
+	LoginAudit        queries.LoginAuditQueriesInterface
+	Archive           queries.ArchiveQueriesInterface
+	Lock              queries.LockQueriesInterface
+	ReportDefinition  queries.ReportDefinitionQueriesInterface
+}
+
+// NewQueries создает запросы к базе данных db
+func NewQueries(db *db.Database) Queries {
+	return Queries{
+		Auth:              queries.NewAuthQueries(db),
+		PVZ:               queries.NewPVZQueries(db),
+		Reception:         queries.NewReceptionQueries(db),
+		Product:           queries.NewProductQueries(db),
+		Report:            queries.NewReportQueries(db),
+		Search:            queries.NewSearchQueries(db),
+		Manifest:          queries.NewManifestQueries(db),
+		ReceptionTemplate: queries.NewReceptionTemplateQueries(db),
+		Supplier:          queries.NewSupplierQueries(db),
+		City:              queries.NewCityQueries(db),
+		OTP:               queries.NewOTPQueries(db),
+		RateLimit:         queries.NewRateLimitQueries(db),
+		ProductLimit:      queries.NewProductLimitQueries(db),
+		Outbox:            queries.NewOutboxQueries(db),
+		Session:           queries.NewSessionQueries(db),
+		Order:             queries.NewOrderQueries(db),
+		Moderator:         queries.NewModeratorQueries(db),
+		Employee:          queries.NewEmployeeQueries(db),
+		Delivery:          queries.NewDeliveryQueries(db),
+		PasswordReset:     queries.NewPasswordResetQueries(db),
+		FeatureFlag:       queries.NewFeatureFlagQueries(db),
+		WorkingHours:      queries.NewWorkingHoursQueries(db),
+		ReportJob:         queries.NewReportJobQueries(db),
+		TwoFactor:         queries.NewTwoFactorQueries(db),
+		Usage:             queries.NewUsageQueries(db),
+		Sync:              queries.NewSyncQueries(db),
+		Quota:             queries.NewQuotaQueries(db),
+		LoginAudit:        queries.NewLoginAuditQueries(db),
+		Archive:           queries.NewArchiveQueries(db),
+		Lock:              queries.NewLockQueries(db),
+		ReportDefinition:  queries.NewReportDefinitionQueries(db),
+	}
+}
+
+// Container содержит зависимости, общие для точек входа сервиса
+type Container struct {
+	Config  *config.Config
+	DB      *db.Database
+	Queries Queries
+
+	// EventHub - лента событий ПВЗ, UsageCounter - счетчики запросов к API до записи в базу
+	EventHub     *events.Hub
+	UsageCounter *usage.Counter
+
+	JWT            *utils.JWTManager
+	PasswordHasher *utils.PasswordHasher
+	SMS            sms.SenderInterface
+	Mail           mail.SenderInterface
+	Storage        storage.Storage
+	// PublicLinks - nil, если публичные ссылки отключены (не задан PUBLIC_LINK_SECRET)
+	PublicLinks *publiclink.Signer
+
+	CityResolver       *city.Resolver
+	RateLimitOverrides *ratelimit.Overrides
+	FeatureFlags       *featureflags.Flags
+
+	// AlertWebhook и EventBroker доставляют события напрямую, AlertNotifier сохраняет
+	// неудачные оповещения в очередь повторов
+	AlertWebhook  notify.Notifier
+	AlertNotifier notify.Notifier
+	EventBroker   notify.Notifier
+
+	WorkingHours   *service.WorkingHours
+	Quotas         *service.Quotas
+	Locks          *service.AdvisoryLocks
+	Sessions       *service.SessionService
+	TwoFactor      *service.TwoFactorService
+	ActiveUsers    *service.ActiveUsers
+	LoginAudit     *service.LoginAudit
+	ProductService *service.ProductService
+	ReportBuilder  *service.ReportBuilder
+}
+
+// New создает все зависимости сервиса по конфигурации cfg поверх соединения database
+func New(cfg *config.Config, database *db.Database) (*Container, error) {
+	c := &Container{
+		Config:       cfg,
+		DB:           database,
+		Queries:      NewQueries(database),
+		EventHub:     events.NewHub(cfg.Events.BufferSize),
+		UsageCounter: usage.NewCounter(),
+		JWT:          utils.NewJWTManager(&cfg.JWT),
+		PublicLinks:  publiclink.NewSigner(cfg.PublicLink.Secret),
+	}
+	q := c.Queries
+
+	var err error
+	// Хеширование паролей алгоритмом из конфигурации
+	if c.PasswordHasher, err = utils.NewPasswordHasher(&cfg.Password); err != nil {
+		return nil, fmt.Errorf("failed to create password hasher: %w", err)
+	}
+	// Провайдер SMS для кодов входа
+	if c.SMS, err = sms.NewSender(cfg.OTP.SMSProvider); err != nil {
+		return nil, fmt.Errorf("failed to create SMS provider: %w", err)
+	}
+	// Отправитель писем для сброса пароля
+	if c.Mail, err = mail.NewSender(&cfg.Mail); err != nil {
+		return nil, fmt.Errorf("failed to create mail sender: %w", err)
+	}
+	// Хранилище вложений для фотографий товаров
+	if c.Storage, err = storage.New(&cfg.Storage); err != nil {
+		return nil, fmt.Errorf("failed to create attachment storage: %w", err)
+	}
+
+	c.CityResolver = city.NewResolver(q.City, city.DefaultCacheTTL)
+	// Индивидуальные лимиты запросов из базы данных
+	c.RateLimitOverrides = ratelimit.NewOverrides(q.RateLimit, cfg.RateLimit.OverridesTTL)
+	// Флаги функций: маршруты новых функций закрываются middleware.RequireFeature
+	c.FeatureFlags = featureflags.NewFlags(q.FeatureFlag, cfg.Features.Enabled, cfg.Features.CacheTTL)
+
+	// Неудачные доставки webhook и брокеру сохраняются и повторяются фоновым заданием
+	c.AlertWebhook = notify.NewNotifier(cfg.Alerts.WebhookURL, cfg.Alerts.WebhookTimeout)
+	c.AlertNotifier = notify.NewDeadLetterNotifier(models.DeliveryTargetAlerts, c.AlertWebhook, q.Delivery)
+	c.EventBroker = notify.NewNotifier(cfg.Events.BrokerURL, cfg.Events.BrokerTimeout)
+
+	// Приёмка товаров разрешена только в часы работы ПВЗ
+	c.WorkingHours = service.NewWorkingHours(q.WorkingHours, q.PVZ, c.FeatureFlags)
+	// Квоты операций по ролям: удаления товаров и создание приёмок сверх квоты отклоняются
+	c.Quotas = service.NewQuotas(q.Quota)
+	// Блокировки ПВЗ в PostgreSQL общие для всех экземпляров сервиса: открытие приёмки и удаление
+	// последних товаров одного ПВЗ не выполняются параллельно
+	c.Locks = service.NewAdvisoryLocks(q.Lock, cfg.Database.LockTimeout)
+	// Токены входа выдаются в сессиях, которые пользователь может завершить
+	c.Sessions = service.NewSessionService(c.JWT, q.Session, q.Employee, q.Auth, cfg.JWT.RememberMeTTL)
+	// Двухфакторная аутентификация по TOTP: после пароля или кода SMS нужен код из приложения
+	c.TwoFactor = service.NewTwoFactorService(q.TwoFactor, database, cfg.TwoFactor)
+	// Статус пользователя кэшируется, чтобы не обращаться к базе данных на каждый запрос
+	c.ActiveUsers = service.NewActiveUsers(q.Auth, cfg.JWT.UserCacheTTL)
+	// Оповещения о подозрительных входах доставляются получателю оповещений, как и оповещения о просроченных приёмках
+	c.LoginAudit = service.NewLoginAudit(q.LoginAudit, c.AlertNotifier, service.LoginAlertRules{
+		FailureThreshold: cfg.LoginAudit.FailureThreshold,
+		FailureWindow:    cfg.LoginAudit.FailureWindow,
+	})
+	// Сохраненные отчёты конструктора запускаются вручную и фоновым заданием по расписанию
+	c.ReportBuilder = service.NewReportBuilder(q.ReportDefinition, q.ReportJob, c.Storage, c.Mail)
+	c.ProductService = service.NewProductService(q.Product, q.Reception, database, q.Outbox, c.Storage, q.ProductLimit, c.WorkingHours, c.Quotas, c.Locks)
+
+	return c, nil
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/config"
+	"pvz-service/internal/db"
+)
+
+// newTestContainer собирает зависимости с конфигурацией по умолчанию поверх sqlmock
+func newTestContainer(t *testing.T, configure func(cfg *config.Config)) *Container {
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { mockDB.Close() })
+
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+	if configure != nil {
+		configure(cfg)
+	}
+
+	c, err := New(cfg, &db.Database{DB: sqlx.NewDb(mockDB, "sqlmock")})
+	require.NoError(t, err)
+	return c
+}
+
+// TestNewContainer проверяет, что все зависимости созданы и не требуют соединения с базой данных
+func TestNewContainer(t *testing.T) {
+	c := newTestContainer(t, nil)
+
+	assert.NotNil(t, c.Queries.Auth)
+	assert.NotNil(t, c.Queries.Archive)
+	assert.NotNil(t, c.ProductService)
+	assert.NotNil(t, c.LoginAudit)
+	assert.Nil(t, c.PublicLinks)
+}
+
+// TestNewContainerInvalidConfig проверяет ошибку вместо паники при неверной конфигурации
+func TestNewContainerInvalidConfig(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+	cfg.Storage.Backend = "ftp"
+
+	_, err = New(cfg, &db.Database{DB: sqlx.NewDb(mockDB, "sqlmock")})
+	assert.Error(t, err)
+}
+
+// TestNewJobs проверяет, что архивирование приёмок создается только при заданном сроке хранения
+func TestNewJobs(t *testing.T) {
+	assert.Nil(t, NewJobs(newTestContainer(t, nil)).ReceptionArchive)
+
+	c := newTestContainer(t, func(cfg *config.Config) { cfg.Jobs.ArchiveAfter = 3 * 365 * 24 * time.Hour })
+	assert.NotNil(t, NewJobs(c).ReceptionArchive)
+}
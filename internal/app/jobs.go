@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+
+	"pvz-service/internal/jobs"
+	"pvz-service/internal/models"
+	"pvz-service/internal/notify"
+	"pvz-service/internal/service"
+)
+
+// Jobs содержит фоновые задания сервиса
+type Jobs struct {
+	InactivePVZ  *jobs.InactivePVZJob
+	Consistency  *jobs.ConsistencyJob
+	UsageFlush   *jobs.UsageFlushJob
+	ReceptionSLA *jobs.ReceptionSLAJob
+	OutboxRelay  *jobs.OutboxRelay
+	// DeliveryRetry повторяет неудачные доставки webhook и брокеру
+	DeliveryRetry *jobs.DeliveryRetryJob
+	// ReceptionArchive - nil, если архивирование приёмок выключено (ARCHIVE_RECEPTIONS_AFTER_DAYS=0)
+	ReceptionArchive *jobs.ReceptionArchiveJob
+	// ReportSchedule запускает сохраненные отчёты по расписанию
+	ReportSchedule *jobs.ReportScheduleJob
+}
+
+// NewJobs создает фоновые задания по зависимостям c
+func NewJobs(c *Container) *Jobs {
+	cfg, q := c.Config, c.Queries
+
+	j := &Jobs{
+		InactivePVZ:  jobs.NewInactivePVZJob(q.PVZ, cfg.Jobs.InactivePVZThreshold, cfg.Jobs.InactivePVZInterval),
+		Consistency:  jobs.NewConsistencyJob(service.NewConsistencyChecker(q.Report), cfg.Jobs.ConsistencyInterval),
+		UsageFlush:   jobs.NewUsageFlushJob(c.UsageCounter, q.Usage, cfg.Jobs.UsageFlushInterval),
+		ReceptionSLA: jobs.NewReceptionSLAJob(q.Reception, c.EventHub, c.AlertNotifier, cfg.Jobs.ReceptionSLA, cfg.Jobs.ReceptionSLAInterval),
+		// Релей доставляет события, записанные обработчиками в outbox, в ленту ПВЗ и брокер
+		OutboxRelay: jobs.NewOutboxRelay(q.Outbox, c.EventHub, c.EventBroker, q.Delivery, cfg.Events.RelayInterval, cfg.Events.RelayBatchSize, cfg.Events.RelayMaxAttempts, cfg.Events.OutboxRetention),
+		DeliveryRetry: jobs.NewDeliveryRetryJob(q.Delivery, map[string]notify.Notifier{
+			models.DeliveryTargetAlerts: c.AlertWebhook,
+			models.DeliveryTargetBroker: c.EventBroker,
+		}, cfg.Events.RetryInterval, cfg.Events.RetryBaseDelay, cfg.Events.RetryMaxAttempts, cfg.Events.RelayBatchSize),
+		ReportSchedule: jobs.NewReportScheduleJob(c.ReportBuilder, cfg.Jobs.ReportScheduleInterval),
+	}
+
+	// Архивирование старых приёмок включается сроком хранения ARCHIVE_RECEPTIONS_AFTER_DAYS
+	if cfg.Jobs.ArchiveAfter > 0 {
+		j.ReceptionArchive = jobs.NewReceptionArchiveJob(q.Archive, cfg.Jobs.ArchiveAfter, cfg.Jobs.ArchiveBatchSize, cfg.Jobs.ArchiveInterval)
+	}
+
+	return j
+}
+
+// Start запускает все задания, они останавливаются при отмене ctx
+func (j *Jobs) Start(ctx context.Context) {
+	go j.InactivePVZ.Run(ctx)
+	go j.Consistency.Run(ctx)
+	go j.UsageFlush.Run(ctx)
+	go j.ReceptionSLA.Run(ctx)
+	go j.OutboxRelay.Run(ctx)
+	go j.DeliveryRetry.Run(ctx)
+	go j.ReportSchedule.Run(ctx)
+	if j.ReceptionArchive != nil {
+		go j.ReceptionArchive.Run(ctx)
+	}
+}
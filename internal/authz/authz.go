@@ -0,0 +1,114 @@
+// Package authz описывает права ролей пользователей. Обработчики и middleware проверяют
+// право на действие, а не конкретную роль, поэтому новая роль добавляется только в матрицу
+package authz
+
+import (
+	"slices"
+	"sort"
+
+	"pvz-service/internal/models"
+)
+
+// Permission - право на действие в API
+type Permission string
+
+// Права на действия
+const (
+	// PVZCreate - создание и импорт ПВЗ
+	PVZCreate Permission = "pvz:create"
+	// PVZDelete - удаление ПВЗ в архив
+	PVZDelete Permission = "pvz:delete"
+	// PVZSchedule - изменение часов работы ПВЗ
+	PVZSchedule Permission = "pvz:schedule"
+	// PVZCapacity - изменение вместимости ПВЗ
+	PVZCapacity Permission = "pvz:capacity"
+	// ReceptionWrite - создание приёмки и изменение ее заметки и тегов
+	ReceptionWrite Permission = "reception:write"
+	// ReceptionMerge - объединение ошибочно открытых приёмок
+	ReceptionMerge Permission = "reception:merge"
+	// ReceptionReassign - перенос приёмки с товарами в другой ПВЗ
+	ReceptionReassign Permission = "reception:reassign"
+	// ReceptionTemplates - создание, изменение и удаление шаблонов приёмок
+	ReceptionTemplates Permission = "reception:templates"
+	// SupplierWrite - создание, изменение и удаление поставщиков
+	SupplierWrite Permission = "supplier:write"
+	// ProductAdd - добавление товара в открытую приёмку
+	ProductAdd Permission = "product:add"
+	// ProductDeleteLast - удаление последнего добавленного товара открытой приёмки
+	ProductDeleteLast Permission = "product:delete_last"
+	// ProductDeleteAny - удаление любого товара открытой приёмки
+	ProductDeleteAny Permission = "product:delete_any"
+	// ProductRetypeOpen - исправление типа товара открытой приёмки
+	ProductRetypeOpen Permission = "product:retype_open"
+	// ProductRetypeClosed - исправление типа товара закрытой приёмки
+	ProductRetypeClosed Permission = "product:retype_closed"
+	// ProductMarkDamaged - отметка товара открытой приёмки поврежденным
+	ProductMarkDamaged Permission = "product:mark_damaged"
+	// ProductIssue - выдача товара с хранения
+	ProductIssue Permission = "product:issue"
+	// OrderWrite - создание и выдача заказов
+	OrderWrite Permission = "order:write"
+	// Admin - администрирование: лимиты, доставки, флаги функций, деактивация ПВЗ
+	Admin Permission = "admin"
+	// ReportsRead - просмотр отчётов
+	ReportsRead Permission = "reports:read"
+)
+
+// matrix - права каждой роли
+var matrix = map[string][]Permission{
+	models.RoleEmployee: {
+		ReceptionWrite,
+		ProductAdd,
+		ProductDeleteLast,
+		ProductRetypeOpen,
+		ProductMarkDamaged,
+		ProductIssue,
+		OrderWrite,
+	},
+	models.RoleModerator: {
+		PVZCreate,
+		PVZDelete,
+		PVZSchedule,
+		PVZCapacity,
+		ReceptionMerge,
+		ReceptionReassign,
+		ReceptionTemplates,
+		SupplierWrite,
+		ProductDeleteAny,
+		ProductRetypeClosed,
+		Admin,
+		ReportsRead,
+	},
+}
+
+// Can сообщает, есть ли у роли право permission. У неизвестной роли прав нет
+func Can(role string, permission Permission) bool {
+	for _, granted := range matrix[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccessPVZ сообщает, входит ли ПВЗ в область токена из claim pvz_ids.
+// Пустая область не ограничивает доступ
+func CanAccessPVZ(pvzIDs []string, pvzID string) bool {
+	return len(pvzIDs) == 0 || slices.Contains(pvzIDs, pvzID)
+}
+
+// IsRole сообщает, известна ли роль
+func IsRole(role string) bool {
+	_, ok := matrix[role]
+	return ok
+}
+
+// Roles возвращает известные роли в алфавитном порядке
+func Roles() []string {
+	roles := make([]string, 0, len(matrix))
+	for role := range matrix {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
@@ -0,0 +1,60 @@
+package authz
+
+import (
+	"testing"
+
+	"pvz-service/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCan(t *testing.T) {
+	tests := []struct {
+		role       string
+		permission Permission
+		want       bool
+	}{
+		{models.RoleEmployee, ReceptionWrite, true},
+		{models.RoleEmployee, ProductDeleteLast, true},
+		{models.RoleEmployee, ProductDeleteAny, false},
+		{models.RoleEmployee, ProductRetypeOpen, true},
+		{models.RoleEmployee, ProductRetypeClosed, false},
+		{models.RoleEmployee, ProductMarkDamaged, true},
+		{models.RoleModerator, ProductMarkDamaged, false},
+		{models.RoleEmployee, PVZCreate, false},
+		{models.RoleEmployee, Admin, false},
+		{models.RoleModerator, PVZCreate, true},
+		{models.RoleModerator, PVZCapacity, true},
+		{models.RoleEmployee, PVZCapacity, false},
+		{models.RoleModerator, ProductDeleteAny, true},
+		{models.RoleModerator, ProductAdd, false},
+		{models.RoleModerator, ProductRetypeClosed, true},
+		{models.RoleModerator, Admin, true},
+		{models.RoleModerator, ReceptionReassign, true},
+		{models.RoleEmployee, ReceptionReassign, false},
+		{models.RoleModerator, ReceptionTemplates, true},
+		{models.RoleEmployee, ReceptionTemplates, false},
+		{models.RoleModerator, SupplierWrite, true},
+		{models.RoleEmployee, SupplierWrite, false},
+		{"auditor", ReportsRead, false},
+		{"", Admin, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role+"/"+string(tt.permission), func(t *testing.T) {
+			assert.Equal(t, tt.want, Can(tt.role, tt.permission))
+		})
+	}
+}
+
+func TestCanAccessPVZ(t *testing.T) {
+	assert.True(t, CanAccessPVZ(nil, "pvz-1"))
+	assert.True(t, CanAccessPVZ([]string{"pvz-1", "pvz-2"}, "pvz-2"))
+	assert.False(t, CanAccessPVZ([]string{"pvz-1"}, "pvz-2"))
+}
+
+func TestRoles(t *testing.T) {
+	assert.Equal(t, []string{models.RoleEmployee, models.RoleModerator}, Roles())
+	assert.True(t, IsRole(models.RoleModerator))
+	assert.False(t, IsRole("admin"))
+}
@@ -0,0 +1,86 @@
+package city
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/models"
+)
+
+// fakeCityQueries отдает справочник и считает обращения к нему
+type fakeCityQueries struct {
+	cities []models.City
+	err    error
+	calls  int
+}
+
+func (q *fakeCityQueries) GetCities(ctx context.Context) ([]models.City, error) {
+	q.calls++
+	return q.cities, q.err
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"Москва":             "москва",
+		"  санкт-петербург ": "санкт петербург",
+		"САНКТ  ПЕТЕРБУРГ":   "санкт петербург",
+		"Санкт–Петербург":    "санкт петербург",
+		"Орёл":               "орел",
+		"St. Petersburg":     "st petersburg",
+		"":                   "",
+	}
+
+	for input, expected := range tests {
+		assert.Equal(t, expected, Normalize(input), input)
+	}
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	cityQueries := &fakeCityQueries{cities: []models.City{
+		{Name: "Москва", Aliases: []string{"мск", "Moscow"}},
+		{Name: "Санкт-Петербург", Aliases: []string{"СПб", "st petersburg"}},
+	}}
+	resolver := NewResolver(cityQueries, DefaultCacheTTL)
+
+	for input, expected := range map[string]string{
+		"москва":          "Москва",
+		"MOSCOW":          "Москва",
+		"санкт петербург": "Санкт-Петербург",
+		"спб":             "Санкт-Петербург",
+		"St. Petersburg":  "Санкт-Петербург",
+	} {
+		name, err := resolver.Resolve(context.Background(), input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, name, input)
+	}
+
+	_, err := resolver.Resolve(context.Background(), "Новосибирск")
+	assert.ErrorIs(t, err, ErrUnknownCity)
+
+	// Справочник загружается один раз в пределах ttl
+	assert.Equal(t, 1, cityQueries.calls)
+}
+
+func TestResolver_UsesStaleIndexOnError(t *testing.T) {
+	cityQueries := &fakeCityQueries{cities: []models.City{{Name: "Казань"}}}
+	resolver := NewResolver(cityQueries, 0)
+
+	_, err := resolver.Resolve(context.Background(), "казань")
+	assert.NoError(t, err)
+
+	cityQueries.err = errors.New("database error")
+	name, err := resolver.Resolve(context.Background(), "казань")
+	assert.NoError(t, err)
+	assert.Equal(t, "Казань", name)
+}
+
+func TestResolver_LoadError(t *testing.T) {
+	resolver := NewResolver(&fakeCityQueries{err: errors.New("database error")}, DefaultCacheTTL)
+
+	_, err := resolver.Resolve(context.Background(), "москва")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrUnknownCity)
+}
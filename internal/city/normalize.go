@@ -0,0 +1,24 @@
+package city
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Normalize приводит ввод к ключу сравнения: обрезает пробелы, приводит к нижнему
+// регистру, заменяет ё на е, а дефисы, точки и повторные пробелы - на один пробел.
+// Так "Санкт-Петербург", "санкт петербург" и " САНКТ-ПЕТЕРБУРГ " дают один ключ
+func Normalize(input string) string {
+	mapped := strings.Map(func(r rune) rune {
+		switch {
+		case r == 'ё' || r == 'Ё':
+			return 'е'
+		case r == '-' || r == '.' || r == '_' || r == '‐' || r == '–' || r == '—':
+			return ' '
+		default:
+			return unicode.ToLower(r)
+		}
+	}, input)
+
+	return strings.Join(strings.Fields(mapped), " ")
+}
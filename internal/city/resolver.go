@@ -0,0 +1,96 @@
+package city
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+)
+
+// DefaultCacheTTL - время жизни кэша справочника городов
+const DefaultCacheTTL = 5 * time.Minute
+
+// ErrUnknownCity возвращается, если ввод не соответствует ни одному городу справочника
+var ErrUnknownCity = errors.New("unknown city")
+
+// ResolverInterface определяет интерфейс для приведения ввода к каноническому названию города
+type ResolverInterface interface {
+	Resolve(ctx context.Context, input string) (string, error)
+}
+
+// Resolver сопоставляет ввод пользователя с каноническим названием города
+// по справочнику cities, кэшируя его в памяти
+type Resolver struct {
+	cityQueries queries.CityQueriesInterface
+	ttl         time.Duration
+
+	mu       sync.RWMutex
+	index    map[string]string
+	loadedAt time.Time
+}
+
+// NewResolver создает новый экземпляр Resolver
+func NewResolver(cityQueries queries.CityQueriesInterface, ttl time.Duration) *Resolver {
+	return &Resolver{
+		cityQueries: cityQueries,
+		ttl:         ttl,
+	}
+}
+
+// Resolve возвращает каноническое название города для ввода пользователя
+func (r *Resolver) Resolve(ctx context.Context, input string) (string, error) {
+	index, err := r.getIndex(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	name, ok := index[Normalize(input)]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownCity, input)
+	}
+	return name, nil
+}
+
+// getIndex возвращает индекс справочника, перечитывая его по истечении ttl
+func (r *Resolver) getIndex(ctx context.Context) (map[string]string, error) {
+	r.mu.RLock()
+	index, loadedAt := r.index, r.loadedAt
+	r.mu.RUnlock()
+
+	if index != nil && time.Since(loadedAt) < r.ttl {
+		return index, nil
+	}
+
+	cities, err := r.cityQueries.GetCities(ctx)
+	if err != nil {
+		// Устаревший справочник лучше отказа в создании ПВЗ
+		if index != nil {
+			return index, nil
+		}
+		return nil, fmt.Errorf("failed to load cities: %w", err)
+	}
+
+	index = buildIndex(cities)
+
+	r.mu.Lock()
+	r.index, r.loadedAt = index, time.Now()
+	r.mu.Unlock()
+
+	return index, nil
+}
+
+// buildIndex строит отображение нормализованных названий и вариантов написания в каноническое название
+func buildIndex(cities []models.City) map[string]string {
+	index := make(map[string]string)
+	for _, c := range cities {
+		index[Normalize(c.Name)] = c.Name
+		for _, alias := range c.Aliases {
+			index[Normalize(alias)] = c.Name
+		}
+	}
+	return index
+}
@@ -0,0 +1,44 @@
+package clock
+
+import (
+	"fmt"
+	"time"
+
+	// База часовых поясов встраивается в бинарник: в образе alpine ее нет
+	_ "time/tzdata"
+)
+
+// Clock - источник текущего времени. Сервис хранит и отдает все отметки времени в UTC,
+// поэтому реализации возвращают время в UTC
+type Clock interface {
+	Now() time.Time
+}
+
+// System возвращает текущее системное время в UTC
+type System struct{}
+
+// Now возвращает текущее время в UTC
+func (System) Now() time.Time {
+	return time.Now().UTC()
+}
+
+// Fixed всегда возвращает одно и то же время, используется в тестах
+type Fixed time.Time
+
+// Now возвращает зафиксированное время в UTC
+func (f Fixed) Now() time.Time {
+	return time.Time(f).UTC()
+}
+
+// Location возвращает часовой пояс для отображения времени по имени из базы IANA.
+// Пустое имя означает UTC
+func Location(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q: %w", name, err)
+	}
+	return loc, nil
+}
@@ -0,0 +1,33 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystemReturnsUTC(t *testing.T) {
+	assert.Equal(t, time.UTC, System{}.Now().Location())
+}
+
+func TestFixed(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	c := Fixed(time.Date(2026, 3, 1, 0, 30, 0, 0, moscow))
+
+	// Время в другом поясе приводится к UTC: после полуночи по Москве в UTC еще прошлые сутки
+	assert.Equal(t, time.Date(2026, 2, 28, 21, 30, 0, 0, time.UTC), c.Now())
+}
+
+func TestLocation(t *testing.T) {
+	loc, err := Location("")
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, loc)
+
+	loc, err = Location("Europe/Moscow")
+	assert.NoError(t, err)
+	assert.Equal(t, "Europe/Moscow", loc.String())
+
+	_, err = Location("Mars/Olympus")
+	assert.Error(t, err)
+}
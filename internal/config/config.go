@@ -1,22 +1,91 @@
 package config
 
 import (
+	"fmt"
+	"log"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // Config содержит все настройки приложения
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
+	App        AppConfig
+	Server     ServerConfig
+	API        APIConfig
+	Database   DatabaseConfig
+	JWT        JWTConfig
+	Password   PasswordConfig
+	Jobs       JobsConfig
+	I18n       I18nConfig
+	Events     EventsConfig
+	OTP        OTPConfig
+	TwoFactor  TwoFactorConfig
+	Reset      PasswordResetConfig
+	Mail       MailConfig
+	RateLimit  RateLimitConfig
+	Features   FeatureFlagsConfig
+	Alerts     AlertsConfig
+	LoginAudit LoginAuditConfig
+	Tracing    TracingConfig
+	Metrics    MetricsConfig
+	Sentry     SentryConfig
+	Storage    StorageConfig
+	DummyLogin DummyLoginConfig
+	Health     HealthConfig
+	PublicLink PublicLinkConfig
+
+	// invalid - переменные окружения, которые не удалось разобрать при загрузке
+	invalid []FieldError
+}
+
+// Окружения, в которых запускается сервис
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
+
+// AppConfig содержит общие настройки приложения
+type AppConfig struct {
+	Environment string
+}
+
+// IsProduction сообщает, запущен ли сервис в промышленном окружении
+func (c AppConfig) IsProduction() bool {
+	return c.Environment == EnvProduction
 }
 
-// ServerConfig содержит настройки сервера
+// ServerConfig содержит настройки сервера.
+// Gzip включает сжатие ответов для клиентов, передающих Accept-Encoding: gzip.
+// GRPCPort - порт gRPC сервера для сканеров, пустое значение его отключает.
+// MaxBodySize и MaxUploadSize ограничивают в байтах тело JSON-запроса и загрузку файлов.
+// GinMode - режим Gin (debug, release, test), TrustedProxies - адреса и подсети балансировщиков,
+// чьим заголовкам X-Forwarded-For доверяется при определении IP клиента. AccessLogFormat - формат
+// журнала запросов: common (Common Log Format) или json
 type ServerConfig struct {
-	Port         string
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
+	Port            string
+	GRPCPort        string
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	Gzip            bool
+	MaxBodySize     int64
+	MaxUploadSize   int64
+	GinMode         string
+	TrustedProxies  []string
+	AccessLogFormat string
+}
+
+// Форматы журнала запросов
+const (
+	AccessLogCommon = "common"
+	AccessLogJSON   = "json"
+)
+
+// APIConfig содержит настройки версий API.
+// LegacyRoutes сохраняет устаревшие маршруты без префикса /api/v1 на время перехода клиентов
+type APIConfig struct {
+	LegacyRoutes bool
 }
 
 // DatabaseConfig содержит настройки базы данных
@@ -27,41 +96,553 @@ type DatabaseConfig struct {
 	Password string
 	DBName   string
 	SSLMode  string
+
+	// Реплика для запросов только на чтение, не используется при пустом ReplicaHost
+	ReplicaHost     string
+	ReplicaPort     string
+	ReplicaUser     string
+	ReplicaPassword string
+
+	// Запросы дольше SlowQueryThreshold пишутся в лог, 0 отключает журнал медленных запросов
+	SlowQueryThreshold time.Duration
+
+	// PrepareStatements включает подготовленные запросы для горячих путей.
+	// Выключается за пулером соединений, не поддерживающим подготовленные запросы
+	PrepareStatements bool
+
+	// ShadowMigrations - режимы переходного периода миграций схемы в виде name=mode,
+	// например product_status=shadow_read
+	ShadowMigrations []string
+
+	// Повтор запросов при временных ошибках PostgreSQL: автоматически для чтений,
+	// явно через Database.Retry для записи. RetryMaxAttempts=1 отключает повторы,
+	// RetryBudget - доля повторов от успешных запросов
+	RetryMaxAttempts int
+	RetryBaseDelay   time.Duration
+	RetryMaxDelay    time.Duration
+	RetryBudget      float64
+
+	// LockTimeout - время ожидания блокировки ПВЗ, общей для экземпляров сервиса,
+	// после которого операция отклоняется
+	LockTimeout time.Duration
+
+	// QueryBudget - число SQL-запросов на один HTTP-запрос, сверх которого запрос пишется в лог
+	// и метрики как вероятный N+1, 0 отключает проверку
+	QueryBudget int
 }
 
 // JWTConfig содержит настройки JWT
 type JWTConfig struct {
-	Secret     string
+	Secret string
+	// ExpireTime - срок действия токена для ролей, которых нет в RoleExpireTime
 	ExpireTime time.Duration
+	// RoleExpireTime - срок действия токена по ролям, например короткий для модераторов
+	RoleExpireTime map[string]time.Duration
+	// RememberMeTTL - срок сессии «запомнить меня» с refresh-токеном для киосков, 0 - не выдавать refresh-токены
+	RememberMeTTL time.Duration
+	Issuer        string
+	Audience      string
+	Leeway        time.Duration
+	// UserCacheTTL - сколько middleware авторизации доверяет закэшированному статусу пользователя
+	UserCacheTTL time.Duration
+}
+
+// PasswordConfig содержит настройки хеширования паролей: алгоритм (bcrypt или argon2id),
+// стоимость bcrypt и параметры argon2id (память в КиБ, число проходов и потоков)
+type PasswordConfig struct {
+	Algorithm     string
+	BcryptCost    int
+	Argon2Memory  uint32
+	Argon2Time    uint32
+	Argon2Threads uint8
+}
+
+// JobsConfig содержит настройки фоновых заданий
+type JobsConfig struct {
+	InactivePVZThreshold time.Duration
+	InactivePVZInterval  time.Duration
+	ReceptionSLA         time.Duration
+	ReceptionSLAInterval time.Duration
+	ConsistencyInterval  time.Duration
+	// UsageFlushInterval - период записи накопленной статистики использования API в базу данных
+	UsageFlushInterval time.Duration
+	// ArchiveAfter - через сколько после закрытия приёмка переносится в архив, ноль отключает архивирование
+	ArchiveAfter     time.Duration
+	ArchiveInterval  time.Duration
+	ArchiveBatchSize int
+	// ReportScheduleInterval - период проверки сохраненных отчётов, которым пора запускаться по расписанию
+	ReportScheduleInterval time.Duration
+}
+
+// AlertsConfig содержит настройки оповещений внешних систем
+type AlertsConfig struct {
+	WebhookURL     string
+	WebhookTimeout time.Duration
+}
+
+// LoginAuditConfig содержит настройки журнала входов: порог неудачных входов с одного email
+// или IP за окно FailureWindow для оповещения (0 отключает оповещение) и заголовок балансировщика
+// со страной клиента для оповещения о входе из новой страны
+type LoginAuditConfig struct {
+	FailureThreshold int
+	FailureWindow    time.Duration
+	CountryHeader    string
+}
+
+// I18nConfig содержит настройки локализации сообщений API
+type I18nConfig struct {
+	DefaultLocale string
+}
+
+// EventsConfig содержит настройки ленты событий ПВЗ и релея outbox,
+// который доставляет события в ленту и брокер (webhook BrokerURL).
+// Событие, не доставленное брокеру за RelayMaxAttempts попыток, и неудачные оповещения
+// webhook повторяются раз в RetryInterval с задержкой от RetryBaseDelay, удваивающейся
+// после каждой попытки; после RetryMaxAttempts попыток доставка попадает в dead-letter queue
+type EventsConfig struct {
+	BufferSize       int
+	PollTimeout      time.Duration
+	RelayInterval    time.Duration
+	RelayBatchSize   int
+	RelayMaxAttempts int
+	OutboxRetention  time.Duration
+	BrokerURL        string
+	BrokerTimeout    time.Duration
+	RetryInterval    time.Duration
+	RetryBaseDelay   time.Duration
+	RetryMaxAttempts int
+}
+
+// OTPConfig содержит настройки входа по одноразовому коду
+type OTPConfig struct {
+	CodeTTL       time.Duration
+	RequestLimit  int
+	RequestWindow time.Duration
+	MaxAttempts   int
+	SMSProvider   string
+}
+
+// TwoFactorConfig содержит настройки двухфакторной аутентификации по TOTP.
+// Issuer показывается в приложении-аутентификаторе рядом с учетной записью,
+// ChallengeTTL и MaxAttempts ограничивают ввод второго фактора после пароля
+type TwoFactorConfig struct {
+	Issuer       string
+	ChallengeTTL time.Duration
+	MaxAttempts  int
+	BackupCodes  int
+}
+
+// PasswordResetConfig содержит настройки сброса пароля по ссылке из письма.
+// RequestLimit ограничивает число писем пользователю за RequestWindow
+type PasswordResetConfig struct {
+	TokenTTL      time.Duration
+	RequestLimit  int
+	RequestWindow time.Duration
+}
+
+// MailConfig содержит настройки отправки писем.
+// Provider - log (письма пишутся в лог) или smtp; SMTPAddr - адрес сервера в виде host:port
+type MailConfig struct {
+	Provider     string
+	From         string
+	SMTPAddr     string
+	SMTPUser     string
+	SMTPPassword string
+}
+
+// FeatureFlagsConfig содержит настройки флагов функций.
+// Enabled - флаги, включенные до того, как они заданы в базе данных, CacheTTL - время жизни кэша флагов
+type FeatureFlagsConfig struct {
+	Enabled  []string
+	CacheTTL time.Duration
+}
+
+// RateLimitConfig содержит настройки ограничения частоты запросов
+type RateLimitConfig struct {
+	Enabled           bool
+	RequestsPerMinute int
+	Burst             int
+	OverridesTTL      time.Duration
+}
+
+// TracingConfig содержит настройки трассировки OpenTelemetry.
+// Endpoint - адрес коллектора OTLP/HTTP в виде host:port, SampleRatio - доля записываемых трасс от 0 до 1
+type TracingConfig struct {
+	Enabled     bool
+	ServiceName string
+	Endpoint    string
+	Insecure    bool
+	SampleRatio float64
+}
+
+// MetricsConfig содержит настройки экспорта метрик OpenTelemetry.
+// Endpoint - адрес коллектора OTLP/HTTP в виде host:port, Interval - период отправки накопленных метрик
+type MetricsConfig struct {
+	Enabled  bool
+	Endpoint string
+	Insecure bool
+	Interval time.Duration
 }
 
-// LoadConfig загружает конфигурацию из переменных окружения
-func LoadConfig() *Config {
-	return &Config{
+// SentryConfig содержит настройки отправки паник в Sentry. Пустой DSN отключает отправку
+type SentryConfig struct {
+	DSN string
+}
+
+// StorageConfig содержит настройки хранилища вложений.
+// Backend - s3, local или пустая строка, если хранилище не используется; URLTTL - срок действия ссылок на скачивание.
+// Локальное хранилище держит файлы в каталоге LocalDir и выдает ссылки на сервис по адресу PublicURL,
+// подписанные ключом LocalSecret
+type StorageConfig struct {
+	Backend     string
+	URLTTL      time.Duration
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3UseSSL    bool
+	LocalDir    string
+	LocalSecret string
+	PublicURL   string
+}
+
+// PublicLinkConfig содержит настройки публичных ссылок на статус приёмки для поставщиков.
+// Secret подписывает токены ссылок, пустое значение отключает публичные ссылки
+type PublicLinkConfig struct {
+	Secret string
+}
+
+// HealthConfig содержит настройки проверки готовности /readyz.
+// CheckTimeout ограничивает проверку каждой зависимости, NonCritical - зависимости,
+// недоступность которых не снимает сервис с балансировки: postgres, replica, storage, broker, alerts
+type HealthConfig struct {
+	CheckTimeout time.Duration
+	NonCritical  []string
+}
+
+// DummyLoginConfig содержит настройки выдачи тестовых токенов через /dummyLogin.
+// В промышленном окружении эндпоинт не регистрируется независимо от Enabled.
+// ReadOnly разрешает тестовым токенам только чтение, по умолчанию включено в промышленном окружении
+type DummyLoginConfig struct {
+	Enabled      bool
+	AllowedRoles []string
+	ReadOnly     bool
+}
+
+// LoadConfig загружает конфигурацию из переменных окружения. Секреты можно передать файлом
+// (переменная с суффиксом _FILE) или ссылкой на Vault, ошибка возвращается, если секрет недоступен.
+// Неразбираемые значения заменяются значениями по умолчанию, о них сообщает Validate
+func LoadConfig() (*Config, error) {
+	env := &envReader{}
+	secrets := newSecretResolver(env)
+
+	cfg := &Config{
+		App: AppConfig{
+			Environment: env.get("APP_ENV", EnvDevelopment),
+		},
 		Server: ServerConfig{
-			Port:         getEnv("SERVER_PORT", "8080"),
-			ReadTimeout:  time.Second * 15,
-			WriteTimeout: time.Second * 15,
+			Port:          env.get("SERVER_PORT", "8080"),
+			GRPCPort:      env.get("GRPC_PORT", "9090"),
+			ReadTimeout:   time.Second * 15,
+			WriteTimeout:  time.Second * 15,
+			Gzip:          env.boolean("SERVER_GZIP_ENABLED", true),
+			MaxBodySize:   int64(env.integer("SERVER_MAX_BODY_SIZE", 1<<20)),
+			MaxUploadSize: int64(env.integer("SERVER_MAX_UPLOAD_SIZE", 128<<20)),
+			// По умолчанию прокси не доверяется: IP клиента - адрес соединения
+			TrustedProxies:  env.list("SERVER_TRUSTED_PROXIES", nil),
+			AccessLogFormat: env.choice("SERVER_ACCESS_LOG_FORMAT", AccessLogCommon, AccessLogCommon, AccessLogJSON),
+		},
+		API: APIConfig{
+			LegacyRoutes: env.boolean("API_LEGACY_ROUTES_ENABLED", true),
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "5432"),
-			User:     getEnv("DB_USER", "root"),
-			Password: getEnv("DB_PASSWORD", "password"),
-			DBName:   getEnv("DB_NAME", "pvz"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			Host:     env.get("DB_HOST", "localhost"),
+			Port:     env.get("DB_PORT", "5432"),
+			User:     env.get("DB_USER", "root"),
+			Password: secrets.get("DB_PASSWORD", "password"),
+			DBName:   env.get("DB_NAME", "pvz"),
+			SSLMode:  env.get("DB_SSLMODE", "disable"),
+
+			ReplicaHost: env.get("DB_REPLICA_HOST", ""),
+
+			SlowQueryThreshold: env.duration("DB_SLOW_QUERY_THRESHOLD", 500*time.Millisecond),
+			PrepareStatements:  env.boolean("DB_PREPARE_STATEMENTS", true),
+			RetryMaxAttempts:   env.integer("DB_RETRY_MAX_ATTEMPTS", 3),
+			RetryBaseDelay:     env.duration("DB_RETRY_BASE_DELAY", 50*time.Millisecond),
+			RetryMaxDelay:      env.duration("DB_RETRY_MAX_DELAY", time.Second),
+			RetryBudget:        env.float("DB_RETRY_BUDGET", 0.1),
+			ShadowMigrations:   env.list("DB_SHADOW_MIGRATIONS", nil),
+			QueryBudget:        env.integer("DB_QUERY_BUDGET", 20),
+			LockTimeout:        env.duration("DB_LOCK_TIMEOUT", 5*time.Second),
 		},
 		JWT: JWTConfig{
-			Secret:     getEnv("JWT_SECRET", "secret-key"),
-			ExpireTime: time.Hour * 24,
+			Secret:        secrets.get("JWT_SECRET", "secret-key"),
+			ExpireTime:    env.duration("JWT_EXPIRE_TIME", 24*time.Hour),
+			RememberMeTTL: env.duration("JWT_REMEMBER_ME_TTL", 30*24*time.Hour),
+			Issuer:        env.get("JWT_ISSUER", "pvz-service"),
+			Audience:      env.get("JWT_AUDIENCE", "pvz-api"),
+			Leeway:        env.duration("JWT_LEEWAY", 30*time.Second),
+			UserCacheTTL:  env.duration("AUTH_USER_CACHE_TTL", 30*time.Second),
+		},
+		Password: PasswordConfig{
+			Algorithm:     env.get("PASSWORD_HASH_ALGORITHM", "argon2id"),
+			BcryptCost:    env.integer("PASSWORD_BCRYPT_COST", 10),
+			Argon2Memory:  uint32(env.integer("PASSWORD_ARGON2_MEMORY", 64*1024)),
+			Argon2Time:    uint32(env.integer("PASSWORD_ARGON2_TIME", 1)),
+			Argon2Threads: uint8(env.integer("PASSWORD_ARGON2_THREADS", 4)),
 		},
+		Jobs: JobsConfig{
+			InactivePVZThreshold:   time.Duration(env.integer("INACTIVE_PVZ_DAYS", 30)) * 24 * time.Hour,
+			InactivePVZInterval:    env.duration("INACTIVE_PVZ_CHECK_INTERVAL", time.Hour),
+			ReceptionSLA:           env.duration("RECEPTION_SLA", 12*time.Hour),
+			ReceptionSLAInterval:   env.duration("RECEPTION_SLA_CHECK_INTERVAL", 5*time.Minute),
+			ConsistencyInterval:    env.duration("CONSISTENCY_CHECK_INTERVAL", 15*time.Minute),
+			UsageFlushInterval:     env.duration("USAGE_FLUSH_INTERVAL", time.Minute),
+			ArchiveAfter:           time.Duration(env.integer("ARCHIVE_RECEPTIONS_AFTER_DAYS", 0)) * 24 * time.Hour,
+			ArchiveInterval:        env.duration("ARCHIVE_CHECK_INTERVAL", time.Hour),
+			ReportScheduleInterval: env.duration("REPORT_SCHEDULE_CHECK_INTERVAL", 5*time.Minute),
+			ArchiveBatchSize:       env.integer("ARCHIVE_BATCH_SIZE", 500),
+		},
+		I18n: I18nConfig{
+			DefaultLocale: env.get("DEFAULT_LOCALE", "ru"),
+		},
+		Events: EventsConfig{
+			BufferSize:       env.integer("EVENTS_BUFFER_SIZE", 1000),
+			PollTimeout:      env.duration("EVENTS_POLL_TIMEOUT", 10*time.Second),
+			RelayInterval:    env.duration("OUTBOX_RELAY_INTERVAL", time.Second),
+			RelayBatchSize:   env.integer("OUTBOX_RELAY_BATCH_SIZE", 100),
+			RelayMaxAttempts: env.integer("OUTBOX_RELAY_MAX_ATTEMPTS", 5),
+			OutboxRetention:  env.duration("OUTBOX_RETENTION", 72*time.Hour),
+			BrokerURL:        env.get("EVENTS_BROKER_URL", ""),
+			BrokerTimeout:    env.duration("EVENTS_BROKER_TIMEOUT", 5*time.Second),
+			RetryInterval:    env.duration("DELIVERY_RETRY_INTERVAL", 30*time.Second),
+			RetryBaseDelay:   env.duration("DELIVERY_RETRY_BASE_DELAY", time.Minute),
+			RetryMaxAttempts: env.integer("DELIVERY_RETRY_MAX_ATTEMPTS", 10),
+		},
+		OTP: OTPConfig{
+			CodeTTL:       env.duration("OTP_CODE_TTL", 5*time.Minute),
+			RequestLimit:  env.integer("OTP_REQUEST_LIMIT", 3),
+			RequestWindow: env.duration("OTP_REQUEST_WINDOW", 15*time.Minute),
+			MaxAttempts:   env.integer("OTP_MAX_ATTEMPTS", 5),
+			SMSProvider:   env.get("SMS_PROVIDER", "log"),
+		},
+		TwoFactor: TwoFactorConfig{
+			Issuer:       env.get("TOTP_ISSUER", "PVZ Service"),
+			ChallengeTTL: env.duration("TOTP_CHALLENGE_TTL", 5*time.Minute),
+			MaxAttempts:  env.integer("TOTP_MAX_ATTEMPTS", 5),
+			BackupCodes:  env.integer("TOTP_BACKUP_CODES", 10),
+		},
+		Reset: PasswordResetConfig{
+			TokenTTL:      env.duration("PASSWORD_RESET_TOKEN_TTL", time.Hour),
+			RequestLimit:  env.integer("PASSWORD_RESET_REQUEST_LIMIT", 3),
+			RequestWindow: env.duration("PASSWORD_RESET_REQUEST_WINDOW", time.Hour),
+		},
+		Mail: MailConfig{
+			Provider:     env.get("MAIL_PROVIDER", "log"),
+			From:         env.get("MAIL_FROM", "noreply@pvz-service.local"),
+			SMTPAddr:     env.get("SMTP_ADDR", ""),
+			SMTPUser:     env.get("SMTP_USER", ""),
+			SMTPPassword: secrets.get("SMTP_PASSWORD", ""),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:           env.boolean("RATE_LIMIT_ENABLED", true),
+			RequestsPerMinute: env.integer("RATE_LIMIT_RPM", 600),
+			Burst:             env.integer("RATE_LIMIT_BURST", 100),
+			OverridesTTL:      env.duration("RATE_LIMIT_OVERRIDES_TTL", time.Minute),
+		},
+		Features: FeatureFlagsConfig{
+			Enabled:  env.list("FEATURE_FLAGS", nil),
+			CacheTTL: env.duration("FEATURE_FLAGS_CACHE_TTL", 30*time.Second),
+		},
+		Alerts: AlertsConfig{
+			WebhookURL:     env.get("ALERT_WEBHOOK_URL", ""),
+			WebhookTimeout: env.duration("ALERT_WEBHOOK_TIMEOUT", 5*time.Second),
+		},
+		LoginAudit: LoginAuditConfig{
+			FailureThreshold: env.integer("LOGIN_FAILURE_THRESHOLD", 5),
+			FailureWindow:    env.duration("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+			CountryHeader:    env.get("LOGIN_COUNTRY_HEADER", ""),
+		},
+		Tracing: TracingConfig{
+			Enabled:     env.boolean("TRACING_ENABLED", false),
+			ServiceName: env.get("TRACING_SERVICE_NAME", "pvz-service"),
+			Endpoint:    env.get("TRACING_OTLP_ENDPOINT", "localhost:4318"),
+			Insecure:    env.boolean("TRACING_OTLP_INSECURE", true),
+			SampleRatio: env.float("TRACING_SAMPLE_RATIO", 1),
+		},
+		Metrics: MetricsConfig{
+			Enabled:  env.boolean("METRICS_ENABLED", false),
+			Endpoint: env.get("METRICS_OTLP_ENDPOINT", "localhost:4318"),
+			Insecure: env.boolean("METRICS_OTLP_INSECURE", true),
+			Interval: env.duration("METRICS_EXPORT_INTERVAL", 15*time.Second),
+		},
+		Sentry: SentryConfig{
+			DSN: secrets.get("SENTRY_DSN", ""),
+		},
+		Storage: StorageConfig{
+			Backend:     env.get("STORAGE_BACKEND", ""),
+			URLTTL:      env.duration("STORAGE_URL_TTL", 15*time.Minute),
+			S3Endpoint:  env.get("S3_ENDPOINT", "localhost:9000"),
+			S3Region:    env.get("S3_REGION", ""),
+			S3Bucket:    env.get("S3_BUCKET", "pvz-attachments"),
+			S3AccessKey: secrets.get("S3_ACCESS_KEY", ""),
+			S3SecretKey: secrets.get("S3_SECRET_KEY", ""),
+			S3UseSSL:    env.boolean("S3_USE_SSL", false),
+			LocalDir:    env.get("STORAGE_LOCAL_DIR", "data/attachments"),
+			LocalSecret: secrets.get("STORAGE_LOCAL_SECRET", ""),
+			PublicURL:   env.get("STORAGE_PUBLIC_URL", "http://localhost:8080"),
+		},
+		DummyLogin: DummyLoginConfig{
+			Enabled:      env.boolean("DUMMY_LOGIN_ENABLED", true),
+			AllowedRoles: env.list("DUMMY_LOGIN_ROLES", []string{"employee", "moderator"}),
+		},
+		Health: HealthConfig{
+			CheckTimeout: env.duration("READINESS_CHECK_TIMEOUT", 2*time.Second),
+			NonCritical:  env.list("READINESS_NON_CRITICAL", []string{"replica", "storage", "broker", "alerts"}),
+		},
+		PublicLink: PublicLinkConfig{
+			Secret: secrets.get("PUBLIC_LINK_SECRET", ""),
+		},
+	}
+
+	// В промышленном окружении Gin по умолчанию работает в release, чтобы не выводить отладочные сообщения
+	ginMode := "debug"
+	if cfg.App.IsProduction() {
+		ginMode = "release"
+	}
+	cfg.Server.GinMode = env.choice("SERVER_GIN_MODE", ginMode, "debug", "release", "test")
+
+	// Тестовые токены, выданные в других окружениях, не должны изменять данные в промышленном
+	cfg.DummyLogin.ReadOnly = env.boolean("DUMMY_LOGIN_READ_ONLY", cfg.App.IsProduction())
+
+	// Параметры реплики по умолчанию совпадают с основным сервером
+	cfg.Database.ReplicaPort = env.get("DB_REPLICA_PORT", cfg.Database.Port)
+	cfg.Database.ReplicaUser = env.get("DB_REPLICA_USER", cfg.Database.User)
+	cfg.Database.ReplicaPassword = secrets.get("DB_REPLICA_PASSWORD", cfg.Database.Password)
+
+	// Срок действия токена роли по умолчанию совпадает с общим
+	cfg.JWT.RoleExpireTime = map[string]time.Duration{
+		"employee":  env.duration("JWT_EMPLOYEE_EXPIRE_TIME", cfg.JWT.ExpireTime),
+		"moderator": env.duration("JWT_MODERATOR_EXPIRE_TIME", cfg.JWT.ExpireTime),
 	}
+
+	if err := secrets.err(); err != nil {
+		return nil, fmt.Errorf("failed to load secrets: %w", err)
+	}
+	cfg.invalid = env.invalid
+
+	return cfg, nil
+}
+
+// envReader читает настройки из переменных окружения. Значение, которое не удалось разобрать,
+// заменяется значением по умолчанию и запоминается, чтобы Validate сообщил о нем
+type envReader struct {
+	invalid []FieldError
+}
+
+// reject запоминает неразбираемое значение переменной key и пишет его в лог
+func (r *envReader) reject(key, value, expected string, defaultValue any) {
+	log.Printf("Invalid %s in %s=%q, using default %v", expected, key, value, defaultValue)
+	r.invalid = append(r.invalid, FieldError{Env: key, Problem: fmt.Sprintf("invalid %s %q", expected, value)})
 }
 
-// getEnv получает значение переменной окружения или возвращает значение по умолчанию
-func getEnv(key, defaultValue string) string {
+// get получает значение переменной окружения или возвращает значение по умолчанию
+func (r *envReader) get(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
 	}
 	return defaultValue
 }
+
+// duration получает длительность из переменной окружения или возвращает значение по умолчанию
+func (r *envReader) duration(key string, defaultValue time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		r.reject(key, value, "duration", defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+// integer получает целое число из переменной окружения или возвращает значение по умолчанию
+func (r *envReader) integer(key string, defaultValue int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		r.reject(key, value, "integer", defaultValue)
+		return defaultValue
+	}
+	return number
+}
+
+// boolean получает логическое значение из переменной окружения или возвращает значение по умолчанию
+func (r *envReader) boolean(key string, defaultValue bool) bool {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		r.reject(key, value, "boolean", defaultValue)
+		return defaultValue
+	}
+	return flag
+}
+
+// float получает дробное число из переменной окружения или возвращает значение по умолчанию
+func (r *envReader) float(key string, defaultValue float64) float64 {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		r.reject(key, value, "number", defaultValue)
+		return defaultValue
+	}
+	return number
+}
+
+// list получает список значений через запятую из переменной окружения или возвращает значение по умолчанию
+func (r *envReader) list(key string, defaultValue []string) []string {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// choice получает значение переменной окружения из списка allowed
+// или возвращает значение по умолчанию, если переменная не задана или содержит другое значение
+func (r *envReader) choice(key, defaultValue string, allowed ...string) string {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	for _, choice := range allowed {
+		if value == choice {
+			return value
+		}
+	}
+	r.reject(key, value, "value (expected one of "+strings.Join(allowed, ", ")+")", defaultValue)
+	return defaultValue
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfigServer(t *testing.T) {
+	t.Setenv("APP_ENV", EnvProduction)
+	t.Setenv("SERVER_TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.10")
+	t.Setenv("SERVER_ACCESS_LOG_FORMAT", "xml")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "release", cfg.Server.GinMode)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.10"}, cfg.Server.TrustedProxies)
+	assert.Equal(t, AccessLogCommon, cfg.Server.AccessLogFormat)
+
+	t.Setenv("SERVER_GIN_MODE", "debug")
+	cfg, err = LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "debug", cfg.Server.GinMode)
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.NoError(t, cfg.Validate())
+
+	t.Setenv("DB_HOST", "")
+	t.Setenv("JWT_EXPIRE_TIME", "1day")
+	t.Setenv("RECEPTION_SLA_CHECK_INTERVAL", "0s")
+	t.Setenv("GRPC_PORT", "grpc")
+	t.Setenv("RATE_LIMIT_ENABLED", "yes please")
+
+	cfg, err = LoadConfig()
+	require.NoError(t, err)
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, cfg.Validate(), &validationErr)
+	assert.ElementsMatch(t, []FieldError{
+		{Env: "DB_HOST", Problem: "must be set"},
+		{Env: "JWT_EXPIRE_TIME", Problem: `invalid duration "1day"`},
+		{Env: "RECEPTION_SLA_CHECK_INTERVAL", Problem: "must be a positive duration, e.g. 30s or 5m"},
+		{Env: "GRPC_PORT", Problem: `invalid port "grpc"`},
+		{Env: "RATE_LIMIT_ENABLED", Problem: `invalid boolean "yes please"`},
+	}, validationErr.Fields)
+	assert.Contains(t, validationErr.Error(), "invalid configuration (5 problems):\n  ")
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// SecretProvider получает значение секрета по ссылке ref из переменной окружения
+type SecretProvider interface {
+	Secret(ctx context.Context, ref string) (string, error)
+}
+
+// FileProvider читает секрет из файла, например смонтированного секрета Docker или Kubernetes.
+// ref - путь к файлу, завершающий перевод строки отбрасывается
+type FileProvider struct{}
+
+// Secret читает секрет из файла ref
+func (FileProvider) Secret(ctx context.Context, ref string) (string, error) {
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// VaultProvider читает секрет из HashiCorp Vault по HTTP API.
+// ref имеет вид path#field, например secret/data/pvz#db_password: поддерживаются хранилища KV v1 и v2
+type VaultProvider struct {
+	Addr   string
+	Token  string
+	client *http.Client
+}
+
+// NewVaultProvider создает VaultProvider для сервера addr с токеном token
+func NewVaultProvider(addr, token string, timeout time.Duration) *VaultProvider {
+	return &VaultProvider{
+		Addr:   strings.TrimSuffix(addr, "/"),
+		Token:  token,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Secret читает поле секрета из Vault
+func (p *VaultProvider) Secret(ctx context.Context, ref string) (string, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || field == "" {
+		return "", fmt.Errorf("vault secret reference %q must look like path#field", ref)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.Addr+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.Token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to read vault secret %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to read vault secret %s: status %d", path, resp.StatusCode)
+	}
+
+	// В KV v2 поля секрета вложены в data.data, в KV v1 - лежат прямо в data
+	var body struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode vault secret %s: %w", path, err)
+	}
+	fields := body.Data
+	if nested, ok := body.Data["data"]; ok {
+		if err := json.Unmarshal(nested, &fields); err != nil {
+			return "", fmt.Errorf("failed to decode vault secret %s: %w", path, err)
+		}
+	}
+
+	var value string
+	raw, ok := fields[field]
+	if !ok || json.Unmarshal(raw, &value) != nil {
+		return "", fmt.Errorf("vault secret %s has no string field %s", path, field)
+	}
+	return value, nil
+}
+
+// secretResolver получает секреты конфигурации: из файла по переменной KEY_FILE,
+// от провайдера по ссылке вида scheme:ref в переменной KEY или из самой переменной KEY.
+// Ошибки накапливаются, чтобы сообщить обо всех недоступных секретах сразу
+type secretResolver struct {
+	env       *envReader
+	providers map[string]SecretProvider
+	timeout   time.Duration
+	errs      []error
+}
+
+// newSecretResolver создает secretResolver со схемами file: и vault:.
+// Vault доступен, если задан VAULT_ADDR; токен берется из VAULT_TOKEN или файла VAULT_TOKEN_FILE
+func newSecretResolver(env *envReader) *secretResolver {
+	r := &secretResolver{
+		env:       env,
+		providers: map[string]SecretProvider{"file": FileProvider{}},
+		timeout:   env.duration("SECRETS_TIMEOUT", 5*time.Second),
+	}
+
+	if addr := env.get("VAULT_ADDR", ""); addr != "" {
+		token := r.get("VAULT_TOKEN", "")
+		r.providers["vault"] = NewVaultProvider(addr, token, r.timeout)
+	}
+
+	return r
+}
+
+// get возвращает секрет из переменной key или значение по умолчанию
+func (r *secretResolver) get(key, defaultValue string) string {
+	if path, ok := os.LookupEnv(key + "_FILE"); ok {
+		return r.resolve(key+"_FILE", "file", path)
+	}
+
+	value := r.env.get(key, defaultValue)
+	if scheme, ref, ok := strings.Cut(value, ":"); ok {
+		if _, known := r.providers[scheme]; known {
+			return r.resolve(key, scheme, ref)
+		}
+		if scheme == "vault" {
+			r.errs = append(r.errs, fmt.Errorf("%s refers to vault, but VAULT_ADDR is not set", key))
+			return ""
+		}
+	}
+	return value
+}
+
+// resolve получает секрет от провайдера scheme. Текст ошибки не содержит значения секрета
+func (r *secretResolver) resolve(key, scheme, ref string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	value, err := r.providers[scheme].Secret(ctx, ref)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s: %w", key, err))
+		return ""
+	}
+	return value
+}
+
+// err возвращает все ошибки получения секретов
+func (r *secretResolver) err() error {
+	return errors.Join(r.errs...)
+}
+
+// redacted заменяет непустой секрет маской для вывода конфигурации в лог
+const redacted = "[REDACTED]"
+
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redacted
+}
+
+// Redacted возвращает копию конфигурации, в которой секреты заменены маской.
+// Конфигурация выводится в лог только в таком виде
+func (c Config) Redacted() Config {
+	c.Database.Password = redact(c.Database.Password)
+	c.Database.ReplicaPassword = redact(c.Database.ReplicaPassword)
+	c.JWT.Secret = redact(c.JWT.Secret)
+	c.Mail.SMTPPassword = redact(c.Mail.SMTPPassword)
+	c.Storage.S3AccessKey = redact(c.Storage.S3AccessKey)
+	c.Storage.S3SecretKey = redact(c.Storage.S3SecretKey)
+	c.Storage.LocalSecret = redact(c.Storage.LocalSecret)
+	c.PublicLink.Secret = redact(c.PublicLink.Secret)
+	c.Sentry.DSN = redact(c.Sentry.DSN)
+	c.Alerts.WebhookURL = redact(c.Alerts.WebhookURL)
+	c.Events.BrokerURL = redact(c.Events.BrokerURL)
+	return c
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfigSecretFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db_password")
+	require.NoError(t, os.WriteFile(path, []byte("from-file\n"), 0o600))
+	t.Setenv("DB_PASSWORD_FILE", path)
+	t.Setenv("DB_PASSWORD", "from-env")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "from-file", cfg.Database.Password)
+	assert.Equal(t, "from-file", cfg.Database.ReplicaPassword)
+
+	t.Setenv("DB_PASSWORD_FILE", filepath.Join(t.TempDir(), "missing"))
+	_, err = LoadConfig()
+	assert.ErrorContains(t, err, "DB_PASSWORD_FILE")
+}
+
+func TestLoadConfigSecretVault(t *testing.T) {
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" || r.URL.Path != "/v1/secret/data/pvz" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"data":{"data":{"jwt_secret":"from-vault"},"metadata":{"version":1}}}`))
+	}))
+	defer vault.Close()
+
+	t.Setenv("VAULT_ADDR", vault.URL)
+	t.Setenv("VAULT_TOKEN", "root")
+	t.Setenv("JWT_SECRET", "vault:secret/data/pvz#jwt_secret")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "from-vault", cfg.JWT.Secret)
+
+	t.Setenv("JWT_SECRET", "vault:secret/data/pvz#missing")
+	_, err = LoadConfig()
+	assert.ErrorContains(t, err, "JWT_SECRET")
+}
+
+func TestConfigRedacted(t *testing.T) {
+	t.Setenv("JWT_SECRET", "jwt")
+	t.Setenv("S3_SECRET_KEY", "")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+
+	redactedCfg := cfg.Redacted()
+	assert.Equal(t, redacted, redactedCfg.JWT.Secret)
+	assert.Equal(t, redacted, redactedCfg.Database.Password)
+	assert.Empty(t, redactedCfg.Storage.S3SecretKey)
+	assert.Equal(t, "jwt", cfg.JWT.Secret)
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldError описывает ошибку одной настройки: переменную окружения и что с ней не так
+type FieldError struct {
+	Env     string
+	Problem string
+}
+
+func (e FieldError) Error() string {
+	return e.Env + ": " + e.Problem
+}
+
+// ValidationError содержит все ошибки конфигурации, чтобы исправить их за один запуск
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid configuration (%d problems):", len(e.Fields))
+	for _, field := range e.Fields {
+		b.WriteString("\n  " + field.Error())
+	}
+	return b.String()
+}
+
+// Validate проверяет конфигурацию до запуска сервиса: неразбираемые значения переменных окружения,
+// незаданные обязательные настройки и значения, с которыми сервис не сможет работать.
+// Возвращает *ValidationError со всеми найденными ошибками
+func (c *Config) Validate() error {
+	v := &validator{fields: append([]FieldError(nil), c.invalid...)}
+
+	v.port("SERVER_PORT", c.Server.Port)
+	if c.Server.GRPCPort != "" {
+		v.port("GRPC_PORT", c.Server.GRPCPort)
+	}
+	v.positive("SERVER_MAX_BODY_SIZE", int(c.Server.MaxBodySize))
+	v.positive("SERVER_MAX_UPLOAD_SIZE", int(c.Server.MaxUploadSize))
+
+	v.required("DB_HOST", c.Database.Host)
+	v.port("DB_PORT", c.Database.Port)
+	v.required("DB_USER", c.Database.User)
+	v.required("DB_NAME", c.Database.DBName)
+	v.oneOf("DB_SSLMODE", c.Database.SSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full")
+	if c.Database.ReplicaHost != "" {
+		v.port("DB_REPLICA_PORT", c.Database.ReplicaPort)
+	}
+	v.positive("DB_RETRY_MAX_ATTEMPTS", c.Database.RetryMaxAttempts)
+	v.positiveDuration("DB_LOCK_TIMEOUT", c.Database.LockTimeout)
+	if c.Database.RetryBudget < 0 {
+		v.add("DB_RETRY_BUDGET", "must not be negative")
+	}
+
+	v.required("JWT_SECRET", c.JWT.Secret)
+	v.positiveDuration("JWT_EXPIRE_TIME", c.JWT.ExpireTime)
+	v.positiveDuration("JWT_EMPLOYEE_EXPIRE_TIME", c.JWT.RoleExpireTime["employee"])
+	v.positiveDuration("JWT_MODERATOR_EXPIRE_TIME", c.JWT.RoleExpireTime["moderator"])
+
+	v.oneOf("PASSWORD_HASH_ALGORITHM", c.Password.Algorithm, "bcrypt", "argon2id")
+
+	// Нулевой интервал фонового задания приводит к панике при создании таймера
+	v.positiveDuration("INACTIVE_PVZ_CHECK_INTERVAL", c.Jobs.InactivePVZInterval)
+	v.positiveDuration("RECEPTION_SLA_CHECK_INTERVAL", c.Jobs.ReceptionSLAInterval)
+	v.positiveDuration("CONSISTENCY_CHECK_INTERVAL", c.Jobs.ConsistencyInterval)
+	v.positiveDuration("USAGE_FLUSH_INTERVAL", c.Jobs.UsageFlushInterval)
+	v.positiveDuration("REPORT_SCHEDULE_CHECK_INTERVAL", c.Jobs.ReportScheduleInterval)
+	if c.Jobs.ArchiveAfter < 0 {
+		v.add("ARCHIVE_RECEPTIONS_AFTER_DAYS", "must not be negative")
+	}
+	if c.Jobs.ArchiveAfter > 0 {
+		v.positiveDuration("ARCHIVE_CHECK_INTERVAL", c.Jobs.ArchiveInterval)
+		v.positive("ARCHIVE_BATCH_SIZE", c.Jobs.ArchiveBatchSize)
+	}
+	v.positiveDuration("OUTBOX_RELAY_INTERVAL", c.Events.RelayInterval)
+	v.positiveDuration("DELIVERY_RETRY_INTERVAL", c.Events.RetryInterval)
+	v.positive("OUTBOX_RELAY_BATCH_SIZE", c.Events.RelayBatchSize)
+
+	if c.LoginAudit.FailureThreshold < 0 {
+		v.add("LOGIN_FAILURE_THRESHOLD", "must not be negative")
+	}
+	if c.LoginAudit.FailureThreshold > 0 {
+		v.positiveDuration("LOGIN_FAILURE_WINDOW", c.LoginAudit.FailureWindow)
+	}
+
+	v.oneOf("MAIL_PROVIDER", c.Mail.Provider, "", "log", "smtp")
+	if c.Mail.Provider == "smtp" {
+		v.required("SMTP_ADDR", c.Mail.SMTPAddr)
+	}
+	v.oneOf("SMS_PROVIDER", c.OTP.SMSProvider, "", "log")
+	v.oneOf("STORAGE_BACKEND", c.Storage.Backend, "", "s3", "local")
+	if c.Storage.Backend == "local" {
+		v.required("STORAGE_LOCAL_SECRET", c.Storage.LocalSecret)
+	}
+
+	if c.RateLimit.Enabled {
+		v.positive("RATE_LIMIT_RPM", c.RateLimit.RequestsPerMinute)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		v.add("TRACING_SAMPLE_RATIO", "must be between 0 and 1")
+	}
+	if c.Metrics.Enabled {
+		v.positiveDuration("METRICS_EXPORT_INTERVAL", c.Metrics.Interval)
+	}
+
+	if len(v.fields) > 0 {
+		return &ValidationError{Fields: v.fields}
+	}
+	return nil
+}
+
+// validator накапливает ошибки проверки конфигурации
+type validator struct {
+	fields []FieldError
+}
+
+func (v *validator) add(env, problem string) {
+	// Неразбираемое значение уже описано, вторая ошибка по той же переменной только запутает
+	for _, field := range v.fields {
+		if field.Env == env {
+			return
+		}
+	}
+	v.fields = append(v.fields, FieldError{Env: env, Problem: problem})
+}
+
+func (v *validator) required(env, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.add(env, "must be set")
+	}
+}
+
+func (v *validator) port(env, value string) {
+	if value == "" {
+		v.add(env, "must be set")
+		return
+	}
+	if port, err := strconv.Atoi(value); err != nil || port < 1 || port > 65535 {
+		v.add(env, fmt.Sprintf("invalid port %q", value))
+	}
+}
+
+func (v *validator) positive(env string, value int) {
+	if value <= 0 {
+		v.add(env, "must be positive")
+	}
+}
+
+func (v *validator) positiveDuration(env string, value time.Duration) {
+	if value <= 0 {
+		v.add(env, "must be a positive duration, e.g. 30s or 5m")
+	}
+}
+
+func (v *validator) oneOf(env, value string, allowed ...string) {
+	for _, choice := range allowed {
+		if value == choice {
+			return
+		}
+	}
+	v.add(env, fmt.Sprintf("unknown value %q", value))
+}
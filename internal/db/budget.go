@@ -0,0 +1,33 @@
+package db
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// QueryCounter считает SQL-запросы, выполненные с контекстом одного HTTP-запроса.
+// Рост числа запросов на один HTTP-запрос обычно означает N+1: запрос в цикле по строкам списка
+type QueryCounter struct {
+	count atomic.Int64
+}
+
+// queryCounterKey - ключ контекста, под которым хранится QueryCounter
+type queryCounterKey struct{}
+
+// WithQueryCounter возвращает контекст, запросы Database с которым учитываются в новом счетчике
+func WithQueryCounter(ctx context.Context) (context.Context, *QueryCounter) {
+	counter := &QueryCounter{}
+	return context.WithValue(ctx, queryCounterKey{}, counter), counter
+}
+
+// Count возвращает число выполненных запросов, включая повторы и запросы к реплике
+func (c *QueryCounter) Count() int {
+	return int(c.count.Load())
+}
+
+// countQuery учитывает запрос в счетчике из контекста, если он есть
+func countQuery(ctx context.Context) {
+	if counter, ok := ctx.Value(queryCounterKey{}).(*QueryCounter); ok {
+		counter.count.Add(1)
+	}
+}
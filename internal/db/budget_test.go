@@ -0,0 +1,28 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatabase_QueryCounter(t *testing.T) {
+	database, primaryMock, _ := setupDatabaseTest(t)
+
+	primaryMock.ExpectQuery(`SELECT 1`).WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+	primaryMock.ExpectExec(`UPDATE pvz`).WillReturnResult(sqlmock.NewResult(0, 1))
+	primaryMock.ExpectQuery(`SELECT 1`).WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+
+	ctx, counter := WithQueryCounter(context.Background())
+	var n int
+	assert.NoError(t, database.GetContext(ctx, &n, "SELECT 1"))
+	_, err := database.ExecContext(ctx, "UPDATE pvz SET city = city")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, counter.Count())
+
+	// Запросы без счетчика в контексте не учитываются
+	assert.NoError(t, database.GetContext(context.Background(), &n, "SELECT 1"))
+	assert.Equal(t, 2, counter.Count())
+}
@@ -1,8 +1,13 @@
 package db
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
+	"time"
 
 	"pvz-service/internal/config"
 
@@ -10,17 +15,75 @@ import (
 	_ "github.com/lib/pq"
 )
 
-// Database представляет соединение с базой данных
+// Database представляет соединение с базой данных: основной пул для записи
+// и необязательный пул реплики для запросов только на чтение
 type Database struct {
 	*sqlx.DB
+	replica *sqlx.DB
+
+	// slowQueryThreshold - длительность, начиная с которой запрос пишется в лог, 0 отключает журнал
+	slowQueryThreshold time.Duration
+
+	// prepareStatements включает подготовку запросов Prepared*, подготовленные запросы кешируются по тексту
+	prepareStatements bool
+	stmtsMu           sync.Mutex
+	stmts             map[string]*sqlx.Stmt
+
+	// shadowModes - режимы переходного периода миграций схемы по имени миграции
+	shadowModes map[string]ShadowMode
+
+	// retrier повторяет запросы при временных ошибках, nil - повторы отключены
+	retrier *retrier
 }
 
 // NewDatabase создает новое соединение с базой данных
 func NewDatabase(config *config.DatabaseConfig) (*Database, error) {
-	// Формируем строку подключения
+	shadowModes, err := ParseShadowModes(config.ShadowMigrations)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := connect(config.Host, config.Port, config.User, config.Password, config.DBName, config.SSLMode)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Println("Connected to database")
+
+	database := &Database{
+		DB:                 db,
+		slowQueryThreshold: config.SlowQueryThreshold,
+		prepareStatements:  config.PrepareStatements,
+		shadowModes:        shadowModes,
+		retrier: newRetrier(RetryPolicy{
+			MaxAttempts: config.RetryMaxAttempts,
+			BaseDelay:   config.RetryBaseDelay,
+			MaxDelay:    config.RetryMaxDelay,
+			Budget:      config.RetryBudget,
+		}),
+	}
+
+	// Реплика необязательна: при недоступности чтение идет с основного сервера
+	if config.ReplicaHost != "" {
+		replica, err := connect(config.ReplicaHost, config.ReplicaPort, config.ReplicaUser, config.ReplicaPassword, config.DBName, config.SSLMode)
+		if err != nil {
+			log.Printf("Read replica is unavailable, reads will use primary: %v", err)
+		} else {
+			log.Println("Connected to read replica")
+			database.replica = replica
+		}
+	}
+
+	return database, nil
+}
+
+// connect устанавливает и проверяет соединение с сервером PostgreSQL
+func connect(host, port, user, password, dbName, sslMode string) (*sqlx.DB, error) {
+	// Формируем строку подключения. Сеанс работает в UTC, чтобы отметки времени
+	// читались и сравнивались в UTC независимо от настроек сервера
 	connStr := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		config.Host, config.Port, config.User, config.Password, config.DBName, config.SSLMode,
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s timezone=UTC",
+		host, port, user, password, dbName, sslMode,
 	)
 
 	// Устанавливаем соединение
@@ -34,7 +97,148 @@ func NewDatabase(config *config.DatabaseConfig) (*Database, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	log.Println("Connected to database")
+	return db, nil
+}
+
+// Close закрывает подготовленные запросы и соединения с основным сервером и репликой
+func (d *Database) Close() error {
+	d.closeStmts()
+	if d.replica != nil {
+		if err := d.replica.Close(); err != nil {
+			log.Printf("Failed to close read replica: %v", err)
+		}
+	}
+	return d.DB.Close()
+}
+
+// HasReplica сообщает, подключена ли реплика для чтения
+func (d *Database) HasReplica() bool {
+	return d.replica != nil
+}
+
+// PingReplica проверяет соединение с репликой
+func (d *Database) PingReplica(ctx context.Context) error {
+	if d.replica == nil {
+		return errors.New("read replica is not connected")
+	}
+	return d.replica.PingContext(ctx)
+}
+
+// ReadSelectContext выполняет запрос на чтение списка на реплике,
+// при ошибке реплики повторяет его на основном сервере. Временные ошибки повторяются по RetryPolicy.
+// Внутри транзакции запрос выполняется в ней, чтобы видеть незафиксированные изменения
+func (d *Database) ReadSelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return d.Retry(ctx, func(ctx context.Context) error {
+		if _, inTx := txFromContext(ctx); d.replica != nil && !inTx {
+			replicaCtx, finish := d.startQuery(ctx, "select", query, args, true)
+			err := d.replica.SelectContext(replicaCtx, dest, query, args...)
+			finish(err)
+			if !shouldFallback(ctx, err) {
+				return err
+			}
+			log.Printf("Read replica query failed, falling back to primary: %v", err)
+		}
+		return d.SelectContext(ctx, dest, query, args...)
+	})
+}
+
+// ReadGetContext выполняет запрос на чтение одной строки на реплике,
+// при ошибке реплики повторяет его на основном сервере
+func (d *Database) ReadGetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return d.Retry(ctx, func(ctx context.Context) error {
+		if _, inTx := txFromContext(ctx); d.replica != nil && !inTx {
+			replicaCtx, finish := d.startQuery(ctx, "get", query, args, true)
+			err := d.replica.GetContext(replicaCtx, dest, query, args...)
+			finish(err)
+			if !shouldFallback(ctx, err) {
+				return err
+			}
+			log.Printf("Read replica query failed, falling back to primary: %v", err)
+		}
+		return d.GetContext(ctx, dest, query, args...)
+	})
+}
+
+// ReadQueryxContext выполняет запрос на чтение на реплике и возвращает курсор по строкам.
+// На основной сервер запрос повторяется, только если реплика не выполнила его:
+// ошибку при чтении уже полученных строк повторить нельзя
+func (d *Database) ReadQueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	var rows *sqlx.Rows
+	err := d.Retry(ctx, func(ctx context.Context) error {
+		var err error
+		if _, inTx := txFromContext(ctx); d.replica != nil && !inTx {
+			replicaCtx, finish := d.startQuery(ctx, "query", query, args, true)
+			rows, err = d.replica.QueryxContext(replicaCtx, query, args...)
+			finish(err)
+			if !shouldFallback(ctx, err) {
+				return err
+			}
+			log.Printf("Read replica query failed, falling back to primary: %v", err)
+		}
+		rows, err = d.QueryxContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// shouldFallback сообщает, нужно ли повторить запрос на основном сервере:
+// отсутствие строк и отмена запроса клиентом не считаются сбоем реплики
+func shouldFallback(ctx context.Context, err error) bool {
+	if err == nil || errors.Is(err, sql.ErrNoRows) {
+		return false
+	}
+	return ctx.Err() == nil
+}
+
+// WithTx выполняет fn в транзакции: при ошибке транзакция откатывается, иначе фиксируется.
+// Если в контексте уже открыта транзакция InTx, fn выполняется в ней
+func (d *Database) WithTx(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	if tx, ok := txFromContext(ctx); ok {
+		return fn(tx)
+	}
+
+	tx, err := d.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			log.Printf("Failed to rollback transaction: %v", rollbackErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Transactor выполняет группу запросов в одной транзакции
+type Transactor interface {
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// txKey - ключ контекста, под которым хранится открытая транзакция
+type txKey struct{}
+
+// txFromContext возвращает транзакцию, открытую InTx
+func txFromContext(ctx context.Context) (*sqlx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sqlx.Tx)
+	return tx, ok
+}
+
+// InTx выполняет fn в транзакции, передавая ее через контекст: запросы Database
+// с этим контекстом, в том числе из разных Queries, идут в одну транзакцию.
+// Вложенный вызов InTx использует уже открытую транзакцию
+func (d *Database) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := txFromContext(ctx); ok {
+		return fn(ctx)
+	}
 
-	return &Database{db}, nil
+	return d.WithTx(ctx, func(tx *sqlx.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
 }
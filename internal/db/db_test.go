@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupDatabaseTest(t *testing.T) (*Database, sqlmock.Sqlmock, sqlmock.Sqlmock) {
+	primaryDB, primaryMock, _ := sqlmock.New()
+	replicaDB, replicaMock, _ := sqlmock.New()
+	t.Cleanup(func() {
+		primaryDB.Close()
+		replicaDB.Close()
+	})
+
+	return &Database{
+		DB:      sqlx.NewDb(primaryDB, "sqlmock"),
+		replica: sqlx.NewDb(replicaDB, "sqlmock"),
+	}, primaryMock, replicaMock
+}
+
+func TestDatabase_ReadSelectContext(t *testing.T) {
+	t.Run("Чтение с реплики", func(t *testing.T) {
+		database, primaryMock, replicaMock := setupDatabaseTest(t)
+
+		replicaMock.ExpectQuery(`SELECT id FROM pvz`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1").AddRow("2"))
+
+		var ids []string
+		err := database.ReadSelectContext(context.Background(), &ids, "SELECT id FROM pvz")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1", "2"}, ids)
+		assert.NoError(t, replicaMock.ExpectationsWereMet())
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+	})
+
+	t.Run("Переключение на основной сервер при ошибке реплики", func(t *testing.T) {
+		database, primaryMock, replicaMock := setupDatabaseTest(t)
+
+		replicaMock.ExpectQuery(`SELECT id FROM pvz`).
+			WillReturnError(errors.New("connection refused"))
+		primaryMock.ExpectQuery(`SELECT id FROM pvz`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+
+		var ids []string
+		err := database.ReadSelectContext(context.Background(), &ids, "SELECT id FROM pvz")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1"}, ids)
+		assert.NoError(t, replicaMock.ExpectationsWereMet())
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+	})
+
+	t.Run("Без реплики запрос идет на основной сервер", func(t *testing.T) {
+		primaryDB, primaryMock, _ := sqlmock.New()
+		defer primaryDB.Close()
+		database := &Database{DB: sqlx.NewDb(primaryDB, "sqlmock")}
+
+		primaryMock.ExpectQuery(`SELECT id FROM pvz`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+
+		var ids []string
+		err := database.ReadSelectContext(context.Background(), &ids, "SELECT id FROM pvz")
+
+		assert.NoError(t, err)
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+	})
+}
+
+func TestDatabase_ReadGetContext(t *testing.T) {
+	t.Run("Отсутствие строк не приводит к повтору на основном сервере", func(t *testing.T) {
+		database, primaryMock, replicaMock := setupDatabaseTest(t)
+
+		replicaMock.ExpectQuery(`SELECT COUNT`).
+			WillReturnError(sql.ErrNoRows)
+
+		var total int
+		err := database.ReadGetContext(context.Background(), &total, "SELECT COUNT(*) FROM pvz")
+
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, replicaMock.ExpectationsWereMet())
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+	})
+
+	t.Run("Отмененный запрос не повторяется", func(t *testing.T) {
+		database, primaryMock, replicaMock := setupDatabaseTest(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var total int
+		err := database.ReadGetContext(ctx, &total, "SELECT COUNT(*) FROM pvz")
+
+		assert.Error(t, err)
+		assert.NoError(t, replicaMock.ExpectationsWereMet())
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+	})
+}
+
+func TestDatabase_InTx(t *testing.T) {
+	t.Run("Запросы из контекста идут в одну транзакцию", func(t *testing.T) {
+		database, primaryMock, replicaMock := setupDatabaseTest(t)
+
+		primaryMock.ExpectBegin()
+		primaryMock.ExpectExec(`INSERT INTO reception`).WillReturnResult(sqlmock.NewResult(0, 1))
+		// Чтение внутри транзакции не уходит на реплику
+		primaryMock.ExpectQuery(`SELECT id FROM reception`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+		primaryMock.ExpectExec(`INSERT INTO event_outbox`).WillReturnResult(sqlmock.NewResult(0, 1))
+		primaryMock.ExpectCommit()
+
+		err := database.InTx(context.Background(), func(ctx context.Context) error {
+			if _, err := database.ExecContext(ctx, "INSERT INTO reception DEFAULT VALUES"); err != nil {
+				return err
+			}
+			var ids []string
+			if err := database.ReadSelectContext(ctx, &ids, "SELECT id FROM reception"); err != nil {
+				return err
+			}
+			// Вложенная транзакция переиспользует открытую
+			return database.WithTx(ctx, func(tx *sqlx.Tx) error {
+				_, err := tx.ExecContext(ctx, "INSERT INTO event_outbox DEFAULT VALUES")
+				return err
+			})
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+		assert.NoError(t, replicaMock.ExpectationsWereMet())
+	})
+
+	t.Run("Ошибка откатывает транзакцию", func(t *testing.T) {
+		database, primaryMock, _ := setupDatabaseTest(t)
+
+		primaryMock.ExpectBegin()
+		primaryMock.ExpectExec(`INSERT INTO reception`).WillReturnResult(sqlmock.NewResult(0, 1))
+		primaryMock.ExpectRollback()
+
+		err := database.InTx(context.Background(), func(ctx context.Context) error {
+			if _, err := database.ExecContext(ctx, "INSERT INTO reception DEFAULT VALUES"); err != nil {
+				return err
+			}
+			return errors.New("outbox error")
+		})
+
+		assert.EqualError(t, err, "outbox error")
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"pvz-service/internal/metrics"
+	"pvz-service/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// queryDuration - гистограмма длительности SQL-запросов с разбивкой по методу запросов,
+// например PVZQueries.GetPVZList, и по имени запроса в реестре (db.query.id)
+var queryDuration, _ = metrics.Meter().Float64Histogram(
+	"db.client.query.duration",
+	metric.WithUnit("s"),
+	metric.WithDescription("Длительность SQL-запросов"),
+	metric.WithExplicitBucketBoundaries(0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10),
+)
+
+// unknownQuery - имя запроса, выполненного вне методов запросов
+const unknownQuery = "unknown"
+
+// startQuery открывает спан SQL-запроса и возвращает функцию, которая завершает его,
+// записывает длительность запроса в метрики и пишет в лог медленный запрос.
+// Запрос учитывается в счетчике запросов HTTP-запроса (WithQueryCounter)
+func (d *Database) startQuery(ctx context.Context, operation, query string, args []interface{}, replica bool) (context.Context, func(error)) {
+	countQuery(ctx)
+
+	name := tracing.Operation(ctx)
+	if name == "" {
+		name = unknownQuery
+	}
+
+	id := queryID(query)
+	ctx, span := startSpan(ctx, operation, query, id, replica)
+	started := time.Now()
+
+	return ctx, func(err error) {
+		elapsed := time.Since(started)
+		tracing.End(span, err)
+
+		queryDuration.Record(ctx, elapsed.Seconds(), metric.WithAttributes(
+			attribute.String("db.query.name", name),
+			attribute.String("db.query.id", id),
+			attribute.String("db.operation", operation),
+			attribute.Bool("db.replica", replica),
+			attribute.Bool("error", err != nil && !errors.Is(err, sql.ErrNoRows)),
+		))
+
+		if d.slowQueryThreshold > 0 && elapsed >= d.slowQueryThreshold {
+			log.Printf("Slow query %s took %s: %s %s", name, elapsed.Round(time.Millisecond), query, redactArgs(args))
+		}
+	}
+}
+
+// redactArgs описывает аргументы запроса без значений: в лог попадают только их типы,
+// чтобы туда не утекали персональные данные и секреты
+func redactArgs(args []interface{}) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = fmt.Sprintf("$%d=<%T>", i+1, arg)
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"pvz-service/internal/tracing"
+)
+
+func TestDatabase_QueryMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	database, primaryMock, _ := setupDatabaseTest(t)
+	database.slowQueryThreshold = time.Nanosecond
+
+	primaryMock.ExpectQuery(`SELECT COUNT\(\*\) FROM pvz`).
+		WithArgs("user@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	ctx, span := tracing.Start(context.Background(), "PVZQueries.GetPVZList")
+	var count int
+	assert.NoError(t, database.GetContext(ctx, &count, RegisterSQL("test.count_pvz", "SELECT COUNT(*) FROM pvz WHERE email = $1"), "user@example.com"))
+	span.End()
+
+	// Медленный запрос пишется в лог без значений аргументов
+	assert.Contains(t, logs.String(), "Slow query PVZQueries.GetPVZList")
+	assert.Contains(t, logs.String(), "[$1=<string>]")
+	assert.NotContains(t, logs.String(), "user@example.com")
+
+	var collected metricdata.ResourceMetrics
+	assert.NoError(t, reader.Collect(context.Background(), &collected))
+	if !assert.Len(t, collected.ScopeMetrics, 1) {
+		return
+	}
+
+	histogram := collected.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[float64])
+	assert.Len(t, histogram.DataPoints, 1)
+	assert.Equal(t, uint64(1), histogram.DataPoints[0].Count)
+
+	name, _ := histogram.DataPoints[0].Attributes.Value("db.query.name")
+	assert.Equal(t, attribute.StringValue("PVZQueries.GetPVZList"), name)
+	id, _ := histogram.DataPoints[0].Attributes.Value("db.query.id")
+	assert.Equal(t, attribute.StringValue("test.count_pvz"), id)
+}
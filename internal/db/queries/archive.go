@@ -0,0 +1,229 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/lib/pq"
+)
+
+// ArchiveQueriesInterface определяет интерфейс для запросов к архиву приёмок
+type ArchiveQueriesInterface interface {
+	ArchiveReceptions(ctx context.Context, closedBefore time.Time, limit int) ([]string, error)
+	RestoreReception(ctx context.Context, receptionID string) (*models.Reception, error)
+}
+
+// ArchiveQueries содержит методы запросов для переноса старых приёмок в архив и восстановления из него
+type ArchiveQueries struct {
+	db    *db.Database
+	clock clock.Clock
+}
+
+var _ ArchiveQueriesInterface = (*ArchiveQueries)(nil)
+
+// NewArchiveQueries создает новый экземпляр ArchiveQueries
+func NewArchiveQueries(db *db.Database) *ArchiveQueries {
+	return &ArchiveQueries{db: db, clock: clock.System{}}
+}
+
+// archivedTable - таблица, строки которой переносятся в архив вместе с приёмкой, в таблицу <name>_archive.
+// filter отбирает строки приёмок из массива $1. История товаров отбирается по рабочей таблице product:
+// при переносе в архив товары еще там, при восстановлении - уже возвращены
+type archivedTable struct {
+	name   string
+	filter string
+}
+
+const (
+	byReception = "reception_id = ANY($1)"
+	byProduct   = "product_id IN (SELECT id FROM product WHERE reception_id = ANY($1))"
+)
+
+var (
+	productHistoryTables = []archivedTable{
+		{"product_photos", byProduct},
+		{"product_damage_photos", byProduct},
+		{"product_type_changes", byProduct},
+		{"product_status", byProduct},
+	}
+	productTable = archivedTable{"product", byReception}
+	// receptionHistoryTables - история приёмки. Оповещения SLA в архив не переносятся:
+	// они нужны только для открытых приёмок и удаляются вместе с приёмкой
+	receptionHistoryTables = []archivedTable{
+		{"expected_products", byReception},
+		{"reception_notes", byReception},
+		{"reception_reassignments", byReception},
+		{"product_deletions", byReception},
+		{"reception_forced_closes", byReception},
+	}
+)
+
+// archiveMoves переносят строки в архив: история товаров раньше товаров, на которые она ссылается
+var archiveMoves = registerMoves("archive.move_", "", "_archive", slices.Concat(productHistoryTables, []archivedTable{productTable}, receptionHistoryTables))
+
+// restoreMoves возвращают строки из архива: товары раньше своей истории
+var restoreMoves = registerMoves("archive.restore_", "_archive", "", slices.Concat([]archivedTable{productTable}, productHistoryTables, receptionHistoryTables))
+
+// registerMoves регистрирует запросы переноса строк таблиц tables из <name><fromSuffix> в <name><toSuffix>
+func registerMoves(prefix, fromSuffix, toSuffix string, tables []archivedTable) []string {
+	moves := make([]string, 0, len(tables))
+	for _, table := range tables {
+		moves = append(moves, db.RegisterSQL(prefix+table.name, moveRowsSQL(table.name+fromSuffix, table.name+toSuffix, table.filter)))
+	}
+	return moves
+}
+
+// moveRowsSQL переносит строки из таблицы from в таблицу to с теми же колонками в том же порядке
+func moveRowsSQL(from, to, filter string) string {
+	return fmt.Sprintf("WITH moved AS (DELETE FROM %s WHERE %s RETURNING *) INSERT INTO %s SELECT * FROM moved", from, filter, to)
+}
+
+// archivableReceptionsSQL выбирает приёмки, закрытые раньше $1, и блокирует их. SKIP LOCKED позволяет
+// нескольким экземплярам сервиса переносить разные приёмки. Приёмки с товарами невыданных заказов
+// остаются в рабочих таблицах
+var archivableReceptionsSQL = db.RegisterSQL("archive.select_receptions", `SELECT r.id FROM reception r
+	WHERE r.status = 'close' AND COALESCE(r.closed_at, r.datetime) < $1
+		AND NOT EXISTS (
+			SELECT 1 FROM product p JOIN customer_orders o ON o.id = p.order_id
+			WHERE p.reception_id = r.id AND o.issued_at IS NULL
+		)
+	ORDER BY COALESCE(r.closed_at, r.datetime), r.id
+	LIMIT $2
+	FOR UPDATE OF r SKIP LOCKED`)
+
+var copyReceptionsSQL = db.RegisterSQL("archive.copy_receptions", "INSERT INTO reception_archive SELECT * FROM reception WHERE id = ANY($1)")
+
+var recordArchivalsSQL = db.RegisterSQL("archive.record_archivals", "INSERT INTO reception_archivals (reception_id, archived_at) SELECT unnest($1::uuid[]), $2")
+
+var deleteArchivedReceptionsSQL = db.RegisterSQL("archive.delete_receptions", "DELETE FROM reception WHERE id = ANY($1)")
+
+// ArchiveReceptions переносит в архив не больше limit приёмок, закрытых раньше closedBefore, вместе
+// с товарами и историей, одной транзакцией. Возвращает ID перенесенных приёмок.
+// Приёмка копируется до удаления товаров, чтобы в архиве остался ее счетчик товаров.
+// Удаленные товары попадают в product_tombstones, и терминалы убирают их при синхронизации
+func (q *ArchiveQueries) ArchiveReceptions(ctx context.Context, closedBefore time.Time, limit int) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "ArchiveQueries.ArchiveReceptions")
+	defer span.End()
+
+	var receptionIDs []string
+	err := q.db.InTx(ctx, func(ctx context.Context) error {
+		if err := q.db.SelectContext(ctx, &receptionIDs, archivableReceptionsSQL, closedBefore, limit); err != nil {
+			return fmt.Errorf("failed to select receptions to archive: %w", err)
+		}
+		if len(receptionIDs) == 0 {
+			return nil
+		}
+
+		ids := pq.Array(receptionIDs)
+		if _, err := q.db.ExecContext(ctx, copyReceptionsSQL, ids); err != nil {
+			return fmt.Errorf("failed to copy receptions to archive: %w", err)
+		}
+		if _, err := q.db.ExecContext(ctx, recordArchivalsSQL, ids, q.clock.Now()); err != nil {
+			return fmt.Errorf("failed to record archivals: %w", err)
+		}
+		for _, move := range archiveMoves {
+			if _, err := q.db.ExecContext(ctx, move, ids); err != nil {
+				return fmt.Errorf("failed to move reception data to archive: %w", err)
+			}
+		}
+		if _, err := q.db.ExecContext(ctx, deleteArchivedReceptionsSQL, ids); err != nil {
+			return fmt.Errorf("failed to delete archived receptions: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return receptionIDs, nil
+}
+
+var deleteArchivalSQL = db.RegisterSQL("archive.delete_archival", "DELETE FROM reception_archivals WHERE reception_id = $1")
+
+// clearDeletedTemplateSQL убирает ссылку на шаблон, удаленный после переноса приёмки в архив,
+// как это сделал бы внешний ключ рабочей таблицы (ON DELETE SET NULL)
+var clearDeletedTemplateSQL = db.RegisterSQL("archive.clear_deleted_template", `UPDATE reception_archive a SET template_id = NULL
+	WHERE a.id = $1 AND a.template_id IS NOT NULL
+		AND NOT EXISTS (SELECT 1 FROM reception_templates t WHERE t.id = a.template_id)`)
+
+var restoreReceptionSQL = db.RegisterSQL("archive.restore_reception", moveRowsSQL("reception_archive", "reception", "id = $1"))
+
+// refreshRestoredReceptionSQL пересчитывает счетчик товаров: триггер уже увеличил скопированный
+// из архива счетчик на каждый восстановленный товар. Обновление ставит приёмке новое время изменения
+var refreshRestoredReceptionSQL = db.RegisterSQL("archive.refresh_reception", `UPDATE reception
+	SET product_count = (SELECT COUNT(*) FROM product WHERE reception_id = $1)
+	WHERE id = $1`)
+
+// refreshRestoredProductsSQL ставит восстановленным товарам новое время изменения, чтобы терминалы
+// получили их при синхронизации
+var refreshRestoredProductsSQL = db.RegisterSQL("archive.refresh_products", "UPDATE product SET updated_at = clock_timestamp() WHERE reception_id = $1")
+
+var deleteRestoredTombstonesSQL = db.RegisterSQL("archive.delete_tombstones", "DELETE FROM product_tombstones WHERE reception_id = $1")
+
+// RestoreReception возвращает приёмку из архива в рабочие таблицы вместе с товарами и историей.
+// Возвращает ErrNotFound, если приёмки нет в архиве, и ErrArchiveRestoreConflict,
+// если ее ПВЗ, поставщик или заказ удалены после переноса в архив
+func (q *ArchiveQueries) RestoreReception(ctx context.Context, receptionID string) (*models.Reception, error) {
+	ctx, span := tracing.Start(ctx, "ArchiveQueries.RestoreReception")
+	defer span.End()
+
+	var reception models.Reception
+	err := q.db.InTx(ctx, func(ctx context.Context) error {
+		result, err := q.db.ExecContext(ctx, deleteArchivalSQL, receptionID)
+		if err != nil {
+			return fmt.Errorf("failed to delete archival: %w", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		if rows == 0 {
+			return fmt.Errorf("archived reception %s: %w", receptionID, ErrNotFound)
+		}
+
+		if _, err := q.db.ExecContext(ctx, clearDeletedTemplateSQL, receptionID); err != nil {
+			return fmt.Errorf("failed to clear deleted template: %w", err)
+		}
+		if _, err := q.db.ExecContext(ctx, restoreReceptionSQL, receptionID); err != nil {
+			return restoreError(err)
+		}
+		ids := pq.Array([]string{receptionID})
+		for _, move := range restoreMoves {
+			if _, err := q.db.ExecContext(ctx, move, ids); err != nil {
+				return restoreError(err)
+			}
+		}
+
+		if _, err := q.db.ExecContext(ctx, refreshRestoredReceptionSQL, receptionID); err != nil {
+			return fmt.Errorf("failed to refresh restored reception: %w", err)
+		}
+		if _, err := q.db.ExecContext(ctx, refreshRestoredProductsSQL, receptionID); err != nil {
+			return fmt.Errorf("failed to refresh restored products: %w", err)
+		}
+		if _, err := q.db.ExecContext(ctx, deleteRestoredTombstonesSQL, receptionID); err != nil {
+			return fmt.Errorf("failed to delete tombstones: %w", err)
+		}
+
+		return q.db.QueryRowxContext(ctx, receptionByIDSQL, receptionID).StructScan(&reception)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &reception, nil
+}
+
+// restoreError оборачивает ошибку восстановления, нарушение внешнего ключа - в ErrArchiveRestoreConflict
+func restoreError(err error) error {
+	if isForeignKeyViolation(err) {
+		return fmt.Errorf("%w: %v", ErrArchiveRestoreConflict, err)
+	}
+	return fmt.Errorf("failed to restore reception from archive: %w", err)
+}
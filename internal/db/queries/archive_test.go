@@ -0,0 +1,116 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db"
+)
+
+func setupArchiveQueriesTest(t *testing.T) (*ArchiveQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &ArchiveQueries{db: &db.Database{DB: sqlxDB}, clock: clock.Fixed(time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC))}, mock
+}
+
+func TestArchiveQueries_ArchiveReceptions(t *testing.T) {
+	q, mock := setupArchiveQueriesTest(t)
+	closedBefore := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Приёмки переносятся в архив", func(t *testing.T) {
+		ids := pq.Array([]string{"r1", "r2"})
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT r.id FROM reception r WHERE r.status = 'close' .* FOR UPDATE OF r SKIP LOCKED`).
+			WithArgs(closedBefore, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("r1").AddRow("r2"))
+		mock.ExpectExec(`INSERT INTO reception_archive SELECT \* FROM reception WHERE id = ANY\(\$1\)`).
+			WithArgs(ids).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`INSERT INTO reception_archivals`).
+			WithArgs(ids, time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		// История товаров переносится раньше товаров
+		mock.ExpectExec(`DELETE FROM product_photos WHERE product_id IN .* INSERT INTO product_photos_archive`).
+			WithArgs(ids).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DELETE FROM product_damage_photos WHERE`).WithArgs(ids).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DELETE FROM product_type_changes WHERE`).WithArgs(ids).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DELETE FROM product_status WHERE`).WithArgs(ids).WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(`DELETE FROM product WHERE reception_id = ANY\(\$1\) RETURNING \*\) INSERT INTO product_archive`).
+			WithArgs(ids).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		for _, table := range []string{"expected_products", "reception_notes", "reception_reassignments", "product_deletions", "reception_forced_closes"} {
+			mock.ExpectExec(`DELETE FROM ` + table + ` WHERE`).WithArgs(ids).WillReturnResult(sqlmock.NewResult(0, 0))
+		}
+		mock.ExpectExec(`DELETE FROM reception WHERE id = ANY\(\$1\)`).
+			WithArgs(ids).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		archived, err := q.ArchiveReceptions(context.Background(), closedBefore, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"r1", "r2"}, archived)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Нечего переносить", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT r.id FROM reception r`).
+			WithArgs(closedBefore, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectCommit()
+
+		archived, err := q.ArchiveReceptions(context.Background(), closedBefore, 2)
+
+		require.NoError(t, err)
+		assert.Empty(t, archived)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestArchiveQueries_RestoreReception(t *testing.T) {
+	q, mock := setupArchiveQueriesTest(t)
+
+	t.Run("Приёмки нет в архиве", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM reception_archivals WHERE reception_id = \$1`).
+			WithArgs("r1").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		_, err := q.RestoreReception(context.Background(), "r1")
+
+		assert.True(t, errors.Is(err, ErrNotFound))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ПВЗ приёмки удален", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM reception_archivals`).
+			WithArgs("r1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE reception_archive a SET template_id = NULL`).
+			WithArgs("r1").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DELETE FROM reception_archive WHERE id = \$1 RETURNING \*\) INSERT INTO reception SELECT`).
+			WithArgs("r1").
+			WillReturnError(&pq.Error{Code: "23503"})
+		mock.ExpectRollback()
+
+		_, err := q.RestoreReception(context.Background(), "r1")
+
+		assert.True(t, errors.Is(err, ErrArchiveRestoreConflict))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
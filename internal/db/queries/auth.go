@@ -3,9 +3,11 @@ package queries
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"pvz-service/internal/db"
 	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
 
 	"github.com/Masterminds/squirrel"
 )
@@ -13,16 +15,28 @@ import (
 // AuthQueriesInterface определяет интерфейс для запросов, связанных с аутентификацией
 type AuthQueriesInterface interface {
 	GetUserByEmail(ctx context.Context, email string) (bool, error)
-	CreateUser(ctx context.Context, email, passwordHash, role string) (string, error)
+	CreateUser(ctx context.Context, email, phone, passwordHash, role string) (string, error)
 	GetUserWithCredentials(ctx context.Context, email string) (*models.User, error)
+	GetUserCredentialsByID(ctx context.Context, userID string) (*models.User, error)
+	GetUserByPhone(ctx context.Context, phone string) (*models.User, error)
+	UpdatePasswordHash(ctx context.Context, userID, passwordHash string) error
+	GetProfile(ctx context.Context, userID string) (*models.UserProfile, error)
+	UpdateProfile(ctx context.Context, userID string, update models.ProfileUpdate) (*models.UserProfile, error)
+	IsUserActive(ctx context.Context, userID string) (bool, error)
+	SetUserActive(ctx context.Context, userID string, active bool) error
 }
 
+// profileColumns - колонки профиля пользователя
+const profileColumns = "id, COALESCE(email, '') AS email, phone, role, first_name, last_name, avatar_key, avatar_url"
+
 // AuthQueries содержит методы запросов для авторизации
 type AuthQueries struct {
 	db *db.Database
 	sq squirrel.StatementBuilderType
 }
 
+var _ AuthQueriesInterface = (*AuthQueries)(nil)
+
 // NewAuthQueries создает новый экземпляр AuthQueries
 func NewAuthQueries(db *db.Database) *AuthQueries {
 	return &AuthQueries{
@@ -31,12 +45,15 @@ func NewAuthQueries(db *db.Database) *AuthQueries {
 	}
 }
 
-// CreateUser создает нового пользователя
-func (q *AuthQueries) CreateUser(ctx context.Context, email, passwordHash, role string) (string, error) {
+// CreateUser создает нового пользователя, пустые email и телефон сохраняются как NULL
+func (q *AuthQueries) CreateUser(ctx context.Context, email, phone, passwordHash, role string) (string, error) {
+	ctx, span := tracing.Start(ctx, "AuthQueries.CreateUser")
+	defer span.End()
+
 	query := q.sq.
 		Insert("users").
-		Columns("email", "password_hash", "role", "created_at").
-		Values(email, passwordHash, role, squirrel.Expr("CURRENT_TIMESTAMP")).
+		Columns("email", "phone", "password_hash", "role", "created_at").
+		Values(nullString(email), nullString(phone), passwordHash, role, squirrel.Expr("CURRENT_TIMESTAMP")).
 		Suffix("RETURNING id")
 
 	sql, args, err := query.ToSql()
@@ -55,6 +72,9 @@ func (q *AuthQueries) CreateUser(ctx context.Context, email, passwordHash, role
 
 // GetUserByEmail проверяет, существует ли пользователь с таким email
 func (q *AuthQueries) GetUserByEmail(ctx context.Context, email string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "AuthQueries.GetUserByEmail")
+	defer span.End()
+
 	query := q.sq.
 		Select("1").
 		From("users").
@@ -78,24 +98,244 @@ func (q *AuthQueries) GetUserByEmail(ctx context.Context, email string) (bool, e
 	return true, nil
 }
 
-// GetUserWithCredentials получает пользователя по email вместе с хешем пароля
+// GetUserWithCredentials получает пользователя по email вместе с хешем пароля.
+// Возвращает ErrNotFound, если пользователя нет
 func (q *AuthQueries) GetUserWithCredentials(ctx context.Context, email string) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "AuthQueries.GetUserWithCredentials")
+	defer span.End()
+
 	query := q.sq.
-		Select("id", "email", "role", "password_hash").
+		Select("id", "email", "role", "password_hash", "is_active").
 		From("users").
 		Where(squirrel.Eq{"email": email}).
 		Limit(1)
 
-	sql, args, err := query.ToSql()
+	qsql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var user models.User
+	err = q.db.GetContext(ctx, &user, qsql, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("user with email %s: %w", email, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return &user, nil
+}
+
+// GetUserCredentialsByID получает пользователя по ID вместе с хешем пароля
+func (q *AuthQueries) GetUserCredentialsByID(ctx context.Context, userID string) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "AuthQueries.GetUserCredentialsByID")
+	defer span.End()
+
+	query := q.sq.
+		Select("id", "COALESCE(email, '') AS email", "role", "password_hash").
+		From("users").
+		Where(squirrel.Eq{"id": userID})
+
+	qsql, args, err := query.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
 
 	var user models.User
-	err = q.db.GetContext(ctx, &user, sql, args...)
+	err = q.db.GetContext(ctx, &user, qsql, args...)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("user %s: %w", userID, ErrNotFound)
+		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
 	return &user, nil
 }
+
+// GetUserByPhone получает пользователя по номеру телефона
+func (q *AuthQueries) GetUserByPhone(ctx context.Context, phone string) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "AuthQueries.GetUserByPhone")
+	defer span.End()
+
+	query := q.sq.
+		Select("id", "COALESCE(email, '') AS email", "role", "phone", "is_active").
+		From("users").
+		Where(squirrel.Eq{"phone": phone}).
+		Limit(1)
+
+	qsql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var user models.User
+	err = q.db.GetContext(ctx, &user, qsql, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("user with phone %s: %w", phone, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get user by phone: %w", err)
+	}
+
+	return &user, nil
+}
+
+// UpdatePasswordHash заменяет хеш пароля пользователя
+func (q *AuthQueries) UpdatePasswordHash(ctx context.Context, userID, passwordHash string) error {
+	ctx, span := tracing.Start(ctx, "AuthQueries.UpdatePasswordHash")
+	defer span.End()
+
+	query := q.sq.
+		Update("users").
+		Set("password_hash", passwordHash).
+		Where(squirrel.Eq{"id": userID})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update password hash: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("user %s: %w", userID, ErrNotFound)
+	}
+
+	return nil
+}
+
+// IsUserActive проверяет, что пользователь существует и не деактивирован.
+// Проверка выполняется на каждый запрос с токеном сессии, поэтому временные ошибки повторяются
+func (q *AuthQueries) IsUserActive(ctx context.Context, userID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "AuthQueries.IsUserActive")
+	defer span.End()
+
+	var active bool
+	err := q.db.Retry(ctx, func(ctx context.Context) error {
+		return q.db.GetContext(ctx, &active, "SELECT is_active FROM users WHERE id = $1", userID)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check user: %w", err)
+	}
+
+	return active, nil
+}
+
+// SetUserActive активирует или деактивирует пользователя, время деактивации сохраняется.
+// Возвращает ErrNotFound, если пользователя нет
+func (q *AuthQueries) SetUserActive(ctx context.Context, userID string, active bool) error {
+	ctx, span := tracing.Start(ctx, "AuthQueries.SetUserActive")
+	defer span.End()
+
+	query := q.sq.
+		Update("users").
+		Set("is_active", active).
+		Where(squirrel.Eq{"id": userID})
+	if active {
+		query = query.Set("deactivated_at", nil)
+	} else {
+		query = query.Set("deactivated_at", squirrel.Expr("CURRENT_TIMESTAMP"))
+	}
+
+	qsql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, qsql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update user activity: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("user %s: %w", userID, ErrNotFound)
+	}
+
+	return nil
+}
+
+// GetProfile получает профиль пользователя
+func (q *AuthQueries) GetProfile(ctx context.Context, userID string) (*models.UserProfile, error) {
+	ctx, span := tracing.Start(ctx, "AuthQueries.GetProfile")
+	defer span.End()
+
+	query := q.sq.
+		Select(profileColumns).
+		From("users").
+		Where(squirrel.Eq{"id": userID})
+
+	qsql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var profile models.UserProfile
+	err = q.db.GetContext(ctx, &profile, qsql, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("user %s: %w", userID, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	return &profile, nil
+}
+
+// UpdateProfile меняет переданные поля профиля и возвращает профиль после изменения.
+// Новый аватар заменяет прежний целиком: ключ в хранилище и внешняя ссылка взаимоисключающие
+func (q *AuthQueries) UpdateProfile(ctx context.Context, userID string, update models.ProfileUpdate) (*models.UserProfile, error) {
+	ctx, span := tracing.Start(ctx, "AuthQueries.UpdateProfile")
+	defer span.End()
+
+	query := q.sq.
+		Update("users").
+		Where(squirrel.Eq{"id": userID}).
+		Suffix("RETURNING " + profileColumns)
+
+	if update.FirstName != nil {
+		query = query.Set("first_name", *update.FirstName)
+	}
+	if update.LastName != nil {
+		query = query.Set("last_name", *update.LastName)
+	}
+	if update.Phone != nil {
+		query = query.Set("phone", *update.Phone)
+	}
+	if update.Avatar != nil {
+		query = query.
+			Set("avatar_key", update.Avatar.StorageKey).
+			Set("avatar_url", update.Avatar.URL)
+	}
+
+	qsql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var profile models.UserProfile
+	err = q.db.GetContext(ctx, &profile, qsql, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("user %s: %w", userID, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	return &profile, nil
+}
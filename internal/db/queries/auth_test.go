@@ -46,9 +46,9 @@ func TestCreateUser(t *testing.T) {
 			passwordHash: "hash123",
 			role:         "employee",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				expectedSQL := `INSERT INTO users \(email,password_hash,role,created_at\) VALUES \(\$1,\$2,\$3,CURRENT_TIMESTAMP\) RETURNING id`
+				expectedSQL := `INSERT INTO users \(email,phone,password_hash,role,created_at\) VALUES \(\$1,\$2,\$3,\$4,CURRENT_TIMESTAMP\) RETURNING id`
 				mock.ExpectQuery(expectedSQL).
-					WithArgs("user@example.com", "hash123", "employee").
+					WithArgs("user@example.com", nil, "hash123", "employee").
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("123e4567-e89b-12d3-a456-426614174000"))
 			},
 			expectedID:  "123e4567-e89b-12d3-a456-426614174000",
@@ -60,9 +60,9 @@ func TestCreateUser(t *testing.T) {
 			passwordHash: "hash123",
 			role:         "employee",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				expectedSQL := `INSERT INTO users \(email,password_hash,role,created_at\) VALUES \(\$1,\$2,\$3,CURRENT_TIMESTAMP\) RETURNING id`
+				expectedSQL := `INSERT INTO users \(email,phone,password_hash,role,created_at\) VALUES \(\$1,\$2,\$3,\$4,CURRENT_TIMESTAMP\) RETURNING id`
 				mock.ExpectQuery(expectedSQL).
-					WithArgs("user@example.com", "hash123", "employee").
+					WithArgs("user@example.com", nil, "hash123", "employee").
 					WillReturnError(errors.New("database error"))
 			},
 			expectedID:  "",
@@ -77,7 +77,7 @@ func TestCreateUser(t *testing.T) {
 			tc.mockSetup(mock)
 
 			// Выполнение
-			id, err := q.CreateUser(context.Background(), tc.email, tc.passwordHash, tc.role)
+			id, err := q.CreateUser(context.Background(), tc.email, "", tc.passwordHash, tc.role)
 
 			// Проверка
 			if tc.expectedErr {
@@ -178,12 +178,12 @@ func TestGetUserWithCredentials(t *testing.T) {
 			name:  "Успешное получение пользователя",
 			email: "user@example.com",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				expectedSQL := `SELECT id, email, role, password_hash FROM users WHERE email = \$1 LIMIT 1`
+				expectedSQL := `SELECT id, email, role, password_hash, is_active FROM users WHERE email = \$1 LIMIT 1`
 				mock.ExpectQuery(expectedSQL).
 					WithArgs("user@example.com").
 					WillReturnRows(
-						sqlmock.NewRows([]string{"id", "email", "role", "password_hash"}).
-							AddRow("123e4567-e89b-12d3-a456-426614174000", "user@example.com", "employee", "hash123"),
+						sqlmock.NewRows([]string{"id", "email", "role", "password_hash", "is_active"}).
+							AddRow("123e4567-e89b-12d3-a456-426614174000", "user@example.com", "employee", "hash123", true),
 					)
 			},
 			expected: &models.User{
@@ -191,6 +191,7 @@ func TestGetUserWithCredentials(t *testing.T) {
 				Email:        "user@example.com",
 				Role:         "employee",
 				PasswordHash: "hash123",
+				IsActive:     true,
 			},
 			expectedErr: false,
 		},
@@ -198,7 +199,7 @@ func TestGetUserWithCredentials(t *testing.T) {
 			name:  "Пользователь не найден",
 			email: "notfound@example.com",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				expectedSQL := `SELECT id, email, role, password_hash FROM users WHERE email = \$1 LIMIT 1`
+				expectedSQL := `SELECT id, email, role, password_hash, is_active FROM users WHERE email = \$1 LIMIT 1`
 				mock.ExpectQuery(expectedSQL).
 					WithArgs("notfound@example.com").
 					WillReturnError(sql.ErrNoRows)
@@ -210,7 +211,7 @@ func TestGetUserWithCredentials(t *testing.T) {
 			name:  "Ошибка базы данных",
 			email: "error@example.com",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				expectedSQL := `SELECT id, email, role, password_hash FROM users WHERE email = \$1 LIMIT 1`
+				expectedSQL := `SELECT id, email, role, password_hash, is_active FROM users WHERE email = \$1 LIMIT 1`
 				mock.ExpectQuery(expectedSQL).
 					WithArgs("error@example.com").
 					WillReturnError(errors.New("database error"))
@@ -239,6 +240,7 @@ func TestGetUserWithCredentials(t *testing.T) {
 				assert.Equal(t, tc.expected.Email, user.Email)
 				assert.Equal(t, tc.expected.Role, user.Role)
 				assert.Equal(t, tc.expected.PasswordHash, user.PasswordHash)
+				assert.Equal(t, tc.expected.IsActive, user.IsActive)
 			}
 
 			// Проверка, что все ожидания были выполнены
@@ -248,3 +250,186 @@ func TestGetUserWithCredentials(t *testing.T) {
 		})
 	}
 }
+
+func TestGetUserByPhone(t *testing.T) {
+	q, mock := setupAuthQueriesTest(t)
+
+	expectedSQL := `SELECT id, COALESCE\(email, ''\) AS email, role, phone, is_active FROM users WHERE phone = \$1 LIMIT 1`
+	t.Run("Пользователь найден", func(t *testing.T) {
+		mock.ExpectQuery(expectedSQL).
+			WithArgs("+79990001122").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "email", "role", "phone", "is_active"}).
+				AddRow("user-id", "", "employee", "+79990001122", true))
+
+		user, err := q.GetUserByPhone(context.Background(), "+79990001122")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-id", user.ID)
+		assert.Equal(t, "+79990001122", *user.Phone)
+	})
+
+	t.Run("Пользователь не найден", func(t *testing.T) {
+		mock.ExpectQuery(expectedSQL).
+			WithArgs("+79990001122").
+			WillReturnError(sql.ErrNoRows)
+
+		user, err := q.GetUserByPhone(context.Background(), "+79990001122")
+
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Nil(t, user)
+	})
+}
+
+func TestUpdatePasswordHash(t *testing.T) {
+	q, mock := setupAuthQueriesTest(t)
+
+	expectedSQL := `UPDATE users SET password_hash = \$1 WHERE id = \$2`
+	t.Run("Хеш обновлен", func(t *testing.T) {
+		mock.ExpectExec(expectedSQL).
+			WithArgs("new-hash", "user-id").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := q.UpdatePasswordHash(context.Background(), "user-id", "new-hash")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Пользователь не найден", func(t *testing.T) {
+		mock.ExpectExec(expectedSQL).
+			WithArgs("new-hash", "user-id").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := q.UpdatePasswordHash(context.Background(), "user-id", "new-hash")
+
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIsUserActive(t *testing.T) {
+	q, mock := setupAuthQueriesTest(t)
+
+	expectedSQL := `SELECT is_active FROM users WHERE id = \$1`
+	t.Run("Пользователь активен", func(t *testing.T) {
+		mock.ExpectQuery(expectedSQL).
+			WithArgs("user-id").
+			WillReturnRows(sqlmock.NewRows([]string{"is_active"}).AddRow(true))
+
+		active, err := q.IsUserActive(context.Background(), "user-id")
+
+		assert.NoError(t, err)
+		assert.True(t, active)
+	})
+
+	t.Run("Пользователь удален", func(t *testing.T) {
+		mock.ExpectQuery(expectedSQL).
+			WithArgs("user-id").
+			WillReturnError(sql.ErrNoRows)
+
+		active, err := q.IsUserActive(context.Background(), "user-id")
+
+		assert.NoError(t, err)
+		assert.False(t, active)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetUserActive(t *testing.T) {
+	q, mock := setupAuthQueriesTest(t)
+
+	t.Run("Деактивация", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE users SET is_active = \$1, deactivated_at = CURRENT_TIMESTAMP WHERE id = \$2`).
+			WithArgs(false, "user-id").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, q.SetUserActive(context.Background(), "user-id", false))
+	})
+
+	t.Run("Пользователь не найден", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE users SET is_active = \$1, deactivated_at = \$2 WHERE id = \$3`).
+			WithArgs(true, nil, "user-id").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, q.SetUserActive(context.Background(), "user-id", true), ErrNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+var profileRows = []string{"id", "email", "phone", "role", "first_name", "last_name", "avatar_key", "avatar_url"}
+
+func TestGetProfile(t *testing.T) {
+	q, mock := setupAuthQueriesTest(t)
+
+	expectedSQL := `SELECT id, COALESCE\(email, ''\) AS email, phone, role, first_name, last_name, avatar_key, avatar_url FROM users WHERE id = \$1`
+	t.Run("Профиль найден", func(t *testing.T) {
+		mock.ExpectQuery(expectedSQL).
+			WithArgs("user-id").
+			WillReturnRows(sqlmock.NewRows(profileRows).
+				AddRow("user-id", "user@example.com", nil, "employee", "Иван", "Петров", "avatars/user-id/a.png", nil))
+
+		profile, err := q.GetProfile(context.Background(), "user-id")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Иван", profile.FirstName)
+		assert.Equal(t, "avatars/user-id/a.png", *profile.AvatarKey)
+		assert.Nil(t, profile.Phone)
+	})
+
+	t.Run("Пользователь не найден", func(t *testing.T) {
+		mock.ExpectQuery(expectedSQL).
+			WithArgs("user-id").
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := q.GetProfile(context.Background(), "user-id")
+
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateProfile(t *testing.T) {
+	q, mock := setupAuthQueriesTest(t)
+
+	t.Run("Имя и телефон", func(t *testing.T) {
+		firstName, phone := "Иван", "+79990001122"
+		mock.ExpectQuery(`UPDATE users SET first_name = \$1, phone = \$2 WHERE id = \$3 RETURNING id, COALESCE\(email, ''\) AS email, phone, role, first_name, last_name, avatar_key, avatar_url`).
+			WithArgs(firstName, phone, "user-id").
+			WillReturnRows(sqlmock.NewRows(profileRows).
+				AddRow("user-id", "", phone, "employee", firstName, "", nil, nil))
+
+		profile, err := q.UpdateProfile(context.Background(), "user-id", models.ProfileUpdate{FirstName: &firstName, Phone: &phone})
+
+		assert.NoError(t, err)
+		assert.Equal(t, phone, *profile.Phone)
+	})
+
+	t.Run("Новый аватар заменяет ссылку", func(t *testing.T) {
+		key := "avatars/user-id/a.png"
+		mock.ExpectQuery(`UPDATE users SET avatar_key = \$1, avatar_url = \$2 WHERE id = \$3`).
+			WithArgs(key, nil, "user-id").
+			WillReturnRows(sqlmock.NewRows(profileRows).
+				AddRow("user-id", "", nil, "employee", "", "", key, nil))
+
+		profile, err := q.UpdateProfile(context.Background(), "user-id", models.ProfileUpdate{Avatar: &models.ProfileAvatar{StorageKey: &key}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, key, *profile.AvatarKey)
+	})
+
+	t.Run("Пользователь не найден", func(t *testing.T) {
+		lastName := "Петров"
+		mock.ExpectQuery(`UPDATE users SET last_name = \$1 WHERE id = \$2`).
+			WithArgs(lastName, "user-id").
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := q.UpdateProfile(context.Background(), "user-id", models.ProfileUpdate{LastName: &lastName})
+
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
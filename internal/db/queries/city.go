@@ -0,0 +1,68 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+// CityQueriesInterface определяет интерфейс для запросов к справочнику городов
+type CityQueriesInterface interface {
+	GetCities(ctx context.Context) ([]models.City, error)
+}
+
+// CityQueries содержит методы запросов для работы со справочником городов
+type CityQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ CityQueriesInterface = (*CityQueries)(nil)
+
+// NewCityQueries создает новый экземпляр CityQueries
+func NewCityQueries(db *db.Database) *CityQueries {
+	return &CityQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+	}
+}
+
+// cityRow представляет строку справочника городов
+type cityRow struct {
+	Name    string         `db:"name"`
+	Aliases pq.StringArray `db:"aliases"`
+}
+
+// GetCities получает все города справочника вместе с вариантами написания
+func (q *CityQueries) GetCities(ctx context.Context) ([]models.City, error) {
+	ctx, span := tracing.Start(ctx, "CityQueries.GetCities")
+	defer span.End()
+
+	sql, args, err := q.sq.
+		Select("name", "aliases").
+		From("cities").
+		OrderBy("name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var rows []cityRow
+	err = q.db.ReadSelectContext(ctx, &rows, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cities: %w", err)
+	}
+
+	cities := make([]models.City, 0, len(rows))
+	for _, row := range rows {
+		cities = append(cities, models.City{Name: row.Name, Aliases: row.Aliases})
+	}
+
+	return cities, nil
+}
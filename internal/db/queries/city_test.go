@@ -0,0 +1,53 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db"
+)
+
+func setupCityQueriesTest(t *testing.T) (*CityQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	dbInstance := &db.Database{DB: sqlxDB}
+
+	return &CityQueries{
+		db: dbInstance,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+func TestCityQueries_GetCities(t *testing.T) {
+	q, mock := setupCityQueriesTest(t)
+
+	expectedSQL := `SELECT name, aliases FROM cities ORDER BY name`
+	t.Run("Успешное получение справочника", func(t *testing.T) {
+		mock.ExpectQuery(expectedSQL).
+			WillReturnRows(sqlmock.NewRows([]string{"name", "aliases"}).
+				AddRow("Казань", "{kazan}").
+				AddRow("Москва", `{мск,moscow}`))
+
+		cities, err := q.GetCities(context.Background())
+
+		assert.NoError(t, err)
+		assert.Len(t, cities, 2)
+		assert.Equal(t, []string{"мск", "moscow"}, cities[1].Aliases)
+	})
+
+	t.Run("Ошибка базы данных", func(t *testing.T) {
+		mock.ExpectQuery(expectedSQL).
+			WillReturnError(errors.New("database error"))
+
+		cities, err := q.GetCities(context.Background())
+
+		assert.Error(t, err)
+		assert.Nil(t, cities)
+	})
+}
@@ -0,0 +1,211 @@
+package queries
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// DeliveryQueriesInterface определяет интерфейс для запросов к неудачным исходящим доставкам
+type DeliveryQueriesInterface interface {
+	AddFailedDelivery(ctx context.Context, target string, event models.Event, reason string) error
+	GetDueDeliveries(ctx context.Context, limit int) ([]models.FailedDelivery, error)
+	RecordDeliveryFailure(ctx context.Context, id int64, reason string, nextAttemptAt time.Time, dead bool) error
+	DeleteDelivery(ctx context.Context, id int64) error
+	GetDeliveries(ctx context.Context, params models.DeliveryListQuery) ([]models.FailedDelivery, error)
+	RequeueDelivery(ctx context.Context, id int64) error
+}
+
+// DeliveryQueries содержит методы запросов для работы с неудачными исходящими доставками
+type DeliveryQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ DeliveryQueriesInterface = (*DeliveryQueries)(nil)
+
+// NewDeliveryQueries создает новый экземпляр DeliveryQueries
+func NewDeliveryQueries(db *db.Database) *DeliveryQueries {
+	return &DeliveryQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+	}
+}
+
+const deliveryColumns = "id, target, event, status, attempts, last_error, next_attempt_at, created_at, updated_at"
+
+// AddFailedDelivery сохраняет неудачную доставку события, первая повторная попытка выполняется сразу
+func (q *DeliveryQueries) AddFailedDelivery(ctx context.Context, target string, event models.Event, reason string) error {
+	ctx, span := tracing.Start(ctx, "DeliveryQueries.AddFailedDelivery")
+	defer span.End()
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	sql, args, err := q.sq.
+		Insert("failed_deliveries").
+		Columns("target", "event", "last_error").
+		Values(target, string(body), reason).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := q.db.ExecContext(ctx, sql, args...); err != nil {
+		return fmt.Errorf("failed to add failed delivery: %w", err)
+	}
+
+	return nil
+}
+
+// GetDueDeliveries получает доставки, время повторной попытки которых наступило
+func (q *DeliveryQueries) GetDueDeliveries(ctx context.Context, limit int) ([]models.FailedDelivery, error) {
+	ctx, span := tracing.Start(ctx, "DeliveryQueries.GetDueDeliveries")
+	defer span.End()
+
+	sql, args, err := q.sq.
+		Select(deliveryColumns).
+		From("failed_deliveries").
+		Where(squirrel.Eq{"status": models.DeliveryStatusRetrying}).
+		Where("next_attempt_at <= CURRENT_TIMESTAMP").
+		OrderBy("next_attempt_at").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var deliveries []models.FailedDelivery
+	err = q.db.SelectContext(ctx, &deliveries, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// RecordDeliveryFailure записывает неудачную повторную попытку. При dead доставка
+// переносится в dead-letter queue и больше не повторяется автоматически
+func (q *DeliveryQueries) RecordDeliveryFailure(ctx context.Context, id int64, reason string, nextAttemptAt time.Time, dead bool) error {
+	ctx, span := tracing.Start(ctx, "DeliveryQueries.RecordDeliveryFailure")
+	defer span.End()
+
+	status := models.DeliveryStatusRetrying
+	if dead {
+		status = models.DeliveryStatusDead
+	}
+
+	sql, args, err := q.sq.
+		Update("failed_deliveries").
+		Set("attempts", squirrel.Expr("attempts + 1")).
+		Set("last_error", reason).
+		Set("next_attempt_at", nextAttemptAt).
+		Set("status", status).
+		Set("updated_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := q.db.ExecContext(ctx, sql, args...); err != nil {
+		return fmt.Errorf("failed to record delivery failure: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteDelivery удаляет доставку после успешной повторной попытки
+func (q *DeliveryQueries) DeleteDelivery(ctx context.Context, id int64) error {
+	ctx, span := tracing.Start(ctx, "DeliveryQueries.DeleteDelivery")
+	defer span.End()
+
+	sql, args, err := q.sq.
+		Delete("failed_deliveries").
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := q.db.ExecContext(ctx, sql, args...); err != nil {
+		return fmt.Errorf("failed to delete delivery: %w", err)
+	}
+
+	return nil
+}
+
+// GetDeliveries получает неудачные доставки, новые первыми, с необязательным фильтром по статусу
+func (q *DeliveryQueries) GetDeliveries(ctx context.Context, params models.DeliveryListQuery) ([]models.FailedDelivery, error) {
+	ctx, span := tracing.Start(ctx, "DeliveryQueries.GetDeliveries")
+	defer span.End()
+
+	if params.Limit <= 0 {
+		params.Limit = 100
+	}
+
+	query := q.sq.
+		Select(deliveryColumns).
+		From("failed_deliveries").
+		OrderBy("id DESC").
+		Limit(uint64(params.Limit))
+	if params.Status != "" {
+		query = query.Where(squirrel.Eq{"status": params.Status})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var deliveries []models.FailedDelivery
+	err = q.db.SelectContext(ctx, &deliveries, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// RequeueDelivery возвращает доставку в очередь повторов с немедленной попыткой и новым счетчиком.
+// Возвращает ErrNotFound, если доставки нет
+func (q *DeliveryQueries) RequeueDelivery(ctx context.Context, id int64) error {
+	ctx, span := tracing.Start(ctx, "DeliveryQueries.RequeueDelivery")
+	defer span.End()
+
+	sql, args, err := q.sq.
+		Update("failed_deliveries").
+		Set("status", models.DeliveryStatusRetrying).
+		Set("attempts", 0).
+		Set("next_attempt_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Set("updated_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to requeue delivery: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("delivery %d: %w", id, ErrNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+)
+
+func setupDeliveryQueriesTest(t *testing.T) (*DeliveryQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &DeliveryQueries{
+		db: &db.Database{DB: sqlxDB},
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+var deliveryRowColumns = []string{"id", "target", "event", "status", "attempts", "last_error", "next_attempt_at", "created_at", "updated_at"}
+
+func TestDeliveryQueries_AddFailedDelivery(t *testing.T) {
+	q, mock := setupDeliveryQueriesTest(t)
+
+	mock.ExpectExec(`INSERT INTO failed_deliveries \(target,event,last_error\) VALUES \(\$1,\$2,\$3\)`).
+		WithArgs(models.DeliveryTargetBroker, sqlmock.AnyArg(), "broker unavailable").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := q.AddFailedDelivery(context.Background(), models.DeliveryTargetBroker, models.Event{ID: 1}, "broker unavailable")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeliveryQueries_GetDueDeliveries(t *testing.T) {
+	q, mock := setupDeliveryQueriesTest(t)
+
+	mock.ExpectQuery(`SELECT .* FROM failed_deliveries WHERE status = \$1 AND next_attempt_at <= CURRENT_TIMESTAMP ORDER BY next_attempt_at LIMIT 100`).
+		WithArgs(models.DeliveryStatusRetrying).
+		WillReturnRows(sqlmock.NewRows(deliveryRowColumns).
+			AddRow(1, models.DeliveryTargetAlerts, []byte(`{"id":1}`), models.DeliveryStatusRetrying, 1, "timeout", time.Now(), time.Now(), time.Now()))
+
+	deliveries, err := q.GetDueDeliveries(context.Background(), 100)
+
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeliveryQueries_RecordDeliveryFailure(t *testing.T) {
+	q, mock := setupDeliveryQueriesTest(t)
+
+	nextAttemptAt := time.Now().Add(time.Minute)
+
+	mock.ExpectExec(`UPDATE failed_deliveries SET attempts = attempts \+ 1, last_error = \$1, next_attempt_at = \$2, status = \$3, updated_at = CURRENT_TIMESTAMP WHERE id = \$4`).
+		WithArgs("timeout", nextAttemptAt, models.DeliveryStatusDead, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := q.RecordDeliveryFailure(context.Background(), 1, "timeout", nextAttemptAt, true)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeliveryQueries_RequeueDelivery(t *testing.T) {
+	q, mock := setupDeliveryQueriesTest(t)
+
+	t.Run("Доставка возвращается в очередь", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE failed_deliveries SET status = \$1, attempts = \$2, next_attempt_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = \$3`).
+			WithArgs(models.DeliveryStatusRetrying, 0, int64(5)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, q.RequeueDelivery(context.Background(), 5))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Доставка не найдена", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE failed_deliveries SET status`).
+			WithArgs(models.DeliveryStatusRetrying, 0, int64(5)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := q.RequeueDelivery(context.Background(), 5)
+
+		assert.True(t, errors.Is(err, ErrNotFound))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
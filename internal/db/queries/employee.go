@@ -0,0 +1,98 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// EmployeeQueriesInterface определяет интерфейс для запросов к назначениям сотрудников в ПВЗ
+type EmployeeQueriesInterface interface {
+	GetEmployeePVZ(ctx context.Context, userID string) ([]string, error)
+	SetEmployeePVZ(ctx context.Context, userID string, pvzIDs []string) error
+}
+
+// EmployeeQueries содержит методы запросов для работы с назначениями сотрудников в ПВЗ
+type EmployeeQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ EmployeeQueriesInterface = (*EmployeeQueries)(nil)
+
+// NewEmployeeQueries создает новый экземпляр EmployeeQueries
+func NewEmployeeQueries(db *db.Database) *EmployeeQueries {
+	return &EmployeeQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+	}
+}
+
+// GetEmployeePVZ получает ID ПВЗ, в которых работает сотрудник
+func (q *EmployeeQueries) GetEmployeePVZ(ctx context.Context, userID string) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "EmployeeQueries.GetEmployeePVZ")
+	defer span.End()
+
+	sql, args, err := q.sq.
+		Select("pvz_id").
+		From("employee_pvz").
+		Where(squirrel.Eq{"user_id": userID}).
+		OrderBy("pvz_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var pvzIDs []string
+	err = q.db.SelectContext(ctx, &pvzIDs, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get employee pvz: %w", err)
+	}
+
+	return pvzIDs, nil
+}
+
+// SetEmployeePVZ заменяет ПВЗ, в которых работает сотрудник.
+// Пустой список снимает ограничение по ПВЗ. Изменение попадает в токены, выданные после него
+func (q *EmployeeQueries) SetEmployeePVZ(ctx context.Context, userID string, pvzIDs []string) error {
+	ctx, span := tracing.Start(ctx, "EmployeeQueries.SetEmployeePVZ")
+	defer span.End()
+
+	deleteSQL, deleteArgs, err := q.sq.
+		Delete("employee_pvz").
+		Where(squirrel.Eq{"user_id": userID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	return q.db.WithTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, deleteSQL, deleteArgs...); err != nil {
+			return fmt.Errorf("failed to clear employee pvz: %w", err)
+		}
+		if len(pvzIDs) == 0 {
+			return nil
+		}
+
+		insert := q.sq.Insert("employee_pvz").Columns("user_id", "pvz_id")
+		for _, pvzID := range uniqueStrings(pvzIDs) {
+			insert = insert.Values(userID, pvzID)
+		}
+
+		insertSQL, insertArgs, err := insert.ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build query: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, insertSQL, insertArgs...); err != nil {
+			return fmt.Errorf("failed to set employee pvz: %w", err)
+		}
+
+		return nil
+	})
+}
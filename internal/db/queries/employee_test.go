@@ -0,0 +1,73 @@
+package queries
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db"
+)
+
+func setupEmployeeQueriesTest(t *testing.T) (*EmployeeQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &EmployeeQueries{
+		db: &db.Database{DB: sqlxDB},
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+func TestEmployeeQueries_GetEmployeePVZ(t *testing.T) {
+	q, mock := setupEmployeeQueriesTest(t)
+	userID := uuid.New().String()
+
+	mock.ExpectQuery(`SELECT pvz_id FROM employee_pvz WHERE user_id = \$1 ORDER BY pvz_id`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"pvz_id"}).AddRow("pvz-1").AddRow("pvz-2"))
+
+	pvzIDs, err := q.GetEmployeePVZ(context.Background(), userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pvz-1", "pvz-2"}, pvzIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEmployeeQueries_SetEmployeePVZ(t *testing.T) {
+	q, mock := setupEmployeeQueriesTest(t)
+	userID := uuid.New().String()
+
+	t.Run("Назначения заменяются", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM employee_pvz WHERE user_id = \$1`).
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO employee_pvz \(user_id,pvz_id\) VALUES \(\$1,\$2\),\(\$3,\$4\)`).
+			WithArgs(userID, "pvz-2", userID, "pvz-1").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		err := q.SetEmployeePVZ(context.Background(), userID, []string{"pvz-2", "pvz-1", "pvz-2"})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Пустой список снимает ограничение", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM employee_pvz WHERE user_id = \$1`).
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		err := q.SetEmployeePVZ(context.Background(), userID, nil)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,49 @@
+package queries
+
+import (
+	"errors"
+	"fmt"
+
+	"pvz-service/internal/models"
+)
+
+// ErrNotFound возвращается, когда запрошенная запись не найдена
+var ErrNotFound = errors.New("not found")
+
+// Ошибки выдачи заказов
+var (
+	// ErrOrderProductsUnavailable возвращается, если часть товаров не найдена в ПВЗ, уже выдана или входит в другой заказ
+	ErrOrderProductsUnavailable = errors.New("order products unavailable")
+	// ErrOrderIssued возвращается при повторной выдаче заказа
+	ErrOrderIssued = errors.New("order already issued")
+	// ErrOrderNotReady возвращается, если не все товары заказа находятся на хранении
+	ErrOrderNotReady = errors.New("order products are not stored")
+)
+
+// ErrReceptionHasOrders возвращается при переносе приёмки, товары которой уже входят в заказы ПВЗ
+var ErrReceptionHasOrders = errors.New("reception products belong to orders")
+
+// ErrTemplateNameTaken возвращается, если шаблон приёмки с таким названием уже есть
+var ErrTemplateNameTaken = errors.New("reception template name is taken")
+
+// ErrSupplierTaken возвращается, если поставщик с таким названием или ИНН уже есть
+var ErrSupplierTaken = errors.New("supplier name or inn is taken")
+
+// ErrSupplierInUse возвращается при удалении поставщика, у которого есть приёмки
+var ErrSupplierInUse = errors.New("supplier has receptions")
+
+// ErrArchiveRestoreConflict возвращается при восстановлении приёмки из архива, если ПВЗ, поставщик
+// или заказ, на которые она ссылается, удалены после переноса в архив
+var ErrArchiveRestoreConflict = errors.New("archived reception references deleted data")
+
+// ReceptionOpenError возвращается при создании или возобновлении приёмки, если в ПВЗ уже есть открытая приёмка
+type ReceptionOpenError struct {
+	Reception *models.Reception
+}
+
+func (e *ReceptionOpenError) Error() string {
+	return fmt.Sprintf("pvz %s already has open reception %s", e.Reception.PvzID, e.Reception.ID)
+}
+
+// ErrUnsupportedReportField возвращается, если группировка или фильтр отчёта конструктора недоступны для его сущности
+var ErrUnsupportedReportField = errors.New("report field is not supported for entity")
@@ -0,0 +1,200 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// FeatureFlagQueriesInterface определяет интерфейс для запросов к флагам функций
+type FeatureFlagQueriesInterface interface {
+	GetFlags(ctx context.Context) ([]models.FeatureFlag, error)
+	GetFlag(ctx context.Context, name string) (*models.FeatureFlag, error)
+	UpsertFlag(ctx context.Context, flag models.FeatureFlag) (*models.FeatureFlag, error)
+	SetPVZFlag(ctx context.Context, name, pvzID string, enabled bool) error
+	DeletePVZFlag(ctx context.Context, name, pvzID string) error
+}
+
+// FeatureFlagQueries содержит методы запросов для работы с флагами функций
+type FeatureFlagQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ FeatureFlagQueriesInterface = (*FeatureFlagQueries)(nil)
+
+// NewFeatureFlagQueries создает новый экземпляр FeatureFlagQueries
+func NewFeatureFlagQueries(db *db.Database) *FeatureFlagQueries {
+	return &FeatureFlagQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+	}
+}
+
+const featureFlagColumns = "name, enabled, description, updated_at"
+
+// GetFlags получает все флаги с их значениями для ПВЗ
+func (q *FeatureFlagQueries) GetFlags(ctx context.Context) ([]models.FeatureFlag, error) {
+	ctx, span := tracing.Start(ctx, "FeatureFlagQueries.GetFlags")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Select(featureFlagColumns).
+		From("feature_flags").
+		OrderBy("name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var flags []models.FeatureFlag
+	err = q.db.SelectContext(ctx, &flags, qsql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flags: %w", err)
+	}
+
+	pvzFlags, err := q.getPVZFlags(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string][]models.FeatureFlagPVZ)
+	for _, pvzFlag := range pvzFlags {
+		byName[pvzFlag.Name] = append(byName[pvzFlag.Name], pvzFlag)
+	}
+	for i := range flags {
+		flags[i].PVZ = byName[flags[i].Name]
+	}
+
+	return flags, nil
+}
+
+// GetFlag получает флаг с его значениями для ПВЗ, возвращает ErrNotFound, если флага нет
+func (q *FeatureFlagQueries) GetFlag(ctx context.Context, name string) (*models.FeatureFlag, error) {
+	ctx, span := tracing.Start(ctx, "FeatureFlagQueries.GetFlag")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Select(featureFlagColumns).
+		From("feature_flags").
+		Where(squirrel.Eq{"name": name}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var flag models.FeatureFlag
+	err = q.db.GetContext(ctx, &flag, qsql, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("feature flag %s: %w", name, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+
+	flag.PVZ, err = q.getPVZFlags(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &flag, nil
+}
+
+// getPVZFlags получает значения для ПВЗ флага name, а при пустом name - всех флагов
+func (q *FeatureFlagQueries) getPVZFlags(ctx context.Context, name string) ([]models.FeatureFlagPVZ, error) {
+	query := q.sq.
+		Select("name, pvz_id, enabled").
+		From("feature_flag_pvz").
+		OrderBy("name", "pvz_id")
+	if name != "" {
+		query = query.Where(squirrel.Eq{"name": name})
+	}
+
+	qsql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var pvzFlags []models.FeatureFlagPVZ
+	err = q.db.SelectContext(ctx, &pvzFlags, qsql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pvz feature flags: %w", err)
+	}
+
+	return pvzFlags, nil
+}
+
+// UpsertFlag создает или обновляет общее значение и описание флага
+func (q *FeatureFlagQueries) UpsertFlag(ctx context.Context, flag models.FeatureFlag) (*models.FeatureFlag, error) {
+	ctx, span := tracing.Start(ctx, "FeatureFlagQueries.UpsertFlag")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Insert("feature_flags").
+		Columns("name", "enabled", "description", "updated_at").
+		Values(flag.Name, flag.Enabled, flag.Description, squirrel.Expr("CURRENT_TIMESTAMP")).
+		Suffix("ON CONFLICT (name) DO UPDATE SET enabled = EXCLUDED.enabled, description = EXCLUDED.description, updated_at = EXCLUDED.updated_at RETURNING " + featureFlagColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var saved models.FeatureFlag
+	err = q.db.QueryRowxContext(ctx, qsql, args...).StructScan(&saved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert feature flag: %w", err)
+	}
+
+	return &saved, nil
+}
+
+// SetPVZFlag задает значение флага для ПВЗ
+func (q *FeatureFlagQueries) SetPVZFlag(ctx context.Context, name, pvzID string, enabled bool) error {
+	ctx, span := tracing.Start(ctx, "FeatureFlagQueries.SetPVZFlag")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Insert("feature_flag_pvz").
+		Columns("name", "pvz_id", "enabled").
+		Values(name, pvzID, enabled).
+		Suffix("ON CONFLICT (name, pvz_id) DO UPDATE SET enabled = EXCLUDED.enabled").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = q.db.ExecContext(ctx, qsql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to set pvz feature flag: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePVZFlag убирает значение флага для ПВЗ, после чего для него действует общее
+func (q *FeatureFlagQueries) DeletePVZFlag(ctx context.Context, name, pvzID string) error {
+	ctx, span := tracing.Start(ctx, "FeatureFlagQueries.DeletePVZFlag")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Delete("feature_flag_pvz").
+		Where(squirrel.Eq{"name": name, "pvz_id": pvzID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = q.db.ExecContext(ctx, qsql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to delete pvz feature flag: %w", err)
+	}
+
+	return nil
+}
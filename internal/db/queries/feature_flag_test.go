@@ -0,0 +1,90 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+)
+
+func setupFeatureFlagQueriesTest(t *testing.T) (*FeatureFlagQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &FeatureFlagQueries{
+		db: &db.Database{DB: sqlxDB},
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+var featureFlagRowColumns = []string{"name", "enabled", "description", "updated_at"}
+
+func TestFeatureFlagQueries_GetFlags(t *testing.T) {
+	q, mock := setupFeatureFlagQueriesTest(t)
+	now := time.Now()
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+
+	mock.ExpectQuery(`SELECT name, enabled, description, updated_at FROM feature_flags ORDER BY name`).
+		WillReturnRows(sqlmock.NewRows(featureFlagRowColumns).
+			AddRow("bulk_issue", true, "", now).
+			AddRow("returns_workflow", false, "Возвраты", now))
+	mock.ExpectQuery(`SELECT name, pvz_id, enabled FROM feature_flag_pvz ORDER BY name, pvz_id`).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "pvz_id", "enabled"}).
+			AddRow("returns_workflow", pvzID, true))
+
+	flags, err := q.GetFlags(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, flags, 2)
+	assert.Empty(t, flags[0].PVZ)
+	assert.Equal(t, []models.FeatureFlagPVZ{{Name: "returns_workflow", PvzID: pvzID, Enabled: true}}, flags[1].PVZ)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFeatureFlagQueries_GetFlag(t *testing.T) {
+	q, mock := setupFeatureFlagQueriesTest(t)
+
+	mock.ExpectQuery(`SELECT name, enabled, description, updated_at FROM feature_flags WHERE name = \$1`).
+		WithArgs("returns_workflow").
+		WillReturnRows(sqlmock.NewRows(featureFlagRowColumns))
+
+	_, err := q.GetFlag(context.Background(), "returns_workflow")
+
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFeatureFlagQueries_UpsertFlag(t *testing.T) {
+	q, mock := setupFeatureFlagQueriesTest(t)
+	now := time.Now()
+
+	mock.ExpectQuery(`INSERT INTO feature_flags \(name,enabled,description,updated_at\) VALUES \(\$1,\$2,\$3,CURRENT_TIMESTAMP\) ON CONFLICT \(name\) DO UPDATE SET .* RETURNING name, enabled, description, updated_at`).
+		WithArgs("returns_workflow", true, "Возвраты").
+		WillReturnRows(sqlmock.NewRows(featureFlagRowColumns).AddRow("returns_workflow", true, "Возвраты", now))
+
+	flag, err := q.UpsertFlag(context.Background(), models.FeatureFlag{Name: "returns_workflow", Enabled: true, Description: "Возвраты"})
+
+	assert.NoError(t, err)
+	assert.True(t, flag.Enabled)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFeatureFlagQueries_SetPVZFlag(t *testing.T) {
+	q, mock := setupFeatureFlagQueriesTest(t)
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+
+	mock.ExpectExec(`INSERT INTO feature_flag_pvz \(name,pvz_id,enabled\) VALUES \(\$1,\$2,\$3\) ON CONFLICT \(name, pvz_id\) DO UPDATE SET enabled = EXCLUDED.enabled`).
+		WithArgs("returns_workflow", pvzID, false).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, q.SetPVZFlag(context.Background(), "returns_workflow", pvzID, false))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
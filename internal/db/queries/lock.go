@@ -0,0 +1,43 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/tracing"
+)
+
+// LockQueriesInterface определяет интерфейс для распределенных блокировок на advisory locks PostgreSQL
+type LockQueriesInterface interface {
+	TryLock(ctx context.Context, key string) (bool, error)
+}
+
+// LockQueries содержит методы запросов для распределенных блокировок
+type LockQueries struct {
+	db *db.Database
+}
+
+var _ LockQueriesInterface = (*LockQueries)(nil)
+
+// NewLockQueries создает новый экземпляр LockQueries
+func NewLockQueries(db *db.Database) *LockQueries {
+	return &LockQueries{db: db}
+}
+
+// Ключ блокировки хешируется в 64-битный номер advisory lock
+var tryLockSQL = db.RegisterSQL("lock.try_xact", `SELECT pg_try_advisory_xact_lock(hashtextextended($1, 0))`)
+
+// TryLock пробует захватить блокировку key без ожидания и сообщает, удалось ли.
+// Блокировка транзакционная: вызывается в транзакции InTx и держится до ее фиксации или отката
+func (q *LockQueries) TryLock(ctx context.Context, key string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "LockQueries.TryLock")
+	defer span.End()
+
+	var locked bool
+	if err := q.db.GetContext(ctx, &locked, tryLockSQL, key); err != nil {
+		return false, fmt.Errorf("failed to acquire lock %s: %w", key, err)
+	}
+
+	return locked, nil
+}
@@ -0,0 +1,40 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db"
+)
+
+func TestLockQueries_TryLock(t *testing.T) {
+	mockDB, mock, _ := sqlmock.New()
+	q := &LockQueries{db: &db.Database{DB: sqlx.NewDb(mockDB, "sqlmock")}}
+
+	mock.ExpectQuery(`SELECT pg_try_advisory_xact_lock\(hashtextextended\(\$1, 0\)\)`).
+		WithArgs("pvz:1").
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_xact_lock"}).AddRow(true))
+	mock.ExpectQuery(`pg_try_advisory_xact_lock`).
+		WithArgs("pvz:1").
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_xact_lock"}).AddRow(false))
+	mock.ExpectQuery(`pg_try_advisory_xact_lock`).
+		WithArgs("pvz:1").
+		WillReturnError(errors.New("connection reset"))
+
+	locked, err := q.TryLock(context.Background(), "pvz:1")
+	assert.NoError(t, err)
+	assert.True(t, locked)
+
+	locked, err = q.TryLock(context.Background(), "pvz:1")
+	assert.NoError(t, err)
+	assert.False(t, locked)
+
+	_, err = q.TryLock(context.Background(), "pvz:1")
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,156 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// LoginAuditQueriesInterface определяет интерфейс для запросов к журналу входов
+type LoginAuditQueriesInterface interface {
+	AddAttempt(ctx context.Context, attempt models.LoginAttempt) (int64, error)
+	CountRecentFailures(ctx context.Context, email, ip string, since time.Time) (byEmail, byIP int, err error)
+	CountCountryLogins(ctx context.Context, userID, country string, excludeID int64) (withCountry, fromCountry int, err error)
+	ListAttempts(ctx context.Context, params models.LoginAuditQuery) ([]models.LoginAttempt, int, error)
+}
+
+// LoginAuditQueries содержит методы запросов для работы с журналом входов
+type LoginAuditQueries struct {
+	db *db.Database
+}
+
+var _ LoginAuditQueriesInterface = (*LoginAuditQueries)(nil)
+
+// NewLoginAuditQueries создает новый экземпляр LoginAuditQueries
+func NewLoginAuditQueries(db *db.Database) *LoginAuditQueries {
+	return &LoginAuditQueries{db: db}
+}
+
+const loginAttemptColumns = "id, user_id, email, success, failure_reason, ip, user_agent, country, created_at"
+
+var addLoginAttemptSQL = db.RegisterSQL("login_audit.add", `INSERT INTO login_audit
+	(user_id, email, success, failure_reason, ip, user_agent, country, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING id`)
+
+// AddAttempt записывает попытку входа в журнал и возвращает ID записи
+func (q *LoginAuditQueries) AddAttempt(ctx context.Context, attempt models.LoginAttempt) (int64, error) {
+	ctx, span := tracing.Start(ctx, "LoginAuditQueries.AddAttempt")
+	defer span.End()
+
+	var id int64
+	err := q.db.GetContext(ctx, &id, addLoginAttemptSQL,
+		attempt.UserID, attempt.Email, attempt.Success, attempt.FailureReason,
+		attempt.IP, attempt.UserAgent, attempt.Country, attempt.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add login attempt: %w", err)
+	}
+
+	return id, nil
+}
+
+var countRecentFailuresSQL = db.RegisterSQL("login_audit.count_failures", `SELECT
+		COUNT(*) FILTER (WHERE email = $1) AS by_email,
+		COUNT(*) FILTER (WHERE ip = $2) AS by_ip
+	FROM login_audit
+	WHERE NOT success AND created_at > $3 AND (email = $1 OR ip = $2)`)
+
+// CountRecentFailures считает неудачные попытки входа с email и с IP после since
+func (q *LoginAuditQueries) CountRecentFailures(ctx context.Context, email, ip string, since time.Time) (int, int, error) {
+	ctx, span := tracing.Start(ctx, "LoginAuditQueries.CountRecentFailures")
+	defer span.End()
+
+	var counts struct {
+		ByEmail int `db:"by_email"`
+		ByIP    int `db:"by_ip"`
+	}
+	if err := q.db.GetContext(ctx, &counts, countRecentFailuresSQL, email, ip, since); err != nil {
+		return 0, 0, fmt.Errorf("failed to count login failures: %w", err)
+	}
+
+	return counts.ByEmail, counts.ByIP, nil
+}
+
+var countCountryLoginsSQL = db.RegisterSQL("login_audit.count_country_logins", `SELECT
+		COUNT(*) FILTER (WHERE country IS NOT NULL) AS with_country,
+		COUNT(*) FILTER (WHERE country = $2) AS from_country
+	FROM login_audit
+	WHERE user_id = $1 AND success AND id <> $3`)
+
+// CountCountryLogins считает успешные входы пользователя, кроме записи excludeID:
+// все входы с известной страной и входы из страны country
+func (q *LoginAuditQueries) CountCountryLogins(ctx context.Context, userID, country string, excludeID int64) (int, int, error) {
+	ctx, span := tracing.Start(ctx, "LoginAuditQueries.CountCountryLogins")
+	defer span.End()
+
+	var counts struct {
+		WithCountry int `db:"with_country"`
+		FromCountry int `db:"from_country"`
+	}
+	if err := q.db.GetContext(ctx, &counts, countCountryLoginsSQL, userID, country, excludeID); err != nil {
+		return 0, 0, fmt.Errorf("failed to count country logins: %w", err)
+	}
+
+	return counts.WithCountry, counts.FromCountry, nil
+}
+
+// ListAttempts получает страницу журнала входов от новых попыток к старым и общее число попыток по фильтру
+func (q *LoginAuditQueries) ListAttempts(ctx context.Context, params models.LoginAuditQuery) ([]models.LoginAttempt, int, error) {
+	ctx, span := tracing.Start(ctx, "LoginAuditQueries.ListAttempts")
+	defer span.End()
+
+	filter := squirrel.And{}
+	if params.UserID != "" {
+		filter = append(filter, squirrel.Eq{"user_id": params.UserID})
+	}
+	if params.Email != "" {
+		filter = append(filter, squirrel.Eq{"email": params.Email})
+	}
+	if params.IP != "" {
+		filter = append(filter, squirrel.Eq{"ip": params.IP})
+	}
+	if params.Success != nil {
+		filter = append(filter, squirrel.Eq{"success": *params.Success})
+	}
+	if params.From != nil {
+		filter = append(filter, squirrel.GtOrEq{"created_at": *params.From})
+	}
+	if params.To != nil {
+		filter = append(filter, squirrel.Lt{"created_at": *params.To})
+	}
+
+	countQuery, countArgs, err := psql.Select("COUNT(*)").From("login_audit").Where(filter).ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build count query: %w", err)
+	}
+
+	var total int
+	if err := q.db.GetContext(ctx, &total, countQuery, countArgs...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count login attempts: %w", err)
+	}
+
+	query, args, err := psql.
+		Select(loginAttemptColumns).
+		From("login_audit").
+		Where(filter).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(params.Limit)).
+		Offset(uint64((params.Page - 1) * params.Limit)).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var attempts []models.LoginAttempt
+	if err := q.db.SelectContext(ctx, &attempts, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to get login attempts: %w", err)
+	}
+
+	return attempts, total, nil
+}
@@ -0,0 +1,80 @@
+package queries
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+)
+
+func setupLoginAuditQueriesTest(t *testing.T) (*LoginAuditQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &LoginAuditQueries{db: &db.Database{DB: sqlxDB}}, mock
+}
+
+func TestLoginAuditQueries_CountRecentFailures(t *testing.T) {
+	q, mock := setupLoginAuditQueriesTest(t)
+	since := time.Date(2026, 3, 2, 9, 45, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FILTER \(WHERE email = \$1\) AS by_email, COUNT\(\*\) FILTER \(WHERE ip = \$2\) AS by_ip FROM login_audit WHERE NOT success AND created_at > \$3`).
+		WithArgs("user@example.com", "192.0.2.1", since).
+		WillReturnRows(sqlmock.NewRows([]string{"by_email", "by_ip"}).AddRow(4, 1))
+
+	byEmail, byIP, err := q.CountRecentFailures(context.Background(), "user@example.com", "192.0.2.1", since)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, byEmail)
+	assert.Equal(t, 1, byIP)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginAuditQueries_ListAttempts(t *testing.T) {
+	q, mock := setupLoginAuditQueriesTest(t)
+	columns := []string{"id", "user_id", "email", "success", "failure_reason", "ip", "user_agent", "country", "created_at"}
+
+	t.Run("С фильтрами", func(t *testing.T) {
+		failed := false
+		from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+		params := models.LoginAuditQuery{Email: "user@example.com", Success: &failed, From: &from, Page: 2, Limit: 10}
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM login_audit WHERE \(email = \$1 AND success = \$2 AND created_at >= \$3\)`).
+			WithArgs("user@example.com", false, from).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+		mock.ExpectQuery(`SELECT id, user_id, email, .* FROM login_audit WHERE \(email = \$1 AND success = \$2 AND created_at >= \$3\) ORDER BY created_at DESC, id DESC LIMIT 10 OFFSET 10`).
+			WithArgs("user@example.com", false, from).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, nil, "user@example.com", false, "unknown_user", "192.0.2.1", "curl/8.0", nil, from))
+
+		attempts, total, err := q.ListAttempts(context.Background(), params)
+
+		require.NoError(t, err)
+		assert.Equal(t, 11, total)
+		require.Len(t, attempts, 1)
+		assert.Nil(t, attempts[0].UserID)
+		assert.Equal(t, models.LoginFailureUnknownUser, *attempts[0].FailureReason)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Без фильтров", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM login_audit WHERE \(1=1\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery(`FROM login_audit WHERE \(1=1\) ORDER BY created_at DESC, id DESC LIMIT 50 OFFSET 0`).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		attempts, total, err := q.ListAttempts(context.Background(), models.LoginAuditQuery{Page: 1, Limit: 50})
+
+		require.NoError(t, err)
+		assert.Zero(t, total)
+		assert.Empty(t, attempts)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,122 @@
+package queries
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// ManifestQueriesInterface определяет интерфейс для запросов к накладным приёмок
+type ManifestQueriesInterface interface {
+	ReplaceExpectedProducts(ctx context.Context, receptionID string, lines []models.ExpectedProduct) error
+	GetExpectedProducts(ctx context.Context, receptionID string) ([]models.ExpectedProduct, error)
+	RecordForcedClose(ctx context.Context, receptionID, userID, reason string, discrepancies *models.ManifestDiscrepancies) error
+}
+
+// ManifestQueries содержит методы запросов для работы с накладными
+type ManifestQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ ManifestQueriesInterface = (*ManifestQueries)(nil)
+
+// NewManifestQueries создает новый экземпляр ManifestQueries
+func NewManifestQueries(db *db.Database) *ManifestQueries {
+	return &ManifestQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+	}
+}
+
+// ReplaceExpectedProducts заменяет накладную приёмки новой в одной транзакции
+func (q *ManifestQueries) ReplaceExpectedProducts(ctx context.Context, receptionID string, lines []models.ExpectedProduct) error {
+	ctx, span := tracing.Start(ctx, "ManifestQueries.ReplaceExpectedProducts")
+	defer span.End()
+
+	deleteSQL, deleteArgs, err := q.sq.
+		Delete("expected_products").
+		Where(squirrel.Eq{"reception_id": receptionID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	insert := q.sq.
+		Insert("expected_products").
+		Columns("reception_id", "type", "barcode", "quantity")
+	for _, line := range lines {
+		var barcode string
+		if line.Barcode != nil {
+			barcode = *line.Barcode
+		}
+		insert = insert.Values(receptionID, line.Type, nullString(barcode), line.Quantity)
+	}
+
+	insertSQL, insertArgs, err := insert.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	return q.db.WithTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, deleteSQL, deleteArgs...); err != nil {
+			return fmt.Errorf("failed to delete previous manifest: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, insertSQL, insertArgs...); err != nil {
+			return fmt.Errorf("failed to insert expected products: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetExpectedProducts получает накладную приёмки
+func (q *ManifestQueries) GetExpectedProducts(ctx context.Context, receptionID string) ([]models.ExpectedProduct, error) {
+	ctx, span := tracing.Start(ctx, "ManifestQueries.GetExpectedProducts")
+	defer span.End()
+
+	query := q.sq.
+		Select("id", "reception_id", "type", "barcode", "quantity").
+		From("expected_products").
+		Where(squirrel.Eq{"reception_id": receptionID}).
+		OrderBy("created_at", "id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var lines []models.ExpectedProduct
+	err = q.db.SelectContext(ctx, &lines, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expected products: %w", err)
+	}
+
+	return lines, nil
+}
+
+var recordForcedCloseSQL = db.RegisterSQL("manifest.record_forced_close", "INSERT INTO reception_forced_closes (reception_id, closed_by, reason, discrepancies) VALUES ($1, $2, $3, $4)")
+
+// RecordForcedClose записывает закрытие приёмки с расхождениями по накладной: кто закрыл, причину
+// и расхождения на момент закрытия. Вызывается в транзакции закрытия
+func (q *ManifestQueries) RecordForcedClose(ctx context.Context, receptionID, userID, reason string, discrepancies *models.ManifestDiscrepancies) error {
+	ctx, span := tracing.Start(ctx, "ManifestQueries.RecordForcedClose")
+	defer span.End()
+
+	payload, err := json.Marshal(discrepancies)
+	if err != nil {
+		return fmt.Errorf("failed to encode discrepancies: %w", err)
+	}
+
+	if _, err := q.db.ExecContext(ctx, recordForcedCloseSQL, receptionID, nullString(userID), reason, payload); err != nil {
+		return fmt.Errorf("failed to record forced close: %w", err)
+	}
+
+	return nil
+}
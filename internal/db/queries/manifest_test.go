@@ -0,0 +1,101 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+)
+
+func setupManifestQueriesTest(t *testing.T) (*ManifestQueries, sqlmock.Sqlmock) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Ошибка при создании mock-базы данных: %v", err)
+	}
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &ManifestQueries{
+		db: &db.Database{DB: sqlxDB},
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+func TestManifestQueries_ReplaceExpectedProducts(t *testing.T) {
+	q, mock := setupManifestQueriesTest(t)
+	receptionID := "223e4567-e89b-12d3-a456-426614174000"
+	barcode := "4600000000011"
+	lines := []models.ExpectedProduct{
+		{Type: "электроника", Barcode: &barcode, Quantity: 1},
+		{Type: "обувь", Quantity: 3},
+	}
+
+	insertSQL := `INSERT INTO expected_products \(reception_id,type,barcode,quantity\) VALUES \(\$1,\$2,\$3,\$4\),\(\$5,\$6,\$7,\$8\)`
+
+	t.Run("Успешная замена накладной", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM expected_products WHERE reception_id = \$1`).
+			WithArgs(receptionID).
+			WillReturnResult(sqlmock.NewResult(0, 5))
+		mock.ExpectExec(insertSQL).
+			WithArgs(receptionID, "электроника", barcode, 1, receptionID, "обувь", nil, 3).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		err := q.ReplaceExpectedProducts(context.Background(), receptionID, lines)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Откат при ошибке вставки", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM expected_products`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(insertSQL).WillReturnError(errors.New("database error"))
+		mock.ExpectRollback()
+
+		err := q.ReplaceExpectedProducts(context.Background(), receptionID, lines)
+		assert.Error(t, err)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestManifestQueries_GetExpectedProducts(t *testing.T) {
+	q, mock := setupManifestQueriesTest(t)
+	receptionID := "223e4567-e89b-12d3-a456-426614174000"
+
+	rows := sqlmock.NewRows([]string{"id", "reception_id", "type", "barcode", "quantity"}).
+		AddRow("e1", receptionID, "одежда", nil, 2)
+	mock.ExpectQuery(`SELECT id, reception_id, type, barcode, quantity FROM expected_products WHERE reception_id = \$1 ORDER BY created_at, id`).
+		WithArgs(receptionID).
+		WillReturnRows(rows)
+
+	lines, err := q.GetExpectedProducts(context.Background(), receptionID)
+
+	assert.NoError(t, err)
+	assert.Len(t, lines, 1)
+	assert.Nil(t, lines[0].Barcode)
+	assert.Equal(t, 2, lines[0].Quantity)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestManifestQueries_RecordForcedClose(t *testing.T) {
+	q, mock := setupManifestQueriesTest(t)
+	receptionID := "223e4567-e89b-12d3-a456-426614174000"
+	userID := "323e4567-e89b-12d3-a456-426614174000"
+	discrepancies := &models.ManifestDiscrepancies{ByType: []models.TypeDiscrepancy{{Type: "обувь", Expected: 3, Actual: 2}}}
+
+	mock.ExpectExec(`INSERT INTO reception_forced_closes \(reception_id, closed_by, reason, discrepancies\) VALUES \(\$1, \$2, \$3, \$4\)`).
+		WithArgs(receptionID, userID, "недовоз, акт у водителя", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := q.RecordForcedClose(context.Background(), receptionID, userID, "недовоз, акт у водителя", discrepancies)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,98 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// ModeratorQueriesInterface определяет интерфейс для запросов к назначениям модераторов по городам
+type ModeratorQueriesInterface interface {
+	GetModeratorCities(ctx context.Context, userID string) ([]string, error)
+	SetModeratorCities(ctx context.Context, userID string, cities []string) error
+}
+
+// ModeratorQueries содержит методы запросов для работы с назначениями модераторов по городам
+type ModeratorQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ ModeratorQueriesInterface = (*ModeratorQueries)(nil)
+
+// NewModeratorQueries создает новый экземпляр ModeratorQueries
+func NewModeratorQueries(db *db.Database) *ModeratorQueries {
+	return &ModeratorQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+	}
+}
+
+// GetModeratorCities получает города, назначенные модератору
+func (q *ModeratorQueries) GetModeratorCities(ctx context.Context, userID string) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "ModeratorQueries.GetModeratorCities")
+	defer span.End()
+
+	sql, args, err := q.sq.
+		Select("city").
+		From("moderator_cities").
+		Where(squirrel.Eq{"user_id": userID}).
+		OrderBy("city").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var cities []string
+	err = q.db.SelectContext(ctx, &cities, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get moderator cities: %w", err)
+	}
+
+	return cities, nil
+}
+
+// SetModeratorCities заменяет города, назначенные модератору.
+// Пустой список снимает ограничение по городам
+func (q *ModeratorQueries) SetModeratorCities(ctx context.Context, userID string, cities []string) error {
+	ctx, span := tracing.Start(ctx, "ModeratorQueries.SetModeratorCities")
+	defer span.End()
+
+	deleteSQL, deleteArgs, err := q.sq.
+		Delete("moderator_cities").
+		Where(squirrel.Eq{"user_id": userID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	return q.db.WithTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, deleteSQL, deleteArgs...); err != nil {
+			return fmt.Errorf("failed to clear moderator cities: %w", err)
+		}
+		if len(cities) == 0 {
+			return nil
+		}
+
+		insert := q.sq.Insert("moderator_cities").Columns("user_id", "city")
+		for _, city := range uniqueStrings(cities) {
+			insert = insert.Values(userID, city)
+		}
+
+		insertSQL, insertArgs, err := insert.ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build query: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, insertSQL, insertArgs...); err != nil {
+			return fmt.Errorf("failed to set moderator cities: %w", err)
+		}
+
+		return nil
+	})
+}
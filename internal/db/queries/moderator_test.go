@@ -0,0 +1,73 @@
+package queries
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db"
+)
+
+func setupModeratorQueriesTest(t *testing.T) (*ModeratorQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &ModeratorQueries{
+		db: &db.Database{DB: sqlxDB},
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+func TestModeratorQueries_GetModeratorCities(t *testing.T) {
+	q, mock := setupModeratorQueriesTest(t)
+	userID := uuid.New().String()
+
+	mock.ExpectQuery(`SELECT city FROM moderator_cities WHERE user_id = \$1 ORDER BY city`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"city"}).AddRow("Казань").AddRow("Москва"))
+
+	cities, err := q.GetModeratorCities(context.Background(), userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Казань", "Москва"}, cities)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestModeratorQueries_SetModeratorCities(t *testing.T) {
+	q, mock := setupModeratorQueriesTest(t)
+	userID := uuid.New().String()
+
+	t.Run("Назначения заменяются", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM moderator_cities WHERE user_id = \$1`).
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO moderator_cities \(user_id,city\) VALUES \(\$1,\$2\),\(\$3,\$4\)`).
+			WithArgs(userID, "Москва", userID, "Казань").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		err := q.SetModeratorCities(context.Background(), userID, []string{"Москва", "Казань", "Москва"})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Пустой список снимает ограничение", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM moderator_cities WHERE user_id = \$1`).
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		err := q.SetModeratorCities(context.Background(), userID, nil)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,267 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// OrderQueriesInterface определяет интерфейс для запросов к заказам покупателей
+type OrderQueriesInterface interface {
+	CreateOrder(ctx context.Context, order models.Order, productIDs []string) (*models.Order, error)
+	GetOrder(ctx context.Context, orderID string) (*models.Order, error)
+	GetOrderByNumber(ctx context.Context, orderNumber string) (*models.Order, error)
+	GetOrderProducts(ctx context.Context, orderID string) ([]models.Product, error)
+	IssueOrder(ctx context.Context, orderID, employeeID string) (*models.Order, error)
+}
+
+// OrderQueries содержит методы запросов для работы с заказами покупателей
+type OrderQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+
+	// statusShadow - переходный период переноса статуса товаров в product_status
+	statusShadow *db.Shadow
+}
+
+var _ OrderQueriesInterface = (*OrderQueries)(nil)
+
+// NewOrderQueries создает новый экземпляр OrderQueries
+func NewOrderQueries(db *db.Database) *OrderQueries {
+	return &OrderQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+
+		statusShadow: db.Shadow(productStatusMigration),
+	}
+}
+
+const orderColumns = "id, order_number, recipient_phone, pvz_id, created_at, issued_at, issued_by"
+
+// CreateOrder создает заказ и привязывает к нему товары ПВЗ.
+// Возвращает ErrOrderProductsUnavailable, если хотя бы один товар нельзя включить в заказ
+func (q *OrderQueries) CreateOrder(ctx context.Context, order models.Order, productIDs []string) (*models.Order, error) {
+	ctx, span := tracing.Start(ctx, "OrderQueries.CreateOrder")
+	defer span.End()
+
+	ids := uniqueStrings(productIDs)
+
+	orderSQL, orderArgs, err := q.sq.
+		Insert("customer_orders").
+		Columns("order_number", "recipient_phone", "pvz_id").
+		Values(order.OrderNumber, order.RecipientPhone, order.PvzID).
+		Suffix("RETURNING " + orderColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var created models.Order
+	err = q.db.WithTx(ctx, func(tx *sqlx.Tx) error {
+		if err := tx.QueryRowxContext(ctx, orderSQL, orderArgs...).StructScan(&created); err != nil {
+			return fmt.Errorf("failed to create order: %w", err)
+		}
+
+		// Товар должен находиться в ПВЗ заказа, не быть выданным и не входить в другой заказ
+		linkSQL, linkArgs, err := q.sq.
+			Update("product").
+			Set("order_id", created.ID).
+			Where(squirrel.Eq{"id": ids, "order_id": nil}).
+			Where(squirrel.NotEq{"status": models.ProductLifecycleIssued}).
+			Where("reception_id IN (SELECT id FROM reception WHERE pvz_id = ?)", order.PvzID).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build query: %w", err)
+		}
+
+		result, err := tx.ExecContext(ctx, linkSQL, linkArgs...)
+		if err != nil {
+			return fmt.Errorf("failed to link order products: %w", err)
+		}
+		linked, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if linked != int64(len(ids)) {
+			return ErrOrderProductsUnavailable
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// GetOrder получает заказ по ID, возвращает ErrNotFound, если заказа нет
+func (q *OrderQueries) GetOrder(ctx context.Context, orderID string) (*models.Order, error) {
+	ctx, span := tracing.Start(ctx, "OrderQueries.GetOrder")
+	defer span.End()
+
+	return q.getOrder(ctx, squirrel.Eq{"id": orderID})
+}
+
+// GetOrderByNumber получает заказ по номеру, возвращает ErrNotFound, если заказа нет
+func (q *OrderQueries) GetOrderByNumber(ctx context.Context, orderNumber string) (*models.Order, error) {
+	ctx, span := tracing.Start(ctx, "OrderQueries.GetOrderByNumber")
+	defer span.End()
+
+	return q.getOrder(ctx, squirrel.Eq{"order_number": orderNumber})
+}
+
+// getOrder получает один заказ по условию
+func (q *OrderQueries) getOrder(ctx context.Context, where squirrel.Eq) (*models.Order, error) {
+	qsql, args, err := q.sq.
+		Select(orderColumns).
+		From("customer_orders").
+		Where(where).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var order models.Order
+	err = q.db.GetContext(ctx, &order, qsql, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("order: %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	return &order, nil
+}
+
+// GetOrderProducts получает товары заказа
+func (q *OrderQueries) GetOrderProducts(ctx context.Context, orderID string) ([]models.Product, error) {
+	ctx, span := tracing.Start(ctx, "OrderQueries.GetOrderProducts")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Select("id", "datetime", "type", "reception_id", "barcode").
+		From("product").
+		Where(squirrel.Eq{"order_id": orderID}).
+		OrderBy("datetime", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var products []models.Product
+	err = q.db.SelectContext(ctx, &products, qsql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order products: %w", err)
+	}
+
+	return products, nil
+}
+
+// IssueOrder выдает все товары заказа получателю от имени сотрудника.
+// Возвращает ErrNotFound, ErrOrderIssued или ErrOrderNotReady, если выдать заказ нельзя
+func (q *OrderQueries) IssueOrder(ctx context.Context, orderID, employeeID string) (*models.Order, error) {
+	ctx, span := tracing.Start(ctx, "OrderQueries.IssueOrder")
+	defer span.End()
+
+	lockSQL, lockArgs, err := q.sq.
+		Select(orderColumns).
+		From("customer_orders").
+		Where(squirrel.Eq{"id": orderID}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	pendingSQL, pendingArgs, err := q.sq.
+		Select("COUNT(*)").
+		From("product").
+		Where(squirrel.Eq{"order_id": orderID}).
+		Where(squirrel.NotEq{"status": models.ProductLifecycleStored}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	productsSQL, productsArgs, err := q.sq.
+		Update("product").
+		Set("status", models.ProductLifecycleIssued).
+		Set("issued_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Set("issued_by", employeeID).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{"order_id": orderID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	orderSQL, orderArgs, err := q.sq.
+		Update("customer_orders").
+		Set("issued_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Set("issued_by", employeeID).
+		Where(squirrel.Eq{"id": orderID}).
+		Suffix("RETURNING " + orderColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var issued models.Order
+	err = q.db.WithTx(ctx, func(tx *sqlx.Tx) error {
+		// Блокируем заказ, чтобы параллельная выдача дождалась завершения транзакции
+		var order models.Order
+		if err := tx.QueryRowxContext(ctx, lockSQL, lockArgs...).StructScan(&order); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("order: %w", ErrNotFound)
+			}
+			return fmt.Errorf("failed to lock order: %w", err)
+		}
+		if order.IssuedAt != nil {
+			return ErrOrderIssued
+		}
+
+		var pending int
+		if err := tx.QueryRowxContext(ctx, pendingSQL, pendingArgs...).Scan(&pending); err != nil {
+			return fmt.Errorf("failed to check order products: %w", err)
+		}
+		if pending > 0 {
+			return ErrOrderNotReady
+		}
+
+		if _, err := tx.ExecContext(ctx, productsSQL, productsArgs...); err != nil {
+			return fmt.Errorf("failed to issue order products: %w", err)
+		}
+		if err := tx.QueryRowxContext(ctx, orderSQL, orderArgs...).StructScan(&issued); err != nil {
+			return fmt.Errorf("failed to issue order: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	syncProductStatus(ctx, q.db, q.statusShadow, "order_id", []string{issued.ID})
+
+	return &issued, nil
+}
+
+// uniqueStrings возвращает значения без повторов в исходном порядке
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		unique = append(unique, value)
+	}
+	return unique
+}
@@ -0,0 +1,140 @@
+package queries
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+)
+
+func setupOrderQueriesTest(t *testing.T) (*OrderQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &OrderQueries{
+		db: &db.Database{DB: sqlxDB},
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+var orderRowColumns = []string{"id", "order_number", "recipient_phone", "pvz_id", "created_at", "issued_at", "issued_by"}
+
+func TestOrderQueries_CreateOrder(t *testing.T) {
+	q, mock := setupOrderQueriesTest(t)
+
+	orderID := uuid.New().String()
+	pvzID := uuid.New().String()
+	productIDs := []string{uuid.New().String(), uuid.New().String()}
+	order := models.Order{OrderNumber: "A-100", RecipientPhone: "+79990000000", PvzID: pvzID}
+
+	t.Run("Заказ создается вместе с привязкой товаров", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO customer_orders \(order_number,recipient_phone,pvz_id\) VALUES \(\$1,\$2,\$3\) RETURNING`).
+			WithArgs("A-100", "+79990000000", pvzID).
+			WillReturnRows(sqlmock.NewRows(orderRowColumns).
+				AddRow(orderID, "A-100", "+79990000000", pvzID, time.Now(), nil, nil))
+		mock.ExpectExec(`UPDATE product SET order_id = \$1 WHERE id IN \(\$2,\$3\) AND order_id IS NULL AND status <> \$4 AND reception_id IN \(SELECT id FROM reception WHERE pvz_id = \$5\)`).
+			WithArgs(orderID, productIDs[0], productIDs[1], models.ProductLifecycleIssued, pvzID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		created, err := q.CreateOrder(context.Background(), order, append(productIDs, productIDs[0]))
+
+		assert.NoError(t, err)
+		assert.Equal(t, orderID, created.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Недоступный товар откатывает заказ", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO customer_orders`).
+			WillReturnRows(sqlmock.NewRows(orderRowColumns).
+				AddRow(orderID, "A-100", "+79990000000", pvzID, time.Now(), nil, nil))
+		mock.ExpectExec(`UPDATE product SET order_id`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectRollback()
+
+		created, err := q.CreateOrder(context.Background(), order, productIDs)
+
+		assert.ErrorIs(t, err, ErrOrderProductsUnavailable)
+		assert.Nil(t, created)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOrderQueries_IssueOrder(t *testing.T) {
+	q, mock := setupOrderQueriesTest(t)
+
+	orderID := uuid.New().String()
+	pvzID := uuid.New().String()
+	employeeID := uuid.New().String()
+	lockSQL := `SELECT id, order_number, recipient_phone, pvz_id, created_at, issued_at, issued_by FROM customer_orders WHERE id = \$1 FOR UPDATE`
+	pendingSQL := `SELECT COUNT\(\*\) FROM product WHERE order_id = \$1 AND status <> \$2`
+
+	t.Run("Успешная выдача заказа", func(t *testing.T) {
+		issuedAt := time.Now()
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockSQL).
+			WithArgs(orderID).
+			WillReturnRows(sqlmock.NewRows(orderRowColumns).
+				AddRow(orderID, "A-100", "+79990000000", pvzID, time.Now(), nil, nil))
+		mock.ExpectQuery(pendingSQL).
+			WithArgs(orderID, models.ProductLifecycleStored).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(`UPDATE product SET status = \$1, issued_at = CURRENT_TIMESTAMP, issued_by = \$2, version = version \+ 1 WHERE order_id = \$3`).
+			WithArgs(models.ProductLifecycleIssued, employeeID, orderID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectQuery(`UPDATE customer_orders SET issued_at = CURRENT_TIMESTAMP, issued_by = \$1 WHERE id = \$2 RETURNING`).
+			WithArgs(employeeID, orderID).
+			WillReturnRows(sqlmock.NewRows(orderRowColumns).
+				AddRow(orderID, "A-100", "+79990000000", pvzID, time.Now(), issuedAt, employeeID))
+		mock.ExpectCommit()
+
+		order, err := q.IssueOrder(context.Background(), orderID, employeeID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, employeeID, *order.IssuedBy)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Повторная выдача заказа", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockSQL).
+			WithArgs(orderID).
+			WillReturnRows(sqlmock.NewRows(orderRowColumns).
+				AddRow(orderID, "A-100", "+79990000000", pvzID, time.Now(), time.Now(), employeeID))
+		mock.ExpectRollback()
+
+		order, err := q.IssueOrder(context.Background(), orderID, employeeID)
+
+		assert.ErrorIs(t, err, ErrOrderIssued)
+		assert.Nil(t, order)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Не все товары на хранении", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockSQL).
+			WithArgs(orderID).
+			WillReturnRows(sqlmock.NewRows(orderRowColumns).
+				AddRow(orderID, "A-100", "+79990000000", pvzID, time.Now(), nil, nil))
+		mock.ExpectQuery(pendingSQL).
+			WithArgs(orderID, models.ProductLifecycleStored).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectRollback()
+
+		order, err := q.IssueOrder(context.Background(), orderID, employeeID)
+
+		assert.ErrorIs(t, err, ErrOrderNotReady)
+		assert.Nil(t, order)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
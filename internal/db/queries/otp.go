@@ -0,0 +1,178 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// OTPQueriesInterface определяет интерфейс для запросов к одноразовым кодам входа
+type OTPQueriesInterface interface {
+	CountCodesSince(ctx context.Context, phone string, since time.Time) (int, error)
+	CreateCode(ctx context.Context, phone, codeHash string, expiresAt time.Time) error
+	GetActiveCode(ctx context.Context, phone string) (*models.OTPCode, error)
+	IncrementAttempts(ctx context.Context, codeID string, maxAttempts int) error
+	ConsumeCode(ctx context.Context, codeID string) error
+}
+
+// OTPQueries содержит методы запросов для работы с одноразовыми кодами
+type OTPQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ OTPQueriesInterface = (*OTPQueries)(nil)
+
+// NewOTPQueries создает новый экземпляр OTPQueries
+func NewOTPQueries(db *db.Database) *OTPQueries {
+	return &OTPQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+	}
+}
+
+// CountCodesSince считает коды, выданные на телефон начиная с момента since
+func (q *OTPQueries) CountCodesSince(ctx context.Context, phone string, since time.Time) (int, error) {
+	ctx, span := tracing.Start(ctx, "OTPQueries.CountCodesSince")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Select("COUNT(*)").
+		From("otp_codes").
+		Where(squirrel.Eq{"phone": phone}).
+		Where(squirrel.GtOrEq{"created_at": since}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var count int
+	err = q.db.GetContext(ctx, &count, qsql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count otp codes: %w", err)
+	}
+
+	return count, nil
+}
+
+// CreateCode сохраняет хеш нового кода входа
+func (q *OTPQueries) CreateCode(ctx context.Context, phone, codeHash string, expiresAt time.Time) error {
+	ctx, span := tracing.Start(ctx, "OTPQueries.CreateCode")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Insert("otp_codes").
+		Columns("phone", "code_hash", "expires_at").
+		Values(phone, codeHash, expiresAt).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = q.db.ExecContext(ctx, qsql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to create otp code: %w", err)
+	}
+
+	return nil
+}
+
+// GetActiveCode получает последний неиспользованный и не истекший код для телефона
+func (q *OTPQueries) GetActiveCode(ctx context.Context, phone string) (*models.OTPCode, error) {
+	ctx, span := tracing.Start(ctx, "OTPQueries.GetActiveCode")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Select("id", "phone", "code_hash", "attempts", "expires_at").
+		From("otp_codes").
+		Where(squirrel.Eq{"phone": phone, "consumed_at": nil}).
+		Where(squirrel.Expr("expires_at > CURRENT_TIMESTAMP")).
+		OrderBy("created_at DESC").
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var code models.OTPCode
+	err = q.db.GetContext(ctx, &code, qsql, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("active otp code for %s: %w", phone, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get otp code: %w", err)
+	}
+
+	return &code, nil
+}
+
+// IncrementAttempts списывает попытку ввода кода, если их меньше maxAttempts. Проверка и увеличение
+// счетчика идут одним запросом, поэтому параллельные проверки не получат больше maxAttempts попыток.
+// Если попытки исчерпаны, возвращает ErrNotFound
+func (q *OTPQueries) IncrementAttempts(ctx context.Context, codeID string, maxAttempts int) error {
+	ctx, span := tracing.Start(ctx, "OTPQueries.IncrementAttempts")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Update("otp_codes").
+		Set("attempts", squirrel.Expr("attempts + 1")).
+		Where(squirrel.Eq{"id": codeID}).
+		Where(squirrel.Lt{"attempts": maxAttempts}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, qsql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to increment otp attempts: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("otp code %s attempts: %w", codeID, ErrNotFound)
+	}
+
+	return nil
+}
+
+// ConsumeCode помечает код использованным, повторное использование возвращает ErrNotFound
+func (q *OTPQueries) ConsumeCode(ctx context.Context, codeID string) error {
+	ctx, span := tracing.Start(ctx, "OTPQueries.ConsumeCode")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Update("otp_codes").
+		Set("consumed_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"id": codeID, "consumed_at": nil}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, qsql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to consume otp code: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("otp code %s: %w", codeID, ErrNotFound)
+	}
+
+	return nil
+}
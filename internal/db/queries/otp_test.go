@@ -0,0 +1,104 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db"
+)
+
+func setupOTPQueriesTest(t *testing.T) (*OTPQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	dbInstance := &db.Database{DB: sqlxDB}
+
+	return &OTPQueries{
+		db: dbInstance,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+func TestOTPQueries_CountCodesSince(t *testing.T) {
+	q, mock := setupOTPQueriesTest(t)
+	since := time.Now().Add(-15 * time.Minute)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM otp_codes WHERE phone = \$1 AND created_at >= \$2`).
+		WithArgs("+79990001122", since).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	count, err := q.CountCodesSince(context.Background(), "+79990001122", since)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOTPQueries_GetActiveCode(t *testing.T) {
+	q, mock := setupOTPQueriesTest(t)
+
+	expectedSQL := `SELECT id, phone, code_hash, attempts, expires_at FROM otp_codes WHERE consumed_at IS NULL AND phone = \$1 AND expires_at > CURRENT_TIMESTAMP ORDER BY created_at DESC LIMIT 1`
+	t.Run("Код найден", func(t *testing.T) {
+		mock.ExpectQuery(expectedSQL).
+			WithArgs("+79990001122").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "phone", "code_hash", "attempts", "expires_at"}).
+				AddRow("code-id", "+79990001122", "hash", 1, time.Now().Add(time.Minute)))
+
+		code, err := q.GetActiveCode(context.Background(), "+79990001122")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "code-id", code.ID)
+		assert.Equal(t, 1, code.Attempts)
+	})
+
+	t.Run("Активного кода нет", func(t *testing.T) {
+		mock.ExpectQuery(expectedSQL).
+			WithArgs("+79990001122").
+			WillReturnError(sql.ErrNoRows)
+
+		code, err := q.GetActiveCode(context.Background(), "+79990001122")
+
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Nil(t, code)
+	})
+}
+
+func TestOTPQueries_ConsumeCode(t *testing.T) {
+	q, mock := setupOTPQueriesTest(t)
+
+	expectedSQL := `UPDATE otp_codes SET consumed_at = CURRENT_TIMESTAMP WHERE consumed_at IS NULL AND id = \$1`
+	t.Run("Код использован", func(t *testing.T) {
+		mock.ExpectExec(expectedSQL).
+			WithArgs("code-id").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, q.ConsumeCode(context.Background(), "code-id"))
+	})
+
+	t.Run("Код уже использован", func(t *testing.T) {
+		mock.ExpectExec(expectedSQL).
+			WithArgs("code-id").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, q.ConsumeCode(context.Background(), "code-id"), ErrNotFound)
+	})
+}
+
+func TestOTPQueries_IncrementAttempts(t *testing.T) {
+	q, mock := setupOTPQueriesTest(t)
+	expectedSQL := `UPDATE otp_codes SET attempts = attempts \+ 1 WHERE id = \$1 AND attempts < \$2`
+
+	mock.ExpectExec(expectedSQL).WithArgs("code-id", 5).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(expectedSQL).WithArgs("code-id", 5).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, q.IncrementAttempts(context.Background(), "code-id", 5))
+	// Попытки исчерпаны: счетчик не меняется
+	assert.ErrorIs(t, q.IncrementAttempts(context.Background(), "code-id", 5), ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,193 @@
+package queries
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// OutboxQueriesInterface определяет интерфейс для запросов к исходящим событиям
+type OutboxQueriesInterface interface {
+	AddEvent(ctx context.Context, pvzID, eventType string, payload interface{}) error
+	GetPendingEvents(ctx context.Context, limit int) ([]models.OutboxEvent, error)
+	MarkEventPublished(ctx context.Context, id int64) error
+	MarkEventFailed(ctx context.Context, id int64, reason string) error
+	DeletePublishedEvents(ctx context.Context, before time.Time) (int64, error)
+	GetEventsPage(ctx context.Context, from, to time.Time, afterID int64, limit int) ([]models.OutboxEvent, error)
+}
+
+// OutboxQueries содержит методы запросов для работы с исходящими событиями
+type OutboxQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ OutboxQueriesInterface = (*OutboxQueries)(nil)
+
+// NewOutboxQueries создает новый экземпляр OutboxQueries
+func NewOutboxQueries(db *db.Database) *OutboxQueries {
+	return &OutboxQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+	}
+}
+
+// AddEvent сохраняет событие для доставки. Вызывается внутри db.InTx вместе
+// с изменением, которое порождает событие, чтобы они фиксировались вместе
+func (q *OutboxQueries) AddEvent(ctx context.Context, pvzID, eventType string, payload interface{}) error {
+	ctx, span := tracing.Start(ctx, "OutboxQueries.AddEvent")
+	defer span.End()
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event payload: %w", err)
+	}
+
+	query := q.sq.
+		Insert("event_outbox").
+		Columns("pvz_id", "event_type", "payload").
+		Values(pvzID, eventType, string(body))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := q.db.ExecContext(ctx, sql, args...); err != nil {
+		return fmt.Errorf("failed to add outbox event: %w", err)
+	}
+
+	return nil
+}
+
+// GetPendingEvents получает недоставленные события в порядке записи
+func (q *OutboxQueries) GetPendingEvents(ctx context.Context, limit int) ([]models.OutboxEvent, error) {
+	ctx, span := tracing.Start(ctx, "OutboxQueries.GetPendingEvents")
+	defer span.End()
+
+	query := q.sq.
+		Select("id", "event_id", "pvz_id", "event_type", "payload", "created_at", "attempts").
+		From("event_outbox").
+		Where(squirrel.Eq{"published_at": nil}).
+		OrderBy("id").
+		Limit(uint64(limit))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var events []models.OutboxEvent
+	err = q.db.SelectContext(ctx, &events, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending outbox events: %w", err)
+	}
+
+	return events, nil
+}
+
+// MarkEventPublished отмечает событие доставленным
+func (q *OutboxQueries) MarkEventPublished(ctx context.Context, id int64) error {
+	ctx, span := tracing.Start(ctx, "OutboxQueries.MarkEventPublished")
+	defer span.End()
+
+	query := q.sq.
+		Update("event_outbox").
+		Set("published_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Set("attempts", squirrel.Expr("attempts + 1")).
+		Set("last_error", nil).
+		Where(squirrel.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := q.db.ExecContext(ctx, sql, args...); err != nil {
+		return fmt.Errorf("failed to mark outbox event published: %w", err)
+	}
+
+	return nil
+}
+
+// MarkEventFailed записывает неудачную попытку доставки, событие остается в очереди
+func (q *OutboxQueries) MarkEventFailed(ctx context.Context, id int64, reason string) error {
+	ctx, span := tracing.Start(ctx, "OutboxQueries.MarkEventFailed")
+	defer span.End()
+
+	query := q.sq.
+		Update("event_outbox").
+		Set("attempts", squirrel.Expr("attempts + 1")).
+		Set("last_error", reason).
+		Where(squirrel.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := q.db.ExecContext(ctx, sql, args...); err != nil {
+		return fmt.Errorf("failed to mark outbox event failed: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePublishedEvents удаляет события, доставленные раньше before
+func (q *OutboxQueries) DeletePublishedEvents(ctx context.Context, before time.Time) (int64, error) {
+	ctx, span := tracing.Start(ctx, "OutboxQueries.DeletePublishedEvents")
+	defer span.End()
+
+	query := q.sq.
+		Delete("event_outbox").
+		Where(squirrel.Lt{"published_at": before})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete published outbox events: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// GetEventsPage получает страницу событий, созданных в периоде [from, to), с ID больше afterID
+// в порядке записи. Выгрузка идет страницами по ID, чтобы не держать соединение с БД открытым,
+// пока клиент читает ответ
+func (q *OutboxQueries) GetEventsPage(ctx context.Context, from, to time.Time, afterID int64, limit int) ([]models.OutboxEvent, error) {
+	ctx, span := tracing.Start(ctx, "OutboxQueries.GetEventsPage")
+	defer span.End()
+
+	query := q.sq.
+		Select("id", "event_id", "pvz_id", "event_type", "payload", "created_at", "attempts").
+		From("event_outbox").
+		Where(squirrel.Gt{"id": afterID}).
+		Where(squirrel.GtOrEq{"created_at": from}).
+		Where(squirrel.Lt{"created_at": to}).
+		OrderBy("id").
+		Limit(uint64(limit))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var events []models.OutboxEvent
+	err = q.db.ReadSelectContext(ctx, &events, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outbox events page: %w", err)
+	}
+
+	return events, nil
+}
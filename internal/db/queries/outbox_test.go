@@ -0,0 +1,107 @@
+package queries
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+)
+
+func setupOutboxQueriesTest(t *testing.T) (*OutboxQueries, sqlmock.Sqlmock) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Ошибка при создании mock-базы данных: %v", err)
+	}
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &OutboxQueries{
+		db: &db.Database{DB: sqlxDB},
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+func TestOutboxQueries_AddEvent(t *testing.T) {
+	q, mock := setupOutboxQueriesTest(t)
+
+	mock.ExpectExec(`INSERT INTO event_outbox \(pvz_id,event_type,payload\) VALUES \(\$1,\$2,\$3\)`).
+		WithArgs("pvz-1", models.EventReceptionCreated, `{"id":"reception-1"}`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := q.AddEvent(context.Background(), "pvz-1", models.EventReceptionCreated, map[string]string{"id": "reception-1"})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxQueries_GetPendingEvents(t *testing.T) {
+	q, mock := setupOutboxQueriesTest(t)
+	createdAt := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT id, event_id, pvz_id, event_type, payload, created_at, attempts FROM event_outbox WHERE published_at IS NULL ORDER BY id LIMIT 100`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_id", "pvz_id", "event_type", "payload", "created_at", "attempts"}).
+			AddRow(1, "event-1", "pvz-1", models.EventProductAdded, []byte(`{"id":"product-1"}`), createdAt, 2))
+
+	events, err := q.GetPendingEvents(context.Background(), 100)
+
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "event-1", events[0].EventID)
+	assert.JSONEq(t, `{"id":"product-1"}`, string(events[0].Payload))
+	assert.Equal(t, 2, events[0].Attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxQueries_MarkEvent(t *testing.T) {
+	q, mock := setupOutboxQueriesTest(t)
+
+	mock.ExpectExec(`UPDATE event_outbox SET published_at = CURRENT_TIMESTAMP, attempts = attempts \+ 1, last_error = \$1 WHERE id = \$2`).
+		WithArgs(nil, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE event_outbox SET attempts = attempts \+ 1, last_error = \$1 WHERE id = \$2`).
+		WithArgs("broker unavailable", int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, q.MarkEventPublished(context.Background(), 1))
+	assert.NoError(t, q.MarkEventFailed(context.Background(), 2, "broker unavailable"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxQueries_DeletePublishedEvents(t *testing.T) {
+	q, mock := setupOutboxQueriesTest(t)
+	before := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(`DELETE FROM event_outbox WHERE published_at < \$1`).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	deleted, err := q.DeletePublishedEvents(context.Background(), before)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxQueries_GetEventsPage(t *testing.T) {
+	q, mock := setupOutboxQueriesTest(t)
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT id, event_id, pvz_id, event_type, payload, created_at, attempts FROM event_outbox WHERE id > \$1 AND created_at >= \$2 AND created_at < \$3 ORDER BY id LIMIT 1000`).
+		WithArgs(int64(10), from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_id", "pvz_id", "event_type", "payload", "created_at", "attempts"}).
+			AddRow(11, "event-11", "pvz-1", models.EventProductAdded, []byte(`{"id":"product-1"}`), from, 1))
+
+	events, err := q.GetEventsPage(context.Background(), from, to, 10, 1000)
+
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, int64(11), events[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,112 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// PasswordResetQueriesInterface определяет интерфейс для запросов к токенам сброса пароля
+type PasswordResetQueriesInterface interface {
+	CountTokensSince(ctx context.Context, userID string, since time.Time) (int, error)
+	CreateToken(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error
+	ConsumeToken(ctx context.Context, tokenHash string) (string, error)
+}
+
+// PasswordResetQueries содержит методы запросов для работы с токенами сброса пароля
+type PasswordResetQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ PasswordResetQueriesInterface = (*PasswordResetQueries)(nil)
+
+// NewPasswordResetQueries создает новый экземпляр PasswordResetQueries
+func NewPasswordResetQueries(db *db.Database) *PasswordResetQueries {
+	return &PasswordResetQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+	}
+}
+
+// CountTokensSince считает токены, выданные пользователю начиная с момента since
+func (q *PasswordResetQueries) CountTokensSince(ctx context.Context, userID string, since time.Time) (int, error) {
+	ctx, span := tracing.Start(ctx, "PasswordResetQueries.CountTokensSince")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Select("COUNT(*)").
+		From("password_reset_tokens").
+		Where(squirrel.Eq{"user_id": userID}).
+		Where(squirrel.GtOrEq{"created_at": since}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var count int
+	err = q.db.GetContext(ctx, &count, qsql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count password reset tokens: %w", err)
+	}
+
+	return count, nil
+}
+
+// CreateToken сохраняет хеш нового токена сброса пароля
+func (q *PasswordResetQueries) CreateToken(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error {
+	ctx, span := tracing.Start(ctx, "PasswordResetQueries.CreateToken")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Insert("password_reset_tokens").
+		Columns("user_id", "token_hash", "expires_at").
+		Values(userID, tokenHash, expiresAt).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = q.db.ExecContext(ctx, qsql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to create password reset token: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeToken отмечает токен использованным и возвращает ID пользователя.
+// Возвращает ErrNotFound, если токен неизвестен, истек или уже использован
+func (q *PasswordResetQueries) ConsumeToken(ctx context.Context, tokenHash string) (string, error) {
+	ctx, span := tracing.Start(ctx, "PasswordResetQueries.ConsumeToken")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Update("password_reset_tokens").
+		Set("used_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"token_hash": tokenHash, "used_at": nil}).
+		Where(squirrel.Expr("expires_at > CURRENT_TIMESTAMP")).
+		Suffix("RETURNING user_id").
+		ToSql()
+	if err != nil {
+		return "", fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var userID string
+	err = q.db.GetContext(ctx, &userID, qsql, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("password reset token: %w", ErrNotFound)
+		}
+		return "", fmt.Errorf("failed to consume password reset token: %w", err)
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,86 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db"
+)
+
+func setupPasswordResetQueriesTest(t *testing.T) (*PasswordResetQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &PasswordResetQueries{
+		db: &db.Database{DB: sqlxDB},
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+func TestPasswordResetQueries_CountTokensSince(t *testing.T) {
+	q, mock := setupPasswordResetQueriesTest(t)
+
+	userID := uuid.New().String()
+	since := time.Now().Add(-time.Hour)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM password_reset_tokens WHERE user_id = \$1 AND created_at >= \$2`).
+		WithArgs(userID, since).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	count, err := q.CountTokensSince(context.Background(), userID, since)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPasswordResetQueries_CreateToken(t *testing.T) {
+	q, mock := setupPasswordResetQueriesTest(t)
+
+	userID := uuid.New().String()
+	expiresAt := time.Now().Add(time.Hour)
+
+	mock.ExpectExec(`INSERT INTO password_reset_tokens \(user_id,token_hash,expires_at\) VALUES \(\$1,\$2,\$3\)`).
+		WithArgs(userID, "hash", expiresAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, q.CreateToken(context.Background(), userID, "hash", expiresAt))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPasswordResetQueries_ConsumeToken(t *testing.T) {
+	q, mock := setupPasswordResetQueriesTest(t)
+
+	userID := uuid.New().String()
+
+	t.Run("Токен используется один раз", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP WHERE token_hash = \$1 AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP RETURNING user_id`).
+			WithArgs("hash").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userID))
+
+		got, err := q.ConsumeToken(context.Background(), "hash")
+
+		assert.NoError(t, err)
+		assert.Equal(t, userID, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Истекший или использованный токен не найден", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE password_reset_tokens SET used_at`).
+			WithArgs("hash").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+		_, err := q.ConsumeToken(context.Background(), "hash")
+
+		assert.True(t, errors.Is(err, ErrNotFound))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -3,85 +3,97 @@ package queries
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
-	"time"
 
+	"pvz-service/internal/clock"
 	"pvz-service/internal/db"
 	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 )
 
 // internal/db/queries/product.go
 
 // ProductQueriesInterface определяет интерфейс для запросов к товарам
 type ProductQueriesInterface interface {
-	AddProduct(ctx context.Context, receptionID, productType string) (*models.Product, error)
+	AddProduct(ctx context.Context, receptionID, userID, productType, barcode string) (*models.Product, error)
 	GetLastProductFromReception(ctx context.Context, receptionID string) (*models.Product, error)
-	DeleteProduct(ctx context.Context, productID string) error
+	GetLastProductsFromReception(ctx context.Context, receptionID string, limit int) ([]models.Product, error)
+	GetProduct(ctx context.Context, productID string) (*models.Product, error)
+	DeleteProduct(ctx context.Context, productID, userID string) error
+	MoveProducts(ctx context.Context, fromReceptionID, toReceptionID string) (int, error)
 	GetProductsByReception(ctx context.Context, receptionID string) ([]models.Product, error)
+	StreamProductsByReception(ctx context.Context, receptionID string, limit, offset int, fn func(models.Product) error) error
+	GetProductStates(ctx context.Context, productIDs []string) ([]models.ProductState, error)
+	AddProductWithPhotos(ctx context.Context, receptionID, userID, productType, barcode string, photos []models.ProductPhoto) (*models.Product, error)
+	GetPhotosByReception(ctx context.Context, receptionID string) ([]models.ProductPhoto, error)
+	GetInventory(ctx context.Context, pvzID string) ([]models.Product, error)
+	IssueProduct(ctx context.Context, productID string) (*models.Product, error)
+	UpdateProductType(ctx context.Context, productID, authorID, productType string) (*models.ProductTypeChange, error)
+	MarkProductDamaged(ctx context.Context, productID, userID, description string, photos []models.ProductPhoto) (*models.ProductDamage, error)
+	GetReceptionDamages(ctx context.Context, receptionID string) ([]models.ProductDamage, error)
 }
 
 // ProductQueries содержит методы запросов для работы с товарами
 type ProductQueries struct {
-	db *db.Database
-	sq squirrel.StatementBuilderType
+	db    *db.Database
+	sq    squirrel.StatementBuilderType
+	clock clock.Clock
+
+	// statusShadow - переходный период переноса статуса товаров в product_status
+	statusShadow *db.Shadow
 }
 
+var _ ProductQueriesInterface = (*ProductQueries)(nil)
+
 // NewProductQueries создает новый экземпляр ProductQueries
 func NewProductQueries(db *db.Database) *ProductQueries {
 	return &ProductQueries{
-		db: db,
-		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+		db:    db,
+		sq:    squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+		clock: clock.System{},
+
+		statusShadow: db.Shadow(productStatusMigration),
 	}
 }
 
-// AddProduct добавляет товар в приёмку
-func (q *ProductQueries) AddProduct(ctx context.Context, receptionID, productType string) (*models.Product, error) {
-	// Генерируем UUID
-	id := uuid.New().String()
-	now := time.Now()
-
-	// Создаем запрос
-	query := q.sq.
-		Insert("product").
-		Columns("id", "datetime", "type", "reception_id").
-		Values(id, now, productType, receptionID).
-		Suffix("RETURNING id, datetime, type, reception_id")
+// addProductSQL - вставка товара при сканировании, самый частый запрос сервиса,
+// поэтому он подготавливается один раз вместо сборки squirrel при каждом вызове
+var addProductSQL = db.RegisterSQL("product.add", "INSERT INTO product (id,datetime,type,reception_id,barcode,created_by) VALUES ($1,$2,$3,$4,$5,$6) RETURNING id, datetime, type, reception_id, barcode")
 
-	qsql, args, err := query.ToSql()
-	log.Printf("SQL: %s, Args: %v", qsql, args)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build query: %w", err)
-	}
+// AddProduct добавляет товар в приёмку, штрихкод необязателен. userID - сотрудник, принявший товар
+func (q *ProductQueries) AddProduct(ctx context.Context, receptionID, userID, productType, barcode string) (*models.Product, error) {
+	ctx, span := tracing.Start(ctx, "ProductQueries.AddProduct")
+	defer span.End()
 
 	var product models.Product
-	err = q.db.QueryRowxContext(ctx, qsql, args...).StructScan(&product)
+	err := q.db.PreparedGetContext(ctx, &product, addProductSQL, uuid.New().String(), q.clock.Now(), productType, receptionID, nullString(barcode), nullString(userID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to add product: %w", err)
 	}
+	syncProductStatus(ctx, q.db, q.statusShadow, "id", []string{product.ID})
 
 	return &product, nil
 }
 
+var lastProductSQL = db.Register("product.get_last", psql.
+	Select("id", "datetime", "type", "reception_id").
+	From("product").
+	Where("reception_id = ?").
+	OrderBy("datetime DESC").
+	Limit(1))
+
 // GetLastProductFromReception получает последний добавленный товар в приёмку
 func (q *ProductQueries) GetLastProductFromReception(ctx context.Context, receptionID string) (*models.Product, error) {
-	query := q.sq.
-		Select("id", "datetime", "type", "reception_id").
-		From("product").
-		Where(squirrel.Eq{"reception_id": receptionID}).
-		OrderBy("datetime DESC").
-		Limit(1)
-
-	qsql, args, err := query.ToSql()
-	if err != nil {
-		return nil, fmt.Errorf("failed to build query: %w", err)
-	}
+	ctx, span := tracing.Start(ctx, "ProductQueries.GetLastProductFromReception")
+	defer span.End()
 
 	var product models.Product
-	err = q.db.QueryRowxContext(ctx, qsql, args...).StructScan(&product)
+	err := q.db.QueryRowxContext(ctx, lastProductSQL, receptionID).StructScan(&product)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("no products found in reception %s", receptionID)
@@ -92,18 +104,64 @@ func (q *ProductQueries) GetLastProductFromReception(ctx context.Context, recept
 	return &product, nil
 }
 
-// DeleteProduct удаляет товар по ID
-func (q *ProductQueries) DeleteProduct(ctx context.Context, productID string) error {
-	query := q.sq.
-		Delete("product").
-		Where(squirrel.Eq{"id": productID})
+var lastProductsSQL = db.Register("product.list_last", psql.
+	Select("id", "datetime", "type", "reception_id", "barcode").
+	From("product").
+	Where("reception_id = ?").
+	OrderBy("datetime DESC").
+	Suffix("LIMIT ?"))
 
-	sql, args, err := query.ToSql()
+// GetLastProductsFromReception получает не больше limit последних добавленных товаров приёмки
+// от последнего к первому
+func (q *ProductQueries) GetLastProductsFromReception(ctx context.Context, receptionID string, limit int) ([]models.Product, error) {
+	ctx, span := tracing.Start(ctx, "ProductQueries.GetLastProductsFromReception")
+	defer span.End()
+
+	var products []models.Product
+	if err := q.db.SelectContext(ctx, &products, lastProductsSQL, receptionID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get last products: %w", err)
+	}
+
+	return products, nil
+}
+
+var productByIDSQL = db.Register("product.get", psql.
+	Select("id", "datetime", "type", "reception_id", "barcode", "damaged").
+	From("product").
+	Where("id = ?"))
+
+// GetProduct получает товар по ID, возвращает ErrNotFound, если товара нет
+func (q *ProductQueries) GetProduct(ctx context.Context, productID string) (*models.Product, error) {
+	ctx, span := tracing.Start(ctx, "ProductQueries.GetProduct")
+	defer span.End()
+
+	var product models.Product
+	err := q.db.QueryRowxContext(ctx, productByIDSQL, productID).StructScan(&product)
 	if err != nil {
-		return fmt.Errorf("failed to build query: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("product %s: %w", productID, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
 
-	result, err := q.db.ExecContext(ctx, sql, args...)
+	return &product, nil
+}
+
+// deleteProductSQL удаляет товар и записывает в журнал удалений, кто его удалил,
+// а также кто и когда его добавил - для документа о цепочке хранения приёмки
+var deleteProductSQL = db.RegisterSQL("product.delete", `WITH deleted AS (
+		DELETE FROM product WHERE id = $1
+		RETURNING id, reception_id, type, created_by, datetime
+	)
+	INSERT INTO product_deletions (product_id, reception_id, type, deleted_by, deleted_at, added_by, added_at)
+	SELECT id, reception_id, type, $2, $3, created_by, datetime FROM deleted`)
+
+// DeleteProduct удаляет товар по ID. userID - пользователь, удаливший товар, попадает в журнал удалений
+func (q *ProductQueries) DeleteProduct(ctx context.Context, productID, userID string) error {
+	ctx, span := tracing.Start(ctx, "ProductQueries.DeleteProduct")
+	defer span.End()
+
+	result, err := q.db.ExecContext(ctx, deleteProductSQL, productID, nullString(userID), q.clock.Now())
 	if err != nil {
 		return fmt.Errorf("failed to delete product: %w", err)
 	}
@@ -120,24 +178,329 @@ func (q *ProductQueries) DeleteProduct(ctx context.Context, productID string) er
 	return nil
 }
 
+var moveProductsSQL = db.Register("product.move", psql.
+	Update("product").
+	Set("reception_id", nil).
+	Where("reception_id = ?"))
+
+// MoveProducts переносит все товары приёмки fromReceptionID в приёмку toReceptionID
+// и возвращает количество перенесенных товаров. Время добавления товаров не меняется
+func (q *ProductQueries) MoveProducts(ctx context.Context, fromReceptionID, toReceptionID string) (int, error) {
+	ctx, span := tracing.Start(ctx, "ProductQueries.MoveProducts")
+	defer span.End()
+
+	result, err := q.db.ExecContext(ctx, moveProductsSQL, toReceptionID, fromReceptionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move products: %w", err)
+	}
+
+	moved, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(moved), nil
+}
+
+var receptionProductsSQL = db.Register("product.list_by_reception", psql.
+	Select("id", "datetime", "type", "reception_id", "barcode", "damaged").
+	From("product").
+	Where("reception_id = ?").
+	OrderBy("datetime DESC"))
+
 // GetProductsByReception получает все товары для приёмки
 func (q *ProductQueries) GetProductsByReception(ctx context.Context, receptionID string) ([]models.Product, error) {
+	ctx, span := tracing.Start(ctx, "ProductQueries.GetProductsByReception")
+	defer span.End()
+
+	var products []models.Product
+	err := q.db.SelectContext(ctx, &products, receptionProductsSQL, receptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	return products, nil
+}
+
+var streamReceptionProductsSQL = db.Register("product.stream_by_reception", psql.
+	Select("id", "datetime", "type", "reception_id", "barcode", "damaged").
+	From("product").
+	Where("reception_id = ?").
+	OrderBy("datetime", "id").
+	Suffix("LIMIT ? OFFSET ?"))
+
+// StreamProductsByReception читает не больше limit товаров приёмки в порядке добавления, пропустив offset первых,
+// и передает их в fn по одному, не загружая результат в память. Ошибка fn прерывает чтение и возвращается как есть
+func (q *ProductQueries) StreamProductsByReception(ctx context.Context, receptionID string, limit, offset int, fn func(models.Product) error) error {
+	ctx, span := tracing.Start(ctx, "ProductQueries.StreamProductsByReception")
+	defer span.End()
+
+	rows, err := q.db.QueryxContext(ctx, streamReceptionProductsSQL, receptionID, limit, offset)
+	if err != nil {
+		return fmt.Errorf("failed to get products: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product models.Product
+		if err := rows.StructScan(&product); err != nil {
+			return fmt.Errorf("failed to scan product: %w", err)
+		}
+		if err := fn(product); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read products: %w", err)
+	}
+
+	return nil
+}
+
+// GetProductStates получает текущее состояние товаров по списку ID
+func (q *ProductQueries) GetProductStates(ctx context.Context, productIDs []string) ([]models.ProductState, error) {
+	ctx, span := tracing.Start(ctx, "ProductQueries.GetProductStates")
+	defer span.End()
+
 	query := q.sq.
-		Select("id", "datetime", "type", "reception_id").
-		From("product").
-		Where(squirrel.Eq{"reception_id": receptionID}).
-		OrderBy("datetime DESC")
+		Select("p.id", "p.version", "p.status", "p.reception_id", "r.status AS reception_status", "r.pvz_id").
+		From("product p").
+		Join("reception r ON r.id = p.reception_id").
+		Where(squirrel.Eq{"p.id": productIDs})
 
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
 
+	var states []models.ProductState
+	err = q.db.SelectContext(ctx, &states, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product states: %w", err)
+	}
+	if len(states) > 0 && q.statusShadow.Reads(ctx) {
+		q.compareProductStates(ctx, states)
+	}
+
+	return states, nil
+}
+
+// AddProductWithPhotos добавляет товар в приёмку вместе с фотографиями в одной транзакции
+func (q *ProductQueries) AddProductWithPhotos(ctx context.Context, receptionID, userID, productType, barcode string, photos []models.ProductPhoto) (*models.Product, error) {
+	ctx, span := tracing.Start(ctx, "ProductQueries.AddProductWithPhotos")
+	defer span.End()
+
+	id := uuid.New().String()
+
+	productSQL, productArgs, err := q.sq.
+		Insert("product").
+		Columns("id", "datetime", "type", "reception_id", "barcode", "created_by").
+		Values(id, q.clock.Now(), productType, receptionID, nullString(barcode), nullString(userID)).
+		Suffix("RETURNING id, datetime, type, reception_id, barcode").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	insert := q.sq.
+		Insert("product_photos").
+		Columns("product_id", "position", "storage_key", "url")
+	for i, photo := range photos {
+		insert = insert.Values(id, i, photo.StorageKey, photo.URL)
+	}
+
+	photosSQL, photosArgs, err := insert.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var product models.Product
+	err = q.db.WithTx(ctx, func(tx *sqlx.Tx) error {
+		if err := tx.QueryRowxContext(ctx, productSQL, productArgs...).StructScan(&product); err != nil {
+			return fmt.Errorf("failed to add product: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, photosSQL, photosArgs...); err != nil {
+			return fmt.Errorf("failed to add product photos: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	syncProductStatus(ctx, q.db, q.statusShadow, "id", []string{product.ID})
+
+	return &product, nil
+}
+
+var receptionPhotosSQL = db.Register("product_photo.list_by_reception", psql.
+	Select("pp.product_id", "pp.storage_key", "pp.url").
+	From("product_photos pp").
+	Join("product p ON p.id = pp.product_id").
+	Where("p.reception_id = ?").
+	OrderBy("pp.product_id", "pp.position"))
+
+// GetPhotosByReception получает фотографии всех товаров приёмки в порядке загрузки
+func (q *ProductQueries) GetPhotosByReception(ctx context.Context, receptionID string) ([]models.ProductPhoto, error) {
+	ctx, span := tracing.Start(ctx, "ProductQueries.GetPhotosByReception")
+	defer span.End()
+
+	var photos []models.ProductPhoto
+	err := q.db.ReadSelectContext(ctx, &photos, receptionPhotosSQL, receptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product photos: %w", err)
+	}
+
+	return photos, nil
+}
+
+var inventorySQL = db.Register("product.list_inventory", psql.
+	Select("p.id", "p.datetime", "p.type", "p.reception_id", "p.barcode").
+	From("product p").
+	Join("reception r ON r.id = p.reception_id").
+	Where("p.status = ? AND r.pvz_id = ?").
+	OrderBy("p.datetime", "p.id"))
+
+// GetInventory получает товары, находящиеся на хранении в ПВЗ.
+// Читает с основного сервера, чтобы не показывать уже выданные товары из-за отставания реплики
+func (q *ProductQueries) GetInventory(ctx context.Context, pvzID string) ([]models.Product, error) {
+	ctx, span := tracing.Start(ctx, "ProductQueries.GetInventory")
+	defer span.End()
+
 	var products []models.Product
-	err = q.db.SelectContext(ctx, &products, sql, args...)
+	err := q.db.SelectContext(ctx, &products, inventorySQL, models.ProductLifecycleStored, pvzID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get products: %w", err)
+		return nil, fmt.Errorf("failed to get inventory: %w", err)
 	}
 
 	return products, nil
 }
+
+var issueProductSQL = db.Register("product.issue", psql.
+	Update("product").
+	Set("status", nil).
+	Set("issued_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+	Set("version", squirrel.Expr("version + 1")).
+	Where("id = ? AND status = ?").
+	Suffix("RETURNING id, datetime, type, reception_id, barcode"))
+
+// IssueProduct отмечает товар, находящийся на хранении, выданным.
+// Возвращает ErrNotFound, если товара нет или он не на хранении
+func (q *ProductQueries) IssueProduct(ctx context.Context, productID string) (*models.Product, error) {
+	ctx, span := tracing.Start(ctx, "ProductQueries.IssueProduct")
+	defer span.End()
+
+	var product models.Product
+	err := q.db.QueryRowxContext(ctx, issueProductSQL, models.ProductLifecycleIssued, productID, models.ProductLifecycleStored).StructScan(&product)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("stored product %s: %w", productID, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to issue product: %w", err)
+	}
+	syncProductStatus(ctx, q.db, q.statusShadow, "id", []string{product.ID})
+
+	return &product, nil
+}
+
+// updateProductTypeSQL меняет тип товара и записывает исправление в историю одним запросом:
+// прежний тип читается под блокировкой строки, поэтому параллельные исправления
+// не теряют предыдущий тип в истории
+var updateProductTypeSQL = db.RegisterSQL("product.update_type", `WITH old AS (
+		SELECT id, type FROM product WHERE id = $1 FOR UPDATE
+	), updated AS (
+		UPDATE product p SET type = $2, version = p.version + 1
+		FROM old
+		WHERE p.id = old.id
+		RETURNING p.id, old.type AS old_type
+	)
+	INSERT INTO product_type_changes (product_id, author_id, old_type, new_type)
+	SELECT id, $3, old_type, $2 FROM updated
+	RETURNING id, product_id, author_id, old_type, new_type, created_at`)
+
+// UpdateProductType меняет тип товара и записывает исправление в историю от имени authorID.
+// Возвращает ошибку с ErrNotFound, если товара нет
+func (q *ProductQueries) UpdateProductType(ctx context.Context, productID, authorID, productType string) (*models.ProductTypeChange, error) {
+	ctx, span := tracing.Start(ctx, "ProductQueries.UpdateProductType")
+	defer span.End()
+
+	var change models.ProductTypeChange
+	err := q.db.QueryRowxContext(ctx, updateProductTypeSQL, productID, productType, authorID).StructScan(&change)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("product %s: %w", productID, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to update product type: %w", err)
+	}
+
+	return &change, nil
+}
+
+// markProductDamagedSQL отмечает товар поврежденным и увеличивает версию товара, как при исправлении типа.
+// Уже отмеченный товар не меняется
+var markProductDamagedSQL = db.RegisterSQL("product.mark_damaged", `UPDATE product
+	SET damaged = true, damage_description = $2, damaged_at = $3, damaged_by = $4, version = version + 1
+	WHERE id = $1 AND NOT damaged
+	RETURNING id AS product_id, reception_id, type, barcode, damage_description, damaged_by, damaged_at`)
+
+// MarkProductDamaged отмечает товар поврежденным и сохраняет фотографии повреждения.
+// Вызывается в транзакции вызывающего. Возвращает ошибку с ErrNotFound, если товара нет или он уже отмечен
+func (q *ProductQueries) MarkProductDamaged(ctx context.Context, productID, userID, description string, photos []models.ProductPhoto) (*models.ProductDamage, error) {
+	ctx, span := tracing.Start(ctx, "ProductQueries.MarkProductDamaged")
+	defer span.End()
+
+	var damage models.ProductDamage
+	err := q.db.QueryRowxContext(ctx, markProductDamagedSQL, productID, description, q.clock.Now(), nullString(userID)).StructScan(&damage)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("undamaged product %s: %w", productID, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to mark product damaged: %w", err)
+	}
+	if len(photos) == 0 {
+		return &damage, nil
+	}
+
+	insert := q.sq.
+		Insert("product_damage_photos").
+		Columns("product_id", "position", "storage_key", "url")
+	for i, photo := range photos {
+		insert = insert.Values(productID, i, photo.StorageKey, photo.URL)
+	}
+
+	photosSQL, photosArgs, err := insert.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+	if _, err := q.db.ExecContext(ctx, photosSQL, photosArgs...); err != nil {
+		return nil, fmt.Errorf("failed to add damage photos: %w", err)
+	}
+
+	return &damage, nil
+}
+
+var receptionDamagesSQL = db.Register("product.list_damaged_by_reception", psql.
+	Select("id AS product_id", "reception_id", "type", "barcode", "damage_description", "damaged_by", "damaged_at").
+	From("product").
+	Where("reception_id = ? AND damaged").
+	OrderBy("damaged_at", "id"))
+
+// GetReceptionDamages получает товары приёмки, отмеченные поврежденными, в порядке отметки
+func (q *ProductQueries) GetReceptionDamages(ctx context.Context, receptionID string) ([]models.ProductDamage, error) {
+	ctx, span := tracing.Start(ctx, "ProductQueries.GetReceptionDamages")
+	defer span.End()
+
+	var damages []models.ProductDamage
+	if err := q.db.SelectContext(ctx, &damages, receptionDamagesSQL, receptionID); err != nil {
+		return nil, fmt.Errorf("failed to get damaged products: %w", err)
+	}
+
+	return damages, nil
+}
+
+// nullString преобразует пустую строку в NULL для необязательных колонок
+func nullString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,207 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// ProductLimitQueriesInterface определяет интерфейс для запросов к ограничениям количества товаров по типам
+// и к вместимости ПВЗ
+type ProductLimitQueriesInterface interface {
+	GetLimits(ctx context.Context) ([]models.ProductTypeLimit, error)
+	GetLimit(ctx context.Context, productType string) (*models.ProductTypeLimit, error)
+	UpsertLimit(ctx context.Context, limit models.ProductTypeLimit) (*models.ProductTypeLimit, error)
+	DeleteLimit(ctx context.Context, productType string) error
+	CountReceptionProducts(ctx context.Context, receptionID, productType string) (int, error)
+	GetPVZOccupancy(ctx context.Context, pvzID string) (*models.PVZOccupancy, error)
+}
+
+// ProductLimitQueries содержит методы запросов для работы с ограничениями количества товаров по типам
+type ProductLimitQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ ProductLimitQueriesInterface = (*ProductLimitQueries)(nil)
+
+// NewProductLimitQueries создает новый экземпляр ProductLimitQueries
+func NewProductLimitQueries(db *db.Database) *ProductLimitQueries {
+	return &ProductLimitQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+	}
+}
+
+const productLimitColumns = "type, max_per_reception, comment, updated_at"
+
+// GetLimits получает все ограничения
+func (q *ProductLimitQueries) GetLimits(ctx context.Context) ([]models.ProductTypeLimit, error) {
+	ctx, span := tracing.Start(ctx, "ProductLimitQueries.GetLimits")
+	defer span.End()
+
+	sql, args, err := q.sq.
+		Select(productLimitColumns).
+		From("product_type_limits").
+		OrderBy("type").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var limits []models.ProductTypeLimit
+	err = q.db.SelectContext(ctx, &limits, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product type limits: %w", err)
+	}
+
+	return limits, nil
+}
+
+// GetLimit получает ограничение для типа товара, возвращает ErrNotFound, если тип не ограничен
+func (q *ProductLimitQueries) GetLimit(ctx context.Context, productType string) (*models.ProductTypeLimit, error) {
+	ctx, span := tracing.Start(ctx, "ProductLimitQueries.GetLimit")
+	defer span.End()
+
+	query, args, err := q.sq.
+		Select(productLimitColumns).
+		From("product_type_limits").
+		Where(squirrel.Eq{"type": productType}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var limit models.ProductTypeLimit
+	err = q.db.GetContext(ctx, &limit, query, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("product type limit %s: %w", productType, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product type limit: %w", err)
+	}
+
+	return &limit, nil
+}
+
+// UpsertLimit создает или обновляет ограничение для типа товара
+func (q *ProductLimitQueries) UpsertLimit(ctx context.Context, limit models.ProductTypeLimit) (*models.ProductTypeLimit, error) {
+	ctx, span := tracing.Start(ctx, "ProductLimitQueries.UpsertLimit")
+	defer span.End()
+
+	sql, args, err := q.sq.
+		Insert("product_type_limits").
+		Columns("type", "max_per_reception", "comment", "updated_at").
+		Values(limit.Type, limit.MaxPerReception, limit.Comment, squirrel.Expr("CURRENT_TIMESTAMP")).
+		Suffix("ON CONFLICT (type) DO UPDATE SET max_per_reception = EXCLUDED.max_per_reception, comment = EXCLUDED.comment, updated_at = EXCLUDED.updated_at RETURNING " + productLimitColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var saved models.ProductTypeLimit
+	err = q.db.QueryRowxContext(ctx, sql, args...).StructScan(&saved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert product type limit: %w", err)
+	}
+
+	return &saved, nil
+}
+
+// DeleteLimit удаляет ограничение, возвращает ErrNotFound, если его не было
+func (q *ProductLimitQueries) DeleteLimit(ctx context.Context, productType string) error {
+	ctx, span := tracing.Start(ctx, "ProductLimitQueries.DeleteLimit")
+	defer span.End()
+
+	sql, args, err := q.sq.
+		Delete("product_type_limits").
+		Where(squirrel.Eq{"type": productType}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to delete product type limit: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("product type limit %s: %w", productType, ErrNotFound)
+	}
+
+	return nil
+}
+
+// CountReceptionProducts считает товары типа productType в приёмке. Строка приёмки блокируется
+// до конца транзакции, чтобы параллельные добавления не превысили ограничение, поэтому метод
+// вызывается внутри транзакции вместе с добавлением товара
+func (q *ProductLimitQueries) CountReceptionProducts(ctx context.Context, receptionID, productType string) (int, error) {
+	ctx, span := tracing.Start(ctx, "ProductLimitQueries.CountReceptionProducts")
+	defer span.End()
+
+	lockSQL, lockArgs, err := q.sq.
+		Select("id").
+		From("reception").
+		Where(squirrel.Eq{"id": receptionID}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var lockedID string
+	if err := q.db.GetContext(ctx, &lockedID, lockSQL, lockArgs...); err != nil {
+		return 0, fmt.Errorf("failed to lock reception: %w", err)
+	}
+
+	countSQL, countArgs, err := q.sq.
+		Select("COUNT(*)").
+		From("product").
+		Where(squirrel.Eq{"reception_id": receptionID, "type": productType}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var count int
+	if err := q.db.GetContext(ctx, &count, countSQL, countArgs...); err != nil {
+		return 0, fmt.Errorf("failed to count reception products: %w", err)
+	}
+
+	return count, nil
+}
+
+var pvzOccupancySQL = db.Register("product_limit.pvz_occupancy", psql.
+	Select("id", "capacity", "occupancy").
+	From("pvz").
+	Where("id = ?"))
+
+// GetPVZOccupancy получает вместимость и заполненность ПВЗ. После добавления товара в той же транзакции
+// заполненность уже учитывает товар, а строка ПВЗ заблокирована триггером счетчика до конца транзакции.
+// Возвращает ошибку с ErrNotFound, если ПВЗ нет
+func (q *ProductLimitQueries) GetPVZOccupancy(ctx context.Context, pvzID string) (*models.PVZOccupancy, error) {
+	ctx, span := tracing.Start(ctx, "ProductLimitQueries.GetPVZOccupancy")
+	defer span.End()
+
+	var occupancy models.PVZOccupancy
+	if err := q.db.GetContext(ctx, &occupancy, pvzOccupancySQL, pvzID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("pvz %s: %w", pvzID, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get pvz occupancy: %w", err)
+	}
+
+	return &occupancy, nil
+}
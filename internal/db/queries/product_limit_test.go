@@ -0,0 +1,92 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+)
+
+func setupProductLimitQueriesTest(t *testing.T) (*ProductLimitQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &ProductLimitQueries{
+		db: &db.Database{DB: sqlxDB},
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+func TestProductLimitQueries_GetLimit(t *testing.T) {
+	q, mock := setupProductLimitQueriesTest(t)
+
+	t.Run("Ограничение задано", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT type, max_per_reception, comment, updated_at FROM product_type_limits WHERE type = \$1`).
+			WithArgs(models.ProductTypeElectronics).
+			WillReturnRows(sqlmock.NewRows([]string{"type", "max_per_reception", "comment", "updated_at"}).
+				AddRow(models.ProductTypeElectronics, 10, "страховка", time.Now()))
+
+		limit, err := q.GetLimit(context.Background(), models.ProductTypeElectronics)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 10, limit.MaxPerReception)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Тип не ограничен", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT .* FROM product_type_limits`).
+			WithArgs(models.ProductTypeShoes).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := q.GetLimit(context.Background(), models.ProductTypeShoes)
+
+		assert.True(t, errors.Is(err, ErrNotFound))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductLimitQueries_CountReceptionProducts(t *testing.T) {
+	q, mock := setupProductLimitQueriesTest(t)
+
+	mock.ExpectQuery(`SELECT id FROM reception WHERE id = \$1 FOR UPDATE`).
+		WithArgs("reception-uuid").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("reception-uuid"))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM product WHERE reception_id = \$1 AND type = \$2`).
+		WithArgs("reception-uuid", models.ProductTypeElectronics).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+
+	count, err := q.CountReceptionProducts(context.Background(), "reception-uuid", models.ProductTypeElectronics)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductLimitQueries_GetPVZOccupancy(t *testing.T) {
+	q, mock := setupProductLimitQueriesTest(t)
+
+	mock.ExpectQuery(`SELECT id, capacity, occupancy FROM pvz WHERE id = \$1`).
+		WithArgs("pvz-uuid").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "capacity", "occupancy"}).AddRow("pvz-uuid", 50, 51))
+
+	occupancy, err := q.GetPVZOccupancy(context.Background(), "pvz-uuid")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 50, *occupancy.Capacity)
+	assert.Equal(t, 51, occupancy.Occupancy)
+
+	mock.ExpectQuery(`FROM pvz`).WithArgs("missing").WillReturnError(sql.ErrNoRows)
+
+	_, err = q.GetPVZOccupancy(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
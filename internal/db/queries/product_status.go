@@ -0,0 +1,62 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// productStatusMigration - миграция статуса товаров из product.status в таблицу product_status
+const productStatusMigration = "product_status"
+
+// syncProductStatusSQL копирует статус товаров, отобранных по колонке %s, из product в product_status.
+// Статус читается из старой схемы, поэтому параллельные записи сходятся к последнему состоянию
+const syncProductStatusSQL = `INSERT INTO product_status (product_id, status, updated_at)
+	SELECT id, status, CURRENT_TIMESTAMP FROM product WHERE %s = ANY($1)
+	ON CONFLICT (product_id) DO UPDATE SET status = EXCLUDED.status, updated_at = EXCLUDED.updated_at`
+
+// shadowProductStatusSQL читает статусы товаров из новой схемы для сверки
+var shadowProductStatusSQL = db.RegisterSQL("product_status.get_shadow", "SELECT product_id, status FROM product_status WHERE product_id = ANY($1)")
+
+// syncProductStatus записывает в новую схему статус товаров, у которых колонка column
+// принимает одно из значений values: id, reception_id или order_id
+func syncProductStatus(ctx context.Context, database *db.Database, shadow *db.Shadow, column string, values []string) {
+	if !shadow.Writes() || len(values) == 0 {
+		return
+	}
+	database.ShadowExec(ctx, shadow, fmt.Sprintf(syncProductStatusSQL, column), pq.Array(values))
+}
+
+// compareProductStates сверяет статусы товаров, прочитанные из старой схемы, с новой
+func (q *ProductQueries) compareProductStates(ctx context.Context, states []models.ProductState) {
+	ids := make([]string, 0, len(states))
+	for _, state := range states {
+		ids = append(ids, state.ID)
+	}
+
+	var rows []struct {
+		ProductID string `db:"product_id"`
+		Status    string `db:"status"`
+	}
+	if err := q.db.SelectContext(ctx, &rows, shadowProductStatusSQL, pq.Array(ids)); err != nil {
+		q.statusShadow.Compare(ctx, "ProductQueries.GetProductStates", 0, err)
+		return
+	}
+
+	shadowStatus := make(map[string]string, len(rows))
+	for _, row := range rows {
+		shadowStatus[row.ProductID] = row.Status
+	}
+
+	diverged := 0
+	for _, state := range states {
+		if status, ok := shadowStatus[state.ID]; !ok || status != state.Status {
+			diverged++
+		}
+	}
+	q.statusShadow.Compare(ctx, "ProductQueries.GetProductStates", diverged, nil)
+}
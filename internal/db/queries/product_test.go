@@ -13,6 +13,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 
+	"pvz-service/internal/clock"
 	"pvz-service/internal/db"
 	"pvz-service/internal/models"
 )
@@ -23,8 +24,9 @@ func setupProductQueriesTest(t *testing.T) (*ProductQueries, sqlmock.Sqlmock) {
 	dbInstance := &db.Database{DB: sqlxDB}
 
 	return &ProductQueries{
-		db: dbInstance,
-		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		db:    dbInstance,
+		sq:    squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		clock: clock.Fixed(testNow),
 	}, mock
 }
 
@@ -35,17 +37,19 @@ func TestProductQueries_AddProduct(t *testing.T) {
 	productType := "электроника"
 	now := time.Now().UTC()
 
-	expectedSQL := `INSERT INTO product \(id,datetime,type,reception_id\) VALUES \(\$1,\$2,\$3,\$4\) RETURNING id, datetime, type, reception_id`
+	userID := uuid.New().String()
+
+	expectedSQL := `INSERT INTO product \(id,datetime,type,reception_id,barcode,created_by\) VALUES \(\$1,\$2,\$3,\$4,\$5,\$6\) RETURNING id, datetime, type, reception_id, barcode`
 	t.Run("Успешное добавление товара", func(t *testing.T) {
 
 		mock.ExpectQuery(expectedSQL).
-			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), productType, receptionID).
+			WithArgs(sqlmock.AnyArg(), testNow, productType, receptionID, nil, userID).
 			WillReturnRows(
 				sqlmock.NewRows([]string{"id", "datetime", "type", "reception_id"}).
 					AddRow(uuid.New().String(), now, productType, receptionID),
 			)
 
-		product, err := q.AddProduct(context.Background(), receptionID, productType)
+		product, err := q.AddProduct(context.Background(), receptionID, userID, productType, "")
 
 		assert.NoError(t, err)
 		assert.Equal(t, productType, product.Type)
@@ -54,10 +58,10 @@ func TestProductQueries_AddProduct(t *testing.T) {
 
 	t.Run("Ошибка базы данных", func(t *testing.T) {
 		mock.ExpectQuery(expectedSQL).
-			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), productType, receptionID).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), productType, receptionID, nil, userID).
 			WillReturnError(errors.New("database error"))
 
-		product, err := q.AddProduct(context.Background(), receptionID, productType)
+		product, err := q.AddProduct(context.Background(), receptionID, userID, productType, "")
 
 		assert.Error(t, err)
 		assert.Nil(t, product)
@@ -102,28 +106,80 @@ func TestProductQueries_GetLastProductFromReception(t *testing.T) {
 	})
 }
 
+func TestProductQueries_GetLastProductsFromReception(t *testing.T) {
+	q, mock := setupProductQueriesTest(t)
+	receptionID := uuid.New().String()
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT id, datetime, type, reception_id, barcode FROM product WHERE reception_id = \$1 ORDER BY datetime DESC LIMIT \$2`).
+		WithArgs(receptionID, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "datetime", "type", "reception_id", "barcode"}).
+			AddRow("p2", now, "обувь", receptionID, "4600000000028").
+			AddRow("p1", now.Add(-time.Second), "одежда", receptionID, nil))
+
+	result, err := q.GetLastProductsFromReception(context.Background(), receptionID, 2)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "p2", result[0].ID)
+	assert.Nil(t, result[1].Barcode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductQueries_GetProduct(t *testing.T) {
+	q, mock := setupProductQueriesTest(t)
+	productID := uuid.New().String()
+
+	expectedSQL := `SELECT id, datetime, type, reception_id, barcode, damaged FROM product WHERE id = \$1`
+	t.Run("Успешное получение товара", func(t *testing.T) {
+		mock.ExpectQuery(expectedSQL).
+			WithArgs(productID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "datetime", "type", "reception_id", "barcode"}).
+				AddRow(productID, time.Now(), "обувь", "r1", nil))
+
+		product, err := q.GetProduct(context.Background(), productID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "r1", product.ReceptionID)
+	})
+
+	t.Run("Товар не найден", func(t *testing.T) {
+		mock.ExpectQuery(expectedSQL).
+			WithArgs(productID).
+			WillReturnError(sql.ErrNoRows)
+
+		product, err := q.GetProduct(context.Background(), productID)
+
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Nil(t, product)
+	})
+}
+
 func TestProductQueries_DeleteProduct(t *testing.T) {
 	q, mock := setupProductQueriesTest(t)
 	productID := uuid.New().String()
 
-	expectedSQL := `DELETE FROM product WHERE id = \$1`
-	t.Run("Успешное удаление товара", func(t *testing.T) {
+	userID := uuid.New().String()
+
+	expectedSQL := `WITH deleted AS \(\s*DELETE FROM product WHERE id = \$1\s*RETURNING id, reception_id, type, created_by, datetime\s*\)\s*` +
+		`INSERT INTO product_deletions \(product_id, reception_id, type, deleted_by, deleted_at, added_by, added_at\)\s*SELECT id, reception_id, type, \$2, \$3, created_by, datetime FROM deleted`
+	t.Run("Удаление записывается в журнал", func(t *testing.T) {
 
 		mock.ExpectExec(expectedSQL).
-			WithArgs(productID).
+			WithArgs(productID, userID, testNow).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
-		err := q.DeleteProduct(context.Background(), productID)
+		err := q.DeleteProduct(context.Background(), productID, userID)
 
 		assert.NoError(t, err)
 	})
 
 	t.Run("Товар не найден", func(t *testing.T) {
 		mock.ExpectExec(expectedSQL).
-			WithArgs(productID).
+			WithArgs(productID, nil, testNow).
 			WillReturnResult(sqlmock.NewResult(0, 0))
 
-		err := q.DeleteProduct(context.Background(), productID)
+		err := q.DeleteProduct(context.Background(), productID, "")
 
 		assert.Error(t, err)
 	})
@@ -133,7 +189,7 @@ func TestProductQueries_GetProductsByReception(t *testing.T) {
 	q, mock := setupProductQueriesTest(t)
 	receptionID := uuid.New().String()
 
-	expectedSQL := `SELECT id, datetime, type, reception_id FROM product WHERE reception_id = \$1 ORDER BY datetime DESC`
+	expectedSQL := `SELECT id, datetime, type, reception_id, barcode, damaged FROM product WHERE reception_id = \$1 ORDER BY datetime DESC`
 	t.Run("Успешное получение товаров", func(t *testing.T) {
 		products := []models.Product{
 			{ID: uuid.New().String(), Datetime: time.Now(), Type: "электроника", ReceptionID: receptionID},
@@ -167,3 +223,247 @@ func TestProductQueries_GetProductsByReception(t *testing.T) {
 		assert.Nil(t, result)
 	})
 }
+
+func TestProductQueries_GetProductStates(t *testing.T) {
+	q, mock := setupProductQueriesTest(t)
+	ids := []string{uuid.New().String(), uuid.New().String()}
+
+	rows := sqlmock.NewRows([]string{"id", "version", "status", "reception_id", "reception_status", "pvz_id"}).
+		AddRow(ids[0], 2, "stored", "r1", "close", "pvz1")
+	mock.ExpectQuery(`SELECT p.id, p.version, p.status, p.reception_id, r.status AS reception_status, r.pvz_id FROM product p JOIN reception r ON r.id = p.reception_id WHERE p.id IN \(\$1,\$2\)`).
+		WithArgs(ids[0], ids[1]).
+		WillReturnRows(rows)
+
+	states, err := q.GetProductStates(context.Background(), ids)
+
+	assert.NoError(t, err)
+	assert.Len(t, states, 1)
+	assert.Equal(t, 2, states[0].Version)
+	assert.Equal(t, "close", states[0].ReceptionStatus)
+	assert.Equal(t, models.ProductLifecycleStored, states[0].Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductQueries_GetInventory(t *testing.T) {
+	q, mock := setupProductQueriesTest(t)
+	pvzID := uuid.New().String()
+
+	rows := sqlmock.NewRows([]string{"id", "datetime", "type", "reception_id", "barcode"}).
+		AddRow(uuid.New().String(), time.Now(), "обувь", "r1", nil).
+		AddRow(uuid.New().String(), time.Now(), "одежда", "r2", "4600000000001")
+	mock.ExpectQuery(`SELECT p.id, p.datetime, p.type, p.reception_id, p.barcode FROM product p JOIN reception r ON r.id = p.reception_id WHERE p.status = \$1 AND r.pvz_id = \$2 ORDER BY p.datetime, p.id`).
+		WithArgs(models.ProductLifecycleStored, pvzID).
+		WillReturnRows(rows)
+
+	products, err := q.GetInventory(context.Background(), pvzID)
+
+	assert.NoError(t, err)
+	assert.Len(t, products, 2)
+	assert.Nil(t, products[0].Barcode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductQueries_IssueProduct(t *testing.T) {
+	q, mock := setupProductQueriesTest(t)
+	productID := uuid.New().String()
+
+	expectedSQL := `UPDATE product SET status = \$1, issued_at = CURRENT_TIMESTAMP, version = version \+ 1 WHERE id = \$2 AND status = \$3 RETURNING id, datetime, type, reception_id, barcode`
+	t.Run("Успешная выдача товара", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "datetime", "type", "reception_id", "barcode"}).
+			AddRow(productID, time.Now(), "обувь", "r1", nil)
+		mock.ExpectQuery(expectedSQL).
+			WithArgs(models.ProductLifecycleIssued, productID, models.ProductLifecycleStored).
+			WillReturnRows(rows)
+
+		product, err := q.IssueProduct(context.Background(), productID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, productID, product.ID)
+	})
+
+	t.Run("Товар не на хранении", func(t *testing.T) {
+		mock.ExpectQuery(expectedSQL).
+			WithArgs(models.ProductLifecycleIssued, productID, models.ProductLifecycleStored).
+			WillReturnError(sql.ErrNoRows)
+
+		product, err := q.IssueProduct(context.Background(), productID)
+
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Nil(t, product)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductQueries_UpdateProductType(t *testing.T) {
+	q, mock := setupProductQueriesTest(t)
+	productID := uuid.New().String()
+	authorID := uuid.New().String()
+
+	expectedSQL := `WITH old AS \(\s*SELECT id, type FROM product WHERE id = \$1 FOR UPDATE.*` +
+		`UPDATE product p SET type = \$2, version = p.version \+ 1.*RETURNING p.id, old.type AS old_type.*` +
+		`INSERT INTO product_type_changes \(product_id, author_id, old_type, new_type\)\s*SELECT id, \$3, old_type, \$2 FROM updated`
+	t.Run("Успешное исправление типа", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "product_id", "author_id", "old_type", "new_type", "created_at"}).
+			AddRow("c1", productID, authorID, "обувь", "одежда", time.Now())
+		mock.ExpectQuery(expectedSQL).
+			WithArgs(productID, "одежда", authorID).
+			WillReturnRows(rows)
+
+		change, err := q.UpdateProductType(context.Background(), productID, authorID, "одежда")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "обувь", change.OldType)
+		assert.Equal(t, "одежда", change.NewType)
+	})
+
+	t.Run("Товар не найден", func(t *testing.T) {
+		mock.ExpectQuery(expectedSQL).
+			WithArgs(productID, "одежда", authorID).
+			WillReturnError(sql.ErrNoRows)
+
+		change, err := q.UpdateProductType(context.Background(), productID, authorID, "одежда")
+
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Nil(t, change)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductQueries_AddProductWithPhotos(t *testing.T) {
+	q, mock := setupProductQueriesTest(t)
+
+	receptionID := uuid.New().String()
+	productID := uuid.New().String()
+	storageKey := "products/" + receptionID + "/photo.jpg"
+	photoURL := "https://cdn.example.com/photo.jpg"
+	photos := []models.ProductPhoto{{StorageKey: &storageKey}, {URL: &photoURL}}
+
+	t.Run("Товар и фотографии добавляются в одной транзакции", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO product \(id,datetime,type,reception_id,barcode,created_by\)`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "обувь", receptionID, nil, "user-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "datetime", "type", "reception_id"}).
+				AddRow(productID, time.Now(), "обувь", receptionID))
+		mock.ExpectExec(`INSERT INTO product_photos \(product_id,position,storage_key,url\) VALUES \(\$1,\$2,\$3,\$4\),\(\$5,\$6,\$7,\$8\)`).
+			WithArgs(sqlmock.AnyArg(), 0, &storageKey, nil, sqlmock.AnyArg(), 1, nil, &photoURL).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		product, err := q.AddProductWithPhotos(context.Background(), receptionID, "user-1", "обувь", "", photos)
+
+		assert.NoError(t, err)
+		assert.Equal(t, productID, product.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Ошибка при сохранении фотографий откатывает товар", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO product`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "datetime", "type", "reception_id"}).
+				AddRow(productID, time.Now(), "обувь", receptionID))
+		mock.ExpectExec(`INSERT INTO product_photos`).
+			WillReturnError(errors.New("database error"))
+		mock.ExpectRollback()
+
+		product, err := q.AddProductWithPhotos(context.Background(), receptionID, "user-1", "обувь", "", photos)
+
+		assert.Error(t, err)
+		assert.Nil(t, product)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMoveProducts(t *testing.T) {
+	q, mock := setupProductQueriesTest(t)
+
+	mock.ExpectExec(`UPDATE product SET reception_id = \$1 WHERE reception_id = \$2`).
+		WithArgs("reception-2", "reception-1").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	moved, err := q.MoveProducts(context.Background(), "reception-1", "reception-2")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, moved)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStreamProductsByReception(t *testing.T) {
+	q, mock := setupProductQueriesTest(t)
+	receptionID := uuid.New().String()
+
+	t.Run("Товары передаются по одному", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT id, datetime, type, reception_id, barcode, damaged FROM product WHERE reception_id = \$1 ORDER BY datetime, id LIMIT \$2 OFFSET \$3`).
+			WithArgs(receptionID, 2, 4).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "datetime", "type", "reception_id", "barcode"}).
+				AddRow("p1", time.Now(), "обувь", receptionID, nil).
+				AddRow("p2", time.Now(), "одежда", receptionID, "4600000000001"))
+
+		var ids []string
+		err := q.StreamProductsByReception(context.Background(), receptionID, 2, 4, func(product models.Product) error {
+			ids = append(ids, product.ID)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"p1", "p2"}, ids)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Ошибка обработчика прерывает чтение", func(t *testing.T) {
+		stop := errors.New("client gone")
+		mock.ExpectQuery(`SELECT id, datetime, type, reception_id, barcode, damaged FROM product`).
+			WithArgs(receptionID, 10, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "datetime", "type", "reception_id", "barcode"}).
+				AddRow("p1", time.Now(), "обувь", receptionID, nil).
+				AddRow("p2", time.Now(), "одежда", receptionID, nil))
+
+		calls := 0
+		err := q.StreamProductsByReception(context.Background(), receptionID, 10, 0, func(product models.Product) error {
+			calls++
+			return stop
+		})
+
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, 1, calls)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductQueries_MarkProductDamaged(t *testing.T) {
+	q, mock := setupProductQueriesTest(t)
+	productID := uuid.New().String()
+	userID := uuid.New().String()
+	photoURL := "https://cdn.example.com/damage.jpg"
+
+	expectedSQL := `UPDATE product SET damaged = true, damage_description = \$2, damaged_at = \$3, damaged_by = \$4, version = version \+ 1 WHERE id = \$1 AND NOT damaged RETURNING id AS product_id`
+	t.Run("Товар отмечается поврежденным с фотографиями", func(t *testing.T) {
+		mock.ExpectQuery(expectedSQL).
+			WithArgs(productID, "разбит экран", testNow, userID).
+			WillReturnRows(sqlmock.NewRows([]string{"product_id", "reception_id", "type", "barcode", "damage_description", "damaged_by", "damaged_at"}).
+				AddRow(productID, "r1", "электроника", nil, "разбит экран", userID, testNow))
+		mock.ExpectExec(`INSERT INTO product_damage_photos \(product_id,position,storage_key,url\) VALUES \(\$1,\$2,\$3,\$4\)`).
+			WithArgs(productID, 0, nil, photoURL).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		damage, err := q.MarkProductDamaged(context.Background(), productID, userID, "разбит экран", []models.ProductPhoto{{URL: &photoURL}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "r1", damage.ReceptionID)
+		assert.Equal(t, "разбит экран", damage.Description)
+	})
+
+	t.Run("Товар уже отмечен", func(t *testing.T) {
+		mock.ExpectQuery(expectedSQL).
+			WithArgs(productID, "разбит экран", testNow, userID).
+			WillReturnError(sql.ErrNoRows)
+
+		damage, err := q.MarkProductDamaged(context.Background(), productID, userID, "разбит экран", nil)
+
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Nil(t, damage)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -3,48 +3,70 @@ package queries
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"pvz-service/internal/clock"
 	"pvz-service/internal/db"
 	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // PVZQueriesInterface определяет интерфейс для запросов к ПВЗ
 type PVZQueriesInterface interface {
-	CreatePVZ(ctx context.Context, city string) (*models.PVZ, error)
+	CreatePVZ(ctx context.Context, city, address, timezone string) (*models.PVZ, error)
+	GetPVZ(ctx context.Context, pvzID string) (*models.PVZ, error)
 	GetPVZList(ctx context.Context, params models.PVZListQuery) ([]models.PVZ, int, error)
+	FlagInactivePVZ(ctx context.Context, inactiveSince time.Time) (int64, error)
+	GetInactivePVZ(ctx context.Context) ([]models.InactivePVZ, error)
+	DeactivatePVZ(ctx context.Context, pvzID string) error
+	CreatePVZBatch(ctx context.Context, pvzs []models.PVZ) ([]models.PVZ, error)
+	GetPVZByAddresses(ctx context.Context, addresses []string) ([]models.PVZ, error)
+	LockPVZ(ctx context.Context, pvzID string) (*models.PVZ, error)
+	ArchivePVZ(ctx context.Context, pvzID string) (time.Time, error)
+	SetCapacity(ctx context.Context, pvzID string, capacity *int) (*models.PVZ, error)
 }
 
 // PVZQueries содержит методы запросов для работы с ПВЗ
 type PVZQueries struct {
-	db *db.Database
-	sq squirrel.StatementBuilderType
+	db    *db.Database
+	sq    squirrel.StatementBuilderType
+	clock clock.Clock
 }
 
+var _ PVZQueriesInterface = (*PVZQueries)(nil)
+
 // NewPVZQueries создает новый экземпляр PVZQueries
 func NewPVZQueries(db *db.Database) *PVZQueries {
 	return &PVZQueries{
-		db: db,
-		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+		db:    db,
+		sq:    squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+		clock: clock.System{},
 	}
 }
 
-// CreatePVZ создает новый ПВЗ
-func (q *PVZQueries) CreatePVZ(ctx context.Context, city string) (*models.PVZ, error) {
+// CreatePVZ создает новый ПВЗ, пустой часовой пояс не сохраняется
+func (q *PVZQueries) CreatePVZ(ctx context.Context, city, address, timezone string) (*models.PVZ, error) {
+	ctx, span := tracing.Start(ctx, "PVZQueries.CreatePVZ")
+	defer span.End()
+
 	// Генерируем UUID
 	id := uuid.New().String()
-	now := time.Now()
+	now := q.clock.Now()
 
 	// Создаем запрос
 	query := q.sq.
 		Insert("pvz").
-		Columns("id", "city", "registration_date").
-		Values(id, city, now).
-		Suffix("RETURNING id, city, registration_date")
+		Columns("id", "city", "address", "registration_date", "timezone").
+		Values(id, city, address, now, nullString(timezone)).
+		Suffix("RETURNING id, city, address, registration_date, timezone")
 
 	sql, args, err := query.ToSql()
 	if err != nil {
@@ -60,46 +82,119 @@ func (q *PVZQueries) CreatePVZ(ctx context.Context, city string) (*models.PVZ, e
 	return &pvz, nil
 }
 
-// GetPVZList получает список ПВЗ с фильтрацией и пагинацией
-func (q *PVZQueries) GetPVZList(ctx context.Context, params models.PVZListQuery) ([]models.PVZ, int, error) {
-	// Формируем базовый запрос
-	queryBuilder := q.sq.
-		Select("id", "registration_date", "city").
-		From("pvz")
+// CreatePVZBatch создает несколько ПВЗ одним запросом: ПВЗ создаются все или ни одного.
+// Из переданных ПВЗ используются город и адрес, созданные ПВЗ возвращаются в том же порядке
+func (q *PVZQueries) CreatePVZBatch(ctx context.Context, pvzs []models.PVZ) ([]models.PVZ, error) {
+	ctx, span := tracing.Start(ctx, "PVZQueries.CreatePVZBatch")
+	defer span.End()
 
-	// Добавляем фильтрацию по датам, если указаны
-	if params.StartDate != "" {
-		startTime, err := time.Parse(time.RFC3339, params.StartDate)
-		if err == nil {
-			queryBuilder = queryBuilder.Where(squirrel.GtOrEq{"registration_date": startTime})
-		}
+	if len(pvzs) == 0 {
+		return nil, nil
 	}
 
-	if params.EndDate != "" {
-		endTime, err := time.Parse(time.RFC3339, params.EndDate)
-		if err == nil {
-			queryBuilder = queryBuilder.Where(squirrel.LtOrEq{"registration_date": endTime})
+	now := q.clock.Now()
+	ids := make([]string, 0, len(pvzs))
+	query := q.sq.
+		Insert("pvz").
+		Columns("id", "city", "address", "registration_date").
+		Suffix("RETURNING id, city, address, registration_date")
+	for _, pvz := range pvzs {
+		id := uuid.New().String()
+		ids = append(ids, id)
+		query = query.Values(id, pvz.City, pvz.Address, now)
+	}
+
+	qsql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var created []models.PVZ
+	if err := q.db.SelectContext(ctx, &created, qsql, args...); err != nil {
+		return nil, fmt.Errorf("failed to create pvz batch: %w", err)
+	}
+
+	// Порядок строк RETURNING не гарантирован, восстанавливаем порядок по ID
+	byID := make(map[string]models.PVZ, len(created))
+	for _, pvz := range created {
+		byID[pvz.ID] = pvz
+	}
+	result := make([]models.PVZ, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, byID[id])
+	}
+
+	return result, nil
+}
+
+// GetPVZByAddresses получает ПВЗ с указанными адресами в любых городах
+func (q *PVZQueries) GetPVZByAddresses(ctx context.Context, addresses []string) ([]models.PVZ, error) {
+	ctx, span := tracing.Start(ctx, "PVZQueries.GetPVZByAddresses")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Select("id", "city", "address", "registration_date").
+		From("pvz").
+		Where("address = ANY(?)", pq.Array(addresses)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var pvzList []models.PVZ
+	if err := q.db.SelectContext(ctx, &pvzList, qsql, args...); err != nil {
+		return nil, fmt.Errorf("failed to get pvz by addresses: %w", err)
+	}
+
+	return pvzList, nil
+}
+
+// GetPVZ получает ПВЗ по ID. Возвращает ошибку с ErrNotFound, если ПВЗ нет
+func (q *PVZQueries) GetPVZ(ctx context.Context, pvzID string) (*models.PVZ, error) {
+	ctx, span := tracing.Start(ctx, "PVZQueries.GetPVZ")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Select("id", "city", "address", "registration_date", "open_reception", "timezone", "capacity", "occupancy").
+		From("pvz").
+		Where(squirrel.Eq{"id": pvzID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var pvz models.PVZ
+	err = q.db.QueryRowxContext(ctx, qsql, args...).StructScan(&pvz)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("pvz %s: %w", pvzID, ErrNotFound)
 		}
+		return nil, fmt.Errorf("failed to get pvz: %w", err)
 	}
 
-	// Создаем отдельный запрос для подсчета
+	return &pvz, nil
+}
+
+// GetPVZList получает список ПВЗ с фильтрацией и пагинацией
+func (q *PVZQueries) GetPVZList(ctx context.Context, params models.PVZListQuery) ([]models.PVZ, int, error) {
+	ctx, span := tracing.Start(ctx, "PVZQueries.GetPVZList")
+	defer span.End()
+
+	filters := pvzListFilters(params)
+
+	// Формируем базовый запрос
+	queryBuilder := q.sq.
+		Select("id", "registration_date", "city", "address", "open_reception", "timezone", "capacity", "occupancy").
+		From("pvz")
+
+	// Создаем отдельный запрос для подсчета с теми же условиями
 	countBuilder := q.sq.
 		Select("COUNT(*)").
 		From("pvz")
 
-	// Копируем те же условия WHERE из основного запроса
-	if params.StartDate != "" {
-		startTime, err := time.Parse(time.RFC3339, params.StartDate)
-		if err == nil {
-			countBuilder = countBuilder.Where(squirrel.GtOrEq{"registration_date": startTime})
-		}
-	}
-
-	if params.EndDate != "" {
-		endTime, err := time.Parse(time.RFC3339, params.EndDate)
-		if err == nil {
-			countBuilder = countBuilder.Where(squirrel.LtOrEq{"registration_date": endTime})
-		}
+	for _, filter := range filters {
+		queryBuilder = queryBuilder.Where(filter)
+		countBuilder = countBuilder.Where(filter)
 	}
 
 	countQuery, countArgs, err := countBuilder.ToSql()
@@ -110,7 +205,7 @@ func (q *PVZQueries) GetPVZList(ctx context.Context, params models.PVZListQuery)
 
 	// Получаем общее количество записей
 	var total int
-	err = q.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total)
+	err = q.db.ReadGetContext(ctx, &total, countQuery, countArgs...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count pvz: %w", err)
 	}
@@ -122,6 +217,15 @@ func (q *PVZQueries) GetPVZList(ctx context.Context, params models.PVZListQuery)
 		Limit(uint64(params.Limit)).
 		Offset(uint64(offset))
 
+	// Счетчики приёмок считаются после пагинации, только для ПВЗ страницы
+	if params.Includes(models.PVZIncludeCounts) {
+		queryBuilder = q.sq.
+			Select("pvz.*", "stats.receptions_count", "stats.products_count", "stats.last_reception_at").
+			FromSelect(queryBuilder, "pvz").
+			JoinClause(pvzStatsJoin).
+			OrderBy("pvz.registration_date DESC")
+	}
+
 	// Выполняем запрос с пагинацией
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -129,10 +233,266 @@ func (q *PVZQueries) GetPVZList(ctx context.Context, params models.PVZListQuery)
 	}
 
 	var pvzList []models.PVZ
-	err = q.db.SelectContext(ctx, &pvzList, query, args...)
+	err = q.db.ReadSelectContext(ctx, &pvzList, query, args...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get pvz list: %w", err)
 	}
 
 	return pvzList, total, nil
 }
+
+// pvzStatsJoin добавляет к ПВЗ количество приёмок и товаров в них и время последней приёмки.
+// Количество товаров берется из счетчика приёмки, без чтения таблицы товаров
+const pvzStatsJoin = `LEFT JOIN LATERAL (
+	SELECT COUNT(*) AS receptions_count, COALESCE(SUM(r.product_count), 0) AS products_count, MAX(r.datetime) AS last_reception_at
+	FROM reception r
+	WHERE r.pvz_id = pvz.id
+) stats ON TRUE`
+
+// pvzListFilters формирует условия WHERE для списка ПВЗ, архивные ПВЗ в список не попадают.
+// Открытая приёмка проверяется по признаку open_reception, остальные фильтры по приёмкам -
+// полусоединением с reception, чтобы ПВЗ с несколькими подходящими приёмками не дублировались в выдаче
+func pvzListFilters(params models.PVZListQuery) []squirrel.Sqlizer {
+	filters := []squirrel.Sqlizer{squirrel.Eq{"archived_at": nil}}
+
+	// Добавляем фильтрацию по датам, если указаны
+	if params.StartDate != "" {
+		startTime, err := time.Parse(time.RFC3339, params.StartDate)
+		if err == nil {
+			filters = append(filters, squirrel.GtOrEq{"registration_date": startTime.UTC()})
+		}
+	}
+
+	if params.EndDate != "" {
+		endTime, err := time.Parse(time.RFC3339, params.EndDate)
+		if err == nil {
+			filters = append(filters, squirrel.LtOrEq{"registration_date": endTime.UTC()})
+		}
+	}
+
+	if params.City != "" {
+		filters = append(filters, squirrel.Eq{"city": params.City})
+	}
+
+	// Статус и тег должны относиться к одной и той же приёмке
+	if params.ReceptionStatus == "in_progress" && params.Tag == "" {
+		filters = append(filters, squirrel.Eq{"open_reception": true})
+	} else if params.ReceptionStatus != "" || params.Tag != "" {
+		conditions := []string{"r.pvz_id = pvz.id"}
+		var args []interface{}
+		if params.ReceptionStatus != "" {
+			conditions = append(conditions, "r.status = ?")
+			args = append(args, params.ReceptionStatus)
+		}
+		if params.Tag != "" {
+			conditions = append(conditions, "r.tags @> ARRAY[?]::text[]")
+			args = append(args, params.Tag)
+		}
+
+		filters = append(filters, squirrel.Expr(
+			"EXISTS (SELECT 1 FROM reception r WHERE "+strings.Join(conditions, " AND ")+")",
+			args...,
+		))
+	}
+
+	return filters
+}
+
+// pvzActivityCTE вычисляет время последней активности каждого активного ПВЗ:
+// регистрация, создание приёмки или добавление товара
+const pvzActivityCTE = `WITH activity AS (
+	SELECT p.id AS pvz_id, GREATEST(p.registration_date, MAX(r.datetime), MAX(pr.datetime)) AS last_activity_at
+	FROM pvz p
+	LEFT JOIN reception r ON r.pvz_id = p.id
+	LEFT JOIN product pr ON pr.reception_id = r.id
+	WHERE p.is_active
+	GROUP BY p.id
+) `
+
+// FlagInactivePVZ отмечает ПВЗ без активности с момента inactiveSince
+// и снимает отметку с ПВЗ, в которых активность возобновилась
+func (q *PVZQueries) FlagInactivePVZ(ctx context.Context, inactiveSince time.Time) (int64, error) {
+	ctx, span := tracing.Start(ctx, "PVZQueries.FlagInactivePVZ")
+	defer span.End()
+
+	flagQuery := pvzActivityCTE + `INSERT INTO pvz_inactivity_flags (pvz_id, last_activity_at)
+	SELECT pvz_id, last_activity_at FROM activity WHERE last_activity_at < $1
+	ON CONFLICT (pvz_id) DO UPDATE SET last_activity_at = EXCLUDED.last_activity_at`
+
+	result, err := q.db.ExecContext(ctx, flagQuery, inactiveSince)
+	if err != nil {
+		return 0, fmt.Errorf("failed to flag inactive pvz: %w", err)
+	}
+
+	flagged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	unflagQuery := pvzActivityCTE + `DELETE FROM pvz_inactivity_flags f
+	USING activity a
+	WHERE f.pvz_id = a.pvz_id AND a.last_activity_at >= $1`
+
+	if _, err := q.db.ExecContext(ctx, unflagQuery, inactiveSince); err != nil {
+		return 0, fmt.Errorf("failed to unflag active pvz: %w", err)
+	}
+
+	return flagged, nil
+}
+
+// GetInactivePVZ получает ПВЗ, предложенные к деактивации
+func (q *PVZQueries) GetInactivePVZ(ctx context.Context) ([]models.InactivePVZ, error) {
+	ctx, span := tracing.Start(ctx, "PVZQueries.GetInactivePVZ")
+	defer span.End()
+
+	query := q.sq.
+		Select("p.id", "p.city", "p.address", "p.registration_date", "f.last_activity_at", "f.flagged_at").
+		From("pvz_inactivity_flags f").
+		Join("pvz p ON p.id = f.pvz_id").
+		Where(squirrel.Eq{"p.is_active": true}).
+		OrderBy("f.last_activity_at")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var inactive []models.InactivePVZ
+	err = q.db.ReadSelectContext(ctx, &inactive, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inactive pvz: %w", err)
+	}
+
+	return inactive, nil
+}
+
+// DeactivatePVZ деактивирует ПВЗ и снимает с него отметку о неактивности
+func (q *PVZQueries) DeactivatePVZ(ctx context.Context, pvzID string) error {
+	ctx, span := tracing.Start(ctx, "PVZQueries.DeactivatePVZ")
+	defer span.End()
+
+	query := q.sq.
+		Update("pvz").
+		Set("is_active", false).
+		Set("deactivated_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"id": pvzID, "is_active": true})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to deactivate pvz: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("active pvz with id %s: %w", pvzID, ErrNotFound)
+	}
+
+	cleanup, args, err := q.sq.Delete("pvz_inactivity_flags").Where(squirrel.Eq{"pvz_id": pvzID}).ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := q.db.ExecContext(ctx, cleanup, args...); err != nil {
+		return fmt.Errorf("failed to remove inactivity flag: %w", err)
+	}
+
+	return nil
+}
+
+// LockPVZ блокирует неархивный ПВЗ до конца транзакции, чтобы в нем не открылась приёмка.
+// Возвращает ошибку с ErrNotFound, если ПВЗ нет или он в архиве
+func (q *PVZQueries) LockPVZ(ctx context.Context, pvzID string) (*models.PVZ, error) {
+	ctx, span := tracing.Start(ctx, "PVZQueries.LockPVZ")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Select("id", "city", "address", "registration_date", "open_reception", "timezone", "capacity", "occupancy").
+		From("pvz").
+		Where(squirrel.Eq{"id": pvzID, "archived_at": nil}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var pvz models.PVZ
+	if err := q.db.GetContext(ctx, &pvz, qsql, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("pvz %s: %w", pvzID, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to lock pvz: %w", err)
+	}
+
+	return &pvz, nil
+}
+
+// ArchivePVZ переносит ПВЗ в архив: он деактивируется и пропадает из списков.
+// Возвращает время переноса или ошибку с ErrNotFound, если ПВЗ нет или он уже в архиве
+func (q *PVZQueries) ArchivePVZ(ctx context.Context, pvzID string) (time.Time, error) {
+	ctx, span := tracing.Start(ctx, "PVZQueries.ArchivePVZ")
+	defer span.End()
+
+	archivedAt := q.clock.Now()
+	qsql, args, err := q.sq.
+		Update("pvz").
+		Set("archived_at", archivedAt).
+		Set("is_active", false).
+		Where(squirrel.Eq{"id": pvzID, "archived_at": nil}).
+		ToSql()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, qsql, args...)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to archive pvz: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return time.Time{}, fmt.Errorf("pvz %s: %w", pvzID, ErrNotFound)
+	}
+
+	cleanup, args, err := q.sq.Delete("pvz_inactivity_flags").Where(squirrel.Eq{"pvz_id": pvzID}).ToSql()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to build query: %w", err)
+	}
+	if _, err := q.db.ExecContext(ctx, cleanup, args...); err != nil {
+		return time.Time{}, fmt.Errorf("failed to remove inactivity flag: %w", err)
+	}
+
+	return archivedAt, nil
+}
+
+var setPVZCapacitySQL = db.RegisterSQL("pvz.set_capacity", `UPDATE pvz SET capacity = $2
+	WHERE id = $1 AND archived_at IS NULL
+	RETURNING id, city, address, registration_date, open_reception, timezone, capacity, occupancy`)
+
+// SetCapacity изменяет вместимость неархивного ПВЗ, nil снимает ограничение.
+// Возвращает ошибку с ErrNotFound, если ПВЗ нет или он в архиве
+func (q *PVZQueries) SetCapacity(ctx context.Context, pvzID string, capacity *int) (*models.PVZ, error) {
+	ctx, span := tracing.Start(ctx, "PVZQueries.SetCapacity")
+	defer span.End()
+
+	var pvz models.PVZ
+	if err := q.db.QueryRowxContext(ctx, setPVZCapacitySQL, pvzID, capacity).StructScan(&pvz); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("pvz %s: %w", pvzID, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to set pvz capacity: %w", err)
+	}
+
+	return &pvz, nil
+}
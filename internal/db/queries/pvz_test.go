@@ -12,6 +12,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 
+	"pvz-service/internal/clock"
 	"pvz-service/internal/db"
 	"pvz-service/internal/models"
 )
@@ -32,8 +33,9 @@ func setupPVZQueriesTest(t *testing.T) (*PVZQueries, sqlmock.Sqlmock) {
 
 	// Создаем объект PVZQueries
 	q := &PVZQueries{
-		db: dbInstance,
-		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		db:    dbInstance,
+		sq:    squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		clock: clock.Fixed(testNow),
 	}
 
 	return q, mock
@@ -67,12 +69,12 @@ func TestGetPVZList(t *testing.T) {
 		totalCount := 2
 
 		// Настраиваем ожидание SQL-запроса для подсчета
-		expectedCountSQL := `SELECT COUNT\(\*\) FROM pvz`
+		expectedCountSQL := `SELECT COUNT\(\*\) FROM pvz WHERE archived_at IS NULL`
 		countRows := sqlmock.NewRows([]string{"count"}).AddRow(totalCount)
 		mock.ExpectQuery(expectedCountSQL).WillReturnRows(countRows)
 
 		// Настраиваем ожидание SQL-запроса для получения списка
-		expectedSQL := `SELECT id, registration_date, city FROM pvz ORDER BY registration_date DESC LIMIT 10 OFFSET 0`
+		expectedSQL := `SELECT id, registration_date, city, address, open_reception, timezone, capacity, occupancy FROM pvz WHERE archived_at IS NULL ORDER BY registration_date DESC LIMIT 10 OFFSET 0`
 		rows := sqlmock.NewRows([]string{"id", "registration_date", "city"})
 		for _, pvz := range expectedPVZs {
 			rows.AddRow(pvz.ID, pvz.RegistrationDate, pvz.City)
@@ -116,14 +118,14 @@ func TestGetPVZList(t *testing.T) {
 		endTime, _ := time.Parse(time.RFC3339, endDate)
 
 		// Настраиваем ожидание SQL-запроса для подсчета с фильтрами
-		expectedCountSQL := `SELECT COUNT\(\*\) FROM pvz WHERE registration_date >= \$1 AND registration_date <= \$2`
+		expectedCountSQL := `SELECT COUNT\(\*\) FROM pvz WHERE archived_at IS NULL AND registration_date >= \$1 AND registration_date <= \$2`
 		countRows := sqlmock.NewRows([]string{"count"}).AddRow(1)
 		mock.ExpectQuery(expectedCountSQL).
 			WithArgs(startTime, endTime).
 			WillReturnRows(countRows)
 
 		// Настраиваем ожидание SQL-запроса для получения отфильтрованного списка
-		expectedSQL := `SELECT id, registration_date, city FROM pvz WHERE registration_date >= \$1 AND registration_date <= \$2 ORDER BY registration_date DESC LIMIT 5 OFFSET 0`
+		expectedSQL := `SELECT id, registration_date, city, address, open_reception, timezone, capacity, occupancy FROM pvz WHERE archived_at IS NULL AND registration_date >= \$1 AND registration_date <= \$2 ORDER BY registration_date DESC LIMIT 5 OFFSET 0`
 
 		pvz := models.PVZ{
 			ID:               uuid.New().String(),
@@ -155,6 +157,101 @@ func TestGetPVZList(t *testing.T) {
 		assert.NoError(t, err, "Не все ожидаемые запросы были выполнены")
 	})
 
+	t.Run("Получение списка с фильтрацией по городу и статусу приёмки", func(t *testing.T) {
+		ctx := context.Background()
+		params := models.PVZListQuery{
+			Page:            1,
+			Limit:           10,
+			City:            "Казань",
+			ReceptionStatus: "in_progress",
+		}
+
+		// Открытая приёмка проверяется по признаку ПВЗ без подзапроса к приёмкам
+		expectedCountSQL := `SELECT COUNT\(\*\) FROM pvz WHERE archived_at IS NULL AND city = \$1 AND open_reception = \$2`
+		mock.ExpectQuery(expectedCountSQL).
+			WithArgs("Казань", true).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		pvzID := uuid.New().String()
+		expectedSQL := `SELECT id, registration_date, city, address, open_reception, timezone, capacity, occupancy FROM pvz WHERE archived_at IS NULL AND city = \$1 AND open_reception = \$2 ORDER BY registration_date DESC LIMIT 10 OFFSET 0`
+		mock.ExpectQuery(expectedSQL).
+			WithArgs("Казань", true).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city", "open_reception"}).
+				AddRow(pvzID, time.Date(2025, 3, 15, 12, 0, 0, 0, time.UTC), "Казань", true))
+
+		pvzList, total, err := pvzQueries.GetPVZList(ctx, params)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Len(t, pvzList, 1)
+		assert.Equal(t, pvzID, pvzList[0].ID)
+		assert.True(t, pvzList[0].OpenReception)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Статус и тег проверяются у одной приёмки", func(t *testing.T) {
+		ctx := context.Background()
+		params := models.PVZListQuery{
+			Page:            1,
+			Limit:           10,
+			ReceptionStatus: "close",
+			Tag:             "повреждена упаковка",
+		}
+
+		expectedCountSQL := `SELECT COUNT\(\*\) FROM pvz WHERE archived_at IS NULL AND EXISTS \(SELECT 1 FROM reception r WHERE r.pvz_id = pvz.id AND r.status = \$1 AND r.tags @> ARRAY\[\$2\]::text\[\]\)`
+		mock.ExpectQuery(expectedCountSQL).
+			WithArgs("close", "повреждена упаковка").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		mock.ExpectQuery(`SELECT id, registration_date, city, address, open_reception, timezone, capacity, occupancy FROM pvz WHERE archived_at IS NULL AND EXISTS`).
+			WithArgs("close", "повреждена упаковка").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}))
+
+		pvzList, total, err := pvzQueries.GetPVZList(ctx, params)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, total)
+		assert.Empty(t, pvzList)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Счетчики приёмок для ПВЗ страницы", func(t *testing.T) {
+		ctx := context.Background()
+		params := models.PVZListQuery{
+			Page:    2,
+			Limit:   10,
+			City:    "Казань",
+			Include: "counts",
+		}
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM pvz WHERE archived_at IS NULL AND city = \$1`).
+			WithArgs("Казань").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+
+		// Счетчики считаются по уже выбранной странице ПВЗ
+		expectedSQL := `SELECT pvz.\*, stats.receptions_count, stats.products_count, stats.last_reception_at ` +
+			`FROM \(SELECT id, registration_date, city, address, open_reception, timezone, capacity, occupancy FROM pvz WHERE archived_at IS NULL AND city = \$1 ORDER BY registration_date DESC LIMIT 10 OFFSET 10\) AS pvz ` +
+			`LEFT JOIN LATERAL \(.+FROM reception r\s+WHERE r.pvz_id = pvz.id\s+\) stats ON TRUE ORDER BY pvz.registration_date DESC`
+		lastReceptionAt := time.Date(2025, 3, 20, 9, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(expectedSQL).
+			WithArgs("Казань").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city", "receptions_count", "products_count", "last_reception_at"}).
+				AddRow(uuid.New().String(), time.Date(2025, 3, 15, 12, 0, 0, 0, time.UTC), "Казань", 4, 57, lastReceptionAt).
+				AddRow(uuid.New().String(), time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC), "Казань", 0, 0, nil))
+
+		pvzList, total, err := pvzQueries.GetPVZList(ctx, params)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 11, total)
+		assert.Len(t, pvzList, 2)
+		assert.Equal(t, 4, *pvzList[0].ReceptionsCount)
+		assert.Equal(t, 57, *pvzList[0].ProductsCount)
+		assert.True(t, lastReceptionAt.Equal(*pvzList[0].LastReceptionAt))
+		assert.Equal(t, 0, *pvzList[1].ReceptionsCount)
+		assert.Nil(t, pvzList[1].LastReceptionAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
 	t.Run("Ошибка при подсчете ПВЗ", func(t *testing.T) {
 		// Тестовые данные
 		ctx := context.Background()
@@ -164,7 +261,7 @@ func TestGetPVZList(t *testing.T) {
 		}
 
 		// Настраиваем ожидание SQL-запроса для подсчета, возвращающего ошибку
-		expectedCountSQL := `SELECT COUNT\(\*\) FROM pvz`
+		expectedCountSQL := `SELECT COUNT\(\*\) FROM pvz WHERE archived_at IS NULL`
 		mock.ExpectQuery(expectedCountSQL).
 			WillReturnError(errors.New("database error during count"))
 
@@ -191,12 +288,12 @@ func TestGetPVZList(t *testing.T) {
 		}
 
 		// Настраиваем ожидание SQL-запроса для подсчета
-		expectedCountSQL := `SELECT COUNT\(\*\) FROM pvz`
+		expectedCountSQL := `SELECT COUNT\(\*\) FROM pvz WHERE archived_at IS NULL`
 		countRows := sqlmock.NewRows([]string{"count"}).AddRow(5)
 		mock.ExpectQuery(expectedCountSQL).WillReturnRows(countRows)
 
 		// Настраиваем ожидание SQL-запроса для получения списка, возвращающего ошибку
-		expectedSQL := `SELECT id, registration_date, city FROM pvz ORDER BY registration_date DESC LIMIT 10 OFFSET 0`
+		expectedSQL := `SELECT id, registration_date, city, address, open_reception, timezone, capacity, occupancy FROM pvz WHERE archived_at IS NULL ORDER BY registration_date DESC LIMIT 10 OFFSET 0`
 		mock.ExpectQuery(expectedSQL).
 			WillReturnError(errors.New("database error during select"))
 
@@ -224,12 +321,12 @@ func TestGetPVZList(t *testing.T) {
 		totalCount := 7 // Всего 7 ПВЗ в базе
 
 		// Настраиваем ожидание SQL-запроса для подсчета
-		expectedCountSQL := `SELECT COUNT\(\*\) FROM pvz`
+		expectedCountSQL := `SELECT COUNT\(\*\) FROM pvz WHERE archived_at IS NULL`
 		countRows := sqlmock.NewRows([]string{"count"}).AddRow(totalCount)
 		mock.ExpectQuery(expectedCountSQL).WillReturnRows(countRows)
 
 		// Настраиваем ожидание SQL-запроса для получения третьей страницы (offset = 4)
-		expectedSQL := `SELECT id, registration_date, city FROM pvz ORDER BY registration_date DESC LIMIT 2 OFFSET 4`
+		expectedSQL := `SELECT id, registration_date, city, address, open_reception, timezone, capacity, occupancy FROM pvz WHERE archived_at IS NULL ORDER BY registration_date DESC LIMIT 2 OFFSET 4`
 
 		// На третьей странице должно быть 2 записи (из 7 всего)
 		pvz1 := models.PVZ{
@@ -279,12 +376,12 @@ func TestGetPVZListValidation(t *testing.T) {
 		}
 
 		// Настраиваем ожидание SQL-запроса для подсчета (без фильтра по дате)
-		expectedCountSQL := `SELECT COUNT\(\*\) FROM pvz`
+		expectedCountSQL := `SELECT COUNT\(\*\) FROM pvz WHERE archived_at IS NULL`
 		countRows := sqlmock.NewRows([]string{"count"}).AddRow(5)
 		mock.ExpectQuery(expectedCountSQL).WillReturnRows(countRows)
 
 		// Настраиваем ожидание SQL-запроса для получения списка (без фильтра по дате)
-		expectedSQL := `SELECT id, registration_date, city FROM pvz ORDER BY registration_date DESC LIMIT 10 OFFSET 0`
+		expectedSQL := `SELECT id, registration_date, city, address, open_reception, timezone, capacity, occupancy FROM pvz WHERE archived_at IS NULL ORDER BY registration_date DESC LIMIT 10 OFFSET 0`
 		rows := sqlmock.NewRows([]string{"id", "registration_date", "city"})
 		mock.ExpectQuery(expectedSQL).WillReturnRows(rows)
 
@@ -299,3 +396,208 @@ func TestGetPVZListValidation(t *testing.T) {
 		assert.NoError(t, err, "Не все ожидаемые запросы были выполнены")
 	})
 }
+
+func TestFlagInactivePVZ(t *testing.T) {
+	pvzQueries, mock := setupPVZQueriesTest(t)
+	since := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(`WITH activity AS \(.+\) INSERT INTO pvz_inactivity_flags .+ ON CONFLICT \(pvz_id\) DO UPDATE`).
+		WithArgs(since).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`WITH activity AS \(.+\) DELETE FROM pvz_inactivity_flags`).
+		WithArgs(since).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	flagged, err := pvzQueries.FlagInactivePVZ(context.Background(), since)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), flagged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeactivatePVZ(t *testing.T) {
+	pvzQueries, mock := setupPVZQueriesTest(t)
+	pvzID := uuid.New().String()
+
+	expectedSQL := `UPDATE pvz SET is_active = \$1, deactivated_at = CURRENT_TIMESTAMP WHERE id = \$2 AND is_active = \$3`
+
+	t.Run("Успешная деактивация", func(t *testing.T) {
+		mock.ExpectExec(expectedSQL).
+			WithArgs(false, pvzID, true).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`DELETE FROM pvz_inactivity_flags WHERE pvz_id = \$1`).
+			WithArgs(pvzID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := pvzQueries.DeactivatePVZ(context.Background(), pvzID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("ПВЗ не найден", func(t *testing.T) {
+		mock.ExpectExec(expectedSQL).
+			WithArgs(false, pvzID, true).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := pvzQueries.DeactivatePVZ(context.Background(), pvzID)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPVZ(t *testing.T) {
+	pvzQueries, mock := setupPVZQueriesTest(t)
+	pvzID := uuid.New().String()
+
+	mock.ExpectQuery(`SELECT id, city, address, registration_date, open_reception, timezone, capacity, occupancy FROM pvz WHERE id = \$1`).
+		WithArgs(pvzID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "city", "registration_date"}).AddRow(pvzID, "Казань", time.Now()))
+
+	pvz, err := pvzQueries.GetPVZ(context.Background(), pvzID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Казань", pvz.City)
+
+	mock.ExpectQuery(`SELECT id, city, address, registration_date, open_reception, timezone, capacity, occupancy FROM pvz`).
+		WithArgs(pvzID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "city", "registration_date"}))
+
+	_, err = pvzQueries.GetPVZ(context.Background(), pvzID)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePVZBatch(t *testing.T) {
+	pvzQueries, mock := setupPVZQueriesTest(t)
+
+	mock.ExpectQuery(`INSERT INTO pvz \(id,city,address,registration_date\) VALUES \(\$1,\$2,\$3,\$4\),\(\$5,\$6,\$7,\$8\) RETURNING id, city, address, registration_date`).
+		WithArgs(sqlmock.AnyArg(), "Москва", "ул. Ленина, 1", testNow, sqlmock.AnyArg(), "Казань", "ул. Баумана, 5", testNow).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "city", "address", "registration_date"}))
+
+	_, err := pvzQueries.CreatePVZBatch(context.Background(), []models.PVZ{
+		{City: "Москва", Address: "ул. Ленина, 1"},
+		{City: "Казань", Address: "ул. Баумана, 5"},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPVZByAddresses(t *testing.T) {
+	pvzQueries, mock := setupPVZQueriesTest(t)
+
+	mock.ExpectQuery(`SELECT id, city, address, registration_date FROM pvz WHERE address = ANY\(\$1\)`).
+		WithArgs(`{"ул. Баумана, 5"}`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "city", "address", "registration_date"}).
+			AddRow(uuid.New().String(), "Казань", "ул. Баумана, 5", time.Now()))
+
+	pvzList, err := pvzQueries.GetPVZByAddresses(context.Background(), []string{"ул. Баумана, 5"})
+
+	assert.NoError(t, err)
+	assert.Len(t, pvzList, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePVZ(t *testing.T) {
+	pvzQueries, mock := setupPVZQueriesTest(t)
+	timezone := "Asia/Almaty"
+
+	mock.ExpectQuery(`INSERT INTO pvz \(id,city,address,registration_date,timezone\) VALUES \(\$1,\$2,\$3,\$4,\$5\) RETURNING id, city, address, registration_date, timezone`).
+		WithArgs(sqlmock.AnyArg(), "Алматы", "ул. Абая, 1", testNow, timezone).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "city", "address", "registration_date", "timezone"}).
+			AddRow(uuid.New().String(), "Алматы", "ул. Абая, 1", testNow, timezone))
+
+	pvz, err := pvzQueries.CreatePVZ(context.Background(), "Алматы", "ул. Абая, 1", timezone)
+	assert.NoError(t, err)
+	assert.Equal(t, timezone, *pvz.Timezone)
+
+	// Пустой часовой пояс не сохраняется
+	mock.ExpectQuery(`INSERT INTO pvz`).
+		WithArgs(sqlmock.AnyArg(), "Москва", "", testNow, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "city", "address", "registration_date", "timezone"}).
+			AddRow(uuid.New().String(), "Москва", "", testNow, nil))
+
+	pvz, err = pvzQueries.CreatePVZ(context.Background(), "Москва", "", "")
+	assert.NoError(t, err)
+	assert.Nil(t, pvz.Timezone)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPVZListFiltersUTC проверяет, что границы периода со смещением приводятся к UTC
+func TestPVZListFiltersUTC(t *testing.T) {
+	filters := pvzListFilters(models.PVZListQuery{StartDate: "2026-03-02T00:30:00+03:00"})
+
+	assert.Len(t, filters, 2)
+	start := filters[1].(squirrel.GtOrEq)["registration_date"].(time.Time)
+	assert.Equal(t, time.UTC, start.Location())
+	assert.Equal(t, time.Date(2026, 3, 1, 21, 30, 0, 0, time.UTC), start)
+}
+
+func TestLockPVZ(t *testing.T) {
+	pvzQueries, mock := setupPVZQueriesTest(t)
+	pvzID := uuid.New().String()
+
+	mock.ExpectQuery(`SELECT id, city, address, registration_date, open_reception, timezone, capacity, occupancy FROM pvz WHERE archived_at IS NULL AND id = \$1 FOR UPDATE`).
+		WithArgs(pvzID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "city", "registration_date", "open_reception"}).AddRow(pvzID, "Казань", testNow, true))
+
+	pvz, err := pvzQueries.LockPVZ(context.Background(), pvzID)
+	assert.NoError(t, err)
+	assert.True(t, pvz.OpenReception)
+
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(pvzID).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err = pvzQueries.LockPVZ(context.Background(), pvzID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetPVZCapacity(t *testing.T) {
+	pvzQueries, mock := setupPVZQueriesTest(t)
+	pvzID := uuid.New().String()
+	capacity := 300
+
+	mock.ExpectQuery(`UPDATE pvz SET capacity = \$2 WHERE id = \$1 AND archived_at IS NULL RETURNING .* capacity, occupancy`).
+		WithArgs(pvzID, &capacity).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "city", "capacity", "occupancy"}).AddRow(pvzID, "Казань", 300, 120))
+
+	pvz, err := pvzQueries.SetCapacity(context.Background(), pvzID, &capacity)
+	assert.NoError(t, err)
+	assert.Equal(t, 300, *pvz.Capacity)
+	assert.Equal(t, 120, pvz.Occupancy)
+
+	mock.ExpectQuery(`UPDATE pvz SET capacity`).WithArgs(pvzID, nil).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err = pvzQueries.SetCapacity(context.Background(), pvzID, nil)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArchivePVZ(t *testing.T) {
+	pvzQueries, mock := setupPVZQueriesTest(t)
+	pvzID := uuid.New().String()
+	expectedSQL := `UPDATE pvz SET archived_at = \$1, is_active = \$2 WHERE archived_at IS NULL AND id = \$3`
+
+	t.Run("ПВЗ перенесен в архив", func(t *testing.T) {
+		mock.ExpectExec(expectedSQL).
+			WithArgs(testNow, false, pvzID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`DELETE FROM pvz_inactivity_flags WHERE pvz_id = \$1`).
+			WithArgs(pvzID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		archivedAt, err := pvzQueries.ArchivePVZ(context.Background(), pvzID)
+		assert.NoError(t, err)
+		assert.Equal(t, testNow, archivedAt)
+	})
+
+	t.Run("ПВЗ уже в архиве", func(t *testing.T) {
+		mock.ExpectExec(expectedSQL).
+			WithArgs(testNow, false, pvzID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		_, err := pvzQueries.ArchivePVZ(context.Background(), pvzID)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,246 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+)
+
+// QuotaQueriesInterface определяет интерфейс для запросов к квотам операций
+type QuotaQueriesInterface interface {
+	GetQuotas(ctx context.Context) ([]models.OperationQuota, error)
+	GetQuota(ctx context.Context, role, operation string) (*models.OperationQuota, error)
+	UpsertQuota(ctx context.Context, quota models.OperationQuota) (*models.OperationQuota, error)
+	DeleteQuota(ctx context.Context, role, operation string) error
+	GetOverrides(ctx context.Context) ([]models.QuotaOverride, error)
+	GetOverride(ctx context.Context, userID, operation string) (*models.QuotaOverride, error)
+	UpsertOverride(ctx context.Context, override models.QuotaOverride) (*models.QuotaOverride, error)
+	DeleteOverride(ctx context.Context, userID, operation string) error
+	Consume(ctx context.Context, userID, operation string, windowStart time.Time, count, limit int) (bool, error)
+}
+
+// QuotaQueries содержит методы запросов для работы с квотами операций
+type QuotaQueries struct {
+	db *db.Database
+}
+
+var _ QuotaQueriesInterface = (*QuotaQueries)(nil)
+
+// NewQuotaQueries создает новый экземпляр QuotaQueries
+func NewQuotaQueries(db *db.Database) *QuotaQueries {
+	return &QuotaQueries{db: db}
+}
+
+const (
+	operationQuotaColumns = "role, operation, max_per_hour, comment, updated_at"
+	quotaOverrideColumns  = "user_id, operation, max_per_hour, expires_at, comment, created_by, created_at"
+)
+
+var listQuotasSQL = db.Register("quota.list", psql.
+	Select(operationQuotaColumns).
+	From("operation_quotas").
+	OrderBy("role", "operation"))
+
+// GetQuotas получает квоты всех ролей
+func (q *QuotaQueries) GetQuotas(ctx context.Context) ([]models.OperationQuota, error) {
+	ctx, span := tracing.Start(ctx, "QuotaQueries.GetQuotas")
+	defer span.End()
+
+	var quotas []models.OperationQuota
+	if err := q.db.SelectContext(ctx, &quotas, listQuotasSQL); err != nil {
+		return nil, fmt.Errorf("failed to get operation quotas: %w", err)
+	}
+
+	return quotas, nil
+}
+
+var getQuotaSQL = db.Register("quota.get", psql.
+	Select(operationQuotaColumns).
+	From("operation_quotas").
+	Where("role = ? AND operation = ?"))
+
+// GetQuota получает квоту операции для роли, возвращает ErrNotFound, если операция роли не ограничена
+func (q *QuotaQueries) GetQuota(ctx context.Context, role, operation string) (*models.OperationQuota, error) {
+	ctx, span := tracing.Start(ctx, "QuotaQueries.GetQuota")
+	defer span.End()
+
+	var quota models.OperationQuota
+	err := q.db.GetContext(ctx, &quota, getQuotaSQL, role, operation)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("quota %s for %s: %w", operation, role, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operation quota: %w", err)
+	}
+
+	return &quota, nil
+}
+
+var upsertQuotaSQL = db.RegisterSQL("quota.upsert", `INSERT INTO operation_quotas (role, operation, max_per_hour, comment, updated_at)
+	VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+	ON CONFLICT (role, operation) DO UPDATE SET max_per_hour = EXCLUDED.max_per_hour, comment = EXCLUDED.comment, updated_at = EXCLUDED.updated_at
+	RETURNING `+operationQuotaColumns)
+
+// UpsertQuota создает или обновляет квоту операции для роли
+func (q *QuotaQueries) UpsertQuota(ctx context.Context, quota models.OperationQuota) (*models.OperationQuota, error) {
+	ctx, span := tracing.Start(ctx, "QuotaQueries.UpsertQuota")
+	defer span.End()
+
+	var saved models.OperationQuota
+	err := q.db.QueryRowxContext(ctx, upsertQuotaSQL, quota.Role, quota.Operation, quota.MaxPerHour, quota.Comment).StructScan(&saved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert operation quota: %w", err)
+	}
+
+	return &saved, nil
+}
+
+var deleteQuotaSQL = db.Register("quota.delete", psql.
+	Delete("operation_quotas").
+	Where("role = ? AND operation = ?"))
+
+// DeleteQuota удаляет квоту операции для роли, возвращает ErrNotFound, если ее не было
+func (q *QuotaQueries) DeleteQuota(ctx context.Context, role, operation string) error {
+	ctx, span := tracing.Start(ctx, "QuotaQueries.DeleteQuota")
+	defer span.End()
+
+	result, err := q.db.ExecContext(ctx, deleteQuotaSQL, role, operation)
+	if err != nil {
+		return fmt.Errorf("failed to delete operation quota: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("quota %s for %s: %w", operation, role, ErrNotFound)
+	}
+
+	return nil
+}
+
+var listQuotaOverridesSQL = db.Register("quota.list_overrides", psql.
+	Select(quotaOverrideColumns).
+	From("operation_quota_overrides").
+	Where("expires_at > CURRENT_TIMESTAMP").
+	OrderBy("expires_at", "user_id", "operation"))
+
+// GetOverrides получает действующие исключения из квот
+func (q *QuotaQueries) GetOverrides(ctx context.Context) ([]models.QuotaOverride, error) {
+	ctx, span := tracing.Start(ctx, "QuotaQueries.GetOverrides")
+	defer span.End()
+
+	var overrides []models.QuotaOverride
+	if err := q.db.SelectContext(ctx, &overrides, listQuotaOverridesSQL); err != nil {
+		return nil, fmt.Errorf("failed to get quota overrides: %w", err)
+	}
+
+	return overrides, nil
+}
+
+var getQuotaOverrideSQL = db.Register("quota.get_override", psql.
+	Select(quotaOverrideColumns).
+	From("operation_quota_overrides").
+	Where("user_id = ? AND operation = ? AND expires_at > CURRENT_TIMESTAMP"))
+
+// GetOverride получает действующее исключение из квоты операции для пользователя,
+// возвращает ErrNotFound, если исключения нет или срок его действия истек
+func (q *QuotaQueries) GetOverride(ctx context.Context, userID, operation string) (*models.QuotaOverride, error) {
+	ctx, span := tracing.Start(ctx, "QuotaQueries.GetOverride")
+	defer span.End()
+
+	var override models.QuotaOverride
+	err := q.db.GetContext(ctx, &override, getQuotaOverrideSQL, userID, operation)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("quota override %s for %s: %w", operation, userID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quota override: %w", err)
+	}
+
+	return &override, nil
+}
+
+var upsertQuotaOverrideSQL = db.RegisterSQL("quota.upsert_override", `INSERT INTO operation_quota_overrides (user_id, operation, max_per_hour, expires_at, comment, created_by, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP)
+	ON CONFLICT (user_id, operation) DO UPDATE SET max_per_hour = EXCLUDED.max_per_hour, expires_at = EXCLUDED.expires_at,
+		comment = EXCLUDED.comment, created_by = EXCLUDED.created_by, created_at = EXCLUDED.created_at
+	RETURNING `+quotaOverrideColumns)
+
+// UpsertOverride создает или заменяет исключение из квоты операции для пользователя.
+// Возвращает ErrNotFound, если пользователя нет
+func (q *QuotaQueries) UpsertOverride(ctx context.Context, override models.QuotaOverride) (*models.QuotaOverride, error) {
+	ctx, span := tracing.Start(ctx, "QuotaQueries.UpsertOverride")
+	defer span.End()
+
+	var saved models.QuotaOverride
+	err := q.db.QueryRowxContext(ctx, upsertQuotaOverrideSQL, override.UserID, override.Operation, override.MaxPerHour, override.ExpiresAt, override.Comment, override.CreatedBy).StructScan(&saved)
+	if isForeignKeyViolation(err) {
+		return nil, fmt.Errorf("user %s: %w", override.UserID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert quota override: %w", err)
+	}
+
+	return &saved, nil
+}
+
+var deleteQuotaOverrideSQL = db.Register("quota.delete_override", psql.
+	Delete("operation_quota_overrides").
+	Where("user_id = ? AND operation = ?"))
+
+// DeleteOverride удаляет исключение из квоты, возвращает ErrNotFound, если его не было
+func (q *QuotaQueries) DeleteOverride(ctx context.Context, userID, operation string) error {
+	ctx, span := tracing.Start(ctx, "QuotaQueries.DeleteOverride")
+	defer span.End()
+
+	result, err := q.db.ExecContext(ctx, deleteQuotaOverrideSQL, userID, operation)
+	if err != nil {
+		return fmt.Errorf("failed to delete quota override: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("quota override %s for %s: %w", operation, userID, ErrNotFound)
+	}
+
+	return nil
+}
+
+// consumeQuotaSQL увеличивает счетчик окна на $4, только если он не превысит $5. Проверка и увеличение
+// выполняются одним запросом под блокировкой строки счетчика, поэтому параллельные операции
+// одного пользователя на разных экземплярах сервиса не превысят квоту
+var consumeQuotaSQL = db.RegisterSQL("quota.consume", `INSERT INTO operation_quota_usage (user_id, operation, window_start, used)
+	SELECT $1::uuid, $2, $3::timestamptz, $4::int WHERE $4::int <= $5::int
+	ON CONFLICT (user_id, operation, window_start) DO UPDATE SET used = operation_quota_usage.used + EXCLUDED.used
+	WHERE operation_quota_usage.used + EXCLUDED.used <= $5::int
+	RETURNING used`)
+
+// Consume расходует count операций из квоты limit в окне windowStart. Возвращает false, если квоты
+// не хватает, счетчик при этом не меняется. Вызывается в транзакции операции, чтобы отмененная
+// операция не расходовала квоту
+func (q *QuotaQueries) Consume(ctx context.Context, userID, operation string, windowStart time.Time, count, limit int) (bool, error) {
+	ctx, span := tracing.Start(ctx, "QuotaQueries.Consume")
+	defer span.End()
+
+	var used int
+	err := q.db.GetContext(ctx, &used, consumeQuotaSQL, userID, operation, windowStart, count, limit)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to consume operation quota: %w", err)
+	}
+
+	return true, nil
+}
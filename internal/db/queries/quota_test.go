@@ -0,0 +1,79 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/db"
+)
+
+func setupQuotaQueriesTest(t *testing.T) (*QuotaQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &QuotaQueries{db: &db.Database{DB: sqlxDB}}, mock
+}
+
+func TestQuotaQueries_Consume(t *testing.T) {
+	q, mock := setupQuotaQueriesTest(t)
+	window := time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Квоты хватает", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO operation_quota_usage .* ON CONFLICT \(user_id, operation, window_start\) DO UPDATE .* WHERE operation_quota_usage.used \+ EXCLUDED.used <= \$5::int RETURNING used`).
+			WithArgs("user-1", "product.delete", window, 2, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"used"}).AddRow(7))
+
+		ok, err := q.Consume(context.Background(), "user-1", "product.delete", window, 2, 10)
+
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Квота исчерпана", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO operation_quota_usage`).
+			WithArgs("user-1", "product.delete", window, 5, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"used"}))
+
+		ok, err := q.Consume(context.Background(), "user-1", "product.delete", window, 5, 10)
+
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestQuotaQueries_GetOverride(t *testing.T) {
+	q, mock := setupQuotaQueriesTest(t)
+
+	t.Run("Исключение без лимита", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT user_id, operation, max_per_hour, expires_at, comment, created_by, created_at FROM operation_quota_overrides WHERE user_id = \$1 AND operation = \$2 AND expires_at > CURRENT_TIMESTAMP`).
+			WithArgs("user-1", "product.delete").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id", "operation", "max_per_hour", "expires_at", "comment", "created_by", "created_at"}).
+				AddRow("user-1", "product.delete", nil, time.Now().Add(time.Hour), "Разбор приёмки", nil, time.Now()))
+
+		override, err := q.GetOverride(context.Background(), "user-1", "product.delete")
+
+		require.NoError(t, err)
+		assert.Nil(t, override.MaxPerHour)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Нет действующего исключения", func(t *testing.T) {
+		mock.ExpectQuery(`FROM operation_quota_overrides`).
+			WithArgs("user-2", "product.delete").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+		_, err := q.GetOverride(context.Background(), "user-2", "product.delete")
+
+		assert.True(t, errors.Is(err, ErrNotFound))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,111 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// RateLimitQueriesInterface определяет интерфейс для запросов к индивидуальным лимитам
+type RateLimitQueriesInterface interface {
+	GetOverrides(ctx context.Context) ([]models.RateLimitOverride, error)
+	UpsertOverride(ctx context.Context, override models.RateLimitOverride) (*models.RateLimitOverride, error)
+	DeleteOverride(ctx context.Context, key string) error
+}
+
+// RateLimitQueries содержит методы запросов для работы с индивидуальными лимитами
+type RateLimitQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ RateLimitQueriesInterface = (*RateLimitQueries)(nil)
+
+// NewRateLimitQueries создает новый экземпляр RateLimitQueries
+func NewRateLimitQueries(db *db.Database) *RateLimitQueries {
+	return &RateLimitQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+	}
+}
+
+// GetOverrides получает все индивидуальные лимиты
+func (q *RateLimitQueries) GetOverrides(ctx context.Context) ([]models.RateLimitOverride, error) {
+	ctx, span := tracing.Start(ctx, "RateLimitQueries.GetOverrides")
+	defer span.End()
+
+	sql, args, err := q.sq.
+		Select("key", "requests_per_minute", "burst", "comment", "updated_at").
+		From("rate_limit_overrides").
+		OrderBy("key").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var overrides []models.RateLimitOverride
+	err = q.db.SelectContext(ctx, &overrides, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rate limit overrides: %w", err)
+	}
+
+	return overrides, nil
+}
+
+// UpsertOverride создает или обновляет индивидуальный лимит для ключа
+func (q *RateLimitQueries) UpsertOverride(ctx context.Context, override models.RateLimitOverride) (*models.RateLimitOverride, error) {
+	ctx, span := tracing.Start(ctx, "RateLimitQueries.UpsertOverride")
+	defer span.End()
+
+	sql, args, err := q.sq.
+		Insert("rate_limit_overrides").
+		Columns("key", "requests_per_minute", "burst", "comment", "updated_at").
+		Values(override.Key, override.RequestsPerMinute, override.Burst, override.Comment, squirrel.Expr("CURRENT_TIMESTAMP")).
+		Suffix("ON CONFLICT (key) DO UPDATE SET requests_per_minute = EXCLUDED.requests_per_minute, burst = EXCLUDED.burst, comment = EXCLUDED.comment, updated_at = EXCLUDED.updated_at RETURNING key, requests_per_minute, burst, comment, updated_at").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var saved models.RateLimitOverride
+	err = q.db.QueryRowxContext(ctx, sql, args...).StructScan(&saved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert rate limit override: %w", err)
+	}
+
+	return &saved, nil
+}
+
+// DeleteOverride удаляет индивидуальный лимит, возвращает ErrNotFound, если его не было
+func (q *RateLimitQueries) DeleteOverride(ctx context.Context, key string) error {
+	ctx, span := tracing.Start(ctx, "RateLimitQueries.DeleteOverride")
+	defer span.End()
+
+	sql, args, err := q.sq.
+		Delete("rate_limit_overrides").
+		Where(squirrel.Eq{"key": key}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to delete rate limit override: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("rate limit override %s: %w", key, ErrNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package queries
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+)
+
+func setupRateLimitQueriesTest(t *testing.T) (*RateLimitQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	dbInstance := &db.Database{DB: sqlxDB}
+
+	return &RateLimitQueries{
+		db: dbInstance,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+func TestRateLimitQueries_UpsertOverride(t *testing.T) {
+	q, mock := setupRateLimitQueriesTest(t)
+	now := time.Now()
+
+	mock.ExpectQuery(`INSERT INTO rate_limit_overrides \(key,requests_per_minute,burst,comment,updated_at\) VALUES \(\$1,\$2,\$3,\$4,CURRENT_TIMESTAMP\) ON CONFLICT \(key\) DO UPDATE SET .* RETURNING key, requests_per_minute, burst, comment, updated_at`).
+		WithArgs("user:tablet", 1200, 200, "").
+		WillReturnRows(sqlmock.NewRows([]string{"key", "requests_per_minute", "burst", "comment", "updated_at"}).
+			AddRow("user:tablet", 1200, 200, "", now))
+
+	override, err := q.UpsertOverride(context.Background(), models.RateLimitOverride{Key: "user:tablet", RequestsPerMinute: 1200, Burst: 200})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1200, override.RequestsPerMinute)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRateLimitQueries_DeleteOverride(t *testing.T) {
+	q, mock := setupRateLimitQueriesTest(t)
+
+	mock.ExpectExec(`DELETE FROM rate_limit_overrides WHERE key = \$1`).
+		WithArgs("user:missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := q.DeleteOverride(context.Background(), "user:missing")
+
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -6,154 +6,631 @@ import (
 	"time"
 
 	"database/sql"
+	"pvz-service/internal/clock"
 	"pvz-service/internal/db"
 	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 // ReceptionQueriesInterface определяет интерфейс для запросов к приёмкам
 type ReceptionQueriesInterface interface {
-	CheckOpenReception(ctx context.Context, pvzID string) (bool, error)
-	CreateReception(ctx context.Context, pvzID string) (*models.Reception, error)
+	CreateReception(ctx context.Context, pvzID, userID string) (*models.Reception, error)
 	GetLastOpenReception(ctx context.Context, pvzID string) (*models.Reception, error)
-	CloseReception(ctx context.Context, receptionID string) (*models.Reception, error)
+	GetLastPausedReception(ctx context.Context, pvzID string) (*models.Reception, error)
+	PauseReception(ctx context.Context, pvzID string) (*models.Reception, error)
+	ResumeReception(ctx context.Context, pvzID string) (*models.Reception, error)
+	CloseReception(ctx context.Context, receptionID, userID string) (*models.Reception, error)
+	CloseStaleReceptions(ctx context.Context, openedBefore time.Time) ([]models.Reception, error)
 	GetReceptionsByPVZ(ctx context.Context, pvzID string) ([]models.Reception, error)
+	ListReceptions(ctx context.Context, pvzID string, params models.ReceptionListQuery) ([]models.Reception, int, error)
+	GetLatestReceptionsByPVZ(ctx context.Context, pvzIDs []string, perPVZ int) ([]models.Reception, error)
+	GetReceptionByID(ctx context.Context, receptionID string) (*models.Reception, error)
+	LockReceptions(ctx context.Context, receptionIDs []string) ([]models.Reception, error)
+	ReassignReception(ctx context.Context, receptionID, fromPvzID, toPvzID, userID string, reason *string) (*models.Reception, error)
+	FlagOverdueReceptions(ctx context.Context, openedBefore time.Time) ([]models.OverdueReception, error)
+	GetOverdueReceptions(ctx context.Context, pvzID string) ([]models.OverdueReception, error)
+	UpdateReceptionNotes(ctx context.Context, receptionID, authorID string, note *string, tags []string) (*models.Reception, error)
+	GetReceptionNotes(ctx context.Context, receptionID string) ([]models.ReceptionNote, error)
+	GetCustodyEntries(ctx context.Context, receptionID string) ([]models.CustodyEntry, error)
 }
 
 // ReceptionQueries содержит методы запросов для работы с приёмками
 type ReceptionQueries struct {
-	db *db.Database
-	sq squirrel.StatementBuilderType
+	db    *db.Database
+	sq    squirrel.StatementBuilderType
+	clock clock.Clock
+
+	// statusShadow - переходный период переноса статуса товаров в product_status
+	statusShadow *db.Shadow
 }
 
+var _ ReceptionQueriesInterface = (*ReceptionQueries)(nil)
+
 // NewReceptionQueries создает новый экземпляр ReceptionQueries
 func NewReceptionQueries(db *db.Database) *ReceptionQueries {
 	return &ReceptionQueries{
-		db: db,
-		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+		db:    db,
+		sq:    squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+		clock: clock.System{},
+
+		statusShadow: db.Shadow(productStatusMigration),
 	}
 }
 
-// CheckOpenReception проверяет, есть ли уже открытая приёмка для данного ПВЗ
-func (q *ReceptionQueries) CheckOpenReception(ctx context.Context, pvzID string) (bool, error) {
-	query := q.sq.
-		Select("1").
-		From("reception").
-		Where(squirrel.Eq{"pvz_id": pvzID, "status": "in_progress"}).
-		Limit(1)
+const receptionColumns = "id, number, datetime, pvz_id, status, note, tags, product_count, capacity, template_id, supplier_id"
 
-	qsql, args, err := query.ToSql()
+// Запросы, выполняемые при каждом добавлении товара, подготавливаются один раз
+// вместо сборки squirrel при каждом вызове
+var lastOpenReceptionSQL = db.Register("reception.get_last_open", psql.
+	Select(receptionColumns).
+	From("reception").
+	Where("pvz_id = ? AND status = ?").
+	OrderBy("datetime DESC").
+	Limit(1))
+
+// createReceptionSQL увеличивает счётчик приёмок ПВЗ за год и создает приёмку с очередным номером
+// в одном запросе: строка счётчика остается заблокированной до конца транзакции,
+// поэтому параллельные приёмки одного ПВЗ не получают одинаковый номер.
+// Уникальный индекс открытых приёмок ПВЗ не дает создать вторую открытую приёмку:
+// при конфликте запрос не возвращает строк, а увеличение счётчика откатывается с транзакцией
+var createReceptionSQL = db.RegisterSQL("reception.create", `WITH seq AS (
+		INSERT INTO reception_number_counters (pvz_id, year, last_number)
+		VALUES ($3, $5, 1)
+		ON CONFLICT (pvz_id, year) DO UPDATE SET last_number = reception_number_counters.last_number + 1
+		RETURNING last_number
+	)
+	INSERT INTO reception (id, datetime, pvz_id, status, number, created_by)
+	SELECT $1, $2, $3, $4, p.code || '-' || $5 || '-' || lpad(seq.last_number::text, GREATEST(6, length(seq.last_number::text)), '0'), $6
+	FROM pvz p, seq
+	WHERE p.id = $3
+	ON CONFLICT (pvz_id) WHERE status = 'in_progress' DO NOTHING
+	RETURNING `+receptionColumns)
+
+// CreateReception создает новую приёмку товаров с очередным номером вида MSK001-2025-000123:
+// код ПВЗ, год создания по UTC и порядковый номер приёмки ПВЗ за этот год. userID - сотрудник, открывший приёмку.
+// Если в ПВЗ уже есть открытая приёмка, возвращает *ReceptionOpenError с ней,
+// если ПВЗ не найден - ErrNotFound
+func (q *ReceptionQueries) CreateReception(ctx context.Context, pvzID, userID string) (*models.Reception, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionQueries.CreateReception")
+	defer span.End()
+
+	// Генерируем UUID
+	id := uuid.New().String()
+	now := q.clock.Now()
+
+	var reception models.Reception
+	err := q.db.QueryRowxContext(ctx, createReceptionSQL, id, now, pvzID, "in_progress", now.Year(), nullString(userID)).StructScan(&reception)
+	if err == sql.ErrNoRows {
+		return nil, q.openReceptionConflict(ctx, pvzID)
+	}
+	// Счетчик номеров создается первым и ссылается на ПВЗ: неизвестный ПВЗ нарушает его внешний ключ
+	if isForeignKeyViolation(err) {
+		return nil, fmt.Errorf("pvz %s: %w", pvzID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reception: %w", err)
+	}
+
+	return &reception, nil
+}
+
+// openReceptionConflict определяет, почему приёмка не создана: в ПВЗ уже есть открытая приёмка
+// или ПВЗ не существует. Открытая приёмка параллельного запроса видна после его фиксации,
+// которой запрос с конфликтом дожидается на уникальном индексе
+func (q *ReceptionQueries) openReceptionConflict(ctx context.Context, pvzID string) error {
+	var existing models.Reception
+	err := q.db.PreparedGetContext(ctx, &existing, lastOpenReceptionSQL, pvzID, "in_progress")
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get open reception: %w", err)
+	}
+
+	return &ReceptionOpenError{Reception: &existing}
+}
+
+// GetLastOpenReception получает последнюю открытую приёмку для ПВЗ
+func (q *ReceptionQueries) GetLastOpenReception(ctx context.Context, pvzID string) (*models.Reception, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionQueries.GetLastOpenReception")
+	defer span.End()
+
+	var reception models.Reception
+	err := q.db.PreparedGetContext(ctx, &reception, lastOpenReceptionSQL, pvzID, "in_progress")
 	if err != nil {
-		return false, fmt.Errorf("failed to build query: %w", err)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("no open reception found for pvz %s", pvzID)
+		}
+		return nil, fmt.Errorf("failed to get open reception: %w", err)
 	}
 
-	var exists int
-	err = q.db.QueryRowContext(ctx, qsql, args...).Scan(&exists)
+	return &reception, nil
+}
+
+// GetLastPausedReception получает последнюю приостановленную приёмку ПВЗ
+func (q *ReceptionQueries) GetLastPausedReception(ctx context.Context, pvzID string) (*models.Reception, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionQueries.GetLastPausedReception")
+	defer span.End()
+
+	var reception models.Reception
+	err := q.db.PreparedGetContext(ctx, &reception, lastOpenReceptionSQL, pvzID, "paused")
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return false, nil
+			return nil, fmt.Errorf("paused reception for pvz %s: %w", pvzID, ErrNotFound)
 		}
-		return false, fmt.Errorf("failed to check open reception: %w", err)
+		return nil, fmt.Errorf("failed to get paused reception: %w", err)
 	}
 
-	return true, nil
+	return &reception, nil
 }
 
-// CreateReception создает новую приёмку товаров
-func (q *ReceptionQueries) CreateReception(ctx context.Context, pvzID string) (*models.Reception, error) {
-	// Генерируем UUID
-	id := uuid.New().String()
-	now := time.Now()
+// pauseReceptionSQL приостанавливает открытую приёмку ПВЗ
+var pauseReceptionSQL = db.Register("reception.pause", psql.
+	Update("reception").
+	Set("status", nil).
+	Where("pvz_id = ? AND status = ?").
+	Suffix("RETURNING "+receptionColumns))
 
-	// Создаем запрос
-	query := q.sq.
-		Insert("reception").
-		Columns("id", "datetime", "pvz_id", "status").
-		Values(id, now, pvzID, "in_progress").
-		Suffix("RETURNING id, datetime, pvz_id, status")
+// PauseReception приостанавливает открытую приёмку ПВЗ. Если открытой приёмки нет, возвращает ErrNotFound
+func (q *ReceptionQueries) PauseReception(ctx context.Context, pvzID string) (*models.Reception, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionQueries.PauseReception")
+	defer span.End()
 
-	sql, args, err := query.ToSql()
+	var reception models.Reception
+	err := q.db.QueryRowxContext(ctx, pauseReceptionSQL, "paused", pvzID, "in_progress").StructScan(&reception)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build query: %w", err)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("open reception for pvz %s: %w", pvzID, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to pause reception: %w", err)
 	}
 
+	return &reception, nil
+}
+
+// resumeReceptionSQL возобновляет последнюю приостановленную приёмку ПВЗ, если в ПВЗ нет открытой:
+// пока приёмка стояла на паузе, в ПВЗ могли открыть новую
+var resumeReceptionSQL = db.RegisterSQL("reception.resume", `UPDATE reception SET status = 'in_progress'
+	WHERE id = (
+		SELECT id FROM reception WHERE pvz_id = $1 AND status = 'paused' ORDER BY datetime DESC, id DESC LIMIT 1
+	)
+	AND NOT EXISTS (SELECT 1 FROM reception WHERE pvz_id = $1 AND status = 'in_progress')
+	RETURNING `+receptionColumns)
+
+// ResumeReception возобновляет последнюю приостановленную приёмку ПВЗ.
+// Если в ПВЗ уже есть открытая приёмка, возвращает *ReceptionOpenError с ней,
+// если приостановленной приёмки нет - ErrNotFound
+func (q *ReceptionQueries) ResumeReception(ctx context.Context, pvzID string) (*models.Reception, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionQueries.ResumeReception")
+	defer span.End()
+
 	var reception models.Reception
-	err = q.db.QueryRowxContext(ctx, sql, args...).StructScan(&reception)
+	err := q.db.QueryRowxContext(ctx, resumeReceptionSQL, pvzID).StructScan(&reception)
+	if err == sql.ErrNoRows {
+		var open models.Reception
+		err = q.db.PreparedGetContext(ctx, &open, lastOpenReceptionSQL, pvzID, "in_progress")
+		if err == nil {
+			return nil, &ReceptionOpenError{Reception: &open}
+		}
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("paused reception for pvz %s: %w", pvzID, ErrNotFound)
+		}
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to create reception: %w", err)
+		return nil, fmt.Errorf("failed to resume reception: %w", err)
 	}
 
 	return &reception, nil
 }
 
-// GetLastOpenReception получает последнюю открытую приёмку для ПВЗ
-func (q *ReceptionQueries) GetLastOpenReception(ctx context.Context, pvzID string) (*models.Reception, error) {
-	query := q.sq.
-		Select("id", "datetime", "pvz_id", "status").
+// closeReceptionSQL закрывает незакрытую приёмку, storeReceptionProductsSQL переводит ее принятые товары
+// на хранение. Приостановленную приёмку закрывает архивирование ПВЗ
+var closeReceptionSQL = db.Register("reception.close", psql.
+	Update("reception").
+	Set("status", nil).
+	Set("closed_by", nil).
+	Where("id = ? AND status IN ('in_progress', 'paused')").
+	Suffix("RETURNING "+receptionColumns))
+
+var storeReceptionProductsSQL = db.Register("reception.store_products", psql.
+	Update("product").
+	Set("status", nil).
+	Where("reception_id = ? AND status = ?"))
+
+// CloseReception закрывает приёмку товаров и переводит ее товары на хранение.
+// userID - пользователь, закрывший приёмку, пустой для закрытия из консоли.
+// Уже закрытая приёмка не закрывается повторно: возвращается ErrNotFound
+func (q *ReceptionQueries) CloseReception(ctx context.Context, receptionID, userID string) (*models.Reception, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionQueries.CloseReception")
+	defer span.End()
+
+	var reception models.Reception
+	err := q.db.WithTx(ctx, func(tx *sqlx.Tx) error {
+		err := tx.QueryRowxContext(ctx, closeReceptionSQL, "close", nullString(userID), receptionID).StructScan(&reception)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("open reception %s: %w", receptionID, ErrNotFound)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to close reception: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, storeReceptionProductsSQL, models.ProductLifecycleStored, receptionID, models.ProductLifecycleReceived); err != nil {
+			return fmt.Errorf("failed to store reception products: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	syncProductStatus(ctx, q.db, q.statusShadow, "reception_id", []string{reception.ID})
+
+	return &reception, nil
+}
+
+// closeStaleReceptionsSQL закрывает открытые приёмки, созданные до $1, и переводит их товары на хранение
+var closeStaleReceptionsSQL = db.RegisterSQL("reception.close_stale", `WITH closed AS (
+		UPDATE reception SET status = 'close'
+		WHERE status = 'in_progress' AND datetime < $1
+		RETURNING `+receptionColumns+`
+	), stored AS (
+		UPDATE product SET status = $2
+		WHERE status = $3 AND reception_id IN (SELECT id FROM closed)
+	)
+	SELECT `+receptionColumns+` FROM closed ORDER BY datetime`)
+
+// CloseStaleReceptions закрывает все открытые приёмки, созданные до openedBefore, и переводит
+// их товары на хранение одним запросом. Приёмки, закрытые параллельно, не попадают в результат
+func (q *ReceptionQueries) CloseStaleReceptions(ctx context.Context, openedBefore time.Time) ([]models.Reception, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionQueries.CloseStaleReceptions")
+	defer span.End()
+
+	var closed []models.Reception
+	err := q.db.SelectContext(ctx, &closed, closeStaleReceptionsSQL, openedBefore, models.ProductLifecycleStored, models.ProductLifecycleReceived)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close stale receptions: %w", err)
+	}
+
+	closedIDs := make([]string, 0, len(closed))
+	for _, reception := range closed {
+		closedIDs = append(closedIDs, reception.ID)
+	}
+	syncProductStatus(ctx, q.db, q.statusShadow, "reception_id", closedIDs)
+
+	return closed, nil
+}
+
+var receptionsByPVZSQL = db.Register("reception.list_by_pvz", psql.
+	Select(receptionColumns).
+	From("reception").
+	Where("pvz_id = ?").
+	OrderBy("datetime DESC"))
+
+// GetReceptionsByPVZ получает все приёмки для ПВЗ
+func (q *ReceptionQueries) GetReceptionsByPVZ(ctx context.Context, pvzID string) ([]models.Reception, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionQueries.GetReceptionsByPVZ")
+	defer span.End()
+
+	var receptions []models.Reception
+	err := q.db.ReadSelectContext(ctx, &receptions, receptionsByPVZSQL, pvzID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receptions: %w", err)
+	}
+
+	return receptions, nil
+}
+
+// ListReceptions получает страницу приёмок ПВЗ от новых к старым и общее количество приёмок по фильтру
+func (q *ReceptionQueries) ListReceptions(ctx context.Context, pvzID string, params models.ReceptionListQuery) ([]models.Reception, int, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionQueries.ListReceptions")
+	defer span.End()
+
+	filter := squirrel.Eq{"pvz_id": pvzID}
+	if params.Status != "" {
+		filter["status"] = params.Status
+	}
+
+	countQuery, countArgs, err := q.sq.Select("COUNT(*)").From("reception").Where(filter).ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build count query: %w", err)
+	}
+
+	var total int
+	err = q.db.ReadGetContext(ctx, &total, countQuery, countArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count receptions: %w", err)
+	}
+
+	query, args, err := q.sq.
+		Select(receptionColumns).
 		From("reception").
-		Where(squirrel.Eq{"pvz_id": pvzID, "status": "in_progress"}).
-		OrderBy("datetime DESC").
-		Limit(1)
+		Where(filter).
+		OrderBy("datetime DESC", "id DESC").
+		Limit(uint64(params.Limit)).
+		Offset(uint64((params.Page - 1) * params.Limit)).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build query: %w", err)
+	}
 
-	qsql, args, err := query.ToSql()
+	var receptions []models.Reception
+	err = q.db.ReadSelectContext(ctx, &receptions, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build query: %w", err)
+		return nil, 0, fmt.Errorf("failed to get receptions: %w", err)
 	}
 
+	return receptions, total, nil
+}
+
+// latestReceptionsSQL нумерует приёмки каждого ПВЗ от новых к старым и оставляет первые $2
+var latestReceptionsSQL = db.RegisterSQL("reception.list_latest", `SELECT `+receptionColumns+`
+	FROM (
+		SELECT `+receptionColumns+`,
+			ROW_NUMBER() OVER (PARTITION BY pvz_id ORDER BY datetime DESC, id DESC) AS rn
+		FROM reception
+		WHERE pvz_id = ANY($1)
+	) ranked
+	WHERE rn <= $2
+	ORDER BY pvz_id, datetime DESC, id DESC`)
+
+// GetLatestReceptionsByPVZ получает не больше perPVZ последних приёмок каждого из ПВЗ одним запросом.
+// Приёмки одного ПВЗ идут подряд от новых к старым
+func (q *ReceptionQueries) GetLatestReceptionsByPVZ(ctx context.Context, pvzIDs []string, perPVZ int) ([]models.Reception, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionQueries.GetLatestReceptionsByPVZ")
+	defer span.End()
+
+	if len(pvzIDs) == 0 || perPVZ <= 0 {
+		return nil, nil
+	}
+
+	var receptions []models.Reception
+	err := q.db.ReadSelectContext(ctx, &receptions, latestReceptionsSQL, pq.Array(pvzIDs), perPVZ)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest receptions: %w", err)
+	}
+
+	return receptions, nil
+}
+
+var receptionByIDSQL = db.Register("reception.get", psql.
+	Select(receptionColumns).
+	From("reception").
+	Where("id = ?"))
+
+// GetReceptionByID получает приёмку по ID
+func (q *ReceptionQueries) GetReceptionByID(ctx context.Context, receptionID string) (*models.Reception, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionQueries.GetReceptionByID")
+	defer span.End()
+
 	var reception models.Reception
-	err = q.db.QueryRowxContext(ctx, qsql, args...).StructScan(&reception)
+	err := q.db.QueryRowxContext(ctx, receptionByIDSQL, receptionID).StructScan(&reception)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("no open reception found for pvz %s", pvzID)
+			return nil, fmt.Errorf("reception %s: %w", receptionID, ErrNotFound)
 		}
-		return nil, fmt.Errorf("failed to get open reception: %w", err)
+		return nil, fmt.Errorf("failed to get reception: %w", err)
 	}
 
 	return &reception, nil
 }
 
-// CloseReception закрывает приёмку товаров
-func (q *ReceptionQueries) CloseReception(ctx context.Context, receptionID string) (*models.Reception, error) {
-	query := q.sq.
-		Update("reception").
-		Set("status", "close").
-		Where(squirrel.Eq{"id": receptionID}).
-		Suffix("RETURNING id, datetime, pvz_id, status")
+// LockReceptions получает приёмки по ID и блокирует их до конца транзакции из контекста.
+// Строки блокируются в порядке ID, чтобы встречные блокировки не приводили к взаимоблокировке
+func (q *ReceptionQueries) LockReceptions(ctx context.Context, receptionIDs []string) ([]models.Reception, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionQueries.LockReceptions")
+	defer span.End()
 
-	sql, args, err := query.ToSql()
+	var receptions []models.Reception
+	err := q.db.SelectContext(ctx, &receptions,
+		"SELECT "+receptionColumns+" FROM reception WHERE id = ANY($1) ORDER BY id FOR UPDATE",
+		pq.Array(receptionIDs))
 	if err != nil {
-		return nil, fmt.Errorf("failed to build query: %w", err)
+		return nil, fmt.Errorf("failed to lock receptions: %w", err)
 	}
 
+	return receptions, nil
+}
+
+// reassignReceptionSQL переносит приёмку в другой ПВЗ и записывает перенос в журнал.
+// Товары связаны с приёмкой и переходят вместе с ней, счетчики ПВЗ обновляет триггер.
+// Приёмка с товарами из заказов не переносится: заказ выдается в своем ПВЗ
+var reassignReceptionSQL = db.RegisterSQL("reception.reassign", `WITH moved AS (
+		UPDATE reception SET pvz_id = $3
+		WHERE id = $1
+		AND NOT EXISTS (SELECT 1 FROM product WHERE reception_id = $1 AND order_id IS NOT NULL)
+		RETURNING `+receptionColumns+`
+	), logged AS (
+		INSERT INTO reception_reassignments (reception_id, from_pvz_id, to_pvz_id, reason, reassigned_by)
+		SELECT id, $2, pvz_id, $4, $5 FROM moved
+	)
+	SELECT `+receptionColumns+` FROM moved`)
+
+// ReassignReception переносит приёмку receptionID из ПВЗ fromPvzID в toPvzID и записывает перенос
+// в журнал reception_reassignments. Приёмку нужно заблокировать заранее (LockReceptions):
+// если строка не обновлена, значит товары приёмки входят в заказы, и возвращается ErrReceptionHasOrders
+func (q *ReceptionQueries) ReassignReception(ctx context.Context, receptionID, fromPvzID, toPvzID, userID string, reason *string) (*models.Reception, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionQueries.ReassignReception")
+	defer span.End()
+
 	var reception models.Reception
-	err = q.db.QueryRowxContext(ctx, sql, args...).StructScan(&reception)
+	err := q.db.QueryRowxContext(ctx, reassignReceptionSQL, receptionID, fromPvzID, toPvzID, reason, nullString(userID)).StructScan(&reception)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("reception %s: %w", receptionID, ErrReceptionHasOrders)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to close reception: %w", err)
+		return nil, fmt.Errorf("failed to reassign reception: %w", err)
 	}
 
 	return &reception, nil
 }
 
-// GetReceptionsByPVZ получает все приёмки для ПВЗ
-func (q *ReceptionQueries) GetReceptionsByPVZ(ctx context.Context, pvzID string) ([]models.Reception, error) {
+// FlagOverdueReceptions отмечает открытые приёмки, созданные до openedBefore,
+// и возвращает только те, что отмечены впервые
+func (q *ReceptionQueries) FlagOverdueReceptions(ctx context.Context, openedBefore time.Time) ([]models.OverdueReception, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionQueries.FlagOverdueReceptions")
+	defer span.End()
+
+	flagQuery := `WITH flagged AS (
+		INSERT INTO reception_sla_alerts (reception_id)
+		SELECT id FROM reception WHERE status = 'in_progress' AND datetime < $1
+		ON CONFLICT (reception_id) DO NOTHING
+		RETURNING reception_id, flagged_at
+	)
+	SELECT r.id, r.number, r.datetime, r.pvz_id, r.status, f.flagged_at
+	FROM flagged f
+	JOIN reception r ON r.id = f.reception_id
+	ORDER BY r.datetime`
+
+	var flagged []models.OverdueReception
+	if err := q.db.SelectContext(ctx, &flagged, flagQuery, openedBefore); err != nil {
+		return nil, fmt.Errorf("failed to flag overdue receptions: %w", err)
+	}
+
+	return flagged, nil
+}
+
+// GetOverdueReceptions получает открытые приёмки, не закрытые в срок SLA.
+// При пустом pvzID возвращаются приёмки всех ПВЗ
+func (q *ReceptionQueries) GetOverdueReceptions(ctx context.Context, pvzID string) ([]models.OverdueReception, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionQueries.GetOverdueReceptions")
+	defer span.End()
+
 	query := q.sq.
-		Select("id", "datetime", "pvz_id", "status").
-		From("reception").
-		Where(squirrel.Eq{"pvz_id": pvzID}).
-		OrderBy("datetime DESC")
+		Select("r.id", "r.number", "r.datetime", "r.pvz_id", "r.status", "a.flagged_at").
+		From("reception_sla_alerts a").
+		Join("reception r ON r.id = a.reception_id").
+		Where(squirrel.Eq{"r.status": "in_progress"}).
+		OrderBy("r.datetime")
+
+	if pvzID != "" {
+		query = query.Where(squirrel.Eq{"r.pvz_id": pvzID})
+	}
 
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	var receptions []models.Reception
-	err = q.db.SelectContext(ctx, &receptions, sql, args...)
+	var overdue []models.OverdueReception
+	err = q.db.ReadSelectContext(ctx, &overdue, sql, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get receptions: %w", err)
+		return nil, fmt.Errorf("failed to get overdue receptions: %w", err)
 	}
 
-	return receptions, nil
+	return overdue, nil
+}
+
+// UpdateReceptionNotes меняет заметку и теги приёмки и записывает результат в историю.
+// При nil note заметка не меняется, при nil tags не меняются теги
+func (q *ReceptionQueries) UpdateReceptionNotes(ctx context.Context, receptionID, authorID string, note *string, tags []string) (*models.Reception, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionQueries.UpdateReceptionNotes")
+	defer span.End()
+
+	query := q.sq.
+		Update("reception").
+		Where(squirrel.Eq{"id": receptionID}).
+		Suffix("RETURNING " + receptionColumns)
+	if note != nil {
+		query = query.Set("note", *note)
+	}
+	if tags != nil {
+		query = query.Set("tags", pq.Array(tags))
+	}
+
+	qsql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var reception models.Reception
+	err = q.db.QueryRowxContext(ctx, qsql, args...).StructScan(&reception)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("reception %s: %w", receptionID, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to update reception notes: %w", err)
+	}
+
+	historySQL, historyArgs, err := q.sq.
+		Insert("reception_notes").
+		Columns("reception_id", "author_id", "note", "tags").
+		Values(reception.ID, authorID, reception.Note, pq.Array([]string(reception.Tags))).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := q.db.ExecContext(ctx, historySQL, historyArgs...); err != nil {
+		return nil, fmt.Errorf("failed to save reception note history: %w", err)
+	}
+
+	return &reception, nil
+}
+
+var receptionNotesSQL = db.Register("reception.list_notes", psql.
+	Select("id", "reception_id", "author_id", "note", "tags", "created_at").
+	From("reception_notes").
+	Where("reception_id = ?").
+	OrderBy("created_at"))
+
+// GetReceptionNotes получает историю заметок и тегов приёмки в порядке изменения
+func (q *ReceptionQueries) GetReceptionNotes(ctx context.Context, receptionID string) ([]models.ReceptionNote, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionQueries.GetReceptionNotes")
+	defer span.End()
+
+	var notes []models.ReceptionNote
+	err := q.db.ReadSelectContext(ctx, &notes, receptionNotesSQL, receptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reception notes: %w", err)
+	}
+
+	return notes, nil
+}
+
+// custodyEntriesSQL собирает действия с приёмкой из журналов: открытие и закрытие приёмки, добавление
+// товаров (в том числе удаленных позже), исправления типа, удаления и переносы в другой ПВЗ.
+// Действия одного момента упорядочены по порядку жизненного цикла, действия без времени - в конце
+var custodyEntriesSQL = db.RegisterSQL("reception.custody_entries", `WITH entries AS (
+		SELECT 'reception_opened' AS action, r.datetime AS at, r.created_by AS actor_id,
+			NULL::uuid AS product_id, NULL::text AS product_type, NULL::text AS barcode, NULL::text AS details, 0 AS ord
+		FROM reception r WHERE r.id = $1
+		UNION ALL
+		SELECT 'product_added', p.datetime, p.created_by, p.id, p.type, p.barcode, NULL, 1
+		FROM product p WHERE p.reception_id = $1
+		UNION ALL
+		SELECT 'product_added', d.added_at, d.added_by, d.product_id, d.type, NULL, NULL, 1
+		FROM product_deletions d WHERE d.reception_id = $1 AND d.added_at IS NOT NULL
+		UNION ALL
+		SELECT 'product_retyped', c.created_at, c.author_id, c.product_id, c.new_type, NULL, c.old_type || ' -> ' || c.new_type, 2
+		FROM product_type_changes c JOIN product p ON p.id = c.product_id WHERE p.reception_id = $1
+		UNION ALL
+		SELECT 'product_deleted', d.deleted_at, d.deleted_by, d.product_id, d.type, NULL, NULL, 3
+		FROM product_deletions d WHERE d.reception_id = $1
+		UNION ALL
+		SELECT 'reception_reassigned', a.reassigned_at, a.reassigned_by, NULL, NULL, NULL, a.from_pvz_id::text || ' -> ' || a.to_pvz_id::text, 4
+		FROM reception_reassignments a WHERE a.reception_id = $1
+		UNION ALL
+		SELECT 'reception_closed', r.closed_at, r.closed_by, NULL, NULL, NULL, NULL, 5
+		FROM reception r WHERE r.id = $1 AND r.status = 'close'
+	)
+	SELECT e.action, e.at, e.actor_id, u.email AS actor_email, e.product_id, e.product_type, e.barcode, e.details
+	FROM entries e
+	LEFT JOIN users u ON u.id = e.actor_id
+	ORDER BY e.at NULLS LAST, e.ord`)
+
+// GetCustodyEntries получает действия с приёмкой для документа о цепочке хранения. Запрос выполняется
+// на основной базе, чтобы в документ попали последние действия
+func (q *ReceptionQueries) GetCustodyEntries(ctx context.Context, receptionID string) ([]models.CustodyEntry, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionQueries.GetCustodyEntries")
+	defer span.End()
+
+	var entries []models.CustodyEntry
+	if err := q.db.SelectContext(ctx, &entries, custodyEntriesSQL, receptionID); err != nil {
+		return nil, fmt.Errorf("failed to get custody entries: %w", err)
+	}
+
+	return entries, nil
 }
@@ -0,0 +1,323 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+// ReceptionTemplateQueriesInterface определяет интерфейс для запросов к шаблонам приёмок
+type ReceptionTemplateQueriesInterface interface {
+	ListTemplates(ctx context.Context) ([]models.ReceptionTemplate, error)
+	GetTemplate(ctx context.Context, templateID string) (*models.ReceptionTemplate, error)
+	CreateTemplate(ctx context.Context, authorID string, req models.ReceptionTemplateRequest) (*models.ReceptionTemplate, error)
+	UpdateTemplate(ctx context.Context, templateID string, req models.ReceptionTemplateRequest) (*models.ReceptionTemplate, error)
+	DeleteTemplate(ctx context.Context, templateID string) error
+	ApplyTemplate(ctx context.Context, receptionID, templateID string) (*models.Reception, error)
+}
+
+// ReceptionTemplateQueries содержит методы запросов для работы с шаблонами приёмок
+type ReceptionTemplateQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ ReceptionTemplateQueriesInterface = (*ReceptionTemplateQueries)(nil)
+
+// NewReceptionTemplateQueries создает новый экземпляр ReceptionTemplateQueries
+func NewReceptionTemplateQueries(db *db.Database) *ReceptionTemplateQueries {
+	return &ReceptionTemplateQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+	}
+}
+
+const receptionTemplateColumns = "id, name, note, capacity, created_by, created_at, updated_at"
+
+// ListTemplates получает все шаблоны приёмок со строками в порядке названий
+func (q *ReceptionTemplateQueries) ListTemplates(ctx context.Context) ([]models.ReceptionTemplate, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionTemplateQueries.ListTemplates")
+	defer span.End()
+
+	query, args, err := q.sq.
+		Select(receptionTemplateColumns).
+		From("reception_templates").
+		OrderBy("name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var templates []models.ReceptionTemplate
+	if err := q.db.SelectContext(ctx, &templates, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get reception templates: %w", err)
+	}
+	if len(templates) == 0 {
+		return templates, nil
+	}
+
+	ids := make([]string, 0, len(templates))
+	for _, template := range templates {
+		ids = append(ids, template.ID)
+	}
+
+	lines, err := q.getLines(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byTemplate := make(map[string][]models.ReceptionTemplateLine, len(templates))
+	for _, line := range lines {
+		byTemplate[line.TemplateID] = append(byTemplate[line.TemplateID], line)
+	}
+	for i := range templates {
+		templates[i].Lines = byTemplate[templates[i].ID]
+	}
+
+	return templates, nil
+}
+
+// GetTemplate получает шаблон приёмки со строками, возвращает ErrNotFound, если шаблона нет
+func (q *ReceptionTemplateQueries) GetTemplate(ctx context.Context, templateID string) (*models.ReceptionTemplate, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionTemplateQueries.GetTemplate")
+	defer span.End()
+
+	query, args, err := q.sq.
+		Select(receptionTemplateColumns).
+		From("reception_templates").
+		Where(squirrel.Eq{"id": templateID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var template models.ReceptionTemplate
+	err = q.db.GetContext(ctx, &template, query, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("reception template %s: %w", templateID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reception template: %w", err)
+	}
+
+	template.Lines, err = q.getLines(ctx, []string{templateID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+// CreateTemplate создает шаблон приёмки со строками в одной транзакции.
+// authorID - модератор, создавший шаблон. Если название занято, возвращает ErrTemplateNameTaken
+func (q *ReceptionTemplateQueries) CreateTemplate(ctx context.Context, authorID string, req models.ReceptionTemplateRequest) (*models.ReceptionTemplate, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionTemplateQueries.CreateTemplate")
+	defer span.End()
+
+	query, args, err := q.sq.
+		Insert("reception_templates").
+		Columns("name", "note", "capacity", "created_by").
+		Values(req.Name, req.Note, req.Capacity, nullString(authorID)).
+		Suffix("RETURNING " + receptionTemplateColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var template models.ReceptionTemplate
+	err = q.db.InTx(ctx, func(ctx context.Context) error {
+		if err := q.db.QueryRowxContext(ctx, query, args...).StructScan(&template); err != nil {
+			if isUniqueViolation(err) {
+				return ErrTemplateNameTaken
+			}
+			return fmt.Errorf("failed to create reception template: %w", err)
+		}
+		return q.insertLines(ctx, template.ID, req.Lines)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	template.Lines = req.Lines
+	return &template, nil
+}
+
+// UpdateTemplate изменяет шаблон приёмки и заменяет его строки в одной транзакции.
+// Возвращает ErrNotFound, если шаблона нет, и ErrTemplateNameTaken, если название занято другим шаблоном
+func (q *ReceptionTemplateQueries) UpdateTemplate(ctx context.Context, templateID string, req models.ReceptionTemplateRequest) (*models.ReceptionTemplate, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionTemplateQueries.UpdateTemplate")
+	defer span.End()
+
+	query, args, err := q.sq.
+		Update("reception_templates").
+		Set("name", req.Name).
+		Set("note", req.Note).
+		Set("capacity", req.Capacity).
+		Set("updated_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"id": templateID}).
+		Suffix("RETURNING " + receptionTemplateColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	deleteSQL, deleteArgs, err := q.sq.
+		Delete("reception_template_lines").
+		Where(squirrel.Eq{"template_id": templateID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var template models.ReceptionTemplate
+	err = q.db.InTx(ctx, func(ctx context.Context) error {
+		err := q.db.QueryRowxContext(ctx, query, args...).StructScan(&template)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("reception template %s: %w", templateID, ErrNotFound)
+		}
+		if isUniqueViolation(err) {
+			return ErrTemplateNameTaken
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update reception template: %w", err)
+		}
+
+		if _, err := q.db.ExecContext(ctx, deleteSQL, deleteArgs...); err != nil {
+			return fmt.Errorf("failed to delete reception template lines: %w", err)
+		}
+		return q.insertLines(ctx, templateID, req.Lines)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	template.Lines = req.Lines
+	return &template, nil
+}
+
+// DeleteTemplate удаляет шаблон приёмки вместе со строками, возвращает ErrNotFound, если шаблона нет.
+// У созданных по шаблону приёмок ссылка на шаблон очищается, их накладная не меняется
+func (q *ReceptionTemplateQueries) DeleteTemplate(ctx context.Context, templateID string) error {
+	ctx, span := tracing.Start(ctx, "ReceptionTemplateQueries.DeleteTemplate")
+	defer span.End()
+
+	query, args, err := q.sq.
+		Delete("reception_templates").
+		Where(squirrel.Eq{"id": templateID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to delete reception template: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("reception template %s: %w", templateID, ErrNotFound)
+	}
+
+	return nil
+}
+
+// applyTemplateSQL копирует вместимость шаблона в приёмку и запоминает шаблон.
+// Столбцы приёмки перечислены с псевдонимом: id и note есть и в шаблоне
+var applyTemplateSQL = db.RegisterSQL("reception_template.apply", `UPDATE reception r
+	SET capacity = t.capacity, template_id = t.id
+	FROM reception_templates t
+	WHERE r.id = $1 AND t.id = $2
+	RETURNING r.id, r.number, r.datetime, r.pvz_id, r.status, r.note, r.tags, r.product_count, r.capacity, r.template_id, r.supplier_id`)
+
+// applyTemplateLinesSQL копирует строки шаблона в накладную приёмки
+var applyTemplateLinesSQL = db.RegisterSQL("reception_template.apply_lines", `INSERT INTO expected_products (reception_id, type, barcode, quantity)
+	SELECT $1, type, barcode, quantity
+	FROM reception_template_lines
+	WHERE template_id = $2
+	ORDER BY position`)
+
+// ApplyTemplate копирует в новую приёмку вместимость шаблона и его строки как накладную.
+// Заметка шаблона не копируется: ее записывает в историю UpdateReceptionNotes.
+// Вызывается в транзакции создания приёмки, возвращает ErrNotFound, если приёмки или шаблона нет
+func (q *ReceptionTemplateQueries) ApplyTemplate(ctx context.Context, receptionID, templateID string) (*models.Reception, error) {
+	ctx, span := tracing.Start(ctx, "ReceptionTemplateQueries.ApplyTemplate")
+	defer span.End()
+
+	var reception models.Reception
+	err := q.db.QueryRowxContext(ctx, applyTemplateSQL, receptionID, templateID).StructScan(&reception)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("reception template %s: %w", templateID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply reception template: %w", err)
+	}
+
+	if _, err := q.db.ExecContext(ctx, applyTemplateLinesSQL, receptionID, templateID); err != nil {
+		return nil, fmt.Errorf("failed to copy reception template lines: %w", err)
+	}
+
+	return &reception, nil
+}
+
+// getLines получает строки шаблонов в порядке их добавления
+func (q *ReceptionTemplateQueries) getLines(ctx context.Context, templateIDs []string) ([]models.ReceptionTemplateLine, error) {
+	query, args, err := q.sq.
+		Select("template_id", "type", "barcode", "quantity").
+		From("reception_template_lines").
+		Where("template_id = ANY(?)", pq.Array(templateIDs)).
+		OrderBy("template_id", "position").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var lines []models.ReceptionTemplateLine
+	if err := q.db.SelectContext(ctx, &lines, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get reception template lines: %w", err)
+	}
+
+	return lines, nil
+}
+
+// insertLines добавляет строки шаблона, позиция строки сохраняет порядок из запроса
+func (q *ReceptionTemplateQueries) insertLines(ctx context.Context, templateID string, lines []models.ReceptionTemplateLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	insert := q.sq.
+		Insert("reception_template_lines").
+		Columns("template_id", "position", "type", "barcode", "quantity")
+	for i, line := range lines {
+		insert = insert.Values(templateID, i, line.Type, line.Barcode, line.Quantity)
+	}
+
+	query, args, err := insert.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := q.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to insert reception template lines: %w", err)
+	}
+
+	return nil
+}
+
+// isUniqueViolation сообщает, что запрос нарушил уникальный индекс
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
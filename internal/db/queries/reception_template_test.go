@@ -0,0 +1,132 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+)
+
+func setupReceptionTemplateQueriesTest(t *testing.T) (*ReceptionTemplateQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &ReceptionTemplateQueries{
+		db: &db.Database{DB: sqlxDB},
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+var receptionTemplateRows = []string{"id", "name", "note", "capacity", "created_by", "created_at", "updated_at"}
+
+func TestReceptionTemplateQueries_CreateTemplate(t *testing.T) {
+	q, mock := setupReceptionTemplateQueriesTest(t)
+	capacity := 40
+	req := models.ReceptionTemplateRequest{
+		Name:     "Еженедельная поставка обуви",
+		Note:     "Коробки по 10 пар",
+		Capacity: &capacity,
+		Lines: []models.ReceptionTemplateLine{
+			{Type: models.ProductTypeShoes, Quantity: 30},
+			{Type: models.ProductTypeClothes, Quantity: 10},
+		},
+	}
+
+	t.Run("Шаблон со строками", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO reception_templates \(name,note,capacity,created_by\) VALUES \(\$1,\$2,\$3,\$4\) RETURNING id, name, note, capacity, created_by, created_at, updated_at`).
+			WithArgs(req.Name, req.Note, &capacity, "user-1").
+			WillReturnRows(sqlmock.NewRows(receptionTemplateRows).
+				AddRow("template-1", req.Name, req.Note, capacity, "user-1", time.Now(), time.Now()))
+		mock.ExpectExec(`INSERT INTO reception_template_lines \(template_id,position,type,barcode,quantity\) VALUES \(\$1,\$2,\$3,\$4,\$5\),\(\$6,\$7,\$8,\$9,\$10\)`).
+			WithArgs("template-1", 0, models.ProductTypeShoes, nil, 30, "template-1", 1, models.ProductTypeClothes, nil, 10).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		template, err := q.CreateTemplate(context.Background(), "user-1", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, "template-1", template.ID)
+		assert.Equal(t, 40, *template.Capacity)
+		assert.Len(t, template.Lines, 2)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Название занято", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO reception_templates`).
+			WillReturnError(&pq.Error{Code: "23505"})
+		mock.ExpectRollback()
+
+		_, err := q.CreateTemplate(context.Background(), "user-1", req)
+
+		assert.True(t, errors.Is(err, ErrTemplateNameTaken))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReceptionTemplateQueries_ListTemplates(t *testing.T) {
+	q, mock := setupReceptionTemplateQueriesTest(t)
+
+	mock.ExpectQuery(`SELECT id, name, note, capacity, created_by, created_at, updated_at FROM reception_templates ORDER BY name`).
+		WillReturnRows(sqlmock.NewRows(receptionTemplateRows).
+			AddRow("template-1", "Обувь", "", nil, nil, time.Now(), time.Now()).
+			AddRow("template-2", "Электроника", "", 5, nil, time.Now(), time.Now()))
+	mock.ExpectQuery(`SELECT template_id, type, barcode, quantity FROM reception_template_lines WHERE template_id = ANY\(\$1\) ORDER BY template_id, position`).
+		WithArgs(pq.Array([]string{"template-1", "template-2"})).
+		WillReturnRows(sqlmock.NewRows([]string{"template_id", "type", "barcode", "quantity"}).
+			AddRow("template-1", models.ProductTypeShoes, nil, 30).
+			AddRow("template-1", models.ProductTypeClothes, "4600000000017", 1))
+
+	templates, err := q.ListTemplates(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, templates, 2)
+	assert.Len(t, templates[0].Lines, 2)
+	assert.Equal(t, "4600000000017", *templates[0].Lines[1].Barcode)
+	assert.Nil(t, templates[0].Capacity)
+	assert.Empty(t, templates[1].Lines)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReceptionTemplateQueries_ApplyTemplate(t *testing.T) {
+	q, mock := setupReceptionTemplateQueriesTest(t)
+
+	t.Run("Вместимость и накладная копируются в приёмку", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE reception r SET capacity = t.capacity, template_id = t.id FROM reception_templates t WHERE r.id = \$1 AND t.id = \$2 RETURNING r.id, .*r.capacity, r.template_id`).
+			WithArgs("reception-1", "template-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "number", "datetime", "pvz_id", "status", "note", "tags", "product_count", "capacity", "template_id"}).
+				AddRow("reception-1", "MSK001-2026-000001", time.Now(), "pvz-1", "in_progress", "", "{}", 0, 40, "template-1"))
+		mock.ExpectExec(`INSERT INTO expected_products \(reception_id, type, barcode, quantity\) SELECT \$1, type, barcode, quantity FROM reception_template_lines WHERE template_id = \$2 ORDER BY position`).
+			WithArgs("reception-1", "template-1").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		reception, err := q.ApplyTemplate(context.Background(), "reception-1", "template-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, 40, *reception.Capacity)
+		assert.Equal(t, "template-1", *reception.TemplateID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Шаблон удален", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE reception r`).
+			WithArgs("reception-1", "template-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := q.ApplyTemplate(context.Background(), "reception-1", "template-1")
+
+		assert.True(t, errors.Is(err, ErrNotFound))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,274 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+)
+
+// testNow - время часов запросов в тестах
+var testNow = time.Date(2026, 3, 1, 21, 30, 0, 0, time.UTC)
+
+func setupReceptionQueriesTest(t *testing.T) (*ReceptionQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &ReceptionQueries{
+		db:    &db.Database{DB: sqlxDB},
+		sq:    squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		clock: clock.Fixed(testNow),
+	}, mock
+}
+
+func TestReceptionQueries_CloseStaleReceptions(t *testing.T) {
+	q, mock := setupReceptionQueriesTest(t)
+	openedBefore := time.Now().Add(-24 * time.Hour)
+
+	t.Run("Закрытые приёмки", func(t *testing.T) {
+		mock.ExpectQuery(`WITH closed AS \(\s*UPDATE reception SET status = 'close'\s*WHERE status = 'in_progress' AND datetime < \$1.*UPDATE product SET status = \$2\s*WHERE status = \$3 AND reception_id IN \(SELECT id FROM closed\)`).
+			WithArgs(openedBefore, models.ProductLifecycleStored, models.ProductLifecycleReceived).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "datetime", "pvz_id", "status", "note", "tags", "product_count"}).
+				AddRow("reception-1", openedBefore.Add(-time.Hour), "pvz-1", "close", "", "{}", 3).
+				AddRow("reception-2", openedBefore.Add(-time.Minute), "pvz-2", "close", "", "{}", 0))
+
+		closed, err := q.CloseStaleReceptions(context.Background(), openedBefore)
+
+		assert.NoError(t, err)
+		assert.Len(t, closed, 2)
+		assert.Equal(t, "reception-1", closed[0].ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Ошибка базы данных", func(t *testing.T) {
+		mock.ExpectQuery(`WITH closed AS`).WillReturnError(errors.New("database error"))
+
+		_, err := q.CloseStaleReceptions(context.Background(), openedBefore)
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReceptionQueries_CloseReception(t *testing.T) {
+	q, mock := setupReceptionQueriesTest(t)
+
+	t.Run("Повторное закрытие приёмки", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`UPDATE reception SET status = \$1, closed_by = \$2 WHERE id = \$3 AND status IN \('in_progress', 'paused'\)`).
+			WithArgs("close", "user-1", "reception-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		_, err := q.CloseReception(context.Background(), "reception-1", "user-1")
+
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReceptionQueries_CreateReception(t *testing.T) {
+	q, mock := setupReceptionQueriesTest(t)
+
+	t.Run("Номер из счётчика приёмок ПВЗ за год", func(t *testing.T) {
+		mock.ExpectQuery(`WITH seq AS \(\s*INSERT INTO reception_number_counters \(pvz_id, year, last_number\)\s*VALUES \(\$3, \$5, 1\)\s*`+
+			`ON CONFLICT \(pvz_id, year\) DO UPDATE SET last_number = reception_number_counters.last_number \+ 1.*`+
+			`INSERT INTO reception \(id, datetime, pvz_id, status, number, created_by\).*FROM pvz p, seq\s*WHERE p.id = \$3\s*`+
+			`ON CONFLICT \(pvz_id\) WHERE status = 'in_progress' DO NOTHING\s*RETURNING id, number, datetime`).
+			WithArgs(sqlmock.AnyArg(), testNow, "pvz-1", "in_progress", 2026, "user-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "number", "datetime", "pvz_id", "status", "note", "tags", "product_count"}).
+				AddRow("reception-1", "MSK001-2026-000123", testNow, "pvz-1", "in_progress", "", "{}", 0))
+
+		reception, err := q.CreateReception(context.Background(), "pvz-1", "user-1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "MSK001-2026-000123", reception.Number)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("В ПВЗ уже есть открытая приёмка", func(t *testing.T) {
+		mock.ExpectQuery(`WITH seq AS`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(`SELECT id, number, datetime, pvz_id, status, note, tags, product_count, capacity, template_id, supplier_id FROM reception WHERE pvz_id = \$1 AND status = \$2`).
+			WithArgs("pvz-1", "in_progress").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "number", "datetime", "pvz_id", "status", "note", "tags", "product_count"}).
+				AddRow("reception-0", "MSK001-2026-000122", testNow, "pvz-1", "in_progress", "", "{}", 3))
+
+		_, err := q.CreateReception(context.Background(), "pvz-1", "user-1")
+
+		var openErr *ReceptionOpenError
+		assert.ErrorAs(t, err, &openErr)
+		assert.Equal(t, "reception-0", openErr.Reception.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ПВЗ не найден", func(t *testing.T) {
+		mock.ExpectQuery(`WITH seq AS`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(`SELECT .* FROM reception WHERE pvz_id = \$1 AND status = \$2`).
+			WithArgs("pvz-1", "in_progress").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := q.CreateReception(context.Background(), "pvz-1", "user-1")
+
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Неизвестный ПВЗ нарушает внешний ключ счётчика номеров", func(t *testing.T) {
+		mock.ExpectQuery(`WITH seq AS`).WillReturnError(&pq.Error{Code: "23503"})
+
+		_, err := q.CreateReception(context.Background(), "pvz-1", "user-1")
+
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Ошибка базы данных", func(t *testing.T) {
+		mock.ExpectQuery(`WITH seq AS`).WillReturnError(errors.New("database error"))
+
+		_, err := q.CreateReception(context.Background(), "pvz-1", "user-1")
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReceptionQueries_ResumeReception(t *testing.T) {
+	q, mock := setupReceptionQueriesTest(t)
+	columns := []string{"id", "number", "datetime", "pvz_id", "status", "note", "tags", "product_count"}
+
+	t.Run("Приостановленная приёмка возобновляется", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE reception SET status = 'in_progress'\s+WHERE id = \(\s+SELECT id FROM reception WHERE pvz_id = \$1 AND status = 'paused'`).
+			WithArgs("pvz-1").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow("reception-1", "MSK001-2026-000001", testNow, "pvz-1", "in_progress", "", "{}", 2))
+
+		reception, err := q.ResumeReception(context.Background(), "pvz-1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "in_progress", reception.Status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("За время паузы открыта другая приёмка", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE reception SET status = 'in_progress'`).WithArgs("pvz-1").WillReturnRows(sqlmock.NewRows(columns))
+		mock.ExpectQuery(`SELECT .* FROM reception WHERE pvz_id = \$1 AND status = \$2`).
+			WithArgs("pvz-1", "in_progress").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow("reception-2", "MSK001-2026-000002", testNow, "pvz-1", "in_progress", "", "{}", 0))
+
+		_, err := q.ResumeReception(context.Background(), "pvz-1")
+
+		var openErr *ReceptionOpenError
+		assert.ErrorAs(t, err, &openErr)
+		assert.Equal(t, "reception-2", openErr.Reception.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Нет приостановленной приёмки", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE reception SET status = 'in_progress'`).WithArgs("pvz-1").WillReturnRows(sqlmock.NewRows(columns))
+		mock.ExpectQuery(`SELECT .* FROM reception WHERE pvz_id = \$1 AND status = \$2`).
+			WithArgs("pvz-1", "in_progress").
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		_, err := q.ResumeReception(context.Background(), "pvz-1")
+
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReceptionQueries_LockReceptions(t *testing.T) {
+	q, mock := setupReceptionQueriesTest(t)
+
+	mock.ExpectQuery(`SELECT id, number, datetime, pvz_id, status, note, tags, product_count, capacity, template_id, supplier_id FROM reception WHERE id = ANY\(\$1\) ORDER BY id FOR UPDATE`).
+		WithArgs(pq.Array([]string{"reception-2", "reception-1"})).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "pvz_id", "status"}).
+			AddRow("reception-1", "pvz-1", "in_progress").
+			AddRow("reception-2", "pvz-1", "in_progress"))
+
+	receptions, err := q.LockReceptions(context.Background(), []string{"reception-2", "reception-1"})
+
+	assert.NoError(t, err)
+	assert.Len(t, receptions, 2)
+	assert.Equal(t, "reception-1", receptions[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReceptionQueries_ListReceptions(t *testing.T) {
+	q, mock := setupReceptionQueriesTest(t)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM reception WHERE pvz_id = \$1 AND status = \$2`).
+		WithArgs("pvz-1", "close").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(`SELECT `+receptionColumns+` FROM reception WHERE pvz_id = \$1 AND status = \$2 ORDER BY datetime DESC, id DESC LIMIT 2 OFFSET 2`).
+		WithArgs("pvz-1", "close").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "pvz_id", "status"}).AddRow("reception-1", "pvz-1", "close"))
+
+	receptions, total, err := q.ListReceptions(context.Background(), "pvz-1", models.ReceptionListQuery{Status: "close", Page: 2, Limit: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, receptions, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReceptionQueries_GetLatestReceptionsByPVZ(t *testing.T) {
+	q, mock := setupReceptionQueriesTest(t)
+
+	t.Run("Последние приёмки каждого ПВЗ", func(t *testing.T) {
+		mock.ExpectQuery(`ROW_NUMBER\(\) OVER \(PARTITION BY pvz_id ORDER BY datetime DESC, id DESC\) AS rn\s+FROM reception\s+WHERE pvz_id = ANY\(\$1\)\s+\) ranked\s+WHERE rn <= \$2`).
+			WithArgs(pq.Array([]string{"pvz-1", "pvz-2"}), 6).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "pvz_id", "status"}).
+				AddRow("reception-2", "pvz-1", "in_progress").
+				AddRow("reception-1", "pvz-1", "close").
+				AddRow("reception-3", "pvz-2", "close"))
+
+		receptions, err := q.GetLatestReceptionsByPVZ(context.Background(), []string{"pvz-1", "pvz-2"}, 6)
+
+		assert.NoError(t, err)
+		assert.Len(t, receptions, 3)
+		assert.Equal(t, "reception-2", receptions[0].ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Пустой список ПВЗ", func(t *testing.T) {
+		receptions, err := q.GetLatestReceptionsByPVZ(context.Background(), nil, 6)
+
+		assert.NoError(t, err)
+		assert.Empty(t, receptions)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReceptionQueries_GetCustodyEntries(t *testing.T) {
+	q, mock := setupReceptionQueriesTest(t)
+	receptionID := "123e4567-e89b-12d3-a456-426614174040"
+	actorID := "123e4567-e89b-12d3-a456-426614174099"
+
+	mock.ExpectQuery(`WITH entries AS \(.+FROM product_deletions d WHERE d.reception_id = \$1 AND d.added_at IS NOT NULL.+\) ` +
+		`SELECT e.action, e.at, e.actor_id, u.email AS actor_email, e.product_id, e.product_type, e.barcode, e.details FROM entries e ` +
+		`LEFT JOIN users u ON u.id = e.actor_id ORDER BY e.at NULLS LAST, e.ord`).
+		WithArgs(receptionID).
+		WillReturnRows(sqlmock.NewRows([]string{"action", "at", "actor_id", "actor_email", "product_id", "product_type", "barcode", "details"}).
+			AddRow("reception_opened", testNow, actorID, "employee@example.com", nil, nil, nil, nil).
+			AddRow("product_retyped", testNow.Add(time.Minute), actorID, nil, "p1", "обувь", nil, "одежда -> обувь").
+			AddRow("reception_closed", nil, nil, nil, nil, nil, nil, nil))
+
+	entries, err := q.GetCustodyEntries(context.Background(), receptionID)
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "employee@example.com", *entries[0].ActorEmail)
+	assert.Equal(t, "одежда -> обувь", *entries[1].Details)
+	assert.Nil(t, entries[2].At)
+	assert.Nil(t, entries[2].ActorID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
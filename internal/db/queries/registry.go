@@ -0,0 +1,8 @@
+package queries
+
+import "github.com/Masterminds/squirrel"
+
+// psql собирает запросы с постоянным текстом для реестра db.Register при инициализации пакета.
+// Значения в таких запросах - плейсхолдеры ?, в Set вместо значения передается nil,
+// сами значения передаются при выполнении
+var psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
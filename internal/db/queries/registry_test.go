@@ -0,0 +1,74 @@
+package queries
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/db"
+)
+
+var placeholderPattern = regexp.MustCompile(`\$(\d+)`)
+
+// placeholderCount возвращает номер последнего плейсхолдера запроса
+func placeholderCount(sql string) int {
+	count := 0
+	for _, match := range placeholderPattern.FindAllStringSubmatch(sql, -1) {
+		n, _ := strconv.Atoi(match[1])
+		count = max(count, n)
+	}
+	return count
+}
+
+// TestRegisteredQueries проверяет, что в реестре нет незамененных ? и пропущенных плейсхолдеров
+func TestRegisteredQueries(t *testing.T) {
+	registered := db.RegisteredQueries()
+	require.NotEmpty(t, registered)
+
+	for _, query := range registered {
+		assert.NotContains(t, query.SQL, "?", query.Name)
+		for n := 1; n <= placeholderCount(query.SQL); n++ {
+			assert.Contains(t, query.SQL, "$"+strconv.Itoa(n), query.Name)
+		}
+	}
+}
+
+// TestRegisteredQueriesExplain строит план каждого запроса реестра на базе TEST_DATABASE_URL
+// со всеми миграциями: запрос, который ссылается на удаленную колонку или таблицу, не пройдет проверку.
+// Запросы не выполняются, планы выводятся с -v. Без TEST_DATABASE_URL тест пропускается
+func TestRegisteredQueriesExplain(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	conn, err := sqlx.Connect("postgres", dsn)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	for _, query := range db.RegisteredQueries() {
+		t.Run(query.Name, func(t *testing.T) {
+			// Типы параметров выводятся сервером так же, как при выполнении запроса драйвером
+			_, err := conn.Exec("PREPARE registry_check AS " + query.SQL)
+			require.NoError(t, err)
+			defer conn.Exec("DEALLOCATE registry_check")
+
+			params := strings.TrimSuffix(strings.Repeat("NULL, ", placeholderCount(query.SQL)), ", ")
+			explain := "EXPLAIN EXECUTE registry_check"
+			if params != "" {
+				explain += fmt.Sprintf("(%s)", params)
+			}
+
+			var plan []string
+			require.NoError(t, conn.Select(&plan, explain))
+			t.Log("\n" + strings.Join(plan, "\n"))
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// ReportQueriesInterface определяет интерфейс для запросов отчётов
+type ReportQueriesInterface interface {
+	GetDuplicateBarcodes(ctx context.Context, since time.Time) ([]models.BarcodeOccurrence, error)
+	GetInvariantViolations(ctx context.Context) ([]models.InvariantViolation, error)
+	GetUserActivity(ctx context.Context, userID string, from, to time.Time) (*models.UserActivity, error)
+	StreamCityReceptions(ctx context.Context, city string, from, to time.Time, fn func(models.ReceptionExportRow) error) error
+	GetDailyIntake(ctx context.Context, pvzID, timezone string, from, to time.Time) ([]models.DailyIntake, error)
+	GetAgingProducts(ctx context.Context, storedBefore time.Time, city string) ([]models.AgingProduct, error)
+	GetSupplierIntake(ctx context.Context, from, to time.Time) ([]models.SupplierIntake, error)
+	GetDamagedProducts(ctx context.Context, from, to time.Time) ([]models.ProductDamage, error)
+}
+
+// ReportQueries содержит методы запросов для построения отчётов
+type ReportQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ ReportQueriesInterface = (*ReportQueries)(nil)
+
+// NewReportQueries создает новый экземпляр ReportQueries
+func NewReportQueries(db *db.Database) *ReportQueries {
+	return &ReportQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+	}
+}
+
+// GetDuplicateBarcodes получает все сканирования штрихкодов, которые начиная с since
+// встречались в нескольких открытых приёмках или в нескольких ПВЗ
+func (q *ReportQueries) GetDuplicateBarcodes(ctx context.Context, since time.Time) ([]models.BarcodeOccurrence, error) {
+	ctx, span := tracing.Start(ctx, "ReportQueries.GetDuplicateBarcodes")
+	defer span.End()
+
+	duplicates := q.sq.
+		Select("dp.barcode").
+		From("product dp").
+		Join("reception dr ON dr.id = dp.reception_id").
+		Where(squirrel.NotEq{"dp.barcode": nil}).
+		Where(squirrel.GtOrEq{"dp.datetime": since}).
+		GroupBy("dp.barcode").
+		Having("COUNT(DISTINCT dr.id) FILTER (WHERE dr.status = 'in_progress') > 1 OR COUNT(DISTINCT dr.pvz_id) > 1")
+
+	query := q.sq.
+		Select(
+			"p.barcode",
+			"p.id AS product_id",
+			"p.datetime",
+			"p.reception_id",
+			"r.number AS reception_number",
+			"r.status AS reception_status",
+			"r.pvz_id",
+			"pvz.city",
+		).
+		From("product p").
+		Join("reception r ON r.id = p.reception_id").
+		Join("pvz ON pvz.id = r.pvz_id").
+		Where(squirrel.GtOrEq{"p.datetime": since}).
+		Where(duplicates.Prefix("p.barcode IN (").Suffix(")")).
+		OrderBy("p.barcode", "p.datetime")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var occurrences []models.BarcodeOccurrence
+	err = q.db.ReadSelectContext(ctx, &occurrences, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get duplicate barcodes: %w", err)
+	}
+
+	return occurrences, nil
+}
+
+// invariantViolationsSQL ищет нарушения инвариантов, которые не гарантируются ограничениями схемы:
+// несколько открытых приёмок одного ПВЗ, товары закрытой приёмки в статусе received
+// (при закрытии все товары переводятся на хранение) и товары в наличии в архивных ПВЗ
+var invariantViolationsSQL = db.RegisterSQL("report.invariant_violations", `SELECT 'multiple_open_receptions' AS kind, r.pvz_id, r.id AS reception_id, NULL AS product_id
+	FROM reception r
+	WHERE r.status = 'in_progress' AND r.pvz_id IN (
+		SELECT pvz_id FROM reception WHERE status = 'in_progress' GROUP BY pvz_id HAVING COUNT(*) > 1
+	)
+	UNION ALL
+	SELECT 'product_after_close', r.pvz_id, r.id, p.id
+	FROM product p
+	JOIN reception r ON r.id = p.reception_id
+	WHERE r.status = 'close' AND p.status = 'received'
+	UNION ALL
+	SELECT 'orphaned_product', r.pvz_id, r.id, p.id
+	FROM product p
+	JOIN reception r ON r.id = p.reception_id
+	JOIN pvz ON pvz.id = r.pvz_id
+	WHERE pvz.archived_at IS NOT NULL AND p.status IN ('received', 'stored')
+	ORDER BY kind, pvz_id, reception_id`)
+
+// GetInvariantViolations получает все нарушения инвариантов данных. Запрос выполняется
+// на основной базе: отставание реплики давало бы ложные нарушения
+func (q *ReportQueries) GetInvariantViolations(ctx context.Context) ([]models.InvariantViolation, error) {
+	ctx, span := tracing.Start(ctx, "ReportQueries.GetInvariantViolations")
+	defer span.End()
+
+	var violations []models.InvariantViolation
+	if err := q.db.SelectContext(ctx, &violations, invariantViolationsSQL); err != nil {
+		return nil, fmt.Errorf("failed to get invariant violations: %w", err)
+	}
+
+	return violations, nil
+}
+
+// userActivitySQL считает приёмки и товары, записанные на пользователя. Удаленные товары
+// учитываются по журналу удалений, принятые - только оставшиеся в приёмках
+var userActivitySQL = db.RegisterSQL("report.user_activity", `SELECT
+	(SELECT COUNT(*) FROM reception WHERE created_by = $1 AND datetime >= $2 AND datetime < $3) AS receptions_created,
+	(SELECT COUNT(*) FROM reception WHERE closed_by = $1 AND datetime >= $2 AND datetime < $3) AS receptions_closed,
+	(SELECT COUNT(*) FROM product WHERE created_by = $1 AND datetime >= $2 AND datetime < $3) AS products_added,
+	(SELECT COUNT(*) FROM product_deletions WHERE deleted_by = $1 AND deleted_at >= $2 AND deleted_at < $3) AS products_deleted`)
+
+// GetUserActivity получает объем работы пользователя за период [from, to)
+func (q *ReportQueries) GetUserActivity(ctx context.Context, userID string, from, to time.Time) (*models.UserActivity, error) {
+	ctx, span := tracing.Start(ctx, "ReportQueries.GetUserActivity")
+	defer span.End()
+
+	activity := models.UserActivity{UserID: userID, From: from, To: to}
+	if err := q.db.ReadGetContext(ctx, &activity, userActivitySQL, userID, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get user activity: %w", err)
+	}
+
+	return &activity, nil
+}
+
+// StreamCityReceptions передает в fn приёмки всех ПВЗ города, открытые в период [from, to),
+// по ПВЗ и времени открытия. Строки читаются с реплики курсором, без загрузки всей выгрузки в память
+func (q *ReportQueries) StreamCityReceptions(ctx context.Context, city string, from, to time.Time, fn func(models.ReceptionExportRow) error) error {
+	ctx, span := tracing.Start(ctx, "ReportQueries.StreamCityReceptions")
+	defer span.End()
+
+	query := q.sq.
+		Select(
+			"r.pvz_id",
+			"pvz.city",
+			"r.id AS reception_id",
+			"r.number",
+			"r.datetime",
+			"r.status",
+			"(SELECT COUNT(*) FROM product p WHERE p.reception_id = r.id) AS product_count",
+			"r.created_by",
+			"r.closed_by",
+		).
+		From("reception r").
+		Join("pvz ON pvz.id = r.pvz_id").
+		Where(squirrel.Eq{"pvz.city": city}).
+		Where(squirrel.GtOrEq{"r.datetime": from}).
+		Where(squirrel.Lt{"r.datetime": to}).
+		OrderBy("r.pvz_id", "r.datetime", "r.id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := q.db.ReadQueryxContext(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to get city receptions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.ReceptionExportRow
+		if err := rows.StructScan(&row); err != nil {
+			return fmt.Errorf("failed to scan city reception: %w", err)
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read city receptions: %w", err)
+	}
+
+	return nil
+}
+
+// dailyIntakeSQL считает товары ПВЗ по местным дням. Дни без товаров в результат не попадают
+var dailyIntakeSQL = db.RegisterSQL("report.daily_intake", `SELECT (p.datetime AT TIME ZONE $2)::date AS day, COUNT(*) AS products
+	FROM product p
+	JOIN reception r ON r.id = p.reception_id
+	WHERE r.pvz_id = $1 AND p.datetime >= $3 AND p.datetime < $4
+	GROUP BY day
+	ORDER BY day`)
+
+// GetDailyIntake получает число товаров, принятых в ПВЗ за период [from, to), по дням в часовом поясе timezone
+func (q *ReportQueries) GetDailyIntake(ctx context.Context, pvzID, timezone string, from, to time.Time) ([]models.DailyIntake, error) {
+	ctx, span := tracing.Start(ctx, "ReportQueries.GetDailyIntake")
+	defer span.End()
+
+	var intake []models.DailyIntake
+	if err := q.db.ReadSelectContext(ctx, &intake, dailyIntakeSQL, pvzID, timezone, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get daily intake: %w", err)
+	}
+
+	return intake, nil
+}
+
+// GetAgingProducts получает товары на хранении в закрытых приёмках, закрытых раньше storedBefore,
+// по городу, ПВЗ и времени закрытия. Непустой city ограничивает выборку одним городом.
+// Приёмки, закрытые до появления closed_at, считаются закрытыми в момент открытия
+func (q *ReportQueries) GetAgingProducts(ctx context.Context, storedBefore time.Time, city string) ([]models.AgingProduct, error) {
+	ctx, span := tracing.Start(ctx, "ReportQueries.GetAgingProducts")
+	defer span.End()
+
+	query := q.sq.
+		Select(
+			"p.id AS product_id",
+			"p.type",
+			"p.barcode",
+			"r.id AS reception_id",
+			"r.number AS reception_number",
+			"r.pvz_id",
+			"pvz.city",
+			"pvz.address",
+			"COALESCE(r.closed_at, r.datetime) AS stored_since",
+		).
+		From("product p").
+		Join("reception r ON r.id = p.reception_id").
+		Join("pvz ON pvz.id = r.pvz_id").
+		Where(squirrel.Eq{"p.status": models.ProductLifecycleStored}).
+		Where(squirrel.Eq{"r.status": "close"}).
+		Where(squirrel.Eq{"pvz.archived_at": nil}).
+		Where(squirrel.Lt{"COALESCE(r.closed_at, r.datetime)": storedBefore}).
+		OrderBy("pvz.city", "r.pvz_id", "stored_since", "p.id")
+
+	if city != "" {
+		query = query.Where(squirrel.Eq{"pvz.city": city})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var products []models.AgingProduct
+	if err := q.db.ReadSelectContext(ctx, &products, sql, args...); err != nil {
+		return nil, fmt.Errorf("failed to get aging products: %w", err)
+	}
+
+	return products, nil
+}
+
+// supplierIntakeSQL считает приёмки и товары по поставщикам, приёмки без поставщика - одной строкой
+var supplierIntakeSQL = db.RegisterSQL("report.supplier_intake", `SELECT r.supplier_id, s.name AS supplier_name, COUNT(*) AS receptions, COALESCE(SUM(r.product_count), 0) AS products
+	FROM reception r
+	LEFT JOIN suppliers s ON s.id = r.supplier_id
+	WHERE r.datetime >= $1 AND r.datetime < $2
+	GROUP BY r.supplier_id, s.name
+	ORDER BY products DESC, s.name`)
+
+// GetSupplierIntake получает число приёмок, открытых в период [from, to), и принятых в них товаров по поставщикам
+func (q *ReportQueries) GetSupplierIntake(ctx context.Context, from, to time.Time) ([]models.SupplierIntake, error) {
+	ctx, span := tracing.Start(ctx, "ReportQueries.GetSupplierIntake")
+	defer span.End()
+
+	var intake []models.SupplierIntake
+	if err := q.db.ReadSelectContext(ctx, &intake, supplierIntakeSQL, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get supplier intake: %w", err)
+	}
+
+	return intake, nil
+}
+
+// damagedProductsSQL выбирает товары, отмеченные поврежденными в период, с ПВЗ их приёмок
+var damagedProductsSQL = db.RegisterSQL("report.damaged_products", `SELECT p.id AS product_id, p.reception_id, r.pvz_id, p.type, p.barcode,
+		p.damage_description, p.damaged_by, p.damaged_at
+	FROM product p
+	JOIN reception r ON r.id = p.reception_id
+	WHERE p.damaged AND p.damaged_at >= $1 AND p.damaged_at < $2
+	ORDER BY p.damaged_at, p.id`)
+
+// GetDamagedProducts получает товары, отмеченные поврежденными в период [from, to), в порядке отметки
+func (q *ReportQueries) GetDamagedProducts(ctx context.Context, from, to time.Time) ([]models.ProductDamage, error) {
+	ctx, span := tracing.Start(ctx, "ReportQueries.GetDamagedProducts")
+	defer span.End()
+
+	var damages []models.ProductDamage
+	if err := q.db.ReadSelectContext(ctx, &damages, damagedProductsSQL, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get damaged products: %w", err)
+	}
+
+	return damages, nil
+}
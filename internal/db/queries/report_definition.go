@@ -0,0 +1,352 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+// ReportDefinitionQueriesInterface определяет интерфейс для запросов к сохраненным отчётам конструктора
+type ReportDefinitionQueriesInterface interface {
+	ListDefinitions(ctx context.Context) ([]models.ReportDefinition, error)
+	GetDefinition(ctx context.Context, definitionID string) (*models.ReportDefinition, error)
+	CreateDefinition(ctx context.Context, req models.ReportDefinitionRequest, createdBy string, nextRunAt *time.Time) (*models.ReportDefinition, error)
+	UpdateDefinition(ctx context.Context, definitionID string, req models.ReportDefinitionRequest, nextRunAt *time.Time) (*models.ReportDefinition, error)
+	DeleteDefinition(ctx context.Context, definitionID string) error
+	ClaimDueDefinitions(ctx context.Context, now time.Time, limit int) ([]models.ReportDefinition, error)
+	BuildReport(ctx context.Context, definition models.ReportDefinition, since *time.Time) ([]models.ReportResultRow, error)
+}
+
+// ReportDefinitionQueries содержит методы запросов для работы с сохраненными отчётами конструктора
+type ReportDefinitionQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ ReportDefinitionQueriesInterface = (*ReportDefinitionQueries)(nil)
+
+// NewReportDefinitionQueries создает новый экземпляр ReportDefinitionQueries
+func NewReportDefinitionQueries(db *db.Database) *ReportDefinitionQueries {
+	return &ReportDefinitionQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+	}
+}
+
+const reportDefinitionColumns = "id, name, entity, period_days, city, pvz_id, status, product_type, group_by, format, " +
+	"schedule, next_run_at, last_run_at, recipients, created_by, created_at, updated_at"
+
+// ListDefinitions получает все сохраненные отчёты в порядке названий
+func (q *ReportDefinitionQueries) ListDefinitions(ctx context.Context) ([]models.ReportDefinition, error) {
+	ctx, span := tracing.Start(ctx, "ReportDefinitionQueries.ListDefinitions")
+	defer span.End()
+
+	query, args, err := q.sq.
+		Select(reportDefinitionColumns).
+		From("report_definitions").
+		OrderBy("name", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var definitions []models.ReportDefinition
+	if err := q.db.SelectContext(ctx, &definitions, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get report definitions: %w", err)
+	}
+
+	return definitions, nil
+}
+
+// GetDefinition получает сохраненный отчёт, возвращает ErrNotFound, если его нет
+func (q *ReportDefinitionQueries) GetDefinition(ctx context.Context, definitionID string) (*models.ReportDefinition, error) {
+	ctx, span := tracing.Start(ctx, "ReportDefinitionQueries.GetDefinition")
+	defer span.End()
+
+	query, args, err := q.sq.
+		Select(reportDefinitionColumns).
+		From("report_definitions").
+		Where(squirrel.Eq{"id": definitionID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var definition models.ReportDefinition
+	err = q.db.GetContext(ctx, &definition, query, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("report definition %s: %w", definitionID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report definition: %w", err)
+	}
+
+	return &definition, nil
+}
+
+// definitionValues возвращает значения колонок сохраненного отчёта из запроса.
+// Пустые списки сохраняются как пустые массивы: колонки массивов NOT NULL
+func definitionValues(req models.ReportDefinitionRequest, nextRunAt *time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"name":         req.Name,
+		"entity":       req.Entity,
+		"period_days":  req.PeriodDays,
+		"city":         req.City,
+		"pvz_id":       req.PvzID,
+		"status":       req.Status,
+		"product_type": req.ProductType,
+		"group_by":     pq.Array(append([]string{}, req.GroupBy...)),
+		"format":       req.Format,
+		"schedule":     req.Schedule,
+		"next_run_at":  nextRunAt,
+		"recipients":   pq.Array(append([]string{}, req.Recipients...)),
+	}
+}
+
+// CreateDefinition сохраняет отчёт. nextRunAt - первый запуск по расписанию, nil - отчёт без расписания
+func (q *ReportDefinitionQueries) CreateDefinition(ctx context.Context, req models.ReportDefinitionRequest, createdBy string, nextRunAt *time.Time) (*models.ReportDefinition, error) {
+	ctx, span := tracing.Start(ctx, "ReportDefinitionQueries.CreateDefinition")
+	defer span.End()
+
+	values := definitionValues(req, nextRunAt)
+	values["created_by"] = nullString(createdBy)
+
+	query, args, err := q.sq.
+		Insert("report_definitions").
+		SetMap(values).
+		Suffix("RETURNING " + reportDefinitionColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var definition models.ReportDefinition
+	if err := q.db.QueryRowxContext(ctx, query, args...).StructScan(&definition); err != nil {
+		return nil, fmt.Errorf("failed to create report definition: %w", err)
+	}
+
+	return &definition, nil
+}
+
+// UpdateDefinition заменяет сохраненный отчёт и переносит следующий запуск на nextRunAt.
+// Возвращает ErrNotFound, если отчёта нет
+func (q *ReportDefinitionQueries) UpdateDefinition(ctx context.Context, definitionID string, req models.ReportDefinitionRequest, nextRunAt *time.Time) (*models.ReportDefinition, error) {
+	ctx, span := tracing.Start(ctx, "ReportDefinitionQueries.UpdateDefinition")
+	defer span.End()
+
+	query, args, err := q.sq.
+		Update("report_definitions").
+		SetMap(definitionValues(req, nextRunAt)).
+		Set("updated_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"id": definitionID}).
+		Suffix("RETURNING " + reportDefinitionColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var definition models.ReportDefinition
+	err = q.db.QueryRowxContext(ctx, query, args...).StructScan(&definition)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("report definition %s: %w", definitionID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update report definition: %w", err)
+	}
+
+	return &definition, nil
+}
+
+// DeleteDefinition удаляет сохраненный отчёт, возвращает ErrNotFound, если его нет.
+// Задания уже сформированных отчётов остаются
+func (q *ReportDefinitionQueries) DeleteDefinition(ctx context.Context, definitionID string) error {
+	ctx, span := tracing.Start(ctx, "ReportDefinitionQueries.DeleteDefinition")
+	defer span.End()
+
+	query, args, err := q.sq.
+		Delete("report_definitions").
+		Where(squirrel.Eq{"id": definitionID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to delete report definition: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("report definition %s: %w", definitionID, ErrNotFound)
+	}
+
+	return nil
+}
+
+// claimDueDefinitionsSQL переносит следующий запуск отчётов, которым пора запускаться, на период расписания
+// от now. SKIP LOCKED не дает двум экземплярам сервиса забрать один и тот же отчёт
+var claimDueDefinitionsSQL = db.RegisterSQL("report_definition.claim_due", `UPDATE report_definitions d
+	SET last_run_at = $1,
+		next_run_at = $1 + CASE d.schedule WHEN 'weekly' THEN interval '7 days' ELSE interval '1 day' END
+	WHERE d.id IN (
+		SELECT id FROM report_definitions
+		WHERE next_run_at <= $1
+		ORDER BY next_run_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING `+reportDefinitionColumns)
+
+// ClaimDueDefinitions забирает до limit отчётов, которым пора запускаться по расписанию, и назначает
+// им следующий запуск. Отчёт, запуск которого не удался, повторяется только в следующий период
+func (q *ReportDefinitionQueries) ClaimDueDefinitions(ctx context.Context, now time.Time, limit int) ([]models.ReportDefinition, error) {
+	ctx, span := tracing.Start(ctx, "ReportDefinitionQueries.ClaimDueDefinitions")
+	defer span.End()
+
+	var definitions []models.ReportDefinition
+	if err := q.db.SelectContext(ctx, &definitions, claimDueDefinitionsSQL, now, limit); err != nil {
+		return nil, fmt.Errorf("failed to claim due report definitions: %w", err)
+	}
+
+	return definitions, nil
+}
+
+// reportSource описывает, как строится отчёт по сущности: таблицы, колонки фильтров,
+// выражения группировок и показатели
+type reportSource struct {
+	from      string
+	timeField string
+	status    string
+	groups    map[string]string
+	metrics   []string
+}
+
+// reportSources - источники отчётов конструктора. В SQL попадают только выражения отсюда,
+// значения фильтров передаются параметрами
+var reportSources = map[string]reportSource{
+	models.ReportEntityReceptions: {
+		from:      "reception r JOIN pvz p ON p.id = r.pvz_id",
+		timeField: "r.datetime",
+		status:    "r.status",
+		groups: map[string]string{
+			"city":   "p.city",
+			"pvz":    "r.pvz_id::text",
+			"status": "r.status",
+			"day":    "to_char(r.datetime, 'YYYY-MM-DD')",
+			"month":  "to_char(r.datetime, 'YYYY-MM')",
+		},
+		metrics: []string{"COUNT(*) AS count", "COALESCE(SUM(r.product_count), 0) AS products"},
+	},
+	models.ReportEntityProducts: {
+		from:      "product pr JOIN reception r ON r.id = pr.reception_id JOIN pvz p ON p.id = r.pvz_id",
+		timeField: "pr.datetime",
+		status:    "pr.status",
+		groups: map[string]string{
+			"city":   "p.city",
+			"pvz":    "r.pvz_id::text",
+			"status": "pr.status",
+			"type":   "pr.type",
+			"day":    "to_char(pr.datetime, 'YYYY-MM-DD')",
+			"month":  "to_char(pr.datetime, 'YYYY-MM')",
+		},
+		metrics: []string{"COUNT(*) AS count", "0 AS products"},
+	},
+}
+
+// BuildReport строит отчёт по определению: строки сущности за период с since (nil - за все время),
+// отобранные фильтрами и сгруппированные по GroupBy в порядке групп. Без группировок возвращается
+// одна строка с итогами. Возвращает ErrUnsupportedReportField для группировки или фильтра, недоступных сущности
+func (q *ReportDefinitionQueries) BuildReport(ctx context.Context, definition models.ReportDefinition, since *time.Time) ([]models.ReportResultRow, error) {
+	ctx, span := tracing.Start(ctx, "ReportDefinitionQueries.BuildReport")
+	defer span.End()
+
+	source, ok := reportSources[definition.Entity]
+	if !ok {
+		return nil, fmt.Errorf("entity %s: %w", definition.Entity, ErrUnsupportedReportField)
+	}
+
+	columns := make([]string, 0, len(definition.GroupBy)+len(source.metrics))
+	groupBy := make([]string, 0, len(definition.GroupBy))
+	for i, group := range definition.GroupBy {
+		expr, ok := source.groups[group]
+		if !ok {
+			return nil, fmt.Errorf("group %s: %w", group, ErrUnsupportedReportField)
+		}
+		columns = append(columns, expr+" AS g"+strconv.Itoa(i))
+		groupBy = append(groupBy, strconv.Itoa(i+1))
+	}
+	columns = append(columns, source.metrics...)
+
+	query := q.sq.Select(columns...).From(source.from)
+	if since != nil {
+		query = query.Where(squirrel.GtOrEq{source.timeField: *since})
+	}
+	if definition.City != nil {
+		query = query.Where(squirrel.Eq{"p.city": *definition.City})
+	}
+	if definition.PvzID != nil {
+		query = query.Where(squirrel.Eq{"r.pvz_id": *definition.PvzID})
+	}
+	if definition.Status != nil {
+		query = query.Where(squirrel.Eq{source.status: *definition.Status})
+	}
+	if definition.ProductType != nil {
+		if definition.Entity != models.ReportEntityProducts {
+			return nil, fmt.Errorf("product type filter: %w", ErrUnsupportedReportField)
+		}
+		query = query.Where(squirrel.Eq{"pr.type": *definition.ProductType})
+	}
+	if len(groupBy) > 0 {
+		query = query.GroupBy(groupBy...).OrderBy(groupBy...)
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := q.db.ReadQueryxContext(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build report: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.ReportResultRow
+	for rows.Next() {
+		groups := make([]*string, len(groupBy))
+		row := models.ReportResultRow{Groups: make([]string, len(groupBy))}
+		dest := make([]interface{}, 0, len(groupBy)+2)
+		for i := range groups {
+			dest = append(dest, &groups[i])
+		}
+		dest = append(dest, &row.Count, &row.Products)
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan report row: %w", err)
+		}
+		for i, value := range groups {
+			if value != nil {
+				row.Groups[i] = *value
+			}
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read report rows: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,137 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+)
+
+func setupReportDefinitionQueriesTest(t *testing.T) (*ReportDefinitionQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return NewReportDefinitionQueries(&db.Database{DB: sqlxDB}), mock
+}
+
+func TestReportDefinitionQueries_CreateDefinition(t *testing.T) {
+	q, mock := setupReportDefinitionQueriesTest(t)
+	nextRunAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	schedule := models.ReportScheduleDaily
+	req := models.ReportDefinitionRequest{Name: "Приёмки по дням", Entity: models.ReportEntityReceptions, Format: "csv", Schedule: &schedule}
+
+	// Пустые группировки и получатели сохраняются пустыми массивами, а не NULL
+	mock.ExpectQuery(`INSERT INTO report_definitions \(city,created_by,entity,format,group_by,name,next_run_at,period_days,product_type,pvz_id,recipients,schedule,status\) VALUES .* RETURNING id, name, entity`).
+		WithArgs(nil, "user-1", models.ReportEntityReceptions, "csv", "{}", "Приёмки по дням", &nextRunAt, nil, nil, nil, "{}", &schedule, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "entity", "group_by", "format", "schedule", "next_run_at", "recipients"}).
+			AddRow("def-1", "Приёмки по дням", models.ReportEntityReceptions, "{}", "csv", schedule, nextRunAt, "{}"))
+
+	definition, err := q.CreateDefinition(context.Background(), req, "user-1", &nextRunAt)
+
+	require.NoError(t, err)
+	assert.Equal(t, "def-1", definition.ID)
+	assert.Equal(t, nextRunAt, *definition.NextRunAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportDefinitionQueries_ClaimDueDefinitions(t *testing.T) {
+	q, mock := setupReportDefinitionQueriesTest(t)
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`UPDATE report_definitions d SET last_run_at = \$1, next_run_at = \$1 \+ CASE d.schedule .* WHERE next_run_at <= \$1 ORDER BY next_run_at LIMIT \$2 FOR UPDATE SKIP LOCKED \) RETURNING`).
+		WithArgs(now, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "entity", "group_by", "recipients"}).
+			AddRow("def-1", "Товары", models.ReportEntityProducts, "{type,day}", "{boss@example.com}"))
+
+	definitions, err := q.ClaimDueDefinitions(context.Background(), now, 20)
+
+	require.NoError(t, err)
+	require.Len(t, definitions, 1)
+	assert.Equal(t, []string{"type", "day"}, []string(definitions[0].GroupBy))
+	assert.Equal(t, []string{"boss@example.com"}, []string(definitions[0].Recipients))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportDefinitionQueries_BuildReport(t *testing.T) {
+	q, mock := setupReportDefinitionQueriesTest(t)
+	since := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	city := "Москва"
+	status := "close"
+
+	t.Run("Приёмки по городам и дням", func(t *testing.T) {
+		definition := models.ReportDefinition{
+			Entity:        models.ReportEntityReceptions,
+			ReportFilters: models.ReportFilters{City: &city, Status: &status},
+			GroupBy:       []string{"city", "day"},
+		}
+
+		mock.ExpectQuery(`SELECT p.city AS g0, to_char\(r.datetime, 'YYYY-MM-DD'\) AS g1, COUNT\(\*\) AS count, COALESCE\(SUM\(r.product_count\), 0\) AS products `+
+			`FROM reception r JOIN pvz p ON p.id = r.pvz_id WHERE r.datetime >= \$1 AND p.city = \$2 AND r.status = \$3 GROUP BY 1, 2 ORDER BY 1, 2`).
+			WithArgs(since, city, status).
+			WillReturnRows(sqlmock.NewRows([]string{"g0", "g1", "count", "products"}).
+				AddRow("Москва", "2026-02-01", 3, 42).
+				AddRow("Москва", "2026-02-02", 1, 7))
+
+		rows, err := q.BuildReport(context.Background(), definition, &since)
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.ReportResultRow{
+			{Groups: []string{"Москва", "2026-02-01"}, Count: 3, Products: 42},
+			{Groups: []string{"Москва", "2026-02-02"}, Count: 1, Products: 7},
+		}, rows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Итог по товарам без группировок", func(t *testing.T) {
+		productType := models.ProductTypeShoes
+		definition := models.ReportDefinition{
+			Entity:        models.ReportEntityProducts,
+			ReportFilters: models.ReportFilters{ProductType: &productType},
+		}
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\) AS count, 0 AS products FROM product pr JOIN reception r ON r.id = pr.reception_id JOIN pvz p ON p.id = r.pvz_id WHERE pr.type = \$1$`).
+			WithArgs(productType).
+			WillReturnRows(sqlmock.NewRows([]string{"count", "products"}).AddRow(12, 0))
+
+		rows, err := q.BuildReport(context.Background(), definition, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.ReportResultRow{{Groups: []string{}, Count: 12}}, rows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Группировка недоступна сущности", func(t *testing.T) {
+		definition := models.ReportDefinition{Entity: models.ReportEntityReceptions, GroupBy: []string{"type"}}
+
+		_, err := q.BuildReport(context.Background(), definition, nil)
+
+		assert.ErrorIs(t, err, ErrUnsupportedReportField)
+	})
+}
+
+func TestReportDefinitionQueries_DeleteDefinition(t *testing.T) {
+	q, mock := setupReportDefinitionQueriesTest(t)
+
+	mock.ExpectExec(`DELETE FROM report_definitions WHERE id = \$1`).
+		WithArgs("def-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := q.DeleteDefinition(context.Background(), "def-1")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	mock.ExpectQuery(`SELECT .* FROM report_definitions WHERE id = \$1`).
+		WithArgs("def-1").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = q.GetDefinition(context.Background(), "def-1")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
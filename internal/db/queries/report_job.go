@@ -0,0 +1,128 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// ReportJobQueriesInterface определяет интерфейс для запросов к заданиям фоновых отчётов
+type ReportJobQueriesInterface interface {
+	CreateReportJob(ctx context.Context, kind string, params []byte, createdBy string) (*models.ReportJob, error)
+	GetReportJob(ctx context.Context, jobID string) (*models.ReportJob, error)
+	CompleteReportJob(ctx context.Context, jobID, objectKey string) error
+	FailReportJob(ctx context.Context, jobID, reason string) error
+}
+
+// ReportJobQueries содержит методы запросов для работы с заданиями фоновых отчётов
+type ReportJobQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ ReportJobQueriesInterface = (*ReportJobQueries)(nil)
+
+// NewReportJobQueries создает новый экземпляр ReportJobQueries
+func NewReportJobQueries(db *db.Database) *ReportJobQueries {
+	return &ReportJobQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+	}
+}
+
+const reportJobColumns = "id, kind, params, status, object_key, error, created_by, created_at, finished_at"
+
+// CreateReportJob создает задание отчёта в статусе running
+func (q *ReportJobQueries) CreateReportJob(ctx context.Context, kind string, params []byte, createdBy string) (*models.ReportJob, error) {
+	ctx, span := tracing.Start(ctx, "ReportJobQueries.CreateReportJob")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Insert("report_jobs").
+		Columns("kind", "params", "created_by").
+		Values(kind, params, nullString(createdBy)).
+		Suffix("RETURNING " + reportJobColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var job models.ReportJob
+	if err := q.db.QueryRowxContext(ctx, qsql, args...).StructScan(&job); err != nil {
+		return nil, fmt.Errorf("failed to create report job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// GetReportJob получает задание отчёта по ID
+func (q *ReportJobQueries) GetReportJob(ctx context.Context, jobID string) (*models.ReportJob, error) {
+	ctx, span := tracing.Start(ctx, "ReportJobQueries.GetReportJob")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Select(reportJobColumns).
+		From("report_jobs").
+		Where(squirrel.Eq{"id": jobID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var job models.ReportJob
+	if err := q.db.GetContext(ctx, &job, qsql, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("report job %s: %w", jobID, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get report job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// CompleteReportJob отмечает задание выполненным и сохраняет ключ файла отчёта в хранилище
+func (q *ReportJobQueries) CompleteReportJob(ctx context.Context, jobID, objectKey string) error {
+	return q.finish(ctx, "ReportJobQueries.CompleteReportJob", jobID, map[string]interface{}{"status": models.ReportJobDone, "object_key": objectKey})
+}
+
+// FailReportJob отмечает задание неудавшимся и сохраняет причину
+func (q *ReportJobQueries) FailReportJob(ctx context.Context, jobID, reason string) error {
+	return q.finish(ctx, "ReportJobQueries.FailReportJob", jobID, map[string]interface{}{"status": models.ReportJobFailed, "error": reason})
+}
+
+// finish завершает выполняющееся задание с указанными значениями колонок
+func (q *ReportJobQueries) finish(ctx context.Context, spanName, jobID string, values map[string]interface{}) error {
+	ctx, span := tracing.Start(ctx, spanName)
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Update("report_jobs").
+		SetMap(values).
+		Set("finished_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"id": jobID, "status": models.ReportJobRunning}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, qsql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to finish report job: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("report job %s: %w", jobID, ErrNotFound)
+	}
+
+	return nil
+}
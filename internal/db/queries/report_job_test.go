@@ -0,0 +1,81 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+)
+
+func setupReportJobQueriesTest(t *testing.T) (*ReportJobQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &ReportJobQueries{
+		db: &db.Database{DB: sqlxDB},
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+var reportJobRowColumns = []string{"id", "kind", "params", "status", "object_key", "error", "created_by", "created_at", "finished_at"}
+
+func TestReportJobQueries_CreateReportJob(t *testing.T) {
+	q, mock := setupReportJobQueriesTest(t)
+	params := []byte(`{"city":"Москва"}`)
+
+	mock.ExpectQuery(`INSERT INTO report_jobs \(kind,params,created_by\) VALUES \(\$1,\$2,\$3\) RETURNING`).
+		WithArgs(models.ReportKindCityReceptions, params, "user-1").
+		WillReturnRows(sqlmock.NewRows(reportJobRowColumns).
+			AddRow("job-1", models.ReportKindCityReceptions, params, models.ReportJobRunning, nil, nil, "user-1", time.Now(), nil))
+
+	job, err := q.CreateReportJob(context.Background(), models.ReportKindCityReceptions, params, "user-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "job-1", job.ID)
+	assert.Equal(t, models.ReportJobRunning, job.Status)
+	assert.JSONEq(t, `{"city":"Москва"}`, string(job.Params))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportJobQueries_GetReportJob(t *testing.T) {
+	q, mock := setupReportJobQueriesTest(t)
+
+	mock.ExpectQuery(`SELECT .+ FROM report_jobs WHERE id = \$1`).
+		WithArgs("job-1").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := q.GetReportJob(context.Background(), "job-1")
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportJobQueries_FinishReportJob(t *testing.T) {
+	q, mock := setupReportJobQueriesTest(t)
+
+	t.Run("Отчёт сохранен", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE report_jobs SET object_key = \$1, status = \$2, finished_at = CURRENT_TIMESTAMP WHERE id = \$3 AND status = \$4`).
+			WithArgs("reports/job-1.csv", models.ReportJobDone, "job-1", models.ReportJobRunning).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, q.CompleteReportJob(context.Background(), "job-1", "reports/job-1.csv"))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Задание уже завершено", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE report_jobs SET error = \$1, status = \$2, finished_at = CURRENT_TIMESTAMP WHERE id = \$3 AND status = \$4`).
+			WithArgs("timeout", models.ReportJobFailed, "job-1", models.ReportJobRunning).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, q.FailReportJob(context.Background(), "job-1", "timeout"), ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,227 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+)
+
+func setupReportQueriesTest(t *testing.T) (*ReportQueries, sqlmock.Sqlmock) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Ошибка при создании mock-базы данных: %v", err)
+	}
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &ReportQueries{
+		db: &db.Database{DB: sqlxDB},
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+func TestReportQueries_GetDuplicateBarcodes(t *testing.T) {
+	q, mock := setupReportQueriesTest(t)
+	since := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	expectedSQL := `SELECT p.barcode, p.id AS product_id, p.datetime, p.reception_id, r.number AS reception_number, r.status AS reception_status, r.pvz_id, pvz.city ` +
+		`FROM product p JOIN reception r ON r.id = p.reception_id JOIN pvz ON pvz.id = r.pvz_id ` +
+		`WHERE p.datetime >= \$1 AND p.barcode IN \( SELECT dp.barcode FROM product dp JOIN reception dr ON dr.id = dp.reception_id ` +
+		`WHERE dp.barcode IS NOT NULL AND dp.datetime >= \$2 GROUP BY dp.barcode HAVING .+ \) ORDER BY p.barcode, p.datetime`
+
+	t.Run("Успешное получение дублей", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"barcode", "product_id", "datetime", "reception_id", "reception_number", "reception_status", "pvz_id", "city"}).
+			AddRow("4600000000011", "p1", since.Add(time.Hour), "r1", "MSK001-2025-000001", "in_progress", "pvz1", "Москва").
+			AddRow("4600000000011", "p2", since.Add(2*time.Hour), "r2", "KZN001-2025-000001", "in_progress", "pvz2", "Казань")
+
+		mock.ExpectQuery(expectedSQL).
+			WithArgs(since, since).
+			WillReturnRows(rows)
+
+		result, err := q.GetDuplicateBarcodes(context.Background(), since)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "4600000000011", result[0].Barcode)
+		assert.Equal(t, "Казань", result[1].City)
+	})
+
+	t.Run("Ошибка базы данных", func(t *testing.T) {
+		mock.ExpectQuery(expectedSQL).
+			WithArgs(since, since).
+			WillReturnError(errors.New("database error"))
+
+		result, err := q.GetDuplicateBarcodes(context.Background(), since)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportQueries_GetInvariantViolations(t *testing.T) {
+	q, mock := setupReportQueriesTest(t)
+
+	t.Run("Нарушения всех видов", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT 'multiple_open_receptions' AS kind, r.pvz_id, r.id AS reception_id, NULL AS product_id\s+FROM reception r.*` +
+			`UNION ALL\s+SELECT 'product_after_close'.*WHERE r.status = 'close' AND p.status = 'received'.*` +
+			`UNION ALL\s+SELECT 'orphaned_product'.*WHERE pvz.archived_at IS NOT NULL`).
+			WillReturnRows(sqlmock.NewRows([]string{"kind", "pvz_id", "reception_id", "product_id"}).
+				AddRow("multiple_open_receptions", "pvz1", "r1", nil).
+				AddRow("product_after_close", "pvz2", "r3", "p1"))
+
+		violations, err := q.GetInvariantViolations(context.Background())
+
+		assert.NoError(t, err)
+		assert.Len(t, violations, 2)
+		assert.Nil(t, violations[0].ProductID)
+		assert.Equal(t, "p1", *violations[1].ProductID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Ошибка базы данных", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT 'multiple_open_receptions'`).WillReturnError(errors.New("database error"))
+
+		_, err := q.GetInvariantViolations(context.Background())
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReportQueries_GetUserActivity(t *testing.T) {
+	q, mock := setupReportQueriesTest(t)
+
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT\s+\(SELECT COUNT\(\*\) FROM reception WHERE created_by = \$1.*FROM product_deletions WHERE deleted_by = \$1`).
+		WithArgs("user-1", from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"receptions_created", "receptions_closed", "products_added", "products_deleted"}).
+			AddRow(12, 11, 340, 4))
+
+	activity, err := q.GetUserActivity(context.Background(), "user-1", from, to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", activity.UserID)
+	assert.Equal(t, 12, activity.ReceptionsCreated)
+	assert.Equal(t, 4, activity.ProductsDeleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportQueries_StreamCityReceptions(t *testing.T) {
+	q, mock := setupReportQueriesTest(t)
+
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	expectedSQL := `SELECT r.pvz_id, pvz.city, r.id AS reception_id, r.number, r.datetime, r.status, .+ AS product_count, r.created_by, r.closed_by ` +
+		`FROM reception r JOIN pvz ON pvz.id = r.pvz_id WHERE pvz.city = \$1 AND r.datetime >= \$2 AND r.datetime < \$3 ORDER BY r.pvz_id, r.datetime, r.id`
+
+	t.Run("Строки передаются по одной", func(t *testing.T) {
+		mock.ExpectQuery(expectedSQL).
+			WithArgs("Москва", from, to).
+			WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "city", "reception_id", "number", "datetime", "status", "product_count", "created_by", "closed_by"}).
+				AddRow("pvz1", "Москва", "r1", "MSK001-2026-000001", from.Add(time.Hour), "close", 12, "u1", "u2").
+				AddRow("pvz2", "Москва", "r2", "MSK002-2026-000001", from.Add(2*time.Hour), "in_progress", 3, nil, nil))
+
+		var rows []models.ReceptionExportRow
+		err := q.StreamCityReceptions(context.Background(), "Москва", from, to, func(row models.ReceptionExportRow) error {
+			rows = append(rows, row)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, rows, 2)
+		assert.Equal(t, "u2", *rows[0].ClosedBy)
+		assert.Nil(t, rows[1].CreatedBy)
+		assert.Equal(t, 3, rows[1].ProductCount)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Ошибка обработчика строки прерывает чтение", func(t *testing.T) {
+		mock.ExpectQuery(expectedSQL).
+			WithArgs("Москва", from, to).
+			WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "city", "reception_id", "number", "datetime", "status", "product_count", "created_by", "closed_by"}).
+				AddRow("pvz1", "Москва", "r1", "MSK001-2026-000001", from, "close", 12, nil, nil))
+
+		errWrite := errors.New("client gone")
+		err := q.StreamCityReceptions(context.Background(), "Москва", from, to, func(row models.ReceptionExportRow) error {
+			return errWrite
+		})
+
+		assert.ErrorIs(t, err, errWrite)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReportQueries_GetDailyIntake(t *testing.T) {
+	q, mock := setupReportQueriesTest(t)
+	from := time.Date(2026, 3, 1, 21, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 29, 21, 0, 0, 0, time.UTC)
+	expectedSQL := `SELECT \(p.datetime AT TIME ZONE \$2\)::date AS day, COUNT\(\*\) AS products FROM product p ` +
+		`JOIN reception r ON r.id = p.reception_id WHERE r.pvz_id = \$1 AND p.datetime >= \$3 AND p.datetime < \$4 GROUP BY day ORDER BY day`
+
+	mock.ExpectQuery(expectedSQL).
+		WithArgs("pvz1", "Europe/Moscow", from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"day", "products"}).
+			AddRow(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), 40).
+			AddRow(time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), 12))
+
+	intake, err := q.GetDailyIntake(context.Background(), "pvz1", "Europe/Moscow", from, to)
+
+	assert.NoError(t, err)
+	assert.Len(t, intake, 2)
+	assert.Equal(t, 40, intake[0].Products)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportQueries_GetAgingProducts(t *testing.T) {
+	q, mock := setupReportQueriesTest(t)
+	storedBefore := time.Date(2026, 2, 16, 10, 0, 0, 0, time.UTC)
+	expectedSQL := `SELECT p.id AS product_id, p.type, p.barcode, r.id AS reception_id, r.number AS reception_number, r.pvz_id, pvz.city, pvz.address, ` +
+		`COALESCE\(r.closed_at, r.datetime\) AS stored_since FROM product p JOIN reception r ON r.id = p.reception_id JOIN pvz ON pvz.id = r.pvz_id ` +
+		`WHERE p.status = \$1 AND r.status = \$2 AND pvz.archived_at IS NULL AND COALESCE\(r.closed_at, r.datetime\) < \$3 AND pvz.city = \$4 ` +
+		`ORDER BY pvz.city, r.pvz_id, stored_since, p.id`
+
+	mock.ExpectQuery(expectedSQL).
+		WithArgs(models.ProductLifecycleStored, "close", storedBefore, "Москва").
+		WillReturnRows(sqlmock.NewRows([]string{"product_id", "type", "barcode", "reception_id", "reception_number", "pvz_id", "city", "address", "stored_since"}).
+			AddRow("p1", "обувь", nil, "r1", "MSK001-2026-000001", "pvz1", "Москва", "Тверская, 1", time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)))
+
+	products, err := q.GetAgingProducts(context.Background(), storedBefore, "Москва")
+
+	assert.NoError(t, err)
+	assert.Len(t, products, 1)
+	assert.Nil(t, products[0].Barcode)
+	assert.Equal(t, "pvz1", products[0].PvzID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportQueries_GetDamagedProducts(t *testing.T) {
+	q, mock := setupReportQueriesTest(t)
+	from := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT p.id AS product_id, p.reception_id, r.pvz_id, .* FROM product p JOIN reception r ON r.id = p.reception_id `+
+		`WHERE p.damaged AND p.damaged_at >= \$1 AND p.damaged_at < \$2 ORDER BY p.damaged_at, p.id`).
+		WithArgs(from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"product_id", "reception_id", "pvz_id", "type", "barcode", "damage_description", "damaged_by", "damaged_at"}).
+			AddRow("p1", "r1", "pvz1", "обувь", nil, "порвана коробка", "u1", time.Date(2026, 2, 3, 9, 0, 0, 0, time.UTC)))
+
+	damages, err := q.GetDamagedProducts(context.Background(), from, to)
+
+	assert.NoError(t, err)
+	assert.Len(t, damages, 1)
+	assert.Equal(t, "pvz1", damages[0].PvzID)
+	assert.Equal(t, "порвана коробка", damages[0].Description)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,102 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// SearchQueriesInterface определяет интерфейс для поиска по ПВЗ, приёмкам и товарам
+type SearchQueriesInterface interface {
+	Search(ctx context.Context, text string, limit int, pvzIDs []string) (*models.SearchResponse, error)
+}
+
+// SearchQueries содержит методы поиска по подстроке. Поиск идет по триграммным индексам,
+// результаты каждой группы сортируются по похожести на искомую строку
+type SearchQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ SearchQueriesInterface = (*SearchQueries)(nil)
+
+// NewSearchQueries создает новый экземпляр SearchQueries
+func NewSearchQueries(db *db.Database) *SearchQueries {
+	return &SearchQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+// likeEscaper экранирует спецсимволы LIKE, чтобы они искались как обычные символы
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Search ищет text в городе и адресе ПВЗ, номере и заметке приёмки, штрихкоде и типе товара.
+// В каждой группе возвращается не больше limit результатов. Непустой pvzIDs ограничивает поиск этими ПВЗ
+func (q *SearchQueries) Search(ctx context.Context, text string, limit int, pvzIDs []string) (*models.SearchResponse, error) {
+	ctx, span := tracing.Start(ctx, "SearchQueries.Search")
+	defer span.End()
+
+	pattern := "%" + likeEscaper.Replace(text) + "%"
+	result := models.SearchResponse{
+		PVZ:        []models.PVZSearchHit{},
+		Receptions: []models.ReceptionSearchHit{},
+		Products:   []models.ProductSearchHit{},
+	}
+
+	pvzQuery := q.sq.
+		Select("id", "city", "address").
+		From("pvz").
+		Where(squirrel.Eq{"archived_at": nil}).
+		Where(squirrel.Or{squirrel.ILike{"city": pattern}, squirrel.ILike{"address": pattern}}).
+		OrderByClause("GREATEST(similarity(city, ?), similarity(address, ?)) DESC, id", text, text).
+		Limit(uint64(limit))
+
+	receptionQuery := q.sq.
+		Select("id", "number", "datetime", "pvz_id", "status", "note").
+		From("reception").
+		Where(squirrel.Or{squirrel.ILike{"number": pattern}, squirrel.ILike{"note": pattern}}).
+		OrderByClause("GREATEST(similarity(number, ?), similarity(note, ?)) DESC, datetime DESC", text, text).
+		Limit(uint64(limit))
+
+	productQuery := q.sq.
+		Select("p.id", "p.datetime", "p.type", "p.barcode", "p.reception_id", "r.pvz_id").
+		From("product p").
+		Join("reception r ON r.id = p.reception_id").
+		Where(squirrel.Or{squirrel.ILike{"p.barcode": pattern}, squirrel.ILike{"p.type": pattern}}).
+		OrderByClause("GREATEST(similarity(COALESCE(p.barcode, ''), ?), similarity(p.type, ?)) DESC, p.datetime DESC", text, text).
+		Limit(uint64(limit))
+
+	if len(pvzIDs) > 0 {
+		pvzQuery = pvzQuery.Where(squirrel.Eq{"id": pvzIDs})
+		receptionQuery = receptionQuery.Where(squirrel.Eq{"pvz_id": pvzIDs})
+		productQuery = productQuery.Where(squirrel.Eq{"r.pvz_id": pvzIDs})
+	}
+
+	if err := q.selectHits(ctx, &result.PVZ, pvzQuery); err != nil {
+		return nil, fmt.Errorf("failed to search pvz: %w", err)
+	}
+	if err := q.selectHits(ctx, &result.Receptions, receptionQuery); err != nil {
+		return nil, fmt.Errorf("failed to search receptions: %w", err)
+	}
+	if err := q.selectHits(ctx, &result.Products, productQuery); err != nil {
+		return nil, fmt.Errorf("failed to search products: %w", err)
+	}
+
+	return &result, nil
+}
+
+// selectHits выполняет запрос поиска на реплике
+func (q *SearchQueries) selectHits(ctx context.Context, dest interface{}, query squirrel.SelectBuilder) error {
+	qsql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+	return q.db.ReadSelectContext(ctx, dest, qsql, args...)
+}
@@ -0,0 +1,51 @@
+package queries
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db"
+)
+
+func setupSearchQueriesTest(t *testing.T) (*SearchQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &SearchQueries{
+		db: &db.Database{DB: sqlxDB},
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+func TestSearchQueries_Search(t *testing.T) {
+	q, mock := setupSearchQueriesTest(t)
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	// Спецсимволы LIKE в строке поиска экранируются
+	mock.ExpectQuery(`SELECT id, city, address FROM pvz WHERE archived_at IS NULL AND \(city ILIKE \$1 OR address ILIKE \$2\) AND id IN \(\$3\) ORDER BY GREATEST\(similarity\(city, \$4\), similarity\(address, \$5\)\) DESC, id LIMIT 10`).
+		WithArgs(`%MSK\_1%`, `%MSK\_1%`, pvzID, "MSK_1", "MSK_1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "city", "address"}))
+	mock.ExpectQuery(`SELECT id, number, datetime, pvz_id, status, note FROM reception WHERE \(number ILIKE \$1 OR note ILIKE \$2\) AND pvz_id IN \(\$3\)`).
+		WithArgs(`%MSK\_1%`, `%MSK\_1%`, pvzID, "MSK_1", "MSK_1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "number", "datetime", "pvz_id", "status", "note"}).
+			AddRow("r1", "MSK_1-2026-000001", now, pvzID, "close", ""))
+	mock.ExpectQuery(`SELECT p.id, p.datetime, p.type, p.barcode, p.reception_id, r.pvz_id FROM product p JOIN reception r ON r.id = p.reception_id WHERE \(p.barcode ILIKE \$1 OR p.type ILIKE \$2\) AND r.pvz_id IN \(\$3\)`).
+		WithArgs(`%MSK\_1%`, `%MSK\_1%`, pvzID, "MSK_1", "MSK_1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "datetime", "type", "barcode", "reception_id", "pvz_id"}))
+
+	result, err := q.Search(context.Background(), "MSK_1", 10, []string{pvzID})
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.PVZ)
+	assert.Len(t, result.Receptions, 1)
+	assert.Equal(t, "MSK_1-2026-000001", result.Receptions[0].Number)
+	assert.NotNil(t, result.Products)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,208 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// SessionQueriesInterface определяет интерфейс для запросов к сессиям пользователей
+type SessionQueriesInterface interface {
+	CreateSession(ctx context.Context, session models.Session) (*models.Session, error)
+	GetActiveSessions(ctx context.Context, userID string) ([]models.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+	RevokeOtherSessions(ctx context.Context, userID, keepSessionID string) (int64, error)
+	RotateRefreshToken(ctx context.Context, oldHash, newHash string) (*models.Session, error)
+}
+
+// SessionQueries содержит методы запросов для работы с сессиями пользователей
+type SessionQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ SessionQueriesInterface = (*SessionQueries)(nil)
+
+// NewSessionQueries создает новый экземпляр SessionQueries
+func NewSessionQueries(db *db.Database) *SessionQueries {
+	return &SessionQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+	}
+}
+
+const sessionColumns = "id, user_id, user_agent, ip, issued_at, expires_at, revoked_at"
+
+// CreateSession сохраняет новую сессию пользователя
+func (q *SessionQueries) CreateSession(ctx context.Context, session models.Session) (*models.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionQueries.CreateSession")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Insert("user_sessions").
+		Columns("user_id", "user_agent", "ip", "expires_at", "refresh_token_hash").
+		Values(session.UserID, session.UserAgent, session.IP, session.ExpiresAt, nullString(session.RefreshTokenHash)).
+		Suffix("RETURNING " + sessionColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var created models.Session
+	err = q.db.QueryRowxContext(ctx, qsql, args...).StructScan(&created)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return &created, nil
+}
+
+// GetActiveSessions получает неотозванные и неистекшие сессии пользователя, новые первыми
+func (q *SessionQueries) GetActiveSessions(ctx context.Context, userID string) ([]models.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionQueries.GetActiveSessions")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Select(sessionColumns).
+		From("user_sessions").
+		Where(squirrel.Eq{"user_id": userID, "revoked_at": nil}).
+		Where("expires_at > CURRENT_TIMESTAMP").
+		OrderBy("issued_at DESC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var sessions []models.Session
+	err = q.db.SelectContext(ctx, &sessions, qsql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession отзывает активную сессию пользователя.
+// Возвращает ErrNotFound, если у пользователя нет такой активной сессии
+func (q *SessionQueries) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	ctx, span := tracing.Start(ctx, "SessionQueries.RevokeSession")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Update("user_sessions").
+		Set("revoked_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"id": sessionID, "user_id": userID, "revoked_at": nil}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, qsql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("session %s: %w", sessionID, ErrNotFound)
+	}
+
+	return nil
+}
+
+// IsSessionActive сообщает, что сессия существует и не отозвана.
+// Проверка выполняется на каждый запрос с токеном сессии, поэтому временные ошибки повторяются
+func (q *SessionQueries) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "SessionQueries.IsSessionActive")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Select("revoked_at IS NULL").
+		From("user_sessions").
+		Where(squirrel.Eq{"id": sessionID}).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var active bool
+	err = q.db.Retry(ctx, func(ctx context.Context) error {
+		return q.db.QueryRowContext(ctx, qsql, args...).Scan(&active)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check session: %w", err)
+	}
+
+	return active, nil
+}
+
+// RevokeOtherSessions отзывает все активные сессии пользователя, кроме keepSessionID.
+// С пустым keepSessionID отзываются все сессии
+func (q *SessionQueries) RevokeOtherSessions(ctx context.Context, userID, keepSessionID string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "SessionQueries.RevokeOtherSessions")
+	defer span.End()
+
+	query := q.sq.
+		Update("user_sessions").
+		Set("revoked_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"user_id": userID, "revoked_at": nil})
+	if keepSessionID != "" {
+		query = query.Where(squirrel.NotEq{"id": keepSessionID})
+	}
+
+	qsql, args, err := query.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, qsql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// RotateRefreshToken заменяет refresh-токен активной сессии и возвращает сессию.
+// Замена выполняется одним запросом, поэтому один refresh-токен обменивается не больше одного раза.
+// Возвращает ErrNotFound, если токен неизвестен, уже заменен или сессия отозвана либо истекла
+func (q *SessionQueries) RotateRefreshToken(ctx context.Context, oldHash, newHash string) (*models.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionQueries.RotateRefreshToken")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Update("user_sessions").
+		Set("refresh_token_hash", newHash).
+		Where(squirrel.Eq{"refresh_token_hash": oldHash, "revoked_at": nil}).
+		Where("expires_at > CURRENT_TIMESTAMP").
+		Suffix("RETURNING " + sessionColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var session models.Session
+	err = q.db.QueryRowxContext(ctx, qsql, args...).StructScan(&session)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("refresh token: %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	return &session, nil
+}
@@ -0,0 +1,183 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+)
+
+func setupSessionQueriesTest(t *testing.T) (*SessionQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &SessionQueries{
+		db: &db.Database{DB: sqlxDB},
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+var sessionRowColumns = []string{"id", "user_id", "user_agent", "ip", "issued_at", "expires_at", "revoked_at"}
+
+func TestSessionQueries_CreateSession(t *testing.T) {
+	q, mock := setupSessionQueriesTest(t)
+
+	sessionID := uuid.New().String()
+	userID := uuid.New().String()
+	expiresAt := time.Now().Add(time.Hour)
+
+	mock.ExpectQuery(`INSERT INTO user_sessions \(user_id,user_agent,ip,expires_at,refresh_token_hash\) VALUES \(\$1,\$2,\$3,\$4,\$5\) RETURNING`).
+		WithArgs(userID, "curl/8.0", "10.0.0.1", expiresAt, nil).
+		WillReturnRows(sqlmock.NewRows(sessionRowColumns).
+			AddRow(sessionID, userID, "curl/8.0", "10.0.0.1", time.Now(), expiresAt, nil))
+
+	session, err := q.CreateSession(context.Background(), models.Session{
+		UserID:    userID,
+		UserAgent: "curl/8.0",
+		IP:        "10.0.0.1",
+		ExpiresAt: expiresAt,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, sessionID, session.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionQueries_RotateRefreshToken(t *testing.T) {
+	q, mock := setupSessionQueriesTest(t)
+
+	sessionID := uuid.New().String()
+	userID := uuid.New().String()
+
+	mock.ExpectQuery(`UPDATE user_sessions SET refresh_token_hash = \$1 WHERE refresh_token_hash = \$2 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP RETURNING`).
+		WithArgs("new-hash", "old-hash").
+		WillReturnRows(sqlmock.NewRows(sessionRowColumns).
+			AddRow(sessionID, userID, "kiosk", "10.0.0.1", time.Now(), time.Now().Add(time.Hour), nil))
+
+	session, err := q.RotateRefreshToken(context.Background(), "old-hash", "new-hash")
+	assert.NoError(t, err)
+	assert.Equal(t, sessionID, session.ID)
+
+	// Уже замененный токен не обменивается повторно
+	mock.ExpectQuery(`UPDATE user_sessions SET refresh_token_hash`).
+		WithArgs("new-hash", "old-hash").
+		WillReturnRows(sqlmock.NewRows(sessionRowColumns))
+
+	_, err = q.RotateRefreshToken(context.Background(), "old-hash", "new-hash")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionQueries_GetActiveSessions(t *testing.T) {
+	q, mock := setupSessionQueriesTest(t)
+
+	userID := uuid.New().String()
+
+	mock.ExpectQuery(`SELECT .* FROM user_sessions WHERE revoked_at IS NULL AND user_id = \$1 AND expires_at > CURRENT_TIMESTAMP ORDER BY issued_at DESC`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows(sessionRowColumns).
+			AddRow(uuid.New().String(), userID, "curl/8.0", "10.0.0.1", time.Now(), time.Now().Add(time.Hour), nil))
+
+	sessions, err := q.GetActiveSessions(context.Background(), userID)
+
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionQueries_RevokeSession(t *testing.T) {
+	q, mock := setupSessionQueriesTest(t)
+
+	sessionID := uuid.New().String()
+	userID := uuid.New().String()
+
+	t.Run("Сессия отзывается", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE user_sessions SET revoked_at = CURRENT_TIMESTAMP WHERE id = \$1 AND revoked_at IS NULL AND user_id = \$2`).
+			WithArgs(sessionID, userID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, q.RevokeSession(context.Background(), userID, sessionID))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Чужая или завершенная сессия не найдена", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE user_sessions SET revoked_at`).
+			WithArgs(sessionID, userID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := q.RevokeSession(context.Background(), userID, sessionID)
+
+		assert.True(t, errors.Is(err, ErrNotFound))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSessionQueries_RevokeOtherSessions(t *testing.T) {
+	q, mock := setupSessionQueriesTest(t)
+
+	sessionID := uuid.New().String()
+	userID := uuid.New().String()
+
+	t.Run("Текущая сессия сохраняется", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE user_sessions SET revoked_at = CURRENT_TIMESTAMP WHERE revoked_at IS NULL AND user_id = \$1 AND id <> \$2`).
+			WithArgs(userID, sessionID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		revoked, err := q.RevokeOtherSessions(context.Background(), userID, sessionID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), revoked)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Без текущей сессии отзываются все", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE user_sessions SET revoked_at = CURRENT_TIMESTAMP WHERE revoked_at IS NULL AND user_id = \$1$`).
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+
+		revoked, err := q.RevokeOtherSessions(context.Background(), userID, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), revoked)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSessionQueries_IsSessionActive(t *testing.T) {
+	q, mock := setupSessionQueriesTest(t)
+
+	sessionID := uuid.New().String()
+
+	t.Run("Отозванная сессия неактивна", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT revoked_at IS NULL FROM user_sessions WHERE id = \$1`).
+			WithArgs(sessionID).
+			WillReturnRows(sqlmock.NewRows([]string{"active"}).AddRow(false))
+
+		active, err := q.IsSessionActive(context.Background(), sessionID)
+
+		assert.NoError(t, err)
+		assert.False(t, active)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Неизвестная сессия неактивна", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT revoked_at IS NULL FROM user_sessions`).
+			WithArgs(sessionID).
+			WillReturnRows(sqlmock.NewRows([]string{"active"}))
+
+		active, err := q.IsSessionActive(context.Background(), sessionID)
+
+		assert.NoError(t, err)
+		assert.False(t, active)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
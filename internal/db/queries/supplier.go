@@ -0,0 +1,222 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+// SupplierQueriesInterface определяет интерфейс для запросов к поставщикам
+type SupplierQueriesInterface interface {
+	ListSuppliers(ctx context.Context) ([]models.Supplier, error)
+	GetSupplier(ctx context.Context, supplierID string) (*models.Supplier, error)
+	CreateSupplier(ctx context.Context, req models.SupplierRequest) (*models.Supplier, error)
+	UpdateSupplier(ctx context.Context, supplierID string, req models.SupplierRequest) (*models.Supplier, error)
+	DeleteSupplier(ctx context.Context, supplierID string) error
+	SetReceptionSupplier(ctx context.Context, receptionID, supplierID string) (*models.Reception, error)
+}
+
+// SupplierQueries содержит методы запросов для работы с поставщиками
+type SupplierQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ SupplierQueriesInterface = (*SupplierQueries)(nil)
+
+// NewSupplierQueries создает новый экземпляр SupplierQueries
+func NewSupplierQueries(db *db.Database) *SupplierQueries {
+	return &SupplierQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+	}
+}
+
+const supplierColumns = "id, name, inn, contact, created_at, updated_at"
+
+// ListSuppliers получает всех поставщиков в порядке названий
+func (q *SupplierQueries) ListSuppliers(ctx context.Context) ([]models.Supplier, error) {
+	ctx, span := tracing.Start(ctx, "SupplierQueries.ListSuppliers")
+	defer span.End()
+
+	query, args, err := q.sq.
+		Select(supplierColumns).
+		From("suppliers").
+		OrderBy("name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var suppliers []models.Supplier
+	if err := q.db.SelectContext(ctx, &suppliers, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get suppliers: %w", err)
+	}
+
+	return suppliers, nil
+}
+
+// GetSupplier получает поставщика, возвращает ErrNotFound, если поставщика нет
+func (q *SupplierQueries) GetSupplier(ctx context.Context, supplierID string) (*models.Supplier, error) {
+	ctx, span := tracing.Start(ctx, "SupplierQueries.GetSupplier")
+	defer span.End()
+
+	query, args, err := q.sq.
+		Select(supplierColumns).
+		From("suppliers").
+		Where(squirrel.Eq{"id": supplierID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var supplier models.Supplier
+	err = q.db.GetContext(ctx, &supplier, query, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("supplier %s: %w", supplierID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get supplier: %w", err)
+	}
+
+	return &supplier, nil
+}
+
+// CreateSupplier создает поставщика. Если название или ИНН заняты, возвращает ErrSupplierTaken
+func (q *SupplierQueries) CreateSupplier(ctx context.Context, req models.SupplierRequest) (*models.Supplier, error) {
+	ctx, span := tracing.Start(ctx, "SupplierQueries.CreateSupplier")
+	defer span.End()
+
+	query, args, err := q.sq.
+		Insert("suppliers").
+		Columns("name", "inn", "contact").
+		Values(req.Name, req.INN, req.Contact).
+		Suffix("RETURNING " + supplierColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var supplier models.Supplier
+	err = q.db.QueryRowxContext(ctx, query, args...).StructScan(&supplier)
+	if isUniqueViolation(err) {
+		return nil, ErrSupplierTaken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create supplier: %w", err)
+	}
+
+	return &supplier, nil
+}
+
+// UpdateSupplier изменяет поставщика. Возвращает ErrNotFound, если поставщика нет,
+// и ErrSupplierTaken, если название или ИНН заняты другим поставщиком
+func (q *SupplierQueries) UpdateSupplier(ctx context.Context, supplierID string, req models.SupplierRequest) (*models.Supplier, error) {
+	ctx, span := tracing.Start(ctx, "SupplierQueries.UpdateSupplier")
+	defer span.End()
+
+	query, args, err := q.sq.
+		Update("suppliers").
+		Set("name", req.Name).
+		Set("inn", req.INN).
+		Set("contact", req.Contact).
+		Set("updated_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"id": supplierID}).
+		Suffix("RETURNING " + supplierColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var supplier models.Supplier
+	err = q.db.QueryRowxContext(ctx, query, args...).StructScan(&supplier)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("supplier %s: %w", supplierID, ErrNotFound)
+	}
+	if isUniqueViolation(err) {
+		return nil, ErrSupplierTaken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update supplier: %w", err)
+	}
+
+	return &supplier, nil
+}
+
+// DeleteSupplier удаляет поставщика. Возвращает ErrNotFound, если поставщика нет,
+// и ErrSupplierInUse, если у поставщика есть приёмки
+func (q *SupplierQueries) DeleteSupplier(ctx context.Context, supplierID string) error {
+	ctx, span := tracing.Start(ctx, "SupplierQueries.DeleteSupplier")
+	defer span.End()
+
+	query, args, err := q.sq.
+		Delete("suppliers").
+		Where(squirrel.Eq{"id": supplierID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, query, args...)
+	if isForeignKeyViolation(err) {
+		return ErrSupplierInUse
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete supplier: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("supplier %s: %w", supplierID, ErrNotFound)
+	}
+
+	return nil
+}
+
+// SetReceptionSupplier указывает поставщика приёмки. Вызывается в транзакции создания приёмки,
+// возвращает ErrNotFound, если приёмки или поставщика нет
+func (q *SupplierQueries) SetReceptionSupplier(ctx context.Context, receptionID, supplierID string) (*models.Reception, error) {
+	ctx, span := tracing.Start(ctx, "SupplierQueries.SetReceptionSupplier")
+	defer span.End()
+
+	query, args, err := q.sq.
+		Update("reception").
+		Set("supplier_id", supplierID).
+		Where(squirrel.Eq{"id": receptionID}).
+		Suffix("RETURNING " + receptionColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var reception models.Reception
+	err = q.db.QueryRowxContext(ctx, query, args...).StructScan(&reception)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("reception %s: %w", receptionID, ErrNotFound)
+	}
+	if isForeignKeyViolation(err) {
+		return nil, fmt.Errorf("supplier %s: %w", supplierID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to set reception supplier: %w", err)
+	}
+
+	return &reception, nil
+}
+
+// isForeignKeyViolation сообщает, что запрос нарушил внешний ключ
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
@@ -0,0 +1,88 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+)
+
+func setupSupplierQueriesTest(t *testing.T) (*SupplierQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &SupplierQueries{
+		db: &db.Database{DB: sqlxDB},
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+func TestSupplierQueries_CreateSupplier(t *testing.T) {
+	q, mock := setupSupplierQueriesTest(t)
+	inn := "7701234567"
+	req := models.SupplierRequest{Name: "ООО Ромашка", INN: &inn, Contact: "Иван, +7 900 000-00-00"}
+
+	t.Run("Новый поставщик", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO suppliers \(name,inn,contact\) VALUES \(\$1,\$2,\$3\) RETURNING id, name, inn, contact, created_at, updated_at`).
+			WithArgs(req.Name, &inn, req.Contact).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "inn", "contact", "created_at", "updated_at"}).
+				AddRow("supplier-1", req.Name, inn, req.Contact, time.Now(), time.Now()))
+
+		supplier, err := q.CreateSupplier(context.Background(), req)
+
+		require.NoError(t, err)
+		assert.Equal(t, "supplier-1", supplier.ID)
+		assert.Equal(t, inn, *supplier.INN)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ИНН занят", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO suppliers`).
+			WillReturnError(&pq.Error{Code: "23505"})
+
+		_, err := q.CreateSupplier(context.Background(), req)
+
+		assert.True(t, errors.Is(err, ErrSupplierTaken))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSupplierQueries_DeleteSupplier(t *testing.T) {
+	q, mock := setupSupplierQueriesTest(t)
+
+	mock.ExpectExec(`DELETE FROM suppliers WHERE id = \$1`).
+		WithArgs("supplier-1").
+		WillReturnError(&pq.Error{Code: "23503"})
+	mock.ExpectExec(`DELETE FROM suppliers WHERE id = \$1`).
+		WithArgs("supplier-2").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.True(t, errors.Is(q.DeleteSupplier(context.Background(), "supplier-1"), ErrSupplierInUse))
+	assert.True(t, errors.Is(q.DeleteSupplier(context.Background(), "supplier-2"), ErrNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSupplierQueries_SetReceptionSupplier(t *testing.T) {
+	q, mock := setupSupplierQueriesTest(t)
+
+	mock.ExpectQuery(`UPDATE reception SET supplier_id = \$1 WHERE id = \$2 RETURNING id, .*, supplier_id`).
+		WithArgs("supplier-1", "reception-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "pvz_id", "status", "tags", "supplier_id"}).
+			AddRow("reception-1", "pvz-1", "in_progress", "{}", "supplier-1"))
+
+	reception, err := q.SetReceptionSupplier(context.Background(), "reception-1", "supplier-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "supplier-1", *reception.SupplierID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,147 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// SyncQueriesInterface определяет интерфейс для запросов инкрементальной синхронизации
+type SyncQueriesInterface interface {
+	GetChanges(ctx context.Context, params models.SyncParams) (*models.SyncChanges, error)
+}
+
+// SyncQueries содержит методы запросов для инкрементальной синхронизации терминалов ПВЗ
+type SyncQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ SyncQueriesInterface = (*SyncQueries)(nil)
+
+// NewSyncQueries создает новый экземпляр SyncQueries
+func NewSyncQueries(db *db.Database) *SyncQueries {
+	return &SyncQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+	}
+}
+
+// GetChanges получает ПВЗ, приёмки и товары, измененные после позиций курсора и не позже params.Until,
+// и товары, удаленные за это время, не больше params.Limit строк каждого вида.
+// Читает с основного сервера: изменение, которое еще не дошло до реплики, курсор пропустил бы навсегда
+func (q *SyncQueries) GetChanges(ctx context.Context, params models.SyncParams) (*models.SyncChanges, error) {
+	ctx, span := tracing.Start(ctx, "SyncQueries.GetChanges")
+	defer span.End()
+
+	// Время в PostgreSQL хранится с точностью до микросекунд, позиция курсора должна совпадать с ним
+	params.Until = params.Until.UTC().Truncate(time.Microsecond)
+	cursor := params.Cursor
+	changes := &models.SyncChanges{}
+	var more bool
+
+	pvzQuery := q.sq.
+		Select("id", "city", "address", "registration_date", "open_reception", "timezone", "archived_at", "updated_at").
+		From("pvz")
+	if len(params.PvzIDs) > 0 {
+		pvzQuery = pvzQuery.Where(squirrel.Eq{"id": params.PvzIDs})
+	}
+	if err := q.selectPage(ctx, &changes.PVZ, pvzQuery, "updated_at", "id", cursor.PVZ, params); err != nil {
+		return nil, fmt.Errorf("failed to get changed pvz: %w", err)
+	}
+	changes.PVZ, changes.Cursor.PVZ, more = syncPage(changes.PVZ, cursor.PVZ, params, func(pvz models.SyncPVZ) models.SyncPosition {
+		return models.SyncPosition{UpdatedAt: pvz.UpdatedAt, ID: pvz.ID}
+	})
+	changes.HasMore = changes.HasMore || more
+
+	receptionQuery := q.sq.
+		Select(receptionColumns, "updated_at").
+		From("reception")
+	if len(params.PvzIDs) > 0 {
+		receptionQuery = receptionQuery.Where(squirrel.Eq{"pvz_id": params.PvzIDs})
+	}
+	if err := q.selectPage(ctx, &changes.Receptions, receptionQuery, "updated_at", "id", cursor.Receptions, params); err != nil {
+		return nil, fmt.Errorf("failed to get changed receptions: %w", err)
+	}
+	changes.Receptions, changes.Cursor.Receptions, more = syncPage(changes.Receptions, cursor.Receptions, params, func(reception models.SyncReception) models.SyncPosition {
+		return models.SyncPosition{UpdatedAt: reception.UpdatedAt, ID: reception.ID}
+	})
+	changes.HasMore = changes.HasMore || more
+
+	productQuery := q.sq.
+		Select("p.id", "p.datetime", "p.type", "p.reception_id", "p.barcode", "p.status", "p.updated_at").
+		From("product p")
+	if len(params.PvzIDs) > 0 {
+		productQuery = productQuery.
+			Join("reception r ON r.id = p.reception_id").
+			Where(squirrel.Eq{"r.pvz_id": params.PvzIDs})
+	}
+	if err := q.selectPage(ctx, &changes.Products, productQuery, "p.updated_at", "p.id", cursor.Products, params); err != nil {
+		return nil, fmt.Errorf("failed to get changed products: %w", err)
+	}
+	changes.Products, changes.Cursor.Products, more = syncPage(changes.Products, cursor.Products, params, func(product models.SyncProduct) models.SyncPosition {
+		return models.SyncPosition{UpdatedAt: product.UpdatedAt, ID: product.ID}
+	})
+	changes.HasMore = changes.HasMore || more
+
+	deletedQuery := q.sq.
+		Select("product_id", "reception_id", "pvz_id", "deleted_at").
+		From("product_tombstones")
+	if len(params.PvzIDs) > 0 {
+		deletedQuery = deletedQuery.Where(squirrel.Eq{"pvz_id": params.PvzIDs})
+	}
+	if err := q.selectPage(ctx, &changes.DeletedProducts, deletedQuery, "deleted_at", "product_id", cursor.DeletedProducts, params); err != nil {
+		return nil, fmt.Errorf("failed to get deleted products: %w", err)
+	}
+	changes.DeletedProducts, changes.Cursor.DeletedProducts, more = syncPage(changes.DeletedProducts, cursor.DeletedProducts, params, func(product models.DeletedProduct) models.SyncPosition {
+		return models.SyncPosition{UpdatedAt: product.DeletedAt, ID: product.ProductID}
+	})
+	changes.HasMore = changes.HasMore || more
+
+	return changes, nil
+}
+
+// selectPage выбирает строки query, измененные после position и не позже params.Until,
+// в порядке (время изменения, ID). Выбирается на одну строку больше лимита, чтобы узнать, есть ли еще
+func (q *SyncQueries) selectPage(ctx context.Context, dest interface{}, query squirrel.SelectBuilder, updatedColumn, idColumn string, position models.SyncPosition, params models.SyncParams) error {
+	if position.ID == "" {
+		query = query.Where(squirrel.Gt{updatedColumn: position.UpdatedAt})
+	} else {
+		query = query.Where(squirrel.Expr(fmt.Sprintf("(%s, %s) > (?, ?)", updatedColumn, idColumn), position.UpdatedAt, position.ID))
+	}
+
+	sql, args, err := query.
+		Where(squirrel.LtOrEq{updatedColumn: params.Until}).
+		OrderBy(updatedColumn, idColumn).
+		Limit(uint64(params.Limit + 1)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	return q.db.SelectContext(ctx, dest, sql, args...)
+}
+
+// syncPage обрезает лишнюю строку, выбранную selectPage, и возвращает позицию следующего запроса:
+// после последней переданной строки, если строк больше лимита, иначе params.Until.
+// Позиция не сдвигается назад, если курсор новее params.Until
+func syncPage[T any](rows []T, current models.SyncPosition, params models.SyncParams, position func(T) models.SyncPosition) ([]T, models.SyncPosition, bool) {
+	if rows == nil {
+		rows = []T{}
+	}
+	if len(rows) > params.Limit {
+		rows = rows[:params.Limit]
+		return rows, position(rows[len(rows)-1]), true
+	}
+
+	if current.UpdatedAt.After(params.Until) {
+		return rows, current, false
+	}
+	return rows, models.SyncPosition{UpdatedAt: params.Until}, false
+}
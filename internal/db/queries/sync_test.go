@@ -0,0 +1,77 @@
+package queries
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+)
+
+func setupSyncQueriesTest(t *testing.T) (*SyncQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &SyncQueries{
+		db: &db.Database{DB: sqlxDB},
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+func TestSyncQueries_GetChanges(t *testing.T) {
+	q, mock := setupSyncQueriesTest(t)
+
+	since := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	changedAt := since.Add(time.Hour)
+	pvzIDs := []string{"pvz-1"}
+
+	cursor := models.SyncCursor{
+		PVZ:             models.SyncPosition{UpdatedAt: since},
+		Receptions:      models.SyncPosition{UpdatedAt: since, ID: "reception-0"},
+		Products:        models.SyncPosition{UpdatedAt: since},
+		DeletedProducts: models.SyncPosition{UpdatedAt: since},
+	}
+
+	// Лимит 1: выбирается на строку больше, чтобы узнать, есть ли еще изменения
+	mock.ExpectQuery(`SELECT id, city, address, registration_date, open_reception, timezone, archived_at, updated_at FROM pvz WHERE id IN \(\$1\) AND updated_at > \$2 AND updated_at <= \$3 ORDER BY updated_at, id LIMIT 2`).
+		WithArgs("pvz-1", since, until).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "city", "address", "registration_date", "open_reception", "timezone", "archived_at", "updated_at"}).
+			AddRow("pvz-1", "Москва", "", since, true, nil, nil, changedAt))
+	mock.ExpectQuery(`SELECT id, .*, supplier_id, updated_at FROM reception WHERE pvz_id IN \(\$1\) AND \(updated_at, id\) > \(\$2, \$3\) AND updated_at <= \$4 ORDER BY updated_at, id LIMIT 2`).
+		WithArgs("pvz-1", since, "reception-0", until).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "pvz_id", "status", "tags", "updated_at"}).
+			AddRow("reception-1", "pvz-1", "in_progress", "{}", changedAt).
+			AddRow("reception-2", "pvz-1", "in_progress", "{}", changedAt))
+	mock.ExpectQuery(`SELECT p.id, p.datetime, p.type, p.reception_id, p.barcode, p.status, p.updated_at FROM product p JOIN reception r ON r.id = p.reception_id WHERE r.pvz_id IN \(\$1\) AND p.updated_at > \$2 AND p.updated_at <= \$3 ORDER BY p.updated_at, p.id LIMIT 2`).
+		WithArgs("pvz-1", since, until).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "datetime", "type", "reception_id", "barcode", "status", "updated_at"}))
+	mock.ExpectQuery(`SELECT product_id, reception_id, pvz_id, deleted_at FROM product_tombstones WHERE pvz_id IN \(\$1\) AND deleted_at > \$2 AND deleted_at <= \$3 ORDER BY deleted_at, product_id LIMIT 2`).
+		WithArgs("pvz-1", since, until).
+		WillReturnRows(sqlmock.NewRows([]string{"product_id", "reception_id", "pvz_id", "deleted_at"}).
+			AddRow("product-1", "reception-1", "pvz-1", changedAt))
+
+	changes, err := q.GetChanges(context.Background(), models.SyncParams{Cursor: cursor, Until: until, PvzIDs: pvzIDs, Limit: 1})
+
+	require.NoError(t, err)
+	assert.True(t, changes.HasMore)
+	assert.Len(t, changes.PVZ, 1)
+	assert.True(t, changes.PVZ[0].OpenReception)
+	assert.Len(t, changes.Receptions, 1)
+	assert.Empty(t, changes.Products)
+	assert.Len(t, changes.DeletedProducts, 1)
+
+	// Приёмки продолжаются с последней переданной, остальные виды переданы до until
+	assert.Equal(t, models.SyncPosition{UpdatedAt: changedAt, ID: "reception-1"}, changes.Cursor.Receptions)
+	assert.Equal(t, models.SyncPosition{UpdatedAt: until}, changes.Cursor.PVZ)
+	assert.Equal(t, models.SyncPosition{UpdatedAt: until}, changes.Cursor.Products)
+	assert.Equal(t, models.SyncPosition{UpdatedAt: until}, changes.Cursor.DeletedProducts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
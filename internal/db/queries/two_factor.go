@@ -0,0 +1,357 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// TwoFactorQueriesInterface определяет интерфейс для запросов двухфакторной аутентификации
+type TwoFactorQueriesInterface interface {
+	GetTOTP(ctx context.Context, userID string) (*models.UserTOTP, error)
+	SaveTOTPSecret(ctx context.Context, userID, secret string) error
+	EnableTOTP(ctx context.Context, userID string, step int64) error
+	UseTOTPStep(ctx context.Context, userID string, step int64) error
+	DeleteTOTP(ctx context.Context, userID string) error
+	ReplaceBackupCodes(ctx context.Context, userID string, codeHashes []string) error
+	UseBackupCode(ctx context.Context, userID, codeHash string) error
+	CountBackupCodes(ctx context.Context, userID string) (int, error)
+	CreateChallenge(ctx context.Context, userID, tokenHash string, rememberMe bool, expiresAt time.Time) error
+	GetActiveChallenge(ctx context.Context, tokenHash string) (*models.LoginChallenge, error)
+	IncrementChallengeAttempts(ctx context.Context, challengeID string, maxAttempts int) error
+	ConsumeChallenge(ctx context.Context, challengeID string) error
+}
+
+// TwoFactorQueries содержит методы запросов к секретам TOTP, резервным кодам и незавершенным входам
+type TwoFactorQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ TwoFactorQueriesInterface = (*TwoFactorQueries)(nil)
+
+// NewTwoFactorQueries создает новый экземпляр TwoFactorQueries
+func NewTwoFactorQueries(db *db.Database) *TwoFactorQueries {
+	return &TwoFactorQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+	}
+}
+
+// GetTOTP получает секрет TOTP пользователя, подтвержденный или ожидающий подтверждения
+func (q *TwoFactorQueries) GetTOTP(ctx context.Context, userID string) (*models.UserTOTP, error) {
+	ctx, span := tracing.Start(ctx, "TwoFactorQueries.GetTOTP")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Select("user_id", "secret", "last_used_step", "enabled_at").
+		From("user_totp").
+		Where(squirrel.Eq{"user_id": userID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var totp models.UserTOTP
+	err = q.db.GetContext(ctx, &totp, qsql, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("totp of user %s: %w", userID, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get totp: %w", err)
+	}
+
+	return &totp, nil
+}
+
+// SaveTOTPSecret сохраняет новый секрет, ожидающий подтверждения, вместо прежнего неподтвержденного.
+// Подтвержденный секрет не заменяется: в этом случае возвращается ErrNotFound
+func (q *TwoFactorQueries) SaveTOTPSecret(ctx context.Context, userID, secret string) error {
+	ctx, span := tracing.Start(ctx, "TwoFactorQueries.SaveTOTPSecret")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Insert("user_totp").
+		Columns("user_id", "secret").
+		Values(userID, secret).
+		Suffix("ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, last_used_step = 0, created_at = CURRENT_TIMESTAMP WHERE user_totp.enabled_at IS NULL").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, qsql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to save totp secret: %w", err)
+	}
+
+	return requireAffected(result, fmt.Sprintf("pending totp of user %s", userID))
+}
+
+// EnableTOTP подтверждает секрет пользователя кодом шага step. Возвращает ErrNotFound,
+// если секрета нет или он уже подтвержден
+func (q *TwoFactorQueries) EnableTOTP(ctx context.Context, userID string, step int64) error {
+	ctx, span := tracing.Start(ctx, "TwoFactorQueries.EnableTOTP")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Update("user_totp").
+		Set("enabled_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Set("last_used_step", step).
+		Where(squirrel.Eq{"user_id": userID, "enabled_at": nil}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, qsql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to enable totp: %w", err)
+	}
+
+	return requireAffected(result, fmt.Sprintf("pending totp of user %s", userID))
+}
+
+// UseTOTPStep запоминает шаг принятого кода. Код того же или более раннего шага
+// уже использован: в этом случае возвращается ErrNotFound
+func (q *TwoFactorQueries) UseTOTPStep(ctx context.Context, userID string, step int64) error {
+	ctx, span := tracing.Start(ctx, "TwoFactorQueries.UseTOTPStep")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Update("user_totp").
+		Set("last_used_step", step).
+		Where(squirrel.Eq{"user_id": userID}).
+		Where(squirrel.Lt{"last_used_step": step}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, qsql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to use totp step: %w", err)
+	}
+
+	return requireAffected(result, fmt.Sprintf("unused totp step of user %s", userID))
+}
+
+// DeleteTOTP удаляет секрет TOTP и резервные коды пользователя
+func (q *TwoFactorQueries) DeleteTOTP(ctx context.Context, userID string) error {
+	ctx, span := tracing.Start(ctx, "TwoFactorQueries.DeleteTOTP")
+	defer span.End()
+
+	for _, table := range []string{"totp_backup_codes", "user_totp"} {
+		qsql, args, err := q.sq.Delete(table).Where(squirrel.Eq{"user_id": userID}).ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build query: %w", err)
+		}
+		if _, err := q.db.ExecContext(ctx, qsql, args...); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", table, err)
+		}
+	}
+
+	return nil
+}
+
+// ReplaceBackupCodes заменяет резервные коды пользователя новыми, прежние перестают действовать
+func (q *TwoFactorQueries) ReplaceBackupCodes(ctx context.Context, userID string, codeHashes []string) error {
+	ctx, span := tracing.Start(ctx, "TwoFactorQueries.ReplaceBackupCodes")
+	defer span.End()
+
+	qsql, args, err := q.sq.Delete("totp_backup_codes").Where(squirrel.Eq{"user_id": userID}).ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+	if _, err := q.db.ExecContext(ctx, qsql, args...); err != nil {
+		return fmt.Errorf("failed to delete backup codes: %w", err)
+	}
+
+	if len(codeHashes) == 0 {
+		return nil
+	}
+
+	insert := q.sq.Insert("totp_backup_codes").Columns("user_id", "code_hash")
+	for _, codeHash := range codeHashes {
+		insert = insert.Values(userID, codeHash)
+	}
+
+	qsql, args, err = insert.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+	if _, err := q.db.ExecContext(ctx, qsql, args...); err != nil {
+		return fmt.Errorf("failed to create backup codes: %w", err)
+	}
+
+	return nil
+}
+
+// UseBackupCode отмечает резервный код использованным. Возвращает ErrNotFound,
+// если кода нет или он уже использован
+func (q *TwoFactorQueries) UseBackupCode(ctx context.Context, userID, codeHash string) error {
+	ctx, span := tracing.Start(ctx, "TwoFactorQueries.UseBackupCode")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Update("totp_backup_codes").
+		Set("used_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"user_id": userID, "code_hash": codeHash, "used_at": nil}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, qsql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to use backup code: %w", err)
+	}
+
+	return requireAffected(result, fmt.Sprintf("unused backup code of user %s", userID))
+}
+
+// CountBackupCodes считает неиспользованные резервные коды пользователя
+func (q *TwoFactorQueries) CountBackupCodes(ctx context.Context, userID string) (int, error) {
+	ctx, span := tracing.Start(ctx, "TwoFactorQueries.CountBackupCodes")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Select("COUNT(*)").
+		From("totp_backup_codes").
+		Where(squirrel.Eq{"user_id": userID, "used_at": nil}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var count int
+	if err := q.db.GetContext(ctx, &count, qsql, args...); err != nil {
+		return 0, fmt.Errorf("failed to count backup codes: %w", err)
+	}
+
+	return count, nil
+}
+
+// CreateChallenge сохраняет хеш токена незавершенного входа
+func (q *TwoFactorQueries) CreateChallenge(ctx context.Context, userID, tokenHash string, rememberMe bool, expiresAt time.Time) error {
+	ctx, span := tracing.Start(ctx, "TwoFactorQueries.CreateChallenge")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Insert("login_challenges").
+		Columns("user_id", "token_hash", "remember_me", "expires_at").
+		Values(userID, tokenHash, rememberMe, expiresAt).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := q.db.ExecContext(ctx, qsql, args...); err != nil {
+		return fmt.Errorf("failed to create login challenge: %w", err)
+	}
+
+	return nil
+}
+
+// GetActiveChallenge получает незавершенный вход по хешу токена, если он не истек и не использован
+func (q *TwoFactorQueries) GetActiveChallenge(ctx context.Context, tokenHash string) (*models.LoginChallenge, error) {
+	ctx, span := tracing.Start(ctx, "TwoFactorQueries.GetActiveChallenge")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Select("id", "user_id", "remember_me", "attempts", "expires_at").
+		From("login_challenges").
+		Where(squirrel.Eq{"token_hash": tokenHash, "consumed_at": nil}).
+		Where(squirrel.Expr("expires_at > CURRENT_TIMESTAMP")).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var challenge models.LoginChallenge
+	err = q.db.GetContext(ctx, &challenge, qsql, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("login challenge: %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get login challenge: %w", err)
+	}
+
+	return &challenge, nil
+}
+
+// IncrementChallengeAttempts списывает попытку ввода второго фактора, если их меньше maxAttempts.
+// Проверка и увеличение счетчика идут одним запросом, поэтому параллельные проверки не получат
+// больше maxAttempts попыток. Если попытки исчерпаны, возвращает ErrNotFound
+func (q *TwoFactorQueries) IncrementChallengeAttempts(ctx context.Context, challengeID string, maxAttempts int) error {
+	ctx, span := tracing.Start(ctx, "TwoFactorQueries.IncrementChallengeAttempts")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Update("login_challenges").
+		Set("attempts", squirrel.Expr("attempts + 1")).
+		Where(squirrel.Eq{"id": challengeID}).
+		Where(squirrel.Lt{"attempts": maxAttempts}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, qsql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to increment login challenge attempts: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("login challenge %s attempts: %w", challengeID, ErrNotFound)
+	}
+
+	return nil
+}
+
+// ConsumeChallenge помечает незавершенный вход использованным, повторное использование возвращает ErrNotFound
+func (q *TwoFactorQueries) ConsumeChallenge(ctx context.Context, challengeID string) error {
+	ctx, span := tracing.Start(ctx, "TwoFactorQueries.ConsumeChallenge")
+	defer span.End()
+
+	qsql, args, err := q.sq.
+		Update("login_challenges").
+		Set("consumed_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"id": challengeID, "consumed_at": nil}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := q.db.ExecContext(ctx, qsql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to consume login challenge: %w", err)
+	}
+
+	return requireAffected(result, fmt.Sprintf("login challenge %s", challengeID))
+}
+
+// requireAffected возвращает ErrNotFound для what, если запрос не изменил ни одной строки
+func requireAffected(result sql.Result, what string) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("%s: %w", what, ErrNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package queries
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db"
+)
+
+func setupTwoFactorQueriesTest(t *testing.T) (*TwoFactorQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &TwoFactorQueries{
+		db: &db.Database{DB: sqlxDB},
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+func TestTwoFactorQueries_SaveTOTPSecret(t *testing.T) {
+	q, mock := setupTwoFactorQueriesTest(t)
+	expectedSQL := `INSERT INTO user_totp \(user_id,secret\) VALUES \(\$1,\$2\) ON CONFLICT \(user_id\) DO UPDATE ` +
+		`SET secret = EXCLUDED.secret, last_used_step = 0, created_at = CURRENT_TIMESTAMP WHERE user_totp.enabled_at IS NULL`
+
+	t.Run("Секрет ждет подтверждения", func(t *testing.T) {
+		mock.ExpectExec(expectedSQL).WithArgs("user-id", "SECRET").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, q.SaveTOTPSecret(context.Background(), "user-id", "SECRET"))
+	})
+
+	t.Run("Секрет уже подтвержден", func(t *testing.T) {
+		mock.ExpectExec(expectedSQL).WithArgs("user-id", "SECRET").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, q.SaveTOTPSecret(context.Background(), "user-id", "SECRET"), ErrNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTwoFactorQueries_UseTOTPStep(t *testing.T) {
+	q, mock := setupTwoFactorQueriesTest(t)
+	expectedSQL := `UPDATE user_totp SET last_used_step = \$1 WHERE user_id = \$2 AND last_used_step < \$3`
+
+	mock.ExpectExec(expectedSQL).WithArgs(int64(100), "user-id", int64(100)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(expectedSQL).WithArgs(int64(100), "user-id", int64(100)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, q.UseTOTPStep(context.Background(), "user-id", 100))
+	// Код того же шага повторно не принимается
+	assert.ErrorIs(t, q.UseTOTPStep(context.Background(), "user-id", 100), ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTwoFactorQueries_IncrementChallengeAttempts(t *testing.T) {
+	q, mock := setupTwoFactorQueriesTest(t)
+	expectedSQL := `UPDATE login_challenges SET attempts = attempts \+ 1 WHERE id = \$1 AND attempts < \$2`
+
+	mock.ExpectExec(expectedSQL).WithArgs("challenge-id", 3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(expectedSQL).WithArgs("challenge-id", 3).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, q.IncrementChallengeAttempts(context.Background(), "challenge-id", 3))
+	// Попытки исчерпаны: счетчик не меняется
+	assert.ErrorIs(t, q.IncrementChallengeAttempts(context.Background(), "challenge-id", 3), ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTwoFactorQueries_ReplaceBackupCodes(t *testing.T) {
+	q, mock := setupTwoFactorQueriesTest(t)
+
+	mock.ExpectExec(`DELETE FROM totp_backup_codes WHERE user_id = \$1`).
+		WithArgs("user-id").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`INSERT INTO totp_backup_codes \(user_id,code_hash\) VALUES \(\$1,\$2\),\(\$3,\$4\)`).
+		WithArgs("user-id", "hash1", "user-id", "hash2").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := q.ReplaceBackupCodes(context.Background(), "user-id", []string{"hash1", "hash2"})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,117 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// UsageQueriesInterface определяет интерфейс для запросов к статистике использования API
+type UsageQueriesInterface interface {
+	AddUsage(ctx context.Context, counts []models.UsageCount) error
+	GetUsage(ctx context.Context, params models.UsageQuery) ([]models.UsageStat, error)
+}
+
+// UsageQueries содержит методы запросов для работы со статистикой использования API
+type UsageQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ UsageQueriesInterface = (*UsageQueries)(nil)
+
+// NewUsageQueries создает новый экземпляр UsageQueries
+func NewUsageQueries(db *db.Database) *UsageQueries {
+	return &UsageQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+	}
+}
+
+// usageBatchSize ограничивает число строк в одном INSERT, чтобы не превысить лимит параметров PostgreSQL
+const usageBatchSize = 1000
+
+// AddUsage прибавляет счетчики запросов к статистике. Ключи счетчиков в counts не должны повторяться
+func (q *UsageQueries) AddUsage(ctx context.Context, counts []models.UsageCount) error {
+	ctx, span := tracing.Start(ctx, "UsageQueries.AddUsage")
+	defer span.End()
+
+	for start := 0; start < len(counts); start += usageBatchSize {
+		batch := counts[start:min(start+usageBatchSize, len(counts))]
+
+		insert := q.sq.
+			Insert("api_usage").
+			Columns("bucket", "pvz_id", "user_id", "method", "route", "requests", "errors").
+			Suffix("ON CONFLICT ON CONSTRAINT api_usage_key DO UPDATE SET requests = api_usage.requests + EXCLUDED.requests, errors = api_usage.errors + EXCLUDED.errors")
+		for _, count := range batch {
+			insert = insert.Values(count.Key.Bucket, nullString(count.Key.PvzID), nullString(count.Key.UserID),
+				count.Key.Method, count.Key.Route, count.Requests, count.Errors)
+		}
+
+		sql, args, err := insert.ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build query: %w", err)
+		}
+
+		if _, err := q.db.ExecContext(ctx, sql, args...); err != nil {
+			return fmt.Errorf("failed to add api usage: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetUsage получает число запросов за период в разрезе params.GroupBy (по умолчанию по ПВЗ),
+// от самых частых к редким
+func (q *UsageQueries) GetUsage(ctx context.Context, params models.UsageQuery) ([]models.UsageStat, error) {
+	ctx, span := tracing.Start(ctx, "UsageQueries.GetUsage")
+	defer span.End()
+
+	if params.Limit <= 0 {
+		params.Limit = 100
+	}
+
+	var groupBy []string
+	switch params.GroupBy {
+	case models.UsageGroupUser:
+		groupBy = []string{"user_id"}
+	case models.UsageGroupRoute:
+		groupBy = []string{"method", "route"}
+	default:
+		groupBy = []string{"pvz_id"}
+	}
+
+	query := q.sq.
+		Select(groupBy...).
+		Columns("SUM(requests) AS requests", "SUM(errors) AS errors").
+		From("api_usage").
+		Where(squirrel.GtOrEq{"bucket": params.From}).
+		Where(squirrel.Lt{"bucket": params.To}).
+		GroupBy(groupBy...).
+		OrderBy("requests DESC").
+		Limit(uint64(params.Limit))
+	if params.PvzID != "" {
+		query = query.Where(squirrel.Eq{"pvz_id": params.PvzID})
+	}
+	if params.UserID != "" {
+		query = query.Where(squirrel.Eq{"user_id": params.UserID})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var stats []models.UsageStat
+	err = q.db.SelectContext(ctx, &stats, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api usage: %w", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,81 @@
+package queries
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+)
+
+func setupUsageQueriesTest(t *testing.T) (*UsageQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &UsageQueries{
+		db: &db.Database{DB: sqlxDB},
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+func TestUsageQueries_AddUsage(t *testing.T) {
+	q, mock := setupUsageQueriesTest(t)
+	bucket := time.Date(2026, 5, 4, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(`INSERT INTO api_usage \(bucket,pvz_id,user_id,method,route,requests,errors\) VALUES \(\$1,\$2,\$3,\$4,\$5,\$6,\$7\),\(\$8,\$9,\$10,\$11,\$12,\$13,\$14\) ON CONFLICT ON CONSTRAINT api_usage_key DO UPDATE SET requests = api_usage.requests \+ EXCLUDED.requests, errors = api_usage.errors \+ EXCLUDED.errors`).
+		WithArgs(bucket, "pvz-1", "user-1", "POST", "/api/v1/products", int64(120), int64(3),
+			bucket, nil, "user-1", "GET", "/api/v1/me", int64(1), int64(0)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := q.AddUsage(context.Background(), []models.UsageCount{
+		{Key: models.UsageKey{Bucket: bucket, PvzID: "pvz-1", UserID: "user-1", Method: "POST", Route: "/api/v1/products"}, Requests: 120, Errors: 3},
+		{Key: models.UsageKey{Bucket: bucket, UserID: "user-1", Method: "GET", Route: "/api/v1/me"}, Requests: 1},
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUsageQueries_GetUsage(t *testing.T) {
+	q, mock := setupUsageQueriesTest(t)
+	from := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	t.Run("По ПВЗ", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT pvz_id, SUM\(requests\) AS requests, SUM\(errors\) AS errors FROM api_usage WHERE bucket >= \$1 AND bucket < \$2 GROUP BY pvz_id ORDER BY requests DESC LIMIT 100`).
+			WithArgs(from, to).
+			WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "requests", "errors"}).
+				AddRow("pvz-1", 5000, 4800).
+				AddRow(nil, 20, 0))
+
+		stats, err := q.GetUsage(context.Background(), models.UsageQuery{From: from, To: to})
+
+		require.NoError(t, err)
+		require.Len(t, stats, 2)
+		assert.Equal(t, "pvz-1", *stats[0].PvzID)
+		assert.Equal(t, int64(4800), stats[0].Errors)
+		assert.Nil(t, stats[1].PvzID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Маршруты одного ПВЗ", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT method, route, SUM\(requests\) AS requests, SUM\(errors\) AS errors FROM api_usage WHERE bucket >= \$1 AND bucket < \$2 AND pvz_id = \$3 GROUP BY method, route ORDER BY requests DESC LIMIT 10`).
+			WithArgs(from, to, "pvz-1").
+			WillReturnRows(sqlmock.NewRows([]string{"method", "route", "requests", "errors"}).
+				AddRow("POST", "/api/v1/products", 4900, 4800))
+
+		stats, err := q.GetUsage(context.Background(), models.UsageQuery{From: from, To: to, GroupBy: models.UsageGroupRoute, PvzID: "pvz-1", Limit: 10})
+
+		require.NoError(t, err)
+		require.Len(t, stats, 1)
+		assert.Equal(t, "/api/v1/products", stats[0].Route)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
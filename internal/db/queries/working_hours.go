@@ -0,0 +1,96 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+	"pvz-service/internal/tracing"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// WorkingHoursQueriesInterface определяет интерфейс для запросов к расписанию работы ПВЗ
+type WorkingHoursQueriesInterface interface {
+	GetWorkingHours(ctx context.Context, pvzID string) ([]models.WorkingDay, error)
+	SetWorkingHours(ctx context.Context, pvzID string, days []models.WorkingDay) error
+}
+
+// WorkingHoursQueries содержит методы запросов для работы с расписанием ПВЗ
+type WorkingHoursQueries struct {
+	db *db.Database
+	sq squirrel.StatementBuilderType
+}
+
+var _ WorkingHoursQueriesInterface = (*WorkingHoursQueries)(nil)
+
+// NewWorkingHoursQueries создает новый экземпляр WorkingHoursQueries
+func NewWorkingHoursQueries(db *db.Database) *WorkingHoursQueries {
+	return &WorkingHoursQueries{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).RunWith(db),
+	}
+}
+
+// GetWorkingHours получает расписание ПВЗ по дням недели. Пустой список - расписание не задано
+func (q *WorkingHoursQueries) GetWorkingHours(ctx context.Context, pvzID string) ([]models.WorkingDay, error) {
+	ctx, span := tracing.Start(ctx, "WorkingHoursQueries.GetWorkingHours")
+	defer span.End()
+
+	query, args, err := q.sq.
+		Select("weekday", "to_char(opens_at, 'HH24:MI') AS opens_at", "to_char(closes_at, 'HH24:MI') AS closes_at").
+		From("pvz_working_hours").
+		Where(squirrel.Eq{"pvz_id": pvzID}).
+		OrderBy("weekday").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var days []models.WorkingDay
+	if err := q.db.SelectContext(ctx, &days, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get working hours: %w", err)
+	}
+
+	return days, nil
+}
+
+// SetWorkingHours заменяет расписание ПВЗ. Вызывается в транзакции,
+// чтобы проверка часов работы не увидела расписание без части дней
+func (q *WorkingHoursQueries) SetWorkingHours(ctx context.Context, pvzID string, days []models.WorkingDay) error {
+	ctx, span := tracing.Start(ctx, "WorkingHoursQueries.SetWorkingHours")
+	defer span.End()
+
+	query, args, err := q.sq.
+		Delete("pvz_working_hours").
+		Where(squirrel.Eq{"pvz_id": pvzID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+	if _, err := q.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to delete working hours: %w", err)
+	}
+
+	if len(days) == 0 {
+		return nil
+	}
+
+	insert := q.sq.
+		Insert("pvz_working_hours").
+		Columns("pvz_id", "weekday", "opens_at", "closes_at")
+	for _, day := range days {
+		insert = insert.Values(pvzID, day.Weekday, day.OpensAt, day.ClosesAt)
+	}
+
+	query, args, err = insert.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+	if _, err := q.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to insert working hours: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package queries
+
+import (
+	"context"
+	"testing"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupWorkingHoursQueriesTest(t *testing.T) (*WorkingHoursQueries, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	return &WorkingHoursQueries{
+		db: &db.Database{DB: sqlxDB},
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}, mock
+}
+
+func TestGetWorkingHours(t *testing.T) {
+	q, mock := setupWorkingHoursQueriesTest(t)
+
+	mock.ExpectQuery(`SELECT weekday, to_char\(opens_at, 'HH24:MI'\) AS opens_at, to_char\(closes_at, 'HH24:MI'\) AS closes_at FROM pvz_working_hours WHERE pvz_id = \$1 ORDER BY weekday`).
+		WithArgs("pvz-id").
+		WillReturnRows(sqlmock.NewRows([]string{"weekday", "opens_at", "closes_at"}).AddRow(1, "09:00", "21:00"))
+
+	days, err := q.GetWorkingHours(context.Background(), "pvz-id")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.WorkingDay{{Weekday: 1, OpensAt: "09:00", ClosesAt: "21:00"}}, days)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetWorkingHours(t *testing.T) {
+	q, mock := setupWorkingHoursQueriesTest(t)
+
+	t.Run("Замена расписания", func(t *testing.T) {
+		mock.ExpectExec(`DELETE FROM pvz_working_hours WHERE pvz_id = \$1`).
+			WithArgs("pvz-id").
+			WillReturnResult(sqlmock.NewResult(0, 7))
+		mock.ExpectExec(`INSERT INTO pvz_working_hours \(pvz_id,weekday,opens_at,closes_at\) VALUES \(\$1,\$2,\$3,\$4\),\(\$5,\$6,\$7,\$8\)`).
+			WithArgs("pvz-id", 1, "09:00", "21:00", "pvz-id", 2, "09:00", "18:00").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		err := q.SetWorkingHours(context.Background(), "pvz-id", []models.WorkingDay{
+			{Weekday: 1, OpensAt: "09:00", ClosesAt: "21:00"},
+			{Weekday: 2, OpensAt: "09:00", ClosesAt: "18:00"},
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Снятие расписания", func(t *testing.T) {
+		mock.ExpectExec(`DELETE FROM pvz_working_hours WHERE pvz_id = \$1`).
+			WithArgs("pvz-id").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		assert.NoError(t, q.SetWorkingHours(context.Background(), "pvz-id", nil))
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
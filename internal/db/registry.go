@@ -0,0 +1,84 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// Реестр именованных запросов: запросы с постоянным текстом собираются один раз при инициализации
+// пакета запросов и получают стабильное имя, например reception.get_last_open. Имя попадает в метрики
+// и спаны как db.query.id и не меняется при переименовании методов, а по реестру тесты проверяют
+// планы всех запросов через EXPLAIN. Запросы, текст которых зависит от фильтров, в реестр не входят
+
+// NamedQuery - зарегистрированный запрос
+type NamedQuery struct {
+	Name string
+	SQL  string
+}
+
+var (
+	registryMu sync.RWMutex
+	// registryByName - текст запроса по имени
+	registryByName = make(map[string]string)
+	// registryBySQL - имя запроса по тексту, для меток метрик
+	registryBySQL = make(map[string]string)
+)
+
+// unregisteredQuery - db.query.id запроса, собранного при вызове
+const unregisteredQuery = "unregistered"
+
+// RegisterSQL регистрирует запрос sql под именем name и возвращает его текст.
+// Вызывается при инициализации пакета: повторное имя или текст - ошибка в коде, поэтому паникует
+func RegisterSQL(name, sql string) string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registryByName[name]; ok {
+		panic(fmt.Sprintf("db: query %q is already registered", name))
+	}
+	if other, ok := registryBySQL[sql]; ok {
+		panic(fmt.Sprintf("db: query %q has the same text as %q", name, other))
+	}
+
+	registryByName[name] = sql
+	registryBySQL[sql] = name
+	return sql
+}
+
+// Register собирает запрос builder и регистрирует его под именем name, возвращает текст запроса.
+// Значения передаются при выполнении, поэтому в builder вместо них ставятся плейсхолдеры ?,
+// например Where("id = ?"). Ошибка сборки - ошибка в коде, поэтому паникует
+func Register(name string, builder squirrel.Sqlizer) string {
+	sql, _, err := builder.ToSql()
+	if err != nil {
+		panic(fmt.Sprintf("db: failed to build query %q: %v", name, err))
+	}
+	return RegisterSQL(name, sql)
+}
+
+// RegisteredQueries возвращает зарегистрированные запросы, отсортированные по имени
+func RegisteredQueries() []NamedQuery {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	queries := make([]NamedQuery, 0, len(registryByName))
+	for name, sql := range registryByName {
+		queries = append(queries, NamedQuery{Name: name, SQL: sql})
+	}
+	sort.Slice(queries, func(i, j int) bool { return queries[i].Name < queries[j].Name })
+	return queries
+}
+
+// queryID возвращает имя зарегистрированного запроса или unregisteredQuery
+func queryID(sql string) string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	if name, ok := registryBySQL[sql]; ok {
+		return name
+	}
+	return unregisteredQuery
+}
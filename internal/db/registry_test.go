@@ -0,0 +1,24 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegister(t *testing.T) {
+	sql := Register("test.get_reception", squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("id").
+		From("reception").
+		Where("pvz_id = ? AND status = ?"))
+
+	assert.Equal(t, "SELECT id FROM reception WHERE pvz_id = $1 AND status = $2", sql)
+	assert.Equal(t, "test.get_reception", queryID(sql))
+	assert.Equal(t, unregisteredQuery, queryID("SELECT 1"))
+	assert.Contains(t, RegisteredQueries(), NamedQuery{Name: "test.get_reception", SQL: sql})
+
+	// Имя и текст запроса в реестре не повторяются
+	assert.Panics(t, func() { RegisterSQL("test.get_reception", "SELECT 2") })
+	assert.Panics(t, func() { RegisterSQL("test.get_reception_copy", sql) })
+}
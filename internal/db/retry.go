@@ -0,0 +1,165 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"syscall"
+	"time"
+
+	"pvz-service/internal/metrics"
+	"pvz-service/internal/tracing"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// retryBudgetMax - сколько повторов подряд допускает бюджет после простоя
+const retryBudgetMax = 10
+
+// queryRetries - количество повторов запросов после временных ошибок. exhausted=true -
+// повтор не выполнен, потому что закончились попытки или бюджет повторов
+var queryRetries, _ = metrics.Meter().Int64Counter(
+	"db.client.query.retries",
+	metric.WithDescription("Повторы SQL-запросов после временных ошибок PostgreSQL"),
+)
+
+// RetryPolicy задает повтор запросов при временных ошибках PostgreSQL
+type RetryPolicy struct {
+	// MaxAttempts - сколько раз выполняется запрос вместе с первой попыткой, 1 отключает повторы
+	MaxAttempts int
+	// BaseDelay и MaxDelay ограничивают задержку перед повтором: она растет вдвое
+	// с каждой попыткой и выбирается случайно от нуля до этой величины
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Budget - доля повторов от успешных запросов. Бюджет не дает повторам
+	// умножить нагрузку на базу, пока она недоступна дольше нескольких попыток
+	Budget float64
+}
+
+// retrier повторяет запросы по RetryPolicy и ведет бюджет повторов
+type retrier struct {
+	policy RetryPolicy
+	sleep  func(ctx context.Context, delay time.Duration) error
+
+	mu     sync.Mutex
+	tokens float64
+}
+
+// newRetrier создает повторитель запросов, nil - если повторы отключены
+func newRetrier(policy RetryPolicy) *retrier {
+	if policy.MaxAttempts <= 1 {
+		return nil
+	}
+	return &retrier{policy: policy, sleep: sleepContext, tokens: retryBudgetMax}
+}
+
+// IsTransient сообщает, что запрос завершился временной ошибкой и его можно повторить:
+// конфликт сериализации или взаимоблокировка, разрыв соединения, перезапуск или переключение сервера
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "40001", "40P01", "57P01", "57P02", "57P03":
+			// serialization_failure, deadlock_detected, admin_shutdown, crash_shutdown, cannot_connect_now
+			return true
+		}
+		// Класс 08 - ошибки соединения
+		return pqErr.Code.Class() == "08"
+	}
+	return false
+}
+
+// Retry выполняет fn и повторяет ее при временных ошибках с растущей случайной задержкой.
+// Чтения через Read* повторяются автоматически, для записи повтор включается явно:
+// fn должна быть идемпотентной или целиком выполнять транзакцию через InTx.
+// Внутри открытой транзакции fn выполняется один раз: повторить часть транзакции нельзя
+func (d *Database) Retry(ctx context.Context, fn func(ctx context.Context) error) error {
+	r := d.retrier
+	if _, inTx := txFromContext(ctx); r == nil || inTx {
+		return fn(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			r.deposit()
+			return nil
+		}
+		if !IsTransient(err) || ctx.Err() != nil {
+			return err
+		}
+
+		name := tracing.Operation(ctx)
+		if name == "" {
+			name = unknownQuery
+		}
+		if attempt >= r.policy.MaxAttempts || !r.withdraw() {
+			queryRetries.Add(ctx, 1, metric.WithAttributes(attribute.String("db.query.name", name), attribute.Bool("exhausted", true)))
+			return err
+		}
+		queryRetries.Add(ctx, 1, metric.WithAttributes(attribute.String("db.query.name", name), attribute.Bool("exhausted", false)))
+		log.Printf("Transient database error in %s, retrying (attempt %d): %v", name, attempt+1, err)
+
+		if sleepErr := r.sleep(ctx, r.delay(attempt)); sleepErr != nil {
+			return err
+		}
+	}
+}
+
+// delay возвращает случайную задержку перед попыткой attempt+1: от нуля до BaseDelay*2^(attempt-1), не больше MaxDelay
+func (r *retrier) delay(attempt int) time.Duration {
+	ceiling := r.policy.BaseDelay
+	for i := 1; i < attempt && ceiling < r.policy.MaxDelay; i++ {
+		ceiling *= 2
+	}
+	ceiling = min(ceiling, r.policy.MaxDelay)
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling + 1)
+}
+
+// deposit пополняет бюджет повторов после успешного запроса
+func (r *retrier) deposit() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens = min(r.tokens+r.policy.Budget, retryBudgetMax)
+}
+
+// withdraw списывает повтор из бюджета, false - бюджет исчерпан
+func (r *retrier) withdraw() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+// sleepContext ждет delay или отмены контекста
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
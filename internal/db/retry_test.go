@@ -0,0 +1,141 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupRetryTest создает базу без реплики с повтором запросов без задержек
+func setupRetryTest(t *testing.T) (*Database, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	t.Cleanup(func() { mockDB.Close() })
+
+	r := newRetrier(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond, Budget: 0.1})
+	r.sleep = func(ctx context.Context, delay time.Duration) error { return nil }
+
+	return &Database{DB: sqlx.NewDb(mockDB, "sqlmock"), retrier: r}, mock
+}
+
+func TestIsTransient(t *testing.T) {
+	assert.True(t, IsTransient(&pq.Error{Code: "40001"}))
+	assert.True(t, IsTransient(fmt.Errorf("failed to get pvz: %w", &pq.Error{Code: "57P01"})))
+	assert.True(t, IsTransient(&pq.Error{Code: "08006"}))
+	assert.True(t, IsTransient(fmt.Errorf("read: %w", syscall.ECONNRESET)))
+	assert.False(t, IsTransient(&pq.Error{Code: "23505"}))
+	assert.False(t, IsTransient(errors.New("syntax error")))
+	assert.False(t, IsTransient(nil))
+}
+
+func TestDatabase_RetryReads(t *testing.T) {
+	t.Run("Чтение повторяется после конфликта сериализации", func(t *testing.T) {
+		database, mock := setupRetryTest(t)
+
+		mock.ExpectQuery(`SELECT id FROM pvz`).WillReturnError(&pq.Error{Code: "40001"})
+		mock.ExpectQuery(`SELECT id FROM pvz`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+
+		var ids []string
+		err := database.ReadSelectContext(context.Background(), &ids, "SELECT id FROM pvz")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1"}, ids)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Постоянная ошибка не повторяется", func(t *testing.T) {
+		database, mock := setupRetryTest(t)
+
+		mock.ExpectQuery(`SELECT id FROM pvz`).WillReturnError(&pq.Error{Code: "42P01"})
+
+		var ids []string
+		err := database.ReadSelectContext(context.Background(), &ids, "SELECT id FROM pvz")
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Не больше MaxAttempts попыток", func(t *testing.T) {
+		database, mock := setupRetryTest(t)
+
+		for range 3 {
+			mock.ExpectQuery(`SELECT id FROM pvz`).WillReturnError(syscall.ECONNRESET)
+		}
+
+		var id string
+		err := database.ReadGetContext(context.Background(), &id, "SELECT id FROM pvz")
+
+		assert.ErrorIs(t, err, syscall.ECONNRESET)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDatabase_RetryBudget(t *testing.T) {
+	database, mock := setupRetryTest(t)
+	database.retrier.tokens = 1
+
+	// Единственный повтор из бюджета расходуется на первый запрос
+	mock.ExpectQuery(`SELECT id FROM pvz`).WillReturnError(&pq.Error{Code: "57P01"})
+	mock.ExpectQuery(`SELECT id FROM pvz`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+	// После него бюджет пополнен на 0.1 и второй запрос не повторяется
+	mock.ExpectQuery(`SELECT id FROM pvz`).WillReturnError(&pq.Error{Code: "57P01"})
+
+	var id string
+	assert.NoError(t, database.ReadGetContext(context.Background(), &id, "SELECT id FROM pvz"))
+	assert.Error(t, database.ReadGetContext(context.Background(), &id, "SELECT id FROM pvz"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDatabase_RetryWrites(t *testing.T) {
+	t.Run("Запись повторяется только явно", func(t *testing.T) {
+		database, mock := setupRetryTest(t)
+
+		mock.ExpectExec(`UPDATE pvz`).WillReturnError(&pq.Error{Code: "40P01"})
+		mock.ExpectExec(`UPDATE pvz`).WillReturnError(&pq.Error{Code: "40P01"})
+		mock.ExpectExec(`UPDATE pvz`).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err := database.ExecContext(context.Background(), "UPDATE pvz SET city = 'Москва'")
+		assert.Error(t, err)
+
+		err = database.Retry(context.Background(), func(ctx context.Context) error {
+			_, err := database.ExecContext(ctx, "UPDATE pvz SET city = 'Москва'")
+			return err
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Внутри транзакции запрос не повторяется", func(t *testing.T) {
+		database, mock := setupRetryTest(t)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT id FROM pvz`).WillReturnError(&pq.Error{Code: "40001"})
+		mock.ExpectRollback()
+
+		err := database.InTx(context.Background(), func(ctx context.Context) error {
+			var ids []string
+			return database.ReadSelectContext(ctx, &ids, "SELECT id FROM pvz")
+		})
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRetrierDelay(t *testing.T) {
+	r := newRetrier(RetryPolicy{MaxAttempts: 5, BaseDelay: 50 * time.Millisecond, MaxDelay: 120 * time.Millisecond})
+
+	for range 100 {
+		assert.LessOrEqual(t, r.delay(1), 50*time.Millisecond)
+		assert.LessOrEqual(t, r.delay(2), 100*time.Millisecond)
+		assert.LessOrEqual(t, r.delay(4), 120*time.Millisecond)
+	}
+	assert.Nil(t, newRetrier(RetryPolicy{MaxAttempts: 1}))
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"pvz-service/internal/metrics"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ShadowMode - режим переходного периода изменения схемы таблицы. Старая схема остается
+// источником истины, новая заполняется параллельно, пока сверка чтений не покажет,
+// что на нее можно переключиться
+type ShadowMode string
+
+const (
+	// ShadowOff - запись и чтение только в старой схеме
+	ShadowOff ShadowMode = "off"
+	// ShadowDualWrite - запись в обе схемы, чтение из старой
+	ShadowDualWrite ShadowMode = "dual_write"
+	// ShadowRead - запись в обе схемы, чтение из старой со сверкой с новой
+	ShadowRead ShadowMode = "shadow_read"
+)
+
+// Результаты сверки теневого чтения
+const (
+	shadowResultMatch    = "match"
+	shadowResultDiverged = "diverged"
+	shadowResultError    = "error"
+)
+
+// shadowSavepoint - точка сохранения, к которой откатывается неудачная теневая запись в транзакции
+const shadowSavepoint = "shadow_write"
+
+// shadowReads - счетчик теневых чтений с разбивкой по миграции, запросу и результату сверки
+var shadowReads, _ = metrics.Meter().Int64Counter(
+	"db.shadow.reads",
+	metric.WithDescription("Теневые чтения из новой схемы и результат их сверки со старой"),
+)
+
+// shadowWriteErrors - счетчик неудачных записей в новую схему
+var shadowWriteErrors, _ = metrics.Meter().Int64Counter(
+	"db.shadow.write_errors",
+	metric.WithDescription("Неудачные записи в новую схему при двойной записи"),
+)
+
+// ParseShadowModes разбирает режимы миграций из списка вида product_status=shadow_read.
+// Миграция без режима в списке работает в режиме ShadowOff
+func ParseShadowModes(values []string) (map[string]ShadowMode, error) {
+	modes := make(map[string]ShadowMode, len(values))
+	for _, value := range values {
+		name, mode, ok := strings.Cut(value, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid shadow migration %q, expected name=mode", value)
+		}
+
+		switch m := ShadowMode(strings.TrimSpace(mode)); m {
+		case ShadowOff, ShadowDualWrite, ShadowRead:
+			modes[name] = m
+		default:
+			return nil, fmt.Errorf("unknown shadow mode %q for migration %s", mode, name)
+		}
+	}
+	return modes, nil
+}
+
+// Shadow управляет переходным периодом одной миграции схемы. Нулевой указатель
+// соответствует режиму ShadowOff, поэтому запросы без настроенной миграции не меняются
+type Shadow struct {
+	name string
+	mode ShadowMode
+}
+
+// Shadow возвращает переходный период миграции name в режиме из настроек
+func (d *Database) Shadow(name string) *Shadow {
+	mode, ok := d.shadowModes[name]
+	if !ok {
+		mode = ShadowOff
+	}
+	return &Shadow{name: name, mode: mode}
+}
+
+// Writes сообщает, нужно ли записывать изменения в новую схему
+func (s *Shadow) Writes() bool {
+	return s != nil && (s.mode == ShadowDualWrite || s.mode == ShadowRead)
+}
+
+// Reads сообщает, нужно ли сверить чтение с новой схемой. Внутри транзакции сверка
+// не выполняется: ошибка теневого запроса прервала бы транзакцию
+func (s *Shadow) Reads(ctx context.Context) bool {
+	if s == nil || s.mode != ShadowRead {
+		return false
+	}
+	_, inTx := txFromContext(ctx)
+	return !inTx
+}
+
+// ShadowExec выполняет запись в новую схему, если она включена для миграции s.
+// Ошибка записи не возвращается: старая схема остается источником истины,
+// а расхождение попадет в лог, метрики и сверку чтений. Внутри транзакции запись
+// выполняется в точке сохранения, чтобы ее ошибка не прерывала транзакцию
+func (d *Database) ShadowExec(ctx context.Context, s *Shadow, query string, args ...interface{}) {
+	if !s.Writes() {
+		return
+	}
+
+	tx, inTx := txFromContext(ctx)
+	if inTx {
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT "+shadowSavepoint); err != nil {
+			s.writeFailed(ctx, err)
+			return
+		}
+	}
+
+	_, err := d.ExecContext(ctx, query, args...)
+
+	if inTx {
+		release := "RELEASE SAVEPOINT " + shadowSavepoint
+		if err != nil {
+			release = "ROLLBACK TO SAVEPOINT " + shadowSavepoint
+		}
+		if _, releaseErr := tx.ExecContext(ctx, release); releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+	}
+
+	if err != nil {
+		s.writeFailed(ctx, err)
+	}
+}
+
+// writeFailed записывает неудачную теневую запись в лог и метрики
+func (s *Shadow) writeFailed(ctx context.Context, err error) {
+	log.Printf("Shadow write for migration %s failed: %v", s.name, err)
+	shadowWriteErrors.Add(ctx, 1, metric.WithAttributes(attribute.String("db.shadow.migration", s.name)))
+}
+
+// Compare записывает в метрики результат сверки теневого чтения запроса query:
+// err - ошибка чтения из новой схемы, diverged - количество расходящихся записей
+func (s *Shadow) Compare(ctx context.Context, query string, diverged int, err error) {
+	result := shadowResultMatch
+	switch {
+	case err != nil:
+		result = shadowResultError
+		log.Printf("Shadow read %s for migration %s failed: %v", query, s.name, err)
+	case diverged > 0:
+		result = shadowResultDiverged
+		log.Printf("Shadow read %s for migration %s diverged in %d rows", query, s.name, diverged)
+	}
+
+	shadowReads.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("db.shadow.migration", s.name),
+		attribute.String("db.query.name", query),
+		attribute.String("db.shadow.result", result),
+	))
+}
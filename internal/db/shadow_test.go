@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseShadowModes(t *testing.T) {
+	modes, err := ParseShadowModes([]string{"product_status=shadow_read", " reception_tags = dual_write "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]ShadowMode{
+		"product_status": ShadowRead,
+		"reception_tags": ShadowDualWrite,
+	}, modes)
+
+	_, err = ParseShadowModes([]string{"product_status"})
+	assert.Error(t, err)
+
+	_, err = ParseShadowModes([]string{"product_status=read_new"})
+	assert.Error(t, err)
+}
+
+func TestShadow_Modes(t *testing.T) {
+	database := &Database{shadowModes: map[string]ShadowMode{
+		"dual":   ShadowDualWrite,
+		"shadow": ShadowRead,
+	}}
+
+	var missing *Shadow
+	assert.False(t, missing.Writes())
+	assert.False(t, missing.Reads(context.Background()))
+
+	assert.False(t, database.Shadow("unknown").Writes())
+	assert.True(t, database.Shadow("dual").Writes())
+	assert.False(t, database.Shadow("dual").Reads(context.Background()))
+	assert.True(t, database.Shadow("shadow").Writes())
+	assert.True(t, database.Shadow("shadow").Reads(context.Background()))
+}
+
+func TestDatabase_ShadowExec(t *testing.T) {
+	t.Run("Запись выключена", func(t *testing.T) {
+		database, primaryMock, _ := setupDatabaseTest(t)
+
+		database.ShadowExec(context.Background(), database.Shadow("product_status"), "INSERT INTO product_status DEFAULT VALUES")
+
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+	})
+
+	t.Run("Ошибка записи не прерывает транзакцию", func(t *testing.T) {
+		database, primaryMock, _ := setupDatabaseTest(t)
+		database.shadowModes = map[string]ShadowMode{"product_status": ShadowDualWrite}
+
+		primaryMock.ExpectBegin()
+		primaryMock.ExpectExec(`UPDATE product SET status`).WillReturnResult(sqlmock.NewResult(0, 1))
+		primaryMock.ExpectExec(`SAVEPOINT shadow_write`).WillReturnResult(sqlmock.NewResult(0, 0))
+		primaryMock.ExpectExec(`INSERT INTO product_status`).WillReturnError(errors.New("relation does not exist"))
+		primaryMock.ExpectExec(`ROLLBACK TO SAVEPOINT shadow_write`).WillReturnResult(sqlmock.NewResult(0, 0))
+		primaryMock.ExpectCommit()
+
+		err := database.InTx(context.Background(), func(ctx context.Context) error {
+			if _, err := database.ExecContext(ctx, "UPDATE product SET status = 'stored'"); err != nil {
+				return err
+			}
+			database.ShadowExec(ctx, database.Shadow("product_status"), "INSERT INTO product_status DEFAULT VALUES")
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+	})
+
+	t.Run("Сверка чтения не выполняется в транзакции", func(t *testing.T) {
+		database, primaryMock, _ := setupDatabaseTest(t)
+		database.shadowModes = map[string]ShadowMode{"product_status": ShadowRead}
+
+		primaryMock.ExpectBegin()
+		primaryMock.ExpectCommit()
+
+		err := database.InTx(context.Background(), func(ctx context.Context) error {
+			assert.False(t, database.Shadow("product_status").Reads(ctx))
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+	})
+}
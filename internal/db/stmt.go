@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Подготовленные запросы для горячих путей: текст запроса разбирается и планируется
+// сервером один раз на соединение, а не при каждом вызове. Запрос подготавливается
+// при первом использовании и кешируется по тексту, поэтому передавать сюда можно
+// только запросы с постоянным текстом - значения передаются аргументами.
+// При выключенной подготовке (например, за PgBouncer в режиме transaction)
+// запросы выполняются как обычные
+
+// PreparedGetContext выполняет подготовленный запрос и сканирует одну строку в dest
+func (d *Database) PreparedGetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	if !d.prepareStatements {
+		return d.GetContext(ctx, dest, query, args...)
+	}
+
+	stmt, err := d.preparedStmt(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	ctx, finish := d.startQuery(ctx, "get", query, args, false)
+	err = stmt.GetContext(ctx, dest, args...)
+	finish(err)
+	return err
+}
+
+// PreparedExecContext выполняет подготовленный запрос без возврата строк
+func (d *Database) PreparedExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if !d.prepareStatements {
+		return d.ExecContext(ctx, query, args...)
+	}
+
+	stmt, err := d.preparedStmt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, finish := d.startQuery(ctx, "exec", query, args, false)
+	result, err := stmt.ExecContext(ctx, args...)
+	finish(err)
+	return result, err
+}
+
+// preparedStmt возвращает подготовленный запрос из кеша, подготавливая его при первом вызове.
+// Внутри InTx запрос привязывается к открытой транзакции
+func (d *Database) preparedStmt(ctx context.Context, query string) (*sqlx.Stmt, error) {
+	d.stmtsMu.Lock()
+	stmt, ok := d.stmts[query]
+	d.stmtsMu.Unlock()
+
+	if !ok {
+		prepared, err := d.DB.PreparexContext(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare statement: %w", err)
+		}
+
+		d.stmtsMu.Lock()
+		// Запрос мог быть подготовлен параллельно: оставляем первый, лишний закрываем
+		if stmt, ok = d.stmts[query]; ok {
+			prepared.Close()
+		} else {
+			if d.stmts == nil {
+				d.stmts = make(map[string]*sqlx.Stmt)
+			}
+			d.stmts[query] = prepared
+			stmt = prepared
+		}
+		d.stmtsMu.Unlock()
+	}
+
+	if tx, ok := txFromContext(ctx); ok {
+		return tx.StmtxContext(ctx, stmt), nil
+	}
+	return stmt, nil
+}
+
+// closeStmts закрывает подготовленные запросы
+func (d *Database) closeStmts() {
+	d.stmtsMu.Lock()
+	defer d.stmtsMu.Unlock()
+
+	for query, stmt := range d.stmts {
+		if err := stmt.Close(); err != nil {
+			log.Printf("Failed to close prepared statement: %v", err)
+		}
+		delete(d.stmts, query)
+	}
+}
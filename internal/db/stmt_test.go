@@ -0,0 +1,122 @@
+package db
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+const testStmtSQL = "SELECT 1 FROM reception WHERE pvz_id = $1 AND status = $2 LIMIT 1"
+
+func TestDatabase_PreparedGetContext(t *testing.T) {
+	t.Run("Запрос подготавливается один раз", func(t *testing.T) {
+		database, primaryMock, _ := setupDatabaseTest(t)
+		database.prepareStatements = true
+
+		prepared := primaryMock.ExpectPrepare(`SELECT 1 FROM reception`)
+		prepared.ExpectQuery().WithArgs("pvz-1", "in_progress").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(1))
+		prepared.ExpectQuery().WithArgs("pvz-2", "in_progress").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(1))
+		prepared.WillBeClosed()
+
+		var exists int
+		assert.NoError(t, database.PreparedGetContext(context.Background(), &exists, testStmtSQL, "pvz-1", "in_progress"))
+		assert.NoError(t, database.PreparedGetContext(context.Background(), &exists, testStmtSQL, "pvz-2", "in_progress"))
+		assert.Len(t, database.stmts, 1)
+
+		database.closeStmts()
+		assert.Empty(t, database.stmts)
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+	})
+
+	t.Run("Без подготовки запрос выполняется как обычный", func(t *testing.T) {
+		database, primaryMock, _ := setupDatabaseTest(t)
+
+		primaryMock.ExpectQuery(`SELECT 1 FROM reception`).WithArgs("pvz-1", "in_progress").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(1))
+
+		var exists int
+		assert.NoError(t, database.PreparedGetContext(context.Background(), &exists, testStmtSQL, "pvz-1", "in_progress"))
+		assert.Empty(t, database.stmts)
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+	})
+
+	t.Run("Ошибка подготовки возвращается", func(t *testing.T) {
+		database, primaryMock, _ := setupDatabaseTest(t)
+		database.prepareStatements = true
+
+		primaryMock.ExpectPrepare(`SELECT 1 FROM reception`).WillReturnError(assert.AnError)
+
+		var exists int
+		err := database.PreparedGetContext(context.Background(), &exists, testStmtSQL, "pvz-1", "in_progress")
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, database.stmts)
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+	})
+
+	t.Run("Внутри InTx запрос выполняется в транзакции", func(t *testing.T) {
+		database, primaryMock, _ := setupDatabaseTest(t)
+		database.prepareStatements = true
+
+		primaryMock.ExpectBegin()
+		primaryMock.ExpectPrepare(`SELECT 1 FROM reception`)
+		// database/sql заново подготавливает запрос на соединении транзакции
+		primaryMock.ExpectPrepare(`SELECT 1 FROM reception`).
+			ExpectQuery().WithArgs("pvz-1", "in_progress").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(1))
+		primaryMock.ExpectCommit()
+
+		err := database.InTx(context.Background(), func(ctx context.Context) error {
+			var exists int
+			return database.PreparedGetContext(ctx, &exists, testStmtSQL, "pvz-1", "in_progress")
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+	})
+}
+
+// benchDatabase подключается к PostgreSQL из BENCH_DATABASE_URL, без него бенчмарк пропускается
+func benchDatabase(b *testing.B, prepare bool) *Database {
+	dsn := os.Getenv("BENCH_DATABASE_URL")
+	if dsn == "" {
+		b.Skip("BENCH_DATABASE_URL не задан")
+	}
+
+	conn, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		b.Fatalf("failed to connect: %v", err)
+	}
+	database := &Database{DB: conn, prepareStatements: prepare}
+	b.Cleanup(func() { database.Close() })
+	return database
+}
+
+func benchmarkCheckOpenReception(b *testing.B, prepare bool) {
+	database := benchDatabase(b, prepare)
+	ctx := context.Background()
+	query := "SELECT count(*) FROM reception WHERE pvz_id = $1 AND status = $2"
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var count int
+		if err := database.PreparedGetContext(ctx, &count, query, "00000000-0000-0000-0000-000000000000", "in_progress"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCheckOpenReception_Prepared(b *testing.B) {
+	benchmarkCheckOpenReception(b, true)
+}
+
+func BenchmarkCheckOpenReception_Plain(b *testing.B) {
+	benchmarkCheckOpenReception(b, false)
+}
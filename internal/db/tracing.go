@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"pvz-service/internal/tracing"
+
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Методы ниже перекрывают методы *sqlx.DB, чтобы каждый SQL-запрос
+// попадал в трассу отдельным спаном с текстом запроса и в метрики длительности,
+// а внутри InTx выполнялся в открытой транзакции
+
+// conn - общие методы пула соединений и транзакции
+type conn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row
+	QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error)
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+// conn возвращает транзакцию из контекста или основной пул
+func (d *Database) conn(ctx context.Context) conn {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx
+	}
+	return d.DB
+}
+
+// startSpan открывает спан SQL-запроса
+func startSpan(ctx context.Context, operation, query, id string, replica bool) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "db."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.statement", query),
+			attribute.String("db.query.id", id),
+			attribute.Bool("db.replica", replica),
+		),
+	)
+}
+
+// ExecContext выполняет запрос без возврата строк
+func (d *Database) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, finish := d.startQuery(ctx, "exec", query, args, false)
+	result, err := d.conn(ctx).ExecContext(ctx, query, args...)
+	finish(err)
+	return result, err
+}
+
+// QueryRowContext выполняет запрос, возвращающий одну строку
+func (d *Database) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, finish := d.startQuery(ctx, "query_row", query, args, false)
+	row := d.conn(ctx).QueryRowContext(ctx, query, args...)
+	finish(row.Err())
+	return row
+}
+
+// QueryRowxContext выполняет запрос, возвращающий одну строку для сканирования в структуру
+func (d *Database) QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	ctx, finish := d.startQuery(ctx, "query_row", query, args, false)
+	row := d.conn(ctx).QueryRowxContext(ctx, query, args...)
+	finish(row.Err())
+	return row
+}
+
+// QueryxContext выполняет запрос и возвращает курсор по строкам для чтения без загрузки
+// всего результата в память. Спан и длительность охватывают выполнение запроса до первой строки,
+// курсор нужно закрыть после чтения
+func (d *Database) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	ctx, finish := d.startQuery(ctx, "query", query, args, false)
+	rows, err := d.conn(ctx).QueryxContext(ctx, query, args...)
+	finish(err)
+	return rows, err
+}
+
+// SelectContext выполняет запрос и сканирует строки в срез dest
+func (d *Database) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx, finish := d.startQuery(ctx, "select", query, args, false)
+	err := d.conn(ctx).SelectContext(ctx, dest, query, args...)
+	finish(err)
+	return err
+}
+
+// GetContext выполняет запрос и сканирует одну строку в dest
+func (d *Database) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx, finish := d.startQuery(ctx, "get", query, args, false)
+	err := d.conn(ctx).GetContext(ctx, dest, query, args...)
+	finish(err)
+	return err
+}
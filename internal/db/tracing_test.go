@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestDatabase_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	database, primaryMock, replicaMock := setupDatabaseTest(t)
+
+	primaryMock.ExpectExec(`UPDATE pvz SET is_active = false`).
+		WillReturnError(errors.New("database error"))
+	replicaMock.ExpectQuery(`SELECT id FROM pvz`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+
+	_, err := database.ExecContext(context.Background(), "UPDATE pvz SET is_active = false")
+	assert.Error(t, err)
+
+	var ids []string
+	assert.NoError(t, database.ReadSelectContext(context.Background(), &ids, "SELECT id FROM pvz"))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+
+	assert.Equal(t, "db.exec", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Contains(t, spans[0].Attributes(), attribute.String("db.statement", "UPDATE pvz SET is_active = false"))
+
+	assert.Equal(t, "db.select", spans[1].Name())
+	assert.Contains(t, spans[1].Attributes(), attribute.Bool("db.replica", true))
+}
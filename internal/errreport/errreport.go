@@ -0,0 +1,85 @@
+// Package errreport отправляет паники обработчиков в Sentry со стеком вызовов,
+// идентификатором запроса и пользователем, чтобы они не терялись в журнале
+package errreport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"pvz-service/internal/config"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// Panic - паника, перехваченная при обработке запроса
+type Panic struct {
+	Value     interface{}
+	RequestID string
+	UserID    string
+	UserRole  string
+	Request   *http.Request
+}
+
+// Setup настраивает глобальный клиент Sentry. При пустом DSN паники не отправляются.
+// Возвращает функцию, дожидающуюся отправки накопленных событий при завершении работы
+func Setup(cfg *config.SentryConfig, environment string) (func(context.Context) error, error) {
+	if cfg.DSN == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	err := sentry.Init(sentry.ClientOptions{
+		Dsn:         cfg.DSN,
+		Environment: environment,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize sentry: %w", err)
+	}
+
+	return func(ctx context.Context) error {
+		timeout := 5 * time.Second
+		if deadline, ok := ctx.Deadline(); ok {
+			timeout = time.Until(deadline)
+		}
+		if !sentry.Flush(timeout) {
+			return fmt.Errorf("failed to flush sentry events")
+		}
+		return nil
+	}, nil
+}
+
+// ReportPanic отправляет панику в Sentry. Вызывается из отложенной функции с recover,
+// пока стек паникующей горутины еще доступен
+func ReportPanic(p Panic) {
+	reportPanic(sentry.CurrentHub(), p)
+}
+
+func reportPanic(hub *sentry.Hub, p Panic) {
+	if hub.Client() == nil {
+		return
+	}
+
+	message := fmt.Sprint(p.Value)
+	event := sentry.NewEvent()
+	event.Level = sentry.LevelFatal
+	event.Message = message
+	event.Exception = []sentry.Exception{{
+		Type:       "panic",
+		Value:      message,
+		Stacktrace: sentry.NewStacktrace(),
+	}}
+
+	// Отдельная область видимости, чтобы теги запроса не попали в другие события
+	hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetTag("request_id", p.RequestID)
+		if p.UserID != "" {
+			scope.SetUser(sentry.User{ID: p.UserID})
+			scope.SetTag("user_role", p.UserRole)
+		}
+		if p.Request != nil {
+			scope.SetRequest(p.Request)
+		}
+		hub.CaptureEvent(event)
+	})
+}
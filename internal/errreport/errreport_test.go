@@ -0,0 +1,95 @@
+package errreport
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pvz-service/internal/config"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTransport запоминает события вместо отправки в Sentry
+type recordingTransport struct {
+	events []*sentry.Event
+}
+
+func (t *recordingTransport) Flush(time.Duration) bool              { return true }
+func (t *recordingTransport) FlushWithContext(context.Context) bool { return true }
+func (t *recordingTransport) Configure(sentry.ClientOptions)        {}
+func (t *recordingTransport) SendEvent(event *sentry.Event)         { t.events = append(t.events, event) }
+func (t *recordingTransport) Close()                                {}
+
+func newTestHub(t *testing.T) (*sentry.Hub, *recordingTransport) {
+	transport := &recordingTransport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Transport: transport})
+	require.NoError(t, err)
+	return sentry.NewHub(client, sentry.NewScope()), transport
+}
+
+func panicAndReport(hub *sentry.Hub, p Panic) {
+	defer func() {
+		p.Value = recover()
+		reportPanic(hub, p)
+	}()
+	panic("nil map write")
+}
+
+func TestReportPanic(t *testing.T) {
+	hub, transport := newTestHub(t)
+
+	panicAndReport(hub, Panic{
+		RequestID: "req-1",
+		UserID:    "user-1",
+		UserRole:  "employee",
+		Request:   httptest.NewRequest("POST", "/products", nil),
+	})
+
+	require.Len(t, transport.events, 1)
+	event := transport.events[0]
+	assert.Equal(t, sentry.LevelFatal, event.Level)
+	assert.Equal(t, "nil map write", event.Message)
+	assert.Equal(t, "req-1", event.Tags["request_id"])
+	assert.Equal(t, "employee", event.Tags["user_role"])
+	assert.Equal(t, "user-1", event.User.ID)
+	assert.True(t, strings.HasSuffix(event.Request.URL, "/products"))
+
+	// Стек указывает на место паники
+	require.Len(t, event.Exception, 1)
+	frames := event.Exception[0].Stacktrace.Frames
+	require.NotEmpty(t, frames)
+	found := false
+	for _, frame := range frames {
+		if frame.Function == "panicAndReport" {
+			found = true
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestReportPanic_ScopeIsolated(t *testing.T) {
+	hub, transport := newTestHub(t)
+
+	panicAndReport(hub, Panic{RequestID: "req-1", UserID: "user-1"})
+	hub.CaptureMessage("после паники")
+
+	require.Len(t, transport.events, 2)
+	assert.Empty(t, transport.events[1].Tags["request_id"])
+}
+
+func TestReportPanic_Disabled(t *testing.T) {
+	// Без клиента событие не отправляется и паники нет
+	reportPanic(sentry.NewHub(nil, sentry.NewScope()), Panic{Value: "boom"})
+}
+
+func TestSetup_EmptyDSN(t *testing.T) {
+	flush, err := Setup(&config.SentryConfig{}, config.EnvDevelopment)
+
+	require.NoError(t, err)
+	assert.NoError(t, flush(context.Background()))
+}
@@ -0,0 +1,148 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/models"
+)
+
+// MaxPollEvents ограничивает количество событий в одном ответе
+const MaxPollEvents = 100
+
+// Publisher публикует события ленты ПВЗ
+type Publisher interface {
+	Publish(pvzID, eventType string, payload interface{}) models.Event
+}
+
+// DedupPublisher публикует события, доставляемые повторно, не более одного раза
+type DedupPublisher interface {
+	PublishOnce(dedupID, pvzID, eventType string, payload interface{}) models.Event
+}
+
+// Poller выдает события ленты ПВЗ после курсора
+type Poller interface {
+	Poll(ctx context.Context, pvzID string, cursor int64, wait time.Duration) ([]models.Event, int64)
+}
+
+// Hub хранит последние события в памяти и будит ожидающих подписчиков.
+// Курсор - идентификатор последнего полученного события, общий для всех ПВЗ
+type Hub struct {
+	mu       sync.Mutex
+	capacity int
+	events   []models.Event
+	lastID   int64
+	changed  chan struct{}
+	clock    clock.Clock
+}
+
+// NewHub создает новый экземпляр Hub, хранящий не более capacity событий
+func NewHub(capacity int) *Hub {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &Hub{
+		capacity: capacity,
+		changed:  make(chan struct{}),
+		clock:    clock.System{},
+	}
+}
+
+// Publish сохраняет событие и оповещает ожидающих подписчиков
+func (h *Hub) Publish(pvzID, eventType string, payload interface{}) models.Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.publish("", pvzID, eventType, payload)
+}
+
+// PublishOnce публикует событие, если событие с тем же dedupID еще в буфере не встречалось,
+// иначе возвращает ранее опубликованное
+func (h *Hub) PublishOnce(dedupID, pvzID, eventType string, payload interface{}) models.Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, event := range h.events {
+		if dedupID != "" && event.DedupID == dedupID {
+			return event
+		}
+	}
+
+	return h.publish(dedupID, pvzID, eventType, payload)
+}
+
+// publish добавляет событие в буфер, вызывается под блокировкой
+func (h *Hub) publish(dedupID, pvzID, eventType string, payload interface{}) models.Event {
+	h.lastID++
+	event := models.Event{
+		ID:        h.lastID,
+		DedupID:   dedupID,
+		PvzID:     pvzID,
+		Type:      eventType,
+		Payload:   payload,
+		CreatedAt: h.clock.Now(),
+	}
+
+	h.events = append(h.events, event)
+	if len(h.events) > h.capacity {
+		h.events = append([]models.Event(nil), h.events[len(h.events)-h.capacity:]...)
+	}
+
+	close(h.changed)
+	h.changed = make(chan struct{})
+
+	return event
+}
+
+// Poll возвращает события ПВЗ после курсора, ожидая их появления не дольше wait
+func (h *Hub) Poll(ctx context.Context, pvzID string, cursor int64, wait time.Duration) ([]models.Event, int64) {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	for {
+		h.mu.Lock()
+		events, next := h.since(pvzID, cursor)
+		changed := h.changed
+		h.mu.Unlock()
+
+		if len(events) > 0 {
+			return events, next
+		}
+		// События других ПВЗ сдвигают курсор, чтобы не просматривать их повторно
+		cursor = next
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			return nil, cursor
+		case <-ctx.Done():
+			return nil, cursor
+		}
+	}
+}
+
+// since возвращает события ПВЗ после курсора и новый курсор, вызывается под блокировкой
+func (h *Hub) since(pvzID string, cursor int64) ([]models.Event, int64) {
+	// Курсор из будущего означает, что сервис перезапускался: отдаем события с начала
+	if cursor > h.lastID {
+		cursor = 0
+	}
+
+	var events []models.Event
+	for _, event := range h.events {
+		if event.ID <= cursor || event.PvzID != pvzID {
+			continue
+		}
+		events = append(events, event)
+		if len(events) == MaxPollEvents {
+			return events, event.ID
+		}
+	}
+
+	if h.lastID > cursor {
+		cursor = h.lastID
+	}
+	return events, cursor
+}
@@ -0,0 +1,97 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/models"
+)
+
+func TestHub_PollReturnsEventsAfterCursor(t *testing.T) {
+	hub := NewHub(10)
+	first := hub.Publish("pvz1", models.EventReceptionCreated, nil)
+	hub.Publish("pvz2", models.EventReceptionCreated, nil)
+	third := hub.Publish("pvz1", models.EventProductAdded, nil)
+
+	events, cursor := hub.Poll(context.Background(), "pvz1", 0, time.Second)
+	assert.Len(t, events, 2)
+	assert.Equal(t, third.ID, cursor)
+
+	events, cursor = hub.Poll(context.Background(), "pvz1", first.ID, time.Second)
+	assert.Len(t, events, 1)
+	assert.Equal(t, models.EventProductAdded, events[0].Type)
+	assert.Equal(t, third.ID, cursor)
+}
+
+func TestHub_PollWaitsForEvent(t *testing.T) {
+	hub := NewHub(10)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		hub.Publish("pvz2", models.EventProductAdded, nil)
+		hub.Publish("pvz1", models.EventProductAdded, nil)
+	}()
+
+	events, cursor := hub.Poll(context.Background(), "pvz1", 0, time.Second)
+	assert.Len(t, events, 1)
+	assert.Equal(t, int64(2), cursor)
+}
+
+func TestHub_PollTimeout(t *testing.T) {
+	hub := NewHub(10)
+	hub.Publish("pvz2", models.EventProductAdded, nil)
+
+	start := time.Now()
+	events, cursor := hub.Poll(context.Background(), "pvz1", 0, 30*time.Millisecond)
+
+	assert.Empty(t, events)
+	// Курсор сдвигается за события других ПВЗ
+	assert.Equal(t, int64(1), cursor)
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+}
+
+func TestHub_PollContextCancel(t *testing.T) {
+	hub := NewHub(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	events, _ := hub.Poll(ctx, "pvz1", 0, time.Minute)
+	assert.Empty(t, events)
+}
+
+func TestHub_BufferCapacity(t *testing.T) {
+	hub := NewHub(2)
+	for i := 0; i < 5; i++ {
+		hub.Publish("pvz1", models.EventProductAdded, i)
+	}
+
+	events, cursor := hub.Poll(context.Background(), "pvz1", 0, time.Millisecond)
+	assert.Len(t, events, 2)
+	assert.Equal(t, int64(4), events[0].ID)
+	assert.Equal(t, int64(5), cursor)
+}
+
+func TestHub_StaleCursorAfterRestart(t *testing.T) {
+	hub := NewHub(10)
+	hub.Publish("pvz1", models.EventProductAdded, nil)
+
+	events, cursor := hub.Poll(context.Background(), "pvz1", 100, time.Millisecond)
+	assert.Len(t, events, 1)
+	assert.Equal(t, int64(1), cursor)
+}
+
+func TestHub_PublishOnceSkipsDuplicates(t *testing.T) {
+	hub := NewHub(10)
+	first := hub.PublishOnce("event-1", "pvz1", models.EventProductAdded, nil)
+	again := hub.PublishOnce("event-1", "pvz1", models.EventProductAdded, nil)
+	hub.Publish("pvz1", models.EventProductDeleted, nil)
+
+	assert.Equal(t, first.ID, again.ID)
+	assert.Equal(t, "event-1", first.DedupID)
+
+	events, _ := hub.Poll(context.Background(), "pvz1", 0, time.Millisecond)
+	assert.Len(t, events, 2)
+}
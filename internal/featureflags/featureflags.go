@@ -0,0 +1,122 @@
+// Package featureflags включает функции для всех ПВЗ или отдельных ПВЗ без перевыпуска сервиса.
+// Значения хранятся в базе данных и кэшируются; флаги, которых нет в базе, берутся из конфигурации
+package featureflags
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"pvz-service/internal/db/queries"
+)
+
+// Известные флаги
+const (
+	// ReturnsWorkflow включает новый процесс возвратов
+	ReturnsWorkflow = "returns_workflow"
+	// PVZDelete открывает удаление ПВЗ (DELETE /pvz/:pvzId)
+	PVZDelete = "pvz_delete"
+	// WorkingHoursOverride разрешает приёмку товаров в ПВЗ вне часов работы
+	WorkingHoursOverride = "working_hours_override"
+)
+
+// Checker сообщает, включен ли флаг. Используется middleware и сервисами
+type Checker interface {
+	Enabled(ctx context.Context, name, pvzID string) bool
+}
+
+// FlagsInterface определяет интерфейс кэша флагов, который сбрасывается после изменений администратора
+type FlagsInterface interface {
+	Checker
+	Invalidate()
+}
+
+// flagState - закэшированное значение флага
+type flagState struct {
+	enabled bool
+	pvz     map[string]bool
+}
+
+// Flags кэширует флаги из базы данных и перечитывает их по истечении ttl
+type Flags struct {
+	flagQueries queries.FeatureFlagQueriesInterface
+	defaults    map[string]bool
+	ttl         time.Duration
+
+	mu       sync.RWMutex
+	flags    map[string]flagState
+	loadedAt time.Time
+}
+
+// NewFlags создает новый экземпляр Flags. defaults - флаги, включенные в конфигурации,
+// они действуют, пока флаг не задан в базе данных
+func NewFlags(flagQueries queries.FeatureFlagQueriesInterface, defaults []string, ttl time.Duration) *Flags {
+	enabled := make(map[string]bool, len(defaults))
+	for _, name := range defaults {
+		enabled[name] = true
+	}
+
+	return &Flags{
+		flagQueries: flagQueries,
+		defaults:    enabled,
+		ttl:         ttl,
+	}
+}
+
+// Enabled сообщает, включен ли флаг для ПВЗ. Значение для ПВЗ важнее общего,
+// при пустом pvzID проверяется общее значение. Неизвестный флаг выключен
+func (f *Flags) Enabled(ctx context.Context, name, pvzID string) bool {
+	state, ok := f.getFlags(ctx)[name]
+	if !ok {
+		return f.defaults[name]
+	}
+
+	if enabled, ok := state.pvz[pvzID]; ok && pvzID != "" {
+		return enabled
+	}
+	return state.enabled
+}
+
+// Invalidate сбрасывает кэш, чтобы изменения администратора применились сразу
+func (f *Flags) Invalidate() {
+	f.mu.Lock()
+	f.loadedAt = time.Time{}
+	f.mu.Unlock()
+}
+
+// getFlags возвращает кэш флагов, перечитывая его по истечении ttl.
+// При ошибке базы данных используется прежний кэш
+func (f *Flags) getFlags(ctx context.Context) map[string]flagState {
+	f.mu.RLock()
+	flags, loadedAt := f.flags, f.loadedAt
+	f.mu.RUnlock()
+
+	if flags != nil && time.Since(loadedAt) < f.ttl {
+		return flags
+	}
+
+	loaded, err := f.flagQueries.GetFlags(ctx)
+	if err != nil {
+		// Следующая попытка - через ttl, чтобы не нагружать базу на каждом запросе
+		log.Printf("Failed to load feature flags: %v", err)
+		if flags == nil {
+			flags = map[string]flagState{}
+		}
+	} else {
+		flags = make(map[string]flagState, len(loaded))
+		for _, flag := range loaded {
+			state := flagState{enabled: flag.Enabled, pvz: make(map[string]bool, len(flag.PVZ))}
+			for _, pvzFlag := range flag.PVZ {
+				state.pvz[pvzFlag.PvzID] = pvzFlag.Enabled
+			}
+			flags[flag.Name] = state
+		}
+	}
+
+	f.mu.Lock()
+	f.flags, f.loadedAt = flags, time.Now()
+	f.mu.Unlock()
+
+	return flags
+}
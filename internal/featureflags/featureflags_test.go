@@ -0,0 +1,86 @@
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/models"
+)
+
+const (
+	testPVZID      = "123e4567-e89b-12d3-a456-426614174000"
+	testOtherPVZID = "123e4567-e89b-12d3-a456-426614174001"
+)
+
+// fakeFlagQueries отдает флаги и считает обращения
+type fakeFlagQueries struct {
+	flags []models.FeatureFlag
+	err   error
+	calls int
+}
+
+func (q *fakeFlagQueries) GetFlags(ctx context.Context) ([]models.FeatureFlag, error) {
+	q.calls++
+	return q.flags, q.err
+}
+
+func (q *fakeFlagQueries) GetFlag(ctx context.Context, name string) (*models.FeatureFlag, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (q *fakeFlagQueries) UpsertFlag(ctx context.Context, flag models.FeatureFlag) (*models.FeatureFlag, error) {
+	return &flag, nil
+}
+
+func (q *fakeFlagQueries) SetPVZFlag(ctx context.Context, name, pvzID string, enabled bool) error {
+	return nil
+}
+
+func (q *fakeFlagQueries) DeletePVZFlag(ctx context.Context, name, pvzID string) error {
+	return nil
+}
+
+func TestFlags_Enabled(t *testing.T) {
+	flagQueries := &fakeFlagQueries{flags: []models.FeatureFlag{
+		{Name: ReturnsWorkflow, Enabled: false, PVZ: []models.FeatureFlagPVZ{
+			{Name: ReturnsWorkflow, PvzID: testPVZID, Enabled: true},
+		}},
+		{Name: "bulk_issue", Enabled: true, PVZ: []models.FeatureFlagPVZ{
+			{Name: "bulk_issue", PvzID: testPVZID, Enabled: false},
+		}},
+	}}
+	flags := NewFlags(flagQueries, []string{"from_config", "bulk_issue"}, time.Minute)
+	ctx := context.Background()
+
+	// Значение для ПВЗ важнее общего
+	assert.True(t, flags.Enabled(ctx, ReturnsWorkflow, testPVZID))
+	assert.False(t, flags.Enabled(ctx, ReturnsWorkflow, testOtherPVZID))
+	assert.False(t, flags.Enabled(ctx, ReturnsWorkflow, ""))
+	assert.False(t, flags.Enabled(ctx, "bulk_issue", testPVZID))
+	assert.True(t, flags.Enabled(ctx, "bulk_issue", testOtherPVZID))
+
+	// Флаги из конфигурации действуют, пока их нет в базе
+	assert.True(t, flags.Enabled(ctx, "from_config", testPVZID))
+	assert.False(t, flags.Enabled(ctx, "unknown", testPVZID))
+	assert.Equal(t, 1, flagQueries.calls)
+
+	// После сброса кэша флаги перечитываются
+	flags.Invalidate()
+	flags.Enabled(ctx, ReturnsWorkflow, testPVZID)
+	assert.Equal(t, 2, flagQueries.calls)
+}
+
+func TestFlags_LoadError(t *testing.T) {
+	flagQueries := &fakeFlagQueries{err: errors.New("database error")}
+	flags := NewFlags(flagQueries, []string{"from_config"}, time.Minute)
+
+	assert.False(t, flags.Enabled(context.Background(), ReturnsWorkflow, testPVZID))
+	assert.True(t, flags.Enabled(context.Background(), "from_config", testPVZID))
+
+	// Повторная попытка откладывается до истечения ttl
+	assert.Equal(t, 1, flagQueries.calls)
+}
@@ -0,0 +1,135 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"pvz-service/internal/grpcapi/scanpb"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/models"
+	"pvz-service/internal/service"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// ProductScanner добавляет товары в открытую приёмку ПВЗ.
+// Реализуется service.ProductService, общим с REST обработчиком AddProduct
+type ProductScanner interface {
+	OpenReception(ctx context.Context, pvzID string) (*models.Reception, error)
+	AddProduct(ctx context.Context, userID string, reception *models.Reception, productType, barcode string, photos []models.ProductPhoto, overrideCapacity bool) (*models.ProductResponse, error)
+}
+
+// ScanServer реализует поток сканирований товаров
+type ScanServer struct {
+	scanpb.UnimplementedScanServiceServer
+	scanner ProductScanner
+}
+
+// NewScanServer создает новый экземпляр ScanServer
+func NewScanServer(scanner ProductScanner) *ScanServer {
+	return &ScanServer{scanner: scanner}
+}
+
+// Scan обрабатывает сканирования по одному в порядке поступления и отвечает на каждое
+// товаром или ошибкой. Поток завершается, когда клиент закрывает отправку
+func (s *ScanServer) Scan(stream scanpb.ScanService_ScanServer) error {
+	ctx := stream.Context()
+	locale := localeFromContext(ctx)
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := stream.Send(s.scan(ctx, locale, req)); err != nil {
+			return err
+		}
+	}
+}
+
+// scan добавляет один отсканированный товар с теми же проверками, что и REST API
+func (s *ScanServer) scan(ctx context.Context, locale string, req *scanpb.ScanRequest) *scanpb.ScanResponse {
+	resp := &scanpb.ScanResponse{RequestId: req.GetRequestId()}
+
+	productReq := models.CreateProductRequest{
+		Type:    req.GetType(),
+		PvzID:   req.GetPvzId(),
+		Barcode: req.GetBarcode(),
+	}
+	if err := binding.Validator.ValidateStruct(&productReq); err != nil {
+		resp.Result = scanError(scanpb.ScanErrorCode_SCAN_ERROR_CODE_INVALID_REQUEST, i18n.Translate(locale, i18n.MsgInvalidRequest)+": "+err.Error())
+		return resp
+	}
+
+	reception, err := s.scanner.OpenReception(ctx, productReq.PvzID)
+	if errors.Is(err, service.ErrReceptionClosed) {
+		resp.Result = scanError(scanpb.ScanErrorCode_SCAN_ERROR_CODE_RECEPTION_CLOSED, i18n.Translate(locale, i18n.MsgReceptionClosed))
+		return resp
+	}
+	if errors.Is(err, service.ErrReceptionPaused) {
+		// Приёмка на паузе: сканер, как и при закрытой приёмке, не может принимать товары
+		resp.Result = scanError(scanpb.ScanErrorCode_SCAN_ERROR_CODE_RECEPTION_CLOSED, i18n.Translate(locale, i18n.MsgReceptionPaused))
+		return resp
+	}
+	if errors.Is(err, service.ErrOutsideWorkingHours) {
+		// Отдельного кода в протоколе нет: сканер, как и при закрытой приёмке, не может принимать товары
+		resp.Result = scanError(scanpb.ScanErrorCode_SCAN_ERROR_CODE_RECEPTION_CLOSED, i18n.Translate(locale, i18n.MsgOutsideWorkingHours))
+		return resp
+	}
+	if err != nil {
+		resp.Result = scanError(scanpb.ScanErrorCode_SCAN_ERROR_CODE_NO_OPEN_RECEPTION, i18n.Translate(locale, i18n.MsgNoOpenReception))
+		return resp
+	}
+
+	// Сканер не подтверждает прием сверх вместимости ПВЗ: такие товары принимаются через REST
+	product, err := s.scanner.AddProduct(ctx, userIDFromContext(ctx), reception, productReq.Type, productReq.Barcode, nil, false)
+	// Приёмку могли закрыть или приостановить параллельным запросом
+	if errors.Is(err, service.ErrReceptionClosed) {
+		resp.Result = scanError(scanpb.ScanErrorCode_SCAN_ERROR_CODE_RECEPTION_CLOSED, i18n.Translate(locale, i18n.MsgReceptionClosed))
+		return resp
+	}
+	if errors.Is(err, service.ErrReceptionPaused) {
+		resp.Result = scanError(scanpb.ScanErrorCode_SCAN_ERROR_CODE_RECEPTION_CLOSED, i18n.Translate(locale, i18n.MsgReceptionPaused))
+		return resp
+	}
+	var limitErr *service.ProductLimitError
+	if errors.As(err, &limitErr) {
+		resp.Result = scanError(scanpb.ScanErrorCode_SCAN_ERROR_CODE_LIMIT_EXCEEDED, i18n.Translate(locale, i18n.MsgProductTypeLimitExceeded))
+		return resp
+	}
+	if errors.Is(err, service.ErrReceptionFull) {
+		resp.Result = scanError(scanpb.ScanErrorCode_SCAN_ERROR_CODE_LIMIT_EXCEEDED, i18n.Translate(locale, i18n.MsgReceptionFull))
+		return resp
+	}
+	var fullErr *service.PVZFullError
+	if errors.As(err, &fullErr) {
+		resp.Result = scanError(scanpb.ScanErrorCode_SCAN_ERROR_CODE_LIMIT_EXCEEDED, i18n.Translate(locale, i18n.MsgPVZFull))
+		return resp
+	}
+	if err != nil {
+		resp.Result = scanError(scanpb.ScanErrorCode_SCAN_ERROR_CODE_INTERNAL, i18n.Translate(locale, i18n.MsgAddProductFailed)+": "+err.Error())
+		return resp
+	}
+
+	added := &scanpb.Product{
+		Id:          product.ID,
+		ReceptionId: product.ReceptionID,
+		Type:        product.Type,
+		DateTime:    product.DateTime.Format(time.RFC3339Nano),
+	}
+	if product.Barcode != nil {
+		added.Barcode = *product.Barcode
+	}
+	resp.Result = &scanpb.ScanResponse_Product{Product: added}
+	return resp
+}
+
+func scanError(code scanpb.ScanErrorCode, message string) *scanpb.ScanResponse_Error {
+	return &scanpb.ScanResponse_Error{Error: &scanpb.ScanError{Code: code, Message: message}}
+}
@@ -0,0 +1,166 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"pvz-service/internal/config"
+	"pvz-service/internal/grpcapi/scanpb"
+	"pvz-service/internal/models"
+	"pvz-service/internal/service"
+	"pvz-service/internal/utils"
+)
+
+const (
+	testPVZID       = "123e4567-e89b-12d3-a456-426614174000"
+	testClosedPVZID = "123e4567-e89b-12d3-a456-426614174001"
+	testReceptionID = "123e4567-e89b-12d3-a456-426614174002"
+)
+
+// fakeScanner добавляет товары в открытую приёмку testPVZID, приёмка testClosedPVZID закрыта
+type fakeScanner struct {
+	added []string
+	users []string
+}
+
+func (s *fakeScanner) OpenReception(ctx context.Context, pvzID string) (*models.Reception, error) {
+	switch pvzID {
+	case testPVZID:
+		return &models.Reception{ID: testReceptionID, PvzID: pvzID, Status: "in_progress"}, nil
+	case testClosedPVZID:
+		return nil, service.ErrReceptionClosed
+	default:
+		return nil, service.ErrNoOpenReception
+	}
+}
+
+func (s *fakeScanner) AddProduct(ctx context.Context, userID string, reception *models.Reception, productType, barcode string, photos []models.ProductPhoto, overrideCapacity bool) (*models.ProductResponse, error) {
+	s.added = append(s.added, barcode)
+	s.users = append(s.users, userID)
+	return &models.ProductResponse{
+		ID:          "p" + barcode,
+		DateTime:    time.Now(),
+		Type:        productType,
+		ReceptionID: reception.ID,
+		Barcode:     &barcode,
+	}, nil
+}
+
+type activeSessions struct{}
+
+func (activeSessions) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	return true, nil
+}
+
+type activeUsers struct{}
+
+func (activeUsers) IsUserActive(ctx context.Context, userID string) (bool, error) {
+	return true, nil
+}
+
+// setupScanTest запускает gRPC сервер в памяти и возвращает клиента и менеджер токенов
+func setupScanTest(t *testing.T) (scanpb.ScanServiceClient, *fakeScanner, *utils.JWTManager) {
+	jwtManager := utils.NewJWTManager(&config.JWTConfig{Secret: "test-secret", ExpireTime: time.Hour})
+	scanner := &fakeScanner{}
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(jwtManager, activeSessions{}, activeUsers{}, false, "ru")
+	scanpb.RegisterScanServiceServer(server, NewScanServer(scanner))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return scanpb.NewScanServiceClient(conn), scanner, jwtManager
+}
+
+func withToken(t *testing.T, jwtManager *utils.JWTManager, role string) context.Context {
+	token, err := jwtManager.GenerateDummyToken(role)
+	require.NoError(t, err)
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+// TestScanRespondsInOrder проверяет, что ответы приходят в порядке сканирований,
+// а ошибка одного сканирования не закрывает поток
+func TestScanRespondsInOrder(t *testing.T) {
+	client, scanner, jwtManager := setupScanTest(t)
+
+	stream, err := client.Scan(withToken(t, jwtManager, "employee"))
+	require.NoError(t, err)
+
+	requests := []*scanpb.ScanRequest{
+		{RequestId: "1", PvzId: testPVZID, Type: "электроника", Barcode: "100"},
+		{RequestId: "2", PvzId: testPVZID, Type: "посуда", Barcode: "101"},
+		{RequestId: "3", PvzId: testClosedPVZID, Type: "обувь", Barcode: "102"},
+		{RequestId: "4", PvzId: "123e4567-e89b-12d3-a456-426614174099", Type: "обувь", Barcode: "103"},
+		{RequestId: "5", PvzId: testPVZID, Type: "одежда", Barcode: "104"},
+	}
+	for _, req := range requests {
+		require.NoError(t, stream.Send(req))
+	}
+	require.NoError(t, stream.CloseSend())
+
+	var responses []*scanpb.ScanResponse
+	for range requests {
+		resp, err := stream.Recv()
+		require.NoError(t, err)
+		responses = append(responses, resp)
+	}
+
+	for i, resp := range responses {
+		assert.Equal(t, requests[i].RequestId, resp.GetRequestId())
+	}
+	assert.Equal(t, testReceptionID, responses[0].GetProduct().GetReceptionId())
+	assert.Equal(t, "100", responses[0].GetProduct().GetBarcode())
+	assert.Equal(t, scanpb.ScanErrorCode_SCAN_ERROR_CODE_INVALID_REQUEST, responses[1].GetError().GetCode())
+	assert.Equal(t, scanpb.ScanErrorCode_SCAN_ERROR_CODE_RECEPTION_CLOSED, responses[2].GetError().GetCode())
+	assert.Equal(t, scanpb.ScanErrorCode_SCAN_ERROR_CODE_NO_OPEN_RECEPTION, responses[3].GetError().GetCode())
+	assert.Equal(t, "104", responses[4].GetProduct().GetBarcode())
+	assert.Equal(t, []string{"100", "104"}, scanner.added)
+	// Товары записываются на владельца токена потока
+	require.Len(t, scanner.users, 2)
+	assert.NotEmpty(t, scanner.users[0])
+	assert.Equal(t, scanner.users[0], scanner.users[1])
+}
+
+// TestScanRequiresEmployee проверяет авторизацию потока
+func TestScanRequiresEmployee(t *testing.T) {
+	client, _, jwtManager := setupScanTest(t)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		code codes.Code
+	}{
+		{"Без токена", context.Background(), codes.Unauthenticated},
+		{"Неверный токен", metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer invalid"), codes.Unauthenticated},
+		{"Модератор", withToken(t, jwtManager, "moderator"), codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.Scan(tt.ctx)
+			require.NoError(t, err)
+
+			_, err = stream.Recv()
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
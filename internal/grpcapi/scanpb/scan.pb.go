@@ -0,0 +1,473 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: internal/grpcapi/scanpb/scan.proto
+
+package scanpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ScanErrorCode int32
+
+const (
+	ScanErrorCode_SCAN_ERROR_CODE_UNSPECIFIED       ScanErrorCode = 0
+	ScanErrorCode_SCAN_ERROR_CODE_INVALID_REQUEST   ScanErrorCode = 1
+	ScanErrorCode_SCAN_ERROR_CODE_NO_OPEN_RECEPTION ScanErrorCode = 2
+	ScanErrorCode_SCAN_ERROR_CODE_RECEPTION_CLOSED  ScanErrorCode = 3
+	ScanErrorCode_SCAN_ERROR_CODE_LIMIT_EXCEEDED    ScanErrorCode = 4
+	ScanErrorCode_SCAN_ERROR_CODE_INTERNAL          ScanErrorCode = 5
+)
+
+// Enum value maps for ScanErrorCode.
+var (
+	ScanErrorCode_name = map[int32]string{
+		0: "SCAN_ERROR_CODE_UNSPECIFIED",
+		1: "SCAN_ERROR_CODE_INVALID_REQUEST",
+		2: "SCAN_ERROR_CODE_NO_OPEN_RECEPTION",
+		3: "SCAN_ERROR_CODE_RECEPTION_CLOSED",
+		4: "SCAN_ERROR_CODE_LIMIT_EXCEEDED",
+		5: "SCAN_ERROR_CODE_INTERNAL",
+	}
+	ScanErrorCode_value = map[string]int32{
+		"SCAN_ERROR_CODE_UNSPECIFIED":       0,
+		"SCAN_ERROR_CODE_INVALID_REQUEST":   1,
+		"SCAN_ERROR_CODE_NO_OPEN_RECEPTION": 2,
+		"SCAN_ERROR_CODE_RECEPTION_CLOSED":  3,
+		"SCAN_ERROR_CODE_LIMIT_EXCEEDED":    4,
+		"SCAN_ERROR_CODE_INTERNAL":          5,
+	}
+)
+
+func (x ScanErrorCode) Enum() *ScanErrorCode {
+	p := new(ScanErrorCode)
+	*p = x
+	return p
+}
+
+func (x ScanErrorCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ScanErrorCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_internal_grpcapi_scanpb_scan_proto_enumTypes[0].Descriptor()
+}
+
+func (ScanErrorCode) Type() protoreflect.EnumType {
+	return &file_internal_grpcapi_scanpb_scan_proto_enumTypes[0]
+}
+
+func (x ScanErrorCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ScanErrorCode.Descriptor instead.
+func (ScanErrorCode) EnumDescriptor() ([]byte, []int) {
+	return file_internal_grpcapi_scanpb_scan_proto_rawDescGZIP(), []int{0}
+}
+
+// ScanRequest - отсканированный товар
+type ScanRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор сканирования на стороне устройства, возвращается в ответе
+	RequestId string `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	PvzId     string `protobuf:"bytes,2,opt,name=pvz_id,json=pvzId,proto3" json:"pvz_id,omitempty"`
+	// Тип товара: электроника, одежда или обувь
+	Type          string `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Barcode       string `protobuf:"bytes,4,opt,name=barcode,proto3" json:"barcode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScanRequest) Reset() {
+	*x = ScanRequest{}
+	mi := &file_internal_grpcapi_scanpb_scan_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanRequest) ProtoMessage() {}
+
+func (x *ScanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_grpcapi_scanpb_scan_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanRequest.ProtoReflect.Descriptor instead.
+func (*ScanRequest) Descriptor() ([]byte, []int) {
+	return file_internal_grpcapi_scanpb_scan_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ScanRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ScanRequest) GetPvzId() string {
+	if x != nil {
+		return x.PvzId
+	}
+	return ""
+}
+
+func (x *ScanRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ScanRequest) GetBarcode() string {
+	if x != nil {
+		return x.Barcode
+	}
+	return ""
+}
+
+// ScanResponse - подтверждение или ошибка сканирования
+type ScanResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	RequestId string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*ScanResponse_Product
+	//	*ScanResponse_Error
+	Result        isScanResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScanResponse) Reset() {
+	*x = ScanResponse{}
+	mi := &file_internal_grpcapi_scanpb_scan_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanResponse) ProtoMessage() {}
+
+func (x *ScanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_grpcapi_scanpb_scan_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanResponse.ProtoReflect.Descriptor instead.
+func (*ScanResponse) Descriptor() ([]byte, []int) {
+	return file_internal_grpcapi_scanpb_scan_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ScanResponse) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ScanResponse) GetResult() isScanResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *ScanResponse) GetProduct() *Product {
+	if x != nil {
+		if x, ok := x.Result.(*ScanResponse_Product); ok {
+			return x.Product
+		}
+	}
+	return nil
+}
+
+func (x *ScanResponse) GetError() *ScanError {
+	if x != nil {
+		if x, ok := x.Result.(*ScanResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isScanResponse_Result interface {
+	isScanResponse_Result()
+}
+
+type ScanResponse_Product struct {
+	Product *Product `protobuf:"bytes,2,opt,name=product,proto3,oneof"`
+}
+
+type ScanResponse_Error struct {
+	Error *ScanError `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+func (*ScanResponse_Product) isScanResponse_Result() {}
+
+func (*ScanResponse_Error) isScanResponse_Result() {}
+
+// Product - добавленный товар
+type Product struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ReceptionId string                 `protobuf:"bytes,2,opt,name=reception_id,json=receptionId,proto3" json:"reception_id,omitempty"`
+	Type        string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Barcode     string                 `protobuf:"bytes,4,opt,name=barcode,proto3" json:"barcode,omitempty"`
+	// Время добавления в формате RFC 3339
+	DateTime      string `protobuf:"bytes,5,opt,name=date_time,json=dateTime,proto3" json:"date_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Product) Reset() {
+	*x = Product{}
+	mi := &file_internal_grpcapi_scanpb_scan_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Product) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Product) ProtoMessage() {}
+
+func (x *Product) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_grpcapi_scanpb_scan_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Product.ProtoReflect.Descriptor instead.
+func (*Product) Descriptor() ([]byte, []int) {
+	return file_internal_grpcapi_scanpb_scan_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Product) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Product) GetReceptionId() string {
+	if x != nil {
+		return x.ReceptionId
+	}
+	return ""
+}
+
+func (x *Product) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Product) GetBarcode() string {
+	if x != nil {
+		return x.Barcode
+	}
+	return ""
+}
+
+func (x *Product) GetDateTime() string {
+	if x != nil {
+		return x.DateTime
+	}
+	return ""
+}
+
+// ScanError - причина отказа в добавлении товара
+type ScanError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Code  ScanErrorCode          `protobuf:"varint,1,opt,name=code,proto3,enum=pvz.scan.v1.ScanErrorCode" json:"code,omitempty"`
+	// Сообщение на языке из метаданных accept-language
+	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScanError) Reset() {
+	*x = ScanError{}
+	mi := &file_internal_grpcapi_scanpb_scan_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScanError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanError) ProtoMessage() {}
+
+func (x *ScanError) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_grpcapi_scanpb_scan_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanError.ProtoReflect.Descriptor instead.
+func (*ScanError) Descriptor() ([]byte, []int) {
+	return file_internal_grpcapi_scanpb_scan_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ScanError) GetCode() ScanErrorCode {
+	if x != nil {
+		return x.Code
+	}
+	return ScanErrorCode_SCAN_ERROR_CODE_UNSPECIFIED
+}
+
+func (x *ScanError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_internal_grpcapi_scanpb_scan_proto protoreflect.FileDescriptor
+
+const file_internal_grpcapi_scanpb_scan_proto_rawDesc = "" +
+	"\n" +
+	"\"internal/grpcapi/scanpb/scan.proto\x12\vpvz.scan.v1\"q\n" +
+	"\vScanRequest\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12\x15\n" +
+	"\x06pvz_id\x18\x02 \x01(\tR\x05pvzId\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x18\n" +
+	"\abarcode\x18\x04 \x01(\tR\abarcode\"\x99\x01\n" +
+	"\fScanResponse\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x120\n" +
+	"\aproduct\x18\x02 \x01(\v2\x14.pvz.scan.v1.ProductH\x00R\aproduct\x12.\n" +
+	"\x05error\x18\x03 \x01(\v2\x16.pvz.scan.v1.ScanErrorH\x00R\x05errorB\b\n" +
+	"\x06result\"\x87\x01\n" +
+	"\aProduct\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\freception_id\x18\x02 \x01(\tR\vreceptionId\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x18\n" +
+	"\abarcode\x18\x04 \x01(\tR\abarcode\x12\x1b\n" +
+	"\tdate_time\x18\x05 \x01(\tR\bdateTime\"U\n" +
+	"\tScanError\x12.\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x1a.pvz.scan.v1.ScanErrorCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage*\xe4\x01\n" +
+	"\rScanErrorCode\x12\x1f\n" +
+	"\x1bSCAN_ERROR_CODE_UNSPECIFIED\x10\x00\x12#\n" +
+	"\x1fSCAN_ERROR_CODE_INVALID_REQUEST\x10\x01\x12%\n" +
+	"!SCAN_ERROR_CODE_NO_OPEN_RECEPTION\x10\x02\x12$\n" +
+	" SCAN_ERROR_CODE_RECEPTION_CLOSED\x10\x03\x12\"\n" +
+	"\x1eSCAN_ERROR_CODE_LIMIT_EXCEEDED\x10\x04\x12\x1c\n" +
+	"\x18SCAN_ERROR_CODE_INTERNAL\x10\x052N\n" +
+	"\vScanService\x12?\n" +
+	"\x04Scan\x12\x18.pvz.scan.v1.ScanRequest\x1a\x19.pvz.scan.v1.ScanResponse(\x010\x01B%Z#pvz-service/internal/grpcapi/scanpbb\x06proto3"
+
+var (
+	file_internal_grpcapi_scanpb_scan_proto_rawDescOnce sync.Once
+	file_internal_grpcapi_scanpb_scan_proto_rawDescData []byte
+)
+
+func file_internal_grpcapi_scanpb_scan_proto_rawDescGZIP() []byte {
+	file_internal_grpcapi_scanpb_scan_proto_rawDescOnce.Do(func() {
+		file_internal_grpcapi_scanpb_scan_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_internal_grpcapi_scanpb_scan_proto_rawDesc), len(file_internal_grpcapi_scanpb_scan_proto_rawDesc)))
+	})
+	return file_internal_grpcapi_scanpb_scan_proto_rawDescData
+}
+
+var file_internal_grpcapi_scanpb_scan_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_internal_grpcapi_scanpb_scan_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_internal_grpcapi_scanpb_scan_proto_goTypes = []any{
+	(ScanErrorCode)(0),   // 0: pvz.scan.v1.ScanErrorCode
+	(*ScanRequest)(nil),  // 1: pvz.scan.v1.ScanRequest
+	(*ScanResponse)(nil), // 2: pvz.scan.v1.ScanResponse
+	(*Product)(nil),      // 3: pvz.scan.v1.Product
+	(*ScanError)(nil),    // 4: pvz.scan.v1.ScanError
+}
+var file_internal_grpcapi_scanpb_scan_proto_depIdxs = []int32{
+	3, // 0: pvz.scan.v1.ScanResponse.product:type_name -> pvz.scan.v1.Product
+	4, // 1: pvz.scan.v1.ScanResponse.error:type_name -> pvz.scan.v1.ScanError
+	0, // 2: pvz.scan.v1.ScanError.code:type_name -> pvz.scan.v1.ScanErrorCode
+	1, // 3: pvz.scan.v1.ScanService.Scan:input_type -> pvz.scan.v1.ScanRequest
+	2, // 4: pvz.scan.v1.ScanService.Scan:output_type -> pvz.scan.v1.ScanResponse
+	4, // [4:5] is the sub-list for method output_type
+	3, // [3:4] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_internal_grpcapi_scanpb_scan_proto_init() }
+func file_internal_grpcapi_scanpb_scan_proto_init() {
+	if File_internal_grpcapi_scanpb_scan_proto != nil {
+		return
+	}
+	file_internal_grpcapi_scanpb_scan_proto_msgTypes[1].OneofWrappers = []any{
+		(*ScanResponse_Product)(nil),
+		(*ScanResponse_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_internal_grpcapi_scanpb_scan_proto_rawDesc), len(file_internal_grpcapi_scanpb_scan_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_internal_grpcapi_scanpb_scan_proto_goTypes,
+		DependencyIndexes: file_internal_grpcapi_scanpb_scan_proto_depIdxs,
+		EnumInfos:         file_internal_grpcapi_scanpb_scan_proto_enumTypes,
+		MessageInfos:      file_internal_grpcapi_scanpb_scan_proto_msgTypes,
+	}.Build()
+	File_internal_grpcapi_scanpb_scan_proto = out.File
+	file_internal_grpcapi_scanpb_scan_proto_goTypes = nil
+	file_internal_grpcapi_scanpb_scan_proto_depIdxs = nil
+}
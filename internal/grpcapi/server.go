@@ -0,0 +1,144 @@
+// Package grpcapi содержит gRPC API сервиса для устройств, которым HTTP-запрос
+// на каждое действие обходится слишком дорого, например сканеров на складском Wi-Fi
+package grpcapi
+
+import (
+	"context"
+	"strings"
+
+	"pvz-service/internal/app"
+	"pvz-service/internal/authz"
+	"pvz-service/internal/grpcapi/scanpb"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// SessionChecker проверяет, что сессия токена не отозвана
+type SessionChecker interface {
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+}
+
+// UserChecker проверяет, что владелец токена существует и не деактивирован
+type UserChecker interface {
+	IsUserActive(ctx context.Context, userID string) (bool, error)
+}
+
+// SetupServer создает gRPC сервер со всеми сервисами поверх зависимостей c, как api.SetupRouter для HTTP
+func SetupServer(c *app.Container) *grpc.Server {
+	server := NewServer(c.JWT, c.Queries.Session, c.ActiveUsers, c.Config.DummyLogin.ReadOnly, c.Config.I18n.DefaultLocale)
+	scanpb.RegisterScanServiceServer(server, NewScanServer(c.ProductService))
+	return server
+}
+
+// NewServer создает gRPC сервер, пропускающий к потокам только сотрудников
+// с действующим токеном в метаданных authorization. При dummyReadOnly тестовые токены
+// /dummyLogin отклоняются: потоки сканирования изменяют данные
+func NewServer(jwtManager utils.JWTManagerInterface, sessions SessionChecker, users UserChecker, dummyReadOnly bool, defaultLocale string) *grpc.Server {
+	if !i18n.IsSupported(defaultLocale) {
+		defaultLocale = i18n.DefaultLocale
+	}
+
+	return grpc.NewServer(grpc.StreamInterceptor(authStreamInterceptor(jwtManager, sessions, users, dummyReadOnly, defaultLocale)))
+}
+
+// authStreamInterceptor проверяет токен и право на добавление товаров так же, как AuthMiddleware и обработчик AddProduct в HTTP API.
+// Локаль сообщений выбирается по метаданным accept-language
+func authStreamInterceptor(jwtManager utils.JWTManagerInterface, sessions SessionChecker, users UserChecker, dummyReadOnly bool, defaultLocale string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := stream.Context()
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		locale := defaultLocale
+		if values := md.Get("accept-language"); len(values) > 0 {
+			locale = i18n.Negotiate(values[0], defaultLocale)
+		}
+
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return status.Error(codes.Unauthenticated, i18n.Translate(locale, i18n.MsgTokenMissing))
+		}
+
+		tokenString, ok := strings.CutPrefix(values[0], "Bearer ")
+		if !ok {
+			return status.Error(codes.Unauthenticated, i18n.Translate(locale, i18n.MsgTokenMalformed))
+		}
+
+		claims, err := jwtManager.ValidateToken(tokenString)
+		if err != nil {
+			return status.Error(codes.Unauthenticated, i18n.Translate(locale, i18n.MsgTokenInvalid)+": "+err.Error())
+		}
+
+		// Тестовые токены /dummyLogin не привязаны к сессии
+		if claims.ID != "" {
+			active, err := sessions.IsSessionActive(ctx, claims.ID)
+			if err != nil {
+				return status.Error(codes.Internal, i18n.Translate(locale, i18n.MsgSessionCheckFailed)+": "+err.Error())
+			}
+			if !active {
+				return status.Error(codes.Unauthenticated, i18n.Translate(locale, i18n.MsgSessionRevoked))
+			}
+
+			userActive, err := users.IsUserActive(ctx, claims.UserID)
+			if err != nil {
+				return status.Error(codes.Internal, i18n.Translate(locale, i18n.MsgUserCheckFailed)+": "+err.Error())
+			}
+			if !userActive {
+				return status.Error(codes.Unauthenticated, i18n.Translate(locale, i18n.MsgUserDeactivated))
+			}
+		}
+
+		if dummyReadOnly && claims.IsDummy() {
+			return status.Error(codes.PermissionDenied, i18n.Translate(locale, i18n.MsgDummyTokenReadOnly))
+		}
+
+		if !authz.Can(claims.Role, authz.ProductAdd) {
+			return status.Error(codes.PermissionDenied, i18n.Translate(locale, i18n.MsgForbiddenAddProduct))
+		}
+
+		ctx = withUserID(withLocale(ctx, locale), claims.UserID)
+		return handler(srv, &localeStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// localeStream подменяет контекст потока, чтобы передать локаль и пользователя обработчику
+type localeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *localeStream) Context() context.Context {
+	return s.ctx
+}
+
+type localeKey struct{}
+
+// withLocale сохраняет локаль потока в контексте
+func withLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// localeFromContext возвращает локаль потока, сохраненную перехватчиком
+func localeFromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok {
+		return locale
+	}
+	return i18n.DefaultLocale
+}
+
+type userIDKey struct{}
+
+// withUserID сохраняет в контексте пользователя, которому выдан токен потока
+func withUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// userIDFromContext возвращает пользователя потока, сохраненного перехватчиком
+func userIDFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDKey{}).(string)
+	return userID
+}
@@ -0,0 +1,136 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Статусы проверки зависимостей и сервиса в целом
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusFail     = "fail"
+)
+
+// Check - проверка одной зависимости сервиса
+type Check struct {
+	// Name - имя зависимости в отчете и в списке некритичных зависимостей
+	Name string
+	// Ping возвращает ошибку, если зависимость недоступна
+	Ping func(ctx context.Context) error
+}
+
+// Result - результат проверки одной зависимости
+type Result struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Critical bool   `json:"critical"`
+	// DurationMs - длительность проверки в миллисекундах
+	DurationMs int64  `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Report - результат проверки готовности сервиса.
+// StatusDegraded означает, что недоступны только некритичные зависимости
+type Report struct {
+	Status string   `json:"status"`
+	Checks []Result `json:"checks"`
+}
+
+// Checker проверяет зависимости сервиса параллельно, ограничивая каждую проверку timeout
+type Checker struct {
+	checks      []Check
+	timeout     time.Duration
+	nonCritical []string
+}
+
+// NewChecker создает новый экземпляр Checker. Зависимости из nonCritical при недоступности
+// переводят сервис в статус degraded, остальные - в fail
+func NewChecker(timeout time.Duration, nonCritical []string, checks ...Check) *Checker {
+	return &Checker{
+		checks:      checks,
+		timeout:     timeout,
+		nonCritical: nonCritical,
+	}
+}
+
+// Run проверяет все зависимости и возвращает отчет в порядке регистрации проверок
+func (c *Checker) Run(ctx context.Context) Report {
+	results := make([]Result, len(c.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: results}
+	for _, result := range results {
+		if result.Status == StatusOK {
+			continue
+		}
+		if result.Critical {
+			report.Status = StatusFail
+			break
+		}
+		report.Status = StatusDegraded
+	}
+
+	return report
+}
+
+// run выполняет одну проверку с ограничением по времени
+func (c *Checker) run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	started := time.Now()
+	err := check.Ping(ctx)
+
+	result := Result{
+		Name:       check.Name,
+		Status:     StatusOK,
+		Critical:   !slices.Contains(c.nonCritical, check.Name),
+		DurationMs: time.Since(started).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// DialURL возвращает проверку, что на хост и порт адреса rawURL устанавливается TCP-соединение.
+// Так проверяются webhook, которым нельзя отправить пробный запрос без побочных эффектов
+func DialURL(rawURL string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return fmt.Errorf("invalid url: %w", err)
+		}
+
+		port := u.Port()
+		if port == "" {
+			port = "80"
+			if u.Scheme == "https" {
+				port = "443"
+			}
+		}
+
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(u.Hostname(), port))
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
@@ -0,0 +1,87 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ok(ctx context.Context) error { return nil }
+
+func failing(ctx context.Context) error { return errors.New("connection refused") }
+
+// hanging ждет отмены контекста, как зависшая зависимость
+func hanging(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestCheckerRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		checks     []Check
+		wantStatus string
+	}{
+		{
+			name:       "Все зависимости доступны",
+			checks:     []Check{{Name: "postgres", Ping: ok}, {Name: "storage", Ping: ok}},
+			wantStatus: StatusOK,
+		},
+		{
+			name:       "Недоступна некритичная зависимость",
+			checks:     []Check{{Name: "postgres", Ping: ok}, {Name: "storage", Ping: failing}},
+			wantStatus: StatusDegraded,
+		},
+		{
+			name:       "Недоступна критичная зависимость",
+			checks:     []Check{{Name: "postgres", Ping: failing}, {Name: "storage", Ping: failing}},
+			wantStatus: StatusFail,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(time.Second, []string{"storage"}, tt.checks...)
+
+			report := checker.Run(context.Background())
+
+			assert.Equal(t, tt.wantStatus, report.Status)
+			assert.Len(t, report.Checks, len(tt.checks))
+			assert.Equal(t, "postgres", report.Checks[0].Name)
+			assert.True(t, report.Checks[0].Critical)
+			assert.False(t, report.Checks[1].Critical)
+		})
+	}
+}
+
+func TestCheckerTimeout(t *testing.T) {
+	checker := NewChecker(20*time.Millisecond, []string{"broker"},
+		Check{Name: "postgres", Ping: ok},
+		Check{Name: "broker", Ping: hanging},
+	)
+
+	started := time.Now()
+	report := checker.Run(context.Background())
+
+	assert.Less(t, time.Since(started), time.Second)
+	assert.Equal(t, StatusDegraded, report.Status)
+	assert.Equal(t, StatusFail, report.Checks[1].Status)
+	assert.Contains(t, report.Checks[1].Error, "deadline exceeded")
+}
+
+func TestDialURL(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	addr := listener.Addr().String()
+
+	assert.NoError(t, DialURL("http://"+addr+"/events")(context.Background()))
+
+	listener.Close()
+	assert.Error(t, DialURL("http://"+addr+"/events")(context.Background()))
+}
@@ -0,0 +1,242 @@
+package i18n
+
+// en - каталог сообщений на английском языке
+var en = map[Key]string{
+	MsgInvalidRequest:       "Invalid request",
+	MsgRequestTooLarge:      "Request body is too large",
+	MsgUnsupportedMediaType: "Request body must be application/json or multipart/form-data",
+	MsgInvalidQueryParams:   "Invalid query parameters",
+	MsgPVZIDRequired:        "PVZ ID is required",
+	MsgInternalError:        "Internal server error",
+	MsgSearchFailed:         "Search failed",
+
+	MsgTokenMissing:               "Authorization token is missing",
+	MsgTokenMalformed:             "Malformed token",
+	MsgTokenInvalid:               "Invalid token",
+	MsgTokenGenerateFailed:        "Failed to generate token",
+	MsgTokenCreateFailed:          "Failed to create token",
+	MsgUserContextMissing:         "User data is missing",
+	MsgCheckEmailFailed:           "Failed to check email",
+	MsgEmailTaken:                 "A user with this email already exists",
+	MsgPasswordHashFailed:         "Failed to hash password",
+	MsgCreateUserFailed:           "Failed to create user",
+	MsgInvalidCredentials:         "Invalid credentials",
+	MsgRefreshTokenInvalid:        "Refresh token is invalid or already used, please log in again",
+	MsgTwoFactorFailed:            "Two-factor authentication error",
+	MsgTwoFactorEnabled:           "Two-factor authentication is already enabled",
+	MsgTwoFactorNotEnabled:        "Two-factor authentication is not enabled",
+	MsgTwoFactorCodeInvalid:       "Invalid or already used code",
+	MsgTwoFactorChallengeInvalid:  "Login attempt not found or expired, please log in again",
+	MsgTwoFactorAttemptsExceeded:  "Too many code attempts, please log in again",
+	MsgDummyRoleForbidden:         "Access denied: test tokens are not issued for this role",
+	MsgDummyTokenReadOnly:         "Access denied: test tokens are read-only",
+	MsgSessionRevoked:             "Session has been revoked, please sign in again",
+	MsgSessionCheckFailed:         "Failed to check session",
+	MsgUserCheckFailed:            "Failed to check user",
+	MsgUserDeactivated:            "User has been deactivated",
+	MsgDeactivateUserFailed:       "Failed to deactivate user",
+	MsgActivateUserFailed:         "Failed to activate user",
+	MsgDeactivateSelf:             "You cannot deactivate yourself",
+	MsgGetSessionsFailed:          "Failed to get sessions",
+	MsgSessionNotFound:            "Active session not found",
+	MsgRevokeSessionFailed:        "Failed to revoke session",
+	MsgOldPasswordInvalid:         "Current password is incorrect",
+	MsgChangePasswordFailed:       "Failed to change password",
+	MsgPasswordResetRequestFailed: "Failed to request password reset",
+	MsgResetTokenInvalid:          "Password reset token is invalid, expired or already used",
+	MsgPasswordResetFailed:        "Failed to reset password",
+	MsgPasswordResetSubject:       "PVZ service password reset",
+	MsgPasswordResetMessage:       "Password reset token. If you did not request a reset, ignore this email",
+	MsgUserNotFound:               "User not found",
+	MsgGetProfileFailed:           "Failed to get profile",
+	MsgUpdateProfileFailed:        "Failed to update profile",
+	MsgProfileUpdateEmpty:         "No profile fields provided",
+	MsgInvalidAvatar:              "Invalid avatar file: JPEG, PNG or WebP up to 5 MB is expected",
+	MsgAvatarStorageDisabled:      "Avatar upload is not configured, pass a link in avatarUrl",
+	MsgAvatarUploadFailed:         "Failed to upload avatar",
+
+	MsgInvalidPhone:        "Invalid phone number",
+	MsgPhoneTaken:          "A user with this phone already exists",
+	MsgCheckPhoneFailed:    "Failed to check phone",
+	MsgOTPTooManyRequests:  "Too many code requests, try again later",
+	MsgOTPCreateFailed:     "Failed to create code",
+	MsgOTPSendFailed:       "Failed to send SMS",
+	MsgOTPInvalid:          "Invalid or expired code",
+	MsgOTPAttemptsExceeded: "Too many attempts, request a new code",
+	MsgOTPVerifyFailed:     "Failed to verify code",
+	MsgOTPMessage:          "PVZ login code",
+
+	MsgRateLimited:                  "Too many requests, try again later",
+	MsgGetRateLimitsFailed:          "Failed to get rate limits",
+	MsgSetRateLimitFailed:           "Failed to save rate limit",
+	MsgDeleteRateLimitFailed:        "Failed to delete rate limit",
+	MsgRateLimitNotFound:            "Rate limit override not found",
+	MsgGetDeliveriesFailed:          "Failed to get failed deliveries",
+	MsgDeliveryNotFound:             "Delivery not found",
+	MsgRequeueDeliveryFailed:        "Failed to requeue delivery",
+	MsgGetUsageFailed:               "Failed to get API usage statistics",
+	MsgSyncFailed:                   "Failed to get changes for sync",
+	MsgInvalidSyncCursor:            "Invalid sync cursor: use the cursor from the previous response or an RFC 3339 time",
+	MsgQuotaExceeded:                "Operation quota exceeded, try again later",
+	MsgGetQuotasFailed:              "Failed to get quotas",
+	MsgSetQuotaFailed:               "Failed to set quota",
+	MsgDeleteQuotaFailed:            "Failed to delete quota",
+	MsgQuotaNotFound:                "Quota not found",
+	MsgQuotaOverrideNotFound:        "Quota override not found",
+	MsgRestoreReceptionFailed:       "Failed to restore reception from archive",
+	MsgArchivedReceptionNotFound:    "Reception not found in archive",
+	MsgArchiveRestoreConflict:       "Reception cannot be restored: its PVZ, supplier or order has been deleted",
+	MsgGetLoginAuditFailed:          "Failed to get login audit",
+	MsgMarkDamagedFailed:            "Failed to mark product as damaged",
+	MsgProductAlreadyDamaged:        "Product is already marked as damaged",
+	MsgCloseConfirmationRequired:    "Reception differs from the manifest: pass force=true and a reason to close it",
+	MsgForceReasonRequired:          "A reason is required to close a reception with discrepancies",
+	MsgPVZFull:                      "Pickup point is full: confirm the intake with overrideCapacity",
+	MsgUpdatePVZCapacityFailed:      "Failed to update pickup point capacity",
+	MsgPVZBusy:                      "Another operation on this PVZ is in progress, please retry later",
+	MsgReportReadySubject:           "Report is ready",
+	MsgReportReadyMessage:           "The report has been generated, download it using the link, which is valid for a limited time",
+	MsgReportDefinitionNotFound:     "Saved report not found",
+	MsgGetReportDefinitionsFailed:   "Failed to get saved reports",
+	MsgSaveReportDefinitionFailed:   "Failed to save report",
+	MsgDeleteReportDefinitionFailed: "Failed to delete saved report",
+	MsgUnsupportedReportField:       "Grouping or filter is not available for the report entity",
+	MsgRunReportFailed:              "Failed to run report",
+	MsgExportEventsFailed:           "Failed to export events",
+	MsgGetFeatureFlagsFailed:        "Failed to get feature flags",
+	MsgUpdateFeatureFlagFailed:      "Failed to update feature flag",
+	MsgInvalidFeatureFlagName:       "Invalid flag name: lowercase latin letters, digits and _ expected, up to 64 characters",
+	MsgFeatureDisabled:              "Feature is not available",
+
+	MsgForbidden:                "Access denied: insufficient permissions",
+	MsgPVZAccessDenied:          "Access denied: employee is not assigned to this PVZ",
+	MsgForbiddenCreatePVZ:       "Access denied: only moderators can create PVZ",
+	MsgForbiddenCreateReception: "Access denied: only employees can create receptions",
+	MsgForbiddenPauseReception:  "Access denied: only employees can pause and resume receptions",
+	MsgForbiddenAddProduct:      "Access denied: only employees can add products",
+	MsgForbiddenDeleteProduct:   "Access denied: only employees can delete products",
+	MsgForbiddenIssueProduct:    "Access denied: only employees can issue products",
+	MsgForbiddenCreateOrder:     "Access denied: only employees can create orders",
+	MsgForbiddenIssueOrder:      "Access denied: only employees can issue orders",
+	MsgForbiddenUpdateReception: "Access denied: only employees can edit reception notes and tags",
+
+	MsgUnknownCity:           "Invalid request: unknown city",
+	MsgCreatePVZFailed:       "Failed to create PVZ",
+	MsgGetPVZListFailed:      "Failed to get PVZ list",
+	MsgGetInactivePVZFailed:  "Failed to get inactive PVZ",
+	MsgActivePVZNotFound:     "Active PVZ not found",
+	MsgPVZNotFound:           "PVZ not found",
+	MsgDeactivatePVZFailed:   "Failed to deactivate PVZ",
+	MsgArchivePVZFailed:      "Failed to delete PVZ",
+	MsgPVZHasOpenReception:   "PVZ has an open reception: close it or retry with force=true",
+	MsgForbiddenPVZCity:      "Access denied: the PVZ is in a city not assigned to the moderator",
+	MsgCheckCityAccessFailed: "Failed to check moderator cities",
+	MsgImportFileMissing:     "Import file is missing",
+	MsgImportFileInvalid:     "Invalid import file: expected CSV with city and address columns",
+	MsgImportPVZFailed:       "Failed to import PVZ",
+	MsgPVZCityRequired:       "City is required",
+	MsgPVZAddressRequired:    "Address is required",
+	MsgPVZAddressTooLong:     "Address is longer than 255 characters",
+	MsgPVZAddressDuplicate:   "Address is repeated in the file",
+	MsgPVZAddressExists:      "A PVZ with this address already exists in this city",
+
+	MsgReceptionAlreadyOpen:       "This PVZ already has an open reception",
+	MsgCreateReceptionFailed:      "Failed to create reception",
+	MsgGetReceptionFailed:         "Failed to get reception",
+	MsgReceptionActFailed:         "Failed to generate reception act",
+	MsgCustodyFailed:              "Failed to build chain-of-custody document",
+	MsgGetReceptionsFailed:        "Failed to get receptions",
+	MsgGetOverdueReceptionsFailed: "Failed to get overdue receptions",
+	MsgCloseReceptionFailed:       "Failed to close reception",
+	MsgPauseReceptionFailed:       "Failed to pause reception",
+	MsgResumeReceptionFailed:      "Failed to resume reception",
+	MsgCloseStaleReceptionsFailed: "Failed to close stale receptions",
+	MsgMergeReceptionsFailed:      "Failed to merge receptions",
+	MsgMergeSameReception:         "A reception cannot be merged into itself",
+	MsgMergeReceptionsNotOpen:     "Only open receptions can be merged",
+	MsgMergeReceptionsPVZMismatch: "Receptions belong to different PVZ",
+	MsgReassignReceptionFailed:    "Failed to reassign reception to another PVZ",
+	MsgReassignSamePVZ:            "Reception already belongs to this PVZ",
+	MsgReassignTargetNotFound:     "Target PVZ not found or archived",
+	MsgReassignTargetOpen:         "Target PVZ already has an open reception: close it or pause the reception being moved",
+	MsgReassignReceptionHasOrders: "Reception products belong to PVZ orders and cannot be moved",
+	MsgInvalidStaleThreshold:      "Invalid olderThan parameter: a positive duration such as 24h is expected",
+	MsgReceptionNotFound:          "Reception not found",
+	MsgPublicLinkNotFound:         "Link is invalid or reception not found",
+	MsgReceptionClosed:            "Reception is already closed",
+	MsgReceptionPaused:            "Reception is paused, resume it to add products",
+	MsgReceptionFull:              "Reception is full: template capacity reached",
+	MsgOutsideWorkingHours:        "PVZ is closed: intake outside working hours is not allowed",
+	MsgCheckWorkingHoursFailed:    "Failed to check PVZ working hours",
+	MsgGetWorkingHoursFailed:      "Failed to get PVZ working hours",
+	MsgSetWorkingHoursFailed:      "Failed to update PVZ working hours",
+	MsgInvalidWorkingHours:        "Invalid schedule: duplicate weekday or opening time is not before closing time",
+	MsgNoOpenReception:            "No open reception for this PVZ",
+	MsgNoPausedReception:          "No paused reception for this PVZ",
+	MsgManifestCompareFailed:      "Failed to compare with manifest",
+	MsgManifestFileMissing:        "Manifest file is missing",
+	MsgManifestReadFailed:         "Failed to read manifest file",
+	MsgManifestUnsupportedFormat:  "Unsupported manifest format: expected .csv or .xlsx",
+	MsgManifestInvalid:            "Invalid manifest format",
+	MsgManifestSaveFailed:         "Failed to save manifest",
+	MsgManifestNotLoaded:          "No manifest has been imported for the reception",
+	MsgReceptionUpdateEmpty:       "Invalid request: provide a note or tags",
+	MsgUpdateReceptionFailed:      "Failed to update reception note and tags",
+	MsgGetReceptionNotesFailed:    "Failed to get reception note history",
+
+	MsgAddProductFailed:         "Failed to add product",
+	MsgDeleteProductFailed:      "Failed to delete product",
+	MsgUpdateProductTypeFailed:  "Failed to change product type",
+	MsgNoProductsToDelete:       "No products to delete in this reception",
+	MsgDeleteNotLastProduct:     "Employees can only delete the last added product",
+	MsgNotEnoughProducts:        "The reception has fewer products than requested to delete",
+	MsgGetProductsFailed:        "Failed to get products",
+	MsgGetProductStatusFailed:   "Failed to get product statuses",
+	MsgProductNotFound:          "Product not found",
+	MsgProductNotStored:         "The product cannot be issued: it is not stored at the PVZ",
+	MsgIssueProductFailed:       "Failed to issue product",
+	MsgGetInventoryFailed:       "Failed to get PVZ inventory",
+	MsgTooManyPhotos:            "Too many photos: at most 10 per product",
+	MsgInvalidPhoto:             "Invalid photo file: expected JPEG, PNG or WebP up to 10 MB",
+	MsgPhotoStorageDisabled:     "Photo upload is not configured, pass links in photoUrls",
+	MsgPhotoUploadFailed:        "Failed to upload photos",
+	MsgGetPhotosFailed:          "Failed to get product photos",
+	MsgFileLinkInvalid:          "File link is invalid or expired",
+	MsgFileNotFound:             "File not found",
+	MsgFileDownloadFailed:       "Failed to download file",
+	MsgProductTypeLimitExceeded: "Reception limit for this product type exceeded",
+	MsgGetProductLimitsFailed:   "Failed to get product quantity limits",
+	MsgSetProductLimitFailed:    "Failed to save product quantity limit",
+	MsgDeleteProductLimitFailed: "Failed to delete product quantity limit",
+	MsgProductLimitNotFound:     "No limit is set for this product type",
+	MsgGetTemplatesFailed:       "Failed to get reception templates",
+	MsgSaveTemplateFailed:       "Failed to save reception template",
+	MsgDeleteTemplateFailed:     "Failed to delete reception template",
+	MsgTemplateNotFound:         "Reception template not found",
+	MsgTemplateNameTaken:        "A reception template with this name already exists",
+	MsgGetSuppliersFailed:       "Failed to get suppliers",
+	MsgSaveSupplierFailed:       "Failed to save supplier",
+	MsgDeleteSupplierFailed:     "Failed to delete supplier",
+	MsgSupplierNotFound:         "Supplier not found",
+	MsgSupplierTaken:            "A supplier with this name or tax ID already exists",
+	MsgSupplierInUse:            "The supplier has receptions and cannot be deleted",
+
+	MsgOrderNumberTaken:         "An order with this number already exists",
+	MsgOrderProductsUnavailable: "Some products cannot be added to the order: they are not at the PVZ, already issued or belong to another order",
+	MsgCreateOrderFailed:        "Failed to create order",
+	MsgOrderNotFound:            "Order not found",
+	MsgGetOrderFailed:           "Failed to get order",
+	MsgOrderAlreadyIssued:       "Order has already been issued",
+	MsgOrderNotReady:            "Order cannot be issued: not all products are stored at the PVZ",
+	MsgIssueOrderFailed:         "Failed to issue order",
+
+	MsgInvalidReportWindow:    "Invalid window parameter: expected a positive duration, e.g. 24h",
+	MsgReportFailed:           "Failed to build report",
+	MsgProductAgingFailed:     "Failed to build product aging report",
+	MsgForecastFailed:         "Failed to build intake forecast",
+	MsgExportReceptionsFailed: "Failed to export receptions",
+	MsgReportStorageDisabled:  "Storage for background reports is not configured",
+	MsgReportJobNotFound:      "Report job not found",
+	MsgGetReportJobFailed:     "Failed to get report job",
+	MsgConsistencyCheckFailed: "Failed to check data consistency",
+}
@@ -0,0 +1,242 @@
+package i18n
+
+// kk - каталог сообщений на казахском языке
+var kk = map[Key]string{
+	MsgInvalidRequest:       "Қате сұраныс",
+	MsgRequestTooLarge:      "Сұраныс денесі тым үлкен",
+	MsgUnsupportedMediaType: "Сұраныс денесі тек application/json немесе multipart/form-data форматында қабылданады",
+	MsgInvalidQueryParams:   "Сұраныс параметрлері қате",
+	MsgPVZIDRequired:        "ПВЗ ID көрсетілмеген",
+	MsgInternalError:        "Сервердің ішкі қатесі",
+	MsgSearchFailed:         "Іздеу кезінде қате",
+
+	MsgTokenMissing:               "Авторизация токені жоқ",
+	MsgTokenMalformed:             "Токен пішімі қате",
+	MsgTokenInvalid:               "Токен жарамсыз",
+	MsgTokenGenerateFailed:        "Токен жасау кезінде қате",
+	MsgTokenCreateFailed:          "Токен құру кезінде қате",
+	MsgUserContextMissing:         "Пайдаланушы туралы деректер жоқ",
+	MsgCheckEmailFailed:           "Email тексеру кезінде қате",
+	MsgEmailTaken:                 "Мұндай email-мен пайдаланушы бар",
+	MsgPasswordHashFailed:         "Құпиясөзді хэштеу кезінде қате",
+	MsgCreateUserFailed:           "Пайдаланушыны құру кезінде қате",
+	MsgInvalidCredentials:         "Тіркелгі деректері қате",
+	MsgRefreshTokenInvalid:        "Refresh-токен жарамсыз немесе бұрын қолданылған, қайта кіріңіз",
+	MsgTwoFactorFailed:            "Екі факторлы аутентификация қатесі",
+	MsgTwoFactorEnabled:           "Екі факторлы аутентификация қосылып қойған",
+	MsgTwoFactorNotEnabled:        "Екі факторлы аутентификация қосылмаған",
+	MsgTwoFactorCodeInvalid:       "Код қате немесе пайдаланылған",
+	MsgTwoFactorChallengeInvalid:  "Кіру табылмады немесе мерзімі өтті, қайта кіріңіз",
+	MsgTwoFactorAttemptsExceeded:  "Код енгізу әрекеттерінің саны асып кетті, қайта кіріңіз",
+	MsgDummyRoleForbidden:         "Қолжетімділік жоқ: бұл рөл үшін тест токені берілмейді",
+	MsgDummyTokenReadOnly:         "Қолжетімділік жоқ: тест токені тек оқуға рұқсат береді",
+	MsgSessionRevoked:             "Сессия аяқталды, қайта кіріңіз",
+	MsgSessionCheckFailed:         "Сессияны тексеру кезінде қате",
+	MsgUserCheckFailed:            "Пайдаланушыны тексеру кезінде қате",
+	MsgUserDeactivated:            "Пайдаланушы өшірілген",
+	MsgDeactivateUserFailed:       "Пайдаланушыны өшіру кезінде қате",
+	MsgActivateUserFailed:         "Пайдаланушыны белсендіру кезінде қате",
+	MsgDeactivateSelf:             "Өзіңізді өшіру мүмкін емес",
+	MsgGetSessionsFailed:          "Сессияларды алу кезінде қате",
+	MsgSessionNotFound:            "Белсенді сессия табылмады",
+	MsgRevokeSessionFailed:        "Сессияны аяқтау кезінде қате",
+	MsgOldPasswordInvalid:         "Ағымдағы құпиясөз қате",
+	MsgChangePasswordFailed:       "Құпиясөзді ауыстыру кезінде қате",
+	MsgPasswordResetRequestFailed: "Құпиясөзді қалпына келтіруді сұрау кезінде қате",
+	MsgResetTokenInvalid:          "Құпиясөзді қалпына келтіру токені қате, мерзімі өткен немесе пайдаланылған",
+	MsgPasswordResetFailed:        "Құпиясөзді қалпына келтіру кезінде қате",
+	MsgPasswordResetSubject:       "ПВЗ сервисінде құпиясөзді қалпына келтіру",
+	MsgPasswordResetMessage:       "Құпиясөзді қалпына келтіру токені. Егер сіз қалпына келтіруді сұрамасаңыз, бұл хатты елемеңіз",
+	MsgUserNotFound:               "Пайдаланушы табылмады",
+	MsgGetProfileFailed:           "Профильді алу кезінде қате",
+	MsgUpdateProfileFailed:        "Профильді өзгерту кезінде қате",
+	MsgProfileUpdateEmpty:         "Профильдің бірде-бір өрісі берілмеген",
+	MsgInvalidAvatar:              "Аватар файлы қате: 5 МБ-қа дейінгі JPEG, PNG немесе WebP күтіледі",
+	MsgAvatarStorageDisabled:      "Аватар жүктеу бапталмаған, сілтемені avatarUrl арқылы беріңіз",
+	MsgAvatarUploadFailed:         "Аватарды жүктеу кезінде қате",
+
+	MsgInvalidPhone:        "Телефон нөмірі қате",
+	MsgPhoneTaken:          "Мұндай телефонмен пайдаланушы бар",
+	MsgCheckPhoneFailed:    "Телефонды тексеру кезінде қате",
+	MsgOTPTooManyRequests:  "Код сұраулары тым көп, кейінірек қайталаңыз",
+	MsgOTPCreateFailed:     "Кодты құру кезінде қате",
+	MsgOTPSendFailed:       "SMS жіберу кезінде қате",
+	MsgOTPInvalid:          "Код қате немесе мерзімі өткен",
+	MsgOTPAttemptsExceeded: "Код енгізу әрекеттері тым көп, жаңа код сұраңыз",
+	MsgOTPVerifyFailed:     "Кодты тексеру кезінде қате",
+	MsgOTPMessage:          "ПВЗ-ға кіру коды",
+
+	MsgRateLimited:                  "Сұраулар тым көп, кейінірек қайталаңыз",
+	MsgGetRateLimitsFailed:          "Сұрау лимиттерін алу кезінде қате",
+	MsgSetRateLimitFailed:           "Сұрау лимитін сақтау кезінде қате",
+	MsgDeleteRateLimitFailed:        "Сұрау лимитін жою кезінде қате",
+	MsgRateLimitNotFound:            "Жеке лимит табылмады",
+	MsgGetDeliveriesFailed:          "Сәтсіз жеткізулерді алу кезінде қате",
+	MsgDeliveryNotFound:             "Жеткізу табылмады",
+	MsgRequeueDeliveryFailed:        "Жеткізуді кезекке қайтару кезінде қате",
+	MsgGetUsageFailed:               "API пайдалану статистикасын алу кезінде қате",
+	MsgSyncFailed:                   "Синхрондау үшін өзгерістерді алу кезінде қате",
+	MsgInvalidSyncCursor:            "Синхрондау курсоры қате: алдыңғы жауаптағы курсорды немесе RFC 3339 форматындағы уақытты көрсетіңіз",
+	MsgQuotaExceeded:                "Операциялар квотасы асып кетті, кейінірек қайталаңыз",
+	MsgGetQuotasFailed:              "Квоталарды алу кезінде қате",
+	MsgSetQuotaFailed:               "Квотаны орнату кезінде қате",
+	MsgDeleteQuotaFailed:            "Квотаны жою кезінде қате",
+	MsgQuotaNotFound:                "Квота табылмады",
+	MsgQuotaOverrideNotFound:        "Квотадан ерекшелік табылмады",
+	MsgRestoreReceptionFailed:       "Қабылдауды мұрағаттан қалпына келтіру қатесі",
+	MsgArchivedReceptionNotFound:    "Қабылдау мұрағаттан табылмады",
+	MsgArchiveRestoreConflict:       "Қабылдауды қалпына келтіру мүмкін емес: оның ПВЗ, жеткізушісі немесе тапсырысы жойылған",
+	MsgGetLoginAuditFailed:          "Кіру журналын алу қатесі",
+	MsgMarkDamagedFailed:            "Тауарды бүлінген деп белгілеу мүмкін болмады",
+	MsgProductAlreadyDamaged:        "Тауар бүлінген деп бұрын белгіленген",
+	MsgCloseConfirmationRequired:    "Қабылдау жүкқұжатпен сәйкес келмейді: жабу үшін force=true және себебін көрсетіңіз",
+	MsgForceReasonRequired:          "Сәйкессіздіктері бар қабылдауды жабу үшін себебін көрсетіңіз",
+	MsgPVZFull:                      "ПВЗ толы: қабылдауды overrideCapacity параметрімен растаңыз",
+	MsgUpdatePVZCapacityFailed:      "ПВЗ сыйымдылығын өзгерту мүмкін болмады",
+	MsgPVZBusy:                      "Бұл ПВЗ-мен басқа операция орындалуда, кейінірек қайталаңыз",
+	MsgReportReadySubject:           "Есеп дайын",
+	MsgReportReadyMessage:           "Есеп дайындалды, оны сілтеме арқылы жүктеп алуға болады, сілтеме шектеулі уақыт жарамды",
+	MsgReportDefinitionNotFound:     "Сақталған есеп табылмады",
+	MsgGetReportDefinitionsFailed:   "Сақталған есептерді алу мүмкін болмады",
+	MsgSaveReportDefinitionFailed:   "Есепті сақтау мүмкін болмады",
+	MsgDeleteReportDefinitionFailed: "Сақталған есепті жою мүмкін болмады",
+	MsgUnsupportedReportField:       "Топтау немесе сүзгі есеп нысаны үшін қолжетімсіз",
+	MsgRunReportFailed:              "Есепті іске қосу мүмкін болмады",
+	MsgExportEventsFailed:           "Оқиғаларды шығару кезінде қате",
+	MsgGetFeatureFlagsFailed:        "Функция жалаушаларын алу кезінде қате",
+	MsgUpdateFeatureFlagFailed:      "Функция жалаушасын өзгерту кезінде қате",
+	MsgInvalidFeatureFlagName:       "Жалауша атауы қате: кіші латын әріптері, сандар және _, 64 таңбаға дейін күтіледі",
+	MsgFeatureDisabled:              "Функция қолжетімсіз",
+
+	MsgForbidden:                "Қолжетімділік жоқ: құқықтар жеткіліксіз",
+	MsgPVZAccessDenied:          "Қолжетімділік жоқ: қызметкер бұл ПВЗ-да жұмыс істемейді",
+	MsgForbiddenCreatePVZ:       "Қолжетімділік жоқ: ПВЗ-ны тек модераторлар құра алады",
+	MsgForbiddenCreateReception: "Қолжетімділік жоқ: қабылдауды тек қызметкерлер құра алады",
+	MsgForbiddenPauseReception:  "Қол жеткізу тыйым салынған: қабылдауды тек қызметкерлер тоқтата және жалғастыра алады",
+	MsgForbiddenAddProduct:      "Қолжетімділік жоқ: тауарды тек қызметкерлер қоса алады",
+	MsgForbiddenDeleteProduct:   "Қолжетімділік жоқ: тауарды тек қызметкерлер жоя алады",
+	MsgForbiddenIssueProduct:    "Қолжетімділік жоқ: тауарды тек қызметкерлер бере алады",
+	MsgForbiddenCreateOrder:     "Қолжетімділік жоқ: тапсырысты тек қызметкерлер құра алады",
+	MsgForbiddenIssueOrder:      "Қолжетімділік жоқ: тапсырысты тек қызметкерлер бере алады",
+	MsgForbiddenUpdateReception: "Қолжетімділік жоқ: қабылдаудың жазбалары мен тегтерін тек қызметкерлер өзгерте алады",
+
+	MsgUnknownCity:           "Қате сұраныс: белгісіз қала",
+	MsgCreatePVZFailed:       "ПВЗ құру кезінде қате",
+	MsgGetPVZListFailed:      "ПВЗ тізімін алу кезінде қате",
+	MsgGetInactivePVZFailed:  "Белсенді емес ПВЗ алу кезінде қате",
+	MsgActivePVZNotFound:     "Белсенді ПВЗ табылмады",
+	MsgPVZNotFound:           "ПВЗ табылмады",
+	MsgDeactivatePVZFailed:   "ПВЗ-ны өшіру кезінде қате",
+	MsgArchivePVZFailed:      "ПВЗ жою қатесі",
+	MsgPVZHasOpenReception:   "ПВЗ-да ашық қабылдау бар: оны жабыңыз немесе force=true арқылы қайталаңыз",
+	MsgForbiddenPVZCity:      "Қолжетімділік жоқ: ПВЗ модераторға тағайындалмаған қалада орналасқан",
+	MsgCheckCityAccessFailed: "Модератор қалаларын тексеру кезінде қате",
+	MsgImportFileMissing:     "Импорт файлы берілмеген",
+	MsgImportFileInvalid:     "Импорт файлының пішімі қате: city және address бағандары бар CSV күтіледі",
+	MsgImportPVZFailed:       "ПВЗ импорттау кезінде қате",
+	MsgPVZCityRequired:       "Қала көрсетілмеген",
+	MsgPVZAddressRequired:    "Мекенжай көрсетілмеген",
+	MsgPVZAddressTooLong:     "Мекенжай 255 таңбадан ұзын",
+	MsgPVZAddressDuplicate:   "Мекенжай файлда қайталанады",
+	MsgPVZAddressExists:      "Бұл қалада мұндай мекенжайы бар ПВЗ бар",
+
+	MsgReceptionAlreadyOpen:       "Бұл ПВЗ-да жабылмаған қабылдау бар",
+	MsgCreateReceptionFailed:      "Қабылдауды құру кезінде қате",
+	MsgGetReceptionFailed:         "Қабылдауды алу кезінде қате",
+	MsgReceptionActFailed:         "Қабылдау актісін қалыптастыру кезінде қате",
+	MsgCustodyFailed:              "Сақтау тізбегі туралы құжатты құру кезінде қате",
+	MsgGetReceptionsFailed:        "Қабылдауларды алу кезінде қате",
+	MsgGetOverdueReceptionsFailed: "Мерзімі өткен қабылдауларды алу кезінде қате",
+	MsgCloseReceptionFailed:       "Қабылдауды жабу кезінде қате",
+	MsgPauseReceptionFailed:       "Қабылдауды тоқтату кезінде қате",
+	MsgResumeReceptionFailed:      "Қабылдауды жалғастыру кезінде қате",
+	MsgCloseStaleReceptionsFailed: "Ілініп қалған қабылдауларды жабу кезінде қате",
+	MsgMergeReceptionsFailed:      "Қабылдауларды біріктіру кезінде қате",
+	MsgMergeSameReception:         "Қабылдауды өзімен біріктіруге болмайды",
+	MsgMergeReceptionsNotOpen:     "Тек ашық қабылдауларды біріктіруге болады",
+	MsgMergeReceptionsPVZMismatch: "Қабылдаулар әртүрлі ПВЗ-ға жатады",
+	MsgReassignReceptionFailed:    "Қабылдауды басқа ПВЗ-ға ауыстыру кезінде қате",
+	MsgReassignSamePVZ:            "Қабылдау осы ПВЗ-ға тиесілі",
+	MsgReassignTargetNotFound:     "Мақсатты ПВЗ табылмады немесе жойылған",
+	MsgReassignTargetOpen:         "Мақсатты ПВЗ-да ашық қабылдау бар: оны жабыңыз немесе ауыстырылатын қабылдауды тоқтатыңыз",
+	MsgReassignReceptionHasOrders: "Қабылдау тауарлары ПВЗ тапсырыстарына кіреді, ауыстыру мүмкін емес",
+	MsgInvalidStaleThreshold:      "olderThan параметрі қате: оң ұзақтық күтіледі, мысалы 24h",
+	MsgReceptionNotFound:          "Қабылдау табылмады",
+	MsgPublicLinkNotFound:         "Сілтеме жарамсыз немесе қабылдау табылмады",
+	MsgReceptionClosed:            "Қабылдау жабылған",
+	MsgReceptionPaused:            "Қабылдау тоқтатылған, тауар қосу үшін оны жалғастырыңыз",
+	MsgReceptionFull:              "Қабылдау толы: үлгідегі сыйымдылыққа жетті",
+	MsgOutsideWorkingHours:        "ПВЗ қазір жабық: жұмыс уақытынан тыс тауар қабылдауға тыйым салынған",
+	MsgCheckWorkingHoursFailed:    "ПВЗ жұмыс уақытын тексеру кезінде қате",
+	MsgGetWorkingHoursFailed:      "ПВЗ жұмыс уақытын алу кезінде қате",
+	MsgSetWorkingHoursFailed:      "ПВЗ жұмыс уақытын өзгерту кезінде қате",
+	MsgInvalidWorkingHours:        "Кесте қате: апта күні екі рет көрсетілген немесе ашылу уақыты жабылу уақытынан ерте емес",
+	MsgNoOpenReception:            "Бұл ПВЗ үшін белсенді қабылдау жоқ",
+	MsgNoPausedReception:          "Осы ПВЗ үшін тоқтатылған қабылдау жоқ",
+	MsgManifestCompareFailed:      "Жүкқұжатпен салыстыру кезінде қате",
+	MsgManifestFileMissing:        "Жүкқұжат файлы берілмеген",
+	MsgManifestReadFailed:         "Жүкқұжат файлын оқу мүмкін болмады",
+	MsgManifestUnsupportedFormat:  "Жүкқұжат пішімі қолдау көрсетілмейді: .csv немесе .xlsx күтіледі",
+	MsgManifestInvalid:            "Жүкқұжат пішімі қате",
+	MsgManifestSaveFailed:         "Жүкқұжатты сақтау кезінде қате",
+	MsgManifestNotLoaded:          "Қабылдау үшін жүкқұжат жүктелмеген",
+	MsgReceptionUpdateEmpty:       "Қате сұраныс: жазбаны немесе тегтерді көрсетіңіз",
+	MsgUpdateReceptionFailed:      "Қабылдаудың жазбасы мен тегтерін өзгерту кезінде қате",
+	MsgGetReceptionNotesFailed:    "Қабылдау жазбаларының тарихын алу кезінде қате",
+
+	MsgAddProductFailed:         "Тауарды қосу кезінде қате",
+	MsgDeleteProductFailed:      "Тауарды жою кезінде қате",
+	MsgUpdateProductTypeFailed:  "Тауар түрін түзету кезінде қате",
+	MsgNoProductsToDelete:       "Бұл қабылдауда жоятын тауар жоқ",
+	MsgDeleteNotLastProduct:     "Қызметкер тек соңғы қосылған тауарды жоя алады",
+	MsgNotEnoughProducts:        "Қабылдауда жоюға сұралғаннан аз тауар бар",
+	MsgGetProductsFailed:        "Тауарларды алу кезінде қате",
+	MsgGetProductStatusFailed:   "Тауар мәртебелерін алу кезінде қате",
+	MsgProductNotFound:          "Тауар табылмады",
+	MsgProductNotStored:         "Тауарды беру мүмкін емес: ол ПВЗ-да сақталмаған",
+	MsgIssueProductFailed:       "Тауарды беру кезінде қате",
+	MsgGetInventoryFailed:       "ПВЗ қалдықтарын алу кезінде қате",
+	MsgTooManyPhotos:            "Фотосуреттер тым көп: бір тауарға 10-нан аспауы керек",
+	MsgInvalidPhoto:             "Фотосурет файлы қате: 10 МБ-қа дейінгі JPEG, PNG немесе WebP күтіледі",
+	MsgPhotoStorageDisabled:     "Фотосуреттерді жүктеу бапталмаған, сілтемелерді photoUrls арқылы беріңіз",
+	MsgPhotoUploadFailed:        "Фотосуреттерді жүктеу кезінде қате",
+	MsgGetPhotosFailed:          "Тауар фотосуреттерін алу кезінде қате",
+	MsgFileLinkInvalid:          "Файл сілтемесі жарамсыз немесе мерзімі өткен",
+	MsgFileNotFound:             "Файл табылмады",
+	MsgFileDownloadFailed:       "Файлды жүктеп алу кезінде қате",
+	MsgProductTypeLimitExceeded: "Қабылдаудағы осы түрдегі тауар санының шегінен асты",
+	MsgGetProductLimitsFailed:   "Тауар саны шектерін алу кезінде қате",
+	MsgSetProductLimitFailed:    "Тауар санының шегін сақтау кезінде қате",
+	MsgDeleteProductLimitFailed: "Тауар санының шегін жою кезінде қате",
+	MsgProductLimitNotFound:     "Бұл тауар түрі үшін шек белгіленбеген",
+	MsgGetTemplatesFailed:       "Қабылдау үлгілерін алу қатесі",
+	MsgSaveTemplateFailed:       "Қабылдау үлгісін сақтау қатесі",
+	MsgDeleteTemplateFailed:     "Қабылдау үлгісін жою қатесі",
+	MsgTemplateNotFound:         "Қабылдау үлгісі табылмады",
+	MsgTemplateNameTaken:        "Мұндай атаумен қабылдау үлгісі бар",
+	MsgGetSuppliersFailed:       "Жеткізушілерді алу қатесі",
+	MsgSaveSupplierFailed:       "Жеткізушіні сақтау қатесі",
+	MsgDeleteSupplierFailed:     "Жеткізушіні жою қатесі",
+	MsgSupplierNotFound:         "Жеткізуші табылмады",
+	MsgSupplierTaken:            "Мұндай атаумен немесе ЖСН-мен жеткізуші бар",
+	MsgSupplierInUse:            "Жеткізушінің қабылдаулары бар, оны жоюға болмайды",
+
+	MsgOrderNumberTaken:         "Мұндай нөмірлі тапсырыс бар",
+	MsgOrderProductsUnavailable: "Кейбір тауарларды тапсырысқа қосу мүмкін емес: олар ПВЗ-да жоқ, берілген немесе басқа тапсырысқа кіреді",
+	MsgCreateOrderFailed:        "Тапсырысты құру кезінде қате",
+	MsgOrderNotFound:            "Тапсырыс табылмады",
+	MsgGetOrderFailed:           "Тапсырысты алу кезінде қате",
+	MsgOrderAlreadyIssued:       "Тапсырыс берілген",
+	MsgOrderNotReady:            "Тапсырысты беру мүмкін емес: барлық тауар ПВЗ-да сақталмаған",
+	MsgIssueOrderFailed:         "Тапсырысты беру кезінде қате",
+
+	MsgInvalidReportWindow:    "window параметрі қате: оң ұзақтық күтіледі, мысалы 24h",
+	MsgReportFailed:           "Есепті құру кезінде қате",
+	MsgProductAgingFailed:     "Ұзақ сақталған тауарлар есебін құру кезінде қате",
+	MsgForecastFailed:         "Тауар түсуінің болжамын құру кезінде қате",
+	MsgExportReceptionsFailed: "Қабылдауларды түсіру кезінде қате",
+	MsgReportStorageDisabled:  "Фондық есептерге арналған қойма бапталмаған",
+	MsgReportJobNotFound:      "Есеп тапсырмасы табылмады",
+	MsgGetReportJobFailed:     "Есеп тапсырмасын алу кезінде қате",
+	MsgConsistencyCheckFailed: "Деректердің келісімділігін тексеру кезінде қате",
+}
@@ -0,0 +1,242 @@
+package i18n
+
+// ru - каталог сообщений на русском языке, используется как резервный
+var ru = map[Key]string{
+	MsgInvalidRequest:       "Неверный запрос",
+	MsgRequestTooLarge:      "Тело запроса слишком большое",
+	MsgUnsupportedMediaType: "Тело запроса принимается только в формате application/json или multipart/form-data",
+	MsgInvalidQueryParams:   "Неверные параметры запроса",
+	MsgPVZIDRequired:        "Не указан ID ПВЗ",
+	MsgInternalError:        "Внутренняя ошибка сервера",
+	MsgSearchFailed:         "Ошибка при поиске",
+
+	MsgTokenMissing:               "Отсутствует токен авторизации",
+	MsgTokenMalformed:             "Неверный формат токена",
+	MsgTokenInvalid:               "Неверный токен",
+	MsgTokenGenerateFailed:        "Ошибка генерации токена",
+	MsgTokenCreateFailed:          "Ошибка при создании токена",
+	MsgUserContextMissing:         "Нет данных о пользователе",
+	MsgCheckEmailFailed:           "Ошибка при проверке email",
+	MsgEmailTaken:                 "Пользователь с таким email уже существует",
+	MsgPasswordHashFailed:         "Ошибка при хешировании пароля",
+	MsgCreateUserFailed:           "Ошибка при создании пользователя",
+	MsgInvalidCredentials:         "Неверные учетные данные",
+	MsgRefreshTokenInvalid:        "Refresh-токен недействителен или уже использован, войдите заново",
+	MsgTwoFactorFailed:            "Ошибка двухфакторной аутентификации",
+	MsgTwoFactorEnabled:           "Двухфакторная аутентификация уже включена",
+	MsgTwoFactorNotEnabled:        "Двухфакторная аутентификация не включена",
+	MsgTwoFactorCodeInvalid:       "Неверный или уже использованный код",
+	MsgTwoFactorChallengeInvalid:  "Вход не найден или истек, войдите заново",
+	MsgTwoFactorAttemptsExceeded:  "Превышено число попыток ввода кода, войдите заново",
+	MsgDummyRoleForbidden:         "Доступ запрещен: тестовый токен для этой роли не выдается",
+	MsgDummyTokenReadOnly:         "Доступ запрещен: тестовый токен дает доступ только на чтение",
+	MsgSessionRevoked:             "Сессия завершена, войдите заново",
+	MsgSessionCheckFailed:         "Ошибка при проверке сессии",
+	MsgUserCheckFailed:            "Ошибка при проверке пользователя",
+	MsgUserDeactivated:            "Пользователь деактивирован",
+	MsgDeactivateUserFailed:       "Ошибка при деактивации пользователя",
+	MsgActivateUserFailed:         "Ошибка при активации пользователя",
+	MsgDeactivateSelf:             "Нельзя деактивировать самого себя",
+	MsgGetSessionsFailed:          "Ошибка при получении сессий",
+	MsgSessionNotFound:            "Активная сессия не найдена",
+	MsgRevokeSessionFailed:        "Ошибка при завершении сессии",
+	MsgOldPasswordInvalid:         "Неверный текущий пароль",
+	MsgChangePasswordFailed:       "Ошибка при смене пароля",
+	MsgPasswordResetRequestFailed: "Ошибка при запросе сброса пароля",
+	MsgResetTokenInvalid:          "Неверный, истекший или уже использованный токен сброса пароля",
+	MsgPasswordResetFailed:        "Ошибка при сбросе пароля",
+	MsgPasswordResetSubject:       "Сброс пароля в сервисе ПВЗ",
+	MsgPasswordResetMessage:       "Токен для сброса пароля. Если вы не запрашивали сброс, проигнорируйте это письмо",
+	MsgUserNotFound:               "Пользователь не найден",
+	MsgGetProfileFailed:           "Ошибка при получении профиля",
+	MsgUpdateProfileFailed:        "Ошибка при изменении профиля",
+	MsgProfileUpdateEmpty:         "Не передано ни одного поля профиля",
+	MsgInvalidAvatar:              "Неверный файл аватара: ожидается JPEG, PNG или WebP размером до 5 МБ",
+	MsgAvatarStorageDisabled:      "Загрузка аватаров не настроена, передайте ссылку в avatarUrl",
+	MsgAvatarUploadFailed:         "Ошибка при загрузке аватара",
+
+	MsgInvalidPhone:        "Неверный номер телефона",
+	MsgPhoneTaken:          "Пользователь с таким телефоном уже существует",
+	MsgCheckPhoneFailed:    "Ошибка при проверке телефона",
+	MsgOTPTooManyRequests:  "Слишком много запросов кода, попробуйте позже",
+	MsgOTPCreateFailed:     "Ошибка при создании кода",
+	MsgOTPSendFailed:       "Ошибка при отправке SMS",
+	MsgOTPInvalid:          "Неверный или истекший код",
+	MsgOTPAttemptsExceeded: "Превышено число попыток ввода кода, запросите новый",
+	MsgOTPVerifyFailed:     "Ошибка при проверке кода",
+	MsgOTPMessage:          "Код для входа в ПВЗ",
+
+	MsgRateLimited:                  "Слишком много запросов, попробуйте позже",
+	MsgGetRateLimitsFailed:          "Ошибка при получении лимитов запросов",
+	MsgSetRateLimitFailed:           "Ошибка при сохранении лимита запросов",
+	MsgDeleteRateLimitFailed:        "Ошибка при удалении лимита запросов",
+	MsgRateLimitNotFound:            "Индивидуальный лимит не найден",
+	MsgGetDeliveriesFailed:          "Ошибка при получении неудачных доставок",
+	MsgDeliveryNotFound:             "Доставка не найдена",
+	MsgRequeueDeliveryFailed:        "Ошибка при возврате доставки в очередь",
+	MsgGetUsageFailed:               "Ошибка при получении статистики использования API",
+	MsgSyncFailed:                   "Ошибка при получении изменений для синхронизации",
+	MsgInvalidSyncCursor:            "Неверный курсор синхронизации: укажите курсор из предыдущего ответа или время в формате RFC 3339",
+	MsgQuotaExceeded:                "Превышена квота операций, попробуйте позже",
+	MsgGetQuotasFailed:              "Ошибка при получении квот",
+	MsgSetQuotaFailed:               "Ошибка при установке квоты",
+	MsgDeleteQuotaFailed:            "Ошибка при удалении квоты",
+	MsgQuotaNotFound:                "Квота не найдена",
+	MsgQuotaOverrideNotFound:        "Исключение из квоты не найдено",
+	MsgRestoreReceptionFailed:       "Ошибка при восстановлении приёмки из архива",
+	MsgArchivedReceptionNotFound:    "Приёмка не найдена в архиве",
+	MsgArchiveRestoreConflict:       "Приёмку нельзя восстановить: ее ПВЗ, поставщик или заказ удалены",
+	MsgGetLoginAuditFailed:          "Ошибка при получении журнала входов",
+	MsgMarkDamagedFailed:            "Не удалось отметить товар поврежденным",
+	MsgProductAlreadyDamaged:        "Товар уже отмечен поврежденным",
+	MsgCloseConfirmationRequired:    "Приёмка расходится с накладной: для закрытия укажите force=true и причину",
+	MsgForceReasonRequired:          "Для закрытия приёмки с расхождениями укажите причину",
+	MsgPVZFull:                      "ПВЗ заполнен: товаров больше вместимости, подтвердите прием параметром overrideCapacity",
+	MsgUpdatePVZCapacityFailed:      "Не удалось изменить вместимость ПВЗ",
+	MsgPVZBusy:                      "Операция с ПВЗ уже выполняется другим запросом, повторите позже",
+	MsgReportReadySubject:           "Отчёт готов",
+	MsgReportReadyMessage:           "Отчёт сформирован, скачать его можно по ссылке, она действует ограниченное время",
+	MsgReportDefinitionNotFound:     "Сохраненный отчёт не найден",
+	MsgGetReportDefinitionsFailed:   "Не удалось получить сохраненные отчёты",
+	MsgSaveReportDefinitionFailed:   "Не удалось сохранить отчёт",
+	MsgDeleteReportDefinitionFailed: "Не удалось удалить сохраненный отчёт",
+	MsgUnsupportedReportField:       "Группировка или фильтр недоступны для выбранной сущности отчёта",
+	MsgRunReportFailed:              "Не удалось запустить отчёт",
+	MsgExportEventsFailed:           "Ошибка при выгрузке событий",
+	MsgGetFeatureFlagsFailed:        "Ошибка при получении флагов функций",
+	MsgUpdateFeatureFlagFailed:      "Ошибка при изменении флага функции",
+	MsgInvalidFeatureFlagName:       "Неверное имя флага: ожидаются строчные латинские буквы, цифры и _, до 64 символов",
+	MsgFeatureDisabled:              "Функция недоступна",
+
+	MsgForbidden:                "Доступ запрещен: недостаточно прав",
+	MsgPVZAccessDenied:          "Доступ запрещен: сотрудник не работает в этом ПВЗ",
+	MsgForbiddenCreatePVZ:       "Доступ запрещен: только модераторы могут создавать ПВЗ",
+	MsgForbiddenCreateReception: "Доступ запрещен: только сотрудники могут создавать приёмки",
+	MsgForbiddenPauseReception:  "Доступ запрещен: только сотрудники могут приостанавливать и возобновлять приёмки",
+	MsgForbiddenAddProduct:      "Доступ запрещен: только сотрудники могут добавлять товары",
+	MsgForbiddenDeleteProduct:   "Доступ запрещен: только сотрудники могут удалять товары",
+	MsgForbiddenIssueProduct:    "Доступ запрещен: только сотрудники могут выдавать товары",
+	MsgForbiddenCreateOrder:     "Доступ запрещен: только сотрудники могут создавать заказы",
+	MsgForbiddenIssueOrder:      "Доступ запрещен: только сотрудники могут выдавать заказы",
+	MsgForbiddenUpdateReception: "Доступ запрещен: только сотрудники могут изменять заметки и теги приёмок",
+
+	MsgUnknownCity:           "Неверный запрос: неизвестный город",
+	MsgCreatePVZFailed:       "Ошибка при создании ПВЗ",
+	MsgGetPVZListFailed:      "Ошибка при получении списка ПВЗ",
+	MsgGetInactivePVZFailed:  "Ошибка при получении неактивных ПВЗ",
+	MsgActivePVZNotFound:     "Активный ПВЗ не найден",
+	MsgPVZNotFound:           "ПВЗ не найден",
+	MsgDeactivatePVZFailed:   "Ошибка при деактивации ПВЗ",
+	MsgArchivePVZFailed:      "Ошибка при удалении ПВЗ",
+	MsgPVZHasOpenReception:   "В ПВЗ есть открытая приёмка: закройте её или повторите запрос с force=true",
+	MsgForbiddenPVZCity:      "Доступ запрещен: ПВЗ находится в городе, не назначенном модератору",
+	MsgCheckCityAccessFailed: "Ошибка при проверке городов модератора",
+	MsgImportFileMissing:     "Не передан файл импорта",
+	MsgImportFileInvalid:     "Неверный формат файла импорта: ожидается CSV с колонками city и address",
+	MsgImportPVZFailed:       "Ошибка при импорте ПВЗ",
+	MsgPVZCityRequired:       "Не указан город",
+	MsgPVZAddressRequired:    "Не указан адрес",
+	MsgPVZAddressTooLong:     "Адрес длиннее 255 символов",
+	MsgPVZAddressDuplicate:   "Адрес повторяется в файле",
+	MsgPVZAddressExists:      "ПВЗ с таким адресом в этом городе уже существует",
+
+	MsgReceptionAlreadyOpen:       "Для данного ПВЗ уже есть незакрытая приёмка",
+	MsgCreateReceptionFailed:      "Ошибка при создании приёмки",
+	MsgGetReceptionFailed:         "Ошибка при получении приёмки",
+	MsgReceptionActFailed:         "Ошибка при формировании акта приёмки",
+	MsgCustodyFailed:              "Ошибка при формировании документа о цепочке хранения",
+	MsgGetReceptionsFailed:        "Ошибка при получении приёмок",
+	MsgGetOverdueReceptionsFailed: "Ошибка при получении просроченных приёмок",
+	MsgCloseReceptionFailed:       "Ошибка при закрытии приёмки",
+	MsgPauseReceptionFailed:       "Ошибка при приостановке приёмки",
+	MsgResumeReceptionFailed:      "Ошибка при возобновлении приёмки",
+	MsgCloseStaleReceptionsFailed: "Ошибка при закрытии зависших приёмок",
+	MsgMergeReceptionsFailed:      "Ошибка при объединении приёмок",
+	MsgMergeSameReception:         "Приёмку нельзя объединить саму с собой",
+	MsgMergeReceptionsNotOpen:     "Объединять можно только открытые приёмки",
+	MsgMergeReceptionsPVZMismatch: "Приёмки относятся к разным ПВЗ",
+	MsgReassignReceptionFailed:    "Ошибка при переносе приёмки в другой ПВЗ",
+	MsgReassignSamePVZ:            "Приёмка уже относится к этому ПВЗ",
+	MsgReassignTargetNotFound:     "ПВЗ назначения не найден или удалён",
+	MsgReassignTargetOpen:         "В ПВЗ назначения уже есть открытая приёмка: закройте её или приостановите перенесённую",
+	MsgReassignReceptionHasOrders: "Товары приёмки входят в заказы ПВЗ, перенос невозможен",
+	MsgInvalidStaleThreshold:      "Неверный параметр olderThan: ожидается положительная длительность, например 24h",
+	MsgReceptionNotFound:          "Приёмка не найдена",
+	MsgPublicLinkNotFound:         "Ссылка недействительна или приёмка не найдена",
+	MsgReceptionClosed:            "Приёмка уже закрыта",
+	MsgReceptionPaused:            "Приёмка приостановлена, возобновите ее, чтобы добавлять товары",
+	MsgReceptionFull:              "Приёмка заполнена: достигнута вместимость из шаблона",
+	MsgOutsideWorkingHours:        "ПВЗ сейчас закрыт: приёмка товаров вне часов работы запрещена",
+	MsgCheckWorkingHoursFailed:    "Ошибка при проверке часов работы ПВЗ",
+	MsgGetWorkingHoursFailed:      "Ошибка при получении часов работы ПВЗ",
+	MsgSetWorkingHoursFailed:      "Ошибка при изменении часов работы ПВЗ",
+	MsgInvalidWorkingHours:        "Некорректное расписание: день недели указан дважды или время открытия не раньше времени закрытия",
+	MsgNoOpenReception:            "Нет активной приёмки для данного ПВЗ",
+	MsgNoPausedReception:          "Нет приостановленной приёмки для данного ПВЗ",
+	MsgManifestCompareFailed:      "Ошибка при сверке с накладной",
+	MsgManifestFileMissing:        "Не передан файл накладной",
+	MsgManifestReadFailed:         "Не удалось прочитать файл накладной",
+	MsgManifestUnsupportedFormat:  "Неподдерживаемый формат накладной: ожидается .csv или .xlsx",
+	MsgManifestInvalid:            "Неверный формат накладной",
+	MsgManifestSaveFailed:         "Ошибка при сохранении накладной",
+	MsgManifestNotLoaded:          "Накладная для приёмки не загружена",
+	MsgReceptionUpdateEmpty:       "Неверный запрос: укажите заметку или теги",
+	MsgUpdateReceptionFailed:      "Ошибка при изменении заметки и тегов приёмки",
+	MsgGetReceptionNotesFailed:    "Ошибка при получении истории заметок приёмки",
+
+	MsgAddProductFailed:         "Ошибка при добавлении товара",
+	MsgDeleteProductFailed:      "Ошибка при удалении товара",
+	MsgUpdateProductTypeFailed:  "Ошибка при исправлении типа товара",
+	MsgNoProductsToDelete:       "Нет товаров для удаления в данной приёмке",
+	MsgDeleteNotLastProduct:     "Сотрудник может удалить только последний добавленный товар",
+	MsgNotEnoughProducts:        "В приёмке меньше товаров, чем запрошено удалить",
+	MsgGetProductsFailed:        "Ошибка при получении товаров",
+	MsgGetProductStatusFailed:   "Ошибка при получении статусов товаров",
+	MsgProductNotFound:          "Товар не найден",
+	MsgProductNotStored:         "Товар нельзя выдать: он не находится на хранении в ПВЗ",
+	MsgIssueProductFailed:       "Ошибка при выдаче товара",
+	MsgGetInventoryFailed:       "Ошибка при получении остатков ПВЗ",
+	MsgTooManyPhotos:            "Слишком много фотографий: не более 10 на товар",
+	MsgInvalidPhoto:             "Неверный файл фотографии: ожидается JPEG, PNG или WebP размером до 10 МБ",
+	MsgPhotoStorageDisabled:     "Загрузка фотографий не настроена, передайте ссылки в photoUrls",
+	MsgPhotoUploadFailed:        "Ошибка при загрузке фотографий",
+	MsgGetPhotosFailed:          "Ошибка при получении фотографий товаров",
+	MsgFileLinkInvalid:          "Ссылка на файл недействительна или истекла",
+	MsgFileNotFound:             "Файл не найден",
+	MsgFileDownloadFailed:       "Ошибка при скачивании файла",
+	MsgProductTypeLimitExceeded: "Превышено ограничение на количество товаров этого типа в приёмке",
+	MsgGetProductLimitsFailed:   "Ошибка при получении ограничений на количество товаров",
+	MsgSetProductLimitFailed:    "Ошибка при сохранении ограничения на количество товаров",
+	MsgDeleteProductLimitFailed: "Ошибка при удалении ограничения на количество товаров",
+	MsgProductLimitNotFound:     "Ограничение для этого типа товара не задано",
+	MsgGetTemplatesFailed:       "Ошибка при получении шаблонов приёмок",
+	MsgSaveTemplateFailed:       "Ошибка при сохранении шаблона приёмки",
+	MsgDeleteTemplateFailed:     "Ошибка при удалении шаблона приёмки",
+	MsgTemplateNotFound:         "Шаблон приёмки не найден",
+	MsgTemplateNameTaken:        "Шаблон приёмки с таким названием уже существует",
+	MsgGetSuppliersFailed:       "Ошибка при получении поставщиков",
+	MsgSaveSupplierFailed:       "Ошибка при сохранении поставщика",
+	MsgDeleteSupplierFailed:     "Ошибка при удалении поставщика",
+	MsgSupplierNotFound:         "Поставщик не найден",
+	MsgSupplierTaken:            "Поставщик с таким названием или ИНН уже существует",
+	MsgSupplierInUse:            "У поставщика есть приёмки, его нельзя удалить",
+
+	MsgOrderNumberTaken:         "Заказ с таким номером уже существует",
+	MsgOrderProductsUnavailable: "Часть товаров нельзя включить в заказ: они не найдены в ПВЗ, уже выданы или входят в другой заказ",
+	MsgCreateOrderFailed:        "Ошибка при создании заказа",
+	MsgOrderNotFound:            "Заказ не найден",
+	MsgGetOrderFailed:           "Ошибка при получении заказа",
+	MsgOrderAlreadyIssued:       "Заказ уже выдан",
+	MsgOrderNotReady:            "Заказ нельзя выдать: не все товары находятся на хранении в ПВЗ",
+	MsgIssueOrderFailed:         "Ошибка при выдаче заказа",
+
+	MsgInvalidReportWindow:    "Неверный параметр window: ожидается положительная длительность, например 24h",
+	MsgReportFailed:           "Ошибка при построении отчёта",
+	MsgProductAgingFailed:     "Ошибка при построении отчёта о залежавшихся товарах",
+	MsgForecastFailed:         "Ошибка при построении прогноза поступления товаров",
+	MsgExportReceptionsFailed: "Ошибка при выгрузке приёмок",
+	MsgReportStorageDisabled:  "Хранилище для фоновых отчётов не настроено",
+	MsgReportJobNotFound:      "Задание отчёта не найдено",
+	MsgGetReportJobFailed:     "Ошибка при получении задания отчёта",
+	MsgConsistencyCheckFailed: "Ошибка при проверке согласованности данных",
+}
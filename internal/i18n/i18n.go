@@ -0,0 +1,111 @@
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Поддерживаемые локали
+const (
+	LocaleRU = "ru"
+	LocaleEN = "en"
+	LocaleKK = "kk"
+)
+
+// DefaultLocale используется, если локаль не определена и не задана в конфигурации
+const DefaultLocale = LocaleRU
+
+// ContextKey - ключ, под которым middleware сохраняет локаль запроса в контексте
+const ContextKey = "locale"
+
+// Key - идентификатор сообщения в каталоге
+type Key string
+
+// catalogs содержит каталоги сообщений по локалям
+var catalogs = map[string]map[Key]string{
+	LocaleRU: ru,
+	LocaleEN: en,
+	LocaleKK: kk,
+}
+
+// IsSupported сообщает, есть ли каталог для локали
+func IsSupported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// Translate возвращает сообщение в указанной локали.
+// При отсутствии перевода используется русский каталог, затем сам ключ
+func Translate(locale string, key Key) string {
+	if message, ok := catalogs[locale][key]; ok {
+		return message
+	}
+	if message, ok := catalogs[DefaultLocale][key]; ok {
+		return message
+	}
+	return string(key)
+}
+
+// FromContext возвращает локаль запроса, сохраненную middleware
+func FromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(ContextKey).(string); ok && locale != "" {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// T возвращает сообщение в локали запроса
+func T(ctx context.Context, key Key) string {
+	return Translate(FromContext(ctx), key)
+}
+
+// Wrap возвращает сообщение в локали запроса, дополненное текстом ошибки
+func Wrap(ctx context.Context, key Key, err error) string {
+	return T(ctx, key) + ": " + err.Error()
+}
+
+// Negotiate выбирает локаль по заголовку Accept-Language с учетом весов q.
+// Если ни одна из запрошенных локалей не поддерживается, возвращается fallback
+func Negotiate(acceptLanguage, fallback string) string {
+	type candidate struct {
+		locale string
+		weight float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+		if weight <= 0 {
+			continue
+		}
+
+		// Учитываем только основной подтег: kk-KZ -> kk
+		primary, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if IsSupported(primary) {
+			candidates = append(candidates, candidate{locale: primary, weight: weight})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return fallback
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].weight > candidates[j].weight
+	})
+	return candidates[0].locale
+}
@@ -0,0 +1,54 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogsAreComplete(t *testing.T) {
+	for locale, catalog := range catalogs {
+		for key := range ru {
+			assert.NotEmpty(t, catalog[key], "locale %s has no message for %s", locale, key)
+		}
+		assert.Len(t, catalog, len(ru), "locale %s has extra keys", locale)
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		fallback string
+		expected string
+	}{
+		{"Пустой заголовок", "", LocaleRU, LocaleRU},
+		{"Точное совпадение", "en", LocaleRU, LocaleEN},
+		{"Региональный подтег", "kk-KZ", LocaleRU, LocaleKK},
+		{"Выбор по весу", "en;q=0.5, kk;q=0.9", LocaleRU, LocaleKK},
+		{"Неподдерживаемые пропускаются", "de-DE, fr;q=0.9, en;q=0.1", LocaleRU, LocaleEN},
+		{"Нулевой вес исключает локаль", "en;q=0", LocaleKK, LocaleKK},
+		{"Ни одной поддерживаемой", "de, fr", LocaleEN, LocaleEN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Negotiate(tt.header, tt.fallback))
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	assert.Equal(t, "Reception not found", Translate(LocaleEN, MsgReceptionNotFound))
+	assert.Equal(t, "Приёмка не найдена", Translate("de", MsgReceptionNotFound))
+	assert.Equal(t, "unknown_key", Translate(LocaleEN, Key("unknown_key")))
+}
+
+func TestWrap(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ContextKey, LocaleEN)
+	assert.Equal(t, "Invalid request: bad json", Wrap(ctx, MsgInvalidRequest, errors.New("bad json")))
+
+	assert.Equal(t, "Неверный запрос: bad json", Wrap(context.Background(), MsgInvalidRequest, errors.New("bad json")))
+}
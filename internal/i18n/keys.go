@@ -0,0 +1,269 @@
+package i18n
+
+// Общие сообщения
+const (
+	MsgInvalidRequest       Key = "invalid_request"
+	MsgRequestTooLarge      Key = "request_too_large"
+	MsgUnsupportedMediaType Key = "unsupported_media_type"
+	MsgInvalidQueryParams   Key = "invalid_query_params"
+	MsgPVZIDRequired        Key = "pvz_id_required"
+	MsgInternalError        Key = "internal_error"
+	MsgSearchFailed         Key = "search_failed"
+)
+
+// Авторизация и пользователи
+const (
+	MsgTokenMissing               Key = "token_missing"
+	MsgTokenMalformed             Key = "token_malformed"
+	MsgTokenInvalid               Key = "token_invalid"
+	MsgTokenGenerateFailed        Key = "token_generate_failed"
+	MsgTokenCreateFailed          Key = "token_create_failed"
+	MsgUserContextMissing         Key = "user_context_missing"
+	MsgCheckEmailFailed           Key = "check_email_failed"
+	MsgEmailTaken                 Key = "email_taken"
+	MsgPasswordHashFailed         Key = "password_hash_failed"
+	MsgCreateUserFailed           Key = "create_user_failed"
+	MsgInvalidCredentials         Key = "invalid_credentials"
+	MsgRefreshTokenInvalid        Key = "refresh_token_invalid"
+	MsgTwoFactorFailed            Key = "two_factor_failed"
+	MsgTwoFactorEnabled           Key = "two_factor_enabled"
+	MsgTwoFactorNotEnabled        Key = "two_factor_not_enabled"
+	MsgTwoFactorCodeInvalid       Key = "two_factor_code_invalid"
+	MsgTwoFactorChallengeInvalid  Key = "two_factor_challenge_invalid"
+	MsgTwoFactorAttemptsExceeded  Key = "two_factor_attempts_exceeded"
+	MsgDummyRoleForbidden         Key = "dummy_role_forbidden"
+	MsgDummyTokenReadOnly         Key = "dummy_token_read_only"
+	MsgSessionRevoked             Key = "session_revoked"
+	MsgSessionCheckFailed         Key = "session_check_failed"
+	MsgUserCheckFailed            Key = "user_check_failed"
+	MsgUserDeactivated            Key = "user_deactivated"
+	MsgDeactivateUserFailed       Key = "deactivate_user_failed"
+	MsgActivateUserFailed         Key = "activate_user_failed"
+	MsgDeactivateSelf             Key = "deactivate_self"
+	MsgGetSessionsFailed          Key = "get_sessions_failed"
+	MsgSessionNotFound            Key = "session_not_found"
+	MsgRevokeSessionFailed        Key = "revoke_session_failed"
+	MsgOldPasswordInvalid         Key = "old_password_invalid"
+	MsgChangePasswordFailed       Key = "change_password_failed"
+	MsgPasswordResetRequestFailed Key = "password_reset_request_failed"
+	MsgResetTokenInvalid          Key = "reset_token_invalid"
+	MsgPasswordResetFailed        Key = "password_reset_failed"
+	MsgPasswordResetSubject       Key = "password_reset_subject"
+	MsgPasswordResetMessage       Key = "password_reset_message"
+	MsgUserNotFound               Key = "user_not_found"
+	MsgGetProfileFailed           Key = "get_profile_failed"
+	MsgUpdateProfileFailed        Key = "update_profile_failed"
+	MsgProfileUpdateEmpty         Key = "profile_update_empty"
+	MsgInvalidAvatar              Key = "invalid_avatar"
+	MsgAvatarStorageDisabled      Key = "avatar_storage_disabled"
+	MsgAvatarUploadFailed         Key = "avatar_upload_failed"
+)
+
+// Вход по телефону
+const (
+	MsgInvalidPhone        Key = "invalid_phone"
+	MsgPhoneTaken          Key = "phone_taken"
+	MsgCheckPhoneFailed    Key = "check_phone_failed"
+	MsgOTPTooManyRequests  Key = "otp_too_many_requests"
+	MsgOTPCreateFailed     Key = "otp_create_failed"
+	MsgOTPSendFailed       Key = "otp_send_failed"
+	MsgOTPInvalid          Key = "otp_invalid"
+	MsgOTPAttemptsExceeded Key = "otp_attempts_exceeded"
+	MsgOTPVerifyFailed     Key = "otp_verify_failed"
+	MsgOTPMessage          Key = "otp_message"
+)
+
+// Ограничение частоты запросов
+const (
+	MsgRateLimited                  Key = "rate_limited"
+	MsgGetRateLimitsFailed          Key = "get_rate_limits_failed"
+	MsgSetRateLimitFailed           Key = "set_rate_limit_failed"
+	MsgDeleteRateLimitFailed        Key = "delete_rate_limit_failed"
+	MsgRateLimitNotFound            Key = "rate_limit_not_found"
+	MsgGetDeliveriesFailed          Key = "get_deliveries_failed"
+	MsgDeliveryNotFound             Key = "delivery_not_found"
+	MsgRequeueDeliveryFailed        Key = "requeue_delivery_failed"
+	MsgGetUsageFailed               Key = "get_usage_failed"
+	MsgSyncFailed                   Key = "sync_failed"
+	MsgInvalidSyncCursor            Key = "invalid_sync_cursor"
+	MsgQuotaExceeded                Key = "quota_exceeded"
+	MsgGetQuotasFailed              Key = "get_quotas_failed"
+	MsgSetQuotaFailed               Key = "set_quota_failed"
+	MsgDeleteQuotaFailed            Key = "delete_quota_failed"
+	MsgQuotaNotFound                Key = "quota_not_found"
+	MsgQuotaOverrideNotFound        Key = "quota_override_not_found"
+	MsgRestoreReceptionFailed       Key = "restore_reception_failed"
+	MsgArchivedReceptionNotFound    Key = "archived_reception_not_found"
+	MsgArchiveRestoreConflict       Key = "archive_restore_conflict"
+	MsgGetLoginAuditFailed          Key = "get_login_audit_failed"
+	MsgMarkDamagedFailed            Key = "mark_damaged_failed"
+	MsgProductAlreadyDamaged        Key = "product_already_damaged"
+	MsgCloseConfirmationRequired    Key = "close_confirmation_required"
+	MsgForceReasonRequired          Key = "force_reason_required"
+	MsgPVZFull                      Key = "pvz_full"
+	MsgUpdatePVZCapacityFailed      Key = "update_pvz_capacity_failed"
+	MsgPVZBusy                      Key = "pvz_busy"
+	MsgReportReadySubject           Key = "report_ready_subject"
+	MsgReportReadyMessage           Key = "report_ready_message"
+	MsgReportDefinitionNotFound     Key = "report_definition_not_found"
+	MsgGetReportDefinitionsFailed   Key = "get_report_definitions_failed"
+	MsgSaveReportDefinitionFailed   Key = "save_report_definition_failed"
+	MsgDeleteReportDefinitionFailed Key = "delete_report_definition_failed"
+	MsgUnsupportedReportField       Key = "unsupported_report_field"
+	MsgRunReportFailed              Key = "run_report_failed"
+	MsgExportEventsFailed           Key = "export_events_failed"
+	MsgGetFeatureFlagsFailed        Key = "get_feature_flags_failed"
+	MsgUpdateFeatureFlagFailed      Key = "update_feature_flag_failed"
+	MsgInvalidFeatureFlagName       Key = "invalid_feature_flag_name"
+	MsgFeatureDisabled              Key = "feature_disabled"
+)
+
+// Доступ
+const (
+	MsgForbidden                Key = "forbidden"
+	MsgPVZAccessDenied          Key = "pvz_access_denied"
+	MsgForbiddenCreatePVZ       Key = "forbidden_create_pvz"
+	MsgForbiddenCreateReception Key = "forbidden_create_reception"
+	MsgForbiddenPauseReception  Key = "forbidden_pause_reception"
+	MsgForbiddenAddProduct      Key = "forbidden_add_product"
+	MsgForbiddenDeleteProduct   Key = "forbidden_delete_product"
+	MsgForbiddenIssueProduct    Key = "forbidden_issue_product"
+	MsgForbiddenCreateOrder     Key = "forbidden_create_order"
+	MsgForbiddenIssueOrder      Key = "forbidden_issue_order"
+	MsgForbiddenUpdateReception Key = "forbidden_update_reception"
+)
+
+// ПВЗ
+const (
+	MsgUnknownCity           Key = "unknown_city"
+	MsgCreatePVZFailed       Key = "create_pvz_failed"
+	MsgGetPVZListFailed      Key = "get_pvz_list_failed"
+	MsgGetInactivePVZFailed  Key = "get_inactive_pvz_failed"
+	MsgActivePVZNotFound     Key = "active_pvz_not_found"
+	MsgPVZNotFound           Key = "pvz_not_found"
+	MsgDeactivatePVZFailed   Key = "deactivate_pvz_failed"
+	MsgArchivePVZFailed      Key = "archive_pvz_failed"
+	MsgPVZHasOpenReception   Key = "pvz_has_open_reception"
+	MsgForbiddenPVZCity      Key = "forbidden_pvz_city"
+	MsgCheckCityAccessFailed Key = "check_city_access_failed"
+	MsgImportFileMissing     Key = "import_file_missing"
+	MsgImportFileInvalid     Key = "import_file_invalid"
+	MsgImportPVZFailed       Key = "import_pvz_failed"
+	MsgPVZCityRequired       Key = "pvz_city_required"
+	MsgPVZAddressRequired    Key = "pvz_address_required"
+	MsgPVZAddressTooLong     Key = "pvz_address_too_long"
+	MsgPVZAddressDuplicate   Key = "pvz_address_duplicate"
+	MsgPVZAddressExists      Key = "pvz_address_exists"
+)
+
+// Приёмки и накладные
+const (
+	MsgReceptionAlreadyOpen       Key = "reception_already_open"
+	MsgCreateReceptionFailed      Key = "create_reception_failed"
+	MsgGetReceptionFailed         Key = "get_reception_failed"
+	MsgReceptionActFailed         Key = "reception_act_failed"
+	MsgCustodyFailed              Key = "custody_failed"
+	MsgGetReceptionsFailed        Key = "get_receptions_failed"
+	MsgGetOverdueReceptionsFailed Key = "get_overdue_receptions_failed"
+	MsgCloseReceptionFailed       Key = "close_reception_failed"
+	MsgPauseReceptionFailed       Key = "pause_reception_failed"
+	MsgResumeReceptionFailed      Key = "resume_reception_failed"
+	MsgCloseStaleReceptionsFailed Key = "close_stale_receptions_failed"
+	MsgMergeReceptionsFailed      Key = "merge_receptions_failed"
+	MsgMergeSameReception         Key = "merge_same_reception"
+	MsgMergeReceptionsNotOpen     Key = "merge_receptions_not_open"
+	MsgMergeReceptionsPVZMismatch Key = "merge_receptions_pvz_mismatch"
+	MsgReassignReceptionFailed    Key = "reassign_reception_failed"
+	MsgReassignSamePVZ            Key = "reassign_same_pvz"
+	MsgReassignTargetNotFound     Key = "reassign_target_not_found"
+	MsgReassignTargetOpen         Key = "reassign_target_open"
+	MsgReassignReceptionHasOrders Key = "reassign_reception_has_orders"
+	MsgInvalidStaleThreshold      Key = "invalid_stale_threshold"
+	MsgReceptionNotFound          Key = "reception_not_found"
+	MsgPublicLinkNotFound         Key = "public_link_not_found"
+	MsgReceptionClosed            Key = "reception_closed"
+	MsgReceptionPaused            Key = "reception_paused"
+	MsgReceptionFull              Key = "reception_full"
+	MsgOutsideWorkingHours        Key = "outside_working_hours"
+	MsgCheckWorkingHoursFailed    Key = "check_working_hours_failed"
+	MsgGetWorkingHoursFailed      Key = "get_working_hours_failed"
+	MsgSetWorkingHoursFailed      Key = "set_working_hours_failed"
+	MsgInvalidWorkingHours        Key = "invalid_working_hours"
+	MsgNoOpenReception            Key = "no_open_reception"
+	MsgNoPausedReception          Key = "no_paused_reception"
+	MsgManifestCompareFailed      Key = "manifest_compare_failed"
+	MsgManifestFileMissing        Key = "manifest_file_missing"
+	MsgManifestReadFailed         Key = "manifest_read_failed"
+	MsgManifestUnsupportedFormat  Key = "manifest_unsupported_format"
+	MsgManifestInvalid            Key = "manifest_invalid"
+	MsgManifestSaveFailed         Key = "manifest_save_failed"
+	MsgManifestNotLoaded          Key = "manifest_not_loaded"
+	MsgReceptionUpdateEmpty       Key = "reception_update_empty"
+	MsgUpdateReceptionFailed      Key = "update_reception_failed"
+	MsgGetReceptionNotesFailed    Key = "get_reception_notes_failed"
+)
+
+// Товары
+const (
+	MsgAddProductFailed         Key = "add_product_failed"
+	MsgDeleteProductFailed      Key = "delete_product_failed"
+	MsgUpdateProductTypeFailed  Key = "update_product_type_failed"
+	MsgNoProductsToDelete       Key = "no_products_to_delete"
+	MsgDeleteNotLastProduct     Key = "delete_not_last_product"
+	MsgNotEnoughProducts        Key = "not_enough_products"
+	MsgGetProductsFailed        Key = "get_products_failed"
+	MsgGetProductStatusFailed   Key = "get_product_status_failed"
+	MsgProductNotFound          Key = "product_not_found"
+	MsgProductNotStored         Key = "product_not_stored"
+	MsgIssueProductFailed       Key = "issue_product_failed"
+	MsgGetInventoryFailed       Key = "get_inventory_failed"
+	MsgTooManyPhotos            Key = "too_many_photos"
+	MsgInvalidPhoto             Key = "invalid_photo"
+	MsgPhotoStorageDisabled     Key = "photo_storage_disabled"
+	MsgPhotoUploadFailed        Key = "photo_upload_failed"
+	MsgGetPhotosFailed          Key = "get_photos_failed"
+	MsgFileLinkInvalid          Key = "file_link_invalid"
+	MsgFileNotFound             Key = "file_not_found"
+	MsgFileDownloadFailed       Key = "file_download_failed"
+	MsgProductTypeLimitExceeded Key = "product_type_limit_exceeded"
+	MsgGetProductLimitsFailed   Key = "get_product_limits_failed"
+	MsgSetProductLimitFailed    Key = "set_product_limit_failed"
+	MsgDeleteProductLimitFailed Key = "delete_product_limit_failed"
+	MsgProductLimitNotFound     Key = "product_limit_not_found"
+	MsgGetTemplatesFailed       Key = "get_templates_failed"
+	MsgSaveTemplateFailed       Key = "save_template_failed"
+	MsgDeleteTemplateFailed     Key = "delete_template_failed"
+	MsgTemplateNotFound         Key = "template_not_found"
+	MsgTemplateNameTaken        Key = "template_name_taken"
+	MsgGetSuppliersFailed       Key = "get_suppliers_failed"
+	MsgSaveSupplierFailed       Key = "save_supplier_failed"
+	MsgDeleteSupplierFailed     Key = "delete_supplier_failed"
+	MsgSupplierNotFound         Key = "supplier_not_found"
+	MsgSupplierTaken            Key = "supplier_taken"
+	MsgSupplierInUse            Key = "supplier_in_use"
+)
+
+// Заказы покупателей
+const (
+	MsgOrderNumberTaken         Key = "order_number_taken"
+	MsgOrderProductsUnavailable Key = "order_products_unavailable"
+	MsgCreateOrderFailed        Key = "create_order_failed"
+	MsgOrderNotFound            Key = "order_not_found"
+	MsgGetOrderFailed           Key = "get_order_failed"
+	MsgOrderAlreadyIssued       Key = "order_already_issued"
+	MsgOrderNotReady            Key = "order_not_ready"
+	MsgIssueOrderFailed         Key = "issue_order_failed"
+)
+
+// Отчёты
+const (
+	MsgInvalidReportWindow    Key = "invalid_report_window"
+	MsgReportFailed           Key = "report_failed"
+	MsgProductAgingFailed     Key = "product_aging_failed"
+	MsgForecastFailed         Key = "forecast_failed"
+	MsgExportReceptionsFailed Key = "export_receptions_failed"
+	MsgReportStorageDisabled  Key = "report_storage_disabled"
+	MsgReportJobNotFound      Key = "report_job_not_found"
+	MsgGetReportJobFailed     Key = "get_report_job_failed"
+	MsgConsistencyCheckFailed Key = "consistency_check_failed"
+)
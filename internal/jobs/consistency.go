@@ -0,0 +1,58 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"pvz-service/internal/models"
+	"pvz-service/internal/service"
+)
+
+// ConsistencyJob периодически проверяет инварианты данных и пишет найденные нарушения в лог,
+// их количество по видам попадает в метрики
+type ConsistencyJob struct {
+	checker  *service.ConsistencyChecker
+	interval time.Duration
+}
+
+// NewConsistencyJob создает новый экземпляр ConsistencyJob
+func NewConsistencyJob(checker *service.ConsistencyChecker, interval time.Duration) *ConsistencyJob {
+	return &ConsistencyJob{
+		checker:  checker,
+		interval: interval,
+	}
+}
+
+// Run запускает задание и блокируется до отмены контекста
+func (j *ConsistencyJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.RunOnce(ctx); err != nil {
+			log.Printf("Consistency check job failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce выполняет одну проверку инвариантов
+func (j *ConsistencyJob) RunOnce(ctx context.Context) error {
+	report, err := j.checker.Check(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, kind := range models.InvariantKinds {
+		if count := report.Counts[kind]; count > 0 {
+			log.Printf("Consistency check found %d violations of %s", count, kind)
+		}
+	}
+	return nil
+}
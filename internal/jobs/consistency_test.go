@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/service"
+)
+
+// fakeReportQueries возвращает заданные нарушения инвариантов
+type fakeReportQueries struct {
+	queries.ReportQueriesInterface
+	violations []models.InvariantViolation
+	err        error
+}
+
+func (f *fakeReportQueries) GetInvariantViolations(ctx context.Context) ([]models.InvariantViolation, error) {
+	return f.violations, f.err
+}
+
+// TestConsistencyJobRunOnce проверяет проверку инвариантов с найденными нарушениями
+func TestConsistencyJobRunOnce(t *testing.T) {
+	fake := &fakeReportQueries{violations: []models.InvariantViolation{
+		{Kind: models.InvariantMultipleOpenReceptions, PvzID: "pvz1", ReceptionID: "r1"},
+	}}
+	job := NewConsistencyJob(service.NewConsistencyChecker(fake), time.Minute)
+
+	assert.NoError(t, job.RunOnce(context.Background()))
+}
+
+// TestConsistencyJobRunOnceError проверяет проброс ошибки запроса
+func TestConsistencyJobRunOnceError(t *testing.T) {
+	fake := &fakeReportQueries{err: errors.New("database error")}
+	job := NewConsistencyJob(service.NewConsistencyChecker(fake), time.Minute)
+
+	assert.Error(t, job.RunOnce(context.Background()))
+}
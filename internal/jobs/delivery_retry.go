@@ -0,0 +1,117 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/notify"
+)
+
+// maxRetryDelay ограничивает рост задержки между повторными попытками доставки
+const maxRetryDelay = 6 * time.Hour
+
+// DeliveryRetryJob повторяет неудачные исходящие доставки с экспоненциальной задержкой.
+// После maxAttempts попыток доставка переносится в dead-letter queue, откуда ее
+// возвращает администратор через POST /admin/deliveries/:deliveryId/requeue
+type DeliveryRetryJob struct {
+	deliveryQueries queries.DeliveryQueriesInterface
+	notifiers       map[string]notify.Notifier
+	interval        time.Duration
+	baseDelay       time.Duration
+	maxAttempts     int
+	batchSize       int
+	clock           clock.Clock
+}
+
+// NewDeliveryRetryJob создает новый экземпляр DeliveryRetryJob.
+// notifiers сопоставляет получателю доставки (models.DeliveryTarget*) его оповещатель
+func NewDeliveryRetryJob(deliveryQueries queries.DeliveryQueriesInterface, notifiers map[string]notify.Notifier, interval, baseDelay time.Duration, maxAttempts, batchSize int) *DeliveryRetryJob {
+	return &DeliveryRetryJob{
+		deliveryQueries: deliveryQueries,
+		notifiers:       notifiers,
+		interval:        interval,
+		baseDelay:       baseDelay,
+		maxAttempts:     maxAttempts,
+		batchSize:       batchSize,
+		clock:           clock.System{},
+	}
+}
+
+// Run запускает задание и блокируется до отмены контекста
+func (j *DeliveryRetryJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.RunOnce(ctx); err != nil {
+			log.Printf("Delivery retry job failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce повторяет доставки, время попытки которых наступило.
+// Ошибка одной доставки не мешает повторить остальные
+func (j *DeliveryRetryJob) RunOnce(ctx context.Context) error {
+	due, err := j.deliveryQueries.GetDueDeliveries(ctx, j.batchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range due {
+		if err := j.deliver(ctx, delivery); err != nil {
+			attempts := delivery.Attempts + 1
+			dead := attempts >= j.maxAttempts
+			nextAttemptAt := j.clock.Now().Add(retryDelay(j.baseDelay, attempts))
+
+			if recordErr := j.deliveryQueries.RecordDeliveryFailure(ctx, delivery.ID, err.Error(), nextAttemptAt, dead); recordErr != nil {
+				return recordErr
+			}
+			if dead {
+				log.Printf("Delivery %d to %s moved to dead-letter queue after %d attempts: %v", delivery.ID, delivery.Target, attempts, err)
+			}
+			continue
+		}
+
+		if err := j.deliveryQueries.DeleteDelivery(ctx, delivery.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deliver повторно отправляет сохраненное событие получателю доставки
+func (j *DeliveryRetryJob) deliver(ctx context.Context, delivery models.FailedDelivery) error {
+	notifier, ok := j.notifiers[delivery.Target]
+	if !ok {
+		return fmt.Errorf("unknown delivery target %q", delivery.Target)
+	}
+
+	var event models.Event
+	if err := json.Unmarshal(delivery.Event, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	return notifier.Notify(ctx, event)
+}
+
+// retryDelay вычисляет задержку перед следующей попыткой: base, 2*base, 4*base... не больше maxRetryDelay
+func retryDelay(base time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
@@ -0,0 +1,82 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/notify"
+)
+
+// fakeDeliveryQueries хранит неудачные доставки в памяти
+type fakeDeliveryQueries struct {
+	queries.DeliveryQueriesInterface
+	added   []models.Event
+	due     []models.FailedDelivery
+	deleted []int64
+	dead    []int64
+	delays  []time.Duration
+}
+
+func (f *fakeDeliveryQueries) AddFailedDelivery(ctx context.Context, target string, event models.Event, reason string) error {
+	f.added = append(f.added, event)
+	return nil
+}
+
+func (f *fakeDeliveryQueries) GetDueDeliveries(ctx context.Context, limit int) ([]models.FailedDelivery, error) {
+	return f.due, nil
+}
+
+func (f *fakeDeliveryQueries) RecordDeliveryFailure(ctx context.Context, id int64, reason string, nextAttemptAt time.Time, dead bool) error {
+	f.delays = append(f.delays, time.Until(nextAttemptAt).Round(time.Minute))
+	if dead {
+		f.dead = append(f.dead, id)
+	}
+	return nil
+}
+
+func (f *fakeDeliveryQueries) DeleteDelivery(ctx context.Context, id int64) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func newFailedDelivery(id int64, target string, attempts int) models.FailedDelivery {
+	body, _ := json.Marshal(models.Event{DedupID: "event-1", PvzID: "pvz-1", Type: models.EventReceptionOverdue})
+	return models.FailedDelivery{ID: id, Target: target, Event: body, Attempts: attempts}
+}
+
+// TestDeliveryRetryJobRunOnce проверяет повтор доставки, экспоненциальную задержку и перенос в dead-letter queue
+func TestDeliveryRetryJobRunOnce(t *testing.T) {
+	deliveries := &fakeDeliveryQueries{due: []models.FailedDelivery{
+		newFailedDelivery(1, models.DeliveryTargetAlerts, 1),
+		newFailedDelivery(2, models.DeliveryTargetBroker, 2),
+		newFailedDelivery(3, models.DeliveryTargetBroker, 4),
+	}}
+	alerts := &fakeNotifier{}
+	broker := &fakeNotifier{err: errors.New("broker unavailable")}
+	job := NewDeliveryRetryJob(deliveries, map[string]notify.Notifier{
+		models.DeliveryTargetAlerts: alerts,
+		models.DeliveryTargetBroker: broker,
+	}, time.Minute, time.Minute, 5, 100)
+
+	err := job.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1}, deliveries.deleted)
+	assert.Equal(t, "event-1", alerts.events[0].DedupID)
+	assert.Equal(t, []time.Duration{4 * time.Minute, 16 * time.Minute}, deliveries.delays)
+	assert.Equal(t, []int64{3}, deliveries.dead)
+}
+
+// TestRetryDelay проверяет рост задержки между попытками и ее ограничение
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Minute, retryDelay(time.Minute, 1))
+	assert.Equal(t, 8*time.Minute, retryDelay(time.Minute, 4))
+	assert.Equal(t, maxRetryDelay, retryDelay(time.Minute, 30))
+}
@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+)
+
+// InactivePVZJob периодически отмечает ПВЗ без активности за заданный период
+type InactivePVZJob struct {
+	pvzQueries queries.PVZQueriesInterface
+	threshold  time.Duration
+	interval   time.Duration
+	clock      clock.Clock
+}
+
+// NewInactivePVZJob создает новый экземпляр InactivePVZJob
+func NewInactivePVZJob(pvzQueries queries.PVZQueriesInterface, threshold, interval time.Duration) *InactivePVZJob {
+	return &InactivePVZJob{
+		pvzQueries: pvzQueries,
+		threshold:  threshold,
+		interval:   interval,
+		clock:      clock.System{},
+	}
+}
+
+// Run запускает задание и блокируется до отмены контекста
+func (j *InactivePVZJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.RunOnce(ctx); err != nil {
+			log.Printf("Inactive PVZ job failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce выполняет одну проверку неактивных ПВЗ
+func (j *InactivePVZJob) RunOnce(ctx context.Context) error {
+	flagged, err := j.pvzQueries.FlagInactivePVZ(ctx, j.clock.Now().Add(-j.threshold))
+	if err != nil {
+		return err
+	}
+
+	if flagged > 0 {
+		log.Printf("Inactive PVZ job flagged %d pvz", flagged)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+)
+
+// fakePVZQueries фиксирует аргументы вызова FlagInactivePVZ
+type fakePVZQueries struct {
+	queries.PVZQueriesInterface
+	since time.Time
+	err   error
+}
+
+func (f *fakePVZQueries) FlagInactivePVZ(ctx context.Context, inactiveSince time.Time) (int64, error) {
+	f.since = inactiveSince
+	return 1, f.err
+}
+
+// TestInactivePVZJobRunOnce проверяет вычисление порога неактивности
+func TestInactivePVZJobRunOnce(t *testing.T) {
+	fake := &fakePVZQueries{}
+	job := NewInactivePVZJob(fake, 30*24*time.Hour, time.Hour)
+	now := time.Date(2026, 3, 1, 21, 30, 0, 0, time.UTC)
+	job.clock = clock.Fixed(now)
+
+	err := job.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(-30*24*time.Hour), fake.since)
+}
+
+// TestInactivePVZJobRunOnceError проверяет проброс ошибки запроса
+func TestInactivePVZJobRunOnceError(t *testing.T) {
+	fake := &fakePVZQueries{err: errors.New("database error")}
+	job := NewInactivePVZJob(fake, time.Hour, time.Hour)
+
+	assert.Error(t, job.RunOnce(context.Background()))
+}
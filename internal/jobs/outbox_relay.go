@@ -0,0 +1,118 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/events"
+	"pvz-service/internal/models"
+	"pvz-service/internal/notify"
+)
+
+// outboxPurgeInterval - как часто релей удаляет доставленные события старше срока хранения
+const outboxPurgeInterval = time.Hour
+
+// OutboxRelay доставляет события из таблицы outbox в ленту ПВЗ и брокер.
+// Событие отмечается доставленным только после успешной отправки в брокер,
+// поэтому при его недоступности доставка повторяется ("хотя бы один раз"),
+// а получатели отбрасывают повторы по DedupID. Событие, не доставленное за maxAttempts
+// попыток, передается в очередь повторов deadLetters, чтобы не задерживать следующие
+type OutboxRelay struct {
+	outboxQueries queries.OutboxQueriesInterface
+	publisher     events.DedupPublisher
+	broker        notify.Notifier
+	deadLetters   notify.FailedDeliveryStore
+	interval      time.Duration
+	batchSize     int
+	maxAttempts   int
+	retention     time.Duration
+	lastPurge     time.Time
+	clock         clock.Clock
+}
+
+// NewOutboxRelay создает новый экземпляр OutboxRelay
+func NewOutboxRelay(outboxQueries queries.OutboxQueriesInterface, publisher events.DedupPublisher, broker notify.Notifier, deadLetters notify.FailedDeliveryStore, interval time.Duration, batchSize, maxAttempts int, retention time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		outboxQueries: outboxQueries,
+		publisher:     publisher,
+		broker:        broker,
+		deadLetters:   deadLetters,
+		interval:      interval,
+		batchSize:     batchSize,
+		maxAttempts:   maxAttempts,
+		retention:     retention,
+		clock:         clock.System{},
+	}
+}
+
+// Run запускает релей и блокируется до отмены контекста
+func (j *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.RunOnce(ctx); err != nil {
+			log.Printf("Outbox relay failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce доставляет пачку ожидающих событий в порядке записи.
+// На первой ошибке брокера релей останавливается, чтобы не нарушить порядок событий,
+// пока у события не кончатся попытки
+func (j *OutboxRelay) RunOnce(ctx context.Context) error {
+	pending, err := j.outboxQueries.GetPendingEvents(ctx, j.batchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, pendingEvent := range pending {
+		event := j.publisher.PublishOnce(pendingEvent.EventID, pendingEvent.PvzID, pendingEvent.Type, json.RawMessage(pendingEvent.Payload))
+
+		if err := j.broker.Notify(ctx, event); err != nil {
+			// Исчерпавшее попытки событие уходит в очередь повторов с экспоненциальной задержкой
+			if pendingEvent.Attempts+1 >= j.maxAttempts {
+				if err := j.deadLetters.AddFailedDelivery(ctx, models.DeliveryTargetBroker, event, err.Error()); err != nil {
+					return err
+				}
+				if err := j.outboxQueries.MarkEventPublished(ctx, pendingEvent.ID); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if markErr := j.outboxQueries.MarkEventFailed(ctx, pendingEvent.ID, err.Error()); markErr != nil {
+				log.Printf("Outbox relay failed to record delivery error: %v", markErr)
+			}
+			return fmt.Errorf("failed to deliver outbox event %s: %w", pendingEvent.EventID, err)
+		}
+
+		if err := j.outboxQueries.MarkEventPublished(ctx, pendingEvent.ID); err != nil {
+			return err
+		}
+	}
+
+	if j.retention > 0 && j.clock.Now().Sub(j.lastPurge) >= outboxPurgeInterval {
+		deleted, err := j.outboxQueries.DeletePublishedEvents(ctx, j.clock.Now().Add(-j.retention))
+		if err != nil {
+			return err
+		}
+		j.lastPurge = j.clock.Now()
+		if deleted > 0 {
+			log.Printf("Outbox relay purged %d published events", deleted)
+		}
+	}
+
+	return nil
+}
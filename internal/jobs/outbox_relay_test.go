@@ -0,0 +1,110 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/events"
+	"pvz-service/internal/models"
+)
+
+// fakeOutboxQueries хранит события outbox в памяти
+type fakeOutboxQueries struct {
+	queries.OutboxQueriesInterface
+	pending   []models.OutboxEvent
+	published []int64
+	failed    []int64
+	purged    bool
+}
+
+func (f *fakeOutboxQueries) GetPendingEvents(ctx context.Context, limit int) ([]models.OutboxEvent, error) {
+	return f.pending, nil
+}
+
+func (f *fakeOutboxQueries) MarkEventPublished(ctx context.Context, id int64) error {
+	f.published = append(f.published, id)
+	return nil
+}
+
+func (f *fakeOutboxQueries) MarkEventFailed(ctx context.Context, id int64, reason string) error {
+	f.failed = append(f.failed, id)
+	return nil
+}
+
+func (f *fakeOutboxQueries) DeletePublishedEvents(ctx context.Context, before time.Time) (int64, error) {
+	f.purged = true
+	return 0, nil
+}
+
+func newPendingEvents() []models.OutboxEvent {
+	return []models.OutboxEvent{
+		{ID: 1, EventID: "event-1", PvzID: "pvz-1", Type: models.EventReceptionCreated, Payload: []byte(`{"id":"reception-1"}`)},
+		{ID: 2, EventID: "event-2", PvzID: "pvz-1", Type: models.EventProductAdded, Payload: []byte(`{"id":"product-1"}`)},
+	}
+}
+
+// TestOutboxRelayRunOnce проверяет доставку событий в ленту и брокер
+func TestOutboxRelayRunOnce(t *testing.T) {
+	outbox := &fakeOutboxQueries{pending: newPendingEvents()}
+	hub := events.NewHub(10)
+	broker := &fakeNotifier{}
+	relay := NewOutboxRelay(outbox, hub, broker, &fakeDeliveryQueries{}, time.Second, 100, 5, time.Hour)
+
+	err := relay.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, outbox.published)
+	assert.True(t, outbox.purged)
+	assert.Len(t, broker.events, 2)
+	assert.Equal(t, "event-1", broker.events[0].DedupID)
+
+	published, _ := hub.Poll(context.Background(), "pvz-1", 0, 0)
+	assert.Len(t, published, 2)
+}
+
+// TestOutboxRelayBrokerDown проверяет повтор доставки без дублей в ленте
+func TestOutboxRelayBrokerDown(t *testing.T) {
+	outbox := &fakeOutboxQueries{pending: newPendingEvents()}
+	hub := events.NewHub(10)
+	broker := &fakeNotifier{err: errors.New("broker unavailable")}
+	relay := NewOutboxRelay(outbox, hub, broker, &fakeDeliveryQueries{}, time.Second, 100, 5, time.Hour)
+
+	err := relay.RunOnce(context.Background())
+
+	assert.Error(t, err)
+	// Доставка останавливается на первом событии, чтобы сохранить порядок
+	assert.Equal(t, []int64{1}, outbox.failed)
+	assert.Empty(t, outbox.published)
+
+	broker.err = nil
+	assert.NoError(t, relay.RunOnce(context.Background()))
+	assert.Equal(t, []int64{1, 2}, outbox.published)
+
+	published, _ := hub.Poll(context.Background(), "pvz-1", 0, 0)
+	assert.Len(t, published, 2)
+}
+
+// TestOutboxRelayDeadLetter проверяет передачу события, исчерпавшего попытки, в очередь повторов
+func TestOutboxRelayDeadLetter(t *testing.T) {
+	pending := newPendingEvents()
+	pending[0].Attempts = 4
+	outbox := &fakeOutboxQueries{pending: pending}
+	deliveries := &fakeDeliveryQueries{}
+	broker := &fakeNotifier{err: errors.New("broker unavailable")}
+	relay := NewOutboxRelay(outbox, events.NewHub(10), broker, deliveries, time.Second, 100, 5, time.Hour)
+
+	err := relay.RunOnce(context.Background())
+
+	// Первое событие передано в очередь повторов, второе не доставлено и ждет следующего запуска
+	assert.Error(t, err)
+	assert.Equal(t, []int64{1}, outbox.published)
+	assert.Equal(t, []int64{2}, outbox.failed)
+	if assert.Len(t, deliveries.added, 1) {
+		assert.Equal(t, "event-1", deliveries.added[0].DedupID)
+	}
+}
@@ -0,0 +1,71 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+)
+
+// ReceptionArchiveJob периодически переносит в архив приёмки, закрытые раньше заданного срока
+type ReceptionArchiveJob struct {
+	archiveQueries queries.ArchiveQueriesInterface
+	after          time.Duration
+	batchSize      int
+	interval       time.Duration
+	clock          clock.Clock
+}
+
+// NewReceptionArchiveJob создает новый экземпляр ReceptionArchiveJob
+func NewReceptionArchiveJob(archiveQueries queries.ArchiveQueriesInterface, after time.Duration, batchSize int, interval time.Duration) *ReceptionArchiveJob {
+	return &ReceptionArchiveJob{
+		archiveQueries: archiveQueries,
+		after:          after,
+		batchSize:      batchSize,
+		interval:       interval,
+		clock:          clock.System{},
+	}
+}
+
+// Run запускает задание и блокируется до отмены контекста
+func (j *ReceptionArchiveJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.RunOnce(ctx); err != nil {
+			log.Printf("Reception archive job failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce переносит в архив все подходящие приёмки пачками по batchSize,
+// каждая пачка - отдельной транзакцией, чтобы не держать долгие блокировки
+func (j *ReceptionArchiveJob) RunOnce(ctx context.Context) error {
+	closedBefore := j.clock.Now().Add(-j.after)
+
+	total := 0
+	for ctx.Err() == nil {
+		archived, err := j.archiveQueries.ArchiveReceptions(ctx, closedBefore, j.batchSize)
+		if err != nil {
+			return err
+		}
+		total += len(archived)
+		if len(archived) < j.batchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.Printf("Reception archive job archived %d receptions", total)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+)
+
+// fakeArchiveQueries отдает заданные пачки приёмок и фиксирует аргументы вызовов
+type fakeArchiveQueries struct {
+	queries.ArchiveQueriesInterface
+	batches      [][]string
+	closedBefore []time.Time
+	err          error
+}
+
+func (f *fakeArchiveQueries) ArchiveReceptions(ctx context.Context, closedBefore time.Time, limit int) ([]string, error) {
+	f.closedBefore = append(f.closedBefore, closedBefore)
+	if f.err != nil {
+		return nil, f.err
+	}
+	if len(f.batches) == 0 {
+		return nil, nil
+	}
+	batch := f.batches[0]
+	f.batches = f.batches[1:]
+	return batch, nil
+}
+
+// TestReceptionArchiveJobRunOnce проверяет перенос пачками до первой неполной пачки
+func TestReceptionArchiveJobRunOnce(t *testing.T) {
+	fake := &fakeArchiveQueries{batches: [][]string{{"r1", "r2"}, {"r3", "r4"}, {"r5"}, {"r6"}}}
+	job := NewReceptionArchiveJob(fake, 3*365*24*time.Hour, 2, time.Hour)
+	now := time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)
+	job.clock = clock.Fixed(now)
+
+	err := job.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, fake.closedBefore, 3)
+	assert.Equal(t, now.Add(-3*365*24*time.Hour), fake.closedBefore[0])
+	assert.Equal(t, [][]string{{"r6"}}, fake.batches)
+}
+
+// TestReceptionArchiveJobRunOnceError проверяет проброс ошибки запроса
+func TestReceptionArchiveJobRunOnceError(t *testing.T) {
+	fake := &fakeArchiveQueries{err: errors.New("database error")}
+	job := NewReceptionArchiveJob(fake, time.Hour, 100, time.Hour)
+
+	assert.Error(t, job.RunOnce(context.Background()))
+}
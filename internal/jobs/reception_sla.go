@@ -0,0 +1,83 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/events"
+	"pvz-service/internal/models"
+	"pvz-service/internal/notify"
+)
+
+// ReceptionSLAJob периодически отмечает приёмки, не закрытые в срок SLA,
+// и оповещает о них ленту событий ПВЗ и webhook
+type ReceptionSLAJob struct {
+	receptionQueries queries.ReceptionQueriesInterface
+	publisher        events.Publisher
+	notifier         notify.Notifier
+	sla              time.Duration
+	interval         time.Duration
+	clock            clock.Clock
+}
+
+// NewReceptionSLAJob создает новый экземпляр ReceptionSLAJob
+func NewReceptionSLAJob(receptionQueries queries.ReceptionQueriesInterface, publisher events.Publisher, notifier notify.Notifier, sla, interval time.Duration) *ReceptionSLAJob {
+	return &ReceptionSLAJob{
+		receptionQueries: receptionQueries,
+		publisher:        publisher,
+		notifier:         notifier,
+		sla:              sla,
+		interval:         interval,
+		clock:            clock.System{},
+	}
+}
+
+// Run запускает задание и блокируется до отмены контекста
+func (j *ReceptionSLAJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.RunOnce(ctx); err != nil {
+			log.Printf("Reception SLA job failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce отмечает просроченные приёмки и отправляет оповещение по каждой новой.
+// Каждая приёмка отмечается один раз, поэтому оповещения не повторяются
+func (j *ReceptionSLAJob) RunOnce(ctx context.Context) error {
+	overdue, err := j.receptionQueries.FlagOverdueReceptions(ctx, j.clock.Now().Add(-j.sla))
+	if err != nil {
+		return err
+	}
+
+	for _, reception := range overdue {
+		event := j.publisher.Publish(reception.PvzID, models.EventReceptionOverdue, models.OverdueReceptionResponse{
+			ID:        reception.ID,
+			Number:    reception.Number,
+			DateTime:  reception.DateTime,
+			PvzID:     reception.PvzID,
+			FlaggedAt: reception.FlaggedAt,
+		})
+
+		// Ошибка доставки webhook не мешает оповестить об остальных приёмках
+		if err := j.notifier.Notify(ctx, event); err != nil {
+			log.Printf("Reception SLA job failed to notify about reception %s: %v", reception.ID, err)
+		}
+	}
+
+	if len(overdue) > 0 {
+		log.Printf("Reception SLA job flagged %d receptions", len(overdue))
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/events"
+	"pvz-service/internal/models"
+)
+
+// fakeReceptionQueries отдает заранее заданные просроченные приёмки
+type fakeReceptionQueries struct {
+	queries.ReceptionQueriesInterface
+	overdue []models.OverdueReception
+	before  time.Time
+	err     error
+}
+
+func (f *fakeReceptionQueries) FlagOverdueReceptions(ctx context.Context, openedBefore time.Time) ([]models.OverdueReception, error) {
+	f.before = openedBefore
+	return f.overdue, f.err
+}
+
+// fakeNotifier запоминает доставленные события
+type fakeNotifier struct {
+	events []models.Event
+	err    error
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, event models.Event) error {
+	f.events = append(f.events, event)
+	return f.err
+}
+
+// TestReceptionSLAJobRunOnce проверяет оповещение о каждой просроченной приёмке
+func TestReceptionSLAJobRunOnce(t *testing.T) {
+	fake := &fakeReceptionQueries{overdue: []models.OverdueReception{
+		{ID: "reception-1", PvzID: "pvz-1"},
+		{ID: "reception-2", PvzID: "pvz-2"},
+	}}
+	hub := events.NewHub(10)
+	notifier := &fakeNotifier{err: errors.New("webhook unavailable")}
+	job := NewReceptionSLAJob(fake, hub, notifier, 12*time.Hour, time.Minute)
+	now := time.Date(2026, 3, 1, 21, 30, 0, 0, time.UTC)
+	job.clock = clock.Fixed(now)
+
+	err := job.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(-12*time.Hour), fake.before)
+	assert.Len(t, notifier.events, 2)
+
+	published, _ := hub.Poll(context.Background(), "pvz-2", 0, 0)
+	assert.Len(t, published, 1)
+	assert.Equal(t, models.EventReceptionOverdue, published[0].Type)
+}
+
+// TestReceptionSLAJobRunOnceError проверяет проброс ошибки запроса
+func TestReceptionSLAJobRunOnceError(t *testing.T) {
+	fake := &fakeReceptionQueries{err: errors.New("database error")}
+	notifier := &fakeNotifier{}
+	job := NewReceptionSLAJob(fake, events.NewHub(10), notifier, time.Hour, time.Minute)
+
+	assert.Error(t, job.RunOnce(context.Background()))
+	assert.Empty(t, notifier.events)
+}
@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// ReportRunner формирует сохраненные отчёты, которым пора запускаться по расписанию
+type ReportRunner interface {
+	RunDue(ctx context.Context) (int, error)
+}
+
+// ReportScheduleJob периодически запускает сохраненные отчёты конструктора по их расписанию
+type ReportScheduleJob struct {
+	runner   ReportRunner
+	interval time.Duration
+}
+
+// NewReportScheduleJob создает новый экземпляр ReportScheduleJob
+func NewReportScheduleJob(runner ReportRunner, interval time.Duration) *ReportScheduleJob {
+	return &ReportScheduleJob{
+		runner:   runner,
+		interval: interval,
+	}
+}
+
+// Run запускает задание и блокируется до отмены контекста
+func (j *ReportScheduleJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.RunOnce(ctx); err != nil {
+			log.Printf("Report schedule job failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce формирует отчёты, которым пора запускаться. Сформированные отчёты учитываются в логе,
+// даже если часть отчётов запустить не удалось
+func (j *ReportScheduleJob) RunOnce(ctx context.Context) error {
+	count, err := j.runner.RunDue(ctx)
+	if count > 0 {
+		log.Printf("Report schedule job generated %d reports", count)
+	}
+	return err
+}
@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeReportRunner считает вызовы RunDue и возвращает заданный результат
+type fakeReportRunner struct {
+	calls int
+	count int
+	err   error
+}
+
+func (f *fakeReportRunner) RunDue(ctx context.Context) (int, error) {
+	f.calls++
+	return f.count, f.err
+}
+
+// TestReportScheduleJobRunOnce проверяет запуск отчётов по расписанию и проброс ошибки
+func TestReportScheduleJobRunOnce(t *testing.T) {
+	runner := &fakeReportRunner{count: 2}
+	job := NewReportScheduleJob(runner, 0)
+
+	assert.NoError(t, job.RunOnce(context.Background()))
+	assert.Equal(t, 1, runner.calls)
+
+	// Ошибка части отчётов пробрасывается, остальные отчёты уже сформированы
+	runner.err = errors.New("database error")
+	assert.Error(t, job.RunOnce(context.Background()))
+	assert.Equal(t, 2, runner.calls)
+}
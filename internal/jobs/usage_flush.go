@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/usage"
+)
+
+// UsageFlushJob периодически записывает накопленную в памяти статистику использования API в базу данных
+type UsageFlushJob struct {
+	counter      *usage.Counter
+	usageQueries queries.UsageQueriesInterface
+	interval     time.Duration
+}
+
+// NewUsageFlushJob создает новый экземпляр UsageFlushJob
+func NewUsageFlushJob(counter *usage.Counter, usageQueries queries.UsageQueriesInterface, interval time.Duration) *UsageFlushJob {
+	return &UsageFlushJob{
+		counter:      counter,
+		usageQueries: usageQueries,
+		interval:     interval,
+	}
+}
+
+// Run запускает задание и блокируется до отмены контекста
+func (j *UsageFlushJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := j.RunOnce(ctx); err != nil {
+			log.Printf("Usage flush job failed: %v", err)
+		}
+	}
+}
+
+// RunOnce записывает накопленные счетчики. Если запись не удалась, счетчики возвращаются
+// в память и записываются при следующем запуске
+func (j *UsageFlushJob) RunOnce(ctx context.Context) error {
+	counts := j.counter.Drain()
+	if len(counts) == 0 {
+		return nil
+	}
+
+	if err := j.usageQueries.AddUsage(ctx, counts); err != nil {
+		j.counter.Restore(counts)
+		return err
+	}
+	return nil
+}
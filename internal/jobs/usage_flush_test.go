@@ -0,0 +1,47 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/usage"
+)
+
+// fakeUsageQueries запоминает записанные счетчики или возвращает ошибку err
+type fakeUsageQueries struct {
+	queries.UsageQueriesInterface
+	added []models.UsageCount
+	err   error
+}
+
+func (f *fakeUsageQueries) AddUsage(ctx context.Context, counts []models.UsageCount) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.added = append(f.added, counts...)
+	return nil
+}
+
+// TestUsageFlushJobRunOnce проверяет запись счетчиков и их сохранение в памяти при ошибке базы данных
+func TestUsageFlushJobRunOnce(t *testing.T) {
+	counter := usage.NewCounter()
+	counter.Record("pvz-1", "user-1", "GET", "/pvz", false)
+	fake := &fakeUsageQueries{err: errors.New("database error")}
+	job := NewUsageFlushJob(counter, fake, 0)
+
+	assert.Error(t, job.RunOnce(context.Background()))
+
+	fake.err = nil
+	assert.NoError(t, job.RunOnce(context.Background()))
+	assert.Len(t, fake.added, 1)
+	assert.Equal(t, int64(1), fake.added[0].Requests)
+
+	// Пустые счетчики не записываются
+	assert.NoError(t, job.RunOnce(context.Background()))
+	assert.Len(t, fake.added, 1)
+}
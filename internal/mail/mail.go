@@ -0,0 +1,72 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"pvz-service/internal/config"
+)
+
+// SenderInterface определяет интерфейс отправки писем
+type SenderInterface interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// LogSender пишет письма в лог вместо отправки, используется для разработки
+type LogSender struct{}
+
+// Send записывает письмо в лог
+func (s *LogSender) Send(ctx context.Context, to, subject, body string) error {
+	log.Printf("Mail to %s: %s: %s", to, subject, body)
+	return nil
+}
+
+// SMTPSender отправляет письма через SMTP-сервер
+type SMTPSender struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// Send отправляет текстовое письмо
+func (s *SMTPSender) Send(ctx context.Context, to, subject, body string) error {
+	message := strings.Join([]string{
+		"From: " + s.from,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	if err := smtp.SendMail(s.addr, s.auth, s.from, []string{to}, []byte(message)); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	return nil
+}
+
+// NewSender создает отправителя писем по имени провайдера из конфигурации
+func NewSender(cfg *config.MailConfig) (SenderInterface, error) {
+	switch cfg.Provider {
+	case "", "log":
+		return &LogSender{}, nil
+	case "smtp":
+		host, _, err := net.SplitHostPort(cfg.SMTPAddr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid smtp address %q: %w", cfg.SMTPAddr, err)
+		}
+
+		var auth smtp.Auth
+		if cfg.SMTPUser != "" {
+			auth = smtp.PlainAuth("", cfg.SMTPUser, cfg.SMTPPassword, host)
+		}
+		return &SMTPSender{addr: cfg.SMTPAddr, from: cfg.From, auth: auth}, nil
+	default:
+		return nil, fmt.Errorf("unknown mail provider: %s", cfg.Provider)
+	}
+}
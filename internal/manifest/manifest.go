@@ -0,0 +1,213 @@
+package manifest
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"pvz-service/internal/models"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ErrUnsupportedFormat возвращается для файлов, отличных от CSV и XLSX
+var ErrUnsupportedFormat = errors.New("unsupported manifest format, expected .csv or .xlsx")
+
+// MaxLines ограничивает количество строк в одной накладной
+const MaxLines = 10000
+
+// Line представляет строку накладной
+type Line struct {
+	Type     string
+	Barcode  string
+	Quantity int
+}
+
+// Parse разбирает накладную, выбирая формат по расширению файла
+func Parse(filename string, r io.Reader) ([]Line, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return ParseCSV(r)
+	case ".xlsx":
+		return ParseXLSX(r)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// ParseCSV разбирает накладную в формате CSV с заголовком type,barcode,quantity
+func ParseCSV(r io.Reader) ([]Line, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv: %w", err)
+	}
+
+	return parseRows(rows)
+}
+
+// ParseXLSX разбирает накладную из первого листа XLSX-файла
+func ParseXLSX(r io.Reader) ([]Line, error) {
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read xlsx: %w", err)
+	}
+	defer file.Close()
+
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, errors.New("xlsx file has no sheets")
+	}
+
+	rows, err := file.GetRows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read xlsx rows: %w", err)
+	}
+
+	return parseRows(rows)
+}
+
+// parseRows проверяет заголовок и строки накладной
+func parseRows(rows [][]string) ([]Line, error) {
+	if len(rows) == 0 {
+		return nil, errors.New("manifest is empty")
+	}
+
+	// Определяем порядок колонок по заголовку
+	columns := map[string]int{}
+	for i, name := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	typeColumn, ok := columns["type"]
+	if !ok {
+		return nil, errors.New("manifest header must contain a type column")
+	}
+
+	lines := make([]Line, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		lineNumber := i + 2
+
+		if isBlank(row) {
+			continue
+		}
+		if len(lines) == MaxLines {
+			return nil, fmt.Errorf("manifest exceeds %d lines", MaxLines)
+		}
+
+		line := Line{
+			Type:     cell(row, typeColumn),
+			Quantity: 1,
+		}
+		if !slices.Contains(models.ProductTypes, line.Type) {
+			return nil, fmt.Errorf("line %d: unknown product type %q", lineNumber, line.Type)
+		}
+
+		if column, ok := columns["barcode"]; ok {
+			line.Barcode = cell(row, column)
+			if len(line.Barcode) > 64 {
+				return nil, fmt.Errorf("line %d: barcode is longer than 64 characters", lineNumber)
+			}
+		}
+
+		if column, ok := columns["quantity"]; ok && cell(row, column) != "" {
+			quantity, err := strconv.Atoi(cell(row, column))
+			if err != nil || quantity <= 0 {
+				return nil, fmt.Errorf("line %d: quantity must be a positive integer", lineNumber)
+			}
+			line.Quantity = quantity
+		}
+
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 {
+		return nil, errors.New("manifest has no lines")
+	}
+
+	return lines, nil
+}
+
+// cell возвращает значение ячейки или пустую строку для коротких строк
+func cell(row []string, column int) string {
+	if column >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[column])
+}
+
+// isBlank сообщает, что строка не содержит значений
+func isBlank(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Compare сопоставляет ожидаемые по накладной товары с отсканированными:
+// количества сравниваются по типам, штрихкоды - поштучно
+func Compare(expected []models.ExpectedProduct, actual []models.Product) models.ManifestDiscrepancies {
+	expectedByType := map[string]int{}
+	expectedBarcodes := map[string]int{}
+	for _, line := range expected {
+		expectedByType[line.Type] += line.Quantity
+		if line.Barcode != nil {
+			expectedBarcodes[*line.Barcode] += line.Quantity
+		}
+	}
+
+	actualByType := map[string]int{}
+	actualBarcodes := map[string]int{}
+	for _, product := range actual {
+		actualByType[product.Type]++
+		if product.Barcode != nil {
+			actualBarcodes[*product.Barcode]++
+		}
+	}
+
+	discrepancies := models.ManifestDiscrepancies{
+		ByType:             []models.TypeDiscrepancy{},
+		MissingBarcodes:    []string{},
+		UnexpectedBarcodes: []string{},
+	}
+
+	for _, productType := range models.ProductTypes {
+		if expectedByType[productType] != actualByType[productType] {
+			discrepancies.ByType = append(discrepancies.ByType, models.TypeDiscrepancy{
+				Type:     productType,
+				Expected: expectedByType[productType],
+				Actual:   actualByType[productType],
+			})
+		}
+	}
+
+	// Штрихкоды сверяются, только если накладная их содержит
+	if len(expectedBarcodes) == 0 {
+		return discrepancies
+	}
+
+	for barcode, count := range expectedBarcodes {
+		for i := actualBarcodes[barcode]; i < count; i++ {
+			discrepancies.MissingBarcodes = append(discrepancies.MissingBarcodes, barcode)
+		}
+	}
+	for barcode, count := range actualBarcodes {
+		for i := expectedBarcodes[barcode]; i < count; i++ {
+			discrepancies.UnexpectedBarcodes = append(discrepancies.UnexpectedBarcodes, barcode)
+		}
+	}
+	sort.Strings(discrepancies.MissingBarcodes)
+	sort.Strings(discrepancies.UnexpectedBarcodes)
+
+	return discrepancies
+}
@@ -0,0 +1,85 @@
+package manifest
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+
+	"pvz-service/internal/models"
+)
+
+func TestParseCSV(t *testing.T) {
+	t.Run("Колонки в произвольном порядке", func(t *testing.T) {
+		lines, err := Parse("manifest.CSV", strings.NewReader("Quantity,Type,Barcode\n2,одежда,\n,обувь,4600000000028\n\n"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Line{
+			{Type: "одежда", Quantity: 2},
+			{Type: "обувь", Barcode: "4600000000028", Quantity: 1},
+		}, lines)
+	})
+
+	t.Run("Ошибки валидации", func(t *testing.T) {
+		_, err := ParseCSV(strings.NewReader("barcode\n123\n"))
+		assert.ErrorContains(t, err, "type column")
+
+		_, err = ParseCSV(strings.NewReader("type,quantity\nобувь,0\n"))
+		assert.ErrorContains(t, err, "line 2")
+
+		_, err = ParseCSV(strings.NewReader("type\n"))
+		assert.ErrorContains(t, err, "no lines")
+	})
+
+	t.Run("Неподдерживаемый формат", func(t *testing.T) {
+		_, err := Parse("manifest.txt", strings.NewReader(""))
+		assert.ErrorIs(t, err, ErrUnsupportedFormat)
+	})
+}
+
+func TestParseXLSX(t *testing.T) {
+	file := excelize.NewFile()
+	sheet := file.GetSheetName(0)
+	assert.NoError(t, file.SetSheetRow(sheet, "A1", &[]interface{}{"type", "barcode", "quantity"}))
+	assert.NoError(t, file.SetSheetRow(sheet, "A2", &[]interface{}{"электроника", "4600000000011", 5}))
+
+	var buf bytes.Buffer
+	assert.NoError(t, file.Write(&buf))
+
+	lines, err := Parse("manifest.xlsx", &buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Line{{Type: "электроника", Barcode: "4600000000011", Quantity: 5}}, lines)
+}
+
+func TestCompare(t *testing.T) {
+	barcode := func(value string) *string { return &value }
+
+	expected := []models.ExpectedProduct{
+		{Type: "электроника", Barcode: barcode("A"), Quantity: 1},
+		{Type: "обувь", Barcode: barcode("B"), Quantity: 2},
+	}
+	actual := []models.Product{
+		{Type: "электроника", Barcode: barcode("A")},
+		{Type: "обувь", Barcode: barcode("B")},
+		{Type: "одежда", Barcode: barcode("C")},
+	}
+
+	discrepancies := Compare(expected, actual)
+
+	assert.True(t, discrepancies.HasAny())
+	assert.Equal(t, []models.TypeDiscrepancy{
+		{Type: "одежда", Expected: 0, Actual: 1},
+		{Type: "обувь", Expected: 2, Actual: 1},
+	}, discrepancies.ByType)
+	assert.Equal(t, []string{"B"}, discrepancies.MissingBarcodes)
+	assert.Equal(t, []string{"C"}, discrepancies.UnexpectedBarcodes)
+
+	t.Run("Накладная без штрихкодов", func(t *testing.T) {
+		discrepancies := Compare([]models.ExpectedProduct{{Type: "одежда", Quantity: 1}}, []models.Product{{Type: "одежда", Barcode: barcode("C")}})
+
+		assert.False(t, discrepancies.HasAny())
+	})
+}
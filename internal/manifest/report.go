@@ -0,0 +1,133 @@
+package manifest
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"pvz-service/internal/models"
+)
+
+// Report построчно сверяет накладную с отсканированными товарами приёмки.
+// Строки накладной со штрихкодом сопоставляются с товарами по штрихкоду: товар с другим типом
+// попадает в TypeMismatches, но засчитывается в строку. Строки без штрихкода закрываются
+// оставшимися товарами того же типа. Недостающие товары собираются в Missing с количеством,
+// не подошедшие ни к одной строке товары - в Extra по одному
+func Report(receptionID string, expected []models.ExpectedProduct, actual []models.Product) models.DiscrepancyReport {
+	report := models.DiscrepancyReport{
+		ReceptionID:    receptionID,
+		ScannedItems:   len(actual),
+		Missing:        []models.DiscrepancyLine{},
+		Extra:          []models.DiscrepancyLine{},
+		TypeMismatches: []models.DiscrepancyLine{},
+	}
+
+	// Товары со штрихкодом, сгруппированные по штрихкоду
+	byBarcode := map[string][]models.Product{}
+	matched := make(map[string]bool, len(actual))
+	for _, product := range actual {
+		if product.Barcode != nil {
+			byBarcode[*product.Barcode] = append(byBarcode[*product.Barcode], product)
+		}
+	}
+
+	// Сначала строки со штрихкодом, чтобы товар с ожидаемым штрихкодом не ушел в строку без штрихкода
+	var withoutBarcode []models.ExpectedProduct
+	for _, line := range expected {
+		report.ExpectedItems += line.Quantity
+		if line.Barcode == nil {
+			withoutBarcode = append(withoutBarcode, line)
+			continue
+		}
+
+		remaining := line.Quantity
+		candidates := byBarcode[*line.Barcode]
+		for len(candidates) > 0 && remaining > 0 {
+			product := candidates[0]
+			candidates = candidates[1:]
+			matched[product.ID] = true
+			remaining--
+
+			if product.Type != line.Type {
+				report.TypeMismatches = append(report.TypeMismatches, models.DiscrepancyLine{
+					Kind:         models.DiscrepancyTypeMismatch,
+					Barcode:      *line.Barcode,
+					ExpectedType: line.Type,
+					ActualType:   product.Type,
+					ProductID:    product.ID,
+					Quantity:     1,
+				})
+			}
+		}
+		byBarcode[*line.Barcode] = candidates
+
+		if remaining > 0 {
+			report.Missing = append(report.Missing, models.DiscrepancyLine{
+				Kind:         models.DiscrepancyMissing,
+				Barcode:      *line.Barcode,
+				ExpectedType: line.Type,
+				Quantity:     remaining,
+			})
+		}
+	}
+
+	// Строки без штрихкода закрываются оставшимися товарами того же типа
+	for _, line := range withoutBarcode {
+		remaining := line.Quantity
+		for _, product := range actual {
+			if remaining == 0 {
+				break
+			}
+			if !matched[product.ID] && product.Type == line.Type {
+				matched[product.ID] = true
+				remaining--
+			}
+		}
+
+		if remaining > 0 {
+			report.Missing = append(report.Missing, models.DiscrepancyLine{
+				Kind:         models.DiscrepancyMissing,
+				ExpectedType: line.Type,
+				Quantity:     remaining,
+			})
+		}
+	}
+
+	for _, product := range actual {
+		if matched[product.ID] {
+			continue
+		}
+		line := models.DiscrepancyLine{
+			Kind:       models.DiscrepancyExtra,
+			ActualType: product.Type,
+			ProductID:  product.ID,
+			Quantity:   1,
+		}
+		if product.Barcode != nil {
+			line.Barcode = *product.Barcode
+		}
+		report.Extra = append(report.Extra, line)
+	}
+
+	return report
+}
+
+// WriteReportCSV записывает отчет сверки в CSV: по строке на каждое расхождение
+func WriteReportCSV(w io.Writer, report models.DiscrepancyReport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"kind", "barcode", "expected_type", "actual_type", "product_id", "quantity"}); err != nil {
+		return err
+	}
+
+	for _, lines := range [][]models.DiscrepancyLine{report.Missing, report.Extra, report.TypeMismatches} {
+		for _, line := range lines {
+			record := []string{line.Kind, line.Barcode, line.ExpectedType, line.ActualType, line.ProductID, strconv.Itoa(line.Quantity)}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
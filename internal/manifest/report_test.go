@@ -0,0 +1,67 @@
+package manifest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/models"
+)
+
+func TestReport(t *testing.T) {
+	phone := "4600000000001"
+	boots := "4600000000002"
+	unknown := "4600000000099"
+
+	expected := []models.ExpectedProduct{
+		{Type: models.ProductTypeElectronics, Barcode: &phone, Quantity: 1},
+		{Type: models.ProductTypeShoes, Barcode: &boots, Quantity: 2},
+		{Type: models.ProductTypeClothes, Quantity: 2},
+	}
+	actual := []models.Product{
+		{ID: "p1", Type: models.ProductTypeClothes, Barcode: &phone},
+		{ID: "p2", Type: models.ProductTypeShoes, Barcode: &boots},
+		{ID: "p3", Type: models.ProductTypeClothes},
+		{ID: "p4", Type: models.ProductTypeElectronics, Barcode: &unknown},
+	}
+
+	report := Report("r1", expected, actual)
+
+	assert.Equal(t, "r1", report.ReceptionID)
+	assert.Equal(t, 5, report.ExpectedItems)
+	assert.Equal(t, 4, report.ScannedItems)
+	assert.Equal(t, []models.DiscrepancyLine{
+		{Kind: models.DiscrepancyMissing, Barcode: boots, ExpectedType: models.ProductTypeShoes, Quantity: 1},
+		{Kind: models.DiscrepancyMissing, ExpectedType: models.ProductTypeClothes, Quantity: 1},
+	}, report.Missing)
+	assert.Equal(t, []models.DiscrepancyLine{
+		{Kind: models.DiscrepancyExtra, Barcode: unknown, ActualType: models.ProductTypeElectronics, ProductID: "p4", Quantity: 1},
+	}, report.Extra)
+	assert.Equal(t, []models.DiscrepancyLine{
+		{Kind: models.DiscrepancyTypeMismatch, Barcode: phone, ExpectedType: models.ProductTypeElectronics, ActualType: models.ProductTypeClothes, ProductID: "p1", Quantity: 1},
+	}, report.TypeMismatches)
+}
+
+func TestReportNoDiscrepancies(t *testing.T) {
+	report := Report("r1",
+		[]models.ExpectedProduct{{Type: models.ProductTypeShoes, Quantity: 1}},
+		[]models.Product{{ID: "p1", Type: models.ProductTypeShoes}})
+
+	assert.Empty(t, report.Missing)
+	assert.Empty(t, report.Extra)
+	assert.Empty(t, report.TypeMismatches)
+}
+
+func TestWriteReportCSV(t *testing.T) {
+	report := models.DiscrepancyReport{
+		Missing: []models.DiscrepancyLine{{Kind: models.DiscrepancyMissing, ExpectedType: "обувь", Quantity: 2}},
+		Extra:   []models.DiscrepancyLine{{Kind: models.DiscrepancyExtra, Barcode: "123", ActualType: "одежда", ProductID: "p1", Quantity: 1}},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteReportCSV(&buf, report))
+	assert.Equal(t, "kind,barcode,expected_type,actual_type,product_id,quantity\n"+
+		"missing,,обувь,,,2\n"+
+		"extra,123,,одежда,p1,1\n", buf.String())
+}
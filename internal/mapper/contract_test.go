@@ -0,0 +1,251 @@
+package mapper
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"pvz-service/internal/models"
+)
+
+// contract описывает соответствие модели БД ответу API, который собирает функция пакета.
+// Каждое поле модели с колонкой БД должно попасть в ответ под тем же JSON-именем, что и в модели
+// (для моделей без JSON-тегов - имя колонки в camelCase), либо быть явно перечислено в renamed или hidden
+type contract struct {
+	name  string
+	model interface{}
+	build func(model interface{}) interface{}
+	// renamed - поля модели, которые лежат в ответе под другим именем; вложенный объект - через точку
+	renamed map[string]string
+	// hidden - поля модели, которые намеренно не отдаются в API
+	hidden []string
+	// computed - поля ответа, которые заполняет обработчик, а не функция пакета из модели
+	computed []string
+}
+
+var contracts = []contract{
+	{
+		name:  "PVZ",
+		model: &models.PVZ{},
+		build: func(m interface{}) interface{} { return PVZ(*m.(*models.PVZ)) },
+	},
+	{
+		name:  "InactivePVZ",
+		model: &models.InactivePVZ{},
+		build: func(m interface{}) interface{} { return InactivePVZ(*m.(*models.InactivePVZ)) },
+		renamed: map[string]string{
+			"ID":               "pvz.id",
+			"City":             "pvz.city",
+			"Address":          "pvz.address",
+			"RegistrationDate": "pvz.registrationDate",
+		},
+		computed: []string{"actions"},
+	},
+	{
+		name:  "Reception",
+		model: &models.Reception{},
+		build: func(m interface{}) interface{} { return Reception(*m.(*models.Reception)) },
+	},
+	{
+		name:  "ReceptionNote",
+		model: &models.ReceptionNote{},
+		build: func(m interface{}) interface{} { return ReceptionNote(*m.(*models.ReceptionNote)) },
+		// Приёмка известна из пути запроса истории заметок
+		hidden: []string{"ReceptionID"},
+	},
+	{
+		name:     "Product",
+		model:    &models.Product{},
+		build:    func(m interface{}) interface{} { return Product(*m.(*models.Product)) },
+		computed: []string{"photos", "overCapacity"},
+	},
+	{
+		name:  "SyncPVZ",
+		model: &models.SyncPVZ{},
+		build: func(m interface{}) interface{} {
+			return Sync(models.SyncChanges{PVZ: []models.SyncPVZ{*m.(*models.SyncPVZ)}}, time.Time{}).PVZ[0]
+		},
+	},
+	{
+		name:  "SyncReception",
+		model: &models.SyncReception{},
+		build: func(m interface{}) interface{} {
+			return Sync(models.SyncChanges{Receptions: []models.SyncReception{*m.(*models.SyncReception)}}, time.Time{}).Receptions[0]
+		},
+	},
+	{
+		name:  "SyncProduct",
+		model: &models.SyncProduct{},
+		build: func(m interface{}) interface{} {
+			return Sync(models.SyncChanges{Products: []models.SyncProduct{*m.(*models.SyncProduct)}}, time.Time{}).Products[0]
+		},
+		computed: []string{"photos", "overCapacity"},
+	},
+}
+
+// TestModelResponseContracts проверяет, что ответы API не расходятся с моделями БД: каждое поле модели
+// отдается под ожидаемым JSON-именем с тем же значением, а в ответе нет полей, которые неоткуда заполнить.
+// Новое поле модели или ответа без записи в contracts ломает тест
+func TestModelResponseContracts(t *testing.T) {
+	for _, c := range contracts {
+		t.Run(c.name, func(t *testing.T) {
+			model := reflect.ValueOf(c.model).Elem()
+			fields := modelFields(model)
+			for i, field := range fields {
+				fillField(t, field.value, field.name, i+1)
+			}
+
+			data, err := json.Marshal(c.build(c.model))
+			if err != nil {
+				t.Fatalf("не удалось сериализовать ответ: %v", err)
+			}
+			var response map[string]interface{}
+			if err := json.Unmarshal(data, &response); err != nil {
+				t.Fatalf("не удалось разобрать ответ: %v", err)
+			}
+
+			sourced := map[string]bool{}
+			for _, field := range fields {
+				if slices.Contains(c.hidden, field.name) {
+					continue
+				}
+
+				path := field.jsonName
+				if renamed, ok := c.renamed[field.name]; ok {
+					path = renamed
+				}
+				sourced[strings.Split(path, ".")[0]] = true
+
+				got, ok := lookup(response, path)
+				if !ok {
+					t.Errorf("поле модели %s не попадает в ответ как %q: добавьте его в ответ или в hidden", field.name, path)
+					continue
+				}
+				want, err := json.Marshal(field.value.Interface())
+				if err != nil {
+					t.Fatalf("не удалось сериализовать поле %s: %v", field.name, err)
+				}
+				gotJSON, _ := json.Marshal(got)
+				var wantValue interface{}
+				_ = json.Unmarshal(want, &wantValue)
+				wantJSON, _ := json.Marshal(wantValue)
+				if string(gotJSON) != string(wantJSON) {
+					t.Errorf("поле модели %s отдается в %q как %s, ожидалось %s", field.name, path, gotJSON, wantJSON)
+				}
+			}
+
+			for _, name := range responseNames(reflect.TypeOf(c.build(c.model))) {
+				if !sourced[name] && !slices.Contains(c.computed, name) {
+					t.Errorf("поле ответа %q не заполняется из модели: добавьте поле в модель или в computed", name)
+				}
+			}
+		})
+	}
+}
+
+// modelField - поле модели с колонкой БД и ожидаемое имя поля в ответе
+type modelField struct {
+	name     string
+	jsonName string
+	value    reflect.Value
+}
+
+// modelFields возвращает поля модели с колонками БД, включая поля встроенных моделей
+func modelFields(v reflect.Value) []modelField {
+	var fields []modelField
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.Anonymous {
+			fields = append(fields, modelFields(v.Field(i))...)
+			continue
+		}
+
+		column := field.Tag.Get("db")
+		if column == "" || column == "-" {
+			continue
+		}
+
+		jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+		if jsonName == "" || jsonName == "-" {
+			jsonName = camelCase(column)
+		}
+		fields = append(fields, modelField{name: field.Name, jsonName: jsonName, value: v.Field(i)})
+	}
+	return fields
+}
+
+// responseNames возвращает JSON-имена полей ответа верхнего уровня, включая поля встроенных структур
+func responseNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			names = append(names, responseNames(field.Type)...)
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// fillField заполняет поле модели ненулевым значением, уникальным для поля, чтобы перепутанные
+// или пропущенные при сборке ответа поля были видны по значению
+func fillField(t *testing.T, v reflect.Value, name string, n int) {
+	t.Helper()
+
+	switch {
+	case v.Type() == reflect.TypeOf(time.Time{}):
+		v.Set(reflect.ValueOf(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(n) * time.Hour)))
+	case v.Kind() == reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		fillField(t, v.Elem(), name, n)
+	case v.Kind() == reflect.String:
+		v.SetString(name)
+	case v.Kind() == reflect.Int:
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.Bool:
+		v.SetBool(true)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		v.Set(reflect.Append(reflect.MakeSlice(v.Type(), 0, 1), reflect.ValueOf(name).Convert(v.Type().Elem())))
+	default:
+		t.Fatalf("тип %s поля %s не поддерживается проверкой: добавьте его в fillField", v.Type(), name)
+	}
+}
+
+// lookup возвращает значение из разобранного ответа по пути через точку
+func lookup(response map[string]interface{}, path string) (interface{}, bool) {
+	parts := strings.Split(path, ".")
+	var value interface{} = response
+	for _, part := range parts {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[part]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// camelCase переводит имя колонки БД в имя поля ответа: reception_id -> receptionId
+func camelCase(column string) string {
+	parts := strings.Split(column, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
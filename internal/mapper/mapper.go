@@ -0,0 +1,154 @@
+// Package mapper преобразует модели БД в ответы API. Ответы собираются только здесь,
+// поэтому поля не расходятся между обработчиками, а новая версия ответа добавляется
+// отдельной функцией рядом с текущей. Соответствие полей моделей БД и ответов проверяет
+// контрактный тест: поле модели, не попавшее в ответ и не помеченное скрытым, ломает сборку в CI
+package mapper
+
+import (
+	"time"
+
+	"pvz-service/internal/models"
+)
+
+// PVZ преобразует ПВЗ в ответ API. Часовой пояс не передается, если время ПВЗ отображается в UTC
+func PVZ(pvz models.PVZ) models.PVZResponse {
+	result := models.PVZResponse{
+		ID:               pvz.ID,
+		RegistrationDate: pvz.RegistrationDate,
+		City:             pvz.City,
+		Address:          pvz.Address,
+		OpenReception:    pvz.OpenReception,
+		Capacity:         pvz.Capacity,
+		Occupancy:        pvz.Occupancy,
+		ReceptionsCount:  pvz.ReceptionsCount,
+		ProductsCount:    pvz.ProductsCount,
+		LastReceptionAt:  pvz.LastReceptionAt,
+	}
+	if pvz.Timezone != nil {
+		result.Timezone = *pvz.Timezone
+	}
+	return result
+}
+
+// InactivePVZ преобразует ПВЗ, предложенный к деактивации, в ответ API без доступных действий
+func InactivePVZ(pvz models.InactivePVZ) models.InactivePVZResponse {
+	return models.InactivePVZResponse{
+		PVZ: models.PVZResponse{
+			ID:               pvz.ID,
+			RegistrationDate: pvz.RegistrationDate,
+			City:             pvz.City,
+			Address:          pvz.Address,
+		},
+		LastActivityAt: pvz.LastActivityAt,
+		FlaggedAt:      pvz.FlaggedAt,
+	}
+}
+
+// Reception преобразует приёмку в ответ API
+func Reception(reception models.Reception) models.ReceptionResponse {
+	return models.ReceptionResponse{
+		ID:           reception.ID,
+		Number:       reception.Number,
+		DateTime:     reception.DateTime,
+		PvzID:        reception.PvzID,
+		Status:       reception.Status,
+		Note:         reception.Note,
+		Tags:         Tags(reception.Tags),
+		ProductCount: reception.ProductCount,
+		Capacity:     reception.Capacity,
+		TemplateID:   reception.TemplateID,
+		SupplierID:   reception.SupplierID,
+	}
+}
+
+// ReceptionNote преобразует запись истории заметок приёмки в ответ API
+func ReceptionNote(note models.ReceptionNote) models.ReceptionNoteResponse {
+	return models.ReceptionNoteResponse{
+		ID:        note.ID,
+		AuthorID:  note.AuthorID,
+		Note:      note.Note,
+		Tags:      Tags(note.Tags),
+		CreatedAt: note.CreatedAt,
+	}
+}
+
+// Product преобразует товар в ответ API без фотографий: ссылки на них подписывает хранилище
+func Product(product models.Product) models.ProductResponse {
+	return models.ProductResponse{
+		ID:          product.ID,
+		DateTime:    product.Datetime,
+		Type:        product.Type,
+		ReceptionID: product.ReceptionID,
+		Barcode:     product.Barcode,
+		Damaged:     product.Damaged,
+	}
+}
+
+// Products преобразует список товаров в ответ API, пустой список отдается как []
+func Products(products []models.Product) []models.ProductResponse {
+	result := make([]models.ProductResponse, 0, len(products))
+	for _, product := range products {
+		result = append(result, Product(product))
+	}
+	return result
+}
+
+// Tags возвращает теги для ответа: пустой список вместо null
+func Tags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// WorkingHours преобразует расписание ПВЗ в ответ API, ПВЗ без расписания получает пустой список дней
+func WorkingHours(pvz models.PVZ, days []models.WorkingDay) models.WorkingHoursResponse {
+	result := models.WorkingHoursResponse{
+		PvzID: pvz.ID,
+		Days:  days,
+	}
+	if days == nil {
+		result.Days = []models.WorkingDay{}
+	}
+	if pvz.Timezone != nil {
+		result.Timezone = *pvz.Timezone
+	}
+	return result
+}
+
+// Sync преобразует изменения для синхронизации терминала в ответ API, пустые списки отдаются как []
+func Sync(changes models.SyncChanges, syncedUntil time.Time) models.SyncResponse {
+	result := models.SyncResponse{
+		PVZ:             make([]models.SyncPVZResponse, 0, len(changes.PVZ)),
+		Receptions:      make([]models.SyncReceptionResponse, 0, len(changes.Receptions)),
+		Products:        make([]models.SyncProductResponse, 0, len(changes.Products)),
+		DeletedProducts: changes.DeletedProducts,
+		Cursor:          changes.Cursor.Encode(),
+		HasMore:         changes.HasMore,
+		SyncedUntil:     syncedUntil,
+	}
+	for _, pvz := range changes.PVZ {
+		result.PVZ = append(result.PVZ, models.SyncPVZResponse{
+			PVZResponse: PVZ(pvz.PVZ),
+			ArchivedAt:  pvz.ArchivedAt,
+			UpdatedAt:   pvz.UpdatedAt,
+		})
+	}
+	for _, reception := range changes.Receptions {
+		result.Receptions = append(result.Receptions, models.SyncReceptionResponse{
+			ReceptionResponse: Reception(reception.Reception),
+			UpdatedAt:         reception.UpdatedAt,
+		})
+	}
+	for _, product := range changes.Products {
+		result.Products = append(result.Products, models.SyncProductResponse{
+			ProductResponse: Product(product.Product),
+			Status:          product.Status,
+			UpdatedAt:       product.UpdatedAt,
+		})
+	}
+	if result.DeletedProducts == nil {
+		result.DeletedProducts = []models.DeletedProduct{}
+	}
+	return result
+}
@@ -0,0 +1,46 @@
+package mapper
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/models"
+)
+
+func TestPVZ(t *testing.T) {
+	timezone := "Europe/Moscow"
+	registered := time.Date(2026, 3, 1, 21, 30, 0, 0, time.UTC)
+
+	result := PVZ(models.PVZ{ID: "pvz1", City: "Москва", RegistrationDate: registered, OpenReception: true, Timezone: &timezone})
+
+	assert.Equal(t, "pvz1", result.ID)
+	assert.Equal(t, registered, result.RegistrationDate)
+	assert.True(t, result.OpenReception)
+	assert.Equal(t, timezone, result.Timezone)
+	assert.Empty(t, PVZ(models.PVZ{ID: "pvz2"}).Timezone)
+}
+
+func TestReception(t *testing.T) {
+	result := Reception(models.Reception{ID: "r1", Number: "MSK001-2026-000001", Status: "in_progress", ProductCount: 2})
+
+	assert.Equal(t, "MSK001-2026-000001", result.Number)
+	assert.Equal(t, 2, result.ProductCount)
+
+	// Теги без значений отдаются пустым списком, а не null
+	data, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"tags":[]`)
+}
+
+func TestProducts(t *testing.T) {
+	barcode := "4600000000011"
+	added := time.Date(2026, 3, 1, 21, 30, 0, 0, time.UTC)
+
+	result := Products([]models.Product{{ID: "p1", Datetime: added, Type: models.ProductTypeShoes, ReceptionID: "r1", Barcode: &barcode}})
+
+	assert.Equal(t, []models.ProductResponse{{ID: "p1", DateTime: added, Type: models.ProductTypeShoes, ReceptionID: "r1", Barcode: &barcode}}, result)
+	assert.NotNil(t, Products(nil))
+}
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+
+	"pvz-service/internal/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// meterName - имя инструментирующей библиотеки в метриках сервиса
+const meterName = "pvz-service"
+
+// Setup настраивает глобальный провайдер метрик с периодическим экспортом по OTLP/HTTP.
+// При выключенных метриках остается провайдер без записи, и измерения ничего не стоят.
+// Возвращает функцию, отправляющую накопленные метрики при завершении работы
+func Setup(ctx context.Context, cfg *config.MetricsConfig, serviceName string) (func(context.Context) error, error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	options := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		options = append(options, otlpmetrichttp.WithInsecure())
+	}
+
+	exporter, err := otlpmetrichttp.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlp metric exporter: %w", err)
+	}
+
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(cfg.Interval))),
+		sdkmetric.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	)
+
+	otel.SetMeterProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Meter возвращает измеритель сервиса. Инструменты, созданные до Setup,
+// начинают записывать значения после настройки провайдера
+func Meter() metric.Meter {
+	return otel.Meter(meterName)
+}
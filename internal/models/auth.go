@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // Типы пользователей
 const (
 	RoleEmployee  = "employee"
@@ -8,10 +10,62 @@ const (
 
 // User представляет пользователя в системе
 type User struct {
-	ID           string `json:"id"`
-	Email        string `json:"email"`
-	Role         string `json:"role"`
-	PasswordHash string `json:"-" db:"password_hash"` // Не отдаем пароль в JSON
+	ID           string  `json:"id"`
+	Email        string  `json:"email"`
+	Role         string  `json:"role"`
+	Phone        *string `json:"phone,omitempty" db:"phone"`
+	PasswordHash string  `json:"-" db:"password_hash"` // Не отдаем пароль в JSON
+	IsActive     bool    `json:"-" db:"is_active"`
+}
+
+// MaxAvatarSize ограничивает размер загружаемого аватара
+const MaxAvatarSize = 5 << 20
+
+// UserProfile представляет профиль пользователя
+type UserProfile struct {
+	ID        string  `db:"id"`
+	Email     string  `db:"email"`
+	Phone     *string `db:"phone"`
+	Role      string  `db:"role"`
+	FirstName string  `db:"first_name"`
+	LastName  string  `db:"last_name"`
+	AvatarKey *string `db:"avatar_key"`
+	AvatarURL *string `db:"avatar_url"`
+}
+
+// ProfileAvatar представляет аватар пользователя: ключ объекта в хранилище или внешнюю ссылку
+type ProfileAvatar struct {
+	StorageKey *string
+	URL        *string
+}
+
+// ProfileUpdate содержит изменяемые поля профиля, nil - поле не меняется
+type ProfileUpdate struct {
+	FirstName *string
+	LastName  *string
+	Phone     *string
+	Avatar    *ProfileAvatar
+}
+
+// UpdateProfileRequest представляет запрос на изменение профиля текущего пользователя.
+// Непереданное поле не меняется; аватар загружается файлом avatar в multipart-запросе
+// или задается внешней ссылкой avatarUrl
+type UpdateProfileRequest struct {
+	FirstName *string `json:"firstName" form:"firstName" binding:"omitempty,max=100"`
+	LastName  *string `json:"lastName" form:"lastName" binding:"omitempty,max=100"`
+	Phone     *string `json:"phone" form:"phone" binding:"omitempty,max=20"`
+	AvatarURL *string `json:"avatarUrl" form:"avatarUrl" binding:"omitempty,url,max=2048"`
+}
+
+// ProfileResponse представляет профиль пользователя в ответе API
+type ProfileResponse struct {
+	ID        string `json:"id"`
+	Email     string `json:"email,omitempty"`
+	Phone     string `json:"phone,omitempty"`
+	Role      string `json:"role"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	AvatarURL string `json:"avatarUrl,omitempty"`
 }
 
 // DummyLoginRequest представляет запрос на получение временного токена
@@ -26,14 +80,17 @@ type DummyLoginResponse struct {
 
 // ErrorResponse представляет ошибку API
 type ErrorResponse struct {
-	Message string `json:"message"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
 }
 
 // internal/models/models.go
 
-// RegisterRequest представляет запрос на регистрацию пользователя
+// RegisterRequest представляет запрос на регистрацию пользователя.
+// Достаточно указать email или телефон
 type RegisterRequest struct {
-	Email    string `json:"email" binding:"required,email"`
+	Email    string `json:"email" binding:"required_without=Phone,omitempty,email"`
+	Phone    string `json:"phone" binding:"required_without=Email,omitempty,max=20"`
 	Password string `json:"password" binding:"required,min=6"`
 	Role     string `json:"role" binding:"required,oneof=employee moderator"`
 }
@@ -41,17 +98,70 @@ type RegisterRequest struct {
 // RegisterResponse представляет ответ на запрос регистрации
 type RegisterResponse struct {
 	ID    string `json:"id"`
-	Email string `json:"email"`
+	Email string `json:"email,omitempty"`
+	Phone string `json:"phone,omitempty"`
 	Role  string `json:"role"`
 }
 
-// LoginRequest представляет запрос на авторизацию
+// LoginRequest представляет запрос на авторизацию.
+// RememberMe открывает долгую сессию с refresh-токеном, например для киоска
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
+	Email      string `json:"email" binding:"required,email"`
+	Password   string `json:"password" binding:"required"`
+	RememberMe bool   `json:"rememberMe"`
 }
 
-// LoginResponse представляет ответ с токеном авторизации
+// LoginResponse представляет ответ с токеном авторизации.
+// RefreshToken выдается только для сессии «запомнить меня»
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken,omitempty"`
+}
+
+// RefreshTokenRequest представляет запрос на обновление токена сессии «запомнить меня»
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// OTPCode представляет одноразовый код входа по телефону
+type OTPCode struct {
+	ID        string    `db:"id"`
+	Phone     string    `db:"phone"`
+	CodeHash  string    `db:"code_hash"`
+	Attempts  int       `db:"attempts"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+// OTPRequest представляет запрос на отправку кода входа
+type OTPRequest struct {
+	Phone string `json:"phone" binding:"required,max=20"`
+}
+
+// OTPRequestResponse представляет ответ на запрос кода входа
+type OTPRequestResponse struct {
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// OTPVerifyRequest представляет запрос на обмен кода входа на токен
+type OTPVerifyRequest struct {
+	Phone      string `json:"phone" binding:"required,max=20"`
+	Code       string `json:"code" binding:"required,len=6,numeric"`
+	RememberMe bool   `json:"rememberMe"`
+}
+
+// ChangePasswordRequest представляет запрос на смену пароля текущего пользователя
+type ChangePasswordRequest struct {
+	OldPassword string `json:"oldPassword" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required,min=6"`
+}
+
+// PasswordResetRequest представляет запрос на отправку токена сброса пароля на email
+type PasswordResetRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// PasswordResetConfirmRequest представляет запрос на установку нового пароля по токену сброса
+type PasswordResetConfirmRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required,min=6"`
 }
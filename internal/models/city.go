@@ -0,0 +1,7 @@
+package models
+
+// City представляет город из справочника с вариантами написания
+type City struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
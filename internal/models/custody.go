@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Действия в документе о цепочке хранения приёмки
+const (
+	CustodyReceptionOpened     = "reception_opened"
+	CustodyProductAdded        = "product_added"
+	CustodyProductRetyped      = "product_retyped"
+	CustodyProductDeleted      = "product_deleted"
+	CustodyReceptionReassigned = "reception_reassigned"
+	CustodyReceptionClosed     = "reception_closed"
+)
+
+// CustodyEntry представляет одно действие с приёмкой или ее товаром. ActorID пустой для действий
+// из консоли и фоновых заданий, ActorEmail - если исполнитель не найден среди пользователей.
+// At пустой, если время действия не записывалось: закрытие приёмки до учета времени закрытия.
+// Details - подробности действия: прежний и новый тип товара или ПВЗ при переносе
+type CustodyEntry struct {
+	Action      string     `json:"action" db:"action"`
+	At          *time.Time `json:"at" db:"at"`
+	ActorID     *string    `json:"actorId,omitempty" db:"actor_id"`
+	ActorEmail  *string    `json:"actorEmail,omitempty" db:"actor_email"`
+	ProductID   *string    `json:"productId,omitempty" db:"product_id"`
+	ProductType *string    `json:"productType,omitempty" db:"product_type"`
+	Barcode     *string    `json:"barcode,omitempty" db:"barcode"`
+	Details     *string    `json:"details,omitempty" db:"details"`
+}
+
+// CustodyDocument представляет документ о цепочке хранения приёмки: кто открыл приёмку, кто и когда
+// добавлял, исправлял и удалял товары, кто ее закрыл. Действия упорядочены по времени
+type CustodyDocument struct {
+	ReceptionID string         `json:"receptionId"`
+	Number      string         `json:"number"`
+	PvzID       string         `json:"pvzId"`
+	Status      string         `json:"status"`
+	Entries     []CustodyEntry `json:"entries"`
+	GeneratedAt time.Time      `json:"generatedAt"`
+}
+
+// CustodyQuery представляет параметры документа о цепочке хранения: JSON или PDF
+type CustodyQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=json pdf"`
+}
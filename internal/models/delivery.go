@@ -0,0 +1,38 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Получатели исходящих доставок
+const (
+	DeliveryTargetAlerts = "alerts"
+	DeliveryTargetBroker = "broker"
+)
+
+// Статусы неудачных доставок: ожидает повтора или перенесена в dead-letter queue
+const (
+	DeliveryStatusRetrying = "retrying"
+	DeliveryStatusDead     = "dead"
+)
+
+// FailedDelivery представляет неудачную доставку события получателю Target.
+// Event содержит событие в том виде, в котором оно отправлялось
+type FailedDelivery struct {
+	ID            int64           `json:"id" db:"id"`
+	Target        string          `json:"target" db:"target"`
+	Event         json.RawMessage `json:"event" db:"event"`
+	Status        string          `json:"status" db:"status"`
+	Attempts      int             `json:"attempts" db:"attempts"`
+	LastError     string          `json:"lastError" db:"last_error"`
+	NextAttemptAt time.Time       `json:"nextAttemptAt" db:"next_attempt_at"`
+	CreatedAt     time.Time       `json:"createdAt" db:"created_at"`
+	UpdatedAt     time.Time       `json:"updatedAt" db:"updated_at"`
+}
+
+// DeliveryListQuery представляет параметры запроса списка неудачных доставок
+type DeliveryListQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=retrying dead"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=500"`
+}
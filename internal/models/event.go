@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Типы событий ленты ПВЗ
+const (
+	EventReceptionCreated    = "reception.created"
+	EventReceptionClosed     = "reception.closed"
+	EventReceptionMerged     = "reception.merged"
+	EventReceptionPaused     = "reception.paused"
+	EventReceptionResumed    = "reception.resumed"
+	EventReceptionReassigned = "reception.reassigned"
+	EventProductAdded        = "product.added"
+	EventProductDeleted      = "product.deleted"
+	EventProductIssued       = "product.issued"
+	EventProductRetyped      = "product.type_changed"
+	EventProductDamaged      = "product.damaged"
+	EventOrderIssued         = "order.issued"
+	EventReceptionOverdue    = "reception.overdue"
+	EventPVZArchived         = "pvz.archived"
+)
+
+// Event представляет событие ленты ПВЗ.
+// DedupID позволяет получателю отбросить повторную доставку события из outbox
+type Event struct {
+	ID        int64       `json:"id"`
+	DedupID   string      `json:"dedupId,omitempty"`
+	PvzID     string      `json:"pvzId"`
+	Type      string      `json:"type"`
+	Payload   interface{} `json:"payload"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+// OutboxEvent представляет событие, ожидающее доставки в таблице event_outbox.
+// Payload содержит JSON полезной нагрузки события
+type OutboxEvent struct {
+	ID        int64     `db:"id"`
+	EventID   string    `db:"event_id"`
+	PvzID     string    `db:"pvz_id"`
+	Type      string    `db:"event_type"`
+	Payload   []byte    `db:"payload"`
+	CreatedAt time.Time `db:"created_at"`
+	Attempts  int       `db:"attempts"`
+}
+
+// EventPollQuery представляет параметры запроса long-polling
+type EventPollQuery struct {
+	Cursor int64 `form:"cursor" binding:"min=0"`
+}
+
+// EventPollResponse представляет ответ long-polling: события после курсора и новый курсор
+type EventPollResponse struct {
+	Events []Event `json:"events"`
+	Cursor int64   `json:"cursor"`
+}
+
+// EventExportQuery представляет параметры выгрузки событий за период [From, To).
+// After - ID последнего полученного события для продолжения прерванной выгрузки,
+// Download отдает выгрузку файлом
+type EventExportQuery struct {
+	From     time.Time `form:"from" binding:"required" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       time.Time `form:"to" binding:"required,gtfield=From" time_format:"2006-01-02T15:04:05Z07:00"`
+	After    int64     `form:"after" binding:"min=0"`
+	Download bool      `form:"download"`
+}
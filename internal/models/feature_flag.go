@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// FeatureFlag представляет флаг функции: общее значение и переопределения для отдельных ПВЗ
+type FeatureFlag struct {
+	Name        string           `json:"name" db:"name"`
+	Enabled     bool             `json:"enabled" db:"enabled"`
+	Description string           `json:"description" db:"description"`
+	UpdatedAt   time.Time        `json:"updatedAt" db:"updated_at"`
+	PVZ         []FeatureFlagPVZ `json:"pvz" db:"-"`
+}
+
+// FeatureFlagPVZ представляет значение флага для отдельного ПВЗ
+type FeatureFlagPVZ struct {
+	Name    string `json:"-" db:"name"`
+	PvzID   string `json:"pvzId" db:"pvz_id"`
+	Enabled bool   `json:"enabled" db:"enabled"`
+}
+
+// UpdateFeatureFlagRequest представляет запрос на изменение флага.
+// Незаданные поля не меняются, флаг создается при первом изменении
+type UpdateFeatureFlagRequest struct {
+	Enabled     *bool                  `json:"enabled"`
+	Description *string                `json:"description" binding:"omitempty,max=500"`
+	PVZ         []UpdateFeatureFlagPVZ `json:"pvz" binding:"omitempty,max=100,dive"`
+}
+
+// UpdateFeatureFlagPVZ задает значение флага для ПВЗ, enabled: null убирает переопределение
+type UpdateFeatureFlagPVZ struct {
+	PvzID   string `json:"pvzId" binding:"required,uuid"`
+	Enabled *bool  `json:"enabled"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// ForecastQuery представляет параметры прогноза поступления товаров в ПВЗ: Days - на сколько дней
+// вперед начиная с сегодняшнего, Weeks - за сколько последних недель берется история
+type ForecastQuery struct {
+	Days  int `form:"days" binding:"min=1,max=14"`
+	Weeks int `form:"weeks" binding:"min=1,max=12"`
+}
+
+// DailyIntake представляет число товаров, принятых в ПВЗ за один местный день
+type DailyIntake struct {
+	Day      time.Time `db:"day"`
+	Products int       `db:"products"`
+}
+
+// ForecastDay представляет ожидаемое число товаров за один день. Weekday - день недели от 1 (понедельник) до 7
+type ForecastDay struct {
+	Date             string  `json:"date"`
+	Weekday          int     `json:"weekday"`
+	ExpectedProducts float64 `json:"expectedProducts"`
+}
+
+// IntakeForecast представляет прогноз поступления товаров в ПВЗ. Ожидаемое число товаров на день -
+// скользящее среднее по тем же дням недели за последние Weeks недель, DailyAverage - среднее по всем дням истории.
+// Даты указаны в часовом поясе ПВЗ, история - полные дни [HistoryFrom, HistoryTo]
+type IntakeForecast struct {
+	PvzID        string        `json:"pvzId"`
+	Timezone     string        `json:"timezone,omitempty"`
+	Weeks        int           `json:"weeks"`
+	HistoryFrom  string        `json:"historyFrom"`
+	HistoryTo    string        `json:"historyTo"`
+	DailyAverage float64       `json:"dailyAverage"`
+	Days         []ForecastDay `json:"days"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Причины неудачного входа в журнале входов
+const (
+	LoginFailureUnknownUser     = "unknown_user"
+	LoginFailureInvalidPassword = "invalid_password"
+	LoginFailureDeactivated     = "deactivated"
+)
+
+// Типы оповещений о подозрительных входах. Оповещения не относятся к ПВЗ и доставляются
+// только получателю оповещений, в ленты ПВЗ они не попадают
+const (
+	EventLoginFailures   = "security.login_failures"
+	EventLoginNewCountry = "security.login_new_country"
+)
+
+// LoginAttempt представляет запись журнала входов. UserID пустой, если пользователь
+// с таким email не найден; Country - страна клиента, если ее передал балансировщик
+type LoginAttempt struct {
+	ID            int64     `json:"id" db:"id"`
+	UserID        *string   `json:"userId,omitempty" db:"user_id"`
+	Email         string    `json:"email" db:"email"`
+	Success       bool      `json:"success" db:"success"`
+	FailureReason *string   `json:"failureReason,omitempty" db:"failure_reason"`
+	IP            string    `json:"ip" db:"ip"`
+	UserAgent     string    `json:"userAgent" db:"user_agent"`
+	Country       *string   `json:"country,omitempty" db:"country"`
+	CreatedAt     time.Time `json:"createdAt" db:"created_at"`
+}
+
+// LoginAuditQuery представляет параметры журнала входов за период [From, To) от новых попыток к старым
+type LoginAuditQuery struct {
+	UserID  string     `form:"userId" binding:"omitempty,uuid"`
+	Email   string     `form:"email" binding:"omitempty,max=255"`
+	IP      string     `form:"ip" binding:"omitempty,ip"`
+	Success *bool      `form:"success"`
+	From    *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To      *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Page    int        `form:"page" binding:"min=1"`
+	Limit   int        `form:"limit" binding:"min=1,max=100"`
+}
+
+// LoginFailuresAlert представляет оповещение о серии неудачных входов с одного email или IP
+// за окно Window
+type LoginFailuresAlert struct {
+	Email    string    `json:"email,omitempty"`
+	IP       string    `json:"ip,omitempty"`
+	Failures int       `json:"failures"`
+	Window   string    `json:"window"`
+	LastAt   time.Time `json:"lastAt"`
+}
+
+// LoginNewCountryAlert представляет оповещение об успешном входе пользователя из страны,
+// из которой он раньше не входил
+type LoginNewCountryAlert struct {
+	UserID    string    `json:"userId"`
+	Email     string    `json:"email"`
+	Country   string    `json:"country"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"userAgent"`
+	LoginAt   time.Time `json:"loginAt"`
+}
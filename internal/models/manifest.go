@@ -0,0 +1,69 @@
+package models
+
+// ExpectedProduct представляет строку накладной с ожидаемыми товарами
+type ExpectedProduct struct {
+	ID          string  `json:"id" db:"id"`
+	ReceptionID string  `json:"receptionId" db:"reception_id"`
+	Type        string  `json:"type" db:"type"`
+	Barcode     *string `json:"barcode,omitempty" db:"barcode"`
+	Quantity    int     `json:"quantity" db:"quantity"`
+}
+
+// ManifestImportResponse представляет результат импорта накладной
+type ManifestImportResponse struct {
+	ReceptionID string `json:"receptionId"`
+	Lines       int    `json:"lines"`
+	Items       int    `json:"items"`
+}
+
+// TypeDiscrepancy представляет расхождение по типу товара
+type TypeDiscrepancy struct {
+	Type     string `json:"type"`
+	Expected int    `json:"expected"`
+	Actual   int    `json:"actual"`
+}
+
+// ManifestDiscrepancies представляет расхождения между накладной и отсканированными товарами
+type ManifestDiscrepancies struct {
+	ByType             []TypeDiscrepancy `json:"byType"`
+	MissingBarcodes    []string          `json:"missingBarcodes"`
+	UnexpectedBarcodes []string          `json:"unexpectedBarcodes"`
+}
+
+// HasAny сообщает, есть ли хотя бы одно расхождение
+func (d *ManifestDiscrepancies) HasAny() bool {
+	return len(d.ByType) > 0 || len(d.MissingBarcodes) > 0 || len(d.UnexpectedBarcodes) > 0
+}
+
+// Виды расхождений в отчете сверки приёмки с накладной
+const (
+	DiscrepancyMissing      = "missing"
+	DiscrepancyExtra        = "extra"
+	DiscrepancyTypeMismatch = "type_mismatch"
+)
+
+// DiscrepancyLine представляет строку отчета сверки: недостающие по накладной товары,
+// лишний отсканированный товар или товар, тип которого не совпал с накладной
+type DiscrepancyLine struct {
+	Kind         string `json:"kind"`
+	Barcode      string `json:"barcode,omitempty"`
+	ExpectedType string `json:"expectedType,omitempty"`
+	ActualType   string `json:"actualType,omitempty"`
+	ProductID    string `json:"productId,omitempty"`
+	Quantity     int    `json:"quantity"`
+}
+
+// DiscrepancyReport представляет отчет сверки приёмки с накладной
+type DiscrepancyReport struct {
+	ReceptionID    string            `json:"receptionId"`
+	ExpectedItems  int               `json:"expectedItems"`
+	ScannedItems   int               `json:"scannedItems"`
+	Missing        []DiscrepancyLine `json:"missing"`
+	Extra          []DiscrepancyLine `json:"extra"`
+	TypeMismatches []DiscrepancyLine `json:"typeMismatches"`
+}
+
+// DiscrepancyReportQuery представляет параметры отчета сверки, format=csv отдает отчет файлом CSV
+type DiscrepancyReportQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=json csv"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Order представляет заказ покупателя, товары которого выдаются в ПВЗ
+type Order struct {
+	ID             string     `json:"id" db:"id"`
+	OrderNumber    string     `json:"orderNumber" db:"order_number"`
+	RecipientPhone string     `json:"recipientPhone" db:"recipient_phone"`
+	PvzID          string     `json:"pvzId" db:"pvz_id"`
+	CreatedAt      time.Time  `json:"createdAt" db:"created_at"`
+	IssuedAt       *time.Time `json:"issuedAt,omitempty" db:"issued_at"`
+	IssuedBy       *string    `json:"issuedBy,omitempty" db:"issued_by"`
+}
+
+// CreateOrderRequest представляет запрос на создание заказа из товаров ПВЗ
+type CreateOrderRequest struct {
+	OrderNumber    string   `json:"orderNumber" binding:"required,max=64"`
+	RecipientPhone string   `json:"recipientPhone" binding:"required"`
+	PvzID          string   `json:"pvzId" binding:"required,uuid"`
+	ProductIDs     []string `json:"productIds" binding:"required,min=1,max=100,dive,uuid"`
+}
+
+// OrderResponse представляет ответ с данными заказа и его товарами
+type OrderResponse struct {
+	Order
+	Products []ProductResponse `json:"products"`
+}
@@ -4,18 +4,96 @@ import (
 	"time"
 )
 
+// Типы товаров
+const (
+	ProductTypeElectronics = "электроника"
+	ProductTypeClothes     = "одежда"
+	ProductTypeShoes       = "обувь"
+)
+
+// ProductTypes содержит все допустимые типы товаров
+var ProductTypes = []string{ProductTypeElectronics, ProductTypeClothes, ProductTypeShoes}
+
 // Product представляет товар
 type Product struct {
 	ID          string    `json:"id" db:"id"`
 	Datetime    time.Time `json:"dateTime" db:"datetime"`
 	Type        string    `json:"type" db:"type"`
 	ReceptionID string    `json:"receptionId" db:"reception_id"`
+	Barcode     *string   `json:"barcode,omitempty" db:"barcode"`
+	Damaged     bool      `json:"damaged,omitempty" db:"damaged"`
 }
 
-// CreateProductRequest представляет запрос на добавление товара
+// Статусы жизненного цикла товара: в открытой приёмке, на хранении в ПВЗ и выдан
+const (
+	ProductLifecycleReceived = "received"
+	ProductLifecycleStored   = "stored"
+	ProductLifecycleIssued   = "issued"
+)
+
+// Ограничения на фотографии товара
+const (
+	MaxProductPhotos = 10
+	MaxPhotoSize     = 10 << 20
+)
+
+// PhotoContentTypes содержит допустимые форматы загружаемых фотографий и расширения файлов в хранилище
+var PhotoContentTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// CreateProductRequest представляет запрос на добавление товара.
+// Принимается как JSON или multipart/form-data с файлами фотографий в поле photos
 type CreateProductRequest struct {
-	Type  string `json:"type" binding:"required,oneof=электроника одежда обувь"`
-	PvzID string `json:"pvzId" binding:"required,uuid"`
+	Type      string   `json:"type" form:"type" binding:"required,oneof=электроника одежда обувь"`
+	PvzID     string   `json:"pvzId" form:"pvzId" binding:"required,uuid"`
+	Barcode   string   `json:"barcode" form:"barcode" binding:"omitempty,max=64"`
+	PhotoURLs []string `json:"photoUrls" form:"photoUrls" binding:"omitempty,max=10,dive,url,max=2048"`
+	// OverrideCapacity принимает товар, даже если ПВЗ заполнен
+	OverrideCapacity bool `json:"overrideCapacity" form:"overrideCapacity"`
+}
+
+// UpdateProductRequest представляет запрос на исправление типа товара
+type UpdateProductRequest struct {
+	Type string `json:"type" binding:"required,oneof=электроника одежда обувь"`
+}
+
+// MaxDeleteLastProducts ограничивает количество товаров, удаляемых одним запросом
+const MaxDeleteLastProducts = 100
+
+// DeleteLastProductsQuery представляет параметры удаления последних добавленных товаров приёмки
+type DeleteLastProductsQuery struct {
+	Count int `form:"count" binding:"required,min=1,max=100"`
+}
+
+// DeleteLastProductsResponse представляет ID удаленных товаров от последнего добавленного к первому
+type DeleteLastProductsResponse struct {
+	ProductIDs []string `json:"productIds"`
+}
+
+// ProductTypeChange представляет запись истории исправлений типа товара
+type ProductTypeChange struct {
+	ID        string    `db:"id"`
+	ProductID string    `db:"product_id"`
+	AuthorID  string    `db:"author_id"`
+	OldType   string    `db:"old_type"`
+	NewType   string    `db:"new_type"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// ProductTypeChangeResponse представляет товар с исправленным типом в ответе API и в событии product.type_changed
+type ProductTypeChangeResponse struct {
+	ProductResponse
+	PreviousType string `json:"previousType"`
+}
+
+// ProductPhoto представляет фотографию товара: ключ объекта в хранилище или внешнюю ссылку
+type ProductPhoto struct {
+	ProductID  string  `db:"product_id"`
+	StorageKey *string `db:"storage_key"`
+	URL        *string `db:"url"`
 }
 
 // ProductResponse представляет ответ с данными товара
@@ -24,4 +102,88 @@ type ProductResponse struct {
 	DateTime    time.Time `json:"dateTime"`
 	Type        string    `json:"type"`
 	ReceptionID string    `json:"receptionId"`
+	Barcode     *string   `json:"barcode,omitempty"`
+	Damaged     bool      `json:"damaged,omitempty"`
+	Photos      []string  `json:"photos,omitempty"`
+	// OverCapacity - товар принят сверх вместимости ПВЗ с подтверждением overrideCapacity
+	OverCapacity bool `json:"overCapacity,omitempty"`
+}
+
+// MarkDamagedRequest представляет запрос на отметку товара поврежденным.
+// Принимается как JSON или multipart/form-data с файлами фотографий повреждения в поле photos
+type MarkDamagedRequest struct {
+	Description string   `json:"description" form:"description" binding:"required,max=1000"`
+	PhotoURLs   []string `json:"photoUrls" form:"photoUrls" binding:"omitempty,max=10,dive,url,max=2048"`
+}
+
+// ProductDamage представляет отметку о повреждении товара в ответе API, в событии product.damaged,
+// в сводке закрытия приёмки и в отчёте о поврежденных товарах. PvzID заполняется только в отчёте,
+// Photos - только в ответе на отметку
+type ProductDamage struct {
+	ProductID   string    `json:"productId" db:"product_id"`
+	ReceptionID string    `json:"receptionId" db:"reception_id"`
+	PvzID       string    `json:"pvzId,omitempty" db:"pvz_id"`
+	Type        string    `json:"type" db:"type"`
+	Barcode     *string   `json:"barcode,omitempty" db:"barcode"`
+	Description string    `json:"description" db:"damage_description"`
+	DamagedBy   *string   `json:"damagedBy,omitempty" db:"damaged_by"`
+	DamagedAt   time.Time `json:"damagedAt" db:"damaged_at"`
+	Photos      []string  `json:"photos,omitempty" db:"-"`
+}
+
+// DamagedProductsQuery представляет параметры отчёта о поврежденных товарах за период [From, To)
+type DamagedProductsQuery struct {
+	From time.Time `form:"from" binding:"required" time_format:"2006-01-02T15:04:05Z07:00"`
+	To   time.Time `form:"to" binding:"required,gtfield=From" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// Статусы товара в ответе пакетной проверки
+const (
+	ProductStatusInReception = "in_reception"
+	ProductStatusReceived    = "received"
+	ProductStatusIssued      = "issued"
+	ProductStatusNotFound    = "not_found"
+)
+
+// MaxStatusBatchSize ограничивает количество товаров в одном запросе статусов
+const MaxStatusBatchSize = 500
+
+// ProductState представляет текущее состояние товара в базе данных
+type ProductState struct {
+	ID              string `db:"id"`
+	Version         int    `db:"version"`
+	Status          string `db:"status"`
+	ReceptionID     string `db:"reception_id"`
+	ReceptionStatus string `db:"reception_status"`
+	PvzID           string `db:"pvz_id"`
+}
+
+// ProductStatusBatchRequest представляет запрос статусов набора товаров
+type ProductStatusBatchRequest struct {
+	ProductIDs []string `json:"productIds" binding:"required,min=1,max=500,dive,uuid"`
+}
+
+// ProductStatusResponse представляет статус одного товара
+type ProductStatusResponse struct {
+	ID          string `json:"id"`
+	Status      string `json:"status"`
+	Version     int    `json:"version,omitempty"`
+	ReceptionID string `json:"receptionId,omitempty"`
+	PvzID       string `json:"pvzId,omitempty"`
+}
+
+// ProductListQuery представляет параметры списка товаров приёмки.
+// Limit без значения и больше ограничения сервера заменяется ограничением сервера. Download отдает список файлом
+type ProductListQuery struct {
+	ReceptionID string `form:"receptionId" binding:"required,uuid"`
+	Page        int    `form:"page" binding:"min=1"`
+	Limit       int    `form:"limit" binding:"omitempty,min=1"`
+	Download    bool   `form:"download"`
+}
+
+// InventoryResponse представляет товары, находящиеся на хранении в ПВЗ
+type InventoryResponse struct {
+	PvzID    string            `json:"pvzId"`
+	Total    int               `json:"total"`
+	Products []ProductResponse `json:"products"`
 }
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// ProductTypeLimit представляет ограничение количества товаров одного типа в приёмке
+type ProductTypeLimit struct {
+	Type            string    `json:"type" db:"type"`
+	MaxPerReception int       `json:"maxPerReception" db:"max_per_reception"`
+	Comment         string    `json:"comment" db:"comment"`
+	UpdatedAt       time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// SetProductTypeLimitRequest представляет запрос на установку ограничения для типа товара
+type SetProductTypeLimitRequest struct {
+	MaxPerReception int    `json:"maxPerReception" binding:"required,min=1"`
+	Comment         string `json:"comment" binding:"max=500"`
+}
+
+// ProductTypeLimitExceeded описывает превышение ограничения в ответе на добавление товара
+type ProductTypeLimitExceeded struct {
+	Type    string `json:"type"`
+	Limit   int    `json:"limit"`
+	Current int    `json:"current"`
+}
@@ -1,40 +1,105 @@
 package models
 
 import (
+	"strings"
 	"time"
 )
 
-// PVZ представляет пункт выдачи заказов
+// PVZ представляет пункт выдачи заказов. OpenReception - есть ли в ПВЗ открытая приёмка,
+// поддерживается триггером в базе данных
 type PVZ struct {
 	ID               string    `json:"id" db:"id"`
 	RegistrationDate time.Time `json:"registrationDate" db:"registration_date"`
 	City             string    `json:"city" db:"city"`
+	Address          string    `json:"address" db:"address"`
+	OpenReception    bool      `json:"openReception" db:"open_reception"`
+	// Timezone - часовой пояс IANA для отображения времени в документах ПВЗ, nil - UTC
+	Timezone *string `json:"timezone,omitempty" db:"timezone"`
+	// Capacity - вместимость ПВЗ в товарах, nil - без ограничения. Occupancy - невыданные товары приёмок ПВЗ
+	Capacity  *int `json:"capacity,omitempty" db:"capacity"`
+	Occupancy int  `json:"occupancy" db:"occupancy"`
+	// Счетчики приёмок заполняются, только если их запросили параметром include=counts
+	ReceptionsCount *int       `json:"receptionsCount,omitempty" db:"receptions_count"`
+	ProductsCount   *int       `json:"productsCount,omitempty" db:"products_count"`
+	LastReceptionAt *time.Time `json:"lastReceptionAt,omitempty" db:"last_reception_at"`
 }
 
-// CreatePVZRequest представляет запрос на создание ПВЗ
+// CreatePVZRequest представляет запрос на создание ПВЗ.
+// Timezone задает часовой пояс IANA для отображения времени, отметки времени в API всегда в UTC
 type CreatePVZRequest struct {
-	City string `json:"city" binding:"required,oneof=Москва Санкт-Петербург Казань"`
+	City     string `json:"city" binding:"required,max=100"`
+	Address  string `json:"address" binding:"omitempty,max=255"`
+	Timezone string `json:"timezone" binding:"omitempty,max=64,timezone"`
 }
 
 // PVZResponse представляет ответ с данными ПВЗ
 type PVZResponse struct {
-	ID               string    `json:"id"`
-	RegistrationDate time.Time `json:"registrationDate"`
-	City             string    `json:"city"`
+	ID               string     `json:"id"`
+	RegistrationDate time.Time  `json:"registrationDate"`
+	City             string     `json:"city"`
+	Address          string     `json:"address,omitempty"`
+	OpenReception    bool       `json:"openReception"`
+	Timezone         string     `json:"timezone,omitempty"`
+	Capacity         *int       `json:"capacity,omitempty"`
+	Occupancy        int        `json:"occupancy"`
+	ReceptionsCount  *int       `json:"receptionsCount,omitempty"`
+	ProductsCount    *int       `json:"productsCount,omitempty"`
+	LastReceptionAt  *time.Time `json:"lastReceptionAt,omitempty"`
 }
 
-// PVZListQuery представляет параметры запроса для получения списка ПВЗ
+// UpdatePVZCapacityRequest представляет запрос на изменение вместимости ПВЗ, null снимает ограничение
+type UpdatePVZCapacityRequest struct {
+	Capacity *int `json:"capacity" binding:"omitempty,min=1"`
+}
+
+// PVZOccupancy представляет вместимость и заполненность ПВЗ
+type PVZOccupancy struct {
+	PvzID     string `json:"pvzId" db:"id"`
+	Capacity  *int   `json:"capacity" db:"capacity"`
+	Occupancy int    `json:"occupancy" db:"occupancy"`
+}
+
+// Дополнительные данные ПВЗ, которые добавляются в список по параметру include
+const (
+	// PVZIncludeCounts - количество приёмок и товаров ПВЗ и время последней приёмки
+	PVZIncludeCounts = "counts"
+)
+
+// PVZIncludes - все допустимые значения параметра include списка ПВЗ
+var PVZIncludes = []string{PVZIncludeCounts}
+
+// PVZListQuery представляет параметры запроса для получения списка ПВЗ.
+// ReceptionStatus оставляет только ПВЗ, у которых есть приёмка в этом статусе.
+// ReceptionsLimit - сколько последних приёмок вернуть для каждого ПВЗ, 0 - только признак receptionsHasMore.
+// Include - дополнительные данные ПВЗ через запятую (PVZIncludes), по умолчанию не добавляются
 type PVZListQuery struct {
-	StartDate string `form:"startDate" time_format:"2006-01-02T15:04:05Z07:00"`
-	EndDate   string `form:"endDate" time_format:"2006-01-02T15:04:05Z07:00"`
-	Page      int    `form:"page" binding:"omitempty,min=1" default:"1"`
-	Limit     int    `form:"limit" binding:"omitempty,min=1,max=30" default:"10"`
+	StartDate       string `form:"startDate" time_format:"2006-01-02T15:04:05Z07:00"`
+	EndDate         string `form:"endDate" time_format:"2006-01-02T15:04:05Z07:00"`
+	ReceptionStatus string `form:"receptionStatus" binding:"omitempty,oneof=in_progress paused close"`
+	City            string `form:"city" binding:"omitempty,max=100"`
+	Tag             string `form:"tag" binding:"omitempty,max=50"`
+	Page            int    `form:"page" binding:"omitempty,min=1" default:"1"`
+	Limit           int    `form:"limit" binding:"omitempty,min=1,max=30" default:"10"`
+	ReceptionsLimit int    `form:"receptionsLimit" binding:"omitempty,min=0,max=50" default:"5"`
+	Include         string `form:"include" binding:"omitempty,max=100"`
 }
 
-// PVZWithReceptionsResponse представляет ответ со списком ПВЗ и связанными приёмками
+// Includes сообщает, запрошены ли дополнительные данные name в параметре include
+func (q PVZListQuery) Includes(name string) bool {
+	for _, part := range strings.Split(q.Include, ",") {
+		if strings.TrimSpace(part) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PVZWithReceptionsResponse представляет ответ со списком ПВЗ и связанными приёмками.
+// Receptions содержит последние приёмки ПВЗ, ReceptionsHasMore сообщает, что есть более старые
 type PVZWithReceptionsResponse struct {
-	PVZ        PVZResponse        `json:"pvz"`
-	Receptions []ReceptionDetails `json:"receptions"`
+	PVZ               PVZResponse        `json:"pvz"`
+	Receptions        []ReceptionDetails `json:"receptions"`
+	ReceptionsHasMore bool               `json:"receptionsHasMore"`
 }
 
 // ReceptionDetails представляет приёмку с товарами
@@ -42,3 +107,74 @@ type ReceptionDetails struct {
 	Reception ReceptionResponse `json:"reception"`
 	Products  []ProductResponse `json:"products"`
 }
+
+// ArchivePVZQuery представляет параметры удаления ПВЗ.
+// Force закрывает открытые приёмки ПВЗ вместо отказа в удалении
+type ArchivePVZQuery struct {
+	Force bool `form:"force"`
+}
+
+// ArchivePVZResponse представляет ответ на удаление ПВЗ: ПВЗ переносится в архив
+// вместе с историей приёмок, ClosedReceptions - приёмки, закрытые при удалении
+type ArchivePVZResponse struct {
+	PvzID            string              `json:"pvzId"`
+	ArchivedAt       time.Time           `json:"archivedAt"`
+	ClosedReceptions []ReceptionResponse `json:"closedReceptions"`
+}
+
+// InactivePVZ представляет ПВЗ, отмеченный заданием как неактивный
+type InactivePVZ struct {
+	ID               string    `db:"id"`
+	City             string    `db:"city"`
+	Address          string    `db:"address"`
+	RegistrationDate time.Time `db:"registration_date"`
+	LastActivityAt   time.Time `db:"last_activity_at"`
+	FlaggedAt        time.Time `db:"flagged_at"`
+}
+
+// PVZAction представляет действие, доступное над ПВЗ в админских отчётах
+type PVZAction struct {
+	Name   string `json:"name"`
+	Method string `json:"method"`
+	Href   string `json:"href"`
+}
+
+// InactivePVZResponse представляет ПВЗ, предложенный к деактивации
+type InactivePVZResponse struct {
+	PVZ            PVZResponse `json:"pvz"`
+	LastActivityAt time.Time   `json:"lastActivityAt"`
+	FlaggedAt      time.Time   `json:"flaggedAt"`
+	Actions        []PVZAction `json:"actions"`
+}
+
+// Статусы строк отчёта об импорте ПВЗ
+const (
+	PVZImportRowValid   = "valid"
+	PVZImportRowCreated = "created"
+	PVZImportRowError   = "error"
+)
+
+// PVZImportQuery представляет параметры импорта ПВЗ.
+// При DryRun файл только проверяется, ПВЗ не создаются
+type PVZImportQuery struct {
+	DryRun bool `form:"dryRun"`
+}
+
+// PVZImportRowResult представляет результат импорта одной строки файла
+type PVZImportRowResult struct {
+	Line    int    `json:"line"`
+	City    string `json:"city"`
+	Address string `json:"address"`
+	Status  string `json:"status"`
+	PvzID   string `json:"pvzId,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// PVZImportResponse представляет отчёт об импорте ПВЗ по строкам файла
+type PVZImportResponse struct {
+	DryRun  bool                 `json:"dryRun"`
+	Total   int                  `json:"total"`
+	Created int                  `json:"created"`
+	Failed  int                  `json:"failed"`
+	Rows    []PVZImportRowResult `json:"rows"`
+}
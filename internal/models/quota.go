@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Операции, ограничиваемые квотами
+const (
+	QuotaOperationProductDelete   = "product.delete"
+	QuotaOperationReceptionCreate = "reception.create"
+)
+
+// QuotaOperations - операции, для которых можно задать квоту
+var QuotaOperations = []string{QuotaOperationProductDelete, QuotaOperationReceptionCreate}
+
+// OperationQuota представляет квоту операции для роли: не больше MaxPerHour операций пользователя в час
+type OperationQuota struct {
+	Role       string    `json:"role" db:"role"`
+	Operation  string    `json:"operation" db:"operation"`
+	MaxPerHour int       `json:"maxPerHour" db:"max_per_hour"`
+	Comment    string    `json:"comment" db:"comment"`
+	UpdatedAt  time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// SetOperationQuotaRequest представляет запрос на установку квоты операции для роли
+type SetOperationQuotaRequest struct {
+	MaxPerHour int    `json:"maxPerHour" binding:"required,min=1"`
+	Comment    string `json:"comment" binding:"max=500"`
+}
+
+// QuotaOverride представляет временное исключение из квоты роли для пользователя.
+// MaxPerHour nil - операция не ограничена до ExpiresAt
+type QuotaOverride struct {
+	UserID     string    `json:"userId" db:"user_id"`
+	Operation  string    `json:"operation" db:"operation"`
+	MaxPerHour *int      `json:"maxPerHour" db:"max_per_hour"`
+	ExpiresAt  time.Time `json:"expiresAt" db:"expires_at"`
+	Comment    string    `json:"comment" db:"comment"`
+	CreatedBy  *string   `json:"createdBy,omitempty" db:"created_by"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+}
+
+// SetQuotaOverrideRequest представляет запрос на временное исключение из квоты.
+// Без maxPerHour операция не ограничена до expiresAt
+type SetQuotaOverrideRequest struct {
+	MaxPerHour *int      `json:"maxPerHour" binding:"omitempty,min=1"`
+	ExpiresAt  time.Time `json:"expiresAt" binding:"required"`
+	Comment    string    `json:"comment" binding:"max=500"`
+}
+
+// QuotaExceeded описывает превышение квоты в ответе на операцию
+type QuotaExceeded struct {
+	Operation    string    `json:"operation"`
+	Limit        int       `json:"limit"`
+	Requested    int       `json:"requested"`
+	WindowResets time.Time `json:"windowResets"`
+}
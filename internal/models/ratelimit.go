@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// RateLimitOverride представляет индивидуальный лимит запросов для ключа
+type RateLimitOverride struct {
+	Key               string    `json:"key" db:"key"`
+	RequestsPerMinute int       `json:"requestsPerMinute" db:"requests_per_minute"`
+	Burst             int       `json:"burst" db:"burst"`
+	Comment           string    `json:"comment" db:"comment"`
+	UpdatedAt         time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// SetRateLimitOverrideRequest представляет запрос на установку индивидуального лимита
+type SetRateLimitOverrideRequest struct {
+	RequestsPerMinute int    `json:"requestsPerMinute" binding:"required,min=1"`
+	Burst             int    `json:"burst" binding:"required,min=1"`
+	Comment           string `json:"comment" binding:"max=500"`
+}
@@ -1,24 +1,179 @@
 package models
 
-import "time"
+import (
+	"time"
 
-// Reception представляет приёмку товаров
+	"github.com/lib/pq"
+)
+
+// Reception представляет приёмку товаров. ProductCount поддерживается триггером в базе данных.
+// Capacity - максимум товаров в приёмке, TemplateID - шаблон, по которому создана приёмка,
+// SupplierID - поставщик, сдавший товары
 type Reception struct {
-	ID       string    `json:"id" db:"id"`
-	DateTime time.Time `json:"dateTime" db:"datetime"`
-	PvzID    string    `json:"pvzId" db:"pvz_id"`
-	Status   string    `json:"status" db:"status"`
+	ID           string         `json:"id" db:"id"`
+	Number       string         `json:"number" db:"number"`
+	DateTime     time.Time      `json:"dateTime" db:"datetime"`
+	PvzID        string         `json:"pvzId" db:"pvz_id"`
+	Status       string         `json:"status" db:"status"`
+	Note         string         `json:"note" db:"note"`
+	Tags         pq.StringArray `json:"tags" db:"tags"`
+	ProductCount int            `json:"productCount" db:"product_count"`
+	Capacity     *int           `json:"capacity,omitempty" db:"capacity"`
+	TemplateID   *string        `json:"templateId,omitempty" db:"template_id"`
+	SupplierID   *string        `json:"supplierId,omitempty" db:"supplier_id"`
 }
 
-// CreateReceptionRequest представляет запрос на создание приёмки товаров
+// CreateReceptionRequest представляет запрос на создание приёмки товаров,
+// SupplierID - необязательный поставщик, сдающий товары
 type CreateReceptionRequest struct {
-	PvzID string `json:"pvzId" binding:"required,uuid"`
+	PvzID      string `json:"pvzId" binding:"required,uuid"`
+	SupplierID string `json:"supplierId,omitempty" binding:"omitempty,uuid"`
+}
+
+// CreateReceptionQuery представляет параметры создания приёмки: TemplateID - шаблон,
+// из которого копируются ожидаемые товары, вместимость и заметка
+type CreateReceptionQuery struct {
+	TemplateID string `form:"templateId" binding:"omitempty,uuid"`
 }
 
 // ReceptionResponse представляет ответ с данными приёмки
 type ReceptionResponse struct {
-	ID       string    `json:"id"`
-	DateTime time.Time `json:"dateTime"`
-	PvzID    string    `json:"pvzId"`
-	Status   string    `json:"status"`
+	ID           string    `json:"id"`
+	Number       string    `json:"number,omitempty"`
+	DateTime     time.Time `json:"dateTime"`
+	PvzID        string    `json:"pvzId"`
+	Status       string    `json:"status"`
+	Note         string    `json:"note,omitempty"`
+	Tags         []string  `json:"tags"`
+	ProductCount int       `json:"productCount"`
+	Capacity     *int      `json:"capacity,omitempty"`
+	TemplateID   *string   `json:"templateId,omitempty"`
+	SupplierID   *string   `json:"supplierId,omitempty"`
+}
+
+// ReceptionListQuery представляет параметры списка приёмок ПВЗ, Status - фильтр по статусу приёмки
+type ReceptionListQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=in_progress paused close"`
+	Page   int    `form:"page" binding:"min=1"`
+	Limit  int    `form:"limit" binding:"min=1,max=100"`
+}
+
+// UpdateReceptionRequest представляет запрос на изменение заметки и тегов приёмки.
+// Непереданное поле не меняется, пустой список tags удаляет все теги
+type UpdateReceptionRequest struct {
+	Note *string   `json:"note" binding:"omitempty,max=1000"`
+	Tags *[]string `json:"tags" binding:"omitempty,max=10,dive,max=50"`
+}
+
+// ReceptionNote представляет запись истории заметок и тегов приёмки
+type ReceptionNote struct {
+	ID          string         `db:"id"`
+	ReceptionID string         `db:"reception_id"`
+	AuthorID    string         `db:"author_id"`
+	Note        string         `db:"note"`
+	Tags        pq.StringArray `db:"tags"`
+	CreatedAt   time.Time      `db:"created_at"`
+}
+
+// ReceptionNoteResponse представляет запись истории заметок в ответе API
+type ReceptionNoteResponse struct {
+	ID        string    `json:"id"`
+	AuthorID  string    `json:"authorId"`
+	Note      string    `json:"note"`
+	Tags      []string  `json:"tags"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CreateReceptionResponse представляет ответ на создание приёмки.
+// PublicToken - токен публичной ссылки на статус приёмки для поставщика, пустой, если ссылки отключены
+type CreateReceptionResponse struct {
+	ReceptionResponse
+	PublicToken string `json:"publicToken,omitempty"`
+}
+
+// PublicReceptionStatus представляет статус приёмки по публичной ссылке: только то,
+// что нужно поставщику, чтобы убедиться, что груз принят. Status - open или closed
+type PublicReceptionStatus struct {
+	Status       string    `json:"status"`
+	DateTime     time.Time `json:"dateTime"`
+	ProductCount int       `json:"productCount"`
+}
+
+// Статусы приёмки по публичной ссылке: приостановленная приёмка для поставщика открыта
+const (
+	PublicReceptionOpen   = "open"
+	PublicReceptionClosed = "closed"
+)
+
+// CloseReceptionQuery представляет параметры закрытия приёмки. Приёмка с расхождениями по накладной
+// закрывается только с Force и причиной Reason
+type CloseReceptionQuery struct {
+	Force  bool   `form:"force"`
+	Reason string `form:"reason" binding:"required_if=Force true,max=500"`
+}
+
+// CloseReceptionResponse представляет ответ на закрытие приёмки с расхождениями по накладной
+// и товарами, отмеченными поврежденными. ForceReason - причина закрытия приёмки с расхождениями
+type CloseReceptionResponse struct {
+	ReceptionResponse
+	Discrepancies *ManifestDiscrepancies `json:"discrepancies,omitempty"`
+	ForceReason   string                 `json:"forceReason,omitempty"`
+	Damaged       []ProductDamage        `json:"damaged,omitempty"`
+}
+
+// CloseStaleReceptionsQuery представляет параметры массового закрытия зависших приёмок
+type CloseStaleReceptionsQuery struct {
+	OlderThan string `form:"olderThan"`
+}
+
+// CloseStaleReceptionsResponse представляет результат массового закрытия зависших приёмок
+type CloseStaleReceptionsResponse struct {
+	ReceptionIDs []string `json:"receptionIds"`
+}
+
+// MergeReceptionsResponse представляет результат объединения приёмок:
+// закрытую исходную приёмку, целевую приёмку и количество перенесенных товаров
+type MergeReceptionsResponse struct {
+	Source        ReceptionResponse `json:"source"`
+	Target        ReceptionResponse `json:"target"`
+	MovedProducts int               `json:"movedProducts"`
+}
+
+// ReassignReceptionQuery представляет параметры переноса приёмки в другой ПВЗ:
+// PvzID - ПВЗ назначения, Reason - причина переноса для журнала
+type ReassignReceptionQuery struct {
+	PvzID  string  `form:"pvzId" binding:"required,uuid"`
+	Reason *string `form:"reason" binding:"omitempty,max=500"`
+}
+
+// ReassignReceptionResponse представляет результат переноса приёмки вместе с товарами в другой ПВЗ
+type ReassignReceptionResponse struct {
+	Reception ReceptionResponse `json:"reception"`
+	FromPvzID string            `json:"fromPvzId"`
+	ToPvzID   string            `json:"toPvzId"`
+	Reason    *string           `json:"reason,omitempty"`
+}
+
+// OverdueReception представляет приёмку, не закрытую в срок SLA
+type OverdueReception struct {
+	ID        string    `db:"id"`
+	Number    string    `db:"number"`
+	DateTime  time.Time `db:"datetime"`
+	PvzID     string    `db:"pvz_id"`
+	Status    string    `db:"status"`
+	FlaggedAt time.Time `db:"flagged_at"`
+}
+
+// OverdueReceptionsQuery представляет параметры запроса просроченных приёмок
+type OverdueReceptionsQuery struct {
+	PvzID string `form:"pvzId" binding:"omitempty,uuid"`
+}
+
+// OverdueReceptionResponse представляет просроченную приёмку в ответе API и в оповещениях
+type OverdueReceptionResponse struct {
+	ID        string    `json:"id"`
+	Number    string    `json:"number,omitempty"`
+	DateTime  time.Time `json:"dateTime"`
+	PvzID     string    `json:"pvzId"`
+	FlaggedAt time.Time `json:"flaggedAt"`
 }
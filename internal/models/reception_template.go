@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// ReceptionTemplate представляет шаблон приёмки для повторяющихся поставок.
+// Строки шаблона копируются в накладную приёмки, Capacity - в ее вместимость, Note - в заметку
+type ReceptionTemplate struct {
+	ID        string                  `json:"id" db:"id"`
+	Name      string                  `json:"name" db:"name"`
+	Note      string                  `json:"note" db:"note"`
+	Capacity  *int                    `json:"capacity,omitempty" db:"capacity"`
+	CreatedBy *string                 `json:"createdBy,omitempty" db:"created_by"`
+	CreatedAt time.Time               `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time               `json:"updatedAt" db:"updated_at"`
+	Lines     []ReceptionTemplateLine `json:"lines" db:"-"`
+}
+
+// ReceptionTemplateLine представляет строку шаблона: ожидаемое количество товаров типа,
+// Barcode - штрихкод ожидаемого товара, если поставщик его сообщает заранее
+type ReceptionTemplateLine struct {
+	TemplateID string  `json:"-" db:"template_id"`
+	Type       string  `json:"type" db:"type" binding:"required,oneof=электроника одежда обувь"`
+	Barcode    *string `json:"barcode,omitempty" db:"barcode" binding:"omitempty,max=64"`
+	Quantity   int     `json:"quantity" db:"quantity" binding:"required,min=1"`
+}
+
+// ReceptionTemplateRequest представляет запрос на создание или изменение шаблона приёмки.
+// Строки изменяемого шаблона заменяются целиком
+type ReceptionTemplateRequest struct {
+	Name     string                  `json:"name" binding:"required,max=100"`
+	Note     string                  `json:"note" binding:"max=1000"`
+	Capacity *int                    `json:"capacity" binding:"omitempty,min=1"`
+	Lines    []ReceptionTemplateLine `json:"lines" binding:"max=500,dive"`
+}
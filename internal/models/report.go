@@ -0,0 +1,183 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// BarcodeOccurrence представляет одно сканирование штрихкода
+type BarcodeOccurrence struct {
+	Barcode         string    `json:"-" db:"barcode"`
+	ProductID       string    `json:"productId" db:"product_id"`
+	DateTime        time.Time `json:"dateTime" db:"datetime"`
+	ReceptionID     string    `json:"receptionId" db:"reception_id"`
+	ReceptionNumber string    `json:"receptionNumber" db:"reception_number"`
+	ReceptionStatus string    `json:"receptionStatus" db:"reception_status"`
+	PvzID           string    `json:"pvzId" db:"pvz_id"`
+	City            string    `json:"city" db:"city"`
+}
+
+// DuplicateBarcodesQuery представляет параметры отчёта по дублям штрихкодов
+type DuplicateBarcodesQuery struct {
+	Window string `form:"window"`
+}
+
+// DuplicateBarcodeResponse представляет штрихкод, найденный в нескольких приёмках или ПВЗ
+type DuplicateBarcodeResponse struct {
+	Barcode     string              `json:"barcode"`
+	Occurrences []BarcodeOccurrence `json:"occurrences"`
+}
+
+// Виды нарушений инвариантов данных
+const (
+	// InvariantMultipleOpenReceptions - у ПВЗ больше одной открытой приёмки
+	InvariantMultipleOpenReceptions = "multiple_open_receptions"
+	// InvariantProductAfterClose - товар закрытой приёмки не переведен на хранение,
+	// то есть добавлен после закрытия приёмки
+	InvariantProductAfterClose = "product_after_close"
+	// InvariantOrphanedProduct - товар остался в наличии в ПВЗ, удаленном в архив
+	InvariantOrphanedProduct = "orphaned_product"
+)
+
+// InvariantKinds - все проверяемые виды нарушений в порядке отчёта
+var InvariantKinds = []string{InvariantMultipleOpenReceptions, InvariantProductAfterClose, InvariantOrphanedProduct}
+
+// InvariantViolation представляет одно нарушение инварианта данных
+type InvariantViolation struct {
+	Kind        string  `json:"kind" db:"kind"`
+	PvzID       string  `json:"pvzId" db:"pvz_id"`
+	ReceptionID string  `json:"receptionId" db:"reception_id"`
+	ProductID   *string `json:"productId,omitempty" db:"product_id"`
+}
+
+// ConsistencyReport представляет результат проверки инвариантов данных
+type ConsistencyReport struct {
+	CheckedAt  time.Time            `json:"checkedAt"`
+	Counts     map[string]int       `json:"counts"`
+	Violations []InvariantViolation `json:"violations"`
+}
+
+// UserActivityQuery представляет параметры отчёта о работе сотрудника за период [From, To)
+type UserActivityQuery struct {
+	From time.Time `form:"from" binding:"required" time_format:"2006-01-02T15:04:05Z07:00"`
+	To   time.Time `form:"to" binding:"required,gtfield=From" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// UserActivity представляет объем работы сотрудника за период: открытые и закрытые им приёмки
+// (по времени открытия приёмки), принятые и удаленные им товары
+type UserActivity struct {
+	UserID            string    `json:"userId" db:"-"`
+	From              time.Time `json:"from" db:"-"`
+	To                time.Time `json:"to" db:"-"`
+	ReceptionsCreated int       `json:"receptionsCreated" db:"receptions_created"`
+	ReceptionsClosed  int       `json:"receptionsClosed" db:"receptions_closed"`
+	ProductsAdded     int       `json:"productsAdded" db:"products_added"`
+	ProductsDeleted   int       `json:"productsDeleted" db:"products_deleted"`
+}
+
+// ReceptionExportQuery представляет параметры выгрузки приёмок всех ПВЗ города за период [From, To).
+// С Async выгрузка формируется в фоне, а в ответ возвращается задание отчёта
+type ReceptionExportQuery struct {
+	City   string    `form:"city" binding:"required"`
+	From   time.Time `form:"from" binding:"required" time_format:"2006-01-02T15:04:05Z07:00"`
+	To     time.Time `form:"to" binding:"required,gtfield=From" time_format:"2006-01-02T15:04:05Z07:00"`
+	Format string    `form:"format" binding:"omitempty,oneof=csv"`
+	Async  bool      `form:"async"`
+}
+
+// ReceptionExportParams представляет параметры выгрузки приёмок города, сохраняемые в задании отчёта
+type ReceptionExportParams struct {
+	City string    `json:"city"`
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// ReceptionExportRow представляет строку выгрузки приёмок города
+type ReceptionExportRow struct {
+	PvzID        string    `db:"pvz_id"`
+	City         string    `db:"city"`
+	ReceptionID  string    `db:"reception_id"`
+	Number       string    `db:"number"`
+	DateTime     time.Time `db:"datetime"`
+	Status       string    `db:"status"`
+	ProductCount int       `db:"product_count"`
+	CreatedBy    *string   `db:"created_by"`
+	ClosedBy     *string   `db:"closed_by"`
+}
+
+// Виды отчётов, формируемых в фоне
+const (
+	// ReportKindCityReceptions - выгрузка приёмок всех ПВЗ города за период
+	ReportKindCityReceptions = "city_receptions"
+	// ReportKindDefinition - сохраненный отчёт конструктора, в параметрах задания - определение отчёта
+	ReportKindDefinition = "definition"
+)
+
+// Статусы задания отчёта
+const (
+	// ReportJobRunning - отчёт формируется
+	ReportJobRunning = "running"
+	// ReportJobDone - отчёт сохранен в хранилище и доступен по ссылке
+	ReportJobDone = "done"
+	// ReportJobFailed - отчёт не удалось сформировать, причина в Error
+	ReportJobFailed = "failed"
+)
+
+// ReportJob представляет задание на формирование отчёта в фоне.
+// Ссылка на скачивание выдается при каждом запросе задания и действует ограниченное время
+type ReportJob struct {
+	ID          string          `json:"id" db:"id"`
+	Kind        string          `json:"kind" db:"kind"`
+	Params      json.RawMessage `json:"params" db:"params"`
+	Status      string          `json:"status" db:"status"`
+	ObjectKey   *string         `json:"-" db:"object_key"`
+	Error       *string         `json:"error,omitempty" db:"error"`
+	CreatedBy   *string         `json:"createdBy,omitempty" db:"created_by"`
+	CreatedAt   time.Time       `json:"createdAt" db:"created_at"`
+	FinishedAt  *time.Time      `json:"finishedAt,omitempty" db:"finished_at"`
+	DownloadURL string          `json:"downloadUrl,omitempty" db:"-"`
+}
+
+// DefaultProductAgingDays - срок хранения по умолчанию, после которого товар попадает в отчёт о залежавшихся товарах
+const DefaultProductAgingDays = 14
+
+// ProductAgingQuery представляет параметры отчёта о товарах, которые дольше Days дней
+// лежат на хранении в закрытых приёмках и не выданы. City ограничивает отчёт одним городом
+type ProductAgingQuery struct {
+	Days   int    `form:"days" binding:"min=1,max=365"`
+	City   string `form:"city"`
+	Format string `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
+// AgingProduct представляет товар на хранении. StoredSince - время закрытия приёмки,
+// AgeDays - число полных дней хранения на момент построения отчёта
+type AgingProduct struct {
+	ProductID       string    `json:"productId" db:"product_id"`
+	Type            string    `json:"type" db:"type"`
+	Barcode         *string   `json:"barcode,omitempty" db:"barcode"`
+	ReceptionID     string    `json:"receptionId" db:"reception_id"`
+	ReceptionNumber string    `json:"receptionNumber" db:"reception_number"`
+	PvzID           string    `json:"-" db:"pvz_id"`
+	City            string    `json:"-" db:"city"`
+	Address         string    `json:"-" db:"address"`
+	StoredSince     time.Time `json:"storedSince" db:"stored_since"`
+	AgeDays         int       `json:"ageDays" db:"-"`
+}
+
+// PVZProductAging представляет залежавшиеся товары одного ПВЗ
+type PVZProductAging struct {
+	PvzID      string         `json:"pvzId"`
+	City       string         `json:"city"`
+	Address    string         `json:"address"`
+	Count      int            `json:"count"`
+	OldestDays int            `json:"oldestDays"`
+	Products   []AgingProduct `json:"products"`
+}
+
+// ProductAgingReport представляет отчёт о залежавшихся товарах, сгруппированных по ПВЗ
+type ProductAgingReport struct {
+	Days        int               `json:"days"`
+	GeneratedAt time.Time         `json:"generatedAt"`
+	Total       int               `json:"total"`
+	PVZ         []PVZProductAging `json:"pvz"`
+}
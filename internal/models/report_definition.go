@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Сущности, по которым строятся отчёты конструктора
+const (
+	// ReportEntityReceptions - приёмки: количество приёмок и принятых в них товаров
+	ReportEntityReceptions = "receptions"
+	// ReportEntityProducts - товары: количество товаров
+	ReportEntityProducts = "products"
+)
+
+// Расписания запуска сохраненных отчётов
+const (
+	ReportScheduleDaily  = "daily"
+	ReportScheduleWeekly = "weekly"
+)
+
+// ReportScheduleInterval возвращает период между запусками отчёта по расписанию
+func ReportScheduleInterval(schedule string) time.Duration {
+	if schedule == ReportScheduleWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// ReportGroupings - допустимые группировки отчёта конструктора по сущностям.
+// day и month - день и месяц приёмки или добавления товара в UTC
+var ReportGroupings = map[string][]string{
+	ReportEntityReceptions: {"city", "pvz", "status", "day", "month"},
+	ReportEntityProducts:   {"city", "pvz", "status", "type", "day", "month"},
+}
+
+// ReportStatuses - допустимые значения фильтра по статусу по сущностям
+var ReportStatuses = map[string][]string{
+	ReportEntityReceptions: {"in_progress", "paused", "close"},
+	ReportEntityProducts:   {"received", "stored", "issued"},
+}
+
+// ReportFilters представляет фильтры отчёта конструктора, пустой фильтр не ограничивает строки.
+// PeriodDays - последние дни на момент запуска, ProductType применяется только к товарам
+type ReportFilters struct {
+	PeriodDays  *int    `json:"periodDays,omitempty" db:"period_days" binding:"omitempty,min=1,max=366"`
+	City        *string `json:"city,omitempty" db:"city" binding:"omitempty,max=100"`
+	PvzID       *string `json:"pvzId,omitempty" db:"pvz_id" binding:"omitempty,uuid"`
+	Status      *string `json:"status,omitempty" db:"status"`
+	ProductType *string `json:"productType,omitempty" db:"product_type" binding:"omitempty,oneof=электроника одежда обувь"`
+}
+
+// ReportDefinition представляет сохраненный отчёт конструктора. Отчёт с расписанием запускается
+// фоновым заданием в NextRunAt, результат доставляется ссылкой в задании отчёта и письмом на Recipients
+type ReportDefinition struct {
+	ID            string `json:"id" db:"id"`
+	Name          string `json:"name" db:"name"`
+	Entity        string `json:"entity" db:"entity"`
+	ReportFilters `json:"filters"`
+	GroupBy       pq.StringArray `json:"groupBy" db:"group_by"`
+	Format        string         `json:"format" db:"format"`
+	Schedule      *string        `json:"schedule,omitempty" db:"schedule"`
+	NextRunAt     *time.Time     `json:"nextRunAt,omitempty" db:"next_run_at"`
+	LastRunAt     *time.Time     `json:"lastRunAt,omitempty" db:"last_run_at"`
+	Recipients    pq.StringArray `json:"recipients" db:"recipients"`
+	CreatedBy     *string        `json:"createdBy,omitempty" db:"created_by"`
+	CreatedAt     time.Time      `json:"createdAt" db:"created_at"`
+	UpdatedAt     time.Time      `json:"updatedAt" db:"updated_at"`
+}
+
+// ReportDefinitionRequest представляет запрос на создание или изменение сохраненного отчёта.
+// Допустимость группировок и статуса для сущности проверяется отдельно по ReportGroupings и ReportStatuses
+type ReportDefinitionRequest struct {
+	Name          string `json:"name" binding:"required,max=200"`
+	Entity        string `json:"entity" binding:"required,oneof=receptions products"`
+	ReportFilters `json:"filters"`
+	GroupBy       []string `json:"groupBy" binding:"max=3,unique,dive,required"`
+	Format        string   `json:"format" binding:"required,oneof=csv json"`
+	Schedule      *string  `json:"schedule" binding:"omitempty,oneof=daily weekly"`
+	Recipients    []string `json:"recipients" binding:"max=20,dive,email"`
+}
+
+// ReportResultRow представляет строку результата отчёта конструктора: значения группировок
+// в порядке GroupBy и показатели. Products считается только для приёмок
+type ReportResultRow struct {
+	Groups   []string
+	Count    int
+	Products int
+}
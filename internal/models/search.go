@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// SearchQuery представляет параметры поиска по ПВЗ, приёмкам и товарам.
+// Limit ограничивает количество результатов в каждой группе
+type SearchQuery struct {
+	Q     string `form:"q" binding:"required,min=2,max=100"`
+	Limit int    `form:"limit" binding:"min=1,max=50"`
+}
+
+// PVZSearchHit представляет ПВЗ, найденный по городу или адресу
+type PVZSearchHit struct {
+	ID      string `db:"id" json:"id"`
+	City    string `db:"city" json:"city"`
+	Address string `db:"address" json:"address"`
+}
+
+// ReceptionSearchHit представляет приёмку, найденную по номеру или заметке
+type ReceptionSearchHit struct {
+	ID       string    `db:"id" json:"id"`
+	Number   string    `db:"number" json:"number"`
+	DateTime time.Time `db:"datetime" json:"dateTime"`
+	PvzID    string    `db:"pvz_id" json:"pvzId"`
+	Status   string    `db:"status" json:"status"`
+	Note     string    `db:"note" json:"note,omitempty"`
+}
+
+// ProductSearchHit представляет товар, найденный по штрихкоду или типу
+type ProductSearchHit struct {
+	ID          string    `db:"id" json:"id"`
+	DateTime    time.Time `db:"datetime" json:"dateTime"`
+	Type        string    `db:"type" json:"type"`
+	Barcode     *string   `db:"barcode" json:"barcode,omitempty"`
+	ReceptionID string    `db:"reception_id" json:"receptionId"`
+	PvzID       string    `db:"pvz_id" json:"pvzId"`
+}
+
+// SearchResponse представляет результаты поиска, сгруппированные по типу сущности
+type SearchResponse struct {
+	PVZ        []PVZSearchHit       `json:"pvz"`
+	Receptions []ReceptionSearchHit `json:"receptions"`
+	Products   []ProductSearchHit   `json:"products"`
+}
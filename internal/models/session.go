@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Session представляет сессию пользователя, к которой привязан выданный токен.
+// RefreshTokenHash задается только у сессий «запомнить меня»
+type Session struct {
+	ID               string     `db:"id"`
+	UserID           string     `db:"user_id"`
+	UserAgent        string     `db:"user_agent"`
+	IP               string     `db:"ip"`
+	IssuedAt         time.Time  `db:"issued_at"`
+	ExpiresAt        time.Time  `db:"expires_at"`
+	RevokedAt        *time.Time `db:"revoked_at"`
+	RefreshTokenHash string     `db:"refresh_token_hash"`
+}
+
+// SessionResponse представляет активную сессию пользователя.
+// Current отмечает сессию, токеном которой выполнен запрос
+type SessionResponse struct {
+	ID        string    `json:"id"`
+	UserAgent string    `json:"userAgent"`
+	IP        string    `json:"ip"`
+	IssuedAt  time.Time `json:"issuedAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Current   bool      `json:"current"`
+}
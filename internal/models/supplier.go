@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Supplier представляет поставщика, сдающего товары в ПВЗ. INN - ИНН поставщика,
+// Contact - контактное лицо и телефон в свободной форме
+type Supplier struct {
+	ID        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	INN       *string   `json:"inn,omitempty" db:"inn"`
+	Contact   string    `json:"contact" db:"contact"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// SupplierRequest представляет запрос на создание или изменение поставщика.
+// ИНН юридического лица состоит из 10 цифр, индивидуального предпринимателя - из 12
+type SupplierRequest struct {
+	Name    string  `json:"name" binding:"required,max=200"`
+	INN     *string `json:"inn" binding:"omitempty,numeric,min=10,max=12"`
+	Contact string  `json:"contact" binding:"max=500"`
+}
+
+// SupplierReportQuery представляет параметры отчёта о поставках по поставщикам за период [From, To)
+type SupplierReportQuery struct {
+	From time.Time `form:"from" binding:"required" time_format:"2006-01-02T15:04:05Z07:00"`
+	To   time.Time `form:"to" binding:"required,gtfield=From" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// SupplierIntake представляет приёмки поставщика за период и число принятых в них товаров.
+// Строка с пустым SupplierID содержит приёмки без поставщика
+type SupplierIntake struct {
+	SupplierID   *string `json:"supplierId" db:"supplier_id"`
+	SupplierName *string `json:"supplierName" db:"supplier_name"`
+	Receptions   int     `json:"receptions" db:"receptions"`
+	Products     int     `json:"products" db:"products"`
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SyncQuery представляет параметры инкрементальной синхронизации терминала ПВЗ.
+// Since - курсор из предыдущего ответа или время RFC 3339, пустой - полная синхронизация.
+// PvzID ограничивает синхронизацию одним ПВЗ, Limit - число строк каждого вида за запрос
+type SyncQuery struct {
+	Since string `form:"since" binding:"omitempty,max=1000"`
+	PvzID string `form:"pvzId" binding:"omitempty,uuid"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+// SyncPosition - позиция синхронизации одного вида данных: время изменения и ID последней переданной строки.
+// Пустой ID означает, что переданы все строки, измененные не позже UpdatedAt
+type SyncPosition struct {
+	UpdatedAt time.Time `json:"t"`
+	ID        string    `json:"i,omitempty"`
+}
+
+// SyncCursor - позиции синхронизации ПВЗ, приёмок, товаров и удаленных товаров
+type SyncCursor struct {
+	PVZ             SyncPosition `json:"v"`
+	Receptions      SyncPosition `json:"r"`
+	Products        SyncPosition `json:"p"`
+	DeletedProducts SyncPosition `json:"d"`
+}
+
+// ErrInvalidSyncCursor - курсор синхронизации поврежден или выдан не этим сервисом
+var ErrInvalidSyncCursor = errors.New("invalid sync cursor")
+
+// Encode кодирует курсор в строку для параметра since
+func (c SyncCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParseSyncSince разбирает параметр since: время RFC 3339 или курсор из ответа синхронизации
+func ParseSyncSince(since string) (SyncCursor, error) {
+	if since == "" {
+		return SyncCursor{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, since); err == nil {
+		position := SyncPosition{UpdatedAt: t.UTC()}
+		return SyncCursor{PVZ: position, Receptions: position, Products: position, DeletedProducts: position}, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(since)
+	if err != nil {
+		return SyncCursor{}, ErrInvalidSyncCursor
+	}
+	var cursor SyncCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return SyncCursor{}, ErrInvalidSyncCursor
+	}
+	for _, position := range []SyncPosition{cursor.PVZ, cursor.Receptions, cursor.Products, cursor.DeletedProducts} {
+		if position.ID == "" {
+			continue
+		}
+		if _, err := uuid.Parse(position.ID); err != nil {
+			return SyncCursor{}, ErrInvalidSyncCursor
+		}
+	}
+
+	return cursor, nil
+}
+
+// SyncParams представляет параметры выборки изменений: изменения после позиций Cursor и не позже Until.
+// PvzIDs ограничивает изменения этими ПВЗ, пустой - все ПВЗ
+type SyncParams struct {
+	Cursor SyncCursor
+	Until  time.Time
+	PvzIDs []string
+	Limit  int
+}
+
+// SyncPVZ представляет измененный ПВЗ, ArchivedAt - время переноса в архив
+type SyncPVZ struct {
+	PVZ
+	ArchivedAt *time.Time `db:"archived_at"`
+	UpdatedAt  time.Time  `db:"updated_at"`
+}
+
+// SyncReception представляет измененную приёмку
+type SyncReception struct {
+	Reception
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// SyncProduct представляет измененный товар и его статус (received, stored или issued)
+type SyncProduct struct {
+	Product
+	Status    string    `db:"status"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// DeletedProduct представляет удаленный товар
+type DeletedProduct struct {
+	ProductID   string    `json:"id" db:"product_id"`
+	ReceptionID string    `json:"receptionId" db:"reception_id"`
+	PvzID       string    `json:"pvzId" db:"pvz_id"`
+	DeletedAt   time.Time `json:"deletedAt" db:"deleted_at"`
+}
+
+// SyncChanges представляет изменения после курсора. Cursor - курсор следующего запроса,
+// HasMore - изменений больше лимита и нужно запросить следующую часть сразу
+type SyncChanges struct {
+	PVZ             []SyncPVZ
+	Receptions      []SyncReception
+	Products        []SyncProduct
+	DeletedProducts []DeletedProduct
+	Cursor          SyncCursor
+	HasMore         bool
+}
+
+// SyncPVZResponse представляет измененный ПВЗ в ответе синхронизации
+type SyncPVZResponse struct {
+	PVZResponse
+	ArchivedAt *time.Time `json:"archivedAt,omitempty"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+// SyncReceptionResponse представляет измененную приёмку в ответе синхронизации
+type SyncReceptionResponse struct {
+	ReceptionResponse
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// SyncProductResponse представляет измененный товар в ответе синхронизации
+type SyncProductResponse struct {
+	ProductResponse
+	Status    string    `json:"status"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// SyncResponse представляет ответ синхронизации: измененные и удаленные данные и курсор
+// для следующего запроса. Если HasMore false, переданы все изменения до SyncedUntil
+type SyncResponse struct {
+	PVZ             []SyncPVZResponse       `json:"pvz"`
+	Receptions      []SyncReceptionResponse `json:"receptions"`
+	Products        []SyncProductResponse   `json:"products"`
+	DeletedProducts []DeletedProduct        `json:"deletedProducts"`
+	Cursor          string                  `json:"cursor"`
+	HasMore         bool                    `json:"hasMore"`
+	SyncedUntil     time.Time               `json:"syncedUntil"`
+}
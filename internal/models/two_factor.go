@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// UserTOTP представляет секрет TOTP пользователя. Пока EnabledAt пустой,
+// секрет ждет подтверждения первым кодом и вход не требует второго фактора
+type UserTOTP struct {
+	UserID       string     `db:"user_id"`
+	Secret       string     `db:"secret"`
+	LastUsedStep int64      `db:"last_used_step"`
+	EnabledAt    *time.Time `db:"enabled_at"`
+}
+
+// LoginChallenge представляет незавершенный вход: первый фактор проверен,
+// токен сессии выдается после кода TOTP или резервного кода
+type LoginChallenge struct {
+	ID         string    `db:"id"`
+	UserID     string    `db:"user_id"`
+	RememberMe bool      `db:"remember_me"`
+	Attempts   int       `db:"attempts"`
+	ExpiresAt  time.Time `db:"expires_at"`
+}
+
+// TwoFactorChallengeResponse представляет ответ на вход пользователя с включенной
+// двухфакторной аутентификацией: вместо токена выдается ChallengeToken для /auth/2fa/verify
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool      `json:"twoFactorRequired"`
+	ChallengeToken    string    `json:"challengeToken"`
+	ExpiresAt         time.Time `json:"expiresAt"`
+}
+
+// TwoFactorVerifyRequest представляет запрос на завершение входа вторым фактором.
+// Code - код из приложения-аутентификатора или резервный код
+type TwoFactorVerifyRequest struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+	Code           string `json:"code" binding:"required,max=20"`
+}
+
+// TwoFactorStatus представляет состояние двухфакторной аутентификации текущего пользователя
+type TwoFactorStatus struct {
+	Enabled         bool       `json:"enabled"`
+	EnabledAt       *time.Time `json:"enabledAt,omitempty"`
+	BackupCodesLeft int        `json:"backupCodesLeft"`
+}
+
+// TwoFactorSetupResponse представляет новый секрет TOTP. URI показывается QR-кодом
+// для приложения-аутентификатора, Secret - для ввода вручную
+type TwoFactorSetupResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// TwoFactorCodeRequest представляет подтверждение действия с двухфакторной аутентификацией
+// кодом из приложения или, где это допускается, резервным кодом
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required,max=20"`
+}
+
+// BackupCodesResponse представляет новые резервные коды. Коды показываются один раз,
+// в базе хранятся только их хеши
+type BackupCodesResponse struct {
+	BackupCodes []string `json:"backupCodes"`
+}
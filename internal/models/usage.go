@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Разрезы статистики использования API
+const (
+	UsageGroupPVZ   = "pvz"
+	UsageGroupUser  = "user"
+	UsageGroupRoute = "route"
+)
+
+// UsageKey определяет счетчик запросов к API: час, ПВЗ, пользователь и маршрут.
+// Пустые PvzID и UserID означают, что ПВЗ или пользователь запроса неизвестны
+type UsageKey struct {
+	Bucket time.Time
+	PvzID  string
+	UserID string
+	Method string
+	Route  string
+}
+
+// UsageCount представляет число запросов и ответов с ошибкой (код 4xx и 5xx) по счетчику Key
+type UsageCount struct {
+	Key      UsageKey
+	Requests int64
+	Errors   int64
+}
+
+// UsageQuery представляет параметры статистики использования API за период [From, To).
+// GroupBy - разрез (pvz, user или route), PvzID и UserID сужают статистику до одного ПВЗ или пользователя
+type UsageQuery struct {
+	From    time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To      time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	GroupBy string    `form:"groupBy" binding:"omitempty,oneof=pvz user route"`
+	PvzID   string    `form:"pvzId" binding:"omitempty,uuid"`
+	UserID  string    `form:"userId" binding:"omitempty,uuid"`
+	Limit   int       `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+// UsageStat представляет число запросов в одном разрезе статистики. Поля других разрезов не заполняются
+type UsageStat struct {
+	PvzID    *string `json:"pvzId,omitempty" db:"pvz_id"`
+	UserID   *string `json:"userId,omitempty" db:"user_id"`
+	Method   string  `json:"method,omitempty" db:"method"`
+	Route    string  `json:"route,omitempty" db:"route"`
+	Requests int64   `json:"requests" db:"requests"`
+	Errors   int64   `json:"errors" db:"errors"`
+}
@@ -0,0 +1,25 @@
+package models
+
+// WorkingHoursLayout - формат времени открытия и закрытия ПВЗ
+const WorkingHoursLayout = "15:04"
+
+// WorkingDay представляет часы работы ПВЗ в один день недели в часовом поясе ПВЗ.
+// Weekday - день недели от 1 (понедельник) до 7 (воскресенье), время в формате ЧЧ:ММ
+type WorkingDay struct {
+	Weekday  int    `json:"weekday" db:"weekday" binding:"required,min=1,max=7"`
+	OpensAt  string `json:"opensAt" db:"opens_at" binding:"required,datetime=15:04"`
+	ClosesAt string `json:"closesAt" db:"closes_at" binding:"required,datetime=15:04"`
+}
+
+// SetWorkingHoursRequest представляет запрос на замену расписания ПВЗ.
+// Пустой список снимает ограничение: ПВЗ работает круглосуточно
+type SetWorkingHoursRequest struct {
+	Days []WorkingDay `json:"days" binding:"max=7,dive"`
+}
+
+// WorkingHoursResponse представляет расписание ПВЗ в ответе API
+type WorkingHoursResponse struct {
+	PvzID    string       `json:"pvzId"`
+	Timezone string       `json:"timezone,omitempty"`
+	Days     []WorkingDay `json:"days"`
+}
@@ -0,0 +1,43 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	"pvz-service/internal/models"
+)
+
+// FailedDeliveryStore сохраняет неудачные доставки для повторных попыток
+type FailedDeliveryStore interface {
+	AddFailedDelivery(ctx context.Context, target string, event models.Event, reason string) error
+}
+
+// DeadLetterNotifier сохраняет события, которые не удалось доставить, в очередь повторов,
+// чтобы оповещение не терялось при недоступности получателя
+type DeadLetterNotifier struct {
+	target   string
+	notifier Notifier
+	store    FailedDeliveryStore
+}
+
+// NewDeadLetterNotifier создает оповещатель для получателя target с сохранением неудачных доставок
+func NewDeadLetterNotifier(target string, notifier Notifier, store FailedDeliveryStore) *DeadLetterNotifier {
+	return &DeadLetterNotifier{
+		target:   target,
+		notifier: notifier,
+		store:    store,
+	}
+}
+
+// Notify доставляет событие, а при ошибке сохраняет его для повтора и возвращает ошибку доставки
+func (n *DeadLetterNotifier) Notify(ctx context.Context, event models.Event) error {
+	err := n.notifier.Notify(ctx, event)
+	if err == nil {
+		return nil
+	}
+
+	if storeErr := n.store.AddFailedDelivery(ctx, n.target, event, err.Error()); storeErr != nil {
+		return fmt.Errorf("%w (failed to queue for retry: %v)", err, storeErr)
+	}
+	return fmt.Errorf("%w (queued for retry)", err)
+}
@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"pvz-service/internal/models"
+)
+
+// Notifier доставляет события во внешние системы
+type Notifier interface {
+	Notify(ctx context.Context, event models.Event) error
+}
+
+// NewNotifier создает оповещатель для webhook по адресу url.
+// При пустом адресе оповещения отключены
+func NewNotifier(url string, timeout time.Duration) Notifier {
+	if url == "" {
+		return NopNotifier{}
+	}
+	return NewWebhookNotifier(url, timeout)
+}
+
+// NopNotifier игнорирует события
+type NopNotifier struct{}
+
+// Notify ничего не делает
+func (NopNotifier) Notify(ctx context.Context, event models.Event) error {
+	return nil
+}
+
+// WebhookNotifier отправляет события POST-запросом с телом в формате JSON
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier создает новый экземпляр WebhookNotifier
+func NewWebhookNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Notify отправляет событие, ответ со статусом вне 2xx считается ошибкой
+func (n *WebhookNotifier) Notify(ctx context.Context, event models.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
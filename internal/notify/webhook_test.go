@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/models"
+)
+
+// TestWebhookNotifier проверяет отправку события в теле запроса
+func TestWebhookNotifier(t *testing.T) {
+	var received models.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := NewNotifier(server.URL, time.Second)
+	err := notifier.Notify(context.Background(), models.Event{ID: 7, PvzID: "pvz-1", Type: models.EventReceptionOverdue})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), received.ID)
+	assert.Equal(t, models.EventReceptionOverdue, received.Type)
+}
+
+// TestWebhookNotifierErrorStatus проверяет ошибку при ответе вне 2xx
+func TestWebhookNotifierErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := NewWebhookNotifier(server.URL, time.Second).Notify(context.Background(), models.Event{})
+
+	assert.Error(t, err)
+}
+
+// TestNewNotifierDisabled проверяет отключение оповещений при пустом адресе
+func TestNewNotifierDisabled(t *testing.T) {
+	notifier := NewNotifier("", time.Second)
+
+	assert.IsType(t, NopNotifier{}, notifier)
+	assert.NoError(t, notifier.Notify(context.Background(), models.Event{}))
+}
+
+// memoryDeliveryStore запоминает сохраненные для повтора события
+type memoryDeliveryStore struct {
+	targets []string
+	events  []models.Event
+}
+
+func (s *memoryDeliveryStore) AddFailedDelivery(ctx context.Context, target string, event models.Event, reason string) error {
+	s.targets = append(s.targets, target)
+	s.events = append(s.events, event)
+	return nil
+}
+
+// failingNotifier всегда возвращает ошибку доставки
+type failingNotifier struct{}
+
+func (failingNotifier) Notify(ctx context.Context, event models.Event) error {
+	return errors.New("webhook returned status 502")
+}
+
+// TestDeadLetterNotifier проверяет сохранение недоставленного события для повтора
+func TestDeadLetterNotifier(t *testing.T) {
+	store := &memoryDeliveryStore{}
+
+	err := NewDeadLetterNotifier(models.DeliveryTargetAlerts, NopNotifier{}, store).Notify(context.Background(), models.Event{ID: 1})
+	assert.NoError(t, err)
+	assert.Empty(t, store.events)
+
+	err = NewDeadLetterNotifier(models.DeliveryTargetAlerts, failingNotifier{}, store).Notify(context.Background(), models.Event{ID: 2})
+	assert.Error(t, err)
+	assert.Equal(t, []string{models.DeliveryTargetAlerts}, store.targets)
+	assert.Equal(t, int64(2), store.events[0].ID)
+}
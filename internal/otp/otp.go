@@ -0,0 +1,88 @@
+package otp
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// CodeLength - количество цифр в коде входа
+const CodeLength = 6
+
+// ErrInvalidPhone возвращается, если номер нельзя привести к формату E.164
+var ErrInvalidPhone = errors.New("invalid phone number")
+
+// GenerateCode генерирует случайный цифровой код входа
+func GenerateCode() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < CodeLength; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate otp code: %w", err)
+	}
+
+	return fmt.Sprintf("%0*d", CodeLength, n), nil
+}
+
+// HashCode возвращает хеш кода, привязанный к телефону, чтобы не хранить коды в открытом виде
+func HashCode(phone, code string) string {
+	sum := sha256.Sum256([]byte(phone + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
+
+// CheckCode сравнивает код с сохраненным хешем за постоянное время
+func CheckCode(phone, code, codeHash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashCode(phone, code)), []byte(codeHash)) == 1
+}
+
+// tokenBytes - количество случайных байт в одноразовом токене
+const tokenBytes = 32
+
+// GenerateToken генерирует случайный одноразовый токен для передачи в ссылке
+func GenerateToken() (string, error) {
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// HashToken возвращает хеш одноразового токена для хранения и поиска в БД
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizePhone приводит номер к формату E.164: убирает пробелы, скобки и дефисы,
+// российский префикс 8 заменяет на +7
+func NormalizePhone(input string) (string, error) {
+	var digits strings.Builder
+	for i, r := range strings.TrimSpace(input) {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' && i == 0:
+		case r == ' ' || r == '-' || r == '(' || r == ')':
+		default:
+			return "", fmt.Errorf("%w: %q", ErrInvalidPhone, input)
+		}
+	}
+
+	number := digits.String()
+	if len(number) == 11 && number[0] == '8' {
+		number = "7" + number[1:]
+	}
+	if len(number) < 10 || len(number) > 15 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPhone, input)
+	}
+
+	return "+" + number, nil
+}
@@ -0,0 +1,105 @@
+package otp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateCode(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		code, err := GenerateCode()
+		assert.NoError(t, err)
+		assert.Len(t, code, CodeLength)
+		assert.Regexp(t, `^\d+$`, code)
+	}
+}
+
+func TestCheckCode(t *testing.T) {
+	hash := HashCode("+79990001122", "123456")
+
+	assert.True(t, CheckCode("+79990001122", "123456", hash))
+	assert.False(t, CheckCode("+79990001122", "654321", hash))
+	// Хеш привязан к телефону
+	assert.False(t, CheckCode("+79990001133", "123456", hash))
+}
+
+func TestGenerateToken(t *testing.T) {
+	first, err := GenerateToken()
+	assert.NoError(t, err)
+	second, err := GenerateToken()
+	assert.NoError(t, err)
+
+	assert.Len(t, first, 64)
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, HashToken(first), HashToken(first))
+	assert.NotEqual(t, first, HashToken(first))
+}
+
+func TestNormalizePhone(t *testing.T) {
+	valid := map[string]string{
+		"+7 (999) 000-11-22": "+79990001122",
+		"89990001122":        "+79990001122",
+		"79990001122":        "+79990001122",
+		"+7 701 123 4567":    "+77011234567",
+	}
+	for input, expected := range valid {
+		phone, err := NormalizePhone(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, phone, input)
+	}
+
+	for _, input := range []string{"", "12345", "+7 999 abc 11 22", "7+9990001122"} {
+		_, err := NormalizePhone(input)
+		assert.ErrorIs(t, err, ErrInvalidPhone, input)
+	}
+}
+
+func TestTOTPCode(t *testing.T) {
+	// Тестовый вектор RFC 6238 для SHA1: секрет "12345678901234567890", 59 секунд
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	code, err := TOTPCode(secret, TOTPStep(time.Unix(59, 0)))
+	assert.NoError(t, err)
+	assert.Equal(t, "287082", code)
+
+	code, err = TOTPCode(secret, TOTPStep(time.Unix(1111111109, 0)))
+	assert.NoError(t, err)
+	assert.Equal(t, "081804", code)
+}
+
+func TestCheckTOTP(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	code, err := TOTPCode(secret, TOTPStep(now))
+	assert.NoError(t, err)
+
+	step, ok := CheckTOTP(secret, code, now)
+	assert.True(t, ok)
+	assert.Equal(t, TOTPStep(now), step)
+
+	// Соседний шаг принимается, более старый - нет
+	_, ok = CheckTOTP(secret, code, now.Add(TOTPPeriod))
+	assert.True(t, ok)
+	_, ok = CheckTOTP(secret, code, now.Add(3*TOTPPeriod))
+	assert.False(t, ok)
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := TOTPURI("PVZ Service", "moderator@example.com", "JBSWY3DPEHPK3PXP")
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/PVZ%20Service:moderator@example.com?"))
+	assert.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+	assert.Contains(t, uri, "issuer=PVZ+Service")
+}
+
+func TestGenerateBackupCode(t *testing.T) {
+	code, err := GenerateBackupCode()
+	assert.NoError(t, err)
+	assert.Regexp(t, `^[a-z2-7]{4}-[a-z2-7]{4}$`, code)
+	assert.Equal(t, strings.ReplaceAll(code, "-", ""), NormalizeBackupCode(" "+strings.ToUpper(code)))
+}
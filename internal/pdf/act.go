@@ -0,0 +1,213 @@
+package pdf
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/models"
+)
+
+// ReceptionAct - данные акта приёмки товаров
+type ReceptionAct struct {
+	Reception   models.Reception
+	PVZ         models.PVZ
+	Products    []models.Product
+	GeneratedAt time.Time
+}
+
+// Разметка акта
+const (
+	actMargin     = 50.0
+	actBottom     = PageHeight - 60
+	actRowHeight  = 16.0
+	actTextSize   = 10.0
+	actTitleSize  = 14.0
+	actFooterSize = 8.0
+)
+
+// actColumn - колонка таблицы товаров
+type actColumn struct {
+	title string
+	x     float64
+	width float64
+}
+
+var actColumns = []actColumn{
+	{"№", actMargin, 30},
+	{"Тип товара", actMargin + 30, 100},
+	{"Штрихкод", actMargin + 130, 200},
+	{"Время добавления", actMargin + 330, PageWidth - 2*actMargin - 330},
+}
+
+// actStatuses - статусы приёмки в тексте акта
+var actStatuses = map[string]string{
+	"in_progress": "открыта",
+	"paused":      "приостановлена",
+	"close":       "закрыта",
+}
+
+// RenderReceptionAct формирует акт приёмки: реквизиты приёмки и ПВЗ, таблицу товаров,
+// итоги по типам и блок подписей для передачи товаров курьеру
+func RenderReceptionAct(w io.Writer, act ReceptionAct) error {
+	doc, err := NewDocument()
+	if err != nil {
+		return err
+	}
+
+	// Время хранится в UTC, в акте оно выводится по часовому поясу ПВЗ
+	loc := actLocation(act.PVZ)
+
+	y := actMargin + actTitleSize
+	title := "АКТ ПРИЁМКИ ТОВАРОВ"
+	doc.Text((PageWidth-doc.TextWidth(title, actTitleSize, Bold))/2, y, actTitleSize, Bold, title)
+	y += 2 * actRowHeight
+
+	pvz := act.PVZ.City
+	if act.PVZ.Address != "" {
+		pvz += ", " + act.PVZ.Address
+	}
+	status := actStatuses[act.Reception.Status]
+	if status == "" {
+		status = act.Reception.Status
+	}
+
+	for _, field := range [][2]string{
+		{"Номер приёмки:", act.Reception.Number},
+		{"Приёмка:", act.Reception.ID},
+		{"Дата приёмки:", act.Reception.DateTime.In(loc).Format("02.01.2006 15:04") + " (" + loc.String() + ")"},
+		{"ПВЗ:", pvz},
+		{"ID ПВЗ:", act.PVZ.ID},
+		{"Статус приёмки:", status},
+	} {
+		doc.Text(actMargin, y, actTextSize, Bold, field[0])
+		doc.Text(actMargin+110, y, actTextSize, Regular, field[1])
+		y += actRowHeight
+	}
+	y += actRowHeight
+
+	y = actTableHeader(doc, y)
+	for i, product := range act.Products {
+		if y+actRowHeight > actBottom {
+			doc.AddPage()
+			y = actTableHeader(doc, actMargin+actTextSize)
+		}
+
+		barcode := ""
+		if product.Barcode != nil {
+			barcode = *product.Barcode
+		}
+		cells := []string{
+			fmt.Sprintf("%d", i+1),
+			product.Type,
+			barcode,
+			product.Datetime.In(loc).Format("02.01.2006 15:04:05"),
+		}
+		for j, column := range actColumns {
+			doc.Text(column.x+3, y, actTextSize, Regular, fitText(doc, cells[j], column.width-6, actTextSize, Regular))
+		}
+		y += actRowHeight
+	}
+	doc.Line(actMargin, y-actRowHeight+4, PageWidth-actMargin, y-actRowHeight+4)
+
+	// Итоги и подписи не разрываются между страницами
+	summary := actSummary(act.Products)
+	if y+float64(len(summary)+8)*actRowHeight > actBottom {
+		doc.AddPage()
+		y = actMargin
+	}
+
+	y += actRowHeight
+	for i, line := range summary {
+		style := Regular
+		if i == 0 {
+			style = Bold
+		}
+		doc.Text(actMargin, y, actTextSize, style, line)
+		y += actRowHeight
+	}
+
+	y += 2 * actRowHeight
+	for _, signer := range []string{"Сдал (сотрудник ПВЗ)", "Принял (курьер)"} {
+		doc.Text(actMargin, y, actTextSize, Regular, signer+":")
+		doc.Line(actMargin+150, y+2, actMargin+290, y+2)
+		doc.Line(actMargin+310, y+2, PageWidth-actMargin, y+2)
+		doc.Text(actMargin+190, y+12, actFooterSize, Regular, "подпись")
+		doc.Text(actMargin+360, y+12, actFooterSize, Regular, "расшифровка подписи")
+		y += 3 * actRowHeight
+	}
+	doc.Text(actMargin, y, actTextSize, Regular, "Дата: «___» ____________ 20___ г.")
+
+	// Нумерация страниц и время формирования - после разметки, когда известно число страниц
+	for page := 0; page < doc.PageCount(); page++ {
+		doc.SetPage(page)
+		footer := fmt.Sprintf("Сформирован %s · Страница %d из %d", act.GeneratedAt.In(loc).Format("02.01.2006 15:04"), page+1, doc.PageCount())
+		doc.Text(PageWidth-actMargin-doc.TextWidth(footer, actFooterSize, Regular), PageHeight-30, actFooterSize, Regular, footer)
+	}
+
+	_, err = doc.WriteTo(w)
+	return err
+}
+
+// actLocation возвращает часовой пояс отображения времени ПВЗ.
+// Неизвестный пояс не мешает сформировать акт, время выводится в UTC
+func actLocation(pvz models.PVZ) *time.Location {
+	if pvz.Timezone == nil {
+		return time.UTC
+	}
+	loc, err := clock.Location(*pvz.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// actTableHeader выводит заголовок таблицы товаров и возвращает положение первой строки
+func actTableHeader(doc *Document, y float64) float64 {
+	for _, column := range actColumns {
+		doc.Text(column.x+3, y, actTextSize, Bold, column.title)
+	}
+	doc.Line(actMargin, y-actRowHeight+4, PageWidth-actMargin, y-actRowHeight+4)
+	doc.Line(actMargin, y+4, PageWidth-actMargin, y+4)
+	return y + actRowHeight
+}
+
+// actSummary возвращает строки итогов: общее количество и количество по типам
+func actSummary(products []models.Product) []string {
+	byType := make(map[string]int)
+	for _, product := range products {
+		byType[product.Type]++
+	}
+
+	types := make([]string, 0, len(byType))
+	for productType := range byType {
+		types = append(types, productType)
+	}
+	sort.Strings(types)
+
+	lines := []string{fmt.Sprintf("Итого товаров: %d", len(products))}
+	for _, productType := range types {
+		lines = append(lines, fmt.Sprintf("%s: %d", productType, byType[productType]))
+	}
+	return lines
+}
+
+// fitText обрезает строку многоточием, чтобы она поместилась в ширину width
+func fitText(doc *Document, s string, width, size float64, style Style) string {
+	if doc.TextWidth(s, size, style) <= width {
+		return s
+	}
+
+	runes := []rune(s)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := strings.TrimSpace(string(runes)) + "…"
+		if doc.TextWidth(candidate, size, style) <= width {
+			return candidate
+		}
+	}
+	return ""
+}
@@ -0,0 +1,104 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/models"
+)
+
+func testAct(products int) ReceptionAct {
+	act := ReceptionAct{
+		Reception:   models.Reception{ID: "11111111-1111-1111-1111-111111111111", Number: "MSK001-2026-000001", DateTime: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC), Status: "close"},
+		PVZ:         models.PVZ{ID: "22222222-2222-2222-2222-222222222222", City: "Москва", Address: "ул. Тверская, 1"},
+		GeneratedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	types := []string{"электроника", "одежда", "обувь"}
+	for i := 0; i < products; i++ {
+		barcode := fmt.Sprintf("460%010d", i)
+		act.Products = append(act.Products, models.Product{
+			ID:       fmt.Sprintf("p-%d", i),
+			Type:     types[i%len(types)],
+			Datetime: act.Reception.DateTime.Add(time.Duration(i) * time.Minute),
+			Barcode:  &barcode,
+		})
+	}
+	return act
+}
+
+func TestRenderReceptionAct(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, RenderReceptionAct(&buf, testAct(3)))
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "%PDF-1.4"))
+	assert.True(t, strings.HasSuffix(out, "%%EOF\n"))
+	assert.Contains(t, out, "/Count 1 ")
+	assert.Contains(t, out, "/FontFile2")
+	assert.Contains(t, out, "/ToUnicode")
+}
+
+func TestRenderReceptionAct_Paginates(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, RenderReceptionAct(&buf, testAct(100)))
+
+	assert.Contains(t, buf.String(), "/Count 3 ")
+}
+
+func TestRenderReceptionAct_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, RenderReceptionAct(&buf, testAct(0)))
+
+	assert.Contains(t, buf.String(), "/Count 1 ")
+}
+
+func TestActSummary(t *testing.T) {
+	lines := actSummary(testAct(4).Products)
+
+	assert.Equal(t, []string{"Итого товаров: 4", "обувь: 1", "одежда: 1", "электроника: 2"}, lines)
+}
+
+func TestFitText(t *testing.T) {
+	doc, err := NewDocument()
+	require.NoError(t, err)
+
+	assert.Equal(t, "обувь", fitText(doc, "обувь", 100, actTextSize, Regular))
+
+	long := strings.Repeat("Ж", 50)
+	fitted := fitText(doc, long, 100, actTextSize, Regular)
+	assert.True(t, strings.HasSuffix(fitted, "…"))
+	assert.LessOrEqual(t, doc.TextWidth(fitted, actTextSize, Regular), 100.0)
+}
+
+func TestTextWidth_Cyrillic(t *testing.T) {
+	doc, err := NewDocument()
+	require.NoError(t, err)
+
+	gi, _ := doc.fonts[Regular].glyph('Ж')
+	assert.NotZero(t, gi)
+	assert.Greater(t, doc.TextWidth("Акт", 10, Bold), 0.0)
+	assert.Greater(t, doc.TextWidth("Акт", 20, Regular), doc.TextWidth("Акт", 10, Regular))
+}
+
+func TestNum(t *testing.T) {
+	assert.Equal(t, "0", num(0))
+	assert.Equal(t, "10", num(10))
+	assert.Equal(t, "595.28", num(PageWidth))
+	assert.Equal(t, "1.5", num(1.5))
+}
+
+func TestActLocation(t *testing.T) {
+	assert.Equal(t, time.UTC, actLocation(models.PVZ{}))
+
+	timezone := "Asia/Almaty"
+	assert.Equal(t, timezone, actLocation(models.PVZ{Timezone: &timezone}).String())
+
+	unknown := "Mars/Olympus"
+	assert.Equal(t, time.UTC, actLocation(models.PVZ{Timezone: &unknown}))
+}
@@ -0,0 +1,159 @@
+package pdf
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"pvz-service/internal/models"
+)
+
+// Custody - данные документа о цепочке хранения приёмки
+type Custody struct {
+	Document models.CustodyDocument
+	PVZ      models.PVZ
+}
+
+// custodyColumns - колонки таблицы действий
+var custodyColumns = []actColumn{
+	{"Время", actMargin, 95},
+	{"Действие", actMargin + 95, 115},
+	{"Исполнитель", actMargin + 210, 130},
+	{"Товар", actMargin + 340, PageWidth - 2*actMargin - 340},
+}
+
+// custodyActions - действия в тексте документа
+var custodyActions = map[string]string{
+	models.CustodyReceptionOpened:     "Открытие приёмки",
+	models.CustodyProductAdded:        "Добавление товара",
+	models.CustodyProductRetyped:      "Исправление типа",
+	models.CustodyProductDeleted:      "Удаление товара",
+	models.CustodyReceptionReassigned: "Перенос в другой ПВЗ",
+	models.CustodyReceptionClosed:     "Закрытие приёмки",
+}
+
+// RenderCustody формирует документ о цепочке хранения приёмки: реквизиты приёмки и ПВЗ
+// и все действия с приёмкой и ее товарами с исполнителем и временем
+func RenderCustody(w io.Writer, custody Custody) error {
+	doc, err := NewDocument()
+	if err != nil {
+		return err
+	}
+
+	// Время хранится в UTC, в документе оно выводится по часовому поясу ПВЗ
+	loc := actLocation(custody.PVZ)
+	document := custody.Document
+
+	y := actMargin + actTitleSize
+	title := "ЦЕПОЧКА ХРАНЕНИЯ ПРИЁМКИ"
+	doc.Text((PageWidth-doc.TextWidth(title, actTitleSize, Bold))/2, y, actTitleSize, Bold, title)
+	y += 2 * actRowHeight
+
+	pvz := custody.PVZ.City
+	if custody.PVZ.Address != "" {
+		pvz += ", " + custody.PVZ.Address
+	}
+	status := actStatuses[document.Status]
+	if status == "" {
+		status = document.Status
+	}
+
+	for _, field := range [][2]string{
+		{"Номер приёмки:", document.Number},
+		{"Приёмка:", document.ReceptionID},
+		{"ПВЗ:", pvz},
+		{"ID ПВЗ:", document.PvzID},
+		{"Статус приёмки:", status},
+		{"Часовой пояс:", loc.String()},
+	} {
+		doc.Text(actMargin, y, actTextSize, Bold, field[0])
+		doc.Text(actMargin+110, y, actTextSize, Regular, field[1])
+		y += actRowHeight
+	}
+	y += actRowHeight
+
+	y = custodyTableHeader(doc, y)
+	for _, entry := range document.Entries {
+		// Подробности действия выводятся отдельной строкой под действием, чтобы не обрезались
+		height := actRowHeight
+		if entry.Details != nil {
+			height += actRowHeight
+		}
+		if y+height > actBottom {
+			doc.AddPage()
+			y = custodyTableHeader(doc, actMargin+actTextSize)
+		}
+
+		cells := []string{custodyTime(entry, loc), custodyAction(entry), custodyActor(entry), custodyProduct(entry)}
+		for j, column := range custodyColumns {
+			doc.Text(column.x+3, y, actTextSize, Regular, fitText(doc, cells[j], column.width-6, actTextSize, Regular))
+		}
+		if entry.Details != nil {
+			details := custodyColumns[1]
+			doc.Text(details.x+3, y+actRowHeight-4, actFooterSize, Regular, fitText(doc, *entry.Details, PageWidth-actMargin-details.x-6, actFooterSize, Regular))
+		}
+		y += height
+	}
+	doc.Line(actMargin, y-actRowHeight+4, PageWidth-actMargin, y-actRowHeight+4)
+
+	for page := 0; page < doc.PageCount(); page++ {
+		doc.SetPage(page)
+		footer := fmt.Sprintf("Сформирован %s · Страница %d из %d", document.GeneratedAt.In(loc).Format("02.01.2006 15:04"), page+1, doc.PageCount())
+		doc.Text(PageWidth-actMargin-doc.TextWidth(footer, actFooterSize, Regular), PageHeight-30, actFooterSize, Regular, footer)
+	}
+
+	_, err = doc.WriteTo(w)
+	return err
+}
+
+// custodyTableHeader выводит заголовок таблицы действий и возвращает положение первой строки
+func custodyTableHeader(doc *Document, y float64) float64 {
+	for _, column := range custodyColumns {
+		doc.Text(column.x+3, y, actTextSize, Bold, column.title)
+	}
+	doc.Line(actMargin, y-actRowHeight+4, PageWidth-actMargin, y-actRowHeight+4)
+	doc.Line(actMargin, y+4, PageWidth-actMargin, y+4)
+	return y + actRowHeight
+}
+
+func custodyTime(entry models.CustodyEntry, loc *time.Location) string {
+	if entry.At == nil {
+		return "не записано"
+	}
+	return entry.At.In(loc).Format("02.01.2006 15:04:05")
+}
+
+func custodyAction(entry models.CustodyEntry) string {
+	if action, ok := custodyActions[entry.Action]; ok {
+		return action
+	}
+	return entry.Action
+}
+
+// custodyActor возвращает email исполнителя, его ID, если пользователь не найден,
+// или «система» для действий из консоли и фоновых заданий
+func custodyActor(entry models.CustodyEntry) string {
+	switch {
+	case entry.ActorEmail != nil:
+		return *entry.ActorEmail
+	case entry.ActorID != nil:
+		return *entry.ActorID
+	default:
+		return "система"
+	}
+}
+
+// custodyProduct возвращает тип товара и его штрихкод, а без штрихкода - ID товара
+func custodyProduct(entry models.CustodyEntry) string {
+	if entry.ProductID == nil {
+		return ""
+	}
+	product := *entry.ProductID
+	if entry.Barcode != nil {
+		product = *entry.Barcode
+	}
+	if entry.ProductType != nil {
+		product = *entry.ProductType + ", " + product
+	}
+	return product
+}
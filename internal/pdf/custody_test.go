@@ -0,0 +1,71 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/models"
+)
+
+func testCustody(products int) Custody {
+	opened := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	actor := "33333333-3333-3333-3333-333333333333"
+	email := "employee@example.com"
+	custody := Custody{
+		Document: models.CustodyDocument{
+			ReceptionID: "11111111-1111-1111-1111-111111111111",
+			Number:      "MSK001-2026-000001",
+			PvzID:       "22222222-2222-2222-2222-222222222222",
+			Status:      "close",
+			Entries:     []models.CustodyEntry{{Action: models.CustodyReceptionOpened, At: &opened, ActorID: &actor, ActorEmail: &email}},
+			GeneratedAt: opened.Add(time.Hour),
+		},
+		PVZ: models.PVZ{ID: "22222222-2222-2222-2222-222222222222", City: "Москва"},
+	}
+	for i := 0; i < products; i++ {
+		at := opened.Add(time.Duration(i) * time.Minute)
+		productID := fmt.Sprintf("p-%d", i)
+		productType := "обувь"
+		details := "одежда -> обувь"
+		custody.Document.Entries = append(custody.Document.Entries,
+			models.CustodyEntry{Action: models.CustodyProductAdded, At: &at, ActorID: &actor, ProductID: &productID, ProductType: &productType},
+			models.CustodyEntry{Action: models.CustodyProductRetyped, At: &at, ActorID: &actor, ProductID: &productID, ProductType: &productType, Details: &details},
+		)
+	}
+	// Приёмка закрыта до учета времени закрытия из консоли
+	custody.Document.Entries = append(custody.Document.Entries, models.CustodyEntry{Action: models.CustodyReceptionClosed})
+	return custody
+}
+
+func TestRenderCustody(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, RenderCustody(&buf, testCustody(2)))
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "%PDF-1.4"))
+	assert.Contains(t, out, "/Count 1 ")
+}
+
+func TestRenderCustody_Paginates(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, RenderCustody(&buf, testCustody(60)))
+
+	assert.Contains(t, buf.String(), "/Count 5 ")
+}
+
+func TestCustodyCells(t *testing.T) {
+	entries := testCustody(1).Document.Entries
+
+	assert.Equal(t, "employee@example.com", custodyActor(entries[0]))
+	assert.Equal(t, "33333333-3333-3333-3333-333333333333", custodyActor(entries[1]))
+	assert.Equal(t, "система", custodyActor(entries[3]))
+	assert.Equal(t, "обувь, p-0", custodyProduct(entries[1]))
+	assert.Equal(t, "Исправление типа", custodyAction(entries[2]))
+	assert.Equal(t, "не записано", custodyTime(entries[3], time.UTC))
+}
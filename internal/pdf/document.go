@@ -0,0 +1,213 @@
+// Package pdf формирует печатные документы сервиса в формате PDF.
+// Поддерживается только то, что нужно для актов и этикеток: страницы A4, текст
+// встроенными шрифтами с кириллицей и линии
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Размер страницы A4 в пунктах
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Style - начертание шрифта
+type Style int
+
+// Начертания шрифта
+const (
+	Regular Style = iota
+	Bold
+)
+
+// Document - PDF документ из страниц A4. Координаты отсчитываются
+// от левого верхнего угла страницы в пунктах
+type Document struct {
+	fonts [2]*usedFont
+	pages []*bytes.Buffer
+	page  int
+}
+
+// NewDocument создает пустой документ
+func NewDocument() (*Document, error) {
+	faces, err := loadFaces()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Document{
+		fonts: [2]*usedFont{newUsedFont(faces[Regular]), newUsedFont(faces[Bold])},
+		page:  -1,
+	}, nil
+}
+
+// AddPage добавляет страницу и делает ее текущей
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.page = len(d.pages) - 1
+}
+
+// PageCount возвращает количество страниц
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// SetPage делает текущей страницу с номером page, считая с нуля.
+// Нужна, например, чтобы дописать нумерацию страниц после разметки документа
+func (d *Document) SetPage(page int) {
+	if page >= 0 && page < len(d.pages) {
+		d.page = page
+	}
+}
+
+// Text выводит строку на текущей странице. y - положение базовой линии
+func (d *Document) Text(x, y, size float64, style Style, s string) {
+	fmt.Fprintf(d.current(), "BT /F%d %s Tf %s %s Td %s Tj ET\n",
+		style+1, num(size), num(x), num(PageHeight-y), d.fonts[style].encode(s))
+}
+
+// TextWidth возвращает ширину строки в пунктах
+func (d *Document) TextWidth(s string, size float64, style Style) float64 {
+	return d.fonts[style].width(s, size)
+}
+
+// Line рисует линию толщиной 0,5 пункта
+func (d *Document) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(d.current(), "0.5 w %s %s m %s %s l S\n", num(x1), num(PageHeight-y1), num(x2), num(PageHeight-y2))
+}
+
+// current возвращает содержимое текущей страницы, создавая первую страницу при необходимости
+func (d *Document) current() *bytes.Buffer {
+	if d.page < 0 {
+		d.AddPage()
+	}
+	return d.pages[d.page]
+}
+
+// WriteTo записывает документ в w
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	out := &pdfWriter{}
+	out.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Номера объектов: 1 - каталог, 2 - дерево страниц, далее по 5 объектов
+	// на шрифт и по 2 на страницу
+	const catalogID, pagesID, fontObjects = 1, 2, 5
+	fontID := func(style int) int { return 3 + style*fontObjects }
+	pageID := func(page int) int { return 3 + len(d.fonts)*fontObjects + page*2 }
+
+	out.object(catalogID, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesID))
+
+	kids := ""
+	for i := range d.pages {
+		kids += fmt.Sprintf("%d 0 R ", pageID(i))
+	}
+	out.object(pagesID, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", kids, len(d.pages)))
+
+	for style, used := range d.fonts {
+		id := fontID(style)
+		face := used.face
+		out.object(id, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+			face.name, id+1, id+4))
+		out.object(id+1, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /W %s /CIDToGIDMap /Identity >>",
+			face.name, id+2, used.widthsArray()))
+		out.object(id+2, fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+			face.name, face.bbox[0], face.bbox[1], face.bbox[2], face.bbox[3], face.ascent, face.descent, face.capHeight, id+3))
+		if err := out.stream(id+3, fmt.Sprintf("/Length1 %d", len(face.data)), face.data); err != nil {
+			return 0, err
+		}
+		if err := out.stream(id+4, "", []byte(used.toUnicode())); err != nil {
+			return 0, err
+		}
+	}
+
+	fontResources := ""
+	for style := range d.fonts {
+		fontResources += fmt.Sprintf("/F%d %d 0 R ", style+1, fontID(style))
+	}
+	for i, content := range d.pages {
+		id := pageID(i)
+		out.object(id, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources << /Font << %s>> >> /Contents %d 0 R >>",
+			pagesID, num(PageWidth), num(PageHeight), fontResources, id+1))
+		if err := out.stream(id+1, "", content.Bytes()); err != nil {
+			return 0, err
+		}
+	}
+
+	out.finish(catalogID)
+
+	n, err := w.Write(out.buf.Bytes())
+	return int64(n), err
+}
+
+// pdfWriter собирает объекты документа и запоминает их смещения для таблицы xref
+type pdfWriter struct {
+	buf     bytes.Buffer
+	offsets map[int]int
+}
+
+// object записывает объект с номером id
+func (w *pdfWriter) object(id int, body string) {
+	w.begin(id)
+	w.buf.WriteString(body)
+	w.buf.WriteString("\nendobj\n")
+}
+
+// stream записывает поток, сжатый FlateDecode. extra - дополнительные ключи словаря потока
+func (w *pdfWriter) stream(id int, extra string, data []byte) error {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(data); err != nil {
+		return fmt.Errorf("failed to compress stream: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to compress stream: %w", err)
+	}
+
+	if extra != "" {
+		extra = " " + extra
+	}
+
+	w.begin(id)
+	fmt.Fprintf(&w.buf, "<< /Length %d /Filter /FlateDecode%s >>\nstream\n", compressed.Len(), extra)
+	w.buf.Write(compressed.Bytes())
+	w.buf.WriteString("\nendstream\nendobj\n")
+	return nil
+}
+
+func (w *pdfWriter) begin(id int) {
+	if w.offsets == nil {
+		w.offsets = make(map[int]int)
+	}
+	w.offsets[id] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n", id)
+}
+
+// finish записывает таблицу xref и трейлер
+func (w *pdfWriter) finish(rootID int) {
+	size := len(w.offsets) + 1
+	xref := w.buf.Len()
+
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", size)
+	for id := 1; id < size; id++ {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", w.offsets[id])
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", size, rootID, xref)
+}
+
+// num форматирует число для PDF с точностью до сотых без лишних нулей
+func num(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,207 @@
+package pdf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf16"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// fontFace - разобранный шрифт TrueType и его метрики в единицах PDF (1/1000 кегля)
+type fontFace struct {
+	name      string
+	data      []byte
+	font      *sfnt.Font
+	ppem      fixed.Int26_6
+	unitsPerM float64
+	ascent    int
+	descent   int
+	capHeight int
+	bbox      [4]int
+}
+
+var (
+	facesOnce sync.Once
+	faces     [2]*fontFace
+	facesErr  error
+)
+
+// loadFaces разбирает встроенные шрифты Go один раз на процесс.
+// Шрифты Go содержат кириллицу и распространяются под лицензией BSD
+func loadFaces() ([2]*fontFace, error) {
+	facesOnce.Do(func() {
+		for style, src := range map[Style]struct {
+			name string
+			data []byte
+		}{
+			Regular: {"GoRegular", goregular.TTF},
+			Bold:    {"GoBold", gobold.TTF},
+		} {
+			faces[style], facesErr = parseFace(src.name, src.data)
+			if facesErr != nil {
+				return
+			}
+		}
+	})
+	return faces, facesErr
+}
+
+// parseFace разбирает шрифт и переводит его метрики в единицы PDF
+func parseFace(name string, data []byte) (*fontFace, error) {
+	f, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font %s: %w", name, err)
+	}
+
+	face := &fontFace{
+		name:      name,
+		data:      data,
+		font:      f,
+		ppem:      fixed.I(int(f.UnitsPerEm())),
+		unitsPerM: float64(f.UnitsPerEm()),
+	}
+
+	var buf sfnt.Buffer
+	metrics, err := f.Metrics(&buf, face.ppem, font.HintingNone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read font %s metrics: %w", name, err)
+	}
+	bounds, err := f.Bounds(&buf, face.ppem, font.HintingNone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read font %s bounds: %w", name, err)
+	}
+
+	// В sfnt ось Y направлена вниз, в PDF - вверх
+	face.ascent = face.scale(metrics.Ascent)
+	face.descent = -face.scale(metrics.Descent)
+	face.capHeight = face.scale(metrics.CapHeight)
+	face.bbox = [4]int{face.scale(bounds.Min.X), -face.scale(bounds.Max.Y), face.scale(bounds.Max.X), -face.scale(bounds.Min.Y)}
+
+	return face, nil
+}
+
+// scale переводит значение в единицах шрифта в 1/1000 кегля
+func (f *fontFace) scale(v fixed.Int26_6) int {
+	return int(float64(v) / 64 * 1000 / f.unitsPerM)
+}
+
+// usedFont отслеживает глифы шрифта, использованные в документе:
+// по ним строятся ширины и таблица обратного соответствия Unicode
+type usedFont struct {
+	face   *fontFace
+	buf    sfnt.Buffer
+	glyphs map[rune]sfnt.GlyphIndex
+	widths map[sfnt.GlyphIndex]int
+	runes  map[sfnt.GlyphIndex]rune
+}
+
+func newUsedFont(face *fontFace) *usedFont {
+	return &usedFont{
+		face:   face,
+		glyphs: make(map[rune]sfnt.GlyphIndex),
+		widths: make(map[sfnt.GlyphIndex]int),
+		runes:  make(map[sfnt.GlyphIndex]rune),
+	}
+}
+
+// glyph возвращает глиф символа и его ширину. Символы, которых нет в шрифте, выводятся пустым глифом
+func (u *usedFont) glyph(r rune) (sfnt.GlyphIndex, int) {
+	if gi, ok := u.glyphs[r]; ok {
+		return gi, u.widths[gi]
+	}
+
+	gi, err := u.face.font.GlyphIndex(&u.buf, r)
+	if err != nil {
+		gi = 0
+	}
+	advance, err := u.face.font.GlyphAdvance(&u.buf, gi, u.face.ppem, font.HintingNone)
+	if err != nil {
+		advance = 0
+	}
+
+	u.glyphs[r] = gi
+	u.widths[gi] = u.face.scale(advance)
+	if _, ok := u.runes[gi]; !ok && gi != 0 {
+		u.runes[gi] = r
+	}
+	return gi, u.widths[gi]
+}
+
+// width возвращает ширину строки в пунктах при кегле size
+func (u *usedFont) width(s string, size float64) float64 {
+	total := 0
+	for _, r := range s {
+		_, w := u.glyph(r)
+		total += w
+	}
+	return float64(total) * size / 1000
+}
+
+// encode кодирует строку номерами глифов для шрифта с кодировкой Identity-H
+func (u *usedFont) encode(s string) string {
+	var b strings.Builder
+	b.WriteByte('<')
+	for _, r := range s {
+		gi, _ := u.glyph(r)
+		fmt.Fprintf(&b, "%04X", uint16(gi))
+	}
+	b.WriteByte('>')
+	return b.String()
+}
+
+// sortedGlyphs возвращает использованные глифы по возрастанию номера
+func (u *usedFont) sortedGlyphs() []sfnt.GlyphIndex {
+	glyphs := make([]sfnt.GlyphIndex, 0, len(u.widths))
+	for gi := range u.widths {
+		glyphs = append(glyphs, gi)
+	}
+	sort.Slice(glyphs, func(i, j int) bool { return glyphs[i] < glyphs[j] })
+	return glyphs
+}
+
+// widthsArray строит массив W шрифта CIDFontType2
+func (u *usedFont) widthsArray() string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for _, gi := range u.sortedGlyphs() {
+		fmt.Fprintf(&b, "%d [%d] ", gi, u.widths[gi])
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// toUnicode строит CMap, по которой программы просмотра копируют и ищут текст
+func (u *usedFont) toUnicode() string {
+	var entries []string
+	for _, gi := range u.sortedGlyphs() {
+		r, ok := u.runes[gi]
+		if !ok {
+			continue
+		}
+		var code strings.Builder
+		for _, unit := range utf16.Encode([]rune{r}) {
+			fmt.Fprintf(&code, "%04X", unit)
+		}
+		entries = append(entries, fmt.Sprintf("<%04X> <%s>", uint16(gi), code.String()))
+	}
+
+	var b strings.Builder
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n")
+	b.WriteString("/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n")
+	b.WriteString("/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n")
+	b.WriteString("1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	// В одном блоке bfchar допускается не более 100 записей
+	for start := 0; start < len(entries); start += 100 {
+		end := min(start+100, len(entries))
+		fmt.Fprintf(&b, "%d beginbfchar\n%s\nendbfchar\n", end-start, strings.Join(entries[start:end], "\n"))
+	}
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return b.String()
+}
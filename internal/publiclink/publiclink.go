@@ -0,0 +1,59 @@
+// Package publiclink выдает подписанные токены публичных ссылок на приёмки.
+// Токен содержит ID приёмки и подпись, поэтому не хранится в базе: поставщик
+// проверяет по ссылке статус сданного груза без учетной записи
+package publiclink
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// signatureSize - длина подписи в токене, укороченной до 128 бит, чтобы ссылка оставалась короткой
+const signatureSize = 16
+
+// ErrInvalidToken возвращается для токена, который не выдан этим ключом
+var ErrInvalidToken = errors.New("invalid public link token")
+
+// Signer подписывает и проверяет токены публичных ссылок
+type Signer struct {
+	secret []byte
+}
+
+// NewSigner создает Signer с ключом подписи secret. Пустой ключ отключает публичные ссылки: возвращается nil
+func NewSigner(secret string) *Signer {
+	if secret == "" {
+		return nil
+	}
+	return &Signer{secret: []byte(secret)}
+}
+
+// Sign возвращает токен ссылки на приёмку receptionID
+func (s *Signer) Sign(receptionID uuid.UUID) string {
+	payload := receptionID[:]
+	return base64.RawURLEncoding.EncodeToString(append(payload, s.signature(payload)...))
+}
+
+// Verify проверяет подпись токена и возвращает ID приёмки
+func (s *Signer) Verify(token string) (uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) != len(uuid.UUID{})+signatureSize {
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	payload, signature := raw[:len(uuid.UUID{})], raw[len(uuid.UUID{}):]
+	if !hmac.Equal(signature, s.signature(payload)) {
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	return uuid.FromBytes(payload)
+}
+
+func (s *Signer) signature(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)[:signatureSize]
+}
@@ -0,0 +1,30 @@
+package publiclink
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner(t *testing.T) {
+	signer := NewSigner("secret")
+	receptionID := uuid.MustParse("423e4567-e89b-12d3-a456-426614174001")
+
+	token := signer.Sign(receptionID)
+	got, err := signer.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, receptionID, got)
+
+	_, err = NewSigner("other").Verify(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = signer.Verify(token[:len(token)-1] + "A")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = signer.Verify("not-a-token")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	assert.Nil(t, NewSigner(""))
+}
@@ -0,0 +1,99 @@
+package pvzimport
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MaxRows ограничивает количество ПВЗ в одном файле импорта
+const MaxRows = 1000
+
+// Row представляет строку файла импорта ПВЗ
+type Row struct {
+	// Line - номер строки в файле с учетом заголовка
+	Line    int
+	City    string
+	Address string
+}
+
+// ParseCSV разбирает файл импорта в формате CSV с заголовком city,address.
+// Значения строк не проверяются: проверка выполняется построчно при импорте
+func ParseCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("import file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv: %w", err)
+	}
+
+	// Определяем порядок колонок по заголовку, Excel добавляет в начало файла BOM
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	cityColumn, ok := columns["city"]
+	if !ok {
+		return nil, errors.New("import header must contain a city column")
+	}
+	addressColumn, ok := columns["address"]
+	if !ok {
+		return nil, errors.New("import header must contain an address column")
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv: %w", err)
+		}
+		if isBlank(record) {
+			continue
+		}
+		if len(rows) == MaxRows {
+			return nil, fmt.Errorf("import file exceeds %d rows", MaxRows)
+		}
+
+		// Номер строки берем у reader: пустые строки он пропускает
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, Row{
+			Line:    line,
+			City:    cell(record, cityColumn),
+			Address: cell(record, addressColumn),
+		})
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("import file has no rows")
+	}
+
+	return rows, nil
+}
+
+// cell возвращает значение ячейки или пустую строку для коротких строк
+func cell(record []string, column int) string {
+	if column >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[column])
+}
+
+// isBlank сообщает, что строка не содержит значений
+func isBlank(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
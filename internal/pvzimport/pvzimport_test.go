@@ -0,0 +1,31 @@
+package pvzimport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCSV(t *testing.T) {
+	t.Run("Колонки в произвольном порядке", func(t *testing.T) {
+		rows, err := ParseCSV(strings.NewReader("\ufeffAddress,City\n\"ул. Ленина, 1\",Москва\n\n,Казань\n"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Row{
+			{Line: 2, City: "Москва", Address: "ул. Ленина, 1"},
+			{Line: 4, City: "Казань", Address: ""},
+		}, rows)
+	})
+
+	t.Run("Ошибки формата", func(t *testing.T) {
+		_, err := ParseCSV(strings.NewReader("address\nул. Ленина, 1\n"))
+		assert.ErrorContains(t, err, "city column")
+
+		_, err = ParseCSV(strings.NewReader("city,address\n"))
+		assert.ErrorContains(t, err, "no rows")
+
+		_, err = ParseCSV(strings.NewReader("city,address\n" + strings.Repeat("Москва,ул. Ленина\n", MaxRows+1)))
+		assert.ErrorContains(t, err, "exceeds")
+	})
+}
@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// idleTTL - время, после которого неиспользуемое ведро удаляется из памяти
+const idleTTL = 10 * time.Minute
+
+// Limit задает лимит запросов: средняя скорость и допустимый всплеск
+type Limit struct {
+	RequestsPerMinute int
+	Burst             int
+}
+
+// bucket - ведро токенов одного ключа
+type bucket struct {
+	tokens   float64
+	updated  time.Time
+	capacity float64
+}
+
+// Limiter ограничивает частоту запросов по ключу алгоритмом token bucket.
+// Состояние хранится в памяти процесса
+type Limiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewLimiter создает новый экземпляр Limiter
+func NewLimiter() *Limiter {
+	return &Limiter{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow расходует токен ключа и сообщает, разрешен ли запрос.
+// Если запрос отклонен, возвращается время до появления следующего токена
+func (l *Limiter) Allow(key string, limit Limit) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	rate := float64(limit.RequestsPerMinute) / 60
+	capacity := float64(limit.Burst)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now, capacity: capacity}
+		l.buckets[key] = b
+	}
+
+	// Лимит ключа мог измениться: ограничиваем накопленные токены новой емкостью
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.updated).Seconds()*rate)
+	b.updated = now
+	b.capacity = capacity
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - b.tokens) / rate * float64(time.Second))
+	return false, wait
+}
+
+// sweep удаляет ведра, не использовавшиеся дольше idleTTL, не чаще раза в минуту
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) > idleTTL {
+			delete(l.buckets, key)
+		}
+	}
+}
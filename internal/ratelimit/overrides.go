@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"pvz-service/internal/db/queries"
+)
+
+// OverridesInterface определяет интерфейс хранилища индивидуальных лимитов
+type OverridesInterface interface {
+	Lookup(ctx context.Context, key string) (Limit, bool)
+	Invalidate()
+}
+
+// Overrides кэширует индивидуальные лимиты из базы данных и перечитывает их по истечении ttl
+type Overrides struct {
+	rateLimitQueries queries.RateLimitQueriesInterface
+	ttl              time.Duration
+
+	mu       sync.RWMutex
+	limits   map[string]Limit
+	loadedAt time.Time
+}
+
+// NewOverrides создает новый экземпляр Overrides
+func NewOverrides(rateLimitQueries queries.RateLimitQueriesInterface, ttl time.Duration) *Overrides {
+	return &Overrides{
+		rateLimitQueries: rateLimitQueries,
+		ttl:              ttl,
+	}
+}
+
+// Lookup возвращает индивидуальный лимит ключа, если он задан
+func (o *Overrides) Lookup(ctx context.Context, key string) (Limit, bool) {
+	limit, ok := o.getLimits(ctx)[key]
+	return limit, ok
+}
+
+// Invalidate сбрасывает кэш, чтобы изменения администратора применились сразу
+func (o *Overrides) Invalidate() {
+	o.mu.Lock()
+	o.loadedAt = time.Time{}
+	o.mu.Unlock()
+}
+
+// getLimits возвращает кэш лимитов, перечитывая его по истечении ttl.
+// При ошибке базы данных используется прежний кэш
+func (o *Overrides) getLimits(ctx context.Context) map[string]Limit {
+	o.mu.RLock()
+	limits, loadedAt := o.limits, o.loadedAt
+	o.mu.RUnlock()
+
+	if limits != nil && time.Since(loadedAt) < o.ttl {
+		return limits
+	}
+
+	overrides, err := o.rateLimitQueries.GetOverrides(ctx)
+	if err != nil {
+		// Следующая попытка - через ttl, чтобы не нагружать базу на каждом запросе
+		log.Printf("Failed to load rate limit overrides: %v", err)
+		if limits == nil {
+			limits = map[string]Limit{}
+		}
+	} else {
+		limits = make(map[string]Limit, len(overrides))
+		for _, override := range overrides {
+			limits[override.Key] = Limit{
+				RequestsPerMinute: override.RequestsPerMinute,
+				Burst:             override.Burst,
+			}
+		}
+	}
+
+	o.mu.Lock()
+	o.limits, o.loadedAt = limits, time.Now()
+	o.mu.Unlock()
+
+	return limits
+}
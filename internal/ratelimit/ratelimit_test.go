@@ -0,0 +1,120 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/models"
+)
+
+func newTestLimiter(now *time.Time) *Limiter {
+	limiter := NewLimiter()
+	limiter.now = func() time.Time { return *now }
+	return limiter
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newTestLimiter(&now)
+	limit := Limit{RequestsPerMinute: 60, Burst: 2}
+
+	allowed, _ := limiter.Allow("user:1", limit)
+	assert.True(t, allowed)
+	allowed, _ = limiter.Allow("user:1", limit)
+	assert.True(t, allowed)
+
+	allowed, retryAfter := limiter.Allow("user:1", limit)
+	assert.False(t, allowed)
+	assert.Equal(t, time.Second, retryAfter)
+
+	// Другие ключи не затрагиваются
+	allowed, _ = limiter.Allow("user:2", limit)
+	assert.True(t, allowed)
+
+	// Через секунду появляется новый токен
+	now = now.Add(time.Second)
+	allowed, _ = limiter.Allow("user:1", limit)
+	assert.True(t, allowed)
+}
+
+func TestLimiter_LimitChange(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newTestLimiter(&now)
+
+	limiter.Allow("user:1", Limit{RequestsPerMinute: 60, Burst: 100})
+
+	// Уменьшение лимита сразу ограничивает накопленные токены
+	small := Limit{RequestsPerMinute: 60, Burst: 1}
+	allowed, _ := limiter.Allow("user:1", small)
+	assert.True(t, allowed)
+	allowed, _ = limiter.Allow("user:1", small)
+	assert.False(t, allowed)
+}
+
+func TestLimiter_SweepsIdleBuckets(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newTestLimiter(&now)
+
+	limiter.Allow("user:1", Limit{RequestsPerMinute: 60, Burst: 1})
+	now = now.Add(idleTTL + time.Minute)
+	limiter.Allow("user:2", Limit{RequestsPerMinute: 60, Burst: 1})
+
+	assert.NotContains(t, limiter.buckets, "user:1")
+	assert.Contains(t, limiter.buckets, "user:2")
+}
+
+// fakeRateLimitQueries отдает индивидуальные лимиты и считает обращения
+type fakeRateLimitQueries struct {
+	overrides []models.RateLimitOverride
+	err       error
+	calls     int
+}
+
+func (q *fakeRateLimitQueries) GetOverrides(ctx context.Context) ([]models.RateLimitOverride, error) {
+	q.calls++
+	return q.overrides, q.err
+}
+
+func (q *fakeRateLimitQueries) UpsertOverride(ctx context.Context, override models.RateLimitOverride) (*models.RateLimitOverride, error) {
+	return &override, nil
+}
+
+func (q *fakeRateLimitQueries) DeleteOverride(ctx context.Context, key string) error {
+	return nil
+}
+
+func TestOverrides_Lookup(t *testing.T) {
+	rateLimitQueries := &fakeRateLimitQueries{overrides: []models.RateLimitOverride{
+		{Key: "user:tablet", RequestsPerMinute: 1200, Burst: 200},
+	}}
+	overrides := NewOverrides(rateLimitQueries, time.Minute)
+
+	limit, ok := overrides.Lookup(context.Background(), "user:tablet")
+	assert.True(t, ok)
+	assert.Equal(t, Limit{RequestsPerMinute: 1200, Burst: 200}, limit)
+
+	_, ok = overrides.Lookup(context.Background(), "user:other")
+	assert.False(t, ok)
+	assert.Equal(t, 1, rateLimitQueries.calls)
+
+	// После сброса кэша лимиты перечитываются
+	overrides.Invalidate()
+	overrides.Lookup(context.Background(), "user:tablet")
+	assert.Equal(t, 2, rateLimitQueries.calls)
+}
+
+func TestOverrides_LoadError(t *testing.T) {
+	rateLimitQueries := &fakeRateLimitQueries{err: errors.New("database error")}
+	overrides := NewOverrides(rateLimitQueries, time.Minute)
+
+	_, ok := overrides.Lookup(context.Background(), "user:tablet")
+	assert.False(t, ok)
+
+	// Повторная попытка откладывается до истечения ttl
+	overrides.Lookup(context.Background(), "user:tablet")
+	assert.Equal(t, 1, rateLimitQueries.calls)
+}
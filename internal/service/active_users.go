@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+)
+
+// activeUsersMaxEntries - размер кэша, после которого из него удаляются устаревшие записи
+const activeUsersMaxEntries = 10000
+
+// activeUserEntry - закэшированный статус пользователя
+type activeUserEntry struct {
+	active    bool
+	checkedAt time.Time
+}
+
+// ActiveUsers кэширует статус пользователей на ttl, чтобы middleware авторизации
+// не обращался к базе данных на каждый запрос
+type ActiveUsers struct {
+	users queries.AuthQueriesInterface
+	ttl   time.Duration
+	clock clock.Clock
+
+	mu      sync.RWMutex
+	entries map[string]activeUserEntry
+}
+
+// NewActiveUsers создает новый экземпляр ActiveUsers.
+// Деактивация применяется не позже чем через ttl, на этом узле - сразу после Forget
+func NewActiveUsers(users queries.AuthQueriesInterface, ttl time.Duration) *ActiveUsers {
+	return &ActiveUsers{
+		users:   users,
+		ttl:     ttl,
+		clock:   clock.System{},
+		entries: make(map[string]activeUserEntry),
+	}
+}
+
+// IsUserActive возвращает статус пользователя из кэша или из базы данных.
+// Ошибка базы данных не кэшируется
+func (a *ActiveUsers) IsUserActive(ctx context.Context, userID string) (bool, error) {
+	now := a.clock.Now()
+
+	a.mu.RLock()
+	entry, ok := a.entries[userID]
+	a.mu.RUnlock()
+	if ok && now.Sub(entry.checkedAt) < a.ttl {
+		return entry.active, nil
+	}
+
+	active, err := a.users.IsUserActive(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	a.mu.Lock()
+	if len(a.entries) >= activeUsersMaxEntries {
+		a.pruneLocked(now)
+	}
+	a.entries[userID] = activeUserEntry{active: active, checkedAt: now}
+	a.mu.Unlock()
+
+	return active, nil
+}
+
+// Forget удаляет статус пользователя из кэша, чтобы изменение применилось сразу
+func (a *ActiveUsers) Forget(userID string) {
+	a.mu.Lock()
+	delete(a.entries, userID)
+	a.mu.Unlock()
+}
+
+// pruneLocked удаляет устаревшие записи, а если их нет - очищает кэш целиком
+func (a *ActiveUsers) pruneLocked(now time.Time) {
+	for userID, entry := range a.entries {
+		if now.Sub(entry.checkedAt) >= a.ttl {
+			delete(a.entries, userID)
+		}
+	}
+	if len(a.entries) >= activeUsersMaxEntries {
+		a.entries = make(map[string]activeUserEntry)
+	}
+}
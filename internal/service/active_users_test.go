@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingAuthQueries возвращает заданный статус пользователя и считает обращения к базе
+type countingAuthQueries struct {
+	queries.AuthQueriesInterface
+	active bool
+	err    error
+	calls  int
+}
+
+func (q *countingAuthQueries) IsUserActive(ctx context.Context, userID string) (bool, error) {
+	q.calls++
+	return q.active, q.err
+}
+
+func TestActiveUsersCache(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	users := &countingAuthQueries{active: true}
+	cache := NewActiveUsers(users, time.Minute)
+	cache.clock = clock.Fixed(now)
+
+	active, err := cache.IsUserActive(context.Background(), "user-id")
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	// В пределах ttl статус берется из кэша
+	users.active = false
+	active, _ = cache.IsUserActive(context.Background(), "user-id")
+	assert.True(t, active)
+	assert.Equal(t, 1, users.calls)
+
+	// После Forget статус перечитывается сразу
+	cache.Forget("user-id")
+	active, _ = cache.IsUserActive(context.Background(), "user-id")
+	assert.False(t, active)
+	assert.Equal(t, 2, users.calls)
+
+	// По истечении ttl статус перечитывается
+	users.active = true
+	cache.clock = clock.Fixed(now.Add(time.Minute))
+	active, _ = cache.IsUserActive(context.Background(), "user-id")
+	assert.True(t, active)
+	assert.Equal(t, 3, users.calls)
+}
+
+func TestActiveUsersErrorNotCached(t *testing.T) {
+	users := &countingAuthQueries{err: errors.New("database error")}
+	cache := NewActiveUsers(users, time.Minute)
+
+	_, err := cache.IsUserActive(context.Background(), "user-id")
+	assert.Error(t, err)
+
+	users.err, users.active = nil, true
+	active, err := cache.IsUserActive(context.Background(), "user-id")
+	assert.NoError(t, err)
+	assert.True(t, active)
+	assert.Equal(t, 2, users.calls)
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"pvz-service/internal/db/queries"
+)
+
+// ErrCityForbidden возвращается, если ПВЗ находится в городе, не назначенном модератору
+var ErrCityForbidden = errors.New("city is not assigned to moderator")
+
+// CityAccess проверяет, что модератор управляет ПВЗ только в назначенных ему городах.
+// Модератор без назначений не ограничен по городам
+type CityAccess struct {
+	moderatorQueries queries.ModeratorQueriesInterface
+	pvzQueries       queries.PVZQueriesInterface
+}
+
+// NewCityAccess создает новый экземпляр CityAccess
+func NewCityAccess(moderatorQueries queries.ModeratorQueriesInterface, pvzQueries queries.PVZQueriesInterface) *CityAccess {
+	return &CityAccess{
+		moderatorQueries: moderatorQueries,
+		pvzQueries:       pvzQueries,
+	}
+}
+
+// CheckCity проверяет, что модератор может управлять ПВЗ в городе city
+func (a *CityAccess) CheckCity(ctx context.Context, userID, city string) error {
+	cities, err := a.moderatorQueries.GetModeratorCities(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return checkCity(cities, city)
+}
+
+// CheckPVZ проверяет, что модератор может управлять ПВЗ pvzID.
+// Возвращает ошибку с queries.ErrNotFound, если ПВЗ нет
+func (a *CityAccess) CheckPVZ(ctx context.Context, userID, pvzID string) error {
+	cities, err := a.moderatorQueries.GetModeratorCities(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(cities) == 0 {
+		return nil
+	}
+
+	pvz, err := a.pvzQueries.GetPVZ(ctx, pvzID)
+	if err != nil {
+		return err
+	}
+
+	return checkCity(cities, pvz.City)
+}
+
+// checkCity применяет правило доступа: пустой список назначений разрешает любой город
+func checkCity(assigned []string, city string) error {
+	if len(assigned) == 0 || slices.Contains(assigned, city) {
+		return nil
+	}
+	return ErrCityForbidden
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCity(t *testing.T) {
+	tests := []struct {
+		name     string
+		assigned []string
+		city     string
+		want     error
+	}{
+		{"Модератор без назначений", nil, "Казань", nil},
+		{"Назначенный город", []string{"Москва", "Казань"}, "Казань", nil},
+		{"Чужой город", []string{"Москва"}, "Казань", ErrCityForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, checkCity(tt.assigned, tt.city), tt.want)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/metrics"
+	"pvz-service/internal/models"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// invariantViolations - количество нарушений инвариантов данных по видам на момент последней проверки
+var invariantViolations, _ = metrics.Meter().Int64Gauge(
+	"pvz.invariant.violations",
+	metric.WithDescription("Количество нарушений инвариантов данных на момент последней проверки"),
+)
+
+// ConsistencyChecker проверяет инварианты данных, которые не гарантируются схемой БД
+type ConsistencyChecker struct {
+	reportQueries queries.ReportQueriesInterface
+	clock         clock.Clock
+}
+
+// NewConsistencyChecker создает новый экземпляр ConsistencyChecker
+func NewConsistencyChecker(reportQueries queries.ReportQueriesInterface) *ConsistencyChecker {
+	return &ConsistencyChecker{
+		reportQueries: reportQueries,
+		clock:         clock.System{},
+	}
+}
+
+// Check ищет нарушения инвариантов и записывает их количество по видам в метрики.
+// В отчёте перечислены все виды нарушений, включая отсутствующие
+func (s *ConsistencyChecker) Check(ctx context.Context) (*models.ConsistencyReport, error) {
+	violations, err := s.reportQueries.GetInvariantViolations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := models.ConsistencyReport{
+		CheckedAt:  s.clock.Now(),
+		Counts:     make(map[string]int, len(models.InvariantKinds)),
+		Violations: violations,
+	}
+	if report.Violations == nil {
+		report.Violations = []models.InvariantViolation{}
+	}
+	for _, kind := range models.InvariantKinds {
+		report.Counts[kind] = 0
+	}
+	for _, violation := range violations {
+		report.Counts[violation.Kind]++
+	}
+
+	for kind, count := range report.Counts {
+		invariantViolations.Record(ctx, int64(count), metric.WithAttributes(attribute.String("kind", kind)))
+	}
+
+	return &report, nil
+}
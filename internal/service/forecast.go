@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+)
+
+// forecastDateLayout - формат дат прогноза
+const forecastDateLayout = "2006-01-02"
+
+// IntakeForecaster прогнозирует поступление товаров в ПВЗ по истории приёмок для планирования смен
+type IntakeForecaster struct {
+	reportQueries queries.ReportQueriesInterface
+	clock         clock.Clock
+}
+
+// NewIntakeForecaster создает новый экземпляр IntakeForecaster
+func NewIntakeForecaster(reportQueries queries.ReportQueriesInterface) *IntakeForecaster {
+	return &IntakeForecaster{
+		reportQueries: reportQueries,
+		clock:         clock.System{},
+	}
+}
+
+// Forecast строит прогноз для ПВЗ на days дней начиная с сегодняшнего по истории за weeks полных недель
+// до сегодняшнего дня. Дни считаются в часовом поясе ПВЗ, сегодняшний неполный день в историю не входит
+func (f *IntakeForecaster) Forecast(ctx context.Context, pvz models.PVZ, days, weeks int) (*models.IntakeForecast, error) {
+	timezone := ""
+	if pvz.Timezone != nil {
+		timezone = *pvz.Timezone
+	}
+	loc, err := clock.Location(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("pvz %s: %w", pvz.ID, err)
+	}
+
+	now := f.clock.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	from := today.AddDate(0, 0, -7*weeks)
+
+	intake, err := f.reportQueries.GetDailyIntake(ctx, pvz.ID, loc.String(), from.UTC(), today.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	return forecastIntake(pvz.ID, timezone, intake, today, days, weeks), nil
+}
+
+// forecastIntake считает прогноз на days дней от today: ожидаемое число товаров на день - среднее
+// по тем же дням недели за weeks недель до today. Дни без товаров входят в среднее с нулем
+func forecastIntake(pvzID, timezone string, intake []models.DailyIntake, today time.Time, days, weeks int) *models.IntakeForecast {
+	byDate := make(map[string]int, len(intake))
+	total := 0
+	for _, day := range intake {
+		byDate[day.Day.Format(forecastDateLayout)] += day.Products
+		total += day.Products
+	}
+
+	result := &models.IntakeForecast{
+		PvzID:        pvzID,
+		Timezone:     timezone,
+		Weeks:        weeks,
+		HistoryFrom:  today.AddDate(0, 0, -7*weeks).Format(forecastDateLayout),
+		HistoryTo:    today.AddDate(0, 0, -1).Format(forecastDateLayout),
+		DailyAverage: roundForecast(float64(total) / float64(7*weeks)),
+		Days:         make([]models.ForecastDay, 0, days),
+	}
+
+	for i := 0; i < days; i++ {
+		date := today.AddDate(0, 0, i)
+
+		// Тот же день недели в истории: за 7, 14, ... дней до сегодняшнего дня с тем же днем недели
+		offset := (i % 7) - 7
+		sum := 0
+		for week := 0; week < weeks; week++ {
+			sum += byDate[today.AddDate(0, 0, offset-7*week).Format(forecastDateLayout)]
+		}
+
+		weekday := int(date.Weekday())
+		if weekday == 0 {
+			weekday = 7
+		}
+		result.Days = append(result.Days, models.ForecastDay{
+			Date:             date.Format(forecastDateLayout),
+			Weekday:          weekday,
+			ExpectedProducts: roundForecast(float64(sum) / float64(weeks)),
+		})
+	}
+
+	return result
+}
+
+// roundForecast округляет ожидаемое число товаров до десятых
+func roundForecast(value float64) float64 {
+	return math.Round(value*10) / 10
+}
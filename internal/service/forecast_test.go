@@ -0,0 +1,36 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"pvz-service/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForecastIntake(t *testing.T) {
+	// Понедельник 30 марта 2026 года, история - 2 недели с 16 по 29 марта
+	today := time.Date(2026, 3, 30, 0, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+
+	intake := []models.DailyIntake{
+		{Day: day(16), Products: 30}, // понедельник
+		{Day: day(17), Products: 10}, // вторник
+		{Day: day(23), Products: 50}, // понедельник
+		{Day: day(29), Products: 7},  // воскресенье
+	}
+
+	forecast := forecastIntake("pvz1", "", intake, today, 8, 2)
+
+	assert.Equal(t, "2026-03-16", forecast.HistoryFrom)
+	assert.Equal(t, "2026-03-29", forecast.HistoryTo)
+	assert.Equal(t, 6.9, forecast.DailyAverage)
+	assert.Len(t, forecast.Days, 8)
+
+	assert.Equal(t, models.ForecastDay{Date: "2026-03-30", Weekday: 1, ExpectedProducts: 40}, forecast.Days[0])
+	assert.Equal(t, models.ForecastDay{Date: "2026-03-31", Weekday: 2, ExpectedProducts: 5}, forecast.Days[1])
+	assert.Equal(t, 0.0, forecast.Days[2].ExpectedProducts)
+	assert.Equal(t, models.ForecastDay{Date: "2026-04-05", Weekday: 7, ExpectedProducts: 3.5}, forecast.Days[6])
+	assert.Equal(t, 40.0, forecast.Days[7].ExpectedProducts)
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"pvz-service/internal/db/queries"
+)
+
+// ErrLockTimeout возвращается, если блокировку не удалось захватить за отведенное время:
+// ту же операцию с ПВЗ выполняет другой запрос, возможно на другом экземпляре сервиса
+var ErrLockTimeout = errors.New("lock wait timeout")
+
+// lockRetryInterval - пауза между попытками захватить занятую блокировку
+const lockRetryInterval = 50 * time.Millisecond
+
+// Locker захватывает блокировку по ключу, общую для всех экземпляров сервиса.
+// Lock вызывается в транзакции, блокировка держится до ее фиксации или отката
+type Locker interface {
+	Lock(ctx context.Context, key string) error
+}
+
+// PVZLockKey возвращает ключ блокировки операций, которые на одном ПВЗ должны выполняться по очереди:
+// открытия приёмки и удаления последних товаров
+func PVZLockKey(pvzID string) string {
+	return "pvz:" + pvzID
+}
+
+// AdvisoryLocks реализует Locker на транзакционных advisory locks PostgreSQL: блокировка видна
+// всем экземплярам сервиса с общей базой данных и снимается сама, если экземпляр упал посреди операции
+type AdvisoryLocks struct {
+	lockQueries   queries.LockQueriesInterface
+	timeout       time.Duration
+	retryInterval time.Duration
+}
+
+var _ Locker = (*AdvisoryLocks)(nil)
+
+// NewAdvisoryLocks создает новый экземпляр AdvisoryLocks, ожидающий занятую блокировку не дольше timeout
+func NewAdvisoryLocks(lockQueries queries.LockQueriesInterface, timeout time.Duration) *AdvisoryLocks {
+	return &AdvisoryLocks{
+		lockQueries:   lockQueries,
+		timeout:       timeout,
+		retryInterval: lockRetryInterval,
+	}
+}
+
+// Lock захватывает блокировку key, повторяя попытки, пока она занята. Через timeout возвращается ErrLockTimeout.
+// Попытки не блокируют соединение в ожидании: отмененный запрос прервал бы транзакцию операции
+func (l *AdvisoryLocks) Lock(ctx context.Context, key string) error {
+	deadline := time.Now().Add(l.timeout)
+	for {
+		locked, err := l.lockQueries.TryLock(ctx, key)
+		if err != nil {
+			return err
+		}
+		if locked {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("%w: %s", ErrLockTimeout, key)
+		}
+
+		timer := time.NewTimer(l.retryInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
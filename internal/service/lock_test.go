@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"pvz-service/internal/db/queries"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// busyLocks - блокировка занята первые busy попыток
+type busyLocks struct {
+	queries.LockQueriesInterface
+	busy     int
+	attempts int
+}
+
+func (b *busyLocks) TryLock(ctx context.Context, key string) (bool, error) {
+	b.attempts++
+	return b.attempts > b.busy, nil
+}
+
+// TestAdvisoryLocks проверяет ожидание освобождения блокировки и отказ по истечении времени ожидания
+func TestAdvisoryLocks(t *testing.T) {
+	t.Run("Блокировка освободилась", func(t *testing.T) {
+		lockQueries := &busyLocks{busy: 2}
+		locks := NewAdvisoryLocks(lockQueries, time.Second)
+		locks.retryInterval = time.Millisecond
+
+		assert.NoError(t, locks.Lock(context.Background(), PVZLockKey("pvz-1")))
+		assert.Equal(t, 3, lockQueries.attempts)
+	})
+
+	t.Run("Блокировка занята дольше времени ожидания", func(t *testing.T) {
+		locks := NewAdvisoryLocks(&busyLocks{busy: 1000}, 5*time.Millisecond)
+		locks.retryInterval = time.Millisecond
+
+		assert.ErrorIs(t, locks.Lock(context.Background(), PVZLockKey("pvz-1")), ErrLockTimeout)
+	})
+
+	t.Run("Запрос отменен", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := NewAdvisoryLocks(&busyLocks{busy: 1000}, time.Second).Lock(ctx, PVZLockKey("pvz-1"))
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/notify"
+)
+
+// LoginRecorder записывает попытки входа
+type LoginRecorder interface {
+	RecordLogin(ctx context.Context, attempt models.LoginAttempt) error
+}
+
+// LoginAlertRules - правила оповещений о подозрительных входах. Оповещение о серии неудачных
+// входов отправляется, когда с одного email или IP за FailureWindow набирается FailureThreshold
+// неудачных попыток; о входе из новой страны - при успешном входе пользователя из страны,
+// из которой он раньше не входил, если страна предыдущих входов известна
+type LoginAlertRules struct {
+	FailureThreshold int
+	FailureWindow    time.Duration
+}
+
+// LoginAudit ведет журнал входов и проверяет его правилами оповещений.
+// Оповещения доставляются notifier, ошибка доставки не мешает входу
+type LoginAudit struct {
+	loginAuditQueries queries.LoginAuditQueriesInterface
+	notifier          notify.Notifier
+	rules             LoginAlertRules
+	clock             clock.Clock
+}
+
+var _ LoginRecorder = (*LoginAudit)(nil)
+
+// NewLoginAudit создает новый экземпляр LoginAudit
+func NewLoginAudit(loginAuditQueries queries.LoginAuditQueriesInterface, notifier notify.Notifier, rules LoginAlertRules) *LoginAudit {
+	return &LoginAudit{
+		loginAuditQueries: loginAuditQueries,
+		notifier:          notifier,
+		rules:             rules,
+		clock:             clock.System{},
+	}
+}
+
+// RecordLogin записывает попытку входа и отправляет оповещения по сработавшим правилам
+func (a *LoginAudit) RecordLogin(ctx context.Context, attempt models.LoginAttempt) error {
+	attempt.CreatedAt = a.clock.Now()
+
+	id, err := a.loginAuditQueries.AddAttempt(ctx, attempt)
+	if err != nil {
+		return err
+	}
+
+	if attempt.Success {
+		return a.checkNewCountry(ctx, id, attempt)
+	}
+	return a.checkFailures(ctx, attempt)
+}
+
+// checkFailures оповещает о серии неудачных входов. Оповещение отправляется один раз,
+// когда число попыток за окно достигает порога, а не при каждой следующей попытке
+func (a *LoginAudit) checkFailures(ctx context.Context, attempt models.LoginAttempt) error {
+	if a.rules.FailureThreshold <= 0 {
+		return nil
+	}
+
+	byEmail, byIP, err := a.loginAuditQueries.CountRecentFailures(ctx, attempt.Email, attempt.IP, attempt.CreatedAt.Add(-a.rules.FailureWindow))
+	if err != nil {
+		return err
+	}
+
+	window := a.rules.FailureWindow.String()
+	if byEmail == a.rules.FailureThreshold {
+		a.notify(ctx, models.EventLoginFailures, models.LoginFailuresAlert{
+			Email: attempt.Email, Failures: byEmail, Window: window, LastAt: attempt.CreatedAt,
+		})
+	}
+	if byIP == a.rules.FailureThreshold {
+		a.notify(ctx, models.EventLoginFailures, models.LoginFailuresAlert{
+			IP: attempt.IP, Failures: byIP, Window: window, LastAt: attempt.CreatedAt,
+		})
+	}
+	return nil
+}
+
+// checkNewCountry оповещает о входе из страны, из которой пользователь раньше не входил.
+// Первый вход с известной страной оповещения не вызывает
+func (a *LoginAudit) checkNewCountry(ctx context.Context, id int64, attempt models.LoginAttempt) error {
+	if attempt.Country == nil || attempt.UserID == nil {
+		return nil
+	}
+
+	withCountry, fromCountry, err := a.loginAuditQueries.CountCountryLogins(ctx, *attempt.UserID, *attempt.Country, id)
+	if err != nil {
+		return err
+	}
+
+	if withCountry > 0 && fromCountry == 0 {
+		a.notify(ctx, models.EventLoginNewCountry, models.LoginNewCountryAlert{
+			UserID:    *attempt.UserID,
+			Email:     attempt.Email,
+			Country:   *attempt.Country,
+			IP:        attempt.IP,
+			UserAgent: attempt.UserAgent,
+			LoginAt:   attempt.CreatedAt,
+		})
+	}
+	return nil
+}
+
+// notify отправляет оповещение, ошибка доставки только пишется в лог
+func (a *LoginAudit) notify(ctx context.Context, eventType string, payload interface{}) {
+	event := models.Event{Type: eventType, Payload: payload, CreatedAt: a.clock.Now()}
+	if err := a.notifier.Notify(ctx, event); err != nil {
+		log.Printf("Failed to send %s alert: %v", eventType, err)
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryLoginAudit хранит журнал входов в памяти
+type memoryLoginAudit struct {
+	queries.LoginAuditQueriesInterface
+	attempts []models.LoginAttempt
+}
+
+func (m *memoryLoginAudit) AddAttempt(ctx context.Context, attempt models.LoginAttempt) (int64, error) {
+	attempt.ID = int64(len(m.attempts) + 1)
+	m.attempts = append(m.attempts, attempt)
+	return attempt.ID, nil
+}
+
+func (m *memoryLoginAudit) CountRecentFailures(ctx context.Context, email, ip string, since time.Time) (int, int, error) {
+	var byEmail, byIP int
+	for _, attempt := range m.attempts {
+		if attempt.Success || !attempt.CreatedAt.After(since) {
+			continue
+		}
+		if attempt.Email == email {
+			byEmail++
+		}
+		if attempt.IP == ip {
+			byIP++
+		}
+	}
+	return byEmail, byIP, nil
+}
+
+func (m *memoryLoginAudit) CountCountryLogins(ctx context.Context, userID, country string, excludeID int64) (int, int, error) {
+	var withCountry, fromCountry int
+	for _, attempt := range m.attempts {
+		if attempt.ID == excludeID || !attempt.Success || attempt.UserID == nil || *attempt.UserID != userID || attempt.Country == nil {
+			continue
+		}
+		withCountry++
+		if *attempt.Country == country {
+			fromCountry++
+		}
+	}
+	return withCountry, fromCountry, nil
+}
+
+// recordingNotifier запоминает отправленные оповещения
+type recordingNotifier struct {
+	events []models.Event
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, event models.Event) error {
+	n.events = append(n.events, event)
+	return nil
+}
+
+func TestLoginAuditFailureAlert(t *testing.T) {
+	store := &memoryLoginAudit{}
+	notifier := &recordingNotifier{}
+	audit := NewLoginAudit(store, notifier, LoginAlertRules{FailureThreshold: 3, FailureWindow: 15 * time.Minute})
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	reason := models.LoginFailureInvalidPassword
+	fail := func(email, ip string) {
+		audit.clock = clock.Fixed(now)
+		require.NoError(t, audit.RecordLogin(ctx, models.LoginAttempt{Email: email, IP: ip, FailureReason: &reason}))
+		now = now.Add(time.Minute)
+	}
+
+	// Две неудачи - ниже порога, третья с того же email оповещает один раз
+	fail("user@example.com", "192.0.2.1")
+	fail("user@example.com", "192.0.2.2")
+	assert.Empty(t, notifier.events)
+	fail("user@example.com", "192.0.2.3")
+	require.Len(t, notifier.events, 1)
+	assert.Equal(t, models.EventLoginFailures, notifier.events[0].Type)
+	assert.Equal(t, "user@example.com", notifier.events[0].Payload.(models.LoginFailuresAlert).Email)
+	fail("user@example.com", "192.0.2.4")
+	assert.Len(t, notifier.events, 1)
+
+	// Перебор разных email с одного IP оповещает по IP
+	fail("a@example.com", "198.51.100.7")
+	fail("b@example.com", "198.51.100.7")
+	fail("c@example.com", "198.51.100.7")
+	require.Len(t, notifier.events, 2)
+	assert.Equal(t, "198.51.100.7", notifier.events[1].Payload.(models.LoginFailuresAlert).IP)
+
+	// За пределами окна счет начинается заново
+	now = now.Add(time.Hour)
+	fail("user@example.com", "192.0.2.1")
+	assert.Len(t, notifier.events, 2)
+}
+
+func TestLoginAuditNewCountryAlert(t *testing.T) {
+	store := &memoryLoginAudit{}
+	notifier := &recordingNotifier{}
+	audit := NewLoginAudit(store, notifier, LoginAlertRules{FailureThreshold: 5, FailureWindow: time.Minute})
+	ctx := context.Background()
+
+	userID := "user-1"
+	login := func(country string) {
+		attempt := models.LoginAttempt{UserID: &userID, Email: "user@example.com", Success: true, IP: "192.0.2.1"}
+		if country != "" {
+			attempt.Country = &country
+		}
+		require.NoError(t, audit.RecordLogin(ctx, attempt))
+	}
+
+	// Первый вход с известной страной и повторные входы оттуда же не оповещают
+	login("RU")
+	login("RU")
+	login("")
+	assert.Empty(t, notifier.events)
+
+	login("KZ")
+	require.Len(t, notifier.events, 1)
+	assert.Equal(t, models.EventLoginNewCountry, notifier.events[0].Type)
+	assert.Equal(t, "KZ", notifier.events[0].Payload.(models.LoginNewCountryAlert).Country)
+
+	login("KZ")
+	assert.Len(t, notifier.events, 1)
+}
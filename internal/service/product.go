@@ -0,0 +1,541 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"pvz-service/internal/authz"
+	"pvz-service/internal/db"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/mapper"
+	"pvz-service/internal/models"
+	"pvz-service/internal/storage"
+)
+
+// Ошибки удаления товаров
+var (
+	// ErrDeleteForbidden возвращается, если роли запрещено удалять товары
+	ErrDeleteForbidden = errors.New("role is not allowed to delete products")
+	// ErrNoOpenReception возвращается, если в ПВЗ нет открытой приёмки
+	ErrNoOpenReception = errors.New("no open reception")
+	// ErrReceptionClosed возвращается при удалении товара из закрытой приёмки
+	ErrReceptionClosed = errors.New("reception is closed")
+	// ErrReceptionPaused возвращается при добавлении товара, если приёмка ПВЗ приостановлена
+	ErrReceptionPaused = errors.New("reception is paused")
+	// ErrReceptionFull возвращается при добавлении товара, если в приёмке уже столько товаров, какова ее вместимость
+	ErrReceptionFull = errors.New("reception capacity reached")
+	// ErrNoProductsToDelete возвращается, если в приёмке нет товаров
+	ErrNoProductsToDelete = errors.New("no products to delete")
+	// ErrNotLastProduct возвращается, если сотрудник удаляет не последний добавленный товар
+	ErrNotLastProduct = errors.New("only the last added product can be deleted")
+	// ErrNotEnoughProducts возвращается, если в приёмке меньше товаров, чем запрошено удалить
+	ErrNotEnoughProducts = errors.New("not enough products to delete")
+)
+
+// ErrRetypeForbidden возвращается, если роли запрещено исправлять тип товара приёмки в ее текущем статусе
+var ErrRetypeForbidden = errors.New("role is not allowed to change product type")
+
+// PVZFullError сообщает, что товар не помещается в ПВЗ: невыданных товаров будет больше его вместимости
+type PVZFullError struct {
+	Occupancy models.PVZOccupancy
+}
+
+func (e *PVZFullError) Error() string {
+	return fmt.Sprintf("pvz %s capacity reached: %d of %d", e.Occupancy.PvzID, e.Occupancy.Occupancy, *e.Occupancy.Capacity)
+}
+
+// ErrProductAlreadyDamaged возвращается при повторной отметке товара поврежденным
+var ErrProductAlreadyDamaged = errors.New("product is already marked damaged")
+
+// ProductLimitError сообщает, что товар превысит ограничение для своего типа в приёмке
+type ProductLimitError struct {
+	Exceeded models.ProductTypeLimitExceeded
+}
+
+func (e *ProductLimitError) Error() string {
+	return fmt.Sprintf("product type %s limit exceeded: %d of %d", e.Exceeded.Type, e.Exceeded.Current, e.Exceeded.Limit)
+}
+
+// ProductService содержит бизнес-правила работы с товарами, общие для нескольких обработчиков
+// и транспортов: добавление товара одинаково проверяется в REST и gRPC
+type ProductService struct {
+	productQueries   queries.ProductQueriesInterface
+	receptionQueries queries.ReceptionQueriesInterface
+	tx               db.Transactor
+	outboxQueries    queries.OutboxQueriesInterface
+	storage          storage.Storage
+	productLimits    queries.ProductLimitQueriesInterface
+	hours            WorkingHoursChecker
+	quotas           QuotaChecker
+	locks            Locker
+}
+
+// NewProductService создает новый экземпляр ProductService.
+// Товары принимаются только в часы работы ПВЗ, которые проверяет hours, удаления расходуют квоту quotas.
+// Удаления последних товаров ПВЗ выполняются по очереди под блокировкой locks, общей для всех экземпляров сервиса
+func NewProductService(productQueries queries.ProductQueriesInterface, receptionQueries queries.ReceptionQueriesInterface, tx db.Transactor, outboxQueries queries.OutboxQueriesInterface, storage storage.Storage, productLimits queries.ProductLimitQueriesInterface, hours WorkingHoursChecker, quotas QuotaChecker, locks Locker) *ProductService {
+	return &ProductService{
+		productQueries:   productQueries,
+		receptionQueries: receptionQueries,
+		tx:               tx,
+		outboxQueries:    outboxQueries,
+		storage:          storage,
+		productLimits:    productLimits,
+		hours:            hours,
+		quotas:           quotas,
+		locks:            locks,
+	}
+}
+
+// OpenReception возвращает открытую приёмку ПВЗ, в которую можно добавлять товары.
+// Если открытой приёмки нет, а приостановленная есть, возвращается ErrReceptionPaused,
+// вне часов работы ПВЗ - ErrOutsideWorkingHours
+func (s *ProductService) OpenReception(ctx context.Context, pvzID string) (*models.Reception, error) {
+	reception, err := s.activeReception(ctx, pvzID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.hours.CheckOpen(ctx, pvzID); err != nil {
+		return nil, err
+	}
+	return reception, nil
+}
+
+// activeReception возвращает открытую приёмку ПВЗ без проверки часов работы: удалить ошибочно
+// принятый товар можно и после закрытия ПВЗ. Ошибки - как у OpenReception
+func (s *ProductService) activeReception(ctx context.Context, pvzID string) (*models.Reception, error) {
+	reception, err := s.receptionQueries.GetLastOpenReception(ctx, pvzID)
+	if err != nil {
+		if _, pausedErr := s.receptionQueries.GetLastPausedReception(ctx, pvzID); pausedErr == nil {
+			return nil, ErrReceptionPaused
+		}
+		return nil, fmt.Errorf("%w: %v", ErrNoOpenReception, err)
+	}
+	if reception.Status != "in_progress" {
+		return nil, ErrReceptionClosed
+	}
+	return reception, nil
+}
+
+// AddProduct добавляет товар в открытую приёмку и записывает событие о нем в outbox в одной транзакции.
+// Фотографии должны быть уже загружены в хранилище или быть внешними ссылками.
+// userID - сотрудник, принявший товар. При превышении ограничения для типа товара возвращается *ProductLimitError,
+// при заполненной приёмке - ErrReceptionFull, при заполненном ПВЗ - *PVZFullError, если не задан overrideCapacity.
+// Если приёмку успели закрыть или приостановить, возвращаются ErrReceptionClosed и ErrReceptionPaused
+func (s *ProductService) AddProduct(ctx context.Context, userID string, reception *models.Reception, productType, barcode string, photos []models.ProductPhoto, overrideCapacity bool) (*models.ProductResponse, error) {
+	var result models.ProductResponse
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		// Вместимость и ограничения по типам проверяются под блокировкой приёмки
+		if err := s.checkCapacity(ctx, reception.ID); err != nil {
+			return err
+		}
+		if err := s.checkProductLimit(ctx, reception.ID, productType); err != nil {
+			return err
+		}
+
+		var product *models.Product
+		var err error
+		if len(photos) > 0 {
+			product, err = s.productQueries.AddProductWithPhotos(ctx, reception.ID, userID, productType, barcode, photos)
+		} else {
+			product, err = s.productQueries.AddProduct(ctx, reception.ID, userID, productType, barcode)
+		}
+		if err != nil {
+			return err
+		}
+
+		overCapacity, err := s.checkPVZCapacity(ctx, reception.PvzID, overrideCapacity)
+		if err != nil {
+			return err
+		}
+
+		result = mapper.Product(*product)
+		result.Photos = PhotoURLs(ctx, s.storage, photos)
+		result.OverCapacity = overCapacity
+		return s.outboxQueries.AddEvent(ctx, reception.PvzID, models.EventProductAdded, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// checkProductLimit проверяет, что в приёмке есть место для еще одного товара типа productType.
+// Типы без ограничения не проверяются. Вызывается под блокировкой строки приёмки, иначе параллельные
+// добавления посчитают одни и те же товары и превысят ограничение
+func (s *ProductService) checkProductLimit(ctx context.Context, receptionID, productType string) error {
+	limit, err := s.productLimits.GetLimit(ctx, productType)
+	if errors.Is(err, queries.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	current, err := s.productLimits.CountReceptionProducts(ctx, receptionID, productType)
+	if err != nil {
+		return err
+	}
+	if current >= limit.MaxPerReception {
+		return &ProductLimitError{Exceeded: models.ProductTypeLimitExceeded{
+			Type:    productType,
+			Limit:   limit.MaxPerReception,
+			Current: current,
+		}}
+	}
+
+	return nil
+}
+
+// checkCapacity блокирует строку приёмки до конца транзакции и проверяет, что в ней есть место для еще
+// одного товара. Блокировка берется и для приёмки без вместимости: под ней проверяются ограничения по типам,
+// и параллельные добавления не превышают ни вместимость, ни ограничения. Статус тоже перечитывается под
+// блокировкой: приёмку могли закрыть (ErrReceptionClosed) или приостановить (ErrReceptionPaused) после
+// того, как ее нашел OpenReception
+func (s *ProductService) checkCapacity(ctx context.Context, receptionID string) error {
+	locked, err := s.receptionQueries.LockReceptions(ctx, []string{receptionID})
+	if err != nil {
+		return err
+	}
+	if len(locked) == 0 {
+		return fmt.Errorf("reception %s: %w", receptionID, queries.ErrNotFound)
+	}
+
+	switch locked[0].Status {
+	case "in_progress":
+	case "paused":
+		return ErrReceptionPaused
+	default:
+		return ErrReceptionClosed
+	}
+	if capacity := locked[0].Capacity; capacity != nil && locked[0].ProductCount >= *capacity {
+		return ErrReceptionFull
+	}
+	return nil
+}
+
+// checkPVZCapacity проверяет после добавления товара, что невыданных товаров ПВЗ не больше его вместимости.
+// Заполненность меняет триггер при добавлении товара, поэтому проверка идет после вставки: строка ПВЗ
+// уже заблокирована и параллельные добавления ждут конца транзакции. С override товар принимается
+// сверх вместимости, и возвращается true
+func (s *ProductService) checkPVZCapacity(ctx context.Context, pvzID string, override bool) (bool, error) {
+	occupancy, err := s.productLimits.GetPVZOccupancy(ctx, pvzID)
+	if err != nil {
+		return false, err
+	}
+	if occupancy.Capacity == nil || occupancy.Occupancy <= *occupancy.Capacity {
+		return false, nil
+	}
+	if override {
+		return true, nil
+	}
+	return false, &PVZFullError{Occupancy: *occupancy}
+}
+
+// PhotoURLs возвращает ссылки на фотографии товара: внешние как есть,
+// загруженные в хранилище - подписанными ссылками
+func PhotoURLs(ctx context.Context, store storage.Storage, photos []models.ProductPhoto) []string {
+	if len(photos) == 0 {
+		return nil
+	}
+
+	urls := make([]string, 0, len(photos))
+	for _, photo := range photos {
+		if photo.URL != nil {
+			urls = append(urls, *photo.URL)
+			continue
+		}
+		if photo.StorageKey == nil {
+			continue
+		}
+
+		// Недоступная ссылка не должна ломать весь ответ
+		url, err := store.URL(ctx, *photo.StorageKey)
+		if err != nil {
+			log.Printf("Failed to get photo url for %s: %v", *photo.StorageKey, err)
+			continue
+		}
+		urls = append(urls, url)
+	}
+
+	return urls
+}
+
+// DeleteLastProduct удаляет последний добавленный товар из открытой приёмки ПВЗ. Последний товар
+// определяется под блокировкой ПВЗ, чтобы два запроса, в том числе на разных экземплярах сервиса,
+// не удалили один и тот же товар. Если блокировка занята дольше таймаута, возвращается ErrLockTimeout
+func (s *ProductService) DeleteLastProduct(ctx context.Context, role, userID, pvzID string) error {
+	return s.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := s.locks.Lock(ctx, PVZLockKey(pvzID)); err != nil {
+			return err
+		}
+
+		reception, err := s.activeReception(ctx, pvzID)
+		if err != nil {
+			return err
+		}
+
+		last, err := s.productQueries.GetLastProductFromReception(ctx, reception.ID)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrNoProductsToDelete, err)
+		}
+
+		return s.deleteProduct(ctx, role, userID, reception, last, last)
+	})
+}
+
+// DeleteLastProducts удаляет count последних добавленных товаров открытой приёмки ПВЗ в одной транзакции,
+// например ошибочно отсканированную коробку, и возвращает их ID от последнего к первому. Приёмка блокируется,
+// чтобы параллельно добавленный товар не попал в удаление, а ПВЗ - блокировкой удалений, как в DeleteLastProduct.
+// Если товаров меньше count, ничего не удаляется
+func (s *ProductService) DeleteLastProducts(ctx context.Context, role, userID, pvzID string, count int) ([]string, error) {
+	if !authz.Can(role, authz.ProductDeleteLast) {
+		return nil, ErrDeleteForbidden
+	}
+
+	var deleted []string
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := s.locks.Lock(ctx, PVZLockKey(pvzID)); err != nil {
+			return err
+		}
+
+		reception, err := s.activeReception(ctx, pvzID)
+		if err != nil {
+			return err
+		}
+
+		receptions, err := s.receptionQueries.LockReceptions(ctx, []string{reception.ID})
+		if err != nil {
+			return err
+		}
+		if len(receptions) == 0 || receptions[0].Status != "in_progress" {
+			return ErrReceptionClosed
+		}
+
+		products, err := s.productQueries.GetLastProductsFromReception(ctx, reception.ID, count)
+		if err != nil {
+			return err
+		}
+		if len(products) == 0 {
+			return ErrNoProductsToDelete
+		}
+		if len(products) < count {
+			return fmt.Errorf("%w: reception has %d products", ErrNotEnoughProducts, len(products))
+		}
+		if err := s.quotas.Consume(ctx, userID, role, models.QuotaOperationProductDelete, len(products)); err != nil {
+			return err
+		}
+
+		deleted = make([]string, 0, len(products))
+		for _, product := range products {
+			if err := s.productQueries.DeleteProduct(ctx, product.ID, userID); err != nil {
+				return err
+			}
+			if err := s.outboxQueries.AddEvent(ctx, reception.PvzID, models.EventProductDeleted, mapper.Product(product)); err != nil {
+				return err
+			}
+			deleted = append(deleted, product.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deleted, nil
+}
+
+// DeleteProduct удаляет товар открытой приёмки по ID. Как и DeleteLastProduct, удаление идет под блокировкой ПВЗ
+// товара, а приёмка и последний товар перечитываются под ней, чтобы параллельные удаления и закрытие приёмки
+// не нарушили порядок удаления. Возвращает ошибку с queries.ErrNotFound, если товара нет
+func (s *ProductService) DeleteProduct(ctx context.Context, role, userID, productID string) error {
+	return s.tx.InTx(ctx, func(ctx context.Context) error {
+		// ПВЗ товара нужен, чтобы взять блокировку
+		product, err := s.productQueries.GetProduct(ctx, productID)
+		if err != nil {
+			return err
+		}
+		reception, err := s.receptionQueries.GetReceptionByID(ctx, product.ReceptionID)
+		if err != nil {
+			return err
+		}
+		if err := s.locks.Lock(ctx, PVZLockKey(reception.PvzID)); err != nil {
+			return err
+		}
+
+		// Пока блокировка была занята, товар могли удалить, а приёмку - закрыть
+		product, err = s.productQueries.GetProduct(ctx, productID)
+		if err != nil {
+			return err
+		}
+		receptions, err := s.receptionQueries.LockReceptions(ctx, []string{product.ReceptionID})
+		if err != nil {
+			return err
+		}
+		if len(receptions) == 0 {
+			return fmt.Errorf("reception %s: %w", product.ReceptionID, queries.ErrNotFound)
+		}
+		reception = &receptions[0]
+
+		// Последний товар нужен только для проверки порядка удаления сотрудником
+		var last *models.Product
+		if !authz.Can(role, authz.ProductDeleteAny) && reception.Status == "in_progress" {
+			last, err = s.productQueries.GetLastProductFromReception(ctx, reception.ID)
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrNoProductsToDelete, err)
+			}
+		}
+
+		return s.deleteProduct(ctx, role, userID, reception, product, last)
+	})
+}
+
+// deleteProduct проверяет правило удаления и удаляет товар от имени userID, записывая событие в outbox в той же транзакции.
+// Вызывается в транзакции вызывающего под блокировкой ПВЗ.
+// Удаление расходует квоту пользователя, при превышении возвращается *QuotaExceededError
+func (s *ProductService) deleteProduct(ctx context.Context, role, userID string, reception *models.Reception, product, last *models.Product) error {
+	if err := checkDeletion(role, reception, product, last); err != nil {
+		return err
+	}
+
+	return s.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := s.quotas.Consume(ctx, userID, role, models.QuotaOperationProductDelete, 1); err != nil {
+			return err
+		}
+		if err := s.productQueries.DeleteProduct(ctx, product.ID, userID); err != nil {
+			return err
+		}
+
+		return s.outboxQueries.AddEvent(ctx, reception.PvzID, models.EventProductDeleted, mapper.Product(*product))
+	})
+}
+
+// checkDeletion применяет правило удаления товаров: удалять можно только из открытой приёмки,
+// роль с правом ProductDeleteLast (сотрудник) удаляет товары строго в обратном порядке добавления (LIFO),
+// роль с правом ProductDeleteAny (модератор) - любой товар
+func checkDeletion(role string, reception *models.Reception, product, last *models.Product) error {
+	if reception.Status != "in_progress" {
+		return ErrReceptionClosed
+	}
+
+	switch {
+	case authz.Can(role, authz.ProductDeleteAny):
+		return nil
+	case authz.Can(role, authz.ProductDeleteLast):
+		if last == nil || last.ID != product.ID {
+			return ErrNotLastProduct
+		}
+		return nil
+	default:
+		return ErrDeleteForbidden
+	}
+}
+
+// UpdateProductType исправляет тип товара без удаления, сохраняя порядок добавления товаров в приёмке.
+// Тип товара открытой приёмки исправляет роль с правом ProductRetypeOpen (сотрудник), закрытой -
+// с правом ProductRetypeClosed (модератор). Приёмка блокируется до конца транзакции, чтобы не закрыться
+// во время исправления. Ограничение количества товаров по типам проверяется только в открытой приёмке.
+// Возвращает ошибку с queries.ErrNotFound, если товара нет
+func (s *ProductService) UpdateProductType(ctx context.Context, role, authorID, productID, productType string) (*models.ProductTypeChangeResponse, error) {
+	product, err := s.productQueries.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := models.ProductTypeChangeResponse{
+		ProductResponse: mapper.Product(*product),
+		PreviousType:    product.Type,
+	}
+
+	err = s.tx.InTx(ctx, func(ctx context.Context) error {
+		receptions, err := s.receptionQueries.LockReceptions(ctx, []string{product.ReceptionID})
+		if err != nil {
+			return err
+		}
+		if len(receptions) == 0 {
+			return fmt.Errorf("reception %s: %w", product.ReceptionID, queries.ErrNotFound)
+		}
+		reception := receptions[0]
+
+		if err := checkRetype(role, &reception); err != nil {
+			return err
+		}
+		if product.Type == productType {
+			return nil
+		}
+		if reception.Status == "in_progress" {
+			if err := s.checkProductLimit(ctx, reception.ID, productType); err != nil {
+				return err
+			}
+		}
+
+		change, err := s.productQueries.UpdateProductType(ctx, product.ID, authorID, productType)
+		if err != nil {
+			return err
+		}
+
+		result.Type = change.NewType
+		result.PreviousType = change.OldType
+		return s.outboxQueries.AddEvent(ctx, reception.PvzID, models.EventProductRetyped, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// MarkDamaged отмечает товар поврежденным и записывает событие product.damaged в outbox в одной транзакции.
+// Повреждения отмечаются при приёмке: товар закрытой приёмки отметить нельзя (ErrReceptionClosed).
+// Приёмка блокируется до конца транзакции, чтобы не закрыться во время отметки. Фотографии повреждения
+// должны быть уже загружены в хранилище или быть внешними ссылками. Повторная отметка возвращает ErrProductAlreadyDamaged
+func (s *ProductService) MarkDamaged(ctx context.Context, userID string, product *models.Product, description string, photos []models.ProductPhoto) (*models.ProductDamage, error) {
+	if product.Damaged {
+		return nil, ErrProductAlreadyDamaged
+	}
+
+	var result *models.ProductDamage
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		receptions, err := s.receptionQueries.LockReceptions(ctx, []string{product.ReceptionID})
+		if err != nil {
+			return err
+		}
+		if len(receptions) == 0 {
+			return fmt.Errorf("reception %s: %w", product.ReceptionID, queries.ErrNotFound)
+		}
+		if receptions[0].Status == "close" {
+			return ErrReceptionClosed
+		}
+
+		// Товар мог быть отмечен параллельным запросом после чтения
+		damage, err := s.productQueries.MarkProductDamaged(ctx, product.ID, userID, description, photos)
+		if errors.Is(err, queries.ErrNotFound) {
+			return ErrProductAlreadyDamaged
+		}
+		if err != nil {
+			return err
+		}
+
+		damage.Photos = PhotoURLs(ctx, s.storage, photos)
+		result = damage
+		return s.outboxQueries.AddEvent(ctx, receptions[0].PvzID, models.EventProductDamaged, damage)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// checkRetype применяет правило исправления типа товара: в открытой приёмке нужно право
+// ProductRetypeOpen, в закрытой - ProductRetypeClosed
+func checkRetype(role string, reception *models.Reception) error {
+	permission := authz.ProductRetypeClosed
+	if reception.Status == "in_progress" {
+		permission = authz.ProductRetypeOpen
+	}
+	if !authz.Can(role, permission) {
+		return ErrRetypeForbidden
+	}
+	return nil
+}
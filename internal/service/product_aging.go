@@ -0,0 +1,71 @@
+package service
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"pvz-service/internal/models"
+)
+
+// BuildProductAging считает срок хранения товаров на момент now и группирует их по ПВЗ.
+// Товары должны быть упорядочены по ПВЗ, порядок внутри ПВЗ сохраняется
+func BuildProductAging(products []models.AgingProduct, days int, now time.Time) *models.ProductAgingReport {
+	report := &models.ProductAgingReport{
+		Days:        days,
+		GeneratedAt: now,
+		Total:       len(products),
+		PVZ:         []models.PVZProductAging{},
+	}
+
+	for _, product := range products {
+		product.AgeDays = int(now.Sub(product.StoredSince) / (24 * time.Hour))
+
+		last := len(report.PVZ) - 1
+		if last < 0 || report.PVZ[last].PvzID != product.PvzID {
+			report.PVZ = append(report.PVZ, models.PVZProductAging{PvzID: product.PvzID, City: product.City, Address: product.Address})
+			last++
+		}
+
+		group := &report.PVZ[last]
+		group.Count++
+		group.OldestDays = max(group.OldestDays, product.AgeDays)
+		group.Products = append(group.Products, product)
+	}
+
+	return report
+}
+
+// WriteProductAgingCSV записывает отчёт о залежавшихся товарах в CSV, по строке на товар
+func WriteProductAgingCSV(w io.Writer, report *models.ProductAgingReport) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"pvz_id", "city", "address", "product_id", "type", "barcode", "reception_id", "reception_number", "stored_since", "age_days"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, group := range report.PVZ {
+		for _, product := range group.Products {
+			record := []string{
+				group.PvzID,
+				group.City,
+				group.Address,
+				product.ProductID,
+				product.Type,
+				stringOrEmpty(product.Barcode),
+				product.ReceptionID,
+				product.ReceptionNumber,
+				product.StoredSince.UTC().Format(time.RFC3339),
+				strconv.Itoa(product.AgeDays),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
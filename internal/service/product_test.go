@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/storage"
+)
+
+func TestCheckDeletion(t *testing.T) {
+	open := &models.Reception{ID: "r1", Status: "in_progress"}
+	closed := &models.Reception{ID: "r1", Status: "close"}
+	first := &models.Product{ID: "p1", ReceptionID: "r1"}
+	last := &models.Product{ID: "p2", ReceptionID: "r1"}
+
+	tests := []struct {
+		name      string
+		role      string
+		reception *models.Reception
+		product   *models.Product
+		last      *models.Product
+		want      error
+	}{
+		{"Сотрудник удаляет последний товар", "employee", open, last, last, nil},
+		{"Сотрудник удаляет не последний товар", "employee", open, first, last, ErrNotLastProduct},
+		{"Модератор удаляет любой товар", "moderator", open, first, nil, nil},
+		{"Приёмка закрыта", "moderator", closed, first, nil, ErrReceptionClosed},
+		{"Неизвестная роль", "client", open, last, last, ErrDeleteForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, checkDeletion(tt.role, tt.reception, tt.product, tt.last), tt.want)
+		})
+	}
+}
+
+func TestCheckRetype(t *testing.T) {
+	open := &models.Reception{ID: "r1", Status: "in_progress"}
+	closed := &models.Reception{ID: "r1", Status: "close"}
+
+	tests := []struct {
+		name      string
+		role      string
+		reception *models.Reception
+		want      error
+	}{
+		{"Сотрудник в открытой приёмке", "employee", open, nil},
+		{"Сотрудник в закрытой приёмке", "employee", closed, ErrRetypeForbidden},
+		{"Модератор в закрытой приёмке", "moderator", closed, nil},
+		{"Модератор в открытой приёмке", "moderator", open, ErrRetypeForbidden},
+		{"Неизвестная роль", "client", open, ErrRetypeForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, checkRetype(tt.role, tt.reception), tt.want)
+		})
+	}
+}
+
+// memoryTx выполняет функцию как транзакцию: блокировки строк, взятые в ней, снимаются по ее завершении
+type memoryTx struct{}
+
+// heldLocksKey - ключ контекста со списком блокировок строк текущей транзакции
+type heldLocksKey struct{}
+
+func (memoryTx) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(heldLocksKey{}) != nil {
+		return fn(ctx)
+	}
+
+	var held []*sync.Mutex
+	err := fn(context.WithValue(ctx, heldLocksKey{}, &held))
+	for _, row := range held {
+		row.Unlock()
+	}
+	return err
+}
+
+// memoryReception - приёмка с товарами в памяти, строка приёмки блокируется до конца транзакции
+type memoryReception struct {
+	row      sync.Mutex
+	mu       sync.Mutex
+	status   string
+	products int
+}
+
+type memoryReceptionRows struct {
+	queries.ReceptionQueriesInterface
+	reception *memoryReception
+}
+
+func (m memoryReceptionRows) LockReceptions(ctx context.Context, receptionIDs []string) ([]models.Reception, error) {
+	m.reception.row.Lock()
+	held := ctx.Value(heldLocksKey{}).(*[]*sync.Mutex)
+	*held = append(*held, &m.reception.row)
+	return []models.Reception{{ID: receptionIDs[0], Status: m.reception.status}}, nil
+}
+
+type memoryProductRows struct {
+	queries.ProductQueriesInterface
+	reception *memoryReception
+}
+
+func (m memoryProductRows) AddProduct(ctx context.Context, receptionID, userID, productType, barcode string) (*models.Product, error) {
+	m.reception.mu.Lock()
+	defer m.reception.mu.Unlock()
+	m.reception.products++
+	return &models.Product{ReceptionID: receptionID, Type: productType}, nil
+}
+
+// memoryLimits ограничивает число товаров одного типа в приёмке. Подсчет медленный,
+// чтобы параллельные добавления без блокировки успели посчитать одни и те же товары
+type memoryLimits struct {
+	queries.ProductLimitQueriesInterface
+	reception *memoryReception
+	limit     int
+}
+
+func (m memoryLimits) GetLimit(ctx context.Context, productType string) (*models.ProductTypeLimit, error) {
+	return &models.ProductTypeLimit{Type: productType, MaxPerReception: m.limit}, nil
+}
+
+func (m memoryLimits) CountReceptionProducts(ctx context.Context, receptionID, productType string) (int, error) {
+	m.reception.mu.Lock()
+	count := m.reception.products
+	m.reception.mu.Unlock()
+	time.Sleep(10 * time.Millisecond)
+	return count, nil
+}
+
+func (m memoryLimits) GetPVZOccupancy(ctx context.Context, pvzID string) (*models.PVZOccupancy, error) {
+	return &models.PVZOccupancy{PvzID: pvzID}, nil
+}
+
+type discardOutbox struct {
+	queries.OutboxQueriesInterface
+}
+
+func (discardOutbox) AddEvent(ctx context.Context, pvzID, eventType string, payload interface{}) error {
+	return nil
+}
+
+// TestAddProductConcurrentTypeLimit проверяет, что параллельные добавления в приёмку без вместимости
+// не превышают ограничение по типу товара: подсчет идет под блокировкой приёмки
+func TestAddProductConcurrentTypeLimit(t *testing.T) {
+	reception := &memoryReception{status: "in_progress"}
+	products := NewProductService(memoryProductRows{reception: reception}, memoryReceptionRows{reception: reception}, memoryTx{}, discardOutbox{}, storage.Disabled{}, memoryLimits{reception: reception, limit: 2}, nil, nil, nil)
+	open := &models.Reception{ID: "r1", PvzID: "pvz1", Status: "in_progress"}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = products.AddProduct(context.Background(), "user-1", open, models.ProductTypeShoes, "", nil, false)
+		}()
+	}
+	wg.Wait()
+
+	var added int
+	for _, err := range errs {
+		var limitErr *ProductLimitError
+		switch {
+		case err == nil:
+			added++
+		case !errors.As(err, &limitErr):
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	assert.Equal(t, 2, added)
+	assert.Equal(t, 2, reception.products)
+}
+
+// TestAddProductReceptionChangedBeforeLock проверяет, что товар не добавляется в приёмку, которую закрыли
+// или приостановили между OpenReception и блокировкой строки: статус перечитывается под блокировкой
+func TestAddProductReceptionChangedBeforeLock(t *testing.T) {
+	tests := []struct {
+		status  string
+		wantErr error
+	}{
+		{status: "close", wantErr: ErrReceptionClosed},
+		{status: "paused", wantErr: ErrReceptionPaused},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			reception := &memoryReception{status: tt.status}
+			products := NewProductService(memoryProductRows{reception: reception}, memoryReceptionRows{reception: reception}, memoryTx{}, discardOutbox{}, storage.Disabled{}, memoryLimits{reception: reception, limit: 10}, nil, nil, nil)
+			// Приёмка была открыта, когда ее нашел OpenReception
+			open := &models.Reception{ID: "r1", PvzID: "pvz1", Status: "in_progress"}
+
+			_, err := products.AddProduct(context.Background(), "user-1", open, models.ProductTypeShoes, "", nil, false)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, 0, reception.products)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"pvz-service/internal/db"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/mapper"
+	"pvz-service/internal/models"
+)
+
+// ErrPVZHasOpenReception возвращается при удалении ПВЗ с открытой приёмкой без force
+var ErrPVZHasOpenReception = errors.New("pvz has an open reception")
+
+// PVZService содержит бизнес-правила жизненного цикла ПВЗ
+type PVZService struct {
+	pvzQueries       queries.PVZQueriesInterface
+	receptionQueries queries.ReceptionQueriesInterface
+	tx               db.Transactor
+	outboxQueries    queries.OutboxQueriesInterface
+}
+
+// NewPVZService создает новый экземпляр PVZService
+func NewPVZService(pvzQueries queries.PVZQueriesInterface, receptionQueries queries.ReceptionQueriesInterface, tx db.Transactor, outboxQueries queries.OutboxQueriesInterface) *PVZService {
+	return &PVZService{
+		pvzQueries:       pvzQueries,
+		receptionQueries: receptionQueries,
+		tx:               tx,
+		outboxQueries:    outboxQueries,
+	}
+}
+
+// ArchivePVZ удаляет ПВЗ в архив. ПВЗ с открытой приёмкой удаляется только с force:
+// тогда его открытые приёмки закрываются в той же транзакции. ПВЗ блокируется до конца
+// транзакции, поэтому приёмка не откроется между проверкой и удалением. userID - модератор,
+// закрывающий приёмки. Возвращает ошибку с queries.ErrNotFound, если ПВЗ нет или он уже в архиве
+func (s *PVZService) ArchivePVZ(ctx context.Context, pvzID, userID string, force bool) (*models.ArchivePVZResponse, error) {
+	result := models.ArchivePVZResponse{
+		PvzID:            pvzID,
+		ClosedReceptions: []models.ReceptionResponse{},
+	}
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		pvz, err := s.pvzQueries.LockPVZ(ctx, pvzID)
+		if err != nil {
+			return err
+		}
+
+		if pvz.OpenReception {
+			if !force {
+				return ErrPVZHasOpenReception
+			}
+			if err := s.closeOpenReceptions(ctx, pvzID, userID, &result); err != nil {
+				return err
+			}
+		}
+
+		archivedAt, err := s.pvzQueries.ArchivePVZ(ctx, pvzID)
+		if err != nil {
+			return err
+		}
+		result.ArchivedAt = archivedAt
+
+		return s.outboxQueries.AddEvent(ctx, pvzID, models.EventPVZArchived, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// closeOpenReceptions закрывает открытые приёмки ПВЗ, о каждой записывается событие reception.closed
+func (s *PVZService) closeOpenReceptions(ctx context.Context, pvzID, userID string, result *models.ArchivePVZResponse) error {
+	receptions, err := s.receptionQueries.GetReceptionsByPVZ(ctx, pvzID)
+	if err != nil {
+		return err
+	}
+
+	for _, reception := range openReceptions(receptions) {
+		closed, err := s.receptionQueries.CloseReception(ctx, reception.ID, userID)
+		if err != nil {
+			return err
+		}
+
+		closedResponse := mapper.Reception(*closed)
+		event := models.CloseReceptionResponse{ReceptionResponse: closedResponse}
+		if err := s.outboxQueries.AddEvent(ctx, pvzID, models.EventReceptionClosed, event); err != nil {
+			return err
+		}
+		result.ClosedReceptions = append(result.ClosedReceptions, closedResponse)
+	}
+
+	return nil
+}
+
+// openReceptions возвращает незакрытые приёмки из списка, включая приостановленные
+func openReceptions(receptions []models.Reception) []models.Reception {
+	var open []models.Reception
+	for _, reception := range receptions {
+		if reception.Status != "close" {
+			open = append(open, reception)
+		}
+	}
+	return open
+}
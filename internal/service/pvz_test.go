@@ -0,0 +1,23 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/models"
+)
+
+func TestOpenReceptions(t *testing.T) {
+	receptions := []models.Reception{
+		{ID: "r1", Status: "close"},
+		{ID: "r2", Status: "in_progress"},
+		{ID: "r3", Status: "close"},
+	}
+
+	open := openReceptions(receptions)
+
+	assert.Len(t, open, 1)
+	assert.Equal(t, "r2", open[0].ID)
+	assert.Empty(t, openReceptions(receptions[:1]))
+}
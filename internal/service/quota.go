@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+)
+
+// quotaWindow - окно счетчика квоты: квоты задаются в операциях в час
+const quotaWindow = time.Hour
+
+// QuotaExceededError сообщает, что операция превысит квоту пользователя в текущем часе
+type QuotaExceededError struct {
+	Exceeded models.QuotaExceeded
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("quota %s exceeded: limit %d per hour, requested %d", e.Exceeded.Operation, e.Exceeded.Limit, e.Exceeded.Requested)
+}
+
+// RetryAfter возвращает время до сброса счетчика квоты
+func (e *QuotaExceededError) RetryAfter(now time.Time) time.Duration {
+	return e.Exceeded.WindowResets.Sub(now)
+}
+
+// QuotaChecker расходует квоту операций пользователя
+type QuotaChecker interface {
+	Consume(ctx context.Context, userID, role, operation string, count int) error
+}
+
+// Quotas ограничивает число операций пользователя в час по квоте его роли.
+// Действующее исключение, выданное администратором пользователю, заменяет квоту роли.
+// Счетчики хранятся в базе данных и общие для всех экземпляров сервиса
+type Quotas struct {
+	quotaQueries queries.QuotaQueriesInterface
+	clock        clock.Clock
+}
+
+var _ QuotaChecker = (*Quotas)(nil)
+
+// NewQuotas создает новый экземпляр Quotas
+func NewQuotas(quotaQueries queries.QuotaQueriesInterface) *Quotas {
+	return &Quotas{
+		quotaQueries: quotaQueries,
+		clock:        clock.System{},
+	}
+}
+
+// Consume расходует count операций operation из квоты пользователя в текущем часе.
+// Возвращает *QuotaExceededError, если квоты не хватает. Вызывается в транзакции операции:
+// при ее отмене израсходованная квота возвращается
+func (q *Quotas) Consume(ctx context.Context, userID, role, operation string, count int) error {
+	// Операции без пользователя, например служебные, квотами не ограничиваются
+	if userID == "" {
+		return nil
+	}
+
+	limit, limited, err := q.limit(ctx, userID, role, operation)
+	if err != nil || !limited {
+		return err
+	}
+
+	now := q.clock.Now().UTC()
+	windowStart := now.Truncate(quotaWindow)
+	ok, err := q.quotaQueries.Consume(ctx, userID, operation, windowStart, count, limit)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &QuotaExceededError{Exceeded: models.QuotaExceeded{
+			Operation:    operation,
+			Limit:        limit,
+			Requested:    count,
+			WindowResets: windowStart.Add(quotaWindow),
+		}}
+	}
+
+	return nil
+}
+
+// limit возвращает квоту операции для пользователя: из действующего исключения или квоты роли.
+// limited false - операция не ограничена
+func (q *Quotas) limit(ctx context.Context, userID, role, operation string) (int, bool, error) {
+	override, err := q.quotaQueries.GetOverride(ctx, userID, operation)
+	if err == nil {
+		if override.MaxPerHour == nil {
+			return 0, false, nil
+		}
+		return *override.MaxPerHour, true, nil
+	}
+	if !errors.Is(err, queries.ErrNotFound) {
+		return 0, false, err
+	}
+
+	quota, err := q.quotaQueries.GetQuota(ctx, role, operation)
+	if errors.Is(err, queries.ErrNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return quota.MaxPerHour, true, nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryQuotas хранит квоты, исключения и счетчики в памяти
+type memoryQuotas struct {
+	queries.QuotaQueriesInterface
+	quotas    map[string]int
+	overrides map[string]*int
+	used      map[string]int
+}
+
+func (m *memoryQuotas) GetQuota(ctx context.Context, role, operation string) (*models.OperationQuota, error) {
+	limit, ok := m.quotas[role+"/"+operation]
+	if !ok {
+		return nil, queries.ErrNotFound
+	}
+	return &models.OperationQuota{Role: role, Operation: operation, MaxPerHour: limit}, nil
+}
+
+func (m *memoryQuotas) GetOverride(ctx context.Context, userID, operation string) (*models.QuotaOverride, error) {
+	limit, ok := m.overrides[userID+"/"+operation]
+	if !ok {
+		return nil, queries.ErrNotFound
+	}
+	return &models.QuotaOverride{UserID: userID, Operation: operation, MaxPerHour: limit}, nil
+}
+
+func (m *memoryQuotas) Consume(ctx context.Context, userID, operation string, windowStart time.Time, count, limit int) (bool, error) {
+	key := fmt.Sprintf("%s/%s/%s", userID, operation, windowStart.Format(time.RFC3339))
+	if m.used[key]+count > limit {
+		return false, nil
+	}
+	m.used[key] += count
+	return true, nil
+}
+
+func TestQuotasConsume(t *testing.T) {
+	now := time.Date(2025, 3, 3, 12, 40, 0, 0, time.UTC)
+	ten := 10
+	store := &memoryQuotas{
+		quotas:    map[string]int{"employee/product.delete": 3},
+		overrides: map[string]*int{"user-2/product.delete": nil, "user-3/product.delete": &ten},
+		used:      map[string]int{},
+	}
+	quotas := &Quotas{quotaQueries: store, clock: clock.Fixed(now)}
+	ctx := context.Background()
+
+	// Квота роли расходуется до конца, следующая операция отклоняется без изменения счетчика
+	require.NoError(t, quotas.Consume(ctx, "user-1", models.RoleEmployee, models.QuotaOperationProductDelete, 2))
+	err := quotas.Consume(ctx, "user-1", models.RoleEmployee, models.QuotaOperationProductDelete, 2)
+	var exceeded *QuotaExceededError
+	require.True(t, errors.As(err, &exceeded))
+	assert.Equal(t, models.QuotaExceeded{
+		Operation:    models.QuotaOperationProductDelete,
+		Limit:        3,
+		Requested:    2,
+		WindowResets: time.Date(2025, 3, 3, 13, 0, 0, 0, time.UTC),
+	}, exceeded.Exceeded)
+	assert.Equal(t, 20*time.Minute, exceeded.RetryAfter(now))
+	require.NoError(t, quotas.Consume(ctx, "user-1", models.RoleEmployee, models.QuotaOperationProductDelete, 1))
+
+	// Исключение без лимита снимает квоту, исключение с лимитом заменяет квоту роли
+	require.NoError(t, quotas.Consume(ctx, "user-2", models.RoleEmployee, models.QuotaOperationProductDelete, 100))
+	require.NoError(t, quotas.Consume(ctx, "user-3", models.RoleEmployee, models.QuotaOperationProductDelete, 10))
+	assert.Error(t, quotas.Consume(ctx, "user-3", models.RoleEmployee, models.QuotaOperationProductDelete, 1))
+
+	// Операции и роли без квоты не ограничены
+	assert.NoError(t, quotas.Consume(ctx, "user-1", models.RoleEmployee, models.QuotaOperationReceptionCreate, 100))
+	assert.NoError(t, quotas.Consume(ctx, "user-4", models.RoleModerator, models.QuotaOperationProductDelete, 100))
+
+	// В следующем часе счетчик начинается заново
+	quotas.clock = clock.Fixed(now.Add(time.Hour))
+	assert.NoError(t, quotas.Consume(ctx, "user-1", models.RoleEmployee, models.QuotaOperationProductDelete, 3))
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/storage"
+)
+
+// exportFlushRows - через сколько строк выгрузка отправляется клиенту, не дожидаясь конца
+const exportFlushRows = 1000
+
+// reportJobTimeout ограничивает время формирования отчёта в фоне
+const reportJobTimeout = time.Hour
+
+// ReceptionExporter выгружает приёмки всех ПВЗ города в CSV: сразу в ответ
+// или в фоне, в файл хранилища вложений, который скачивается по ссылке из задания отчёта
+type ReceptionExporter struct {
+	reportQueries    queries.ReportQueriesInterface
+	reportJobQueries queries.ReportJobQueriesInterface
+	storage          storage.Storage
+	running          sync.WaitGroup
+}
+
+// NewReceptionExporter создает новый экземпляр ReceptionExporter
+func NewReceptionExporter(reportQueries queries.ReportQueriesInterface, reportJobQueries queries.ReportJobQueriesInterface, storage storage.Storage) *ReceptionExporter {
+	return &ReceptionExporter{
+		reportQueries:    reportQueries,
+		reportJobQueries: reportJobQueries,
+		storage:          storage,
+	}
+}
+
+// WriteCSV записывает выгрузку в w по мере чтения из БД. Если w поддерживает Flush
+// (как http.ResponseWriter), выгрузка отправляется частями по exportFlushRows строк
+func (e *ReceptionExporter) WriteCSV(ctx context.Context, w io.Writer, params models.ReceptionExportParams) error {
+	writer := csv.NewWriter(w)
+	flusher, _ := w.(interface{ Flush() })
+
+	header := []string{"pvz_id", "city", "reception_id", "number", "datetime", "status", "product_count", "created_by", "closed_by"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	written := 0
+	err := e.reportQueries.StreamCityReceptions(ctx, params.City, params.From, params.To, func(row models.ReceptionExportRow) error {
+		record := []string{
+			row.PvzID,
+			row.City,
+			row.ReceptionID,
+			row.Number,
+			row.DateTime.UTC().Format(time.RFC3339),
+			row.Status,
+			strconv.Itoa(row.ProductCount),
+			stringOrEmpty(row.CreatedBy),
+			stringOrEmpty(row.ClosedBy),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+
+		written++
+		if written%exportFlushRows == 0 {
+			writer.Flush()
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return writer.Error()
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// StartExport создает задание отчёта и формирует выгрузку в фоне. Без хранилища вложений возвращает storage.ErrNotConfigured. Формирование не зависит
+// от запроса, который его запустил: клиент узнает результат по заданию отчёта
+func (e *ReceptionExporter) StartExport(ctx context.Context, params models.ReceptionExportParams, createdBy string) (*models.ReportJob, error) {
+	// Без хранилища готовый отчёт некуда сохранить
+	if _, disabled := e.storage.(storage.Disabled); disabled {
+		return nil, storage.ErrNotConfigured
+	}
+
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal report params: %w", err)
+	}
+
+	job, err := e.reportJobQueries.CreateReportJob(ctx, models.ReportKindCityReceptions, encoded, createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	e.running.Add(1)
+	go func() {
+		defer e.running.Done()
+
+		jobCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), reportJobTimeout)
+		defer cancel()
+		e.generate(jobCtx, job.ID, params)
+	}()
+
+	return job, nil
+}
+
+// Wait ждет завершения выгрузок, запущенных в фоне
+func (e *ReceptionExporter) Wait() {
+	e.running.Wait()
+}
+
+// generate записывает выгрузку во временный файл, сохраняет его в хранилище и завершает задание.
+// Временный файл нужен, потому что хранилищу передается размер объекта
+func (e *ReceptionExporter) generate(ctx context.Context, jobID string, params models.ReceptionExportParams) {
+	key := "reports/" + jobID + ".csv"
+	err := func() error {
+		file, err := os.CreateTemp("", "report-*.csv")
+		if err != nil {
+			return fmt.Errorf("failed to create report file: %w", err)
+		}
+		defer os.Remove(file.Name())
+		defer file.Close()
+
+		if err := e.WriteCSV(ctx, file, params); err != nil {
+			return err
+		}
+
+		size, err := file.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return fmt.Errorf("failed to get report size: %w", err)
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("failed to rewind report file: %w", err)
+		}
+
+		return e.storage.Put(ctx, key, file, size, "text/csv")
+	}()
+
+	if err != nil {
+		log.Printf("Report job %s failed: %v", jobID, err)
+		if failErr := e.reportJobQueries.FailReportJob(ctx, jobID, err.Error()); failErr != nil {
+			log.Printf("Failed to mark report job %s as failed: %v", jobID, failErr)
+		}
+		return
+	}
+
+	if err := e.reportJobQueries.CompleteReportJob(ctx, jobID, key); err != nil {
+		log.Printf("Failed to complete report job %s: %v", jobID, err)
+	}
+}
+
+// stringOrEmpty возвращает значение или пустую строку для NULL
+func stringOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
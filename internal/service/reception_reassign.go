@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/mapper"
+	"pvz-service/internal/models"
+)
+
+// Ошибки переноса приёмки в другой ПВЗ
+var (
+	// ErrReassignSamePVZ возвращается, если приёмка уже относится к ПВЗ назначения
+	ErrReassignSamePVZ = errors.New("reception already belongs to target pvz")
+	// ErrTargetPVZNotFound возвращается, если ПВЗ назначения нет или он в архиве
+	ErrTargetPVZNotFound = errors.New("target pvz not found")
+	// ErrTargetPVZHasOpenReception возвращается при переносе открытой приёмки в ПВЗ, где уже есть открытая приёмка
+	ErrTargetPVZHasOpenReception = errors.New("target pvz has an open reception")
+)
+
+// ReassignReception переносит приёмку вместе с товарами в ПВЗ toPvzID, например если приёмку
+// открыли не в том ПВЗ. Номер приёмки сохраняется. ПВЗ назначения блокируется раньше приёмки,
+// как при удалении ПВЗ, чтобы в нем не открылась приёмка до конца переноса. Перенос записывается
+// в журнал от имени userID, событие reception.reassigned попадает в ленты обоих ПВЗ.
+// Возвращает ошибку с queries.ErrNotFound, если приёмки нет, и queries.ErrReceptionHasOrders,
+// если ее товары уже входят в заказы
+func (s *PVZService) ReassignReception(ctx context.Context, receptionID, toPvzID, userID string, reason *string) (*models.ReassignReceptionResponse, error) {
+	var result models.ReassignReceptionResponse
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		target, err := s.pvzQueries.LockPVZ(ctx, toPvzID)
+		if errors.Is(err, queries.ErrNotFound) {
+			return ErrTargetPVZNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		receptions, err := s.receptionQueries.LockReceptions(ctx, []string{receptionID})
+		if err != nil {
+			return err
+		}
+		if len(receptions) == 0 {
+			return fmt.Errorf("reception %s: %w", receptionID, queries.ErrNotFound)
+		}
+		reception := receptions[0]
+
+		if reception.PvzID == toPvzID {
+			return ErrReassignSamePVZ
+		}
+		if reception.Status == "in_progress" && target.OpenReception {
+			return ErrTargetPVZHasOpenReception
+		}
+
+		moved, err := s.receptionQueries.ReassignReception(ctx, reception.ID, reception.PvzID, toPvzID, userID, reason)
+		if err != nil {
+			return err
+		}
+
+		result = models.ReassignReceptionResponse{
+			Reception: mapper.Reception(*moved),
+			FromPvzID: reception.PvzID,
+			ToPvzID:   toPvzID,
+			Reason:    reason,
+		}
+
+		if err := s.outboxQueries.AddEvent(ctx, reception.PvzID, models.EventReceptionReassigned, result); err != nil {
+			return err
+		}
+		return s.outboxQueries.AddEvent(ctx, toPvzID, models.EventReceptionReassigned, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/i18n"
+	"pvz-service/internal/mail"
+	"pvz-service/internal/models"
+	"pvz-service/internal/storage"
+)
+
+// dueReportsBatch - сколько отчётов по расписанию забирает один проход фонового задания
+const dueReportsBatch = 20
+
+// ReportDefinitionError сообщает, что группировка или фильтр сохраненного отчёта недоступны для его сущности
+type ReportDefinitionError struct {
+	Field string
+	Value string
+}
+
+func (e *ReportDefinitionError) Error() string {
+	return fmt.Sprintf("report %s %q is not supported for entity", e.Field, e.Value)
+}
+
+// ValidateReportDefinition проверяет, что группировки и фильтры запроса доступны для сущности отчёта.
+// Возвращает *ReportDefinitionError для первого недоступного поля
+func ValidateReportDefinition(req models.ReportDefinitionRequest) error {
+	for _, group := range req.GroupBy {
+		if !slices.Contains(models.ReportGroupings[req.Entity], group) {
+			return &ReportDefinitionError{Field: "groupBy", Value: group}
+		}
+	}
+	if req.Status != nil && !slices.Contains(models.ReportStatuses[req.Entity], *req.Status) {
+		return &ReportDefinitionError{Field: "status", Value: *req.Status}
+	}
+	if req.ProductType != nil && req.Entity != models.ReportEntityProducts {
+		return &ReportDefinitionError{Field: "productType", Value: *req.ProductType}
+	}
+	return nil
+}
+
+// ReportBuilder запускает сохраненные отчёты конструктора: вручную или по расписанию.
+// Отчёт формируется в фоне в файл хранилища вложений, который скачивается по ссылке из задания отчёта,
+// ссылка отправляется письмом получателям отчёта
+type ReportBuilder struct {
+	definitionQueries queries.ReportDefinitionQueriesInterface
+	reportJobQueries  queries.ReportJobQueriesInterface
+	storage           storage.Storage
+	mailSender        mail.SenderInterface
+	clock             clock.Clock
+	running           sync.WaitGroup
+}
+
+// NewReportBuilder создает новый экземпляр ReportBuilder
+func NewReportBuilder(definitionQueries queries.ReportDefinitionQueriesInterface, reportJobQueries queries.ReportJobQueriesInterface, storage storage.Storage, mailSender mail.SenderInterface) *ReportBuilder {
+	return &ReportBuilder{
+		definitionQueries: definitionQueries,
+		reportJobQueries:  reportJobQueries,
+		storage:           storage,
+		mailSender:        mailSender,
+		clock:             clock.System{},
+	}
+}
+
+// NextRunAt возвращает первый запуск отчёта с расписанием schedule, сохраняемого сейчас: через период
+// расписания. Для отчёта без расписания возвращает nil
+func (b *ReportBuilder) NextRunAt(schedule *string) *time.Time {
+	if schedule == nil {
+		return nil
+	}
+	next := b.clock.Now().Add(models.ReportScheduleInterval(*schedule))
+	return &next
+}
+
+// Run создает задание отчёта по определению и формирует отчёт в фоне. Без хранилища вложений
+// возвращает storage.ErrNotConfigured. Результат клиент узнает по заданию отчёта
+func (b *ReportBuilder) Run(ctx context.Context, definition models.ReportDefinition, createdBy string) (*models.ReportJob, error) {
+	job, err := b.createJob(ctx, definition, createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	b.running.Add(1)
+	go func() {
+		defer b.running.Done()
+
+		jobCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), reportJobTimeout)
+		defer cancel()
+		b.generate(jobCtx, job.ID, definition)
+	}()
+
+	return job, nil
+}
+
+// RunDue формирует отчёты, которым пора запускаться по расписанию, и возвращает количество запущенных.
+// Отчёты формируются по очереди в вызывающей горутине фонового задания. Забранные отчёты уже перенесены
+// на следующий период, поэтому ошибка запуска одного не останавливает остальные: ошибки возвращаются вместе
+func (b *ReportBuilder) RunDue(ctx context.Context) (int, error) {
+	definitions, err := b.definitionQueries.ClaimDueDefinitions(ctx, b.clock.Now(), dueReportsBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		count int
+		errs  []error
+	)
+	for _, definition := range definitions {
+		job, err := b.createJob(ctx, definition, "")
+		if err != nil {
+			log.Printf("Failed to start scheduled report %s: %v", definition.ID, err)
+			errs = append(errs, fmt.Errorf("report %s: %w", definition.ID, err))
+			continue
+		}
+
+		jobCtx, cancel := context.WithTimeout(ctx, reportJobTimeout)
+		b.generate(jobCtx, job.ID, definition)
+		cancel()
+		count++
+	}
+
+	return count, errors.Join(errs...)
+}
+
+// Wait ждет завершения отчётов, запущенных в фоне
+func (b *ReportBuilder) Wait() {
+	b.running.Wait()
+}
+
+// createJob создает задание отчёта, в параметрах которого сохраняется определение на момент запуска
+func (b *ReportBuilder) createJob(ctx context.Context, definition models.ReportDefinition, createdBy string) (*models.ReportJob, error) {
+	// Без хранилища готовый отчёт некуда сохранить
+	if _, disabled := b.storage.(storage.Disabled); disabled {
+		return nil, storage.ErrNotConfigured
+	}
+
+	encoded, err := json.Marshal(definition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal report definition: %w", err)
+	}
+
+	return b.reportJobQueries.CreateReportJob(ctx, models.ReportKindDefinition, encoded, createdBy)
+}
+
+// generate строит отчёт, сохраняет его в хранилище, завершает задание и отправляет ссылку получателям
+func (b *ReportBuilder) generate(ctx context.Context, jobID string, definition models.ReportDefinition) {
+	key := "reports/" + jobID + "." + definition.Format
+	err := func() error {
+		var since *time.Time
+		if definition.PeriodDays != nil {
+			from := b.clock.Now().AddDate(0, 0, -*definition.PeriodDays)
+			since = &from
+		}
+
+		rows, err := b.definitionQueries.BuildReport(ctx, definition, since)
+		if err != nil {
+			return err
+		}
+
+		file, err := os.CreateTemp("", "report-*."+definition.Format)
+		if err != nil {
+			return fmt.Errorf("failed to create report file: %w", err)
+		}
+		defer os.Remove(file.Name())
+		defer file.Close()
+
+		contentType := "text/csv"
+		if definition.Format == "json" {
+			contentType = "application/json"
+			err = WriteReportJSON(file, definition, rows)
+		} else {
+			err = WriteReportCSV(file, definition, rows)
+		}
+		if err != nil {
+			return err
+		}
+
+		size, err := file.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return fmt.Errorf("failed to get report size: %w", err)
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("failed to rewind report file: %w", err)
+		}
+
+		return b.storage.Put(ctx, key, file, size, contentType)
+	}()
+
+	if err != nil {
+		log.Printf("Report job %s failed: %v", jobID, err)
+		if failErr := b.reportJobQueries.FailReportJob(ctx, jobID, err.Error()); failErr != nil {
+			log.Printf("Failed to mark report job %s as failed: %v", jobID, failErr)
+		}
+		return
+	}
+
+	if err := b.reportJobQueries.CompleteReportJob(ctx, jobID, key); err != nil {
+		log.Printf("Failed to complete report job %s: %v", jobID, err)
+		return
+	}
+
+	b.deliver(ctx, definition, key)
+}
+
+// deliver отправляет получателям отчёта ссылку на скачивание. Ошибка отправки одному получателю
+// не мешает остальным: отчёт всегда доступен по заданию отчёта
+func (b *ReportBuilder) deliver(ctx context.Context, definition models.ReportDefinition, key string) {
+	if len(definition.Recipients) == 0 {
+		return
+	}
+
+	url, err := b.storage.URL(ctx, key)
+	if err != nil {
+		log.Printf("Failed to get download link of report %s: %v", definition.ID, err)
+		return
+	}
+
+	subject := i18n.T(ctx, i18n.MsgReportReadySubject) + ": " + definition.Name
+	body := i18n.T(ctx, i18n.MsgReportReadyMessage) + ": " + url
+	for _, recipient := range definition.Recipients {
+		if err := b.mailSender.Send(ctx, recipient, subject, body); err != nil {
+			log.Printf("Failed to send report %s to %s: %v", definition.ID, recipient, err)
+		}
+	}
+}
+
+// reportHeader возвращает названия колонок отчёта: группировки и показатели сущности
+func reportHeader(definition models.ReportDefinition) []string {
+	header := append([]string{}, definition.GroupBy...)
+	header = append(header, "count")
+	if definition.Entity == models.ReportEntityReceptions {
+		header = append(header, "products")
+	}
+	return header
+}
+
+// WriteReportCSV записывает отчёт конструктора в CSV: строка заголовка и строка на группу
+func WriteReportCSV(w io.Writer, definition models.ReportDefinition, rows []models.ReportResultRow) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(reportHeader(definition)); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		record := append([]string{}, row.Groups...)
+		record = append(record, strconv.Itoa(row.Count))
+		if definition.Entity == models.ReportEntityReceptions {
+			record = append(record, strconv.Itoa(row.Products))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteReportJSON записывает отчёт конструктора в JSON: массив объектов с группировками и показателями
+func WriteReportJSON(w io.Writer, definition models.ReportDefinition, rows []models.ReportResultRow) error {
+	result := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		item := make(map[string]interface{}, len(row.Groups)+2)
+		for i, group := range definition.GroupBy {
+			item[group] = row.Groups[i]
+		}
+		item["count"] = row.Count
+		if definition.Entity == models.ReportEntityReceptions {
+			item["products"] = row.Products
+		}
+		result = append(result, item)
+	}
+
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
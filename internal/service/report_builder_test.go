@@ -0,0 +1,214 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/storage"
+)
+
+// memoryReportDefinitions отдает заданные отчёты по расписанию и строки отчёта
+type memoryReportDefinitions struct {
+	queries.ReportDefinitionQueriesInterface
+	due   []models.ReportDefinition
+	rows  []models.ReportResultRow
+	since *time.Time
+}
+
+func (m *memoryReportDefinitions) ClaimDueDefinitions(ctx context.Context, now time.Time, limit int) ([]models.ReportDefinition, error) {
+	due := m.due
+	m.due = nil
+	return due, nil
+}
+
+func (m *memoryReportDefinitions) BuildReport(ctx context.Context, definition models.ReportDefinition, since *time.Time) ([]models.ReportResultRow, error) {
+	m.since = since
+	return m.rows, nil
+}
+
+// memoryReportJobs хранит задания отчётов в памяти, первые failures созданий заданий завершаются ошибкой
+type memoryReportJobs struct {
+	queries.ReportJobQueriesInterface
+	jobs     map[string]*models.ReportJob
+	failures int
+}
+
+func (m *memoryReportJobs) CreateReportJob(ctx context.Context, kind string, params []byte, createdBy string) (*models.ReportJob, error) {
+	if m.failures > 0 {
+		m.failures--
+		return nil, errors.New("database error")
+	}
+	job := &models.ReportJob{ID: "job-1", Kind: kind, Status: models.ReportJobRunning}
+	m.jobs[job.ID] = job
+	return job, nil
+}
+
+func (m *memoryReportJobs) CompleteReportJob(ctx context.Context, id, resultKey string) error {
+	m.jobs[id].Status = models.ReportJobDone
+	m.jobs[id].ObjectKey = &resultKey
+	return nil
+}
+
+func (m *memoryReportJobs) FailReportJob(ctx context.Context, id, message string) error {
+	m.jobs[id].Status = models.ReportJobFailed
+	return nil
+}
+
+// memoryObjects сохраняет файлы хранилища вложений в памяти
+type memoryObjects map[string]string
+
+func (m memoryObjects) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	data, err := io.ReadAll(body)
+	m[key] = string(data)
+	return err
+}
+
+func (m memoryObjects) URL(ctx context.Context, key string) (string, error) {
+	return "https://storage.example.com/" + key, nil
+}
+
+// recordingMail запоминает отправленные письма
+type recordingMail struct {
+	sent []string
+}
+
+func (r *recordingMail) Send(ctx context.Context, to, subject, body string) error {
+	r.sent = append(r.sent, to+"|"+subject+"|"+body)
+	return nil
+}
+
+// TestValidateReportDefinition проверяет допустимость группировок и фильтров для сущности отчёта
+func TestValidateReportDefinition(t *testing.T) {
+	status := "issued"
+	productType := models.ProductTypeShoes
+
+	tests := []struct {
+		name    string
+		req     models.ReportDefinitionRequest
+		wantErr *ReportDefinitionError
+	}{
+		{
+			name: "Товары по типам и дням",
+			req:  models.ReportDefinitionRequest{Entity: models.ReportEntityProducts, GroupBy: []string{"type", "day"}, ReportFilters: models.ReportFilters{Status: &status, ProductType: &productType}},
+		},
+		{
+			name:    "Тип товара недоступен для приёмок",
+			req:     models.ReportDefinitionRequest{Entity: models.ReportEntityReceptions, GroupBy: []string{"city", "type"}},
+			wantErr: &ReportDefinitionError{Field: "groupBy", Value: "type"},
+		},
+		{
+			name:    "Статус товара недоступен для приёмок",
+			req:     models.ReportDefinitionRequest{Entity: models.ReportEntityReceptions, ReportFilters: models.ReportFilters{Status: &status}},
+			wantErr: &ReportDefinitionError{Field: "status", Value: status},
+		},
+		{
+			name:    "Фильтр по типу товара недоступен для приёмок",
+			req:     models.ReportDefinitionRequest{Entity: models.ReportEntityReceptions, ReportFilters: models.ReportFilters{ProductType: &productType}},
+			wantErr: &ReportDefinitionError{Field: "productType", Value: productType},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateReportDefinition(tt.req)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+// TestReportBuilderRunDue проверяет формирование отчёта по расписанию: файл в хранилище,
+// завершенное задание и письмо получателю со ссылкой
+func TestReportBuilderRunDue(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	periodDays := 7
+	definitions := &memoryReportDefinitions{
+		due: []models.ReportDefinition{{
+			ID:            "def-1",
+			Name:          "Приёмки за неделю",
+			Entity:        models.ReportEntityReceptions,
+			ReportFilters: models.ReportFilters{PeriodDays: &periodDays},
+			GroupBy:       []string{"city"},
+			Format:        "csv",
+			Recipients:    []string{"boss@example.com"},
+		}},
+		rows: []models.ReportResultRow{{Groups: []string{"Москва"}, Count: 3, Products: 42}},
+	}
+	jobs := &memoryReportJobs{jobs: map[string]*models.ReportJob{}}
+	objects := memoryObjects{}
+	sender := &recordingMail{}
+	builder := NewReportBuilder(definitions, jobs, objects, sender)
+	builder.clock = clock.Fixed(now)
+
+	count, err := builder.RunDue(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, now.AddDate(0, 0, -7), *definitions.since)
+	assert.Equal(t, models.ReportJobDone, jobs.jobs["job-1"].Status)
+	assert.Equal(t, "city,count,products\nМосква,3,42\n", objects["reports/job-1.csv"])
+	require.Len(t, sender.sent, 1)
+	assert.Contains(t, sender.sent[0], "boss@example.com|")
+	assert.Contains(t, sender.sent[0], "Приёмки за неделю")
+	assert.Contains(t, sender.sent[0], "https://storage.example.com/reports/job-1.csv")
+
+	// Очередной проход без отчётов к запуску ничего не формирует
+	count, err = builder.RunDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+// TestReportBuilderRunDueContinuesAfterError проверяет, что ошибка запуска одного отчёта по расписанию
+// не мешает сформировать остальные забранные отчёты
+func TestReportBuilderRunDueContinuesAfterError(t *testing.T) {
+	definitions := &memoryReportDefinitions{
+		due: []models.ReportDefinition{
+			{ID: "def-1", Entity: models.ReportEntityProducts, Format: "csv"},
+			{ID: "def-2", Entity: models.ReportEntityProducts, Format: "csv"},
+		},
+		rows: []models.ReportResultRow{{Groups: []string{}, Count: 5}},
+	}
+	jobs := &memoryReportJobs{jobs: map[string]*models.ReportJob{}, failures: 1}
+	objects := memoryObjects{}
+	builder := NewReportBuilder(definitions, jobs, objects, &recordingMail{})
+
+	count, err := builder.RunDue(context.Background())
+
+	assert.ErrorContains(t, err, "report def-1")
+	assert.Equal(t, 1, count)
+	assert.Equal(t, models.ReportJobDone, jobs.jobs["job-1"].Status)
+	assert.Equal(t, "count\n5\n", objects["reports/job-1.csv"])
+}
+
+// TestReportBuilderRunStorageDisabled проверяет отказ запуска отчёта без хранилища вложений
+func TestReportBuilderRunStorageDisabled(t *testing.T) {
+	builder := NewReportBuilder(&memoryReportDefinitions{}, &memoryReportJobs{jobs: map[string]*models.ReportJob{}}, storage.Disabled{}, &recordingMail{})
+
+	_, err := builder.Run(context.Background(), models.ReportDefinition{Format: "csv"}, "user-1")
+
+	assert.ErrorIs(t, err, storage.ErrNotConfigured)
+}
+
+// TestWriteReportJSON проверяет запись отчёта по товарам в JSON без показателя товаров в приёмках
+func TestWriteReportJSON(t *testing.T) {
+	definition := models.ReportDefinition{Entity: models.ReportEntityProducts, GroupBy: []string{"type"}}
+	var buf bytes.Buffer
+
+	err := WriteReportJSON(&buf, definition, []models.ReportResultRow{{Groups: []string{"обувь"}, Count: 5}})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"type":"обувь","count":5}]`, buf.String())
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/otp"
+	"pvz-service/internal/utils"
+)
+
+// Ошибки обновления токена по refresh-токену
+var (
+	// ErrRefreshTokenInvalid возвращается для неизвестного, уже использованного или истекшего refresh-токена
+	ErrRefreshTokenInvalid = errors.New("invalid refresh token")
+	// ErrUserDeactivated возвращается, если пользователь сессии деактивирован
+	ErrUserDeactivated = errors.New("user is deactivated")
+)
+
+// SessionIssuer выдает токен новой сессии пользователя и обновляет токен сессии «запомнить меня»
+type SessionIssuer interface {
+	IssueToken(ctx context.Context, userID, role, userAgent, ip string, rememberMe bool) (*models.LoginResponse, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*models.LoginResponse, error)
+}
+
+// SessionService создает сессии пользователей и выдает привязанные к ним токены
+type SessionService struct {
+	jwtManager      utils.JWTManagerInterface
+	sessionQueries  queries.SessionQueriesInterface
+	employeeQueries queries.EmployeeQueriesInterface
+	authQueries     queries.AuthQueriesInterface
+	rememberMeTTL   time.Duration
+	clock           clock.Clock
+}
+
+// NewSessionService создает новый экземпляр SessionService.
+// Обычная сессия действует столько же, сколько токен роли, и определяет, сколько сессия видна в списке активных.
+// Сессия «запомнить меня» действует rememberMeTTL, токен в ней обновляется по refresh-токену;
+// при нулевом rememberMeTTL refresh-токены не выдаются
+func NewSessionService(jwtManager utils.JWTManagerInterface, sessionQueries queries.SessionQueriesInterface, employeeQueries queries.EmployeeQueriesInterface, authQueries queries.AuthQueriesInterface, rememberMeTTL time.Duration) *SessionService {
+	return &SessionService{
+		jwtManager:      jwtManager,
+		sessionQueries:  sessionQueries,
+		employeeQueries: employeeQueries,
+		authQueries:     authQueries,
+		rememberMeTTL:   rememberMeTTL,
+		clock:           clock.System{},
+	}
+}
+
+// IssueToken сохраняет сессию с данными устройства и выдает токен с ID сессии.
+// С rememberMe сессия живет rememberMeTTL и в ответ добавляется refresh-токен
+func (s *SessionService) IssueToken(ctx context.Context, userID, role, userAgent, ip string, rememberMe bool) (*models.LoginResponse, error) {
+	session := models.Session{
+		UserID:    userID,
+		UserAgent: userAgent,
+		IP:        ip,
+		ExpiresAt: s.clock.Now().Add(s.jwtManager.TokenTTL(role)),
+	}
+
+	var refreshToken string
+	if rememberMe && s.rememberMeTTL > 0 {
+		var err error
+		refreshToken, err = otp.GenerateToken()
+		if err != nil {
+			return nil, err
+		}
+		session.RefreshTokenHash = otp.HashToken(refreshToken)
+		session.ExpiresAt = s.clock.Now().Add(s.rememberMeTTL)
+	}
+
+	created, err := s.sessionQueries.CreateSession(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.generateToken(ctx, userID, role, created.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LoginResponse{Token: token, RefreshToken: refreshToken}, nil
+}
+
+// RefreshToken выдает новый токен сессии «запомнить меня» и заменяет refresh-токен:
+// предыдущий refresh-токен после обмена недействителен
+func (s *SessionService) RefreshToken(ctx context.Context, refreshToken string) (*models.LoginResponse, error) {
+	nextToken, err := otp.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.sessionQueries.RotateRefreshToken(ctx, otp.HashToken(refreshToken), otp.HashToken(nextToken))
+	if errors.Is(err, queries.ErrNotFound) {
+		return nil, ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Роль и активность читаются заново: за время сессии пользователя могли деактивировать
+	user, err := s.authQueries.GetUserCredentialsByID(ctx, session.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session user: %w", err)
+	}
+	if !user.IsActive {
+		return nil, ErrUserDeactivated
+	}
+
+	token, err := s.generateToken(ctx, user.ID, user.Role, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LoginResponse{Token: token, RefreshToken: nextToken}, nil
+}
+
+// generateToken выдает токен сессии. В токен сотрудника записываются ПВЗ, в которых он работает
+func (s *SessionService) generateToken(ctx context.Context, userID, role, sessionID string) (string, error) {
+	var pvzIDs []string
+	if role == models.RoleEmployee {
+		assigned, err := s.employeeQueries.GetEmployeePVZ(ctx, userID)
+		if err != nil {
+			return "", err
+		}
+		pvzIDs = assigned
+	}
+
+	token, err := s.jwtManager.GenerateToken(userID, role, sessionID, pvzIDs)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return token, nil
+}
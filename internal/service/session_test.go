@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/config"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fixedSessionQueries сохраняет сессию с заданным ID и запоминает хеш ее refresh-токена
+type fixedSessionQueries struct {
+	queries.SessionQueriesInterface
+	refreshTokenHash string
+	created          models.Session
+}
+
+func (q *fixedSessionQueries) CreateSession(ctx context.Context, session models.Session) (*models.Session, error) {
+	session.ID = "session-1"
+	q.created = session
+	q.refreshTokenHash = session.RefreshTokenHash
+	return &session, nil
+}
+
+func (q *fixedSessionQueries) RotateRefreshToken(ctx context.Context, oldHash, newHash string) (*models.Session, error) {
+	if q.refreshTokenHash == "" || oldHash != q.refreshTokenHash {
+		return nil, queries.ErrNotFound
+	}
+	q.refreshTokenHash = newHash
+	return &q.created, nil
+}
+
+// sessionUsers возвращает пользователей сессий
+type sessionUsers struct {
+	queries.AuthQueriesInterface
+	active bool
+}
+
+func (u sessionUsers) GetUserCredentialsByID(ctx context.Context, userID string) (*models.User, error) {
+	return &models.User{ID: userID, Role: models.RoleEmployee, IsActive: u.active}, nil
+}
+
+// assignedPVZ возвращает ПВЗ, назначенные сотрудникам
+type assignedPVZ struct {
+	queries.EmployeeQueriesInterface
+	byUser map[string][]string
+}
+
+func (q assignedPVZ) GetEmployeePVZ(ctx context.Context, userID string) ([]string, error) {
+	return q.byUser[userID], nil
+}
+
+func TestSessionServicePVZScope(t *testing.T) {
+	jwtManager := utils.NewJWTManager(&config.JWTConfig{Secret: "test-secret", ExpireTime: time.Hour})
+	sessions := NewSessionService(jwtManager, &fixedSessionQueries{}, assignedPVZ{byUser: map[string][]string{
+		"employee-id":  {"pvz-1", "pvz-2"},
+		"moderator-id": {"pvz-1"},
+	}}, sessionUsers{active: true}, 30*24*time.Hour)
+
+	tokens, err := sessions.IssueToken(context.Background(), "employee-id", models.RoleEmployee, "curl", "127.0.0.1", false)
+	assert.NoError(t, err)
+	assert.Empty(t, tokens.RefreshToken)
+	claims, err := jwtManager.ValidateToken(tokens.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, "session-1", claims.ID)
+	assert.Equal(t, []string{"pvz-1", "pvz-2"}, claims.PVZIDs)
+
+	// Список ПВЗ записывается только в токены сотрудников
+	tokens, err = sessions.IssueToken(context.Background(), "moderator-id", models.RoleModerator, "curl", "127.0.0.1", false)
+	assert.NoError(t, err)
+	claims, err = jwtManager.ValidateToken(tokens.Token)
+	assert.NoError(t, err)
+	assert.Empty(t, claims.PVZIDs)
+}
+
+// TestSessionServiceRememberMe проверяет долгую сессию киоска и одноразовость refresh-токена
+func TestSessionServiceRememberMe(t *testing.T) {
+	jwtManager := utils.NewJWTManager(&config.JWTConfig{
+		Secret:         "test-secret",
+		ExpireTime:     24 * time.Hour,
+		RoleExpireTime: map[string]time.Duration{models.RoleModerator: time.Hour},
+	})
+	sessionQueries := &fixedSessionQueries{}
+	sessions := NewSessionService(jwtManager, sessionQueries, assignedPVZ{}, sessionUsers{active: true}, 30*24*time.Hour)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	sessions.clock = clock.Fixed(now)
+
+	// Обычная сессия модератора живет столько же, сколько его токен
+	_, err := sessions.IssueToken(context.Background(), "moderator-id", models.RoleModerator, "curl", "127.0.0.1", false)
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(time.Hour), sessionQueries.created.ExpiresAt)
+
+	tokens, err := sessions.IssueToken(context.Background(), "employee-id", models.RoleEmployee, "kiosk", "127.0.0.1", true)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.RefreshToken)
+	assert.Equal(t, now.Add(30*24*time.Hour), sessionQueries.created.ExpiresAt)
+
+	refreshed, err := sessions.RefreshToken(context.Background(), tokens.RefreshToken)
+	assert.NoError(t, err)
+	assert.NotEqual(t, tokens.RefreshToken, refreshed.RefreshToken)
+	claims, err := jwtManager.ValidateToken(refreshed.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, "session-1", claims.ID)
+
+	// Использованный refresh-токен больше не действует
+	_, err = sessions.RefreshToken(context.Background(), tokens.RefreshToken)
+	assert.ErrorIs(t, err, ErrRefreshTokenInvalid)
+
+	// Деактивированный пользователь не получает новый токен
+	sessions.authQueries = sessionUsers{active: false}
+	_, err = sessions.RefreshToken(context.Background(), refreshed.RefreshToken)
+	assert.ErrorIs(t, err, ErrUserDeactivated)
+}
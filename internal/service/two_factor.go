@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/config"
+	"pvz-service/internal/db"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/otp"
+)
+
+// Ошибки двухфакторной аутентификации
+var (
+	// ErrTwoFactorEnabled возвращается при повторном подключении уже включенной двухфакторной аутентификации
+	ErrTwoFactorEnabled = errors.New("two-factor authentication is already enabled")
+	// ErrTwoFactorNotEnabled возвращается, если двухфакторная аутентификация не включена
+	// или, при подтверждении, не начато ее подключение
+	ErrTwoFactorNotEnabled = errors.New("two-factor authentication is not enabled")
+	// ErrTwoFactorCodeInvalid возвращается для неверного, уже использованного или просроченного кода
+	ErrTwoFactorCodeInvalid = errors.New("invalid two-factor code")
+	// ErrChallengeInvalid возвращается для неизвестного, истекшего или уже завершенного входа
+	ErrChallengeInvalid = errors.New("invalid login challenge")
+	// ErrChallengeAttemptsExceeded возвращается, если исчерпаны попытки ввода второго фактора
+	ErrChallengeAttemptsExceeded = errors.New("login challenge attempts exceeded")
+)
+
+// totpCodePattern отличает код из приложения от резервного кода
+var totpCodePattern = regexp.MustCompile(`^\d{6}$`)
+
+// TwoFactorChallenger начинает второй этап входа для пользователя с включенной двухфакторной аутентификацией
+type TwoFactorChallenger interface {
+	Challenge(ctx context.Context, userID string, rememberMe bool) (*models.TwoFactorChallengeResponse, error)
+}
+
+// TwoFactorService подключает двухфакторную аутентификацию по TOTP и проверяет второй фактор при входе.
+// Вместо кода из приложения можно ввести один из резервных кодов, каждый действует один раз
+type TwoFactorService struct {
+	queries queries.TwoFactorQueriesInterface
+	tx      db.Transactor
+	config  config.TwoFactorConfig
+	clock   clock.Clock
+}
+
+var _ TwoFactorChallenger = (*TwoFactorService)(nil)
+
+// NewTwoFactorService создает новый экземпляр TwoFactorService
+func NewTwoFactorService(queries queries.TwoFactorQueriesInterface, tx db.Transactor, config config.TwoFactorConfig) *TwoFactorService {
+	return &TwoFactorService{
+		queries: queries,
+		tx:      tx,
+		config:  config,
+		clock:   clock.System{},
+	}
+}
+
+// Status возвращает, включена ли у пользователя двухфакторная аутентификация и сколько осталось резервных кодов
+func (s *TwoFactorService) Status(ctx context.Context, userID string) (*models.TwoFactorStatus, error) {
+	totp, err := s.enabledTOTP(ctx, userID)
+	if errors.Is(err, ErrTwoFactorNotEnabled) {
+		return &models.TwoFactorStatus{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	left, err := s.queries.CountBackupCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TwoFactorStatus{Enabled: true, EnabledAt: totp.EnabledAt, BackupCodesLeft: left}, nil
+}
+
+// Setup создает новый секрет TOTP для подключения приложения-аутентификатора.
+// Вход не меняется, пока секрет не подтвержден кодом в Enable. account - имя учетной записи в приложении
+func (s *TwoFactorService) Setup(ctx context.Context, userID, account string) (*models.TwoFactorSetupResponse, error) {
+	secret, err := otp.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.queries.SaveTOTPSecret(ctx, userID, secret)
+	if errors.Is(err, queries.ErrNotFound) {
+		return nil, ErrTwoFactorEnabled
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TwoFactorSetupResponse{
+		Secret: secret,
+		URI:    otp.TOTPURI(s.config.Issuer, account, secret),
+	}, nil
+}
+
+// Enable включает двухфакторную аутентификацию, если code совпадает с кодом нового секрета,
+// и выдает резервные коды
+func (s *TwoFactorService) Enable(ctx context.Context, userID, code string) (*models.BackupCodesResponse, error) {
+	totp, err := s.queries.GetTOTP(ctx, userID)
+	if errors.Is(err, queries.ErrNotFound) {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	if err != nil {
+		return nil, err
+	}
+	if totp.EnabledAt != nil {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	step, ok := otp.CheckTOTP(totp.Secret, code, s.clock.Now())
+	if !ok {
+		return nil, ErrTwoFactorCodeInvalid
+	}
+
+	codes, hashes, err := s.generateBackupCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := s.queries.EnableTOTP(ctx, userID, step); err != nil {
+			return err
+		}
+		return s.queries.ReplaceBackupCodes(ctx, userID, hashes)
+	})
+	if errors.Is(err, queries.ErrNotFound) {
+		return nil, ErrTwoFactorEnabled
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BackupCodesResponse{BackupCodes: codes}, nil
+}
+
+// Disable отключает двухфакторную аутентификацию после проверки кода из приложения или резервного кода
+func (s *TwoFactorService) Disable(ctx context.Context, userID, code string) error {
+	totp, err := s.enabledTOTP(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkCode(ctx, totp, code); err != nil {
+		return err
+	}
+
+	return s.tx.InTx(ctx, func(ctx context.Context) error {
+		return s.queries.DeleteTOTP(ctx, userID)
+	})
+}
+
+// RegenerateBackupCodes выдает новые резервные коды вместо прежних после проверки кода из приложения
+func (s *TwoFactorService) RegenerateBackupCodes(ctx context.Context, userID, code string) (*models.BackupCodesResponse, error) {
+	totp, err := s.enabledTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Резервным кодом новые коды не получить: иначе один утекший код дает постоянный доступ
+	if !totpCodePattern.MatchString(code) {
+		return nil, ErrTwoFactorCodeInvalid
+	}
+	if err := s.checkCode(ctx, totp, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := s.generateBackupCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.queries.ReplaceBackupCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return &models.BackupCodesResponse{BackupCodes: codes}, nil
+}
+
+// Challenge начинает второй этап входа, если у пользователя включена двухфакторная аутентификация.
+// Для остальных пользователей возвращает nil: токен выдается сразу
+func (s *TwoFactorService) Challenge(ctx context.Context, userID string, rememberMe bool) (*models.TwoFactorChallengeResponse, error) {
+	_, err := s.enabledTOTP(ctx, userID)
+	if errors.Is(err, ErrTwoFactorNotEnabled) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := otp.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := s.clock.Now().Add(s.config.ChallengeTTL)
+	if err := s.queries.CreateChallenge(ctx, userID, otp.HashToken(token), rememberMe, expiresAt); err != nil {
+		return nil, err
+	}
+
+	return &models.TwoFactorChallengeResponse{TwoFactorRequired: true, ChallengeToken: token, ExpiresAt: expiresAt}, nil
+}
+
+// Verify завершает вход кодом из приложения или резервным кодом и возвращает незавершенный вход,
+// по которому выдается токен. Каждая проверка кода списывает попытку, после MaxAttempts вход нужно начинать заново
+func (s *TwoFactorService) Verify(ctx context.Context, challengeToken, code string) (*models.LoginChallenge, error) {
+	challenge, err := s.queries.GetActiveChallenge(ctx, otp.HashToken(challengeToken))
+	if errors.Is(err, queries.ErrNotFound) {
+		return nil, ErrChallengeInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Попытка списывается до проверки кода условным обновлением счетчика: параллельные запросы
+	// не получат больше MaxAttempts проверок, сколько бы их ни пришло одновременно
+	err = s.queries.IncrementChallengeAttempts(ctx, challenge.ID, s.config.MaxAttempts)
+	if errors.Is(err, queries.ErrNotFound) {
+		return nil, ErrChallengeAttemptsExceeded
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Двухфакторную аутентификацию могли отключить после ввода пароля
+	totp, err := s.enabledTOTP(ctx, challenge.UserID)
+	if errors.Is(err, ErrTwoFactorNotEnabled) {
+		return nil, ErrChallengeInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCode(ctx, totp, code); err != nil {
+		return nil, err
+	}
+
+	// Вход одноразовый: повторный запрос с тем же токеном получит ErrChallengeInvalid
+	err = s.queries.ConsumeChallenge(ctx, challenge.ID)
+	if errors.Is(err, queries.ErrNotFound) {
+		return nil, ErrChallengeInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// enabledTOTP получает подтвержденный секрет пользователя или ErrTwoFactorNotEnabled
+func (s *TwoFactorService) enabledTOTP(ctx context.Context, userID string) (*models.UserTOTP, error) {
+	totp, err := s.queries.GetTOTP(ctx, userID)
+	if errors.Is(err, queries.ErrNotFound) {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	if err != nil {
+		return nil, err
+	}
+	if totp.EnabledAt == nil {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	return totp, nil
+}
+
+// checkCode проверяет код из приложения или резервный код. Код из приложения принимается
+// один раз, резервный код после проверки больше не действует
+func (s *TwoFactorService) checkCode(ctx context.Context, totp *models.UserTOTP, code string) error {
+	if totpCodePattern.MatchString(code) {
+		step, ok := otp.CheckTOTP(totp.Secret, code, s.clock.Now())
+		if !ok {
+			return ErrTwoFactorCodeInvalid
+		}
+		err := s.queries.UseTOTPStep(ctx, totp.UserID, step)
+		if errors.Is(err, queries.ErrNotFound) {
+			return ErrTwoFactorCodeInvalid
+		}
+		return err
+	}
+
+	err := s.queries.UseBackupCode(ctx, totp.UserID, otp.HashToken(otp.NormalizeBackupCode(code)))
+	if errors.Is(err, queries.ErrNotFound) {
+		return ErrTwoFactorCodeInvalid
+	}
+	return err
+}
+
+// generateBackupCodes генерирует резервные коды и их хеши для хранения
+func (s *TwoFactorService) generateBackupCodes() ([]string, []string, error) {
+	codes := make([]string, 0, s.config.BackupCodes)
+	hashes := make([]string, 0, s.config.BackupCodes)
+	for i := 0; i < s.config.BackupCodes; i++ {
+		code, err := otp.GenerateBackupCode()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate backup codes: %w", err)
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, otp.HashToken(otp.NormalizeBackupCode(code)))
+	}
+	return codes, hashes, nil
+}
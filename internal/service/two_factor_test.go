@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/config"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+	"pvz-service/internal/otp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryTwoFactor хранит секреты, резервные коды и незавершенные входы в памяти
+type memoryTwoFactor struct {
+	totp        map[string]*models.UserTOTP
+	backupCodes map[string]map[string]bool
+	challenges  map[string]*models.LoginChallenge
+
+	// mu защищает счетчик попыток при параллельных проверках
+	mu sync.Mutex
+}
+
+func newMemoryTwoFactor() *memoryTwoFactor {
+	return &memoryTwoFactor{
+		totp:        map[string]*models.UserTOTP{},
+		backupCodes: map[string]map[string]bool{},
+		challenges:  map[string]*models.LoginChallenge{},
+	}
+}
+
+func (m *memoryTwoFactor) GetTOTP(ctx context.Context, userID string) (*models.UserTOTP, error) {
+	totp, ok := m.totp[userID]
+	if !ok {
+		return nil, queries.ErrNotFound
+	}
+	copied := *totp
+	return &copied, nil
+}
+
+func (m *memoryTwoFactor) SaveTOTPSecret(ctx context.Context, userID, secret string) error {
+	if totp, ok := m.totp[userID]; ok && totp.EnabledAt != nil {
+		return queries.ErrNotFound
+	}
+	m.totp[userID] = &models.UserTOTP{UserID: userID, Secret: secret}
+	return nil
+}
+
+func (m *memoryTwoFactor) EnableTOTP(ctx context.Context, userID string, step int64) error {
+	totp, ok := m.totp[userID]
+	if !ok || totp.EnabledAt != nil {
+		return queries.ErrNotFound
+	}
+	now := time.Now()
+	totp.EnabledAt = &now
+	totp.LastUsedStep = step
+	return nil
+}
+
+func (m *memoryTwoFactor) UseTOTPStep(ctx context.Context, userID string, step int64) error {
+	totp, ok := m.totp[userID]
+	if !ok || totp.LastUsedStep >= step {
+		return queries.ErrNotFound
+	}
+	totp.LastUsedStep = step
+	return nil
+}
+
+func (m *memoryTwoFactor) DeleteTOTP(ctx context.Context, userID string) error {
+	delete(m.totp, userID)
+	delete(m.backupCodes, userID)
+	return nil
+}
+
+func (m *memoryTwoFactor) ReplaceBackupCodes(ctx context.Context, userID string, codeHashes []string) error {
+	m.backupCodes[userID] = map[string]bool{}
+	for _, codeHash := range codeHashes {
+		m.backupCodes[userID][codeHash] = false
+	}
+	return nil
+}
+
+func (m *memoryTwoFactor) UseBackupCode(ctx context.Context, userID, codeHash string) error {
+	used, ok := m.backupCodes[userID][codeHash]
+	if !ok || used {
+		return queries.ErrNotFound
+	}
+	m.backupCodes[userID][codeHash] = true
+	return nil
+}
+
+func (m *memoryTwoFactor) CountBackupCodes(ctx context.Context, userID string) (int, error) {
+	count := 0
+	for _, used := range m.backupCodes[userID] {
+		if !used {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *memoryTwoFactor) CreateChallenge(ctx context.Context, userID, tokenHash string, rememberMe bool, expiresAt time.Time) error {
+	m.challenges[tokenHash] = &models.LoginChallenge{ID: tokenHash, UserID: userID, RememberMe: rememberMe, ExpiresAt: expiresAt}
+	return nil
+}
+
+func (m *memoryTwoFactor) GetActiveChallenge(ctx context.Context, tokenHash string) (*models.LoginChallenge, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	challenge, ok := m.challenges[tokenHash]
+	if !ok {
+		return nil, queries.ErrNotFound
+	}
+	copied := *challenge
+	return &copied, nil
+}
+
+func (m *memoryTwoFactor) IncrementChallengeAttempts(ctx context.Context, challengeID string, maxAttempts int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	challenge := m.challenges[challengeID]
+	if challenge.Attempts >= maxAttempts {
+		return queries.ErrNotFound
+	}
+	challenge.Attempts++
+	return nil
+}
+
+func (m *memoryTwoFactor) ConsumeChallenge(ctx context.Context, challengeID string) error {
+	if _, ok := m.challenges[challengeID]; !ok {
+		return queries.ErrNotFound
+	}
+	delete(m.challenges, challengeID)
+	return nil
+}
+
+// noTx выполняет функцию без транзакции
+type noTx struct{}
+
+func (noTx) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// setupTwoFactorTest создает сервис с зафиксированным временем и подключенной пользователю user-1
+// двухфакторной аутентификацией. Возвращает секрет и резервные коды
+func setupTwoFactorTest(t *testing.T, now time.Time) (*TwoFactorService, *memoryTwoFactor, string, []string) {
+	store := newMemoryTwoFactor()
+	s := NewTwoFactorService(store, noTx{}, config.TwoFactorConfig{Issuer: "PVZ", ChallengeTTL: 5 * time.Minute, MaxAttempts: 3, BackupCodes: 4})
+
+	// Подключение идет на два шага раньше входа, чтобы код входа не совпал с кодом подключения
+	s.clock = clock.Fixed(now.Add(-otp.TOTPPeriod * 2))
+	setup, err := s.Setup(context.Background(), "user-1", "moderator@example.com")
+	require.NoError(t, err)
+	assert.Contains(t, setup.URI, "otpauth://totp/PVZ:moderator@example.com?")
+
+	code, err := otp.TOTPCode(setup.Secret, otp.TOTPStep(s.clock.Now()))
+	require.NoError(t, err)
+	backup, err := s.Enable(context.Background(), "user-1", code)
+	require.NoError(t, err)
+	assert.Len(t, backup.BackupCodes, 4)
+
+	s.clock = clock.Fixed(now)
+	return s, store, setup.Secret, backup.BackupCodes
+}
+
+func TestTwoFactorServiceEnable(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	s, _, _, _ := setupTwoFactorTest(t, now)
+
+	status, err := s.Status(context.Background(), "user-1")
+	require.NoError(t, err)
+	assert.True(t, status.Enabled)
+	assert.Equal(t, 4, status.BackupCodesLeft)
+
+	// Включенный секрет не заменяется новым
+	_, err = s.Setup(context.Background(), "user-1", "moderator@example.com")
+	assert.ErrorIs(t, err, ErrTwoFactorEnabled)
+
+	// Без подключения включить нечего, неверный код не включает
+	_, err = s.Enable(context.Background(), "user-2", "123456")
+	assert.ErrorIs(t, err, ErrTwoFactorNotEnabled)
+	_, err = s.Setup(context.Background(), "user-2", "employee@example.com")
+	require.NoError(t, err)
+	_, err = s.Enable(context.Background(), "user-2", "000000")
+	assert.ErrorIs(t, err, ErrTwoFactorCodeInvalid)
+
+	status, err = s.Status(context.Background(), "user-2")
+	require.NoError(t, err)
+	assert.False(t, status.Enabled)
+}
+
+func TestTwoFactorServiceLogin(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	s, _, secret, _ := setupTwoFactorTest(t, now)
+
+	// Без двухфакторной аутентификации токен выдается сразу
+	challenge, err := s.Challenge(context.Background(), "user-2", false)
+	require.NoError(t, err)
+	assert.Nil(t, challenge)
+
+	challenge, err = s.Challenge(context.Background(), "user-1", true)
+	require.NoError(t, err)
+	require.NotNil(t, challenge)
+	assert.True(t, challenge.TwoFactorRequired)
+	assert.Equal(t, now.Add(5*time.Minute), challenge.ExpiresAt)
+
+	code, err := otp.TOTPCode(secret, otp.TOTPStep(now))
+	require.NoError(t, err)
+
+	login, err := s.Verify(context.Background(), challenge.ChallengeToken, code)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", login.UserID)
+	assert.True(t, login.RememberMe)
+
+	// Вход одноразовый, а код того же шага повторно не принимается
+	_, err = s.Verify(context.Background(), challenge.ChallengeToken, code)
+	assert.ErrorIs(t, err, ErrChallengeInvalid)
+
+	challenge, err = s.Challenge(context.Background(), "user-1", false)
+	require.NoError(t, err)
+	_, err = s.Verify(context.Background(), challenge.ChallengeToken, code)
+	assert.ErrorIs(t, err, ErrTwoFactorCodeInvalid)
+}
+
+func TestTwoFactorServiceBackupCode(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	s, _, _, backupCodes := setupTwoFactorTest(t, now)
+
+	challenge, err := s.Challenge(context.Background(), "user-1", false)
+	require.NoError(t, err)
+
+	// Резервный код вводится без учета регистра и дефиса
+	_, err = s.Verify(context.Background(), challenge.ChallengeToken, " "+strings.ToUpper(otp.NormalizeBackupCode(backupCodes[0])))
+	require.NoError(t, err)
+
+	challenge, err = s.Challenge(context.Background(), "user-1", false)
+	require.NoError(t, err)
+	_, err = s.Verify(context.Background(), challenge.ChallengeToken, backupCodes[0])
+	assert.ErrorIs(t, err, ErrTwoFactorCodeInvalid)
+
+	status, err := s.Status(context.Background(), "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, status.BackupCodesLeft)
+
+	// Новые резервные коды выдаются только по коду из приложения
+	_, err = s.RegenerateBackupCodes(context.Background(), "user-1", backupCodes[1])
+	assert.ErrorIs(t, err, ErrTwoFactorCodeInvalid)
+}
+
+func TestTwoFactorServiceAttemptsExceeded(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	s, _, secret, _ := setupTwoFactorTest(t, now)
+
+	challenge, err := s.Challenge(context.Background(), "user-1", false)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = s.Verify(context.Background(), challenge.ChallengeToken, "wrong-code")
+		assert.ErrorIs(t, err, ErrTwoFactorCodeInvalid)
+	}
+
+	code, err := otp.TOTPCode(secret, otp.TOTPStep(now))
+	require.NoError(t, err)
+	_, err = s.Verify(context.Background(), challenge.ChallengeToken, code)
+	assert.ErrorIs(t, err, ErrChallengeAttemptsExceeded)
+}
+
+// TestTwoFactorServiceParallelAttempts проверяет, что одновременные запросы не получают больше
+// MaxAttempts проверок кода
+func TestTwoFactorServiceParallelAttempts(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	s, _, _, _ := setupTwoFactorTest(t, now)
+
+	challenge, err := s.Challenge(context.Background(), "user-1", false)
+	require.NoError(t, err)
+
+	const requests = 10
+	errs := make(chan error, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.Verify(context.Background(), challenge.ChallengeToken, "wrong-code")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	checked := 0
+	for err := range errs {
+		if errors.Is(err, ErrTwoFactorCodeInvalid) {
+			checked++
+			continue
+		}
+		assert.ErrorIs(t, err, ErrChallengeAttemptsExceeded)
+	}
+	assert.Equal(t, 3, checked)
+}
+
+func TestTwoFactorServiceDisable(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	s, store, _, backupCodes := setupTwoFactorTest(t, now)
+
+	assert.ErrorIs(t, s.Disable(context.Background(), "user-1", "000000"), ErrTwoFactorCodeInvalid)
+	require.NoError(t, s.Disable(context.Background(), "user-1", backupCodes[0]))
+	assert.Empty(t, store.totp)
+
+	// Отключенную двухфакторную аутентификацию повторно не отключить
+	assert.ErrorIs(t, s.Disable(context.Background(), "user-1", backupCodes[1]), ErrTwoFactorNotEnabled)
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/featureflags"
+	"pvz-service/internal/models"
+)
+
+// ErrOutsideWorkingHours возвращается при приёмке товаров в ПВЗ вне часов его работы
+var ErrOutsideWorkingHours = errors.New("pvz is closed at this time")
+
+// WorkingHoursChecker проверяет, что ПВЗ сейчас работает
+type WorkingHoursChecker interface {
+	CheckOpen(ctx context.Context, pvzID string) error
+}
+
+// WorkingHours проверяет часы работы ПВЗ по его расписанию и часовому поясу.
+// Модератор снимает ограничение для ПВЗ флагом working_hours_override
+type WorkingHours struct {
+	hoursQueries queries.WorkingHoursQueriesInterface
+	pvzQueries   queries.PVZQueriesInterface
+	flags        featureflags.Checker
+	clock        clock.Clock
+}
+
+// NewWorkingHours создает новый экземпляр WorkingHours
+func NewWorkingHours(hoursQueries queries.WorkingHoursQueriesInterface, pvzQueries queries.PVZQueriesInterface, flags featureflags.Checker) *WorkingHours {
+	return &WorkingHours{
+		hoursQueries: hoursQueries,
+		pvzQueries:   pvzQueries,
+		flags:        flags,
+		clock:        clock.System{},
+	}
+}
+
+// CheckOpen возвращает ErrOutsideWorkingHours, если ПВЗ сейчас закрыт.
+// ПВЗ без расписания работает круглосуточно
+func (w *WorkingHours) CheckOpen(ctx context.Context, pvzID string) error {
+	if w.flags.Enabled(ctx, featureflags.WorkingHoursOverride, pvzID) {
+		return nil
+	}
+
+	days, err := w.hoursQueries.GetWorkingHours(ctx, pvzID)
+	if err != nil {
+		return err
+	}
+	if len(days) == 0 {
+		return nil
+	}
+
+	pvz, err := w.pvzQueries.GetPVZ(ctx, pvzID)
+	if err != nil {
+		return err
+	}
+	loc := time.UTC
+	if pvz.Timezone != nil {
+		if loc, err = clock.Location(*pvz.Timezone); err != nil {
+			return fmt.Errorf("pvz %s: %w", pvzID, err)
+		}
+	}
+
+	if !isOpenAt(days, w.clock.Now().In(loc)) {
+		return ErrOutsideWorkingHours
+	}
+	return nil
+}
+
+// isOpenAt сообщает, попадает ли местное время в часы работы. День без расписания - выходной
+func isOpenAt(days []models.WorkingDay, local time.Time) bool {
+	weekday := int(local.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+
+	// Время в формате ЧЧ:ММ сравнивается как строка
+	current := local.Format(models.WorkingHoursLayout)
+	for _, day := range days {
+		if day.Weekday == weekday {
+			return current >= day.OpensAt && current < day.ClosesAt
+		}
+	}
+	return false
+}
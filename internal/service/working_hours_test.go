@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/db/queries"
+	"pvz-service/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fixedHours возвращает заданное расписание ПВЗ
+type fixedHours struct {
+	queries.WorkingHoursQueriesInterface
+	days []models.WorkingDay
+}
+
+func (h fixedHours) GetWorkingHours(ctx context.Context, pvzID string) ([]models.WorkingDay, error) {
+	return h.days, nil
+}
+
+// fixedPVZ возвращает ПВЗ с заданным часовым поясом
+type fixedPVZ struct {
+	queries.PVZQueriesInterface
+	timezone *string
+}
+
+func (p fixedPVZ) GetPVZ(ctx context.Context, pvzID string) (*models.PVZ, error) {
+	return &models.PVZ{ID: pvzID, Timezone: p.timezone}, nil
+}
+
+// staticFlags включает перечисленные флаги для всех ПВЗ
+type staticFlags map[string]bool
+
+func (f staticFlags) Enabled(ctx context.Context, name, pvzID string) bool {
+	return f[name]
+}
+
+func TestIsOpenAt(t *testing.T) {
+	days := []models.WorkingDay{
+		{Weekday: 1, OpensAt: "09:00", ClosesAt: "21:00"},
+		{Weekday: 7, OpensAt: "10:00", ClosesAt: "16:00"},
+	}
+
+	tests := []struct {
+		name  string
+		local time.Time
+		want  bool
+	}{
+		{"Понедельник днем", time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC), true},
+		{"Понедельник в момент открытия", time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC), true},
+		{"Понедельник в момент закрытия", time.Date(2025, 3, 3, 21, 0, 0, 0, time.UTC), false},
+		{"Понедельник ночью", time.Date(2025, 3, 3, 2, 30, 0, 0, time.UTC), false},
+		{"Вторник - выходной", time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC), false},
+		{"Воскресенье", time.Date(2025, 3, 9, 15, 59, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isOpenAt(days, tt.local))
+		})
+	}
+}
+
+func TestWorkingHoursCheckOpen(t *testing.T) {
+	moscow := "Europe/Moscow"
+	days := []models.WorkingDay{{Weekday: 1, OpensAt: "09:00", ClosesAt: "21:00"}}
+	// Понедельник, 20:00 UTC - 23:00 по Москве
+	now := clock.Fixed(time.Date(2025, 3, 3, 20, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name     string
+		days     []models.WorkingDay
+		timezone *string
+		flags    staticFlags
+		want     error
+	}{
+		{"Расписание не задано", nil, &moscow, nil, nil},
+		{"Открыт в UTC", days, nil, nil, nil},
+		{"Закрыт по местному времени", days, &moscow, nil, ErrOutsideWorkingHours},
+		{"Ограничение снято модератором", days, &moscow, staticFlags{"working_hours_override": true}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewWorkingHours(fixedHours{days: tt.days}, fixedPVZ{timezone: tt.timezone}, tt.flags)
+			checker.clock = now
+
+			assert.ErrorIs(t, checker.CheckOpen(context.Background(), "pvz-id"), tt.want)
+		})
+	}
+}
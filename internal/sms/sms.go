@@ -0,0 +1,31 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// SenderInterface определяет интерфейс провайдера SMS
+type SenderInterface interface {
+	Send(ctx context.Context, phone, message string) error
+}
+
+// LogSender пишет сообщения в лог вместо отправки, используется для разработки
+type LogSender struct{}
+
+// Send записывает сообщение в лог
+func (s *LogSender) Send(ctx context.Context, phone, message string) error {
+	log.Printf("SMS to %s: %s", phone, message)
+	return nil
+}
+
+// NewSender создает провайдера SMS по имени из конфигурации
+func NewSender(provider string) (SenderInterface, error) {
+	switch provider {
+	case "", "log":
+		return &LogSender{}, nil
+	default:
+		return nil, fmt.Errorf("unknown sms provider: %s", provider)
+	}
+}
@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/config"
+)
+
+// Ошибки скачивания по ссылке локального хранилища
+var (
+	// ErrInvalidLink возвращается, если подпись ссылки не совпадает или срок ее действия истек
+	ErrInvalidLink = errors.New("invalid or expired link")
+	// ErrObjectNotFound возвращается, если объекта с ключом нет в хранилище
+	ErrObjectNotFound = errors.New("object not found")
+	// ErrInvalidKey возвращается для ключа, который указывает за пределы каталога хранилища
+	ErrInvalidKey = errors.New("invalid object key")
+)
+
+// LocalStorage хранит объекты в каталоге файловой системы, для установок без S3.
+// Ссылки на скачивание ведут на сервис и, как подписанные ссылки S3, действуют ограниченное время
+type LocalStorage struct {
+	dir       string
+	secret    []byte
+	publicURL string
+	urlTTL    time.Duration
+	clock     clock.Clock
+}
+
+// NewLocalStorage создает новый экземпляр LocalStorage и каталог для объектов
+func NewLocalStorage(cfg *config.StorageConfig) (*LocalStorage, error) {
+	if cfg.LocalSecret == "" {
+		return nil, errors.New("local storage requires STORAGE_LOCAL_SECRET to sign links")
+	}
+	if err := os.MkdirAll(cfg.LocalDir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create storage dir %s: %w", cfg.LocalDir, err)
+	}
+
+	return &LocalStorage{
+		dir:       cfg.LocalDir,
+		secret:    []byte(cfg.LocalSecret),
+		publicURL: strings.TrimRight(cfg.PublicURL, "/"),
+		urlTTL:    cfg.URLTTL,
+		clock:     clock.System{},
+	}, nil
+}
+
+// Ping проверяет, что каталог хранилища существует
+func (s *LocalStorage) Ping(ctx context.Context) error {
+	info, err := os.Stat(s.dir)
+	if err != nil {
+		return fmt.Errorf("failed to stat storage dir %s: %w", s.dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("storage path %s is not a directory", s.dir)
+	}
+	return nil
+}
+
+// Put сохраняет объект в файл. Файл пишется во временный и переименовывается,
+// поэтому скачивание не увидит наполовину записанный объект
+func (s *LocalStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create dir for object %s: %w", key, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create object %s: %w", key, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write object %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write object %s: %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to put object %s: %w", key, err)
+	}
+	return nil
+}
+
+// URL возвращает ссылку на скачивание объекта через сервис, действующую urlTTL
+func (s *LocalStorage) URL(ctx context.Context, key string) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+
+	expires := strconv.FormatInt(s.clock.Now().Add(s.urlTTL).Unix(), 10)
+	query := url.Values{"expires": {expires}, "signature": {s.sign(key, expires)}}
+	return s.publicURL + "/files/" + (&url.URL{Path: key}).EscapedPath() + "?" + query.Encode(), nil
+}
+
+// Open проверяет подпись и срок действия ссылки и открывает объект для скачивания
+func (s *LocalStorage) Open(ctx context.Context, key, expires, signature string) (*os.File, error) {
+	deadline, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || s.clock.Now().Unix() > deadline {
+		return nil, ErrInvalidLink
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(key, expires))) {
+		return nil, ErrInvalidLink
+	}
+
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open object %s: %w", key, err)
+	}
+	return file, nil
+}
+
+// sign подписывает ключ объекта и срок действия ссылки
+func (s *LocalStorage) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path возвращает путь к файлу объекта, не выходящий за пределы каталога хранилища
+func (s *LocalStorage) path(key string) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(key)) {
+		return "", fmt.Errorf("%w: %s", ErrInvalidKey, key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"pvz-service/internal/clock"
+	"pvz-service/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupLocalStorageTest(t *testing.T) *LocalStorage {
+	store, err := New(&config.StorageConfig{
+		Backend:     "local",
+		LocalDir:    t.TempDir(),
+		LocalSecret: "secret",
+		PublicURL:   "https://pvz.example.com/",
+		URLTTL:      15 * time.Minute,
+	})
+	require.NoError(t, err)
+
+	local := store.(*LocalStorage)
+	local.clock = clock.Fixed(time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC))
+	return local
+}
+
+// parseLink разбирает ссылку локального хранилища на ключ, срок действия и подпись
+func parseLink(t *testing.T, link string) (string, string, string) {
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	return strings.TrimPrefix(u.Path, "/files/"), u.Query().Get("expires"), u.Query().Get("signature")
+}
+
+// TestLocalStorage_PutAndOpen проверяет скачивание сохраненного объекта по выданной ссылке
+func TestLocalStorage_PutAndOpen(t *testing.T) {
+	store := setupLocalStorageTest(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "products/photo.jpg", strings.NewReader("jpeg"), 4, "image/jpeg"))
+
+	link, err := store.URL(ctx, "products/photo.jpg")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(link, "https://pvz.example.com/files/products/photo.jpg?"))
+
+	key, expires, signature := parseLink(t, link)
+	file, err := store.Open(ctx, key, expires, signature)
+	require.NoError(t, err)
+	defer file.Close()
+
+	body, err := io.ReadAll(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "jpeg", string(body))
+}
+
+// TestLocalStorage_OpenRejectsInvalidLinks проверяет отказ по чужой подписи, истекшей ссылке и ключу вне каталога
+func TestLocalStorage_OpenRejectsInvalidLinks(t *testing.T) {
+	store := setupLocalStorageTest(t)
+	ctx := context.Background()
+	require.NoError(t, store.Put(ctx, "products/photo.jpg", strings.NewReader("jpeg"), 4, "image/jpeg"))
+
+	link, err := store.URL(ctx, "products/photo.jpg")
+	require.NoError(t, err)
+	key, expires, signature := parseLink(t, link)
+
+	_, err = store.Open(ctx, "products/other.jpg", expires, signature)
+	assert.ErrorIs(t, err, ErrInvalidLink)
+
+	store.clock = clock.Fixed(time.Date(2026, 3, 2, 10, 16, 0, 0, time.UTC))
+	_, err = store.Open(ctx, key, expires, signature)
+	assert.ErrorIs(t, err, ErrInvalidLink)
+
+	assert.ErrorIs(t, store.Put(ctx, "../photo.jpg", strings.NewReader("jpeg"), 4, "image/jpeg"), ErrInvalidKey)
+	_, err = store.URL(ctx, "/etc/passwd")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+// TestLocalStorage_OpenMissingObject проверяет ссылку на удаленный объект
+func TestLocalStorage_OpenMissingObject(t *testing.T) {
+	store := setupLocalStorageTest(t)
+	ctx := context.Background()
+
+	link, err := store.URL(ctx, "products/missing.jpg")
+	require.NoError(t, err)
+	key, expires, signature := parseLink(t, link)
+
+	_, err = store.Open(ctx, key, expires, signature)
+	assert.ErrorIs(t, err, ErrObjectNotFound)
+}
+
+// TestNewLocalStorageRequiresSecret проверяет, что без ключа подписи локальное хранилище не создается
+func TestNewLocalStorageRequiresSecret(t *testing.T) {
+	_, err := New(&config.StorageConfig{Backend: "local", LocalDir: t.TempDir()})
+	assert.Error(t, err)
+}
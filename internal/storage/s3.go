@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"pvz-service/internal/config"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Storage хранит объекты в бакете S3-совместимого хранилища
+// и выдает на них подписанные ссылки
+type S3Storage struct {
+	client *minio.Client
+	bucket string
+	urlTTL time.Duration
+}
+
+// NewS3Storage создает новый экземпляр S3Storage
+func NewS3Storage(cfg *config.StorageConfig) (*S3Storage, error) {
+	client, err := minio.New(cfg.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.S3AccessKey, cfg.S3SecretKey, ""),
+		Secure: cfg.S3UseSSL,
+		Region: cfg.S3Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 client: %w", err)
+	}
+
+	return &S3Storage{
+		client: client,
+		bucket: cfg.S3Bucket,
+		urlTTL: cfg.URLTTL,
+	}, nil
+}
+
+// Ping проверяет, что хранилище доступно и бакет существует
+func (s *S3Storage) Ping(ctx context.Context) error {
+	exists, err := s.client.BucketExists(ctx, s.bucket)
+	if err != nil {
+		return fmt.Errorf("failed to check bucket %s: %w", s.bucket, err)
+	}
+	if !exists {
+		return fmt.Errorf("bucket %s does not exist", s.bucket)
+	}
+	return nil
+}
+
+// Put загружает объект в бакет
+func (s *S3Storage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, body, size, minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		return fmt.Errorf("failed to put object %s: %w", key, err)
+	}
+	return nil
+}
+
+// URL возвращает подписанную ссылку на объект, действующую urlTTL
+func (s *S3Storage) URL(ctx context.Context, key string) (string, error) {
+	url, err := s.client.PresignedGetObject(ctx, s.bucket, key, s.urlTTL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign object %s: %w", key, err)
+	}
+	return url.String(), nil
+}
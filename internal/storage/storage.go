@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"pvz-service/internal/config"
+)
+
+// ErrNotConfigured возвращается, если хранилище вложений не настроено
+var ErrNotConfigured = errors.New("storage is not configured")
+
+// Storage хранит вложения (фотографии товаров и т.п.) и выдает ссылки на их скачивание
+type Storage interface {
+	// Put сохраняет объект под ключом key
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// URL возвращает ссылку на скачивание объекта с ограниченным сроком действия
+	URL(ctx context.Context, key string) (string, error)
+}
+
+// Pinger - хранилище, доступность которого можно проверить
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Downloader - хранилище, ссылки которого ведут на сам сервис. Обработчик скачивания
+// открывает объект по ключу, сроку действия и подписи из ссылки
+type Downloader interface {
+	Open(ctx context.Context, key, expires, signature string) (*os.File, error)
+}
+
+// New создает хранилище по настройкам: s3 - S3-совместимое хранилище, local - каталог
+// файловой системы, пустое значение - хранилище не используется
+func New(cfg *config.StorageConfig) (Storage, error) {
+	switch cfg.Backend {
+	case "":
+		return Disabled{}, nil
+	case "s3":
+		return NewS3Storage(cfg)
+	case "local":
+		return NewLocalStorage(cfg)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
+
+// Disabled отклоняет все операции, пока хранилище не настроено
+type Disabled struct{}
+
+// Put всегда возвращает ErrNotConfigured
+func (Disabled) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	return ErrNotConfigured
+}
+
+// URL всегда возвращает ErrNotConfigured
+func (Disabled) URL(ctx context.Context, key string) (string, error) {
+	return "", ErrNotConfigured
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"pvz-service/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNew проверяет выбор хранилища по настройкам
+func TestNew(t *testing.T) {
+	disabled, err := New(&config.StorageConfig{})
+	assert.NoError(t, err)
+	assert.ErrorIs(t, disabled.Put(context.Background(), "key", strings.NewReader(""), 0, "image/png"), ErrNotConfigured)
+
+	_, err = New(&config.StorageConfig{Backend: "ftp"})
+	assert.Error(t, err)
+}
+
+// TestS3StorageURL проверяет, что ссылка подписывается локально и ведет на объект в бакете
+func TestS3StorageURL(t *testing.T) {
+	s3, err := New(&config.StorageConfig{
+		Backend:     "s3",
+		S3Endpoint:  "storage.example.com",
+		S3AccessKey: "access",
+		S3SecretKey: "secret",
+		S3Bucket:    "photos",
+		S3Region:    "us-east-1",
+		S3UseSSL:    true,
+		URLTTL:      15 * time.Minute,
+	})
+	assert.NoError(t, err)
+
+	url, err := s3.URL(context.Background(), "products/photo.jpg")
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(url, "https://storage.example.com/photos/products/photo.jpg?"))
+	assert.Contains(t, url, "X-Amz-Expires=900")
+}
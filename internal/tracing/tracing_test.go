@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"pvz-service/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestEnd проверяет запись ошибки в спан
+func TestEnd(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	_, span := Start(context.Background(), "ok")
+	End(span, nil)
+	_, span = Start(context.Background(), "failed")
+	End(span, errors.New("database error"))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "database error", spans[1].Status().Description)
+}
+
+// TestSetupDisabled проверяет, что выключенная трассировка не меняет провайдер
+func TestSetupDisabled(t *testing.T) {
+	previous := otel.GetTracerProvider()
+
+	shutdown, err := Setup(context.Background(), &config.TracingConfig{Enabled: false})
+
+	assert.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+	assert.Equal(t, previous, otel.GetTracerProvider())
+}
+
+// TestOperation проверяет имя последней открытой операции
+func TestOperation(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, Operation(ctx))
+
+	ctx, span := Start(ctx, "PVZQueries.GetPVZList")
+	defer span.End()
+	assert.Equal(t, "PVZQueries.GetPVZList", Operation(ctx))
+}
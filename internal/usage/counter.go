@@ -0,0 +1,78 @@
+package usage
+
+import (
+	"sync"
+	"time"
+
+	"pvz-service/internal/models"
+)
+
+// Counter копит число запросов к API в памяти процесса до записи в базу данных.
+// Запросы группируются по часам: за час в памяти остается по одному счетчику
+// на сочетание ПВЗ, пользователя и маршрута
+type Counter struct {
+	mu     sync.Mutex
+	counts map[models.UsageKey]*models.UsageCount
+	now    func() time.Time
+}
+
+// NewCounter создает новый экземпляр Counter
+func NewCounter() *Counter {
+	return &Counter{
+		counts: make(map[models.UsageKey]*models.UsageCount),
+		now:    func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Record учитывает запрос к маршруту route, failed - ответ с ошибкой
+func (c *Counter) Record(pvzID, userID, method, route string, failed bool) {
+	key := models.UsageKey{
+		Bucket: c.now().Truncate(time.Hour),
+		PvzID:  pvzID,
+		UserID: userID,
+		Method: method,
+		Route:  route,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	count, ok := c.counts[key]
+	if !ok {
+		count = &models.UsageCount{Key: key}
+		c.counts[key] = count
+	}
+	count.Requests++
+	if failed {
+		count.Errors++
+	}
+}
+
+// Drain возвращает накопленные счетчики и обнуляет их
+func (c *Counter) Drain() []models.UsageCount {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counts := make([]models.UsageCount, 0, len(c.counts))
+	for _, count := range c.counts {
+		counts = append(counts, *count)
+	}
+	c.counts = make(map[models.UsageKey]*models.UsageCount)
+	return counts
+}
+
+// Restore возвращает в Counter счетчики, которые не удалось записать, чтобы записать их со следующими
+func (c *Counter) Restore(counts []models.UsageCount) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, restored := range counts {
+		count, ok := c.counts[restored.Key]
+		if !ok {
+			count = &models.UsageCount{Key: restored.Key}
+			c.counts[restored.Key] = count
+		}
+		count.Requests += restored.Requests
+		count.Errors += restored.Errors
+	}
+}
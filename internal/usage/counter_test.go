@@ -0,0 +1,48 @@
+package usage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/models"
+)
+
+// TestCounter проверяет группировку запросов по часам и возврат незаписанных счетчиков
+func TestCounter(t *testing.T) {
+	now := time.Date(2026, 5, 4, 10, 59, 0, 0, time.UTC)
+	counter := NewCounter()
+	counter.now = func() time.Time { return now }
+
+	counter.Record("pvz-1", "user-1", "POST", "/products", false)
+	counter.Record("pvz-1", "user-1", "POST", "/products", true)
+	now = now.Add(2 * time.Minute)
+	counter.Record("pvz-1", "user-1", "POST", "/products", false)
+
+	counts := counter.Drain()
+	require.Len(t, counts, 2)
+	byHour := map[int]models.UsageCount{}
+	for _, count := range counts {
+		byHour[count.Key.Bucket.Hour()] = count
+	}
+	assert.Equal(t, models.UsageCount{
+		Key:      models.UsageKey{Bucket: time.Date(2026, 5, 4, 10, 0, 0, 0, time.UTC), PvzID: "pvz-1", UserID: "user-1", Method: "POST", Route: "/products"},
+		Requests: 2,
+		Errors:   1,
+	}, byHour[10])
+	assert.Equal(t, int64(1), byHour[11].Requests)
+	assert.Empty(t, counter.Drain())
+
+	// Незаписанные счетчики складываются с новыми
+	counter.Record("pvz-1", "user-1", "POST", "/products", false)
+	counter.Restore(counts)
+	counts = counter.Drain()
+	require.Len(t, counts, 2)
+	for _, count := range counts {
+		if count.Key.Bucket.Hour() == 11 {
+			assert.Equal(t, int64(2), count.Requests)
+		}
+	}
+}
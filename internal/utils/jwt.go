@@ -6,36 +6,55 @@ import (
 
 	"pvz-service/internal/config"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
 // JWTManagerInterface определяет интерфейс для JWT операций
 type JWTManagerInterface interface {
 	GenerateDummyToken(role string) (string, error)
-	GenerateToken(userID, role string) (string, error)
+	GenerateToken(userID, role, sessionID string, pvzIDs []string) (string, error)
 	ValidateToken(tokenString string) (*CustomClaims, error)
+	TokenTTL(role string) time.Duration
 }
 
 // JWTManager управляет созданием и проверкой JWT токенов
 type JWTManager struct {
-	secretKey  string
-	expireTime time.Duration
+	secretKey      string
+	expireTime     time.Duration
+	roleExpireTime map[string]time.Duration
+	issuer         string
+	audience       string
+	leeway         time.Duration
 }
 
 // NewJWTManager создает новый экземпляр JWTManager
 func NewJWTManager(config *config.JWTConfig) *JWTManager {
 	return &JWTManager{
-		secretKey:  config.Secret,
-		expireTime: config.ExpireTime,
+		secretKey:      config.Secret,
+		expireTime:     config.ExpireTime,
+		roleExpireTime: config.RoleExpireTime,
+		issuer:         config.Issuer,
+		audience:       config.Audience,
+		leeway:         config.Leeway,
 	}
 }
 
 // CustomClaims представляет данные, которые будут закодированы в JWT
 type CustomClaims struct {
-	jwt.StandardClaims
+	jwt.RegisteredClaims
 	UserID string `json:"user_id"`
 	Role   string `json:"role"`
+	// PVZIDs - ПВЗ, в которых работает сотрудник; пустой список не ограничивает доступ
+	PVZIDs []string `json:"pvz_ids,omitempty"`
+	// Dummy отмечает тестовый токен /dummyLogin
+	Dummy bool `json:"dummy,omitempty"`
+}
+
+// IsDummy сообщает, выдан ли токен через /dummyLogin. Токены без сессии выдавались
+// только /dummyLogin, поэтому тестовыми считаются и прежние токены без claim dummy
+func (c *CustomClaims) IsDummy() bool {
+	return c.Dummy || c.ID == ""
 }
 
 // GenerateDummyToken создает тестовый JWT токен для указанной роли
@@ -43,66 +62,76 @@ func (manager *JWTManager) GenerateDummyToken(role string) (string, error) {
 	// Создаем уникальный ID для пользователя
 	dummyUserID := uuid.New().String()
 
-	// Устанавливаем время истечения токена
-	expirationTime := time.Now().Add(manager.expireTime)
-
-	// Создаем claims
-	claims := &CustomClaims{
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: expirationTime.Unix(),
-			IssuedAt:  time.Now().Unix(),
-			Subject:   dummyUserID,
-		},
-		UserID: dummyUserID,
-		Role:   role,
-	}
-
-	// Создаем токен с claims
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return manager.signToken(dummyUserID, role, "", nil, true)
+}
 
-	// Подписываем токен нашим секретным ключом
-	tokenString, err := token.SignedString([]byte(manager.secretKey))
+// GenerateToken создает JWT-токен для авторизованного пользователя.
+// ID сессии записывается в claim jti и позволяет отозвать токен до истечения срока,
+// pvzIDs - в claim pvz_ids и ограничивает ПВЗ, с которыми работает сотрудник
+func (manager *JWTManager) GenerateToken(userID, role, sessionID string, pvzIDs []string) (string, error) {
+	return manager.signToken(userID, role, sessionID, pvzIDs, false)
+}
 
-	return tokenString, err
+// TokenTTL возвращает срок действия токена роли
+func (manager *JWTManager) TokenTTL(role string) time.Duration {
+	if ttl, ok := manager.roleExpireTime[role]; ok && ttl > 0 {
+		return ttl
+	}
+	return manager.expireTime
 }
 
-// GenerateToken создает JWT-токен для авторизованного пользователя
-func (manager *JWTManager) GenerateToken(userID, role string) (string, error) {
-	// Устанавливаем время истечения токена
-	expirationTime := time.Now().Add(manager.expireTime)
+// signToken формирует claims и подписывает токен секретным ключом
+func (manager *JWTManager) signToken(userID, role, sessionID string, pvzIDs []string, dummy bool) (string, error) {
+	now := time.Now()
 
 	// Создаем claims
 	claims := &CustomClaims{
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: expirationTime.Unix(),
-			IssuedAt:  time.Now().Unix(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sessionID,
+			Issuer:    manager.issuer,
 			Subject:   userID,
+			ExpiresAt: jwt.NewNumericDate(now.Add(manager.TokenTTL(role))),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 		UserID: userID,
 		Role:   role,
+		PVZIDs: pvzIDs,
+		Dummy:  dummy,
+	}
+	if manager.audience != "" {
+		claims.Audience = jwt.ClaimStrings{manager.audience}
 	}
 
-	// Создаем токен с claims
+	// Создаем токен с claims и подписываем его
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
-	// Подписываем токен секретным ключом
-	tokenString, err := token.SignedString([]byte(manager.secretKey))
-
-	return tokenString, err
+	return token.SignedString([]byte(manager.secretKey))
 }
 
 // ValidateToken проверяет JWT токен
 func (manager *JWTManager) ValidateToken(tokenString string) (*CustomClaims, error) {
+	// Разрешаем только HS256, чтобы исключить подмену алгоритма
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(manager.leeway),
+	}
+	if manager.issuer != "" {
+		options = append(options, jwt.WithIssuer(manager.issuer))
+	}
+	if manager.audience != "" {
+		options = append(options, jwt.WithAudience(manager.audience))
+	}
+
 	// Парсим токен
 	token, err := jwt.ParseWithClaims(
 		tokenString,
 		&CustomClaims{},
 		func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
 			return []byte(manager.secretKey), nil
 		},
+		options...,
 	)
 
 	if err != nil {
@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/config"
+)
+
+func newTestJWTManager() *JWTManager {
+	return NewJWTManager(&config.JWTConfig{
+		Secret:     "test-secret",
+		ExpireTime: time.Hour,
+		Issuer:     "pvz-service",
+		Audience:   "pvz-api",
+		Leeway:     30 * time.Second,
+	})
+}
+
+// TestJWTManagerRoundTrip проверяет выпуск и проверку токена
+func TestJWTManagerRoundTrip(t *testing.T) {
+	manager := newTestJWTManager()
+
+	token, err := manager.GenerateToken("user123", "employee", "session-1", []string{"pvz-1", "pvz-2"})
+	assert.NoError(t, err)
+
+	claims, err := manager.ValidateToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "user123", claims.UserID)
+	assert.Equal(t, "session-1", claims.ID)
+	assert.Equal(t, "employee", claims.Role)
+	assert.Equal(t, []string{"pvz-1", "pvz-2"}, claims.PVZIDs)
+	assert.Equal(t, "pvz-service", claims.Issuer)
+	assert.Equal(t, jwt.ClaimStrings{"pvz-api"}, claims.Audience)
+}
+
+// TestJWTManagerDummyToken проверяет, что тестовый токен отличается от токена сессии
+func TestJWTManagerDummyToken(t *testing.T) {
+	manager := newTestJWTManager()
+
+	token, err := manager.GenerateDummyToken("moderator")
+	assert.NoError(t, err)
+	claims, err := manager.ValidateToken(token)
+	assert.NoError(t, err)
+	assert.True(t, claims.Dummy)
+	assert.True(t, claims.IsDummy())
+
+	token, err = manager.GenerateToken("user123", "moderator", "session-1", nil)
+	assert.NoError(t, err)
+	claims, err = manager.ValidateToken(token)
+	assert.NoError(t, err)
+	assert.False(t, claims.IsDummy())
+
+	// Токен без сессии, выданный до появления claim dummy, тоже тестовый
+	assert.True(t, (&CustomClaims{UserID: "user123"}).IsDummy())
+}
+
+// TestJWTManagerRoleExpireTime проверяет срок действия токена по роли
+func TestJWTManagerRoleExpireTime(t *testing.T) {
+	manager := NewJWTManager(&config.JWTConfig{
+		Secret:         "test-secret",
+		ExpireTime:     24 * time.Hour,
+		RoleExpireTime: map[string]time.Duration{"moderator": time.Hour},
+	})
+
+	assert.Equal(t, time.Hour, manager.TokenTTL("moderator"))
+	assert.Equal(t, 24*time.Hour, manager.TokenTTL("employee"))
+
+	token, err := manager.GenerateToken("user123", "moderator", "", nil)
+	assert.NoError(t, err)
+	claims, err := manager.ValidateToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, claims.ExpiresAt.Sub(claims.IssuedAt.Time))
+}
+
+// TestJWTManagerRejectsForeignIssuerAndAudience проверяет отказ при чужих iss/aud
+func TestJWTManagerRejectsForeignIssuerAndAudience(t *testing.T) {
+	manager := newTestJWTManager()
+
+	foreignIssuer := NewJWTManager(&config.JWTConfig{Secret: "test-secret", ExpireTime: time.Hour, Issuer: "other", Audience: "pvz-api"})
+	token, err := foreignIssuer.GenerateToken("user123", "employee", "", nil)
+	assert.NoError(t, err)
+	_, err = manager.ValidateToken(token)
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidIssuer)
+
+	foreignAudience := NewJWTManager(&config.JWTConfig{Secret: "test-secret", ExpireTime: time.Hour, Issuer: "pvz-service", Audience: "other"})
+	token, err = foreignAudience.GenerateToken("user123", "employee", "", nil)
+	assert.NoError(t, err)
+	_, err = manager.ValidateToken(token)
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidAudience)
+}
+
+// TestJWTManagerLeeway проверяет допуск расхождения часов
+func TestJWTManagerLeeway(t *testing.T) {
+	manager := newTestJWTManager()
+
+	// Токен истёк 10 секунд назад - укладывается в допуск
+	manager.expireTime = -10 * time.Second
+	token, err := manager.GenerateToken("user123", "employee", "", nil)
+	assert.NoError(t, err)
+	manager.expireTime = time.Hour
+	_, err = manager.ValidateToken(token)
+	assert.NoError(t, err)
+
+	// Токен истёк минуту назад - отклоняется
+	manager.expireTime = -time.Minute
+	token, err = manager.GenerateToken("user123", "employee", "", nil)
+	assert.NoError(t, err)
+	manager.expireTime = time.Hour
+	_, err = manager.ValidateToken(token)
+	assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+}
+
+// TestJWTManagerRejectsOtherAlgorithms проверяет закрепление алгоритма HS256
+func TestJWTManagerRejectsOtherAlgorithms(t *testing.T) {
+	manager := newTestJWTManager()
+
+	claims := &CustomClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "pvz-service",
+			Audience:  jwt.ClaimStrings{"pvz-api"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		UserID: "user123",
+		Role:   "moderator",
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS512, claims).SignedString([]byte("test-secret"))
+	assert.NoError(t, err)
+	_, err = manager.ValidateToken(token)
+	assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+
+	token, err = jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	assert.NoError(t, err)
+	_, err = manager.ValidateToken(token)
+	assert.Error(t, err)
+}
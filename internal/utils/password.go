@@ -1,28 +1,171 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"pvz-service/internal/config"
+
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// PasswordCheckerInterface определяет интерфейс для проверки паролей
+// Поддерживаемые алгоритмы хеширования паролей
+const (
+	PasswordAlgorithmBcrypt   = "bcrypt"
+	PasswordAlgorithmArgon2id = "argon2id"
+)
+
+const (
+	argon2idPrefix    = "$argon2id$"
+	argon2SaltLength  = 16
+	argon2KeyLength   = 32
+	argon2HashPattern = "$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s"
+)
+
+// ErrPasswordMismatch возвращается, если пароль не соответствует хешу
+var ErrPasswordMismatch = errors.New("password does not match")
+
+// PasswordCheckerInterface определяет интерфейс для хеширования и проверки паролей
 type PasswordCheckerInterface interface {
+	HashPassword(password string) (string, error)
 	CheckPassword(password, hashedPassword string) error
+	// NeedsRehash сообщает, что хеш создан другим алгоритмом или с другими параметрами
+	NeedsRehash(hashedPassword string) bool
 }
 
-// DefaultPasswordChecker реализует стандартную проверку с bcrypt
-// создание пустой структуры и интерфейса нужно, чтобы легче реализовать мокирование
-// в тестах
-type DefaultPasswordChecker struct{}
+// PasswordHasher хеширует пароли выбранным в конфигурации алгоритмом.
+// Проверка принимает хеши любого поддерживаемого алгоритма, поэтому
+// старые пароли продолжают работать после смены настроек
+type PasswordHasher struct {
+	algorithm  string
+	bcryptCost int
+	argon2     argon2Params
+}
 
-func (c *DefaultPasswordChecker) CheckPassword(password, hashedPassword string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+// argon2Params содержит параметры argon2id: память в КиБ, число проходов и потоков
+type argon2Params struct {
+	memory  uint32
+	time    uint32
+	threads uint8
 }
 
-// HashPassword создает хеш пароля с использованием bcrypt
-func HashPassword(password string) (string, error) {
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+// NewPasswordHasher создает PasswordHasher с параметрами из конфигурации
+func NewPasswordHasher(config *config.PasswordConfig) (*PasswordHasher, error) {
+	h := &PasswordHasher{
+		algorithm:  config.Algorithm,
+		bcryptCost: config.BcryptCost,
+		argon2: argon2Params{
+			memory:  config.Argon2Memory,
+			time:    config.Argon2Time,
+			threads: config.Argon2Threads,
+		},
+	}
+
+	switch h.algorithm {
+	case PasswordAlgorithmBcrypt:
+		if h.bcryptCost < bcrypt.MinCost || h.bcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	case PasswordAlgorithmArgon2id:
+		if h.argon2.memory == 0 || h.argon2.time == 0 || h.argon2.threads == 0 {
+			return nil, errors.New("argon2id memory, time and threads must be positive")
+		}
+	default:
+		return nil, fmt.Errorf("unknown password hashing algorithm: %q", h.algorithm)
+	}
+
+	return h, nil
+}
+
+// HashPassword создает хеш пароля настроенным алгоритмом
+func (h *PasswordHasher) HashPassword(password string) (string, error) {
+	if h.algorithm == PasswordAlgorithmBcrypt {
+		hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), h.bcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hashedBytes), nil
+	}
+
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.argon2.time, h.argon2.memory, h.argon2.threads, argon2KeyLength)
+
+	return fmt.Sprintf(argon2HashPattern, argon2.Version, h.argon2.memory, h.argon2.time, h.argon2.threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// CheckPassword проверяет пароль по хешу, алгоритм определяется по формату хеша
+func (h *PasswordHasher) CheckPassword(password, hashedPassword string) error {
+	if !strings.HasPrefix(hashedPassword, argon2idPrefix) {
+		err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrPasswordMismatch
+		}
+		return err
+	}
+
+	params, salt, key, err := decodeArgon2Hash(hashedPassword)
 	if err != nil {
-		return "", err
+		return err
 	}
-	return string(hashedBytes), nil
+
+	actual := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(actual, key) != 1 {
+		return ErrPasswordMismatch
+	}
+	return nil
+}
+
+// NeedsRehash сообщает, что хеш нужно пересчитать под текущие настройки
+func (h *PasswordHasher) NeedsRehash(hashedPassword string) bool {
+	if h.algorithm == PasswordAlgorithmBcrypt {
+		cost, err := bcrypt.Cost([]byte(hashedPassword))
+		return err != nil || cost != h.bcryptCost
+	}
+
+	if !strings.HasPrefix(hashedPassword, argon2idPrefix) {
+		return true
+	}
+	params, _, key, err := decodeArgon2Hash(hashedPassword)
+	return err != nil || params != h.argon2 || len(key) != argon2KeyLength
+}
+
+// decodeArgon2Hash разбирает хеш argon2id в формате PHC
+func decodeArgon2Hash(hashedPassword string) (argon2Params, []byte, []byte, error) {
+	var params argon2Params
+
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, errors.New("invalid argon2id hash format")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, errors.New("unsupported argon2id version")
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id parameters: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id key: %w", err)
+	}
+
+	return params, salt, key, nil
 }
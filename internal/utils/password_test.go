@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+
+	"pvz-service/internal/config"
+)
+
+// newTestPasswordHasher создает хешер с минимальными параметрами, чтобы тесты шли быстро
+func newTestPasswordHasher(t *testing.T, algorithm string) *PasswordHasher {
+	hasher, err := NewPasswordHasher(&config.PasswordConfig{
+		Algorithm:     algorithm,
+		BcryptCost:    bcrypt.MinCost,
+		Argon2Memory:  1024,
+		Argon2Time:    1,
+		Argon2Threads: 1,
+	})
+	if err != nil {
+		t.Fatalf("failed to create password hasher: %v", err)
+	}
+	return hasher
+}
+
+func TestPasswordHasher_HashAndCheck(t *testing.T) {
+	for _, algorithm := range []string{PasswordAlgorithmBcrypt, PasswordAlgorithmArgon2id} {
+		t.Run(algorithm, func(t *testing.T) {
+			hasher := newTestPasswordHasher(t, algorithm)
+
+			hash, err := hasher.HashPassword("secret")
+			assert.NoError(t, err)
+
+			assert.NoError(t, hasher.CheckPassword("secret", hash))
+			assert.ErrorIs(t, hasher.CheckPassword("wrong", hash), ErrPasswordMismatch)
+			assert.False(t, hasher.NeedsRehash(hash))
+		})
+	}
+}
+
+func TestPasswordHasher_ChecksOtherAlgorithm(t *testing.T) {
+	bcryptHasher := newTestPasswordHasher(t, PasswordAlgorithmBcrypt)
+	argon2Hasher := newTestPasswordHasher(t, PasswordAlgorithmArgon2id)
+
+	hash, err := bcryptHasher.HashPassword("secret")
+	assert.NoError(t, err)
+
+	// Старый bcrypt-хеш принимается после перехода на argon2id, но требует пересчета
+	assert.NoError(t, argon2Hasher.CheckPassword("secret", hash))
+	assert.True(t, argon2Hasher.NeedsRehash(hash))
+}
+
+func TestPasswordHasher_NeedsRehashOnParamsChange(t *testing.T) {
+	hasher := newTestPasswordHasher(t, PasswordAlgorithmArgon2id)
+	hash, err := hasher.HashPassword("secret")
+	assert.NoError(t, err)
+
+	stronger, err := NewPasswordHasher(&config.PasswordConfig{
+		Algorithm:     PasswordAlgorithmArgon2id,
+		Argon2Memory:  2048,
+		Argon2Time:    2,
+		Argon2Threads: 1,
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, stronger.CheckPassword("secret", hash))
+	assert.True(t, stronger.NeedsRehash(hash))
+
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost+1)
+	assert.NoError(t, err)
+	assert.True(t, newTestPasswordHasher(t, PasswordAlgorithmBcrypt).NeedsRehash(string(bcryptHash)))
+}
+
+func TestNewPasswordHasher_InvalidConfig(t *testing.T) {
+	_, err := NewPasswordHasher(&config.PasswordConfig{Algorithm: "md5"})
+	assert.Error(t, err)
+
+	_, err = NewPasswordHasher(&config.PasswordConfig{Algorithm: PasswordAlgorithmBcrypt, BcryptCost: 100})
+	assert.Error(t, err)
+
+	_, err = NewPasswordHasher(&config.PasswordConfig{Algorithm: PasswordAlgorithmArgon2id})
+	assert.Error(t, err)
+}
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_product_barcode;
+ALTER TABLE IF EXISTS product DROP COLUMN IF EXISTS barcode;
+
+COMMIT;
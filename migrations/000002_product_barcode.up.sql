@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Штрихкод товара, сканируемый при приёмке (необязательный)
+ALTER TABLE product ADD COLUMN IF NOT EXISTS barcode VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_product_barcode ON product(barcode) WHERE barcode IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS pvz_inactivity_flags;
+ALTER TABLE IF EXISTS pvz DROP COLUMN IF EXISTS deactivated_at;
+ALTER TABLE IF EXISTS pvz DROP COLUMN IF EXISTS is_active;
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Признак активности ПВЗ
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS is_active BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMP;
+
+-- ПВЗ без активности, предложенные к деактивации
+CREATE TABLE IF NOT EXISTS pvz_inactivity_flags (
+    pvz_id UUID PRIMARY KEY REFERENCES pvz(id),
+    last_activity_at TIMESTAMP NOT NULL,
+    flagged_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+-- Ожидаемые товары из накладной поставщика
+CREATE TABLE IF NOT EXISTS expected_products (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reception_id UUID NOT NULL REFERENCES reception(id),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('электроника', 'одежда', 'обувь')),
+    barcode VARCHAR(64),
+    quantity INT NOT NULL DEFAULT 1 CHECK (quantity > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_expected_products_reception_id ON expected_products(reception_id);
+
+COMMIT;
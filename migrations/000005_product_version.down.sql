@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE IF EXISTS product DROP COLUMN IF EXISTS version;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Версия товара для сверки офлайн-очереди мобильного приложения
+ALTER TABLE product ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE IF EXISTS pvz DROP CONSTRAINT IF EXISTS fk_pvz_city;
+ALTER TABLE IF EXISTS pvz DROP CONSTRAINT IF EXISTS pvz_city_check;
+ALTER TABLE IF EXISTS pvz ADD CONSTRAINT pvz_city_check CHECK (city IN ('Москва', 'Санкт-Петербург', 'Казань'));
+DROP TABLE IF EXISTS cities;
+
+COMMIT;
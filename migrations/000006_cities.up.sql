@@ -0,0 +1,20 @@
+BEGIN;
+
+-- Справочник городов с вариантами написания для нормализации ввода
+CREATE TABLE IF NOT EXISTS cities (
+    name VARCHAR(100) PRIMARY KEY,
+    aliases TEXT[] NOT NULL DEFAULT '{}'
+);
+
+INSERT INTO cities (name, aliases) VALUES
+    ('Москва', ARRAY['мск', 'moscow', 'moskva', 'msk']),
+    ('Санкт-Петербург', ARRAY['спб', 'питер', 'петербург', 'saint petersburg', 'st petersburg', 'sankt-peterburg', 'spb']),
+    ('Казань', ARRAY['kazan', 'kazan''', 'qazan'])
+ON CONFLICT (name) DO NOTHING;
+
+-- Допустимые города теперь определяются справочником, а не ограничением CHECK
+ALTER TABLE pvz DROP CONSTRAINT IF EXISTS pvz_city_check;
+ALTER TABLE pvz DROP CONSTRAINT IF EXISTS fk_pvz_city;
+ALTER TABLE pvz ADD CONSTRAINT fk_pvz_city FOREIGN KEY (city) REFERENCES cities(name);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS otp_codes;
+ALTER TABLE IF EXISTS users DROP CONSTRAINT IF EXISTS users_email_or_phone_check;
+DELETE FROM users WHERE email IS NULL;
+ALTER TABLE IF EXISTS users ALTER COLUMN email SET NOT NULL;
+ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS phone;
+
+COMMIT;
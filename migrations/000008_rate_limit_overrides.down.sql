@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS rate_limit_overrides;
+
+COMMIT;
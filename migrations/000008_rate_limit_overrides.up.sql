@@ -0,0 +1,12 @@
+BEGIN;
+
+-- Индивидуальные лимиты запросов для пользователей и ключей (user:<id>, ip:<адрес>)
+CREATE TABLE IF NOT EXISTS rate_limit_overrides (
+    key VARCHAR(255) PRIMARY KEY,
+    requests_per_minute INT NOT NULL CHECK (requests_per_minute > 0),
+    burst INT NOT NULL CHECK (burst > 0),
+    comment TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS reception_sla_alerts;
+
+COMMIT;
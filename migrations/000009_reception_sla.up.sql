@@ -0,0 +1,9 @@
+BEGIN;
+
+-- Приёмки, не закрытые в срок SLA
+CREATE TABLE IF NOT EXISTS reception_sla_alerts (
+    reception_id UUID PRIMARY KEY REFERENCES reception(id) ON DELETE CASCADE,
+    flagged_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMIT;
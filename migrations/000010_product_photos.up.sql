@@ -0,0 +1,16 @@
+BEGIN;
+
+-- Фотографии товаров при приёмке: загруженные в хранилище (storage_key) или внешние ссылки (url)
+CREATE TABLE IF NOT EXISTS product_photos (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES product(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    storage_key TEXT,
+    url TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT product_photos_source_check CHECK ((storage_key IS NULL) <> (url IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_photos_product_id ON product_photos(product_id);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_product_stored;
+ALTER TABLE IF EXISTS product DROP CONSTRAINT IF EXISTS product_status_check;
+ALTER TABLE IF EXISTS product DROP COLUMN IF EXISTS issued_at;
+ALTER TABLE IF EXISTS product DROP COLUMN IF EXISTS status;
+
+COMMIT;
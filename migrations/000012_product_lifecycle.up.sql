@@ -0,0 +1,17 @@
+BEGIN;
+
+-- Жизненный цикл товара: received (в открытой приёмке) -> stored (на хранении после закрытия приёмки) -> issued (выдан)
+ALTER TABLE product ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'received';
+ALTER TABLE product ADD COLUMN IF NOT EXISTS issued_at TIMESTAMP;
+
+ALTER TABLE product DROP CONSTRAINT IF EXISTS product_status_check;
+ALTER TABLE product ADD CONSTRAINT product_status_check CHECK (status IN ('received', 'stored', 'issued'));
+
+-- Товары уже закрытых приёмок находятся на хранении
+UPDATE product p SET status = 'stored'
+FROM reception r
+WHERE r.id = p.reception_id AND r.status = 'close';
+
+CREATE INDEX IF NOT EXISTS idx_product_stored ON product(reception_id) WHERE status = 'stored';
+
+COMMIT;
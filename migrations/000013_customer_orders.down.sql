@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_product_order_id;
+ALTER TABLE IF EXISTS product DROP COLUMN IF EXISTS issued_by;
+ALTER TABLE IF EXISTS product DROP COLUMN IF EXISTS order_id;
+
+DROP TABLE IF EXISTS customer_orders;
+
+COMMIT;
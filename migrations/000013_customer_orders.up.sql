@@ -0,0 +1,22 @@
+BEGIN;
+
+-- Заказы покупателей: товары ПВЗ, выдаваемые получателю по номеру заказа
+CREATE TABLE IF NOT EXISTS customer_orders (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_number VARCHAR(64) NOT NULL UNIQUE,
+    recipient_phone VARCHAR(20) NOT NULL,
+    pvz_id UUID NOT NULL REFERENCES pvz(id),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    issued_at TIMESTAMP,
+    -- Без внешнего ключа: тестовые токены dummyLogin не связаны с записью в users
+    issued_by UUID
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_orders_pvz_id ON customer_orders(pvz_id);
+
+ALTER TABLE product ADD COLUMN IF NOT EXISTS order_id UUID REFERENCES customer_orders(id);
+ALTER TABLE product ADD COLUMN IF NOT EXISTS issued_by UUID;
+
+CREATE INDEX IF NOT EXISTS idx_product_order_id ON product(order_id);
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+-- Города, в которых модератор управляет ПВЗ. Модератор без назначений не ограничен по городам
+CREATE TABLE IF NOT EXISTS moderator_cities (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    city VARCHAR(100) NOT NULL REFERENCES cities(name) ON UPDATE CASCADE,
+    PRIMARY KEY (user_id, city)
+);
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+-- Неудачные исходящие доставки (webhook оповещений и брокер событий).
+-- retrying - ожидают повторной попытки с экспоненциальной задержкой,
+-- dead - исчерпали попытки и ждут разбора администратором (dead-letter queue)
+CREATE TABLE IF NOT EXISTS failed_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    target VARCHAR(50) NOT NULL,
+    event JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'retrying' CHECK (status IN ('retrying', 'dead')),
+    attempts INT NOT NULL DEFAULT 1,
+    last_error TEXT NOT NULL,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_failed_deliveries_due ON failed_deliveries(next_attempt_at) WHERE status = 'retrying';
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+-- Максимальное количество товаров одного типа в приёмке (например, ограничения страховки для дорогих товаров)
+CREATE TABLE IF NOT EXISTS product_type_limits (
+    type VARCHAR(20) PRIMARY KEY CHECK (type IN ('электроника', 'одежда', 'обувь')),
+    max_per_reception INT NOT NULL CHECK (max_per_reception > 0),
+    comment TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMIT;
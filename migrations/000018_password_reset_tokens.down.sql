@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS password_reset_tokens;
+
+COMMIT;
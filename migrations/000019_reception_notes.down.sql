@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP TABLE IF EXISTS reception_notes;
+
+DROP INDEX IF EXISTS idx_reception_tags;
+
+ALTER TABLE reception
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS note;
+
+COMMIT;
@@ -0,0 +1,23 @@
+BEGIN;
+
+-- Текущие заметка и теги приёмки (например, «повреждена упаковка»)
+ALTER TABLE reception
+    ADD COLUMN IF NOT EXISTS note TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_reception_tags ON reception USING GIN (tags);
+
+-- История изменений заметок и тегов приёмки.
+-- author_id без внешнего ключа: тестовые токены не связаны с пользователями
+CREATE TABLE IF NOT EXISTS reception_notes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reception_id UUID NOT NULL REFERENCES reception(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL,
+    note TEXT NOT NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_reception_notes_reception_created_at ON reception_notes(reception_id, created_at);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS uq_pvz_city_address;
+
+ALTER TABLE pvz DROP COLUMN IF EXISTS address;
+
+COMMIT;
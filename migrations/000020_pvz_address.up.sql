@@ -0,0 +1,9 @@
+BEGIN;
+
+-- Адрес ПВЗ; у ранее созданных ПВЗ адрес пустой
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS address VARCHAR(255) NOT NULL DEFAULT '';
+
+-- В одном городе не может быть двух ПВЗ с одинаковым адресом
+CREATE UNIQUE INDEX IF NOT EXISTS uq_pvz_city_address ON pvz(city, address) WHERE address <> '';
+
+COMMIT;
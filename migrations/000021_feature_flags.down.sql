@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS feature_flag_pvz;
+DROP TABLE IF EXISTS feature_flags;
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+-- Флаги функций, которые включаются без перевыпуска сервиса
+CREATE TABLE IF NOT EXISTS feature_flags (
+    name VARCHAR(64) PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    description TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Значение флага для отдельного ПВЗ переопределяет общее
+CREATE TABLE IF NOT EXISTS feature_flag_pvz (
+    name VARCHAR(64) NOT NULL REFERENCES feature_flags(name) ON DELETE CASCADE,
+    pvz_id UUID NOT NULL REFERENCES pvz(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL,
+    PRIMARY KEY (name, pvz_id)
+);
+
+COMMIT;
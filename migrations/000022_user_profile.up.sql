@@ -0,0 +1,10 @@
+BEGIN;
+
+-- Профиль пользователя для отображения в терминале ПВЗ. Аватар хранится
+-- ключом объекта в хранилище вложений или внешней ссылкой
+ALTER TABLE users ADD COLUMN IF NOT EXISTS first_name VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_name VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_key TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_url TEXT;
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_pvz_open_reception;
+
+DROP TRIGGER IF EXISTS trg_pvz_open_reception ON reception;
+DROP FUNCTION IF EXISTS pvz_open_reception();
+
+DROP TRIGGER IF EXISTS trg_reception_product_count ON product;
+DROP FUNCTION IF EXISTS reception_product_count();
+
+ALTER TABLE pvz DROP COLUMN IF EXISTS open_reception;
+ALTER TABLE reception DROP COLUMN IF EXISTS product_count;
+
+COMMIT;
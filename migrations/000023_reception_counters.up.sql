@@ -0,0 +1,52 @@
+BEGIN;
+
+-- Счётчик товаров приёмки и признак открытой приёмки ПВЗ, чтобы список ПВЗ
+-- не считал товары и не искал открытые приёмки подзапросами
+ALTER TABLE reception ADD COLUMN IF NOT EXISTS product_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS open_reception BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE reception r SET product_count = (SELECT COUNT(*) FROM product p WHERE p.reception_id = r.id);
+UPDATE pvz p SET open_reception = EXISTS (
+    SELECT 1 FROM reception r WHERE r.pvz_id = p.id AND r.status = 'in_progress'
+);
+
+-- Счётчики поддерживаются триггерами, чтобы их не обходили запросы из pvzctl и массовые операции
+CREATE OR REPLACE FUNCTION reception_product_count() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE reception SET product_count = product_count - 1 WHERE id = OLD.reception_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE reception SET product_count = product_count + 1 WHERE id = NEW.reception_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_reception_product_count
+    AFTER INSERT OR DELETE OR UPDATE OF reception_id ON product
+    FOR EACH ROW EXECUTE FUNCTION reception_product_count();
+
+CREATE OR REPLACE FUNCTION pvz_open_reception() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE pvz SET open_reception = EXISTS (
+            SELECT 1 FROM reception WHERE pvz_id = OLD.pvz_id AND status = 'in_progress'
+        ) WHERE id = OLD.pvz_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE pvz SET open_reception = EXISTS (
+            SELECT 1 FROM reception WHERE pvz_id = NEW.pvz_id AND status = 'in_progress'
+        ) WHERE id = NEW.pvz_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_pvz_open_reception
+    AFTER INSERT OR DELETE OR UPDATE OF status, pvz_id ON reception
+    FOR EACH ROW EXECUTE FUNCTION pvz_open_reception();
+
+CREATE INDEX IF NOT EXISTS idx_pvz_open_reception ON pvz(open_reception) WHERE open_reception;
+
+COMMIT;
@@ -0,0 +1,51 @@
+BEGIN;
+
+-- Отметки времени хранились как TIMESTAMP без часового пояса: сервис записывал локальное время
+-- своего процесса, а значения по умолчанию - время сервера БД, из-за чего фильтры по датам
+-- ошибались около полуночи. Все столбцы переводятся в TIMESTAMPTZ и хранят момент времени.
+--
+-- Время, которое записывал сервис, интерпретируется в поясе из параметра pvz.legacy_timezone,
+-- значения по умолчанию - в поясе из pvz.legacy_db_timezone, например:
+--   PGOPTIONS="-c pvz.legacy_timezone=Europe/Moscow -c pvz.legacy_db_timezone=UTC"
+-- Незаданный параметр означает пояс сеанса миграции
+CREATE FUNCTION pg_temp.legacy_timezone(setting TEXT) RETURNS TEXT AS $$
+    SELECT COALESCE(NULLIF(current_setting(setting, true), ''), current_setting('TimeZone'))
+$$ LANGUAGE sql STABLE;
+
+ALTER TABLE pvz ALTER COLUMN registration_date TYPE TIMESTAMPTZ USING registration_date AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_timezone');
+ALTER TABLE reception ALTER COLUMN datetime TYPE TIMESTAMPTZ USING datetime AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_timezone');
+ALTER TABLE product ALTER COLUMN datetime TYPE TIMESTAMPTZ USING datetime AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_timezone');
+ALTER TABLE pvz_inactivity_flags ALTER COLUMN last_activity_at TYPE TIMESTAMPTZ USING last_activity_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_timezone');
+ALTER TABLE otp_codes ALTER COLUMN expires_at TYPE TIMESTAMPTZ USING expires_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_timezone');
+ALTER TABLE user_sessions ALTER COLUMN expires_at TYPE TIMESTAMPTZ USING expires_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_timezone');
+ALTER TABLE password_reset_tokens ALTER COLUMN expires_at TYPE TIMESTAMPTZ USING expires_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_timezone');
+ALTER TABLE failed_deliveries ALTER COLUMN next_attempt_at TYPE TIMESTAMPTZ USING next_attempt_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_timezone');
+
+ALTER TABLE pvz ALTER COLUMN deactivated_at TYPE TIMESTAMPTZ USING deactivated_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone');
+ALTER TABLE users ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone');
+ALTER TABLE product ALTER COLUMN issued_at TYPE TIMESTAMPTZ USING issued_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone');
+ALTER TABLE pvz_inactivity_flags ALTER COLUMN flagged_at TYPE TIMESTAMPTZ USING flagged_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone');
+ALTER TABLE expected_products ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone');
+ALTER TABLE otp_codes ALTER COLUMN consumed_at TYPE TIMESTAMPTZ USING consumed_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone'),
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone');
+ALTER TABLE rate_limit_overrides ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone');
+ALTER TABLE reception_sla_alerts ALTER COLUMN flagged_at TYPE TIMESTAMPTZ USING flagged_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone');
+ALTER TABLE product_photos ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone');
+ALTER TABLE event_outbox ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone'),
+    ALTER COLUMN published_at TYPE TIMESTAMPTZ USING published_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone');
+ALTER TABLE customer_orders ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone'),
+    ALTER COLUMN issued_at TYPE TIMESTAMPTZ USING issued_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone');
+ALTER TABLE user_sessions ALTER COLUMN issued_at TYPE TIMESTAMPTZ USING issued_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone'),
+    ALTER COLUMN revoked_at TYPE TIMESTAMPTZ USING revoked_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone');
+ALTER TABLE failed_deliveries ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone'),
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone');
+ALTER TABLE product_type_limits ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone');
+ALTER TABLE password_reset_tokens ALTER COLUMN used_at TYPE TIMESTAMPTZ USING used_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone'),
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone');
+ALTER TABLE reception_notes ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone');
+ALTER TABLE feature_flags ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE pg_temp.legacy_timezone('pvz.legacy_db_timezone');
+
+-- Часовой пояс IANA для отображения времени в документах ПВЗ, NULL - UTC
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS timezone TEXT;
+
+COMMIT;
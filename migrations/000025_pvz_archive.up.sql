@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Удаленный ПВЗ переносится в архив: история приёмок и товаров сохраняется,
+-- а ПВЗ пропадает из списков
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+COMMIT;
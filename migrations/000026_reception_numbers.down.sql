@@ -0,0 +1,16 @@
+BEGIN;
+
+DROP INDEX IF EXISTS uq_reception_number;
+ALTER TABLE reception DROP COLUMN IF EXISTS number;
+DROP TABLE IF EXISTS reception_number_counters;
+
+DROP TRIGGER IF EXISTS trg_pvz_assign_code ON pvz;
+DROP FUNCTION IF EXISTS pvz_assign_code();
+
+DROP INDEX IF EXISTS uq_pvz_code;
+ALTER TABLE pvz DROP COLUMN IF EXISTS code;
+DROP TABLE IF EXISTS pvz_code_counters;
+
+ALTER TABLE cities DROP COLUMN IF EXISTS code;
+
+COMMIT;
@@ -0,0 +1,91 @@
+BEGIN;
+
+-- Коды городов и ПВЗ для человекочитаемых номеров приёмок вида MSK001-2025-000123
+ALTER TABLE cities ADD COLUMN IF NOT EXISTS code VARCHAR(8);
+UPDATE cities SET code = 'MSK' WHERE name = 'Москва';
+UPDATE cities SET code = 'SPB' WHERE name = 'Санкт-Петербург';
+UPDATE cities SET code = 'KZN' WHERE name = 'Казань';
+
+-- Последний порядковый номер ПВЗ по коду города, ПВЗ городов без кода нумеруются с префиксом PVZ
+CREATE TABLE IF NOT EXISTS pvz_code_counters (
+    prefix VARCHAR(8) PRIMARY KEY,
+    last_number INTEGER NOT NULL
+);
+
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS code VARCHAR(16);
+
+UPDATE pvz p SET code = n.prefix || lpad(n.number::text, GREATEST(3, length(n.number::text)), '0')
+FROM (
+    SELECT p.id, COALESCE(c.code, 'PVZ') AS prefix,
+           row_number() OVER (PARTITION BY COALESCE(c.code, 'PVZ') ORDER BY p.registration_date, p.id) AS number
+    FROM pvz p
+    LEFT JOIN cities c ON c.name = p.city
+) n
+WHERE n.id = p.id;
+
+INSERT INTO pvz_code_counters (prefix, last_number)
+SELECT COALESCE(c.code, 'PVZ'), COUNT(*)
+FROM pvz p
+LEFT JOIN cities c ON c.name = p.city
+GROUP BY COALESCE(c.code, 'PVZ');
+
+ALTER TABLE pvz ALTER COLUMN code SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_pvz_code ON pvz(code);
+
+-- Код назначается триггером, чтобы его не обходили пакетное создание ПВЗ и pvzctl
+CREATE OR REPLACE FUNCTION pvz_assign_code() RETURNS trigger AS $$
+DECLARE
+    code_prefix VARCHAR(8);
+    next_number INTEGER;
+BEGIN
+    IF NEW.code IS NOT NULL THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT COALESCE(code, 'PVZ') INTO code_prefix FROM cities WHERE name = NEW.city;
+    code_prefix := COALESCE(code_prefix, 'PVZ');
+
+    INSERT INTO pvz_code_counters (prefix, last_number) VALUES (code_prefix, 1)
+    ON CONFLICT (prefix) DO UPDATE SET last_number = pvz_code_counters.last_number + 1
+    RETURNING last_number INTO next_number;
+
+    NEW.code := code_prefix || lpad(next_number::text, GREATEST(3, length(next_number::text)), '0');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_pvz_assign_code
+    BEFORE INSERT ON pvz
+    FOR EACH ROW EXECUTE FUNCTION pvz_assign_code();
+
+-- Последний номер приёмки ПВЗ за год, строка блокируется на время создания приёмки
+CREATE TABLE IF NOT EXISTS reception_number_counters (
+    pvz_id UUID NOT NULL REFERENCES pvz(id) ON DELETE CASCADE,
+    year INTEGER NOT NULL,
+    last_number INTEGER NOT NULL,
+    PRIMARY KEY (pvz_id, year)
+);
+
+ALTER TABLE reception ADD COLUMN IF NOT EXISTS number VARCHAR(40);
+
+UPDATE reception r SET number = n.code || '-' || n.year || '-' || lpad(n.number::text, GREATEST(6, length(n.number::text)), '0')
+FROM (
+    SELECT r.id, p.code, EXTRACT(YEAR FROM r.datetime AT TIME ZONE 'UTC')::int AS year,
+           row_number() OVER (
+               PARTITION BY r.pvz_id, EXTRACT(YEAR FROM r.datetime AT TIME ZONE 'UTC')
+               ORDER BY r.datetime, r.id
+           ) AS number
+    FROM reception r
+    JOIN pvz p ON p.id = r.pvz_id
+) n
+WHERE n.id = r.id;
+
+INSERT INTO reception_number_counters (pvz_id, year, last_number)
+SELECT pvz_id, EXTRACT(YEAR FROM datetime AT TIME ZONE 'UTC')::int, COUNT(*)
+FROM reception
+GROUP BY pvz_id, EXTRACT(YEAR FROM datetime AT TIME ZONE 'UTC')::int;
+
+ALTER TABLE reception ALTER COLUMN number SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_reception_number ON reception(number);
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+-- История исправлений типа товара
+CREATE TABLE IF NOT EXISTS product_type_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES product(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL,
+    old_type VARCHAR(20) NOT NULL,
+    new_type VARCHAR(20) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_type_changes_product_id ON product_type_changes(product_id);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Деактивированный пользователь не может войти, а выданные ему токены перестают приниматься
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_active BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMPTZ;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS pvz_working_hours;
+
+COMMIT;
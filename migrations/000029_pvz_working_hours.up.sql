@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Часы работы ПВЗ по дням недели (1 - понедельник, 7 - воскресенье) в часовом поясе ПВЗ.
+-- ПВЗ без расписания работает круглосуточно, день без строки в расписании - выходной
+CREATE TABLE IF NOT EXISTS pvz_working_hours (
+    pvz_id UUID NOT NULL REFERENCES pvz(id) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 1 AND 7),
+    opens_at TIME NOT NULL,
+    closes_at TIME NOT NULL,
+    PRIMARY KEY (pvz_id, weekday),
+    CHECK (opens_at < closes_at)
+);
+
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+-- Новая схема статусов товаров: статус выносится из горячей таблицы product в отдельную.
+-- На переходный период сервис пишет статус в обе таблицы (DB_SHADOW_MIGRATIONS=product_status=dual_write)
+-- и сверяет чтения (product_status=shadow_read); источником истины остается product.status
+CREATE TABLE IF NOT EXISTS product_status (
+    product_id UUID PRIMARY KEY REFERENCES product(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('received', 'stored', 'issued')),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Перенос существующих статусов. Товары, измененные до включения двойной записи,
+-- исправляются следующей записью и видны в сверке чтений
+INSERT INTO product_status (product_id, status)
+SELECT id, status FROM product
+ON CONFLICT (product_id) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+-- ПВЗ, в которых работает сотрудник. Список попадает в токен сотрудника,
+-- сотрудник без назначений не ограничен по ПВЗ
+CREATE TABLE IF NOT EXISTS employee_pvz (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    pvz_id UUID NOT NULL REFERENCES pvz(id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, pvz_id)
+);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX IF EXISTS uq_reception_open_pvz;
+
+COMMIT;
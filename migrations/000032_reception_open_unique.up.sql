@@ -0,0 +1,28 @@
+BEGIN;
+
+-- Закрываем лишние открытые приёмки, созданные параллельными запросами до появления индекса:
+-- открытой остается последняя приёмка ПВЗ, товары закрытых переходят на хранение
+WITH duplicates AS (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (PARTITION BY pvz_id ORDER BY datetime DESC, id DESC) AS rn
+        FROM reception
+        WHERE status = 'in_progress'
+    ) ranked
+    WHERE rn > 1
+), closed AS (
+    UPDATE reception SET status = 'close'
+    WHERE id IN (SELECT id FROM duplicates)
+    RETURNING id
+)
+UPDATE product SET status = 'stored'
+WHERE reception_id IN (SELECT id FROM closed) AND status = 'received';
+
+-- Новая схема статуса товаров должна совпадать со старой
+UPDATE product_status ps SET status = p.status, updated_at = CURRENT_TIMESTAMP
+FROM product p
+WHERE ps.product_id = p.id AND ps.status <> p.status;
+
+-- В ПВЗ может быть только одна открытая приёмка: индекс гарантирует это при параллельном создании
+CREATE UNIQUE INDEX IF NOT EXISTS uq_reception_open_pvz ON reception(pvz_id) WHERE status = 'in_progress';
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP TABLE IF EXISTS product_deletions;
+
+ALTER TABLE product DROP COLUMN IF EXISTS created_by;
+
+ALTER TABLE reception
+    DROP COLUMN IF EXISTS closed_by,
+    DROP COLUMN IF EXISTS created_by;
+
+COMMIT;
@@ -0,0 +1,25 @@
+BEGIN;
+
+-- Кто открыл и закрыл приёмку и кто принял товар. Колонки без внешних ключей:
+-- тестовые токены не связаны с пользователями. NULL - действие из консоли или фонового задания
+ALTER TABLE reception
+    ADD COLUMN IF NOT EXISTS created_by UUID,
+    ADD COLUMN IF NOT EXISTS closed_by UUID;
+
+ALTER TABLE product ADD COLUMN IF NOT EXISTS created_by UUID;
+
+-- Журнал удаленных товаров: товар удаляется из product, а запись о том, кто его удалил, остается
+CREATE TABLE IF NOT EXISTS product_deletions (
+    product_id UUID PRIMARY KEY,
+    reception_id UUID NOT NULL REFERENCES reception(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    deleted_by UUID,
+    deleted_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_reception_created_by ON reception(created_by, datetime) WHERE created_by IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_reception_closed_by ON reception(closed_by, datetime) WHERE closed_by IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_product_created_by ON product(created_by, datetime) WHERE created_by IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_product_deletions_deleted_by ON product_deletions(deleted_by, deleted_at);
+
+COMMIT;
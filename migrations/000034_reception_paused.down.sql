@@ -0,0 +1,21 @@
+BEGIN;
+
+-- Приостановленные приёмки закрываются, их товары переходят на хранение
+WITH closed AS (
+    UPDATE reception SET status = 'close'
+    WHERE status = 'paused'
+    RETURNING id
+)
+UPDATE product SET status = 'stored'
+WHERE reception_id IN (SELECT id FROM closed) AND status = 'received';
+
+UPDATE product_status ps SET status = p.status, updated_at = CURRENT_TIMESTAMP
+FROM product p
+WHERE ps.product_id = p.id AND ps.status <> p.status;
+
+DROP INDEX IF EXISTS idx_reception_paused_pvz;
+
+ALTER TABLE reception DROP CONSTRAINT IF EXISTS reception_status_check;
+ALTER TABLE reception ADD CONSTRAINT reception_status_check CHECK (status IN ('in_progress', 'close'));
+
+COMMIT;
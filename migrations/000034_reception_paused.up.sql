@@ -0,0 +1,10 @@
+BEGIN;
+
+-- Приостановленная приёмка (обед, пересменка): товары в нее не принимаются,
+-- а уникальный индекс открытых приёмок ее не учитывает
+ALTER TABLE reception DROP CONSTRAINT IF EXISTS reception_status_check;
+ALTER TABLE reception ADD CONSTRAINT reception_status_check CHECK (status IN ('in_progress', 'paused', 'close'));
+
+CREATE INDEX IF NOT EXISTS idx_reception_paused_pvz ON reception(pvz_id, datetime) WHERE status = 'paused';
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS uq_user_sessions_refresh_token;
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS refresh_token_hash;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+-- Хеш refresh-токена сессии «запомнить меня»: токен меняется при каждом обновлении,
+-- поэтому повторно использовать перехваченный refresh-токен нельзя
+ALTER TABLE user_sessions ADD COLUMN IF NOT EXISTS refresh_token_hash TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_user_sessions_refresh_token ON user_sessions(refresh_token_hash) WHERE refresh_token_hash IS NOT NULL;
+
+COMMIT;
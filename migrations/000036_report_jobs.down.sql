@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_reception_pvz_datetime;
+DROP TABLE IF EXISTS report_jobs;
+
+COMMIT;
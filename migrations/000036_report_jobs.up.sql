@@ -0,0 +1,20 @@
+BEGIN;
+
+-- Отчёты, которые формируются в фоне: за большой период синхронный запрос не укладывается в таймаут.
+-- Готовый файл сохраняется в хранилище вложений под ключом object_key
+CREATE TABLE IF NOT EXISTS report_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind VARCHAR(50) NOT NULL,
+    params JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'done', 'failed')),
+    object_key TEXT,
+    error TEXT,
+    created_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMPTZ
+);
+
+-- Выгрузка приёмок города выбирает приёмки ПВЗ за период
+CREATE INDEX IF NOT EXISTS idx_reception_pvz_datetime ON reception(pvz_id, datetime);
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+-- Журнал переноса приёмок между ПВЗ: кто, когда и откуда перенес приёмку вместе с товарами
+CREATE TABLE IF NOT EXISTS reception_reassignments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reception_id UUID NOT NULL REFERENCES reception(id) ON DELETE CASCADE,
+    from_pvz_id UUID NOT NULL REFERENCES pvz(id),
+    to_pvz_id UUID NOT NULL REFERENCES pvz(id),
+    reason TEXT,
+    reassigned_by UUID,
+    reassigned_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_reception_reassignments_reception_id ON reception_reassignments(reception_id);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_product_barcode_trgm;
+DROP INDEX IF EXISTS idx_reception_note_trgm;
+DROP INDEX IF EXISTS idx_reception_number_trgm;
+DROP INDEX IF EXISTS idx_pvz_address_trgm;
+DROP INDEX IF EXISTS idx_pvz_city_trgm;
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+-- Поиск по подстроке в ПВЗ, приёмках и товарах: триграммные индексы ускоряют ILIKE '%...%'
+-- и дают оценку похожести similarity() для сортировки результатов
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_pvz_city_trgm ON pvz USING GIN (city gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_pvz_address_trgm ON pvz USING GIN (address gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_reception_number_trgm ON reception USING GIN (number gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_reception_note_trgm ON reception USING GIN (note gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_product_barcode_trgm ON product USING GIN (barcode gin_trgm_ops);
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_product_deletions_reception_id;
+
+ALTER TABLE product_deletions
+    DROP COLUMN IF EXISTS added_at,
+    DROP COLUMN IF EXISTS added_by;
+
+DROP TRIGGER IF EXISTS trg_reception_closed_at ON reception;
+DROP FUNCTION IF EXISTS reception_closed_at();
+
+ALTER TABLE reception DROP COLUMN IF EXISTS closed_at;
+
+COMMIT;
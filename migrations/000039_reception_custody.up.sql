@@ -0,0 +1,28 @@
+BEGIN;
+
+-- Время закрытия приёмки для документа о цепочке хранения. Ставится триггером,
+-- чтобы его не обходили закрытие из pvzctl, массовое закрытие и объединение приёмок.
+-- У приёмок, закрытых до миграции, время закрытия неизвестно
+ALTER TABLE reception ADD COLUMN IF NOT EXISTS closed_at TIMESTAMPTZ;
+
+CREATE OR REPLACE FUNCTION reception_closed_at() RETURNS trigger AS $$
+BEGIN
+    IF NEW.status = 'close' AND OLD.status <> 'close' THEN
+        NEW.closed_at := CURRENT_TIMESTAMP;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_reception_closed_at
+    BEFORE UPDATE OF status ON reception
+    FOR EACH ROW EXECUTE FUNCTION reception_closed_at();
+
+-- Кто и когда добавил удаленный товар: без этого в журнале удалений теряется добавление товара
+ALTER TABLE product_deletions
+    ADD COLUMN IF NOT EXISTS added_by UUID,
+    ADD COLUMN IF NOT EXISTS added_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_product_deletions_reception_id ON product_deletions(reception_id);
+
+COMMIT;
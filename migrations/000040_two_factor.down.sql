@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS login_challenges;
+DROP TABLE IF EXISTS totp_backup_codes;
+DROP TABLE IF EXISTS user_totp;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE reception
+    DROP COLUMN IF EXISTS template_id,
+    DROP COLUMN IF EXISTS capacity;
+
+DROP TABLE IF EXISTS reception_template_lines;
+DROP TABLE IF EXISTS reception_templates;
+
+COMMIT;
@@ -0,0 +1,33 @@
+BEGIN;
+
+-- Шаблоны приёмок для повторяющихся поставок: ожидаемый состав по типам товаров,
+-- вместимость и заметка копируются в приёмку, созданную по шаблону.
+-- created_by без внешнего ключа: тестовые токены не связаны с пользователями
+CREATE TABLE IF NOT EXISTS reception_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    note TEXT NOT NULL DEFAULT '',
+    capacity INT CHECK (capacity > 0),
+    created_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Строки шаблона в формате накладной expected_products
+CREATE TABLE IF NOT EXISTS reception_template_lines (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    template_id UUID NOT NULL REFERENCES reception_templates(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('электроника', 'одежда', 'обувь')),
+    barcode VARCHAR(64),
+    quantity INT NOT NULL DEFAULT 1 CHECK (quantity > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reception_template_lines_template_id ON reception_template_lines(template_id, position);
+
+-- Вместимость приёмки (максимум товаров) и шаблон, по которому она создана
+ALTER TABLE reception
+    ADD COLUMN IF NOT EXISTS capacity INT CHECK (capacity > 0),
+    ADD COLUMN IF NOT EXISTS template_id UUID REFERENCES reception_templates(id) ON DELETE SET NULL;
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+-- Число запросов к API по часам в разрезе ПВЗ, пользователя и маршрута. Счетчики копятся в памяти
+-- экземпляра сервиса и периодически прибавляются к строке часа, поэтому внешних ключей нет:
+-- статистика переживает удаление ПВЗ и пользователя, а запись не зависит от них
+CREATE TABLE IF NOT EXISTS api_usage (
+    bucket TIMESTAMPTZ NOT NULL,
+    pvz_id UUID,
+    user_id UUID,
+    method VARCHAR(10) NOT NULL,
+    route VARCHAR(255) NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    errors BIGINT NOT NULL DEFAULT 0,
+    CONSTRAINT api_usage_key UNIQUE NULLS NOT DISTINCT (bucket, pvz_id, user_id, method, route)
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_usage_pvz_id ON api_usage(pvz_id, bucket);
+CREATE INDEX IF NOT EXISTS idx_api_usage_user_id ON api_usage(user_id, bucket);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE reception DROP COLUMN IF EXISTS supplier_id;
+
+DROP TABLE IF EXISTS suppliers;
+
+COMMIT;
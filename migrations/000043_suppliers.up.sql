@@ -0,0 +1,19 @@
+BEGIN;
+
+-- Поставщики, сдающие товары в ПВЗ. ИНН необязателен, но не повторяется
+CREATE TABLE IF NOT EXISTS suppliers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(200) NOT NULL UNIQUE,
+    inn VARCHAR(12) UNIQUE,
+    contact VARCHAR(500) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Поставщик приёмки. Поставщика с приёмками удалить нельзя, чтобы не потерять сверку поставок
+ALTER TABLE reception
+    ADD COLUMN IF NOT EXISTS supplier_id UUID REFERENCES suppliers(id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_reception_supplier_id ON reception(supplier_id, datetime) WHERE supplier_id IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS trg_product_tombstone ON product;
+DROP FUNCTION IF EXISTS product_tombstone();
+DROP TABLE IF EXISTS product_tombstones;
+
+DROP TRIGGER IF EXISTS trg_product_updated_at ON product;
+DROP TRIGGER IF EXISTS trg_reception_updated_at ON reception;
+DROP TRIGGER IF EXISTS trg_pvz_updated_at ON pvz;
+DROP FUNCTION IF EXISTS touch_updated_at();
+
+DROP INDEX IF EXISTS idx_product_updated_at;
+DROP INDEX IF EXISTS idx_reception_updated_at;
+DROP INDEX IF EXISTS idx_pvz_updated_at;
+
+ALTER TABLE product DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE reception DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE pvz DROP COLUMN IF EXISTS updated_at;
+
+COMMIT;
@@ -0,0 +1,57 @@
+BEGIN;
+
+-- Время последнего изменения ПВЗ, приёмок и товаров для инкрементальной синхронизации
+-- терминалов ПВЗ (GET /sync). Существующие строки получают время миграции
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE reception ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE product ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- Время ставится триггером, чтобы его не обходили pvzctl, массовые операции и триггеры счётчиков.
+-- clock_timestamp(), а не время транзакции: строки, измененные одним запросом, реже получают одинаковое время
+CREATE OR REPLACE FUNCTION touch_updated_at() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at := clock_timestamp();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_pvz_updated_at
+    BEFORE UPDATE ON pvz
+    FOR EACH ROW EXECUTE FUNCTION touch_updated_at();
+
+CREATE TRIGGER trg_reception_updated_at
+    BEFORE UPDATE ON reception
+    FOR EACH ROW EXECUTE FUNCTION touch_updated_at();
+
+CREATE TRIGGER trg_product_updated_at
+    BEFORE UPDATE ON product
+    FOR EACH ROW EXECUTE FUNCTION touch_updated_at();
+
+-- Удаленные товары, чтобы терминал убрал их из своей копии. Записываются триггером при любом удалении
+CREATE TABLE IF NOT EXISTS product_tombstones (
+    product_id UUID PRIMARY KEY,
+    reception_id UUID NOT NULL,
+    pvz_id UUID NOT NULL,
+    deleted_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE OR REPLACE FUNCTION product_tombstone() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO product_tombstones (product_id, reception_id, pvz_id)
+    SELECT OLD.id, OLD.reception_id, r.pvz_id FROM reception r WHERE r.id = OLD.reception_id
+    ON CONFLICT (product_id) DO NOTHING;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_product_tombstone
+    AFTER DELETE ON product
+    FOR EACH ROW EXECUTE FUNCTION product_tombstone();
+
+-- Синхронизация читает изменения по порядку (updated_at, id)
+CREATE INDEX IF NOT EXISTS idx_pvz_updated_at ON pvz(updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_reception_updated_at ON reception(updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_product_updated_at ON product(updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_product_tombstones_deleted_at ON product_tombstones(deleted_at, product_id);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS operation_quota_usage;
+DROP TABLE IF EXISTS operation_quota_overrides;
+DROP TABLE IF EXISTS operation_quotas;
+
+COMMIT;
@@ -0,0 +1,46 @@
+BEGIN;
+
+-- Квоты операций по ролям: сколько раз в час пользователь роли может выполнить операцию,
+-- например удалить товар. Операции без квоты не ограничены
+CREATE TABLE IF NOT EXISTS operation_quotas (
+    role VARCHAR(20) NOT NULL CHECK (role IN ('employee', 'moderator')),
+    operation VARCHAR(50) NOT NULL CHECK (operation IN ('product.delete', 'reception.create')),
+    max_per_hour INT NOT NULL CHECK (max_per_hour > 0),
+    comment TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (role, operation)
+);
+
+-- Сотрудник удаляет товары по одному при ошибке сканирования, сотни удалений в час - признак
+-- взлома или злоупотребления учетной записью
+INSERT INTO operation_quotas (role, operation, max_per_hour, comment)
+VALUES ('employee', 'product.delete', 200, 'Защита от массового удаления товаров приёмки')
+ON CONFLICT DO NOTHING;
+
+-- Временное исключение для пользователя, выданное администратором, например на разбор
+-- ошибочной приёмки. max_per_hour NULL - операция не ограничена до expires_at.
+-- created_by без внешнего ключа: тестовые токены не связаны с пользователями
+CREATE TABLE IF NOT EXISTS operation_quota_overrides (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    operation VARCHAR(50) NOT NULL CHECK (operation IN ('product.delete', 'reception.create')),
+    max_per_hour INT CHECK (max_per_hour > 0),
+    expires_at TIMESTAMPTZ NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, operation)
+);
+
+-- Счетчики операций пользователя по часовым окнам. Увеличиваются в транзакции операции,
+-- поэтому отмененная операция квоту не расходует. Общие для всех экземпляров сервиса
+CREATE TABLE IF NOT EXISTS operation_quota_usage (
+    user_id UUID NOT NULL,
+    operation VARCHAR(50) NOT NULL,
+    window_start TIMESTAMPTZ NOT NULL,
+    used INT NOT NULL CHECK (used >= 0),
+    PRIMARY KEY (user_id, operation, window_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_operation_quota_usage_window ON operation_quota_usage(window_start);
+
+COMMIT;
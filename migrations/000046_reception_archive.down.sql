@@ -0,0 +1,15 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_reception_closed;
+DROP TABLE IF EXISTS reception_archivals;
+DROP TABLE IF EXISTS product_deletions_archive;
+DROP TABLE IF EXISTS reception_reassignments_archive;
+DROP TABLE IF EXISTS reception_notes_archive;
+DROP TABLE IF EXISTS expected_products_archive;
+DROP TABLE IF EXISTS product_status_archive;
+DROP TABLE IF EXISTS product_type_changes_archive;
+DROP TABLE IF EXISTS product_photos_archive;
+DROP TABLE IF EXISTS product_archive;
+DROP TABLE IF EXISTS reception_archive;
+
+COMMIT;
@@ -0,0 +1,45 @@
+BEGIN;
+
+-- Архив закрытых приёмок старше срока хранения (ARCHIVE_RECEPTIONS_AFTER_DAYS). Приёмки с товарами
+-- и их историей переносятся из рабочих таблиц, поэтому списки, отчёты и синхронизация их не видят
+-- и работают с объемом данных за срок хранения. Таблицы архива повторяют колонки рабочих таблиц
+-- в том же порядке: перенос и восстановление копируют строки целиком, поэтому миграция, меняющая
+-- колонки рабочей таблицы, должна так же изменить таблицу архива
+CREATE TABLE IF NOT EXISTS reception_archive (LIKE reception);
+ALTER TABLE reception_archive ADD PRIMARY KEY (id);
+CREATE INDEX IF NOT EXISTS idx_reception_archive_pvz_id ON reception_archive(pvz_id, datetime);
+
+CREATE TABLE IF NOT EXISTS product_archive (LIKE product);
+CREATE INDEX IF NOT EXISTS idx_product_archive_reception_id ON product_archive(reception_id);
+
+CREATE TABLE IF NOT EXISTS product_photos_archive (LIKE product_photos);
+CREATE INDEX IF NOT EXISTS idx_product_photos_archive_product_id ON product_photos_archive(product_id);
+
+CREATE TABLE IF NOT EXISTS product_type_changes_archive (LIKE product_type_changes);
+CREATE INDEX IF NOT EXISTS idx_product_type_changes_archive_product_id ON product_type_changes_archive(product_id);
+
+CREATE TABLE IF NOT EXISTS product_status_archive (LIKE product_status);
+CREATE INDEX IF NOT EXISTS idx_product_status_archive_product_id ON product_status_archive(product_id);
+
+CREATE TABLE IF NOT EXISTS expected_products_archive (LIKE expected_products);
+CREATE INDEX IF NOT EXISTS idx_expected_products_archive_reception_id ON expected_products_archive(reception_id);
+
+CREATE TABLE IF NOT EXISTS reception_notes_archive (LIKE reception_notes);
+CREATE INDEX IF NOT EXISTS idx_reception_notes_archive_reception_id ON reception_notes_archive(reception_id);
+
+CREATE TABLE IF NOT EXISTS reception_reassignments_archive (LIKE reception_reassignments);
+CREATE INDEX IF NOT EXISTS idx_reception_reassignments_archive_reception_id ON reception_reassignments_archive(reception_id);
+
+CREATE TABLE IF NOT EXISTS product_deletions_archive (LIKE product_deletions);
+CREATE INDEX IF NOT EXISTS idx_product_deletions_archive_reception_id ON product_deletions_archive(reception_id);
+
+-- Когда приёмка перенесена в архив. Отдельно от reception_archive, чтобы ее колонки совпадали с reception
+CREATE TABLE IF NOT EXISTS reception_archivals (
+    reception_id UUID PRIMARY KEY REFERENCES reception_archive(id) ON DELETE CASCADE,
+    archived_at TIMESTAMPTZ NOT NULL
+);
+
+-- Поиск приёмок для переноса: закрытые раньше срока хранения
+CREATE INDEX IF NOT EXISTS idx_reception_closed ON reception(COALESCE(closed_at, datetime)) WHERE status = 'close';
+
+COMMIT;
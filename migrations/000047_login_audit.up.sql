@@ -0,0 +1,24 @@
+BEGIN;
+
+-- Журнал попыток входа по email и паролю. user_id пустой, если пользователь с таким email не найден
+CREATE TABLE IF NOT EXISTS login_audit (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    email TEXT NOT NULL,
+    success BOOLEAN NOT NULL,
+    failure_reason TEXT CHECK (failure_reason IN ('unknown_user', 'invalid_password', 'deactivated')),
+    ip TEXT NOT NULL,
+    user_agent TEXT NOT NULL DEFAULT '',
+    country TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (success = (failure_reason IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_audit_created_at ON login_audit(created_at);
+CREATE INDEX IF NOT EXISTS idx_login_audit_user ON login_audit(user_id, created_at);
+
+-- Неудачные попытки считаются по email и по IP за короткое окно
+CREATE INDEX IF NOT EXISTS idx_login_audit_failed_email ON login_audit(email, created_at) WHERE NOT success;
+CREATE INDEX IF NOT EXISTS idx_login_audit_failed_ip ON login_audit(ip, created_at) WHERE NOT success;
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+DROP TABLE IF EXISTS product_damage_photos_archive;
+DROP TABLE IF EXISTS product_damage_photos;
+DROP INDEX IF EXISTS idx_product_damaged_at;
+
+ALTER TABLE product_archive
+    DROP COLUMN IF EXISTS damaged_by,
+    DROP COLUMN IF EXISTS damaged_at,
+    DROP COLUMN IF EXISTS damage_description,
+    DROP COLUMN IF EXISTS damaged;
+
+ALTER TABLE product
+    DROP COLUMN IF EXISTS damaged_by,
+    DROP COLUMN IF EXISTS damaged_at,
+    DROP COLUMN IF EXISTS damage_description,
+    DROP COLUMN IF EXISTS damaged;
+
+COMMIT;
@@ -0,0 +1,36 @@
+BEGIN;
+
+-- Отметка о повреждении товара при приёмке: описание, кто и когда отметил. Раньше поврежденные товары
+-- записывали в бумажный журнал. Таблица архива меняется так же, чтобы колонки совпадали с product
+ALTER TABLE product
+    ADD COLUMN IF NOT EXISTS damaged BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS damage_description TEXT,
+    ADD COLUMN IF NOT EXISTS damaged_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS damaged_by UUID;
+
+ALTER TABLE product_archive
+    ADD COLUMN IF NOT EXISTS damaged BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS damage_description TEXT,
+    ADD COLUMN IF NOT EXISTS damaged_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS damaged_by UUID;
+
+-- Отчёт о поврежденных товарах за период
+CREATE INDEX IF NOT EXISTS idx_product_damaged_at ON product(damaged_at) WHERE damaged;
+
+-- Фотографии повреждения: отдельно от фотографий товара при приёмке
+CREATE TABLE IF NOT EXISTS product_damage_photos (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES product(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    storage_key TEXT,
+    url TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT product_damage_photos_source_check CHECK ((storage_key IS NULL) <> (url IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_damage_photos_product_id ON product_damage_photos(product_id);
+
+CREATE TABLE IF NOT EXISTS product_damage_photos_archive (LIKE product_damage_photos);
+CREATE INDEX IF NOT EXISTS idx_product_damage_photos_archive_product_id ON product_damage_photos_archive(product_id);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS reception_forced_closes_archive;
+DROP TABLE IF EXISTS reception_forced_closes;
+
+COMMIT;
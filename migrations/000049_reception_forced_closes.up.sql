@@ -0,0 +1,19 @@
+BEGIN;
+
+-- Закрытия приёмок с расхождениями по накладной: приёмка с расхождениями закрывается только с force=true
+-- и причиной, которые записываются сюда вместе с расхождениями на момент закрытия
+CREATE TABLE IF NOT EXISTS reception_forced_closes (
+    id BIGSERIAL PRIMARY KEY,
+    reception_id UUID NOT NULL REFERENCES reception(id) ON DELETE CASCADE,
+    closed_by UUID,
+    reason TEXT NOT NULL,
+    discrepancies JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_reception_forced_closes_reception_id ON reception_forced_closes(reception_id);
+
+CREATE TABLE IF NOT EXISTS reception_forced_closes_archive (LIKE reception_forced_closes);
+CREATE INDEX IF NOT EXISTS idx_reception_forced_closes_archive_reception_id ON reception_forced_closes_archive(reception_id);
+
+COMMIT;
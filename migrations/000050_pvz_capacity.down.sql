@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS trg_reception_pvz_occupancy_reassign ON reception;
+DROP FUNCTION IF EXISTS pvz_occupancy_reassign();
+DROP TRIGGER IF EXISTS trg_reception_pvz_occupancy ON product;
+DROP FUNCTION IF EXISTS pvz_occupancy();
+
+ALTER TABLE pvz DROP COLUMN IF EXISTS occupancy;
+ALTER TABLE pvz DROP COLUMN IF EXISTS capacity;
+
+COMMIT;
@@ -0,0 +1,52 @@
+BEGIN;
+
+-- Вместимость ПВЗ (ячейки или объем полок в товарах) и текущая заполненность: товары приёмок ПВЗ,
+-- которые еще не выданы. Без вместимости (NULL) товары принимаются без ограничения
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS capacity INTEGER CHECK (capacity > 0);
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS occupancy INTEGER NOT NULL DEFAULT 0;
+
+UPDATE pvz p SET occupancy = (
+    SELECT COUNT(*) FROM product pr JOIN reception r ON r.id = pr.reception_id
+    WHERE r.pvz_id = p.id AND pr.status <> 'issued'
+);
+
+-- Заполненность, как и счетчик товаров приёмки, поддерживается триггерами по переходам жизненного цикла
+-- товара: добавление, удаление, выдача и перенос между приёмками. Имя триггера выбрано так, чтобы он
+-- срабатывал после trg_reception_product_count: строки блокируются в порядке приёмка, затем ПВЗ,
+-- как при закрытии приёмки
+CREATE OR REPLACE FUNCTION pvz_occupancy() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.status <> 'issued' THEN
+        UPDATE pvz SET occupancy = occupancy - 1
+        WHERE id = (SELECT pvz_id FROM reception WHERE id = OLD.reception_id);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.status <> 'issued' THEN
+        UPDATE pvz SET occupancy = occupancy + 1
+        WHERE id = (SELECT pvz_id FROM reception WHERE id = NEW.reception_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_reception_pvz_occupancy
+    AFTER INSERT OR DELETE OR UPDATE OF status, reception_id ON product
+    FOR EACH ROW EXECUTE FUNCTION pvz_occupancy();
+
+-- Перенос приёмки в другой ПВЗ переносит и ее невыданные товары
+CREATE OR REPLACE FUNCTION pvz_occupancy_reassign() RETURNS trigger AS $$
+DECLARE
+    moved INTEGER;
+BEGIN
+    SELECT COUNT(*) INTO moved FROM product WHERE reception_id = NEW.id AND status <> 'issued';
+    UPDATE pvz SET occupancy = occupancy - moved WHERE id = OLD.pvz_id;
+    UPDATE pvz SET occupancy = occupancy + moved WHERE id = NEW.pvz_id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_reception_pvz_occupancy_reassign
+    AFTER UPDATE OF pvz_id ON reception
+    FOR EACH ROW WHEN (OLD.pvz_id IS DISTINCT FROM NEW.pvz_id)
+    EXECUTE FUNCTION pvz_occupancy_reassign();
+
+COMMIT;
@@ -0,0 +1,34 @@
+BEGIN;
+
+-- Сохраненные отчёты конструктора: что считать (entity), какие строки брать (фильтры),
+-- как группировать и в каком формате выдавать. Период задается последними period_days днями
+-- на момент запуска, чтобы отчёт по расписанию каждый раз строился за свежий период.
+-- created_by без внешнего ключа: тестовые токены не связаны с пользователями
+CREATE TABLE IF NOT EXISTS report_definitions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(200) NOT NULL,
+    entity VARCHAR(20) NOT NULL CHECK (entity IN ('receptions', 'products')),
+    period_days INT CHECK (period_days > 0),
+    city VARCHAR(100),
+    pvz_id UUID REFERENCES pvz(id) ON DELETE CASCADE,
+    status VARCHAR(20),
+    product_type VARCHAR(50),
+    group_by TEXT[] NOT NULL DEFAULT '{}',
+    format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'json')),
+    -- Расписание: NULL - только запуск вручную. Следующий запуск next_run_at забирает фоновое задание
+    schedule VARCHAR(10) CHECK (schedule IN ('daily', 'weekly')),
+    next_run_at TIMESTAMPTZ,
+    last_run_at TIMESTAMPTZ,
+    -- Адреса, на которые отправляется ссылка на готовый отчёт
+    recipients TEXT[] NOT NULL DEFAULT '{}',
+    created_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((schedule IS NULL) = (next_run_at IS NULL))
+);
+
+-- Фоновое задание выбирает отчёты, которым пора запускаться
+CREATE INDEX IF NOT EXISTS idx_report_definitions_next_run ON report_definitions(next_run_at)
+WHERE next_run_at IS NOT NULL;
+
+COMMIT;